@@ -0,0 +1,534 @@
+// Package arbitration decides which of several controllers may command a robot's
+// components. Clients declare the source of their commands and its priority in gRPC
+// metadata; a source takes control of a component with its first command and keeps it
+// while it keeps commanding. Commands from other sources are rejected, or queued if they
+// ask to wait, until control is released or lapses, unless they have a higher priority,
+// in which case they preempt the current source and cancel its running commands.
+//
+// Only commands are arbitrated: Stop and methods that read state, such as Get* and Is*,
+// are always served. Commands that do not declare a source never take control and are
+// only served while no source has control, so robots without declared sources behave
+// as before.
+package arbitration
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// A Priority orders the sources of commands. Higher priorities preempt lower ones.
+type Priority int
+
+const (
+	// PriorityUndeclared is the priority of commands that do not declare a source.
+	PriorityUndeclared Priority = 0
+	// PriorityAutonomous is the priority of autonomous behaviors.
+	PriorityAutonomous Priority = 10
+	// PriorityTeleop is the priority of operators teleoperating the robot.
+	PriorityTeleop Priority = 20
+	// PrioritySafety is the priority of safety controllers.
+	PrioritySafety Priority = 30
+)
+
+var priorityNames = map[Priority]string{
+	PriorityUndeclared: "undeclared",
+	PriorityAutonomous: "autonomous",
+	PriorityTeleop:     "teleop",
+	PrioritySafety:     "safety",
+}
+
+// String returns the name of the priority, or its value if it has none.
+func (p Priority) String() string {
+	if name, ok := priorityNames[p]; ok {
+		return name
+	}
+	return strconv.Itoa(int(p))
+}
+
+// ParsePriority parses a priority from its name or a positive integer.
+func ParsePriority(s string) (Priority, error) {
+	for p, name := range priorityNames {
+		if p != PriorityUndeclared && name == s {
+			return p, nil
+		}
+	}
+	value, err := strconv.Atoi(s)
+	if err != nil || value <= 0 {
+		return 0, errors.Errorf("invalid command priority %q", s)
+	}
+	return Priority(value), nil
+}
+
+// A Source is a controller that commands components.
+type Source struct {
+	// Name identifies the source; commands with the same name share control.
+	Name     string
+	Priority Priority
+	// Queue waits for control instead of failing while another source has it.
+	Queue bool
+}
+
+// The gRPC metadata keys a client declares the source of its commands with.
+const (
+	SourceMetadataKey   = "viam-command-source"
+	PriorityMetadataKey = "viam-command-priority"
+	QueueMetadataKey    = "viam-command-queue"
+)
+
+// ContextWithSource returns a context whose outgoing gRPC calls declare the given source.
+func ContextWithSource(ctx context.Context, src Source) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		SourceMetadataKey, src.Name,
+		PriorityMetadataKey, src.Priority.String(),
+		QueueMetadataKey, strconv.FormatBool(src.Queue),
+	)
+}
+
+// UnaryClientInterceptor returns an interceptor that declares the given source on every
+// call that does not already declare one.
+func UnaryClientInterceptor(src Source) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if md, ok := metadata.FromOutgoingContext(ctx); !ok || len(md.Get(SourceMetadataKey)) == 0 {
+			ctx = ContextWithSource(ctx, src)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// sourceFromIncomingContext returns the source declared by the caller, if any.
+func sourceFromIncomingContext(ctx context.Context) (Source, bool, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Source{}, false, nil
+	}
+	names := md.Get(SourceMetadataKey)
+	if len(names) == 0 || names[0] == "" {
+		return Source{}, false, nil
+	}
+	src := Source{Name: names[0], Priority: PriorityAutonomous}
+	if priorities := md.Get(PriorityMetadataKey); len(priorities) != 0 {
+		p, err := ParsePriority(priorities[0])
+		if err != nil {
+			return Source{}, false, err
+		}
+		src.Priority = p
+	}
+	if queues := md.Get(QueueMetadataKey); len(queues) != 0 {
+		queue, err := strconv.ParseBool(queues[0])
+		if err != nil {
+			return Source{}, false, errors.Errorf("invalid %s %q", QueueMetadataKey, queues[0])
+		}
+		src.Queue = queue
+	}
+	return src, true, nil
+}
+
+// DefaultHoldTime is how long a source keeps control of a component after its last
+// command finishes.
+const DefaultHoldTime = 2 * time.Second
+
+// ErrPreempted is the cause with which commands are canceled when a higher priority
+// source takes control of their component.
+var ErrPreempted = errors.New("command preempted by a higher priority source")
+
+// Control is the control of a component by a source.
+type Control struct {
+	Resource resource.Name
+	Source   Source
+	Since    time.Time
+}
+
+type control struct {
+	src   Source
+	since time.Time
+	// expires is when control lapses if no commands are running.
+	expires time.Time
+	running map[*command]struct{}
+}
+
+type command struct {
+	cancel context.CancelCauseFunc
+}
+
+// A Robot is a robot arbitrating which source may command its components.
+type Robot interface {
+	// Arbiter returns the arbiter deciding which source may command the robot's components.
+	Arbiter() *Arbiter
+}
+
+// An Arbiter arbitrates the commands sent to a robot's components.
+type Arbiter struct {
+	logger logging.Logger
+
+	mu       sync.Mutex
+	controls map[resource.Name]*control
+	// changed is closed and replaced whenever control may have become available.
+	changed chan struct{}
+
+	// apis caches the API served by each gRPC service.
+	apis sync.Map
+}
+
+// NewArbiter returns an Arbiter with no component under control.
+func NewArbiter(logger logging.Logger) *Arbiter {
+	return &Arbiter{
+		logger:   logger,
+		controls: map[resource.Name]*control{},
+		changed:  make(chan struct{}),
+	}
+}
+
+// current returns the control of the named component, if it has not lapsed. It must be
+// called with mu held.
+func (a *Arbiter) current(name resource.Name, now time.Time) *control {
+	c, ok := a.controls[name]
+	if !ok {
+		return nil
+	}
+	if len(c.running) == 0 && !now.Before(c.expires) {
+		delete(a.controls, name)
+		return nil
+	}
+	return c
+}
+
+// notify wakes up queued commands. It must be called with mu held.
+func (a *Arbiter) notify() {
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// claim gives src control of the named component, preempting or waiting for the
+// current source as needed, and keeps it for at least hold. Undeclared sources are given
+// no control but may proceed while no source has it, in which case claim returns nil. On
+// success, mu is held.
+func (a *Arbiter) claim(
+	ctx context.Context,
+	name resource.Name,
+	src Source,
+	declared bool,
+	hold time.Duration,
+) (*control, error) {
+	for {
+		a.mu.Lock()
+		now := clock.Now()
+		c := a.current(name, now)
+		switch {
+		case c == nil:
+			if !declared {
+				return nil, nil
+			}
+			c = &control{src: src, since: now, running: map[*command]struct{}{}}
+			a.controls[name] = c
+			a.logger.CDebugw(ctx, "source took control", "resource", name, "source", src.Name, "priority", src.Priority)
+		case declared && c.src.Name == src.Name:
+			c.src = src
+		case declared && src.Priority > c.src.Priority:
+			a.logger.CInfow(ctx, "source preempted control",
+				"resource", name, "source", src.Name, "priority", src.Priority,
+				"preempted_source", c.src.Name, "preempted_priority", c.src.Priority)
+			for cmd := range c.running {
+				cmd.cancel(ErrPreempted)
+			}
+			c = &control{src: src, since: now, running: map[*command]struct{}{}}
+			a.controls[name] = c
+			a.notify()
+		default:
+			if !src.Queue {
+				owner := c.src
+				a.mu.Unlock()
+				return nil, status.Errorf(codes.Aborted, "%q is controlled by %q with priority %s",
+					name, owner.Name, owner.Priority)
+			}
+			changed := a.changed
+			var lapsed <-chan time.Time
+			if len(c.running) == 0 {
+				lapsed = clock.Robot().After(c.expires.Sub(now))
+			}
+			a.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return nil, status.FromContextError(ctx.Err()).Err()
+			case <-changed:
+			case <-lapsed:
+			}
+			continue
+		}
+		if expires := now.Add(hold); expires.After(c.expires) {
+			c.expires = expires
+		}
+		return c, nil
+	}
+}
+
+// begin arbitrates a command on the named component. The returned context is canceled
+// with ErrPreempted if a higher priority source takes control while the command runs,
+// and done must be called once the command finishes.
+func (a *Arbiter) begin(
+	ctx context.Context,
+	name resource.Name,
+	src Source,
+	declared bool,
+) (context.Context, func(), error) {
+	c, err := a.claim(ctx, name, src, declared, DefaultHoldTime)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c == nil {
+		a.mu.Unlock()
+		return ctx, func() {}, nil
+	}
+	cmdCtx, cancel := context.WithCancelCause(ctx)
+	cmd := &command{cancel: cancel}
+	c.running[cmd] = struct{}{}
+	a.mu.Unlock()
+	return cmdCtx, func() {
+		a.mu.Lock()
+		delete(c.running, cmd)
+		if expires := clock.Now().Add(DefaultHoldTime); expires.After(c.expires) {
+			c.expires = expires
+		}
+		a.notify()
+		a.mu.Unlock()
+		cancel(nil)
+	}, nil
+}
+
+// Acquire gives src control of the named component for at least hold, as if it had sent
+// a command. It fails if a source with a higher or equal priority has control, unless
+// src queues, in which case it waits for control.
+func (a *Arbiter) Acquire(ctx context.Context, name resource.Name, src Source, hold time.Duration) error {
+	if src.Name == "" {
+		return errors.New("source must have a name")
+	}
+	if src.Priority <= PriorityUndeclared {
+		return errors.Errorf("invalid command priority %s", src.Priority)
+	}
+	if _, err := a.claim(ctx, name, src, true, hold); err != nil {
+		return err
+	}
+	a.mu.Unlock()
+	return nil
+}
+
+// Release gives up the named source's control of the named component. Running commands
+// are not canceled.
+func (a *Arbiter) Release(name resource.Name, source string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c := a.current(name, clock.Now())
+	if c == nil || c.src.Name != source {
+		return errors.Errorf("%q is not controlled by %q", name, source)
+	}
+	delete(a.controls, name)
+	a.notify()
+	return nil
+}
+
+// Control returns the control of the named component, if any source has it.
+func (a *Arbiter) Control(name resource.Name) (Control, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	c := a.current(name, clock.Now())
+	if c == nil {
+		return Control{}, false
+	}
+	return Control{Resource: name, Source: c.src, Since: c.since}, true
+}
+
+// Controls returns the control of every component that a source has, sorted by component.
+func (a *Arbiter) Controls() []Control {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	now := clock.Now()
+	var controls []Control
+	for name := range a.controls {
+		if c := a.current(name, now); c != nil {
+			controls = append(controls, Control{Resource: name, Source: c.src, Since: c.since})
+		}
+	}
+	sort.Slice(controls, func(i, j int) bool {
+		return controls[i].Resource.String() < controls[j].Resource.String()
+	})
+	return controls
+}
+
+// readPrefixes are the prefixes of methods that only read state.
+var readPrefixes = []string{
+	"Get", "Is", "Read", "Stream", "Next", "Render", "Chunks", "List", "Properties", "Capture", "PWM",
+}
+
+// IsCommand reports whether the resource API method of the given name commands its resource,
+// rather than only reading its state, and so is arbitrated. Stop is not a command.
+func IsCommand(method string) bool {
+	if method == "Stop" {
+		return false
+	}
+	for _, prefix := range readPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// componentName returns the component a call of fullMethod with the given request is for,
+// if it is one.
+func (a *Arbiter) componentName(fullMethod string, req interface{}) (resource.Name, bool) {
+	named, ok := req.(interface{ GetName() string })
+	if !ok || named.GetName() == "" {
+		return resource.Name{}, false
+	}
+	service := strings.TrimPrefix(path.Dir(fullMethod), "/")
+	cached, ok := a.apis.Load(service)
+	if !ok {
+		var found *resource.API
+		for api, reg := range resource.RegisteredAPIs() {
+			if reg.RPCServiceDesc != nil && reg.RPCServiceDesc.ServiceName == service {
+				api := api
+				found = &api
+				break
+			}
+		}
+		cached, _ = a.apis.LoadOrStore(service, found)
+	}
+	api, _ := cached.(*resource.API)
+	if api == nil || !api.IsComponent() {
+		return resource.Name{}, false
+	}
+	return resource.NewName(*api, named.GetName()), true
+}
+
+// UnaryServerInterceptor arbitrates unary commands on components.
+func (a *Arbiter) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if !IsCommand(path.Base(info.FullMethod)) {
+		return handler(ctx, req)
+	}
+	name, ok := a.componentName(info.FullMethod, req)
+	if !ok {
+		return handler(ctx, req)
+	}
+	src, declared, err := sourceFromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	cmdCtx, done, err := a.begin(ctx, name, src, declared)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	resp, err := handler(cmdCtx, req)
+	if errors.Is(context.Cause(cmdCtx), ErrPreempted) && ctx.Err() == nil {
+		return nil, status.Error(codes.Aborted, ErrPreempted.Error())
+	}
+	return resp, err
+}
+
+// CommandKey is the DoCommand key used to query and change the control of a component. Its
+// value may contain an "action": "acquire" takes control for the caller's declared source
+// for "hold_sec" seconds, and "release" gives it up. The response always contains the
+// component's "control", which is null if no source has it.
+const CommandKey = "command_arbitration"
+
+// CommandUnaryServerInterceptor handles DoCommand requests containing CommandKey instead
+// of passing them to the component.
+func (a *Arbiter) CommandUnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	cmdReq, ok := req.(*commonpb.DoCommandRequest)
+	if !ok {
+		return handler(ctx, req)
+	}
+	cmd, ok := cmdReq.GetCommand().AsMap()[CommandKey]
+	if !ok {
+		return handler(ctx, req)
+	}
+	name, ok := a.componentName(info.FullMethod, req)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	var args struct {
+		Action  string  `json:"action"`
+		HoldSec float64 `json:"hold_sec"`
+	}
+	raw, err := json.Marshal(cmd)
+	if err == nil {
+		err = json.Unmarshal(raw, &args)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s command: %v", CommandKey, err)
+	}
+	if args.Action != "" {
+		src, declared, err := sourceFromIncomingContext(ctx)
+		if err == nil && !declared {
+			err = errors.Errorf("%s must be set to %s", SourceMetadataKey, args.Action)
+		}
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		switch args.Action {
+		case "acquire":
+			hold := time.Duration(args.HoldSec * float64(time.Second))
+			if err := a.Acquire(ctx, name, src, hold); err != nil {
+				if _, ok := status.FromError(err); ok {
+					return nil, err
+				}
+				return nil, status.Error(codes.InvalidArgument, err.Error())
+			}
+		case "release":
+			if err := a.Release(name, src.Name); err != nil {
+				return nil, status.Error(codes.FailedPrecondition, err.Error())
+			}
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unknown %s action %q", CommandKey, args.Action)
+		}
+	}
+
+	result := map[string]interface{}{"control": nil}
+	if c, ok := a.Control(name); ok {
+		result["control"] = map[string]interface{}{
+			"source":   c.Source.Name,
+			"priority": c.Source.Priority.String(),
+			"since":    c.Since.Format(time.RFC3339Nano),
+		}
+	}
+	resp, err := structpb.NewStruct(result)
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: resp}, nil
+}
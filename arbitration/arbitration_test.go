@@ -0,0 +1,190 @@
+package arbitration_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonpb "go.viam.com/api/common/v1"
+	armpb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/arbitration"
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/logging"
+)
+
+// incoming returns a context as the server sees a call declaring the given source.
+func incoming(src *arbitration.Source) context.Context {
+	ctx := context.Background()
+	if src == nil {
+		return ctx
+	}
+	md, _ := metadata.FromOutgoingContext(arbitration.ContextWithSource(ctx, *src))
+	return metadata.NewIncomingContext(ctx, md)
+}
+
+func armCall(
+	ctx context.Context,
+	arbiter *arbitration.Arbiter,
+	method string,
+	handler grpc.UnaryHandler,
+) error {
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/" + method}
+	if handler == nil {
+		handler = func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &armpb.MoveToPositionResponse{}, nil
+		}
+	}
+	_, err := arbiter.UnaryServerInterceptor(ctx, &armpb.MoveToPositionRequest{Name: "arm1"}, info, handler)
+	return err
+}
+
+func controlCommand(
+	t *testing.T,
+	ctx context.Context,
+	arbiter *arbitration.Arbiter,
+	cmd map[string]interface{},
+) (map[string]interface{}, error) {
+	t.Helper()
+	command, err := structpb.NewStruct(map[string]interface{}{arbitration.CommandKey: cmd})
+	test.That(t, err, test.ShouldBeNil)
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/DoCommand"}
+	resp, err := arbiter.CommandUnaryServerInterceptor(ctx, &commonpb.DoCommandRequest{Name: "arm1", Command: command}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			t.Fatal("command should not reach the component")
+			return nil, nil
+		})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*commonpb.DoCommandResponse).GetResult().AsMap(), nil
+}
+
+func TestPriority(t *testing.T) {
+	for _, p := range []arbitration.Priority{arbitration.PriorityAutonomous, arbitration.PriorityTeleop, arbitration.PrioritySafety, 15} {
+		parsed, err := arbitration.ParsePriority(p.String())
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, parsed, test.ShouldEqual, p)
+	}
+	for _, invalid := range []string{"undeclared", "0", "-1", "urgent"} {
+		_, err := arbitration.ParsePriority(invalid)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+}
+
+func TestArbitration(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+	arbiter := arbitration.NewArbiter(logging.NewTestLogger(t))
+
+	planner := incoming(&arbitration.Source{Name: "planner", Priority: arbitration.PriorityAutonomous})
+	other := incoming(&arbitration.Source{Name: "other", Priority: arbitration.PriorityAutonomous})
+	operator := incoming(&arbitration.Source{Name: "operator", Priority: arbitration.PriorityTeleop})
+	undeclared := incoming(nil)
+
+	t.Run("undeclared commands are served while no source has control", func(t *testing.T) {
+		test.That(t, armCall(undeclared, arbiter, "MoveToPosition", nil), test.ShouldBeNil)
+		_, ok := arbiter.Control(arm.Named("arm1"))
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+
+	t.Run("first source takes control", func(t *testing.T) {
+		test.That(t, armCall(planner, arbiter, "MoveToPosition", nil), test.ShouldBeNil)
+		control, ok := arbiter.Control(arm.Named("arm1"))
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, control.Source.Name, test.ShouldEqual, "planner")
+		test.That(t, arbiter.Controls(), test.ShouldHaveLength, 1)
+
+		for _, ctx := range []context.Context{other, undeclared} {
+			err := armCall(ctx, arbiter, "MoveToPosition", nil)
+			test.That(t, status.Code(err), test.ShouldEqual, codes.Aborted)
+			test.That(t, err.Error(), test.ShouldContainSubstring, `controlled by "planner"`)
+		}
+		// reads and stops are always served.
+		test.That(t, armCall(other, arbiter, "GetEndPosition", nil), test.ShouldBeNil)
+		test.That(t, armCall(undeclared, arbiter, "Stop", nil), test.ShouldBeNil)
+	})
+
+	t.Run("control lapses after the hold time", func(t *testing.T) {
+		virtual.Step(arbitration.DefaultHoldTime)
+		_, ok := arbiter.Control(arm.Named("arm1"))
+		test.That(t, ok, test.ShouldBeFalse)
+		test.That(t, armCall(other, arbiter, "MoveToPosition", nil), test.ShouldBeNil)
+		virtual.Step(arbitration.DefaultHoldTime)
+	})
+
+	t.Run("higher priority preempts running commands", func(t *testing.T) {
+		started := make(chan struct{})
+		preempted := make(chan error, 1)
+		go func() {
+			preempted <- armCall(planner, arbiter, "MoveToPosition", func(ctx context.Context, req interface{}) (interface{}, error) {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			})
+		}()
+		<-started
+		test.That(t, armCall(operator, arbiter, "MoveToPosition", nil), test.ShouldBeNil)
+		err := <-preempted
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Aborted)
+		test.That(t, err.Error(), test.ShouldContainSubstring, arbitration.ErrPreempted.Error())
+
+		control, ok := arbiter.Control(arm.Named("arm1"))
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, control.Source.Name, test.ShouldEqual, "operator")
+		test.That(t, control.Source.Priority, test.ShouldEqual, arbitration.PriorityTeleop)
+	})
+
+	t.Run("queued commands wait for control", func(t *testing.T) {
+		queued := make(chan error, 1)
+		go func() {
+			queued <- armCall(
+				incoming(&arbitration.Source{Name: "planner", Priority: arbitration.PriorityAutonomous, Queue: true}),
+				arbiter, "MoveToPosition", nil)
+		}()
+		select {
+		case err := <-queued:
+			t.Fatalf("queued command was served while another source had control: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		_, err := controlCommand(t, operator, arbiter, map[string]interface{}{"action": "release"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, <-queued, test.ShouldBeNil)
+		control, ok := arbiter.Control(arm.Named("arm1"))
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, control.Source.Name, test.ShouldEqual, "planner")
+	})
+
+	t.Run("control can be queried and acquired", func(t *testing.T) {
+		result, err := controlCommand(t, undeclared, arbiter, map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result["control"].(map[string]interface{})["source"], test.ShouldEqual, "planner")
+
+		_, err = controlCommand(t, undeclared, arbiter, map[string]interface{}{"action": "acquire"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+		_, err = controlCommand(t, other, arbiter, map[string]interface{}{"action": "release"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+
+		safety := incoming(&arbitration.Source{Name: "estop", Priority: arbitration.PrioritySafety})
+		result, err = controlCommand(t, safety, arbiter, map[string]interface{}{"action": "acquire", "hold_sec": 60})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, result["control"], test.ShouldResemble, map[string]interface{}{
+			"source":   "estop",
+			"priority": "safety",
+			"since":    virtual.Now().Format(time.RFC3339Nano),
+		})
+		virtual.Step(30 * time.Second)
+		err = armCall(operator, arbiter, "MoveToPosition", nil)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Aborted)
+		virtual.Step(30 * time.Second)
+		test.That(t, armCall(operator, arbiter, "MoveToPosition", nil), test.ShouldBeNil)
+	})
+}
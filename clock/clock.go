@@ -0,0 +1,105 @@
+// Package clock provides the robot-wide clock used by fake components, the data manager,
+// and services. It is the system clock unless a virtual clock has been installed, which
+// lets time-dependent behavior be tested deterministically and quickly.
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+)
+
+// Clock is the interface to the time functions used throughout the robot.
+type Clock = clk.Clock
+
+var (
+	currentMu sync.RWMutex
+	current   = clk.New()
+
+	robotClock Clock = forwardingClock{}
+)
+
+// Robot returns the robot-wide clock. Every call on it is forwarded to whichever clock is
+// installed at the time of the call, so it is safe to hold on to.
+func Robot() Clock {
+	return robotClock
+}
+
+// Set installs c as the robot-wide clock and returns a function that restores the clock
+// that was previously installed.
+func Set(c Clock) (restore func()) {
+	currentMu.Lock()
+	defer currentMu.Unlock()
+	prev := current
+	current = c
+	return func() {
+		currentMu.Lock()
+		defer currentMu.Unlock()
+		current = prev
+	}
+}
+
+func installed() Clock {
+	currentMu.RLock()
+	defer currentMu.RUnlock()
+	return current
+}
+
+// Now returns the current time according to the robot-wide clock.
+func Now() time.Time {
+	return installed().Now()
+}
+
+// Since returns the time elapsed since t according to the robot-wide clock.
+func Since(t time.Time) time.Duration {
+	return installed().Since(t)
+}
+
+// SleepContext waits for the given duration on the robot-wide clock. It returns false if
+// the context is done before the duration elapses.
+func SleepContext(ctx context.Context, dur time.Duration) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	timer := installed().Timer(dur)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// forwardingClock forwards every call to the installed clock.
+type forwardingClock struct{}
+
+func (forwardingClock) After(d time.Duration) <-chan time.Time { return installed().After(d) }
+
+func (forwardingClock) AfterFunc(d time.Duration, f func()) *clk.Timer {
+	return installed().AfterFunc(d, f)
+}
+
+func (forwardingClock) Now() time.Time { return installed().Now() }
+
+func (forwardingClock) Since(t time.Time) time.Duration { return installed().Since(t) }
+
+func (forwardingClock) Until(t time.Time) time.Duration { return installed().Until(t) }
+
+func (forwardingClock) Sleep(d time.Duration) { installed().Sleep(d) }
+
+func (forwardingClock) Tick(d time.Duration) <-chan time.Time { return installed().Tick(d) }
+
+func (forwardingClock) Ticker(d time.Duration) *clk.Ticker { return installed().Ticker(d) }
+
+func (forwardingClock) Timer(d time.Duration) *clk.Timer { return installed().Timer(d) }
+
+func (forwardingClock) WithDeadline(parent context.Context, d time.Time) (context.Context, context.CancelFunc) {
+	return installed().WithDeadline(parent, d)
+}
+
+func (forwardingClock) WithTimeout(parent context.Context, t time.Duration) (context.Context, context.CancelFunc) {
+	return installed().WithTimeout(parent, t)
+}
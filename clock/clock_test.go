@@ -0,0 +1,72 @@
+package clock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+)
+
+func TestSet(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	virtual := NewVirtual(start)
+	held := Robot()
+
+	restore := Set(virtual)
+	test.That(t, Now(), test.ShouldEqual, start)
+	test.That(t, held.Now(), test.ShouldEqual, start)
+
+	virtual.Step(time.Hour)
+	test.That(t, Since(start), test.ShouldEqual, time.Hour)
+	test.That(t, held.Since(start), test.ShouldEqual, time.Hour)
+
+	restore()
+	test.That(t, Now().After(start.Add(time.Hour)), test.ShouldBeTrue)
+	test.That(t, held.Now().After(start.Add(time.Hour)), test.ShouldBeTrue)
+}
+
+func TestSleepContext(t *testing.T) {
+	virtual := NewVirtual(time.Now())
+	defer Set(virtual)()
+
+	t.Run("stepped", func(t *testing.T) {
+		done := make(chan bool, 1)
+		go func() {
+			done <- SleepContext(context.Background(), time.Minute)
+		}()
+		test.That(t, virtual.StepUntil(time.Second, 2*time.Minute, func() bool { return len(done) == 1 }), test.ShouldBeTrue)
+		test.That(t, <-done, test.ShouldBeTrue)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan bool, 1)
+		go func() {
+			done <- SleepContext(ctx, time.Minute)
+		}()
+		cancel()
+		test.That(t, <-done, test.ShouldBeFalse)
+		test.That(t, SleepContext(ctx, time.Minute), test.ShouldBeFalse)
+	})
+}
+
+func TestAccelerate(t *testing.T) {
+	start := time.Now()
+	virtual := NewVirtual(start)
+
+	virtual.Accelerate(context.Background(), 1000, time.Millisecond)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, virtual.Now().Sub(start), test.ShouldBeGreaterThanOrEqualTo, 10*time.Second)
+	})
+
+	virtual.Decelerate()
+	stopped := virtual.Now()
+	time.Sleep(10 * time.Millisecond)
+	test.That(t, virtual.Now(), test.ShouldEqual, stopped)
+
+	virtual.Step(time.Second)
+	test.That(t, virtual.Now(), test.ShouldEqual, stopped.Add(time.Second))
+}
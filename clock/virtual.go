@@ -0,0 +1,82 @@
+package clock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+	"go.viam.com/utils"
+)
+
+// A Virtual clock only moves forward when it is stepped or accelerated. Timers and
+// tickers created from it fire in order as time passes over them.
+type Virtual struct {
+	*clk.Mock
+
+	mu           sync.Mutex
+	cancelAccel  func()
+	accelWorkers sync.WaitGroup
+}
+
+// NewVirtual returns a virtual clock starting at the given time.
+func NewVirtual(start time.Time) *Virtual {
+	mock := clk.NewMock()
+	mock.Set(start)
+	return &Virtual{Mock: mock}
+}
+
+// Step moves the clock forward by the given duration, firing any timers that come due.
+func (v *Virtual) Step(d time.Duration) {
+	v.Add(d)
+}
+
+// StepUntil moves the clock forward in increments of step until done returns true or
+// limit has elapsed on the clock. It returns whether done returned true.
+func (v *Virtual) StepUntil(step, limit time.Duration, done func() bool) bool {
+	for elapsed := time.Duration(0); elapsed < limit; elapsed += step {
+		if done() {
+			return true
+		}
+		v.Add(step)
+	}
+	return done()
+}
+
+// Accelerate moves the clock forward by factor times the real time that passes, in
+// increments of tick of real time, until the context is done or Decelerate is called.
+// Calling Accelerate again replaces the previous acceleration.
+func (v *Virtual) Accelerate(ctx context.Context, factor float64, tick time.Duration) {
+	v.Decelerate()
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	cancelCtx, cancel := context.WithCancel(ctx)
+	v.cancelAccel = cancel
+	step := time.Duration(float64(tick) * factor)
+	v.accelWorkers.Add(1)
+	utils.ManagedGo(func() {
+		ticker := time.NewTicker(tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-cancelCtx.Done():
+				return
+			case <-ticker.C:
+			}
+			v.Add(step)
+		}
+	}, v.accelWorkers.Done)
+}
+
+// Decelerate stops any acceleration so that the clock only moves when stepped.
+func (v *Virtual) Decelerate() {
+	v.mu.Lock()
+	cancel := v.cancelAccel
+	v.cancelAccel = nil
+	v.mu.Unlock()
+	if cancel != nil {
+		cancel()
+		v.accelWorkers.Wait()
+	}
+}
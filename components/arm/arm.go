@@ -25,6 +25,10 @@ import (
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Arm]{
 		Status:                      resource.StatusFunc(CreateStatus),
+		SelfTest:                    SelfTest,
+		Shutdown:                    Shutdown,
+		Limit:                       Limit,
+		Calibrate:                   Calibrate,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterArmServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.ArmService_ServiceDesc,
@@ -230,3 +234,32 @@ func CheckDesiredJointPositions(ctx context.Context, a Arm, desiredInputs []refe
 	}
 	return nil
 }
+
+// SelfTest checks that the arm is functional by querying its joint positions.
+func SelfTest(ctx context.Context, a Arm) error {
+	_, err := a.JointPositions(ctx, nil)
+	return err
+}
+
+// Shutdown moves the arm to the "joint_positions", in degrees, of the action so that it is
+// left in a safe pose. Other actions are sent to the arm with DoCommand.
+func Shutdown(ctx context.Context, a Arm, action map[string]interface{}) error {
+	positions, ok := action["joint_positions"]
+	if !ok {
+		_, err := a.DoCommand(ctx, action)
+		return err
+	}
+	raw, ok := positions.([]interface{})
+	if !ok {
+		return fmt.Errorf("shutdown joint_positions must be a list of degrees, got %v", positions)
+	}
+	degrees := make([]float64, 0, len(raw))
+	for _, v := range raw {
+		deg, ok := v.(float64)
+		if !ok {
+			return fmt.Errorf("shutdown joint_positions must be a list of degrees, got %v", positions)
+		}
+		degrees = append(degrees, deg)
+	}
+	return a.MoveToJointPositions(ctx, &pb.JointPositions{Values: degrees}, nil)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/golang/geo/r3"
@@ -586,3 +587,117 @@ func locationCheckTestHelper(geomList []spatialmath.Geometry, checkMap map[strin
 	}
 	return true
 }
+
+func TestLimit(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{
+		Name:                testArmName,
+		Model:               resource.DefaultModelFamily.WithModel("ur5e"),
+		ConvertedAttributes: &fake.Config{ArmModel: "ur5e"},
+	}
+	notReal, err := fake.NewArm(ctx, nil, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	var steps [][]float64
+	injectedArm := &inject.Arm{Arm: notReal}
+	injectedArm.MoveToJointPositionsFunc = func(ctx context.Context, pos *pb.JointPositions, extra map[string]interface{}) error {
+		steps = append(steps, pos.Values)
+		return notReal.MoveToJointPositions(ctx, pos, extra)
+	}
+	home, err := notReal.EndPosition(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	var violations []error
+	limits := resource.MotionLimits{
+		MaxJointVelocityDegsPerSec: []float64{200},
+		Workspace: &resource.Workspace{
+			Min: home.Point().Sub(r3.Vector{X: 100, Y: 100, Z: 100}),
+			Max: home.Point().Add(r3.Vector{X: 100, Y: 100, Z: 100}),
+		},
+	}
+	limited := arm.Limit(injectedArm, resource.NewMotionLimiter(arm.Named(testArmName), limits, logger,
+		func(err error) { violations = append(violations, err) }))
+
+	t.Run("joint velocity", func(t *testing.T) {
+		// 20 degrees at 200 degrees per second takes at least 100ms.
+		start := time.Now()
+		err := limited.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{0, 0, 0, 0, 0, 20}}, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 90*time.Millisecond)
+		test.That(t, steps, test.ShouldHaveLength, 20)
+		test.That(t, steps[0][5], test.ShouldAlmostEqual, 1)
+		test.That(t, steps[19], test.ShouldResemble, []float64{0, 0, 0, 0, 0, 20})
+		test.That(t, violations, test.ShouldBeEmpty)
+	})
+
+	t.Run("workspace", func(t *testing.T) {
+		steps = nil
+		far := spatialmath.NewPoseFromPoint(home.Point().Add(r3.Vector{X: 500}))
+		err := limited.MoveToPosition(ctx, far, nil)
+		test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+
+		// the shoulder turned by 90 degrees swings the end effector out of the workspace.
+		err = limited.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{90, 0, 0, 0, 0, 0}}, nil)
+		test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "outside the workspace")
+		test.That(t, steps, test.ShouldBeEmpty)
+		test.That(t, violations, test.ShouldHaveLength, 2)
+	})
+
+	err = limited.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{0, 0, 0}}, nil)
+	test.That(t, err, test.ShouldBeError, "arm has 6 joints, not 3")
+}
+
+func TestCalibrateSelfCollision(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := resource.Config{
+		Name:                testArmName,
+		Model:               resource.DefaultModelFamily.WithModel("ur5e"),
+		ConvertedAttributes: &fake.Config{ArmModel: "ur5e"},
+	}
+	notReal, err := fake.NewArm(ctx, nil, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	var moves int
+	injectedArm := &inject.Arm{Arm: notReal}
+	injectedArm.MoveToJointPositionsFunc = func(ctx context.Context, pos *pb.JointPositions, extra map[string]interface{}) error {
+		moves++
+		return notReal.MoveToJointPositions(ctx, pos, extra)
+	}
+	injectedArm.GoToInputsFunc = func(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+		moves++
+		return notReal.GoToInputs(ctx, inputSteps...)
+	}
+
+	// the kinematics of the arm have no geometries of their own.
+	kinematics := resource.NewKinematics(arm.Named(testArmName), resource.KinematicsConfig{
+		File:          utils.ResolveFile("referenceframe/testjson/ur5eDH.json"),
+		SelfCollision: &resource.SelfCollisionConfig{MonitorExecution: true},
+	}, logger)
+	calibrated := arm.Calibrate(injectedArm, kinematics)
+	geometries, err := calibrated.Geometries(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometries, test.ShouldHaveLength, 6)
+
+	test.That(t, calibrated.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{0, 0, 20, 0, 0, 0}}, nil), test.ShouldBeNil)
+	test.That(t, moves, test.ShouldEqual, 1)
+
+	// folding the forearm back onto the upper arm is refused before the arm moves.
+	err = calibrated.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{0, 0, 175, 0, 0, 0}}, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "collision with itself")
+	err = calibrated.GoToInputs(ctx,
+		referenceframe.FloatsToInputs([]float64{0, 0, 0, 0, 0, 0}),
+		referenceframe.FloatsToInputs([]float64{0, 0, utils.DegToRad(175), 0, 0, 0}),
+	)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, moves, test.ShouldEqual, 1)
+
+	// without monitoring, only plans are checked.
+	unmonitored := arm.Calibrate(injectedArm, resource.NewKinematics(arm.Named(testArmName), resource.KinematicsConfig{
+		File:          utils.ResolveFile("referenceframe/testjson/ur5eDH.json"),
+		SelfCollision: &resource.SelfCollisionConfig{},
+	}, logger))
+	test.That(t, unmonitored.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{0, 0, 175, 0, 0, 0}}, nil), test.ShouldBeNil)
+	test.That(t, moves, test.ShouldEqual, 2)
+}
@@ -19,6 +19,9 @@ import (
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/referenceframe/urdf"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/tools"
+	"go.viam.com/rdk/simulation/imperfection"
+	"go.viam.com/rdk/simulation/script"
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -40,6 +43,12 @@ var dofbotjson []byte
 type Config struct {
 	ArmModel      string `json:"arm-model,omitempty"`
 	ModelFilePath string `json:"model-path,omitempty"`
+	// Scenario scripts the joint positions over time. Moves are accepted but the reported
+	// joint positions follow the scenario.
+	Scenario *script.Config `json:"scenario,omitempty"`
+	// Imperfections simulates the latency, noise, drift, and dropouts of a real arm. Noise
+	// and drift apply to the joint positions, in degrees.
+	Imperfections *imperfection.Config `json:"imperfections,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -53,13 +62,32 @@ func (conf *Config) Validate(path string) ([]string, error) {
 	case conf.ArmModel == "" && conf.ModelFilePath != "":
 		_, err = modelFromPath(conf.ModelFilePath, "")
 	}
+	if err == nil && conf.Scenario != nil {
+		err = conf.Scenario.Validate(path)
+	}
+	if err == nil && conf.Imperfections != nil {
+		err = conf.Imperfections.Validate(path)
+	}
 	return nil, err
 }
 
+// simulatedModels are the models of the arms in the RDK that the fake arm simulates, with their
+// kinematics only, when the robot runs in simulation.
+var simulatedModels = []resource.Model{
+	ur.Model,
+	eva.Model,
+	resource.DefaultModelFamily.WithModel(xarm.ModelName6DOF),
+	resource.DefaultModelFamily.WithModel(xarm.ModelName7DOF),
+	resource.DefaultModelFamily.WithModel(xarm.ModelNameLite),
+}
+
 func init() {
 	resource.RegisterComponent(arm.API, Model, resource.Registration[arm.Arm, *Config]{
 		Constructor: NewArm,
 	})
+	for _, model := range simulatedModels {
+		resource.RegisterSimulator(arm.API, model, newSimulatedArm)
+	}
 }
 
 // NewArm returns a new fake arm.
@@ -74,6 +102,29 @@ func NewArm(ctx context.Context, deps resource.Dependencies, conf resource.Confi
 	return a, nil
 }
 
+// simulatedArm is a fake arm simulating the real arm model of its config.
+type simulatedArm struct {
+	*Arm
+}
+
+func newSimulatedArm(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (arm.Arm, error) {
+	a := &simulatedArm{&Arm{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+	}}
+	if err := a.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Reconfigure reconfigures the arm with the kinematics of the model of the config, ignoring
+// the attributes of the real arm.
+func (a *simulatedArm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	conf.ConvertedAttributes = &Config{ArmModel: conf.Model.Name}
+	return a.Arm.Reconfigure(ctx, deps, conf)
+}
+
 func buildModel(cfg resource.Config, newConf *Config) (referenceframe.Model, error) {
 	var (
 		model referenceframe.Model
@@ -103,11 +154,16 @@ type Arm struct {
 	CloseCount int
 	logger     logging.Logger
 
-	mu     sync.RWMutex
-	joints *pb.JointPositions
-	model  referenceframe.Model
+	mu      sync.RWMutex
+	joints  *pb.JointPositions
+	model   referenceframe.Model
+	player  *script.Player
+	inj     *imperfection.Injector
+	payload tools.Payload
 }
 
+var _ tools.PayloadSetter = (*Arm)(nil)
+
 // Reconfigure atomically reconfigures this arm in place based on the new config.
 func (a *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
 	newConf, err := resource.NativeConfig[*Config](conf)
@@ -127,10 +183,20 @@ func (a *Arm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf
 			"the arm-model and model-path from attributes")
 	}
 
+	player, err := newConf.Scenario.NewPlayer()
+	if err != nil {
+		return err
+	}
+	if player != nil && player.Script().NumJoints() != dof {
+		return errors.Errorf("fake arm scenario scripts %d joints but the arm has %d", player.Script().NumJoints(), dof)
+	}
+
 	a.mu.Lock()
 	defer a.mu.Unlock()
 	a.joints = &pb.JointPositions{Values: make([]float64, dof)}
 	a.model = model
+	a.player = player
+	a.inj = newConf.Imperfections.NewInjector()
 
 	return nil
 }
@@ -160,6 +226,12 @@ func (a *Arm) MoveToPosition(ctx context.Context, pos spatialmath.Pose, extra ma
 
 // MoveToJointPositions sets the joints.
 func (a *Arm) MoveToJointPositions(ctx context.Context, joints *pb.JointPositions, extra map[string]interface{}) error {
+	a.mu.RLock()
+	inj := a.inj
+	a.mu.RUnlock()
+	if err := inj.Call(ctx); err != nil {
+		return err
+	}
 	inputs := a.model.InputFromProtobuf(joints)
 	if err := arm.CheckDesiredJointPositions(ctx, a, inputs); err != nil {
 		return err
@@ -177,6 +249,19 @@ func (a *Arm) MoveToJointPositions(ctx context.Context, joints *pb.JointPosition
 
 // JointPositions returns joints.
 func (a *Arm) JointPositions(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+	a.mu.RLock()
+	player := a.player
+	inj := a.inj
+	a.mu.RUnlock()
+	if err := inj.Call(ctx); err != nil {
+		return nil, err
+	}
+	if player != nil {
+		return &pb.JointPositions{Values: inj.Values(player.State().JointsDeg)}, nil
+	}
+	if inj.Perturbs() {
+		return &pb.JointPositions{Values: inj.Values(a.joints.Values)}, nil
+	}
 	retJoint := &pb.JointPositions{Values: a.joints.Values}
 	return retJoint, nil
 }
@@ -186,11 +271,50 @@ func (a *Arm) Stop(ctx context.Context, extra map[string]interface{}) error {
 	return nil
 }
 
-// IsMoving is always false for a fake arm.
+// IsMoving is false for a fake arm unless its scenario is moving the joints.
 func (a *Arm) IsMoving(ctx context.Context) (bool, error) {
+	a.mu.RLock()
+	player := a.player
+	a.mu.RUnlock()
+	if player != nil {
+		return player.State().Moving, nil
+	}
 	return false, nil
 }
 
+// Snapshot returns the joint positions of the arm.
+func (a *Arm) Snapshot(ctx context.Context) (map[string]interface{}, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return map[string]interface{}{"joints_deg": append([]float64{}, a.joints.Values...)}, nil
+}
+
+// Restore sets the joint positions of the arm to ones returned by Snapshot.
+func (a *Arm) Restore(ctx context.Context, state map[string]interface{}) error {
+	var joints []float64
+	switch values := state["joints_deg"].(type) {
+	case []float64:
+		joints = values
+	case []interface{}:
+		for _, value := range values {
+			joint, ok := value.(float64)
+			if !ok {
+				return errors.Errorf("expected joints_deg to contain numbers but got %T", value)
+			}
+			joints = append(joints, joint)
+		}
+	default:
+		return errors.Errorf("expected joints_deg to be a list of numbers but got %T", values)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if len(joints) != len(a.joints.Values) {
+		return errors.Errorf("snapshot has %d joints but the arm has %d", len(joints), len(a.joints.Values))
+	}
+	copy(a.joints.Values, joints)
+	return nil
+}
+
 // CurrentInputs TODO.
 func (a *Arm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
 	res, err := a.JointPositions(ctx, nil)
@@ -217,6 +341,21 @@ func (a *Arm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Inp
 	return nil
 }
 
+// SetPayload records the payload of the tool mounted on the arm.
+func (a *Arm) SetPayload(ctx context.Context, payload tools.Payload) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.payload = payload
+	return nil
+}
+
+// Payload returns the payload of the tool mounted on the arm.
+func (a *Arm) Payload() tools.Payload {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.payload
+}
+
 // Close does nothing.
 func (a *Arm) Close(ctx context.Context) error {
 	a.mu.Lock()
@@ -3,13 +3,17 @@ package fake
 import (
 	"context"
 	"testing"
+	"time"
 
 	pb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/simulation/imperfection"
+	"go.viam.com/rdk/utils"
 )
 
 func TestReconfigure(t *testing.T) {
@@ -101,3 +105,80 @@ func TestReconfigure(t *testing.T) {
 	test.That(t, fakeArm.joints.Values, test.ShouldResemble, modelJoints)
 	test.That(t, fakeArm.model, test.ShouldResemble, model)
 }
+
+func TestScenario(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	attrs := utils.AttributeMap{
+		"arm-model": "ur5e",
+		"scenario": map[string]interface{}{
+			"keyframes": []interface{}{
+				map[string]interface{}{"at_sec": 0, "joints_deg": []interface{}{0, 0, 0, 0, 0, 0}},
+				map[string]interface{}{"at_sec": 10, "joints_deg": []interface{}{60, 0, 0, 0, 0, -30}},
+			},
+		},
+	}
+	conf, err := resource.TransformAttributeMap[*Config](attrs)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	a, err := NewArm(ctx, nil, resource.Config{Name: "testArm", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	virtual.Step(5 * time.Second)
+	joints, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints.Values, test.ShouldResemble, []float64{30, 0, 0, 0, 0, -15})
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+
+	virtual.Step(5 * time.Second)
+	moving, err = a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	conf.Scenario.Keyframes = conf.Scenario.Keyframes[:1]
+	conf.Scenario.Keyframes[0].JointsDeg = []float64{0}
+	err = a.Reconfigure(ctx, nil, resource.Config{Name: "testArm", ConvertedAttributes: conf})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "scripts 1 joints but the arm has 6")
+}
+
+func TestImperfections(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	attrs := utils.AttributeMap{
+		"arm-model":     "ur5e",
+		"imperfections": map[string]interface{}{"drift_per_sec": 0.5},
+	}
+	conf, err := resource.TransformAttributeMap[*Config](attrs)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	a, err := NewArm(ctx, nil, resource.Config{Name: "testArm", ConvertedAttributes: conf}, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	virtual.Step(2 * time.Second)
+	joints, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints.Values, test.ShouldResemble, []float64{1, 1, 1, 1, 1, 1})
+
+	conf.Imperfections.DropoutProbability = 1
+	test.That(t, a.Reconfigure(ctx, nil, resource.Config{Name: "testArm", ConvertedAttributes: conf}), test.ShouldBeNil)
+	_, err = a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeError, imperfection.ErrDropout)
+
+	conf.Imperfections.DropoutProbability = 2
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "dropout_probability")
+}
@@ -0,0 +1,143 @@
+//go:build !no_cgo
+
+package arm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// selfCollisionChecks is how many configurations along the way to each joint positions an arm
+// monitoring its execution checks for collisions with itself.
+const selfCollisionChecks = 20
+
+var errNoConfiguredModel = errors.New("the configured kinematics of the arm could not be loaded")
+
+// Calibrate returns the arm with the kinematic model given by the kinematics in place of its
+// own. Its end position, geometries and moves to positions are those of that model. If the
+// kinematics monitor execution for self collisions, moves of the arm that would take it into
+// collision with itself are refused before it moves.
+func Calibrate(a Arm, kinematics *resource.Kinematics) Arm {
+	return calibratedArm{Arm: a, kinematics: kinematics}
+}
+
+type calibratedArm struct {
+	Arm
+	kinematics *resource.Kinematics
+}
+
+func (a calibratedArm) ModelFrame() referenceframe.Model {
+	return a.kinematics.Model(a.Arm.ModelFrame())
+}
+
+func (a calibratedArm) EndPosition(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+	model := a.ModelFrame()
+	if model == nil {
+		return nil, errNoConfiguredModel
+	}
+	joints, err := a.JointPositions(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	return motionplan.ComputeOOBPosition(model, joints)
+}
+
+func (a calibratedArm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+	if a.ModelFrame() == nil {
+		return errNoConfiguredModel
+	}
+	return Move(ctx, a.kinematics.Logger(), a, pose)
+}
+
+func (a calibratedArm) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	model := a.ModelFrame()
+	if model == nil {
+		return nil, errNoConfiguredModel
+	}
+	joints, err := a.JointPositions(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	gif, err := model.Geometries(model.InputFromProtobuf(joints))
+	if err != nil {
+		return nil, err
+	}
+	return gif.Geometries(), nil
+}
+
+func (a calibratedArm) MoveToJointPositions(ctx context.Context, positionDegs *pb.JointPositions, extra map[string]interface{}) error {
+	if a.monitored() {
+		model := a.ModelFrame()
+		if model == nil {
+			return errNoConfiguredModel
+		}
+		if err := a.checkSelfCollision(ctx, model, model.InputFromProtobuf(positionDegs)); err != nil {
+			return err
+		}
+	}
+	return a.Arm.MoveToJointPositions(ctx, positionDegs, extra)
+}
+
+func (a calibratedArm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	if a.monitored() {
+		model := a.ModelFrame()
+		if model == nil {
+			return errNoConfiguredModel
+		}
+		if err := a.checkSelfCollision(ctx, model, inputSteps...); err != nil {
+			return err
+		}
+	}
+	return a.Arm.GoToInputs(ctx, inputSteps...)
+}
+
+func (a calibratedArm) monitored() bool {
+	selfCollision := a.kinematics.Config().SelfCollision
+	return selfCollision != nil && selfCollision.MonitorExecution
+}
+
+// checkSelfCollision returns an error if the model would collide with itself moving from the
+// current joint positions of the arm through the steps. Collisions the arm is already in are
+// ignored.
+func (a calibratedArm) checkSelfCollision(ctx context.Context, model referenceframe.Model, inputSteps ...[]referenceframe.Input) error {
+	joints, err := a.JointPositions(ctx, nil)
+	if err != nil {
+		return err
+	}
+	from := model.InputFromProtobuf(joints)
+	geometries, err := model.Geometries(from)
+	if err != nil {
+		return err
+	}
+	if len(geometries.Geometries()) < 2 {
+		return nil
+	}
+	constraint, err := motionplan.NewCollisionConstraint(geometries.Geometries(), nil, nil, false, 0)
+	if err != nil {
+		return err
+	}
+	for _, step := range inputSteps {
+		for i := 1; i <= selfCollisionChecks; i++ {
+			inputs, err := model.Interpolate(from, step, float64(i)/selfCollisionChecks)
+			if err != nil {
+				return err
+			}
+			if !constraint(&ik.State{Configuration: inputs, Frame: model}) {
+				return errors.Errorf(
+					"moving arm to joint positions %v would take it into collision with itself",
+					model.ProtobufFromInput(step).Values,
+				)
+			}
+		}
+		from = step
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+//go:build !no_cgo
+
+package arm
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// limitPeriod is the longest step a speed limited arm is moved in at once.
+	limitPeriod = 50 * time.Millisecond
+	// workspaceChecks is how many points of a move along the way to its joint positions are
+	// checked to be within the workspace.
+	workspaceChecks = 20
+)
+
+var errNoModel = errors.New("the limits of an arm without a kinematic model cannot be enforced")
+
+// Limit returns the arm enforcing the motion limits of the limiter. Moves are split into
+// steps paced so that no joint, nor the end effector, moves faster than its limit, and moves
+// that would take the end effector out of the workspace are refused.
+func Limit(a Arm, limiter *resource.MotionLimiter) Arm {
+	return limitedArm{Arm: a, limiter: limiter}
+}
+
+type limitedArm struct {
+	Arm
+	limiter *resource.MotionLimiter
+}
+
+func (a limitedArm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+	if ws := a.limiter.Limits().Workspace; ws != nil && !ws.Contains(pose.Point()) {
+		return a.limiter.Violation(resource.LimitWorkspace, "position %v is outside the workspace", pose.Point())
+	}
+	// the planned waypoints go through GoToInputs, which paces them.
+	return Move(ctx, a.limiter.Logger(), a, pose)
+}
+
+func (a limitedArm) MoveToJointPositions(ctx context.Context, positionDegs *pb.JointPositions, extra map[string]interface{}) error {
+	return a.move(ctx, positionDegs.Values, extra)
+}
+
+func (a limitedArm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	model := a.ModelFrame()
+	if model == nil {
+		return errNoModel
+	}
+	for _, step := range inputSteps {
+		if err := a.move(ctx, model.ProtobufFromInput(step).Values, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// move moves the arm to the joint positions, in steps along the way to them that are each
+// checked against the workspace and take at least as long as the speed limits allow.
+func (a limitedArm) move(ctx context.Context, target []float64, extra map[string]interface{}) error {
+	limits := a.limiter.Limits()
+	current, err := a.JointPositions(ctx, extra)
+	if err != nil {
+		return err
+	}
+	if len(current.Values) != len(target) {
+		return errors.Errorf("arm has %d joints, not %d", len(current.Values), len(target))
+	}
+
+	model := a.ModelFrame()
+	withPoints := limits.Workspace != nil || limits.MaxCartesianSpeedMmPerSec > 0
+	if withPoints && model == nil {
+		return errNoModel
+	}
+	pointOf := func(values []float64) (r3.Vector, error) {
+		if !withPoints {
+			return r3.Vector{}, nil
+		}
+		// out of bounds positions are left to the arm to refuse.
+		pose, err := model.Transform(model.InputFromProtobuf(&pb.JointPositions{Values: values}))
+		if pose == nil {
+			return r3.Vector{}, err
+		}
+		return pose.Point(), nil
+	}
+
+	start, err := pointOf(current.Values)
+	if err != nil {
+		return err
+	}
+	end, err := pointOf(target)
+	if err != nil {
+		return err
+	}
+	steps := int(math.Ceil(float64(minMoveDuration(limits, current.Values, target, start, end)) / float64(limitPeriod)))
+	paced := steps > 0
+	if limits.Workspace != nil {
+		steps = max(steps, workspaceChecks)
+	}
+	steps = max(steps, 1)
+
+	path := make([][]float64, 0, steps)
+	points := make([]r3.Vector, 0, steps)
+	for i := 1; i <= steps; i++ {
+		values := make([]float64, len(target))
+		for j := range values {
+			values[j] = current.Values[j] + (target[j]-current.Values[j])*float64(i)/float64(steps)
+		}
+		pt, err := pointOf(values)
+		if err != nil {
+			return err
+		}
+		if limits.Workspace != nil && !limits.Workspace.Contains(pt) {
+			return a.limiter.Violation(resource.LimitWorkspace,
+				"moving to joint positions %v takes the end effector to %v, outside the workspace", target, pt)
+		}
+		path = append(path, values)
+		points = append(points, pt)
+	}
+	if !paced {
+		return a.Arm.MoveToJointPositions(ctx, &pb.JointPositions{Values: target}, extra)
+	}
+
+	prev, prevPoint := current.Values, start
+	for i, values := range path {
+		began := clock.Now()
+		if err := a.Arm.MoveToJointPositions(ctx, &pb.JointPositions{Values: values}, extra); err != nil {
+			return err
+		}
+		wait := minMoveDuration(limits, prev, values, prevPoint, points[i]) - clock.Since(began)
+		if wait > 0 && !clock.SleepContext(ctx, wait) {
+			return ctx.Err()
+		}
+		prev, prevPoint = values, points[i]
+	}
+	return nil
+}
+
+// minMoveDuration returns the least time the speed limits allow for a move between the joint
+// positions, whose end effector positions are given.
+func minMoveDuration(limits resource.MotionLimits, from, to []float64, fromPoint, toPoint r3.Vector) time.Duration {
+	var secs float64
+	for i := range to {
+		if v := limits.JointVelocity(i); v > 0 {
+			secs = max(secs, math.Abs(to[i]-from[i])/v)
+		}
+	}
+	if v := limits.MaxCartesianSpeedMmPerSec; v > 0 {
+		secs = max(secs, toPoint.Sub(fromPoint).Norm()/v)
+	}
+	return time.Duration(secs * float64(time.Second))
+}
@@ -15,6 +15,7 @@ import (
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Base]{
 		Status:                      resource.StatusFunc(CreateStatus),
+		Limit:                       Limit,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterBaseServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.BaseService_ServiceDesc,
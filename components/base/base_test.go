@@ -3,13 +3,17 @@ package base_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/go-viper/mapstructure/v2"
+	"github.com/golang/geo/r3"
 	commonpb "go.viam.com/api/common/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/protoutils"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/testutils/inject"
 )
@@ -78,3 +82,69 @@ func TestCreateStatus(t *testing.T) {
 		test.That(t, status1, test.ShouldResemble, status)
 	})
 }
+
+func TestLimit(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	var velocity r3.Vector
+	injectBase := inject.NewBase(testBaseName)
+	injectBase.MoveStraightFunc = func(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+		return nil
+	}
+	injectBase.SetVelocityFunc = func(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+		velocity = linear
+		return nil
+	}
+	injectBase.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		velocity = r3.Vector{}
+		return nil
+	}
+	var violations []error
+	limits := resource.MotionLimits{
+		MaxLinearSpeedMmPerSec:            500,
+		MaxAngularSpeedDegsPerSec:         90,
+		MaxLinearAccelerationMmPerSec2:    200,
+		MaxAngularAccelerationDegsPerSec2: 180,
+	}
+	limited := base.Limit(injectBase, resource.NewMotionLimiter(base.Named(testBaseName), limits, logger,
+		func(err error) { violations = append(violations, err) }))
+
+	t.Run("speed", func(t *testing.T) {
+		test.That(t, limited.MoveStraight(ctx, 100, 400, nil), test.ShouldBeNil)
+		err := limited.MoveStraight(ctx, 100, -600, nil)
+		test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, resource.LimitLinearSpeed)
+		err = limited.Spin(ctx, 90, 120, nil)
+		test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, resource.LimitAngularSpeed)
+		test.That(t, violations, test.ShouldHaveLength, 2)
+	})
+
+	t.Run("acceleration", func(t *testing.T) {
+		violations = nil
+		// from rest, a change is taken to be spread over a second.
+		test.That(t, limited.SetVelocity(ctx, r3.Vector{Y: 200}, r3.Vector{}, nil), test.ShouldBeNil)
+		virtual.Step(100 * time.Millisecond)
+		err := limited.SetVelocity(ctx, r3.Vector{Y: 300}, r3.Vector{}, nil)
+		test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+		test.That(t, err.Error(), test.ShouldContainSubstring, resource.LimitLinearAcceleration)
+		test.That(t, velocity, test.ShouldResemble, r3.Vector{Y: 200})
+		test.That(t, limited.SetVelocity(ctx, r3.Vector{Y: 220}, r3.Vector{}, nil), test.ShouldBeNil)
+
+		// slowing down is always allowed, but reversing is not.
+		virtual.Step(100 * time.Millisecond)
+		test.That(t, limited.SetVelocity(ctx, r3.Vector{Y: 50}, r3.Vector{}, nil), test.ShouldBeNil)
+		err = limited.SetVelocity(ctx, r3.Vector{Y: -50}, r3.Vector{}, nil)
+		test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+
+		test.That(t, limited.Stop(ctx, nil), test.ShouldBeNil)
+		test.That(t, limited.SetVelocity(ctx, r3.Vector{Y: -150}, r3.Vector{}, nil), test.ShouldBeNil)
+		test.That(t, violations, test.ShouldHaveLength, 2)
+	})
+
+	err := limited.SetPower(ctx, r3.Vector{Y: 1}, r3.Vector{}, nil)
+	test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+}
@@ -3,12 +3,15 @@ package fake
 
 import (
 	"context"
+	"sync"
 
 	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
 
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/simulation/script"
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -16,10 +19,31 @@ func init() {
 	resource.RegisterComponent(
 		base.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[base.Base, resource.NoNativeConfig]{Constructor: NewBase},
+		resource.Registration[base.Base, *Config]{Constructor: NewBase},
 	)
 }
 
+// Config is the config of a fake base.
+type Config struct {
+	// Scenario scripts the position of the base over time. The base reports that it is
+	// moving while the scenario moves it and its position is available through DoCommand.
+	Scenario *script.Config `json:"scenario,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Scenario != nil {
+		if err := conf.Scenario.Validate(path); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// PositionCommand is the DoCommand key that returns the position of a base following a
+// scenario.
+const PositionCommand = "get_position"
+
 const (
 	defaultWidthMm               = 600
 	defaultMinimumTurningRadiusM = 0
@@ -29,17 +53,19 @@ const (
 // Base is a fake base that returns what it was provided in each method.
 type Base struct {
 	resource.Named
-	resource.TriviallyReconfigurable
 	CloseCount               int
 	WidthMeters              float64
 	TurningRadius            float64
 	WheelCircumferenceMeters float64
 	Geometry                 []spatialmath.Geometry
 	logger                   logging.Logger
+
+	mu     sync.Mutex
+	player *script.Player
 }
 
 // NewBase instantiates a new base of the fake model type.
-func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (base.Base, error) {
+func NewBase(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (base.Base, error) {
 	b := &Base{
 		Named:    conf.ResourceName().AsNamed(),
 		Geometry: []spatialmath.Geometry{},
@@ -54,9 +80,40 @@ func NewBase(_ context.Context, _ resource.Dependencies, conf resource.Config, l
 	}
 	b.WidthMeters = defaultWidthMm * 0.001
 	b.TurningRadius = defaultMinimumTurningRadiusM
+	if err := b.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
 	return b, nil
 }
 
+// Reconfigure loads the configured scenario, if any, and starts playing it.
+func (b *Base) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	var player *script.Player
+	if newConf, ok := conf.ConvertedAttributes.(*Config); ok {
+		var err error
+		if player, err = newConf.Scenario.NewPlayer(); err != nil {
+			return err
+		}
+		if player != nil && player.State().Position == nil {
+			return errors.New("fake base scenario must script a position")
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.player = player
+	return nil
+}
+
+func (b *Base) scenarioState() (script.State, bool) {
+	b.mu.Lock()
+	player := b.player
+	b.mu.Unlock()
+	if player == nil {
+		return script.State{}, false
+	}
+	return player.State(), true
+}
+
 // MoveStraight does nothing.
 func (b *Base) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
 	return nil
@@ -82,9 +139,27 @@ func (b *Base) Stop(ctx context.Context, extra map[string]interface{}) error {
 	return nil
 }
 
-// IsMoving always returns false.
+// IsMoving returns whether the scenario is moving the base, or always false without one.
 func (b *Base) IsMoving(ctx context.Context) (bool, error) {
-	return false, nil
+	state, ok := b.scenarioState()
+	return ok && state.Moving, nil
+}
+
+// DoCommand returns the scripted position of the base for PositionCommand.
+func (b *Base) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd[PositionCommand]; !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	state, ok := b.scenarioState()
+	if !ok {
+		return nil, errors.New("fake base has no scenario")
+	}
+	return map[string]interface{}{
+		"x_mm":      state.Position.XMm,
+		"y_mm":      state.Position.YMm,
+		"theta_deg": state.Position.ThetaDeg,
+		"moving":    state.Moving,
+	}, nil
 }
 
 // Close does nothing.
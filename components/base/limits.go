@@ -0,0 +1,119 @@
+package base
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/resource"
+)
+
+// maxAccelerationWindow is the longest time over which a change of velocity is taken to be
+// spread when checking it against the acceleration limits.
+const maxAccelerationWindow = time.Second
+
+// Limit returns the base enforcing the motion limits of the limiter. Moves faster than the
+// speed limits are refused, as are changes of velocity faster than the acceleration limits
+// allow since the previous one; slowing down is always allowed. SetPower is refused if any
+// limit is set, since the speed it results in is not known.
+func Limit(b Base, limiter *resource.MotionLimiter) Base {
+	return limitedBase{Base: b, limiter: limiter}
+}
+
+type limitedBase struct {
+	Base
+	limiter *resource.MotionLimiter
+}
+
+func (b limitedBase) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	if err := b.checkSpeed(r3.Vector{Y: mmPerSec}, r3.Vector{}); err != nil {
+		return err
+	}
+	defer b.limiter.Reset()
+	return b.Base.MoveStraight(ctx, distanceMm, mmPerSec, extra)
+}
+
+func (b limitedBase) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	if err := b.checkSpeed(r3.Vector{}, r3.Vector{Z: degsPerSec}); err != nil {
+		return err
+	}
+	defer b.limiter.Reset()
+	return b.Base.Spin(ctx, angleDeg, degsPerSec, extra)
+}
+
+func (b limitedBase) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	limits := b.limiter.Limits()
+	for _, limit := range []struct {
+		name  string
+		value float64
+	}{
+		{resource.LimitLinearSpeed, limits.MaxLinearSpeedMmPerSec},
+		{resource.LimitAngularSpeed, limits.MaxAngularSpeedDegsPerSec},
+		{resource.LimitLinearAcceleration, limits.MaxLinearAccelerationMmPerSec2},
+		{resource.LimitAngularAcceleration, limits.MaxAngularAccelerationDegsPerSec2},
+	} {
+		if limit.value > 0 {
+			return b.limiter.Violation(limit.name, "power cannot be set on a limited base; set its velocity instead")
+		}
+	}
+	return b.Base.SetPower(ctx, linear, angular, extra)
+}
+
+func (b limitedBase) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	if err := b.checkSpeed(linear, angular); err != nil {
+		return err
+	}
+	limits := b.limiter.Limits()
+	lastLinear, lastAngular, elapsed, ok := b.limiter.LastVelocity()
+	if !ok || elapsed > maxAccelerationWindow {
+		elapsed = maxAccelerationWindow
+	}
+	if err := b.checkAcceleration(resource.LimitLinearAcceleration, limits.MaxLinearAccelerationMmPerSec2,
+		lastLinear, linear, elapsed, "mm/s"); err != nil {
+		return err
+	}
+	if err := b.checkAcceleration(resource.LimitAngularAcceleration, limits.MaxAngularAccelerationDegsPerSec2,
+		lastAngular, angular, elapsed, "deg/s"); err != nil {
+		return err
+	}
+	if err := b.Base.SetVelocity(ctx, linear, angular, extra); err != nil {
+		return err
+	}
+	b.limiter.SetVelocity(linear, angular)
+	return nil
+}
+
+func (b limitedBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	defer b.limiter.Reset()
+	return b.Base.Stop(ctx, extra)
+}
+
+func (b limitedBase) checkSpeed(linear, angular r3.Vector) error {
+	limits := b.limiter.Limits()
+	if v := limits.MaxLinearSpeedMmPerSec; v > 0 && linear.Norm() > v {
+		return b.limiter.Violation(resource.LimitLinearSpeed, "linear speed of %.1f mm/s is above %.1f mm/s", linear.Norm(), v)
+	}
+	if v := limits.MaxAngularSpeedDegsPerSec; v > 0 && angular.Norm() > v {
+		return b.limiter.Violation(resource.LimitAngularSpeed, "angular speed of %.1f deg/s is above %.1f deg/s", angular.Norm(), v)
+	}
+	return nil
+}
+
+func (b limitedBase) checkAcceleration(
+	limit string,
+	maxAcceleration float64,
+	from, to r3.Vector,
+	elapsed time.Duration,
+	unit string,
+) error {
+	if maxAcceleration <= 0 || to.Norm() <= from.Norm() && to.Dot(from) >= 0 {
+		return nil
+	}
+	change := to.Sub(from).Norm()
+	if allowed := maxAcceleration * elapsed.Seconds(); change > allowed {
+		return b.limiter.Violation(limit, "changing velocity by %.1f %s in %v is above %.1f %s/s",
+			change, unit, elapsed.Round(time.Millisecond), maxAcceleration, unit)
+	}
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	pb "go.viam.com/api/component/board/v1"
 	"go.viam.com/utils"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/components/board"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
@@ -60,6 +61,13 @@ func (conf *Config) Validate(path string) ([]string, error) {
 
 var model = resource.DefaultModelFamily.WithModel("fake")
 
+// simulatedModels are the names of the models of the boards in the RDK that the fake board
+// simulates, with the analog readers and digital interrupts of their configs, when the robot runs
+// in simulation.
+var simulatedModels = []string{
+	"beaglebone", "customlinux", "jetson", "numato", "odroid", "orangepi", "pi", "pi5", "ti", "upboard",
+}
+
 func init() {
 	resource.RegisterComponent(
 		board.API,
@@ -74,6 +82,47 @@ func init() {
 				return NewBoard(ctx, cfg, logger)
 			},
 		})
+	for _, name := range simulatedModels {
+		resource.RegisterSimulator(board.API, resource.DefaultModelFamily.WithModel(name), newSimulatedBoard)
+	}
+}
+
+// simulatedBoard is a fake board simulating the real board model of its config.
+type simulatedBoard struct {
+	*Board
+}
+
+func newSimulatedBoard(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (board.Board, error) {
+	simConf, err := simulatedConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+	b, err := NewBoard(ctx, simConf, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &simulatedBoard{b}, nil
+}
+
+// Reconfigure reconfigures the board with the analog readers and digital interrupts of the
+// config, ignoring the other attributes of the real board.
+func (b *simulatedBoard) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	simConf, err := simulatedConfig(conf)
+	if err != nil {
+		return err
+	}
+	return b.Board.Reconfigure(ctx, deps, simConf)
+}
+
+// simulatedConfig returns the config of a real board as that of a fake board.
+func simulatedConfig(conf resource.Config) (resource.Config, error) {
+	newConf, err := resource.TransformAttributeMap[*Config](conf.Attributes)
+	if err != nil {
+		return resource.Config{}, errors.Wrapf(err, "cannot simulate board %q", conf.Name)
+	}
+	newConf.FailNew = false
+	conf.ConvertedAttributes = newConf
+	return conf, nil
 }
 
 // NewBoard returns a new fake board.
@@ -267,7 +316,7 @@ func (b *Board) StreamTicks(ctx context.Context, interrupts []board.DigitalInter
 				//nolint:gosec
 				randBool := rand.Int()%2 == 0
 				select {
-				case ch <- board.Tick{Name: di.Name(), High: randBool, TimestampNanosec: uint64(time.Now().Unix())}:
+				case ch <- board.Tick{Name: di.Name(), High: randBool, TimestampNanosec: uint64(clock.Now().Unix())}:
 				default:
 					// if nothing is listening to the channel just do nothing.
 				}
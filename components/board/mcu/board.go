@@ -0,0 +1,663 @@
+// Package mcu implements a board whose pins are those of a microcontroller, such as an ESP32,
+// attached over a serial or USB port and running firmware that speaks the line protocol described
+// below. Its GPIO pins, PWM, analog readers, digital interrupts and quadrature encoders are exposed
+// as those of a native board.
+//
+// Each request sent to the firmware is a line of an id, a command and its arguments, and the
+// firmware replies to it with a line of the same id followed by "ok" and any values, or by "err"
+// and a message:
+//
+//	hello                     -> ok <firmware> <protocol version>
+//	ping                      -> ok
+//	gpio set <pin> <0|1>      -> ok
+//	gpio get <pin>            -> ok <0|1>
+//	pwm set <pin> <duty>      -> ok                  (duty cycle between 0 and 1)
+//	pwm get <pin>             -> ok <duty>
+//	pwmfreq set <pin> <hz>    -> ok
+//	pwmfreq get <pin>         -> ok <hz>
+//	adc read <pin>            -> ok <value> <max volts> <bits>
+//	dac write <pin> <value>   -> ok
+//	interrupt add <pin>       -> ok                  (count the rising edges of the pin)
+//	interrupt get <pin>       -> ok <count>
+//	interrupt stream <pin> <0|1> -> ok               (send the ticks of the pin as events)
+//	encoder add <a> <b>       -> ok                  (count the quadrature encoder on pins a and b)
+//	encoder get <a>           -> ok <count>
+//	encoder reset <a>         -> ok
+//
+// The firmware sends events as lines starting with "*": "* tick <pin> <0|1> <microseconds>" for
+// each edge of a streamed interrupt, and "* boot" when it starts.
+//
+// The board pings the firmware every heartbeat interval and reconnects when the port fails or the
+// firmware stops replying. Interrupts and encoders are set up again after reconnecting or when the
+// firmware restarts; the pins it drives are not, since they are reset with it.
+package mcu
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jacobsa/go-serial/serial"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	pb "go.viam.com/api/component/board/v1"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/board/pinwrappers"
+	"go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+// Model is the name of the microcontroller board model.
+var Model = resource.DefaultModelFamily.WithModel("mcu")
+
+const (
+	defaultBaudRate          = 115200
+	defaultHeartbeatInterval = time.Second
+	defaultTimeout           = time.Second
+
+	// helloAttempts is how many times hello is sent when connecting, since microcontrollers such
+	// as the ESP32 restart when their port is opened and miss the requests sent while starting.
+	helloAttempts = 3
+)
+
+// A Config describes the configuration of a microcontroller board.
+type Config struct {
+	SerialPath          string                         `json:"serial_path"`
+	BaudRate            int                            `json:"baud_rate,omitempty"`
+	Analogs             []board.AnalogReaderConfig     `json:"analogs,omitempty"`
+	DigitalInterrupts   []board.DigitalInterruptConfig `json:"digital_interrupts,omitempty"`
+	Encoders            []EncoderConfig                `json:"encoders,omitempty"`
+	HeartbeatIntervalMs int                            `json:"heartbeat_interval_ms,omitempty"`
+	TimeoutMs           int                            `json:"timeout_ms,omitempty"`
+}
+
+// EncoderConfig describes a quadrature encoder counted by the firmware.
+type EncoderConfig struct {
+	Name string `json:"name"`
+	PinA string `json:"a"`
+	PinB string `json:"b"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.SerialPath == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "serial_path")
+	}
+	if conf.BaudRate < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("baud_rate cannot be negative"))
+	}
+	if conf.HeartbeatIntervalMs < 0 || conf.TimeoutMs < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("heartbeat_interval_ms and timeout_ms cannot be negative"))
+	}
+	for idx, c := range conf.Analogs {
+		if err := c.Validate(fmt.Sprintf("%s.%s.%d", path, "analogs", idx)); err != nil {
+			return nil, err
+		}
+	}
+	for idx, c := range conf.DigitalInterrupts {
+		if err := c.Validate(fmt.Sprintf("%s.%s.%d", path, "digital_interrupts", idx)); err != nil {
+			return nil, err
+		}
+	}
+	for idx, c := range conf.Encoders {
+		encPath := fmt.Sprintf("%s.%s.%d", path, "encoders", idx)
+		switch {
+		case c.Name == "":
+			return nil, resource.NewConfigValidationFieldRequiredError(encPath, "name")
+		case c.PinA == "":
+			return nil, resource.NewConfigValidationFieldRequiredError(encPath, "a")
+		case c.PinB == "":
+			return nil, resource.NewConfigValidationFieldRequiredError(encPath, "b")
+		}
+	}
+	return nil, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		board.API,
+		Model,
+		resource.Registration[board.Board, *Config]{
+			Constructor: func(
+				ctx context.Context,
+				_ resource.Dependencies,
+				conf resource.Config,
+				logger logging.Logger,
+			) (board.Board, error) {
+				newConf, err := resource.NativeConfig[*Config](conf)
+				if err != nil {
+					return nil, err
+				}
+				return newBoard(ctx, conf.ResourceName(), newConf, openSerial(newConf), logger)
+			},
+		})
+}
+
+// openSerial returns a function opening the serial port of the config.
+func openSerial(conf *Config) func() (io.ReadWriteCloser, error) {
+	baudRate := conf.BaudRate
+	if baudRate == 0 {
+		baudRate = defaultBaudRate
+	}
+	return func() (io.ReadWriteCloser, error) {
+		return serial.Open(serial.OpenOptions{
+			PortName:        conf.SerialPath,
+			BaudRate:        uint(baudRate),
+			DataBits:        8,
+			StopBits:        1,
+			MinimumReadSize: 1,
+		})
+	}
+}
+
+// An EncoderBoard is a board counting quadrature encoders, such as the microcontroller board,
+// whose counts encoder components read.
+type EncoderBoard interface {
+	board.Board
+
+	// EncoderPosition returns the count of the named encoder.
+	EncoderPosition(ctx context.Context, name string) (int64, error)
+
+	// ResetEncoderPosition sets the count of the named encoder to zero.
+	ResetEncoderPosition(ctx context.Context, name string) error
+}
+
+type mcuBoard struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	open              func() (io.ReadWriteCloser, error)
+	heartbeatInterval time.Duration
+	timeout           time.Duration
+	logger            logging.Logger
+
+	analogs    map[string]*pinwrappers.AnalogSmoother
+	interrupts map[string]*digitalInterrupt
+	encoders   map[string]EncoderConfig
+
+	mu   sync.Mutex
+	conn *conn
+
+	rebooted chan struct{}
+	workers  rdkutils.StoppableWorkers
+}
+
+var _ EncoderBoard = (*mcuBoard)(nil)
+
+// newBoard returns a board connected to the firmware of the microcontroller on the port opened.
+func newBoard(
+	ctx context.Context,
+	name resource.Name,
+	conf *Config,
+	open func() (io.ReadWriteCloser, error),
+	logger logging.Logger,
+) (board.Board, error) {
+	b := &mcuBoard{
+		Named:             name.AsNamed(),
+		open:              open,
+		heartbeatInterval: time.Duration(conf.HeartbeatIntervalMs) * time.Millisecond,
+		timeout:           time.Duration(conf.TimeoutMs) * time.Millisecond,
+		logger:            logger,
+		analogs:           map[string]*pinwrappers.AnalogSmoother{},
+		interrupts:        map[string]*digitalInterrupt{},
+		encoders:          map[string]EncoderConfig{},
+		rebooted:          make(chan struct{}, 1),
+	}
+	if b.heartbeatInterval == 0 {
+		b.heartbeatInterval = defaultHeartbeatInterval
+	}
+	if b.timeout == 0 {
+		b.timeout = defaultTimeout
+	}
+	for _, c := range conf.DigitalInterrupts {
+		b.interrupts[c.Name] = &digitalInterrupt{b: b, conf: c}
+	}
+	for _, c := range conf.Encoders {
+		b.encoders[c.Name] = c
+	}
+
+	if err := b.connect(ctx); err != nil {
+		return nil, err
+	}
+	for _, c := range conf.Analogs {
+		b.analogs[c.Name] = pinwrappers.SmoothAnalogReader(&analog{b, c.Pin}, c, logger)
+	}
+	b.workers = rdkutils.NewStoppableWorkers(b.maintainConnection)
+	return b, nil
+}
+
+// connect opens the port, checks that the firmware speaks the protocol and sets up its
+// interrupts and encoders.
+func (b *mcuBoard) connect(ctx context.Context) error {
+	port, err := b.open()
+	if err != nil {
+		return errors.Wrap(err, "cannot open the port of the microcontroller")
+	}
+	c := newConn(port, b.handleEvent, b.logger)
+
+	var hello []string
+	for attempt := 0; attempt < helloAttempts; attempt++ {
+		if hello, err = c.request(ctx, b.timeout, "hello"); err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	if err == nil && (len(hello) < 2 || hello[1] != strconv.Itoa(protocolVersion)) {
+		err = errors.Errorf("microcontroller firmware replied %q to hello; expected protocol version %d", hello, protocolVersion)
+	}
+	if err == nil {
+		b.logger.CDebugw(ctx, "connected to microcontroller", "firmware", hello[0])
+		err = b.setup(ctx, c)
+	}
+	if err != nil {
+		return multierr.Combine(err, c.Close())
+	}
+
+	b.mu.Lock()
+	b.conn = c
+	b.mu.Unlock()
+	return nil
+}
+
+// setup sets up the interrupts and encoders of the board in the firmware.
+func (b *mcuBoard) setup(ctx context.Context, c *conn) error {
+	for _, di := range b.interrupts {
+		if _, err := c.request(ctx, b.timeout, "interrupt add", di.conf.Pin); err != nil {
+			return err
+		}
+		if di.streaming() {
+			if _, err := c.request(ctx, b.timeout, "interrupt stream", di.conf.Pin, 1); err != nil {
+				return err
+			}
+		}
+	}
+	for _, enc := range b.encoders {
+		if _, err := c.request(ctx, b.timeout, "encoder add", enc.PinA, enc.PinB); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maintainConnection pings the firmware every heartbeat interval, reconnecting when the connection
+// fails, and sets the firmware up again when it restarts.
+func (b *mcuBoard) maintainConnection(ctx context.Context) {
+	heartbeat := time.NewTicker(b.heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		b.mu.Lock()
+		c := b.conn
+		b.mu.Unlock()
+
+		if c == nil {
+			if !utils.SelectContextOrWait(ctx, b.heartbeatInterval) {
+				return
+			}
+			if err := b.connect(ctx); err != nil {
+				b.logger.CDebugw(ctx, "cannot reconnect to microcontroller", "error", err)
+				continue
+			}
+			b.logger.CInfo(ctx, "reconnected to microcontroller")
+			continue
+		}
+
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.Done():
+			err = errDisconnected
+		case <-b.rebooted:
+			b.logger.CInfo(ctx, "microcontroller restarted; setting it up again")
+			err = b.setup(ctx, c)
+		case <-heartbeat.C:
+			_, err = c.request(ctx, b.timeout, "ping")
+		}
+		if err != nil && ctx.Err() == nil {
+			b.logger.CWarnw(ctx, "lost connection to microcontroller; reconnecting", "error", err)
+			b.mu.Lock()
+			b.conn = nil
+			b.mu.Unlock()
+			utils.UncheckedError(c.Close())
+		}
+	}
+}
+
+// handleEvent handles an event sent by the firmware.
+func (b *mcuBoard) handleEvent(ev event) {
+	switch ev.kind {
+	case "boot":
+		select {
+		case b.rebooted <- struct{}{}:
+		default:
+		}
+	case "tick":
+		if len(ev.args) != 3 {
+			b.logger.Debugw("ignoring malformed tick from microcontroller", "args", ev.args)
+			return
+		}
+		micros, err := strconv.ParseUint(ev.args[2], 10, 64)
+		if err != nil {
+			b.logger.Debugw("ignoring malformed tick from microcontroller", "args", ev.args)
+			return
+		}
+		for _, di := range b.interrupts {
+			if di.conf.Pin == ev.args[0] {
+				di.tick(ev.args[1] == "1", micros*1000)
+			}
+		}
+	}
+}
+
+// request sends the command to the firmware if the board is connected to it.
+func (b *mcuBoard) request(ctx context.Context, command string, args ...interface{}) ([]string, error) {
+	b.mu.Lock()
+	c := b.conn
+	b.mu.Unlock()
+	if c == nil {
+		return nil, errors.Errorf("board %q is not connected to its microcontroller", b.Name().ShortName())
+	}
+	return c.request(ctx, b.timeout, command, args...)
+}
+
+// requestValue sends the command to the firmware and returns the single value replied.
+func (b *mcuBoard) requestValue(ctx context.Context, command string, args ...interface{}) (string, error) {
+	values, err := b.request(ctx, command, args...)
+	if err != nil {
+		return "", err
+	}
+	if len(values) != 1 {
+		return "", errors.Errorf("microcontroller replied %q to %q; expected one value", values, command)
+	}
+	return values[0], nil
+}
+
+// AnalogByName returns the analog pin by the given name if it exists.
+func (b *mcuBoard) AnalogByName(name string) (board.Analog, error) {
+	a, ok := b.analogs[name]
+	if !ok {
+		return nil, errors.Errorf("can't find AnalogReader (%s)", name)
+	}
+	return a, nil
+}
+
+// DigitalInterruptByName returns the interrupt by the given name if it exists.
+func (b *mcuBoard) DigitalInterruptByName(name string) (board.DigitalInterrupt, error) {
+	di, ok := b.interrupts[name]
+	if !ok {
+		return nil, errors.Errorf("cant find DigitalInterrupt (%s)", name)
+	}
+	return di, nil
+}
+
+// GPIOPinByName returns the GPIO pin by the given name.
+func (b *mcuBoard) GPIOPinByName(name string) (board.GPIOPin, error) {
+	return &gpioPin{b, name}, nil
+}
+
+// AnalogNames returns the names of all known analog pins.
+func (b *mcuBoard) AnalogNames() []string {
+	names := []string{}
+	for name := range b.analogs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// DigitalInterruptNames returns the names of all known digital interrupts.
+func (b *mcuBoard) DigitalInterruptNames() []string {
+	names := []string{}
+	for name := range b.interrupts {
+		names = append(names, name)
+	}
+	return names
+}
+
+// EncoderPosition returns the count of the named encoder.
+func (b *mcuBoard) EncoderPosition(ctx context.Context, name string) (int64, error) {
+	enc, ok := b.encoders[name]
+	if !ok {
+		return 0, errors.Errorf("cant find encoder (%s)", name)
+	}
+	value, err := b.requestValue(ctx, "encoder get", enc.PinA)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// ResetEncoderPosition sets the count of the named encoder to zero.
+func (b *mcuBoard) ResetEncoderPosition(ctx context.Context, name string) error {
+	enc, ok := b.encoders[name]
+	if !ok {
+		return errors.Errorf("cant find encoder (%s)", name)
+	}
+	_, err := b.request(ctx, "encoder reset", enc.PinA)
+	return err
+}
+
+// SetPowerMode sets the board to the given power mode.
+func (b *mcuBoard) SetPowerMode(ctx context.Context, mode pb.PowerMode, duration *time.Duration) error {
+	return grpc.UnimplementedError
+}
+
+// StreamTicks starts a stream of digital interrupt ticks.
+func (b *mcuBoard) StreamTicks(ctx context.Context, interrupts []board.DigitalInterrupt, ch chan board.Tick,
+	extra map[string]interface{},
+) error {
+	var rawInterrupts []*digitalInterrupt
+	for _, i := range interrupts {
+		raw, ok := i.(*digitalInterrupt)
+		if !ok || raw.b != b {
+			return errors.New("cannot stream ticks to an interrupt not associated with this board")
+		}
+		rawInterrupts = append(rawInterrupts, raw)
+	}
+
+	for _, di := range rawInterrupts {
+		if di.addChannel(ch) {
+			if _, err := b.request(ctx, "interrupt stream", di.conf.Pin, 1); err != nil {
+				return multierr.Combine(err, b.stopStreaming(ctx, rawInterrupts, ch))
+			}
+		}
+	}
+
+	b.workers.AddWorkers(func(workersCtx context.Context) {
+		select {
+		case <-ctx.Done():
+		case <-workersCtx.Done():
+		}
+		stopCtx, cancel := context.WithTimeout(context.Background(), b.timeout)
+		defer cancel()
+		if err := b.stopStreaming(stopCtx, rawInterrupts, ch); err != nil {
+			b.logger.Debugw("cannot stop streaming ticks", "error", err)
+		}
+	})
+	return nil
+}
+
+// stopStreaming stops streaming the ticks of the interrupts to the channel.
+func (b *mcuBoard) stopStreaming(ctx context.Context, interrupts []*digitalInterrupt, ch chan board.Tick) error {
+	var errs error
+	for _, di := range interrupts {
+		if di.removeChannel(ch) {
+			_, err := b.request(ctx, "interrupt stream", di.conf.Pin, 0)
+			errs = multierr.Combine(errs, err)
+		}
+	}
+	return errs
+}
+
+// Close stops maintaining the connection to the firmware and closes it.
+func (b *mcuBoard) Close(ctx context.Context) error {
+	b.workers.Stop()
+	var errs error
+	for _, a := range b.analogs {
+		errs = multierr.Combine(errs, a.Close(ctx))
+	}
+	b.mu.Lock()
+	c := b.conn
+	b.conn = nil
+	b.mu.Unlock()
+	if c != nil {
+		errs = multierr.Combine(errs, c.Close())
+	}
+	return errs
+}
+
+type gpioPin struct {
+	b   *mcuBoard
+	pin string
+}
+
+func (gp *gpioPin) Set(ctx context.Context, high bool, extra map[string]interface{}) error {
+	value := 0
+	if high {
+		value = 1
+	}
+	_, err := gp.b.request(ctx, "gpio set", gp.pin, value)
+	return err
+}
+
+func (gp *gpioPin) Get(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	value, err := gp.b.requestValue(ctx, "gpio get", gp.pin)
+	if err != nil {
+		return false, err
+	}
+	return value == "1", nil
+}
+
+func (gp *gpioPin) PWM(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	value, err := gp.b.requestValue(ctx, "pwm get", gp.pin)
+	if err != nil {
+		return math.NaN(), err
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+func (gp *gpioPin) SetPWM(ctx context.Context, dutyCyclePct float64, extra map[string]interface{}) error {
+	_, err := gp.b.request(ctx, "pwm set", gp.pin, strconv.FormatFloat(dutyCyclePct, 'f', -1, 64))
+	return err
+}
+
+func (gp *gpioPin) PWMFreq(ctx context.Context, extra map[string]interface{}) (uint, error) {
+	value, err := gp.b.requestValue(ctx, "pwmfreq get", gp.pin)
+	if err != nil {
+		return 0, err
+	}
+	freq, err := strconv.ParseUint(value, 10, 64)
+	return uint(freq), err
+}
+
+func (gp *gpioPin) SetPWMFreq(ctx context.Context, freqHz uint, extra map[string]interface{}) error {
+	_, err := gp.b.request(ctx, "pwmfreq set", gp.pin, freqHz)
+	return err
+}
+
+type analog struct {
+	b   *mcuBoard
+	pin string
+}
+
+// Read returns the analog value with the range and step size in V/bit reported by the firmware.
+func (a *analog) Read(ctx context.Context, extra map[string]interface{}) (board.AnalogValue, error) {
+	values, err := a.b.request(ctx, "adc read", a.pin)
+	if err != nil {
+		return board.AnalogValue{}, err
+	}
+	if len(values) != 3 {
+		return board.AnalogValue{}, errors.Errorf("microcontroller replied %q to adc read; expected value, max volts and bits", values)
+	}
+	value, err := strconv.Atoi(values[0])
+	if err != nil {
+		return board.AnalogValue{}, err
+	}
+	maxVolts, err := strconv.ParseFloat(values[1], 32)
+	if err != nil {
+		return board.AnalogValue{}, err
+	}
+	bits, err := strconv.Atoi(values[2])
+	if err != nil {
+		return board.AnalogValue{}, err
+	}
+	return board.AnalogValue{
+		Value:    value,
+		Min:      0,
+		Max:      float32(maxVolts),
+		StepSize: float32(maxVolts / float64(int(1)<<bits)),
+	}, nil
+}
+
+func (a *analog) Write(ctx context.Context, value int, extra map[string]interface{}) error {
+	_, err := a.b.request(ctx, "dac write", a.pin, value)
+	return err
+}
+
+type digitalInterrupt struct {
+	b    *mcuBoard
+	conf board.DigitalInterruptConfig
+
+	mu       sync.Mutex
+	channels []chan board.Tick
+}
+
+func (di *digitalInterrupt) Name() string {
+	return di.conf.Name
+}
+
+// Value returns the number of rising edges counted by the firmware.
+func (di *digitalInterrupt) Value(ctx context.Context, extra map[string]interface{}) (int64, error) {
+	value, err := di.b.requestValue(ctx, "interrupt get", di.conf.Pin)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// addChannel adds a channel to stream ticks to, returning whether it is the first.
+func (di *digitalInterrupt) addChannel(ch chan board.Tick) bool {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+	di.channels = append(di.channels, ch)
+	return len(di.channels) == 1
+}
+
+// removeChannel removes a channel streamed ticks to, returning whether it was the last.
+func (di *digitalInterrupt) removeChannel(ch chan board.Tick) bool {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+	for i, oldCh := range di.channels {
+		if oldCh == ch {
+			di.channels = append(di.channels[:i], di.channels[i+1:]...)
+			return len(di.channels) == 0
+		}
+	}
+	return false
+}
+
+func (di *digitalInterrupt) streaming() bool {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+	return len(di.channels) != 0
+}
+
+func (di *digitalInterrupt) tick(high bool, nanos uint64) {
+	di.mu.Lock()
+	defer di.mu.Unlock()
+	tick := board.Tick{Name: di.conf.Name, High: high, TimestampNanosec: nanos}
+	for _, ch := range di.channels {
+		// ticks are dropped rather than holding up the replies of the firmware.
+		select {
+		case ch <- tick:
+		default:
+			di.b.logger.Debugw("dropping tick of digital interrupt; its stream is full", "name", di.conf.Name)
+		}
+	}
+}
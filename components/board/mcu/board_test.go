@@ -0,0 +1,273 @@
+package mcu
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// fakeFirmware speaks the protocol of the board over pipes, as the firmware of a
+// microcontroller does over its serial port.
+type fakeFirmware struct {
+	mu         sync.Mutex
+	port       net.Conn
+	pins       map[string]string
+	pwms       map[string]string
+	interrupts map[string]int
+	streamed   map[string]bool
+	encoders   map[string]int
+	opens      int
+	silent     bool
+}
+
+func newFakeFirmware() *fakeFirmware {
+	return &fakeFirmware{
+		pins:       map[string]string{},
+		pwms:       map[string]string{},
+		interrupts: map[string]int{},
+		streamed:   map[string]bool{},
+		encoders:   map[string]int{},
+	}
+}
+
+// open connects the board to the firmware, as if its serial port were opened.
+func (f *fakeFirmware) open() (io.ReadWriteCloser, error) {
+	boardEnd, firmwareEnd := net.Pipe()
+	f.mu.Lock()
+	f.port = firmwareEnd
+	f.opens++
+	f.mu.Unlock()
+	go f.serve(firmwareEnd)
+	return boardEnd, nil
+}
+
+func (f *fakeFirmware) serve(port net.Conn) {
+	in := bufio.NewReader(port)
+	for {
+		line, err := in.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		reply := f.handle(fields[1:])
+		f.mu.Lock()
+		silent := f.silent
+		f.mu.Unlock()
+		if silent {
+			continue
+		}
+		if _, err := fmt.Fprintf(port, "%s %s\n", fields[0], reply); err != nil {
+			return
+		}
+	}
+}
+
+func (f *fakeFirmware) handle(request []string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch strings.Join(request[:min(2, len(request))], " ") {
+	case "hello":
+		return fmt.Sprintf("ok fake-esp32 %d", protocolVersion)
+	case "ping":
+		return "ok"
+	case "gpio set":
+		f.pins[request[2]] = request[3]
+		return "ok"
+	case "gpio get":
+		return "ok " + f.pins[request[2]]
+	case "pwm set":
+		f.pwms[request[2]] = request[3]
+		return "ok"
+	case "pwm get":
+		return "ok " + f.pwms[request[2]]
+	case "adc read":
+		return "ok 2048 3.3 12"
+	case "interrupt add":
+		f.interrupts[request[2]] = 0
+		return "ok"
+	case "interrupt get":
+		return fmt.Sprintf("ok %d", f.interrupts[request[2]])
+	case "interrupt stream":
+		f.streamed[request[2]] = request[3] == "1"
+		return "ok"
+	case "encoder add":
+		f.encoders[request[2]] = 0
+		return "ok"
+	case "encoder get":
+		count, ok := f.encoders[request[2]]
+		if !ok {
+			return "err no such encoder"
+		}
+		return fmt.Sprintf("ok %d", count)
+	case "encoder reset":
+		f.encoders[request[2]] = 0
+		return "ok"
+	default:
+		return "err unknown command"
+	}
+}
+
+// send sends a line to the board on its own, such as an event.
+func (f *fakeFirmware) send(t *testing.T, line string) {
+	t.Helper()
+	f.mu.Lock()
+	port := f.port
+	f.mu.Unlock()
+	_, err := fmt.Fprintln(port, line)
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func (f *fakeFirmware) state(fn func()) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn()
+}
+
+func newTestBoard(t *testing.T, firmware *fakeFirmware) EncoderBoard {
+	t.Helper()
+	conf := &Config{
+		SerialPath:          "/dev/ttyUSB0",
+		Analogs:             []board.AnalogReaderConfig{{Name: "battery", Pin: "34"}},
+		DigitalInterrupts:   []board.DigitalInterruptConfig{{Name: "bumper", Pin: "4"}},
+		Encoders:            []EncoderConfig{{Name: "left", PinA: "16", PinB: "17"}},
+		HeartbeatIntervalMs: 20,
+		TimeoutMs:           100,
+	}
+	b, err := newBoard(context.Background(), board.Named("esp32"), conf, firmware.open, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		test.That(t, b.Close(context.Background()), test.ShouldBeNil)
+	})
+	return b.(EncoderBoard)
+}
+
+func TestBoard(t *testing.T) {
+	ctx := context.Background()
+	firmware := newFakeFirmware()
+	b := newTestBoard(t, firmware)
+
+	pin, err := b.GPIOPinByName("2")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pin.Set(ctx, true, nil), test.ShouldBeNil)
+	high, err := pin.Get(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, high, test.ShouldBeTrue)
+	test.That(t, pin.SetPWM(ctx, 0.25, nil), test.ShouldBeNil)
+	duty, err := pin.PWM(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, duty, test.ShouldEqual, 0.25)
+	_, err = pin.PWMFreq(ctx, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown command")
+
+	_, err = b.AnalogByName("battery")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, b.AnalogNames(), test.ShouldResemble, []string{"battery"})
+	value, err := (&analog{b.(*mcuBoard), "34"}).Read(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, value.Value, test.ShouldEqual, 2048)
+	test.That(t, value.Max, test.ShouldEqual, float32(3.3))
+	test.That(t, value.StepSize, test.ShouldEqual, float32(3.3/4096))
+
+	di, err := b.DigitalInterruptByName("bumper")
+	test.That(t, err, test.ShouldBeNil)
+	firmware.state(func() { firmware.interrupts["4"] = 3 })
+	count, err := di.Value(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, count, test.ShouldEqual, 3)
+
+	firmware.state(func() { firmware.encoders["16"] = -42 })
+	position, err := b.EncoderPosition(ctx, "left")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, position, test.ShouldEqual, -42)
+	test.That(t, b.ResetEncoderPosition(ctx, "left"), test.ShouldBeNil)
+	position, err = b.EncoderPosition(ctx, "left")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, position, test.ShouldEqual, 0)
+	_, err = b.EncoderPosition(ctx, "right")
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestBoardStreamTicks(t *testing.T) {
+	firmware := newFakeFirmware()
+	b := newTestBoard(t, firmware)
+	di, err := b.DigitalInterruptByName("bumper")
+	test.That(t, err, test.ShouldBeNil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ticks := make(chan board.Tick, 1)
+	test.That(t, b.StreamTicks(ctx, []board.DigitalInterrupt{di}, ticks, nil), test.ShouldBeNil)
+	firmware.state(func() { test.That(t, firmware.streamed["4"], test.ShouldBeTrue) })
+
+	firmware.send(t, "* tick 4 1 1500")
+	select {
+	case tick := <-ticks:
+		test.That(t, tick, test.ShouldResemble, board.Tick{Name: "bumper", High: true, TimestampNanosec: 1500000})
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for tick")
+	}
+
+	cancel()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		firmware.state(func() { test.That(tb, firmware.streamed["4"], test.ShouldBeFalse) })
+	})
+}
+
+func TestBoardReconnects(t *testing.T) {
+	ctx := context.Background()
+	firmware := newFakeFirmware()
+	b := newTestBoard(t, firmware)
+
+	// the firmware restarting loses its interrupts and encoders, which are set up again.
+	firmware.state(func() { firmware.encoders = map[string]int{} })
+	firmware.send(t, "* boot")
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		_, err := b.EncoderPosition(ctx, "left")
+		test.That(tb, err, test.ShouldBeNil)
+	})
+
+	// the firmware not replying to the heartbeat makes the board reconnect.
+	firmware.state(func() {
+		firmware.silent = true
+		firmware.encoders = map[string]int{}
+	})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		firmware.state(func() { test.That(tb, firmware.opens, test.ShouldBeGreaterThan, 1) })
+	})
+	firmware.state(func() { firmware.silent = false })
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		_, err := b.EncoderPosition(ctx, "left")
+		test.That(tb, err, test.ShouldBeNil)
+	})
+}
+
+func TestConfigValidate(t *testing.T) {
+	conf := Config{}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, resource.GetFieldFromFieldRequiredError(err), test.ShouldEqual, "serial_path")
+
+	conf.SerialPath = "/dev/ttyUSB0"
+	conf.Encoders = []EncoderConfig{{Name: "left", PinA: "16"}}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "path.encoders.0")
+	test.That(t, resource.GetFieldFromFieldRequiredError(err), test.ShouldEqual, "b")
+
+	conf.Encoders[0].PinB = "17"
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+}
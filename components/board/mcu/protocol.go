@@ -0,0 +1,196 @@
+package mcu
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+)
+
+// protocolVersion is the version of the serial protocol spoken with the firmware, which it
+// reports in reply to hello.
+const protocolVersion = 1
+
+// errDisconnected is returned by the requests of a connection after it fails or is closed.
+var errDisconnected = errors.New("connection to the microcontroller is closed")
+
+// An event is a line the firmware sends on its own rather than in reply to a request, such as a
+// tick of a digital interrupt.
+type event struct {
+	kind string
+	args []string
+}
+
+type reply struct {
+	values []string
+	err    error
+}
+
+// conn is a connection to the firmware of a microcontroller over a serial port. Requests are
+// lines of an id, a command and its arguments; the firmware replies to each with a line of the
+// same id followed by "ok" and the values asked for, or by "err" and a message. Lines starting
+// with "*" are events.
+type conn struct {
+	port    io.ReadWriteCloser
+	onEvent func(event)
+	logger  logging.Logger
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  uint64
+	pending map[uint64]chan reply
+	closed  bool
+	done    chan struct{}
+
+	workers sync.WaitGroup
+}
+
+// newConn returns a connection reading the lines of the port until it is closed, calling onEvent
+// with the events received.
+func newConn(port io.ReadWriteCloser, onEvent func(event), logger logging.Logger) *conn {
+	c := &conn{
+		port:    port,
+		onEvent: onEvent,
+		logger:  logger,
+		pending: map[uint64]chan reply{},
+		done:    make(chan struct{}),
+	}
+	c.workers.Add(1)
+	utils.ManagedGo(c.readLoop, c.workers.Done)
+	return c
+}
+
+// request sends the command with its arguments and returns the values replied, failing if no
+// reply is received within the timeout.
+func (c *conn) request(ctx context.Context, timeout time.Duration, command string, args ...interface{}) ([]string, error) {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil, errDisconnected
+	}
+	c.nextID++
+	id := c.nextID
+	replyCh := make(chan reply, 1)
+	c.pending[id] = replyCh
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+	}()
+
+	line := strconv.FormatUint(id, 10) + " " + command
+	for _, arg := range args {
+		line += " " + fmt.Sprint(arg)
+	}
+	c.writeMu.Lock()
+	_, err := io.WriteString(c.port, line+"\n")
+	c.writeMu.Unlock()
+	if err != nil {
+		c.fail(err)
+		return nil, err
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, errDisconnected
+	case <-timer.C:
+		return nil, errors.Errorf("microcontroller did not reply to %q within %v", command, timeout)
+	case r := <-replyCh:
+		return r.values, r.err
+	}
+}
+
+func (c *conn) readLoop() {
+	in := bufio.NewReader(c.port)
+	for {
+		line, err := in.ReadString('\n')
+		if err != nil {
+			c.fail(err)
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if fields[0] == "*" {
+			if len(fields) > 1 {
+				c.onEvent(event{kind: fields[1], args: fields[2:]})
+			}
+			continue
+		}
+
+		id, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil || len(fields) < 2 {
+			c.logger.Debugw("ignoring malformed line from microcontroller", "line", strings.TrimSpace(line))
+			continue
+		}
+		var r reply
+		switch fields[1] {
+		case "ok":
+			r.values = fields[2:]
+		case "err":
+			r.err = errors.Errorf("microcontroller error: %s", strings.Join(fields[2:], " "))
+		default:
+			c.logger.Debugw("ignoring malformed line from microcontroller", "line", strings.TrimSpace(line))
+			continue
+		}
+		c.mu.Lock()
+		replyCh, ok := c.pending[id]
+		c.mu.Unlock()
+		if ok {
+			select {
+			case replyCh <- r:
+			default:
+			}
+		}
+	}
+}
+
+// fail closes the connection after an error reading or writing the port.
+func (c *conn) fail(err error) {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	if closed {
+		return
+	}
+	c.logger.Debugw("connection to microcontroller failed", "error", err)
+	close(c.done)
+	utils.UncheckedError(c.port.Close())
+}
+
+// Done returns a channel closed once the connection fails or is closed.
+func (c *conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// Close closes the port and waits for the connection to stop reading it.
+func (c *conn) Close() error {
+	c.mu.Lock()
+	closed := c.closed
+	c.closed = true
+	c.mu.Unlock()
+	var err error
+	if !closed {
+		close(c.done)
+		err = c.port.Close()
+	}
+	c.workers.Wait()
+	return err
+}
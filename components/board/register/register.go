@@ -8,6 +8,7 @@ import (
 	_ "go.viam.com/rdk/components/board/fake"
 	_ "go.viam.com/rdk/components/board/hat/pca9685"
 	_ "go.viam.com/rdk/components/board/jetson"
+	_ "go.viam.com/rdk/components/board/mcu"
 	_ "go.viam.com/rdk/components/board/numato"
 	_ "go.viam.com/rdk/components/board/odroid"
 	_ "go.viam.com/rdk/components/board/orangepi"
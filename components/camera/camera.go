@@ -28,6 +28,7 @@ import (
 
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Camera]{
+		SelfTest:                    SelfTest,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterCameraServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.CameraService_ServiceDesc,
@@ -46,6 +47,10 @@ func init() {
 		API:        API,
 		MethodName: getImages.String(),
 	}, newGetImagesCollector)
+	data.RegisterCollector(data.MethodMetadata{
+		API:        API,
+		MethodName: videoSegments.String(),
+	}, newVideoSegmentsCollector)
 }
 
 // SubtypeName is a constant that identifies the camera resource subtype string.
@@ -461,3 +466,13 @@ func SimultaneousColorDepthNext(ctx context.Context, color, depth gostream.Video
 	wg.Wait()
 	return col, dm
 }
+
+// SelfTest checks that the camera is functional by grabbing a frame from it.
+func SelfTest(ctx context.Context, cam Camera) error {
+	_, release, err := ReadImage(ctx, cam)
+	if err != nil {
+		return err
+	}
+	release()
+	return nil
+}
@@ -22,6 +22,7 @@ const (
 	nextPointCloud method = iota
 	readImage
 	getImages
+	videoSegments
 )
 
 func (m method) String() string {
@@ -32,6 +33,8 @@ func (m method) String() string {
 		return "ReadImage"
 	case getImages:
 		return "GetImages"
+	case videoSegments:
+		return "VideoSegments"
 	}
 	return "Unknown"
 }
@@ -96,7 +99,8 @@ func newReadImageCollector(resource interface{}, params data.CollectorParams) (d
 
 		ctx = context.WithValue(ctx, data.FromDMContextKey{}, true)
 
-		img, release, err := ReadImage(ctx, camera)
+		// share frames with other consumers of the camera, such as vision services.
+		frame, release, err := SharedFrameBroker(camera).Next(ctx)
 		if err != nil {
 			// A modular filter component can be created to filter the readings from a component. The error ErrNoCaptureToStore
 			// is used in the datamanager to exclude readings from being captured and stored.
@@ -106,18 +110,15 @@ func newReadImageCollector(resource interface{}, params data.CollectorParams) (d
 
 			return nil, data.FailedToReadErr(params.ComponentName, readImage.String(), err)
 		}
-		defer func() {
-			if release != nil {
-				release()
-			}
-		}()
+		defer release()
+		span.AddAttributes(trace.Int64Attribute("frame_sequence", int64(frame.Sequence)))
 
 		mimeStr := new(wrapperspb.StringValue)
 		if err := mimeType.UnmarshalTo(mimeStr); err != nil {
 			return nil, err
 		}
 
-		outBytes, err := rimage.EncodeImage(ctx, img, mimeStr.Value)
+		outBytes, err := rimage.EncodeImage(ctx, frame.Image, mimeStr.Value)
 		if err != nil {
 			return nil, err
 		}
@@ -28,6 +28,7 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/rimage"
 	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/simulation/imperfection"
 	rutils "go.viam.com/rdk/utils"
 )
 
@@ -77,6 +78,7 @@ func NewCamera(
 		Height:         height,
 		Animated:       newConf.Animated,
 		RTPPassthrough: newConf.RTPPassthrough,
+		imperfections:  newConf.Imperfections.NewInjector(),
 		bufAndCBByID:   make(map[rtppassthrough.SubscriptionID]bufAndCB),
 		logger:         logger,
 	}
@@ -112,6 +114,10 @@ type Config struct {
 	Height         int  `json:"height,omitempty"`
 	Animated       bool `json:"animated,omitempty"`
 	RTPPassthrough bool `json:"rtp_passthrough,omitempty"`
+	// Imperfections simulates the latency, noise, drift, and dropouts of a real camera. Noise
+	// and drift apply to the intensity of each color channel, from 0 to 255, and to the depth
+	// of each point of point clouds.
+	Imperfections *imperfection.Config `json:"imperfections,omitempty"`
 }
 
 // Validate checks that the config attributes are valid for a fake camera.
@@ -132,6 +138,12 @@ func (conf *Config) Validate(path string) ([]string, error) {
 		return nil, errors.Errorf("odd-number resolutions cannot be rendered, cannot use a width of %d", conf.Width)
 	}
 
+	if conf.Imperfections != nil {
+		if err := conf.Imperfections.Validate(path); err != nil {
+			return nil, err
+		}
+	}
+
 	return nil, nil
 }
 
@@ -224,13 +236,25 @@ type Camera struct {
 	bufAndCBByID            map[rtppassthrough.SubscriptionID]bufAndCB
 	cacheImage              image.Image
 	cachePointCloud         pointcloud.PointCloud
+	imperfections           *imperfection.Injector
 	logger                  logging.Logger
 }
 
 // Read always returns the same image of a yellow to blue gradient.
 func (c *Camera) Read(ctx context.Context) (image.Image, func(), error) {
+	if err := c.imperfections.Call(ctx); err != nil {
+		return nil, nil, err
+	}
+	img, err := c.read()
+	if err != nil || !c.imperfections.Perturbs() {
+		return img, func() {}, err
+	}
+	return c.perturbImage(img), func() {}, nil
+}
+
+func (c *Camera) read() (image.Image, error) {
 	if c.cacheImage != nil {
-		return c.cacheImage, func() {}, nil
+		return c.cacheImage, nil
 	}
 	width := float64(c.Width)
 	height := float64(c.Height)
@@ -259,11 +283,48 @@ func (c *Camera) Read(ctx context.Context) (image.Image, func(), error) {
 	if !c.Animated {
 		c.cacheImage = img
 	}
-	return rimage.ConvertImage(img), func() {}, nil
+	return rimage.ConvertImage(img), nil
+}
+
+// perturbImage returns a copy of the image with the camera's imperfections added to the
+// intensity of each color channel.
+func (c *Camera) perturbImage(img image.Image) image.Image {
+	bounds := img.Bounds()
+	perturbed := image.NewNRGBA(bounds)
+	channel := func(value uint32) uint8 {
+		return uint8(math.Max(0, math.Min(255, math.Round(c.imperfections.Value(float64(value>>8))))))
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			perturbed.SetNRGBA(x, y, color.NRGBA{channel(r), channel(g), channel(b), uint8(a >> 8)})
+		}
+	}
+	return perturbed
 }
 
 // NextPointCloud always returns a pointcloud of a yellow to blue gradient, with the depth determined by the intensity of blue.
 func (c *Camera) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	if err := c.imperfections.Call(ctx); err != nil {
+		return nil, err
+	}
+	pc, err := c.nextPointCloud()
+	if err != nil || !c.imperfections.Perturbs() {
+		return pc, err
+	}
+	perturbed := pointcloud.NewWithPrealloc(pc.Size())
+	pc.Iterate(0, 0, func(p r3.Vector, d pointcloud.Data) bool {
+		p.Z = c.imperfections.Value(p.Z)
+		err = perturbed.Set(p, d)
+		return err == nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return perturbed, nil
+}
+
+func (c *Camera) nextPointCloud() (pointcloud.PointCloud, error) {
 	if c.cachePointCloud != nil {
 		return c.cachePointCloud, nil
 	}
@@ -0,0 +1,205 @@
+package camera
+
+import (
+	"context"
+	"image"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/resource"
+)
+
+// A Frame is an image read from a camera by its FrameBroker.
+type Frame struct {
+	Image image.Image
+	// Sequence numbers the frames read by a broker in the order they were read, so that
+	// consumers can tell whether they received the same frame. It is zero for frames that
+	// could not be shared.
+	Sequence uint64
+	// Time is when the frame was read.
+	Time time.Time
+}
+
+// sharedFrame is a frame and the consumers holding it.
+type sharedFrame struct {
+	Frame
+	// readTook is how long reading the frame took.
+	readTook time.Duration
+	// fromDM is whether the frame was read for the data manager, which filter cameras only
+	// return frames to that they want captured.
+	fromDM bool
+	// release releases the frame once it is superseded and no consumer holds it.
+	release    func()
+	refs       int
+	superseded bool
+}
+
+// frameRead is a read of a frame that consumers may wait on.
+type frameRead struct {
+	done   chan struct{}
+	fromDM bool
+	frame  *sharedFrame
+	err    error
+}
+
+// fromDM returns whether the context is of a request from the data manager.
+func fromDM(ctx context.Context) bool {
+	return ctx.Value(data.FromDMContextKey{}) == true
+}
+
+// canShare returns whether a frame read for the data manager, or not, may be given to a
+// consumer with the given context. Frames read for others may have been filtered out for
+// the data manager.
+func canShare(ctx context.Context, readFromDM bool) bool {
+	return readFromDM || !fromDM(ctx)
+}
+
+// A FrameBroker shares the frames of a camera between its consumers, such as the data
+// manager and vision services, so that consumers asking for a frame at about the same time
+// receive the same frame rather than each reading one from the camera. Consumers asking
+// while a frame is being read wait for it, and a frame is shared for as long as reading it
+// took, which for a camera that blocks until its next frame is about its frame period.
+type FrameBroker struct {
+	cam Camera
+
+	mu       sync.Mutex
+	sequence uint64
+	latest   *sharedFrame
+	reading  *frameRead
+}
+
+// NewFrameBroker returns a broker sharing the frames of the given camera.
+func NewFrameBroker(cam Camera) *FrameBroker {
+	return &FrameBroker{cam: cam}
+}
+
+var frameBrokers = struct {
+	mu      sync.Mutex
+	brokers map[resource.Name]*FrameBroker
+}{brokers: map[resource.Name]*FrameBroker{}}
+
+// SharedFrameBroker returns the broker shared by every consumer of the given camera in the
+// process.
+func SharedFrameBroker(cam Camera) *FrameBroker {
+	frameBrokers.mu.Lock()
+	defer frameBrokers.mu.Unlock()
+	name := cam.Name()
+	if broker, ok := frameBrokers.brokers[name]; ok && broker.cam == cam {
+		return broker
+	}
+	if previous, ok := frameBrokers.brokers[name]; ok {
+		// the camera was rebuilt; the frame of the previous one is no longer current.
+		previous.mu.Lock()
+		previous.supersede(nil)
+		previous.mu.Unlock()
+	}
+	broker := NewFrameBroker(cam)
+	frameBrokers.brokers[name] = broker
+	return broker
+}
+
+// Next returns the latest frame of the camera if it is still current, and otherwise reads
+// the next one. The returned function must be called once the consumer is done with the
+// frame.
+func (b *FrameBroker) Next(ctx context.Context) (Frame, func(), error) {
+	b.mu.Lock()
+	if latest := b.latest; latest != nil && canShare(ctx, latest.fromDM) &&
+		clock.Now().Sub(latest.Time) <= latest.readTook {
+		defer b.mu.Unlock()
+		return b.acquire(latest)
+	}
+	read := b.reading
+	leader := read == nil || !canShare(ctx, read.fromDM)
+	if leader {
+		read = &frameRead{done: make(chan struct{}), fromDM: fromDM(ctx)}
+		if b.reading == nil {
+			b.reading = read
+		}
+	}
+	b.mu.Unlock()
+
+	if leader {
+		b.read(ctx, read)
+		if read.err != nil {
+			return Frame{}, nil, read.err
+		}
+	} else {
+		select {
+		case <-read.done:
+		case <-ctx.Done():
+			return Frame{}, nil, ctx.Err()
+		}
+		if read.err != nil {
+			// the read failed for its leader, which may have been canceled; read without
+			// sharing instead.
+			img, release, err := ReadImage(ctx, b.cam)
+			if err != nil {
+				return Frame{}, nil, err
+			}
+			if release == nil {
+				release = func() {}
+			}
+			return Frame{Image: img, Time: clock.Now()}, release, nil
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.acquire(read.frame)
+}
+
+// read reads the next frame of the camera for the given read.
+func (b *FrameBroker) read(ctx context.Context, read *frameRead) {
+	defer close(read.done)
+	start := clock.Now()
+	img, release, err := ReadImage(ctx, b.cam)
+	now := clock.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.reading == read {
+		b.reading = nil
+	}
+	if err != nil {
+		read.err = err
+		return
+	}
+	b.sequence++
+	read.frame = &sharedFrame{
+		Frame:    Frame{Image: img, Sequence: b.sequence, Time: now},
+		readTook: now.Sub(start),
+		fromDM:   read.fromDM,
+		release:  release,
+	}
+	b.supersede(read.frame)
+}
+
+// supersede makes the given frame the latest, releasing the previous one if no consumer
+// holds it. It must be called with the broker locked.
+func (b *FrameBroker) supersede(frame *sharedFrame) {
+	if previous := b.latest; previous != nil {
+		previous.superseded = true
+		if previous.refs == 0 && previous.release != nil {
+			previous.release()
+		}
+	}
+	b.latest = frame
+}
+
+// acquire returns the frame for a consumer and the function it calls once done with it. It
+// must be called with the broker locked.
+func (b *FrameBroker) acquire(frame *sharedFrame) (Frame, func(), error) {
+	frame.refs++
+	var once sync.Once
+	return frame.Frame, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			frame.refs--
+			if frame.refs == 0 && frame.superseded && frame.release != nil {
+				frame.release()
+			}
+		})
+	}, nil
+}
@@ -0,0 +1,117 @@
+package camera_test
+
+import (
+	"context"
+	"image"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// readerCamera is a camera whose frames are read directly, each read taking a frame period
+// of virtual time once it is allowed to finish.
+type readerCamera struct {
+	*inject.Camera
+	virtual  *clock.Virtual
+	proceed  chan struct{}
+	mu       sync.Mutex
+	reads    int
+	released int
+}
+
+func (c *readerCamera) Read(ctx context.Context) (image.Image, func(), error) {
+	<-c.proceed
+	c.virtual.Step(100 * time.Millisecond)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reads++
+	return image.NewGray(image.Rect(0, 0, c.reads, 1)), func() {
+		c.mu.Lock()
+		c.released++
+		c.mu.Unlock()
+	}, nil
+}
+
+func (c *readerCamera) counts() (int, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.reads, c.released
+}
+
+func TestFrameBroker(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+	cam := &readerCamera{Camera: inject.NewCamera("cam1"), virtual: virtual, proceed: make(chan struct{})}
+	broker := camera.SharedFrameBroker(cam)
+	test.That(t, camera.SharedFrameBroker(cam), test.ShouldEqual, broker)
+	ctx := context.Background()
+
+	// consumers asking while a frame is read wait for it.
+	type result struct {
+		frame   camera.Frame
+		release func()
+		err     error
+	}
+	results := make(chan result, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			frame, release, err := broker.Next(ctx)
+			results <- result{frame, release, err}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(cam.proceed)
+	first, second := <-results, <-results
+	test.That(t, first.err, test.ShouldBeNil)
+	test.That(t, second.err, test.ShouldBeNil)
+	test.That(t, first.frame.Sequence, test.ShouldEqual, 1)
+	test.That(t, second.frame.Sequence, test.ShouldEqual, 1)
+	test.That(t, second.frame.Image, test.ShouldEqual, first.frame.Image)
+	reads, _ := cam.counts()
+	test.That(t, reads, test.ShouldEqual, 1)
+
+	// the frame is shared for as long as reading it took.
+	frame, release, err := broker.Next(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frame.Sequence, test.ShouldEqual, 1)
+	release()
+	first.release()
+	second.release()
+	_, released := cam.counts()
+	test.That(t, released, test.ShouldEqual, 0)
+
+	// and released once a newer one supersedes it.
+	virtual.Step(200 * time.Millisecond)
+	frame, release, err = broker.Next(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frame.Sequence, test.ShouldEqual, 2)
+	reads, released = cam.counts()
+	test.That(t, reads, test.ShouldEqual, 2)
+	test.That(t, released, test.ShouldEqual, 1)
+	release()
+
+	// frames read for others are not given to the data manager, which cameras may filter
+	// frames for, but frames read for it are given to others.
+	dmCtx := context.WithValue(ctx, data.FromDMContextKey{}, true)
+	frame, release, err = broker.Next(dmCtx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frame.Sequence, test.ShouldEqual, 3)
+	release()
+	frame, release, err = broker.Next(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frame.Sequence, test.ShouldEqual, 3)
+	release()
+
+	// a rebuilt camera gets a new broker.
+	rebuilt := &readerCamera{Camera: inject.NewCamera("cam1"), virtual: virtual, proceed: cam.proceed}
+	test.That(t, camera.SharedFrameBroker(rebuilt), test.ShouldNotEqual, broker)
+	_, released = cam.counts()
+	test.That(t, released, test.ShouldEqual, 3)
+}
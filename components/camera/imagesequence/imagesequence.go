@@ -0,0 +1,365 @@
+// Package imagesequence implements a camera that plays back frames from a directory of
+// images or a video file, used to develop vision and SLAM pipelines without hardware.
+package imagesequence
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+)
+
+var (
+	// Model is the model of the image sequence camera.
+	Model = resource.DefaultModelFamily.WithModel("image_sequence")
+	// ErrEndOfSequence is returned once every frame has been played back and loop is not set.
+	ErrEndOfSequence = errors.New("reached end of image sequence")
+)
+
+// TimestampSource is where the capture time of each frame comes from.
+type TimestampSource string
+
+const (
+	// TimestampNow uses the time each frame is requested, according to the robot clock.
+	TimestampNow TimestampSource = "now"
+	// TimestampFrameRate uses the time the frame is due: the start of playback plus the
+	// frame's position divided by fps.
+	TimestampFrameRate TimestampSource = "frame_rate"
+	// TimestampFileName parses the frame's file name as unix seconds with an optional
+	// fraction (e.g. 1305031102.175304.png), as used by many SLAM datasets.
+	TimestampFileName TimestampSource = "file_name"
+)
+
+// SupportedTimestampSources is a list of all supported timestamp sources.
+var SupportedTimestampSources = []TimestampSource{TimestampNow, TimestampFrameRate, TimestampFileName}
+
+var (
+	colorExtensions = []string{".png", ".jpg", ".jpeg", ".ppm", ".qoi"}
+	depthExtensions = []string{".png", ".dat", ".dat.gz"}
+)
+
+func init() {
+	resource.RegisterComponent(
+		camera.API,
+		Model,
+		resource.Registration[camera.Camera, *Config]{Constructor: NewCamera},
+	)
+}
+
+// Config are the attributes of an image sequence camera.
+type Config struct {
+	CameraParameters     *transform.PinholeCameraIntrinsics `json:"intrinsic_parameters,omitempty"`
+	DistortionParameters *transform.BrownConrady            `json:"distortion_parameters,omitempty"`
+	// ImageDir is a directory of color images played back in file name order.
+	ImageDir string `json:"image_dir,omitempty"`
+	// VideoPath is a video file whose frames are played back. Requires ffmpeg.
+	VideoPath string `json:"video_path,omitempty"`
+	// DepthDir is a directory of depth images paired in file name order with the color
+	// frames.
+	DepthDir string `json:"depth_dir,omitempty"`
+	// FPS is the playback rate. When zero, each request advances one frame so that no
+	// frame is skipped.
+	FPS float64 `json:"fps,omitempty"`
+	// Loop restarts playback from the first frame after the last one.
+	Loop bool `json:"loop,omitempty"`
+	// Timestamps is where frame capture times come from. Defaults to now.
+	Timestamps TimestampSource `json:"timestamps,omitempty"`
+}
+
+// Validate checks that the config attributes are valid for an image sequence camera.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.ImageDir == "" && conf.VideoPath == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "image_dir")
+	}
+	if conf.ImageDir != "" && conf.VideoPath != "" {
+		return nil, resource.NewConfigValidationError(path, errors.New("only one of image_dir and video_path may be set"))
+	}
+	if conf.FPS < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("fps must not be negative"))
+	}
+	if conf.Timestamps != "" && !slices.Contains(SupportedTimestampSources, conf.Timestamps) {
+		return nil, resource.NewConfigValidationError(path, errors.Errorf("unsupported timestamps %q. Must be one of: %v",
+			conf.Timestamps, SupportedTimestampSources))
+	}
+	if conf.Timestamps == TimestampFrameRate && conf.FPS == 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("frame_rate timestamps require fps"))
+	}
+	if conf.Timestamps == TimestampFileName && conf.ImageDir == "" {
+		return nil, resource.NewConfigValidationError(path, errors.New("file_name timestamps require image_dir"))
+	}
+	if conf.CameraParameters != nil {
+		if conf.CameraParameters.Width < 0 || conf.CameraParameters.Height < 0 {
+			return nil, fmt.Errorf(
+				"got illegal negative dimensions for width_px and height_px (%d, %d) fields set in intrinsic_parameters for image_sequence camera",
+				conf.CameraParameters.Width, conf.CameraParameters.Height)
+		}
+	}
+	return []string{}, nil
+}
+
+// NewCamera returns a new image sequence camera.
+func NewCamera(
+	ctx context.Context,
+	_ resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (camera.Camera, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
+	src, err := newSequenceSource(ctx, newConf, logger)
+	if err != nil {
+		return nil, err
+	}
+	cameraModel := camera.NewPinholeModelWithBrownConradyDistortion(newConf.CameraParameters, newConf.DistortionParameters)
+	vs, err := camera.NewVideoSourceFromReader(ctx, src, &cameraModel, camera.ColorStream)
+	if err != nil {
+		return nil, multierr.Combine(err, src.Close(ctx))
+	}
+	return camera.FromVideoSource(conf.ResourceName(), vs, logger), nil
+}
+
+type frame struct {
+	color      string
+	depth      string
+	capturedAt time.Time // only set for file_name timestamps
+}
+
+// sequenceSource plays back frames read from files.
+type sequenceSource struct {
+	conf       *Config
+	frames     []frame
+	intrinsics *transform.PinholeCameraIntrinsics
+	tempDir    string
+
+	mu      sync.Mutex
+	started bool
+	start   time.Time
+	next    int
+}
+
+func newSequenceSource(ctx context.Context, conf *Config, logger logging.Logger) (*sequenceSource, error) {
+	src := &sequenceSource{conf: conf, intrinsics: conf.CameraParameters}
+	imageDir := conf.ImageDir
+	if conf.VideoPath != "" {
+		var err error
+		if imageDir, err = extractFrames(ctx, conf.VideoPath, logger); err != nil {
+			return nil, err
+		}
+		src.tempDir = imageDir
+	}
+
+	colorFiles, err := listFiles(imageDir, colorExtensions)
+	if err == nil && len(colorFiles) == 0 {
+		err = errors.Errorf("no images found in %q", imageDir)
+	}
+	var depthFiles []string
+	if err == nil && conf.DepthDir != "" {
+		depthFiles, err = listFiles(conf.DepthDir, depthExtensions)
+		if err == nil && len(depthFiles) != len(colorFiles) {
+			err = errors.Errorf("found %d depth images but %d color frames", len(depthFiles), len(colorFiles))
+		}
+	}
+	if err != nil {
+		return nil, multierr.Combine(err, src.Close(ctx))
+	}
+
+	src.frames = make([]frame, len(colorFiles))
+	for idx, fn := range colorFiles {
+		src.frames[idx].color = fn
+		if depthFiles != nil {
+			src.frames[idx].depth = depthFiles[idx]
+		}
+		if conf.Timestamps == TimestampFileName {
+			if src.frames[idx].capturedAt, err = parseFileNameTime(fn); err != nil {
+				return nil, multierr.Combine(err, src.Close(ctx))
+			}
+		}
+	}
+	return src, nil
+}
+
+// extractFrames decodes every frame of a video into a new temporary directory.
+func extractFrames(ctx context.Context, videoPath string, logger logging.Logger) (string, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return "", errors.Wrap(err, "ffmpeg is required to play back video files")
+	}
+	dir, err := os.MkdirTemp("", "image_sequence")
+	if err != nil {
+		return "", err
+	}
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, "ffmpeg", "-loglevel", "error", "-i", videoPath,
+		"-q:v", "2", filepath.Join(dir, "%08d.jpg"))
+	logger.Debugw("extracting video frames", "cmd", cmd.String())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return "", multierr.Combine(
+			errors.Wrapf(err, "failed to extract frames from %q: %s", videoPath, strings.TrimSpace(string(out))),
+			os.RemoveAll(dir))
+	}
+	return dir, nil
+}
+
+// listFiles returns the files in dir with one of the given extensions, sorted by name.
+func listFiles(dir string, extensions []string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		name := strings.ToLower(entry.Name())
+		if slices.ContainsFunc(extensions, func(ext string) bool { return strings.HasSuffix(name, ext) }) {
+			files = append(files, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// parseFileNameTime parses a file name of the form <seconds>[.<fraction>].<ext>.
+func parseFileNameTime(fn string) (time.Time, error) {
+	base := filepath.Base(fn)
+	parts := strings.SplitN(base, ".", 3)
+	secs, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return time.Time{}, errors.Errorf("cannot parse timestamp from file name %q", base)
+	}
+	var nanos int64
+	if len(parts) == 3 && parts[1] != "" {
+		frac := parts[1]
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		frac += strings.Repeat("0", 9-len(frac))
+		if nanos, err = strconv.ParseInt(frac, 10, 64); err != nil {
+			return time.Time{}, errors.Errorf("cannot parse timestamp from file name %q", base)
+		}
+	}
+	return time.Unix(secs, nanos), nil
+}
+
+// nextFrame returns the frame to serve now and its capture time.
+func (src *sequenceSource) nextFrame() (frame, time.Time, error) {
+	src.mu.Lock()
+	defer src.mu.Unlock()
+	now := clock.Now()
+	if !src.started {
+		src.started = true
+		src.start = now
+	}
+
+	var pos int
+	if src.conf.FPS == 0 {
+		pos = src.next
+		src.next++
+	} else {
+		pos = int(now.Sub(src.start).Seconds() * src.conf.FPS)
+	}
+	idx := pos
+	if idx >= len(src.frames) {
+		if !src.conf.Loop {
+			return frame{}, time.Time{}, ErrEndOfSequence
+		}
+		idx %= len(src.frames)
+	}
+
+	f := src.frames[idx]
+	switch src.conf.Timestamps {
+	case TimestampFrameRate:
+		return f, src.start.Add(time.Duration(float64(pos) / src.conf.FPS * float64(time.Second))), nil
+	case TimestampFileName:
+		return f, f.capturedAt, nil
+	case TimestampNow, "":
+	}
+	return f, now, nil
+}
+
+// Read returns the color image of the current frame.
+func (src *sequenceSource) Read(ctx context.Context) (image.Image, func(), error) {
+	f, _, err := src.nextFrame()
+	if err != nil {
+		return nil, nil, err
+	}
+	img, err := rimage.NewImageFromFile(f.color)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, func() {}, nil
+}
+
+// Images returns the color and, if configured, depth image of the current frame.
+func (src *sequenceSource) Images(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+	f, capturedAt, err := src.nextFrame()
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	img, err := rimage.NewImageFromFile(f.color)
+	if err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	imgs := []camera.NamedImage{{img, "color"}}
+	if f.depth != "" {
+		dm, err := rimage.NewDepthMapFromFile(ctx, f.depth)
+		if err != nil {
+			return nil, resource.ResponseMetadata{}, err
+		}
+		imgs = append(imgs, camera.NamedImage{dm, "depth"})
+	}
+	return imgs, resource.ResponseMetadata{CapturedAt: capturedAt}, nil
+}
+
+// NextPointCloud returns the point cloud from projecting the color and depth images of the
+// current frame using the intrinsic parameters.
+func (src *sequenceSource) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	if src.intrinsics == nil {
+		return nil, transform.NewNoIntrinsicsError("camera intrinsics not found in config")
+	}
+	if src.conf.DepthDir == "" {
+		return nil, errors.New("no depth images to project to pointcloud")
+	}
+	f, _, err := src.nextFrame()
+	if err != nil {
+		return nil, err
+	}
+	img, err := rimage.NewImageFromFile(f.color)
+	if err != nil {
+		return nil, err
+	}
+	dm, err := rimage.NewDepthMapFromFile(ctx, f.depth)
+	if err != nil {
+		return nil, err
+	}
+	return src.intrinsics.RGBDToPointCloud(img, dm)
+}
+
+// Close removes any frames extracted from a video.
+func (src *sequenceSource) Close(ctx context.Context) error {
+	if src.tempDir == "" {
+		return nil
+	}
+	return os.RemoveAll(src.tempDir)
+}
@@ -0,0 +1,230 @@
+package imagesequence
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage"
+	"go.viam.com/rdk/rimage/transform"
+)
+
+// writeFrames writes n color images to dir, each filled with a gray level equal to its
+// index, named with the given function.
+func writeFrames(t *testing.T, dir string, n int, name func(idx int) string) {
+	t.Helper()
+	for idx := 0; idx < n; idx++ {
+		img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+		for x := 0; x < 4; x++ {
+			for y := 0; y < 2; y++ {
+				img.Set(x, y, color.RGBA{uint8(idx), uint8(idx), uint8(idx), 255})
+			}
+		}
+		test.That(t, rimage.WriteImageToFile(filepath.Join(dir, name(idx)), img), test.ShouldBeNil)
+	}
+}
+
+func frameIndex(t *testing.T, imgs []camera.NamedImage) int {
+	t.Helper()
+	test.That(t, imgs, test.ShouldNotBeEmpty)
+	r, _, _, _ := imgs[0].Image.At(0, 0).RGBA()
+	return int(r >> 8)
+}
+
+func newTestCamera(t *testing.T, conf *Config) camera.Camera {
+	t.Helper()
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+	cam, err := NewCamera(context.Background(), nil, resource.Config{
+		Name:                "cam",
+		API:                 camera.API,
+		Model:               Model,
+		ConvertedAttributes: conf,
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	return cam
+}
+
+func TestValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		conf Config
+		err  string
+	}{
+		{"no source", Config{}, "image_dir"},
+		{"two sources", Config{ImageDir: "a", VideoPath: "b"}, "only one of"},
+		{"negative fps", Config{ImageDir: "a", FPS: -1}, "fps"},
+		{"bad timestamps", Config{ImageDir: "a", Timestamps: "bad"}, "unsupported timestamps"},
+		{"frame rate without fps", Config{ImageDir: "a", Timestamps: TimestampFrameRate}, "require fps"},
+		{"file name from video", Config{VideoPath: "a", Timestamps: TimestampFileName}, "require image_dir"},
+		{"valid", Config{ImageDir: "a", FPS: 30, Loop: true, Timestamps: TimestampFrameRate}, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.conf.Validate("path")
+			if tc.err == "" {
+				test.That(t, err, test.ShouldBeNil)
+			} else {
+				test.That(t, err, test.ShouldNotBeNil)
+				test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+			}
+		})
+	}
+}
+
+func TestStepPlayback(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFrames(t, dir, 3, func(idx int) string { return []string{"a.png", "b.jpg", "c.png"}[idx] })
+
+	cam := newTestCamera(t, &Config{ImageDir: dir})
+	for idx := 0; idx < 3; idx++ {
+		imgs, _, err := cam.Images(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, frameIndex(t, imgs), test.ShouldEqual, idx)
+	}
+	_, _, err := cam.Images(ctx)
+	test.That(t, err, test.ShouldBeError, ErrEndOfSequence)
+	test.That(t, cam.Close(ctx), test.ShouldBeNil)
+
+	cam = newTestCamera(t, &Config{ImageDir: dir, Loop: true})
+	for idx := 0; idx < 5; idx++ {
+		imgs, _, err := cam.Images(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, frameIndex(t, imgs), test.ShouldEqual, idx%3)
+	}
+	test.That(t, cam.Close(ctx), test.ShouldBeNil)
+
+	_, err = NewCamera(ctx, nil, resource.Config{
+		Name:                "cam",
+		API:                 camera.API,
+		Model:               Model,
+		ConvertedAttributes: &Config{ImageDir: t.TempDir()},
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no images found")
+}
+
+func TestFrameRatePlayback(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeFrames(t, dir, 4, func(idx int) string { return []string{"0.png", "1.png", "2.png", "3.png"}[idx] })
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	virtual := clock.NewVirtual(start)
+	defer clock.Set(virtual)()
+
+	cam := newTestCamera(t, &Config{ImageDir: dir, FPS: 10, Loop: true, Timestamps: TimestampFrameRate})
+	defer func() {
+		test.That(t, cam.Close(ctx), test.ShouldBeNil)
+	}()
+
+	// playback starts with the first request.
+	imgs, meta, err := cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frameIndex(t, imgs), test.ShouldEqual, 0)
+	test.That(t, meta.CapturedAt, test.ShouldEqual, start)
+
+	virtual.Step(250 * time.Millisecond)
+	imgs, meta, err = cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frameIndex(t, imgs), test.ShouldEqual, 2)
+	test.That(t, meta.CapturedAt, test.ShouldEqual, start.Add(200*time.Millisecond))
+
+	// frames are repeated until the next one is due and loop at the end.
+	imgs, _, err = cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frameIndex(t, imgs), test.ShouldEqual, 2)
+	virtual.Step(300 * time.Millisecond)
+	imgs, meta, err = cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frameIndex(t, imgs), test.ShouldEqual, 1)
+	test.That(t, meta.CapturedAt, test.ShouldEqual, start.Add(500*time.Millisecond))
+}
+
+func TestDepthAndFileNameTimestamps(t *testing.T) {
+	ctx := context.Background()
+	colorDir := t.TempDir()
+	depthDir := t.TempDir()
+	names := []string{"1305031102.175304.png", "1305031102.211214.png"}
+	writeFrames(t, colorDir, 2, func(idx int) string { return names[idx] })
+	for idx := 0; idx < 2; idx++ {
+		dm := rimage.NewEmptyDepthMap(4, 2)
+		for x := 0; x < 4; x++ {
+			for y := 0; y < 2; y++ {
+				dm.Set(x, y, rimage.Depth(1000*(idx+1)))
+			}
+		}
+		test.That(t, rimage.WriteImageToFile(filepath.Join(depthDir, names[idx]), dm), test.ShouldBeNil)
+	}
+
+	cam := newTestCamera(t, &Config{
+		ImageDir:   colorDir,
+		DepthDir:   depthDir,
+		Timestamps: TimestampFileName,
+		CameraParameters: &transform.PinholeCameraIntrinsics{
+			Width: 4, Height: 2, Fx: 1, Fy: 1, Ppx: 2, Ppy: 1,
+		},
+	})
+	defer func() {
+		test.That(t, cam.Close(ctx), test.ShouldBeNil)
+	}()
+
+	imgs, meta, err := cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, imgs, test.ShouldHaveLength, 2)
+	test.That(t, imgs[1].SourceName, test.ShouldEqual, "depth")
+	dm, ok := imgs[1].Image.(*rimage.DepthMap)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, dm.GetDepth(0, 0), test.ShouldEqual, rimage.Depth(1000))
+	test.That(t, meta.CapturedAt, test.ShouldEqual, time.Unix(1305031102, 175304000))
+
+	pc, err := cam.NextPointCloud(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pc.Size(), test.ShouldEqual, 8)
+
+	_, _, err = cam.Images(ctx)
+	test.That(t, err, test.ShouldBeError, ErrEndOfSequence)
+
+	_, err = NewCamera(ctx, nil, resource.Config{
+		Name:                "cam",
+		API:                 camera.API,
+		Model:               Model,
+		ConvertedAttributes: &Config{ImageDir: colorDir, DepthDir: t.TempDir()},
+	}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "found 0 depth images but 2 color frames")
+}
+
+func TestVideoPlayback(t *testing.T) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		t.Skip("ffmpeg is not installed")
+	}
+	ctx := context.Background()
+	dir := t.TempDir()
+	videoPath := filepath.Join(dir, "video.mp4")
+	//nolint:gosec
+	out, err := exec.Command("ffmpeg", "-loglevel", "error", "-f", "lavfi", "-i", "testsrc=size=64x48:rate=10",
+		"-frames:v", "5", "-pix_fmt", "yuv420p", videoPath).CombinedOutput()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(out), test.ShouldBeEmpty)
+
+	cam := newTestCamera(t, &Config{VideoPath: videoPath})
+	for idx := 0; idx < 5; idx++ {
+		imgs, _, err := cam.Images(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, imgs[0].Image.Bounds().Dx(), test.ShouldEqual, 64)
+	}
+	_, _, err = cam.Images(ctx)
+	test.That(t, err, test.ShouldBeError, ErrEndOfSequence)
+	test.That(t, cam.Close(ctx), test.ShouldBeNil)
+}
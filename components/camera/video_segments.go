@@ -0,0 +1,280 @@
+package camera
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.opencensus.io/trace"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/gostream/codec"
+	"go.viam.com/rdk/logging"
+)
+
+// An EncodedVideoSource provides the video of a camera as encoded for streaming it, such as
+// by the web service's stream server.
+type EncodedVideoSource interface {
+	// SubscribeEncodedVideo returns the queue of the video frames encoded for the camera from
+	// now on, streaming the camera if it is not, until unsubscribe is called, which closes
+	// the queue. Frames the subscriber has no room for are handled with the policy of the
+	// queue.
+	SubscribeEncodedVideo(ctx context.Context, queue gostream.QueueOptions) (
+		frames *gostream.Queue[gostream.EncodedVideoFrame], unsubscribe func(), err error)
+}
+
+var encodedVideoSources = struct {
+	mu      sync.Mutex
+	sources map[string]*EncodedVideoSource
+}{sources: map[string]*EncodedVideoSource{}}
+
+// RegisterEncodedVideoSource makes the given source provide the encoded video of the named
+// camera in the process, replacing any previous source, until the returned function is
+// called.
+func RegisterEncodedVideoSource(name string, source EncodedVideoSource) (unregister func()) {
+	encodedVideoSources.mu.Lock()
+	defer encodedVideoSources.mu.Unlock()
+	registered := &source
+	encodedVideoSources.sources[name] = registered
+	return func() {
+		encodedVideoSources.mu.Lock()
+		defer encodedVideoSources.mu.Unlock()
+		if encodedVideoSources.sources[name] == registered {
+			delete(encodedVideoSources.sources, name)
+		}
+	}
+}
+
+// LookupEncodedVideoSource returns the source of the encoded video of the named camera, if
+// one is registered.
+func LookupEncodedVideoSource(name string) (EncodedVideoSource, bool) {
+	encodedVideoSources.mu.Lock()
+	defer encodedVideoSources.mu.Unlock()
+	source, ok := encodedVideoSources.sources[name]
+	if !ok {
+		return nil, false
+	}
+	return *source, true
+}
+
+// maxVideoFrameRate bounds the frame rate of streamed video, to size the buffer of frames
+// kept between captures of video segments.
+const maxVideoFrameRate = 60
+
+// videoSegmentCollector captures the video streamed for a camera as segments, each holding
+// the frames encoded since the previous segment, so that video is stored without decoding
+// and encoding it again. Segments start at key frames so that each can be played on its
+// own; frames after the last key frame are kept for the next segment. H264 segments are
+// Annex B byte streams and VP8 segments are IVF files.
+//
+// Cameras whose stream passes their RTP packets through are not encoded by the stream, and
+// no segments are captured while they are.
+type videoSegmentCollector struct {
+	data.Collector
+	name       string
+	bufferSize int
+	logger     logging.Logger
+
+	mu          sync.Mutex
+	frames      *gostream.Queue[gostream.EncodedVideoFrame]
+	unsubscribe func()
+	// dropped is how many frames the queue had dropped when they were last received.
+	dropped uint64
+	pending []gostream.EncodedVideoFrame
+}
+
+func newVideoSegmentsCollector(resource interface{}, params data.CollectorParams) (data.Collector, error) {
+	if _, err := assertCamera(resource); err != nil {
+		return nil, err
+	}
+	c := &videoSegmentCollector{
+		name:       params.ComponentName,
+		bufferSize: codec.DefaultKeyFrameInterval + int(params.Interval.Seconds()*maxVideoFrameRate),
+		logger:     params.Logger,
+	}
+	cFunc := data.CaptureFunc(func(ctx context.Context, _ map[string]*anypb.Any) (interface{}, error) {
+		_, span := trace.StartSpan(ctx, "camera::data::collector::CaptureFunc::VideoSegments")
+		defer span.End()
+
+		segment, err := c.nextSegment(ctx)
+		if err != nil {
+			if errors.Is(err, data.ErrNoCaptureToStore) {
+				return nil, err
+			}
+			return nil, data.FailedToReadErr(params.ComponentName, videoSegments.String(), err)
+		}
+		return segment, nil
+	})
+	collector, err := data.NewCollector(cFunc, params)
+	if err != nil {
+		return nil, err
+	}
+	c.Collector = collector
+	return c, nil
+}
+
+// Close stops capturing and unsubscribes from the camera's video.
+func (c *videoSegmentCollector) Close() {
+	c.Collector.Close()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.unsubscribe != nil {
+		c.unsubscribe()
+		c.frames, c.unsubscribe = nil, nil
+	}
+}
+
+// nextSegment returns the segment of the frames received since the previous one, up to the
+// last key frame received. It returns data.ErrNoCaptureToStore if there is none yet.
+func (c *videoSegmentCollector) nextSegment(ctx context.Context) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.frames == nil {
+		source, ok := LookupEncodedVideoSource(c.name)
+		if !ok {
+			return nil, errors.Errorf("camera %q is not streamed; video segments need a stream of the camera", c.name)
+		}
+		// frames are kept in the order they were encoded, the newest being dropped, so that
+		// the frames before a drop can still be completed into a segment.
+		frames, unsubscribe, err := source.SubscribeEncodedVideo(ctx, gostream.QueueOptions{
+			Size:   c.bufferSize,
+			Policy: gostream.QueueDropNewest,
+		})
+		if err != nil {
+			return nil, err
+		}
+		c.frames, c.unsubscribe, c.dropped = frames, unsubscribe, 0
+	}
+
+	// frames dropped since the last capture leave a gap after the frames queued, past which
+	// the frames kept cannot be continued; the frames before it end the segment.
+	dropped := c.frames.Stats().Dropped
+	gap := dropped > c.dropped
+	queued := len(c.frames.Items())
+	if gap {
+		c.logger.Warnw("video frames were dropped while capturing video segments; the current segment ends early",
+			"camera", c.name, "dropped", dropped-c.dropped)
+	}
+	c.dropped = dropped
+
+receive:
+	for received := 0; !gap || received < queued; received++ {
+		select {
+		case frame, ok := <-c.frames.Items():
+			if !ok {
+				// the stream ended, such as when the web service restarted; subscribe again
+				// on the next capture.
+				c.frames, c.unsubscribe = nil, nil
+				break receive
+			}
+			if !isH264(frame) && !isVP8(frame) {
+				return nil, errors.Errorf("cannot capture video segments of %q video", frame.MIMEType)
+			}
+			if len(c.pending) == 0 && !isKeyFrame(frame) {
+				continue
+			}
+			if len(c.pending) != 0 && frame.MIMEType != c.pending[0].MIMEType {
+				// the stream changed encoding; the frames kept cannot be completed.
+				c.pending = nil
+				if !isKeyFrame(frame) {
+					continue
+				}
+			}
+			c.pending = append(c.pending, frame)
+		default:
+			break receive
+		}
+	}
+
+	end := 0
+	if gap {
+		end = len(c.pending)
+	}
+	for idx := len(c.pending) - 1; idx > 0 && end == 0; idx-- {
+		if isKeyFrame(c.pending[idx]) {
+			end = idx
+			break
+		}
+	}
+	if end == 0 {
+		return nil, data.ErrNoCaptureToStore
+	}
+	segment, err := encodeVideoSegment(c.pending[:end])
+	c.pending = append([]gostream.EncodedVideoFrame(nil), c.pending[end:]...)
+	return segment, err
+}
+
+func isH264(frame gostream.EncodedVideoFrame) bool {
+	return strings.EqualFold(frame.MIMEType, "video/h264")
+}
+
+func isVP8(frame gostream.EncodedVideoFrame) bool {
+	return strings.EqualFold(frame.MIMEType, "video/vp8")
+}
+
+// isKeyFrame returns whether the frame can be decoded without the frames before it.
+func isKeyFrame(frame gostream.EncodedVideoFrame) bool {
+	switch {
+	case isH264(frame):
+		// look for an IDR slice or a sequence parameter set, which precedes one, among the NAL
+		// units of the Annex B frame.
+		for _, nal := range bytes.Split(frame.Data, []byte{0, 0, 1}) {
+			if len(nal) == 0 {
+				continue
+			}
+			if nalType := nal[0] & 0x1f; nalType == 5 || nalType == 7 {
+				return true
+			}
+		}
+		return false
+	case isVP8(frame):
+		// the first bit of the frame tag is zero for key frames.
+		return len(frame.Data) != 0 && frame.Data[0]&1 == 0
+	default:
+		return false
+	}
+}
+
+// encodeVideoSegment returns the frames, which start with a key frame, as an H264 Annex B
+// byte stream or a VP8 IVF file.
+func encodeVideoSegment(frames []gostream.EncodedVideoFrame) ([]byte, error) {
+	var buf bytes.Buffer
+	switch {
+	case isH264(frames[0]):
+		for _, frame := range frames {
+			buf.Write(frame.Data)
+		}
+	case isVP8(frames[0]):
+		var width, height uint16
+		if key := frames[0].Data; len(key) >= 10 {
+			width = binary.LittleEndian.Uint16(key[6:8]) & 0x3fff
+			height = binary.LittleEndian.Uint16(key[8:10]) & 0x3fff
+		}
+		header := make([]byte, 32)
+		copy(header, "DKIF")
+		binary.LittleEndian.PutUint16(header[6:], 32)
+		copy(header[8:], "VP80")
+		binary.LittleEndian.PutUint16(header[12:], width)
+		binary.LittleEndian.PutUint16(header[14:], height)
+		// timestamps are in milliseconds.
+		binary.LittleEndian.PutUint32(header[16:], 1000)
+		binary.LittleEndian.PutUint32(header[20:], 1)
+		binary.LittleEndian.PutUint32(header[24:], uint32(len(frames)))
+		buf.Write(header)
+		for _, frame := range frames {
+			frameHeader := make([]byte, 12)
+			binary.LittleEndian.PutUint32(frameHeader, uint32(len(frame.Data)))
+			binary.LittleEndian.PutUint64(frameHeader[4:], uint64(frame.Time.Sub(frames[0].Time).Milliseconds()))
+			buf.Write(frameHeader)
+			buf.Write(frame.Data)
+		}
+	default:
+		return nil, errors.Errorf("cannot capture video segments of %q video", frames[0].MIMEType)
+	}
+	return buf.Bytes(), nil
+}
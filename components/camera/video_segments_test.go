@@ -0,0 +1,185 @@
+package camera_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	clk "github.com/benbjohnson/clock"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	tu "go.viam.com/rdk/testutils"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+const numRetries = 5
+
+// fakeVideoSource is a stream of encoded video whose frames are sent by the test.
+type fakeVideoSource struct {
+	frames       *gostream.Queue[gostream.EncodedVideoFrame]
+	subscribed   chan struct{}
+	unsubscribed chan struct{}
+}
+
+func newFakeVideoSource(size int) *fakeVideoSource {
+	return &fakeVideoSource{
+		frames:       gostream.NewQueue[gostream.EncodedVideoFrame](gostream.QueueOptions{Size: size}),
+		subscribed:   make(chan struct{}),
+		unsubscribed: make(chan struct{}),
+	}
+}
+
+func (s *fakeVideoSource) SubscribeEncodedVideo(
+	ctx context.Context, queue gostream.QueueOptions,
+) (*gostream.Queue[gostream.EncodedVideoFrame], func(), error) {
+	close(s.subscribed)
+	return s.frames, func() { close(s.unsubscribed) }, nil
+}
+
+func (s *fakeVideoSource) send(frames ...gostream.EncodedVideoFrame) {
+	for _, frame := range frames {
+		s.frames.Push(context.Background(), frame)
+	}
+}
+
+func newVideoSegmentsCollector(t *testing.T, buf *tu.MockBuffer, mockClock *clk.Mock) data.Collector {
+	t.Helper()
+	params := data.CollectorParams{
+		ComponentName: "camera",
+		Interval:      time.Second,
+		Logger:        logging.NewTestLogger(t),
+		Target:        buf,
+		Clock:         mockClock,
+	}
+	newCollector := data.CollectorLookup(data.MethodMetadata{API: camera.API, MethodName: "VideoSegments"})
+	test.That(t, newCollector, test.ShouldNotBeNil)
+	col, err := (*newCollector)(&inject.Camera{}, params)
+	test.That(t, err, test.ShouldBeNil)
+	return col
+}
+
+func TestVideoSegmentsCollector(t *testing.T) {
+	const interval = time.Second
+	start := time.Now()
+	frame := func(mimeType string, idx int, data ...byte) gostream.EncodedVideoFrame {
+		return gostream.EncodedVideoFrame{Data: data, MIMEType: mimeType, Time: start.Add(time.Duration(idx) * 100 * time.Millisecond)}
+	}
+	h264Key := []byte{0, 0, 0, 1, 0x67, 1, 0, 0, 0, 1, 0x65, 2}
+	h264Delta := []byte{0, 0, 0, 1, 0x41, 3}
+	vp8Key := []byte{0x10, 0, 0, 0x9d, 0x01, 0x2a, 0x40, 0x01, 0xf0, 0x00}
+	vp8Delta := []byte{0x11, 0, 0}
+
+	for _, tc := range []struct {
+		mimeType   string
+		key, delta []byte
+		check      func(t *testing.T, segment []byte)
+	}{
+		{
+			mimeType: "video/H264",
+			key:      h264Key,
+			delta:    h264Delta,
+			check: func(t *testing.T, segment []byte) {
+				// the segment starts at the first key frame and ends before the last one.
+				test.That(t, segment, test.ShouldResemble, bytes.Join([][]byte{h264Key, h264Delta, h264Delta}, nil))
+			},
+		},
+		{
+			mimeType: "video/vp8",
+			key:      vp8Key,
+			delta:    vp8Delta,
+			check: func(t *testing.T, segment []byte) {
+				test.That(t, string(segment[:4]), test.ShouldEqual, "DKIF")
+				test.That(t, string(segment[8:12]), test.ShouldEqual, "VP80")
+				test.That(t, binary.LittleEndian.Uint16(segment[12:]), test.ShouldEqual, 320)
+				test.That(t, binary.LittleEndian.Uint16(segment[14:]), test.ShouldEqual, 240)
+				test.That(t, binary.LittleEndian.Uint32(segment[24:]), test.ShouldEqual, 3)
+				// the first frame follows the header, and the last one is 200ms after it.
+				test.That(t, binary.LittleEndian.Uint32(segment[32:]), test.ShouldEqual, len(vp8Key))
+				test.That(t, segment[44:44+len(vp8Key)], test.ShouldResemble, vp8Key)
+				last := len(segment) - len(vp8Delta) - 12
+				test.That(t, binary.LittleEndian.Uint64(segment[last+4:]), test.ShouldEqual, 200)
+			},
+		},
+	} {
+		t.Run(tc.mimeType, func(t *testing.T) {
+			source := newFakeVideoSource(16)
+			unregister := camera.RegisterEncodedVideoSource("camera", source)
+			defer unregister()
+
+			mockClock := clk.NewMock()
+			buf := tu.MockBuffer{}
+			col := newVideoSegmentsCollector(t, &buf, mockClock)
+			col.Collect()
+
+			// the first capture subscribes to the video, and has nothing to store yet.
+			mockClock.Add(interval)
+			<-source.subscribed
+
+			// frames before the first key frame cannot be decoded, so they are dropped.
+			source.send(
+				frame(tc.mimeType, -1, tc.delta...),
+				frame(tc.mimeType, 0, tc.key...),
+				frame(tc.mimeType, 1, tc.delta...),
+				frame(tc.mimeType, 2, tc.delta...),
+				frame(tc.mimeType, 3, tc.key...),
+				frame(tc.mimeType, 4, tc.delta...),
+			)
+			mockClock.Add(interval)
+			tu.Retry(func() bool {
+				return buf.Length() != 0
+			}, numRetries)
+			test.That(t, buf.Length(), test.ShouldEqual, 1)
+			tc.check(t, buf.Writes[0].GetBinary())
+
+			col.Close()
+			<-source.unsubscribed
+		})
+	}
+}
+
+func TestVideoSegmentsDroppedFrames(t *testing.T) {
+	key := []byte{0, 0, 0, 1, 0x67, 1, 0, 0, 0, 1, 0x65, 2}
+	delta := []byte{0, 0, 0, 1, 0x41, 3}
+	frame := func(data []byte) gostream.EncodedVideoFrame {
+		return gostream.EncodedVideoFrame{Data: data, MIMEType: "video/H264", Time: time.Now()}
+	}
+	source := newFakeVideoSource(4)
+	unregister := camera.RegisterEncodedVideoSource("camera", source)
+	defer unregister()
+
+	mockClock := clk.NewMock()
+	buf := tu.MockBuffer{}
+	col := newVideoSegmentsCollector(t, &buf, mockClock)
+	col.Collect()
+	mockClock.Add(time.Second)
+	<-source.subscribed
+
+	// the last frame does not fit in the queue; the frames before it end the segment, even
+	// though they end with a key frame.
+	source.send(frame(key), frame(delta), frame(delta), frame(key), frame(delta))
+	test.That(t, source.frames.Stats(), test.ShouldResemble, gostream.QueueStats{Queued: 4, Dropped: 1})
+	mockClock.Add(time.Second)
+	tu.Retry(func() bool {
+		return buf.Length() != 0
+	}, numRetries)
+	test.That(t, buf.Length(), test.ShouldEqual, 1)
+	test.That(t, buf.Writes[0].GetBinary(), test.ShouldResemble, bytes.Join([][]byte{key, delta, delta, key}, nil))
+
+	// frames after the gap are captured from the next key frame on.
+	source.send(frame(delta), frame(key), frame(delta), frame(key))
+	mockClock.Add(time.Second)
+	tu.Retry(func() bool {
+		return buf.Length() == 2
+	}, numRetries)
+	test.That(t, buf.Length(), test.ShouldEqual, 2)
+	test.That(t, buf.Writes[1].GetBinary(), test.ShouldResemble, bytes.Join([][]byte{key, delta}, nil))
+
+	col.Close()
+	<-source.unsubscribed
+}
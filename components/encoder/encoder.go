@@ -13,6 +13,7 @@ import (
 
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Encoder]{
+		SelfTest:                    SelfTest,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterEncoderServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.EncoderService_ServiceDesc,
@@ -138,3 +139,9 @@ func ToProtoPositionType(positionType PositionType) pb.PositionType {
 	}
 	return pb.PositionType_POSITION_TYPE_UNSPECIFIED
 }
+
+// SelfTest checks that the encoder is functional by querying its position.
+func SelfTest(ctx context.Context, e Encoder) error {
+	_, _, err := e.Position(ctx, PositionTypeUnspecified, nil)
+	return err
+}
@@ -9,6 +9,7 @@ import (
 
 	"go.viam.com/utils"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/components/encoder"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -121,7 +122,7 @@ func (e *fakeEncoder) start(cancelCtx context.Context) {
 			e.mu.RLock()
 			updateRate := e.updateRate
 			e.mu.RUnlock()
-			if !utils.SelectContextOrWait(cancelCtx, time.Duration(updateRate)*time.Millisecond) {
+			if !clock.SleepContext(cancelCtx, time.Duration(updateRate)*time.Millisecond) {
 				return
 			}
 
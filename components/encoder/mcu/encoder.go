@@ -0,0 +1,141 @@
+/*
+Package mcu implements a quadrature encoder counted by the firmware of a microcontroller board,
+so that no ticks are lost however fast the encoder turns.
+
+Sample configuration:
+
+	{
+		"board": "esp32",
+		"encoder": "left-wheel"
+	}
+
+where the board is of the mcu model and has an encoder named "left-wheel".
+*/
+package mcu
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/board"
+	mcuboard "go.viam.com/rdk/components/board/mcu"
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+var model = resource.DefaultModelFamily.WithModel("mcu")
+
+func init() {
+	resource.RegisterComponent(
+		encoder.API,
+		model,
+		resource.Registration[encoder.Encoder, *Config]{
+			Constructor: NewEncoder,
+		})
+}
+
+// Config describes the configuration of an encoder counted by a microcontroller board.
+type Config struct {
+	BoardName   string `json:"board"`
+	EncoderName string `json:"encoder"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.BoardName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "board")
+	}
+	if conf.EncoderName == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "encoder")
+	}
+	return []string{conf.BoardName}, nil
+}
+
+// Encoder reads the count of an encoder of a microcontroller board.
+type Encoder struct {
+	resource.Named
+
+	mu    sync.Mutex
+	board mcuboard.EncoderBoard
+	name  string
+}
+
+// NewEncoder creates a new Encoder.
+func NewEncoder(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	logger logging.Logger,
+) (encoder.Encoder, error) {
+	e := &Encoder{Named: conf.ResourceName().AsNamed()}
+	if err := e.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Reconfigure atomically reconfigures this encoder in place based on the new config.
+func (e *Encoder) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	b, err := board.FromDependencies(deps, newConf.BoardName)
+	if err != nil {
+		return err
+	}
+	encBoard, ok := b.(mcuboard.EncoderBoard)
+	if !ok {
+		return errors.Errorf("board %q does not count encoders; it must be of the %s model", newConf.BoardName, mcuboard.Model)
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.board = encBoard
+	e.name = newConf.EncoderName
+	return nil
+}
+
+// Position returns the count of the encoder in ticks.
+func (e *Encoder) Position(
+	ctx context.Context,
+	positionType encoder.PositionType,
+	extra map[string]interface{},
+) (float64, encoder.PositionType, error) {
+	if positionType == encoder.PositionTypeDegrees {
+		return math.NaN(), encoder.PositionTypeUnspecified, encoder.NewPositionTypeUnsupportedError(positionType)
+	}
+	e.mu.Lock()
+	b, name := e.board, e.name
+	e.mu.Unlock()
+	count, err := b.EncoderPosition(ctx, name)
+	if err != nil {
+		return math.NaN(), encoder.PositionTypeUnspecified, err
+	}
+	return float64(count), encoder.PositionTypeTicks, nil
+}
+
+// ResetPosition sets the count of the encoder to zero.
+func (e *Encoder) ResetPosition(ctx context.Context, extra map[string]interface{}) error {
+	e.mu.Lock()
+	b, name := e.board, e.name
+	e.mu.Unlock()
+	return b.ResetEncoderPosition(ctx, name)
+}
+
+// Properties returns a list of all the position types that are supported by a given encoder.
+func (e *Encoder) Properties(ctx context.Context, extra map[string]interface{}) (encoder.Properties, error) {
+	return encoder.Properties{
+		TicksCountSupported:   true,
+		AngleDegreesSupported: false,
+	}, nil
+}
+
+// Close closes the encoder; the board keeps counting it.
+func (e *Encoder) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,62 @@
+package mcu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// encoderBoard counts encoders as a microcontroller board does.
+type encoderBoard struct {
+	*inject.Board
+	counts map[string]int64
+}
+
+func (b *encoderBoard) EncoderPosition(ctx context.Context, name string) (int64, error) {
+	count, ok := b.counts[name]
+	if !ok {
+		return 0, errors.Errorf("cant find encoder (%s)", name)
+	}
+	return count, nil
+}
+
+func (b *encoderBoard) ResetEncoderPosition(ctx context.Context, name string) error {
+	b.counts[name] = 0
+	return nil
+}
+
+func TestEncoder(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	b := &encoderBoard{Board: inject.NewBoard("esp32"), counts: map[string]int64{"left": 12}}
+	deps := resource.Dependencies{board.Named("esp32"): b}
+	conf := resource.Config{Name: "enc1", ConvertedAttributes: &Config{BoardName: "esp32", EncoderName: "left"}}
+
+	enc, err := NewEncoder(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+	position, positionType, err := enc.Position(ctx, encoder.PositionTypeUnspecified, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, position, test.ShouldEqual, 12)
+	test.That(t, positionType, test.ShouldEqual, encoder.PositionTypeTicks)
+	_, _, err = enc.Position(ctx, encoder.PositionTypeDegrees, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, enc.ResetPosition(ctx, nil), test.ShouldBeNil)
+	position, _, err = enc.Position(ctx, encoder.PositionTypeTicks, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, position, test.ShouldEqual, 0)
+
+	// a board not counting encoders cannot be used.
+	deps[board.Named("esp32")] = inject.NewBoard("esp32")
+	_, err = NewEncoder(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "does not count encoders")
+}
@@ -5,5 +5,6 @@ import (
 	// Load all encoders.
 	_ "go.viam.com/rdk/components/encoder/ams"
 	_ "go.viam.com/rdk/components/encoder/incremental"
+	_ "go.viam.com/rdk/components/encoder/mcu"
 	_ "go.viam.com/rdk/components/encoder/single"
 )
@@ -398,6 +398,7 @@ func (g *singleAxis) homeEncoder(ctx context.Context) error {
 	positionB := positionA + revPerLength
 
 	g.positionLimits = []float64{positionA, positionB}
+	g.positionRange = positionB - positionA
 	return nil
 }
 
@@ -593,6 +594,55 @@ func (g *singleAxis) IsMoving(ctx context.Context) (bool, error) {
 	return g.opMgr.OpRunning(), nil
 }
 
+// HomingState returns the position of the gantry in millimeters and the motor revolutions
+// spanning its length, from which its position limits are restored after the robot restarts,
+// or nil if the gantry is not homed.
+func (g *singleAxis) HomingState(ctx context.Context) (map[string]interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.positionRange == 0 {
+		return nil, nil
+	}
+	pos, err := g.motor.Position(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"position_mm":    g.lengthMm * ((pos - g.positionLimits[0]) / g.positionRange),
+		"position_range": g.positionRange,
+	}, nil
+}
+
+// RestoreHomingState restores the position limits of the gantry from a state returned by
+// HomingState, taking the gantry to be where it was when the state was kept even if the
+// encoder of its motor was reset since. A nil state forgets the position limits so that the
+// gantry must be homed again.
+func (g *singleAxis) RestoreHomingState(ctx context.Context, state map[string]interface{}) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if state == nil {
+		g.positionRange = 0
+		g.positionLimits = []float64{0, 0}
+		return nil
+	}
+	positionMm, ok := state["position_mm"].(float64)
+	if !ok || positionMm < 0 || positionMm > g.lengthMm {
+		return errors.Errorf("invalid homed position %v of gantry '%v'", state["position_mm"], g.Named.Name().ShortName())
+	}
+	positionRange, ok := state["position_range"].(float64)
+	if !ok || positionRange == 0 || math.IsNaN(positionRange) || math.IsInf(positionRange, 0) {
+		return errors.Errorf("invalid homed position range %v of gantry '%v'", state["position_range"], g.Named.Name().ShortName())
+	}
+	pos, err := g.motor.Position(ctx, nil)
+	if err != nil {
+		return err
+	}
+	positionA := pos - positionMm/g.lengthMm*positionRange
+	g.positionLimits = []float64{positionA, positionA + positionRange}
+	g.positionRange = positionRange
+	return nil
+}
+
 // ModelFrame returns the frame model of the Gantry.
 func (g *singleAxis) ModelFrame() referenceframe.Model {
 	g.mu.Lock()
@@ -10,6 +10,7 @@ import (
 	"go.viam.com/test"
 
 	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/gantry"
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
@@ -876,3 +877,47 @@ func TestGoToInputs(t *testing.T) {
 	err = fakegantry.GoToInputs(ctx)
 	test.That(t, err, test.ShouldBeNil)
 }
+
+func TestHomingState(t *testing.T) {
+	ctx := context.Background()
+	motorPosition := 3.0
+	fakegantry := &singleAxis{
+		Named: gantry.Named(testGName).AsNamed(),
+		motor: &inject.Motor{
+			PositionFunc: func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+				return motorPosition, nil
+			},
+		},
+		lengthMm:       100,
+		positionLimits: []float64{0, 0},
+		logger:         logging.NewTestLogger(t),
+		opMgr:          operation.NewSingleOperationManager(),
+	}
+
+	state, err := fakegantry.HomingState(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, state, test.ShouldBeNil)
+
+	fakegantry.positionLimits = []float64{1, 5}
+	fakegantry.positionRange = 4
+	state, err = fakegantry.HomingState(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, state, test.ShouldResemble, map[string]interface{}{"position_mm": 50.0, "position_range": 4.0})
+
+	// the encoder of the motor restarts from zero, where the gantry still is halfway.
+	motorPosition = 0
+	test.That(t, fakegantry.RestoreHomingState(ctx, nil), test.ShouldBeNil)
+	test.That(t, fakegantry.positionRange, test.ShouldEqual, 0)
+	test.That(t, fakegantry.RestoreHomingState(ctx, state), test.ShouldBeNil)
+	test.That(t, fakegantry.positionLimits, test.ShouldResemble, []float64{-2, 2})
+	pos, err := fakegantry.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldResemble, []float64{50})
+
+	err = fakegantry.RestoreHomingState(ctx, map[string]interface{}{"position_mm": 150.0, "position_range": 4.0})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid homed position")
+	err = fakegantry.RestoreHomingState(ctx, map[string]interface{}{"position_mm": 50.0})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid homed position range")
+}
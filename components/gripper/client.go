@@ -26,6 +26,12 @@ type client struct {
 	logger logging.Logger
 }
 
+var (
+	_ = ForceGripper(&client{})
+	_ = PositionGripper(&client{})
+	_ = GripSensor(&client{})
+)
+
 // NewClientFromConn constructs a new Client from connection passed in.
 func NewClientFromConn(
 	ctx context.Context,
@@ -113,3 +119,23 @@ func (c *client) Geometries(ctx context.Context, extra map[string]interface{}) (
 	}
 	return spatialmath.NewGeometriesFromProto(resp.GetGeometries())
 }
+
+func (c *client) GrabWithForce(ctx context.Context, forceN float64, extra map[string]interface{}) (bool, error) {
+	result, err := control(ctx, c, controlCommand{Action: actionGrabWithForce, ForceN: forceN, Extra: extra})
+	return result.Success, err
+}
+
+func (c *client) MoveToWidth(ctx context.Context, widthMm float64, extra map[string]interface{}) error {
+	_, err := control(ctx, c, controlCommand{Action: actionMoveToWidth, WidthMm: widthMm, Extra: extra})
+	return err
+}
+
+func (c *client) Width(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	result, err := control(ctx, c, controlCommand{Action: actionGetWidth, Extra: extra})
+	return result.WidthMm, err
+}
+
+func (c *client) IsHolding(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	result, err := control(ctx, c, controlCommand{Action: actionIsHolding, Extra: extra})
+	return result.Holding, err
+}
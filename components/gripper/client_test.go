@@ -8,8 +8,11 @@ import (
 	"github.com/golang/geo/r3"
 	"go.viam.com/test"
 	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/components/gripper"
+	"go.viam.com/rdk/components/gripper/fake"
 	viamgrpc "go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -149,3 +152,86 @@ func TestClient(t *testing.T) {
 		test.That(t, conn.Close(), test.ShouldBeNil)
 	})
 }
+
+func TestClientControl(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	listener1, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	rpcServer, err := rpc.NewServer(logger.AsZap(), rpc.WithUnauthenticated())
+	test.That(t, err, test.ShouldBeNil)
+
+	fakeGripper, err := fake.NewGripper(context.Background(), nil, resource.Config{
+		Name:                testGripperName,
+		ConvertedAttributes: &fake.Config{StrokeMm: 100, ObjectWidthMm: 40},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	gripperSvc, err := resource.NewAPIResourceCollection(gripper.API, map[resource.Name]gripper.Gripper{
+		gripper.Named(testGripperName): fakeGripper,
+		gripper.Named(failGripperName): &inject.Gripper{},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	resourceAPI, ok, err := resource.LookupAPIRegistration[gripper.Gripper](gripper.API)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, resourceAPI.RegisterRPCService(context.Background(), rpcServer, gripperSvc), test.ShouldBeNil)
+
+	go rpcServer.Serve(listener1)
+	defer rpcServer.Stop()
+
+	conn, err := viamgrpc.Dial(context.Background(), listener1.Addr().String(), logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	client1, err := gripper.NewClientFromConn(context.Background(), conn, "", gripper.Named(testGripperName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	positionClient := client1.(gripper.PositionGripper)
+	forceClient := client1.(gripper.ForceGripper)
+	sensorClient := client1.(gripper.GripSensor)
+
+	width, err := positionClient.Width(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, width, test.ShouldEqual, 100)
+	test.That(t, positionClient.MoveToWidth(context.Background(), 60, nil), test.ShouldBeNil)
+	width, err = positionClient.Width(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, width, test.ShouldEqual, 60)
+	holding, err := sensorClient.IsHolding(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, holding, test.ShouldBeFalse)
+
+	err = positionClient.MoveToWidth(context.Background(), 120, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "outside of the gripper's stroke")
+
+	// the fingers close on the object.
+	grabbed, err := forceClient.GrabWithForce(context.Background(), 20, map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grabbed, test.ShouldBeTrue)
+	test.That(t, fakeGripper.(*fake.Gripper).LastForce(), test.ShouldEqual, 20)
+	width, err = positionClient.Width(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, width, test.ShouldEqual, 40)
+	holding, err = sensorClient.IsHolding(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, holding, test.ShouldBeTrue)
+
+	_, err = forceClient.GrabWithForce(context.Background(), 0, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be positive")
+
+	test.That(t, client1.Open(context.Background(), nil), test.ShouldBeNil)
+	holding, err = sensorClient.IsHolding(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, holding, test.ShouldBeFalse)
+
+	// grippers without the capabilities report them as unimplemented.
+	client2, err := gripper.NewClientFromConn(context.Background(), conn, "", gripper.Named(failGripperName), logger)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = client2.(gripper.ForceGripper).GrabWithForce(context.Background(), 20, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+	_, err = client2.(gripper.PositionGripper).Width(context.Background(), nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+	_, err = client2.(gripper.GripSensor).IsHolding(context.Background(), nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+}
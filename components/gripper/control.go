@@ -0,0 +1,190 @@
+package gripper
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A ForceGripper is a gripper that can limit the force it grabs with, so that fragile
+// objects are not crushed.
+type ForceGripper interface {
+	Gripper
+
+	// GrabWithForce makes the gripper grab, squeezing with at most the given force in
+	// newtons.
+	// returns true if we grabbed something.
+	// This will block until done or a new operation cancels this one
+	GrabWithForce(ctx context.Context, forceN float64, extra map[string]interface{}) (bool, error)
+}
+
+// A PositionGripper is a gripper whose fingers can be moved to a given width, and which
+// reports their width.
+type PositionGripper interface {
+	Gripper
+
+	// MoveToWidth moves the fingers of the gripper to the given distance apart in
+	// millimeters.
+	// This will block until done or a new operation cancels this one
+	MoveToWidth(ctx context.Context, widthMm float64, extra map[string]interface{}) error
+
+	// Width returns the distance between the fingers of the gripper in millimeters.
+	Width(ctx context.Context, extra map[string]interface{}) (float64, error)
+}
+
+// A GripSensor is a gripper that detects whether an object is held between its fingers,
+// so that picks can be verified.
+type GripSensor interface {
+	Gripper
+
+	// IsHolding returns whether the gripper detects an object between its fingers.
+	IsHolding(ctx context.Context, extra map[string]interface{}) (bool, error)
+}
+
+// ControlCommandKey is the DoCommand key used to reach the ForceGripper, PositionGripper
+// and GripSensor methods of a gripper over the network. Its value holds an "action" of
+// "grab_with_force", "move_to_width", "get_width" or "is_holding", along with "force_n",
+// "width_mm" and "extra" as needed. Grippers lacking the capability return an
+// Unimplemented error.
+const ControlCommandKey = "gripper_control"
+
+// The actions of ControlCommandKey.
+const (
+	actionGrabWithForce = "grab_with_force"
+	actionMoveToWidth   = "move_to_width"
+	actionGetWidth      = "get_width"
+	actionIsHolding     = "is_holding"
+)
+
+// isReadCommand reports whether the DoCommand command only reads the width of a gripper or
+// whether it is holding something.
+func isReadCommand(cmd map[string]interface{}) bool {
+	args, ok := cmd[ControlCommandKey].(map[string]interface{})
+	if !ok || len(cmd) != 1 {
+		return false
+	}
+	action := args["action"]
+	return action == actionGetWidth || action == actionIsHolding
+}
+
+type controlCommand struct {
+	Action  string                 `json:"action"`
+	ForceN  float64                `json:"force_n,omitempty"`
+	WidthMm float64                `json:"width_mm,omitempty"`
+	Extra   map[string]interface{} `json:"extra,omitempty"`
+}
+
+type controlResult struct {
+	Success bool    `json:"success,omitempty"`
+	WidthMm float64 `json:"width_mm,omitempty"`
+	Holding bool    `json:"holding,omitempty"`
+}
+
+func unsupported(g Gripper, capability string) error {
+	return status.Errorf(codes.Unimplemented, "gripper %q does not support %s", g.Name().ShortName(), capability)
+}
+
+// doControl carries out the ControlCommandKey command cmd on the gripper.
+func doControl(ctx context.Context, g Gripper, cmd interface{}) (map[string]interface{}, error) {
+	var args controlCommand
+	raw, err := json.Marshal(cmd)
+	if err == nil {
+		err = json.Unmarshal(raw, &args)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s command: %v", ControlCommandKey, err)
+	}
+
+	var result controlResult
+	switch args.Action {
+	case actionGrabWithForce:
+		fg, ok := g.(ForceGripper)
+		if !ok {
+			return nil, unsupported(g, "force-limited grabbing")
+		}
+		if result.Success, err = fg.GrabWithForce(ctx, args.ForceN, args.Extra); err != nil {
+			return nil, err
+		}
+	case actionMoveToWidth:
+		pg, ok := g.(PositionGripper)
+		if !ok {
+			return nil, unsupported(g, "position control")
+		}
+		if err := pg.MoveToWidth(ctx, args.WidthMm, args.Extra); err != nil {
+			return nil, err
+		}
+	case actionGetWidth:
+		pg, ok := g.(PositionGripper)
+		if !ok {
+			return nil, unsupported(g, "position control")
+		}
+		if result.WidthMm, err = pg.Width(ctx, args.Extra); err != nil {
+			return nil, err
+		}
+	case actionIsHolding:
+		gs, ok := g.(GripSensor)
+		if !ok {
+			return nil, unsupported(g, "object detection")
+		}
+		if result.Holding, err = gs.IsHolding(ctx, args.Extra); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown %s action %q", ControlCommandKey, args.Action)
+	}
+
+	raw, err = json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// control sends the ControlCommandKey command cmd to the gripper with DoCommand.
+func control(ctx context.Context, g Gripper, cmd controlCommand) (controlResult, error) {
+	var result controlResult
+	var args map[string]interface{}
+	raw, err := json.Marshal(cmd)
+	if err == nil {
+		err = json.Unmarshal(raw, &args)
+	}
+	if err != nil {
+		return result, err
+	}
+	resp, err := g.DoCommand(ctx, map[string]interface{}{ControlCommandKey: args})
+	if err != nil {
+		return result, err
+	}
+	raw, err = json.Marshal(resp)
+	if err == nil {
+		err = json.Unmarshal(raw, &result)
+	}
+	return result, errors.Wrapf(err, "invalid %s response", ControlCommandKey)
+}
+
+// ValidateForce checks that a force to grab with is positive.
+func ValidateForce(forceN float64) error {
+	if forceN <= 0 {
+		return resource.NewInvalidCommandError(errors.Errorf("grab force must be positive, got %v N", forceN))
+	}
+	return nil
+}
+
+// ValidateWidth checks that a width to move the fingers of a gripper to is within its
+// stroke.
+func ValidateWidth(widthMm, strokeMm float64) error {
+	if widthMm < 0 || widthMm > strokeMm {
+		return resource.NewInvalidCommandError(
+			errors.Errorf("width %v mm is outside of the gripper's stroke of 0 to %v mm", widthMm, strokeMm))
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package fake
 
 import (
 	"context"
+	"errors"
 	"sync"
 
 	"go.viam.com/rdk/components/gripper"
@@ -14,24 +15,54 @@ import (
 
 var model = resource.DefaultModelFamily.WithModel("fake")
 
+// defaultStrokeMm is the stroke of a fake gripper whose config does not set one.
+const defaultStrokeMm = 85
+
 // Config is the config for a trossen gripper.
 type Config struct {
-	resource.TriviallyValidateConfig
+	// StrokeMm is the distance between the fingers when the gripper is open.
+	StrokeMm float64 `json:"stroke_mm,omitempty"`
+	// ObjectWidthMm is the width of a simulated object between the fingers, if any. Closing
+	// the gripper stops at it and holds it.
+	ObjectWidthMm float64 `json:"object_width_mm,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.StrokeMm < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("stroke_mm must not be negative"))
+	}
+	if cfg.ObjectWidthMm < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("object_width_mm must not be negative"))
+	}
+	return nil, nil
 }
 
 func init() {
 	resource.RegisterComponent(gripper.API, model, resource.Registration[gripper.Gripper, *Config]{Constructor: NewGripper})
 }
 
-// Gripper is a fake gripper that can simply read and set properties.
+// Gripper is a fake gripper that can simply read and set properties. It limits its force,
+// moves its fingers to a given width and detects the simulated object of its config.
 type Gripper struct {
 	resource.Named
 	resource.TriviallyCloseable
 	geometries []spatialmath.Geometry
+	strokeMm   float64
+	objectMm   float64
+	widthMm    float64
+	holding    bool
+	lastForceN float64
 	mu         sync.Mutex
 	logger     logging.Logger
 }
 
+var (
+	_ = gripper.ForceGripper(&Gripper{})
+	_ = gripper.PositionGripper(&Gripper{})
+	_ = gripper.GripSensor(&Gripper{})
+)
+
 // NewGripper instantiates a new gripper of the fake model type.
 func NewGripper(
 	ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
@@ -52,6 +83,17 @@ func (g *Gripper) Reconfigure(_ context.Context, _ resource.Dependencies, conf r
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
+	g.strokeMm = defaultStrokeMm
+	g.objectMm = 0
+	if newConf, ok := conf.ConvertedAttributes.(*Config); ok {
+		if newConf.StrokeMm != 0 {
+			g.strokeMm = newConf.StrokeMm
+		}
+		g.objectMm = newConf.ObjectWidthMm
+	}
+	g.widthMm = g.strokeMm
+	g.holding = false
+
 	if conf.Frame != nil && conf.Frame.Geometry != nil {
 		geometry, err := conf.Frame.Geometry.ParseConfig()
 		if err != nil {
@@ -67,14 +109,75 @@ func (g *Gripper) ModelFrame() referenceframe.Model {
 	return nil
 }
 
-// Open does nothing.
+// Open opens the fingers fully, releasing any object.
 func (g *Gripper) Open(ctx context.Context, extra map[string]interface{}) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.widthMm = g.strokeMm
+	g.holding = false
 	return nil
 }
 
-// Grab does nothing.
+// Grab closes the fingers until they hold the simulated object, if any.
 func (g *Gripper) Grab(ctx context.Context, extra map[string]interface{}) (bool, error) {
-	return false, nil
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.moveTo(0)
+	return g.holding, nil
+}
+
+// GrabWithForce grabs like Grab, recording the force.
+func (g *Gripper) GrabWithForce(ctx context.Context, forceN float64, extra map[string]interface{}) (bool, error) {
+	if err := gripper.ValidateForce(forceN); err != nil {
+		return false, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastForceN = forceN
+	g.moveTo(0)
+	return g.holding, nil
+}
+
+// MoveToWidth moves the fingers to the given width, stopping at the simulated object, if
+// any.
+func (g *Gripper) MoveToWidth(ctx context.Context, widthMm float64, extra map[string]interface{}) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if err := gripper.ValidateWidth(widthMm, g.strokeMm); err != nil {
+		return err
+	}
+	g.moveTo(widthMm)
+	return nil
+}
+
+// moveTo moves the fingers to the given width. It must be called with mu held.
+func (g *Gripper) moveTo(widthMm float64) {
+	g.holding = g.objectMm > 0 && g.objectMm <= g.strokeMm && widthMm < g.objectMm
+	if g.holding {
+		widthMm = g.objectMm
+	}
+	g.widthMm = widthMm
+}
+
+// Width returns the distance between the fingers.
+func (g *Gripper) Width(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.widthMm, nil
+}
+
+// IsHolding returns whether the fingers hold the simulated object.
+func (g *Gripper) IsHolding(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.holding, nil
+}
+
+// LastForce returns the force of the last force-limited grab, in newtons.
+func (g *Gripper) LastForce() float64 {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.lastForceN
 }
 
 // Stop doesn't do anything for a fake gripper.
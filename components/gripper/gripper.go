@@ -15,6 +15,7 @@ import (
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Gripper]{
 		Status:                      resource.StatusFunc(CreateStatus),
+		ReadCommand:                 isReadCommand,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterGripperServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.GripperService_ServiceDesc,
@@ -6,11 +6,14 @@ package robotiq
 import (
 	"context"
 	"fmt"
+	"math"
 	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.viam.com/utils"
 
 	"go.viam.com/rdk/components/gripper"
@@ -23,9 +26,24 @@ import (
 
 var model = resource.DefaultModelFamily.WithModel("robotiq")
 
+// The stroke and maximum grip force of the 2F-85, used unless configured otherwise.
+const (
+	defaultStrokeMm  = 85
+	defaultMaxForceN = 235
+)
+
+// defaultForce is the force register value (0-255) used by Grab.
+const defaultForce = "200"
+
 // Config is used for converting config attributes.
 type Config struct {
 	Host string `json:"host"`
+	// StrokeMm is the distance between the fingers when the gripper is open, 85 for the
+	// 2F-85 and 140 for the 2F-140.
+	StrokeMm float64 `json:"stroke_mm,omitempty"`
+	// MaxForceN is the grip force at the maximum force setting, 235 for the 2F-85 and 125
+	// for the 2F-140.
+	MaxForceN float64 `json:"max_force_n,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -33,6 +51,12 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	if cfg.Host == "" {
 		return nil, resource.NewConfigValidationFieldRequiredError(path, "host")
 	}
+	if cfg.StrokeMm < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("stroke_mm must not be negative"))
+	}
+	if cfg.MaxForceN < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("max_force_n must not be negative"))
+	}
 	return nil, nil
 }
 
@@ -45,7 +69,7 @@ func init() {
 			if err != nil {
 				return nil, err
 			}
-			return newGripper(ctx, conf, newConf.Host, logger)
+			return newGripper(ctx, conf, newConf, logger)
 		},
 	})
 }
@@ -62,11 +86,19 @@ type robotiqGripper struct {
 	logger     logging.Logger
 	opMgr      *operation.SingleOperationManager
 	geometries []spatialmath.Geometry
+	strokeMm   float64
+	maxForceN  float64
 }
 
+var (
+	_ = gripper.ForceGripper(&robotiqGripper{})
+	_ = gripper.PositionGripper(&robotiqGripper{})
+	_ = gripper.GripSensor(&robotiqGripper{})
+)
+
 // newGripper instantiates a new Gripper of robotiqGripper type.
-func newGripper(ctx context.Context, conf resource.Config, host string, logger logging.Logger) (gripper.Gripper, error) {
-	conn, err := net.Dial("tcp", host+":63352")
+func newGripper(ctx context.Context, conf resource.Config, newConf *Config, logger logging.Logger) (gripper.Gripper, error) {
+	conn, err := net.Dial("tcp", newConf.Host+":63352")
 	if err != nil {
 		return nil, err
 	}
@@ -79,13 +111,21 @@ func newGripper(ctx context.Context, conf resource.Config, host string, logger l
 		logger,
 		operation.NewSingleOperationManager(),
 		[]spatialmath.Geometry{},
+		newConf.StrokeMm,
+		newConf.MaxForceN,
+	}
+	if g.strokeMm == 0 {
+		g.strokeMm = defaultStrokeMm
+	}
+	if g.maxForceN == 0 {
+		g.maxForceN = defaultMaxForceN
 	}
 
 	init := [][]string{
-		{"ACT", "1"},   // robot activate
-		{"GTO", "1"},   // gripper activate
-		{"FOR", "200"}, // force (0-255)
-		{"SPE", "255"}, // speed (0-255)
+		{"ACT", "1"},          // robot activate
+		{"GTO", "1"},          // gripper activate
+		{"FOR", defaultForce}, // force (0-255)
+		{"SPE", "255"},        // speed (0-255)
 	}
 	err = g.MultiSet(ctx, init)
 	if err != nil {
@@ -236,6 +276,27 @@ func (g *robotiqGripper) Grab(ctx context.Context, extra map[string]interface{})
 	ctx, done := g.opMgr.New(ctx)
 	defer done()
 
+	return g.grab(ctx)
+}
+
+// GrabWithForce grabs squeezing with at most the given force, and returns true iff grabbed
+// something.
+func (g *robotiqGripper) GrabWithForce(ctx context.Context, forceN float64, extra map[string]interface{}) (bool, error) {
+	if err := gripper.ValidateForce(forceN); err != nil {
+		return false, err
+	}
+	ctx, done := g.opMgr.New(ctx)
+	defer done()
+
+	force := int(math.Round(math.Min(forceN/g.maxForceN, 1) * 255))
+	if err := g.Set("FOR", strconv.Itoa(force)); err != nil {
+		return false, err
+	}
+	grabbed, err := g.grab(ctx)
+	return grabbed, multierr.Combine(err, g.Set("FOR", defaultForce))
+}
+
+func (g *robotiqGripper) grab(ctx context.Context) (bool, error) {
 	res, err := g.SetPos(ctx, g.closeLimit)
 	if err != nil {
 		return false, err
@@ -253,6 +314,69 @@ func (g *robotiqGripper) Grab(ctx context.Context, extra map[string]interface{})
 	return val == "OBJ 2", nil
 }
 
+// MoveToWidth moves the fingers to the given distance apart.
+func (g *robotiqGripper) MoveToWidth(ctx context.Context, widthMm float64, extra map[string]interface{}) error {
+	if err := gripper.ValidateWidth(widthMm, g.strokeMm); err != nil {
+		return err
+	}
+	ctx, done := g.opMgr.New(ctx)
+	defer done()
+
+	openPos, closePos, err := g.limits()
+	if err != nil {
+		return err
+	}
+	pos := closePos - int(math.Round(widthMm/g.strokeMm*float64(closePos-openPos)))
+	_, err = g.SetPos(ctx, strconv.Itoa(pos))
+	return err
+}
+
+// Width returns the distance between the fingers, interpolated between the calibrated
+// limits.
+func (g *robotiqGripper) Width(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	openPos, closePos, err := g.limits()
+	if err != nil {
+		return 0, err
+	}
+	x, err := g.Get("POS")
+	if err != nil {
+		return 0, err
+	}
+	pos, err := strconv.Atoi(strings.TrimPrefix(x, "POS "))
+	if err != nil {
+		return 0, errors.Errorf("unexpected position %q", x)
+	}
+	width := float64(closePos-pos) / float64(closePos-openPos) * g.strokeMm
+	return math.Max(0, math.Min(width, g.strokeMm)), nil
+}
+
+// IsHolding returns whether the gripper detected an object while its fingers moved.
+func (g *robotiqGripper) IsHolding(ctx context.Context, extra map[string]interface{}) (bool, error) {
+	val, err := g.Get("OBJ")
+	if err != nil {
+		return false, err
+	}
+	// 1 and 2 are objects detected while opening and closing; 0 is moving and 3 is at the
+	// requested position without an object.
+	return val == "OBJ 1" || val == "OBJ 2", nil
+}
+
+// limits returns the calibrated positions of the open and closed gripper.
+func (g *robotiqGripper) limits() (int, int, error) {
+	openPos, err := strconv.Atoi(g.openLimit)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid open limit %q", g.openLimit)
+	}
+	closePos, err := strconv.Atoi(g.closeLimit)
+	if err != nil {
+		return 0, 0, errors.Errorf("invalid close limit %q", g.closeLimit)
+	}
+	if openPos == closePos {
+		return 0, 0, errors.New("gripper is not calibrated")
+	}
+	return openPos, closePos, nil
+}
+
 // Calibrate TODO.
 func (g *robotiqGripper) Calibrate(ctx context.Context) error {
 	err := g.Open(ctx, map[string]interface{}{})
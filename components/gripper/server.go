@@ -6,6 +6,7 @@ import (
 
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/component/gripper/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/protoutils"
@@ -78,6 +79,17 @@ func (s *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if cmd, ok := req.GetCommand().AsMap()[ControlCommandKey]; ok {
+		res, err := doControl(ctx, gripper, cmd)
+		if err != nil {
+			return nil, err
+		}
+		pbRes, err := structpb.NewStruct(res)
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: pbRes}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, gripper, req)
 }
 
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"go.viam.com/utils"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/components/input"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -135,7 +136,7 @@ func (c *InputController) Events(ctx context.Context, extra map[string]interface
 	defer c.mu.Unlock()
 	eventsOut := make(map[input.Control]input.Event)
 
-	eventsOut[input.AbsoluteX] = input.Event{Time: time.Now(), Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: c.eventVal()}
+	eventsOut[input.AbsoluteX] = input.Event{Time: clock.Now(), Event: input.PositionChangeAbs, Control: input.AbsoluteX, Value: c.eventVal()}
 	return eventsOut, nil
 }
 
@@ -184,7 +185,7 @@ func (c *InputController) startCallbackLoop() {
 			evValue := c.eventVal()
 			for _, callback := range c.callbacks {
 				for _, t := range callback.triggers {
-					event := input.Event{Time: time.Now(), Event: t, Control: callback.control, Value: evValue}
+					event := input.Event{Time: clock.Now(), Event: t, Control: callback.control, Value: evValue}
 					callback.ctrlFunc(c.closeCtx, event)
 				}
 			}
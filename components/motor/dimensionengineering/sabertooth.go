@@ -131,6 +131,7 @@ func init() {
 			}
 			return NewMotor(ctx, newConf, conf.ResourceName(), logger)
 		},
+		Unimplemented: []string{"GoTo", "SetRPM", "ResetZeroPosition"},
 	})
 }
 
@@ -21,6 +21,7 @@ import (
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils/contextutils"
 )
 
 // Timeout for Home().
@@ -39,6 +40,8 @@ var (
 type controller struct {
 	mu           sync.Mutex
 	port         io.ReadWriteCloser
+	reader       *contextutils.Reader
+	unfinished   bool
 	serialDevice string
 	logger       logging.Logger
 	activeAxes   map[string]bool
@@ -121,7 +124,7 @@ func NewMotor(ctx context.Context, c *Config, name resource.Name, logger logging
 	globalMu.Lock()
 	ctrl, ok := controllers[c.SerialDevice]
 	if !ok {
-		newCtrl, err := newController(c, logger)
+		newCtrl, err := newController(ctx, c, logger)
 		if err != nil {
 			return nil, err
 		}
@@ -172,7 +175,7 @@ func NewMotor(ctx context.Context, c *Config, name resource.Name, logger logging
 		m.HomeRPM = m.maxRPM / 4
 	}
 
-	if err := m.configure(c); err != nil {
+	if err := m.configure(ctx, c); err != nil {
 		return nil, err
 	}
 
@@ -201,6 +204,7 @@ func (m *Motor) Close(ctx context.Context) error {
 		}
 	}
 	if m.c.port != nil {
+		m.c.reader.Close()
 		err = m.c.port.Close()
 		if err != nil {
 			m.c.logger.CError(ctx, err)
@@ -212,7 +216,7 @@ func (m *Motor) Close(ctx context.Context) error {
 	return nil
 }
 
-func newController(c *Config, logger logging.Logger) (*controller, error) {
+func newController(ctx context.Context, c *Config, logger logging.Logger) (*controller, error) {
 	ctrl := new(controller)
 	ctrl.activeAxes = make(map[string]bool)
 	ctrl.serialDevice = c.SerialDevice
@@ -235,15 +239,16 @@ func newController(c *Config, logger logging.Logger) (*controller, error) {
 			return nil, err
 		}
 		ctrl.port = port
+		ctrl.reader = contextutils.NewReader(port)
 	}
 
 	// Set echo off to not scramble our returns
-	_, err := ctrl.sendCmd("EO 0")
+	_, err := ctrl.sendCmd(ctx, "EO 0")
 	if err != nil && !strings.HasPrefix(err.Error(), "unknown error after cmd") {
 		return nil, err
 	}
 
-	ret, err := ctrl.sendCmd("ID")
+	ret, err := ctrl.sendCmd(ctx, "ID")
 	if err != nil {
 		return nil, err
 	}
@@ -289,7 +294,7 @@ func newController(c *Config, logger logging.Logger) (*controller, error) {
 }
 
 // Must be run inside a lock.
-func (m *Motor) configure(c *Config) error {
+func (m *Motor) configure(ctx context.Context, c *Config) error {
 	var amp string
 	if m.Axis == "A" || m.Axis == "B" || m.Axis == "C" || m.Axis == "D" {
 		amp = m.c.ampModel1
@@ -308,43 +313,43 @@ func (m *Motor) configure(c *Config) error {
 		}
 
 		// Turn off the motor
-		_, err := m.c.sendCmd(fmt.Sprintf("MO%s", m.Axis))
+		_, err := m.c.sendCmd(ctx, fmt.Sprintf("MO%s", m.Axis))
 		if err != nil {
 			return err
 		}
 
 		// Set motor type to stepper (possibly reversed)
-		_, err = m.c.sendCmd(fmt.Sprintf("MT%s=%s", m.Axis, motorType))
+		_, err = m.c.sendCmd(ctx, fmt.Sprintf("MT%s=%s", m.Axis, motorType))
 		if err != nil {
 			return err
 		}
 
 		// Set amplifier gain
-		_, err = m.c.sendCmd(fmt.Sprintf("AG%s=%d", m.Axis, c.AmplifierGain))
+		_, err = m.c.sendCmd(ctx, fmt.Sprintf("AG%s=%d", m.Axis, c.AmplifierGain))
 		if err != nil {
 			return err
 		}
 
 		// Set low current mode
-		_, err = m.c.sendCmd(fmt.Sprintf("LC%s=%d", m.Axis, c.LowCurrent))
+		_, err = m.c.sendCmd(ctx, fmt.Sprintf("LC%s=%d", m.Axis, c.LowCurrent))
 		if err != nil {
 			return err
 		}
 
 		// Acceleration
-		_, err = m.c.sendCmd(fmt.Sprintf("AC%s=%d", m.Axis, m.rpmsToA(m.MaxAcceleration)))
+		_, err = m.c.sendCmd(ctx, fmt.Sprintf("AC%s=%d", m.Axis, m.rpmsToA(m.MaxAcceleration)))
 		if err != nil {
 			return err
 		}
 
 		// Deceleration
-		_, err = m.c.sendCmd(fmt.Sprintf("DC%s=%d", m.Axis, m.rpmsToA(m.MaxAcceleration)))
+		_, err = m.c.sendCmd(ctx, fmt.Sprintf("DC%s=%d", m.Axis, m.rpmsToA(m.MaxAcceleration)))
 		if err != nil {
 			return err
 		}
 
 		// Enable the motor
-		_, err = m.c.sendCmd(fmt.Sprintf("SH%s", m.Axis))
+		_, err = m.c.sendCmd(ctx, fmt.Sprintf("SH%s", m.Axis))
 		return err
 
 	default:
@@ -353,10 +358,20 @@ func (m *Motor) configure(c *Config) error {
 }
 
 // Must be run inside a lock.
-func (c *controller) sendCmd(cmd string) (string, error) {
+func (c *controller) sendCmd(ctx context.Context, cmd string) (string, error) {
 	if c.testChan != nil {
-		c.testChan <- cmd
+		select {
+		case c.testChan <- cmd:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
 	} else {
+		if c.unfinished {
+			// the response to a canceled command must not be taken for the response to this one.
+			if _, err := c.readResponse(ctx); err != nil {
+				return "", errors.Wrap(err, "failed to discard the response to a canceled command")
+			}
+		}
 		_, err := c.port.Write([]byte(cmd + "\r\n"))
 		if err != nil {
 			return "", err
@@ -364,21 +379,18 @@ func (c *controller) sendCmd(cmd string) (string, error) {
 	}
 
 	var ret []byte
-	for {
-		buf := make([]byte, 4096)
-		if c.testChan != nil {
-			ret = []byte(<-c.testChan)
-			break
+	if c.testChan != nil {
+		select {
+		case res := <-c.testChan:
+			ret = []byte(res)
+		case <-ctx.Done():
+			return "", ctx.Err()
 		}
-
-		n, err := c.port.Read(buf)
-		if err != nil {
+	} else {
+		var err error
+		if ret, err = c.readResponse(ctx); err != nil {
 			return string(ret), err
 		}
-		ret = append(ret, buf[:n]...)
-		if bytes.ContainsAny(buf[:n], ":?") {
-			break
-		}
 	}
 	if bytes.LastIndexByte(ret, []byte(":")[0]) == len(ret)-1 {
 		ret := string(bytes.TrimSpace(ret[:len(ret)-1]))
@@ -387,7 +399,7 @@ func (c *controller) sendCmd(cmd string) (string, error) {
 	}
 
 	if bytes.LastIndexByte(ret, []byte("?")[0]) == len(ret)-1 {
-		errorDetail, err := c.sendCmd("TC1")
+		errorDetail, err := c.sendCmd(ctx, "TC1")
 		if err != nil {
 			return string(ret), fmt.Errorf("error when trying to get error code from previous command (%s): %w", cmd, err)
 		}
@@ -397,6 +409,26 @@ func (c *controller) sendCmd(cmd string) (string, error) {
 	return string(ret), fmt.Errorf("unknown error after cmd (%s), response: %s", cmd, string(ret))
 }
 
+// readResponse reads the response to a command up to its terminating ":" or "?". If the
+// context is done first, the rest of the response is discarded before the next command is
+// sent. Must be run inside a lock.
+func (c *controller) readResponse(ctx context.Context) ([]byte, error) {
+	c.unfinished = true
+	var ret []byte
+	buf := make([]byte, 4096)
+	for {
+		n, err := c.reader.ReadContext(ctx, buf)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, buf[:n]...)
+		if bytes.ContainsAny(buf[:n], ":?") {
+			c.unfinished = false
+			return ret, nil
+		}
+	}
+}
+
 // Convert rpm to DMC4000 counts/sec.
 func (m *Motor) rpmToV(rpm float64) int {
 	rpm = math.Abs(rpm)
@@ -474,23 +506,23 @@ func (m *Motor) Jog(ctx context.Context, rpm float64) error {
 	}
 
 	// Jog
-	_, err := m.c.sendCmd(fmt.Sprintf("JG%s=%d", m.Axis, rawSpeed))
+	_, err := m.c.sendCmd(ctx, fmt.Sprintf("JG%s=%d", m.Axis, rawSpeed))
 	if err != nil {
 		return err
 	}
 
 	// Begin action
-	_, err = m.c.sendCmd(fmt.Sprintf("BG%s", m.Axis))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("BG%s", m.Axis))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (m *Motor) stopJog() error {
+func (m *Motor) stopJog(ctx context.Context) error {
 	if m.jogging {
 		m.jogging = false
-		_, err := m.c.sendCmd(fmt.Sprintf("ST%s", m.Axis))
+		_, err := m.c.sendCmd(ctx, fmt.Sprintf("ST%s", m.Axis))
 		return err
 	}
 	return nil
@@ -514,7 +546,7 @@ func (m *Motor) GoFor(ctx context.Context, rpm, revolutions float64, extra map[s
 
 	m.c.mu.Lock()
 	defer m.c.mu.Unlock()
-	curPos, err := m.doPosition()
+	curPos, err := m.doPosition(ctx)
 	if err != nil {
 		return err
 	}
@@ -522,7 +554,7 @@ func (m *Motor) GoFor(ctx context.Context, rpm, revolutions float64, extra map[s
 		revolutions *= -1
 	}
 	goal := curPos + revolutions
-	err = m.doGoTo(rpm, goal)
+	err = m.doGoTo(ctx, rpm, goal)
 	if err != nil {
 		return err
 	}
@@ -544,7 +576,7 @@ func (m *Motor) GoTo(ctx context.Context, rpm, position float64, extra map[strin
 	m.c.mu.Lock()
 	defer m.c.mu.Unlock()
 
-	if err := m.doGoTo(rpm, position); err != nil {
+	if err := m.doGoTo(ctx, rpm, position); err != nil {
 		return motor.NewGoToUnsupportedError(m.Name().ShortName())
 	}
 
@@ -564,7 +596,7 @@ func (m *Motor) SetRPM(ctx context.Context, rpm float64, extra map[string]interf
 func (m *Motor) ResetZeroPosition(ctx context.Context, offset float64, extra map[string]interface{}) error {
 	m.c.mu.Lock()
 	defer m.c.mu.Unlock()
-	_, err := m.c.sendCmd(fmt.Sprintf("DP%s=%d", m.Axis, int(-1*offset*float64(m.TicksPerRotation))))
+	_, err := m.c.sendCmd(ctx, fmt.Sprintf("DP%s=%d", m.Axis, int(-1*offset*float64(m.TicksPerRotation))))
 	if err != nil {
 		return errors.Wrap(err, "error in ResetZeroPosition")
 	}
@@ -575,7 +607,7 @@ func (m *Motor) ResetZeroPosition(ctx context.Context, offset float64, extra map
 func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (float64, error) {
 	m.c.mu.Lock()
 	defer m.c.mu.Unlock()
-	return m.doPosition()
+	return m.doPosition(ctx)
 }
 
 // Stop turns the power to the motor off immediately, without any gradual step down.
@@ -587,7 +619,7 @@ func (m *Motor) Stop(ctx context.Context, extra map[string]interface{}) error {
 	defer done()
 
 	m.jogging = false
-	_, err := m.c.sendCmd(fmt.Sprintf("ST%s", m.Axis))
+	_, err := m.c.sendCmd(ctx, fmt.Sprintf("ST%s", m.Axis))
 	if err != nil {
 		return errors.Wrap(err, "error in Stop function")
 	}
@@ -622,7 +654,7 @@ func (m *Motor) IsPowered(ctx context.Context, extra map[string]interface{}) (bo
 // Must be run inside a lock.
 func (m *Motor) isStopped(ctx context.Context) (bool, error) {
 	// check that stop was actually commanded
-	ret, err := m.c.sendCmd(fmt.Sprintf("SC%s", m.Axis))
+	ret, err := m.c.sendCmd(ctx, fmt.Sprintf("SC%s", m.Axis))
 	if err != nil {
 		return false, err
 	}
@@ -638,7 +670,7 @@ func (m *Motor) isStopped(ctx context.Context) (bool, error) {
 	}
 
 	// check that total error is zero (not coasting)
-	ret, err = m.c.sendCmd(fmt.Sprintf("TE%s", m.Axis))
+	ret, err = m.c.sendCmd(ctx, fmt.Sprintf("TE%s", m.Axis))
 	if err != nil {
 		return false, err
 	}
@@ -660,13 +692,13 @@ func (m *Motor) Home(ctx context.Context) error {
 	defer done()
 
 	// start homing (self-locking)
-	if err := m.startHome(); err != nil {
+	if err := m.startHome(ctx); err != nil {
 		return err
 	}
 
 	// wait for routine to finish
 	defer func() {
-		if err := m.Stop(ctx, nil); err != nil {
+		if err := m.Stop(context.WithoutCancel(ctx), nil); err != nil {
 			m.c.logger.CError(ctx, err)
 		}
 	}()
@@ -679,7 +711,7 @@ func (m *Motor) Home(ctx context.Context) error {
 
 		// Wait for
 		m.c.mu.Lock()
-		ret, err := m.c.sendCmd(fmt.Sprintf("SC%s", m.Axis))
+		ret, err := m.c.sendCmd(ctx, fmt.Sprintf("SC%s", m.Axis))
 		m.c.mu.Unlock()
 		if err != nil {
 			return err
@@ -701,36 +733,36 @@ func (m *Motor) Home(ctx context.Context) error {
 }
 
 // Does its own locking.
-func (m *Motor) startHome() error {
+func (m *Motor) startHome(ctx context.Context) error {
 	m.c.mu.Lock()
 	defer m.c.mu.Unlock()
 
 	// Exit jog mode if in it
-	err := m.stopJog()
+	err := m.stopJog(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Speed (stage 1)
-	_, err = m.c.sendCmd(fmt.Sprintf("SP%s=%d", m.Axis, m.rpmToV(m.HomeRPM)))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("SP%s=%d", m.Axis, m.rpmToV(m.HomeRPM)))
 	if err != nil {
 		return err
 	}
 
 	// Speed (stage 2)
-	_, err = m.c.sendCmd(fmt.Sprintf("HV%s=%d", m.Axis, m.rpmToV(m.HomeRPM/10)))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("HV%s=%d", m.Axis, m.rpmToV(m.HomeRPM/10)))
 	if err != nil {
 		return err
 	}
 
 	// Homing action
-	_, err = m.c.sendCmd(fmt.Sprintf("HM%s", m.Axis))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("HM%s", m.Axis))
 	if err != nil {
 		return err
 	}
 
 	// Begin action
-	_, err = m.c.sendCmd(fmt.Sprintf("BG%s", m.Axis))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("BG%s", m.Axis))
 	if err != nil {
 		return err
 	}
@@ -739,15 +771,15 @@ func (m *Motor) startHome() error {
 }
 
 // Must be run inside a lock.
-func (m *Motor) doGoTo(rpm, position float64) error {
+func (m *Motor) doGoTo(ctx context.Context, rpm, position float64) error {
 	// Exit jog mode if in it
-	err := m.stopJog()
+	err := m.stopJog(ctx)
 	if err != nil {
 		return err
 	}
 
 	// Position tracking mode
-	_, err = m.c.sendCmd(fmt.Sprintf("PT%s=1", m.Axis))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("PT%s=1", m.Axis))
 	if err != nil {
 		return err
 	}
@@ -761,13 +793,13 @@ func (m *Motor) doGoTo(rpm, position float64) error {
 	}
 
 	// Speed
-	_, err = m.c.sendCmd(fmt.Sprintf("SP%s=%d", m.Axis, m.rpmToV(rpm)))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("SP%s=%d", m.Axis, m.rpmToV(rpm)))
 	if err != nil {
 		return err
 	}
 
 	// Position target
-	_, err = m.c.sendCmd(fmt.Sprintf("PA%s=%d", m.Axis, m.posToSteps(position)))
+	_, err = m.c.sendCmd(ctx, fmt.Sprintf("PA%s=%d", m.Axis, m.posToSteps(position)))
 	if err != nil {
 		return err
 	}
@@ -775,8 +807,8 @@ func (m *Motor) doGoTo(rpm, position float64) error {
 }
 
 // Must be run inside a lock.
-func (m *Motor) doPosition() (float64, error) {
-	ret, err := m.c.sendCmd(fmt.Sprintf("RP%s", m.Axis))
+func (m *Motor) doPosition(ctx context.Context) (float64, error) {
+	ret, err := m.c.sendCmd(ctx, fmt.Sprintf("RP%s", m.Axis))
 	if err != nil {
 		return 0, err
 	}
@@ -813,7 +845,7 @@ func (m *Motor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[
 		}
 		m.c.mu.Lock()
 		defer m.c.mu.Unlock()
-		retVal, err := m.c.sendCmd(raw.(string))
+		retVal, err := m.c.sendCmd(ctx, raw.(string))
 		ret := map[string]interface{}{"return": retVal}
 		return ret, err
 	default:
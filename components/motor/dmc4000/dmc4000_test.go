@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sync"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 
@@ -125,6 +126,14 @@ func TestDMC4000Motor(t *testing.T) {
 	test.That(t, ok, test.ShouldBeTrue)
 	waitTx(t, resChan)
 
+	t.Run("motor commands return when canceled", func(t *testing.T) {
+		// no controller responds.
+		timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		_, err := motorDep.Position(timeoutCtx, nil)
+		test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+	})
+
 	t.Run("motor supports position reporting", func(t *testing.T) {
 		properties, err := motorDep.Properties(ctx, nil)
 		test.That(t, err, test.ShouldBeNil)
@@ -17,6 +17,7 @@ import (
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/simulation/imperfection"
 )
 
 var (
@@ -49,6 +50,9 @@ type Config struct {
 	MaxRPM           float64   `json:"max_rpm,omitempty"`
 	TicksPerRotation int       `json:"ticks_per_rotation,omitempty"`
 	DirectionFlip    bool      `json:"direction_flip,omitempty"`
+	// Imperfections simulates the latency, noise, drift, and dropouts of a real motor. Noise
+	// and drift apply to the position, in revolutions.
+	Imperfections *imperfection.Config `json:"imperfections,omitempty"`
 }
 
 // Validate ensures all parts of the config are valid.
@@ -63,12 +67,18 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 		}
 		deps = append(deps, cfg.Encoder)
 	}
+	if cfg.Imperfections != nil {
+		if err := cfg.Imperfections.Validate(path); err != nil {
+			return nil, err
+		}
+	}
 	return deps, nil
 }
 
 func init() {
 	resource.RegisterComponent(motor.API, model, resource.Registration[motor.Motor, *Config]{
-		Constructor: NewMotor,
+		Constructor:   NewMotor,
+		Unimplemented: []string{"SetRPM"},
 	})
 }
 
@@ -87,6 +97,7 @@ type Motor struct {
 	MaxRPM            float64
 	DirFlip           bool
 	TicksPerRotation  int
+	imperfections     *imperfection.Injector
 
 	OpMgr  *operation.SingleOperationManager
 	Logger logging.Logger
@@ -169,11 +180,15 @@ func (m *Motor) Reconfigure(ctx context.Context, deps resource.Dependencies, con
 	if newConf.DirectionFlip {
 		m.DirFlip = true
 	}
+	m.imperfections = newConf.Imperfections.NewInjector()
 	return nil
 }
 
 // Position returns motor position in rotations.
 func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	if err := m.injector().Call(ctx); err != nil {
+		return 0, err
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -190,7 +205,13 @@ func (m *Motor) Position(ctx context.Context, extra map[string]interface{}) (flo
 		return 0, errors.New("need nonzero TicksPerRotation for motor")
 	}
 
-	return ticks / float64(m.TicksPerRotation), nil
+	return m.imperfections.Value(ticks / float64(m.TicksPerRotation)), nil
+}
+
+func (m *Motor) injector() *imperfection.Injector {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.imperfections
 }
 
 // Properties returns the status of whether the motor supports certain optional properties.
@@ -202,6 +223,9 @@ func (m *Motor) Properties(ctx context.Context, extra map[string]interface{}) (m
 
 // SetPower sets the given power percentage.
 func (m *Motor) SetPower(ctx context.Context, powerPct float64, extra map[string]interface{}) error {
+	if err := m.injector().Call(ctx); err != nil {
+		return err
+	}
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
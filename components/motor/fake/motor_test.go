@@ -4,10 +4,12 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/components/encoder/fake"
 	"go.viam.com/rdk/components/motor"
 	"go.viam.com/rdk/logging"
@@ -78,6 +80,47 @@ func TestGoFor(t *testing.T) {
 	})
 }
 
+func TestGoForVirtualTime(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	enc, err := fake.NewEncoder(ctx, resource.Config{
+		ConvertedAttributes: &fake.Config{},
+	}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	m := &Motor{
+		Encoder:           enc.(fake.Encoder),
+		Logger:            logger,
+		PositionReporting: true,
+		MaxRPM:            60,
+		TicksPerRotation:  1,
+		OpMgr:             operation.NewSingleOperationManager(),
+	}
+
+	// 10 revolutions at 30 rpm takes 20 seconds of virtual time
+	done := make(chan error, 1)
+	go func() {
+		done <- m.GoFor(ctx, 30, 10, nil)
+	}()
+	finished := func() bool {
+		select {
+		case err := <-done:
+			test.That(t, err, test.ShouldBeNil)
+			return true
+		default:
+			return false
+		}
+	}
+	test.That(t, virtual.StepUntil(time.Second, time.Minute, finished), test.ShouldBeTrue)
+
+	pos, err := m.Position(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pos, test.ShouldEqual, 10)
+}
+
 func TestGoTo(t *testing.T) {
 	logger, obs := logging.NewObservedTestLogger(t)
 	ctx := context.Background()
@@ -98,6 +98,7 @@ func init() {
 
 			return newGPIOStepper(ctx, actualBoard, *motorConfig, conf.ResourceName(), logger)
 		},
+		Unimplemented: []string{"SetRPM"},
 	})
 }
 
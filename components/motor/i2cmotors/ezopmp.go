@@ -62,6 +62,7 @@ func init() {
 			}
 			return NewMotor(ctx, deps, newConf, conf.ResourceName(), logger)
 		},
+		Unimplemented: []string{"SetRPM", "ResetZeroPosition"},
 	})
 }
 
@@ -13,6 +13,7 @@ import (
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Motor]{
 		Status:                      resource.StatusFunc(CreateStatus),
+		SelfTest:                    SelfTest,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterMotorServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.MotorService_ServiceDesc,
@@ -174,3 +175,9 @@ func CreateStatus(ctx context.Context, m Motor) (*pb.Status, error) {
 		IsMoving:  isMoving,
 	}, nil
 }
+
+// SelfTest checks that the motor is functional by querying whether it is powered.
+func SelfTest(ctx context.Context, m Motor) error {
+	_, _, err := m.IsPowered(ctx, nil)
+	return err
+}
@@ -144,6 +144,7 @@ func init() {
 			) (motor.Motor, error) {
 				return newRoboClaw(conf, logger)
 			},
+			Unimplemented: []string{"SetRPM"},
 		},
 	)
 }
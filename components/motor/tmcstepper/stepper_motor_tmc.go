@@ -74,7 +74,8 @@ func (config *TMC5072Config) Validate(path string) ([]string, error) {
 
 func init() {
 	resource.RegisterComponent(motor.API, model, resource.Registration[motor.Motor, *TMC5072Config]{
-		Constructor: newMotor,
+		Constructor:   newMotor,
+		Unimplemented: []string{"SetRPM"},
 	})
 }
 
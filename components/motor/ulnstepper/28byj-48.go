@@ -95,7 +95,8 @@ func (conf *Config) Validate(path string) ([]string, error) {
 
 func init() {
 	resource.RegisterComponent(motor.API, model, resource.Registration[motor.Motor, *Config]{
-		Constructor: new28byj,
+		Constructor:   new28byj,
+		Unimplemented: []string{"SetRPM"},
 	})
 }
 
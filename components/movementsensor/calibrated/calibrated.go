@@ -0,0 +1,509 @@
+// Package calibrated implements a movement sensor that corrects the readings of another with
+// calibrations measured by guided routines: the gyroscope's bias at rest, the accelerometer's
+// bias and scale from six positions, and the magnetometer's hard and soft iron distortion
+// from an ellipsoid fit.
+//
+// The routines are run with DoCommand, using CalibrateCommandKey, so that they can be run
+// over the network. Their results are saved to a file and applied to the readings from then
+// on, including after the robot restarts.
+package calibrated
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
+)
+
+var model = resource.DefaultModelFamily.WithModel("calibrated")
+
+const (
+	defaultSamples          = 100
+	defaultSampleIntervalMs = 10
+	// defaultMagnetometerSec is how long the magnetometer is sampled while it is turned.
+	defaultMagnetometerSec = 30
+	// gyroTolerance and accelTolerance are how far in degrees per second and meters per
+	// second per second samples may be from their mean while the sensor is still.
+	gyroTolerance  = 2.0
+	accelTolerance = 1.0
+	// maxTilt is the largest roll and pitch the compass heading is compensated for.
+	maxTilt = math.Pi / 4
+)
+
+// CalibrateCommandKey is the DoCommand key running the calibration routines. Its value is one
+// of the routines below.
+const CalibrateCommandKey = "calibrate"
+
+// The routines of CalibrateCommandKey.
+const (
+	// RoutineGyroBias samples the gyroscope while the sensor is still.
+	RoutineGyroBias = "gyro_bias"
+	// RoutineAccelerometer samples the accelerometer while the sensor is still in the
+	// "position" given with the command: "+x", "-x", "+y", "-y", "+z" or "-z", naming the axis
+	// pointing up. Once every position has been sampled, the calibration is fit.
+	RoutineAccelerometer = "accelerometer"
+	// RoutineMagnetometer samples the magnetometer for "duration_sec" seconds, 30 by default,
+	// while the sensor is turned through as many directions as possible.
+	RoutineMagnetometer = "magnetometer"
+	// RoutineGet returns the calibration.
+	RoutineGet = "get"
+	// RoutineClear discards the calibration.
+	RoutineClear = "clear"
+)
+
+// Config is the config of the calibrated movement_sensor model.
+type Config struct {
+	// MovementSensor is the name of the movement sensor whose readings are corrected.
+	MovementSensor string `json:"movement_sensor"`
+	// CalibrationFile is where the calibration is saved. It defaults to a file named after
+	// the sensor in the calibration directory of the viam directory.
+	CalibrationFile string `json:"calibration_file,omitempty"`
+	// Samples and SampleIntervalMs are how many samples the still routines take and how far
+	// apart. They default to 100 and 10ms.
+	Samples          int `json:"samples,omitempty"`
+	SampleIntervalMs int `json:"sample_interval_ms,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *Config) Validate(path string) ([]string, error) {
+	if cfg.MovementSensor == "" {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "movement_sensor")
+	}
+	if cfg.Samples < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("samples must not be negative"))
+	}
+	if cfg.SampleIntervalMs < 0 {
+		return nil, resource.NewConfigValidationError(path, errors.New("sample_interval_ms must not be negative"))
+	}
+	return []string{cfg.MovementSensor}, nil
+}
+
+func init() {
+	resource.RegisterComponent(
+		movementsensor.API, model,
+		resource.Registration[movementsensor.MovementSensor, *Config]{Constructor: newCalibrated})
+}
+
+type calibrated struct {
+	resource.Named
+	logger logging.Logger
+
+	mu             sync.Mutex
+	sensor         movementsensor.MovementSensor
+	path           string
+	samples        int
+	sampleInterval time.Duration
+	calibration    Calibration
+	// positions are the mean accelerations measured so far in the six positions.
+	positions map[string]r3.Vector
+}
+
+func newCalibrated(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (
+	movementsensor.MovementSensor, error,
+) {
+	c := &calibrated{Named: conf.ResourceName().AsNamed(), logger: logger}
+	if err := c.Reconfigure(ctx, deps, conf); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *calibrated) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return err
+	}
+	sensor, err := movementsensor.FromDependencies(deps, newConf.MovementSensor)
+	if err != nil {
+		return err
+	}
+	path := newConf.CalibrationFile
+	if path == "" {
+		path = filepath.Join(config.ViamDotDir, "calibration", conf.ResourceName().ShortName()+".json")
+	}
+	cal, err := loadCalibration(path)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sensor = sensor
+	if path != c.path {
+		c.positions = nil
+	}
+	c.path = path
+	c.calibration = cal
+	c.samples = newConf.Samples
+	if c.samples == 0 {
+		c.samples = defaultSamples
+	}
+	c.sampleInterval = time.Duration(newConf.SampleIntervalMs) * time.Millisecond
+	if c.sampleInterval == 0 {
+		c.sampleInterval = defaultSampleIntervalMs * time.Millisecond
+	}
+	return nil
+}
+
+// loadCalibration reads the calibration saved at path, which is empty if none was saved.
+func loadCalibration(path string) (Calibration, error) {
+	var cal Calibration
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cal, nil
+	}
+	if err != nil {
+		return cal, err
+	}
+	if err := json.Unmarshal(data, &cal); err != nil {
+		return cal, errors.Wrapf(err, "failed to read calibration from %q", path)
+	}
+	return cal, nil
+}
+
+// saveCalibration saves the calibration to path, replacing the file so that a crash while
+// saving does not leave it partly written.
+func saveCalibration(path string, cal Calibration) error {
+	data, err := json.MarshalIndent(cal, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// state returns the wrapped sensor and the calibration.
+func (c *calibrated) state() (movementsensor.MovementSensor, Calibration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sensor, c.calibration
+}
+
+func (c *calibrated) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	sensor, _ := c.state()
+	return sensor.Position(ctx, extra)
+}
+
+func (c *calibrated) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	sensor, _ := c.state()
+	return sensor.LinearVelocity(ctx, extra)
+}
+
+func (c *calibrated) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+	sensor, cal := c.state()
+	vel, err := sensor.AngularVelocity(ctx, extra)
+	if err != nil || cal.GyroBias == nil {
+		return vel, err
+	}
+	return spatialmath.AngularVelocity(r3.Vector(vel).Sub(*cal.GyroBias)), nil
+}
+
+func (c *calibrated) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+	sensor, cal := c.state()
+	acc, err := sensor.LinearAcceleration(ctx, extra)
+	if err != nil || cal.Accelerometer == nil {
+		return acc, err
+	}
+	return cal.Accelerometer.Apply(acc), nil
+}
+
+// CompassHeading computes the heading from the corrected magnetic field once the
+// magnetometer is calibrated, compensating for tilts of up to 45 degrees if the sensor
+// reports its orientation.
+func (c *calibrated) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
+	sensor, cal := c.state()
+	if cal.Magnetometer == nil {
+		return sensor.CompassHeading(ctx, extra)
+	}
+	field, err := magneticField(ctx, sensor, extra)
+	if err != nil {
+		return 0, err
+	}
+	field = cal.Magnetometer.Apply(field)
+	x, y := field.X, field.Y
+	if ori, err := sensor.Orientation(ctx, extra); err == nil {
+		euler := ori.EulerAngles()
+		if math.Abs(euler.Roll) <= maxTilt && math.Abs(euler.Pitch) <= maxTilt {
+			x = field.X*math.Cos(euler.Pitch) + field.Z*math.Sin(euler.Pitch)
+			y = field.X*math.Sin(euler.Roll)*math.Sin(euler.Pitch) +
+				field.Y*math.Cos(euler.Roll) - field.Z*math.Sin(euler.Roll)*math.Cos(euler.Pitch)
+		}
+	}
+	heading := rutils.RadToDeg(math.Atan2(y, x))
+	return math.Mod(math.Mod(heading, 360)+360, 360), nil
+}
+
+// magneticField returns the magnetic field the sensor reports in its "magnetometer" reading.
+func magneticField(ctx context.Context, sensor movementsensor.MovementSensor, extra map[string]interface{}) (r3.Vector, error) {
+	readings, err := sensor.Readings(ctx, extra)
+	if err != nil {
+		return r3.Vector{}, err
+	}
+	switch field := readings["magnetometer"].(type) {
+	case r3.Vector:
+		return field, nil
+	case map[string]interface{}:
+		// readings of sensors served over the network are decoded into maps.
+		x, okX := field["x"].(float64)
+		y, okY := field["y"].(float64)
+		z, okZ := field["z"].(float64)
+		if okX && okY && okZ {
+			return r3.Vector{X: x, Y: y, Z: z}, nil
+		}
+	}
+	return r3.Vector{}, errors.Errorf("movement sensor %q does not report a magnetometer reading", sensor.Name().ShortName())
+}
+
+func (c *calibrated) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	sensor, _ := c.state()
+	return sensor.Orientation(ctx, extra)
+}
+
+func (c *calibrated) Properties(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+	sensor, _ := c.state()
+	return sensor.Properties(ctx, extra)
+}
+
+func (c *calibrated) Accuracy(ctx context.Context, extra map[string]interface{}) (*movementsensor.Accuracy, error) {
+	sensor, _ := c.state()
+	return sensor.Accuracy(ctx, extra)
+}
+
+func (c *calibrated) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	readings, err := movementsensor.DefaultAPIReadings(ctx, c, extra)
+	if err != nil {
+		return nil, err
+	}
+	sensor, cal := c.state()
+	if field, err := magneticField(ctx, sensor, extra); err == nil {
+		if cal.Magnetometer != nil {
+			field = cal.Magnetometer.Apply(field)
+		}
+		readings["magnetometer"] = field
+	}
+	return readings, nil
+}
+
+// DoCommand runs the calibration routine named by CalibrateCommandKey, returning the
+// calibration as it is after the routine.
+func (c *calibrated) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	routine, ok := cmd[CalibrateCommandKey].(string)
+	if !ok {
+		return nil, resource.ErrDoUnimplemented
+	}
+	resp := map[string]interface{}{}
+	var err error
+	switch routine {
+	case RoutineGyroBias:
+		err = c.calibrateGyroBias(ctx)
+	case RoutineAccelerometer:
+		position, _ := cmd["position"].(string)
+		err = c.calibrateAccelerometer(ctx, position, resp)
+	case RoutineMagnetometer:
+		duration := float64(defaultMagnetometerSec)
+		if sec, ok := cmd["duration_sec"].(float64); ok {
+			duration = sec
+		}
+		err = c.calibrateMagnetometer(ctx, time.Duration(duration*float64(time.Second)))
+	case RoutineGet:
+	case RoutineClear:
+		err = c.update(func(cal *Calibration) {
+			*cal = Calibration{}
+			c.positions = nil
+		})
+	default:
+		return nil, errors.Errorf("unknown calibration routine %q", routine)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, cal := c.state()
+	data, err := json.Marshal(cal)
+	if err != nil {
+		return nil, err
+	}
+	var calMap map[string]interface{}
+	if err := json.Unmarshal(data, &calMap); err != nil {
+		return nil, err
+	}
+	resp["calibration"] = calMap
+	return resp, nil
+}
+
+// update changes the calibration and saves it. The change is discarded if it fails to save.
+func (c *calibrated) update(change func(cal *Calibration)) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cal := c.calibration
+	change(&cal)
+	if err := saveCalibration(c.path, cal); err != nil {
+		return errors.Wrap(err, "failed to save calibration")
+	}
+	c.calibration = cal
+	return nil
+}
+
+// sample reads the sensor at the configured interval, for the configured number of samples
+// or until the duration, if any, is over.
+func (c *calibrated) sample(
+	ctx context.Context,
+	duration time.Duration,
+	read func(ctx context.Context, sensor movementsensor.MovementSensor) (r3.Vector, error),
+) ([]r3.Vector, error) {
+	c.mu.Lock()
+	sensor, n, interval := c.sensor, c.samples, c.sampleInterval
+	c.mu.Unlock()
+	var deadline time.Time
+	if duration > 0 {
+		deadline = time.Now().Add(duration)
+	}
+	var samples []r3.Vector
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s, err := read(ctx, sensor)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, s)
+		if deadline.IsZero() && len(samples) == n {
+			return samples, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case now := <-ticker.C:
+			if !deadline.IsZero() && !now.Before(deadline) {
+				return samples, nil
+			}
+		}
+	}
+}
+
+func (c *calibrated) calibrateGyroBias(ctx context.Context) error {
+	samples, err := c.sample(ctx, 0, func(ctx context.Context, sensor movementsensor.MovementSensor) (r3.Vector, error) {
+		vel, err := sensor.AngularVelocity(ctx, nil)
+		return r3.Vector(vel), err
+	})
+	if err != nil {
+		return err
+	}
+	bias, err := stationaryMean(samples, gyroTolerance)
+	if err != nil {
+		return err
+	}
+	c.logger.CInfow(ctx, "calibrated gyroscope bias", "bias", bias)
+	return c.update(func(cal *Calibration) { cal.GyroBias = &bias })
+}
+
+// calibrateAccelerometer measures the mean acceleration in the position, and fits the
+// calibration once every position is measured. The positions measured and left to measure
+// are added to resp.
+func (c *calibrated) calibrateAccelerometer(ctx context.Context, position string, resp map[string]interface{}) error {
+	validPosition := false
+	for _, p := range positionOrder {
+		validPosition = validPosition || p == position
+	}
+	if !validPosition {
+		return errors.Errorf("position must be one of %v, got %q", positionOrder, position)
+	}
+	samples, err := c.sample(ctx, 0, func(ctx context.Context, sensor movementsensor.MovementSensor) (r3.Vector, error) {
+		return sensor.LinearAcceleration(ctx, nil)
+	})
+	if err != nil {
+		return err
+	}
+	acc, err := stationaryMean(samples, accelTolerance)
+	if err != nil {
+		return err
+	}
+	if err := checkPosition(position, acc); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.positions == nil {
+		c.positions = map[string]r3.Vector{}
+	}
+	c.positions[position] = acc
+	var remaining []string
+	for _, p := range positionOrder {
+		if _, ok := c.positions[p]; !ok {
+			remaining = append(remaining, p)
+		}
+	}
+	measured := make([]string, 0, len(c.positions))
+	for p := range c.positions {
+		measured = append(measured, p)
+	}
+	positions := c.positions
+	c.mu.Unlock()
+	sort.Strings(measured)
+	resp["measured_positions"] = toInterfaces(measured)
+	resp["remaining_positions"] = toInterfaces(remaining)
+	if len(remaining) != 0 {
+		return nil
+	}
+
+	accel, err := fitAccelerometer(positions)
+	if err != nil {
+		return err
+	}
+	c.logger.CInfow(ctx, "calibrated accelerometer", "bias", accel.Bias, "scale", accel.Scale)
+	return c.update(func(cal *Calibration) {
+		cal.Accelerometer = accel
+		c.positions = nil
+	})
+}
+
+func (c *calibrated) calibrateMagnetometer(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return errors.New("duration_sec must be positive")
+	}
+	samples, err := c.sample(ctx, duration, func(ctx context.Context, sensor movementsensor.MovementSensor) (r3.Vector, error) {
+		return magneticField(ctx, sensor, nil)
+	})
+	if err != nil {
+		return err
+	}
+	mag, err := fitMagnetometer(samples)
+	if err != nil {
+		return err
+	}
+	c.logger.CInfow(ctx, "calibrated magnetometer", "hard_iron", mag.HardIron, "samples", len(samples))
+	return c.update(func(cal *Calibration) { cal.Magnetometer = mag })
+}
+
+func toInterfaces(strs []string) []interface{} {
+	out := make([]interface{}, 0, len(strs))
+	for _, s := range strs {
+		out = append(out, s)
+	}
+	return out
+}
+
+func (c *calibrated) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,251 @@
+package calibrated
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+var (
+	testHardIron = r3.Vector{X: 12, Y: -7, Z: 4}
+	testGyroBias = r3.Vector{X: 0.5, Y: -1.5, Z: 0.25}
+	testAccBias  = r3.Vector{X: 0.1, Y: -0.2, Z: 0.3}
+	testAccScale = 1.05
+)
+
+// distort applies a soft iron distortion and the hard iron offset to a field.
+func distort(field r3.Vector) r3.Vector {
+	return r3.Vector{
+		X: 1.2*field.X + 0.1*field.Y,
+		Y: 0.1*field.X + 0.9*field.Y + 0.05*field.Z,
+		Z: 0.05*field.Y + 1.1*field.Z,
+	}.Add(testHardIron)
+}
+
+// sphere returns n points spread evenly over a sphere of the given radius.
+func sphere(n int, radius float64) []r3.Vector {
+	points := make([]r3.Vector, 0, n)
+	golden := math.Pi * (3 - math.Sqrt(5))
+	for i := 0; i < n; i++ {
+		z := 1 - 2*(float64(i)+0.5)/float64(n)
+		r := math.Sqrt(1 - z*z)
+		points = append(points, r3.Vector{X: r * math.Cos(golden*float64(i)), Y: r * math.Sin(golden*float64(i)), Z: z}.Mul(radius))
+	}
+	return points
+}
+
+func TestFitMagnetometer(t *testing.T) {
+	var samples []r3.Vector
+	for _, field := range sphere(200, 50) {
+		samples = append(samples, distort(field))
+	}
+	mag, err := fitMagnetometer(samples)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mag.HardIron.X, test.ShouldAlmostEqual, testHardIron.X, 1e-6)
+	test.That(t, mag.HardIron.Y, test.ShouldAlmostEqual, testHardIron.Y, 1e-6)
+	test.That(t, mag.HardIron.Z, test.ShouldAlmostEqual, testHardIron.Z, 1e-6)
+	radius := mag.Apply(samples[0]).Norm()
+	for _, s := range samples {
+		test.That(t, mag.Apply(s).Norm(), test.ShouldAlmostEqual, radius, 1e-6)
+	}
+	// the radius is near the strength of the undistorted field.
+	test.That(t, radius, test.ShouldAlmostEqual, 50, 5)
+
+	_, err = fitMagnetometer(samples[:5])
+	test.That(t, err, test.ShouldBeError, "at least 9 samples are needed, got 5")
+
+	// samples in a plane do not describe an ellipsoid.
+	var flat []r3.Vector
+	for _, s := range samples {
+		flat = append(flat, r3.Vector{X: s.X, Y: s.Y})
+	}
+	_, err = fitMagnetometer(flat)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestFitAccelerometer(t *testing.T) {
+	positions := map[string]r3.Vector{}
+	for position, up := range map[string]r3.Vector{
+		positionXUp: {X: 1}, positionXDown: {X: -1},
+		positionYUp: {Y: 1}, positionYDown: {Y: -1},
+		positionZUp: {Z: 1}, positionZDown: {Z: -1},
+	} {
+		acc := up.Mul(standardGravity / testAccScale).Add(testAccBias)
+		test.That(t, checkPosition(position, acc), test.ShouldBeNil)
+		positions[position] = acc
+	}
+	test.That(t, checkPosition(positionXDown, positions[positionXUp]), test.ShouldNotBeNil)
+
+	accel, err := fitAccelerometer(positions)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, accel.Bias.Sub(testAccBias).Norm(), test.ShouldAlmostEqual, 0, 1e-9)
+	test.That(t, accel.Scale.X, test.ShouldAlmostEqual, testAccScale)
+	test.That(t, accel.Scale.Y, test.ShouldAlmostEqual, testAccScale)
+	test.That(t, accel.Scale.Z, test.ShouldAlmostEqual, testAccScale)
+
+	delete(positions, positionZDown)
+	_, err = fitAccelerometer(positions)
+	test.That(t, err, test.ShouldBeError, "position -z has not been measured")
+}
+
+// fakeIMU is an IMU at rest with the given axis up and a biased gyroscope, accelerometer,
+// and magnetometer whose readings turn through a sphere.
+type fakeIMU struct {
+	mu     sync.Mutex
+	up     r3.Vector
+	fields []r3.Vector
+	next   int
+}
+
+func (f *fakeIMU) sensor() *inject.MovementSensor {
+	ms := inject.NewMovementSensor("imu")
+	ms.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return nil, 0, movementsensor.ErrMethodUnimplementedPosition
+	}
+	ms.LinearVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		return r3.Vector{}, movementsensor.ErrMethodUnimplementedLinearVelocity
+	}
+	ms.AngularVelocityFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
+		return spatialmath.AngularVelocity(testGyroBias), nil
+	}
+	ms.LinearAccelerationFunc = func(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.up.Mul(standardGravity / testAccScale).Add(testAccBias), nil
+	}
+	ms.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return spatialmath.NewZeroOrientation(), nil
+	}
+	ms.CompassHeadingFunc = func(ctx context.Context, extra map[string]interface{}) (float64, error) {
+		return 123, nil
+	}
+	ms.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		field := f.fields[f.next%len(f.fields)]
+		f.next++
+		return map[string]interface{}{"magnetometer": distort(field)}, nil
+	}
+	return ms
+}
+
+func (f *fakeIMU) setUp(up r3.Vector) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.up = up
+}
+
+func TestCalibrated(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	imu := &fakeIMU{up: r3.Vector{Z: 1}, fields: sphere(200, 50)}
+	deps := resource.Dependencies{movementsensor.Named("imu"): imu.sensor()}
+	conf := resource.Config{
+		Name:  "calibrated",
+		API:   movementsensor.API,
+		Model: model,
+		ConvertedAttributes: &Config{
+			MovementSensor:   "imu",
+			CalibrationFile:  filepath.Join(t.TempDir(), "calibration", "imu.json"),
+			Samples:          5,
+			SampleIntervalMs: 1,
+		},
+	}
+	ms, err := newCalibrated(ctx, deps, conf, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// readings are passed through until calibrated.
+	vel, err := ms.AngularVelocity(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, r3.Vector(vel), test.ShouldResemble, testGyroBias)
+	heading, err := ms.CompassHeading(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, heading, test.ShouldEqual, 123)
+
+	t.Run("gyro bias", func(t *testing.T) {
+		resp, err := ms.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: RoutineGyroBias})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["calibration"], test.ShouldContainKey, "gyro_bias")
+		vel, err := ms.AngularVelocity(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, r3.Vector(vel).Norm(), test.ShouldAlmostEqual, 0, 1e-9)
+	})
+
+	t.Run("accelerometer", func(t *testing.T) {
+		_, err := ms.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: RoutineAccelerometer, "position": "up"})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "position must be one of")
+
+		// the sensor is still +z up.
+		_, err = ms.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: RoutineAccelerometer, "position": "+x"})
+		test.That(t, err, test.ShouldBeError, "the sensor does not read gravity along x; place it with its +x axis pointing up")
+
+		for i, position := range positionOrder {
+			up := map[string]r3.Vector{
+				positionXUp: {X: 1}, positionXDown: {X: -1},
+				positionYUp: {Y: 1}, positionYDown: {Y: -1},
+				positionZUp: {Z: 1}, positionZDown: {Z: -1},
+			}[position]
+			imu.setUp(up)
+			resp, err := ms.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: RoutineAccelerometer, "position": position})
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, resp["remaining_positions"], test.ShouldHaveLength, len(positionOrder)-i-1)
+			if i < len(positionOrder)-1 {
+				test.That(t, resp["calibration"], test.ShouldNotContainKey, "accelerometer")
+			}
+		}
+		imu.setUp(r3.Vector{Z: 1})
+		acc, err := ms.LinearAcceleration(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, acc.Sub(r3.Vector{Z: standardGravity}).Norm(), test.ShouldAlmostEqual, 0, 1e-9)
+	})
+
+	t.Run("magnetometer", func(t *testing.T) {
+		_, err := ms.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: RoutineMagnetometer, "duration_sec": 0.5})
+		test.That(t, err, test.ShouldBeNil)
+		readings, err := ms.Readings(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		radius := readings["magnetometer"].(r3.Vector).Norm()
+		test.That(t, radius, test.ShouldAlmostEqual, 50, 5)
+
+		// the heading is computed from the corrected field, with north along y.
+		imu.mu.Lock()
+		imu.fields = []r3.Vector{{Y: 50}}
+		imu.mu.Unlock()
+		heading, err := ms.CompassHeading(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, heading, test.ShouldAlmostEqual, 90, 1)
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		_, cal := ms.(*calibrated).state()
+		reloaded, err := newCalibrated(ctx, deps, conf, logger)
+		test.That(t, err, test.ShouldBeNil)
+		_, reloadedCal := reloaded.(*calibrated).state()
+		test.That(t, reloadedCal, test.ShouldResemble, cal)
+
+		_, err = reloaded.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: RoutineClear})
+		test.That(t, err, test.ShouldBeNil)
+		reloaded, err = newCalibrated(ctx, deps, conf, logger)
+		test.That(t, err, test.ShouldBeNil)
+		_, reloadedCal = reloaded.(*calibrated).state()
+		test.That(t, reloadedCal, test.ShouldResemble, Calibration{})
+	})
+
+	_, err = ms.DoCommand(ctx, map[string]interface{}{CalibrateCommandKey: "everything"})
+	test.That(t, err, test.ShouldBeError, `unknown calibration routine "everything"`)
+	_, err = ms.DoCommand(ctx, map[string]interface{}{"other": true})
+	test.That(t, err, test.ShouldBeError, resource.ErrDoUnimplemented)
+}
@@ -0,0 +1,212 @@
+package calibrated
+
+import (
+	"math"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"gonum.org/v1/gonum/mat"
+)
+
+// standardGravity is the acceleration due to gravity in meters per second per second.
+const standardGravity = 9.80665
+
+// A Calibration corrects the readings of a movement sensor. Each part is set once the routine
+// measuring it has run.
+type Calibration struct {
+	// GyroBias is the angular velocity in degrees per second the gyroscope reads at rest.
+	GyroBias *r3.Vector `json:"gyro_bias,omitempty"`
+	// Accelerometer corrects linear accelerations.
+	Accelerometer *AccelerometerCalibration `json:"accelerometer,omitempty"`
+	// Magnetometer corrects magnetic fields, and the compass headings computed from them.
+	Magnetometer *MagnetometerCalibration `json:"magnetometer,omitempty"`
+}
+
+// AccelerometerCalibration corrects a linear acceleration a to (a - Bias) * Scale, per axis.
+type AccelerometerCalibration struct {
+	Bias  r3.Vector `json:"bias"`
+	Scale r3.Vector `json:"scale"`
+}
+
+// Apply returns the corrected linear acceleration.
+func (c *AccelerometerCalibration) Apply(acc r3.Vector) r3.Vector {
+	return r3.Vector{
+		X: (acc.X - c.Bias.X) * c.Scale.X,
+		Y: (acc.Y - c.Bias.Y) * c.Scale.Y,
+		Z: (acc.Z - c.Bias.Z) * c.Scale.Z,
+	}
+}
+
+// MagnetometerCalibration corrects a magnetic field m to SoftIron * (m - HardIron). HardIron
+// is the field of magnetized parts moving with the sensor, and SoftIron undoes the
+// distortion of the field by parts moving with the sensor, given row by row.
+type MagnetometerCalibration struct {
+	HardIron r3.Vector  `json:"hard_iron"`
+	SoftIron [9]float64 `json:"soft_iron"`
+}
+
+// Apply returns the corrected magnetic field.
+func (c *MagnetometerCalibration) Apply(field r3.Vector) r3.Vector {
+	d := field.Sub(c.HardIron)
+	w := c.SoftIron
+	return r3.Vector{
+		X: w[0]*d.X + w[1]*d.Y + w[2]*d.Z,
+		Y: w[3]*d.X + w[4]*d.Y + w[5]*d.Z,
+		Z: w[6]*d.X + w[7]*d.Y + w[8]*d.Z,
+	}
+}
+
+// mean returns the mean of the samples.
+func mean(samples []r3.Vector) r3.Vector {
+	var sum r3.Vector
+	for _, s := range samples {
+		sum = sum.Add(s)
+	}
+	return sum.Mul(1 / float64(len(samples)))
+}
+
+// stationaryMean returns the mean of the samples, failing if any sample is further than
+// tolerance from it, which means the sensor moved while it was sampled.
+func stationaryMean(samples []r3.Vector, tolerance float64) (r3.Vector, error) {
+	m := mean(samples)
+	for _, s := range samples {
+		if s.Sub(m).Norm() > tolerance {
+			return r3.Vector{}, errors.New("the sensor moved while it was sampled; keep it still")
+		}
+	}
+	return m, nil
+}
+
+// The positions of the six-position accelerometer calibration, naming the axis of the sensor
+// that points up.
+const (
+	positionXUp     = "+x"
+	positionXDown   = "-x"
+	positionYUp     = "+y"
+	positionYDown   = "-y"
+	positionZUp     = "+z"
+	positionZDown   = "-z"
+	minAxisFraction = 0.5
+)
+
+var positionOrder = []string{positionXUp, positionXDown, positionYUp, positionYDown, positionZUp, positionZDown}
+
+// axisOf returns the component of v along the axis of the position, and the components of v
+// across it.
+func axisOf(position string, v r3.Vector) (float64, float64) {
+	switch position {
+	case positionXUp, positionXDown:
+		return v.X, math.Hypot(v.Y, v.Z)
+	case positionYUp, positionYDown:
+		return v.Y, math.Hypot(v.X, v.Z)
+	default:
+		return v.Z, math.Hypot(v.X, v.Y)
+	}
+}
+
+// checkPosition checks that the mean acceleration measured in the position is gravity along
+// the position's axis, so that a sensor placed the wrong way up is caught.
+func checkPosition(position string, acc r3.Vector) error {
+	along, across := axisOf(position, acc)
+	if position[0] == '-' {
+		along = -along
+	}
+	if along < minAxisFraction*standardGravity || across > along {
+		return errors.Errorf("the sensor does not read gravity along %s; place it with its %s axis pointing up",
+			position[1:], position)
+	}
+	return nil
+}
+
+// fitAccelerometer returns the calibration of an accelerometer from its mean accelerations
+// at rest in each of the six positions. Each axis reads +g pointing up and -g pointing down,
+// so its bias is the middle of the two and its scale makes them g apart from the middle.
+func fitAccelerometer(positions map[string]r3.Vector) (*AccelerometerCalibration, error) {
+	for _, position := range positionOrder {
+		if _, ok := positions[position]; !ok {
+			return nil, errors.Errorf("position %s has not been measured", position)
+		}
+	}
+	axis := func(up, down string) (float64, float64) {
+		u, _ := axisOf(up, positions[up])
+		d, _ := axisOf(down, positions[down])
+		return (u + d) / 2, 2 * standardGravity / (u - d)
+	}
+	var c AccelerometerCalibration
+	c.Bias.X, c.Scale.X = axis(positionXUp, positionXDown)
+	c.Bias.Y, c.Scale.Y = axis(positionYUp, positionYDown)
+	c.Bias.Z, c.Scale.Z = axis(positionZUp, positionZDown)
+	return &c, nil
+}
+
+// minMagnetometerSamples is the least number of samples an ellipsoid can be fit to.
+const minMagnetometerSamples = 9
+
+// fitMagnetometer returns the calibration of a magnetometer from fields sampled while the
+// sensor was turned through as many orientations as possible. A magnetometer in a uniform
+// field reads points on an ellipsoid, whose center is the hard iron offset and whose shape is
+// the soft iron distortion; the calibration maps it back onto a sphere whose radius is the
+// geometric mean of the ellipsoid's semi-axes.
+func fitMagnetometer(samples []r3.Vector) (*MagnetometerCalibration, error) {
+	if len(samples) < minMagnetometerSamples {
+		return nil, errors.Errorf("at least %d samples are needed, got %d", minMagnetometerSamples, len(samples))
+	}
+	// fit the quadric x'Ax + 2b'x = 1 by least squares, with A symmetric.
+	design := mat.NewDense(len(samples), 9, nil)
+	ones := mat.NewVecDense(len(samples), nil)
+	for i, s := range samples {
+		design.SetRow(i, []float64{s.X * s.X, s.Y * s.Y, s.Z * s.Z, 2 * s.X * s.Y, 2 * s.X * s.Z, 2 * s.Y * s.Z, 2 * s.X, 2 * s.Y, 2 * s.Z})
+		ones.SetVec(i, 1)
+	}
+	var v mat.VecDense
+	if err := v.SolveVec(design, ones); err != nil {
+		return nil, errors.Wrap(err, "the samples do not cover enough orientations; turn the sensor through every direction")
+	}
+	a := mat.NewSymDense(3, []float64{
+		v.AtVec(0), v.AtVec(3), v.AtVec(4),
+		v.AtVec(3), v.AtVec(1), v.AtVec(5),
+		v.AtVec(4), v.AtVec(5), v.AtVec(2),
+	})
+	b := mat.NewVecDense(3, []float64{v.AtVec(6), v.AtVec(7), v.AtVec(8)})
+
+	// the center c = -A^-1 b, around which (x-c)'A(x-c) = 1 + c'Ac.
+	var center mat.VecDense
+	if err := center.SolveVec(a, b); err != nil {
+		return nil, errors.Wrap(err, "the samples do not lie on an ellipsoid")
+	}
+	center.ScaleVec(-1, &center)
+	var ac mat.VecDense
+	ac.MulVec(a, &center)
+	k := 1 + mat.Dot(&center, &ac)
+
+	var eigen mat.EigenSym
+	if !eigen.Factorize(a, true) {
+		return nil, errors.New("the samples do not lie on an ellipsoid")
+	}
+	values := eigen.Values(nil)
+	var vectors mat.Dense
+	eigen.VectorsTo(&vectors)
+	for i := range values {
+		values[i] /= k
+		if values[i] <= 0 {
+			return nil, errors.New("the samples do not lie on an ellipsoid; turn the sensor through every direction")
+		}
+	}
+	// the semi-axes are 1/sqrt(value), so their geometric mean is prod(value)^(-1/6).
+	radius := math.Pow(values[0]*values[1]*values[2], -1.0/6)
+	scale := mat.NewDiagDense(3, []float64{
+		radius * math.Sqrt(values[0]),
+		radius * math.Sqrt(values[1]),
+		radius * math.Sqrt(values[2]),
+	})
+	var softIron mat.Dense
+	softIron.Product(&vectors, scale, vectors.T())
+
+	c := &MagnetometerCalibration{HardIron: r3.Vector{X: center.AtVec(0), Y: center.AtVec(1), Z: center.AtVec(2)}}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			c.SoftIron[3*i+j] = softIron.At(i, j)
+		}
+	}
+	return c, nil
+}
@@ -3,6 +3,7 @@ package fake
 
 import (
 	"context"
+	"math"
 
 	"github.com/golang/geo/r3"
 	geo "github.com/kellydunn/golang-geo"
@@ -10,6 +11,7 @@ import (
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/simulation/imperfection"
 	"go.viam.com/rdk/spatialmath"
 )
 
@@ -17,7 +19,20 @@ var model = resource.DefaultModelFamily.WithModel("fake")
 
 // Config is used for converting fake movementsensor attributes.
 type Config struct {
-	resource.TriviallyValidateConfig
+	// Imperfections simulates the latency, noise, drift, and dropouts of a real movement
+	// sensor. Noise and drift apply to each value in its own units, except positions, which
+	// are offset in meters.
+	Imperfections *imperfection.Config `json:"imperfections,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Imperfections != nil {
+		if err := conf.Imperfections.Validate(path); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
 }
 
 func init() {
@@ -30,9 +45,14 @@ func init() {
 // NewMovementSensor makes a new fake movement sensor.
 func NewMovementSensor(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger,
 ) (movementsensor.MovementSensor, error) {
+	newConf, err := resource.NativeConfig[*Config](conf)
+	if err != nil {
+		return nil, err
+	}
 	return &MovementSensor{
-		Named:  conf.ResourceName().AsNamed(),
-		logger: logger,
+		Named:         conf.ResourceName().AsNamed(),
+		logger:        logger,
+		imperfections: newConf.Imperfections.NewInjector(),
 	}, nil
 }
 
@@ -40,40 +60,68 @@ func NewMovementSensor(ctx context.Context, deps resource.Dependencies, conf res
 type MovementSensor struct {
 	resource.Named
 	resource.AlwaysRebuild
-	logger logging.Logger
+	logger        logging.Logger
+	imperfections *imperfection.Injector
 }
 
 // Position gets the position of a fake movementsensor.
 func (f *MovementSensor) Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+	if err := f.imperfections.Call(ctx); err != nil {
+		return nil, 0, err
+	}
 	p := geo.NewPoint(40.7, -73.98)
-	return p, 50.5, nil
+	if f.imperfections.Perturbs() {
+		north, east := f.imperfections.Value(0), f.imperfections.Value(0)
+		bearing := math.Atan2(east, north) * 180 / math.Pi
+		p = p.PointAtDistanceAndBearing(math.Hypot(north, east)/1000, bearing)
+	}
+	return p, f.imperfections.Value(50.5), nil
 }
 
 // LinearVelocity gets the linear velocity of a fake movementsensor.
 func (f *MovementSensor) LinearVelocity(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
-	return r3.Vector{Y: 5.4}, nil
+	if err := f.imperfections.Call(ctx); err != nil {
+		return r3.Vector{}, err
+	}
+	return f.vector(r3.Vector{Y: 5.4}), nil
 }
 
 // LinearAcceleration gets the linear acceleration of a fake movementsensor.
 func (f *MovementSensor) LinearAcceleration(ctx context.Context, extra map[string]interface{}) (r3.Vector, error) {
-	return r3.Vector{X: 2.2, Y: 4.5, Z: 2}, nil
+	if err := f.imperfections.Call(ctx); err != nil {
+		return r3.Vector{}, err
+	}
+	return f.vector(r3.Vector{X: 2.2, Y: 4.5, Z: 2}), nil
 }
 
 // AngularVelocity gets the angular velocity of a fake movementsensor.
 func (f *MovementSensor) AngularVelocity(ctx context.Context, extra map[string]interface{}) (spatialmath.AngularVelocity, error) {
-	return spatialmath.AngularVelocity{Z: 1}, nil
+	if err := f.imperfections.Call(ctx); err != nil {
+		return spatialmath.AngularVelocity{}, err
+	}
+	return spatialmath.AngularVelocity(f.vector(r3.Vector{Z: 1})), nil
 }
 
 // CompassHeading gets the compass headings of a fake movementsensor.
 func (f *MovementSensor) CompassHeading(ctx context.Context, extra map[string]interface{}) (float64, error) {
-	return 25, nil
+	if err := f.imperfections.Call(ctx); err != nil {
+		return 0, err
+	}
+	return f.imperfections.Value(25), nil
 }
 
 // Orientation gets the orientation of a fake movementsensor.
 func (f *MovementSensor) Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+	if err := f.imperfections.Call(ctx); err != nil {
+		return nil, err
+	}
 	return spatialmath.NewZeroOrientation(), nil
 }
 
+func (f *MovementSensor) vector(v r3.Vector) r3.Vector {
+	return r3.Vector{X: f.imperfections.Value(v.X), Y: f.imperfections.Value(v.Y), Z: f.imperfections.Value(v.Z)}
+}
+
 // DoCommand uses a map string to run custom functionality of a fake movementsensor.
 func (f *MovementSensor) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return map[string]interface{}{}, nil
@@ -18,6 +18,7 @@ import (
 
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[MovementSensor]{
+		SelfTest:                    SelfTest,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterMovementSensorServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.MovementSensorService_ServiceDesc,
@@ -241,3 +242,9 @@ func UnimplementedOptionalAccuracies() *Accuracy {
 		CompassDegreeError: nan32Bit,
 	}
 }
+
+// SelfTest checks that the movement sensor is functional by reading from it once.
+func SelfTest(ctx context.Context, ms MovementSensor) error {
+	_, err := ms.Readings(ctx, nil)
+	return err
+}
@@ -7,4 +7,5 @@ import (
 	_ "go.viam.com/rdk/components/arm/register"
 	_ "go.viam.com/rdk/components/audioinput/register"
 	_ "go.viam.com/rdk/components/base/register"
+	_ "go.viam.com/rdk/simulation/gazebo"
 )
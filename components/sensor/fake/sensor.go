@@ -8,23 +8,44 @@ import (
 	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/simulation/script"
 )
 
 func init() {
 	resource.RegisterComponent(
 		sensor.API,
 		resource.DefaultModelFamily.WithModel("fake"),
-		resource.Registration[sensor.Sensor, resource.NoNativeConfig]{Constructor: func(
+		resource.Registration[sensor.Sensor, *Config]{Constructor: func(
 			ctx context.Context,
 			deps resource.Dependencies,
 			conf resource.Config,
 			logger logging.Logger,
 		) (sensor.Sensor, error) {
-			return newSensor(conf.ResourceName(), logger), nil
+			s := newSensor(conf.ResourceName(), logger)
+			if err := s.Reconfigure(ctx, deps, conf); err != nil {
+				return nil, err
+			}
+			return s, nil
 		}})
 }
 
-func newSensor(name resource.Name, logger logging.Logger) sensor.Sensor {
+// Config is the config of a fake sensor.
+type Config struct {
+	// Scenario scripts the readings over time.
+	Scenario *script.Config `json:"scenario,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) ([]string, error) {
+	if conf.Scenario != nil {
+		if err := conf.Scenario.Validate(path); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func newSensor(name resource.Name, logger logging.Logger) *Sensor {
 	return &Sensor{
 		Named:  name.AsNamed(),
 		logger: logger,
@@ -35,14 +56,32 @@ func newSensor(name resource.Name, logger logging.Logger) sensor.Sensor {
 type Sensor struct {
 	mu sync.Mutex
 	resource.Named
-	resource.TriviallyReconfigurable
 	resource.TriviallyCloseable
 	logger logging.Logger
+	player *script.Player
 }
 
-// Readings always returns the set values.
+// Reconfigure loads the configured scenario, if any, and starts playing it.
+func (s *Sensor) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	var player *script.Player
+	if newConf, ok := conf.ConvertedAttributes.(*Config); ok {
+		var err error
+		if player, err = newConf.Scenario.NewPlayer(); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.player = player
+	return nil
+}
+
+// Readings returns the scripted readings, or always the same values without a scenario.
 func (s *Sensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.player != nil {
+		return s.player.State().Readings, nil
+	}
 	return map[string]interface{}{"a": 1, "b": 2, "c": 3}, nil
 }
@@ -2,6 +2,8 @@
 package sensor
 
 import (
+	"context"
+
 	pb "go.viam.com/api/component/sensor/v1"
 
 	"go.viam.com/rdk/data"
@@ -11,6 +13,7 @@ import (
 
 func init() {
 	resource.RegisterAPI(API, resource.APIRegistration[Sensor]{
+		SelfTest:                    SelfTest,
 		RPCServiceServerConstructor: NewRPCServiceServer,
 		RPCServiceHandler:           pb.RegisterSensorServiceHandlerFromEndpoint,
 		RPCServiceDesc:              &pb.SensorService_ServiceDesc,
@@ -55,3 +58,9 @@ func FromRobot(r robot.Robot, name string) (Sensor, error) {
 func NamesFromRobot(r robot.Robot) []string {
 	return robot.NamesByAPI(r, API)
 }
+
+// SelfTest checks that the sensor is functional by reading from it once.
+func SelfTest(ctx context.Context, s Sensor) error {
+	_, err := s.Readings(ctx, nil)
+	return err
+}
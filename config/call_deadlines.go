@@ -0,0 +1,110 @@
+package config
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// DefaultCallDeadline is how long the calls served for a resource may take when neither its
+// operation timeout nor CallDeadlines bounds them, so that a call stuck on unresponsive
+// hardware is always canceled eventually.
+const DefaultCallDeadline = 10 * time.Minute
+
+// CallDeadlines bounds how long the calls served for the robot's resources may take, by API,
+// for the resources that are not configured with an operation timeout of their own.
+type CallDeadlines struct {
+	// Default bounds the calls to resources of APIs not listed in APIs. Defaults to
+	// DefaultCallDeadline.
+	Default time.Duration
+	// APIs bounds the calls to resources of each API (e.g. rdk:component:camera).
+	APIs map[string]time.Duration
+}
+
+// Note: keep this in sync with CallDeadlines.
+type callDeadlinesData struct {
+	Default string            `json:"default,omitempty"`
+	APIs    map[string]string `json:"apis,omitempty"`
+}
+
+// UnmarshalJSON unmarshals JSON data into these deadlines.
+func (d *CallDeadlines) UnmarshalJSON(data []byte) error {
+	var temp callDeadlinesData
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	*d = CallDeadlines{}
+	if temp.Default != "" {
+		dur, err := time.ParseDuration(temp.Default)
+		if err != nil {
+			return err
+		}
+		d.Default = dur
+	}
+	if len(temp.APIs) != 0 {
+		d.APIs = make(map[string]time.Duration, len(temp.APIs))
+	}
+	for api, value := range temp.APIs {
+		dur, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.Wrapf(err, "invalid deadline of %s", api)
+		}
+		d.APIs[api] = dur
+	}
+	return nil
+}
+
+// MarshalJSON marshals out these deadlines.
+func (d CallDeadlines) MarshalJSON() ([]byte, error) {
+	temp := callDeadlinesData{}
+	if d.Default != 0 {
+		temp.Default = d.Default.String()
+	}
+	if len(d.APIs) != 0 {
+		temp.APIs = make(map[string]string, len(d.APIs))
+	}
+	for api, dur := range d.APIs {
+		temp.APIs[api] = dur.String()
+	}
+	return json.Marshal(temp)
+}
+
+// Validate checks if the deadlines are valid.
+func (d *CallDeadlines) Validate(path string) error {
+	if d.Default < 0 {
+		return resource.NewConfigValidationError(path, errors.New("default must not be negative"))
+	}
+	for api, dur := range d.APIs {
+		if _, err := resource.NewAPIFromString(api); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrapf(err, "invalid API %q", api))
+		}
+		if dur <= 0 {
+			return resource.NewConfigValidationError(path, errors.Errorf("deadline of %s must be positive", api))
+		}
+	}
+	return nil
+}
+
+// Deadlines returns the deadline of calls to resources of each API, and the default deadline
+// of calls to resources of other APIs. Nil deadlines only have the default one.
+func (d *CallDeadlines) Deadlines() (map[resource.API]time.Duration, time.Duration) {
+	if d == nil {
+		return nil, DefaultCallDeadline
+	}
+	def := d.Default
+	if def == 0 {
+		def = DefaultCallDeadline
+	}
+	apis := make(map[resource.API]time.Duration, len(d.APIs))
+	for api, dur := range d.APIs {
+		parsed, err := resource.NewAPIFromString(api)
+		if err != nil {
+			continue
+		}
+		apis[parsed] = dur
+	}
+	return apis, def
+}
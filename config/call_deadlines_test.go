@@ -0,0 +1,59 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+func TestCallDeadlines(t *testing.T) {
+	var cfg Config
+	test.That(t, json.Unmarshal([]byte(`{
+		"call_deadlines": {
+			"default": "1m",
+			"apis": {"rdk:component:camera": "5s"}
+		}
+	}`), &cfg), test.ShouldBeNil)
+	test.That(t, cfg.CallDeadlines, test.ShouldResemble, &CallDeadlines{
+		Default: time.Minute,
+		APIs:    map[string]time.Duration{"rdk:component:camera": 5 * time.Second},
+	})
+	test.That(t, cfg.CallDeadlines.Validate("call_deadlines"), test.ShouldBeNil)
+	apis, def := cfg.CallDeadlines.Deadlines()
+	test.That(t, def, test.ShouldEqual, time.Minute)
+	test.That(t, apis, test.ShouldResemble, map[resource.API]time.Duration{
+		resource.APINamespaceRDK.WithComponentType("camera"): 5 * time.Second,
+	})
+
+	data, err := json.Marshal(cfg)
+	test.That(t, err, test.ShouldBeNil)
+	var roundTripped Config
+	test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+	test.That(t, roundTripped.CallDeadlines, test.ShouldResemble, cfg.CallDeadlines)
+
+	// calls are always bounded.
+	var unset *CallDeadlines
+	apis, def = unset.Deadlines()
+	test.That(t, apis, test.ShouldBeEmpty)
+	test.That(t, def, test.ShouldEqual, DefaultCallDeadline)
+
+	for _, tc := range []struct {
+		name      string
+		deadlines CallDeadlines
+		errStr    string
+	}{
+		{"negative default", CallDeadlines{Default: -1}, "default"},
+		{"bad api", CallDeadlines{APIs: map[string]time.Duration{"camera": time.Second}}, "invalid API"},
+		{"zero deadline", CallDeadlines{APIs: map[string]time.Duration{"rdk:component:camera": 0}}, "must be positive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.deadlines.Validate("call_deadlines")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
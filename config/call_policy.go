@@ -0,0 +1,145 @@
+package config
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A RemoteCallPolicy describes how the calls made to the resources of a remote are timed
+// out, retried, and hedged, so that a brief loss of the connection to the remote does not
+// fail every call made to it. Only idempotent calls, which read state without changing it,
+// are covered by the policy, so that actuation is never repeated.
+type RemoteCallPolicy struct {
+	// AttemptTimeout bounds each attempt of a call, so that a hung attempt is retried. Zero
+	// means attempts are only bounded by the call.
+	AttemptTimeout time.Duration
+	// MaxAttempts is how many times a call is attempted at most, counting hedged attempts.
+	// Zero means calls are attempted once.
+	MaxAttempts int
+	// InitialBackoff is how long to wait before retrying a failed call the first time, doubled
+	// for every retry after up to MaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// HedgeDelay, if set, is how long to wait for an attempt before making another alongside
+	// it, using the result of whichever succeeds first.
+	HedgeDelay time.Duration
+	// RetryCodes are the names of the status codes of the failed attempts that are retried
+	// (e.g. UNAVAILABLE). Defaults to UNAVAILABLE and DEADLINE_EXCEEDED.
+	RetryCodes []string
+	// IdempotentMethods names the methods (e.g. Stop) that are idempotent besides those whose
+	// names start with Get, Is, List, or Read.
+	IdempotentMethods []string
+}
+
+// Note: keep this in sync with RemoteCallPolicy.
+type remoteCallPolicyData struct {
+	AttemptTimeout    string   `json:"attempt_timeout,omitempty"`
+	MaxAttempts       int      `json:"max_attempts,omitempty"`
+	InitialBackoff    string   `json:"initial_backoff,omitempty"`
+	MaxBackoff        string   `json:"max_backoff,omitempty"`
+	HedgeDelay        string   `json:"hedge_delay,omitempty"`
+	RetryCodes        []string `json:"retry_codes,omitempty"`
+	IdempotentMethods []string `json:"idempotent_methods,omitempty"`
+}
+
+// UnmarshalJSON unmarshals JSON data into this policy.
+func (p *RemoteCallPolicy) UnmarshalJSON(data []byte) error {
+	var temp remoteCallPolicyData
+	if err := json.Unmarshal(data, &temp); err != nil {
+		return err
+	}
+	*p = RemoteCallPolicy{
+		MaxAttempts:       temp.MaxAttempts,
+		RetryCodes:        temp.RetryCodes,
+		IdempotentMethods: temp.IdempotentMethods,
+	}
+	for _, field := range []struct {
+		value string
+		dst   *time.Duration
+	}{
+		{temp.AttemptTimeout, &p.AttemptTimeout},
+		{temp.InitialBackoff, &p.InitialBackoff},
+		{temp.MaxBackoff, &p.MaxBackoff},
+		{temp.HedgeDelay, &p.HedgeDelay},
+	} {
+		if field.value == "" {
+			continue
+		}
+		dur, err := time.ParseDuration(field.value)
+		if err != nil {
+			return err
+		}
+		*field.dst = dur
+	}
+	return nil
+}
+
+// MarshalJSON marshals out this policy.
+func (p RemoteCallPolicy) MarshalJSON() ([]byte, error) {
+	temp := remoteCallPolicyData{
+		MaxAttempts:       p.MaxAttempts,
+		RetryCodes:        p.RetryCodes,
+		IdempotentMethods: p.IdempotentMethods,
+	}
+	durationString := func(dur time.Duration) string {
+		if dur == 0 {
+			return ""
+		}
+		return dur.String()
+	}
+	temp.AttemptTimeout = durationString(p.AttemptTimeout)
+	temp.InitialBackoff = durationString(p.InitialBackoff)
+	temp.MaxBackoff = durationString(p.MaxBackoff)
+	temp.HedgeDelay = durationString(p.HedgeDelay)
+	return json.Marshal(temp)
+}
+
+// Validate checks if the policy is valid.
+func (p *RemoteCallPolicy) Validate(path string) error {
+	if p.AttemptTimeout < 0 {
+		return resource.NewConfigValidationError(path, errors.New("attempt_timeout must not be negative"))
+	}
+	if p.MaxAttempts < 0 {
+		return resource.NewConfigValidationError(path, errors.New("max_attempts must not be negative"))
+	}
+	if p.InitialBackoff < 0 || p.MaxBackoff < 0 {
+		return resource.NewConfigValidationError(path, errors.New("initial_backoff and max_backoff must not be negative"))
+	}
+	if p.MaxBackoff != 0 && p.MaxBackoff < p.InitialBackoff {
+		return resource.NewConfigValidationError(path, errors.New("max_backoff must not be less than initial_backoff"))
+	}
+	if p.HedgeDelay < 0 {
+		return resource.NewConfigValidationError(path, errors.New("hedge_delay must not be negative"))
+	}
+	if _, err := p.StatusCodes(); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	for _, method := range p.IdempotentMethods {
+		if method == "" {
+			return resource.NewConfigValidationError(path, errors.New("idempotent_methods must not be empty"))
+		}
+	}
+	return nil
+}
+
+// StatusCodes returns the status codes of the failed attempts that are retried.
+func (p *RemoteCallPolicy) StatusCodes() ([]codes.Code, error) {
+	if len(p.RetryCodes) == 0 {
+		return []codes.Code{codes.Unavailable, codes.DeadlineExceeded}, nil
+	}
+	statusCodes := make([]codes.Code, 0, len(p.RetryCodes))
+	for _, name := range p.RetryCodes {
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+			return nil, errors.Errorf("unknown status code %q", name)
+		}
+		statusCodes = append(statusCodes, code)
+	}
+	return statusCodes, nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+)
+
+func TestRemoteCallPolicy(t *testing.T) {
+	var remote Remote
+	test.That(t, json.Unmarshal([]byte(`{
+		"name": "other",
+		"address": "other.local:8080",
+		"call_policy": {
+			"attempt_timeout": "500ms",
+			"max_attempts": 3,
+			"initial_backoff": "20ms",
+			"hedge_delay": "100ms",
+			"idempotent_methods": ["Stop"]
+		}
+	}`), &remote), test.ShouldBeNil)
+	test.That(t, remote.CallPolicy, test.ShouldResemble, &RemoteCallPolicy{
+		AttemptTimeout:    500 * time.Millisecond,
+		MaxAttempts:       3,
+		InitialBackoff:    20 * time.Millisecond,
+		HedgeDelay:        100 * time.Millisecond,
+		IdempotentMethods: []string{"Stop"},
+	})
+	_, err := remote.Validate("remotes.0")
+	test.That(t, err, test.ShouldBeNil)
+	statusCodes, err := remote.CallPolicy.StatusCodes()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statusCodes, test.ShouldResemble, []codes.Code{codes.Unavailable, codes.DeadlineExceeded})
+
+	data, err := json.Marshal(remote)
+	test.That(t, err, test.ShouldBeNil)
+	var roundTripped Remote
+	test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+	test.That(t, roundTripped.CallPolicy, test.ShouldResemble, remote.CallPolicy)
+
+	for _, tc := range []struct {
+		name   string
+		policy RemoteCallPolicy
+		errStr string
+	}{
+		{"negative timeout", RemoteCallPolicy{AttemptTimeout: -1}, "attempt_timeout"},
+		{"negative attempts", RemoteCallPolicy{MaxAttempts: -1}, "max_attempts"},
+		{"backoff order", RemoteCallPolicy{InitialBackoff: time.Second, MaxBackoff: time.Millisecond}, "max_backoff"},
+		{"negative hedge", RemoteCallPolicy{HedgeDelay: -1}, "hedge_delay"},
+		{"bad code", RemoteCallPolicy{RetryCodes: []string{"OOPS"}}, "unknown status code"},
+		{"empty method", RemoteCallPolicy{IdempotentMethods: []string{""}}, "idempotent_methods"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.policy.Validate("remotes.0.call_policy")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
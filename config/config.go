@@ -25,6 +25,14 @@ import (
 	rutils "go.viam.com/rdk/utils"
 )
 
+// DefaultMaxRemoteDepth is how many remotes deep the resources of remotes of remotes are
+// reached through when Config.MaxRemoteDepth is not set.
+const DefaultMaxRemoteDepth = 5
+
+// DefaultResourceRetryInterval is how often the resources that failed to build are retried
+// when Config.ResourceRetryInterval is not set.
+const DefaultResourceRetryInterval = 5 * time.Second
+
 // A Config describes the configuration of a robot.
 type Config struct {
 	Cloud           *Cloud
@@ -34,10 +42,36 @@ type Config struct {
 	Processes       []pexec.ProcessConfig
 	Services        []resource.Config
 	Packages        []PackageConfig
-	Network         NetworkConfig
-	Auth            AuthConfig
-	Debug           bool
-	GlobalLogConfig []GlobalLogConfig
+	Firmware        []FirmwareConfig
+	Faults          []FaultConfig
+	SelfTest        *SelfTestConfig
+	ControlLoops    []ControlLoopConfig
+	MapTiles        []MapTileAreaConfig
+	Obstacles       []ObstacleConfig
+	SecretProviders []SecretProviderConfig
+	RemoteDiscovery *RemoteDiscoveryConfig
+	Profiles        []ProfileConfig
+	// Profile is the profile the robot switches to when configured. The robot keeps the
+	// profile it is switched to at runtime until this changes.
+	Profile string
+	// MaxRemoteDepth bounds how many remotes deep the resources of remotes of remotes are
+	// reached through. Defaults to DefaultMaxRemoteDepth.
+	MaxRemoteDepth int
+	// CallDeadlines bounds how long the calls served for resources without an operation
+	// timeout may take. Calls are bounded by DefaultCallDeadline if unset.
+	CallDeadlines *CallDeadlines
+	// Version is the version of the config format the config is written for. Configs of older
+	// versions are upgraded by the registered migrations when read, keeping their version.
+	Version int
+	// ResourceRetryInterval is how often the resources that failed to build, such as a camera
+	// whose USB device is not yet enumerated, are retried in the background until they build.
+	// Changes to remotes are also checked for this often. Defaults to
+	// DefaultResourceRetryInterval.
+	ResourceRetryInterval time.Duration
+	Network               NetworkConfig
+	Auth                  AuthConfig
+	Debug                 bool
+	GlobalLogConfig       []GlobalLogConfig
 
 	ConfigFilePath string
 
@@ -57,7 +91,8 @@ type Config struct {
 	FromCommand bool
 
 	// DisablePartialStart ensures that a robot will only start when all the components,
-	// services, and remotes pass config validation. This value is false by default
+	// services, and remotes pass config validation, and all the components and services not
+	// marked optional are built. This value is false by default
 	DisablePartialStart bool
 
 	// PackagePath sets the directory used to store packages locally. Defaults to ~/.viam/packages
@@ -65,23 +100,54 @@ type Config struct {
 
 	// EnableWebProfile turns pprof http server in localhost. Defaults to false.
 	EnableWebProfile bool
+
+	// ReadOnly starts the robot rejecting the requests to it that would actuate its resources,
+	// such as moving an arm, powering a motor or any DoCommand, while sensing and streaming work
+	// normally, so that observers can safely connect to a production robot. It takes effect when
+	// the robot starts.
+	ReadOnly bool
+
+	// Simulation starts the robot with simulated backends, such as fake boards and arms with
+	// only their kinematics, in place of the hardware of the models that register them, keeping
+	// the names and frames of the resources. It takes effect when the robot starts.
+	Simulation bool
+
+	// secretReferences maps the paths of the secrets resolved by ResolveSecrets to the
+	// references they were resolved from.
+	secretReferences map[string]secretReference
 }
 
 // NOTE: This data must be maintained with what is in Config.
 type configData struct {
-	Cloud               *Cloud                `json:"cloud,omitempty"`
-	Modules             []Module              `json:"modules,omitempty"`
-	Remotes             []Remote              `json:"remotes,omitempty"`
-	Components          []resource.Config     `json:"components,omitempty"`
-	Processes           []pexec.ProcessConfig `json:"processes,omitempty"`
-	Services            []resource.Config     `json:"services,omitempty"`
-	Packages            []PackageConfig       `json:"packages,omitempty"`
-	Network             NetworkConfig         `json:"network"`
-	Auth                AuthConfig            `json:"auth"`
-	Debug               bool                  `json:"debug,omitempty"`
-	DisablePartialStart bool                  `json:"disable_partial_start"`
-	EnableWebProfile    bool                  `json:"enable_web_profile"`
-	GlobalLogConfig     []GlobalLogConfig     `json:"global_log_configuration"`
+	Cloud                 *Cloud                 `json:"cloud,omitempty"`
+	Modules               []Module               `json:"modules,omitempty"`
+	Remotes               []Remote               `json:"remotes,omitempty"`
+	Components            []resource.Config      `json:"components,omitempty"`
+	Processes             []pexec.ProcessConfig  `json:"processes,omitempty"`
+	Services              []resource.Config      `json:"services,omitempty"`
+	Packages              []PackageConfig        `json:"packages,omitempty"`
+	Firmware              []FirmwareConfig       `json:"firmware,omitempty"`
+	Faults                []FaultConfig          `json:"faults,omitempty"`
+	SelfTest              *SelfTestConfig        `json:"self_test,omitempty"`
+	CallDeadlines         *CallDeadlines         `json:"call_deadlines,omitempty"`
+	Version               int                    `json:"version,omitempty"`
+	ControlLoops          []ControlLoopConfig    `json:"control_loops,omitempty"`
+	MapTiles              []MapTileAreaConfig    `json:"map_tiles,omitempty"`
+	Obstacles             []ObstacleConfig       `json:"obstacles,omitempty"`
+	SecretProviders       []SecretProviderConfig `json:"secret_providers,omitempty"`
+	RemoteDiscovery       *RemoteDiscoveryConfig `json:"remote_discovery,omitempty"`
+	Profiles              []ProfileConfig        `json:"profiles,omitempty"`
+	Profile               string                 `json:"profile,omitempty"`
+	MaxRemoteDepth        int                    `json:"max_remote_depth,omitempty"`
+	ResourceRetryInterval string                 `json:"resource_retry_interval,omitempty"`
+	Network               NetworkConfig          `json:"network"`
+	Auth                  AuthConfig             `json:"auth"`
+	Debug                 bool                   `json:"debug,omitempty"`
+	DisablePartialStart   bool                   `json:"disable_partial_start"`
+	ReadOnly              bool                   `json:"read_only,omitempty"`
+	Simulation            bool                   `json:"simulation,omitempty"`
+	EnableWebProfile      bool                   `json:"enable_web_profile"`
+	GlobalLogConfig       []GlobalLogConfig      `json:"global_log_configuration"`
 }
 
 // AppValidationStatus refers to the.
@@ -101,11 +167,43 @@ func (c *Config) validateUniqueResource(logger logging.Logger, seenResources map
 	return nil
 }
 
+// selectConditionalResources returns the resources whose when conditions match the robot,
+// leaving out the others so that resources of the same name may be configured for different
+// robots.
+func (c *Config) selectConditionalResources(confs []resource.Config, kind string, logger logging.Logger) ([]resource.Config, error) {
+	selected := confs[:0]
+	for _, conf := range confs {
+		matches, err := conf.When.Matches()
+		if err != nil {
+			err = errors.Wrapf(err, "error evaluating when condition of %s %s", kind, conf.Name)
+			if c.DisablePartialStart && !conf.Optional {
+				return nil, err
+			}
+			logger.Errorw(kind+" when condition error; starting robot without "+kind, "name", conf.Name, "error", err)
+			continue
+		}
+		if !matches {
+			logger.Debugw(kind+" when condition does not match; leaving it out of the config", "name", conf.Name)
+			continue
+		}
+		selected = append(selected, conf)
+	}
+	return selected, nil
+}
+
 // Ensure ensures all parts of the config are valid, which may include updating it. Only returns an error
 // if c.DisablePartialStart is true (default: false).
 func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 	seenResources := make(map[string]bool)
 
+	var err error
+	if c.Components, err = c.selectConditionalResources(c.Components, "component", logger); err != nil {
+		return err
+	}
+	if c.Services, err = c.selectConditionalResources(c.Services, "service", logger); err != nil {
+		return err
+	}
+
 	if c.Cloud != nil {
 		// Adds default for RefreshInterval if not set.
 		if err := c.Cloud.Validate("cloud", fromCloud); err != nil {
@@ -159,7 +257,7 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		dependsOn, err := component.Validate(fmt.Sprintf("%s.%d", "components", idx), resource.APITypeComponentName)
 		if err != nil {
 			fullErr := errors.Wrapf(err, "error validating component %s: %s", component.Name, err)
-			if c.DisablePartialStart {
+			if c.DisablePartialStart && !component.Optional {
 				return fullErr
 			}
 			resLogger := logger.Sublogger(component.ResourceName().String())
@@ -194,7 +292,7 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		// dependencies are gathered during robot reconfiguration itself.
 		dependsOn, err := service.Validate(fmt.Sprintf("%s.%d", "services", idx), resource.APITypeServiceName)
 		if err != nil {
-			if c.DisablePartialStart {
+			if c.DisablePartialStart && !service.Optional {
 				return err
 			}
 			resLogger := logger.Sublogger(service.ResourceName().String())
@@ -221,6 +319,175 @@ func (c *Config) Ensure(fromCloud bool, logger logging.Logger) error {
 		}
 	}
 
+	for idx := 0; idx < len(c.Firmware); idx++ {
+		if err := c.Firmware[idx].Validate(fmt.Sprintf("%s.%d", "firmware", idx)); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("firmware config error; starting robot without firmware update", "name", c.Firmware[idx].Name, "error", err)
+		}
+	}
+
+	for idx := 0; idx < len(c.Faults); idx++ {
+		if err := c.Faults[idx].Validate(fmt.Sprintf("%s.%d", "faults", idx)); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("fault config error; starting robot without fault", "resource", c.Faults[idx].Resource, "error", err)
+		}
+	}
+
+	if c.SelfTest != nil {
+		if err := c.SelfTest.Validate("self_test"); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("self-test config error; starting robot without self-test", "error", err)
+			c.SelfTest = nil
+		}
+	}
+
+	if c.CallDeadlines != nil {
+		if err := c.CallDeadlines.Validate("call_deadlines"); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("call deadlines config error; starting robot with the default call deadline", "error", err)
+			c.CallDeadlines = nil
+		}
+	}
+
+	seenControlLoops := map[string]bool{}
+	validControlLoops := c.ControlLoops[:0]
+	for idx, loop := range c.ControlLoops {
+		err := loop.Validate(fmt.Sprintf("%s.%d", "control_loops", idx))
+		if err == nil && seenControlLoops[loop.Name] {
+			err = errors.Errorf("duplicate control loop %s in robot config", loop.Name)
+		}
+		if err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("control loop config error; starting robot without control loop", "name", loop.Name, "error", err)
+			continue
+		}
+		seenControlLoops[loop.Name] = true
+		validControlLoops = append(validControlLoops, loop)
+	}
+	c.ControlLoops = validControlLoops
+
+	seenMapTileAreas := map[string]bool{}
+	validMapTileAreas := c.MapTiles[:0]
+	for idx, area := range c.MapTiles {
+		err := area.Validate(fmt.Sprintf("%s.%d", "map_tiles", idx))
+		if err == nil && seenMapTileAreas[area.Name] {
+			err = errors.Errorf("duplicate map tile area %s in robot config", area.Name)
+		}
+		if err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("map tile area config error; starting robot without map tile area", "name", area.Name, "error", err)
+			continue
+		}
+		seenMapTileAreas[area.Name] = true
+		validMapTileAreas = append(validMapTileAreas, area)
+	}
+	c.MapTiles = validMapTileAreas
+
+	seenObstacles := map[string]bool{}
+	validObstacles := c.Obstacles[:0]
+	for idx, obstacles := range c.Obstacles {
+		err := obstacles.Validate(fmt.Sprintf("%s.%d", "obstacles", idx))
+		if err == nil && seenObstacles[obstacles.Name] {
+			err = errors.Errorf("duplicate obstacles %s in robot config", obstacles.Name)
+		}
+		if err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("obstacles config error; starting robot without obstacles", "name", obstacles.Name, "error", err)
+			continue
+		}
+		seenObstacles[obstacles.Name] = true
+		validObstacles = append(validObstacles, obstacles)
+	}
+	c.Obstacles = validObstacles
+
+	seenSecretProviders := map[string]bool{}
+	validSecretProviders := c.SecretProviders[:0]
+	for idx, provider := range c.SecretProviders {
+		err := provider.Validate(fmt.Sprintf("%s.%d", "secret_providers", idx))
+		if err == nil && seenSecretProviders[provider.Name] {
+			err = errors.Errorf("duplicate secret provider %s in robot config", provider.Name)
+		}
+		if err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("secret provider config error; starting robot without secret provider", "name", provider.Name, "error", err)
+			continue
+		}
+		seenSecretProviders[provider.Name] = true
+		validSecretProviders = append(validSecretProviders, provider)
+	}
+	c.SecretProviders = validSecretProviders
+
+	if c.RemoteDiscovery != nil {
+		if err := c.RemoteDiscovery.Validate("remote_discovery"); err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("remote discovery config error; starting robot without remote discovery", "error", err)
+			c.RemoteDiscovery = nil
+		}
+	}
+
+	seenProfiles := map[string]bool{}
+	validProfiles := c.Profiles[:0]
+	for idx, profile := range c.Profiles {
+		err := profile.Validate(fmt.Sprintf("%s.%d", "profiles", idx))
+		if err == nil && seenProfiles[profile.Name] {
+			err = errors.Errorf("duplicate profile %s in robot config", profile.Name)
+		}
+		if err != nil {
+			if c.DisablePartialStart {
+				return err
+			}
+			logger.Errorw("profile config error; starting robot without profile", "name", profile.Name, "error", err)
+			continue
+		}
+		seenProfiles[profile.Name] = true
+		validProfiles = append(validProfiles, profile)
+	}
+	c.Profiles = validProfiles
+	if c.Profile != "" && !seenProfiles[c.Profile] {
+		err := resource.NewConfigValidationError("profile", errors.Errorf("no profile named %q", c.Profile))
+		if c.DisablePartialStart {
+			return err
+		}
+		logger.Errorw("profile config error; starting robot without a profile", "error", err)
+		c.Profile = ""
+	}
+
+	if c.MaxRemoteDepth < 0 {
+		err := resource.NewConfigValidationError("max_remote_depth", errors.New("must not be negative"))
+		if c.DisablePartialStart {
+			return err
+		}
+		logger.Errorw("remote depth config error; starting robot with the default max remote depth", "error", err)
+		c.MaxRemoteDepth = 0
+	}
+
+	if c.ResourceRetryInterval < 0 {
+		err := resource.NewConfigValidationError("resource_retry_interval", errors.New("must not be negative"))
+		if c.DisablePartialStart {
+			return err
+		}
+		logger.Errorw("resource retry interval config error; starting robot with the default resource retry interval", "error", err)
+		c.ResourceRetryInterval = 0
+	}
+
 	for idx, globalLogConfig := range c.GlobalLogConfig {
 		if err := globalLogConfig.Validate(fmt.Sprintf("global_log_configuration.%d", idx)); err != nil {
 			logger.Errorw("log configuration error", "err", err)
@@ -264,10 +531,32 @@ func (c *Config) UnmarshalJSON(data []byte) error {
 	c.Processes = conf.Processes
 	c.Services = conf.Services
 	c.Packages = conf.Packages
+	c.Firmware = conf.Firmware
+	c.Faults = conf.Faults
+	c.SelfTest = conf.SelfTest
+	c.CallDeadlines = conf.CallDeadlines
+	c.Version = conf.Version
+	c.ControlLoops = conf.ControlLoops
+	c.MapTiles = conf.MapTiles
+	c.Obstacles = conf.Obstacles
+	c.SecretProviders = conf.SecretProviders
+	c.RemoteDiscovery = conf.RemoteDiscovery
+	c.Profiles = conf.Profiles
+	c.Profile = conf.Profile
+	c.MaxRemoteDepth = conf.MaxRemoteDepth
+	if conf.ResourceRetryInterval != "" {
+		dur, err := time.ParseDuration(conf.ResourceRetryInterval)
+		if err != nil {
+			return err
+		}
+		c.ResourceRetryInterval = dur
+	}
 	c.Network = conf.Network
 	c.Auth = conf.Auth
 	c.Debug = conf.Debug
 	c.DisablePartialStart = conf.DisablePartialStart
+	c.ReadOnly = conf.ReadOnly
+	c.Simulation = conf.Simulation
 	c.EnableWebProfile = conf.EnableWebProfile
 	c.GlobalLogConfig = conf.GlobalLogConfig
 
@@ -286,20 +575,40 @@ func (c Config) MarshalJSON() ([]byte, error) {
 		c.Remotes[idx].adjustPartialNames()
 	}
 
+	var resourceRetryInterval string
+	if c.ResourceRetryInterval != 0 {
+		resourceRetryInterval = c.ResourceRetryInterval.String()
+	}
 	return json.Marshal(configData{
-		Cloud:               c.Cloud,
-		Modules:             c.Modules,
-		Remotes:             c.Remotes,
-		Components:          c.Components,
-		Processes:           c.Processes,
-		Services:            c.Services,
-		Packages:            c.Packages,
-		Network:             c.Network,
-		Auth:                c.Auth,
-		Debug:               c.Debug,
-		DisablePartialStart: c.DisablePartialStart,
-		EnableWebProfile:    c.EnableWebProfile,
-		GlobalLogConfig:     c.GlobalLogConfig,
+		Cloud:                 c.Cloud,
+		Modules:               c.Modules,
+		Remotes:               c.Remotes,
+		Components:            c.Components,
+		Processes:             c.Processes,
+		Services:              c.Services,
+		Packages:              c.Packages,
+		Firmware:              c.Firmware,
+		Faults:                c.Faults,
+		SelfTest:              c.SelfTest,
+		CallDeadlines:         c.CallDeadlines,
+		Version:               c.Version,
+		ControlLoops:          c.ControlLoops,
+		MapTiles:              c.MapTiles,
+		Obstacles:             c.Obstacles,
+		SecretProviders:       c.SecretProviders,
+		RemoteDiscovery:       c.RemoteDiscovery,
+		Profiles:              c.Profiles,
+		Profile:               c.Profile,
+		MaxRemoteDepth:        c.MaxRemoteDepth,
+		ResourceRetryInterval: resourceRetryInterval,
+		Network:               c.Network,
+		Auth:                  c.Auth,
+		Debug:                 c.Debug,
+		DisablePartialStart:   c.DisablePartialStart,
+		ReadOnly:              c.ReadOnly,
+		Simulation:            c.Simulation,
+		EnableWebProfile:      c.EnableWebProfile,
+		GlobalLogConfig:       c.GlobalLogConfig,
 	})
 }
 
@@ -324,6 +633,13 @@ func (c *Config) CopyOnlyPublicFields() (*Config, error) {
 // The Frame field defines how the "world" node of the remote robot should be reconciled with the "world" node of
 // the current robot. All components of the remote robot who have Parent as "world" will be attached to the parent defined
 // in Frame, and with the given offset as well.
+// When MaxReconnectInterval is set, failed attempts to reconnect the remote back off exponentially
+// from ReconnectInterval up to it.
+// When CacheMetadata is set, the names and frame system of the remote's resources are persisted
+// locally while it is connected, and the robot serves the last known frame system of the remote
+// while it cannot connect to it, such as when the remote is unreachable when the robot starts.
+// When CallPolicy is set, the idempotent calls made to the remote's resources are timed out,
+// retried, and hedged as it describes.
 type Remote struct {
 	Name                      string
 	Address                   string
@@ -333,6 +649,9 @@ type Remote struct {
 	Insecure                  bool
 	ConnectionCheckInterval   time.Duration
 	ReconnectInterval         time.Duration
+	MaxReconnectInterval      time.Duration
+	CacheMetadata             bool
+	CallPolicy                *RemoteCallPolicy
 	AssociatedResourceConfigs []resource.AssociatedResourceConfig
 
 	// Secret is a helper for a robot location secret.
@@ -352,6 +671,9 @@ type remoteData struct {
 	Insecure                  bool                                `json:"insecure"`
 	ConnectionCheckInterval   string                              `json:"connection_check_interval,omitempty"`
 	ReconnectInterval         string                              `json:"reconnect_interval,omitempty"`
+	MaxReconnectInterval      string                              `json:"max_reconnect_interval,omitempty"`
+	CacheMetadata             bool                                `json:"cache_metadata,omitempty"`
+	CallPolicy                *RemoteCallPolicy                   `json:"call_policy,omitempty"`
 	AssociatedResourceConfigs []resource.AssociatedResourceConfig `json:"service_configs"`
 
 	// Secret is a helper for a robot location secret.
@@ -381,6 +703,8 @@ func (conf *Remote) UnmarshalJSON(data []byte) error {
 		Auth:                      temp.Auth,
 		ManagedBy:                 temp.ManagedBy,
 		Insecure:                  temp.Insecure,
+		CacheMetadata:             temp.CacheMetadata,
+		CallPolicy:                temp.CallPolicy,
 		AssociatedResourceConfigs: temp.AssociatedResourceConfigs,
 		Secret:                    temp.Secret,
 	}
@@ -398,6 +722,13 @@ func (conf *Remote) UnmarshalJSON(data []byte) error {
 		}
 		conf.ReconnectInterval = dur
 	}
+	if temp.MaxReconnectInterval != "" {
+		dur, err := time.ParseDuration(temp.MaxReconnectInterval)
+		if err != nil {
+			return err
+		}
+		conf.MaxReconnectInterval = dur
+	}
 	return nil
 }
 
@@ -410,6 +741,8 @@ func (conf Remote) MarshalJSON() ([]byte, error) {
 		Auth:                      conf.Auth,
 		ManagedBy:                 conf.ManagedBy,
 		Insecure:                  conf.Insecure,
+		CacheMetadata:             conf.CacheMetadata,
+		CallPolicy:                conf.CallPolicy,
 		AssociatedResourceConfigs: conf.AssociatedResourceConfigs,
 		Secret:                    conf.Secret,
 	}
@@ -419,6 +752,9 @@ func (conf Remote) MarshalJSON() ([]byte, error) {
 	if conf.ReconnectInterval != 0 {
 		temp.ReconnectInterval = conf.ReconnectInterval.String()
 	}
+	if conf.MaxReconnectInterval != 0 {
+		temp.MaxReconnectInterval = conf.MaxReconnectInterval.String()
+	}
 	return json.Marshal(temp)
 }
 
@@ -475,6 +811,15 @@ func (conf *Remote) validate(path string) error {
 			return resource.NewConfigValidationFieldRequiredError(path, "frame.parent")
 		}
 	}
+	if conf.MaxReconnectInterval < 0 || (conf.MaxReconnectInterval != 0 && conf.MaxReconnectInterval < conf.ReconnectInterval) {
+		return resource.NewConfigValidationError(path,
+			errors.New("max_reconnect_interval must not be less than reconnect_interval"))
+	}
+	if conf.CallPolicy != nil {
+		if err := conf.CallPolicy.Validate(path + ".call_policy"); err != nil {
+			return err
+		}
+	}
 
 	if conf.Secret != "" {
 		conf.Auth = RemoteAuth{
@@ -711,15 +1056,39 @@ func (nc *NetworkConfig) Validate(path string) error {
 }
 
 // SessionsConfig configures various parameters used in session management.
+//
+// The session limits bound only the sessions clients start, not their connections: a client
+// connected without a session is not counted, and a client holding sessions over several
+// connections is counted once per session.
 type SessionsConfig struct {
 	// HeartbeatWindow is the window within which clients must send at least one
 	// heartbeat in order to keep a session alive.
 	HeartbeatWindow time.Duration
+
+	// IdleTimeout is how long a session may go without any non-heartbeat
+	// request before it is torn down. Zero disables idle expiry.
+	IdleTimeout time.Duration
+
+	// SessionLimit limits the total number of concurrent sessions. Zero uses
+	// the built in default.
+	SessionLimit int
+
+	// SessionLimitPerCredential limits the number of concurrent sessions a single
+	// authenticated entity (credential) may hold. Zero means no limit.
+	SessionLimitPerCredential int
+
+	// SessionLimitPerIP limits the number of concurrent sessions that may
+	// originate from a single remote IP address. Zero means no limit.
+	SessionLimitPerIP int
 }
 
 // Note: keep this in sync with SessionsConfig.
 type sessionsConfigData struct {
-	HeartbeatWindow string `json:"heartbeat_window,omitempty"`
+	HeartbeatWindow           string `json:"heartbeat_window,omitempty"`
+	IdleTimeout               string `json:"idle_timeout,omitempty"`
+	SessionLimit              int    `json:"session_limit,omitempty"`
+	SessionLimitPerCredential int    `json:"session_limit_per_credential,omitempty"`
+	SessionLimitPerIP         int    `json:"session_limit_per_ip,omitempty"`
 }
 
 // UnmarshalJSON unmarshals JSON data into this config.
@@ -735,15 +1104,32 @@ func (sc *SessionsConfig) UnmarshalJSON(data []byte) error {
 		}
 		sc.HeartbeatWindow = dur
 	}
+	if temp.IdleTimeout != "" {
+		dur, err := time.ParseDuration(temp.IdleTimeout)
+		if err != nil {
+			return err
+		}
+		sc.IdleTimeout = dur
+	}
+	sc.SessionLimit = temp.SessionLimit
+	sc.SessionLimitPerCredential = temp.SessionLimitPerCredential
+	sc.SessionLimitPerIP = temp.SessionLimitPerIP
 	return nil
 }
 
 // MarshalJSON marshals out this config.
 func (sc SessionsConfig) MarshalJSON() ([]byte, error) {
-	var temp sessionsConfigData
+	temp := sessionsConfigData{
+		SessionLimit:              sc.SessionLimit,
+		SessionLimitPerCredential: sc.SessionLimitPerCredential,
+		SessionLimitPerIP:         sc.SessionLimitPerIP,
+	}
 	if sc.HeartbeatWindow != 0 {
 		temp.HeartbeatWindow = sc.HeartbeatWindow.String()
 	}
+	if sc.IdleTimeout != 0 {
+		temp.IdleTimeout = sc.IdleTimeout.String()
+	}
 	return json.Marshal(temp)
 }
 
@@ -759,6 +1145,15 @@ func (sc *SessionsConfig) Validate(path string) error {
 		sc.HeartbeatWindow > time.Minute {
 		return resource.NewConfigValidationError(path, errors.New("heartbeat_window must be between [30ms, 1m]"))
 	}
+	if sc.IdleTimeout < 0 {
+		return resource.NewConfigValidationError(path, errors.New("idle_timeout cannot be negative"))
+	}
+	if sc.IdleTimeout != 0 && sc.IdleTimeout < sc.HeartbeatWindow {
+		return resource.NewConfigValidationError(path, errors.New("idle_timeout must be at least heartbeat_window"))
+	}
+	if sc.SessionLimit < 0 || sc.SessionLimitPerCredential < 0 || sc.SessionLimitPerIP < 0 {
+		return resource.NewConfigValidationError(path, errors.New("session limits cannot be negative"))
+	}
 
 	return nil
 }
@@ -10,6 +10,7 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/encoder/incremental"
 	fakemotor "go.viam.com/rdk/components/motor/fake"
+	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
@@ -69,6 +71,160 @@ func TestConfigRobot(t *testing.T) {
 	test.That(t, newBc, test.ShouldResemble, bc)
 }
 
+func TestConfigYAML(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	fromJSON, err := config.Read(context.Background(), "data/robot.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+	fromYAML, err := config.Read(context.Background(), "data/robot.yaml", logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fromYAML.Components, test.ShouldResemble, fromJSON.Components)
+	test.That(t, fromYAML.Remotes, test.ShouldResemble, fromJSON.Remotes)
+
+	cfg, err := config.FromReader(context.Background(), "robot.yml", strings.NewReader(`
+components:
+  - name: board1
+    type: board
+    model: fake
+    attributes:
+      analogs:
+        - name: analog1
+          pin: "0"
+`), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components[0].ConvertedAttributes, test.ShouldResemble, &fakeboard.Config{
+		AnalogReaders: []board.AnalogReaderConfig{{Name: "analog1", Pin: "0"}},
+	})
+
+	_, err = config.FromReader(context.Background(), "robot.yaml", strings.NewReader("components: [{"), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "failed to decode Config from yaml")
+}
+
+func TestConfigIncludes(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	cfg, err := config.Read(context.Background(), "data/includes/robot.json", logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// fragments come first, in the order they are included, then the including config's own.
+	names := make([]string, 0, len(cfg.Components))
+	for _, conf := range cfg.Components {
+		names = append(names, conf.Name)
+	}
+	test.That(t, names, test.ShouldResemble, []string{"arm1", "gripper1", "board1", "base1", "motor1"})
+
+	// the including config overrides the fragments, merging into resources of the same name.
+	arm := cfg.Components[0]
+	test.That(t, arm.Model, test.ShouldResemble, resource.DefaultModelFamily.WithModel("fake"))
+	test.That(t, arm.Attributes.String("arm-model"), test.ShouldEqual, "ur5e")
+	test.That(t, arm.Frame, test.ShouldNotBeNil)
+	test.That(t, arm.Frame.Parent, test.ShouldEqual, "world")
+	test.That(t, cfg.Network.BindAddress, test.ShouldEqual, ":8081")
+
+	read := func(includes string) error {
+		_, err := config.FromReader(context.Background(), "data/includes/robot.json",
+			strings.NewReader(`{"includes": `+includes+`}`), logger)
+		return err
+	}
+	err = read(`["fragments/cycle.json"]`)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "config include cycle")
+	test.That(t, err.Error(), test.ShouldContainSubstring,
+		filepath.Join("fragments", "cycle_back.json")+" includes ")
+
+	err = read(`["fragments/missing.json"]`)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "failed to read config fragment")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "missing.json")
+
+	err = read(`["fragments/invalid.json"]`)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid.json")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "failed to decode fragment from json")
+
+	err = read(`"fragments/cell.json"`)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "must be a list of paths")
+}
+
+func TestConfigTemplates(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	read := func(components string) (*config.Config, error) {
+		return config.FromReader(context.Background(), "robot.json",
+			strings.NewReader(`{"components": `+components+`}`), logger)
+	}
+
+	cfg, err := read(`[
+		{"name": "board1", "api": "rdk:component:board", "model": "fake"},
+		{
+			"for_each": [{"n": 1, "pin": 11}, {"n": 2, "pin": 13}],
+			"name": "ultrasonic{{n}}",
+			"api": "rdk:component:sensor",
+			"model": "fake",
+			"depends_on": ["board1"],
+			"attributes": {"trigger_pin": "{{ pin }}", "echo_interrupt_pin": "echo{{n}}"}
+		}
+	]`)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components, test.ShouldHaveLength, 3)
+	for i, conf := range cfg.Components[1:] {
+		test.That(t, conf.Name, test.ShouldEqual, fmt.Sprintf("ultrasonic%d", i+1))
+		test.That(t, conf.API, test.ShouldResemble, sensor.API)
+		test.That(t, conf.DependsOn, test.ShouldResemble, []string{"board1"})
+		test.That(t, conf.Attributes.Int("trigger_pin", 0), test.ShouldEqual, 11+2*i)
+		test.That(t, conf.Attributes.String("echo_interrupt_pin"), test.ShouldEqual, fmt.Sprintf("echo%d", i+1))
+	}
+
+	_, err = read(`[{"for_each": [{"n": 1}], "name": "ultrasonic{{n}}", "model": "{{model}}"}]`)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `undefined template parameter "model"`)
+
+	_, err = read(`[{"for_each": {"n": 1}, "name": "ultrasonic{{n}}"}]`)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "for_each of entry 0 must be a list of parameters")
+}
+
+func TestConfigEnvironmentVariables(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	path := filepath.Join(t.TempDir(), "robot.json")
+	test.That(t, os.WriteFile(path, []byte(`{
+		"components": [
+			{
+				"name": "motor1",
+				"type": "motor",
+				"model": "fake",
+				"attributes": {
+					"serial_path": "${TEST_SERIAL_PATH:-/dev/ttyUSB0}",
+					"api_key": "${TEST_API_KEY}",
+					"price": "$$5",
+					"data_dir": "$HOME/data"
+				}
+			}
+		]
+	}`), 0o600), test.ShouldBeNil)
+
+	t.Setenv("TEST_API_KEY", "abc123")
+	cfg, err := config.Read(context.Background(), path, logger)
+	test.That(t, err, test.ShouldBeNil)
+	attrs := cfg.Components[0].Attributes
+	test.That(t, attrs.String("serial_path"), test.ShouldEqual, "/dev/ttyUSB0")
+	test.That(t, attrs.String("api_key"), test.ShouldEqual, "abc123")
+	test.That(t, attrs.String("price"), test.ShouldEqual, "$5")
+	test.That(t, attrs.String("data_dir"), test.ShouldEqual, os.Getenv("HOME")+"/data")
+
+	t.Setenv("TEST_SERIAL_PATH", "/dev/ttyACM1")
+	cfg, err = config.Read(context.Background(), path, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components[0].Attributes.String("serial_path"), test.ShouldEqual, "/dev/ttyACM1")
+
+	// a variable without a default that is not set expands to an empty string.
+	test.That(t, os.Unsetenv("TEST_API_KEY"), test.ShouldBeNil)
+	logger, logs := logging.NewObservedTestLogger(t)
+	cfg, err = config.Read(context.Background(), path, logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Components[0].Attributes.String("api_key"), test.ShouldEqual, "")
+	test.That(t, logs.FilterMessageSnippet("environment variables that are not set").Len(), test.ShouldEqual, 1)
+}
+
 func TestConfig3(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 
@@ -128,6 +284,8 @@ func TestConfig3(t *testing.T) {
 	test.That(t, cfg.Remotes, test.ShouldHaveLength, 1)
 	test.That(t, cfg.Remotes[0].ConnectionCheckInterval, test.ShouldEqual, 12*time.Second)
 	test.That(t, cfg.Remotes[0].ReconnectInterval, test.ShouldEqual, 3*time.Second)
+	test.That(t, cfg.Remotes[0].MaxReconnectInterval, test.ShouldEqual, time.Minute)
+	test.That(t, cfg.Remotes[0].CacheMetadata, test.ShouldBeTrue)
 	test.That(t, cfg.Remotes[0].AssociatedResourceConfigs, test.ShouldHaveLength, 2)
 	test.That(t, cfg.Remotes[0].AssociatedResourceConfigs[0], test.ShouldResemble, resource.AssociatedResourceConfig{
 		API: resource.APINamespaceRDK.WithServiceType("data_manager"),
@@ -249,6 +407,9 @@ func TestConfigEnsure(t *testing.T) {
 	test.That(t, err, test.ShouldNotBeNil)
 	test.That(t, err.Error(), test.ShouldContainSubstring, `components.0`)
 	test.That(t, resource.GetFieldFromFieldRequiredError(err), test.ShouldEqual, "name")
+	// optional components are started without, even with partial start disabled.
+	invalidComponents.Components[0].Optional = true
+	test.That(t, invalidComponents.Ensure(false, logger), test.ShouldBeNil)
 	invalidComponents.Components[0] = resource.Config{
 		Name:  "foo",
 		API:   base.API,
@@ -257,6 +418,29 @@ func TestConfigEnsure(t *testing.T) {
 
 	test.That(t, invalidComponents.Ensure(false, logger), test.ShouldBeNil)
 
+	invalidRemoteDepth := config.Config{DisablePartialStart: true, MaxRemoteDepth: -1}
+	err = invalidRemoteDepth.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "max_remote_depth")
+	invalidRemoteDepth.DisablePartialStart = false
+	test.That(t, invalidRemoteDepth.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, invalidRemoteDepth.MaxRemoteDepth, test.ShouldEqual, 0)
+
+	invalidRetryInterval := config.Config{DisablePartialStart: true, ResourceRetryInterval: -time.Second}
+	err = invalidRetryInterval.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "resource_retry_interval")
+	invalidRetryInterval.DisablePartialStart = false
+	test.That(t, invalidRetryInterval.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, invalidRetryInterval.ResourceRetryInterval, test.ShouldEqual, 0)
+
+	var retryInterval config.Config
+	test.That(t, json.Unmarshal([]byte(`{"resource_retry_interval": "2s"}`), &retryInterval), test.ShouldBeNil)
+	test.That(t, retryInterval.ResourceRetryInterval, test.ShouldEqual, 2*time.Second)
+	data, err := json.Marshal(retryInterval)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(data), test.ShouldContainSubstring, `"resource_retry_interval":"2s"`)
+
 	c1 := resource.Config{
 		Name:  "c1",
 		API:   base.API,
@@ -368,6 +552,20 @@ func TestConfigEnsure(t *testing.T) {
 	invalidNetwork.Network.Sessions.HeartbeatWindow = 30 * time.Millisecond
 	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
 
+	invalidNetwork.Network.Sessions.IdleTimeout = 10 * time.Millisecond
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `idle_timeout`)
+
+	invalidNetwork.Network.Sessions.IdleTimeout = time.Minute
+	invalidNetwork.Network.Sessions.SessionLimitPerIP = -1
+	err = invalidNetwork.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `session limits`)
+
+	invalidNetwork.Network.Sessions.SessionLimitPerIP = 2
+	test.That(t, invalidNetwork.Ensure(false, logger), test.ShouldBeNil)
+
 	invalidNetwork.Network.BindAddress = "woop"
 	err = invalidNetwork.Ensure(false, logger)
 	test.That(t, err, test.ShouldNotBeNil)
@@ -695,6 +893,27 @@ func TestRemoteValidate(t *testing.T) {
 			"must start with a letter or number and must only contain letters, numbers, dashes, and underscores",
 		)
 	})
+
+	t.Run("remote reconnect backoff", func(t *testing.T) {
+		remote := config.Remote{
+			Name:                 "foo",
+			Address:              "address",
+			ReconnectInterval:    time.Second,
+			MaxReconnectInterval: time.Minute,
+		}
+		_, err := remote.Validate("path")
+		test.That(t, err, test.ShouldBeNil)
+
+		remote = config.Remote{
+			Name:                 "foo",
+			Address:              "address",
+			ReconnectInterval:    time.Minute,
+			MaxReconnectInterval: time.Second,
+		}
+		_, err = remote.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "max_reconnect_interval")
+	})
 }
 
 func TestCopyOnlyPublicFields(t *testing.T) {
@@ -911,6 +1130,40 @@ ph2C/7IgjA==
 	})
 }
 
+func TestConfigEnsureWhen(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	t.Setenv("TEST_ROBOT_KIND", "rover")
+	fakeModel := resource.DefaultModelFamily.WithModel("fake")
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "imu", API: base.API, Model: fakeModel, When: &resource.When{Env: map[string]string{"TEST_ROBOT_KIND": "arm"}}},
+			{Name: "imu", API: base.API, Model: fakeModel, When: &resource.When{Env: map[string]string{"TEST_ROBOT_KIND": "rover"}}},
+			{Name: "lidar", API: camera.API, Model: fakeModel, When: &resource.When{OS: []string{"plan9"}}},
+			{Name: "cam", API: camera.API, Model: fakeModel},
+		},
+		Services: []resource.Config{
+			{Name: "shell", API: shell.API, Model: fakeModel, When: &resource.When{Hostname: []string{"["}}},
+		},
+	}
+	test.That(t, cfg.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, cfg.Components, test.ShouldHaveLength, 2)
+	test.That(t, cfg.Components[0].Name, test.ShouldEqual, "imu")
+	test.That(t, cfg.Components[0].When.Env["TEST_ROBOT_KIND"], test.ShouldEqual, "rover")
+	test.That(t, cfg.Components[1].Name, test.ShouldEqual, "cam")
+	test.That(t, cfg.Services, test.ShouldBeEmpty)
+
+	cfg = &config.Config{
+		Components: []resource.Config{
+			{Name: "imu", API: base.API, Model: fakeModel, When: &resource.When{Hostname: []string{"["}}},
+		},
+		DisablePartialStart: true,
+	}
+	err := cfg.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "error evaluating when condition of component imu")
+}
+
 func TestAuthConfigEnsure(t *testing.T) {
 	t.Run("unknown handler", func(t *testing.T) {
 		logger := logging.NewTestLogger(t)
@@ -1226,3 +1479,57 @@ func TestConfigRobotWebProfile(t *testing.T) {
 
 	test.That(t, cfg.EnableWebProfile, test.ShouldBeTrue)
 }
+
+func TestConfigSchema(t *testing.T) {
+	data, err := json.Marshal(config.Schema())
+	test.That(t, err, test.ShouldBeNil)
+	var schema map[string]interface{}
+	test.That(t, json.Unmarshal(data, &schema), test.ShouldBeNil)
+	test.That(t, schema["$id"], test.ShouldEqual, config.SchemaID)
+
+	properties := schema["properties"].(map[string]interface{})
+	for _, key := range []string{"components", "services"} {
+		test.That(t, properties[key], test.ShouldResemble, map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"$ref": "#/$defs/resource"},
+		})
+	}
+
+	// the attributes of the fake motor are described by its native config.
+	definitions := schema["$defs"].(map[string]interface{})
+	attributes := definitions["go.viam.com.rdk.components.motor.fake.config"].(map[string]interface{})
+	test.That(t, attributes["$id"], test.ShouldEqual, "https://go.viam.com/rdk/components/motor/fake/config")
+	motorConfig := attributes["$defs"].(map[string]interface{})["Config"].(map[string]interface{})
+	test.That(t, motorConfig["properties"], test.ShouldContainKey, "max_rpm")
+	test.That(t, motorConfig["properties"], test.ShouldContainKey, "board")
+
+	var fakeMotor map[string]interface{}
+	for _, condition := range definitions["resource"].(map[string]interface{})["allOf"].([]interface{}) {
+		condition := condition.(map[string]interface{})
+		matched, err := json.Marshal(condition["if"])
+		test.That(t, err, test.ShouldBeNil)
+		if strings.Contains(string(matched), `"rdk:component:motor"`) && strings.Contains(string(matched), `"rdk:builtin:fake"`) {
+			fakeMotor = condition
+		}
+	}
+	test.That(t, fakeMotor, test.ShouldNotBeNil)
+	ifModel := fakeMotor["if"].(map[string]interface{})
+	test.That(t, ifModel["properties"], test.ShouldResemble, map[string]interface{}{
+		"model": map[string]interface{}{"enum": []interface{}{"rdk:builtin:fake", "fake"}},
+	})
+	test.That(t, ifModel["anyOf"], test.ShouldResemble, []interface{}{
+		map[string]interface{}{
+			"required":   []interface{}{"api"},
+			"properties": map[string]interface{}{"api": map[string]interface{}{"const": "rdk:component:motor"}},
+		},
+		map[string]interface{}{
+			"required":   []interface{}{"type"},
+			"properties": map[string]interface{}{"type": map[string]interface{}{"const": "motor"}},
+		},
+	})
+	test.That(t, fakeMotor["then"], test.ShouldResemble, map[string]interface{}{
+		"properties": map[string]interface{}{
+			"attributes": map[string]interface{}{"$ref": "#/$defs/go.viam.com.rdk.components.motor.fake.config"},
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/control"
+	"go.viam.com/rdk/resource"
+)
+
+// ControlLoopConfig describes a control loop the robot runs at a fixed frequency in its own
+// goroutines, built from blocks (e.g. sum, gain, PID, trapezoidalVelocityProfile) wired
+// together like a block diagram. Its endpoint block drives either a motor from the position
+// of an encoder, or a base from the velocity and heading of a movement sensor, so that e.g.
+// a PID can hold a motor's position or a base's heading.
+type ControlLoopConfig struct {
+	// Name identifies the loop.
+	Name string `json:"name"`
+	// Frequency is how many times a second the loop runs, up to control.MaxFrequency.
+	Frequency float64 `json:"frequency_hz"`
+	// Motor is the name of the motor driven with the power its endpoint block is given, and
+	// Encoder is the name of the encoder whose position in ticks the endpoint outputs.
+	Motor   string `json:"motor,omitempty"`
+	Encoder string `json:"encoder,omitempty"`
+	// Base is the name of the base driven with the angular power its endpoint block is given,
+	// preceded by the linear power if given two signals, and MovementSensor is the name of the
+	// movement sensor whose linear velocity and compass heading the endpoint outputs.
+	Base           string `json:"base,omitempty"`
+	MovementSensor string `json:"movement_sensor,omitempty"`
+	// Blocks are the blocks of the loop, including exactly one endpoint block.
+	Blocks []control.BlockConfig `json:"blocks"`
+}
+
+// Validate checks if the config is valid.
+func (c *ControlLoopConfig) Validate(path string) error {
+	if c.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if c.Frequency <= 0 || c.Frequency > control.MaxFrequency {
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("frequency_hz must be positive and at most %v", control.MaxFrequency))
+	}
+	switch {
+	case c.Motor != "" && c.Base != "":
+		return resource.NewConfigValidationError(path, errors.New("a control loop drives either a motor or a base, not both"))
+	case c.Motor != "":
+		if c.Encoder == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "encoder")
+		}
+	case c.Base != "":
+		if c.MovementSensor == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "movement_sensor")
+		}
+	default:
+		return resource.NewConfigValidationError(path, errors.New("a control loop must drive a motor or a base"))
+	}
+	var endpoints int
+	for _, block := range c.Blocks {
+		if block.Name == "" {
+			return resource.NewConfigValidationError(path, errors.New("block names must not be empty"))
+		}
+		if string(block.Type) == "endpoint" {
+			endpoints++
+		}
+	}
+	if endpoints != 1 {
+		return resource.NewConfigValidationError(path, errors.New("a control loop must have exactly one endpoint block"))
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/control"
+	"go.viam.com/rdk/logging"
+)
+
+func TestControlLoopConfigValidate(t *testing.T) {
+	valid := ControlLoopConfig{
+		Name:      "hold",
+		Frequency: 100,
+		Motor:     "m1",
+		Encoder:   "e1",
+		Blocks: []control.BlockConfig{
+			{Name: "setpoint", Type: "constant"},
+			{Name: "m1", Type: "endpoint", DependsOn: []string{"setpoint"}},
+		},
+	}
+	test.That(t, valid.Validate("control_loops.0"), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *ControlLoopConfig)
+		errStr string
+	}{
+		{"no name", func(c *ControlLoopConfig) { c.Name = "" }, "name"},
+		{"no frequency", func(c *ControlLoopConfig) { c.Frequency = 0 }, "frequency_hz"},
+		{"too fast", func(c *ControlLoopConfig) { c.Frequency = control.MaxFrequency + 1 }, "frequency_hz"},
+		{"no encoder", func(c *ControlLoopConfig) { c.Encoder = "" }, "encoder"},
+		{"motor and base", func(c *ControlLoopConfig) { c.Base = "b1" }, "not both"},
+		{"nothing driven", func(c *ControlLoopConfig) { c.Motor = "" }, "motor or a base"},
+		{"base without sensor", func(c *ControlLoopConfig) { c.Motor, c.Base = "", "b1" }, "movement_sensor"},
+		{"no endpoint", func(c *ControlLoopConfig) { c.Blocks = c.Blocks[:1] }, "one endpoint"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate("control_loops.0")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+
+	conf := Config{ControlLoops: []ControlLoopConfig{valid, {Name: "broken"}, valid}}
+	test.That(t, conf.Ensure(false, logging.NewTestLogger(t)), test.ShouldBeNil)
+	test.That(t, conf.ControlLoops, test.ShouldResemble, []ControlLoopConfig{valid})
+}
@@ -22,7 +22,11 @@ type Diff struct {
 	Removed        *Config
 	ResourcesEqual bool
 	NetworkEqual   bool
-	PrettyDiff     string
+	// CredentialsRotated is true when the networking config only differs in credentials
+	// (auth handler secrets, location secrets, and the TLS certificate) that can be
+	// rotated without restarting the web server.
+	CredentialsRotated bool
+	PrettyDiff         string
 }
 
 // ModifiedConfigDiff is the modificative different between two configs.
@@ -36,7 +40,7 @@ type ModifiedConfigDiff struct {
 }
 
 // DiffConfigs returns the difference between the two given configs
-// from left to right.
+// from left to right. Diff.Changes lists what changed with per-field detail.
 func DiffConfigs(left, right Config, revealSensitiveConfigDiffs bool) (_ *Diff, err error) {
 	var PrettyDiff string
 	if revealSensitiveConfigDiffs {
@@ -79,6 +83,7 @@ func DiffConfigs(left, right Config, revealSensitiveConfigDiffs bool) (_ *Diff,
 
 	networkDifferent := diffNetworkingCfg(&left, &right)
 	diff.NetworkEqual = !networkDifferent
+	diff.CredentialsRotated = networkDifferent && !diffNetworkingCfgIgnoringCredentials(left, right)
 
 	return &diff, nil
 }
@@ -174,6 +179,135 @@ func (diff *Diff) String() string {
 	return diff.PrettyDiff
 }
 
+// A ChangeKind is how a part of a config changed from the left to the right config of a Diff.
+type ChangeKind string
+
+// The kinds of changes.
+const (
+	ChangeAdded    ChangeKind = "added"
+	ChangeRemoved  ChangeKind = "removed"
+	ChangeModified ChangeKind = "modified"
+)
+
+// A Change is a remote, component, service, process, package or module that was added,
+// removed or modified from the left to the right config of a Diff. It holds no values of the
+// configs, so that it is safe to log.
+type Change struct {
+	Kind ChangeKind `json:"kind"`
+	// Section is the field of the config holding it (e.g. "components").
+	Section string `json:"section"`
+	// Name identifies it within its section. It is the full name of components and services,
+	// and the ID of processes.
+	Name string `json:"name"`
+	// Fields are the JSON fields of a modified one whose values differ, sorted. The attributes
+	// of components and services are detailed as "attributes.<name>".
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Changes returns the changes of the diff, by section in the order remotes, components,
+// services, processes, packages and modules, then added, modified and removed.
+func (diff *Diff) Changes() []Change {
+	var changes []Change
+	remoteName := func(conf Remote) string { return conf.Name }
+	resourceName := func(conf resource.Config) string { return conf.ResourceName().String() }
+	processName := func(conf pexec.ProcessConfig) string { return conf.ID }
+	packageName := func(conf PackageConfig) string { return conf.Name }
+	moduleName := func(conf Module) string { return conf.Name }
+
+	changes = appendChanges(changes, "remotes", remoteName,
+		diff.Left.Remotes, diff.Added.Remotes, diff.Modified.Remotes, diff.Removed.Remotes)
+	changes = appendChanges(changes, "components", resourceName,
+		diff.Left.Components, diff.Added.Components, diff.Modified.Components, diff.Removed.Components)
+	changes = appendChanges(changes, "services", resourceName,
+		diff.Left.Services, diff.Added.Services, diff.Modified.Services, diff.Removed.Services)
+	changes = appendChanges(changes, "processes", processName,
+		diff.Left.Processes, diff.Added.Processes, diff.Modified.Processes, diff.Removed.Processes)
+	changes = appendChanges(changes, "packages", packageName,
+		diff.Left.Packages, diff.Added.Packages, diff.Modified.Packages, diff.Removed.Packages)
+	changes = appendChanges(changes, "modules", moduleName,
+		diff.Left.Modules, diff.Added.Modules, diff.Modified.Modules, diff.Removed.Modules)
+	return changes
+}
+
+// appendChanges appends the changes of a section of the config, whose left values are those
+// of the left config of the diff.
+func appendChanges[T any](
+	changes []Change,
+	section string,
+	name func(T) string,
+	left, added, modified, removed []T,
+) []Change {
+	for _, conf := range added {
+		changes = append(changes, Change{Kind: ChangeAdded, Section: section, Name: name(conf)})
+	}
+	for _, conf := range modified {
+		change := Change{Kind: ChangeModified, Section: section, Name: name(conf)}
+		for _, leftConf := range left {
+			if name(leftConf) == change.Name {
+				change.Fields = changedFields(leftConf, conf)
+				break
+			}
+		}
+		changes = append(changes, change)
+	}
+	for _, conf := range removed {
+		changes = append(changes, Change{Kind: ChangeRemoved, Section: section, Name: name(conf)})
+	}
+	return changes
+}
+
+// changedFields returns the JSON fields whose values differ between left and right, sorted,
+// detailing the fields of attributes.
+func changedFields(left, right interface{}) []string {
+	leftFields, leftErr := jsonFields(left)
+	rightFields, rightErr := jsonFields(right)
+	if leftErr != nil || rightErr != nil {
+		return nil
+	}
+	fields := diffFields("", leftFields, rightFields)
+	sort.Strings(fields)
+	return fields
+}
+
+func diffFields(prefix string, left, right map[string]interface{}) []string {
+	var fields []string
+	for field, leftValue := range left {
+		rightValue, ok := right[field]
+		if ok && reflect.DeepEqual(leftValue, rightValue) {
+			continue
+		}
+		leftAttrs, leftOK := leftValue.(map[string]interface{})
+		rightAttrs, rightOK := rightValue.(map[string]interface{})
+		if prefix == "" && field == "attributes" && leftOK && rightOK {
+			fields = append(fields, diffFields("attributes.", leftAttrs, rightAttrs)...)
+			continue
+		}
+		fields = append(fields, prefix+field)
+	}
+	for field := range right {
+		if _, ok := left[field]; !ok {
+			if rightAttrs, ok := right[field].(map[string]interface{}); ok && prefix == "" && field == "attributes" {
+				fields = append(fields, diffFields("attributes.", nil, rightAttrs)...)
+				continue
+			}
+			fields = append(fields, prefix+field)
+		}
+	}
+	return fields
+}
+
+func jsonFields(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
 //nolint:dupl
 func diffRemotes(left, right []Remote, diff *Diff) bool {
 	leftIndex := make(map[string]int)
@@ -405,6 +539,46 @@ func diffNetworkingCfg(left, right *Config) bool {
 	return false
 }
 
+// diffNetworkingCfgIgnoringCredentials returns true if any part of the networking config
+// other than rotatable credentials is different.
+func diffNetworkingCfgIgnoringCredentials(left, right Config) bool {
+	left.Cloud, right.Cloud = withoutRotatableCredentials(left.Cloud), withoutRotatableCredentials(right.Cloud)
+	left.Auth.Handlers = withoutHandlerSecrets(left.Auth.Handlers)
+	right.Auth.Handlers = withoutHandlerSecrets(right.Auth.Handlers)
+	if left.Network.TLSConfig != nil && right.Network.TLSConfig != nil &&
+		left.Network.TLSConfig.MinVersion == right.Network.TLSConfig.MinVersion {
+		right.Network.TLSConfig = left.Network.TLSConfig
+	}
+	return diffNetworkingCfg(&left, &right)
+}
+
+// withoutRotatableCredentials returns a copy of the cloud config without the location
+// secrets and TLS certificate. The robot secret is kept since it is used to connect to
+// the signaling server, which requires a restart to change.
+func withoutRotatableCredentials(cloud *Cloud) *Cloud {
+	if cloud == nil {
+		return nil
+	}
+	cloudCopy := *cloud
+	cloudCopy.LocationSecret = ""
+	cloudCopy.LocationSecrets = nil
+	cloudCopy.TLSCertificate = ""
+	cloudCopy.TLSPrivateKey = ""
+	return &cloudCopy
+}
+
+// withoutHandlerSecrets returns a copy of the auth handlers with only their types.
+func withoutHandlerSecrets(handlers []AuthHandlerConfig) []AuthHandlerConfig {
+	if handlers == nil {
+		return nil
+	}
+	out := make([]AuthHandlerConfig, 0, len(handlers))
+	for _, handler := range handlers {
+		out = append(out, AuthHandlerConfig{Type: handler.Type})
+	}
+	return out
+}
+
 // diffNetwork returns true if any part of the network config is different.
 func diffNetwork(leftCopy, rightCopy NetworkConfig) bool {
 	if diffTLS(leftCopy.TLSConfig, rightCopy.TLSConfig) {
@@ -462,6 +462,56 @@ func TestDiffConfigHeterogenousTypes(t *testing.T) {
 	}
 }
 
+func TestDiffChanges(t *testing.T) {
+	left := config.Config{
+		Remotes: []config.Remote{{Name: "rem1", Address: "addr1"}},
+		Components: []resource.Config{
+			{
+				Name:       "arm1",
+				API:        arm.API,
+				Model:      fakeModel,
+				Attributes: utils.AttributeMap{"speed": 1.0, "port": "/dev/ttyUSB0"},
+			},
+			{Name: "base1", API: base.API, Model: fakeModel},
+		},
+		Processes: []pexec.ProcessConfig{{ID: "proc1", Name: "echo"}},
+	}
+	right := config.Config{
+		Remotes: []config.Remote{{Name: "rem1", Address: "addr2"}},
+		Components: []resource.Config{
+			{
+				Name:       "arm1",
+				API:        arm.API,
+				Model:      fakeModel,
+				Attributes: utils.AttributeMap{"speed": 2.0, "port": "/dev/ttyUSB0", "secret": "hunter2"},
+				DependsOn:  []string{"board1"},
+			},
+			{Name: "board1", API: board.API, Model: fakeModel},
+		},
+		Services:  []resource.Config{{Name: "nav1", API: resource.APINamespaceRDK.WithServiceType("navigation"), Model: fakeModel}},
+		Processes: []pexec.ProcessConfig{{ID: "proc1", Name: "echo"}},
+	}
+
+	diff, err := config.DiffConfigs(left, right, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.Changes(), test.ShouldResemble, []config.Change{
+		{Kind: config.ChangeModified, Section: "remotes", Name: "rem1", Fields: []string{"address"}},
+		{Kind: config.ChangeAdded, Section: "components", Name: board.Named("board1").String()},
+		{
+			Kind:    config.ChangeModified,
+			Section: "components",
+			Name:    arm.Named("arm1").String(),
+			Fields:  []string{"attributes.secret", "attributes.speed", "depends_on"},
+		},
+		{Kind: config.ChangeRemoved, Section: "components", Name: base.Named("base1").String()},
+		{Kind: config.ChangeAdded, Section: "services", Name: "rdk:service:navigation/nav1"},
+	})
+
+	diff, err = config.DiffConfigs(left, left, false)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, diff.Changes(), test.ShouldBeEmpty)
+}
+
 func TestDiffNetworkingCfg(t *testing.T) {
 	network1 := config.NetworkConfig{NetworkConfigData: config.NetworkConfigData{FQDN: "abc"}}
 	network2 := config.NetworkConfig{NetworkConfigData: config.NetworkConfigData{FQDN: "xyz"}}
@@ -512,6 +562,8 @@ func TestDiffNetworkingCfg(t *testing.T) {
 
 	cloud1 := &config.Cloud{ID: "1"}
 	cloud2 := &config.Cloud{ID: "2"}
+	cloud3 := &config.Cloud{ID: "1", Secret: "new"}
+	cloud4 := &config.Cloud{ID: "1", LocationSecrets: []config.LocationSecret{{ID: "2", Secret: "new"}}}
 
 	auth1 := config.AuthConfig{
 		Handlers: []config.AuthHandlerConfig{{Config: utils.AttributeMap{"key": "value"}}},
@@ -519,71 +571,106 @@ func TestDiffNetworkingCfg(t *testing.T) {
 	auth2 := config.AuthConfig{
 		Handlers: []config.AuthHandlerConfig{{Config: utils.AttributeMap{"key2": "value2"}}},
 	}
+	auth3 := config.AuthConfig{
+		Handlers: []config.AuthHandlerConfig{{Type: rpc.CredentialsTypeAPIKey, Config: utils.AttributeMap{"key": "value"}}},
+	}
 	for _, tc := range []struct {
-		Name         string
-		LeftCfg      config.Config
-		RightCfg     config.Config
-		NetworkEqual bool
+		Name               string
+		LeftCfg            config.Config
+		RightCfg           config.Config
+		NetworkEqual       bool
+		CredentialsRotated bool
 	}{
 		{
 			"same",
 			config.Config{Network: network1, Cloud: cloud1, Auth: auth1},
 			config.Config{Network: network1, Cloud: cloud1, Auth: auth1},
 			true,
+			false,
 		},
 		{
 			"diff network",
 			config.Config{Network: network1},
 			config.Config{Network: network2},
 			false,
+			false,
 		},
 		{
 			"same tls",
 			config.Config{Network: tls3},
 			config.Config{Network: tls4},
 			true,
+			false,
 		},
 		{
 			"diff tls",
 			config.Config{Network: tls1},
 			config.Config{Network: tls2},
 			false,
+			false,
 		},
 		{
 			"diff tls cert",
 			config.Config{Network: tls3},
 			config.Config{Network: tls5},
 			false,
+			true,
 		},
 		{
 			"diff tls client cert",
 			config.Config{Network: tls3},
 			config.Config{Network: tls6},
 			false,
+			true,
 		},
 		{
 			"diff cloud",
 			config.Config{Cloud: cloud1},
 			config.Config{Cloud: cloud2},
 			false,
+			false,
+		},
+		{
+			"diff cloud secret",
+			config.Config{Cloud: cloud1},
+			config.Config{Cloud: cloud3},
+			false,
+			false,
+		},
+		{
+			"diff location secrets",
+			config.Config{Cloud: cloud1},
+			config.Config{Cloud: cloud4},
+			false,
+			true,
 		},
 		{
 			"diff auth",
 			config.Config{Auth: auth1},
 			config.Config{Auth: auth2},
 			false,
+			true,
+		},
+		{
+			"diff auth type",
+			config.Config{Auth: auth1},
+			config.Config{Auth: auth3},
+			false,
+			false,
 		},
 		{
 			"webprofile",
 			config.Config{},
 			config.Config{EnableWebProfile: true},
 			false,
+			false,
 		},
 		{
 			"disable webprofile",
 			config.Config{EnableWebProfile: true},
 			config.Config{EnableWebProfile: false},
 			false,
+			false,
 		},
 	} {
 		t.Run(tc.Name, func(t *testing.T) {
@@ -591,6 +678,7 @@ func TestDiffNetworkingCfg(t *testing.T) {
 			test.That(t, err, test.ShouldBeNil)
 
 			test.That(t, diff.NetworkEqual, test.ShouldEqual, tc.NetworkEqual)
+			test.That(t, diff.CredentialsRotated, test.ShouldEqual, tc.CredentialsRotated)
 		})
 	}
 }
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"slices"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+
+	"go.viam.com/rdk/resource"
+)
+
+// FaultType is the kind of fault injected into requests made to a resource.
+type FaultType string
+
+const (
+	// FaultTypeError makes requests fail with an error.
+	FaultTypeError FaultType = "error"
+	// FaultTypeLatency delays requests and streamed responses.
+	FaultTypeLatency FaultType = "latency"
+	// FaultTypeDropFrames drops frames returned by image, point cloud, and audio methods.
+	FaultTypeDropFrames FaultType = "drop_frames"
+	// FaultTypeDisconnect makes the resource appear unreachable, failing requests and
+	// ending open streams.
+	FaultTypeDisconnect FaultType = "disconnect"
+)
+
+// SupportedFaultTypes is a list of all supported fault types.
+var SupportedFaultTypes = []FaultType{
+	FaultTypeError,
+	FaultTypeLatency,
+	FaultTypeDropFrames,
+	FaultTypeDisconnect,
+}
+
+// FaultConfig describes a fault to inject into the requests made to a resource over
+// gRPC, used to test how applications handle flaky hardware.
+type FaultConfig struct {
+	// Resource is the name of the resource to inject the fault into.
+	Resource string `json:"resource"`
+	// Type is the kind of fault to inject.
+	Type FaultType `json:"type"`
+	// Methods limits the fault to the named gRPC methods (e.g. GetImage). By default
+	// drop_frames faults apply to frame returning methods and all other faults apply to
+	// every method.
+	Methods []string `json:"methods,omitempty"`
+	// Probability is the chance that the fault is injected into a matching call. Zero
+	// means every call.
+	Probability float64 `json:"probability,omitempty"`
+	// Every injects the fault into only every nth matching call.
+	Every int `json:"every,omitempty"`
+	// Code is the name of the gRPC status code returned by error faults (e.g.
+	// UNAVAILABLE). Defaults to INTERNAL.
+	Code string `json:"code,omitempty"`
+	// Message is the message returned by error faults.
+	Message string `json:"message,omitempty"`
+	// LatencyMs is the delay added by latency faults.
+	LatencyMs int `json:"latency_ms,omitempty"`
+	// JitterMs is the maximum random delay added on top of LatencyMs.
+	JitterMs int `json:"jitter_ms,omitempty"`
+	// InactiveSec and ActiveSec schedule the fault. Once set, the fault is inactive for
+	// InactiveSec and then active for ActiveSec, repeating. If ActiveSec is zero the
+	// fault stays active once InactiveSec has passed.
+	InactiveSec float64 `json:"inactive_sec,omitempty"`
+	ActiveSec   float64 `json:"active_sec,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (f *FaultConfig) Validate(path string) error {
+	if f.Resource == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "resource")
+	}
+	if !slices.Contains(SupportedFaultTypes, f.Type) {
+		return resource.NewConfigValidationError(path, errors.Errorf("unsupported fault type %q. Must be one of: %v",
+			f.Type, SupportedFaultTypes))
+	}
+	if slices.Contains(f.Methods, "") {
+		return resource.NewConfigValidationError(path, errors.New("methods must not be empty"))
+	}
+	if f.Probability < 0 || f.Probability > 1 {
+		return resource.NewConfigValidationError(path, errors.New("probability must be between 0 and 1"))
+	}
+	if f.Every < 0 {
+		return resource.NewConfigValidationError(path, errors.New("every must not be negative"))
+	}
+	if _, err := f.StatusCode(); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	if f.LatencyMs < 0 || f.JitterMs < 0 {
+		return resource.NewConfigValidationError(path, errors.New("latency_ms and jitter_ms must not be negative"))
+	}
+	if f.Type == FaultTypeLatency && f.LatencyMs == 0 && f.JitterMs == 0 {
+		return resource.NewConfigValidationFieldRequiredError(path, "latency_ms")
+	}
+	if f.InactiveSec < 0 || f.ActiveSec < 0 {
+		return resource.NewConfigValidationError(path, errors.New("inactive_sec and active_sec must not be negative"))
+	}
+	return nil
+}
+
+// StatusCode returns the gRPC status code returned by an error fault.
+func (f *FaultConfig) StatusCode() (codes.Code, error) {
+	if f.Code == "" {
+		return codes.Internal, nil
+	}
+	var code codes.Code
+	if err := code.UnmarshalJSON([]byte(strconv.Quote(f.Code))); err != nil {
+		return 0, errors.Errorf("unknown status code %q", f.Code)
+	}
+	return code, nil
+}
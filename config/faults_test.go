@@ -0,0 +1,43 @@
+package config
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+)
+
+func TestFaultConfigValidate(t *testing.T) {
+	valid := FaultConfig{
+		Resource: "camera1",
+		Type:     FaultTypeError,
+		Code:     "UNAVAILABLE",
+	}
+	test.That(t, valid.Validate("faults.0"), test.ShouldBeNil)
+	code, err := valid.StatusCode()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, code, test.ShouldEqual, codes.Unavailable)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(f *FaultConfig)
+		errStr string
+	}{
+		{"no resource", func(f *FaultConfig) { f.Resource = "" }, "resource"},
+		{"bad type", func(f *FaultConfig) { f.Type = "explode" }, "unsupported fault type"},
+		{"empty method", func(f *FaultConfig) { f.Methods = []string{""} }, "methods"},
+		{"bad probability", func(f *FaultConfig) { f.Probability = 1.5 }, "probability"},
+		{"negative every", func(f *FaultConfig) { f.Every = -1 }, "every"},
+		{"bad code", func(f *FaultConfig) { f.Code = "OOPS" }, "unknown status code"},
+		{"no latency", func(f *FaultConfig) { f.Type = FaultTypeLatency }, "latency_ms"},
+		{"negative schedule", func(f *FaultConfig) { f.ActiveSec = -1 }, "active_sec"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := valid
+			tc.modify(&f)
+			err := f.Validate("faults.0")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
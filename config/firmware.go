@@ -0,0 +1,98 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/hex"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// FirmwareTarget describes how firmware is delivered to an attached microcontroller.
+type FirmwareTarget string
+
+const (
+	// FirmwareTargetESP32Serial flashes an ESP32 over a serial port.
+	FirmwareTargetESP32Serial FirmwareTarget = "esp32_serial"
+	// FirmwareTargetESP32OTA flashes an ESP32 over the network using its OTA update endpoint.
+	FirmwareTargetESP32OTA FirmwareTarget = "esp32_ota"
+	// FirmwareTargetSTM32DFU flashes an STM32 using USB DFU.
+	FirmwareTargetSTM32DFU FirmwareTarget = "stm32_dfu"
+)
+
+// SupportedFirmwareTargets is a list of all supported firmware targets.
+var SupportedFirmwareTargets = []FirmwareTarget{
+	FirmwareTargetESP32Serial,
+	FirmwareTargetESP32OTA,
+	FirmwareTargetSTM32DFU,
+}
+
+// FirmwareConfig describes a firmware image that should be flashed to an attached
+// microcontroller along with the resources that depend on that microcontroller.
+type FirmwareConfig struct {
+	// Name is an arbitrary name used to identify the update.
+	Name string `json:"name"`
+	// Target is how the firmware will be delivered.
+	Target FirmwareTarget `json:"target"`
+	// Port is the serial port or DFU device used by serial and DFU targets.
+	Port string `json:"port,omitempty"`
+	// Address is the network address used by OTA targets.
+	Address string `json:"address,omitempty"`
+	// Artifact is the path to the firmware image. It may reference a package with
+	// a placeholder (e.g. ${packages.my-firmware}/firmware.bin).
+	Artifact string `json:"artifact"`
+	// SHA256 is the optional hex encoded digest of the firmware image.
+	SHA256 string `json:"sha256,omitempty"`
+	// Signature is the base64 encoded ed25519 signature of the firmware image.
+	Signature string `json:"signature"`
+	// PublicKey is the base64 encoded ed25519 public key used to verify Signature.
+	PublicKey string `json:"public_key"`
+	// Resources are the names of the resources backed by the microcontroller. They are
+	// stopped and closed while flashing and rebuilt afterwards.
+	Resources []string `json:"resources,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (f *FirmwareConfig) Validate(path string) error {
+	if f.Name == "" {
+		return resource.NewConfigValidationError(path, errors.New("empty firmware name"))
+	}
+	if !slices.Contains(SupportedFirmwareTargets, f.Target) {
+		return resource.NewConfigValidationError(path, errors.Errorf("unsupported firmware target %q. Must be one of: %v",
+			f.Target, SupportedFirmwareTargets))
+	}
+	switch f.Target {
+	case FirmwareTargetESP32Serial, FirmwareTargetSTM32DFU:
+		if f.Port == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "port")
+		}
+	case FirmwareTargetESP32OTA:
+		if f.Address == "" {
+			return resource.NewConfigValidationFieldRequiredError(path, "address")
+		}
+	}
+	if f.Artifact == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "artifact")
+	}
+	if f.SHA256 != "" {
+		if digest, err := hex.DecodeString(f.SHA256); err != nil || len(digest) != 32 {
+			return resource.NewConfigValidationError(path, errors.New("sha256 must be a hex encoded SHA-256 digest"))
+		}
+	}
+	if f.Signature == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "signature")
+	}
+	if _, err := base64.StdEncoding.DecodeString(f.Signature); err != nil {
+		return resource.NewConfigValidationError(path, errors.Wrap(err, "signature must be base64 encoded"))
+	}
+	if f.PublicKey == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "public_key")
+	}
+	if key, err := base64.StdEncoding.DecodeString(f.PublicKey); err != nil || len(key) != ed25519.PublicKeySize {
+		return resource.NewConfigValidationError(path, errors.New("public_key must be a base64 encoded ed25519 public key"))
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestFirmwareConfigValidate(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	valid := FirmwareConfig{
+		Name:      "mcu",
+		Target:    FirmwareTargetESP32Serial,
+		Port:      "/dev/ttyUSB0",
+		Artifact:  "firmware.bin",
+		Signature: base64.StdEncoding.EncodeToString([]byte("sig")),
+		PublicKey: base64.StdEncoding.EncodeToString(pub),
+	}
+	test.That(t, valid.Validate("firmware.0"), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(f *FirmwareConfig)
+		errStr string
+	}{
+		{"no name", func(f *FirmwareConfig) { f.Name = "" }, "empty firmware name"},
+		{"bad target", func(f *FirmwareConfig) { f.Target = "avr" }, "unsupported firmware target"},
+		{"no port", func(f *FirmwareConfig) { f.Port = "" }, "port"},
+		{"no address", func(f *FirmwareConfig) { f.Target = FirmwareTargetESP32OTA }, "address"},
+		{"bad digest", func(f *FirmwareConfig) { f.SHA256 = "abc" }, "sha256"},
+		{"no signature", func(f *FirmwareConfig) { f.Signature = "" }, "signature"},
+		{"bad key", func(f *FirmwareConfig) { f.PublicKey = "aGk=" }, "public_key"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := valid
+			tc.modify(&f)
+			err := f.Validate("firmware.0")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
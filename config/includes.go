@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+// includesKey is the key of the list of config fragment files a config includes. A fragment
+// is a config file, in JSON or YAML, holding part of a robot's config, such as a standard arm
+// and gripper cell, that is shared by several robots.
+const includesKey = "includes"
+
+// resolveIncludes returns the JSON config read from the given path with the fragments it
+// includes merged in. Fragments are merged in the order they are listed, each overriding the
+// ones before it, and the including config overrides them all: objects are merged key by key,
+// lists of named objects, such as components, are merged by name, and anything else is
+// replaced. Fragments may include other fragments, at paths relative to their own, as long
+// as no fragment ends up including itself. A config including no fragments is returned as is.
+func resolveIncludes(path string, data []byte, logger logging.Logger) ([]byte, error) {
+	raw, err := decodeRaw(data)
+	if err != nil {
+		// left to the decoding of the config to report.
+		return data, nil //nolint:nilerr
+	}
+	if _, ok := raw[includesKey]; !ok {
+		return data, nil
+	}
+	merged, err := includeAll(path, raw, nil, logger)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merged)
+}
+
+// includeAll returns the config read from the given path with the fragments it includes
+// merged in. The chain is the paths of the configs including it, outermost first.
+func includeAll(
+	path string,
+	raw map[string]interface{},
+	chain []string,
+	logger logging.Logger,
+) (map[string]interface{}, error) {
+	if path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+	}
+	chain = append(slices.Clip(chain), path)
+
+	includes, err := includePaths(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s of config %q", includesKey, path)
+	}
+	delete(raw, includesKey)
+
+	merged := map[string]interface{}{}
+	for _, included := range includes {
+		if !filepath.IsAbs(included) {
+			included = filepath.Join(filepath.Dir(path), included)
+		}
+		if slices.Contains(chain, included) {
+			return nil, errors.Errorf("config include cycle: %s", strings.Join(append(chain, included), " includes "))
+		}
+		fragment, err := readFragment(included, logger)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read config fragment %q included by %q", included, path)
+		}
+		if fragment, err = includeAll(included, fragment, chain, logger); err != nil {
+			return nil, err
+		}
+		merged = mergeConfig(merged, fragment).(map[string]interface{})
+	}
+	return mergeConfig(merged, raw).(map[string]interface{}), nil
+}
+
+func includePaths(raw map[string]interface{}) ([]string, error) {
+	value, ok := raw[includesKey]
+	if !ok {
+		return nil, nil
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		return nil, errors.New("must be a list of paths")
+	}
+	paths := make([]string, 0, len(list))
+	for _, item := range list {
+		path, ok := item.(string)
+		if !ok || path == "" {
+			return nil, errors.Errorf("%v is not a path", item)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// readFragment reads the config fragment at the given path, checking that it decodes as a
+// config so that mistakes in it are reported along with its path.
+func readFragment(path string, logger logging.Logger) (map[string]interface{}, error) {
+	data, err := ReadFile(path, logger)
+	if err != nil {
+		return nil, err
+	}
+	if isYAML(path) {
+		if data, err = yamlToJSON(bytes.NewReader(data)); err != nil {
+			return nil, errors.Wrap(err, "failed to decode fragment from yaml")
+		}
+	}
+	if data, err = expandTemplates(data); err != nil {
+		return nil, err
+	}
+	var check configData
+	if err := json.Unmarshal(data, &check); err != nil {
+		return nil, errors.Wrap(err, "failed to decode fragment from json")
+	}
+	return decodeRaw(data)
+}
+
+// decodeRaw decodes a JSON config object, keeping its numbers as they are written.
+func decodeRaw(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var raw map[string]interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return raw, nil
+}
+
+// mergeConfig returns the decoded JSON base with override merged into it, as by
+// resolveIncludes.
+func mergeConfig(base, override interface{}) interface{} {
+	switch override := override.(type) {
+	case map[string]interface{}:
+		baseMap, ok := base.(map[string]interface{})
+		if !ok {
+			return override
+		}
+		out := make(map[string]interface{}, len(baseMap)+len(override))
+		for key, value := range baseMap {
+			out[key] = value
+		}
+		for key, value := range override {
+			if baseValue, ok := out[key]; ok {
+				value = mergeConfig(baseValue, value)
+			}
+			out[key] = value
+		}
+		return out
+	case []interface{}:
+		baseList, ok := base.([]interface{})
+		if !ok || !allNamed(baseList) || !allNamed(override) {
+			return override
+		}
+		out := slices.Clone(baseList)
+		for _, item := range override {
+			name := nameOf(item)
+			idx := slices.IndexFunc(out, func(baseItem interface{}) bool { return nameOf(baseItem) == name })
+			if idx == -1 {
+				out = append(out, item)
+				continue
+			}
+			out[idx] = mergeConfig(out[idx], item)
+		}
+		return out
+	default:
+		return override
+	}
+}
+
+func nameOf(item interface{}) string {
+	if obj, ok := item.(map[string]interface{}); ok {
+		if name, ok := obj["name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+func allNamed(list []interface{}) bool {
+	for _, item := range list {
+		if nameOf(item) == "" {
+			return false
+		}
+	}
+	return true
+}
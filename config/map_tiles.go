@@ -0,0 +1,110 @@
+package config
+
+import (
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	// MaxMapTileZoom is the highest zoom level of the map tiles a robot caches.
+	MaxMapTileZoom = 20
+	// MaxMapTilesPerArea bounds how many map tiles an area may span, so that a mistyped area
+	// does not fill the robot's disk.
+	MaxMapTilesPerArea = 100000
+	// maxMapTileLatitude is the latitude beyond which web mercator tiles do not extend.
+	maxMapTileLatitude = 85.0511287798
+)
+
+// MapTileAreaConfig describes a geographic area whose map tiles the robot downloads from an
+// OSM or XYZ tile server and serves itself, at /tiles/<name>/<z>/<x>/<y> on its web server
+// when started with -webmaptiles, so that navigation UIs rendered from the robot keep working
+// in the field without internet connectivity.
+type MapTileAreaConfig struct {
+	// Name identifies the area in the URLs of its tiles.
+	Name string `json:"name"`
+	// URL is the template of the URLs of the tiles on the tile server, where {z}, {x} and {y}
+	// are replaced by the zoom level and coordinates of a tile (e.g.
+	// "https://tile.openstreetmap.org/{z}/{x}/{y}.png").
+	URL string `json:"url"`
+	// MinLatitude, MinLongitude, MaxLatitude and MaxLongitude bound the area, in degrees.
+	MinLatitude  float64 `json:"min_latitude"`
+	MinLongitude float64 `json:"min_longitude"`
+	MaxLatitude  float64 `json:"max_latitude"`
+	MaxLongitude float64 `json:"max_longitude"`
+	// MinZoom and MaxZoom are the range of zoom levels whose tiles are downloaded.
+	MinZoom int `json:"min_zoom,omitempty"`
+	MaxZoom int `json:"max_zoom"`
+}
+
+// Validate checks if the config is valid.
+func (c *MapTileAreaConfig) Validate(path string) error {
+	if c.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if err := utils.ValidateResourceName(c.Name); err != nil {
+		return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid name"))
+	}
+	if c.URL == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "url")
+	}
+	for _, placeholder := range []string{"{z}", "{x}", "{y}"} {
+		if !strings.Contains(c.URL, placeholder) {
+			return resource.NewConfigValidationError(path, errors.Errorf("url must contain %s", placeholder))
+		}
+	}
+	if c.MinLatitude < -maxMapTileLatitude || c.MaxLatitude > maxMapTileLatitude || c.MinLatitude >= c.MaxLatitude {
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("latitudes must be between -%v and %v with min_latitude below max_latitude", maxMapTileLatitude, maxMapTileLatitude))
+	}
+	if c.MinLongitude < -180 || c.MaxLongitude > 180 || c.MinLongitude >= c.MaxLongitude {
+		return resource.NewConfigValidationError(path,
+			errors.New("longitudes must be between -180 and 180 with min_longitude below max_longitude"))
+	}
+	if c.MinZoom < 0 || c.MaxZoom > MaxMapTileZoom || c.MinZoom > c.MaxZoom {
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("zoom levels must be between 0 and %d with min_zoom at most max_zoom", MaxMapTileZoom))
+	}
+	if count := c.TileCount(); count > MaxMapTilesPerArea {
+		return resource.NewConfigValidationError(path,
+			errors.Errorf("area spans %d tiles, more than the %d allowed; shrink it or lower max_zoom", count, MaxMapTilesPerArea))
+	}
+	return nil
+}
+
+// TileRange returns the range of the x and y coordinates, inclusive, of the tiles of the area
+// at the given zoom level.
+func (c *MapTileAreaConfig) TileRange(zoom int) (minX, maxX, minY, maxY int) {
+	minX, maxY = mapTile(c.MinLatitude, c.MinLongitude, zoom)
+	maxX, minY = mapTile(c.MaxLatitude, c.MaxLongitude, zoom)
+	return minX, maxX, minY, maxY
+}
+
+// TileCount returns how many tiles the area spans over its zoom levels.
+func (c *MapTileAreaConfig) TileCount() int {
+	var count int
+	for zoom := c.MinZoom; zoom <= c.MaxZoom; zoom++ {
+		minX, maxX, minY, maxY := c.TileRange(zoom)
+		count += (maxX - minX + 1) * (maxY - minY + 1)
+		if count > MaxMapTilesPerArea {
+			// higher zoom levels only add more.
+			return count
+		}
+	}
+	return count
+}
+
+// mapTile returns the coordinates of the web mercator tile holding the given point at the
+// given zoom level.
+func mapTile(latitude, longitude float64, zoom int) (int, int) {
+	n := math.Exp2(float64(zoom))
+	latRad := latitude * math.Pi / 180
+	x := int(math.Floor((longitude + 180) / 360 * n))
+	y := int(math.Floor((1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n))
+	last := int(n) - 1
+	return min(max(x, 0), last), min(max(y, 0), last)
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestMapTileAreaConfigValidate(t *testing.T) {
+	valid := MapTileAreaConfig{
+		Name:         "field",
+		URL:          "https://tile.openstreetmap.org/{z}/{x}/{y}.png",
+		MinLatitude:  37.7,
+		MinLongitude: -122.5,
+		MaxLatitude:  37.8,
+		MaxLongitude: -122.4,
+		MaxZoom:      10,
+	}
+	test.That(t, valid.Validate("map_tiles.0"), test.ShouldBeNil)
+
+	// the whole world is a single tile at zoom level 0.
+	minX, maxX, minY, maxY := valid.TileRange(0)
+	test.That(t, []int{minX, maxX, minY, maxY}, test.ShouldResemble, []int{0, 0, 0, 0})
+	minX, maxX, minY, maxY = valid.TileRange(10)
+	test.That(t, []int{minX, maxX, minY, maxY}, test.ShouldResemble, []int{163, 163, 395, 396})
+	test.That(t, valid.TileCount(), test.ShouldEqual, 14)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *MapTileAreaConfig)
+		errStr string
+	}{
+		{"no name", func(c *MapTileAreaConfig) { c.Name = "" }, "name"},
+		{"bad name", func(c *MapTileAreaConfig) { c.Name = "north field" }, "invalid name"},
+		{"no url", func(c *MapTileAreaConfig) { c.URL = "" }, "url"},
+		{"no placeholder", func(c *MapTileAreaConfig) { c.URL = "https://tiles.example.com/{z}/{x}.png" }, "url must contain {y}"},
+		{"latitude out of range", func(c *MapTileAreaConfig) { c.MaxLatitude = 89 }, "latitudes"},
+		{"latitudes reversed", func(c *MapTileAreaConfig) { c.MinLatitude = 38 }, "latitudes"},
+		{"longitudes reversed", func(c *MapTileAreaConfig) { c.MaxLongitude = -123 }, "longitudes"},
+		{"zoom out of range", func(c *MapTileAreaConfig) { c.MaxZoom = 21 }, "zoom levels"},
+		{"zooms reversed", func(c *MapTileAreaConfig) { c.MinZoom = 11 }, "zoom levels"},
+		{"too many tiles", func(c *MapTileAreaConfig) { c.MaxZoom = 20 }, "more than the 100000 allowed"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate("map_tiles.0")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
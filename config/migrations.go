@@ -0,0 +1,218 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// versionKey is the key of the version of the config format a config was written for.
+// Configs without one predate versioning and are version 0.
+const versionKey = "version"
+
+// A Migration upgrades configs written for the previous version of the config format, such as
+// by renaming a deprecated attribute key, so that stale configs keep working as the format
+// changes.
+type Migration struct {
+	// Version is the version of the config format the migration upgrades configs to.
+	Version int
+	// Description says what the migration changes, for the warning listing the migrations
+	// applied to a config.
+	Description string
+	// Migrate upgrades the decoded JSON config in place and returns whether it changed it.
+	// Numbers in the config are json.Number values.
+	Migrate func(cfg map[string]interface{}) (bool, error)
+}
+
+var (
+	migrationsMu sync.Mutex
+	migrations   = map[int]Migration{}
+)
+
+// RegisterMigration registers a migration applied to configs of older versions when they are
+// read. Each migration must have its own version.
+func RegisterMigration(m Migration) {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	if m.Version < 1 || m.Description == "" || m.Migrate == nil {
+		panic(errors.Errorf("invalid config migration to version %d", m.Version))
+	}
+	if _, ok := migrations[m.Version]; ok {
+		panic(errors.Errorf("trying to register two config migrations to version %d", m.Version))
+	}
+	migrations[m.Version] = m
+}
+
+// registeredMigrations returns the registered migrations, oldest first.
+func registeredMigrations() []Migration {
+	migrationsMu.Lock()
+	defer migrationsMu.Unlock()
+	out := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		out = append(out, m)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Version < out[j].Version
+	})
+	return out
+}
+
+// LatestVersion returns the version of the config format that configs are upgraded to, which
+// is that of the newest registered migration.
+func LatestVersion() int {
+	all := registeredMigrations()
+	if len(all) == 0 {
+		return 0
+	}
+	return all[len(all)-1].Version
+}
+
+// migrateConfig returns the JSON config upgraded by the registered migrations it predates,
+// logging a warning listing those that changed it.
+func migrateConfig(data []byte, logger logging.Logger) ([]byte, error) {
+	raw, err := decodeRaw(data)
+	if err != nil {
+		// left to the decoding of the config to report.
+		return data, nil //nolint:nilerr
+	}
+	changed, err := applyMigrations(raw, registeredMigrations(), logger)
+	if err != nil || !changed {
+		return data, err
+	}
+	return json.Marshal(raw)
+}
+
+// applyMigrations upgrades the decoded JSON config by the given migrations, oldest first, that
+// are newer than its version, and returns whether it changed it. The version of the config is
+// left as written, so that it keeps saying what the config was written for.
+func applyMigrations(raw map[string]interface{}, all []Migration, logger logging.Logger) (bool, error) {
+	version := 0
+	if value, ok := raw[versionKey]; ok {
+		number, ok := value.(json.Number)
+		if !ok {
+			return false, errors.Errorf("%s must be a number", versionKey)
+		}
+		v, err := number.Int64()
+		if err != nil || v < 0 {
+			return false, errors.Errorf("%s must be a non-negative integer", versionKey)
+		}
+		version = int(v)
+	}
+	latest := 0
+	if len(all) != 0 {
+		latest = all[len(all)-1].Version
+	}
+	if version > latest {
+		logger.Warnw("config is for a newer version of the config format than this robot supports; settings it does not know are ignored",
+			"version", version, "supported_version", latest)
+		return false, nil
+	}
+	if version == latest {
+		return false, nil
+	}
+
+	var applied []string
+	for _, m := range all {
+		if m.Version <= version {
+			continue
+		}
+		changed, err := m.Migrate(raw)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to migrate config to version %d", m.Version)
+		}
+		if changed {
+			applied = append(applied, fmt.Sprintf("%d: %s", m.Version, m.Description))
+		}
+	}
+	if len(applied) == 0 {
+		return false, nil
+	}
+	logger.Warnw(fmt.Sprintf("upgraded config written for an older version of the config format; "+
+		"update it and set its %s to %d to stop this warning", versionKey, latest),
+		"version", version, "migrations", applied)
+	return true, nil
+}
+
+// resourceEntries returns the components and services of the decoded JSON config, with the
+// API type of each list.
+func resourceEntries(raw map[string]interface{}) map[string][]map[string]interface{} {
+	entries := map[string][]map[string]interface{}{}
+	for key, apiType := range map[string]string{"components": resource.APITypeComponentName, "services": resource.APITypeServiceName} {
+		list, ok := raw[key].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, item := range list {
+			if entry, ok := item.(map[string]interface{}); ok {
+				entries[apiType] = append(entries[apiType], entry)
+			}
+		}
+	}
+	return entries
+}
+
+// RenameAttribute returns the Migrate function of a migration renaming a deprecated attribute
+// key of the resources of the given API and model. A value under the new key is kept over one
+// under the deprecated key.
+func RenameAttribute(api resource.API, model resource.Model, from, to string) func(map[string]interface{}) (bool, error) {
+	return func(raw map[string]interface{}) (bool, error) {
+		changed := false
+		for _, entry := range resourceEntries(raw)[api.Type.Name] {
+			apiStr, _ := entry["api"].(string)
+			if entryAPI, err := resource.NewAPIFromString(apiStr); err != nil || entryAPI != api {
+				continue
+			}
+			modelStr, _ := entry["model"].(string)
+			if entryModel, err := resource.NewModelFromString(modelStr); err != nil || entryModel != model {
+				continue
+			}
+			attrs, ok := entry["attributes"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			value, ok := attrs[from]
+			if !ok {
+				continue
+			}
+			if _, ok := attrs[to]; !ok {
+				attrs[to] = value
+			}
+			delete(attrs, from)
+			changed = true
+		}
+		return changed, nil
+	}
+}
+
+func init() {
+	RegisterMigration(Migration{
+		Version:     1,
+		Description: `replaced the "namespace" and "type" of resources with their "api"`,
+		Migrate: func(raw map[string]interface{}) (bool, error) {
+			changed := false
+			for apiType, entries := range resourceEntries(raw) {
+				for _, entry := range entries {
+					subtype, ok := entry["type"].(string)
+					if _, hasAPI := entry["api"]; hasAPI || !ok || subtype == "" {
+						continue
+					}
+					namespace, _ := entry["namespace"].(string)
+					if namespace == "" {
+						namespace = string(resource.APINamespaceRDK)
+					}
+					entry["api"] = resource.APINamespace(namespace).WithType(apiType).WithSubtype(subtype).String()
+					delete(entry, "namespace")
+					delete(entry, "type")
+					changed = true
+				}
+			}
+			return changed, nil
+		},
+	})
+}
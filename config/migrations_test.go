@@ -0,0 +1,75 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestMigrations(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	sensorAPI := resource.APINamespaceRDK.WithComponentType("sensor")
+	ultrasonic := resource.DefaultModelFamily.WithModel("ultrasonic")
+	all := []Migration{
+		registeredMigrations()[0],
+		{Version: 2, Description: "renamed trigger to trigger_pin", Migrate: RenameAttribute(sensorAPI, ultrasonic, "trigger", "trigger_pin")},
+	}
+
+	raw, err := decodeRaw([]byte(`{
+		"components": [
+			{"name": "s1", "type": "sensor", "model": "ultrasonic", "attributes": {"trigger": "11"}},
+			{"name": "s2", "api": "rdk:component:sensor", "model": "ultrasonic", "attributes": {"trigger": "13", "trigger_pin": "15"}},
+			{"name": "s3", "api": "rdk:component:sensor", "model": "fake", "attributes": {"trigger": "16"}}
+		]
+	}`))
+	test.That(t, err, test.ShouldBeNil)
+	observedLogger, logs := logging.NewObservedTestLogger(t)
+	changed, err := applyMigrations(raw, all, observedLogger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, changed, test.ShouldBeTrue)
+	components := raw["components"].([]interface{})
+	test.That(t, components[0], test.ShouldResemble, map[string]interface{}{
+		"name": "s1", "api": "rdk:component:sensor", "model": "ultrasonic",
+		"attributes": map[string]interface{}{"trigger_pin": "11"},
+	})
+	// values under the new key are kept, and other models are left alone.
+	test.That(t, components[1].(map[string]interface{})["attributes"], test.ShouldResemble, map[string]interface{}{"trigger_pin": "15"})
+	test.That(t, components[2].(map[string]interface{})["attributes"], test.ShouldResemble, map[string]interface{}{"trigger": "16"})
+	test.That(t, logs.FilterMessageSnippet("set its version to 2").Len(), test.ShouldEqual, 1)
+
+	// configs of the latest version, or that no migration changes, are left as is.
+	for _, data := range []string{
+		`{"version": 2, "components": [{"name": "s1", "type": "sensor", "model": "ultrasonic"}]}`,
+		`{"version": 1, "components": [{"name": "s1", "api": "rdk:component:sensor", "model": "ultrasonic"}]}`,
+		`{"version": 3}`,
+	} {
+		raw, err := decodeRaw([]byte(data))
+		test.That(t, err, test.ShouldBeNil)
+		changed, err := applyMigrations(raw, all, logger)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, changed, test.ShouldBeFalse)
+	}
+
+	raw, err = decodeRaw([]byte(`{"version": -1}`))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = applyMigrations(raw, all, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "non-negative")
+
+	test.That(t, func() {
+		RegisterMigration(Migration{Version: 1, Description: "again", Migrate: all[1].Migrate})
+	}, test.ShouldPanic)
+
+	// stale configs are upgraded when read, keeping their version.
+	cfg, err := FromReader(context.Background(), "", strings.NewReader(`{
+		"services": [{"name": "m", "namespace": "acme", "type": "navigation", "model": "fake"}]
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cfg.Version, test.ShouldEqual, 0)
+	test.That(t, cfg.Services[0].API, test.ShouldResemble, resource.APINamespace("acme").WithServiceType("navigation"))
+}
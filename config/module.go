@@ -38,6 +38,8 @@ type Module struct {
 	// Environment contains additional variables that are passed to the module process when it is started.
 	// They overwrite existing environment variables.
 	Environment map[string]string `json:"env,omitempty"`
+	// Budget limits the CPU and memory the module process may use.
+	Budget *ModuleBudget `json:"budget,omitempty"`
 
 	// Status refers to the validations done in the APP to make sure a module is configured correctly
 	Status           *AppValidationStatus `json:"status"`
@@ -93,6 +95,59 @@ func (m *Module) validate(path string) error {
 		return errors.Errorf("module %s cannot use the reserved name of %s", path, reservedModuleName)
 	}
 
+	if m.Budget != nil {
+		if err := m.Budget.Validate(fmt.Sprintf("%s.budget", path)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// BudgetAction is what is done when a module exceeds its budget.
+type BudgetAction string
+
+const (
+	// BudgetActionWarn logs a warning.
+	BudgetActionWarn BudgetAction = "warn"
+	// BudgetActionThrottle pauses the module process for part of the time to keep its CPU
+	// usage within budget. Memory usage cannot be throttled and is only warned about.
+	BudgetActionThrottle BudgetAction = "throttle"
+	// BudgetActionRestart restarts the module process once it has exceeded its budget for
+	// the grace period.
+	BudgetActionRestart BudgetAction = "restart"
+)
+
+// ModuleBudget is the CPU and memory a module process may use. Usage is only measured on
+// Linux.
+type ModuleBudget struct {
+	// CPUPercent is the CPU usage allowed, as a percentage of one core. Unlimited if zero.
+	CPUPercent float64 `json:"cpu_percent,omitempty"`
+	// MemoryMB is the resident memory allowed, in megabytes. Unlimited if zero.
+	MemoryMB float64 `json:"memory_mb,omitempty"`
+	// Action is what is done when the budget is exceeded. Defaults to warn.
+	Action BudgetAction `json:"action,omitempty"`
+	// GracePeriodSec is how long the budget may be exceeded before the module is restarted.
+	// Defaults to 10 seconds.
+	GracePeriodSec float64 `json:"grace_period_sec,omitempty"`
+}
+
+// Validate ensures all parts of the budget are valid.
+func (b *ModuleBudget) Validate(path string) error {
+	if b.CPUPercent < 0 {
+		return resource.NewConfigValidationError(path, errors.New("cpu_percent cannot be negative"))
+	}
+	if b.MemoryMB < 0 {
+		return resource.NewConfigValidationError(path, errors.New("memory_mb cannot be negative"))
+	}
+	if b.GracePeriodSec < 0 {
+		return resource.NewConfigValidationError(path, errors.New("grace_period_sec cannot be negative"))
+	}
+	switch b.Action {
+	case "", BudgetActionWarn, BudgetActionThrottle, BudgetActionRestart:
+	default:
+		return resource.NewConfigValidationError(path, errors.Errorf("unknown action %q", b.Action))
+	}
 	return nil
 }
 
@@ -112,3 +112,31 @@ func testWriteJSON(t *testing.T, path string, value any) {
 	err = encoder.Encode(value)
 	test.That(t, err, test.ShouldBeNil)
 }
+
+func TestModuleBudget(t *testing.T) {
+	var mod Module
+	err := json.Unmarshal([]byte(`{
+		"name": "mod",
+		"type": "registry",
+		"module_id": "org:mod",
+		"budget": {"cpu_percent": 50, "memory_mb": 256, "action": "restart", "grace_period_sec": 30}
+	}`), &mod)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mod.Budget, test.ShouldResemble, &ModuleBudget{
+		CPUPercent: 50, MemoryMB: 256, Action: BudgetActionRestart, GracePeriodSec: 30,
+	})
+	test.That(t, mod.Validate("modules.0"), test.ShouldBeNil)
+
+	for _, invalid := range []ModuleBudget{
+		{CPUPercent: -1},
+		{MemoryMB: -1},
+		{GracePeriodSec: -1},
+		{Action: "pause"},
+	} {
+		mod.Budget = &invalid
+		mod.alreadyValidated = false
+		err := mod.Validate("modules.0")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "modules.0.budget")
+	}
+}
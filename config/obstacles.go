@@ -0,0 +1,80 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// ObstacleConfig describes a named set of obstacles, such as the walls and tables of a
+// workcell, and of transforms the robot's frame system is extended with, which the motion
+// service avoids and the frame system includes on every request, on top of the world state
+// given with each request.
+type ObstacleConfig struct {
+	// Name identifies the set of obstacles. Its geometries without a label are labeled after
+	// it.
+	Name string `json:"name"`
+	// Parent is the frame the geometries are in. Defaults to the world frame.
+	Parent     string                       `json:"parent,omitempty"`
+	Geometries []spatialmath.GeometryConfig `json:"geometries,omitempty"`
+	Transforms []referenceframe.LinkConfig  `json:"transforms,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (c *ObstacleConfig) Validate(path string) error {
+	if c.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if err := utils.ValidateResourceName(c.Name); err != nil {
+		return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid name"))
+	}
+	if len(c.Geometries) == 0 && len(c.Transforms) == 0 {
+		return resource.NewConfigValidationError(path, errors.New("geometries or transforms are required"))
+	}
+	for idx, transform := range c.Transforms {
+		if transform.ID == "" || transform.ID == referenceframe.World {
+			return resource.NewConfigValidationError(path, errors.Errorf("transform %d must have a name other than %q",
+				idx, referenceframe.World))
+		}
+		if transform.Parent == "" {
+			return resource.NewConfigValidationError(path, errors.Errorf("transform %q must have a parent", transform.ID))
+		}
+	}
+	if _, _, err := c.ParseConfig(); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	return nil
+}
+
+// ParseConfig returns the geometries of the set, in their parent frame, and its transforms.
+func (c *ObstacleConfig) ParseConfig() (*referenceframe.GeometriesInFrame, []*referenceframe.LinkInFrame, error) {
+	parent := c.Parent
+	if parent == "" {
+		parent = referenceframe.World
+	}
+	geometries := make([]spatialmath.Geometry, 0, len(c.Geometries))
+	for idx := range c.Geometries {
+		geometry, err := c.Geometries[idx].ParseConfig()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid geometry %d", idx)
+		}
+		if geometry.Label() == "" {
+			geometry.SetLabel(fmt.Sprintf("%s_%d", c.Name, idx))
+		}
+		geometries = append(geometries, geometry)
+	}
+	transforms := make([]*referenceframe.LinkInFrame, 0, len(c.Transforms))
+	for idx := range c.Transforms {
+		transform, err := c.Transforms[idx].ParseConfig()
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "invalid transform %q", c.Transforms[idx].ID)
+		}
+		transforms = append(transforms, transform)
+	}
+	return referenceframe.NewGeometriesInFrame(parent, geometries), transforms, nil
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestObstacleConfigValidate(t *testing.T) {
+	valid := ObstacleConfig{
+		Name: "workcell",
+		Geometries: []spatialmath.GeometryConfig{
+			{Type: spatialmath.BoxType, X: 1000, Y: 1000, Z: 10, TranslationOffset: r3.Vector{Z: -5}},
+			{Type: spatialmath.SphereType, R: 50, Label: "lamp"},
+		},
+		Transforms: []referenceframe.LinkConfig{{ID: "table", Parent: referenceframe.World, Translation: r3.Vector{X: 500}}},
+	}
+	test.That(t, valid.Validate("obstacles.0"), test.ShouldBeNil)
+
+	geometries, transforms, err := valid.ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometries.Parent(), test.ShouldEqual, referenceframe.World)
+	test.That(t, geometries.Geometries(), test.ShouldHaveLength, 2)
+	test.That(t, geometries.Geometries()[0].Label(), test.ShouldEqual, "workcell_0")
+	test.That(t, geometries.Geometries()[1].Label(), test.ShouldEqual, "lamp")
+	test.That(t, transforms, test.ShouldHaveLength, 1)
+	test.That(t, transforms[0].Name(), test.ShouldEqual, "table")
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *ObstacleConfig)
+		errStr string
+	}{
+		{"no name", func(c *ObstacleConfig) { c.Name = "" }, "name"},
+		{"bad name", func(c *ObstacleConfig) { c.Name = "work cell" }, "invalid name"},
+		{"empty", func(c *ObstacleConfig) { c.Geometries, c.Transforms = nil, nil }, "geometries or transforms are required"},
+		{"bad geometry", func(c *ObstacleConfig) {
+			c.Geometries = []spatialmath.GeometryConfig{{Type: spatialmath.BoxType, X: -1, Y: 1, Z: 1}}
+		}, "invalid geometry 0"},
+		{"world transform", func(c *ObstacleConfig) {
+			c.Transforms = []referenceframe.LinkConfig{{ID: referenceframe.World, Parent: "table"}}
+		}, "name other than"},
+		{"orphan transform", func(c *ObstacleConfig) {
+			c.Transforms = []referenceframe.LinkConfig{{ID: "table"}}
+		}, "must have a parent"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate("obstacles.0")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
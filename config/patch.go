@@ -0,0 +1,125 @@
+package config
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// A Patch changes the components and services of a config, so that a robot can be updated
+// incrementally without sending its whole config.
+type Patch struct {
+	Components ResourcePatch `json:"components"`
+	Services   ResourcePatch `json:"services"`
+}
+
+// A ResourcePatch changes the resources of one kind of a config, identified by name. Resources
+// are removed first, then updated, then have their attributes changed, and then added.
+type ResourcePatch struct {
+	// Remove names the resources to remove.
+	Remove []string `json:"remove,omitempty"`
+	// Update replaces the resources of the same names.
+	Update []resource.Config `json:"update,omitempty"`
+	// Attributes sets the attributes of the resources of the given names. An attribute set to
+	// null is removed.
+	Attributes map[string]utils.AttributeMap `json:"attributes,omitempty"`
+	// Add adds resources, whose names must not be taken.
+	Add []resource.Config `json:"add,omitempty"`
+}
+
+// ApplyPatch returns a copy of the config with the patch applied, or an error if the patch
+// does not apply to it, such as when it removes a resource that is not configured. Resources
+// the patch changes must be processed again before a robot is configured with them, since
+// their attributes are not converted.
+func (c *Config) ApplyPatch(p Patch) (*Config, error) {
+	out := *c
+	var err error
+	if out.Components, err = applyResourcePatch(c.Components, p.Components, resource.APITypeComponentName); err != nil {
+		return nil, errors.Wrap(err, "error patching components")
+	}
+	if out.Services, err = applyResourcePatch(c.Services, p.Services, resource.APITypeServiceName); err != nil {
+		return nil, errors.Wrap(err, "error patching services")
+	}
+	return &out, nil
+}
+
+func applyResourcePatch(confs []resource.Config, p ResourcePatch, apiType string) ([]resource.Config, error) {
+	out := slices.Clone(confs)
+	index := func(name string) int {
+		return slices.IndexFunc(out, func(conf resource.Config) bool { return conf.Name == name })
+	}
+
+	for _, name := range p.Remove {
+		idx := index(name)
+		if idx == -1 {
+			return nil, errors.Errorf("cannot remove %q: no such resource", name)
+		}
+		out = slices.Delete(out, idx, idx+1)
+	}
+	for _, conf := range p.Update {
+		idx := index(conf.Name)
+		if idx == -1 {
+			return nil, errors.Errorf("cannot update %q: no such resource", conf.Name)
+		}
+		conf.AdjustPartialNames(apiType)
+		out[idx] = conf
+	}
+	names := make([]string, 0, len(p.Attributes))
+	for name := range p.Attributes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		idx := index(name)
+		if idx == -1 {
+			return nil, errors.Errorf("cannot set attributes of %q: no such resource", name)
+		}
+		conf, err := withAttributes(out[idx], p.Attributes[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot set attributes of %q", name)
+		}
+		out[idx] = conf
+	}
+	for _, conf := range p.Add {
+		conf.AdjustPartialNames(apiType)
+		if conf.Name == "" {
+			return nil, errors.New("cannot add a resource without a name")
+		}
+		if index(conf.Name) != -1 {
+			return nil, errors.Errorf("cannot add %q: a resource of the same name exists", conf.Name)
+		}
+		out = append(out, conf)
+	}
+	return out, nil
+}
+
+// withAttributes returns a copy of the resource config with the given attributes set. Only
+// the public fields are copied, so that the copy is validated and its attributes converted
+// again.
+func withAttributes(conf resource.Config, attrs utils.AttributeMap) (resource.Config, error) {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return resource.Config{}, err
+	}
+	var out resource.Config
+	if err := json.Unmarshal(data, &out); err != nil {
+		return resource.Config{}, err
+	}
+	merged := utils.AttributeMap{}
+	for k, v := range conf.Attributes {
+		merged[k] = v
+	}
+	for k, v := range attrs {
+		if v == nil {
+			delete(merged, k)
+			continue
+		}
+		merged[k] = v
+	}
+	out.Attributes = merged
+	return out, nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+func TestApplyPatch(t *testing.T) {
+	var cfg Config
+	test.That(t, json.Unmarshal([]byte(`{
+		"components": [
+			{"name": "arm1", "type": "arm", "model": "fake", "attributes": {"arm-model": "ur5e", "speed": 10}},
+			{"name": "motor1", "type": "motor", "model": "fake"},
+			{"name": "motor2", "type": "motor", "model": "fake"}
+		],
+		"services": [{"name": "nav", "type": "navigation", "model": "builtin"}]
+	}`), &cfg), test.ShouldBeNil)
+
+	var patch Patch
+	test.That(t, json.Unmarshal([]byte(`{
+		"components": {
+			"remove": ["motor1"],
+			"update": [{"name": "motor2", "type": "motor", "model": "fake", "attributes": {"max_rpm": 60}}],
+			"attributes": {"arm1": {"speed": 20, "arm-model": null}},
+			"add": [{"name": "board1", "type": "board", "model": "fake"}]
+		},
+		"services": {"remove": ["nav"]}
+	}`), &patch), test.ShouldBeNil)
+	patched, err := cfg.ApplyPatch(patch)
+	test.That(t, err, test.ShouldBeNil)
+
+	names := []string{}
+	for _, conf := range patched.Components {
+		names = append(names, conf.Name)
+	}
+	test.That(t, names, test.ShouldResemble, []string{"arm1", "motor2", "board1"})
+	test.That(t, patched.Components[0].Attributes, test.ShouldResemble, utils.AttributeMap{"speed": 20.0})
+	test.That(t, patched.Components[0].API, test.ShouldResemble, cfg.Components[0].API)
+	test.That(t, patched.Components[1].Attributes, test.ShouldResemble, utils.AttributeMap{"max_rpm": 60.0})
+	test.That(t, patched.Components[2].API.Type.Namespace, test.ShouldEqual, resource.APINamespaceRDK)
+	test.That(t, patched.Services, test.ShouldBeEmpty)
+
+	// the patched config is a copy.
+	test.That(t, cfg.Components, test.ShouldHaveLength, 3)
+	test.That(t, cfg.Components[0].Attributes, test.ShouldResemble, utils.AttributeMap{"arm-model": "ur5e", "speed": 10.0})
+	test.That(t, cfg.Services, test.ShouldHaveLength, 1)
+
+	for _, tc := range []struct {
+		name   string
+		patch  Patch
+		errStr string
+	}{
+		{"remove missing", Patch{Components: ResourcePatch{Remove: []string{"nope"}}}, `cannot remove "nope"`},
+		{
+			"update missing",
+			Patch{Services: ResourcePatch{Update: []resource.Config{{Name: "nope"}}}},
+			`cannot update "nope"`,
+		},
+		{
+			"attributes of missing",
+			Patch{Components: ResourcePatch{Attributes: map[string]utils.AttributeMap{"nope": {}}}},
+			`cannot set attributes of "nope"`,
+		},
+		{
+			"add existing",
+			Patch{Components: ResourcePatch{Add: []resource.Config{{Name: "arm1"}}}},
+			`cannot add "arm1"`,
+		},
+		{"add unnamed", Patch{Components: ResourcePatch{Add: []resource.Config{{}}}}, "without a name"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := cfg.ApplyPatch(tc.patch)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
@@ -65,6 +65,11 @@ func (c *Config) ReplacePlaceholders() error {
 		}
 	}
 
+	for i, firmware := range c.Firmware {
+		c.Firmware[i].Artifact, err = visitor.replacePlaceholders(firmware.Artifact)
+		allErrs = multierr.Append(allErrs, err)
+	}
+
 	return multierr.Append(visitor.AllErrors, allErrs)
 }
 
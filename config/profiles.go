@@ -0,0 +1,72 @@
+package config
+
+import (
+	"regexp"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+var cpuGovernorRegexp = regexp.MustCompile(`^[a-z]+$`)
+
+// ProfileConfig describes a runtime profile of a robot, such as a "patrol" profile that lowers
+// camera frame rates and capture rates to extend battery life and an "inspect" profile that
+// restores them. The robot switches between its profiles at runtime, applying all of a
+// profile's settings in one reconfiguration.
+type ProfileConfig struct {
+	// Name identifies the profile.
+	Name string `json:"name"`
+	// Components and Services set attributes of the components and services of the given
+	// names, such as stream parameters, capture rates, and telemetry intervals. An attribute
+	// set to null is removed. Attributes a profile does not set keep their configured values.
+	Components map[string]utils.AttributeMap `json:"components,omitempty"`
+	Services   map[string]utils.AttributeMap `json:"services,omitempty"`
+	// CPUGovernor is the CPU frequency governor, such as "powersave" or "performance", the
+	// robot sets on Linux when switching to the profile. The governor is left as is if unset.
+	CPUGovernor string `json:"cpu_governor,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (c *ProfileConfig) Validate(path string) error {
+	if c.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if err := utils.ValidateResourceName(c.Name); err != nil {
+		return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid name"))
+	}
+	if c.CPUGovernor != "" && !cpuGovernorRegexp.MatchString(c.CPUGovernor) {
+		return resource.NewConfigValidationError(path, errors.Errorf("invalid cpu_governor %q", c.CPUGovernor))
+	}
+	return nil
+}
+
+// FindProfile finds a profile by name.
+func (c *Config) FindProfile(name string) (*ProfileConfig, bool) {
+	for idx := range c.Profiles {
+		if c.Profiles[idx].Name == name {
+			return &c.Profiles[idx], true
+		}
+	}
+	return nil, false
+}
+
+// WithProfile returns a copy of the config with the attributes set by the named profile, or
+// an error if there is no such profile or it sets attributes of resources that are not
+// configured. As with patches, the resources the profile changes must be processed again
+// before a robot is configured with them.
+func (c *Config) WithProfile(name string) (*Config, error) {
+	profile, ok := c.FindProfile(name)
+	if !ok {
+		return nil, errors.Errorf("no profile named %q", name)
+	}
+	out, err := c.ApplyPatch(Patch{
+		Components: ResourcePatch{Attributes: profile.Components},
+		Services:   ResourcePatch{Attributes: profile.Services},
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "cannot apply profile %q", name)
+	}
+	return out, nil
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+func TestWithProfile(t *testing.T) {
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:       "cam",
+				API:        resource.APINamespaceRDK.WithComponentType("camera"),
+				Model:      resource.DefaultModelFamily.WithModel("fake"),
+				Attributes: utils.AttributeMap{"frame_rate": 30, "width": 640},
+			},
+		},
+		Services: []resource.Config{
+			{
+				Name:       "data",
+				API:        resource.APINamespaceRDK.WithServiceType("data_manager"),
+				Model:      resource.DefaultServiceModel,
+				Attributes: utils.AttributeMap{"capture_frequency_hz": 10},
+			},
+		},
+		Profiles: []config.ProfileConfig{
+			{
+				Name:       "patrol",
+				Components: map[string]utils.AttributeMap{"cam": {"frame_rate": 5}},
+				Services:   map[string]utils.AttributeMap{"data": {"capture_frequency_hz": nil}},
+			},
+			{Name: "missing", Components: map[string]utils.AttributeMap{"nope": {"frame_rate": 5}}},
+		},
+	}
+
+	profiled, err := cfg.WithProfile("patrol")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, profiled.Components[0].Attributes, test.ShouldResemble, utils.AttributeMap{"frame_rate": 5, "width": 640})
+	test.That(t, profiled.Services[0].Attributes, test.ShouldResemble, utils.AttributeMap{})
+	// the config is left as is.
+	test.That(t, cfg.Components[0].Attributes, test.ShouldResemble, utils.AttributeMap{"frame_rate": 30, "width": 640})
+
+	_, err = cfg.WithProfile("missing")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `cannot set attributes of "nope"`)
+
+	_, err = cfg.WithProfile("nope")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no profile named "nope"`)
+}
+
+func TestProfilesEnsure(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	cfg := &config.Config{
+		Profiles: []config.ProfileConfig{
+			{Name: "patrol", CPUGovernor: "powersave"},
+			{Name: "patrol"},
+			{Name: "bad", CPUGovernor: "../../etc"},
+		},
+		Profile: "bad",
+	}
+	test.That(t, cfg.Ensure(false, logger), test.ShouldBeNil)
+	test.That(t, cfg.Profiles, test.ShouldResemble, []config.ProfileConfig{{Name: "patrol", CPUGovernor: "powersave"}})
+	test.That(t, cfg.Profile, test.ShouldEqual, "")
+
+	cfg = &config.Config{
+		Profiles:            []config.ProfileConfig{{Name: "patrol"}},
+		Profile:             "inspect",
+		DisablePartialStart: true,
+	}
+	err := cfg.Ensure(false, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no profile named "inspect"`)
+}
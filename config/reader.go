@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/a8m/envsubst"
@@ -20,6 +21,7 @@ import (
 	"go.viam.com/utils/artifact"
 	"go.viam.com/utils/rpc"
 	"golang.org/x/sys/cpu"
+	"gopkg.in/yaml.v3"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
@@ -228,7 +230,7 @@ func readFromCloud(
 	}
 
 	// process the config
-	cfg, err := processConfigFromCloud(unprocessedConfig, logger)
+	cfg, err := processConfigFromCloud(ctx, unprocessedConfig, logger)
 	if err != nil {
 		// If we cannot process the config from the cache we should clear it.
 		if cached {
@@ -349,13 +351,14 @@ func (tls *tlsConfig) readFromCache(id string, logger logging.Logger) error {
 	return nil
 }
 
-// Read reads a config from the given file.
+// Read reads a config from the given file, with the environment variables it references
+// expanded as by ReadFile.
 func Read(
 	ctx context.Context,
 	filePath string,
 	logger logging.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := ReadFile(filePath, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -369,7 +372,7 @@ func ReadLocalConfig(
 	filePath string,
 	logger logging.Logger,
 ) (*Config, error) {
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := ReadFile(filePath, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -377,8 +380,35 @@ func ReadLocalConfig(
 	return fromReader(ctx, filePath, bytes.NewReader(buf), logger, false)
 }
 
+// ReadFile returns the contents of the config file at the given path with the environment
+// variables it references expanded, so that the same config works on every machine, with
+// the serial ports, API keys, or data directories of each. $VAR and ${VAR} are the value of
+// VAR, ${VAR:-default} is default if VAR is unset or empty, and ${VAR-default} is default if
+// VAR is unset; $$ is a literal $. A variable that is not set and has no default expands to
+// an empty string, as it always has, with a warning.
+func ReadFile(filePath string, logger logging.Logger) ([]byte, error) {
+	//nolint:gosec
+	buf, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	expanded, err := envsubst.Bytes(buf)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to expand the environment variables of config %q", filePath)
+	}
+	if _, err := envsubst.BytesRestricted(buf, true, false); err != nil {
+		logger.Warnw("config references environment variables that are not set; expanding them to empty strings",
+			"path", filePath, "error", err)
+	}
+	return expanded, nil
+}
+
 // FromReader reads a config from the given reader and specifies
-// where, if applicable, the file the reader originated from.
+// where, if applicable, the file the reader originated from. The config is read as YAML if
+// that file has a .yaml or .yml extension, and as JSON otherwise. The config fragment files
+// listed in its "includes" are read relative to that file and merged into it, and the entries
+// of its lists with "for_each" parameters are expanded as templates. A config written for an
+// older version of the config format is upgraded by the registered migrations.
 func FromReader(
 	ctx context.Context,
 	originalPath string,
@@ -401,11 +431,30 @@ func fromReader(
 	unprocessedConfig := Config{
 		ConfigFilePath: originalPath,
 	}
-	err := json.NewDecoder(r).Decode(&unprocessedConfig)
+	if isYAML(originalPath) {
+		converted, err := yamlToJSON(r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode Config from yaml")
+		}
+		r = bytes.NewReader(converted)
+	}
+	data, err := io.ReadAll(r)
 	if err != nil {
+		return nil, err
+	}
+	if data, err = resolveIncludes(originalPath, data, logger); err != nil {
+		return nil, err
+	}
+	if data, err = expandTemplates(data); err != nil {
+		return nil, err
+	}
+	if data, err = migrateConfig(data, logger); err != nil {
+		return nil, err
+	}
+	if err := json.NewDecoder(bytes.NewReader(data)).Decode(&unprocessedConfig); err != nil {
 		return nil, errors.Wrapf(err, "failed to decode Config from json")
 	}
-	cfgFromDisk, err := processConfigLocalConfig(&unprocessedConfig, logger)
+	cfgFromDisk, err := processConfigLocalConfig(ctx, &unprocessedConfig, logger)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to process Config")
 	}
@@ -418,24 +467,43 @@ func fromReader(
 	return cfgFromDisk, err
 }
 
+// isYAML returns whether the config file at the given path is YAML rather than JSON.
+func isYAML(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlToJSON converts a YAML config to JSON, so that a YAML config has the same schema as a
+// JSON one and is decoded by the same code.
+func yamlToJSON(r io.Reader) ([]byte, error) {
+	var raw interface{}
+	if err := yaml.NewDecoder(r).Decode(&raw); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if raw == nil {
+		raw = map[string]interface{}{}
+	}
+	return json.Marshal(raw)
+}
+
 // processConfigFromCloud returns a copy of the current config with all attributes parsed
 // and config validated with the assumption the config came from the cloud.
 // Returns an error if the unprocessedConfig is non-valid.
-func processConfigFromCloud(unprocessedConfig *Config, logger logging.Logger) (*Config, error) {
-	return processConfig(unprocessedConfig, true, logger)
+func processConfigFromCloud(ctx context.Context, unprocessedConfig *Config, logger logging.Logger) (*Config, error) {
+	return processConfig(ctx, unprocessedConfig, true, logger)
 }
 
 // processConfigLocalConfig returns a copy of the current config with all attributes parsed
 // and config validated with the assumption the config came from a local file.
 // Returns an error if the unprocessedConfig is non-valid.
-func processConfigLocalConfig(unprocessedConfig *Config, logger logging.Logger) (*Config, error) {
-	return processConfig(unprocessedConfig, false, logger)
+func processConfigLocalConfig(ctx context.Context, unprocessedConfig *Config, logger logging.Logger) (*Config, error) {
+	return processConfig(ctx, unprocessedConfig, false, logger)
 }
 
 // processConfig processes the config passed in. The config can be either JSON or gRPC derived.
 // If any part of this function errors, the function will exit and no part of the new config will be returned
 // until it is corrected.
-func processConfig(unprocessedConfig *Config, fromCloud bool, logger logging.Logger) (*Config, error) {
+func processConfig(ctx context.Context, unprocessedConfig *Config, fromCloud bool, logger logging.Logger) (*Config, error) {
 	// Ensure validates the config but also substitutes in some defaults. Implicit dependencies for builtin resource
 	// models are not filled in until attributes are converted.
 	if err := unprocessedConfig.Ensure(fromCloud, logger); err != nil {
@@ -461,6 +529,13 @@ func processConfig(unprocessedConfig *Config, fromCloud bool, logger logging.Log
 		logger.Errorw("error during placeholder replacement", "err", err)
 	}
 
+	// secrets are resolved after copying so that the cached config keeps their references rather
+	// than their values. look at config/secrets.go for available providers. Like attribute
+	// conversion errors, a secret that cannot be resolved fails the config until it is corrected.
+	if err := cfg.ResolveSecrets(ctx); err != nil {
+		return nil, errors.Wrap(err, "error resolving secrets")
+	}
+
 	// See if default service already exists in the config and add them in if not. This code allows for default services to be
 	// defined under a name other than "builtin".
 	defaultServices := resource.DefaultServices()
@@ -277,7 +277,7 @@ func TestProcessConfig(t *testing.T) {
 		ConfigFilePath: "path",
 	}
 
-	cfg, err := processConfig(&unprocessedConfig, true, logger)
+	cfg, err := processConfig(context.Background(), &unprocessedConfig, true, logger)
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, *cfg, test.ShouldResemble, unprocessedConfig)
 }
@@ -360,7 +360,7 @@ func TestReadTLSFromCache(t *testing.T) {
 
 		// the config is missing several fields required to start the robot, but this
 		// should not prevent us from reading TLS information from it.
-		_, err = processConfigFromCloud(cfg, logger)
+		_, err = processConfigFromCloud(context.Background(), cfg, logger)
 		test.That(t, err, test.ShouldNotBeNil)
 		tls := tlsConfig{}
 		err = tls.readFromCache(robotPartID, logger)
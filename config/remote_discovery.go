@@ -0,0 +1,86 @@
+package config
+
+import (
+	"path"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// DefaultRemoteDiscoveryInterval is how often the local network is browsed for robots when
+// RemoteDiscoveryConfig.Interval is not set.
+const DefaultRemoteDiscoveryInterval = 10 * time.Second
+
+// RemoteDiscoveryConfig advertises the robot on the local network with multicast DNS and
+// discovers the robots advertised by others, which are candidate remotes. Discovered robots
+// whose names match AutoAdd are added as remotes of the robot, unless a configured remote
+// has the same name.
+type RemoteDiscoveryConfig struct {
+	// Name is the name the robot advertises itself with. Defaults to the hostname.
+	Name string `json:"name,omitempty"`
+	// DisableAdvertise stops the robot from advertising itself while it still discovers others.
+	DisableAdvertise bool `json:"disable_advertise,omitempty"`
+	// AutoAdd are patterns, as matched by path.Match, of the names of discovered robots that
+	// are added as remotes (e.g. "cell-*").
+	AutoAdd []string `json:"auto_add,omitempty"`
+	// Interval is how often the local network is browsed (e.g. "30s"). Defaults to
+	// DefaultRemoteDiscoveryInterval.
+	Interval string `json:"interval,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (c *RemoteDiscoveryConfig) Validate(path string) error {
+	if c.Name != "" {
+		if err := utils.ValidateResourceName(c.Name); err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid name"))
+		}
+	}
+	for _, pattern := range c.AutoAdd {
+		if _, err := matchName(pattern, ""); err != nil {
+			return resource.NewConfigValidationError(path, errors.Errorf("invalid auto_add pattern %q", pattern))
+		}
+	}
+	if c.Interval != "" {
+		interval, err := time.ParseDuration(c.Interval)
+		if err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid interval"))
+		}
+		if interval <= 0 {
+			return resource.NewConfigValidationError(path, errors.New("interval must be positive"))
+		}
+	}
+	return nil
+}
+
+// IntervalDuration returns how often the local network is browsed.
+func (c *RemoteDiscoveryConfig) IntervalDuration() time.Duration {
+	if c == nil || c.Interval == "" {
+		return DefaultRemoteDiscoveryInterval
+	}
+	interval, err := time.ParseDuration(c.Interval)
+	if err != nil || interval <= 0 {
+		return DefaultRemoteDiscoveryInterval
+	}
+	return interval
+}
+
+// ShouldAutoAdd returns whether the discovered robot of the given name is added as a remote.
+func (c *RemoteDiscoveryConfig) ShouldAutoAdd(name string) bool {
+	if c == nil {
+		return false
+	}
+	for _, pattern := range c.AutoAdd {
+		if matched, err := matchName(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchName matches the name of a robot against an auto_add pattern.
+func matchName(pattern, name string) (bool, error) {
+	return path.Match(pattern, name)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestRemoteDiscoveryConfigValidate(t *testing.T) {
+	var unset *RemoteDiscoveryConfig
+	test.That(t, unset.IntervalDuration(), test.ShouldEqual, DefaultRemoteDiscoveryInterval)
+	test.That(t, unset.ShouldAutoAdd("cell-1"), test.ShouldBeFalse)
+
+	valid := RemoteDiscoveryConfig{Name: "main", AutoAdd: []string{"cell-*", "camera"}, Interval: "30s"}
+	test.That(t, valid.Validate("remote_discovery"), test.ShouldBeNil)
+	test.That(t, valid.IntervalDuration(), test.ShouldEqual, 30*time.Second)
+	test.That(t, valid.ShouldAutoAdd("cell-1"), test.ShouldBeTrue)
+	test.That(t, valid.ShouldAutoAdd("camera"), test.ShouldBeTrue)
+	test.That(t, valid.ShouldAutoAdd("cameras"), test.ShouldBeFalse)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *RemoteDiscoveryConfig)
+		errStr string
+	}{
+		{"bad name", func(c *RemoteDiscoveryConfig) { c.Name = "main robot" }, "invalid name"},
+		{"bad pattern", func(c *RemoteDiscoveryConfig) { c.AutoAdd = []string{"cell-["} }, "invalid auto_add pattern"},
+		{"bad interval", func(c *RemoteDiscoveryConfig) { c.Interval = "often" }, "invalid interval"},
+		{"negative interval", func(c *RemoteDiscoveryConfig) { c.Interval = "-1s" }, "positive"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate("remote_discovery")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
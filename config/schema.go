@@ -0,0 +1,122 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/invopop/jsonschema"
+
+	"go.viam.com/rdk/resource"
+)
+
+// SchemaID is the $id of the JSON Schema of robot configs.
+const SchemaID = "https://go.viam.com/rdk/config"
+
+// resourceDefinition is the name of the definition of a component or service config in the
+// JSON Schema of robot configs.
+const resourceDefinition = "resource"
+
+// Schema returns a JSON Schema describing valid robot configs, for editors to validate and
+// complete configs with. The attributes of each component or service are described by the
+// schema of the native config of its model, for every model registered when it is called;
+// the attributes of models with no native config are left undescribed, as are the parts of a
+// robot config other than its components and services.
+func Schema() *jsonschema.Schema {
+	registrations := resource.RegisteredResources()
+	apiModels := make([]resource.APIModel, 0, len(registrations))
+	for apiModel := range registrations {
+		apiModels = append(apiModels, apiModel)
+	}
+	sort.Slice(apiModels, func(i, j int) bool {
+		if apiModels[i].API != apiModels[j].API {
+			return apiModels[i].API.String() < apiModels[j].API.String()
+		}
+		return apiModels[i].Model.String() < apiModels[j].Model.String()
+	})
+
+	definitions := jsonschema.Definitions{}
+	resourceSchema := &jsonschema.Schema{
+		Type:     "object",
+		Required: []string{"name", "model"},
+		Properties: schemaProperties(
+			"name", &jsonschema.Schema{Type: "string"},
+			"api", &jsonschema.Schema{Type: "string"},
+			"namespace", &jsonschema.Schema{Type: "string"},
+			"type", &jsonschema.Schema{Type: "string"},
+			"model", &jsonschema.Schema{Type: "string"},
+			"depends_on", &jsonschema.Schema{Type: "array", Items: &jsonschema.Schema{Type: "string"}},
+			"attributes", &jsonschema.Schema{Type: "object"},
+		),
+	}
+	for _, apiModel := range apiModels {
+		attributes := registrations[apiModel].AttributeSchema()
+		if attributes == nil {
+			continue
+		}
+		// models sharing a native config share its definition, named after its $id.
+		name := strings.ReplaceAll(strings.TrimPrefix(string(attributes.ID), "https://"), "/", ".")
+		if _, ok := definitions[name]; !ok {
+			definitions[name] = attributes
+		}
+		resourceSchema.AllOf = append(resourceSchema.AllOf, &jsonschema.Schema{
+			If:   modelSchema(apiModel),
+			Then: &jsonschema.Schema{Properties: schemaProperties("attributes", definitionRef(name))},
+		})
+	}
+	definitions[resourceDefinition] = resourceSchema
+
+	return &jsonschema.Schema{
+		Version: jsonschema.Version,
+		ID:      SchemaID,
+		Title:   "Robot config",
+		Type:    "object",
+		Properties: schemaProperties(
+			"components", &jsonschema.Schema{Type: "array", Items: definitionRef(resourceDefinition)},
+			"services", &jsonschema.Schema{Type: "array", Items: definitionRef(resourceDefinition)},
+		),
+		Definitions: definitions,
+	}
+}
+
+// modelSchema returns the schema matching the configs of resources of the given API and
+// model, whether their API is given in full or by its type and namespace, and whether a
+// builtin model is given in full or by its name alone.
+func modelSchema(apiModel resource.APIModel) *jsonschema.Schema {
+	models := []interface{}{apiModel.Model.String()}
+	if apiModel.Model.Family == resource.DefaultModelFamily {
+		models = append(models, apiModel.Model.Name)
+	}
+	byType := &jsonschema.Schema{
+		Required:   []string{"type"},
+		Properties: schemaProperties("type", &jsonschema.Schema{Const: apiModel.API.SubtypeName}),
+	}
+	if apiModel.API.Type.Namespace != resource.APINamespaceRDK {
+		byType.Required = append(byType.Required, "namespace")
+		byType.Properties.Set("namespace", &jsonschema.Schema{Const: string(apiModel.API.Type.Namespace)})
+	}
+	return &jsonschema.Schema{
+		Required:   []string{"model"},
+		Properties: schemaProperties("model", &jsonschema.Schema{Enum: models}),
+		AnyOf: []*jsonschema.Schema{
+			{
+				Required:   []string{"api"},
+				Properties: schemaProperties("api", &jsonschema.Schema{Const: apiModel.API.String()}),
+			},
+			byType,
+		},
+	}
+}
+
+// schemaProperties returns the properties of a schema from alternating names and schemas.
+func schemaProperties(namesAndSchemas ...interface{}) *orderedmap.OrderedMap {
+	properties := orderedmap.New()
+	for i := 0; i+1 < len(namesAndSchemas); i += 2 {
+		properties.Set(namesAndSchemas[i].(string), namesAndSchemas[i+1])
+	}
+	return properties
+}
+
+func definitionRef(name string) *jsonschema.Schema {
+	return &jsonschema.Schema{Ref: "#/$defs/" + name}
+}
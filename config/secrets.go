@@ -0,0 +1,431 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// secretReferenceRegexp matches the strings referencing a secret, such as
+// secret://env/MOTOR_API_KEY, whose values are resolved by the named provider.
+var secretReferenceRegexp = regexp.MustCompile(`^secret://(?P<provider>[\w-]+)/(?P<key>.+)$`)
+
+// secretProviderNameRegexp matches the valid names of secret providers.
+var secretProviderNameRegexp = regexp.MustCompile(`^[\w-]+$`)
+
+// secretProviderTimeout bounds how long resolving a single secret may take.
+const secretProviderTimeout = 10 * time.Second
+
+// secretManagerClient is the client of the secret managers of SecretProviderConfigs.
+var secretManagerClient = &http.Client{Timeout: secretProviderTimeout}
+
+// A SecretProvider returns the values of the secrets that config fields reference as
+// secret://<provider>/<key>.
+type SecretProvider interface {
+	// Secret returns the value of the secret with the given key.
+	Secret(ctx context.Context, key string) (string, error)
+}
+
+// SecretProviderFunc is a SecretProvider implemented by a function.
+type SecretProviderFunc func(ctx context.Context, key string) (string, error)
+
+// Secret calls the function.
+func (f SecretProviderFunc) Secret(ctx context.Context, key string) (string, error) {
+	return f(ctx, key)
+}
+
+var (
+	secretProvidersMu sync.RWMutex
+	secretProviders   = map[string]SecretProvider{
+		"env":  SecretProviderFunc(envSecret),
+		"file": SecretProviderFunc(fileSecret),
+	}
+)
+
+// RegisterSecretProvider registers a provider of the secrets referenced as
+// secret://<name>/<key>. The env provider, resolving keys as environment variables, and the
+// file provider, resolving keys as paths to files holding the secrets, are always registered.
+// It panics if a provider is already registered with the name.
+func RegisterSecretProvider(name string, provider SecretProvider) {
+	secretProvidersMu.Lock()
+	defer secretProvidersMu.Unlock()
+	if _, ok := secretProviders[name]; ok {
+		panic(errors.Errorf("secret provider %q already registered", name))
+	}
+	secretProviders[name] = provider
+}
+
+func lookupSecretProvider(name string) (SecretProvider, bool) {
+	secretProvidersMu.RLock()
+	defer secretProvidersMu.RUnlock()
+	provider, ok := secretProviders[name]
+	return provider, ok
+}
+
+func envSecret(ctx context.Context, key string) (string, error) {
+	value, ok := os.LookupEnv(key)
+	if !ok {
+		return "", errors.Errorf("environment variable %q is not set", key)
+	}
+	return value, nil
+}
+
+func fileSecret(ctx context.Context, key string) (string, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(key)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// IsSecretReference returns true if the passed string references a secret, as
+// secret://<provider>/<key>.
+func IsSecretReference(s string) bool {
+	return secretReferenceRegexp.MatchString(s)
+}
+
+// SecretProviderConfig describes a cloud secret manager from which the robot resolves the
+// secrets referenced as secret://<name>/<key>, by getting the URL with the key of the secret
+// in place of {key}.
+type SecretProviderConfig struct {
+	Name string `json:"name"`
+	// URL is the template of the URLs of the secrets, where {key} is replaced by the escaped
+	// key of a secret (e.g. "https://vault.example.com/v1/secret/data/{key}").
+	URL string `json:"url"`
+	// Headers are sent with every request, typically to authenticate the robot. Their values
+	// may themselves reference secrets of the env or file providers.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Field is the dot separated path of the value of the secret in the JSON responses (e.g.
+	// "data.data.value"). The whole response is the value of the secret when it is empty.
+	Field string `json:"field,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (c *SecretProviderConfig) Validate(path string) error {
+	if c.Name == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "name")
+	}
+	if !secretProviderNameRegexp.MatchString(c.Name) {
+		return resource.NewConfigValidationError(path, errors.Errorf("invalid name %q", c.Name))
+	}
+	if _, ok := lookupSecretProvider(c.Name); ok {
+		return resource.NewConfigValidationError(path, errors.Errorf("secret provider %q is already registered", c.Name))
+	}
+	if c.URL == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "url")
+	}
+	if !strings.Contains(c.URL, "{key}") {
+		return resource.NewConfigValidationError(path, errors.New("url must contain {key}"))
+	}
+	return nil
+}
+
+// Secret returns the value of the secret with the given key from the secret manager.
+func (c *SecretProviderConfig) Secret(ctx context.Context, key string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.ReplaceAll(c.URL, "{key}", url.PathEscape(key)), nil)
+	if err != nil {
+		return "", err
+	}
+	for name, value := range c.Headers {
+		if IsSecretReference(value) {
+			if value, err = resolveSecret(ctx, value, nil); err != nil {
+				return "", errors.Wrapf(err, "failed to resolve header %q", name)
+			}
+		}
+		req.Header.Set(name, value)
+	}
+	//nolint:bodyclose
+	resp, err := secretManagerClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer goutils.UncheckedErrorFunc(resp.Body.Close)
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("secret manager responded with %s", resp.Status)
+	}
+	if c.Field == "" {
+		return strings.TrimRight(string(body), "\r\n"), nil
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return "", errors.Wrap(err, "failed to decode secret manager response")
+	}
+	for _, field := range strings.Split(c.Field, ".") {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return "", errors.Errorf("secret manager response has no field %q", c.Field)
+		}
+		if value, ok = fields[field]; !ok {
+			return "", errors.Errorf("secret manager response has no field %q", c.Field)
+		}
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", errors.Errorf("field %q of secret manager response is not a string", c.Field)
+	}
+	return s, nil
+}
+
+// resolveSecret returns the value of the referenced secret from the registered providers or
+// those of the given configs.
+func resolveSecret(ctx context.Context, reference string, configured map[string]SecretProvider) (string, error) {
+	matches := secretReferenceRegexp.FindStringSubmatch(reference)
+	if matches == nil {
+		return "", errors.Errorf("invalid secret reference %q", reference)
+	}
+	name := matches[secretReferenceRegexp.SubexpIndex("provider")]
+	key := matches[secretReferenceRegexp.SubexpIndex("key")]
+	provider, ok := configured[name]
+	if !ok {
+		if provider, ok = lookupSecretProvider(name); !ok {
+			return "", errors.Errorf("unknown secret provider %q in %q", name, reference)
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, secretProviderTimeout)
+	defer cancel()
+	value, err := provider.Secret(ctx, key)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve secret %q", reference)
+	}
+	return value, nil
+}
+
+// ResolveSecrets replaces the references to secrets, written as secret://<provider>/<key>, in
+// the attributes of components and services, the environment of modules and the credentials
+// of remotes with the values of the secrets. The references are remembered by the paths they
+// are at so that WithSecretReferences returns the config as it was written, without the
+// secrets. References that fail to resolve are left as they are and their errors returned.
+func (c *Config) ResolveSecrets(ctx context.Context) error {
+	configured := make(map[string]SecretProvider, len(c.SecretProviders))
+	for i := range c.SecretProviders {
+		configured[c.SecretProviders[i].Name] = &c.SecretProviders[i]
+	}
+	resolver := &secretResolver{
+		ctx:        ctx,
+		providers:  configured,
+		resolved:   map[string]string{},
+		references: c.secretReferences,
+	}
+	if resolver.references == nil {
+		resolver.references = map[string]secretReference{}
+	}
+
+	for i, service := range c.Services {
+		if service.Attributes == nil {
+			continue
+		}
+		path := secretPath("services", service.Name, "attributes")
+		c.Services[i].Attributes = resolver.resolveValue(path, service.Attributes).(utils.AttributeMap)
+	}
+	for i, component := range c.Components {
+		if component.Attributes == nil {
+			continue
+		}
+		path := secretPath("components", component.Name, "attributes")
+		c.Components[i].Attributes = resolver.resolveValue(path, component.Attributes).(utils.AttributeMap)
+	}
+	for i, module := range c.Modules {
+		for envName, envVal := range module.Environment {
+			c.Modules[i].Environment[envName] = resolver.resolve(secretPath("modules", module.Name, "env", envName), envVal)
+		}
+	}
+	for i, remote := range c.Remotes {
+		c.Remotes[i].Secret = resolver.resolve(secretPath("remotes", remote.Name, "secret"), remote.Secret)
+		if remote.Auth.Credentials != nil {
+			creds := *remote.Auth.Credentials
+			creds.Payload = resolver.resolve(secretPath("remotes", remote.Name, "auth", "credentials", "payload"), creds.Payload)
+			c.Remotes[i].Auth.Credentials = &creds
+		}
+	}
+
+	if len(resolver.references) != 0 {
+		c.secretReferences = resolver.references
+	}
+	return resolver.allErrors
+}
+
+// WithSecretReferences returns a copy of the config whose resolved secrets are replaced by
+// the references they were resolved from, for outputs of the config that must not reveal
+// them, such as snapshots. Only the values at the paths the secrets were resolved at are
+// replaced, and only while they still hold the resolved values. It returns the config itself
+// if it references no secrets.
+func (c *Config) WithSecretReferences() *Config {
+	if len(c.secretReferences) == 0 {
+		return c
+	}
+	out := *c
+	restorer := secretRestorer{references: c.secretReferences}
+	restoreResources := func(kind string, confs []resource.Config) []resource.Config {
+		restored := make([]resource.Config, len(confs))
+		for i, conf := range confs {
+			restored[i] = conf
+			if conf.Attributes == nil {
+				continue
+			}
+			path := secretPath(kind, conf.Name, "attributes")
+			restored[i].Attributes = restorer.restoreValue(path, conf.Attributes).(utils.AttributeMap)
+		}
+		return restored
+	}
+	out.Components = restoreResources("components", c.Components)
+	out.Services = restoreResources("services", c.Services)
+
+	out.Modules = make([]Module, len(c.Modules))
+	for i, module := range c.Modules {
+		out.Modules[i] = module
+		if module.Environment == nil {
+			continue
+		}
+		out.Modules[i].Environment = make(map[string]string, len(module.Environment))
+		for envName, envVal := range module.Environment {
+			out.Modules[i].Environment[envName] = restorer.restore(secretPath("modules", module.Name, "env", envName), envVal)
+		}
+	}
+	out.Remotes = make([]Remote, len(c.Remotes))
+	for i, remote := range c.Remotes {
+		out.Remotes[i] = remote
+		out.Remotes[i].Secret = restorer.restore(secretPath("remotes", remote.Name, "secret"), remote.Secret)
+		if remote.Auth.Credentials != nil {
+			creds := *remote.Auth.Credentials
+			creds.Payload = restorer.restore(secretPath("remotes", remote.Name, "auth", "credentials", "payload"), creds.Payload)
+			out.Remotes[i].Auth.Credentials = &creds
+		}
+	}
+	return &out
+}
+
+// A secretReference is a reference to a secret resolved at a path of a config, along with
+// the value it was resolved to.
+type secretReference struct {
+	reference string
+	value     string
+}
+
+// secretPath returns the path of a field of a config from the keys leading to it, such as
+// components.m.attributes.api_key.
+func secretPath(keys ...string) string {
+	return strings.Join(keys, ".")
+}
+
+// secretResolver replaces strings referencing secrets with their values. Like
+// placeholderReplacementVisitor, it accumulates its errors instead of returning them, so that
+// a single unresolvable secret does not leave others unresolved.
+type secretResolver struct {
+	ctx       context.Context
+	providers map[string]SecretProvider
+	// resolved maps the references resolved so far to their values, so that a secret
+	// referenced more than once is only looked up once.
+	resolved map[string]string
+	// references maps the paths of the resolved secrets to their references.
+	references map[string]secretReference
+	allErrors  error
+}
+
+// resolveValue returns a copy of the attribute value at the given path, such as a map of
+// attributes, with the secrets it references resolved.
+func (r *secretResolver) resolveValue(path string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.resolve(path, v)
+	case utils.AttributeMap:
+		resolved := make(utils.AttributeMap, len(v))
+		for key, elem := range v {
+			resolved[key] = r.resolveValue(secretPath(path, key), elem)
+		}
+		return resolved
+	case map[string]interface{}:
+		resolved := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			resolved[key] = r.resolveValue(secretPath(path, key), elem)
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, elem := range v {
+			resolved[i] = r.resolveValue(secretPath(path, strconv.Itoa(i)), elem)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+func (r *secretResolver) resolve(path, s string) string {
+	if !IsSecretReference(s) {
+		return s
+	}
+	value, ok := r.resolved[s]
+	if !ok {
+		var err error
+		if value, err = resolveSecret(r.ctx, s, r.providers); err != nil {
+			r.allErrors = multierr.Append(r.allErrors, errors.Wrapf(err, "at %s", path))
+			return s
+		}
+		r.resolved[s] = value
+	}
+	r.references[path] = secretReference{reference: s, value: value}
+	return value
+}
+
+// secretRestorer replaces the values of resolved secrets with the references they were
+// resolved from.
+type secretRestorer struct {
+	references map[string]secretReference
+}
+
+// restoreValue returns a copy of the attribute value at the given path with the secrets
+// resolved in it replaced by their references.
+func (r secretRestorer) restoreValue(path string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		return r.restore(path, v)
+	case utils.AttributeMap:
+		restored := make(utils.AttributeMap, len(v))
+		for key, elem := range v {
+			restored[key] = r.restoreValue(secretPath(path, key), elem)
+		}
+		return restored
+	case map[string]interface{}:
+		restored := make(map[string]interface{}, len(v))
+		for key, elem := range v {
+			restored[key] = r.restoreValue(secretPath(path, key), elem)
+		}
+		return restored
+	case []interface{}:
+		restored := make([]interface{}, len(v))
+		for i, elem := range v {
+			restored[i] = r.restoreValue(secretPath(path, strconv.Itoa(i)), elem)
+		}
+		return restored
+	default:
+		return value
+	}
+}
+
+func (r secretRestorer) restore(path, s string) string {
+	if ref, ok := r.references[path]; ok && ref.value == s {
+		return ref.reference
+	}
+	return s
+}
@@ -0,0 +1,184 @@
+package config_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	t.Setenv("TEST_MOTOR_KEY", "env-secret")
+	t.Setenv("TEST_VAULT_TOKEN", "vault-token")
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	test.That(t, os.WriteFile(secretFile, []byte("file-secret\n"), 0o600), test.ShouldBeNil)
+
+	vault := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/secret/remote" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"value":"vault-secret"}}`))
+	}))
+	defer vault.Close()
+
+	cfg := &config.Config{
+		SecretProviders: []config.SecretProviderConfig{
+			{
+				Name:    "vault",
+				URL:     vault.URL + "/v1/secret/{key}",
+				Headers: map[string]string{"X-Vault-Token": "secret://env/TEST_VAULT_TOKEN"},
+				Field:   "data.value",
+			},
+		},
+		Components: []resource.Config{
+			{
+				Name: "m",
+				Attributes: utils.AttributeMap{
+					"api_key": "secret://env/TEST_MOTOR_KEY",
+					"nested":  map[string]interface{}{"token": "secret://file/" + secretFile},
+					"plain":   "not a secret",
+					// written as the value of a secret rather than referencing it.
+					"copy": "env-secret",
+				},
+			},
+		},
+		Modules: []config.Module{
+			{Name: "mod", Environment: map[string]string{"TOKEN": "secret://env/TEST_MOTOR_KEY"}},
+		},
+		Remotes: []config.Remote{
+			{
+				Name: "rem",
+				Auth: config.RemoteAuth{
+					Credentials: &rpc.Credentials{Type: utils.CredentialsTypeRobotLocationSecret, Payload: "secret://vault/remote"},
+				},
+			},
+		},
+	}
+	test.That(t, cfg.ResolveSecrets(context.Background()), test.ShouldBeNil)
+
+	attrs := cfg.Components[0].Attributes
+	test.That(t, attrs["api_key"], test.ShouldEqual, "env-secret")
+	test.That(t, attrs["nested"], test.ShouldResemble, map[string]interface{}{"token": "file-secret"})
+	test.That(t, attrs["plain"], test.ShouldEqual, "not a secret")
+	test.That(t, cfg.Modules[0].Environment["TOKEN"], test.ShouldEqual, "env-secret")
+	test.That(t, cfg.Remotes[0].Auth.Credentials.Payload, test.ShouldEqual, "vault-secret")
+
+	t.Run("with secret references", func(t *testing.T) {
+		referenced := cfg.WithSecretReferences()
+		attrs := referenced.Components[0].Attributes
+		test.That(t, attrs["api_key"], test.ShouldEqual, "secret://env/TEST_MOTOR_KEY")
+		test.That(t, attrs["nested"], test.ShouldResemble, map[string]interface{}{"token": "secret://file/" + secretFile})
+		test.That(t, attrs["plain"], test.ShouldEqual, "not a secret")
+		test.That(t, attrs["copy"], test.ShouldEqual, "env-secret")
+		test.That(t, referenced.Modules[0].Environment["TOKEN"], test.ShouldEqual, "secret://env/TEST_MOTOR_KEY")
+		test.That(t, referenced.Remotes[0].Auth.Credentials.Payload, test.ShouldEqual, "secret://vault/remote")
+
+		// the resolved config is left as is.
+		test.That(t, cfg.Components[0].Attributes["api_key"], test.ShouldEqual, "env-secret")
+		test.That(t, cfg.Modules[0].Environment["TOKEN"], test.ShouldEqual, "env-secret")
+		test.That(t, cfg.Remotes[0].Auth.Credentials.Payload, test.ShouldEqual, "vault-secret")
+
+		// a value changed since the secret was resolved is not replaced.
+		changed := *cfg
+		changed.Components = []resource.Config{cfg.Components[0]}
+		changed.Components[0].Attributes = utils.AttributeMap{"api_key": "other", "plain": "env-secret"}
+		attrs = changed.WithSecretReferences().Components[0].Attributes
+		test.That(t, attrs["api_key"], test.ShouldEqual, "other")
+		test.That(t, attrs["plain"], test.ShouldEqual, "env-secret")
+	})
+
+	t.Run("unresolvable secrets", func(t *testing.T) {
+		cfg := &config.Config{
+			Components: []resource.Config{
+				{
+					Name: "m",
+					Attributes: utils.AttributeMap{
+						"unset":   "secret://env/TEST_UNSET_SECRET",
+						"unknown": "secret://nope/key",
+						"api_key": "secret://env/TEST_MOTOR_KEY",
+					},
+				},
+			},
+		}
+		err := cfg.ResolveSecrets(context.Background())
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `environment variable "TEST_UNSET_SECRET" is not set`)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `unknown secret provider "nope"`)
+
+		// references that fail to resolve are kept, and others are still resolved.
+		attrs := cfg.Components[0].Attributes
+		test.That(t, attrs["unset"], test.ShouldEqual, "secret://env/TEST_UNSET_SECRET")
+		test.That(t, attrs["unknown"], test.ShouldEqual, "secret://nope/key")
+		test.That(t, attrs["api_key"], test.ShouldEqual, "env-secret")
+	})
+
+	t.Run("unresolvable secrets fail the config", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "robot.json")
+		test.That(t, os.WriteFile(path, []byte(`{
+			"components": [
+				{
+					"name": "m",
+					"type": "motor",
+					"model": "fake",
+					"attributes": {"api_key": "secret://env/TEST_UNSET_SECRET"}
+				}
+			]
+		}`), 0o600), test.ShouldBeNil)
+		_, err := config.Read(context.Background(), path, logging.NewTestLogger(t))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "components.m.attributes.api_key")
+		test.That(t, err.Error(), test.ShouldContainSubstring, `environment variable "TEST_UNSET_SECRET" is not set`)
+	})
+
+	t.Run("registered provider", func(t *testing.T) {
+		config.RegisterSecretProvider("test-static", config.SecretProviderFunc(func(ctx context.Context, key string) (string, error) {
+			return "static-" + key, nil
+		}))
+		cfg := &config.Config{
+			Services: []resource.Config{{Name: "s", Attributes: utils.AttributeMap{"key": "secret://test-static/abc"}}},
+		}
+		test.That(t, cfg.ResolveSecrets(context.Background()), test.ShouldBeNil)
+		test.That(t, cfg.Services[0].Attributes["key"], test.ShouldEqual, "static-abc")
+
+		invalid := config.SecretProviderConfig{Name: "test-static", URL: "https://example.com/{key}"}
+		test.That(t, invalid.Validate("secret_providers.0"), test.ShouldNotBeNil)
+	})
+}
+
+func TestSecretProviderConfigValidate(t *testing.T) {
+	valid := config.SecretProviderConfig{Name: "vault", URL: "https://vault.example.com/v1/secret/{key}"}
+	test.That(t, valid.Validate("secret_providers.0"), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name   string
+		conf   config.SecretProviderConfig
+		errMsg string
+	}{
+		{"no name", config.SecretProviderConfig{URL: valid.URL}, `Field: "name"`},
+		{"invalid name", config.SecretProviderConfig{Name: "a/b", URL: valid.URL}, "invalid name"},
+		{"builtin name", config.SecretProviderConfig{Name: "env", URL: valid.URL}, "already registered"},
+		{"no url", config.SecretProviderConfig{Name: "vault"}, `Field: "url"`},
+		{"no key placeholder", config.SecretProviderConfig{Name: "vault", URL: "https://vault.example.com"}, "{key}"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.conf.Validate("secret_providers.0")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errMsg)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/resource"
+)
+
+// DefaultSelfTestTimeout is how long the self-test of a single component may take when
+// SelfTestConfig.Timeout is not set.
+const DefaultSelfTestTimeout = 10 * time.Second
+
+// SelfTestConfig enables a self-test of the robot's components once they are first built.
+// Each component is exercised with checks that are safe to run at any time, such as
+// reading a sensor once, querying the joint positions of an arm, or grabbing a frame from
+// a camera, so that operators know the robot is functional before dispatching it.
+type SelfTestConfig struct {
+	// Timeout is how long the self-test of a single component may take (e.g. "5s").
+	// Defaults to DefaultSelfTestTimeout.
+	Timeout string `json:"timeout,omitempty"`
+	// Skip are the names of the components that should not be self-tested.
+	Skip []string `json:"skip,omitempty"`
+}
+
+// Validate checks if the config is valid.
+func (c *SelfTestConfig) Validate(path string) error {
+	if c.Timeout != "" {
+		timeout, err := time.ParseDuration(c.Timeout)
+		if err != nil {
+			return resource.NewConfigValidationError(path, errors.Wrap(err, "invalid timeout"))
+		}
+		if timeout <= 0 {
+			return resource.NewConfigValidationError(path, errors.New("timeout must be positive"))
+		}
+	}
+	if slices.Contains(c.Skip, "") {
+		return resource.NewConfigValidationError(path, errors.New("skipped component names must not be empty"))
+	}
+	return nil
+}
+
+// TimeoutDuration returns how long the self-test of a single component may take.
+func (c *SelfTestConfig) TimeoutDuration() time.Duration {
+	if c == nil || c.Timeout == "" {
+		return DefaultSelfTestTimeout
+	}
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil || timeout <= 0 {
+		return DefaultSelfTestTimeout
+	}
+	return timeout
+}
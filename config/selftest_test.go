@@ -0,0 +1,36 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestSelfTestConfigValidate(t *testing.T) {
+	var unset *SelfTestConfig
+	test.That(t, unset.TimeoutDuration(), test.ShouldEqual, DefaultSelfTestTimeout)
+
+	valid := SelfTestConfig{Timeout: "5s", Skip: []string{"arm1"}}
+	test.That(t, valid.Validate("self_test"), test.ShouldBeNil)
+	test.That(t, valid.TimeoutDuration(), test.ShouldEqual, 5*time.Second)
+	test.That(t, (&SelfTestConfig{}).TimeoutDuration(), test.ShouldEqual, DefaultSelfTestTimeout)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(c *SelfTestConfig)
+		errStr string
+	}{
+		{"bad timeout", func(c *SelfTestConfig) { c.Timeout = "soon" }, "invalid timeout"},
+		{"negative timeout", func(c *SelfTestConfig) { c.Timeout = "-1s" }, "positive"},
+		{"empty skip", func(c *SelfTestConfig) { c.Skip = []string{""} }, "skipped"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := valid
+			tc.modify(&c)
+			err := c.Validate("self_test")
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
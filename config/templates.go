@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+)
+
+// forEachKey is the key of the parameters of an entry of a list of a config, such as a
+// component, that is a template of several entries. For each set of parameters, the entry is
+// repeated with "{{param}}" placeholders in its names and values replaced by the value of the
+// parameter, so that a dozen identical sensors differing only by name and pin are described
+// once:
+//
+//	{
+//	  "for_each": [{"n": 1, "pin": "11"}, {"n": 2, "pin": "13"}],
+//	  "name": "ultrasonic{{n}}",
+//	  "api": "rdk:component:sensor",
+//	  "model": "ultrasonic",
+//	  "attributes": {"trigger_pin": "{{pin}}"}
+//	}
+const forEachKey = "for_each"
+
+var templateParamRegexp = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// expandTemplates returns the JSON config with the templates in its lists expanded. A value
+// that is only a placeholder is replaced by the parameter as is, so that numbers stay numbers,
+// and placeholders within other strings are replaced by the parameter as text. A config with
+// no templates is returned as is.
+func expandTemplates(data []byte) ([]byte, error) {
+	raw, err := decodeRaw(data)
+	if err != nil {
+		// left to the decoding of the config to report.
+		return data, nil //nolint:nilerr
+	}
+	expanded := false
+	for key, value := range raw {
+		list, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		out, listExpanded, err := expandList(list)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to expand template of %s", key)
+		}
+		if listExpanded {
+			raw[key] = out
+			expanded = true
+		}
+	}
+	if !expanded {
+		return data, nil
+	}
+	return json.Marshal(raw)
+}
+
+// expandList returns the list with its templates expanded, and whether it had any.
+func expandList(list []interface{}) ([]interface{}, bool, error) {
+	out := make([]interface{}, 0, len(list))
+	expanded := false
+	for idx, item := range list {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			out = append(out, item)
+			continue
+		}
+		value, ok := obj[forEachKey]
+		if !ok {
+			out = append(out, item)
+			continue
+		}
+		expanded = true
+		paramSets, ok := value.([]interface{})
+		if !ok {
+			return nil, false, errors.Errorf("%s of entry %d must be a list of parameters", forEachKey, idx)
+		}
+		template := make(map[string]interface{}, len(obj))
+		for key, value := range obj {
+			if key != forEachKey {
+				template[key] = value
+			}
+		}
+		for setIdx, paramSet := range paramSets {
+			params, ok := paramSet.(map[string]interface{})
+			if !ok {
+				return nil, false, errors.Errorf("parameters %d of entry %d must be an object", setIdx, idx)
+			}
+			entry, err := substitute(template, params)
+			if err != nil {
+				return nil, false, errors.Wrapf(err, "failed to expand entry %d with parameters %d", idx, setIdx)
+			}
+			out = append(out, entry)
+		}
+	}
+	return out, expanded, nil
+}
+
+// substitute returns a copy of the decoded JSON value with its placeholders replaced by the
+// given parameters.
+func substitute(value interface{}, params map[string]interface{}) (interface{}, error) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			newKey, err := substituteText(key, params)
+			if err != nil {
+				return nil, err
+			}
+			if out[newKey], err = substitute(item, params); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(value))
+		for _, item := range value {
+			newItem, err := substitute(item, params)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, newItem)
+		}
+		return out, nil
+	case string:
+		if match := templateParamRegexp.FindStringSubmatch(value); match != nil && match[0] == value {
+			param, ok := params[match[1]]
+			if !ok {
+				return nil, errors.Errorf("undefined template parameter %q", match[1])
+			}
+			return param, nil
+		}
+		return substituteText(value, params)
+	default:
+		return value, nil
+	}
+}
+
+// substituteText returns the string with its placeholders replaced by the given
+// parameters as text.
+func substituteText(s string, params map[string]interface{}) (string, error) {
+	var err error
+	out := templateParamRegexp.ReplaceAllStringFunc(s, func(placeholder string) string {
+		name := templateParamRegexp.FindStringSubmatch(placeholder)[1]
+		param, ok := params[name]
+		if !ok {
+			err = errors.Errorf("undefined template parameter %q", name)
+			return placeholder
+		}
+		return fmt.Sprint(param)
+	})
+	return out, err
+}
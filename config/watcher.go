@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/bep/debounce"
@@ -109,6 +110,10 @@ func (w *cloudWatcher) Close() error {
 	return nil
 }
 
+// configPollInterval is how often a config file is checked for changes when the file system
+// cannot notify of them.
+var configPollInterval = 2 * time.Second
+
 // A fsConfigWatcher fetches new configs from an underlying file when written to.
 type fsConfigWatcher struct {
 	fsWatcher     *fsnotify.Watcher
@@ -118,21 +123,61 @@ type fsConfigWatcher struct {
 }
 
 // newFSWatcher returns a new v that will fetch new configs
-// as soon as the underlying file is written to.
+// as soon as the underlying file is written to. The directory of the file is watched rather
+// than the file itself, so that the file is still watched after an editor saves it by
+// replacing it. If the file system cannot notify of changes, the file is polled instead.
+// Rapid edits are coalesced into one reload, and edits leaving the config invalid are logged
+// and skipped.
 func newFSWatcher(ctx context.Context, configPath string, logger logging.Logger) (*fsConfigWatcher, error) {
-	fsWatcher, err := fsnotify.NewWatcher()
-	if err != nil {
+	if _, err := os.Stat(configPath); err != nil {
 		return nil, err
 	}
-	if err := fsWatcher.Add(configPath); err != nil {
-		return nil, err
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		if err = fsWatcher.Add(filepath.Dir(configPath)); err != nil {
+			utils.UncheckedError(fsWatcher.Close())
+		}
 	}
+	if err != nil {
+		logger.Warnw("cannot be notified of changes to the config file; polling it instead",
+			"path", configPath, "interval", configPollInterval, "error", err)
+		fsWatcher = nil
+	}
+
 	configCh := make(chan *Config)
 	watcherDoneCh := make(chan struct{})
 	cancelCtx, cancel := context.WithCancel(ctx)
 	var lastRd []byte
+	reload := func() {
+		rd, err := ReadFile(configPath, logger)
+		if err != nil {
+			logger.Errorw("error reading config file after write", "error", err)
+			return
+		}
+		if bytes.Equal(rd, lastRd) {
+			return
+		}
+		lastRd = rd
+		logger.Info("On-disk config file changed. Reloading the config file.")
+		newConfig, err := FromReader(cancelCtx, configPath, bytes.NewReader(rd), logger)
+		if err != nil {
+			logger.Errorw("error reading config after write", "error", err)
+			return
+		}
+		UpdateFileConfigDebug(newConfig.Debug)
+		select {
+		case <-cancelCtx.Done():
+			return
+		case configCh <- newConfig:
+		}
+	}
+
 	utils.ManagedGo(func() {
 		debounced := debounce.New(time.Millisecond * 500)
+		if fsWatcher == nil {
+			pollConfigFile(cancelCtx, configPath, func() { debounced(reload) })
+			return
+		}
 		for {
 			if cancelCtx.Err() != nil {
 				return
@@ -140,32 +185,12 @@ func newFSWatcher(ctx context.Context, configPath string, logger logging.Logger)
 			select {
 			case <-cancelCtx.Done():
 				return
+			case err := <-fsWatcher.Errors:
+				logger.Warnw("error watching config file", "error", err)
 			case event := <-fsWatcher.Events:
-				if event.Op&fsnotify.Write == fsnotify.Write {
-					debounced(func() {
-						logger.Info("On-disk config file changed. Reloading the config file.")
-						//nolint:gosec
-						rd, err := os.ReadFile(configPath)
-						if err != nil {
-							logger.Errorw("error reading config file after write", "error", err)
-							return
-						}
-						if bytes.Equal(rd, lastRd) {
-							return
-						}
-						lastRd = rd
-						newConfig, err := FromReader(cancelCtx, configPath, bytes.NewReader(rd), logger)
-						if err != nil {
-							logger.Errorw("error reading config after write", "error", err)
-							return
-						}
-						UpdateFileConfigDebug(newConfig.Debug)
-						select {
-						case <-cancelCtx.Done():
-							return
-						case configCh <- newConfig:
-						}
-					})
+				if filepath.Clean(event.Name) == filepath.Clean(configPath) &&
+					event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					debounced(reload)
 				}
 			}
 		}
@@ -180,6 +205,28 @@ func newFSWatcher(ctx context.Context, configPath string, logger logging.Logger)
 	}, nil
 }
 
+// pollConfigFile calls changed whenever the modification time or size of the config file
+// changes, until the context is done.
+func pollConfigFile(ctx context.Context, configPath string, changed func()) {
+	var last os.FileInfo
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		info, err := os.Stat(configPath)
+		if err == nil && last != nil && (!info.ModTime().Equal(last.ModTime()) || info.Size() != last.Size()) {
+			changed()
+		}
+		if err == nil {
+			last = info
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 func (w *fsConfigWatcher) Config() <-chan *Config {
 	return w.configCh
 }
@@ -187,6 +234,9 @@ func (w *fsConfigWatcher) Config() <-chan *Config {
 func (w *fsConfigWatcher) Close() error {
 	w.cancel()
 	<-w.watcherDoneCh
+	if w.fsWatcher == nil {
+		return nil
+	}
 	return w.fsWatcher.Close()
 }
 
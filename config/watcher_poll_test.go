@@ -0,0 +1,47 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestPollConfigFile(t *testing.T) {
+	defer func(interval time.Duration) { configPollInterval = interval }(configPollInterval)
+	configPollInterval = 10 * time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "robot.json")
+	test.That(t, os.WriteFile(path, []byte(`{}`), 0o600), test.ShouldBeNil)
+	ctx, cancel := context.WithCancel(context.Background())
+	changed := make(chan struct{}, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pollConfigFile(ctx, path, func() {
+			select {
+			case changed <- struct{}{}:
+			default:
+			}
+		})
+	}()
+
+	// the file is unchanged until it is written to.
+	select {
+	case <-changed:
+		t.Fatal("unchanged config file reported as changed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	test.That(t, os.WriteFile(path, []byte(`{"components": []}`), 0o600), test.ShouldBeNil)
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("changed config file not reported")
+	}
+
+	cancel()
+	<-done
+}
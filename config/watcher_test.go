@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -152,6 +153,7 @@ func TestNewWatcherFile(t *testing.T) {
 				Model: resource.DefaultModelFamily.WithModel("woo"),
 				Attributes: rutils.AttributeMap{
 					"wah": 1.0,
+					"env": "${TEST_WATCHER_ENV:-fallback}",
 				},
 			},
 		},
@@ -169,6 +171,8 @@ func TestNewWatcherFile(t *testing.T) {
 		}},
 	}
 	writeConf(&confToWrite)
+	// environment variables are expanded on reload too.
+	confToWrite.Components[0].Attributes["env"] = "fallback"
 	test.That(t, confToWrite.Ensure(false, logger), test.ShouldBeNil)
 
 	newConf = <-watcher.Config()
@@ -362,3 +366,33 @@ func TestNewWatcherCloud(t *testing.T) {
 
 	test.That(t, watcher.Close(), test.ShouldBeNil)
 }
+
+func TestNewWatcherFileReplaced(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	dir := t.TempDir()
+	path := filepath.Join(dir, "robot.json")
+	test.That(t, os.WriteFile(path, []byte(`{}`), 0o600), test.ShouldBeNil)
+
+	watcher, err := config.NewWatcher(context.Background(), &config.Config{ConfigFilePath: path}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, watcher.Close(), test.ShouldBeNil)
+	}()
+
+	// editors often save a file by writing a new one and moving it over the old one; the
+	// config is still watched after each save.
+	for _, name := range []string{"motor1", "motor2"} {
+		replacement := filepath.Join(dir, "robot.json.tmp")
+		conf := fmt.Sprintf(`{"components": [{"name": %q, "type": "motor", "model": "fake"}]}`, name)
+		test.That(t, os.WriteFile(replacement, []byte(conf), 0o600), test.ShouldBeNil)
+		test.That(t, os.Rename(replacement, path), test.ShouldBeNil)
+
+		select {
+		case newConf := <-watcher.Config():
+			test.That(t, newConf.Components, test.ShouldHaveLength, 1)
+			test.That(t, newConf.Components[0].Name, test.ShouldEqual, name)
+		case <-time.After(10 * time.Second):
+			t.Fatalf("config with %s was not reloaded", name)
+		}
+	}
+}
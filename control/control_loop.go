@@ -28,6 +28,27 @@ type controlTicker struct {
 	stop   chan bool
 }
 
+// LoopStats describes how regularly a loop has been running at its frequency.
+type LoopStats struct {
+	// Ticks is how many times the loop ran.
+	Ticks uint64 `json:"ticks"`
+	// Overruns is how many times the loop ran more than half a period late, which happens
+	// when its blocks take longer than a period to run and drops ticks.
+	Overruns uint64 `json:"overruns"`
+	// MaxJitter is the largest difference between the period of the loop and the time
+	// between two of its ticks.
+	MaxJitter time.Duration `json:"max_jitter"`
+	// MeanJitter is the mean difference between the period of the loop and the time
+	// between two of its ticks.
+	MeanJitter time.Duration `json:"mean_jitter"`
+}
+
+// MaxFrequency is the highest frequency in Hz a loop can run at.
+const MaxFrequency = 200.0
+
+// overrunLogEvery is how many overruns of a loop are counted between warnings about them.
+const overrunLogEvery = 100
+
 // Loop holds the loop config.
 type Loop struct {
 	cfg                     Config
@@ -41,6 +62,11 @@ type Loop struct {
 	cancel                  context.CancelFunc
 	running                 atomic.Bool
 	pidBlocks               []*basicPID
+
+	statsMu   sync.Mutex
+	stats     LoopStats
+	jitterSum time.Duration
+	lastTick  time.Time
 }
 
 // NewLoop construct a new control loop for a specific endpoint.
@@ -58,8 +84,8 @@ func createLoop(logger logging.Logger, cfg Config, m Controllable) (*Loop, error
 		cancel:    cancel,
 	}
 	l.running.Store(false)
-	if l.cfg.Frequency == 0.0 || l.cfg.Frequency > 200 {
-		return nil, errors.New("loop frequency shouldn't be 0 or above 200Hz")
+	if l.cfg.Frequency == 0.0 || l.cfg.Frequency > MaxFrequency {
+		return nil, errors.Errorf("loop frequency shouldn't be 0 or above %vHz", MaxFrequency)
 	}
 	l.dt = time.Duration(float64(time.Second) * (1.0 / (l.cfg.Frequency)))
 	for _, bcfg := range cfg.Blocks {
@@ -238,6 +264,7 @@ func (l *Loop) Start() error {
 			}
 			select {
 			case t := <-ct.ticker.C:
+				l.recordTick(time.Now())
 				for _, c := range ts {
 					c <- t
 				}
@@ -259,6 +286,40 @@ func (l *Loop) Start() error {
 	return nil
 }
 
+// recordTick records that the loop ticked at the given time.
+func (l *Loop) recordTick(now time.Time) {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+	l.stats.Ticks++
+	if !l.lastTick.IsZero() {
+		interval := now.Sub(l.lastTick)
+		jitter := interval - l.dt
+		if jitter < 0 {
+			jitter = -jitter
+		}
+		l.jitterSum += jitter
+		l.stats.MeanJitter = l.jitterSum / time.Duration(l.stats.Ticks-1)
+		if jitter > l.stats.MaxJitter {
+			l.stats.MaxJitter = jitter
+		}
+		if interval > l.dt+l.dt/2 {
+			l.stats.Overruns++
+			if l.stats.Overruns%overrunLogEvery == 1 {
+				l.logger.Warnw("control loop is not keeping up with its frequency",
+					"frequency", l.cfg.Frequency, "period", interval, "overruns", l.stats.Overruns)
+			}
+		}
+	}
+	l.lastTick = now
+}
+
+// Stats returns how regularly the loop has been running at its frequency.
+func (l *Loop) Stats() LoopStats {
+	l.statsMu.Lock()
+	defer l.statsMu.Unlock()
+	return l.stats
+}
+
 // StartBenchmark special start function to benchmark speed of complex loop configurations.
 func (l *Loop) startBenchmark(loops int) error {
 	if len(l.ts) == 0 {
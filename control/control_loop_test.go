@@ -383,3 +383,17 @@ func TestMultiSignalLoop(t *testing.T) {
 
 	cLoop.Stop()
 }
+
+func TestLoopStats(t *testing.T) {
+	l := &Loop{logger: logging.NewTestLogger(t), cfg: Config{Frequency: 100}, dt: 10 * time.Millisecond}
+	start := time.Now()
+	for _, offset := range []time.Duration{0, 10, 22, 30, 50} {
+		l.recordTick(start.Add(offset * time.Millisecond))
+	}
+	stats := l.Stats()
+	test.That(t, stats.Ticks, test.ShouldEqual, 5)
+	// the loop ran on time, 2ms late, 2ms early, then missed a tick.
+	test.That(t, stats.Overruns, test.ShouldEqual, 1)
+	test.That(t, stats.MaxJitter, test.ShouldEqual, 10*time.Millisecond)
+	test.That(t, stats.MeanJitter, test.ShouldEqual, 3500*time.Microsecond)
+}
@@ -23,6 +23,7 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	rclock "go.viam.com/rdk/clock"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/datamanager/datacapture"
@@ -290,7 +291,7 @@ func NewCollector(captureFunc CaptureFunc, params CollectorParams) (Collector, e
 	cancelCtx, cancelFunc := context.WithCancel(context.Background())
 	var c clock.Clock
 	if params.Clock == nil {
-		c = clock.New()
+		c = rclock.Robot()
 	} else {
 		c = params.Clock
 	}
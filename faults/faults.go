@@ -0,0 +1,375 @@
+// Package faults injects errors, latency, dropped frames, and disconnects into the gRPC
+// requests served for a robot's resources so applications can be tested against flaky
+// hardware.
+package faults
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"path"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+// CommandKey is the DoCommand key used to inspect and change a resource's faults at
+// runtime when the robot is in debug mode. The value may contain a "faults" list that
+// replaces the resource's faults; the response always lists the resource's faults.
+const CommandKey = "fault_injection"
+
+// FrameMethods are the gRPC methods drop_frames faults apply to by default.
+var FrameMethods = []string{"GetImage", "GetImages", "RenderFrame", "GetPointCloud", "NextPointCloud", "Chunks"}
+
+type fault struct {
+	cfg   config.FaultConfig
+	code  codes.Code
+	start time.Time
+	calls int
+}
+
+func (f *fault) matches(name, method string) bool {
+	if f.cfg.Resource != name {
+		return false
+	}
+	if len(f.cfg.Methods) != 0 {
+		return slices.Contains(f.cfg.Methods, method)
+	}
+	if f.cfg.Type == config.FaultTypeDropFrames {
+		return slices.Contains(FrameMethods, method)
+	}
+	return true
+}
+
+// active reports whether the fault's schedule has it active at the given time.
+func (f *fault) active(now time.Time) bool {
+	if f.cfg.InactiveSec == 0 && f.cfg.ActiveSec == 0 {
+		return true
+	}
+	elapsed := now.Sub(f.start).Seconds()
+	if f.cfg.ActiveSec == 0 {
+		return elapsed >= f.cfg.InactiveSec
+	}
+	period := f.cfg.InactiveSec + f.cfg.ActiveSec
+	return elapsed-period*float64(int(elapsed/period)) >= f.cfg.InactiveSec
+}
+
+// trigger decides whether the fault is injected into the current matching call.
+func (f *fault) trigger(rnd *rand.Rand) bool {
+	f.calls++
+	if f.cfg.Every > 0 && f.calls%f.cfg.Every != 0 {
+		return false
+	}
+	return f.cfg.Probability == 0 || rnd.Float64() < f.cfg.Probability
+}
+
+// A Robot is a robot injecting faults into the requests served for its resources.
+type Robot interface {
+	// FaultInjector returns the injector used to inject faults into the requests served
+	// for the robot's resources.
+	FaultInjector() *Injector
+}
+
+// An Injector injects the faults configured for resources into the gRPC requests
+// served for them.
+type Injector struct {
+	mu         sync.Mutex
+	logger     logging.Logger
+	rnd        *rand.Rand
+	fromConfig []config.FaultConfig
+	faults     []*fault
+}
+
+// NewInjector returns an Injector with no faults.
+func NewInjector(logger logging.Logger) *Injector {
+	return &Injector{
+		logger: logger,
+		//nolint:gosec
+		rnd: rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Reconfigure replaces all faults with the given faults from a robot config if they
+// have changed since the last call. Invalid faults are skipped.
+func (i *Injector) Reconfigure(cfgs []config.FaultConfig) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if reflect.DeepEqual(cfgs, i.fromConfig) {
+		return
+	}
+	i.fromConfig = cfgs
+
+	i.faults = nil
+	for idx, cfg := range cfgs {
+		f, err := newFault(cfg)
+		if err != nil {
+			i.logger.Warnw("skipping invalid fault", "index", idx, "error", err)
+			continue
+		}
+		i.faults = append(i.faults, f)
+	}
+	if len(i.faults) != 0 {
+		i.logger.Warnw("fault injection enabled", "faults", len(i.faults))
+	}
+}
+
+// SetResourceFaults replaces the faults of the named resource. The resource of each
+// given fault is set to name.
+func (i *Injector) SetResourceFaults(name string, cfgs []config.FaultConfig) error {
+	var added []*fault
+	for idx, cfg := range cfgs {
+		cfg.Resource = name
+		f, err := newFault(cfg)
+		if err != nil {
+			return errors.Wrapf(err, "fault %d", idx)
+		}
+		added = append(added, f)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.faults = slices.DeleteFunc(i.faults, func(f *fault) bool {
+		return f.cfg.Resource == name
+	})
+	i.faults = append(i.faults, added...)
+	return nil
+}
+
+// Faults returns the faults currently injected.
+func (i *Injector) Faults() []config.FaultConfig {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	cfgs := make([]config.FaultConfig, 0, len(i.faults))
+	for _, f := range i.faults {
+		cfgs = append(cfgs, f.cfg)
+	}
+	return cfgs
+}
+
+func newFault(cfg config.FaultConfig) (*fault, error) {
+	if err := cfg.Validate(""); err != nil {
+		return nil, err
+	}
+	code, err := cfg.StatusCode()
+	if err != nil {
+		return nil, err
+	}
+	return &fault{cfg: cfg, code: code, start: clock.Now()}, nil
+}
+
+// apply injects the faults matching a call of method on the named resource. Errors are
+// only injected when a call starts, not while sending streamed responses. It reports
+// whether the frame being returned should be dropped.
+func (i *Injector) apply(ctx context.Context, name, method string, sending bool) (bool, error) {
+	var (
+		drop    bool
+		latency time.Duration
+		err     error
+	)
+	i.mu.Lock()
+	now := clock.Now()
+	for _, f := range i.faults {
+		if !f.matches(name, method) || !f.active(now) {
+			continue
+		}
+		switch f.cfg.Type {
+		case config.FaultTypeError:
+			if !sending && err == nil && f.trigger(i.rnd) {
+				msg := f.cfg.Message
+				if msg == "" {
+					msg = "injected fault"
+				}
+				err = status.Error(f.code, msg)
+			}
+		case config.FaultTypeDisconnect:
+			if err == nil && f.trigger(i.rnd) {
+				err = status.Errorf(codes.Unavailable, "resource %q disconnected by fault injection", name)
+			}
+		case config.FaultTypeLatency:
+			if f.trigger(i.rnd) {
+				latency += time.Duration(f.cfg.LatencyMs) * time.Millisecond
+				if f.cfg.JitterMs > 0 {
+					latency += time.Duration(i.rnd.Int63n(int64(f.cfg.JitterMs)*int64(time.Millisecond) + 1))
+				}
+			}
+		case config.FaultTypeDropFrames:
+			if f.trigger(i.rnd) {
+				drop = true
+			}
+		}
+	}
+	i.mu.Unlock()
+
+	if err != nil {
+		return false, err
+	}
+	if latency > 0 && !clock.SleepContext(ctx, latency) {
+		return false, ctx.Err()
+	}
+	return drop, nil
+}
+
+func (i *Injector) empty() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return len(i.faults) == 0
+}
+
+func requestResourceName(req interface{}) string {
+	if named, ok := req.(interface{ GetName() string }); ok {
+		return named.GetName()
+	}
+	return ""
+}
+
+// UnaryServerInterceptor injects faults into unary calls on resources.
+func (i *Injector) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if i.empty() {
+		return handler(ctx, req)
+	}
+	name := requestResourceName(req)
+	if name == "" {
+		return handler(ctx, req)
+	}
+	drop, err := i.apply(ctx, name, path.Base(info.FullMethod), false)
+	if err != nil {
+		return nil, err
+	}
+	if drop {
+		return nil, status.Errorf(codes.Unavailable, "frame from %q dropped by fault injection", name)
+	}
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor injects faults into streaming calls on resources. The
+// resource is known once the first request is received.
+func (i *Injector) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if i.empty() {
+		return handler(srv, ss)
+	}
+	return handler(srv, &faultStream{ServerStream: ss, injector: i, method: path.Base(info.FullMethod)})
+}
+
+type faultStream struct {
+	grpc.ServerStream
+	injector *Injector
+	method   string
+	// name is set by RecvMsg, which may run concurrently with SendMsg.
+	name atomic.Value // string
+}
+
+func (s *faultStream) resourceName() string {
+	name, _ := s.name.Load().(string)
+	return name
+}
+
+func (s *faultStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if s.resourceName() != "" {
+		return nil
+	}
+	name := requestResourceName(m)
+	if name == "" {
+		return nil
+	}
+	s.name.Store(name)
+	_, err := s.injector.apply(s.Context(), name, s.method, false)
+	return err
+}
+
+func (s *faultStream) SendMsg(m interface{}) error {
+	if name := s.resourceName(); name != "" {
+		drop, err := s.injector.apply(s.Context(), name, s.method, true)
+		if err != nil {
+			return err
+		}
+		if drop {
+			return nil
+		}
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+// CommandUnaryServerInterceptor handles DoCommand requests containing CommandKey
+// instead of passing them to the resource. It should only be installed in debug mode.
+func (i *Injector) CommandUnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	cmdReq, ok := req.(*commonpb.DoCommandRequest)
+	if !ok {
+		return handler(ctx, req)
+	}
+	cmd, ok := cmdReq.GetCommand().AsMap()[CommandKey]
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	var args struct {
+		Faults *[]config.FaultConfig `json:"faults"`
+	}
+	raw, err := json.Marshal(cmd)
+	if err == nil {
+		err = json.Unmarshal(raw, &args)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s command: %v", CommandKey, err)
+	}
+	if args.Faults != nil {
+		if err := i.SetResourceFaults(cmdReq.GetName(), *args.Faults); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s command: %v", CommandKey, err)
+		}
+		i.logger.CInfow(ctx, "resource faults changed", "resource", cmdReq.GetName(), "faults", len(*args.Faults))
+	}
+
+	resp, err := i.resourceFaultsStruct(cmdReq.GetName())
+	if err != nil {
+		return nil, err
+	}
+	return &commonpb.DoCommandResponse{Result: resp}, nil
+}
+
+func (i *Injector) resourceFaultsStruct(name string) (*structpb.Struct, error) {
+	current := []config.FaultConfig{}
+	for _, cfg := range i.Faults() {
+		if cfg.Resource == name {
+			current = append(current, cfg)
+		}
+	}
+	raw, err := json.Marshal(map[string]interface{}{"faults": current})
+	if err != nil {
+		return nil, err
+	}
+	var asMap map[string]interface{}
+	if err := json.Unmarshal(raw, &asMap); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(asMap)
+}
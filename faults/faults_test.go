@@ -0,0 +1,198 @@
+package faults
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	commonpb "go.viam.com/api/common/v1"
+	camerapb "go.viam.com/api/component/camera/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+func unaryCall(t *testing.T, injector *Injector, method, name string) error {
+	t.Helper()
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.camera.v1.CameraService/" + method}
+	_, err := injector.UnaryServerInterceptor(context.Background(), &camerapb.GetImageRequest{Name: name}, info,
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return &camerapb.GetImageResponse{}, nil
+		})
+	return err
+}
+
+func TestUnaryFaults(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+	injector := NewInjector(logging.NewTestLogger(t))
+
+	injector.Reconfigure([]config.FaultConfig{
+		{Resource: "cam", Type: config.FaultTypeError, Code: "DATA_LOSS", Every: 2, Methods: []string{"GetProperties"}},
+		{Resource: "cam", Type: config.FaultTypeDropFrames, Every: 3},
+		{Resource: "other", Type: config.FaultTypeDisconnect, InactiveSec: 1, ActiveSec: 1},
+		{Resource: "invalid", Type: "explode"},
+	})
+	test.That(t, injector.Faults(), test.ShouldHaveLength, 3)
+
+	t.Run("error every other call", func(t *testing.T) {
+		test.That(t, unaryCall(t, injector, "GetProperties", "cam"), test.ShouldBeNil)
+		err := unaryCall(t, injector, "GetProperties", "cam")
+		test.That(t, status.Code(err), test.ShouldEqual, codes.DataLoss)
+		test.That(t, unaryCall(t, injector, "GetProperties", "cam"), test.ShouldBeNil)
+	})
+
+	t.Run("drop every third frame", func(t *testing.T) {
+		test.That(t, unaryCall(t, injector, "GetImage", "cam"), test.ShouldBeNil)
+		test.That(t, unaryCall(t, injector, "GetImage", "cam"), test.ShouldBeNil)
+		err := unaryCall(t, injector, "GetImage", "cam")
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "dropped")
+	})
+
+	t.Run("scheduled disconnect", func(t *testing.T) {
+		test.That(t, unaryCall(t, injector, "GetImage", "other"), test.ShouldBeNil)
+		virtual.Step(1500 * time.Millisecond)
+		err := unaryCall(t, injector, "GetImage", "other")
+		test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "disconnected")
+		virtual.Step(time.Second)
+		test.That(t, unaryCall(t, injector, "GetImage", "other"), test.ShouldBeNil)
+	})
+
+	// the same config does not reset faults, but a changed one does
+	injector.Reconfigure(injector.fromConfig)
+	test.That(t, injector.Faults(), test.ShouldHaveLength, 3)
+	injector.Reconfigure(nil)
+	test.That(t, injector.Faults(), test.ShouldBeEmpty)
+	test.That(t, unaryCall(t, injector, "GetImage", "other"), test.ShouldBeNil)
+}
+
+func TestLatencyFault(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+	injector := NewInjector(logging.NewTestLogger(t))
+	test.That(t, injector.SetResourceFaults("cam", []config.FaultConfig{
+		{Type: config.FaultTypeLatency, LatencyMs: 200},
+	}), test.ShouldBeNil)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- unaryCall(t, injector, "GetImage", "cam")
+	}()
+	virtual.Step(100 * time.Millisecond)
+	test.That(t, done, test.ShouldBeEmpty)
+	test.That(t, virtual.StepUntil(50*time.Millisecond, time.Second, func() bool { return len(done) == 1 }), test.ShouldBeTrue)
+	test.That(t, <-done, test.ShouldBeNil)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	recv []interface{}
+	sent []interface{}
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return context.Background()
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	req := s.recv[0]
+	s.recv = s.recv[1:]
+	m.(*commonpb.DoCommandRequest).Name = req.(*commonpb.DoCommandRequest).Name
+	return nil
+}
+
+func (s *fakeServerStream) SendMsg(m interface{}) error {
+	s.sent = append(s.sent, m)
+	return nil
+}
+
+func TestStreamFaults(t *testing.T) {
+	injector := NewInjector(logging.NewTestLogger(t))
+	test.That(t, injector.SetResourceFaults("mic", []config.FaultConfig{
+		{Type: config.FaultTypeDropFrames, Every: 2},
+	}), test.ShouldBeNil)
+
+	ss := &fakeServerStream{recv: []interface{}{&commonpb.DoCommandRequest{Name: "mic"}}}
+	info := &grpc.StreamServerInfo{FullMethod: "/viam.component.audioinput.v1.AudioInputService/Chunks"}
+	err := injector.StreamServerInterceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		var req commonpb.DoCommandRequest
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+		for i := 0; i < 4; i++ {
+			if err := stream.SendMsg(&commonpb.DoCommandResponse{}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ss.sent, test.ShouldHaveLength, 2)
+
+	test.That(t, injector.SetResourceFaults("mic", []config.FaultConfig{
+		{Type: config.FaultTypeDisconnect},
+	}), test.ShouldBeNil)
+	ss = &fakeServerStream{recv: []interface{}{&commonpb.DoCommandRequest{Name: "mic"}}}
+	err = injector.StreamServerInterceptor(nil, ss, info, func(srv interface{}, stream grpc.ServerStream) error {
+		var req commonpb.DoCommandRequest
+		return stream.RecvMsg(&req)
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+}
+
+func TestCommandUnaryServerInterceptor(t *testing.T) {
+	injector := NewInjector(logging.NewTestLogger(t))
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.camera.v1.CameraService/DoCommand"}
+	var handled bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handled = true
+		return &commonpb.DoCommandResponse{}, nil
+	}
+	doCommand := func(cmd map[string]interface{}) (map[string]interface{}, error) {
+		pbCmd, err := structpb.NewStruct(cmd)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := injector.CommandUnaryServerInterceptor(context.Background(),
+			&commonpb.DoCommandRequest{Name: "cam", Command: pbCmd}, info, handler)
+		if err != nil {
+			return nil, err
+		}
+		return resp.(*commonpb.DoCommandResponse).GetResult().AsMap(), nil
+	}
+
+	_, err := doCommand(map[string]interface{}{"hello": "world"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, handled, test.ShouldBeTrue)
+	handled = false
+
+	resp, err := doCommand(map[string]interface{}{CommandKey: map[string]interface{}{
+		"faults": []interface{}{map[string]interface{}{"type": "error", "every": 3}},
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, handled, test.ShouldBeFalse)
+	test.That(t, resp["faults"], test.ShouldHaveLength, 1)
+	test.That(t, injector.Faults(), test.ShouldResemble, []config.FaultConfig{
+		{Resource: "cam", Type: config.FaultTypeError, Every: 3},
+	})
+
+	resp, err = doCommand(map[string]interface{}{CommandKey: map[string]interface{}{}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["faults"], test.ShouldHaveLength, 1)
+
+	_, err = doCommand(map[string]interface{}{CommandKey: map[string]interface{}{
+		"faults": []interface{}{map[string]interface{}{"type": "explode"}},
+	}})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+
+	resp, err = doCommand(map[string]interface{}{CommandKey: map[string]interface{}{"faults": []interface{}{}}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["faults"], test.ShouldBeEmpty)
+	test.That(t, injector.Faults(), test.ShouldBeEmpty)
+}
@@ -0,0 +1,121 @@
+// Package firmware flashes verified firmware images to microcontrollers attached to a robot.
+package firmware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+// A Flasher writes a firmware image to a microcontroller.
+type Flasher interface {
+	Flash(ctx context.Context, image []byte) error
+}
+
+// A FlasherConstructor creates a Flasher for the given firmware config.
+type FlasherConstructor func(cfg config.FirmwareConfig, logger logging.Logger) (Flasher, error)
+
+var (
+	flashersMu sync.RWMutex
+	flashers   = map[config.FirmwareTarget]FlasherConstructor{}
+)
+
+// RegisterFlasher registers a flasher for a firmware target. Registering the same
+// target twice replaces the previous flasher.
+func RegisterFlasher(target config.FirmwareTarget, constructor FlasherConstructor) {
+	flashersMu.Lock()
+	defer flashersMu.Unlock()
+	flashers[target] = constructor
+}
+
+// LookupFlasher returns the flasher constructor for the given target, if any.
+func LookupFlasher(target config.FirmwareTarget) (FlasherConstructor, bool) {
+	flashersMu.RLock()
+	defer flashersMu.RUnlock()
+	constructor, ok := flashers[target]
+	return constructor, ok
+}
+
+// Verify checks that the image matches the digest and ed25519 signature in the config.
+func Verify(cfg config.FirmwareConfig, image []byte) error {
+	if cfg.SHA256 != "" {
+		digest := sha256.Sum256(image)
+		if hex.EncodeToString(digest[:]) != cfg.SHA256 {
+			return errors.Errorf("firmware %q does not match expected sha256", cfg.Name)
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(cfg.PublicKey)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode public key for firmware %q", cfg.Name)
+	}
+	if len(key) != ed25519.PublicKeySize {
+		return errors.Errorf("invalid public key size for firmware %q", cfg.Name)
+	}
+	sig, err := base64.StdEncoding.DecodeString(cfg.Signature)
+	if err != nil {
+		return errors.Wrapf(err, "failed to decode signature for firmware %q", cfg.Name)
+	}
+	if !ed25519.Verify(ed25519.PublicKey(key), image, sig) {
+		return errors.Errorf("signature verification failed for firmware %q", cfg.Name)
+	}
+	return nil
+}
+
+// A Quiescer pauses the resources that depend on a microcontroller while it is being
+// flashed. The returned resume function brings them back once flashing is finished.
+type Quiescer interface {
+	Quiesce(ctx context.Context, resources []string) (resume func(ctx context.Context) error, err error)
+}
+
+// Update reads, verifies, and flashes the firmware described by cfg. The resources in
+// cfg are quiesced for the duration of the flash and resumed regardless of whether
+// flashing succeeds.
+func Update(ctx context.Context, cfg config.FirmwareConfig, q Quiescer, logger logging.Logger) (err error) {
+	constructor, ok := LookupFlasher(cfg.Target)
+	if !ok {
+		return errors.Errorf("no flasher registered for firmware target %q", cfg.Target)
+	}
+
+	//nolint:gosec
+	image, err := os.ReadFile(cfg.Artifact)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read firmware artifact for %q", cfg.Name)
+	}
+	if err := Verify(cfg, image); err != nil {
+		return err
+	}
+
+	flasher, err := constructor(cfg, logger)
+	if err != nil {
+		return err
+	}
+
+	if q != nil && len(cfg.Resources) != 0 {
+		resume, err := q.Quiesce(ctx, cfg.Resources)
+		if err != nil {
+			return errors.Wrapf(err, "failed to quiesce resources for firmware %q", cfg.Name)
+		}
+		defer func() {
+			if resumeErr := resume(ctx); resumeErr != nil {
+				err = multierr.Combine(err, errors.Wrapf(resumeErr, "failed to resume resources after firmware %q", cfg.Name))
+			}
+		}()
+	}
+
+	logger.CInfow(ctx, "flashing firmware", "name", cfg.Name, "target", cfg.Target, "size", len(image))
+	if err := flasher.Flash(ctx, image); err != nil {
+		return errors.Wrapf(err, "failed to flash firmware %q", cfg.Name)
+	}
+	logger.CInfow(ctx, "flashed firmware", "name", cfg.Name)
+	return nil
+}
@@ -0,0 +1,111 @@
+package firmware
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+type fakeFlasher struct {
+	flashed []byte
+	err     error
+}
+
+func (f *fakeFlasher) Flash(ctx context.Context, image []byte) error {
+	f.flashed = image
+	return f.err
+}
+
+type fakeQuiescer struct {
+	quiesced []string
+	resumed  bool
+}
+
+func (q *fakeQuiescer) Quiesce(ctx context.Context, resources []string) (func(ctx context.Context) error, error) {
+	q.quiesced = resources
+	return func(ctx context.Context) error {
+		q.resumed = true
+		return nil
+	}, nil
+}
+
+func signedConfig(t *testing.T, image []byte) config.FirmwareConfig {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	artifact := filepath.Join(t.TempDir(), "firmware.bin")
+	test.That(t, os.WriteFile(artifact, image, 0o600), test.ShouldBeNil)
+
+	digest := sha256.Sum256(image)
+	return config.FirmwareConfig{
+		Name:      "mcu",
+		Target:    "fake",
+		Artifact:  artifact,
+		SHA256:    hex.EncodeToString(digest[:]),
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, image)),
+		PublicKey: base64.StdEncoding.EncodeToString(pub),
+		Resources: []string{"motor1"},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	image := []byte("some firmware")
+	cfg := signedConfig(t, image)
+	test.That(t, Verify(cfg, image), test.ShouldBeNil)
+
+	err := Verify(cfg, []byte("other firmware"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "sha256")
+
+	cfg.SHA256 = ""
+	err = Verify(cfg, []byte("other firmware"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "signature verification failed")
+}
+
+func TestUpdate(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	image := []byte("some firmware")
+	cfg := signedConfig(t, image)
+
+	flasher := &fakeFlasher{}
+	RegisterFlasher("fake", func(cfg config.FirmwareConfig, logger logging.Logger) (Flasher, error) {
+		return flasher, nil
+	})
+
+	q := &fakeQuiescer{}
+	test.That(t, Update(context.Background(), cfg, q, logger), test.ShouldBeNil)
+	test.That(t, flasher.flashed, test.ShouldResemble, image)
+	test.That(t, q.quiesced, test.ShouldResemble, []string{"motor1"})
+	test.That(t, q.resumed, test.ShouldBeTrue)
+
+	flasher.err = errors.New("bad flash")
+	q = &fakeQuiescer{}
+	err := Update(context.Background(), cfg, q, logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "bad flash")
+	test.That(t, q.resumed, test.ShouldBeTrue)
+
+	// tampered artifacts must never reach the flasher or quiesce anything.
+	flasher.flashed = nil
+	test.That(t, os.WriteFile(cfg.Artifact, []byte("tampered"), 0o600), test.ShouldBeNil)
+	q = &fakeQuiescer{}
+	test.That(t, Update(context.Background(), cfg, q, logger), test.ShouldNotBeNil)
+	test.That(t, flasher.flashed, test.ShouldBeNil)
+	test.That(t, q.quiesced, test.ShouldBeNil)
+
+	cfg.Target = "unknown"
+	test.That(t, Update(context.Background(), cfg, q, logger), test.ShouldNotBeNil)
+}
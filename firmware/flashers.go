@@ -0,0 +1,133 @@
+package firmware
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+func init() {
+	RegisterFlasher(config.FirmwareTargetESP32Serial, newESP32SerialFlasher)
+	RegisterFlasher(config.FirmwareTargetESP32OTA, newESP32OTAFlasher)
+	RegisterFlasher(config.FirmwareTargetSTM32DFU, newSTM32DFUFlasher)
+}
+
+// commandFlasher flashes by handing the image to an external tool.
+type commandFlasher struct {
+	logger logging.Logger
+	tool   string
+	args   func(imagePath string) []string
+}
+
+func (f *commandFlasher) Flash(ctx context.Context, image []byte) error {
+	if _, err := exec.LookPath(f.tool); err != nil {
+		return errors.Wrapf(err, "%s is required to flash this target", f.tool)
+	}
+	tmp, err := os.CreateTemp("", "firmware-*.bin")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := os.Remove(tmp.Name()); err != nil {
+			f.logger.Debugw("failed to remove temporary firmware image", "error", err)
+		}
+	}()
+	if _, err := tmp.Write(image); err != nil {
+		return multierr.Combine(err, tmp.Close())
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, f.tool, f.args(tmp.Name())...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return errors.Wrapf(err, "%s failed: %s", f.tool, strings.TrimSpace(string(out)))
+	}
+	f.logger.Debugw("flash tool output", "tool", f.tool, "output", string(out))
+	return nil
+}
+
+// newESP32SerialFlasher flashes an ESP32 over serial using esptool.
+func newESP32SerialFlasher(cfg config.FirmwareConfig, logger logging.Logger) (Flasher, error) {
+	return &commandFlasher{
+		logger: logger,
+		tool:   "esptool.py",
+		args: func(imagePath string) []string {
+			return []string{"--port", cfg.Port, "write_flash", "0x10000", imagePath}
+		},
+	}, nil
+}
+
+// newSTM32DFUFlasher flashes an STM32 over USB DFU using dfu-util.
+func newSTM32DFUFlasher(cfg config.FirmwareConfig, logger logging.Logger) (Flasher, error) {
+	return &commandFlasher{
+		logger: logger,
+		tool:   "dfu-util",
+		args: func(imagePath string) []string {
+			return []string{"--device", cfg.Port, "--alt", "0", "--dfuse-address", "0x08000000:leave", "--download", imagePath}
+		},
+	}, nil
+}
+
+// esp32OTAFlasher uploads an image to the HTTP update endpoint exposed by the ESP32
+// Arduino/IDF OTA libraries.
+type esp32OTAFlasher struct {
+	url    string
+	client *http.Client
+}
+
+func newESP32OTAFlasher(cfg config.FirmwareConfig, logger logging.Logger) (Flasher, error) {
+	url := cfg.Address
+	if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		url = fmt.Sprintf("http://%s/update", url)
+	}
+	return &esp32OTAFlasher{url: url, client: http.DefaultClient}, nil
+}
+
+func (f *esp32OTAFlasher) Flash(ctx context.Context, image []byte) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("update", "firmware.bin")
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(image); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.url, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		//nolint:errcheck
+		resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("OTA update failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
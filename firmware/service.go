@@ -0,0 +1,64 @@
+package firmware
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service triggering the firmware updates of a robot. Its
+// requests and responses are google.protobuf.Struct messages holding the JSON form of the
+// types below.
+const ServiceName = "viam.rdk.firmware.v1.FirmwareService"
+
+// An Updater flashes the firmware updates in the config of a robot.
+type Updater interface {
+	// UpdateFirmware flashes the named firmware update from the config to its
+	// microcontroller, quiescing the resources that depend on it while flashing.
+	UpdateFirmware(ctx context.Context, name string) error
+}
+
+type request struct {
+	Name string `json:"name"`
+}
+
+// A Server serves an updater with ServiceDesc.
+type Server struct {
+	updater Updater
+}
+
+// NewServer returns a server for the given updater.
+func NewServer(updater Updater) *Server {
+	return &Server{updater: updater}
+}
+
+func (s *Server) update(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.updater.UpdateFirmware(ctx, req.Name)
+}
+
+// ServiceDesc describes the gRPC service triggering the firmware updates of a robot. It is
+// served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/firmware",
+	structrpc.Unary("Update", (*Server).update),
+)
+
+// A Client is the Updater of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Updater = (*Client)(nil)
+
+// NewClient returns a client of the updater served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// UpdateFirmware flashes the named firmware update from the config of the robot to its
+// microcontroller. It returns once flashing is done and the resources depending on the
+// microcontroller are rebuilt.
+func (c *Client) UpdateFirmware(ctx context.Context, name string) error {
+	return c.client.Invoke(ctx, "Update", request{Name: name}, nil)
+}
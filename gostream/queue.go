@@ -0,0 +1,150 @@
+package gostream
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// A QueuePolicy is what a Queue does with an item pushed to it while it is full.
+type QueuePolicy string
+
+// The policies of a Queue.
+const (
+	// QueueDropNewest drops the item pushed, keeping the items already queued. It is the
+	// policy of a Queue whose policy is not set.
+	QueueDropNewest QueuePolicy = "drop_newest"
+	// QueueDropOldest drops the oldest item queued to make room for the item pushed, so that
+	// a consumer falling behind catches up on the newest items.
+	QueueDropOldest QueuePolicy = "drop_oldest"
+	// QueueLatestOnly keeps only the newest item, whatever the size of the queue, so that a
+	// slow consumer, such as vision inference, always receives the freshest frame.
+	QueueLatestOnly QueuePolicy = "latest_only"
+	// QueueBlock waits for the consumer to make room for the item pushed, holding back the
+	// producer, for consumers that must not miss an item.
+	QueueBlock QueuePolicy = "block"
+)
+
+// QueueOptions configure a Queue.
+type QueueOptions struct {
+	// Size is how many items the queue holds.
+	Size   int         `json:"size,omitempty"`
+	Policy QueuePolicy `json:"policy,omitempty"`
+}
+
+// Validate returns an error if the options do not describe a queue.
+func (o QueueOptions) Validate() error {
+	switch o.Policy {
+	case "", QueueDropNewest, QueueDropOldest, QueueLatestOnly, QueueBlock:
+	default:
+		return errors.Errorf("unknown queue policy %q", o.Policy)
+	}
+	if o.Size < 0 {
+		return errors.New("queue size cannot be negative")
+	}
+	return nil
+}
+
+// QueueStats count the items pushed to a Queue.
+type QueueStats struct {
+	// Queued is how many items were queued for the consumer.
+	Queued uint64
+	// Dropped is how many items were dropped, whether they were pushed while the queue was
+	// full or dropped from the queue to make room.
+	Dropped uint64
+}
+
+// A Queue passes items from a producer to a consumer with a policy for when the consumer
+// falls behind, counting the items it drops.
+type Queue[T any] struct {
+	policy QueuePolicy
+	items  chan T
+	closed chan struct{}
+
+	// pushMu serializes pushes, so that making room for an item is not raced by another push,
+	// and closing, so that no push is in progress when the items are closed.
+	pushMu    sync.Mutex
+	closeOnce sync.Once
+	queued    atomic.Uint64
+	dropped   atomic.Uint64
+}
+
+// NewQueue returns a queue with the given options.
+func NewQueue[T any](opts QueueOptions) *Queue[T] {
+	size := opts.Size
+	if opts.Policy == QueueLatestOnly {
+		size = 1
+	}
+	policy := opts.Policy
+	if policy == "" {
+		policy = QueueDropNewest
+	}
+	return &Queue[T]{policy: policy, items: make(chan T, size), closed: make(chan struct{})}
+}
+
+// Items returns the items queued, which is closed once the queue is.
+func (q *Queue[T]) Items() <-chan T {
+	return q.items
+}
+
+// Push queues the item following the policy of the queue, and returns whether it was queued.
+// A queue with QueueBlock waits for room until the context is done or the queue is closed.
+func (q *Queue[T]) Push(ctx context.Context, item T) bool {
+	q.pushMu.Lock()
+	defer q.pushMu.Unlock()
+	select {
+	case <-q.closed:
+		return false
+	default:
+	}
+
+	select {
+	case q.items <- item:
+		q.queued.Add(1)
+		return true
+	default:
+	}
+	switch q.policy {
+	case QueueDropOldest, QueueLatestOnly:
+		// the consumer may take the oldest item in the meantime, leaving room.
+		select {
+		case <-q.items:
+			q.dropped.Add(1)
+		default:
+		}
+		select {
+		case q.items <- item:
+			q.queued.Add(1)
+			return true
+		default:
+		}
+	case QueueBlock:
+		select {
+		case q.items <- item:
+			q.queued.Add(1)
+			return true
+		case <-q.closed:
+		case <-ctx.Done():
+		}
+	case QueueDropNewest:
+	}
+	q.dropped.Add(1)
+	return false
+}
+
+// Close closes the queue, ending pushes waiting on it, and then its items.
+func (q *Queue[T]) Close() {
+	q.closeOnce.Do(func() {
+		close(q.closed)
+		q.pushMu.Lock()
+		defer q.pushMu.Unlock()
+		close(q.items)
+	})
+}
+
+// Stats returns the counts of the items pushed to the queue so far.
+func (q *Queue[T]) Stats() QueueStats {
+	return QueueStats{Queued: q.queued.Load(), Dropped: q.dropped.Load()}
+}
@@ -0,0 +1,80 @@
+package gostream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+)
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	drain := func(q *Queue[int]) []int {
+		var items []int
+		for {
+			select {
+			case item := <-q.Items():
+				items = append(items, item)
+			default:
+				return items
+			}
+		}
+	}
+
+	for _, tc := range []struct {
+		policy QueuePolicy
+		queued []int
+	}{
+		{"", []int{1, 2}},
+		{QueueDropNewest, []int{1, 2}},
+		{QueueDropOldest, []int{3, 4}},
+		{QueueLatestOnly, []int{4}},
+	} {
+		t.Run(string(tc.policy), func(t *testing.T) {
+			q := NewQueue[int](QueueOptions{Size: 2, Policy: tc.policy})
+			for i := 1; i <= 4; i++ {
+				q.Push(ctx, i)
+			}
+			test.That(t, drain(q), test.ShouldResemble, tc.queued)
+			stats := q.Stats()
+			test.That(t, stats.Dropped, test.ShouldEqual, 4-len(tc.queued))
+
+			q.Close()
+			test.That(t, q.Push(ctx, 5), test.ShouldBeFalse)
+			_, ok := <-q.Items()
+			test.That(t, ok, test.ShouldBeFalse)
+			q.Close()
+		})
+	}
+
+	t.Run(string(QueueBlock), func(t *testing.T) {
+		q := NewQueue[int](QueueOptions{Size: 1, Policy: QueueBlock})
+		test.That(t, q.Push(ctx, 1), test.ShouldBeTrue)
+
+		pushed := make(chan bool)
+		go func() { pushed <- q.Push(ctx, 2) }()
+		select {
+		case <-pushed:
+			t.Fatal("push did not wait for room")
+		case <-time.After(10 * time.Millisecond):
+		}
+		test.That(t, <-q.Items(), test.ShouldEqual, 1)
+		test.That(t, <-pushed, test.ShouldBeTrue)
+		test.That(t, <-q.Items(), test.ShouldEqual, 2)
+
+		// a push waiting for room gives up once its context is done or the queue is closed.
+		test.That(t, q.Push(ctx, 3), test.ShouldBeTrue)
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		test.That(t, q.Push(timeoutCtx, 4), test.ShouldBeFalse)
+		test.That(t, q.Stats(), test.ShouldResemble, QueueStats{Queued: 3, Dropped: 1})
+		go func() { pushed <- q.Push(ctx, 5) }()
+		q.Close()
+		test.That(t, <-pushed, test.ShouldBeFalse)
+	})
+
+	test.That(t, QueueOptions{Policy: "newest"}.Validate(), test.ShouldBeError, `unknown queue policy "newest"`)
+	test.That(t, QueueOptions{Size: -1}.Validate(), test.ShouldNotBeNil)
+	test.That(t, QueueOptions{Size: 8, Policy: QueueBlock}.Validate(), test.ShouldBeNil)
+}
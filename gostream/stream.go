@@ -50,6 +50,24 @@ type internalStream interface {
 	AudioTrackLocal() (webrtc.TrackLocal, bool)
 }
 
+// An EncodedVideoFrame is a frame of video as encoded for a stream.
+type EncodedVideoFrame struct {
+	Data []byte
+	// MIMEType is the MIME type of the stream's video encoding, such as "video/H264".
+	MIMEType string
+	// Time is when the frame was written to the stream's video track.
+	Time time.Time
+}
+
+// An EncodedVideoSubscriber is a Stream whose video frames can be received as encoded for
+// its video track, so that they can be kept without encoding them again.
+type EncodedVideoSubscriber interface {
+	// SubscribeEncodedVideo returns the queue of the video frames the stream encodes from now
+	// on, until unsubscribe is called, which closes the queue. Frames the subscriber has no
+	// room for are handled with the policy of the queue.
+	SubscribeEncodedVideo(queue QueueOptions) (frames *Queue[EncodedVideoFrame], unsubscribe func())
+}
+
 // MediaReleasePair associates a media with a corresponding
 // function to release its resources once the receiver of a
 // pair is finished with the media.
@@ -105,6 +123,8 @@ func NewStream(config StreamConfig) (Stream, error) {
 		inputImageChan:  make(chan MediaReleasePair[image.Image]),
 		outputVideoChan: make(chan []byte),
 
+		videoSubscribers: map[*Queue[EncodedVideoFrame]]struct{}{},
+
 		audioTrackLocal: audioTrackLocal,
 		inputAudioChan:  make(chan MediaReleasePair[wave.Audio]),
 		outputAudioChan: make(chan []byte),
@@ -129,6 +149,9 @@ type basicStream struct {
 	outputVideoChan chan []byte
 	videoEncoder    codec.VideoEncoder
 
+	videoSubscribersMu sync.Mutex
+	videoSubscribers   map[*Queue[EncodedVideoFrame]]struct{}
+
 	audioTrackLocal *trackLocalStaticSample
 	inputAudioChan  chan MediaReleasePair[wave.Audio]
 	outputAudioChan chan []byte
@@ -379,6 +402,35 @@ func (bs *basicStream) processOutputFrames() {
 		if Debug {
 			bs.logger.Debugw("wrote sample", "frames_sent", framesSent, "write_time", time.Since(now))
 		}
+		bs.publishEncodedVideo(EncodedVideoFrame{
+			Data:     outputFrame,
+			MIMEType: bs.config.VideoEncoderFactory.MIMEType(),
+			Time:     now,
+		})
+	}
+}
+
+func (bs *basicStream) SubscribeEncodedVideo(queue QueueOptions) (*Queue[EncodedVideoFrame], func()) {
+	frames := NewQueue[EncodedVideoFrame](queue)
+	bs.videoSubscribersMu.Lock()
+	bs.videoSubscribers[frames] = struct{}{}
+	bs.videoSubscribersMu.Unlock()
+	return frames, func() {
+		// closed first, so that a publish blocked on the subscriber gives up.
+		frames.Close()
+		bs.videoSubscribersMu.Lock()
+		defer bs.videoSubscribersMu.Unlock()
+		delete(bs.videoSubscribers, frames)
+	}
+}
+
+// publishEncodedVideo pushes the frame to the queues of the subscribers to the stream's
+// encoded video.
+func (bs *basicStream) publishEncodedVideo(frame EncodedVideoFrame) {
+	bs.videoSubscribersMu.Lock()
+	defer bs.videoSubscribersMu.Unlock()
+	for frames := range bs.videoSubscribers {
+		frames.Push(bs.shutdownCtx, frame)
 	}
 }
 
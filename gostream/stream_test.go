@@ -7,8 +7,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/edaniels/golog"
+	"github.com/pion/mediadevices/pkg/prop"
 	"go.viam.com/test"
 	"golang.org/x/time/rate"
+
+	"go.viam.com/rdk/gostream/codec"
 )
 
 func init() {
@@ -159,3 +163,53 @@ func BenchmarkStream_30FPS_3Streams(b *testing.B) {
 	cancel()
 	b.ReportMetric(SecondNs/avgNs, "fps")
 }
+
+// countingEncoder encodes each frame as the number of frames encoded before it.
+type countingEncoder struct {
+	count byte
+}
+
+func (e *countingEncoder) Encode(ctx context.Context, img image.Image) ([]byte, error) {
+	e.count++
+	return []byte{e.count}, nil
+}
+
+func (e *countingEncoder) Close() error { return nil }
+
+type countingEncoderFactory struct{}
+
+func (countingEncoderFactory) New(height, width, keyFrameInterval int, logger golog.Logger) (codec.VideoEncoder, error) {
+	return &countingEncoder{}, nil
+}
+
+func (countingEncoderFactory) MIMEType() string { return "video/counting" }
+
+func TestSubscribeEncodedVideo(t *testing.T) {
+	s, err := NewStream(StreamConfig{VideoEncoderFactory: countingEncoderFactory{}, TargetFrameRate: 100})
+	test.That(t, err, test.ShouldBeNil)
+	s.Start()
+	defer s.Stop()
+	input, err := s.InputVideoFrames(prop.Video{})
+	test.That(t, err, test.ShouldBeNil)
+
+	subscriber, ok := s.(EncodedVideoSubscriber)
+	test.That(t, ok, test.ShouldBeTrue)
+	frames, unsubscribe := subscriber.SubscribeEncodedVideo(QueueOptions{Size: 1})
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	input <- MediaReleasePair[image.Image]{Media: img}
+	frame := <-frames.Items()
+	test.That(t, frame.Data, test.ShouldResemble, []byte{1})
+	test.That(t, frame.MIMEType, test.ShouldEqual, "video/counting")
+
+	// frames are dropped while the subscriber has no room for them.
+	input <- MediaReleasePair[image.Image]{Media: img}
+	input <- MediaReleasePair[image.Image]{Media: img}
+	input <- MediaReleasePair[image.Image]{Media: img}
+	frame = <-frames.Items()
+	test.That(t, frame.Data, test.ShouldResemble, []byte{2})
+
+	unsubscribe()
+	_, ok = <-frames.Items()
+	test.That(t, ok, test.ShouldBeFalse)
+	unsubscribe()
+}
@@ -0,0 +1,180 @@
+package recording
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+)
+
+// MaxStreamMessages is the number of requests and responses recorded for each stream.
+// Later messages are dropped and the call is marked truncated so that long running
+// streams (e.g. audio) do not grow without bound.
+const MaxStreamMessages = 100
+
+// A Recorder writes the resource API calls it intercepts to a trace. Calls are
+// recorded once they finish.
+type Recorder struct {
+	mu     sync.Mutex
+	enc    *json.Encoder
+	closer io.Closer
+	logger logging.Logger
+	failed bool
+}
+
+// NewRecorder returns a Recorder that writes its trace to w.
+func NewRecorder(w io.Writer, logger logging.Logger) *Recorder {
+	return &Recorder{enc: json.NewEncoder(w), logger: logger}
+}
+
+// OpenTrace returns a Recorder that appends its trace to the file at path, creating it
+// if needed. Appending keeps calls recorded before a web server restart.
+func OpenTrace(path string, logger logging.Logger) (*Recorder, error) {
+	//nolint:gosec
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	rec := NewRecorder(f, logger)
+	rec.closer = f
+	return rec, nil
+}
+
+// Close closes the underlying trace file, if any.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closer == nil {
+		return nil
+	}
+	err := r.closer.Close()
+	r.closer = nil
+	r.enc = json.NewEncoder(io.Discard)
+	return err
+}
+
+func (r *Recorder) record(call Call, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err = multierr.Combine(err, r.enc.Encode(call))
+	if err != nil && !r.failed {
+		// only log the first failure to avoid flooding the logs.
+		r.failed = true
+		r.logger.Errorw("failed to record call", "method", call.Method, "error", err)
+	}
+}
+
+// isResourceRequest reports whether a request is made to a resource. Only these are
+// recorded.
+func isResourceRequest(req interface{}) bool {
+	named, ok := req.(interface{ GetName() string })
+	return ok && named.GetName() != ""
+}
+
+// UnaryServerInterceptor records unary calls on resources.
+func (r *Recorder) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if !isResourceRequest(req) {
+		return handler(ctx, req)
+	}
+
+	start := clock.Now()
+	resp, err := handler(ctx, req)
+	call := Call{Method: info.FullMethod, Start: start, Duration: clock.Since(start)}
+	recordErr := call.addRequest(req)
+	if err == nil {
+		recordErr = multierr.Combine(recordErr, call.addResponse(resp))
+	}
+	call.setError(err)
+	r.record(call, recordErr)
+	return resp, err
+}
+
+// StreamServerInterceptor records streaming calls on resources. Whether a stream is
+// made to a resource is known once its first request is received.
+func (r *Recorder) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	stream := &recordingStream{
+		ServerStream: ss,
+		call: Call{
+			Method:        info.FullMethod,
+			ClientStreams: info.IsClientStream,
+			ServerStreams: info.IsServerStream,
+			Start:         clock.Now(),
+		},
+	}
+	err := handler(srv, stream)
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if stream.resource {
+		stream.call.Duration = clock.Since(stream.call.Start)
+		stream.call.setError(err)
+		r.record(stream.call, stream.err)
+	}
+	return err
+}
+
+type recordingStream struct {
+	grpc.ServerStream
+
+	// mu guards the fields below since RecvMsg and SendMsg may be called concurrently.
+	mu       sync.Mutex
+	call     Call
+	received bool
+	resource bool
+	err      error
+}
+
+func (s *recordingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.received {
+		s.received = true
+		s.resource = isResourceRequest(m)
+	}
+	if !s.resource {
+		return nil
+	}
+	if len(s.call.Requests) >= MaxStreamMessages {
+		s.call.Truncated = true
+		return nil
+	}
+	s.err = multierr.Combine(s.err, s.call.addRequest(m))
+	return nil
+}
+
+func (s *recordingStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.resource {
+		return nil
+	}
+	if len(s.call.Responses) >= MaxStreamMessages {
+		s.call.Truncated = true
+		return nil
+	}
+	s.err = multierr.Combine(s.err, s.call.addResponse(m))
+	return nil
+}
@@ -0,0 +1,132 @@
+// Package recording records the resource API requests and responses served by a robot
+// to a trace file and replays traces against a robot, comparing the results. Traces
+// make golden regression tests of drivers and services possible.
+package recording
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// A Call is a single recorded gRPC call. Traces are stored as one JSON encoded Call
+// per line.
+type Call struct {
+	// Method is the full gRPC method name.
+	Method        string `json:"method"`
+	ClientStreams bool   `json:"client_streams,omitempty"`
+	ServerStreams bool   `json:"server_streams,omitempty"`
+	// Start is when the call started and Duration is how long it took.
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	// RequestType and ResponseType are the full protobuf message names.
+	RequestType  string            `json:"request_type"`
+	ResponseType string            `json:"response_type,omitempty"`
+	Requests     []json.RawMessage `json:"requests"`
+	Responses    []json.RawMessage `json:"responses,omitempty"`
+	// Truncated is set when a stream produced more messages than were recorded.
+	Truncated bool `json:"truncated,omitempty"`
+	// Code and Message are the status of a failed call.
+	Code    codes.Code `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+func (c *Call) addRequest(m interface{}) error {
+	raw, name, err := marshalMessage(m)
+	if err != nil {
+		return err
+	}
+	c.RequestType = name
+	c.Requests = append(c.Requests, raw)
+	return nil
+}
+
+func (c *Call) addResponse(m interface{}) error {
+	raw, name, err := marshalMessage(m)
+	if err != nil {
+		return err
+	}
+	c.ResponseType = name
+	c.Responses = append(c.Responses, raw)
+	return nil
+}
+
+func (c *Call) setError(err error) {
+	if err == nil {
+		return
+	}
+	s := status.Convert(err)
+	c.Code = s.Code()
+	c.Message = s.Message()
+}
+
+func marshalMessage(m interface{}) (json.RawMessage, string, error) {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil, "", errors.Errorf("expected proto.Message but got %T", m)
+	}
+	raw, err := protojson.Marshal(msg)
+	if err != nil {
+		return nil, "", err
+	}
+	return raw, string(proto.MessageName(msg)), nil
+}
+
+func unmarshalMessage(typeName string, raw json.RawMessage) (proto.Message, error) {
+	msg, err := newMessage(typeName)
+	if err != nil {
+		return nil, err
+	}
+	if err := protojson.Unmarshal(raw, msg); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s", typeName)
+	}
+	return msg, nil
+}
+
+func newMessage(typeName string) (proto.Message, error) {
+	msgType, err := protoregistry.GlobalTypes.FindMessageByName(protoreflect.FullName(typeName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unknown message type %q", typeName)
+	}
+	return msgType.New().Interface(), nil
+}
+
+// Read reads a trace of calls.
+func Read(r io.Reader) ([]Call, error) {
+	var calls []Call
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 64<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var call Call
+		if err := json.Unmarshal(scanner.Bytes(), &call); err != nil {
+			return nil, errors.Wrapf(err, "failed to read call %d", len(calls))
+		}
+		calls = append(calls, call)
+	}
+	return calls, scanner.Err()
+}
+
+// Load reads a trace of calls from the given file.
+func Load(path string) ([]Call, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	return Read(f)
+}
@@ -0,0 +1,115 @@
+package recording_test
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+
+	"go.viam.com/rdk/components/motor"
+	fakemotor "go.viam.com/rdk/components/motor/fake"
+	"go.viam.com/rdk/config"
+	rgrpc "go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/grpc/recording"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	robotimpl "go.viam.com/rdk/robot/impl"
+	"go.viam.com/rdk/testutils/robottestutils"
+)
+
+func setupMotorRobot(t *testing.T, recordTrace string) (robot.LocalRobot, string) {
+	t.Helper()
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "m1",
+				Model:               resource.DefaultModelFamily.WithModel("fake"),
+				API:                 motor.API,
+				ConvertedAttributes: &fakemotor.Config{},
+			},
+		},
+	}
+	r, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	})
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.RecordTrace = recordTrace
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+	return r, addr
+}
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	tracePath := filepath.Join(t.TempDir(), "trace.jsonl")
+
+	// record
+	r, addr := setupMotorRobot(t, tracePath)
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	m1, err := motor.FromRobot(robotClient, "m1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m1.SetPower(ctx, 0.5, nil), test.ShouldBeNil)
+	on, powerPct, err := m1.IsPowered(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeTrue)
+	test.That(t, powerPct, test.ShouldEqual, 0.5)
+	test.That(t, m1.GoFor(ctx, 0, 1, nil), test.ShouldNotBeNil)
+	test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	r.StopWeb()
+
+	calls, err := recording.Load(tracePath)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, calls, test.ShouldHaveLength, 3)
+	test.That(t, calls[0].Method, test.ShouldEqual, "/viam.component.motor.v1.MotorService/SetPower")
+	test.That(t, calls[1].ResponseType, test.ShouldEqual, "viam.component.motor.v1.IsPoweredResponse")
+	test.That(t, calls[2].Code, test.ShouldNotEqual, codes.OK)
+
+	// replay against a fresh robot
+	_, addr = setupMotorRobot(t, "")
+	conn, err := rgrpc.Dial(ctx, addr, logger, rpc.WithWebRTCOptions(rpc.DialWebRTCOptions{Disable: true}))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+
+	report, err := recording.Replay(ctx, conn, calls, recording.ReplayOptions{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Calls, test.ShouldEqual, 3)
+	test.That(t, report.Err(), test.ShouldBeNil)
+
+	// a regression is reported as a mismatch
+	calls[0].Requests[0] = []byte(`{"name":"m1","powerPct":0.25}`)
+	report, err = recording.Replay(ctx, conn, calls, recording.ReplayOptions{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Mismatches, test.ShouldHaveLength, 1)
+	test.That(t, report.Mismatches[0].Index, test.ShouldEqual, 1)
+	test.That(t, report.Err().Error(), test.ShouldContainSubstring, "IsPowered")
+
+	// unless the differing field is ignored
+	report, err = recording.Replay(ctx, conn, calls, recording.ReplayOptions{IgnoreFields: []string{"power_pct"}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Mismatches, test.ShouldBeEmpty)
+}
+
+func TestRead(t *testing.T) {
+	calls, err := recording.Read(bytes.NewBufferString(
+		`{"method":"/a/B","request_type":"x","requests":[{}]}` + "\n\n" + `{"method":"/a/C","requests":[]}` + "\n"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, calls, test.ShouldHaveLength, 2)
+	test.That(t, calls[1].Method, test.ShouldEqual, "/a/C")
+
+	_, err = recording.Read(bytes.NewBufferString("not json\n"))
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -0,0 +1,238 @@
+package recording
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"go.viam.com/rdk/clock"
+)
+
+// ReplayOptions configure how a trace is replayed.
+type ReplayOptions struct {
+	// Timing waits between calls as long as was recorded, using the robot clock.
+	// Otherwise calls are made back to back.
+	Timing bool
+
+	// IgnoreFields are the names of response fields, at any depth, that are not
+	// compared (e.g. timestamps that change between runs).
+	IgnoreFields []string
+
+	// Equal compares a recorded response to a replayed one. Defaults to proto.Equal
+	// after clearing IgnoreFields.
+	Equal func(method string, want, got proto.Message) bool
+}
+
+// A Mismatch is a replayed call whose result differs from the recorded one.
+type Mismatch struct {
+	Index  int
+	Method string
+	Want   string
+	Got    string
+}
+
+// String describes the mismatch.
+func (m Mismatch) String() string {
+	return fmt.Sprintf("call %d (%s): want %s; got %s", m.Index, m.Method, m.Want, m.Got)
+}
+
+// A Report is the result of replaying a trace.
+type Report struct {
+	Calls      int
+	Mismatches []Mismatch
+}
+
+// Err returns an error describing every mismatch, if any.
+func (r *Report) Err() error {
+	var err error
+	for _, m := range r.Mismatches {
+		err = multierr.Combine(err, errors.New(m.String()))
+	}
+	return err
+}
+
+// Replay makes every call in the trace over conn in order and compares the results
+// with the recorded ones. Results that differ are reported as mismatches; an error is
+// only returned if the trace cannot be replayed. Streams that were truncated or
+// canceled while recording are only replayed up to their recorded responses.
+func Replay(ctx context.Context, conn grpc.ClientConnInterface, calls []Call, opts ReplayOptions) (*Report, error) {
+	if opts.Equal == nil {
+		opts.Equal = func(method string, want, got proto.Message) bool {
+			clearFields(want.ProtoReflect(), opts.IgnoreFields)
+			clearFields(got.ProtoReflect(), opts.IgnoreFields)
+			return proto.Equal(want, got)
+		}
+	}
+
+	report := &Report{}
+	for idx, call := range calls {
+		if opts.Timing && idx > 0 {
+			if !clock.SleepContext(ctx, call.Start.Sub(calls[idx-1].Start)) {
+				return report, ctx.Err()
+			}
+		}
+
+		var (
+			mismatch *Mismatch
+			err      error
+		)
+		if call.ClientStreams || call.ServerStreams {
+			mismatch, err = replayStream(ctx, conn, call, opts)
+		} else {
+			mismatch, err = replayUnary(ctx, conn, call, opts)
+		}
+		if err != nil {
+			return report, errors.Wrapf(err, "failed to replay call %d (%s)", idx, call.Method)
+		}
+		report.Calls++
+		if mismatch != nil {
+			mismatch.Index = idx
+			mismatch.Method = call.Method
+			report.Mismatches = append(report.Mismatches, *mismatch)
+		}
+	}
+	return report, nil
+}
+
+func replayUnary(ctx context.Context, conn grpc.ClientConnInterface, call Call, opts ReplayOptions) (*Mismatch, error) {
+	if len(call.Requests) != 1 {
+		return nil, errors.Errorf("expected 1 request but got %d", len(call.Requests))
+	}
+	req, err := unmarshalMessage(call.RequestType, call.Requests[0])
+	if err != nil {
+		return nil, err
+	}
+	respType := call.ResponseType
+	if respType == "" {
+		// failed calls do not record their response type.
+		return compareStatus(call, conn.Invoke(ctx, call.Method, req, &emptypb.Empty{})), nil
+	}
+	got, err := newMessage(respType)
+	if err != nil {
+		return nil, err
+	}
+	if mismatch := compareStatus(call, conn.Invoke(ctx, call.Method, req, got)); mismatch != nil {
+		return mismatch, nil
+	}
+	return compareResponses(call, []proto.Message{got}, opts)
+}
+
+func replayStream(ctx context.Context, conn grpc.ClientConnInterface, call Call, opts ReplayOptions) (*Mismatch, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{ClientStreams: call.ClientStreams, ServerStreams: call.ServerStreams}
+	stream, err := conn.NewStream(ctx, desc, call.Method)
+	if err != nil {
+		return compareStatus(call, err), nil
+	}
+	for _, raw := range call.Requests {
+		req, err := unmarshalMessage(call.RequestType, raw)
+		if err != nil {
+			return nil, err
+		}
+		if err := stream.SendMsg(req); err != nil {
+			break
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		return compareStatus(call, err), nil
+	}
+
+	// streams the client ended early, such as video streams, are only replayed as far
+	// as they were recorded.
+	partial := call.Truncated || call.Code == codes.Canceled
+	var got []proto.Message
+	var recvErr error
+	for !partial || len(got) < len(call.Responses) {
+		var resp proto.Message = &emptypb.Empty{}
+		if call.ResponseType != "" {
+			if resp, err = newMessage(call.ResponseType); err != nil {
+				return nil, err
+			}
+		}
+		if recvErr = stream.RecvMsg(resp); recvErr != nil {
+			if errors.Is(recvErr, io.EOF) {
+				recvErr = nil
+			}
+			break
+		}
+		got = append(got, resp)
+	}
+	if partial && len(got) == len(call.Responses) {
+		return compareResponses(call, got, opts)
+	}
+	if mismatch := compareStatus(call, recvErr); mismatch != nil {
+		return mismatch, nil
+	}
+	return compareResponses(call, got, opts)
+}
+
+func compareStatus(call Call, err error) *Mismatch {
+	got := status.Convert(err)
+	if got.Code() == call.Code {
+		return nil
+	}
+	return &Mismatch{
+		Want: fmt.Sprintf("status %s (%s)", call.Code, call.Message),
+		Got:  fmt.Sprintf("status %s (%s)", got.Code(), got.Message()),
+	}
+}
+
+func compareResponses(call Call, got []proto.Message, opts ReplayOptions) (*Mismatch, error) {
+	if len(got) != len(call.Responses) {
+		return &Mismatch{
+			Want: fmt.Sprintf("%d responses", len(call.Responses)),
+			Got:  fmt.Sprintf("%d responses", len(got)),
+		}, nil
+	}
+	for idx, raw := range call.Responses {
+		want, err := unmarshalMessage(call.ResponseType, raw)
+		if err != nil {
+			return nil, err
+		}
+		if !opts.Equal(call.Method, want, got[idx]) {
+			return &Mismatch{Want: string(raw), Got: protojson.Format(got[idx])}, nil
+		}
+	}
+	return nil, nil
+}
+
+// clearFields clears the named fields anywhere in m.
+func clearFields(m protoreflect.Message, names []string) {
+	if len(names) == 0 {
+		return
+	}
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if slices.Contains(names, string(fd.Name())) {
+			m.Clear(fd)
+			return true
+		}
+		switch {
+		case fd.IsList() && fd.Message() != nil:
+			list := v.List()
+			for i := 0; i < list.Len(); i++ {
+				clearFields(list.Get(i).Message(), names)
+			}
+		case fd.IsMap() && fd.MapValue().Message() != nil:
+			v.Map().Range(func(_ protoreflect.MapKey, mv protoreflect.Value) bool {
+				clearFields(mv.Message(), names)
+				return true
+			})
+		case !fd.IsList() && !fd.IsMap() && fd.Message() != nil:
+			clearFields(v.Message(), names)
+		}
+		return true
+	})
+}
@@ -0,0 +1,217 @@
+// Package structrpc serves and calls the gRPC services of a robot that have no protos. Their
+// requests and responses are google.protobuf.Struct messages holding the JSON form of Go values.
+package structrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Encode returns the google.protobuf.Struct holding the JSON form of v, which must encode to a
+// JSON object.
+func Encode(v interface{}) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Decode decodes the JSON form held by s into v.
+func Decode(s *structpb.Struct, v interface{}) error {
+	data, err := s.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func decodeRequest(in *structpb.Struct, req interface{}) error {
+	if err := Decode(in, req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	return nil
+}
+
+// A ServerStream is the stream of a call of a server streaming method, its responses are sent on.
+type ServerStream struct {
+	grpc.ServerStream
+}
+
+// Send sends the JSON form of v as a response.
+func (s ServerStream) Send(v interface{}) error {
+	msg, err := Encode(v)
+	if err != nil {
+		return err
+	}
+	return s.SendMsg(msg)
+}
+
+// A Method is a method of a service, handled by the server registered for it.
+type Method struct {
+	name   string
+	unary  func(srv interface{}, ctx context.Context, in *structpb.Struct) (interface{}, error)
+	stream func(srv interface{}, in *structpb.Struct, stream ServerStream) error
+}
+
+// Unary returns the unary method handled by handle, which is given the server of the call and
+// its decoded request, and returns the value of its response. A request that cannot be decoded
+// is refused with an InvalidArgument error.
+func Unary[S, Req any](name string, handle func(srv S, ctx context.Context, req Req) (interface{}, error)) Method {
+	return Method{
+		name: name,
+		unary: func(srv interface{}, ctx context.Context, in *structpb.Struct) (interface{}, error) {
+			var req Req
+			if err := decodeRequest(in, &req); err != nil {
+				return nil, err
+			}
+			return handle(srv.(S), ctx, req)
+		},
+	}
+}
+
+// ServerStreaming returns the server streaming method handled by handle, which is given the
+// server of the call and its decoded request, and sends its responses on the stream.
+func ServerStreaming[S, Req any](name string, handle func(srv S, req Req, stream ServerStream) error) Method {
+	return Method{
+		name: name,
+		stream: func(srv interface{}, in *structpb.Struct, stream ServerStream) error {
+			var req Req
+			if err := decodeRequest(in, &req); err != nil {
+				return err
+			}
+			return handle(srv.(S), req, stream)
+		},
+	}
+}
+
+// ServiceDesc returns the description of the named service and its methods, to register the
+// server handling them with. The metadata names where the service is defined.
+func ServiceDesc(name, metadata string, methods ...Method) grpc.ServiceDesc {
+	desc := grpc.ServiceDesc{
+		ServiceName: name,
+		HandlerType: (*interface{})(nil),
+		Metadata:    metadata,
+	}
+	for _, method := range methods {
+		if method.unary != nil {
+			desc.Methods = append(desc.Methods, unaryMethodDesc(name, method))
+			continue
+		}
+		handle := method.stream
+		desc.Streams = append(desc.Streams, grpc.StreamDesc{
+			StreamName:    method.name,
+			ServerStreams: true,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				in := &structpb.Struct{}
+				if err := stream.RecvMsg(in); err != nil {
+					return err
+				}
+				return handle(srv, in, ServerStream{stream})
+			},
+		})
+	}
+	return desc
+}
+
+func unaryMethodDesc(service string, method Method) grpc.MethodDesc {
+	fullMethod := "/" + service + "/" + method.name
+	return grpc.MethodDesc{
+		MethodName: method.name,
+		Handler: func(
+			srv interface{},
+			ctx context.Context,
+			dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (interface{}, error) {
+			in := &structpb.Struct{}
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, in interface{}) (interface{}, error) {
+				resp, err := method.unary(srv, ctx, in.(*structpb.Struct))
+				if err != nil {
+					return nil, err
+				}
+				return Encode(resp)
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: fullMethod}, handler)
+		},
+	}
+}
+
+// A Client calls the methods of a service over a connection.
+type Client struct {
+	conn    grpc.ClientConnInterface
+	service string
+}
+
+// NewClient returns a client of the named service served over the given connection.
+func NewClient(conn grpc.ClientConnInterface, service string) *Client {
+	return &Client{conn: conn, service: service}
+}
+
+// Invoke calls the unary method with the JSON form of req, and decodes its response into resp
+// unless resp is nil.
+func (c *Client) Invoke(ctx context.Context, method string, req, resp interface{}) error {
+	in, err := Encode(req)
+	if err != nil {
+		return err
+	}
+	out := &structpb.Struct{}
+	if err := c.conn.Invoke(ctx, "/"+c.service+"/"+method, in, out); err != nil {
+		return err
+	}
+	if resp == nil {
+		return nil
+	}
+	return Decode(out, resp)
+}
+
+// Stream calls the server streaming method with the JSON form of req, and returns the stream
+// its responses are received on.
+func (c *Client) Stream(ctx context.Context, method string, req interface{}) (ClientStream, error) {
+	in, err := Encode(req)
+	if err != nil {
+		return ClientStream{}, err
+	}
+	desc := &grpc.StreamDesc{StreamName: method, ServerStreams: true}
+	stream, err := c.conn.NewStream(ctx, desc, "/"+c.service+"/"+method)
+	if err != nil {
+		return ClientStream{}, err
+	}
+	if err := stream.SendMsg(in); err != nil {
+		return ClientStream{}, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return ClientStream{}, err
+	}
+	return ClientStream{stream}, nil
+}
+
+// A ClientStream is the stream the responses of a call of a server streaming method are
+// received on.
+type ClientStream struct {
+	grpc.ClientStream
+}
+
+// Recv receives the next response, decoding it into v.
+func (s ClientStream) Recv(v interface{}) error {
+	out := &structpb.Struct{}
+	if err := s.RecvMsg(out); err != nil {
+		return err
+	}
+	return Decode(out, v)
+}
@@ -0,0 +1,95 @@
+package structrpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testServiceName = "viam.rdk.test.v1.TestService"
+
+type greeting struct {
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type testServer struct {
+	greeting string
+}
+
+func (s *testServer) greet(ctx context.Context, g greeting) (interface{}, error) {
+	if g.Name == "" {
+		return nil, errors.New("nobody to greet")
+	}
+	return greeting{Name: s.greeting + " " + g.Name}, nil
+}
+
+func (s *testServer) greetRepeatedly(g greeting, stream ServerStream) error {
+	for i := 0; i < g.Count; i++ {
+		if err := stream.Send(greeting{Name: s.greeting + " " + g.Name, Count: i}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var testServiceDesc = ServiceDesc(testServiceName, "rdk/grpc/structrpc",
+	Unary("Greet", (*testServer).greet),
+	ServerStreaming("GreetRepeatedly", (*testServer).greetRepeatedly),
+)
+
+func TestStructRPC(t *testing.T) {
+	listener := bufconn.Listen(1 << 16)
+	server := grpc.NewServer()
+	server.RegisterService(&testServiceDesc, &testServer{greeting: "hello"})
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	defer conn.Close()
+	client := NewClient(conn, testServiceName)
+	ctx := context.Background()
+
+	var resp greeting
+	test.That(t, client.Invoke(ctx, "Greet", greeting{Name: "world"}, &resp), test.ShouldBeNil)
+	test.That(t, resp.Name, test.ShouldEqual, "hello world")
+	test.That(t, client.Invoke(ctx, "Greet", greeting{Name: "world"}, nil), test.ShouldBeNil)
+
+	err = client.Invoke(ctx, "Greet", greeting{}, &resp)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "nobody to greet")
+	err = client.Invoke(ctx, "Greet", map[string]interface{}{"name": 1}, &resp)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	err = client.Invoke(ctx, "Wave", greeting{Name: "world"}, &resp)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unimplemented)
+
+	stream, err := client.Stream(ctx, "GreetRepeatedly", greeting{Name: "world", Count: 3})
+	test.That(t, err, test.ShouldBeNil)
+	var greetings []greeting
+	for {
+		var g greeting
+		if err := stream.Recv(&g); err != nil {
+			test.That(t, err, test.ShouldEqual, io.EOF)
+			break
+		}
+		greetings = append(greetings, g)
+	}
+	test.That(t, greetings, test.ShouldResemble, []greeting{
+		{Name: "hello world"},
+		{Name: "hello world", Count: 1},
+		{Name: "hello world", Count: 2},
+	})
+}
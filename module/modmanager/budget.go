@@ -0,0 +1,230 @@
+package modmanager
+
+import (
+	"context"
+	"time"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/metrics"
+)
+
+const (
+	// usageInterval is how often the usage of module processes is measured.
+	usageInterval = time.Second
+	// defaultBudgetGracePeriod is how long a module may exceed its budget before it is
+	// restarted, if its budget does not say.
+	defaultBudgetGracePeriod = 10 * time.Second
+	// maxThrottle is the largest part of the time a throttled module is paused for, so
+	// that it can still respond.
+	maxThrottle = 0.9
+)
+
+// A moduleProcess measures and controls the operating system process of a module.
+type moduleProcess interface {
+	// usage returns the total CPU time used by the process and its resident memory.
+	usage() (cpu time.Duration, rssBytes uint64, err error)
+	pause() error
+	resume() error
+	kill() error
+}
+
+// usageSample is the usage of a module process over one interval.
+type usageSample struct {
+	cpuPercent float64
+	memoryMB   float64
+}
+
+// budgetEnforcer measures the usage of a module process and enforces the module's budget.
+type budgetEnforcer struct {
+	name   string
+	budget config.ModuleBudget
+	proc   moduleProcess
+	logger logging.Logger
+	// metrics, if set, exposes the usage of the process.
+	metrics *metrics.Recorder
+
+	lastCPU time.Duration
+	lastAt  time.Time
+	// throttle is the fraction of the time the process is paused for.
+	throttle float64
+	// overSince is when the process started exceeding its budget, if it is.
+	overSince time.Time
+}
+
+func newBudgetEnforcer(
+	name string,
+	budget *config.ModuleBudget,
+	proc moduleProcess,
+	now time.Time,
+	recorder *metrics.Recorder,
+	logger logging.Logger,
+) (*budgetEnforcer, error) {
+	e := &budgetEnforcer{name: name, proc: proc, logger: logger, metrics: recorder, lastAt: now}
+	if budget != nil {
+		e.budget = *budget
+	}
+	if e.budget.Action == "" {
+		e.budget.Action = config.BudgetActionWarn
+	}
+	cpu, _, err := proc.usage()
+	if err != nil {
+		return nil, err
+	}
+	e.lastCPU = cpu
+	return e, nil
+}
+
+// sample measures the usage of the process since the last sample and applies the budget.
+// It returns how long the process should be paused for during the next interval.
+func (e *budgetEnforcer) sample(ctx context.Context, now time.Time, interval time.Duration) (time.Duration, error) {
+	cpu, rss, err := e.proc.usage()
+	if err != nil {
+		return 0, err
+	}
+	elapsed := now.Sub(e.lastAt)
+	if elapsed <= 0 {
+		return 0, nil
+	}
+	usage := usageSample{
+		cpuPercent: 100 * float64(cpu-e.lastCPU) / float64(elapsed),
+		memoryMB:   float64(rss) / (1 << 20),
+	}
+	e.lastCPU = cpu
+	e.lastAt = now
+
+	overCPU := e.budget.CPUPercent > 0 && usage.cpuPercent > e.budget.CPUPercent
+	overMemory := e.budget.MemoryMB > 0 && usage.memoryMB > e.budget.MemoryMB
+
+	if e.budget.Action == config.BudgetActionThrottle && e.budget.CPUPercent > 0 {
+		// usage was measured while running for only part of the interval, so scale the part
+		// the process runs for by how far it is from its budget.
+		running := 1 - e.throttle
+		if usage.cpuPercent > 0 {
+			running *= e.budget.CPUPercent / usage.cpuPercent
+		} else {
+			running = 1
+		}
+		e.throttle = min(max(1-running, 0), maxThrottle)
+		// throttling keeps the CPU usage within budget as long as it is not at its limit.
+		overCPU = overCPU && e.throttle == maxThrottle
+	}
+
+	if e.metrics != nil {
+		e.metrics.RecordModuleUsage(e.name, metrics.ModuleUsage{
+			CPUPercent: usage.cpuPercent,
+			MemoryMB:   usage.memoryMB,
+			Throttle:   e.throttle,
+		})
+	}
+
+	if !overCPU && !overMemory {
+		if !e.overSince.IsZero() {
+			e.logger.CInfow(ctx, "Module is within its budget again", "module", e.name)
+		}
+		e.overSince = time.Time{}
+		return time.Duration(e.throttle * float64(interval)), nil
+	}
+
+	if e.overSince.IsZero() {
+		e.overSince = now
+		e.logger.CWarnw(ctx, "Module exceeded its budget",
+			"module", e.name,
+			"cpu_percent", usage.cpuPercent, "cpu_percent_budget", e.budget.CPUPercent,
+			"memory_mb", usage.memoryMB, "memory_mb_budget", e.budget.MemoryMB,
+			"action", e.budget.Action)
+	}
+	gracePeriod := defaultBudgetGracePeriod
+	if e.budget.GracePeriodSec > 0 {
+		gracePeriod = time.Duration(e.budget.GracePeriodSec * float64(time.Second))
+	}
+	if e.budget.Action == config.BudgetActionRestart && now.Sub(e.overSince) >= gracePeriod {
+		e.logger.CErrorw(ctx, "Module exceeded its budget for too long; restarting it",
+			"module", e.name, "grace_period", gracePeriod)
+		e.overSince = time.Time{}
+		// the module is restarted like after any unexpected exit.
+		return 0, e.proc.kill()
+	}
+	return time.Duration(e.throttle * float64(interval)), nil
+}
+
+// usageMonitor runs a budgetEnforcer for a module process until stopped.
+type usageMonitor struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// startUsageMonitor starts measuring the usage of the module's process and enforcing its
+// budget. Usage is not measured on platforms that do not support it.
+func (m *module) startUsageMonitor(logger logging.Logger) {
+	m.stopUsageMonitor()
+
+	name := m.cfg.Name
+	proc, err := newModuleProcess(m.addr)
+	if err != nil {
+		if m.cfg.Budget != nil {
+			logger.Warnw("Cannot measure module usage; its budget is not enforced", "module", m.cfg.Name, "error", err)
+		}
+		return
+	}
+	enforcer, err := newBudgetEnforcer(m.cfg.Name, m.cfg.Budget, proc, time.Now(), m.metrics, logger)
+	if err != nil {
+		logger.Debugw("Cannot measure module usage", "module", m.cfg.Name, "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	monitor := &usageMonitor{cancel: cancel, done: make(chan struct{})}
+	m.usageMonitorMu.Lock()
+	m.usageMonitor = monitor
+	m.usageMonitorMu.Unlock()
+
+	utils.PanicCapturingGo(func() {
+		defer close(monitor.done)
+		ticker := time.NewTicker(usageInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				pause, err := enforcer.sample(ctx, now, usageInterval)
+				if err != nil {
+					// the process exited, and is restarted with a new monitor if it should be.
+					logger.Debugw("Stopped measuring module usage", "module", name, "error", err)
+					return
+				}
+				if pause <= 0 {
+					continue
+				}
+				if err := proc.pause(); err != nil {
+					logger.Debugw("Failed to throttle module", "module", name, "error", err)
+					continue
+				}
+				utils.SelectContextOrWait(ctx, pause)
+				if err := proc.resume(); err != nil {
+					logger.Warnw("Failed to resume throttled module", "module", name, "error", err)
+				}
+			}
+		}
+	})
+}
+
+// stopUsageMonitor stops the usage monitor of the module, if any, and stops exposing the
+// usage it measured. The module's process is never left paused.
+func (m *module) stopUsageMonitor() {
+	m.usageMonitorMu.Lock()
+	monitor := m.usageMonitor
+	m.usageMonitor = nil
+	m.usageMonitorMu.Unlock()
+	if monitor == nil {
+		return
+	}
+	monitor.cancel()
+	<-monitor.done
+	if m.metrics != nil {
+		m.metrics.ForgetModule(m.cfg.Name)
+	}
+}
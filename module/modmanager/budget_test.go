@@ -0,0 +1,164 @@
+package modmanager
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/metrics"
+	rtestutils "go.viam.com/rdk/testutils"
+)
+
+type fakeModuleProcess struct {
+	cpu    time.Duration
+	rss    uint64
+	killed bool
+}
+
+func (p *fakeModuleProcess) usage() (time.Duration, uint64, error) {
+	return p.cpu, p.rss, nil
+}
+
+func (p *fakeModuleProcess) pause() error  { return nil }
+func (p *fakeModuleProcess) resume() error { return nil }
+
+func (p *fakeModuleProcess) kill() error {
+	p.killed = true
+	return nil
+}
+
+func TestBudgetEnforcer(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	start := time.Now()
+
+	// run runs the process at the given CPU percentage for the part of the interval it is not
+	// paused for, and returns how long it is paused for during the next interval.
+	run := func(t *testing.T, e *budgetEnforcer, p *fakeModuleProcess, now *time.Time, cpuPercent float64, paused time.Duration) time.Duration {
+		t.Helper()
+		p.cpu += time.Duration(cpuPercent / 100 * float64(time.Second-paused))
+		*now = now.Add(time.Second)
+		pause, err := e.sample(ctx, *now, time.Second)
+		test.That(t, err, test.ShouldBeNil)
+		return pause
+	}
+
+	t.Run("without budget", func(t *testing.T) {
+		p := &fakeModuleProcess{rss: 1 << 30}
+		now := start
+		e, err := newBudgetEnforcer("mod", nil, p, now, nil, logger)
+		test.That(t, err, test.ShouldBeNil)
+		for i := 0; i < 20; i++ {
+			test.That(t, run(t, e, p, &now, 400, 0), test.ShouldEqual, 0)
+		}
+		test.That(t, p.killed, test.ShouldBeFalse)
+	})
+
+	t.Run("throttle", func(t *testing.T) {
+		p := &fakeModuleProcess{}
+		now := start
+		recorder := metrics.NewRecorder()
+		e, err := newBudgetEnforcer("mod", &config.ModuleBudget{CPUPercent: 50, Action: config.BudgetActionThrottle}, p, now, recorder, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		// a process that would use a whole core is paused for half the time.
+		pause := run(t, e, p, &now, 100, 0)
+		test.That(t, pause, test.ShouldEqual, 500*time.Millisecond)
+		usage, ok := recorder.ModuleUsage("mod")
+		test.That(t, ok, test.ShouldBeTrue)
+		test.That(t, usage.CPUPercent, test.ShouldAlmostEqual, 100)
+		test.That(t, usage.Throttle, test.ShouldAlmostEqual, 0.5)
+		pause = run(t, e, p, &now, 100, pause)
+		test.That(t, pause, test.ShouldEqual, 500*time.Millisecond)
+
+		// and no longer once it is idle.
+		pause = run(t, e, p, &now, 0, pause)
+		test.That(t, pause, test.ShouldEqual, 0)
+
+		// a process using more cores can only be throttled so far, and is never restarted.
+		for i := 0; i < 20; i++ {
+			pause = run(t, e, p, &now, 800, pause)
+		}
+		test.That(t, pause, test.ShouldEqual, time.Duration(maxThrottle*float64(time.Second)))
+		test.That(t, p.killed, test.ShouldBeFalse)
+	})
+
+	t.Run("restart", func(t *testing.T) {
+		p := &fakeModuleProcess{}
+		now := start
+		e, err := newBudgetEnforcer("mod", &config.ModuleBudget{
+			MemoryMB: 100, Action: config.BudgetActionRestart, GracePeriodSec: 5,
+		}, p, now, nil, logger)
+		test.That(t, err, test.ShouldBeNil)
+
+		p.rss = 200 << 20
+		for i := 0; i < 4; i++ {
+			run(t, e, p, &now, 0, 0)
+		}
+		// going back within budget resets the grace period.
+		p.rss = 50 << 20
+		run(t, e, p, &now, 0, 0)
+		p.rss = 200 << 20
+		for i := 0; i < 6; i++ {
+			test.That(t, p.killed, test.ShouldBeFalse)
+			run(t, e, p, &now, 0, 0)
+		}
+		test.That(t, p.killed, test.ShouldBeTrue)
+	})
+}
+
+func TestModuleUsage(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("module usage is only measured on linux")
+	}
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	modPath := rtestutils.BuildTempModule(t, "examples/customresources/demos/simplemodule")
+	parentAddr := setupSocketWithRobot(t)
+	viamHomeTemp := t.TempDir()
+
+	mod := &module{
+		cfg: config.Module{
+			Name:     "test",
+			ExePath:  modPath,
+			Type:     config.ModuleTypeRegistry,
+			ModuleID: "new:york",
+		},
+		logger:  logger,
+		metrics: metrics.NewRecorder(),
+	}
+	err := mod.startProcess(ctx, parentAddr, nil, logger, viamHomeTemp, filepath.Join(viamHomeTemp, "packages"))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, mod.stopProcess(), test.ShouldBeNil)
+		test.That(t, mod.usageMonitor, test.ShouldBeNil)
+		_, ok := mod.metrics.ModuleUsage("test")
+		test.That(t, ok, test.ShouldBeFalse)
+	}()
+	test.That(t, mod.usageMonitor, test.ShouldNotBeNil)
+
+	// the usage of the process is exposed once it is measured.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		usage, ok := mod.metrics.ModuleUsage("test")
+		test.That(tb, ok, test.ShouldBeTrue)
+		test.That(tb, usage.MemoryMB, test.ShouldBeGreaterThan, 0)
+	})
+
+	proc, err := newModuleProcess(mod.addr)
+	test.That(t, err, test.ShouldBeNil)
+	cpu, rss, err := proc.usage()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, cpu, test.ShouldBeGreaterThanOrEqualTo, 0)
+	test.That(t, rss, test.ShouldBeGreaterThan, 0)
+
+	_, err = newModuleProcess(mod.addr + "-other")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -34,6 +34,7 @@ import (
 	"go.viam.com/rdk/module/modmaninterface"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/metrics"
 	"go.viam.com/rdk/robot/packages"
 	rutils "go.viam.com/rdk/utils"
 )
@@ -65,6 +66,7 @@ func NewManager(
 		restartCtx:              restartCtx,
 		restartCtxCancel:        restartCtxCancel,
 		packagesDir:             options.PackagesDir,
+		metrics:                 options.Metrics,
 	}
 }
 
@@ -94,6 +96,13 @@ type module struct {
 	inStartup      atomic.Bool
 	inRecoveryLock sync.Mutex
 	logger         logging.Logger
+	// metrics, if set, exposes the usage of the module's process.
+	metrics *metrics.Recorder
+
+	// usageMonitor measures the usage of the module's process and enforces its budget;
+	// usageMonitorMu guards it.
+	usageMonitorMu sync.Mutex
+	usageMonitor   *usageMonitor
 }
 
 type addedResource struct {
@@ -166,6 +175,8 @@ type Manager struct {
 	removeOrphanedResources func(ctx context.Context, rNames []resource.Name)
 	restartCtx              context.Context
 	restartCtxCancel        context.CancelFunc
+	// metrics, if set, exposes the usage of the processes of modules.
+	metrics *metrics.Recorder
 }
 
 // Close terminates module connections and processes.
@@ -279,6 +290,7 @@ func (mgr *Manager) add(ctx context.Context, conf config.Module) error {
 		dataDir:   moduleDataDir,
 		resources: map[resource.Name]*addedResource{},
 		logger:    mgr.logger.Sublogger(conf.Name),
+		metrics:   mgr.metrics,
 	}
 
 	if err := mgr.startModule(ctx, mod); err != nil {
@@ -1038,6 +1050,9 @@ func (m *module) startProcess(
 	viamHomeDir string,
 	packagesDir string,
 ) error {
+	// the monitor of a previous process must not pause or kill the new one.
+	m.stopUsageMonitor()
+
 	var err error
 	// append a random alpha string to the module name while creating a socket address to avoid conflicts
 	// with old versions of the module.
@@ -1110,6 +1125,7 @@ func (m *module) startProcess(
 		}
 		break
 	}
+	m.startUsageMonitor(logger)
 	return nil
 }
 
@@ -1117,6 +1133,7 @@ func (m *module) stopProcess() error {
 	if m.process == nil {
 		return nil
 	}
+	m.stopUsageMonitor()
 	// Attempt to remove module's .sock file if module did not remove it
 	// already.
 	defer rutils.RemoveFileNoError(m.addr)
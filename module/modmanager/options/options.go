@@ -5,6 +5,7 @@ import (
 	"context"
 
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/metrics"
 )
 
 // Options configures a modManager.
@@ -19,4 +20,6 @@ type Options struct {
 	RemoveOrphanedResources func(ctx context.Context, rNames []resource.Name)
 	// PackagesDir is from Config.PackagesPath. It's used for resolving local tarball module paths.
 	PackagesDir string
+	// Metrics, if set, exposes the CPU, memory and throttling of the processes of modules.
+	Metrics *metrics.Recorder
 }
@@ -0,0 +1,127 @@
+package modmanager
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// clockTicksPerSecond is the unit of CPU times in /proc, which is fixed on Linux.
+const clockTicksPerSecond = 100
+
+// processGroup is a module process started in its own process group, along with any
+// processes it started.
+type processGroup struct {
+	pgid int
+}
+
+// newModuleProcess finds the process of the module listening on addr among the children
+// of this process.
+func newModuleProcess(addr string) (moduleProcess, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := readProcStat(pid)
+		if err != nil || stat.ppid != os.Getpid() {
+			continue
+		}
+		cmdline, err := os.ReadFile(filepath.Join("/proc", entry.Name(), "cmdline"))
+		if err != nil {
+			continue
+		}
+		// the module is started with the address it listens on as its first argument.
+		args := bytes.Split(cmdline, []byte{0})
+		if len(args) > 1 && string(args[1]) == addr {
+			return &processGroup{pgid: pid}, nil
+		}
+	}
+	return nil, errors.Errorf("no module process listening on %q", addr)
+}
+
+func (p *processGroup) usage() (time.Duration, uint64, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, 0, err
+	}
+	var ticks, rssPages uint64
+	found := false
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		stat, err := readProcStat(pid)
+		if err != nil || stat.pgid != p.pgid {
+			continue
+		}
+		found = found || pid == p.pgid
+		ticks += stat.utime + stat.stime
+		rssPages += stat.rss
+	}
+	if !found {
+		return 0, 0, errors.Errorf("module process %d exited", p.pgid)
+	}
+	cpu := time.Duration(ticks) * time.Second / clockTicksPerSecond
+	return cpu, rssPages * uint64(os.Getpagesize()), nil
+}
+
+func (p *processGroup) pause() error {
+	return syscall.Kill(-p.pgid, syscall.SIGSTOP)
+}
+
+func (p *processGroup) resume() error {
+	return syscall.Kill(-p.pgid, syscall.SIGCONT)
+}
+
+func (p *processGroup) kill() error {
+	return syscall.Kill(-p.pgid, syscall.SIGKILL)
+}
+
+type procStat struct {
+	ppid, pgid   int
+	utime, stime uint64
+	rss          uint64
+}
+
+// readProcStat reads the fields of /proc/<pid>/stat needed to measure usage.
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return procStat{}, err
+	}
+	// the command name may contain spaces and parentheses, so fields are counted from
+	// the last parenthesis; the first field after it is the third in the file.
+	end := bytes.LastIndexByte(data, ')')
+	if end < 0 {
+		return procStat{}, errors.Errorf("malformed stat for process %d", pid)
+	}
+	fields := bytes.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return procStat{}, errors.Errorf("malformed stat for process %d", pid)
+	}
+	field := func(n int) uint64 {
+		v, err := strconv.ParseUint(string(fields[n-3]), 10, 64)
+		if err != nil {
+			return 0
+		}
+		return v
+	}
+	return procStat{
+		ppid:  int(field(4)),
+		pgid:  int(field(5)),
+		utime: field(14),
+		stime: field(15),
+		rss:   field(24),
+	}, nil
+}
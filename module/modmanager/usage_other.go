@@ -0,0 +1,10 @@
+//go:build !linux
+
+package modmanager
+
+import "github.com/pkg/errors"
+
+// newModuleProcess is only supported on Linux.
+func newModuleProcess(addr string) (moduleProcess, error) {
+	return nil, errors.New("measuring module usage is only supported on linux")
+}
@@ -471,30 +471,42 @@ func (m *Module) AddResource(ctx context.Context, req *pb.AddResourceRequest) (*
 	if err != nil {
 		return nil, err
 	}
+	if err := resource.PreStart(ctx, res); err != nil {
+		return nil, multierr.Combine(errors.Wrap(err, "resource pre-start error"), res.Close(ctx))
+	}
 
 	var passthroughSource rtppassthrough.Source
 	if p, ok := res.(rtppassthrough.Source); ok {
 		passthroughSource = p
 	}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	coll, ok := m.collections[conf.API]
-	if !ok {
-		return nil, errors.Errorf("module cannot service api: %s", conf.API)
-	}
+	if err := func() error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		coll, ok := m.collections[conf.API]
+		if !ok {
+			return errors.Errorf("module cannot service api: %s", conf.API)
+		}
 
-	// If adding the resource name to the collection fails, close the resource
-	// and return an error
-	if err := coll.Add(conf.ResourceName(), res); err != nil {
-		return nil, multierr.Combine(err, res.Close(ctx))
-	}
+		// If adding the resource name to the collection fails, close the resource
+		// and return an error
+		if err := coll.Add(conf.ResourceName(), res); err != nil {
+			return multierr.Combine(err, res.Close(ctx))
+		}
 
-	m.resLoggers[res] = resLogger
+		m.resLoggers[res] = resLogger
 
-	// add the video stream resources upon creation
-	if passthroughSource != nil {
-		m.streamSourceByName[res.Name()] = passthroughSource
+		// add the video stream resources upon creation
+		if passthroughSource != nil {
+			m.streamSourceByName[res.Name()] = passthroughSource
+		}
+		return nil
+	}(); err != nil {
+		return nil, err
+	}
+
+	if err := resource.PostStart(ctx, res); err != nil {
+		m.logger.Errorw("error running post-start hook of resource", "resource", res.Name(), "error", err)
 	}
 	return &pb.AddResourceResponse{}, nil
 }
@@ -556,6 +568,9 @@ func (m *Module) ReconfigureResource(ctx context.Context, req *pb.ReconfigureRes
 	}
 
 	m.logger.Debugw("rebuilding", "name", conf.ResourceName())
+	if err := resource.PreClose(ctx, res); err != nil {
+		m.logger.Errorw("error running pre-close hook of resource", "resource", res.Name(), "error", err)
+	}
 	if err := res.Close(ctx); err != nil {
 		m.logger.Error(err)
 	}
@@ -573,6 +588,9 @@ func (m *Module) ReconfigureResource(ctx context.Context, req *pb.ReconfigureRes
 	if err != nil {
 		return nil, err
 	}
+	if err := resource.PreStart(ctx, newRes); err != nil {
+		return nil, multierr.Combine(errors.Wrap(err, "resource pre-start error"), newRes.Close(ctx))
+	}
 	var passthroughSource rtppassthrough.Source
 	if p, ok := newRes.(rtppassthrough.Source); ok {
 		passthroughSource = p
@@ -581,7 +599,13 @@ func (m *Module) ReconfigureResource(ctx context.Context, req *pb.ReconfigureRes
 	if passthroughSource != nil {
 		m.streamSourceByName[res.Name()] = passthroughSource
 	}
-	return &pb.ReconfigureResourceResponse{}, coll.ReplaceOne(conf.ResourceName(), newRes)
+	if err := coll.ReplaceOne(conf.ResourceName(), newRes); err != nil {
+		return nil, err
+	}
+	if err := resource.PostStart(ctx, newRes); err != nil {
+		m.logger.Errorw("error running post-start hook of resource", "resource", newRes.Name(), "error", err)
+	}
+	return &pb.ReconfigureResourceResponse{}, nil
 }
 
 // ValidateConfig receives the validation request for a resource from the parent.
@@ -635,6 +659,9 @@ func (m *Module) RemoveResource(ctx context.Context, req *pb.RemoveResourceReque
 		return nil, err
 	}
 
+	if err := resource.PreClose(ctx, res); err != nil {
+		m.logger.Errorw("error running pre-close hook of resource", "resource", res.Name(), "error", err)
+	}
 	if err := res.Close(ctx); err != nil {
 		m.logger.Error(err)
 	}
@@ -502,6 +502,25 @@ func NewOctreeCollisionConstraint(octree *pointcloud.BasicOctree, threshold int,
 	return constraint
 }
 
+// NewJointLimitConstraint returns a constraint which is violated by configurations outside of the given limits, one per degree of
+// freedom of the frame being moved, such as limits tighter than the hardware limits of an arm.
+func NewJointLimitConstraint(limits []referenceframe.Limit) StateConstraint {
+	return func(state *ik.State) bool {
+		if state.Configuration == nil {
+			return true
+		}
+		if len(state.Configuration) != len(limits) {
+			return false
+		}
+		for i, input := range state.Configuration {
+			if input.Value < limits[i].Min || input.Value > limits[i].Max {
+				return false
+			}
+		}
+		return true
+	}
+}
+
 // NewBoundingRegionConstraint will determine if the given list of robot geometries are in collision with the
 // given list of bounding regions.
 func NewBoundingRegionConstraint(robotGeoms, boundingRegions []spatial.Geometry, collisionBufferMM float64) StateConstraint {
@@ -187,6 +187,17 @@ func TestLineFollow(t *testing.T) {
 	test.That(t, failName, test.ShouldEqual, "whiteboard")
 }
 
+func TestJointLimitConstraint(t *testing.T) {
+	constraint := NewJointLimitConstraint([]frame.Limit{{Min: 0, Max: 1}, {Min: -math.Pi, Max: math.Pi}})
+	test.That(t, constraint(&ik.State{Configuration: frame.FloatsToInputs([]float64{0.5, 3})}), test.ShouldBeTrue)
+	test.That(t, constraint(&ik.State{Configuration: frame.FloatsToInputs([]float64{1, -math.Pi})}), test.ShouldBeTrue)
+	test.That(t, constraint(&ik.State{Configuration: frame.FloatsToInputs([]float64{1.1, 0})}), test.ShouldBeFalse)
+	test.That(t, constraint(&ik.State{Configuration: frame.FloatsToInputs([]float64{0, -4})}), test.ShouldBeFalse)
+	test.That(t, constraint(&ik.State{Configuration: frame.FloatsToInputs([]float64{0})}), test.ShouldBeFalse)
+	// states given only by position cannot be checked.
+	test.That(t, constraint(&ik.State{Position: spatial.NewZeroPose()}), test.ShouldBeTrue)
+}
+
 func TestCollisionConstraints(t *testing.T) {
 	zeroPos := frame.FloatsToInputs([]float64{0, 0, 0, 0, 0, 0})
 	cases := []struct {
@@ -0,0 +1,67 @@
+//go:build !windows && !no_cgo
+
+package ik
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+const (
+	// NloptSolver is the name of the gradient-descent solver, which solves any frame.
+	NloptSolver = "nlopt"
+	// AnalyticSolver is the name of the closed-form solvers of the kinematic models that have
+	// one registered.
+	AnalyticSolver = "analytic"
+)
+
+// An AnalyticSolverConstructor returns a closed-form solver for a frame of a kinematic model,
+// which should send every configuration reaching the goal, such as elbow up and elbow down.
+type AnalyticSolverConstructor func(model referenceframe.Frame, logger logging.Logger) (InverseKinematics, error)
+
+var (
+	analyticSolversMu sync.RWMutex
+	analyticSolvers   = map[string]AnalyticSolverConstructor{}
+)
+
+// RegisterAnalyticSolver registers the closed-form solver of the kinematic model of the given
+// name, as given in its kinematics file.
+func RegisterAnalyticSolver(modelName string, constructor AnalyticSolverConstructor) {
+	analyticSolversMu.Lock()
+	defer analyticSolversMu.Unlock()
+	analyticSolvers[modelName] = constructor
+}
+
+// modelName returns the name of the kinematic model of the frame, as given in its kinematics
+// file, or the name of the frame if it was not built from one.
+func modelName(model referenceframe.Frame) string {
+	if m, ok := model.(referenceframe.Model); ok {
+		if cfg := m.ModelConfig(); cfg != nil && cfg.Name != "" {
+			return cfg.Name
+		}
+	}
+	return model.Name()
+}
+
+// CreateSolver returns the solver of the given name for the frame, defaulting to NloptSolver.
+// nCPU is how many nlopt solvers run in parallel.
+func CreateSolver(name string, model referenceframe.Frame, logger logging.Logger, nCPU int) (InverseKinematics, error) {
+	switch name {
+	case "", NloptSolver:
+		return CreateCombinedIKSolver(model, logger, nCPU, defaultGoalThreshold)
+	case AnalyticSolver:
+		analyticSolversMu.RLock()
+		constructor, ok := analyticSolvers[modelName(model)]
+		analyticSolversMu.RUnlock()
+		if !ok {
+			return nil, errors.Errorf("no analytic solver for kinematic model %q; use the %s solver", modelName(model), NloptSolver)
+		}
+		return constructor(model, logger)
+	default:
+		return nil, errors.Errorf("unknown IK solver %q, expected %s or %s", name, NloptSolver, AnalyticSolver)
+	}
+}
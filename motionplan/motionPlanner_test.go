@@ -551,6 +551,55 @@ func TestArmAndGantrySolve(t *testing.T) {
 	test.That(t, spatialmath.PoseAlmostCoincidentEps(solvedPose.(*frame.PoseInFrame).Pose(), goal1, 0.01), test.ShouldBeTrue)
 }
 
+func TestJointLimitsOption(t *testing.T) {
+	fs := makeTestFS(t)
+	positions := frame.StartPositions(fs)
+	goal := spatialmath.NewPose(r3.Vector{X: 257, Y: 210, Z: -300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+
+	// joint limits are given as they arrive in the extra parameters of the motion service.
+	for _, tc := range []struct {
+		limits interface{}
+		errStr string
+	}{
+		{map[string]interface{}{"urCamera": []interface{}{}}, "not a frame moved by the plan"},
+		{map[string]interface{}{"UR5e": []interface{}{map[string]interface{}{"min": 0., "max": 1.}}}, "has 6 degrees of freedom"},
+		{map[string]interface{}{"UR5e": "narrow"}, "could not interpret joint_limits"},
+		{
+			map[string]interface{}{"UR5e": []interface{}{
+				map[string]interface{}{"min": 1., "max": -1.}, map[string]interface{}{}, map[string]interface{}{},
+				map[string]interface{}{}, map[string]interface{}{}, map[string]interface{}{},
+			}},
+			"min greater than its max",
+		},
+	} {
+		_, err := PlanMotion(context.Background(), &PlanRequest{
+			Logger:             logger,
+			Goal:               frame.NewPoseInFrame(frame.World, goal),
+			Frame:              fs.Frame("urCamera"),
+			StartConfiguration: positions,
+			FrameSystem:        fs,
+			Options:            map[string]interface{}{"joint_limits": tc.limits},
+		})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+	}
+}
+
+func TestUnknownPlanningAlg(t *testing.T) {
+	fs := makeTestFS(t)
+	goal := spatialmath.NewPose(r3.Vector{X: 257, Y: 210, Z: -300}, &spatialmath.OrientationVectorDegrees{OZ: -1})
+	_, err := PlanMotion(context.Background(), &PlanRequest{
+		Logger:             logger,
+		Goal:               frame.NewPoseInFrame(frame.World, goal),
+		Frame:              fs.Frame("urCamera"),
+		StartConfiguration: frame.StartPositions(fs),
+		FrameSystem:        fs,
+		Options:            map[string]interface{}{"planning_alg": "bitstar"},
+	})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `unknown planning_alg "bitstar"`)
+}
+
 func TestMultiArmSolve(t *testing.T) {
 	fs := makeTestFS(t)
 	positions := frame.StartPositions(fs)
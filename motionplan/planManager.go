@@ -560,6 +560,14 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 		opt.AddStateConstraint(name, constraint)
 	}
 
+	if rawLimits, ok := planningOpts["joint_limits"]; ok {
+		limits, err := pm.frame.jointLimits(rawLimits)
+		if err != nil {
+			return nil, err
+		}
+		opt.AddStateConstraint(defaultJointLimitConstraintDesc, NewJointLimitConstraint(limits))
+	}
+
 	hasTopoConstraint := opt.addPbTopoConstraints(from, to, constraints)
 	if hasTopoConstraint {
 		planAlg = "cbirrt"
@@ -603,8 +611,10 @@ func (pm *planManager) plannerSetupFromMoveRequest(
 			opt.PlannerConstructor = newRRTStarConnectMotionPlanner
 			// TODO(pl): more logic for RRT*?
 			return opt, nil
-		default:
+		case "":
 			// use default, already set
+		default:
+			return nil, fmt.Errorf("unknown planning_alg %q, expected cbirrt or rrtstar", planAlg)
 		}
 	}
 	if pm.useTPspace {
@@ -61,6 +61,7 @@ const (
 	defaultObstacleConstraintDesc       = "Collision between the robot and an obstacle"
 	defaultSelfCollisionConstraintDesc  = "Collision between two robot components that are moving"
 	defaultRobotCollisionConstraintDesc = "Collision between a robot component that is moving and one that is stationary"
+	defaultJointLimitConstraintDesc     = "Constraint to keep joints within the requested limits"
 
 	// When breaking down a path into smaller waypoints, add a waypoint every this many mm of movement.
 	defaultPathStepSize = 10
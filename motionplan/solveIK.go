@@ -0,0 +1,152 @@
+//go:build !no_cgo
+
+package motionplan
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// default number of distinct IK solutions returned.
+	defaultIKSolutions = 8
+
+	// default time to look for IK solutions for before returning those found.
+	defaultIKTimeout = 5 * time.Second
+
+	// IK solutions closer than this to each other, in the L2 norm of their inputs, are the same
+	// configuration.
+	minIKSolutionDistance = 0.01
+)
+
+// An IKRequest is a request for the configurations of a frame that put its end at a goal pose.
+type IKRequest struct {
+	Logger logging.Logger
+	// Frame is the frame solved for, such as the model frame of an arm.
+	Frame referenceframe.Frame
+	// Goal is the pose of the end of the frame to reach, in the parent frame of the frame.
+	Goal spatialmath.Pose
+	// Seed is the configuration to solve from, such as the current one.
+	Seed []referenceframe.Input
+	// Solver is the name of the solver to use, ik.NloptSolver if empty.
+	Solver string
+	// MaxSolutions is how many distinct solutions to return at most. Defaults to 8.
+	MaxSolutions int
+	// Timeout bounds how long solutions are looked for. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// SolveIK returns the distinct configurations of the frame found reaching the goal, sorted by
+// their distance from the seed, nearest first, so that callers can choose between
+// configurations, such as elbow up and elbow down, before moving the frame.
+func SolveIK(ctx context.Context, req *IKRequest) ([]*ik.Solution, error) {
+	if req.Frame == nil {
+		return nil, errors.New("frame is required")
+	}
+	if len(req.Frame.DoF()) == 0 {
+		return nil, errors.Errorf("frame %q has no inputs to solve for", req.Frame.Name())
+	}
+	if req.Goal == nil {
+		return nil, errors.New("goal is required")
+	}
+	seed := req.Seed
+	if seed == nil {
+		seed = make([]referenceframe.Input, len(req.Frame.DoF()))
+	}
+	if len(seed) != len(req.Frame.DoF()) {
+		return nil, referenceframe.NewIncorrectInputLengthError(len(seed), len(req.Frame.DoF()))
+	}
+	maxSolutions := req.MaxSolutions
+	if maxSolutions <= 0 {
+		maxSolutions = defaultIKSolutions
+	}
+	timeout := req.Timeout
+	if timeout <= 0 {
+		timeout = defaultIKTimeout
+	}
+
+	solver, err := ik.CreateSolver(req.Solver, req.Frame, req.Logger, defaultNumThreads)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxWithCancel, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	solutionGen := make(chan *ik.Solution, defaultNumThreads*2)
+	ikErr := make(chan error, 1)
+	var activeSolvers sync.WaitGroup
+	defer activeSolvers.Wait()
+	activeSolvers.Add(1)
+	utils.PanicCapturingGo(func() {
+		defer close(ikErr)
+		defer activeSolvers.Done()
+		ikErr <- solver.Solve(ctxWithCancel, solutionGen, seed, ik.NewSquaredNormMetric(req.Goal), 1)
+	})
+
+	var solutions []*ik.Solution
+	add := func(solution *ik.Solution) {
+		if !solution.Exact || len(solutions) >= maxSolutions {
+			return
+		}
+		for _, found := range solutions {
+			if referenceframe.InputsL2Distance(found.Configuration, solution.Configuration) < minIKSolutionDistance {
+				return
+			}
+		}
+		solutions = append(solutions, solution)
+	}
+	var solveErr error
+IK:
+	for len(solutions) < maxSolutions {
+		select {
+		case solution := <-solutionGen:
+			add(solution)
+		case solveErr = <-ikErr:
+			// the solver is done; read the solutions it sent before returning.
+			for {
+				select {
+				case solution := <-solutionGen:
+					add(solution)
+				default:
+					break IK
+				}
+			}
+		case <-ctxWithCancel.Done():
+			break IK
+		}
+	}
+
+	// stop the solver, reading what it is sending so that it is not blocked.
+	cancel()
+	go func() {
+		for range solutionGen {
+		}
+	}()
+	activeSolvers.Wait()
+	close(solutionGen)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(solutions) == 0 {
+		if solveErr != nil {
+			return nil, errors.Wrap(solveErr, "no IK solutions found")
+		}
+		return nil, errors.New("no IK solutions found")
+	}
+	sort.SliceStable(solutions, func(i, j int) bool {
+		return referenceframe.InputsL2Distance(seed, solutions[i].Configuration) <
+			referenceframe.InputsL2Distance(seed, solutions[j].Configuration)
+	})
+	return solutions, nil
+}
@@ -1,7 +1,9 @@
 package motionplan
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 
 	"go.uber.org/multierr"
 	pb "go.viam.com/api/component/arm/v1"
@@ -321,6 +323,46 @@ func (sf *solverFrame) mapToSlice(inputMap map[string][]frame.Input) ([]frame.In
 	return inputs, nil
 }
 
+// jointLimits returns the limits of each degree of freedom of the solver frame, replacing those of frames with the given limits,
+// which map the names of frames to one limit per degree of freedom, as in the "joint_limits" planning option.
+func (sf *solverFrame) jointLimits(rawLimits interface{}) ([]frame.Limit, error) {
+	data, err := json.Marshal(rawLimits)
+	if err != nil {
+		return nil, err
+	}
+	var limitsByFrame map[string][]frame.Limit
+	if err := json.Unmarshal(data, &limitsByFrame); err != nil {
+		return nil, fmt.Errorf("could not interpret joint_limits field as limits by frame name: %w", err)
+	}
+	for name, limits := range limitsByFrame {
+		f := sf.movingFS.Frame(name)
+		if f == nil || len(f.DoF()) == 0 {
+			return nil, fmt.Errorf("joint limits given for %q, which is not a frame moved by the plan", name)
+		}
+		if len(limits) != len(f.DoF()) {
+			return nil, fmt.Errorf("%d joint limits given for %q, which has %d degrees of freedom", len(limits), name, len(f.DoF()))
+		}
+		for i, limit := range limits {
+			if limit.Min > limit.Max {
+				return nil, fmt.Errorf("joint limit %d of %q has a min greater than its max", i, name)
+			}
+		}
+	}
+
+	var limits []frame.Limit
+	for _, f := range sf.frames {
+		if len(f.DoF()) == 0 {
+			continue
+		}
+		if frameLimits, ok := limitsByFrame[f.Name()]; ok {
+			limits = append(limits, frameLimits...)
+		} else {
+			limits = append(limits, f.DoF()...)
+		}
+	}
+	return limits, nil
+}
+
 func (sf *solverFrame) sliceToMap(inputSlice []frame.Input) map[string][]frame.Input {
 	inputs := map[string][]frame.Input{}
 	for k, v := range sf.origSeed {
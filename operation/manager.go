@@ -8,6 +8,8 @@ import (
 
 	"go.uber.org/multierr"
 	"go.viam.com/utils"
+
+	"go.viam.com/rdk/clock"
 )
 
 type anOp struct {
@@ -111,7 +113,7 @@ func (sm *SingleOperationManager) NewTimedWaitOp(ctx context.Context, dur time.D
 	ctx, finish := sm.New(ctx)
 	defer finish()
 
-	return utils.SelectContextOrWait(ctx, dur)
+	return clock.SleepContext(ctx, dur)
 }
 
 // IsPoweredInterface is a utility so can wait on IsPowered easily. It returns whether it is
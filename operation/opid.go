@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.viam.com/utils/rpc"
 
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/session"
@@ -21,19 +22,45 @@ var methodPrefixesToFilter = [...]string{
 	"/proto.rpc.webrtc.v1.SignalingService",
 	"/viam.robot.v1.RobotService/StreamStatus",
 	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo",
+	// calls served in chunks are operations of the methods they invoke.
+	"/viam.rdk.chunking.v1.ChunkingService",
 }
 
 // Operation is an operation happening on the server.
+// Resource is the name of the resource a request is for, if it names one.
+// Caller is who made the request the operation serves, if it came over a connection.
 type Operation struct {
 	ID        uuid.UUID
 	SessionID uuid.UUID
 	Method    string
 	Arguments interface{}
 	Started   time.Time
+	Resource  string
+	Caller    Caller
 
 	myManager *Manager
 	cancel    context.CancelFunc
 	labels    []string
+	// err is why the operation failed, if it did, guarded by the lock of its manager.
+	err error
+}
+
+// A Caller is who made a request.
+type Caller struct {
+	// Entity is the entity the caller authenticated as, if any.
+	Entity string
+	// Address is the remote address of the caller's connection, if known.
+	Address string
+}
+
+// callerFromContext returns the caller of the request served with the context.
+func callerFromContext(ctx context.Context) Caller {
+	var caller Caller
+	if entity, ok := rpc.ContextAuthEntity(ctx); ok {
+		caller.Entity = entity.Entity
+	}
+	caller.Address = rpc.PeerConnectionInfoFromContext(ctx).RemoteAddress
+	return caller
 }
 
 // Cancel cancel the context associated with an operation.
@@ -66,10 +93,29 @@ func (o *Operation) CancelOtherWithLabel(label string) {
 	o.labels = append(o.labels, label)
 }
 
+// Fail records that the operation failed with the error, such as a motion aborted for deviating
+// from its path, so that the failure is listed with it while it runs and among the recent
+// failures of its manager once it ends.
+func (o *Operation) Fail(err error) {
+	o.myManager.lock.Lock()
+	defer o.myManager.lock.Unlock()
+	o.err = err
+}
+
+// Err returns why the operation failed, or nil if it has not.
+func (o *Operation) Err() error {
+	o.myManager.lock.Lock()
+	defer o.myManager.lock.Unlock()
+	return o.err
+}
+
 func (o *Operation) cleanup() {
 	o.myManager.remove(o.ID)
 }
 
+// maxFailures is how many of the operations that failed most recently a manager keeps.
+const maxFailures = 100
+
 // NewManager creates a new manager for holding Operations.
 func NewManager(logger logging.Logger) *Manager {
 	return &Manager{ops: map[string]*Operation{}, logger: logger}
@@ -80,12 +126,35 @@ type Manager struct {
 	ops    map[string]*Operation
 	lock   sync.Mutex
 	logger logging.Logger
+	// failures are the operations that ended having failed, oldest first.
+	failures []Info
 }
 
 func (m *Manager) remove(id uuid.UUID) {
 	m.lock.Lock()
 	defer m.lock.Unlock()
+	op, ok := m.ops[id.String()]
+	if !ok {
+		return
+	}
 	delete(m.ops, id.String())
+	if op.err != nil {
+		info := infoOf(op, op.err)
+		ended := time.Now()
+		info.Ended = &ended
+		if len(m.failures) == maxFailures {
+			m.failures = m.failures[1:]
+		}
+		m.failures = append(m.failures, info)
+	}
+}
+
+// Failures returns the operations that ended having failed, most recent last, up to the last
+// hundred of them.
+func (m *Manager) Failures() []Info {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return append([]Info(nil), m.failures...)
 }
 
 func (m *Manager) add(op *Operation) {
@@ -125,6 +194,18 @@ func (m *Manager) Create(ctx context.Context, method string, args interface{}) (
 }
 
 func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string, args interface{}) (context.Context, func()) {
+	return m.create(ctx, id, method, args, "")
+}
+
+// create puts an operation of the given ID on this context, for a request for the named
+// resource, if any.
+func (m *Manager) create(
+	ctx context.Context,
+	id uuid.UUID,
+	method string,
+	args interface{},
+	resourceName string,
+) (context.Context, func()) {
 	if ctx.Value(opidKey) != nil {
 		panic("operations cannot be nested")
 	}
@@ -160,6 +241,8 @@ func (m *Manager) createWithID(ctx context.Context, id uuid.UUID, method string,
 		Method:    method,
 		Arguments: args,
 		Started:   time.Now(),
+		Resource:  resourceName,
+		Caller:    callerFromContext(ctx),
 		myManager: m,
 	}
 	if sess, ok := session.FromContext(ctx); ok {
@@ -181,6 +264,14 @@ func Get(ctx context.Context) *Operation {
 	return o.(*Operation)
 }
 
+// Fail records that the current Operation failed with the error.
+// if no Operation is set, will do nothing.
+func Fail(ctx context.Context, err error) {
+	if o := Get(ctx); o != nil {
+		o.Fail(err)
+	}
+}
+
 // CancelOtherWithLabel will cancel all operations besides this one with this label.
 // if no Operation is set, will do nothing.
 func CancelOtherWithLabel(ctx context.Context, label string) {
@@ -0,0 +1,138 @@
+package operation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a manager, for finding and canceling
+// stuck operations remotely. Its requests and responses are google.protobuf.Struct messages
+// holding the JSON form of the types below.
+const ServiceName = "viam.rdk.operation.v1.OperationService"
+
+// Info describes an operation running on a robot.
+type Info struct {
+	ID        string    `json:"id"`
+	Method    string    `json:"method"`
+	Resource  string    `json:"resource,omitempty"`
+	Entity    string    `json:"entity,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+	Started   time.Time `json:"started"`
+	// Error is why the operation failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Ended is when the operation ended, if it did.
+	Ended *time.Time `json:"ended,omitempty"`
+}
+
+type request struct {
+	ID string `json:"id,omitempty"`
+}
+
+type listResponse struct {
+	Operations []Info `json:"operations"`
+}
+
+// infoOf describes the operation, which failed with err if it is not nil.
+func infoOf(op *Operation, err error) Info {
+	info := Info{
+		ID:       op.ID.String(),
+		Method:   op.Method,
+		Resource: op.Resource,
+		Entity:   op.Caller.Entity,
+		Address:  op.Caller.Address,
+		Started:  op.Started,
+	}
+	if op.SessionID != uuid.Nil {
+		info.SessionID = op.SessionID.String()
+	}
+	if err != nil {
+		info.Error = err.Error()
+	}
+	return info
+}
+
+// A Server serves a manager with ServiceDesc.
+type Server struct {
+	manager *Manager
+}
+
+// NewServer returns a server for the given manager.
+func NewServer(manager *Manager) *Server {
+	return &Server{manager: manager}
+}
+
+func (s *Server) listOperations(ctx context.Context, _ request) (interface{}, error) {
+	me := Get(ctx)
+	resp := listResponse{Operations: []Info{}}
+	for _, op := range s.manager.All() {
+		if op == me {
+			continue
+		}
+		resp.Operations = append(resp.Operations, infoOf(op, op.Err()))
+	}
+	return resp, nil
+}
+
+func (s *Server) listFailedOperations(ctx context.Context, _ request) (interface{}, error) {
+	return listResponse{Operations: append([]Info{}, s.manager.Failures()...)}, nil
+}
+
+func (s *Server) cancelOperation(ctx context.Context, req request) (interface{}, error) {
+	op := s.manager.FindString(req.ID)
+	if op == nil {
+		return nil, status.Errorf(codes.NotFound, "no operation %q", req.ID)
+	}
+	op.Cancel()
+	return struct{}{}, nil
+}
+
+// ServiceDesc describes the gRPC service serving a manager. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/operation",
+	structrpc.Unary("ListOperations", (*Server).listOperations),
+	structrpc.Unary("ListFailedOperations", (*Server).listFailedOperations),
+	structrpc.Unary("CancelOperation", (*Server).cancelOperation),
+)
+
+// A Client lists and cancels the operations of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the manager served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// ListOperations returns the operations running on the robot, other than the one listing
+// them.
+func (c *Client) ListOperations(ctx context.Context) ([]Info, error) {
+	var resp listResponse
+	if err := c.client.Invoke(ctx, "ListOperations", request{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Operations, nil
+}
+
+// ListFailedOperations returns the operations that ended having failed on the robot, such as
+// motions aborted for deviating from their paths, most recent last.
+func (c *Client) ListFailedOperations(ctx context.Context) ([]Info, error) {
+	var resp listResponse
+	if err := c.client.Invoke(ctx, "ListFailedOperations", request{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Operations, nil
+}
+
+// CancelOperation cancels the operation of the given ID, such as a stuck arm motion. It
+// returns an error with code NotFound if no such operation is running.
+func (c *Client) CancelOperation(ctx context.Context, id string) error {
+	return c.client.Invoke(ctx, "CancelOperation", request{ID: id}, nil)
+}
@@ -0,0 +1,103 @@
+package operation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestServer(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	m := NewManager(logger)
+	server := NewServer(m)
+
+	// requests served record the resource they are for and who made them.
+	ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 1, 2, 3), Port: 5000}})
+	ctx = rpc.ContextWithAuthEntity(ctx, rpc.EntityInfo{Entity: "operator"})
+	started := make(chan struct{})
+	moved := make(chan error)
+	go func() {
+		_, err := m.UnaryServerInterceptor(
+			ctx,
+			&pb.MoveToPositionRequest{Name: "arm1"},
+			&grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/MoveToPosition"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				close(started)
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		)
+		moved <- err
+	}()
+	<-started
+
+	resp, err := server.listOperations(context.Background(), request{})
+	test.That(t, err, test.ShouldBeNil)
+	ops := resp.(listResponse).Operations
+	test.That(t, ops, test.ShouldHaveLength, 1)
+	test.That(t, ops[0].Method, test.ShouldEqual, "/viam.component.arm.v1.ArmService/MoveToPosition")
+	test.That(t, ops[0].Resource, test.ShouldEqual, "arm1")
+	test.That(t, ops[0].Entity, test.ShouldEqual, "operator")
+	test.That(t, ops[0].Address, test.ShouldEqual, "10.1.2.3:5000")
+	test.That(t, ops[0].ID, test.ShouldNotBeEmpty)
+
+	// the operation listing them is not listed.
+	listCtx, done := m.Create(context.Background(), "/"+ServiceName+"/ListOperations", nil)
+	resp, err = server.listOperations(listCtx, request{})
+	done()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.(listResponse).Operations, test.ShouldHaveLength, 1)
+
+	// canceling an operation cancels the request it serves.
+	_, err = server.cancelOperation(context.Background(), request{ID: ops[0].ID})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, <-moved, test.ShouldBeError, context.Canceled)
+	resp, err = server.listOperations(context.Background(), request{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.(listResponse).Operations, test.ShouldBeEmpty)
+
+	_, err = server.cancelOperation(context.Background(), request{ID: ops[0].ID})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+
+	// operations that fail are listed with why while they run, and among the failures once they
+	// end.
+	resp, err = server.listFailedOperations(context.Background(), request{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.(listResponse).Operations, test.ShouldBeEmpty)
+	failCtx, done := m.Create(context.Background(), "/viam.service.motion.v1.MotionService/Move", nil)
+	Fail(failCtx, errors.New("arm1 deviated from its path"))
+	resp, err = server.listOperations(context.Background(), request{})
+	test.That(t, err, test.ShouldBeNil)
+	ops = resp.(listResponse).Operations
+	test.That(t, ops, test.ShouldHaveLength, 1)
+	test.That(t, ops[0].Error, test.ShouldEqual, "arm1 deviated from its path")
+	test.That(t, ops[0].Ended, test.ShouldBeNil)
+	done()
+	resp, err = server.listFailedOperations(context.Background(), request{})
+	test.That(t, err, test.ShouldBeNil)
+	failed := resp.(listResponse).Operations
+	test.That(t, failed, test.ShouldHaveLength, 1)
+	test.That(t, failed[0].ID, test.ShouldEqual, ops[0].ID)
+	test.That(t, failed[0].Error, test.ShouldEqual, "arm1 deviated from its path")
+	test.That(t, failed[0].Ended, test.ShouldNotBeNil)
+
+	// only the most recent failures are kept.
+	for i := 0; i < maxFailures; i++ {
+		failCtx, done := m.Create(context.Background(), "/viam.service.motion.v1.MotionService/Move", nil)
+		Fail(failCtx, errors.New("failed"))
+		done()
+	}
+	test.That(t, m.Failures(), test.ShouldHaveLength, maxFailures)
+	test.That(t, m.Failures()[0].ID, test.ShouldNotEqual, ops[0].ID)
+}
@@ -53,7 +53,11 @@ func (m *Manager) UnaryServerInterceptor(
 	info *grpc.UnaryServerInfo,
 	handler grpc.UnaryHandler,
 ) (interface{}, error) {
-	ctx, done := m.CreateFromIncomingContext(ctx, info.FullMethod)
+	var resourceName string
+	if named, ok := req.(interface{ GetName() string }); ok {
+		resourceName = named.GetName()
+	}
+	ctx, done := m.createFromIncomingContext(ctx, info.FullMethod, resourceName)
 	defer done()
 	if op := Get(ctx); op != nil && op.ID.String() != "" {
 		utils.UncheckedError(grpc.SetHeader(ctx, metadata.MD{opidMetadataKey: []string{op.ID.String()}}))
@@ -80,17 +84,23 @@ func (m *Manager) StreamServerInterceptor(
 
 // CreateFromIncomingContext creates a new operation from an incoming context.
 func (m *Manager) CreateFromIncomingContext(ctx context.Context, method string) (context.Context, func()) {
+	return m.createFromIncomingContext(ctx, method, "")
+}
+
+// createFromIncomingContext creates a new operation from an incoming context of a request
+// for the named resource, if any.
+func (m *Manager) createFromIncomingContext(ctx context.Context, method, resourceName string) (context.Context, func()) {
 	meta, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
 		m.logger.CWarnw(ctx, "failed to pull metadata from context", "method", method)
-		return m.Create(ctx, method, nil)
+		return m.create(ctx, uuid.New(), method, nil, resourceName)
 	}
 	opid, err := GetOrCreateFromMetadata(meta)
 	if err != nil {
 		m.logger.CWarnw(ctx, "failed to create operation id from metadata", "error", err)
-		return m.Create(ctx, method, nil)
+		return m.create(ctx, uuid.New(), method, nil, resourceName)
 	}
-	return m.createWithID(ctx, opid, method, nil)
+	return m.create(ctx, opid, method, nil, resourceName)
 }
 
 // GetOrCreateFromMetadata returns an operation id from metadata, or generates a random
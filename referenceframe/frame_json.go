@@ -33,6 +33,9 @@ type JointConfig struct {
 	Max      float64                 `json:"max"`                // in mm or degs
 	Min      float64                 `json:"min"`                // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"` // only valid for prismatic/translational joints
+	// Offset is a calibration offset, in mm or degs, added to the position of the joint, so that
+	// the joint reported at zero is modeled at its offset.
+	Offset float64 `json:"offset,omitempty"`
 }
 
 // DHParamConfig is a revolute and static frame combined in a set of Denavit Hartenberg parameters.
@@ -45,6 +48,8 @@ type DHParamConfig struct {
 	Max      float64                 `json:"max"` // in mm or degs
 	Min      float64                 `json:"min"` // in mm or degs
 	Geometry *spatial.GeometryConfig `json:"geometry,omitempty"`
+	// Offset is a calibration offset, in degs, added to the position of the joint.
+	Offset float64 `json:"offset,omitempty"`
 }
 
 // NewLinkConfig constructs a config from a Frame.
@@ -114,6 +119,26 @@ func (cfg *JointConfig) ToFrame() (Frame, error) {
 	}
 }
 
+// offsetFrame returns the static frame moving the joint by its calibration offset, to be put
+// between the joint and its parent, or nil if the joint has no offset.
+func (cfg *JointConfig) offsetFrame() (Frame, error) {
+	if cfg.Offset == 0 {
+		return nil, nil
+	}
+	id := cfg.ID + "_offset"
+	switch cfg.Type {
+	case RevoluteJoint:
+		axis := cfg.Axis.ParseConfig()
+		axis.Normalize()
+		axis.Theta = utils.DegToRad(cfg.Offset)
+		return NewStaticFrame(id, spatial.NewPoseFromOrientation(&axis))
+	case PrismaticJoint:
+		return NewStaticFrame(id, spatial.NewPoseFromPoint(r3.Vector(cfg.Axis).Normalize().Mul(cfg.Offset)))
+	default:
+		return nil, NewUnsupportedJointTypeError(cfg.Type)
+	}
+}
+
 // ToDHFrames converts a DHParamConfig into a joint frame and a link frame.
 func (cfg *DHParamConfig) ToDHFrames() (Frame, Frame, error) {
 	jointID := cfg.ID + "_j"
@@ -126,6 +151,10 @@ func (cfg *DHParamConfig) ToDHFrames() (Frame, Frame, error) {
 	// Link part of DH param
 	linkID := cfg.ID
 	pose := spatial.NewPoseFromDH(cfg.A, cfg.D, utils.DegToRad(cfg.Alpha))
+	if cfg.Offset != 0 {
+		// the joint turns about z, so turning the link about z moves the joint by the offset.
+		pose = spatial.Compose(spatial.NewPoseFromOrientation(&spatial.R4AA{Theta: utils.DegToRad(cfg.Offset), RZ: 1}), pose)
+	}
 	var lFrame Frame
 	if cfg.Geometry != nil {
 		geometryCreator, err := cfg.Geometry.ParseConfig()
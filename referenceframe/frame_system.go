@@ -68,6 +68,14 @@ type FrameSystem interface {
 	// frame's children and parentage to replacementFrame. The original frame is removed entirely from the frame system.
 	// replacementFrame is not allowed to exist within the frame system at the time of the call.
 	ReplaceFrame(replacementFrame Frame) error
+
+	// ToURDF returns the frame system at zero inputs as a URDF robot description, for use in tools such as RViz.
+	// The frames of models are prefixed with the name of the model and a colon.
+	ToURDF() ([]byte, error)
+
+	// ToSDF returns the frame system at zero inputs as an SDF model, for use in tools such as Gazebo.
+	// The frames of models are prefixed with the name of the model and a colon.
+	ToSDF() ([]byte, error)
 }
 
 // FrameSystemPart is used to collect all the info need from a named robot part to build the frame node in a frame system.
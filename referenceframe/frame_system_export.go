@@ -0,0 +1,468 @@
+package referenceframe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// sdfVersion is the version of SDF that frame systems are exported to; capsules need 1.8 or later.
+const sdfVersion = "1.9"
+
+// sdfUnlimited is the limit of SDF joints that are not limited.
+const sdfUnlimited = 1e16
+
+// An exportedLink is a rigid body of a frame system being exported to a robot description format.
+type exportedLink struct {
+	name string
+	// geometries are relative to the link.
+	geometries []spatial.Geometry
+}
+
+// An exportedJoint connects a link of a frame system being exported to its parent link.
+type exportedJoint struct {
+	name      string
+	jointType string
+	parent    string
+	child     string
+	// origin is the pose of the child link relative to the parent link at zero inputs.
+	origin spatial.Pose
+	// axis and limit are only set for revolute and prismatic joints. Limits are in radians or mm.
+	axis  r3.Vector
+	limit *Limit
+}
+
+// exportTree returns the links and joints of the frame system at zero inputs. Each frame is a
+// link joined to the link of its parent frame, except for models, whose frames are links named
+// after the model, prefixed with its name and a colon, and joined in a chain ending at a link
+// named after the model.
+func (sfs *simpleFrameSystem) exportTree() ([]exportedLink, []exportedJoint, error) {
+	names := sfs.FrameNames()
+	sort.Strings(names)
+	links := []exportedLink{{name: World}}
+	var joints []exportedJoint
+	for _, name := range names {
+		frame := sfs.frames[name]
+		parent, err := sfs.Parent(frame)
+		if err != nil {
+			return nil, nil, err
+		}
+		links, joints, err = exportFrame(frame, name, parent.Name(), links, joints)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return links, joints, nil
+}
+
+func exportFrame(
+	frame Frame,
+	name, parent string,
+	links []exportedLink,
+	joints []exportedJoint,
+) ([]exportedLink, []exportedJoint, error) {
+	if named, ok := frame.(*namedFrame); ok {
+		return exportFrame(named.Frame, name, parent, links, joints)
+	}
+	if model, ok := frame.(*SimpleModel); ok && len(model.OrdTransforms) != 0 {
+		var err error
+		elemParent := parent
+		for _, elem := range model.OrdTransforms {
+			elemName := name + ":" + elem.Name()
+			links, joints, err = exportFrame(elem, elemName, elemParent, links, joints)
+			if err != nil {
+				return nil, nil, err
+			}
+			elemParent = elemName
+		}
+		links = append(links, exportedLink{name: name})
+		joints = append(joints, exportedJoint{
+			name:      name + "_joint",
+			jointType: FixedJoint,
+			parent:    elemParent,
+			child:     name,
+			origin:    spatial.NewZeroPose(),
+		})
+		return links, joints, nil
+	}
+
+	// frames we cannot describe the motion of are exported fixed at zero inputs.
+	zero := make([]Input, len(frame.DoF()))
+	pose, err := frame.Transform(zero)
+	if pose == nil || (err != nil && !strings.Contains(err.Error(), OOBErrString)) {
+		return nil, nil, errors.Wrapf(err, "failed to export frame %q", name)
+	}
+	gif, err := frame.Geometries(zero)
+	if gif == nil || (err != nil && !strings.Contains(err.Error(), OOBErrString)) {
+		return nil, nil, errors.Wrapf(err, "failed to export geometries of frame %q", name)
+	}
+	// geometries of a frame are relative to its parent.
+	toLink := spatial.PoseInverse(pose)
+	link := exportedLink{name: name}
+	for _, geom := range gif.Geometries() {
+		link.geometries = append(link.geometries, geom.Transform(toLink))
+	}
+	joint := exportedJoint{name: name + "_joint", jointType: FixedJoint, parent: parent, child: name, origin: pose}
+	switch f := frame.(type) {
+	case *rotationalFrame:
+		if len(f.limits) == 1 {
+			joint.jointType, joint.axis, joint.limit = RevoluteJoint, f.rotAxis, &f.limits[0]
+			if math.IsInf(f.limits[0].Min, -1) && math.IsInf(f.limits[0].Max, 1) {
+				joint.jointType, joint.limit = ContinuousJoint, nil
+			}
+		}
+	case *translationalFrame:
+		if len(f.limits) == 1 {
+			joint.jointType, joint.axis, joint.limit = PrismaticJoint, f.transAxis, &f.limits[0]
+		}
+	}
+	return append(links, link), append(joints, joint), nil
+}
+
+// ToURDF returns the frame system as a URDF robot description.
+func (sfs *simpleFrameSystem) ToURDF() ([]byte, error) {
+	links, joints, err := sfs.exportTree()
+	if err != nil {
+		return nil, err
+	}
+	robot := urdfRobot{Name: sfs.name}
+	for _, link := range links {
+		out := urdfLink{Name: link.name}
+		for i, geom := range link.geometries {
+			geometry, ok, err := newURDFGeometry(geom)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to export geometry of frame %q", link.name)
+			}
+			if !ok {
+				continue
+			}
+			origin := newURDFOrigin(geom.Pose())
+			name := geometryName(geom, link.name, i)
+			out.Visuals = append(out.Visuals, urdfShape{XMLName: xml.Name{Local: "visual"}, Name: name, Origin: origin, Geometry: geometry})
+			out.Collisions = append(out.Collisions, urdfShape{
+				XMLName: xml.Name{Local: "collision"}, Name: name, Origin: origin, Geometry: geometry,
+			})
+		}
+		robot.Links = append(robot.Links, out)
+	}
+	for _, joint := range joints {
+		out := urdfJoint{
+			Name:   joint.name,
+			Type:   joint.jointType,
+			Origin: newURDFOrigin(joint.origin),
+			Parent: urdfLinkRef{Link: joint.parent},
+			Child:  urdfLinkRef{Link: joint.child},
+		}
+		if joint.jointType != FixedJoint {
+			out.Axis = &urdfAxis{XYZ: vectorString(joint.axis)}
+		}
+		if joint.limit != nil {
+			out.Limit = &urdfLimit{Lower: joint.limit.Min, Upper: joint.limit.Max}
+			if joint.jointType == PrismaticJoint {
+				out.Limit.Lower, out.Limit.Upper = utils.MMToMeters(joint.limit.Min), utils.MMToMeters(joint.limit.Max)
+			}
+		}
+		robot.Joints = append(robot.Joints, out)
+	}
+	return marshalXML(robot)
+}
+
+// ToSDF returns the frame system as an SDF model, rooted at the world.
+func (sfs *simpleFrameSystem) ToSDF() ([]byte, error) {
+	links, joints, err := sfs.exportTree()
+	if err != nil {
+		return nil, err
+	}
+	parents := make(map[string]exportedJoint, len(joints))
+	for _, joint := range joints {
+		parents[joint.child] = joint
+	}
+
+	model := sdfModel{Name: sfs.name}
+	for _, link := range links {
+		// world is a frame of its own in SDF rather than a link.
+		if link.name == World {
+			continue
+		}
+		out := sdfLink{Name: link.name}
+		if joint, ok := parents[link.name]; ok {
+			out.Pose = newSDFPose(joint.origin)
+			if joint.parent != World {
+				out.Pose.RelativeTo = joint.parent
+			}
+		}
+		for i, geom := range link.geometries {
+			geometry, ok, err := newSDFGeometry(geom)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to export geometry of frame %q", link.name)
+			}
+			if !ok {
+				continue
+			}
+			name := geometryName(geom, link.name, i)
+			pose := newSDFPose(geom.Pose())
+			out.Visuals = append(out.Visuals, sdfShape{XMLName: xml.Name{Local: "visual"}, Name: name, Pose: pose, Geometry: geometry})
+			out.Collisions = append(out.Collisions, sdfShape{
+				XMLName: xml.Name{Local: "collision"}, Name: name, Pose: pose, Geometry: geometry,
+			})
+		}
+		model.Links = append(model.Links, out)
+	}
+	for _, joint := range joints {
+		out := sdfJoint{Name: joint.name, Type: joint.jointType, Parent: joint.parent, Child: joint.child}
+		if joint.jointType != FixedJoint {
+			out.Axis = &sdfAxis{XYZ: vectorString(joint.axis)}
+		}
+		if joint.limit != nil {
+			out.Axis.Limit = &sdfLimit{Lower: joint.limit.Min, Upper: joint.limit.Max}
+			if joint.jointType == PrismaticJoint {
+				out.Axis.Limit.Lower, out.Axis.Limit.Upper = utils.MMToMeters(joint.limit.Min), utils.MMToMeters(joint.limit.Max)
+			}
+		}
+		if joint.jointType == ContinuousJoint {
+			// SDF has no continuous joints; revolute joints with these limits are continuous.
+			out.Type = RevoluteJoint
+			out.Axis.Limit = &sdfLimit{Lower: -sdfUnlimited, Upper: sdfUnlimited}
+		}
+		model.Joints = append(model.Joints, out)
+	}
+	return marshalXML(sdfRoot{Version: sdfVersion, Model: model})
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(data, '\n')...), nil
+}
+
+// geometryName returns the name of a geometry of a link, which must be unique within the link.
+func geometryName(geom spatial.Geometry, link string, i int) string {
+	if label := geom.Label(); label != "" && label != link {
+		return fmt.Sprintf("%s_%d", label, i)
+	}
+	return fmt.Sprintf("%s_geometry_%d", link, i)
+}
+
+// vectorString returns the vector as space separated values, as robot description formats
+// expect.
+func vectorString(v r3.Vector) string {
+	return fmt.Sprintf("%g %g %g", v.X, v.Y, v.Z)
+}
+
+// poseStrings returns the position of the pose in meters and its orientation as roll, pitch,
+// and yaw in radians.
+func poseStrings(p spatial.Pose) (string, string) {
+	pt := p.Point()
+	o := p.Orientation().EulerAngles()
+	return vectorString(r3.Vector{X: utils.MMToMeters(pt.X), Y: utils.MMToMeters(pt.Y), Z: utils.MMToMeters(pt.Z)}),
+		fmt.Sprintf("%g %g %g", o.Roll, o.Pitch, o.Yaw)
+}
+
+type urdfRobot struct {
+	XMLName xml.Name    `xml:"robot"`
+	Name    string      `xml:"name,attr"`
+	Links   []urdfLink  `xml:"link"`
+	Joints  []urdfJoint `xml:"joint"`
+}
+
+type urdfLink struct {
+	Name       string      `xml:"name,attr"`
+	Visuals    []urdfShape `xml:"visual"`
+	Collisions []urdfShape `xml:"collision"`
+}
+
+// urdfShape is the XML of a URDF visual or collision element.
+type urdfShape struct {
+	XMLName  xml.Name
+	Name     string       `xml:"name,attr,omitempty"`
+	Origin   urdfOrigin   `xml:"origin"`
+	Geometry urdfGeometry `xml:"geometry"`
+}
+
+type urdfOrigin struct {
+	XYZ string `xml:"xyz,attr"` // in meters
+	RPY string `xml:"rpy,attr"` // in radians
+}
+
+func newURDFOrigin(p spatial.Pose) urdfOrigin {
+	xyz, rpy := poseStrings(p)
+	return urdfOrigin{XYZ: xyz, RPY: rpy}
+}
+
+type urdfGeometry struct {
+	Box *struct {
+		Size string `xml:"size,attr"`
+	} `xml:"box,omitempty"`
+	Sphere *struct {
+		Radius float64 `xml:"radius,attr"`
+	} `xml:"sphere,omitempty"`
+	Cylinder *struct {
+		Radius float64 `xml:"radius,attr"`
+		Length float64 `xml:"length,attr"`
+	} `xml:"cylinder,omitempty"`
+}
+
+// newURDFGeometry returns the URDF form of the geometry, and false if the geometry has no
+// volume to describe. URDF has no capsules, so capsules are described by the cylinders
+// enclosing them.
+func newURDFGeometry(geom spatial.Geometry) (urdfGeometry, bool, error) {
+	cfg, err := spatial.NewGeometryConfig(geom)
+	if err != nil {
+		return urdfGeometry{}, false, err
+	}
+	var out urdfGeometry
+	switch cfg.Type {
+	case spatial.BoxType:
+		out.Box = &struct {
+			Size string `xml:"size,attr"`
+		}{Size: vectorString(r3.Vector{X: utils.MMToMeters(cfg.X), Y: utils.MMToMeters(cfg.Y), Z: utils.MMToMeters(cfg.Z)})}
+	case spatial.SphereType:
+		out.Sphere = &struct {
+			Radius float64 `xml:"radius,attr"`
+		}{Radius: utils.MMToMeters(cfg.R)}
+	case spatial.CapsuleType:
+		out.Cylinder = &struct {
+			Radius float64 `xml:"radius,attr"`
+			Length float64 `xml:"length,attr"`
+		}{Radius: utils.MMToMeters(cfg.R), Length: utils.MMToMeters(cfg.L)}
+	case spatial.PointType:
+		return urdfGeometry{}, false, nil
+	case spatial.UnknownType:
+		return urdfGeometry{}, false, errors.Errorf("unsupported geometry type %T", geom)
+	}
+	return out, true, nil
+}
+
+type urdfLinkRef struct {
+	Link string `xml:"link,attr"`
+}
+
+type urdfAxis struct {
+	XYZ string `xml:"xyz,attr"`
+}
+
+type urdfLimit struct {
+	Lower    float64 `xml:"lower,attr"` // in meters or radians
+	Upper    float64 `xml:"upper,attr"` // in meters or radians
+	Effort   float64 `xml:"effort,attr"`
+	Velocity float64 `xml:"velocity,attr"`
+}
+
+type urdfJoint struct {
+	Name   string      `xml:"name,attr"`
+	Type   string      `xml:"type,attr"`
+	Origin urdfOrigin  `xml:"origin"`
+	Parent urdfLinkRef `xml:"parent"`
+	Child  urdfLinkRef `xml:"child"`
+	Axis   *urdfAxis   `xml:"axis,omitempty"`
+	Limit  *urdfLimit  `xml:"limit,omitempty"`
+}
+
+type sdfRoot struct {
+	XMLName xml.Name `xml:"sdf"`
+	Version string   `xml:"version,attr"`
+	Model   sdfModel `xml:"model"`
+}
+
+type sdfModel struct {
+	Name   string     `xml:"name,attr"`
+	Links  []sdfLink  `xml:"link"`
+	Joints []sdfJoint `xml:"joint"`
+}
+
+type sdfPose struct {
+	RelativeTo string `xml:"relative_to,attr,omitempty"`
+	// Value is the position in meters followed by the roll, pitch, and yaw in radians.
+	Value string `xml:",chardata"`
+}
+
+func newSDFPose(p spatial.Pose) *sdfPose {
+	xyz, rpy := poseStrings(p)
+	return &sdfPose{Value: xyz + " " + rpy}
+}
+
+type sdfLink struct {
+	Name       string     `xml:"name,attr"`
+	Pose       *sdfPose   `xml:"pose,omitempty"`
+	Visuals    []sdfShape `xml:"visual"`
+	Collisions []sdfShape `xml:"collision"`
+}
+
+// sdfShape is the XML of an SDF visual or collision element.
+type sdfShape struct {
+	XMLName  xml.Name
+	Name     string      `xml:"name,attr"`
+	Pose     *sdfPose    `xml:"pose,omitempty"`
+	Geometry sdfGeometry `xml:"geometry"`
+}
+
+type sdfGeometry struct {
+	Box *struct {
+		Size string `xml:"size"`
+	} `xml:"box,omitempty"`
+	Sphere *struct {
+		Radius float64 `xml:"radius"`
+	} `xml:"sphere,omitempty"`
+	Capsule *struct {
+		Radius float64 `xml:"radius"`
+		Length float64 `xml:"length"` // of the cylinder between the caps
+	} `xml:"capsule,omitempty"`
+}
+
+// newSDFGeometry returns the SDF form of the geometry, and false if the geometry has no volume
+// to describe.
+func newSDFGeometry(geom spatial.Geometry) (sdfGeometry, bool, error) {
+	cfg, err := spatial.NewGeometryConfig(geom)
+	if err != nil {
+		return sdfGeometry{}, false, err
+	}
+	var out sdfGeometry
+	switch cfg.Type {
+	case spatial.BoxType:
+		out.Box = &struct {
+			Size string `xml:"size"`
+		}{Size: vectorString(r3.Vector{X: utils.MMToMeters(cfg.X), Y: utils.MMToMeters(cfg.Y), Z: utils.MMToMeters(cfg.Z)})}
+	case spatial.SphereType:
+		out.Sphere = &struct {
+			Radius float64 `xml:"radius"`
+		}{Radius: utils.MMToMeters(cfg.R)}
+	case spatial.CapsuleType:
+		out.Capsule = &struct {
+			Radius float64 `xml:"radius"`
+			Length float64 `xml:"length"`
+		}{Radius: utils.MMToMeters(cfg.R), Length: utils.MMToMeters(cfg.L - 2*cfg.R)}
+	case spatial.PointType:
+		return sdfGeometry{}, false, nil
+	case spatial.UnknownType:
+		return sdfGeometry{}, false, errors.Errorf("unsupported geometry type %T", geom)
+	}
+	return out, true, nil
+}
+
+type sdfAxis struct {
+	XYZ   string    `xml:"xyz"`
+	Limit *sdfLimit `xml:"limit,omitempty"`
+}
+
+type sdfLimit struct {
+	Lower float64 `xml:"lower"` // in meters or radians
+	Upper float64 `xml:"upper"` // in meters or radians
+}
+
+type sdfJoint struct {
+	Name   string   `xml:"name,attr"`
+	Type   string   `xml:"type,attr"`
+	Parent string   `xml:"parent"`
+	Child  string   `xml:"child"`
+	Axis   *sdfAxis `xml:"axis,omitempty"`
+}
@@ -0,0 +1,172 @@
+package referenceframe
+
+import (
+	"encoding/xml"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
+)
+
+// exportTestFrameSystem returns a frame system with a camera, a remote arm holding a gripper,
+// and a gantry.
+func exportTestFrameSystem(t *testing.T) FrameSystem {
+	t.Helper()
+	arm, err := ParseModelJSONFile(utils.ResolveFile("referenceframe/testjson/ur5eDH.json"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+
+	rail, err := NewTranslationalFrameWithGeometry("rail", r3.Vector{X: 1}, Limit{Min: 0, Max: 500}, nil)
+	test.That(t, err, test.ShouldBeNil)
+	capsule, err := spatial.NewCapsule(spatial.NewZeroPose(), 20, 200, "carriage")
+	test.That(t, err, test.ShouldBeNil)
+	carriage, err := NewStaticFrameWithGeometry("carriage", spatial.NewZeroPose(), capsule)
+	test.That(t, err, test.ShouldBeNil)
+	gantry := NewSimpleModel("gantry")
+	gantry.OrdTransforms = []Frame{rail, carriage}
+
+	box, err := spatial.NewBox(spatial.NewZeroPose(), r3.Vector{X: 100, Y: 50, Z: 20}, "")
+	test.That(t, err, test.ShouldBeNil)
+	parts := []*FrameSystemPart{
+		{FrameConfig: NewLinkInFrame(World, spatial.NewPoseFromPoint(r3.Vector{X: 10, Z: 500}), "camera", box)},
+		// remote parts are prefixed with the name of their remote, unlike their models.
+		{
+			FrameConfig: NewLinkInFrame(World, spatial.NewPose(r3.Vector{Y: 200}, &spatial.OrientationVectorDegrees{OZ: 1, Theta: 90}),
+				"remote:arm", nil),
+			ModelFrame: arm,
+		},
+		{FrameConfig: NewLinkInFrame("remote:arm", spatial.NewPoseFromPoint(r3.Vector{Z: 150}), "gripper", nil)},
+		{FrameConfig: NewLinkInFrame(World, spatial.NewZeroPose(), "gantry", nil), ModelFrame: gantry},
+	}
+	fs, err := NewFrameSystem("test", parts, nil)
+	test.That(t, err, test.ShouldBeNil)
+	return fs
+}
+
+func parsePoseStrings(t *testing.T, xyz, rpy string) spatial.Pose {
+	t.Helper()
+	var x, y, z, roll, pitch, yaw float64
+	_, err := fmt.Sscan(xyz, &x, &y, &z)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = fmt.Sscan(rpy, &roll, &pitch, &yaw)
+	test.That(t, err, test.ShouldBeNil)
+	return spatial.NewPose(
+		r3.Vector{X: utils.MetersToMM(x), Y: utils.MetersToMM(y), Z: utils.MetersToMM(z)},
+		&spatial.EulerAngles{Roll: roll, Pitch: pitch, Yaw: yaw},
+	)
+}
+
+func TestToURDF(t *testing.T) {
+	fs := exportTestFrameSystem(t)
+	data, err := fs.ToURDF()
+	test.That(t, err, test.ShouldBeNil)
+	var robot urdfRobot
+	test.That(t, xml.Unmarshal(data, &robot), test.ShouldBeNil)
+	test.That(t, robot.Name, test.ShouldEqual, "test")
+
+	links := map[string]urdfLink{}
+	for _, link := range robot.Links {
+		links[link.Name] = link
+	}
+	for _, name := range []string{World, "camera", "remote:arm", "remote:arm:base_j", "gripper", "gantry", "gantry:rail", "gantry:carriage"} {
+		test.That(t, links, test.ShouldContainKey, name)
+	}
+	// the geometry of a part is on the frame placing it.
+	test.That(t, links["camera_origin"].Collisions, test.ShouldHaveLength, 1)
+	test.That(t, links["camera_origin"].Visuals, test.ShouldHaveLength, 1)
+	test.That(t, links["camera_origin"].Collisions[0].Geometry.Box.Size, test.ShouldEqual, "0.1 0.05 0.02")
+	cylinder := links["gantry:carriage"].Collisions[0].Geometry.Cylinder
+	test.That(t, cylinder.Radius, test.ShouldAlmostEqual, 0.02)
+	test.That(t, cylinder.Length, test.ShouldAlmostEqual, 0.2)
+
+	joints := map[string]urdfJoint{}
+	var revolute int
+	for _, joint := range robot.Joints {
+		joints[joint.Child.Link] = joint
+		if joint.Type == RevoluteJoint {
+			revolute++
+			test.That(t, joint.Limit, test.ShouldNotBeNil)
+		}
+	}
+	test.That(t, revolute, test.ShouldEqual, 6)
+	rail := joints["gantry:rail"]
+	test.That(t, rail.Type, test.ShouldEqual, PrismaticJoint)
+	test.That(t, rail.Parent.Link, test.ShouldEqual, "gantry_origin")
+	test.That(t, rail.Axis.XYZ, test.ShouldEqual, "1 0 0")
+	test.That(t, rail.Limit.Upper, test.ShouldAlmostEqual, 0.5)
+
+	// composing the joints from the world gives the pose of each frame at zero inputs.
+	inputs := StartPositions(fs)
+	for _, name := range []string{"camera", "remote:arm", "gripper", "gantry"} {
+		pose := spatial.NewZeroPose()
+		for link := name; link != World; {
+			joint, ok := joints[link]
+			test.That(t, ok, test.ShouldBeTrue)
+			pose = spatial.Compose(parsePoseStrings(t, joint.Origin.XYZ, joint.Origin.RPY), pose)
+			link = joint.Parent.Link
+		}
+		expected, err := fs.Transform(inputs, NewPoseInFrame(name, spatial.NewZeroPose()), World)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatial.PoseAlmostCoincidentEps(pose, expected.(*PoseInFrame).Pose(), 1e-2), test.ShouldBeTrue)
+	}
+}
+
+func TestToSDF(t *testing.T) {
+	fs := exportTestFrameSystem(t)
+	data, err := fs.ToSDF()
+	test.That(t, err, test.ShouldBeNil)
+	var root sdfRoot
+	test.That(t, xml.Unmarshal(data, &root), test.ShouldBeNil)
+	test.That(t, root.Version, test.ShouldEqual, sdfVersion)
+	test.That(t, root.Model.Name, test.ShouldEqual, "test")
+
+	links := map[string]sdfLink{}
+	for _, link := range root.Model.Links {
+		links[link.Name] = link
+	}
+	test.That(t, links, test.ShouldNotContainKey, World)
+	test.That(t, links["camera_origin"].Pose.RelativeTo, test.ShouldBeEmpty)
+	test.That(t, links["camera_origin"].Pose.Value, test.ShouldEqual, "0.01 0 0.5 0 0 0")
+	test.That(t, links["camera"].Pose.RelativeTo, test.ShouldEqual, "camera_origin")
+	test.That(t, links["gripper_origin"].Pose.RelativeTo, test.ShouldEqual, "remote:arm")
+	capsule := links["gantry:carriage"].Collisions[0].Geometry.Capsule
+	test.That(t, capsule.Radius, test.ShouldAlmostEqual, 0.02)
+	test.That(t, capsule.Length, test.ShouldAlmostEqual, 0.16)
+
+	var rail sdfJoint
+	for _, joint := range root.Model.Joints {
+		if joint.Child == "gantry:rail" {
+			rail = joint
+		}
+		test.That(t, joint.Type, test.ShouldNotEqual, ContinuousJoint)
+	}
+	test.That(t, rail.Type, test.ShouldEqual, PrismaticJoint)
+	test.That(t, rail.Axis.Limit.Lower, test.ShouldEqual, 0)
+	test.That(t, rail.Axis.Limit.Upper, test.ShouldAlmostEqual, 0.5)
+}
+
+func TestExportContinuousJoint(t *testing.T) {
+	spin, err := NewRotationalFrame("spin", spatial.R4AA{RZ: 1}, Limit{Min: math.Inf(-1), Max: math.Inf(1)})
+	test.That(t, err, test.ShouldBeNil)
+	fs := NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(spin, fs.World()), test.ShouldBeNil)
+
+	data, err := fs.ToURDF()
+	test.That(t, err, test.ShouldBeNil)
+	var robot urdfRobot
+	test.That(t, xml.Unmarshal(data, &robot), test.ShouldBeNil)
+	test.That(t, robot.Joints, test.ShouldHaveLength, 1)
+	test.That(t, robot.Joints[0].Type, test.ShouldEqual, ContinuousJoint)
+	test.That(t, robot.Joints[0].Limit, test.ShouldBeNil)
+
+	data, err = fs.ToSDF()
+	test.That(t, err, test.ShouldBeNil)
+	var root sdfRoot
+	test.That(t, xml.Unmarshal(data, &root), test.ShouldBeNil)
+	test.That(t, root.Model.Joints[0].Type, test.ShouldEqual, RevoluteJoint)
+	test.That(t, root.Model.Joints[0].Axis.Limit.Upper, test.ShouldEqual, sdfUnlimited)
+}
@@ -5,6 +5,9 @@ import (
 	"os"
 
 	"github.com/pkg/errors"
+
+	spatial "go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/utils"
 )
 
 // ErrNoModelInformation is used when there is no model information.
@@ -91,6 +94,16 @@ func (cfg *ModelConfig) ParseConfig(modelName string) (Model, error) {
 			if err != nil {
 				return nil, err
 			}
+			// a calibration offset goes between the joint and its parent.
+			offset, err := joint.offsetFrame()
+			if err != nil {
+				return nil, err
+			}
+			if offset != nil {
+				parentMap[offset.Name()] = joint.Parent
+				parentMap[joint.ID] = offset.Name()
+				transforms[offset.Name()] = offset
+			}
 		}
 
 	case "DH":
@@ -156,3 +169,113 @@ func ParseModelJSONFile(filename, modelName string) (Model, error) {
 	}
 	return UnmarshalModelJSON(jsonData, modelName)
 }
+
+// CalibrateModel returns the model with the given calibration offsets, in mm or degs, added to
+// the positions of its joints, in the order of its inputs, so that a joint reported at zero is
+// modeled at its offset. The offsets are added to those the model already has.
+func CalibrateModel(model Model, offsets []float64) (Model, error) {
+	simple, ok := model.(*SimpleModel)
+	if !ok || simple.modelConfig == nil {
+		return nil, errors.Errorf("model %q was not built from a kinematics config and cannot be calibrated", model.Name())
+	}
+	if len(offsets) != len(model.DoF()) {
+		return nil, errors.Errorf("%d calibration offsets given for model %q with %d joints", len(offsets), model.Name(), len(model.DoF()))
+	}
+
+	cfg := *simple.modelConfig
+	cfg.Joints = append([]JointConfig(nil), cfg.Joints...)
+	cfg.DHParams = append([]DHParamConfig(nil), cfg.DHParams...)
+	offsetOf := map[string]float64{}
+	i := 0
+	for _, frame := range simple.OrdTransforms {
+		if len(frame.DoF()) == 0 {
+			continue
+		}
+		offsetOf[frame.Name()] = offsets[i]
+		i++
+	}
+	for i := range cfg.Joints {
+		cfg.Joints[i].Offset += offsetOf[cfg.Joints[i].ID]
+	}
+	for i := range cfg.DHParams {
+		cfg.DHParams[i].Offset += offsetOf[cfg.DHParams[i].ID+"_j"]
+	}
+
+	// the kinematics served for the model are those it was calibrated to.
+	return cfg.reparse(model.Name())
+}
+
+// WithLinkGeometries returns the model with a capsule of the given radius, in mm, generated along
+// every link of its kinematics that has none of its own, from the origin of the frame before it
+// to its own, so that the model can be checked for collisions with itself.
+func WithLinkGeometries(model Model, radiusMm float64) (Model, error) {
+	simple, ok := model.(*SimpleModel)
+	if !ok || simple.modelConfig == nil {
+		return nil, errors.Errorf("model %q was not built from a kinematics config and cannot have its links generated", model.Name())
+	}
+	if radiusMm <= 0 {
+		return nil, errors.Errorf("links of model %q cannot be generated with radius %v", model.Name(), radiusMm)
+	}
+
+	cfg := *simple.modelConfig
+	cfg.Links = append([]LinkConfig(nil), cfg.Links...)
+	cfg.DHParams = append([]DHParamConfig(nil), cfg.DHParams...)
+	for i := range cfg.Links {
+		if cfg.Links[i].Geometry != nil {
+			continue
+		}
+		pose, err := cfg.Links[i].Pose()
+		if err != nil {
+			return nil, err
+		}
+		if cfg.Links[i].Geometry, err = linkCapsule(pose, radiusMm); err != nil {
+			return nil, err
+		}
+	}
+	for i := range cfg.DHParams {
+		if cfg.DHParams[i].Geometry != nil {
+			continue
+		}
+		pose := spatial.NewPoseFromDH(cfg.DHParams[i].A, cfg.DHParams[i].D, utils.DegToRad(cfg.DHParams[i].Alpha))
+		var err error
+		if cfg.DHParams[i].Geometry, err = linkCapsule(pose, radiusMm); err != nil {
+			return nil, err
+		}
+	}
+	return cfg.reparse(model.Name())
+}
+
+// linkCapsule returns the config of a capsule of the given radius joining the origin of the frame
+// a link is posed from to the origin of the link, in the frame it is posed from, where the
+// geometry of a link is placed, or nil if they coincide.
+func linkCapsule(pose spatial.Pose, radiusMm float64) (*spatial.GeometryConfig, error) {
+	link := pose.Point()
+	length := link.Norm()
+	if length < 1e-6 {
+		return nil, nil
+	}
+	dir := link.Normalize()
+	orientation, err := spatial.NewOrientationConfig(&spatial.OrientationVector{OX: dir.X, OY: dir.Y, OZ: dir.Z})
+	if err != nil {
+		return nil, err
+	}
+	return &spatial.GeometryConfig{
+		Type:              spatial.CapsuleType,
+		R:                 radiusMm,
+		L:                 length + 2*radiusMm,
+		TranslationOffset: link.Mul(0.5),
+		OrientationOffset: *orientation,
+	}, nil
+}
+
+// reparse parses the config into a model with the given name, serving the config as the
+// kinematics of the model.
+func (cfg ModelConfig) reparse(modelName string) (Model, error) {
+	cfg.OriginalFile = nil
+	data, err := json.Marshal(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	cfg.OriginalFile = &ModelFile{Bytes: data, Extension: "json"}
+	return cfg.ParseConfig(modelName)
+}
@@ -140,3 +140,105 @@ func Test2DMobileModelFrame(t *testing.T) {
 	limit := frame.DoF()
 	test.That(t, limit[0], test.ShouldResemble, expLimit[0])
 }
+
+func TestCalibrateModel(t *testing.T) {
+	for _, file := range []string{"components/arm/xarm/xarm6_kinematics.json", "referenceframe/testjson/ur5eDH.json"} {
+		t.Run(file, func(t *testing.T) {
+			m, err := ParseModelJSONFile(utils.ResolveFile(file), "arm")
+			test.That(t, err, test.ShouldBeNil)
+			offsets := []float64{10, -20, 0, 5, 90, -45}
+			calibrated, err := CalibrateModel(m, offsets)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, calibrated.Name(), test.ShouldEqual, "arm")
+			test.That(t, calibrated.DoF(), test.ShouldResemble, m.DoF())
+
+			// the calibrated model at some inputs is where the model is at the inputs plus the offsets.
+			inputs := FloatsToInputs([]float64{0.1, 0.2, -0.3, 0.4, 0.5, 0.6})
+			shifted := make([]Input, len(inputs))
+			for i, input := range inputs {
+				shifted[i] = Input{input.Value + utils.DegToRad(offsets[i])}
+			}
+			got, err := calibrated.Transform(inputs)
+			test.That(t, err, test.ShouldBeNil)
+			want, err := m.Transform(shifted)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, spatial.PoseAlmostEqual(got, want), test.ShouldBeTrue)
+
+			// the kinematics of the calibrated model rebuild it.
+			reparsed, err := UnmarshalModelJSON(calibrated.ModelConfig().OriginalFile.Bytes, "arm")
+			test.That(t, err, test.ShouldBeNil)
+			got, err = reparsed.Transform(inputs)
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, spatial.PoseAlmostEqual(got, want), test.ShouldBeTrue)
+		})
+	}
+
+	m, err := ParseModelJSONFile(utils.ResolveFile("components/arm/xarm/xarm6_kinematics.json"), "")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = CalibrateModel(m, []float64{1, 2})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "2 calibration offsets")
+}
+
+func TestWithLinkGeometries(t *testing.T) {
+	m, err := ParseModelJSONFile(utils.ResolveFile("referenceframe/testjson/ur5eDH.json"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+	inputs := FloatsToInputs([]float64{0.1, 0.2, -0.3, 0.4, 0.5, 0.6})
+	geoms, err := m.Geometries(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geoms.Geometries(), test.ShouldBeEmpty)
+
+	generated, err := WithLinkGeometries(m, 20)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, generated.DoF(), test.ShouldResemble, m.DoF())
+	got, err := generated.Transform(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	want, err := m.Transform(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatial.PoseAlmostEqual(got, want), test.ShouldBeTrue)
+
+	// every link of the arm has a capsule, which touches the capsule of the link before it.
+	geoms, err = generated.Geometries(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	links := geoms.Geometries()
+	test.That(t, len(links), test.ShouldEqual, len(m.ModelConfig().DHParams))
+	for i := 1; i < len(links); i++ {
+		collides, err := links[i].CollidesWith(links[i-1], 0)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, collides, test.ShouldBeTrue)
+	}
+	// the end of the last link is at the end of the arm.
+	end, err := spatial.NewSphere(want, 1, "")
+	test.That(t, err, test.ShouldBeNil)
+	collides, err := links[len(links)-1].CollidesWith(end, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collides, test.ShouldBeTrue)
+
+	// the kinematics of the model carry the generated links.
+	reparsed, err := UnmarshalModelJSON(generated.ModelConfig().OriginalFile.Bytes, "arm")
+	test.That(t, err, test.ShouldBeNil)
+	geoms, err = reparsed.Geometries(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(geoms.Geometries()), test.ShouldEqual, len(links))
+
+	// links with geometries of their own keep them.
+	m, err = ParseModelJSONFile(utils.ResolveFile("components/arm/universalrobots/ur5e.json"), "arm")
+	test.That(t, err, test.ShouldBeNil)
+	own, err := m.Geometries(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	generated, err = WithLinkGeometries(m, 20)
+	test.That(t, err, test.ShouldBeNil)
+	geoms, err = generated.Geometries(inputs)
+	test.That(t, err, test.ShouldBeNil)
+	byLabel := map[string]spatial.Geometry{}
+	for _, g := range geoms.Geometries() {
+		byLabel[g.Label()] = g
+	}
+	for _, g := range own.Geometries() {
+		test.That(t, byLabel[g.Label()], test.ShouldNotBeNil)
+		test.That(t, spatial.GeometriesAlmostEqual(byLabel[g.Label()], g), test.ShouldBeTrue)
+	}
+
+	_, err = WithLinkGeometries(m, 0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
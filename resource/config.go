@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/go-viper/mapstructure/v2"
 	"github.com/pkg/errors"
@@ -15,6 +16,20 @@ import (
 )
 
 // A Config describes the configuration of a resource.
+// Standby names another resource of the same API that lookups of this resource fail over to
+// while this resource is unavailable.
+// OperationTimeout bounds how long each request to the resource may take before it is
+// canceled; if RestartOnTimeout is set, the resource is also rebuilt when one takes longer.
+// Shutdown describes what is done with the resource when the robot closes.
+// Labels are arbitrary key/value pairs by which groups of resources are selected, as with
+// a LabelSelector.
+// Optional marks a resource the robot can do without: if it fails to build, the robot still
+// starts, even with partial start disabled, and the resource is retried in the background.
+// Limits are the safety limits of the motion of an arm or base, enforced on every request.
+// Kinematics override the kinematic model of an arm, such as to calibrate its joints.
+// Odometry drives the frame of a mobile base by where a movement sensor reports it is.
+// When is the condition under which the resource is configured; resources whose condition
+// does not match the robot are left out of its config when the config is read.
 type Config struct {
 	Name             string
 	API              API
@@ -23,6 +38,16 @@ type Config struct {
 	DependsOn        []string
 	LogConfiguration LogConfig
 	Attributes       utils.AttributeMap
+	Standby          string
+	OperationTimeout time.Duration
+	RestartOnTimeout bool
+	Shutdown         *ShutdownConfig
+	Labels           map[string]string
+	Optional         bool
+	Limits           *MotionLimits
+	Kinematics       *KinematicsConfig
+	Odometry         *OdometryConfig
+	When             *When
 
 	AssociatedResourceConfigs []AssociatedResourceConfig
 	AssociatedAttributes      map[Name]AssociatedConfig
@@ -34,6 +59,18 @@ type Config struct {
 	cachedErr          error
 }
 
+// A ShutdownConfig describes how a resource is shut down when the robot closes. Resources
+// are closed after the resources that depend on them, or that are mounted on their frames.
+type ShutdownConfig struct {
+	// Before names resources that are closed after this one even though this one does not
+	// depend on them, such as the board powering an arm.
+	Before []string `json:"before,omitempty"`
+	// Action is carried out just before the resource is closed, such as moving an arm to a
+	// safe pose. It is interpreted by the API of the resource if it registers a
+	// ShutdownAction, and otherwise sent to the resource with DoCommand.
+	Action map[string]interface{} `json:"action,omitempty"`
+}
+
 // A LogConfig describes the LogConfig config object.
 type LogConfig struct {
 	Level logging.Level `json:"level"`
@@ -50,6 +87,16 @@ type typeSpecificConfigData struct {
 	LogConfiguration          LogConfig                  `json:"log_configuration"`
 	AssociatedResourceConfigs []AssociatedResourceConfig `json:"service_configs,omitempty"`
 	Attributes                utils.AttributeMap         `json:"attributes,omitempty"`
+	Standby                   string                     `json:"standby,omitempty"`
+	OperationTimeout          string                     `json:"operation_timeout,omitempty"`
+	RestartOnTimeout          bool                       `json:"restart_on_timeout,omitempty"`
+	Shutdown                  *ShutdownConfig            `json:"shutdown,omitempty"`
+	Labels                    map[string]string          `json:"labels,omitempty"`
+	Optional                  bool                       `json:"optional,omitempty"`
+	Limits                    *MotionLimits              `json:"limits,omitempty"`
+	Kinematics                *KinematicsConfig          `json:"kinematics,omitempty"`
+	Odometry                  *OdometryConfig            `json:"odometry,omitempty"`
+	When                      *When                      `json:"when,omitempty"`
 }
 
 // NOTE: This data must be maintained with what is in Config.
@@ -62,6 +109,16 @@ type configData struct {
 	LogConfiguration          LogConfig                  `json:"log_configuration"`
 	AssociatedResourceConfigs []AssociatedResourceConfig `json:"service_configs,omitempty"`
 	Attributes                utils.AttributeMap         `json:"attributes,omitempty"`
+	Standby                   string                     `json:"standby,omitempty"`
+	OperationTimeout          string                     `json:"operation_timeout,omitempty"`
+	RestartOnTimeout          bool                       `json:"restart_on_timeout,omitempty"`
+	Shutdown                  *ShutdownConfig            `json:"shutdown,omitempty"`
+	Labels                    map[string]string          `json:"labels,omitempty"`
+	Optional                  bool                       `json:"optional,omitempty"`
+	Limits                    *MotionLimits              `json:"limits,omitempty"`
+	Kinematics                *KinematicsConfig          `json:"kinematics,omitempty"`
+	Odometry                  *OdometryConfig            `json:"odometry,omitempty"`
+	When                      *When                      `json:"when,omitempty"`
 }
 
 // UnmarshalJSON unmarshals JSON into the config.
@@ -83,7 +140,16 @@ func (conf *Config) UnmarshalJSON(data []byte) error {
 		conf.LogConfiguration = confData.LogConfiguration
 		conf.AssociatedResourceConfigs = confData.AssociatedResourceConfigs
 		conf.Attributes = confData.Attributes
-		return nil
+		conf.Standby = confData.Standby
+		conf.RestartOnTimeout = confData.RestartOnTimeout
+		conf.Shutdown = confData.Shutdown
+		conf.Labels = confData.Labels
+		conf.Optional = confData.Optional
+		conf.Limits = confData.Limits
+		conf.Kinematics = confData.Kinematics
+		conf.Odometry = confData.Odometry
+		conf.When = confData.When
+		return conf.setOperationTimeout(confData.OperationTimeout)
 	}
 
 	var typeSpecificConf typeSpecificConfigData
@@ -99,11 +165,37 @@ func (conf *Config) UnmarshalJSON(data []byte) error {
 	conf.LogConfiguration = typeSpecificConf.LogConfiguration
 	conf.AssociatedResourceConfigs = typeSpecificConf.AssociatedResourceConfigs
 	conf.Attributes = typeSpecificConf.Attributes
+	conf.Standby = typeSpecificConf.Standby
+	conf.RestartOnTimeout = typeSpecificConf.RestartOnTimeout
+	conf.Shutdown = typeSpecificConf.Shutdown
+	conf.Labels = typeSpecificConf.Labels
+	conf.Optional = typeSpecificConf.Optional
+	conf.Limits = typeSpecificConf.Limits
+	conf.Kinematics = typeSpecificConf.Kinematics
+	conf.Odometry = typeSpecificConf.Odometry
+	conf.When = typeSpecificConf.When
+	return conf.setOperationTimeout(typeSpecificConf.OperationTimeout)
+}
+
+func (conf *Config) setOperationTimeout(timeout string) error {
+	conf.OperationTimeout = 0
+	if timeout == "" {
+		return nil
+	}
+	dur, err := time.ParseDuration(timeout)
+	if err != nil {
+		return errors.Wrap(err, "invalid operation_timeout")
+	}
+	conf.OperationTimeout = dur
 	return nil
 }
 
 // MarshalJSON marshals JSON from the config.
 func (conf Config) MarshalJSON() ([]byte, error) {
+	var operationTimeout string
+	if conf.OperationTimeout != 0 {
+		operationTimeout = conf.OperationTimeout.String()
+	}
 	return json.Marshal(configData{
 		Name:                      conf.Name,
 		API:                       conf.API,
@@ -113,6 +205,16 @@ func (conf Config) MarshalJSON() ([]byte, error) {
 		LogConfiguration:          conf.LogConfiguration,
 		AssociatedResourceConfigs: conf.AssociatedResourceConfigs,
 		Attributes:                conf.Attributes,
+		Standby:                   conf.Standby,
+		OperationTimeout:          operationTimeout,
+		RestartOnTimeout:          conf.RestartOnTimeout,
+		Shutdown:                  conf.Shutdown,
+		Labels:                    conf.Labels,
+		Optional:                  conf.Optional,
+		Limits:                    conf.Limits,
+		Kinematics:                conf.Kinematics,
+		Odometry:                  conf.Odometry,
+		When:                      conf.When,
 	})
 }
 
@@ -299,6 +401,73 @@ func (conf *Config) validate(path, defaultAPIType string) ([]string, error) {
 		return nil, err
 	}
 
+	if conf.Standby != "" {
+		if conf.Standby == conf.Name {
+			return nil, errors.Errorf("resource %q cannot be its own standby", conf.Name)
+		}
+		if err := utils.ValidateResourceName(conf.Standby); err != nil {
+			return nil, err
+		}
+	}
+
+	if conf.OperationTimeout < 0 {
+		return nil, errors.Errorf("resource %q operation_timeout cannot be negative", conf.Name)
+	}
+	if conf.RestartOnTimeout && conf.OperationTimeout == 0 {
+		return nil, errors.Errorf("resource %q cannot restart_on_timeout without an operation_timeout", conf.Name)
+	}
+
+	if conf.Shutdown != nil {
+		for _, before := range conf.Shutdown.Before {
+			if before == conf.Name {
+				return nil, errors.Errorf("resource %q cannot shut down before itself", conf.Name)
+			}
+			if err := utils.ValidateResourceName(before); err != nil {
+				return nil, errors.Wrapf(err, "resource %q shutdown before", conf.Name)
+			}
+		}
+	}
+
+	if err := ValidateLabels(conf.Labels); err != nil {
+		return nil, errors.Wrapf(err, "resource %q labels", conf.Name)
+	}
+
+	if conf.Limits != nil {
+		if err := conf.Limits.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "resource %q limits", conf.Name)
+		}
+		if reg, ok := LookupGenericAPIRegistration(conf.API); ok && reg.Limit == nil {
+			return nil, errors.Errorf("resource %q of API %s does not support limits", conf.Name, conf.API)
+		}
+	}
+
+	if conf.Kinematics != nil {
+		if err := conf.Kinematics.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "resource %q kinematics", conf.Name)
+		}
+		if reg, ok := LookupGenericAPIRegistration(conf.API); ok && reg.Calibrate == nil {
+			return nil, errors.Errorf("resource %q of API %s does not support kinematics", conf.Name, conf.API)
+		}
+	}
+
+	if conf.Odometry != nil {
+		if err := conf.Odometry.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "resource %q odometry", conf.Name)
+		}
+		if conf.Frame == nil {
+			return nil, errors.Errorf("resource %q needs a frame to be driven by odometry", conf.Name)
+		}
+		if conf.Odometry.MovementSensor == conf.Name {
+			return nil, errors.Errorf("resource %q cannot drive its own frame by odometry", conf.Name)
+		}
+	}
+
+	if conf.When != nil {
+		if err := conf.When.Validate(); err != nil {
+			return nil, errors.Wrapf(err, "resource %q when", conf.Name)
+		}
+	}
+
 	if err := conf.Model.Validate(); err != nil {
 		return nil, err
 	}
@@ -1,7 +1,9 @@
 package resource_test
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"go.viam.com/test"
 
@@ -9,6 +11,7 @@ import (
 	"go.viam.com/rdk/components/base"
 	"go.viam.com/rdk/components/movementsensor"
 	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/services/motion"
 	"go.viam.com/rdk/testutils"
@@ -83,6 +86,219 @@ func TestComponentValidate(t *testing.T) {
 		test.That(t, err, test.ShouldBeNil)
 	})
 
+	t.Run("standby", func(t *testing.T) {
+		conf := resource.Config{
+			Name:    "gps1",
+			API:     arm.API,
+			Model:   fakeModel,
+			Standby: "gps2",
+		}
+		_, err := conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		data, err := json.Marshal(conf)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped resource.Config
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.Standby, test.ShouldEqual, "gps2")
+
+		conf = resource.Config{Name: "gps1", API: arm.API, Model: fakeModel, Standby: "gps1"}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "its own standby")
+	})
+
+	t.Run("operation timeout", func(t *testing.T) {
+		var conf resource.Config
+		test.That(t, json.Unmarshal([]byte(`{
+			"name": "imu1",
+			"api": "rdk:component:arm",
+			"model": "rdk:builtin:fake",
+			"operation_timeout": "1.5s",
+			"restart_on_timeout": true
+		}`), &conf), test.ShouldBeNil)
+		test.That(t, conf.OperationTimeout, test.ShouldEqual, 1500*time.Millisecond)
+		test.That(t, conf.RestartOnTimeout, test.ShouldBeTrue)
+		_, err := conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		data, err := json.Marshal(conf)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped resource.Config
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.OperationTimeout, test.ShouldEqual, conf.OperationTimeout)
+		test.That(t, roundTripped.RestartOnTimeout, test.ShouldBeTrue)
+
+		test.That(t, json.Unmarshal([]byte(`{"name": "imu1", "operation_timeout": "soon"}`), &conf), test.ShouldNotBeNil)
+
+		conf = resource.Config{Name: "imu1", API: arm.API, Model: fakeModel, RestartOnTimeout: true}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "without an operation_timeout")
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		var conf resource.Config
+		test.That(t, json.Unmarshal([]byte(`{
+			"name": "arm1",
+			"api": "rdk:component:arm",
+			"model": "rdk:builtin:fake",
+			"shutdown": {"before": ["board1"], "action": {"joint_positions": [0, -90, 0]}}
+		}`), &conf), test.ShouldBeNil)
+		test.That(t, conf.Shutdown.Before, test.ShouldResemble, []string{"board1"})
+		test.That(t, conf.Shutdown.Action, test.ShouldContainKey, "joint_positions")
+		_, err := conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		data, err := json.Marshal(conf)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped resource.Config
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.Shutdown, test.ShouldResemble, conf.Shutdown)
+
+		conf = resource.Config{
+			Name: "arm1", API: arm.API, Model: fakeModel,
+			Shutdown: &resource.ShutdownConfig{Before: []string{"arm1"}},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "before itself")
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		var conf resource.Config
+		test.That(t, json.Unmarshal([]byte(`{
+			"name": "base1",
+			"api": "rdk:component:base",
+			"model": "rdk:builtin:fake",
+			"limits": {"max_linear_speed_mm_per_sec": 300, "max_linear_acceleration_mm_per_sec2": 100}
+		}`), &conf), test.ShouldBeNil)
+		test.That(t, conf.Limits, test.ShouldResemble, &resource.MotionLimits{
+			MaxLinearSpeedMmPerSec:         300,
+			MaxLinearAccelerationMmPerSec2: 100,
+		})
+		_, err := conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		data, err := json.Marshal(conf)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped resource.Config
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.Limits, test.ShouldResemble, conf.Limits)
+
+		conf = resource.Config{
+			Name: "arm1", API: arm.API, Model: fakeModel,
+			Limits: &resource.MotionLimits{MaxJointVelocityDegsPerSec: []float64{90, 0}},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "of joint 1 must be positive")
+
+		conf = resource.Config{
+			Name: "sensor1", API: sensor.API, Model: fakeModel,
+			Limits: &resource.MotionLimits{MaxLinearSpeedMmPerSec: 300},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not support limits")
+	})
+
+	t.Run("kinematics", func(t *testing.T) {
+		var conf resource.Config
+		test.That(t, json.Unmarshal([]byte(`{
+			"name": "arm1",
+			"api": "rdk:component:arm",
+			"model": "rdk:builtin:fake",
+			"kinematics": {
+				"file": "/calibrated/arm1.json",
+				"joint_offsets": [0.5, -1.2],
+				"self_collision": {"link_radius_mm": 30, "monitor_execution": true}
+			}
+		}`), &conf), test.ShouldBeNil)
+		test.That(t, conf.Kinematics, test.ShouldResemble, &resource.KinematicsConfig{
+			File:          "/calibrated/arm1.json",
+			JointOffsets:  []float64{0.5, -1.2},
+			SelfCollision: &resource.SelfCollisionConfig{LinkRadiusMm: 30, MonitorExecution: true},
+		})
+		_, err := conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		data, err := json.Marshal(conf)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped resource.Config
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.Kinematics, test.ShouldResemble, conf.Kinematics)
+
+		conf = resource.Config{
+			Name: "arm1", API: arm.API, Model: fakeModel,
+			Kinematics: &resource.KinematicsConfig{File: "arm1.yaml"},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "must be a .json or .urdf file")
+
+		conf = resource.Config{Name: "arm1", API: arm.API, Model: fakeModel, Kinematics: &resource.KinematicsConfig{}}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "joint offsets or self collision checking are required")
+
+		conf = resource.Config{
+			Name: "arm1", API: arm.API, Model: fakeModel,
+			Kinematics: &resource.KinematicsConfig{SelfCollision: &resource.SelfCollisionConfig{}},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, conf.Kinematics.SelfCollision.LinkRadius(), test.ShouldEqual, 40)
+
+		conf = resource.Config{
+			Name: "arm1", API: arm.API, Model: fakeModel,
+			Kinematics: &resource.KinematicsConfig{SelfCollision: &resource.SelfCollisionConfig{LinkRadiusMm: -1}},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "link radius cannot be negative")
+
+		conf = resource.Config{
+			Name: "sensor1", API: sensor.API, Model: fakeModel,
+			Kinematics: &resource.KinematicsConfig{JointOffsets: []float64{1}},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "does not support kinematics")
+	})
+
+	t.Run("odometry", func(t *testing.T) {
+		var conf resource.Config
+		test.That(t, json.Unmarshal([]byte(`{
+			"name": "base1",
+			"api": "rdk:component:base",
+			"model": "rdk:builtin:fake",
+			"frame": {"parent": "world"},
+			"odometry": {"movement_sensor": "odom", "origin_latitude": 40.7, "origin_longitude": -73.98}
+		}`), &conf), test.ShouldBeNil)
+		test.That(t, conf.Odometry, test.ShouldResemble, &resource.OdometryConfig{
+			MovementSensor:  "odom",
+			OriginLatitude:  40.7,
+			OriginLongitude: -73.98,
+		})
+		_, err := conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldBeNil)
+		data, err := json.Marshal(conf)
+		test.That(t, err, test.ShouldBeNil)
+		var roundTripped resource.Config
+		test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+		test.That(t, roundTripped.Odometry, test.ShouldResemble, conf.Odometry)
+
+		conf = resource.Config{Name: "base1", API: base.API, Model: fakeModel, Odometry: &resource.OdometryConfig{MovementSensor: "odom"}}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "needs a frame")
+
+		conf = resource.Config{
+			Name: "base1", API: base.API, Model: fakeModel,
+			Frame:    &referenceframe.LinkConfig{Parent: referenceframe.World},
+			Odometry: &resource.OdometryConfig{},
+		}
+		_, err = conf.Validate("path", resource.APITypeComponentName)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "movement sensor is required")
+	})
+
 	t.Run("ConvertedAttributes", func(t *testing.T) {
 		t.Run("config invalid", func(t *testing.T) {
 			invalidConf := resource.Config{
@@ -1,9 +1,12 @@
 package resource
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/utils"
 )
@@ -54,6 +57,12 @@ func NewMustRebuildError(name Name) error {
 	return &mustRebuildError{name: name}
 }
 
+// IsNotInitializedError returns whether the given error is the error of a resource that
+// has not been constructed yet and has not failed to be.
+func IsNotInitializedError(err error) bool {
+	return errors.Is(err, errNotInitalized)
+}
+
 // IsMustRebuildError returns whether or not the given error is a MustRebuildError.
 func IsMustRebuildError(err error) bool {
 	var errArt *mustRebuildError
@@ -68,6 +77,31 @@ func (e *mustRebuildError) Error() string {
 	return fmt.Sprintf("cannot reconfigure %q; must rebuild", e.name)
 }
 
+// NewReadOnlyError is returned for requests that would actuate resources of a robot in read-only
+// mode. Its gRPC status code is PermissionDenied.
+func NewReadOnlyError(name Name) error {
+	return &readOnlyError{name: name}
+}
+
+// IsReadOnlyError returns whether the given error is a read-only error. Over gRPC, it is
+// returned with the PermissionDenied code instead.
+func IsReadOnlyError(err error) bool {
+	var errArt *readOnlyError
+	return errors.As(err, &errArt)
+}
+
+type readOnlyError struct {
+	name Name
+}
+
+func (e *readOnlyError) Error() string {
+	return fmt.Sprintf("resource %q cannot be actuated; the robot is in read-only mode", e.name)
+}
+
+func (e *readOnlyError) GRPCStatus() *status.Status {
+	return status.New(codes.PermissionDenied, e.Error())
+}
+
 // DependencyNotFoundError is used when a resource is not found in a dependencies.
 func DependencyNotFoundError(name Name) error {
 	// This error represents a logical configuration error. No need to include a stack trace.
@@ -85,3 +119,67 @@ func TypeError[T Resource](actual Resource) error {
 	// This error represents a coding error. Include a stack trace for diagnostics.
 	return errors.Errorf("expected implementation of %s but it was a %T", utils.TypeStr[T](), actual)
 }
+
+// An ErrorClass is a machine-readable kind of error returned by a resource, so that clients
+// can explain why a request failed without parsing its message.
+type ErrorClass string
+
+// The classes of errors returned by resources.
+const (
+	// ErrorClassHardwareFault is the class of errors caused by the hardware behind a resource,
+	// such as a motor driver reporting a fault or a disconnected sensor.
+	ErrorClassHardwareFault ErrorClass = "hardware_fault"
+	// ErrorClassTimeout is the class of requests that did not finish in time.
+	ErrorClassTimeout ErrorClass = "timeout"
+	// ErrorClassInvalidCommand is the class of requests that the resource cannot carry out as
+	// given, such as a position out of range or an unimplemented method.
+	ErrorClassInvalidCommand ErrorClass = "invalid_command"
+	// ErrorClassUnknown is the class of every other error.
+	ErrorClassUnknown ErrorClass = "unknown"
+)
+
+// NewHardwareFaultError returns the given error classified as a hardware fault.
+func NewHardwareFaultError(err error) error {
+	return &classifiedError{class: ErrorClassHardwareFault, err: err}
+}
+
+// NewInvalidCommandError returns the given error classified as an invalid command.
+func NewInvalidCommandError(err error) error {
+	return &classifiedError{class: ErrorClassInvalidCommand, err: err}
+}
+
+type classifiedError struct {
+	class ErrorClass
+	err   error
+}
+
+func (e *classifiedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *classifiedError) Unwrap() error {
+	return e.err
+}
+
+// ClassifyError returns the class of the given error. Errors not classified by the resource
+// returning them are classified by their gRPC status code, if any.
+func ClassifyError(err error) ErrorClass {
+	var classified *classifiedError
+	if errors.As(err, &classified) {
+		return classified.class
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassTimeout
+	}
+	if errors.Is(err, ErrDoUnimplemented) {
+		return ErrorClassInvalidCommand
+	}
+	switch status.Code(err) { //nolint:exhaustive
+	case codes.DeadlineExceeded:
+		return ErrorClassTimeout
+	case codes.InvalidArgument, codes.OutOfRange, codes.Unimplemented:
+		return ErrorClassInvalidCommand
+	default:
+		return ErrorClassUnknown
+	}
+}
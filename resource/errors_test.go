@@ -1,9 +1,13 @@
 package resource
 
 import (
+	"context"
 	"testing"
 
+	"github.com/pkg/errors"
 	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestDependencyTypeError(t *testing.T) {
@@ -48,3 +52,20 @@ type someRes2 struct {
 	TriviallyReconfigurable
 	TriviallyCloseable
 }
+
+func TestClassifyError(t *testing.T) {
+	failure := errors.New("failed")
+	for err, expected := range map[error]ErrorClass{
+		failure:                                         ErrorClassUnknown,
+		NewHardwareFaultError(failure):                  ErrorClassHardwareFault,
+		NewInvalidCommandError(failure):                 ErrorClassInvalidCommand,
+		errors.Wrap(context.DeadlineExceeded, "moving"): ErrorClassTimeout,
+		status.Error(codes.DeadlineExceeded, "moving"):  ErrorClassTimeout,
+		status.Error(codes.OutOfRange, "joint limit"):   ErrorClassInvalidCommand,
+		ErrDoUnimplemented:                              ErrorClassInvalidCommand,
+	} {
+		test.That(t, ClassifyError(err), test.ShouldEqual, expected)
+	}
+	test.That(t, NewHardwareFaultError(failure).Error(), test.ShouldEqual, "failed")
+	test.That(t, errors.Is(NewHardwareFaultError(failure), failure), test.ShouldBeTrue)
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 
 	"go.viam.com/rdk/logging"
 )
@@ -324,7 +325,8 @@ func (w *GraphNode) UnresolvedDependencies() []string {
 	return unresolvedDependencies
 }
 
-// Close closes the underlying resource of this node.
+// Close runs the pre-close hook of the underlying resource of this node, if any, and
+// closes it.
 func (w *GraphNode) Close(ctx context.Context) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -334,7 +336,7 @@ func (w *GraphNode) Close(ctx context.Context) error {
 	}
 	current := w.current
 	w.current = nil
-	return current.Close(ctx)
+	return multierr.Combine(PreClose(ctx, current), current.Close(ctx))
 }
 
 func (w *GraphNode) replace(other *GraphNode) error {
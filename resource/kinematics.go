@@ -0,0 +1,182 @@
+package resource
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/referenceframe/urdf"
+)
+
+// KinematicsConfig overrides the kinematic model of a resource, such as an arm, so that it can
+// be calibrated in the field without editing the kinematics built into it.
+type KinematicsConfig struct {
+	// File is the path of a kinematics file, .json or .urdf, replacing the model built into the
+	// resource. It is read again whenever it changes.
+	File string `json:"file,omitempty"`
+	// JointOffsets are calibration offsets, in degrees, or millimeters for prismatic joints,
+	// added to the positions of the joints of the model, in the order of its inputs, so that a
+	// joint reported at zero is modeled at its offset.
+	JointOffsets []float64 `json:"joint_offsets,omitempty"`
+	// SelfCollision checks the resource for collisions with itself, with its model given a
+	// capsule along every link that has no geometry of its own.
+	SelfCollision *SelfCollisionConfig `json:"self_collision,omitempty"`
+}
+
+// defaultLinkRadiusMm is the radius of the capsules generated along links by default.
+const defaultLinkRadiusMm = 40.
+
+// SelfCollisionConfig configures the checking of a resource for collisions with itself. Plans
+// for the resource are always checked once its links have geometries.
+type SelfCollisionConfig struct {
+	// LinkRadiusMm is the radius of the capsules generated along links. It defaults to 40.
+	LinkRadiusMm float64 `json:"link_radius_mm,omitempty"`
+	// MonitorExecution also checks every move of the resource, such as one to joint positions
+	// given directly, refusing those that would take it into collision with itself.
+	MonitorExecution bool `json:"monitor_execution,omitempty"`
+}
+
+// LinkRadius returns the radius of the capsules generated along links, in mm.
+func (c *SelfCollisionConfig) LinkRadius() float64 {
+	if c.LinkRadiusMm == 0 {
+		return defaultLinkRadiusMm
+	}
+	return c.LinkRadiusMm
+}
+
+// Validate returns an error if the kinematics cannot be overridden as configured.
+func (k *KinematicsConfig) Validate() error {
+	if k.File == "" && len(k.JointOffsets) == 0 && k.SelfCollision == nil {
+		return errors.New("a kinematics file, joint offsets or self collision checking are required")
+	}
+	if k.SelfCollision != nil && k.SelfCollision.LinkRadiusMm < 0 {
+		return errors.New("self collision link radius cannot be negative")
+	}
+	if k.File != "" {
+		switch filepath.Ext(k.File) {
+		case ".json", ".urdf":
+		default:
+			return errors.Errorf("kinematics file %q must be a .json or .urdf file", k.File)
+		}
+	}
+	return nil
+}
+
+// Kinematics give the kinematic model of a resource as configured, reading its kinematics
+// file again whenever it changes.
+type Kinematics struct {
+	name   Name
+	conf   KinematicsConfig
+	logger logging.Logger
+
+	mu sync.Mutex
+	// read is whether the file was read, at its modification time modTime, into fileModel.
+	read      bool
+	modTime   time.Time
+	fileModel referenceframe.Model
+	// model is the calibrated model of base, with its links generated, or nil if it could not
+	// be derived.
+	base    referenceframe.Model
+	derived bool
+	model   referenceframe.Model
+}
+
+// NewKinematics returns the kinematics of the named resource as configured.
+func NewKinematics(name Name, conf KinematicsConfig, logger logging.Logger) *Kinematics {
+	return &Kinematics{name: name, conf: conf, logger: logger}
+}
+
+// Config returns the configured kinematics.
+func (k *Kinematics) Config() KinematicsConfig {
+	return k.conf
+}
+
+// Logger returns the logger of the kinematics.
+func (k *Kinematics) Logger() logging.Logger {
+	return k.logger
+}
+
+// Reload reads the kinematics file again if it changed since it was last read, returning
+// whether it was read. A file that cannot be read is reported, and the model last read from
+// it is kept.
+func (k *Kinematics) Reload() bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.reload()
+}
+
+func (k *Kinematics) reload() bool {
+	if k.conf.File == "" {
+		return false
+	}
+	var modTime time.Time
+	if info, err := os.Stat(k.conf.File); err == nil {
+		modTime = info.ModTime()
+	}
+	if k.read && modTime.Equal(k.modTime) {
+		return false
+	}
+	k.read, k.modTime = true, modTime
+
+	var model referenceframe.Model
+	var err error
+	if filepath.Ext(k.conf.File) == ".urdf" {
+		model, err = urdf.ParseModelXMLFile(k.conf.File, k.name.ShortName())
+	} else {
+		model, err = referenceframe.ParseModelJSONFile(k.conf.File, k.name.ShortName())
+	}
+	if err != nil {
+		k.logger.Errorw("cannot read kinematics file; keeping the model last read", "resource", k.name, "file", k.conf.File, "error", err)
+		return false
+	}
+	k.logger.Infow("read kinematics file", "resource", k.name, "file", k.conf.File)
+	k.fileModel = model
+	return true
+}
+
+// Model returns the kinematic model of the resource as configured, given the model built into
+// it: the model of the kinematics file, if any, calibrated with the joint offsets, and with its
+// links generated for self collision checking. It returns nil, having reported why, if there is
+// no such model.
+func (k *Kinematics) Model(builtin referenceframe.Model) referenceframe.Model {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	base := builtin
+	if k.conf.File != "" {
+		if !k.read {
+			k.reload()
+		}
+		base = k.fileModel
+	}
+	if base == nil {
+		return nil
+	}
+	if len(k.conf.JointOffsets) == 0 && k.conf.SelfCollision == nil {
+		return base
+	}
+	if k.derived && k.base == base {
+		return k.model
+	}
+	k.base, k.derived, k.model = base, true, nil
+	model := base
+	var err error
+	if len(k.conf.JointOffsets) != 0 {
+		if model, err = referenceframe.CalibrateModel(model, k.conf.JointOffsets); err != nil {
+			k.logger.Errorw("cannot calibrate kinematic model", "resource", k.name, "error", err)
+			return nil
+		}
+	}
+	if k.conf.SelfCollision != nil {
+		if model, err = referenceframe.WithLinkGeometries(model, k.conf.SelfCollision.LinkRadius()); err != nil {
+			k.logger.Errorw("cannot generate links of kinematic model", "resource", k.name, "error", err)
+			return nil
+		}
+	}
+	k.model = model
+	return model
+}
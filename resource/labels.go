@@ -0,0 +1,128 @@
+package resource
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// labelRegexp matches the keys and non-empty values of labels. They may not contain the
+// characters separating the requirements of a label selector.
+var labelRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9_./-]*[a-zA-Z0-9])?$`)
+
+// ValidateLabels returns an error if a key or value of the labels is invalid. Keys and
+// values are made of letters, digits, '_', '.', '/' and '-', and start and end with a
+// letter or digit; values may also be empty.
+func ValidateLabels(labels map[string]string) error {
+	for key, value := range labels {
+		if !labelRegexp.MatchString(key) {
+			return errors.Errorf("invalid label key %q", key)
+		}
+		if value != "" && !labelRegexp.MatchString(value) {
+			return errors.Errorf("invalid value %q of label %q", value, key)
+		}
+	}
+	return nil
+}
+
+type labelOperator int
+
+const (
+	labelEquals labelOperator = iota
+	labelNotEquals
+	labelExists
+	labelNotExists
+)
+
+type labelRequirement struct {
+	key      string
+	operator labelOperator
+	value    string
+}
+
+func (req labelRequirement) matches(labels map[string]string) bool {
+	value, ok := labels[req.key]
+	switch req.operator {
+	case labelEquals:
+		return ok && value == req.value
+	case labelNotEquals:
+		return !ok || value != req.value
+	case labelExists:
+		return ok
+	case labelNotExists:
+		return !ok
+	default:
+		return false
+	}
+}
+
+// A LabelSelector selects resources by their labels. It is parsed from a comma-separated
+// list of requirements, all of which labels must meet to match:
+//
+//	key=value   the label is set to the value ("==" may be used too)
+//	key!=value  the label is not set to the value, or is not set
+//	key         the label is set
+//	!key        the label is not set
+//
+// For instance, "side=left,arm" selects the resources labeled with an arm label and a side
+// label set to left. The empty selector matches all resources.
+type LabelSelector struct {
+	requirements []labelRequirement
+}
+
+// ParseLabelSelector parses the label selector.
+func ParseLabelSelector(selector string) (LabelSelector, error) {
+	var sel LabelSelector
+	if strings.TrimSpace(selector) == "" {
+		return sel, nil
+	}
+	for _, part := range strings.Split(selector, ",") {
+		part = strings.TrimSpace(part)
+		var req labelRequirement
+		switch {
+		case strings.Contains(part, "!="):
+			key, value, _ := strings.Cut(part, "!=")
+			req = labelRequirement{key: key, operator: labelNotEquals, value: value}
+		case strings.Contains(part, "=="):
+			key, value, _ := strings.Cut(part, "==")
+			req = labelRequirement{key: key, operator: labelEquals, value: value}
+		case strings.Contains(part, "="):
+			key, value, _ := strings.Cut(part, "=")
+			req = labelRequirement{key: key, operator: labelEquals, value: value}
+		case strings.HasPrefix(part, "!"):
+			req = labelRequirement{key: strings.TrimPrefix(part, "!"), operator: labelNotExists}
+		default:
+			req = labelRequirement{key: part, operator: labelExists}
+		}
+		req.key = strings.TrimSpace(req.key)
+		req.value = strings.TrimSpace(req.value)
+		if err := ValidateLabels(map[string]string{req.key: req.value}); err != nil {
+			return LabelSelector{}, errors.Wrapf(err, "invalid label selector %q", selector)
+		}
+		sel.requirements = append(sel.requirements, req)
+	}
+	return sel, nil
+}
+
+// Matches returns whether the labels meet all the requirements of the selector.
+func (sel LabelSelector) Matches(labels map[string]string) bool {
+	for _, req := range sel.requirements {
+		if !req.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+// SelectByLabel returns the names among the given ones whose labels, looked up in the given
+// map, match the selector. Names without labels match as if they had none.
+func SelectByLabel(names []Name, labels map[Name]map[string]string, sel LabelSelector) []Name {
+	var selected []Name
+	for _, name := range names {
+		if sel.Matches(labels[name]) {
+			selected = append(selected, name)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,60 @@
+package resource_test
+
+import (
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/resource"
+)
+
+func TestLabelSelector(t *testing.T) {
+	labels := map[string]string{"side": "left", "arm": "", "zone": "cell-1"}
+	for _, tc := range []struct {
+		selector string
+		matches  bool
+	}{
+		{"", true},
+		{"side=left", true},
+		{"side==left", true},
+		{"side=right", false},
+		{"side!=right", true},
+		{"side!=left", false},
+		{"missing!=left", true},
+		{"arm", true},
+		{"missing", false},
+		{"!missing", true},
+		{"!arm", false},
+		{"arm=", true},
+		{"side=", false},
+		{"side=left, zone=cell-1, arm", true},
+		{"side=left,zone=cell-2", false},
+	} {
+		sel, err := resource.ParseLabelSelector(tc.selector)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, sel.Matches(labels), test.ShouldEqual, tc.matches)
+	}
+
+	for _, selector := range []string{"=left", "side=left,", "!", "side=le ft", "a=b=c"} {
+		_, err := resource.ParseLabelSelector(selector)
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+
+	sel, err := resource.ParseLabelSelector("side=left")
+	test.That(t, err, test.ShouldBeNil)
+	names := []resource.Name{arm.Named("left_arm"), arm.Named("right_arm"), base.Named("base")}
+	selected := resource.SelectByLabel(names, map[resource.Name]map[string]string{
+		arm.Named("left_arm"):  {"side": "left"},
+		arm.Named("right_arm"): {"side": "right"},
+	}, sel)
+	test.That(t, selected, test.ShouldResemble, []resource.Name{arm.Named("left_arm")})
+}
+
+func TestValidateLabels(t *testing.T) {
+	test.That(t, resource.ValidateLabels(map[string]string{"side": "left", "viam.com/group": "", "a": "1"}), test.ShouldBeNil)
+	test.That(t, resource.ValidateLabels(map[string]string{"": "left"}), test.ShouldNotBeNil)
+	test.That(t, resource.ValidateLabels(map[string]string{"-side": "left"}), test.ShouldNotBeNil)
+	test.That(t, resource.ValidateLabels(map[string]string{"side": "left,right"}), test.ShouldNotBeNil)
+}
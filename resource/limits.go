@@ -0,0 +1,192 @@
+package resource
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+)
+
+// The names of the motion limits, as reported by limit violations.
+const (
+	LimitJointVelocity       = "max_joint_velocity_degs_per_sec"
+	LimitCartesianSpeed      = "max_cartesian_speed_mm_per_sec"
+	LimitWorkspace           = "workspace"
+	LimitLinearSpeed         = "max_linear_speed_mm_per_sec"
+	LimitAngularSpeed        = "max_angular_speed_degs_per_sec"
+	LimitLinearAcceleration  = "max_linear_acceleration_mm_per_sec2"
+	LimitAngularAcceleration = "max_angular_acceleration_degs_per_sec2"
+)
+
+// MotionLimits are the safety limits of the motion of a resource, enforced by the robot on
+// every request to move it, whoever makes it. Limits left at zero are not enforced. The arm
+// limits apply to arms and the speed and acceleration limits to bases.
+type MotionLimits struct {
+	// MaxJointVelocityDegsPerSec caps the speed of each joint of an arm, in degrees per
+	// second, or millimeters per second for prismatic joints. A single value applies to every
+	// joint.
+	MaxJointVelocityDegsPerSec []float64 `json:"max_joint_velocity_degs_per_sec,omitempty"`
+	// MaxCartesianSpeedMmPerSec caps the speed of the end effector of an arm.
+	MaxCartesianSpeedMmPerSec float64 `json:"max_cartesian_speed_mm_per_sec,omitempty"`
+	// Workspace bounds the position of the end effector of an arm.
+	Workspace *Workspace `json:"workspace,omitempty"`
+
+	MaxLinearSpeedMmPerSec            float64 `json:"max_linear_speed_mm_per_sec,omitempty"`
+	MaxAngularSpeedDegsPerSec         float64 `json:"max_angular_speed_degs_per_sec,omitempty"`
+	MaxLinearAccelerationMmPerSec2    float64 `json:"max_linear_acceleration_mm_per_sec2,omitempty"`
+	MaxAngularAccelerationDegsPerSec2 float64 `json:"max_angular_acceleration_degs_per_sec2,omitempty"`
+}
+
+// A Workspace is a box, in millimeters in the base frame of an arm, that its end effector
+// must stay within.
+type Workspace struct {
+	Min r3.Vector `json:"min"`
+	Max r3.Vector `json:"max"`
+}
+
+// Contains returns whether the point is within the workspace.
+func (w *Workspace) Contains(pt r3.Vector) bool {
+	return pt.X >= w.Min.X && pt.X <= w.Max.X &&
+		pt.Y >= w.Min.Y && pt.Y <= w.Max.Y &&
+		pt.Z >= w.Min.Z && pt.Z <= w.Max.Z
+}
+
+// Validate returns an error if any of the limits cannot be enforced.
+func (l *MotionLimits) Validate() error {
+	for i, v := range l.MaxJointVelocityDegsPerSec {
+		if v <= 0 {
+			return errors.Errorf("%s of joint %d must be positive", LimitJointVelocity, i)
+		}
+	}
+	for name, v := range map[string]float64{
+		LimitCartesianSpeed:      l.MaxCartesianSpeedMmPerSec,
+		LimitLinearSpeed:         l.MaxLinearSpeedMmPerSec,
+		LimitAngularSpeed:        l.MaxAngularSpeedDegsPerSec,
+		LimitLinearAcceleration:  l.MaxLinearAccelerationMmPerSec2,
+		LimitAngularAcceleration: l.MaxAngularAccelerationDegsPerSec2,
+	} {
+		if v < 0 {
+			return errors.Errorf("%s cannot be negative", name)
+		}
+	}
+	if w := l.Workspace; w != nil && (w.Min.X > w.Max.X || w.Min.Y > w.Max.Y || w.Min.Z > w.Max.Z) {
+		return errors.Errorf("%s min must not be above its max", LimitWorkspace)
+	}
+	return nil
+}
+
+// JointVelocity returns the velocity limit of the given joint, or zero if it is not limited.
+func (l *MotionLimits) JointVelocity(joint int) float64 {
+	switch {
+	case len(l.MaxJointVelocityDegsPerSec) == 1:
+		return l.MaxJointVelocityDegsPerSec[0]
+	case joint < len(l.MaxJointVelocityDegsPerSec):
+		return l.MaxJointVelocityDegsPerSec[joint]
+	default:
+		return 0
+	}
+}
+
+// A MotionLimiter enforces the motion limits of a resource across the requests made to it.
+// It reports every violation, and remembers the last velocity commanded, so that changes of
+// velocity can be bounded by the acceleration limits.
+type MotionLimiter struct {
+	name   Name
+	limits MotionLimits
+	logger logging.Logger
+	report func(error)
+
+	mu        sync.Mutex
+	linear    r3.Vector
+	angular   r3.Vector
+	commanded time.Time
+}
+
+// NewMotionLimiter returns a limiter enforcing the limits of the named resource, which calls
+// report with every violation.
+func NewMotionLimiter(name Name, limits MotionLimits, logger logging.Logger, report func(error)) *MotionLimiter {
+	return &MotionLimiter{name: name, limits: limits, logger: logger, report: report}
+}
+
+// Limits returns the limits enforced.
+func (l *MotionLimiter) Limits() MotionLimits {
+	return l.limits
+}
+
+// Logger returns the logger of the limiter.
+func (l *MotionLimiter) Logger() logging.Logger {
+	return l.logger
+}
+
+// Violation reports a violation of the given limit and returns it as an error.
+func (l *MotionLimiter) Violation(limit, format string, args ...interface{}) error {
+	err := NewLimitViolationError(l.name, limit, fmt.Sprintf(format, args...))
+	l.logger.Warnw("refused a request violating a motion limit", "error", err)
+	if l.report != nil {
+		l.report(err)
+	}
+	return err
+}
+
+// LastVelocity returns the last velocity commanded and how long ago it was, or false if no
+// velocity has been commanded since the resource was last at rest.
+func (l *MotionLimiter) LastVelocity() (linear, angular r3.Vector, elapsed time.Duration, ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.commanded.IsZero() {
+		return r3.Vector{}, r3.Vector{}, 0, false
+	}
+	return l.linear, l.angular, clock.Since(l.commanded), true
+}
+
+// SetVelocity records the velocity commanded.
+func (l *MotionLimiter) SetVelocity(linear, angular r3.Vector) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.linear, l.angular, l.commanded = linear, angular, clock.Now()
+}
+
+// Reset records that the resource is at rest.
+func (l *MotionLimiter) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.linear, l.angular, l.commanded = r3.Vector{}, r3.Vector{}, time.Time{}
+}
+
+// NewLimitViolationError is returned when a request to a resource would break one of its
+// motion limits. Its gRPC status code is OutOfRange.
+func NewLimitViolationError(name Name, limit, detail string) error {
+	return &LimitViolationError{Name: name, Limit: limit, Detail: detail}
+}
+
+// IsLimitViolationError returns whether the given error is a limit violation. Over gRPC, it
+// is returned with the OutOfRange code instead.
+func IsLimitViolationError(err error) bool {
+	var errArt *LimitViolationError
+	return errors.As(err, &errArt)
+}
+
+// A LimitViolationError is a request to a resource that would break one of its motion
+// limits, which is refused.
+type LimitViolationError struct {
+	Name Name
+	// Limit is the name of the limit, such as LimitWorkspace.
+	Limit  string
+	Detail string
+}
+
+func (e *LimitViolationError) Error() string {
+	return fmt.Sprintf("resource %q violates its %s limit: %s", e.Name, e.Limit, e.Detail)
+}
+
+// GRPCStatus returns the status of the error over gRPC.
+func (e *LimitViolationError) GRPCStatus() *status.Status {
+	return status.New(codes.OutOfRange, e.Error())
+}
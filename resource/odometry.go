@@ -0,0 +1,33 @@
+package resource
+
+import (
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+)
+
+// OdometryConfig drives the frame of a component, such as a mobile base, by where a movement
+// sensor reports it is, so that the frames mounted on it, such as that of an arm, move with it
+// through the frame of its parent, such as a map or the world.
+type OdometryConfig struct {
+	// MovementSensor is the name of the movement sensor reporting where the component is, such
+	// as a wheeled odometry sensor.
+	MovementSensor string `json:"movement_sensor"`
+	// OriginLatitude and OriginLongitude are the position the movement sensor reports when the
+	// component is at the pose of its frame config. They default to 0, where odometry starts.
+	OriginLatitude  float64 `json:"origin_latitude,omitempty"`
+	OriginLongitude float64 `json:"origin_longitude,omitempty"`
+}
+
+// Validate returns an error if the odometry cannot drive a frame as configured.
+func (o *OdometryConfig) Validate() error {
+	if o.MovementSensor == "" {
+		return errors.New("a movement sensor is required")
+	}
+	return nil
+}
+
+// Origin returns the position the movement sensor reports when the component is at the pose of
+// its frame config.
+func (o *OdometryConfig) Origin() *geo.Point {
+	return geo.NewPoint(o.OriginLatitude, o.OriginLongitude)
+}
@@ -156,6 +156,63 @@ type Shaped interface {
 	Geometries(context.Context, map[string]interface{}) ([]spatialmath.Geometry, error)
 }
 
+// A Snapshotter is any resource with state that is not described by its config, such as
+// calibration, which can be captured in a robot snapshot and restored later.
+type Snapshotter interface {
+	// Snapshot returns the state of the resource. It must be serializable as JSON.
+	Snapshot(ctx context.Context) (map[string]interface{}, error)
+
+	// Restore replaces the state of the resource with one returned by Snapshot.
+	Restore(ctx context.Context, state map[string]interface{}) error
+}
+
+// A PreStarter is any resource that needs to run a routine, such as a calibration, after it
+// is constructed but before it is available to clients and other resources.
+type PreStarter interface {
+	// PreStart is called after the resource is constructed. If it fails, the resource is
+	// closed and reported as failing to build.
+	PreStart(ctx context.Context) error
+}
+
+// A PostStarter is any resource that needs to run a routine once it is available to clients
+// and other resources.
+type PostStarter interface {
+	// PostStart is called after the resource becomes available. Errors are only logged.
+	PostStart(ctx context.Context) error
+}
+
+// A PreCloser is any resource that needs to run a routine, such as parking, before it is
+// closed.
+type PreCloser interface {
+	// PreClose is called before the resource is closed. Errors are only logged and the
+	// resource is closed regardless.
+	PreClose(ctx context.Context) error
+}
+
+// PreStart calls PreStart on the resource if it is a PreStarter.
+func PreStart(ctx context.Context, res Resource) error {
+	if starter, ok := res.(PreStarter); ok {
+		return starter.PreStart(ctx)
+	}
+	return nil
+}
+
+// PostStart calls PostStart on the resource if it is a PostStarter.
+func PostStart(ctx context.Context, res Resource) error {
+	if starter, ok := res.(PostStarter); ok {
+		return starter.PostStart(ctx)
+	}
+	return nil
+}
+
+// PreClose calls PreClose on the resource if it is a PreCloser.
+func PreClose(ctx context.Context, res Resource) error {
+	if closer, ok := res.(PreCloser); ok {
+		return closer.PreClose(ctx)
+	}
+	return nil
+}
+
 // ErrDoUnimplemented is returned if the DoCommand methods is not implemented.
 var ErrDoUnimplemented = errors.New("DoCommand unimplemented")
 
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/invopop/jsonschema"
 	"github.com/jhump/protoreflect/desc"
 	"github.com/jhump/protoreflect/grpcreflect"
 	"github.com/pkg/errors"
@@ -49,6 +50,26 @@ type (
 	// Results with other types of data are not guaranteed.
 	CreateStatus[ResourceT Resource] func(ctx context.Context, res ResourceT) (interface{}, error)
 
+	// RunSelfTest exercises a resource with checks that are safe to run at any time, such as
+	// reading from it once, returning an error if the resource is not functional.
+	RunSelfTest[ResourceT Resource] func(ctx context.Context, res ResourceT) error
+
+	// ShutdownAction carries out the action configured to be done with a resource before it is
+	// closed when the robot closes, such as moving an arm to a safe pose.
+	ShutdownAction[ResourceT Resource] func(ctx context.Context, res ResourceT, action map[string]interface{}) error
+
+	// A ReadCommandFunc reports whether the DoCommand command only reads the state of a resource
+	// of the API, such as the width of a gripper, so that robots in read-only mode serve it.
+	ReadCommandFunc func(cmd map[string]interface{}) bool
+
+	// A LimitWrapper returns a resource enforcing the motion limits of the given resource with
+	// the limiter, returning a limit violation error from the requests that would break them.
+	LimitWrapper[ResourceT Resource] func(res ResourceT, limiter *MotionLimiter) ResourceT
+
+	// A CalibrateWrapper returns a resource with the kinematic model given by the kinematics,
+	// in place of the model built into the given resource.
+	CalibrateWrapper[ResourceT Resource] func(res ResourceT, kinematics *Kinematics) ResourceT
+
 	// A CreateRPCClient will create the client for the resource.
 	CreateRPCClient[ResourceT Resource] func(
 		ctx context.Context,
@@ -130,6 +151,11 @@ type Registration[ResourceT Resource, ConfigT any] struct {
 	// Discover looks around for information about this specific model.
 	Discover DiscoveryFunc
 
+	// Unimplemented are the names of the methods of the API's RPC service, such as
+	// "ResetZeroPosition", that the model never implements, so that clients can hide the
+	// controls it does not support rather than finding out from their errors.
+	Unimplemented []string
+
 	// configType can be used to dynamically inspect the resource config type.
 	configType reflect.Type
 
@@ -142,9 +168,23 @@ func (r Registration[ResourceT, ConfigT]) ConfigReflectType() reflect.Type {
 	return r.configType
 }
 
+// AttributeSchema returns the JSON Schema of the attributes of the registered model, generated
+// from the json tags of its native config, or nil if it has none.
+func (r Registration[ResourceT, ConfigT]) AttributeSchema() *jsonschema.Schema {
+	if r.configType == nil || r.configType == noNativeConfigType {
+		return nil
+	}
+	return jsonschema.ReflectFromType(r.configType)
+}
+
 // APIRegistration stores api-specific functions and clients.
 type APIRegistration[ResourceT Resource] struct {
 	Status                      CreateStatus[ResourceT]
+	SelfTest                    RunSelfTest[ResourceT]
+	Shutdown                    ShutdownAction[ResourceT]
+	ReadCommand                 ReadCommandFunc
+	Limit                       LimitWrapper[ResourceT]
+	Calibrate                   CalibrateWrapper[ResourceT]
 	RPCServiceServerConstructor func(apiColl APIResourceCollection[ResourceT]) interface{}
 	RPCServiceHandler           rpc.RegisterServiceHandlerFromEndpointFunc
 	RPCServiceDesc              *grpc.ServiceDesc
@@ -300,6 +340,7 @@ func makeGenericResourceRegistration[ResourceT Resource, ConfigT ConfigValidator
 		// NOTE: any fields added to Registration must be copied/adapted here.
 		WeakDependencies: typed.WeakDependencies,
 		Discover:         typed.Discover,
+		Unimplemented:    typed.Unimplemented,
 		isDefault:        typed.isDefault,
 		api:              typed.api,
 		configType:       typed.configType,
@@ -483,6 +524,7 @@ func makeGenericAPIRegistration[ResourceT Resource](
 		RPCServiceHandler:     typed.RPCServiceHandler,
 		ReflectRPCServiceDesc: typed.ReflectRPCServiceDesc,
 		MaxInstance:           typed.MaxInstance,
+		ReadCommand:           typed.ReadCommand,
 		typedVersion:          typed,
 		MakeEmptyCollection: func() APIResourceCollection[Resource] {
 			return genericSubypeCollection[ResourceT]{NewEmptyAPIResourceCollection[ResourceT](api)}
@@ -497,6 +539,42 @@ func makeGenericAPIRegistration[ResourceT Resource](
 			return typed.Status(ctx, typedRes)
 		}
 	}
+	if typed.SelfTest != nil {
+		reg.SelfTest = func(ctx context.Context, res Resource) error {
+			typedRes, err := AsType[ResourceT](res)
+			if err != nil {
+				return err
+			}
+			return typed.SelfTest(ctx, typedRes)
+		}
+	}
+	if typed.Shutdown != nil {
+		reg.Shutdown = func(ctx context.Context, res Resource, action map[string]interface{}) error {
+			typedRes, err := AsType[ResourceT](res)
+			if err != nil {
+				return err
+			}
+			return typed.Shutdown(ctx, typedRes, action)
+		}
+	}
+	if typed.Limit != nil {
+		reg.Limit = func(res Resource, limiter *MotionLimiter) Resource {
+			typedRes, err := AsType[ResourceT](res)
+			if err != nil {
+				return res
+			}
+			return typed.Limit(typedRes, limiter)
+		}
+	}
+	if typed.Calibrate != nil {
+		reg.Calibrate = func(res Resource, kinematics *Kinematics) Resource {
+			typedRes, err := AsType[ResourceT](res)
+			if err != nil {
+				return res
+			}
+			return typed.Calibrate(typedRes, kinematics)
+		}
+	}
 	if typed.RPCServiceServerConstructor != nil {
 		reg.RPCServiceServerConstructor = func(
 			coll APIResourceCollection[Resource],
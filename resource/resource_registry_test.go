@@ -342,3 +342,27 @@ func TestDependencyNotReadyError(t *testing.T) {
       - Because "toe" is not ready yet
         - Because "turf toe"`)
 }
+
+func TestSimulatorRegistry(t *testing.T) {
+	ctx := context.Background()
+	test.That(t, resource.IsSimulation(ctx), test.ShouldBeFalse)
+	test.That(t, resource.IsSimulation(resource.WithSimulation(ctx)), test.ShouldBeTrue)
+
+	model := resource.Model{Name: "x"}
+	_, ok := resource.LookupSimulator(acme.API, model)
+	test.That(t, ok, test.ShouldBeFalse)
+	test.That(t, func() {
+		resource.RegisterSimulator[arm.Arm](acme.API, model, nil)
+	}, test.ShouldPanic)
+	resource.RegisterSimulator(acme.API, model,
+		func(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (arm.Arm, error) {
+			return &fake.Arm{Named: conf.ResourceName().AsNamed()}, nil
+		})
+	defer resource.DeregisterSimulator(acme.API, model)
+
+	simulate, ok := resource.LookupSimulator(acme.API, model)
+	test.That(t, ok, test.ShouldBeTrue)
+	res, err := simulate(ctx, nil, resource.Config{Name: "foo"}, logging.NewTestLogger(t))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res.Name().Name, test.ShouldEqual, "foo")
+}
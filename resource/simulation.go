@@ -0,0 +1,64 @@
+package resource
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+)
+
+type simulationCtxKey struct{}
+
+// WithSimulation returns a context telling the constructors and Reconfigure methods of resources
+// given it that the robot runs in simulation, so that models can substitute simulated backends
+// for their hardware.
+func WithSimulation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, simulationCtxKey{}, true)
+}
+
+// IsSimulation returns whether the robot building or reconfiguring a resource with the given
+// context runs in simulation.
+func IsSimulation(ctx context.Context) bool {
+	simulation, _ := ctx.Value(simulationCtxKey{}).(bool)
+	return simulation
+}
+
+// simulators are the constructors of simulated backends registered for models.
+var simulators = map[APIModel]Create[Resource]{}
+
+// RegisterSimulator registers a constructor of a simulated backend for a model, such as a fake
+// board for a real board model, that a robot running in simulation uses in place of the model's
+// own constructor. The simulated resource is given the config, name and frame of the model's,
+// and is reconfigured with them.
+func RegisterSimulator[ResourceT Resource](api API, model Model, create Create[ResourceT]) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	apiModel := APIModel{api, model}
+	if _, old := simulators[apiModel]; old {
+		panic(errors.Errorf("trying to register two simulators with same api: %q, model: %q", api, model))
+	}
+	if create == nil {
+		panic(errors.Errorf("cannot register a nil simulator for api: %q, model: %q", api, model))
+	}
+	simulators[apiModel] = func(ctx context.Context, deps Dependencies, conf Config, logger logging.Logger) (Resource, error) {
+		return create(ctx, deps, conf, logger)
+	}
+}
+
+// DeregisterSimulator removes a previously registered simulator.
+func DeregisterSimulator(api API, model Model) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(simulators, APIModel{api, model})
+}
+
+// LookupSimulator looks up the constructor of the simulated backend registered for the given
+// api and model.
+func LookupSimulator(api API, model Model) (Create[Resource], bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	create, ok := simulators[APIModel{api, model}]
+	return create, ok
+}
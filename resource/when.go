@@ -0,0 +1,85 @@
+package resource
+
+import (
+	"os"
+	"path"
+	"runtime"
+	"slices"
+
+	"github.com/pkg/errors"
+)
+
+// A When is the condition under which a resource is configured, so that one config can serve
+// a fleet of robots where some lack certain hardware. It is evaluated when a config is read,
+// and resources whose condition does not match the robot are left out of its config.
+//
+// Every field that is set must match. Hostnames and environment variable values are glob
+// patterns, as matched by path.Match.
+type When struct {
+	// OS matches if runtime.GOOS is any of the given values, such as "linux" or "darwin".
+	OS []string `json:"os,omitempty"`
+	// Arch matches if runtime.GOARCH is any of the given values, such as "arm64" or "amd64".
+	Arch []string `json:"arch,omitempty"`
+	// Hostname matches if the hostname of the robot matches any of the given patterns.
+	Hostname []string `json:"hostname,omitempty"`
+	// Env matches if each of the given environment variables is set to a value matching its
+	// pattern.
+	Env map[string]string `json:"env,omitempty"`
+}
+
+// Validate ensures all parts of the condition are valid.
+func (w *When) Validate() error {
+	for _, pattern := range w.Hostname {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid hostname pattern %q", pattern)
+		}
+	}
+	for name, pattern := range w.Env {
+		if name == "" {
+			return errors.New("environment variable name cannot be empty")
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return errors.Wrapf(err, "invalid pattern %q of environment variable %s", pattern, name)
+		}
+	}
+	return nil
+}
+
+// Matches returns whether the condition matches the platform, hostname, and environment
+// the robot runs in. A nil condition always matches.
+func (w *When) Matches() (bool, error) {
+	if w == nil {
+		return true, nil
+	}
+	if err := w.Validate(); err != nil {
+		return false, err
+	}
+	if len(w.OS) != 0 && !slices.Contains(w.OS, runtime.GOOS) {
+		return false, nil
+	}
+	if len(w.Arch) != 0 && !slices.Contains(w.Arch, runtime.GOARCH) {
+		return false, nil
+	}
+	if len(w.Hostname) != 0 {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return false, errors.Wrap(err, "failed to get the hostname")
+		}
+		if !slices.ContainsFunc(w.Hostname, func(pattern string) bool {
+			matched, _ := path.Match(pattern, hostname)
+			return matched
+		}) {
+			return false, nil
+		}
+	}
+	for name, pattern := range w.Env {
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return false, nil
+		}
+		if matched, _ := path.Match(pattern, value); !matched {
+			return false, nil
+		}
+	}
+	return true, nil
+}
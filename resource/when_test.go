@@ -0,0 +1,68 @@
+package resource_test
+
+import (
+	"encoding/json"
+	"os"
+	"runtime"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/resource"
+)
+
+func TestWhenMatches(t *testing.T) {
+	t.Setenv("TEST_FLEET_ROLE", "scout-7")
+	hostname, err := os.Hostname()
+	test.That(t, err, test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name    string
+		when    *resource.When
+		matches bool
+	}{
+		{"nil", nil, true},
+		{"empty", &resource.When{}, true},
+		{"os", &resource.When{OS: []string{"plan9", runtime.GOOS}}, true},
+		{"other os", &resource.When{OS: []string{"plan9"}}, false},
+		{"arch", &resource.When{Arch: []string{runtime.GOARCH}}, true},
+		{"other arch", &resource.When{Arch: []string{"mips"}}, false},
+		{"hostname", &resource.When{Hostname: []string{"nope", hostname}}, true},
+		{"hostname pattern", &resource.When{Hostname: []string{"*"}}, true},
+		{"other hostname", &resource.When{Hostname: []string{hostname + "-other"}}, false},
+		{"env", &resource.When{Env: map[string]string{"TEST_FLEET_ROLE": "scout-*"}}, true},
+		{"other env", &resource.When{Env: map[string]string{"TEST_FLEET_ROLE": "tug-*"}}, false},
+		{"unset env", &resource.When{Env: map[string]string{"TEST_FLEET_UNSET": "*"}}, false},
+		{"all", &resource.When{OS: []string{runtime.GOOS}, Env: map[string]string{"TEST_FLEET_ROLE": "scout-7"}}, true},
+		{"not all", &resource.When{OS: []string{runtime.GOOS}, Arch: []string{"mips"}}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			matches, err := tc.when.Matches()
+			test.That(t, err, test.ShouldBeNil)
+			test.That(t, matches, test.ShouldEqual, tc.matches)
+		})
+	}
+
+	_, err = (&resource.When{Hostname: []string{"["}}).Matches()
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid hostname pattern")
+}
+
+func TestWhenJSON(t *testing.T) {
+	var conf resource.Config
+	err := json.Unmarshal([]byte(`{
+		"name": "imu",
+		"type": "movement_sensor",
+		"model": "fake",
+		"when": {"arch": ["arm64"], "env": {"ROBOT_KIND": "rover"}}
+	}`), &conf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, conf.When, test.ShouldResemble, &resource.When{Arch: []string{"arm64"}, Env: map[string]string{"ROBOT_KIND": "rover"}})
+
+	conf.AdjustPartialNames(resource.APITypeComponentName)
+	data, err := json.Marshal(conf)
+	test.That(t, err, test.ShouldBeNil)
+	var roundTripped resource.Config
+	test.That(t, json.Unmarshal(data, &roundTripped), test.ShouldBeNil)
+	test.That(t, roundTripped.When, test.ShouldResemble, conf.When)
+}
@@ -0,0 +1,186 @@
+// Package camerapose streams the poses of a robot's cameras in the world frame along with
+// their intrinsics, for clients that overlay world geometry on the video of a camera, such as
+// augmented reality and teleoperation interfaces.
+//
+// The pose of a camera is that of its frame in the robot's frame system, given the current
+// inputs of the frame system such as the joint positions of the arm it is mounted on. A
+// camera on a mobile base is located with an odometry movement sensor: its pose is then the
+// pose reported by the sensor, relative to latitude and longitude zero where odometry starts,
+// composed with the pose of the camera relative to the sensor's frame.
+//
+// Samples are taken at the frame rate of the camera's video stream and stamped with the time
+// they were taken, so that a client pairs each video frame with the sample closest in time.
+package camerapose
+
+import (
+	"context"
+	"math"
+	"time"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pion/mediadevices/pkg/prop"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// DefaultRateHz is how many times a second poses are sampled if no rate is given and the
+	// camera does not report its frame rate, which is the frame rate video is streamed at then.
+	DefaultRateHz = 60.0
+	// MaxRateHz is how many times a second poses can be sampled at most.
+	MaxRateHz = 120.0
+)
+
+// A Request describes the poses to stream.
+type Request struct {
+	// Camera is the name of the camera.
+	Camera string `json:"camera"`
+	// Odometry is the name of the movement sensor locating the base the camera is on in the
+	// world, if any.
+	Odometry string `json:"odometry,omitempty"`
+	// RateHz is how many times a second poses are sampled. Zero means the frame rate of the
+	// camera, or DefaultRateHz if it does not report one.
+	RateHz float64 `json:"rate_hz,omitempty"`
+}
+
+// A Sample is the pose of a camera in the world frame at a time.
+type Sample struct {
+	Time time.Time
+	Pose spatialmath.Pose
+	// Intrinsics are the intrinsics of the camera when the stream started, or nil if it does
+	// not report them.
+	Intrinsics *transform.PinholeCameraIntrinsics
+}
+
+// A Service streams the poses of a robot's cameras.
+type Service interface {
+	// StreamPoses returns the poses of the camera sampled from now on, until the context is
+	// done. Samples are dropped while the receiver is behind.
+	StreamPoses(ctx context.Context, req Request) (<-chan Sample, error)
+}
+
+// A Streamer streams the poses of the cameras of a robot.
+type Streamer struct {
+	robot  robot.Robot
+	logger logging.Logger
+}
+
+var _ Service = (*Streamer)(nil)
+
+// NewStreamer returns a streamer of the poses of the cameras of the given robot.
+func NewStreamer(r robot.Robot, logger logging.Logger) *Streamer {
+	return &Streamer{robot: r, logger: logger}
+}
+
+// StreamPoses returns the poses of the camera sampled from now on, until the context is done.
+func (s *Streamer) StreamPoses(ctx context.Context, req Request) (<-chan Sample, error) {
+	if req.RateHz < 0 || req.RateHz > MaxRateHz {
+		return nil, errors.Errorf("rate must be between 0 and %v Hz", MaxRateHz)
+	}
+	cam, err := camera.FromRobot(s.robot, req.Camera)
+	if err != nil {
+		return nil, err
+	}
+	var localizer motion.Localizer
+	if req.Odometry != "" {
+		odometry, err := movementsensor.FromRobot(s.robot, req.Odometry)
+		if err != nil {
+			return nil, err
+		}
+		localizer = motion.NewMovementSensorLocalizer(odometry, geo.NewPoint(0, 0), spatialmath.NewZeroPose())
+	}
+
+	var intrinsics *transform.PinholeCameraIntrinsics
+	if props, err := cam.Properties(ctx); err == nil {
+		intrinsics = props.IntrinsicParams
+	} else {
+		s.logger.CDebugw(ctx, "failed to get camera intrinsics", "camera", req.Camera, "error", err)
+	}
+	rate := req.RateHz
+	if rate == 0 {
+		rate = frameRate(ctx, cam)
+	}
+
+	// the first pose is taken before returning so that a camera not in the frame system
+	// fails the request.
+	first, err := s.pose(ctx, req, localizer)
+	if err != nil {
+		return nil, err
+	}
+	samples := make(chan Sample, 1)
+	samples <- Sample{Time: time.Now(), Pose: first, Intrinsics: intrinsics}
+	utils.PanicCapturingGo(func() {
+		defer close(samples)
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rate))
+		defer ticker.Stop()
+		var failing bool
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			taken := time.Now()
+			pose, err := s.pose(ctx, req, localizer)
+			if err != nil {
+				if !failing && ctx.Err() == nil {
+					s.logger.CWarnw(ctx, "failed to get camera pose; skipping samples until it succeeds", "camera", req.Camera, "error", err)
+				}
+				failing = true
+				continue
+			}
+			failing = false
+			select {
+			case samples <- Sample{Time: taken, Pose: pose, Intrinsics: intrinsics}:
+			default:
+			}
+		}
+	})
+	return samples, nil
+}
+
+// pose returns the pose of the camera in the world frame.
+func (s *Streamer) pose(ctx context.Context, req Request, localizer motion.Localizer) (spatialmath.Pose, error) {
+	origin := referenceframe.NewPoseInFrame(req.Camera, spatialmath.NewZeroPose())
+	if localizer == nil {
+		pif, err := s.robot.TransformPose(ctx, origin, referenceframe.World, nil)
+		if err != nil {
+			return nil, err
+		}
+		return pif.Pose(), nil
+	}
+	located, err := localizer.CurrentPosition(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to locate camera with odometry %q", req.Odometry)
+	}
+	pif, err := s.robot.TransformPose(ctx, origin, req.Odometry, nil)
+	if err != nil {
+		return nil, err
+	}
+	return spatialmath.Compose(located.Pose(), pif.Pose()), nil
+}
+
+// frameRate returns the frame rate of the camera's video, or DefaultRateHz if it does not
+// report one.
+func frameRate(ctx context.Context, cam camera.Camera) float64 {
+	source, ok := cam.(interface {
+		MediaProperties(ctx context.Context) (prop.Video, error)
+	})
+	if !ok {
+		return DefaultRateHz
+	}
+	props, err := source.MediaProperties(ctx)
+	if err != nil || props.FrameRate <= 0 {
+		return DefaultRateHz
+	}
+	return math.Min(float64(props.FrameRate), MaxRateHz)
+}
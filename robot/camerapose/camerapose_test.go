@@ -0,0 +1,142 @@
+package camerapose_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/robot/camerapose"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+var intrinsics = &transform.PinholeCameraIntrinsics{Width: 640, Height: 480, Fx: 500, Fy: 500, Ppx: 320, Ppy: 240}
+
+// newRobot returns a robot with a camera 100mm along x from the odometry sensor of its base,
+// which is itself 50mm along x from the world frame.
+func newRobot(logger logging.Logger) *inject.Robot {
+	cam := inject.NewCamera("cam")
+	cam.PropertiesFunc = func(ctx context.Context) (camera.Properties, error) {
+		return camera.Properties{IntrinsicParams: intrinsics}, nil
+	}
+	odometry := inject.NewMovementSensor("odometry")
+	odometry.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return geo.NewPoint(0, 0), 0, nil
+	}
+	odometry.PropertiesFunc = func(ctx context.Context, extra map[string]interface{}) (*movementsensor.Properties, error) {
+		return &movementsensor.Properties{PositionSupported: true, OrientationSupported: true}, nil
+	}
+	odometry.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90}, nil
+	}
+
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		camera.Named("cam"):              cam,
+		movementsensor.Named("odometry"): odometry,
+	})
+	r.TransformPoseFunc = func(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		if pose.Parent() != "cam" {
+			return nil, errors.Errorf("frame %q not found", pose.Parent())
+		}
+		switch dst {
+		case referenceframe.World:
+			return referenceframe.NewPoseInFrame(dst, spatialmath.NewPoseFromPoint(r3.Vector{X: 150})), nil
+		case "odometry":
+			return referenceframe.NewPoseInFrame(dst, spatialmath.NewPoseFromPoint(r3.Vector{X: 100})), nil
+		default:
+			return nil, errors.Errorf("frame %q not found", dst)
+		}
+	}
+	return r
+}
+
+func TestStreamer(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	streamer := camerapose.NewStreamer(newRobot(logger), logger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the camera is located with the frame system alone.
+	samples, err := streamer.StreamPoses(ctx, camerapose.Request{Camera: "cam", RateHz: 100})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 3; i++ {
+		sample := <-samples
+		test.That(t, sample.Pose.Point(), test.ShouldResemble, r3.Vector{X: 150})
+		test.That(t, sample.Intrinsics, test.ShouldResemble, intrinsics)
+		test.That(t, sample.Time, test.ShouldHappenWithin, time.Second, time.Now())
+	}
+
+	// and on a base, with its odometry: the base is turned a quarter turn left, so the
+	// camera in front of the sensor is along y.
+	samples, err = streamer.StreamPoses(ctx, camerapose.Request{Camera: "cam", Odometry: "odometry", RateHz: 100})
+	test.That(t, err, test.ShouldBeNil)
+	sample := <-samples
+	test.That(t, sample.Pose.Point().X, test.ShouldAlmostEqual, 0)
+	test.That(t, sample.Pose.Point().Y, test.ShouldAlmostEqual, 100)
+
+	// the stream ends with the context.
+	cancel()
+	for range samples {
+	}
+
+	_, err = streamer.StreamPoses(context.Background(), camerapose.Request{Camera: "missing"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = streamer.StreamPoses(context.Background(), camerapose.Request{Camera: "cam", Odometry: "missing"})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = streamer.StreamPoses(context.Background(), camerapose.Request{Camera: "cam", RateHz: camerapose.MaxRateHz + 1})
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	robotClient, err := client.NewInProcess(ctx, newRobot(logger), logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	samples, err := robotClient.CameraPoses().StreamPoses(streamCtx, camerapose.Request{Camera: "cam", Odometry: "odometry"})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 3; i++ {
+		sample := <-samples
+		test.That(t, sample.Pose.Point().X, test.ShouldAlmostEqual, 0)
+		test.That(t, sample.Pose.Point().Y, test.ShouldAlmostEqual, 100)
+		test.That(t, spatialmath.OrientationAlmostEqual(
+			sample.Pose.Orientation(),
+			&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90},
+		), test.ShouldBeTrue)
+		test.That(t, sample.Intrinsics, test.ShouldResemble, intrinsics)
+	}
+	cancel()
+	for range samples {
+	}
+
+	// requests that fail, fail the call.
+	_, err = robotClient.CameraPoses().StreamPoses(ctx, camerapose.Request{Camera: "missing"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not found")
+}
@@ -0,0 +1,134 @@
+package camerapose
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/rimage/transform"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ServiceName is the name of the gRPC service serving a streamer. Its requests and responses
+// are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.camerapose.v1.CameraPoseService"
+
+// poseData is the JSON form of a pose, in millimeters and an orientation vector in degrees.
+type poseData struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Z     float64 `json:"z"`
+	OX    float64 `json:"o_x"`
+	OY    float64 `json:"o_y"`
+	OZ    float64 `json:"o_z"`
+	Theta float64 `json:"theta"`
+}
+
+// sampleData is the JSON form of a Sample.
+type sampleData struct {
+	Time       time.Time                          `json:"time"`
+	Pose       poseData                           `json:"pose"`
+	Intrinsics *transform.PinholeCameraIntrinsics `json:"intrinsics,omitempty"`
+}
+
+func newSampleData(sample Sample) sampleData {
+	pt := sample.Pose.Point()
+	ov := sample.Pose.Orientation().OrientationVectorDegrees()
+	return sampleData{
+		Time:       sample.Time,
+		Pose:       poseData{X: pt.X, Y: pt.Y, Z: pt.Z, OX: ov.OX, OY: ov.OY, OZ: ov.OZ, Theta: ov.Theta},
+		Intrinsics: sample.Intrinsics,
+	}
+}
+
+func (data sampleData) sample() Sample {
+	pose := spatialmath.NewPose(
+		r3.Vector{X: data.Pose.X, Y: data.Pose.Y, Z: data.Pose.Z},
+		&spatialmath.OrientationVectorDegrees{OX: data.Pose.OX, OY: data.Pose.OY, OZ: data.Pose.OZ, Theta: data.Pose.Theta},
+	)
+	return Sample{Time: data.Time, Pose: pose, Intrinsics: data.Intrinsics}
+}
+
+// A Server serves a streamer with ServiceDesc.
+type Server struct {
+	streamer Service
+}
+
+// NewServer returns a server for the given streamer.
+func NewServer(streamer Service) *Server {
+	return &Server{streamer: streamer}
+}
+
+func (s *Server) streamPoses(req Request, stream structrpc.ServerStream) error {
+	ctx := stream.Context()
+	samples, err := s.streamer.StreamPoses(ctx, req)
+	if err != nil {
+		return err
+	}
+	for sample := range samples {
+		if err := stream.Send(newSampleData(sample)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ServiceDesc describes the gRPC service serving a streamer. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/camerapose",
+	structrpc.ServerStreaming("StreamCameraPoses", (*Server).streamPoses),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the streamer served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// StreamPoses returns the poses of the camera sampled from now on, until the context is done
+// or the stream ends.
+func (c *Client) StreamPoses(ctx context.Context, req Request) (<-chan Sample, error) {
+	stream, err := c.client.Stream(ctx, "StreamCameraPoses", req)
+	if err != nil {
+		return nil, err
+	}
+	// the first sample is sent as soon as the stream starts, so that a failed request fails
+	// the call.
+	first, err := recvSample(stream)
+	if err != nil {
+		return nil, err
+	}
+	samples := make(chan Sample, 1)
+	samples <- first
+	go func() {
+		defer close(samples)
+		for {
+			sample, err := recvSample(stream)
+			if err != nil {
+				return
+			}
+			select {
+			case samples <- sample:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return samples, nil
+}
+
+func recvSample(stream structrpc.ClientStream) (Sample, error) {
+	var data sampleData
+	if err := stream.Recv(&data); err != nil {
+		return Sample{}, err
+	}
+	return data.sample(), nil
+}
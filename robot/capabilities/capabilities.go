@@ -0,0 +1,121 @@
+// Package capabilities reports which methods of their APIs the resources of a robot
+// implement, as declared by the registrations of their models, so that client SDKs and UIs
+// hide the controls a resource does not support, such as resetting the zero position of a
+// motor that cannot, rather than finding out from Unimplemented errors.
+package capabilities
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// A Robot is a robot whose resources' capabilities can be reported.
+type Robot interface {
+	robot.Robot
+	Config() *config.Config
+}
+
+// A Request selects the resources whose capabilities are reported.
+type Request struct {
+	// Resources are the full or short names of the resources to report. Every resource is
+	// reported if none are given.
+	Resources []string `json:"resources,omitempty"`
+}
+
+// Capabilities are the methods of its API a resource implements.
+type Capabilities struct {
+	Name string `json:"name"`
+	API  string `json:"api"`
+	// Model is the model of a local resource. It is empty for the resources of remotes, which
+	// are reported as implementing every method of their API.
+	Model string `json:"model,omitempty"`
+	// Supported are the methods of the RPC service of the API that the resource implements,
+	// and Unsupported those it does not, sorted.
+	Supported   []string `json:"supported"`
+	Unsupported []string `json:"unsupported,omitempty"`
+}
+
+// Build returns the capabilities of the resources of the robot selected by the request,
+// sorted by name.
+func Build(r Robot, req Request) ([]Capabilities, error) {
+	names, err := selectResources(r, req.Resources)
+	if err != nil {
+		return nil, err
+	}
+	models := map[resource.Name]resource.Model{}
+	if cfg := r.Config(); cfg != nil {
+		for _, conf := range append(cfg.Components, cfg.Services...) {
+			models[conf.ResourceName()] = conf.Model
+		}
+	}
+
+	caps := make([]Capabilities, 0, len(names))
+	for _, name := range names {
+		resCaps := Capabilities{Name: name.String(), API: name.API.String(), Supported: []string{}}
+		unimplemented := map[string]bool{}
+		if model, ok := models[name]; ok {
+			resCaps.Model = model.String()
+			if reg, ok := resource.LookupRegistration(name.API, model); ok {
+				for _, method := range reg.Unimplemented {
+					unimplemented[method] = true
+				}
+			}
+		}
+		for _, method := range apiMethods(name.API) {
+			if unimplemented[method] {
+				resCaps.Unsupported = append(resCaps.Unsupported, method)
+			} else {
+				resCaps.Supported = append(resCaps.Supported, method)
+			}
+		}
+		caps = append(caps, resCaps)
+	}
+	return caps, nil
+}
+
+// apiMethods returns the methods of the RPC service of the API, sorted.
+func apiMethods(api resource.API) []string {
+	reg, ok := resource.LookupGenericAPIRegistration(api)
+	if !ok || reg.RPCServiceDesc == nil {
+		return nil
+	}
+	methods := make([]string, 0, len(reg.RPCServiceDesc.Methods)+len(reg.RPCServiceDesc.Streams))
+	for _, method := range reg.RPCServiceDesc.Methods {
+		methods = append(methods, method.MethodName)
+	}
+	for _, stream := range reg.RPCServiceDesc.Streams {
+		methods = append(methods, stream.StreamName)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// selectResources returns the names of the resources of the robot with the given full or
+// short names, or of every resource, sorted.
+func selectResources(r robot.Robot, selectors []string) ([]resource.Name, error) {
+	all := r.ResourceNames()
+	names := all
+	if len(selectors) != 0 {
+		names = nil
+		for _, selector := range selectors {
+			matched := false
+			for _, name := range all {
+				if name.String() == selector || name.ShortName() == selector {
+					names = append(names, name)
+					matched = true
+				}
+			}
+			if !matched {
+				return nil, errors.Errorf("no resource named %q", selector)
+			}
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+	return slices.Compact(names), nil
+}
@@ -0,0 +1,79 @@
+package capabilities_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/motor"
+	_ "go.viam.com/rdk/components/motor/fake"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/capabilities"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestCapabilities(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	motor1 := inject.NewMotor("motor1")
+	remoteMotor := inject.NewMotor("remote:motor2")
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		ConfigFunc: func() *config.Config {
+			return &config.Config{Components: []resource.Config{{
+				Name:  "motor1",
+				API:   motor.API,
+				Model: resource.DefaultModelFamily.WithModel("fake"),
+			}}}
+		},
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		motor1.Name():      motor1,
+		remoteMotor.Name(): remoteMotor,
+	})
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	allMethods := []string{
+		"DoCommand", "GetGeometries", "GetPosition", "GetProperties", "GoFor", "GoTo",
+		"IsMoving", "IsPowered", "ResetZeroPosition", "SetPower", "SetRPM", "Stop",
+	}
+
+	caps, err := robotClient.Capabilities().Get(ctx, capabilities.Request{})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, caps, test.ShouldResemble, []capabilities.Capabilities{
+		{
+			Name:  motor1.Name().String(),
+			API:   motor.API.String(),
+			Model: "rdk:builtin:fake",
+			Supported: []string{
+				"DoCommand", "GetGeometries", "GetPosition", "GetProperties", "GoFor", "GoTo",
+				"IsMoving", "IsPowered", "ResetZeroPosition", "SetPower", "Stop",
+			},
+			Unsupported: []string{"SetRPM"},
+		},
+		// the models of the resources of remotes are not known.
+		{Name: remoteMotor.Name().String(), API: motor.API.String(), Supported: allMethods},
+	})
+
+	caps, err = robotClient.Capabilities().Get(ctx, capabilities.Request{Resources: []string{"motor1", motor1.Name().String()}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, caps, test.ShouldHaveLength, 1)
+	test.That(t, caps[0].Name, test.ShouldEqual, motor1.Name().String())
+
+	_, err = robotClient.Capabilities().Get(ctx, capabilities.Request{Resources: []string{"gripper"}})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no resource named "gripper"`)
+}
@@ -0,0 +1,65 @@
+package capabilities
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service reporting the capabilities of a robot's
+// resources. Its requests and responses are google.protobuf.Struct messages holding the JSON
+// form of Request and of the type below.
+const ServiceName = "viam.rdk.capabilities.v1.CapabilitiesService"
+
+// capabilitiesResponse holds the capabilities of the resources selected by a request.
+type capabilitiesResponse struct {
+	Resources []Capabilities `json:"resources"`
+}
+
+// A Server serves the capabilities of a robot's resources with ServiceDesc.
+type Server struct {
+	robot Robot
+}
+
+// NewServer returns a server of the capabilities of the resources of the given robot.
+func NewServer(r Robot) *Server {
+	return &Server{robot: r}
+}
+
+func (s *Server) getCapabilities(ctx context.Context, req Request) (interface{}, error) {
+	caps, err := Build(s.robot, req)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &capabilitiesResponse{Resources: caps}, nil
+}
+
+// ServiceDesc describes the gRPC service reporting the capabilities of a robot's resources.
+// It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/capabilities",
+	structrpc.Unary("GetCapabilities", (*Server).getCapabilities),
+)
+
+// A Client reports the capabilities of the resources of a robot over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the capabilities served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Get returns the capabilities of the resources of the robot selected by the request, sorted
+// by name. Selecting a resource the robot does not have fails with code NotFound.
+func (c *Client) Get(ctx context.Context, req Request) ([]Capabilities, error) {
+	var resp capabilitiesResponse
+	if err := c.client.Invoke(ctx, "GetCapabilities", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Resources, nil
+}
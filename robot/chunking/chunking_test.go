@@ -0,0 +1,153 @@
+package chunking_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/chunking"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/configschema"
+	"go.viam.com/rdk/robot/server"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestChunking(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	// a response of several megabytes, spanning many chunks.
+	large := strings.Repeat("point cloud ", 300000)
+	lidar := inject.NewSensor("lidar")
+	lidar.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		if cmd["fail"] == true {
+			return nil, errors.New("lidar unplugged")
+		}
+		return map[string]interface{}{"cloud": large}, nil
+	}
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{lidar.Name(): lidar})
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	t.Run("large response", func(t *testing.T) {
+		lidarClient, err := sensor.NewClientFromConn(ctx, robotClient.ChunkedConn(), "", lidar.Name(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := lidarClient.DoCommand(ctx, map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["cloud"], test.ShouldEqual, large)
+
+		_, err = lidarClient.DoCommand(ctx, map[string]interface{}{"fail": true})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "lidar unplugged")
+	})
+
+	t.Run("struct services", func(t *testing.T) {
+		schema, err := configschema.NewClient(robotClient.ChunkedConn()).Get(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, schema.ID, test.ShouldEqual, config.SchemaID)
+	})
+
+	t.Run("methods not chunked", func(t *testing.T) {
+		conn := robotClient.ChunkedConn("/viam.component.camera.v1.CameraService/GetImage")
+		lidarClient, err := sensor.NewClientFromConn(ctx, conn, "", lidar.Name(), logger)
+		test.That(t, err, test.ShouldBeNil)
+		resp, err := lidarClient.DoCommand(ctx, map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp["cloud"], test.ShouldEqual, large)
+	})
+
+	t.Run("flow control", func(t *testing.T) {
+		conn := client.NewInProcessConn(r, server.NewInterceptors(r, server.InterceptorOptions{}), logger)
+		defer func() {
+			test.That(t, conn.Close(), test.ShouldBeNil)
+		}()
+		cmd, err := structpb.NewStruct(map[string]interface{}{})
+		test.That(t, err, test.ShouldBeNil)
+		req, err := proto.Marshal(&commonpb.DoCommandRequest{Name: lidar.Name().ShortName(), Command: cmd})
+		test.That(t, err, test.ShouldBeNil)
+
+		streamCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		stream, err := conn.NewStream(
+			streamCtx,
+			&grpc.StreamDesc{ServerStreams: true, ClientStreams: true},
+			"/"+chunking.ServiceName+"/Call",
+		)
+		test.That(t, err, test.ShouldBeNil)
+		first, err := structpb.NewStruct(map[string]interface{}{
+			"method":     "/viam.component.sensor.v1.SensorService/DoCommand",
+			"request":    req,
+			"chunk_size": 1024,
+			"credit":     1,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stream.SendMsg(first), test.ShouldBeNil)
+
+		chunks := make(chan error)
+		go func() {
+			for {
+				err := stream.RecvMsg(&wrapperspb.BytesValue{})
+				chunks <- err
+				if err != nil {
+					return
+				}
+			}
+		}()
+		test.That(t, <-chunks, test.ShouldBeNil)
+
+		// without more credit, the server sends nothing more.
+		select {
+		case err := <-chunks:
+			t.Fatalf("received a chunk without credit: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		credit, err := structpb.NewStruct(map[string]interface{}{"credit": 2})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stream.SendMsg(credit), test.ShouldBeNil)
+		test.That(t, <-chunks, test.ShouldBeNil)
+		test.That(t, <-chunks, test.ShouldBeNil)
+		select {
+		case err := <-chunks:
+			t.Fatalf("received a chunk without credit: %v", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		// canceling the call stops the server from sending the rest.
+		cancel()
+		test.That(t, <-chunks, test.ShouldNotBeNil)
+	})
+
+	t.Run("invalid methods", func(t *testing.T) {
+		conn := robotClient.ChunkedConn()
+		err := conn.Invoke(ctx, "/viam.component.sensor.v1.SensorService/Explode",
+			&commonpb.DoCommandRequest{}, &commonpb.DoCommandResponse{})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "unknown method")
+
+		err = conn.Invoke(ctx, "/"+chunking.ServiceName+"/Call", &commonpb.DoCommandRequest{}, &commonpb.DoCommandResponse{})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "cannot chunk")
+	})
+}
@@ -0,0 +1,325 @@
+// Package chunking serves large unary responses, such as multi-megabyte images, point clouds
+// and maps, in chunks over a bidirectional stream with credit based flow control. Over a
+// WebRTC data channel, a large response sent as a single message holds the channel until it
+// is fully sent, stalling concurrent calls such as those controlling motors. Sent in chunks,
+// which the server only sends as the client grants it credit for, it interleaves with them,
+// and a client that stops reading or cancels stops the server from sending the rest.
+package chunking
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/pion/webrtc/v3"
+	"github.com/pkg/errors"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving unary calls in chunks. Clients send
+// google.protobuf.Struct messages holding the JSON form of the frames below, and the server
+// sends google.protobuf.BytesValue messages holding the chunks of the wire encoding of the
+// response.
+const ServiceName = "viam.rdk.chunking.v1.ChunkingService"
+
+const (
+	// DefaultChunkSize is the size of the chunks responses are sent in when a client does not
+	// ask for a size.
+	DefaultChunkSize = 64 << 10
+	// MaxChunkSize bounds the size of the chunks clients may ask for.
+	MaxChunkSize = 1 << 20
+	// DefaultWindow is how many chunks a Conn lets the server send before it has received
+	// them.
+	DefaultWindow = 4
+)
+
+// frame is a message from a client. The first frame of a call names the method and holds
+// its request, and later ones grant the server credit to send more chunks.
+type frame struct {
+	Method string `json:"method,omitempty"`
+	// Request is the wire encoding of the request.
+	Request   []byte `json:"request,omitempty"`
+	ChunkSize int    `json:"chunk_size,omitempty"`
+	// Credit is how many more chunks the server may send.
+	Credit int `json:"credit,omitempty"`
+}
+
+func sendFrame(stream grpc.Stream, f frame) error {
+	s, err := structrpc.Encode(f)
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(s)
+}
+
+func recvFrame(stream grpc.Stream) (frame, error) {
+	s := &structpb.Struct{}
+	if err := stream.RecvMsg(s); err != nil {
+		return frame{}, err
+	}
+	var f frame
+	if err := structrpc.Decode(s, &f); err != nil {
+		return frame{}, status.Errorf(codes.InvalidArgument, "invalid frame: %v", err)
+	}
+	return f, nil
+}
+
+var streamDesc = grpc.StreamDesc{StreamName: "Call", ServerStreams: true, ClientStreams: true}
+
+// A Server serves unary calls in chunks with ServiceDesc, invoking them over a connection to
+// the services of the robot, such as an in-process one.
+type Server struct {
+	conn grpc.ClientConnInterface
+}
+
+// NewServer returns a server of unary calls in chunks invoking them over the given connection.
+func NewServer(conn grpc.ClientConnInterface) *Server {
+	return &Server{conn: conn}
+}
+
+// serviceServer is the interface served by ServiceDesc.
+type serviceServer interface {
+	call(stream grpc.ServerStream) error
+}
+
+// messages returns new request and response messages of the unary method. Methods of
+// services not in the proto registry, such as those of the robot served with
+// google.protobuf.Struct messages, are assumed to use those.
+func messages(method string) (proto.Message, proto.Message, error) {
+	serviceName, methodName, ok := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	if !ok || serviceName == "" || methodName == "" {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "invalid method %q", method)
+	}
+	if serviceName == ServiceName {
+		return nil, nil, status.Error(codes.InvalidArgument, "cannot chunk calls of the chunking service")
+	}
+	desc, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return &structpb.Struct{}, &structpb.Struct{}, nil
+	}
+	serviceDesc, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "%q is not a service", serviceName)
+	}
+	methodDesc := serviceDesc.Methods().ByName(protoreflect.Name(methodName))
+	if methodDesc == nil {
+		return nil, nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+	if methodDesc.IsStreamingClient() || methodDesc.IsStreamingServer() {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "%s is not a unary method", method)
+	}
+	newMessage := func(desc protoreflect.MessageDescriptor) (proto.Message, error) {
+		typ, err := protoregistry.GlobalTypes.FindMessageByName(desc.FullName())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unknown message %s", desc.FullName())
+		}
+		return typ.New().Interface(), nil
+	}
+	req, err := newMessage(methodDesc.Input())
+	if err != nil {
+		return nil, nil, err
+	}
+	resp, err := newMessage(methodDesc.Output())
+	if err != nil {
+		return nil, nil, err
+	}
+	return req, resp, nil
+}
+
+func (s *Server) call(stream grpc.ServerStream) error {
+	first, err := recvFrame(stream)
+	if err != nil {
+		return err
+	}
+	req, resp, err := messages(first.Method)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(first.Request, req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid request: %v", err)
+	}
+	chunkSize := first.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	chunkSize = min(chunkSize, MaxChunkSize)
+
+	// the call is made with the metadata of the client, as if it were made directly.
+	ctx := stream.Context()
+	md, _ := metadata.FromIncomingContext(ctx)
+	if err := s.conn.Invoke(metadata.NewOutgoingContext(ctx, md.Copy()), first.Method, req, resp); err != nil {
+		return err
+	}
+	data, err := proto.Marshal(resp)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal response: %v", err)
+	}
+
+	// credit is granted by a reader of the frames of the client that never blocks on the
+	// sender, so that a client sending credit while the server sends a chunk cannot deadlock.
+	var mu sync.Mutex
+	credit := first.Credit
+	granted := make(chan struct{}, 1)
+	clientDone := make(chan struct{})
+	go func() {
+		defer close(clientDone)
+		for {
+			f, err := recvFrame(stream)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			credit += f.Credit
+			mu.Unlock()
+			select {
+			case granted <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	hasCredit := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return credit > 0
+	}
+
+	for len(data) != 0 {
+		for !hasCredit() {
+			select {
+			case <-ctx.Done():
+				return status.FromContextError(ctx.Err()).Err()
+			case <-granted:
+			case <-clientDone:
+				if !hasCredit() {
+					return status.Error(codes.Canceled, "client stopped granting credit")
+				}
+			}
+		}
+		n := min(chunkSize, len(data))
+		if err := stream.SendMsg(wrapperspb.Bytes(data[:n])); err != nil {
+			return err
+		}
+		data = data[n:]
+		mu.Lock()
+		credit--
+		mu.Unlock()
+	}
+	return nil
+}
+
+// ServiceDesc describes the gRPC service serving unary calls in chunks. It is served with a
+// Server.
+var ServiceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*serviceServer)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName: streamDesc.StreamName,
+			Handler: func(srv interface{}, stream grpc.ServerStream) error {
+				return srv.(serviceServer).call(stream)
+			},
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "rdk/robot/chunking",
+}
+
+// A Conn is a connection to a robot receiving the responses of unary calls in chunks. Other
+// calls, and calls of methods not chunked, go over the underlying connection as they are. It
+// is used in place of the connection when constructing the clients of resources, such as
+// cameras, whose responses are large.
+type Conn struct {
+	conn    rpc.ClientConn
+	methods map[string]bool
+}
+
+// NewConn returns a connection receiving the responses of the given unary methods (e.g.
+// "/viam.component.camera.v1.CameraService/GetImage") in chunks over the given one, or of
+// every unary method if none are given.
+func NewConn(conn rpc.ClientConn, methods ...string) *Conn {
+	c := &Conn{conn: conn}
+	if len(methods) != 0 {
+		c.methods = make(map[string]bool, len(methods))
+		for _, method := range methods {
+			c.methods[method] = true
+		}
+	}
+	return c
+}
+
+// Invoke makes a unary call, receiving its response in chunks if its method is chunked.
+func (c *Conn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	if c.methods != nil && !c.methods[method] {
+		return c.conn.Invoke(ctx, method, args, reply, opts...)
+	}
+	req, ok := args.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", args)
+	}
+	resp, ok := reply.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", reply)
+	}
+	reqData, err := proto.Marshal(req)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal request: %v", err)
+	}
+
+	// canceling the stream stops the server from sending the rest of the response.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := c.conn.NewStream(ctx, &streamDesc, "/"+ServiceName+"/"+streamDesc.StreamName, opts...)
+	if err != nil {
+		return err
+	}
+	if err := sendFrame(stream, frame{Method: method, Request: reqData, Credit: DefaultWindow}); err != nil {
+		return err
+	}
+	var data []byte
+	for {
+		chunk := &wrapperspb.BytesValue{}
+		if err := stream.RecvMsg(chunk); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return err
+		}
+		data = append(data, chunk.Value...)
+		// the server may have sent its last chunk and ended the call.
+		if err := sendFrame(stream, frame{Credit: 1}); err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+	}
+	if err := proto.Unmarshal(data, resp); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal response: %v", err)
+	}
+	return nil
+}
+
+// NewStream starts a streaming call over the underlying connection.
+func (c *Conn) NewStream(ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return c.conn.NewStream(ctx, desc, method, opts...)
+}
+
+// PeerConn returns the WebRTC peer connection of the underlying connection, if any.
+func (c *Conn) PeerConn() *webrtc.PeerConnection {
+	return c.conn.PeerConn()
+}
+
+// Close does nothing; the underlying connection is closed by its owner.
+func (c *Conn) Close() error {
+	return nil
+}
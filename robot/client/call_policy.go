@@ -0,0 +1,172 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"go.viam.com/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	defaultInitialBackoff = 50 * time.Millisecond
+	defaultMaxBackoff     = time.Second
+)
+
+// idempotentMethodPrefixes are the prefixes of the names of the methods that read state
+// without changing it, which are safe to call more than once.
+var idempotentMethodPrefixes = []string{"Get", "Is", "List", "Read"}
+
+// A CallPolicy describes how the idempotent unary calls made by a client are timed out,
+// retried, and hedged. Calls that may actuate are made once, as they are without a policy.
+type CallPolicy struct {
+	// AttemptTimeout bounds each attempt of a call. Zero means attempts are only bounded by
+	// the call's context.
+	AttemptTimeout time.Duration
+	// MaxAttempts is how many times a call is attempted at most, counting hedged attempts.
+	MaxAttempts int
+	// InitialBackoff is how long to wait before retrying a failed call the first time, doubled
+	// for every retry after up to MaxBackoff. They default to 50ms and 1s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// HedgeDelay, if set, is how long to wait for an attempt before making another alongside
+	// it, using the result of whichever succeeds first.
+	HedgeDelay time.Duration
+	// RetryCodes are the codes of the errors of failed attempts that are retried.
+	RetryCodes []codes.Code
+	// IdempotentMethods names the methods that are idempotent besides those whose names start
+	// with Get, Is, List, or Read.
+	IdempotentMethods []string
+}
+
+// idempotent returns whether the full gRPC method is safe to call more than once.
+func (p *CallPolicy) idempotent(fullMethod string) bool {
+	method := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if slices.Contains(p.IdempotentMethods, method) {
+		return true
+	}
+	for _, prefix := range idempotentMethodPrefixes {
+		if strings.HasPrefix(method, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// attemptResult is the result of an attempt of a call.
+type attemptResult struct {
+	reply interface{}
+	err   error
+}
+
+// unaryClientInterceptor returns an interceptor making the idempotent calls passed to it as
+// the policy describes.
+func (p *CallPolicy) unaryClientInterceptor(logger logging.Logger) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply interface{},
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		if !p.idempotent(method) || (p.MaxAttempts <= 1 && p.AttemptTimeout == 0) {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		replyMsg, ok := reply.(proto.Message)
+		if !ok {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		// each attempt decodes into its own reply, since hedged attempts run concurrently.
+		results := make(chan attemptResult, max(p.MaxAttempts, 1))
+		attempt := func() {
+			attemptCtx, attemptCancel := ctx, context.CancelFunc(func() {})
+			if p.AttemptTimeout != 0 {
+				attemptCtx, attemptCancel = context.WithTimeout(ctx, p.AttemptTimeout)
+			}
+			utils.PanicCapturingGo(func() {
+				defer attemptCancel()
+				attemptReply := replyMsg.ProtoReflect().New().Interface()
+				err := invoker(attemptCtx, method, req, attemptReply, cc, opts...)
+				results <- attemptResult{reply: attemptReply, err: err}
+			})
+		}
+
+		attempts, pending := 1, 1
+		attempt()
+		backoff := p.InitialBackoff
+		if backoff == 0 {
+			backoff = defaultInitialBackoff
+		}
+		maxBackoff := p.MaxBackoff
+		if maxBackoff == 0 {
+			maxBackoff = max(defaultMaxBackoff, backoff)
+		}
+		var hedge <-chan time.Time
+		if p.HedgeDelay != 0 && attempts < p.MaxAttempts {
+			hedge = time.After(p.HedgeDelay)
+		}
+		var retry <-chan time.Time
+		var lastErr error
+		for {
+			select {
+			case <-ctx.Done():
+				if lastErr != nil {
+					return lastErr
+				}
+				return status.FromContextError(ctx.Err()).Err()
+			case <-hedge:
+				hedge = nil
+				if attempts < p.MaxAttempts {
+					logger.CDebugw(ctx, "hedging call", "method", method, "attempt", attempts+1)
+					attempts++
+					pending++
+					attempt()
+					if p.HedgeDelay != 0 && attempts < p.MaxAttempts {
+						hedge = time.After(p.HedgeDelay)
+					}
+				}
+			case <-retry:
+				retry = nil
+				logger.CDebugw(ctx, "retrying call", "method", method, "attempt", attempts+1, "error", lastErr)
+				attempts++
+				pending++
+				attempt()
+				if p.HedgeDelay != 0 && attempts < p.MaxAttempts {
+					hedge = time.After(p.HedgeDelay)
+				}
+			case result := <-results:
+				pending--
+				if result.err == nil {
+					proto.Reset(replyMsg)
+					proto.Merge(replyMsg, result.reply.(proto.Message))
+					return nil
+				}
+				lastErr = result.err
+				if ctx.Err() == nil && !slices.Contains(p.RetryCodes, status.Code(result.err)) {
+					return result.err
+				}
+				if pending != 0 || retry != nil {
+					continue
+				}
+				if attempts >= p.MaxAttempts || ctx.Err() != nil {
+					return result.err
+				}
+				// the retry takes the place of a hedged attempt.
+				hedge = nil
+				retry = time.After(backoff)
+				backoff = min(2*backoff, maxBackoff)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/logging"
+)
+
+func TestCallPolicy(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	policy := CallPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		RetryCodes:     []codes.Code{codes.Unavailable, codes.DeadlineExceeded},
+	}
+
+	// invoke calls the method through the policy, with each attempt handled by the given
+	// function, and returns the error of the call and how many attempts were made.
+	invoke := func(policy CallPolicy, method string, handle func(ctx context.Context, attempt int32) error) (*structpb.Struct, int32, error) {
+		var attempts atomic.Int32
+		reply := &structpb.Struct{}
+		err := policy.unaryClientInterceptor(logger)(
+			context.Background(),
+			method,
+			&structpb.Struct{},
+			reply,
+			nil,
+			func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				attempt := attempts.Add(1)
+				if err := handle(ctx, attempt); err != nil {
+					return err
+				}
+				reply.(*structpb.Struct).Fields = map[string]*structpb.Value{"attempt": structpb.NewNumberValue(float64(attempt))}
+				return nil
+			},
+		)
+		return reply, attempts.Load(), err
+	}
+	unavailable := status.Error(codes.Unavailable, "connection lost")
+
+	t.Run("idempotent calls are retried", func(t *testing.T) {
+		reply, attempts, err := invoke(policy, "/viam.component.arm.v1.ArmService/GetJointPositions",
+			func(ctx context.Context, attempt int32) error {
+				if attempt < 3 {
+					return unavailable
+				}
+				return nil
+			})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, attempts, test.ShouldEqual, 3)
+		test.That(t, reply.Fields["attempt"].GetNumberValue(), test.ShouldEqual, 3)
+
+		_, attempts, err = invoke(policy, "/viam.component.arm.v1.ArmService/GetJointPositions",
+			func(ctx context.Context, attempt int32) error { return unavailable })
+		test.That(t, err, test.ShouldBeError, unavailable)
+		test.That(t, attempts, test.ShouldEqual, 3)
+	})
+
+	t.Run("other calls and errors are not retried", func(t *testing.T) {
+		_, attempts, err := invoke(policy, "/viam.component.arm.v1.ArmService/MoveToPosition",
+			func(ctx context.Context, attempt int32) error { return unavailable })
+		test.That(t, err, test.ShouldBeError, unavailable)
+		test.That(t, attempts, test.ShouldEqual, 1)
+
+		invalid := status.Error(codes.InvalidArgument, "no such arm")
+		_, attempts, err = invoke(policy, "/viam.component.arm.v1.ArmService/GetJointPositions",
+			func(ctx context.Context, attempt int32) error { return invalid })
+		test.That(t, err, test.ShouldBeError, invalid)
+		test.That(t, attempts, test.ShouldEqual, 1)
+
+		// unless declared idempotent.
+		stopPolicy := policy
+		stopPolicy.IdempotentMethods = []string{"Stop"}
+		_, attempts, err = invoke(stopPolicy, "/viam.component.arm.v1.ArmService/Stop",
+			func(ctx context.Context, attempt int32) error { return unavailable })
+		test.That(t, err, test.ShouldBeError, unavailable)
+		test.That(t, attempts, test.ShouldEqual, 3)
+	})
+
+	t.Run("hung attempts time out", func(t *testing.T) {
+		timeoutPolicy := policy
+		timeoutPolicy.AttemptTimeout = 10 * time.Millisecond
+		reply, attempts, err := invoke(timeoutPolicy, "/viam.component.arm.v1.ArmService/GetJointPositions",
+			func(ctx context.Context, attempt int32) error {
+				if attempt == 1 {
+					<-ctx.Done()
+					return status.FromContextError(ctx.Err()).Err()
+				}
+				return nil
+			})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, attempts, test.ShouldEqual, 2)
+		test.That(t, reply.Fields["attempt"].GetNumberValue(), test.ShouldEqual, 2)
+	})
+
+	t.Run("slow attempts are hedged", func(t *testing.T) {
+		hedgePolicy := policy
+		hedgePolicy.MaxAttempts = 2
+		hedgePolicy.HedgeDelay = 10 * time.Millisecond
+		canceled := make(chan struct{})
+		reply, attempts, err := invoke(hedgePolicy, "/viam.component.arm.v1.ArmService/GetJointPositions",
+			func(ctx context.Context, attempt int32) error {
+				if attempt == 1 {
+					<-ctx.Done()
+					close(canceled)
+					return ctx.Err()
+				}
+				return nil
+			})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, attempts, test.ShouldEqual, 2)
+		test.That(t, reply.Fields["attempt"].GetNumberValue(), test.ShouldEqual, 2)
+		// the slow attempt is canceled once the hedged one succeeds.
+		<-canceled
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"maps"
+	"math/rand"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -32,7 +34,10 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.viam.com/rdk/arbitration"
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/cloud"
+	"go.viam.com/rdk/firmware"
 	"go.viam.com/rdk/grpc"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
@@ -41,11 +46,33 @@ import (
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/camerapose"
+	"go.viam.com/rdk/robot/capabilities"
+	"go.viam.com/rdk/robot/chunking"
+	"go.viam.com/rdk/robot/configpatch"
+	"go.viam.com/rdk/robot/configschema"
+	"go.viam.com/rdk/robot/coordination"
+	"go.viam.com/rdk/robot/dashboard"
+	"go.viam.com/rdk/robot/effectiveconfig"
+	"go.viam.com/rdk/robot/events"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/homing"
+	"go.viam.com/rdk/robot/kinematics"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/metadata"
+	"go.viam.com/rdk/robot/mission"
 	"go.viam.com/rdk/robot/packages"
+	"go.viam.com/rdk/robot/profiles"
+	"go.viam.com/rdk/robot/records"
+	"go.viam.com/rdk/robot/selftest"
+	"go.viam.com/rdk/robot/timesync"
+	"go.viam.com/rdk/robot/tools"
+	"go.viam.com/rdk/robot/trajectories"
+	"go.viam.com/rdk/robot/transaction"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/spatialmath"
 	"go.viam.com/rdk/utils/contextutils"
+	"go.viam.com/rdk/watchdog"
 )
 
 var (
@@ -67,9 +94,12 @@ type RobotClient struct {
 	remoteName  string
 	address     string
 	dialOptions []rpc.DialOption
+	// dial, when set, is used to connect instead of dialing address.
+	dial func(ctx context.Context) (rpc.ClientConn, error)
 
 	mu                       sync.RWMutex
 	resourceNames            []resource.Name
+	resourceLabels           map[resource.Name]map[string]string
 	resourceRPCAPIs          []resource.RPCAPI
 	resourceClients          map[resource.Name]resource.Resource
 	remoteNameMap            map[resource.Name]resource.Name
@@ -81,6 +111,9 @@ type RobotClient struct {
 	connected                atomic.Bool
 	rpcSubtypesUnimplemented bool
 
+	stateMu   sync.Mutex
+	connState ConnectionState
+
 	activeBackgroundWorkers sync.WaitGroup
 	backgroundCtx           context.Context
 	backgroundCtxCancel     func()
@@ -99,6 +132,18 @@ type RobotClient struct {
 	heartbeatCtxCancel func()
 }
 
+// ConnectionState is the state of the connection of a client to its robot.
+type ConnectionState struct {
+	Connected bool
+	// Since is when the client last connected or lost its connection.
+	Since time.Time
+	// ReconnectAttempts is how many attempts to reconnect have failed since the client lost
+	// its connection.
+	ReconnectAttempts int
+	// NextReconnect is when the client next tries to reconnect, if it is not connected.
+	NextReconnect time.Time
+}
+
 // RemoteTypeName is the type name used for a remote. This is for internal use.
 const RemoteTypeName = string("remote")
 
@@ -208,7 +253,16 @@ func (rc *RobotClient) handleStreamDisconnect(
 // New constructs a new RobotClient that is served at the given address. The given
 // context can be used to cancel the operation.
 func New(ctx context.Context, address string, clientLogger logging.ZapCompatibleLogger, opts ...RobotClientOption) (*RobotClient, error) {
-	logger := logging.FromZapCompatible(clientLogger)
+	return newRobotClient(ctx, address, nil, logging.FromZapCompatible(clientLogger), opts...)
+}
+
+func newRobotClient(
+	ctx context.Context,
+	address string,
+	dial func(ctx context.Context) (rpc.ClientConn, error),
+	logger logging.Logger,
+	opts ...RobotClientOption,
+) (*RobotClient, error) {
 	var rOpts robotClientOpts
 
 	for _, opt := range opts {
@@ -221,6 +275,7 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 		Named:               resource.NewName(RemoteAPI, rOpts.remoteName).AsNamed(),
 		remoteName:          rOpts.remoteName,
 		address:             address,
+		dial:                dial,
 		backgroundCtx:       backgroundCtx,
 		backgroundCtxCancel: backgroundCtxCancel,
 		logger:              logger,
@@ -237,6 +292,13 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 	rc.dialOptions = append(
 		rc.dialOptions,
 		rpc.WithUnaryClientInterceptor(contextutils.ContextWithMetadataUnaryClientInterceptor),
+	)
+	if rOpts.callPolicy != nil {
+		// each attempt of a call goes through the interceptors below.
+		rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(rOpts.callPolicy.unaryClientInterceptor(logger)))
+	}
+	rc.dialOptions = append(
+		rc.dialOptions,
 		// error handling
 		rpc.WithUnaryClientInterceptor(rc.handleUnaryDisconnect),
 		rpc.WithStreamClientInterceptor(rc.handleStreamDisconnect),
@@ -250,6 +312,9 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 		rpc.WithStreamClientInterceptor(operation.StreamClientInterceptor),
 		rpc.WithUnaryClientInterceptor(logging.UnaryClientInterceptor),
 	)
+	if rOpts.commandSource != nil {
+		rc.dialOptions = append(rc.dialOptions, rpc.WithUnaryClientInterceptor(arbitration.UnaryClientInterceptor(*rOpts.commandSource)))
+	}
 
 	if err := rc.connect(ctx); err != nil {
 		return nil, err
@@ -283,7 +348,7 @@ func New(ctx context.Context, address string, clientLogger logging.ZapCompatible
 		refresh := checkConnectedTime == refreshTime
 		rc.activeBackgroundWorkers.Add(1)
 		utils.ManagedGo(func() {
-			rc.checkConnection(backgroundCtx, checkConnectedTime, reconnectTime, refresh)
+			rc.checkConnection(backgroundCtx, checkConnectedTime, reconnectTime, rOpts.maxReconnectEvery, refresh)
 		}, rc.activeBackgroundWorkers.Done)
 
 		// If checkConnection() is running refresh, there is no need to create a separate
@@ -344,7 +409,13 @@ func (rc *RobotClient) connectWithLock(ctx context.Context) error {
 	if err := rc.conn.Close(); err != nil {
 		return err
 	}
-	conn, err := grpc.Dial(ctx, rc.address, rc.logger, rc.dialOptions...)
+	var conn rpc.ClientConn
+	var err error
+	if rc.dial != nil {
+		conn, err = rc.dial(ctx)
+	} else {
+		conn, err = grpc.Dial(ctx, rc.address, rc.logger, rc.dialOptions...)
+	}
 	if err != nil {
 		return err
 	}
@@ -357,6 +428,7 @@ func (rc *RobotClient) connectWithLock(ctx context.Context) error {
 	rc.client = client
 	rc.refClient = refClient
 	rc.connected.Store(true)
+	rc.setConnected(true)
 	if len(rc.resourceClients) != 0 {
 		if err := rc.updateResources(ctx); err != nil {
 			return err
@@ -390,15 +462,56 @@ func (rc *RobotClient) updateResourceClients(ctx context.Context) error {
 	return nil
 }
 
+// ConnectionState returns the state of the connection of the client to its robot.
+func (rc *RobotClient) ConnectionState() ConnectionState {
+	rc.stateMu.Lock()
+	defer rc.stateMu.Unlock()
+	return rc.connState
+}
+
+func (rc *RobotClient) setConnected(connected bool) {
+	rc.stateMu.Lock()
+	defer rc.stateMu.Unlock()
+	if rc.connState.Since.IsZero() || rc.connState.Connected != connected {
+		rc.connState = ConnectionState{Connected: connected, Since: clock.Now()}
+	}
+}
+
+// reconnectBackoff returns how long to wait before the next attempt to reconnect after the
+// given number of failed attempts. Without a maximum above reconnectEvery, it is always
+// reconnectEvery. Otherwise it doubles with each failure up to the maximum, with jitter so
+// that the clients of a restarted robot do not all reconnect at once.
+func reconnectBackoff(reconnectEvery, maxReconnectEvery time.Duration, failures int) time.Duration {
+	if maxReconnectEvery <= reconnectEvery || failures == 0 {
+		return reconnectEvery
+	}
+	wait := reconnectEvery
+	for i := 0; i < failures && wait < maxReconnectEvery; i++ {
+		wait *= 2
+	}
+	if wait > maxReconnectEvery {
+		wait = maxReconnectEvery
+	}
+	//nolint:gosec
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
 // checkConnection either checks if the client is still connected, or attempts to reconnect to the remote.
-func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnectEvery time.Duration, refresh bool) {
+func (rc *RobotClient) checkConnection(
+	ctx context.Context,
+	checkEvery, reconnectEvery, maxReconnectEvery time.Duration,
+	refresh bool,
+) {
 	for {
 		var waitTime time.Duration
 		if rc.connected.Load() {
 			waitTime = checkEvery
 		} else {
 			if reconnectEvery != 0 {
-				waitTime = reconnectEvery
+				rc.stateMu.Lock()
+				waitTime = reconnectBackoff(reconnectEvery, maxReconnectEvery, rc.connState.ReconnectAttempts)
+				rc.connState.NextReconnect = clock.Now().Add(waitTime)
+				rc.stateMu.Unlock()
 			} else {
 				// if reconnectEvery is unset, we will not attempt to reconnect
 				return
@@ -408,12 +521,18 @@ func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnec
 			return
 		}
 		if !rc.connected.Load() {
-			rc.Logger().CInfow(ctx, "trying to reconnect to remote at address", "address", rc.address)
+			attempt := rc.ConnectionState().ReconnectAttempts + 1
+			rc.Logger().CInfow(ctx, "trying to reconnect to remote at address", "address", rc.address, "attempt", attempt)
 			if err := rc.connect(ctx); err != nil {
-				rc.Logger().CErrorw(ctx, "failed to reconnect remote", "error", err, "address", rc.address)
+				rc.stateMu.Lock()
+				if !rc.connState.Connected {
+					rc.connState.ReconnectAttempts = attempt
+				}
+				rc.stateMu.Unlock()
+				rc.Logger().CErrorw(ctx, "failed to reconnect remote", "error", err, "address", rc.address, "attempt", attempt)
 				continue
 			}
-			rc.Logger().CInfow(ctx, "successfully reconnected remote at address", "address", rc.address)
+			rc.Logger().CInfow(ctx, "successfully reconnected remote at address", "address", rc.address, "attempts", attempt)
 		} else {
 			check := func() error {
 				if refresh {
@@ -451,6 +570,7 @@ func (rc *RobotClient) checkConnection(ctx context.Context, checkEvery, reconnec
 				)
 				rc.mu.Lock()
 				rc.connected.Store(false)
+				rc.setConnected(false)
 				if rc.changeChan != nil {
 					rc.changeChan <- true
 				}
@@ -657,6 +777,14 @@ func (rc *RobotClient) updateResources(ctx context.Context) error {
 	rc.resourceNames = append(rc.resourceNames, names...)
 	rc.resourceRPCAPIs = rpcAPIs
 
+	// robots not serving labels have none.
+	labels, err := rc.Metadata().ResourceLabels(ctx, "")
+	if err != nil {
+		rc.Logger().CDebugw(ctx, "failed to get resource labels", "error", err)
+		labels = nil
+	}
+	rc.resourceLabels = labels
+
 	rc.updateRemoteNameMap()
 
 	return rc.updateResourceClients(ctx)
@@ -726,6 +854,18 @@ func (rc *RobotClient) ResourceNames() []resource.Name {
 	return names
 }
 
+// ResourceLabels returns the labels of the known resources that have any, as of the last
+// refresh.
+func (rc *RobotClient) ResourceLabels() map[resource.Name]map[string]string {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	labels := make(map[resource.Name]map[string]string, len(rc.resourceLabels))
+	for name, resLabels := range rc.resourceLabels {
+		labels[name] = maps.Clone(resLabels)
+	}
+	return labels
+}
+
 // ResourceRPCAPIs returns a list of all known resource APIs.
 func (rc *RobotClient) ResourceRPCAPIs() []resource.RPCAPI {
 	if err := rc.checkConnected(); err != nil {
@@ -891,14 +1031,24 @@ func (rc *RobotClient) Status(ctx context.Context, resourceNames []resource.Name
 		return nil, err
 	}
 
+	resourceRecords, err := rc.Records(ctx, resourceNames)
+	if err != nil {
+		return nil, err
+	}
+
 	statuses := make([]robot.Status, 0, len(resp.Status))
-	for _, status := range resp.Status {
-		statuses = append(
-			statuses, robot.Status{
-				Name:             rprotoutils.ResourceNameFromProto(status.Name),
-				LastReconfigured: status.LastReconfigured.AsTime(),
-				Status:           status.Status.AsMap(),
-			})
+	for _, st := range resp.Status {
+		resourceStatus := robot.Status{
+			Name:             rprotoutils.ResourceNameFromProto(st.Name),
+			LastReconfigured: st.LastReconfigured.AsTime(),
+			Status:           st.Status.AsMap(),
+		}
+		if rec, ok := resourceRecords[resourceStatus.Name]; ok {
+			resourceStatus.LastError = rec.LastError
+			resourceStatus.LastStop = rec.LastStop
+			resourceStatus.SelfTest = rec.SelfTest
+		}
+		statuses = append(statuses, resourceStatus)
 	}
 	return statuses, nil
 }
@@ -991,6 +1141,159 @@ func (rc *RobotClient) RestartModule(ctx context.Context, req robot.RestartModul
 	return nil
 }
 
+// ExecuteTransaction executes a batch of commands to several resources of the robot in one
+// request. See the transaction package for how batches are validated and dispatched.
+func (rc *RobotClient) ExecuteTransaction(ctx context.Context, batch transaction.Batch) ([]transaction.Result, error) {
+	if err := rc.checkConnected(); err != nil {
+		return nil, err
+	}
+	return transaction.Execute(ctx, &rc.conn, batch)
+}
+
+// KV returns the persistent key-value store of the robot.
+func (rc *RobotClient) KV() *kv.Client {
+	return kv.NewClient(&rc.conn)
+}
+
+// Metadata returns the client querying the labels of the robot's resources, which unlike
+// ResourceLabels fetches them from the robot on every call.
+func (rc *RobotClient) Metadata() *metadata.Client {
+	return metadata.NewClient(&rc.conn)
+}
+
+// Records returns the records of the given resources that have any, such as their last
+// errors, or of all resources with records if none are given. Status returns them along with
+// the statuses of the resources. Robots not serving records have none.
+func (rc *RobotClient) Records(ctx context.Context, names []resource.Name) (map[resource.Name]records.Records, error) {
+	recs, err := records.NewClient(&rc.conn).Records(ctx, names)
+	if err != nil && status.Code(err) != codes.Unimplemented {
+		return nil, err
+	}
+	return recs, nil
+}
+
+// Missions returns the queue of tasks of the robot.
+func (rc *RobotClient) Missions() *mission.Client {
+	return mission.NewClient(&rc.conn)
+}
+
+// Coordination returns the coordinator of the locks and barriers shared by the robot and its
+// remotes.
+func (rc *RobotClient) Coordination() *coordination.Client {
+	return coordination.NewClient(&rc.conn)
+}
+
+// Trajectories returns the recorder of the trajectories of the robot's arms.
+func (rc *RobotClient) Trajectories() *trajectories.Client {
+	return trajectories.NewClient(&rc.conn)
+}
+
+// TimeSync returns the clock of the robot, whose offset from the local clock can be measured.
+func (rc *RobotClient) TimeSync() *timesync.Client {
+	return timesync.NewClient(&rc.conn)
+}
+
+// Dashboard returns the dashboard of the robot, which reads the status, readings, and health
+// of its resources and the operations running on it in one round trip.
+func (rc *RobotClient) Dashboard() *dashboard.Client {
+	return dashboard.NewClient(&rc.conn)
+}
+
+// Operations returns the operations running on the robot, which may be canceled.
+func (rc *RobotClient) Operations() *operation.Client {
+	return operation.NewClient(&rc.conn)
+}
+
+// CameraPoses returns the streamer of the poses of the robot's cameras in the world frame.
+func (rc *RobotClient) CameraPoses() *camerapose.Client {
+	return camerapose.NewClient(&rc.conn)
+}
+
+// Capabilities returns the reporter of which methods of their APIs the robot's resources
+// implement.
+func (rc *RobotClient) Capabilities() *capabilities.Client {
+	return capabilities.NewClient(&rc.conn)
+}
+
+// ChunkedConn returns a connection to the robot receiving the responses of the given unary
+// methods, or of every unary method if none are given, in chunks. Clients of resources with
+// large responses, such as cameras, constructed with it do not stall concurrent calls over
+// WebRTC while receiving them.
+func (rc *RobotClient) ChunkedConn(methods ...string) *chunking.Conn {
+	return chunking.NewConn(&rc.conn, methods...)
+}
+
+// ConfigPatcher returns the patcher of the robot's config, which updates the robot without
+// sending its whole config.
+func (rc *RobotClient) ConfigPatcher() *configpatch.Client {
+	return configpatch.NewClient(&rc.conn)
+}
+
+// ConfigSchema returns the JSON Schema of the robot's configs, describing the models it
+// registers.
+func (rc *RobotClient) ConfigSchema() *configschema.Client {
+	return configschema.NewClient(&rc.conn)
+}
+
+// Events returns the bus publishing changes to the state of the robot's resources and
+// remotes.
+func (rc *RobotClient) Events() *events.Client {
+	return events.NewClient(&rc.conn)
+}
+
+// SelfTest returns the self-tests of the robot's components.
+func (rc *RobotClient) SelfTest() *selftest.Client {
+	return selftest.NewClient(&rc.conn)
+}
+
+// Profiles returns the switcher of the robot's runtime profiles, such as power saving ones.
+func (rc *RobotClient) Profiles() *profiles.Client {
+	return profiles.NewClient(&rc.conn)
+}
+
+// Homing returns the keeper of the homing of the robot's components, which forces them to be
+// homed again when their positions cannot be trusted.
+func (rc *RobotClient) Homing() *homing.Client {
+	return homing.NewClient(&rc.conn)
+}
+
+// Watchdog returns the diagnostics of the robot's watchdog, which lists the calls to its
+// resources that exceeded their deadlines without returning.
+func (rc *RobotClient) Watchdog() *watchdog.Client {
+	return watchdog.NewClient(&rc.conn)
+}
+
+// Firmware returns the updater of the firmware of the robot's microcontrollers, which flashes
+// the firmware updates in its config.
+func (rc *RobotClient) Firmware() *firmware.Client {
+	return firmware.NewClient(&rc.conn)
+}
+
+// EffectiveConfig returns the complete config the robot runs with, with defaults applied.
+func (rc *RobotClient) EffectiveConfig(ctx context.Context) (*robot.EffectiveConfig, error) {
+	return effectiveconfig.NewClient(&rc.conn).Get(ctx)
+}
+
+// Tools returns the manager of the tools mounted on the robot's arms.
+func (rc *RobotClient) Tools() *tools.Client {
+	return tools.NewClient(&rc.conn)
+}
+
+// RuntimeFrames returns the frames added to the robot's frame system at runtime.
+func (rc *RobotClient) RuntimeFrames() *framesystem.UpdateClient {
+	return framesystem.NewUpdateClient(&rc.conn)
+}
+
+// Obstacles returns the obstacles of the robot's environment.
+func (rc *RobotClient) Obstacles() *framesystem.ObstacleClient {
+	return framesystem.NewObstacleClient(&rc.conn)
+}
+
+// Kinematics returns the inverse kinematics of the robot's frame system.
+func (rc *RobotClient) Kinematics() *kinematics.Client {
+	return kinematics.NewClient(&rc.conn)
+}
+
 // Shutdown shuts down the robot. May return DeadlineExceeded error if shutdown request times out,
 // or if robot server shuts down before having a chance to send a response. May return Unavailable error
 // if server is unavailable, or if robot server is in the process of shutting down when response is ready.
@@ -3,6 +3,7 @@ package client
 import (
 	"time"
 
+	"go.viam.com/rdk/arbitration"
 	"go.viam.com/utils/rpc"
 )
 
@@ -24,6 +25,10 @@ type robotClientOpts struct {
 	// it will automatically refresh every 1s
 	reconnectEvery *time.Duration
 
+	// maxReconnectEvery, when greater than reconnectEvery, makes the client back off
+	// exponentially up to it while reconnecting fails.
+	maxReconnectEvery time.Duration
+
 	// dialOptions are options using for clients dialing gRPC servers.
 	dialOptions []rpc.DialOption
 
@@ -32,6 +37,12 @@ type robotClientOpts struct {
 
 	// controls whether or not sessions are disabled.
 	disableSessions bool
+
+	// commandSource is declared as the source of every call that does not declare one.
+	commandSource *arbitration.Source
+
+	// callPolicy times out, retries, and hedges idempotent calls.
+	callPolicy *CallPolicy
 }
 
 // RobotClientOption configures how we set up the connection.
@@ -78,6 +89,14 @@ func WithReconnectEvery(reconnectEvery time.Duration) RobotClientOption {
 	})
 }
 
+// WithReconnectBackoff returns a RobotClientOption that makes the client wait exponentially
+// longer between failed attempts to reconnect the robot, up to the given interval.
+func WithReconnectBackoff(maxReconnectEvery time.Duration) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.maxReconnectEvery = maxReconnectEvery
+	})
+}
+
 // WithRemoteName returns a RobotClientOption setting the name of the remote robot.
 func WithRemoteName(remoteName string) RobotClientOption {
 	return newFuncRobotClientOption(func(o *robotClientOpts) {
@@ -92,6 +111,23 @@ func WithDisableSessions() RobotClientOption {
 	})
 }
 
+// WithCommandSource returns a RobotClientOption that declares the given source for the
+// commands sent through the client, so that the robot can arbitrate between them and
+// those of other controllers.
+func WithCommandSource(src arbitration.Source) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.commandSource = &src
+	})
+}
+
+// WithCallPolicy returns a RobotClientOption that times out, retries, and hedges the
+// idempotent calls made through the client as the given policy describes.
+func WithCallPolicy(policy CallPolicy) RobotClientOption {
+	return newFuncRobotClientOption(func(o *robotClientOpts) {
+		o.callPolicy = &policy
+	})
+}
+
 // WithDialOptions returns a RobotClientOption which sets the options for making
 // gRPC connections to other servers.
 func WithDialOptions(opts ...rpc.DialOption) RobotClientOption {
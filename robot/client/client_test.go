@@ -42,7 +42,9 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/reflection"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 
+	"go.viam.com/rdk/arbitration"
 	"go.viam.com/rdk/cloud"
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/base"
@@ -2051,3 +2053,144 @@ func TestShutDown(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, shutdownCalled, test.ShouldBeTrue)
 }
+
+func TestInProcessClient(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var powerSet float64
+	injectMotor := &inject.Motor{
+		SetPowerFunc: func(ctx context.Context, powerPct float64, extra map[string]interface{}) error {
+			powerSet = powerPct
+			return nil
+		},
+		DoFunc: func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+			return cmd, nil
+		},
+	}
+	resources := map[resource.Name]resource.Resource{motor.Named("motor1"): injectMotor}
+	var resourcesMu sync.Mutex
+	stopAllCalled := false
+	injectRobot := &inject.Robot{
+		LoggerFunc: func() logging.Logger { return logger },
+		ResourceNamesFunc: func() []resource.Name {
+			resourcesMu.Lock()
+			defer resourcesMu.Unlock()
+			names := []resource.Name{}
+			for name := range resources {
+				names = append(names, name)
+			}
+			return names
+		},
+		ResourceByNameFunc: func(name resource.Name) (resource.Resource, error) {
+			resourcesMu.Lock()
+			defer resourcesMu.Unlock()
+			res, ok := resources[name]
+			if !ok {
+				return nil, resource.NewNotFoundError(name)
+			}
+			return res, nil
+		},
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		StopAllFunc: func(ctx context.Context, extra map[resource.Name]map[string]interface{}) error {
+			stopAllCalled = true
+			return nil
+		},
+		StatusFunc: func(ctx context.Context, resourceNames []resource.Name) ([]robot.Status, error) {
+			return []robot.Status{}, nil
+		},
+	}
+
+	client, err := NewInProcess(ctx, injectRobot, logger, WithRefreshEvery(0), WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, client.Connected(), test.ShouldBeTrue)
+	test.That(t, client.ResourceNames(), test.ShouldResemble, []resource.Name{motor.Named("motor1")})
+
+	m, err := motor.FromRobot(client, "motor1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, m.SetPower(ctx, 0.5, nil), test.ShouldBeNil)
+	test.That(t, powerSet, test.ShouldEqual, 0.5)
+	resp, err := m.DoCommand(ctx, map[string]interface{}{"foo": "bar"})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+
+	// errors keep their status as they would over the network.
+	injectMotor.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		return status.Error(codes.FailedPrecondition, "motor is stuck")
+	}
+	err = m.Stop(ctx, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "motor is stuck")
+
+	// calls are tracked like those served over the network.
+	total, failed := injectRobot.Metrics().Operations("motor1", "Stop")
+	test.That(t, total, test.ShouldEqual, 1)
+	test.That(t, failed, test.ShouldEqual, 1)
+	lastErr, ok := injectRobot.HealthTracker().LastError("motor1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, lastErr.Error, test.ShouldContainSubstring, "motor is stuck")
+
+	test.That(t, client.StopAll(ctx, nil), test.ShouldBeNil)
+	test.That(t, stopAllCalled, test.ShouldBeTrue)
+
+	// commands from a declared source are arbitrated against those of other clients.
+	operator, err := NewInProcess(ctx, injectRobot, logger, WithRefreshEvery(0), WithCheckConnectedEvery(0),
+		WithCommandSource(arbitration.Source{Name: "operator", Priority: arbitration.PriorityTeleop}))
+	test.That(t, err, test.ShouldBeNil)
+	operatorMotor, err := motor.FromRobot(operator, "motor1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, operatorMotor.SetPower(ctx, 0.25, nil), test.ShouldBeNil)
+	err = m.SetPower(ctx, 0.5, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Aborted)
+	test.That(t, powerSet, test.ShouldEqual, 0.25)
+	test.That(t, operator.Close(ctx), test.ShouldBeNil)
+	test.That(t, injectRobot.Arbiter().Release(motor.Named("motor1"), "operator"), test.ShouldBeNil)
+
+	// streams are served until the client cancels them.
+	streamCtx, cancel := context.WithCancel(ctx)
+	stream, err := pb.NewRobotServiceClient(&client.conn).StreamStatus(streamCtx, &pb.StreamStatusRequest{
+		Every: durationpb.New(time.Millisecond),
+	})
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 3; i++ {
+		_, err := stream.Recv()
+		test.That(t, err, test.ShouldBeNil)
+	}
+	cancel()
+	_, err = stream.Recv()
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Canceled)
+
+	// resources added to the robot are served once the client refreshes.
+	resourcesMu.Lock()
+	resources[motor.Named("motor2")] = &inject.Motor{
+		IsPoweredFunc: func(ctx context.Context, extra map[string]interface{}) (bool, float64, error) {
+			return true, 0.25, nil
+		},
+	}
+	resourcesMu.Unlock()
+	test.That(t, client.Refresh(ctx), test.ShouldBeNil)
+	m2, err := motor.FromRobot(client, "motor2")
+	test.That(t, err, test.ShouldBeNil)
+	on, powerPct, err := m2.IsPowered(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeTrue)
+	test.That(t, powerPct, test.ShouldEqual, 0.25)
+
+	test.That(t, client.Close(ctx), test.ShouldBeNil)
+	err = m.SetPower(ctx, 0.5, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestReconnectBackoff(t *testing.T) {
+	test.That(t, reconnectBackoff(time.Second, 0, 5), test.ShouldEqual, time.Second)
+	test.That(t, reconnectBackoff(time.Second, time.Minute, 0), test.ShouldEqual, time.Second)
+	for failures, expected := range map[int]time.Duration{
+		1:  2 * time.Second,
+		3:  8 * time.Second,
+		10: time.Minute,
+		64: time.Minute,
+	} {
+		wait := reconnectBackoff(time.Second, time.Minute, failures)
+		test.That(t, wait, test.ShouldBeBetweenOrEqual, expected/2, expected)
+	}
+}
@@ -0,0 +1,563 @@
+package client
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/pion/webrtc/v3"
+	"github.com/pkg/errors"
+	pb "go.viam.com/api/robot/v1"
+	"go.viam.com/utils"
+	"go.viam.com/utils/rpc"
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	reflectpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/arbitration"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/server"
+	"go.viam.com/rdk/utils/contextutils"
+)
+
+// NewInProcess constructs a new RobotClient for a robot running in the same process. Calls
+// are handed directly to the robot's gRPC service implementations instead of going over a
+// network connection, so the client behaves like one returned by New while being cheap
+// enough to create in unit tests. Sessions are not supported.
+func NewInProcess(
+	ctx context.Context,
+	r robot.Robot,
+	clientLogger logging.ZapCompatibleLogger,
+	opts ...RobotClientOption,
+) (*RobotClient, error) {
+	logger := logging.FromZapCompatible(clientLogger)
+	var rOpts robotClientOpts
+	for _, opt := range opts {
+		opt.apply(&rOpts)
+	}
+	dial := func(ctx context.Context) (rpc.ClientConn, error) {
+		return newInProcessConn(r, server.NewInterceptors(r, server.InterceptorOptions{}), rOpts.commandSource, logger), nil
+	}
+	return newRobotClient(ctx, "in-process", dial, logger, append(opts, WithDisableSessions())...)
+}
+
+// NewInProcessConn returns a connection serving calls with the gRPC service implementations
+// of a robot running in the same process and the given interceptors, like the connections
+// of clients returned by NewInProcess.
+func NewInProcessConn(r robot.Robot, ints server.Interceptors, logger logging.Logger) rpc.ClientConn {
+	return newInProcessConn(r, ints, nil, logger)
+}
+
+// inProcessMethod is a gRPC method served in process.
+type inProcessMethod struct {
+	srv    interface{}
+	unary  *googlegrpc.MethodDesc
+	stream *googlegrpc.StreamDesc
+
+	// api and coll are set for resource APIs; coll is refreshed from the robot before
+	// every call.
+	api  *resource.API
+	coll resource.APIResourceCollection[resource.Resource]
+}
+
+// inProcessConn is an rpc.ClientConn that serves calls by invoking service
+// implementations directly. Messages are copied through their wire encoding so that
+// neither side can observe changes made by the other.
+type inProcessConn struct {
+	r        robot.Robot
+	methods  map[string]*inProcessMethod
+	services map[string]googlegrpc.ServiceInfo
+
+	unaryClientInt  googlegrpc.UnaryClientInterceptor
+	streamClientInt googlegrpc.StreamClientInterceptor
+	unaryServerInt  googlegrpc.UnaryServerInterceptor
+	streamServerInt googlegrpc.StreamServerInterceptor
+
+	closeCtx context.Context
+	close    func()
+}
+
+func newInProcessConn(
+	r robot.Robot,
+	ints server.Interceptors,
+	commandSource *arbitration.Source,
+	logger logging.Logger,
+) *inProcessConn {
+	closeCtx, closeFn := context.WithCancel(context.Background())
+	c := &inProcessConn{
+		r:               r,
+		methods:         map[string]*inProcessMethod{},
+		services:        map[string]googlegrpc.ServiceInfo{},
+		unaryServerInt:  ints.Unary,
+		streamServerInt: ints.Stream,
+		closeCtx:        closeCtx,
+		close:           closeFn,
+	}
+
+	c.register(&pb.RobotService_ServiceDesc, server.New(r), nil, nil)
+	c.register(&reflectpb.ServerReflection_ServiceDesc, reflection.NewServer(reflection.ServerOptions{Services: c}), nil, nil)
+	for api, reg := range resource.RegisteredAPIs() {
+		if reg.RPCServiceServerConstructor == nil || reg.RPCServiceDesc == nil {
+			continue
+		}
+		api := api
+		coll := reg.MakeEmptyCollection()
+		srv := reg.RPCServiceServerConstructor(coll)
+		if err, ok := srv.(error); ok {
+			logger.Debugw("failed to create in-process service", "api", api, "error", err)
+			continue
+		}
+		c.register(reg.RPCServiceDesc, srv, &api, coll)
+	}
+	// the services are registered as they are on the web server, so registering cannot fail.
+	utils.UncheckedError(server.RegisterServices(r, r.Logger(), ints, c,
+		func(desc *googlegrpc.ServiceDesc, srv interface{}) error {
+			c.register(desc, srv, nil, nil)
+			return nil
+		}))
+
+	// client interceptors mirror those of New.
+	unaryClientInts := []googlegrpc.UnaryClientInterceptor{
+		contextutils.ContextWithMetadataUnaryClientInterceptor,
+		operation.UnaryClientInterceptor,
+		logging.UnaryClientInterceptor,
+	}
+	if commandSource != nil {
+		unaryClientInts = append(unaryClientInts, arbitration.UnaryClientInterceptor(*commandSource))
+	}
+	c.unaryClientInt = grpc_middleware.ChainUnaryClient(unaryClientInts...)
+	c.streamClientInt = operation.StreamClientInterceptor
+	return c
+}
+
+func (c *inProcessConn) register(
+	desc *googlegrpc.ServiceDesc,
+	srv interface{},
+	api *resource.API,
+	coll resource.APIResourceCollection[resource.Resource],
+) {
+	info := googlegrpc.ServiceInfo{Metadata: desc.Metadata}
+	for idx := range desc.Methods {
+		method := &desc.Methods[idx]
+		c.methods["/"+desc.ServiceName+"/"+method.MethodName] = &inProcessMethod{srv: srv, unary: method, api: api, coll: coll}
+		info.Methods = append(info.Methods, googlegrpc.MethodInfo{Name: method.MethodName})
+	}
+	for idx := range desc.Streams {
+		stream := &desc.Streams[idx]
+		c.methods["/"+desc.ServiceName+"/"+stream.StreamName] = &inProcessMethod{srv: srv, stream: stream, api: api, coll: coll}
+		info.Methods = append(info.Methods, googlegrpc.MethodInfo{
+			Name:           stream.StreamName,
+			IsClientStream: stream.ClientStreams,
+			IsServerStream: stream.ServerStreams,
+		})
+	}
+	c.services[desc.ServiceName] = info
+}
+
+// GetServiceInfo returns the services served in process for reflection.
+func (c *inProcessConn) GetServiceInfo() map[string]googlegrpc.ServiceInfo {
+	return c.services
+}
+
+// PeerConn always returns nil since there is no peer.
+func (c *inProcessConn) PeerConn() *webrtc.PeerConnection {
+	return nil
+}
+
+// Close cancels any calls still being served.
+func (c *inProcessConn) Close() error {
+	c.close()
+	return nil
+}
+
+func (c *inProcessConn) lookup(method string, stream bool) (*inProcessMethod, error) {
+	if c.closeCtx.Err() != nil {
+		return nil, status.Error(codes.Canceled, "grpc: the client connection is closing")
+	}
+	m, ok := c.methods[method]
+	if !ok || (m.stream != nil) != stream {
+		return nil, status.Errorf(codes.Unimplemented, "unknown method %s", method)
+	}
+	if m.api == nil {
+		return m, nil
+	}
+
+	resources := map[resource.Name]resource.Resource{}
+	for _, name := range c.r.ResourceNames() {
+		if name.API != *m.api {
+			continue
+		}
+		res, err := c.r.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		resources[name] = res
+	}
+	if err := m.coll.ReplaceAll(resources); err != nil {
+		return nil, status.Convert(err).Err()
+	}
+	return m, nil
+}
+
+// serverContext returns the context a call is served with. Outgoing metadata becomes
+// incoming metadata, as it would over the network.
+func serverContext(ctx context.Context, method string) (context.Context, *inProcessTransportStream) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	ctx = metadata.NewIncomingContext(ctx, md.Copy())
+	ctx = metadata.NewOutgoingContext(ctx, metadata.MD{})
+	ts := &inProcessTransportStream{method: method, headerSent: make(chan struct{})}
+	return googlegrpc.NewContextWithServerTransportStream(ctx, ts), ts
+}
+
+// copyMessage copies src into dst through the wire encoding.
+func copyMessage(dst, src interface{}) error {
+	srcMsg, ok := src.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", src)
+	}
+	dstMsg, ok := dst.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", dst)
+	}
+	data, err := proto.Marshal(srcMsg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal message: %v", err)
+	}
+	if err := proto.Unmarshal(data, dstMsg); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal message: %v", err)
+	}
+	return nil
+}
+
+// Invoke serves a unary call.
+func (c *inProcessConn) Invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	opts ...googlegrpc.CallOption,
+) error {
+	return c.unaryClientInt(ctx, method, args, reply, nil, c.invoke, opts...)
+}
+
+func (c *inProcessConn) invoke(
+	ctx context.Context,
+	method string,
+	args, reply interface{},
+	_ *googlegrpc.ClientConn,
+	opts ...googlegrpc.CallOption,
+) error {
+	m, err := c.lookup(method, false)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(c.closeCtx, cancel)
+	defer stop()
+
+	serverCtx, ts := serverContext(ctx, method)
+	dec := func(in interface{}) error {
+		return copyMessage(in, args)
+	}
+	resp, err := m.unary.Handler(m.srv, serverCtx, dec, c.unaryServerInt)
+	ts.applyCallOptions(opts)
+	if err != nil {
+		return status.Convert(err).Err()
+	}
+	return copyMessage(reply, resp)
+}
+
+// NewStream serves a streaming call. The handler runs until it returns or the call's
+// context is done.
+func (c *inProcessConn) NewStream(
+	ctx context.Context,
+	desc *googlegrpc.StreamDesc,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	return c.streamClientInt(ctx, desc, nil, method, c.newStream, opts...)
+}
+
+func (c *inProcessConn) newStream(
+	ctx context.Context,
+	_ *googlegrpc.StreamDesc,
+	_ *googlegrpc.ClientConn,
+	method string,
+	opts ...googlegrpc.CallOption,
+) (googlegrpc.ClientStream, error) {
+	m, err := c.lookup(method, true)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(c.closeCtx, cancel)
+
+	serverCtx, ts := serverContext(ctx, method)
+	cs := &inProcessClientStream{
+		ctx:      ctx,
+		cancel:   cancel,
+		ts:       ts,
+		opts:     opts,
+		toServer: make(chan []byte),
+		toClient: make(chan []byte),
+		done:     make(chan struct{}),
+	}
+	ss := &inProcessServerStream{ctx: serverCtx, cs: cs}
+	info := &googlegrpc.StreamServerInfo{
+		FullMethod:     method,
+		IsClientStream: m.stream.ClientStreams,
+		IsServerStream: m.stream.ServerStreams,
+	}
+	utils.PanicCapturingGo(func() {
+		var err error
+		defer func() {
+			stop()
+			cs.finish(err)
+		}()
+		err = c.streamServerInt(m.srv, ss, info, m.stream.Handler)
+	})
+	return cs, nil
+}
+
+// inProcessTransportStream records the headers and trailers set by a handler.
+type inProcessTransportStream struct {
+	method string
+
+	mu         sync.Mutex
+	header     metadata.MD
+	trailer    metadata.MD
+	headerSent chan struct{}
+	sent       bool
+}
+
+func (ts *inProcessTransportStream) Method() string {
+	return ts.method
+}
+
+func (ts *inProcessTransportStream) SetHeader(md metadata.MD) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.sent {
+		return errors.New("transport: the stream is done or WriteHeader was already called")
+	}
+	ts.header = metadata.Join(ts.header, md)
+	return nil
+}
+
+func (ts *inProcessTransportStream) SendHeader(md metadata.MD) error {
+	if err := ts.SetHeader(md); err != nil {
+		return err
+	}
+	ts.sendHeader()
+	return nil
+}
+
+func (ts *inProcessTransportStream) SetTrailer(md metadata.MD) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	ts.trailer = metadata.Join(ts.trailer, md)
+	return nil
+}
+
+func (ts *inProcessTransportStream) sendHeader() {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if !ts.sent {
+		ts.sent = true
+		close(ts.headerSent)
+	}
+}
+
+func (ts *inProcessTransportStream) applyCallOptions(opts []googlegrpc.CallOption) {
+	ts.sendHeader()
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case googlegrpc.HeaderCallOption:
+			*o.HeaderAddr = ts.header.Copy()
+		case googlegrpc.TrailerCallOption:
+			*o.TrailerAddr = ts.trailer.Copy()
+		}
+	}
+}
+
+// inProcessClientStream is the client side of an in-process stream. Messages are passed
+// over unbuffered channels so that every message sent by a handler is received before
+// the handler's result.
+type inProcessClientStream struct {
+	ctx    context.Context
+	cancel func()
+	ts     *inProcessTransportStream
+	opts   []googlegrpc.CallOption
+
+	sendMu     sync.Mutex
+	sendClosed bool
+	toServer   chan []byte
+	toClient   chan []byte
+
+	done chan struct{}
+	err  error // set before done is closed
+}
+
+func (cs *inProcessClientStream) finish(err error) {
+	if err != nil {
+		err = status.Convert(err).Err()
+	}
+	cs.ts.applyCallOptions(cs.opts)
+	cs.err = err
+	close(cs.done)
+	cs.cancel()
+}
+
+// result returns the result of the handler, preferring it to the call being canceled.
+func (cs *inProcessClientStream) result() error {
+	select {
+	case <-cs.done:
+		if cs.err == nil {
+			return io.EOF
+		}
+		return cs.err
+	default:
+		return status.FromContextError(cs.ctx.Err()).Err()
+	}
+}
+
+func (cs *inProcessClientStream) Header() (metadata.MD, error) {
+	select {
+	case <-cs.ts.headerSent:
+	case <-cs.done:
+	case <-cs.ctx.Done():
+	}
+	cs.ts.mu.Lock()
+	defer cs.ts.mu.Unlock()
+	return cs.ts.header.Copy(), nil
+}
+
+func (cs *inProcessClientStream) Trailer() metadata.MD {
+	cs.ts.mu.Lock()
+	defer cs.ts.mu.Unlock()
+	return cs.ts.trailer.Copy()
+}
+
+func (cs *inProcessClientStream) CloseSend() error {
+	cs.sendMu.Lock()
+	defer cs.sendMu.Unlock()
+	if !cs.sendClosed {
+		cs.sendClosed = true
+		close(cs.toServer)
+	}
+	return nil
+}
+
+func (cs *inProcessClientStream) Context() context.Context {
+	return cs.ctx
+}
+
+func (cs *inProcessClientStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", m)
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal message: %v", err)
+	}
+
+	cs.sendMu.Lock()
+	defer cs.sendMu.Unlock()
+	if cs.sendClosed {
+		return errors.New("SendMsg called after CloseSend")
+	}
+	select {
+	case cs.toServer <- data:
+		return nil
+	case <-cs.done:
+		// like gRPC, the status of the call is returned by RecvMsg.
+		return io.EOF
+	case <-cs.ctx.Done():
+		return cs.result()
+	}
+}
+
+func (cs *inProcessClientStream) RecvMsg(m interface{}) error {
+	select {
+	case data := <-cs.toClient:
+		return unmarshalStreamMessage(data, m)
+	case <-cs.done:
+		return cs.result()
+	case <-cs.ctx.Done():
+		return cs.result()
+	}
+}
+
+func unmarshalStreamMessage(data []byte, m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", m)
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return status.Errorf(codes.Internal, "failed to unmarshal message: %v", err)
+	}
+	return nil
+}
+
+// inProcessServerStream is the server side of an in-process stream.
+type inProcessServerStream struct {
+	ctx context.Context
+	cs  *inProcessClientStream
+}
+
+func (ss *inProcessServerStream) SetHeader(md metadata.MD) error {
+	return ss.cs.ts.SetHeader(md)
+}
+
+func (ss *inProcessServerStream) SendHeader(md metadata.MD) error {
+	return ss.cs.ts.SendHeader(md)
+}
+
+func (ss *inProcessServerStream) SetTrailer(md metadata.MD) {
+	utils.UncheckedError(ss.cs.ts.SetTrailer(md))
+}
+
+func (ss *inProcessServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+func (ss *inProcessServerStream) SendMsg(m interface{}) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return status.Errorf(codes.Internal, "expected proto.Message but got %T", m)
+	}
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to marshal message: %v", err)
+	}
+	ss.cs.ts.sendHeader()
+	select {
+	case ss.cs.toClient <- data:
+		return nil
+	case <-ss.ctx.Done():
+		return status.FromContextError(ss.ctx.Err()).Err()
+	}
+}
+
+func (ss *inProcessServerStream) RecvMsg(m interface{}) error {
+	select {
+	case data, ok := <-ss.cs.toServer:
+		if !ok {
+			return io.EOF
+		}
+		return unmarshalStreamMessage(data, m)
+	case <-ss.ctx.Done():
+		return status.FromContextError(ss.ctx.Err()).Err()
+	}
+}
@@ -0,0 +1,30 @@
+// Package configpatch serves the patching of a robot's config, for fleet managers that update
+// robots incrementally rather than by sending their whole configs.
+//
+// A patch adds, removes, and replaces components and services, and sets or removes their
+// attributes, as described by config.Patch. It is validated against the robot's current config
+// and applied through the robot's usual reconfiguration, so a patch that fails to apply or
+// introduces errors leaves the robot as it is. Applied patches are logged by the robot, and
+// the components and services of its config once patched are returned, so that a fleet
+// manager records what each robot is configured with.
+package configpatch
+
+import (
+	"context"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/robot"
+)
+
+// A Service patches the config of a robot.
+type Service interface {
+	// PatchConfig applies the patch to the robot's config and returns the robot's config
+	// once reconfigured.
+	PatchConfig(ctx context.Context, patch config.Patch) (*config.Config, error)
+}
+
+// A Robot is a robot whose config can be patched.
+type Robot interface {
+	robot.Robot
+	Service
+}
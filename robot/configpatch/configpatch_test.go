@@ -0,0 +1,70 @@
+package configpatch_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/testutils/inject"
+	"go.viam.com/rdk/utils"
+)
+
+// patchableRobot is a robot whose config is the given components, patched with
+// config.Config.ApplyPatch.
+type patchableRobot struct {
+	*inject.Robot
+	cfg *config.Config
+}
+
+func (r *patchableRobot) PatchConfig(ctx context.Context, patch config.Patch) (*config.Config, error) {
+	patched, err := r.cfg.ApplyPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	r.cfg = patched
+	return patched, nil
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &patchableRobot{
+		Robot: &inject.Robot{
+			LoggerFunc:          func() logging.Logger { return logger },
+			ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		},
+		cfg: &config.Config{Components: []resource.Config{
+			{Name: "motor1", API: motor.API, Model: resource.DefaultModelFamily.WithModel("fake")},
+		}},
+	}
+	r.MockResourcesFromMap(nil)
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	patched, err := robotClient.ConfigPatcher().PatchConfig(ctx, config.Patch{
+		Components: config.ResourcePatch{Attributes: map[string]utils.AttributeMap{"motor1": {"max_rpm": 100}}},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, patched.Components, test.ShouldHaveLength, 1)
+	test.That(t, patched.Components[0].Name, test.ShouldEqual, "motor1")
+	test.That(t, patched.Components[0].API, test.ShouldResemble, motor.API)
+	test.That(t, patched.Components[0].Attributes, test.ShouldResemble, utils.AttributeMap{"max_rpm": 100.0})
+
+	// patches that are rejected fail with InvalidArgument.
+	_, err = robotClient.ConfigPatcher().PatchConfig(ctx, config.Patch{
+		Components: config.ResourcePatch{Remove: []string{"motor2"}},
+	})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `cannot remove "motor2"`)
+}
@@ -0,0 +1,74 @@
+package configpatch
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/resource"
+)
+
+// ServiceName is the name of the gRPC service serving config patches. Its requests are
+// google.protobuf.Struct messages holding the JSON form of a config.Patch, and its responses
+// hold the JSON form of the type below.
+const ServiceName = "viam.rdk.configpatch.v1.ConfigPatchService"
+
+// patchResponse is the part of a config a patch changes.
+type patchResponse struct {
+	Components []resource.Config `json:"components"`
+	Services   []resource.Config `json:"services"`
+}
+
+// A Server serves config patches with ServiceDesc.
+type Server struct {
+	patcher Service
+}
+
+// NewServer returns a server for the given patcher.
+func NewServer(patcher Service) *Server {
+	return &Server{patcher: patcher}
+}
+
+func (s *Server) patchConfig(ctx context.Context, patch config.Patch) (interface{}, error) {
+	cfg, err := s.patcher.PatchConfig(ctx, patch)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	cfg = cfg.WithSecretReferences()
+	return &patchResponse{Components: cfg.Components, Services: cfg.Services}, nil
+}
+
+// ServiceDesc describes the gRPC service serving config patches. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/configpatch",
+	structrpc.Unary("PatchConfig", (*Server).patchConfig),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the config patches served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// PatchConfig applies the patch to the robot's config and returns the components and
+// services of the robot's config once reconfigured. A patch that does not apply or makes the
+// config invalid fails with code InvalidArgument.
+func (c *Client) PatchConfig(ctx context.Context, patch config.Patch) (*config.Config, error) {
+	var resp patchResponse
+	if err := c.client.Invoke(ctx, "PatchConfig", patch, &resp); err != nil {
+		return nil, err
+	}
+	return &config.Config{Components: resp.Components, Services: resp.Services}, nil
+}
@@ -0,0 +1,37 @@
+package configschema_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	_ "go.viam.com/rdk/components/motor/fake"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestGetConfigSchema(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceNamesFunc:   func() []resource.Name { return nil },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	schema, err := robotClient.ConfigSchema().Get(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(schema.ID), test.ShouldEqual, config.SchemaID)
+	test.That(t, schema.Definitions, test.ShouldContainKey, "resource")
+	test.That(t, schema.Definitions, test.ShouldContainKey, "go.viam.com.rdk.components.motor.fake.config")
+	test.That(t, len(schema.Definitions["resource"].AllOf), test.ShouldEqual, len(config.Schema().Definitions["resource"].AllOf))
+}
@@ -0,0 +1,57 @@
+// Package configschema serves the JSON Schema of robot configs, as returned by config.Schema,
+// for editors to validate and complete the configs of a robot with the models it registers.
+package configschema
+
+import (
+	"context"
+
+	"github.com/invopop/jsonschema"
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving the JSON Schema of robot configs. Its
+// requests are empty google.protobuf.Struct messages, and its responses hold the schema.
+const ServiceName = "viam.rdk.configschema.v1.ConfigSchemaService"
+
+// A Server serves the JSON Schema of robot configs with ServiceDesc, describing the models
+// registered in its process.
+type Server struct{}
+
+// NewServer returns a server of the JSON Schema of robot configs.
+func NewServer() *Server {
+	return &Server{}
+}
+
+func (s *Server) getConfigSchema(ctx context.Context, _ struct{}) (interface{}, error) {
+	return config.Schema(), nil
+}
+
+// ServiceDesc describes the gRPC service serving the JSON Schema of robot configs. It is
+// served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/configschema",
+	structrpc.Unary("GetConfigSchema", (*Server).getConfigSchema),
+)
+
+// A Client is the JSON Schema of robot configs served over a connection to a robot.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the JSON Schema of robot configs served over the given
+// connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Get returns the JSON Schema of the configs of the robot, describing the models it
+// registers.
+func (c *Client) Get(ctx context.Context) (*jsonschema.Schema, error) {
+	var schema jsonschema.Schema
+	if err := c.client.Invoke(ctx, "GetConfigSchema", struct{}{}, &schema); err != nil {
+		return nil, err
+	}
+	return &schema, nil
+}
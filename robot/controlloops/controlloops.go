@@ -0,0 +1,173 @@
+// Package controlloops hosts the control loops of a robot, each running its blocks at a
+// configured frequency in dedicated goroutines to drive a motor or a base from its sensors,
+// and monitors how regularly they run.
+package controlloops
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/control"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/utils"
+)
+
+// Controlled is what a control loop drives and reads its state from. Controlled values are
+// compared to tell whether the resources a loop drives were rebuilt, so they should hold
+// the resources themselves.
+type Controlled interface {
+	control.Controllable
+	// Stop stops the driven resource once its loop stops.
+	Stop(ctx context.Context, extra map[string]interface{}) error
+}
+
+// Status describes a control loop of the robot.
+type Status struct {
+	// Name is the name of the loop.
+	Name string `json:"name"`
+	// Running is whether the loop is running.
+	Running bool `json:"running"`
+	// Error is why the loop is not running, if it is not.
+	Error string `json:"error,omitempty"`
+	// Stats describes how regularly the loop has been running at its frequency.
+	Stats control.LoopStats `json:"stats"`
+}
+
+type hostedLoop struct {
+	conf       config.ControlLoopConfig
+	controlled Controlled
+	loop       *control.Loop
+	err        error
+}
+
+// A Robot is a robot running control loops.
+type Robot interface {
+	// ControlLoops returns the host of the control loops configured for the robot.
+	ControlLoops() *Host
+}
+
+// A Host runs the control loops of a robot.
+type Host struct {
+	logger     logging.Logger
+	controlled func(ctx context.Context, conf config.ControlLoopConfig) (Controlled, error)
+
+	mu    sync.Mutex
+	loops map[string]*hostedLoop
+}
+
+// NewHost returns a host of control loops driving what controlled returns for their config.
+func NewHost(
+	logger logging.Logger,
+	controlled func(ctx context.Context, conf config.ControlLoopConfig) (Controlled, error),
+) *Host {
+	return &Host{logger: logger, controlled: controlled, loops: map[string]*hostedLoop{}}
+}
+
+// Reconfigure runs the given control loops. Loops no longer configured are stopped, and
+// loops whose config changed, whose driven resources were rebuilt, or which failed to start
+// are restarted.
+func (h *Host) Reconfigure(ctx context.Context, confs []config.ControlLoopConfig) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	configured := make(map[string]struct{}, len(confs))
+	for _, conf := range confs {
+		configured[conf.Name] = struct{}{}
+		controlled, err := h.controlled(ctx, conf)
+		if hosted, ok := h.loops[conf.Name]; ok {
+			if hosted.err == nil && err == nil && hosted.controlled == controlled && reflect.DeepEqual(hosted.conf, conf) {
+				continue
+			}
+			h.stop(ctx, hosted)
+		}
+		h.loops[conf.Name] = h.start(conf, controlled, err)
+	}
+	for name, hosted := range h.loops {
+		if _, ok := configured[name]; !ok {
+			h.stop(ctx, hosted)
+			delete(h.loops, name)
+		}
+	}
+}
+
+func (h *Host) start(conf config.ControlLoopConfig, controlled Controlled, err error) *hostedLoop {
+	hosted := &hostedLoop{conf: conf, controlled: controlled, err: err}
+	if err != nil {
+		h.logger.Warnw("cannot start control loop", "name", conf.Name, "error", err)
+		return hosted
+	}
+	hosted.loop, hosted.err = control.NewLoop(h.logger.Sublogger(conf.Name), loopConfig(conf), controlled)
+	if hosted.err == nil {
+		hosted.err = hosted.loop.Start()
+	}
+	if hosted.err != nil {
+		h.logger.Warnw("cannot start control loop", "name", conf.Name, "error", hosted.err)
+		hosted.loop = nil
+	}
+	return hosted
+}
+
+// loopConfig returns the config of the loop for conf, naming the driven resource in its
+// endpoint block if it does not already.
+func loopConfig(conf config.ControlLoopConfig) control.Config {
+	blocks := make([]control.BlockConfig, 0, len(conf.Blocks))
+	for _, block := range conf.Blocks {
+		if string(block.Type) == "endpoint" && !block.Attribute.Has("motor_name") && !block.Attribute.Has("base_name") {
+			attrs := utils.AttributeMap{}
+			for k, v := range block.Attribute {
+				attrs[k] = v
+			}
+			if conf.Motor != "" {
+				attrs["motor_name"] = conf.Motor
+			} else {
+				attrs["base_name"] = conf.Base
+			}
+			block.Attribute = attrs
+		}
+		blocks = append(blocks, block)
+	}
+	return control.Config{Blocks: blocks, Frequency: conf.Frequency}
+}
+
+func (h *Host) stop(ctx context.Context, hosted *hostedLoop) {
+	if hosted.loop == nil {
+		return
+	}
+	hosted.loop.Stop()
+	if err := hosted.controlled.Stop(ctx, nil); err != nil {
+		h.logger.Warnw("error stopping resource driven by control loop", "name", hosted.conf.Name, "error", err)
+	}
+}
+
+// Status returns the status of each control loop, sorted by name.
+func (h *Host) Status() []Status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	statuses := make([]Status, 0, len(h.loops))
+	for name, hosted := range h.loops {
+		status := Status{Name: name}
+		if hosted.loop != nil {
+			status.Running = true
+			status.Stats = hosted.loop.Stats()
+		}
+		if hosted.err != nil {
+			status.Error = hosted.err.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// Close stops every control loop.
+func (h *Host) Close(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for name, hosted := range h.loops {
+		h.stop(ctx, hosted)
+		delete(h.loops, name)
+	}
+}
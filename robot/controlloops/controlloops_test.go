@@ -0,0 +1,131 @@
+package controlloops_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/control"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/controlloops"
+	"go.viam.com/rdk/utils"
+)
+
+// fakeMotor holds the position it is driven to with the power it is set to.
+type fakeMotor struct {
+	mu       sync.Mutex
+	position float64
+	power    float64
+	stops    int
+}
+
+func (m *fakeMotor) SetState(ctx context.Context, state []*control.Signal) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.power = state[0].GetSignalValueAt(0)
+	m.position += m.power
+	return nil
+}
+
+func (m *fakeMotor) State(ctx context.Context) ([]float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return []float64{m.position}, nil
+}
+
+func (m *fakeMotor) Stop(ctx context.Context, extra map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stops++
+	return nil
+}
+
+func (m *fakeMotor) String() string {
+	return "fake motor"
+}
+
+func (m *fakeMotor) state() (position float64, stops int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.position, m.stops
+}
+
+// holdConfig drives the motor to the setpoint with a PID.
+func holdConfig(setpoint float64) config.ControlLoopConfig {
+	return config.ControlLoopConfig{
+		Name:      "hold",
+		Frequency: 100,
+		Motor:     "m1",
+		Encoder:   "e1",
+		Blocks: []control.BlockConfig{
+			{Name: "setpoint", Type: "constant", Attribute: utils.AttributeMap{"constant_val": setpoint}},
+			{Name: "error", Type: "sum", Attribute: utils.AttributeMap{"sum_string": "+-"}, DependsOn: []string{"setpoint", "m1"}},
+			{
+				Name:      "PID",
+				Type:      "PID",
+				Attribute: utils.AttributeMap{"kP": 0.5, "kI": 0.0, "kD": 0.0},
+				DependsOn: []string{"error"},
+			},
+			{Name: "m1", Type: "endpoint", DependsOn: []string{"PID"}},
+		},
+	}
+}
+
+func TestHost(t *testing.T) {
+	ctx := context.Background()
+	motor := &fakeMotor{}
+	var available bool
+	host := controlloops.NewHost(logging.NewTestLogger(t),
+		func(ctx context.Context, conf config.ControlLoopConfig) (controlloops.Controlled, error) {
+			if !available {
+				return nil, errors.New("motor m1 not found")
+			}
+			return motor, nil
+		})
+	defer host.Close(ctx)
+
+	// loops whose resources are unavailable are retried on the next reconfiguration.
+	host.Reconfigure(ctx, []config.ControlLoopConfig{holdConfig(10)})
+	statuses := host.Status()
+	test.That(t, statuses, test.ShouldHaveLength, 1)
+	test.That(t, statuses[0].Running, test.ShouldBeFalse)
+	test.That(t, statuses[0].Error, test.ShouldContainSubstring, "not found")
+
+	available = true
+	host.Reconfigure(ctx, []config.ControlLoopConfig{holdConfig(10)})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		position, _ := motor.state()
+		test.That(tb, position, test.ShouldAlmostEqual, 10, 0.1)
+	})
+	statuses = host.Status()
+	test.That(t, statuses[0].Running, test.ShouldBeTrue)
+	test.That(t, statuses[0].Error, test.ShouldBeEmpty)
+	test.That(t, statuses[0].Stats.Ticks, test.ShouldBeGreaterThan, 0)
+
+	// an unchanged loop keeps running, and a changed loop is restarted.
+	host.Reconfigure(ctx, []config.ControlLoopConfig{holdConfig(10)})
+	_, stops := motor.state()
+	test.That(t, stops, test.ShouldEqual, 0)
+	host.Reconfigure(ctx, []config.ControlLoopConfig{holdConfig(20)})
+	_, stops = motor.state()
+	test.That(t, stops, test.ShouldEqual, 1)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		position, _ := motor.state()
+		test.That(tb, position, test.ShouldAlmostEqual, 20, 0.1)
+	})
+
+	// removed loops stop and stop their motor.
+	host.Reconfigure(ctx, nil)
+	test.That(t, host.Status(), test.ShouldBeEmpty)
+	position, stops := motor.state()
+	test.That(t, stops, test.ShouldEqual, 2)
+	time.Sleep(50 * time.Millisecond)
+	stopped, _ := motor.state()
+	test.That(t, stopped, test.ShouldEqual, position)
+}
@@ -0,0 +1,301 @@
+// Package coordination provides named locks and barriers shared by a robot and its remotes,
+// so that e.g. two arms sharing a workspace, or robots sharing a corridor, can take turns
+// without an external coordinator.
+//
+// Each lock and barrier is held by one robot. A name of the form "remote:name" refers to the
+// lock or barrier named "name" held by the robot's remote named "remote", and any other name
+// to one held by the robot itself. Code running on a remote refers to the locks it holds
+// without the prefix, so that it and its parent serialize on the same lock.
+//
+// Locks are leased: a lock is held for a time to live, which its holder renews while it
+// works. Should the holder fail, its lease expires and the lock is taken by the next waiter.
+package coordination
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// DefaultTTL is how long a lease lasts unless renewed if no time to live is given.
+	DefaultTTL = 10 * time.Second
+	// MaxTTL is the longest time to live of a lease.
+	MaxTTL = 5 * time.Minute
+)
+
+// A Lease is the hold of a lock by its owner.
+type Lease struct {
+	// ID identifies the lease, and is needed to renew and release it.
+	ID string `json:"id"`
+	// Lock is the name of the lock held.
+	Lock string `json:"lock"`
+	// Owner describes the holder of the lock to people, such as "arm1 pick and place".
+	Owner string `json:"owner"`
+	// Expires is when the lock is freed unless the lease is renewed.
+	Expires time.Time `json:"expires"`
+}
+
+// A Service acquires the locks and crosses the barriers of a robot and its remotes, either
+// directly or over a connection to the robot.
+type Service interface {
+	// Acquire waits until the named lock is free and acquires it for the given owner, for
+	// ttl or DefaultTTL if zero. It returns the context's error if it is done first.
+	Acquire(ctx context.Context, lock, owner string, ttl time.Duration) (Lease, error)
+	// Renew extends the lease on the named lock by its time to live from now. A lease that
+	// expired cannot be renewed.
+	Renew(ctx context.Context, lock, leaseID string) (Lease, error)
+	// Release releases the lease on the named lock. Releasing a lease that expired does
+	// nothing.
+	Release(ctx context.Context, lock, leaseID string) error
+	// Leases returns the leases on the locks held by the robot itself, sorted by lock.
+	Leases(ctx context.Context) ([]Lease, error)
+	// Arrive waits until the given number of participants, including this one, have arrived
+	// at the named barrier, and returns how many times the barrier was crossed before. A
+	// participant leaves the barrier if the context is done first. Every participant must
+	// give the same number of parties.
+	Arrive(ctx context.Context, barrier, participant string, parties int) (uint64, error)
+}
+
+type heldLock struct {
+	lease    Lease
+	ttl      time.Duration
+	released chan struct{}
+}
+
+type barrier struct {
+	parties    int
+	generation uint64
+	arrived    map[string]struct{}
+	crossed    chan struct{}
+}
+
+// A Robot is a robot sharing locks and barriers with its remotes.
+type Robot interface {
+	// Coordination returns the coordinator of the locks and barriers shared by the robot and
+	// its remotes, which is also served to modules and remote clients.
+	Coordination() *Coordinator
+}
+
+// A Coordinator is the Service of a robot, holding its locks and barriers and forwarding
+// requests for those of its remotes.
+type Coordinator struct {
+	logger logging.Logger
+	remote func(name string) (Service, bool)
+
+	mu       sync.Mutex
+	locks    map[string]*heldLock
+	barriers map[string]*barrier
+}
+
+var _ Service = (*Coordinator)(nil)
+
+// NewCoordinator returns a coordinator forwarding requests for the locks and barriers of a
+// remote to the service remote returns for its name.
+func NewCoordinator(logger logging.Logger, remote func(name string) (Service, bool)) *Coordinator {
+	return &Coordinator{
+		logger:   logger,
+		remote:   remote,
+		locks:    map[string]*heldLock{},
+		barriers: map[string]*barrier{},
+	}
+}
+
+// route returns the service holding the named lock or barrier and its name there.
+func (c *Coordinator) route(name string) (Service, string, error) {
+	if name == "" {
+		return nil, "", status.Error(codes.InvalidArgument, "name must not be empty")
+	}
+	remoteName, rest, ok := strings.Cut(name, ":")
+	if !ok {
+		return nil, name, nil
+	}
+	remote, ok := c.remote(remoteName)
+	if !ok {
+		return nil, "", status.Errorf(codes.NotFound, "no remote named %q", remoteName)
+	}
+	return remote, rest, nil
+}
+
+// Acquire waits until the named lock is free and acquires it for the given owner, for ttl
+// or DefaultTTL if zero. It returns the context's error if it is done first.
+func (c *Coordinator) Acquire(ctx context.Context, lock, owner string, ttl time.Duration) (Lease, error) {
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+	if ttl < 0 || ttl > MaxTTL {
+		return Lease{}, status.Errorf(codes.InvalidArgument, "time to live must be positive and at most %v", MaxTTL)
+	}
+	remote, name, err := c.route(lock)
+	if err != nil {
+		return Lease{}, err
+	}
+	if remote != nil {
+		return remote.Acquire(ctx, name, owner, ttl)
+	}
+
+	for {
+		c.mu.Lock()
+		held, ok := c.locks[name]
+		if !ok || !clock.Now().Before(held.lease.Expires) {
+			if ok {
+				c.logger.CWarnw(ctx, "lease expired", "lock", name, "owner", held.lease.Owner)
+				close(held.released)
+			}
+			held = &heldLock{
+				lease:    Lease{ID: uuid.NewString(), Lock: name, Owner: owner, Expires: clock.Now().Add(ttl)},
+				ttl:      ttl,
+				released: make(chan struct{}),
+			}
+			c.locks[name] = held
+			c.mu.Unlock()
+			return held.lease, nil
+		}
+		released, wait := held.released, clock.Robot().Until(held.lease.Expires)
+		c.mu.Unlock()
+
+		timer := clock.Robot().Timer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Lease{}, ctx.Err()
+		case <-released:
+		case <-timer.C:
+		}
+		timer.Stop()
+	}
+}
+
+// lease returns the unexpired lock held with the given lease. It must be called with the
+// coordinator locked.
+func (c *Coordinator) lease(name, leaseID string) (*heldLock, bool) {
+	held, ok := c.locks[name]
+	if !ok || held.lease.ID != leaseID || !clock.Now().Before(held.lease.Expires) {
+		return nil, false
+	}
+	return held, true
+}
+
+// Renew extends the lease on the named lock by its time to live from now. A lease that
+// expired cannot be renewed.
+func (c *Coordinator) Renew(ctx context.Context, lock, leaseID string) (Lease, error) {
+	remote, name, err := c.route(lock)
+	if err != nil {
+		return Lease{}, err
+	}
+	if remote != nil {
+		return remote.Renew(ctx, name, leaseID)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	held, ok := c.lease(name, leaseID)
+	if !ok {
+		return Lease{}, status.Errorf(codes.FailedPrecondition, "lease %q on lock %q is not held", leaseID, name)
+	}
+	held.lease.Expires = clock.Now().Add(held.ttl)
+	return held.lease, nil
+}
+
+// Release releases the lease on the named lock. Releasing a lease that expired does
+// nothing.
+func (c *Coordinator) Release(ctx context.Context, lock, leaseID string) error {
+	remote, name, err := c.route(lock)
+	if err != nil {
+		return err
+	}
+	if remote != nil {
+		return remote.Release(ctx, name, leaseID)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if held, ok := c.lease(name, leaseID); ok {
+		delete(c.locks, name)
+		close(held.released)
+	}
+	return nil
+}
+
+// Leases returns the leases on the locks held by the robot itself, sorted by lock.
+func (c *Coordinator) Leases(ctx context.Context) ([]Lease, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	leases := []Lease{}
+	now := clock.Now()
+	for _, held := range c.locks {
+		if now.Before(held.lease.Expires) {
+			leases = append(leases, held.lease)
+		}
+	}
+	sort.Slice(leases, func(i, j int) bool { return leases[i].Lock < leases[j].Lock })
+	return leases, nil
+}
+
+// Arrive waits until the given number of participants, including this one, have arrived
+// at the named barrier, and returns how many times the barrier was crossed before. A
+// participant leaves the barrier if the context is done first. Every participant must give
+// the same number of parties.
+func (c *Coordinator) Arrive(ctx context.Context, name, participant string, parties int) (uint64, error) {
+	if parties < 1 {
+		return 0, status.Error(codes.InvalidArgument, "a barrier must have at least one party")
+	}
+	remote, local, err := c.route(name)
+	if err != nil {
+		return 0, err
+	}
+	if remote != nil {
+		return remote.Arrive(ctx, local, participant, parties)
+	}
+
+	c.mu.Lock()
+	b, ok := c.barriers[local]
+	switch {
+	case !ok:
+		b = &barrier{parties: parties, arrived: map[string]struct{}{}, crossed: make(chan struct{})}
+		c.barriers[local] = b
+	case b.parties != parties && len(b.arrived) != 0:
+		c.mu.Unlock()
+		return 0, status.Errorf(codes.InvalidArgument, "barrier %q has %d parties, not %d", local, b.parties, parties)
+	default:
+		b.parties = parties
+	}
+	if _, ok := b.arrived[participant]; ok {
+		c.mu.Unlock()
+		return 0, status.Errorf(codes.AlreadyExists, "participant %q already arrived at barrier %q", participant, local)
+	}
+	b.arrived[participant] = struct{}{}
+	generation, crossed := b.generation, b.crossed
+	if len(b.arrived) == b.parties {
+		close(b.crossed)
+		b.generation++
+		b.arrived = map[string]struct{}{}
+		b.crossed = make(chan struct{})
+		c.mu.Unlock()
+		return generation, nil
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-crossed:
+		return generation, nil
+	case <-ctx.Done():
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		select {
+		case <-crossed:
+			// the barrier was crossed while giving up.
+			return generation, nil
+		default:
+		}
+		delete(b.arrived, participant)
+		return 0, ctx.Err()
+	}
+}
@@ -0,0 +1,172 @@
+package coordination_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/coordination"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func noRemotes(string) (coordination.Service, bool) { return nil, false }
+
+func TestLocks(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	c := coordination.NewCoordinator(logger, noRemotes)
+
+	lease, err := c.Acquire(ctx, "workspace", "arm1", time.Minute)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lease.Lock, test.ShouldEqual, "workspace")
+	test.That(t, lease.Owner, test.ShouldEqual, "arm1")
+	leases, err := c.Leases(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldResemble, []coordination.Lease{lease})
+
+	// a held lock is waited for until released.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err = c.Acquire(timeoutCtx, "workspace", "arm2", time.Minute)
+	cancel()
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+	acquired := make(chan coordination.Lease)
+	go func() {
+		lease, err := c.Acquire(ctx, "workspace", "arm2", 0)
+		test.That(t, err, test.ShouldBeNil)
+		acquired <- lease
+	}()
+	renewed, err := c.Renew(ctx, "workspace", lease.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, renewed.Expires.After(lease.Expires), test.ShouldBeTrue)
+	test.That(t, c.Release(ctx, "workspace", lease.ID), test.ShouldBeNil)
+	second := <-acquired
+	test.That(t, second.Owner, test.ShouldEqual, "arm2")
+	test.That(t, second.ID, test.ShouldNotEqual, lease.ID)
+
+	// releasing or renewing a lease no longer held does nothing.
+	test.That(t, c.Release(ctx, "workspace", lease.ID), test.ShouldBeNil)
+	_, err = c.Renew(ctx, "workspace", lease.ID)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+	test.That(t, c.Release(ctx, "workspace", second.ID), test.ShouldBeNil)
+
+	// the lock of a failed holder is taken once its lease expires.
+	_, err = c.Acquire(ctx, "corridor", "rover1", 100*time.Millisecond)
+	test.That(t, err, test.ShouldBeNil)
+	start := time.Now()
+	lease, err = c.Acquire(ctx, "corridor", "rover2", time.Minute)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, 50*time.Millisecond)
+	test.That(t, lease.Owner, test.ShouldEqual, "rover2")
+
+	_, err = c.Acquire(ctx, "corridor", "rover3", coordination.MaxTTL+time.Second)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	_, err = c.Acquire(ctx, "", "rover3", 0)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
+
+func TestBarriers(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	c := coordination.NewCoordinator(logger, noRemotes)
+
+	// a participant giving up leaves the barrier.
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err := c.Arrive(timeoutCtx, "handoff", "arm1", 3)
+	cancel()
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+
+	for generation := uint64(0); generation < 2; generation++ {
+		var wg sync.WaitGroup
+		for _, participant := range []string{"arm1", "arm2"} {
+			wg.Add(1)
+			go func(participant string) {
+				defer wg.Done()
+				crossed, err := c.Arrive(ctx, "handoff", participant, 3)
+				test.That(t, err, test.ShouldBeNil)
+				test.That(t, crossed, test.ShouldEqual, generation)
+			}(participant)
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, err := c.Arrive(ctx, "handoff", "arm3", 2)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+		crossed, err := c.Arrive(ctx, "handoff", "arm3", 3)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, crossed, test.ShouldEqual, generation)
+		wg.Wait()
+	}
+}
+
+func TestRemotes(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	remote := coordination.NewCoordinator(logger, noRemotes)
+	c := coordination.NewCoordinator(logger, func(name string) (coordination.Service, bool) {
+		return remote, name == "rover"
+	})
+
+	// the remote and its parent serialize on the locks the remote holds.
+	lease, err := c.Acquire(ctx, "rover:corridor", "parent", time.Minute)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lease.Lock, test.ShouldEqual, "corridor")
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	_, err = remote.Acquire(timeoutCtx, "corridor", "rover", time.Minute)
+	cancel()
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+	leases, err := c.Leases(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldBeEmpty)
+	test.That(t, c.Release(ctx, "rover:corridor", lease.ID), test.ShouldBeNil)
+	leases, err = remote.Leases(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldBeEmpty)
+
+	_, err = c.Acquire(ctx, "arm:corridor", "parent", time.Minute)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(nil)
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	coordinator := robotClient.Coordination()
+
+	lease, err := coordinator.Acquire(ctx, "workspace", "arm1", time.Minute)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lease.Owner, test.ShouldEqual, "arm1")
+	leases, err := r.Coordination().Leases(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldHaveLength, 1)
+	test.That(t, leases[0].ID, test.ShouldEqual, lease.ID)
+
+	renewed, err := coordinator.Renew(ctx, "workspace", lease.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, renewed.ID, test.ShouldEqual, lease.ID)
+	test.That(t, coordinator.Release(ctx, "workspace", lease.ID), test.ShouldBeNil)
+	leases, err = coordinator.Leases(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldBeEmpty)
+
+	crossed, err := coordinator.Arrive(ctx, "start", "arm1", 1)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, crossed, test.ShouldEqual, 0)
+	_, err = coordinator.Arrive(ctx, "start", "arm1", 0)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
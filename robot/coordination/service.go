@@ -0,0 +1,132 @@
+package coordination
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a coordinator. Its requests and
+// responses are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.coordination.v1.CoordinationService"
+
+type request struct {
+	Name        string        `json:"name"`
+	Owner       string        `json:"owner,omitempty"`
+	TTL         time.Duration `json:"ttl,omitempty"`
+	LeaseID     string        `json:"lease_id,omitempty"`
+	Participant string        `json:"participant,omitempty"`
+	Parties     int           `json:"parties,omitempty"`
+}
+
+type leasesResponse struct {
+	Leases []Lease `json:"leases"`
+}
+
+type arriveResponse struct {
+	Generation uint64 `json:"generation"`
+}
+
+// A Server serves a coordinator with ServiceDesc.
+type Server struct {
+	coordinator Service
+}
+
+// NewServer returns a server for the given coordinator.
+func NewServer(coordinator Service) *Server {
+	return &Server{coordinator: coordinator}
+}
+
+func (s *Server) acquire(ctx context.Context, req request) (interface{}, error) {
+	return s.coordinator.Acquire(ctx, req.Name, req.Owner, req.TTL)
+}
+
+func (s *Server) renew(ctx context.Context, req request) (interface{}, error) {
+	return s.coordinator.Renew(ctx, req.Name, req.LeaseID)
+}
+
+func (s *Server) release(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.coordinator.Release(ctx, req.Name, req.LeaseID)
+}
+
+func (s *Server) listLeases(ctx context.Context, _ request) (interface{}, error) {
+	leases, err := s.coordinator.Leases(ctx)
+	return leasesResponse{Leases: leases}, err
+}
+
+func (s *Server) arrive(ctx context.Context, req request) (interface{}, error) {
+	generation, err := s.coordinator.Arrive(ctx, req.Name, req.Participant, req.Parties)
+	return arriveResponse{Generation: generation}, err
+}
+
+// ServiceDesc describes the gRPC service serving a coordinator. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/coordination",
+	structrpc.Unary("Acquire", (*Server).acquire),
+	structrpc.Unary("Renew", (*Server).renew),
+	structrpc.Unary("Release", (*Server).release),
+	structrpc.Unary("ListLeases", (*Server).listLeases),
+	structrpc.Unary("Arrive", (*Server).arrive),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the coordinator served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Acquire waits until the named lock is free and acquires it for the given owner, for ttl
+// or DefaultTTL if zero. It returns the context's error if it is done first.
+func (c *Client) Acquire(ctx context.Context, lock, owner string, ttl time.Duration) (Lease, error) {
+	var lease Lease
+	if err := c.client.Invoke(ctx, "Acquire", request{Name: lock, Owner: owner, TTL: ttl}, &lease); err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Renew extends the lease on the named lock by its time to live from now. A lease that
+// expired cannot be renewed.
+func (c *Client) Renew(ctx context.Context, lock, leaseID string) (Lease, error) {
+	var lease Lease
+	if err := c.client.Invoke(ctx, "Renew", request{Name: lock, LeaseID: leaseID}, &lease); err != nil {
+		return Lease{}, err
+	}
+	return lease, nil
+}
+
+// Release releases the lease on the named lock. Releasing a lease that expired does
+// nothing.
+func (c *Client) Release(ctx context.Context, lock, leaseID string) error {
+	return c.client.Invoke(ctx, "Release", request{Name: lock, LeaseID: leaseID}, nil)
+}
+
+// Leases returns the leases on the locks held by the robot itself, sorted by lock.
+func (c *Client) Leases(ctx context.Context) ([]Lease, error) {
+	var resp leasesResponse
+	if err := c.client.Invoke(ctx, "ListLeases", request{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Leases, nil
+}
+
+// Arrive waits until the given number of participants, including this one, have arrived
+// at the named barrier, and returns how many times the barrier was crossed before. A
+// participant leaves the barrier if the context is done first. Every participant must give
+// the same number of parties.
+func (c *Client) Arrive(ctx context.Context, barrier, participant string, parties int) (uint64, error) {
+	var resp arriveResponse
+	req := request{Name: barrier, Participant: participant, Parties: parties}
+	if err := c.client.Invoke(ctx, "Arrive", req, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Generation, nil
+}
@@ -0,0 +1,293 @@
+// Package dashboard builds, in one request, what a dashboard shows of a robot: the status,
+// sensor readings, and health of each of its resources, and the operations running on it.
+// Dashboards refreshing over high-latency links make one round trip per refresh rather than
+// one per resource and kind of information, and select only the fields they show.
+package dashboard
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	vprotoutils "go.viam.com/utils/protoutils"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/protoutils"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/health"
+)
+
+// The fields of a dashboard that a request may select.
+const (
+	FieldStatus     = "status"
+	FieldReadings   = "readings"
+	FieldHealth     = "health"
+	FieldOperations = "operations"
+)
+
+var allFields = []string{FieldStatus, FieldReadings, FieldHealth, FieldOperations}
+
+// DefaultReadingsTimeout bounds how long the readings of each sensor are waited for, unless a
+// request sets its own timeout.
+const DefaultReadingsTimeout = 2 * time.Second
+
+// A Robot is a robot whose dashboard can be built.
+type Robot interface {
+	robot.Robot
+	Health(ctx context.Context) (health.Report, error)
+}
+
+// A Request selects what a dashboard holds.
+type Request struct {
+	// Fields are the fields of the dashboard to fill in, such as FieldStatus. Every field is
+	// filled in if none are given.
+	Fields []string `json:"fields,omitempty"`
+	// Resources are the full or short names of the resources to include. Every resource is
+	// included if none are given.
+	Resources []string `json:"resources,omitempty"`
+	// ReadingsTimeoutMs bounds how long the readings of each sensor are waited for, if set.
+	ReadingsTimeoutMs int `json:"readings_timeout_ms,omitempty"`
+}
+
+// A Dashboard is the state of a robot as a dashboard shows it. Its fields not selected by the
+// request that built it are left empty.
+type Dashboard struct {
+	// Ready is whether every resource of the robot is healthy, with FieldHealth.
+	Ready      *bool            `json:"ready,omitempty"`
+	Resources  []ResourceState  `json:"resources"`
+	Operations []OperationState `json:"operations,omitempty"`
+}
+
+// ResourceState is the state of one resource of a robot.
+type ResourceState struct {
+	Name string `json:"name"`
+	// Status is the JSON form of the status returned by the resource's API, with FieldStatus.
+	Status map[string]interface{} `json:"status,omitempty"`
+	// StatusError is why the status of the resource could not be read, if it could not.
+	StatusError string `json:"status_error,omitempty"`
+	// Readings are the readings of a sensor, with FieldReadings. Over gRPC, they are
+	// converted back to the types a sensor returns, as by the sensor's own client.
+	Readings map[string]interface{} `json:"readings,omitempty"`
+	// ReadingsError is why the readings of a sensor could not be read, if they could not.
+	ReadingsError string       `json:"readings_error,omitempty"`
+	Health        *HealthState `json:"health,omitempty"`
+}
+
+// HealthState is the health of a local resource of a robot, with FieldHealth.
+type HealthState struct {
+	Healthy bool `json:"healthy"`
+	// Error is the error that makes the resource unavailable, if any.
+	Error            string              `json:"error,omitempty"`
+	LastReconfigured *time.Time          `json:"last_reconfigured,omitempty"`
+	LastSuccess      *time.Time          `json:"last_success,omitempty"`
+	LastError        *health.ErrorRecord `json:"last_error,omitempty"`
+	LastStop         *health.StopRecord  `json:"last_stop,omitempty"`
+}
+
+// OperationState is an operation running on a robot, with FieldOperations.
+type OperationState struct {
+	ID       string    `json:"id"`
+	Method   string    `json:"method"`
+	Resource string    `json:"resource,omitempty"`
+	Started  time.Time `json:"started"`
+}
+
+// Build returns the dashboard of the robot selected by the request. The readings of the
+// sensors are read concurrently, and a sensor failing to be read, or taking longer than the
+// readings timeout, is recorded in its ReadingsError rather than failing the dashboard.
+func Build(ctx context.Context, r Robot, req Request) (*Dashboard, error) {
+	fields := req.Fields
+	if len(fields) == 0 {
+		fields = allFields
+	}
+	selected := map[string]bool{}
+	for _, field := range fields {
+		switch field {
+		case FieldStatus, FieldReadings, FieldHealth, FieldOperations:
+			selected[field] = true
+		default:
+			return nil, errors.Errorf("unknown dashboard field %q", field)
+		}
+	}
+
+	names, err := selectResources(r, req.Resources)
+	if err != nil {
+		return nil, err
+	}
+	dash := &Dashboard{Resources: make([]ResourceState, len(names))}
+	byName := map[resource.Name]*ResourceState{}
+	for i, name := range names {
+		dash.Resources[i].Name = name.String()
+		byName[name] = &dash.Resources[i]
+	}
+
+	if selected[FieldStatus] && len(names) > 0 {
+		if err := readStatuses(ctx, r, names, byName); err != nil {
+			return nil, err
+		}
+	}
+
+	if selected[FieldReadings] {
+		timeout := DefaultReadingsTimeout
+		if req.ReadingsTimeoutMs > 0 {
+			timeout = time.Duration(req.ReadingsTimeoutMs) * time.Millisecond
+		}
+		readAll(ctx, r, byName, timeout)
+	}
+
+	if selected[FieldHealth] {
+		report, err := r.Health(ctx)
+		if err != nil {
+			return nil, err
+		}
+		dash.Ready = &report.Ready
+		for _, resHealth := range report.Resources {
+			if state, ok := byName[resHealth.Name]; ok {
+				state.Health = healthState(resHealth)
+			}
+		}
+	}
+
+	if selected[FieldOperations] {
+		if opManager := r.OperationManager(); opManager != nil {
+			for _, op := range opManager.All() {
+				dash.Operations = append(dash.Operations, OperationState{
+					ID: op.ID.String(), Method: op.Method, Resource: op.Resource, Started: op.Started,
+				})
+			}
+		}
+		sort.Slice(dash.Operations, func(i, j int) bool {
+			return dash.Operations[i].Started.Before(dash.Operations[j].Started)
+		})
+	}
+	return dash, nil
+}
+
+// selectResources returns the names of the resources of the robot with the given full or
+// short names, or of every resource, sorted.
+func selectResources(r robot.Robot, selectors []string) ([]resource.Name, error) {
+	all := r.ResourceNames()
+	if len(selectors) == 0 {
+		sort.Slice(all, func(i, j int) bool { return all[i].String() < all[j].String() })
+		return all, nil
+	}
+	found := map[resource.Name]bool{}
+	for _, selector := range selectors {
+		matched := false
+		for _, name := range all {
+			if name.String() == selector || name.ShortName() == selector {
+				found[name] = true
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, errors.Errorf("no resource named %q", selector)
+		}
+	}
+	names := make([]resource.Name, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i].String() < names[j].String() })
+	return names, nil
+}
+
+// readStatuses reads the statuses of the named resources. They are read together, unless one
+// of them fails to be read, in which case each is read on its own so that the others are
+// still shown.
+func readStatuses(ctx context.Context, r robot.Robot, names []resource.Name, states map[resource.Name]*ResourceState) error {
+	statuses, err := r.Status(ctx, names)
+	if err == nil {
+		return setStatuses(statuses, states)
+	}
+	for _, name := range names {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		statuses, err := r.Status(ctx, []resource.Name{name})
+		if err != nil {
+			states[name].StatusError = err.Error()
+			continue
+		}
+		if err := setStatuses(statuses, states); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setStatuses(statuses []robot.Status, states map[resource.Name]*ResourceState) error {
+	for _, status := range statuses {
+		state, ok := states[status.Name]
+		if !ok || status.Status == nil {
+			continue
+		}
+		asStruct, err := vprotoutils.StructToStructPb(status.Status)
+		if err != nil {
+			return errors.Wrapf(err, "failed to convert the status of %q", status.Name)
+		}
+		state.Status = asStruct.AsMap()
+	}
+	return nil
+}
+
+// readAll reads the readings of the sensors among the given resources concurrently.
+func readAll(ctx context.Context, r robot.Robot, states map[resource.Name]*ResourceState, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for name, state := range states {
+		res, err := r.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		sensor, ok := res.(resource.Sensor)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(state *ResourceState) {
+			defer wg.Done()
+			readCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+			readings, err := readingsToJSON(sensor.Readings(readCtx, nil))
+			if err != nil {
+				state.ReadingsError = err.Error()
+				return
+			}
+			state.Readings = readings
+		}(state)
+	}
+	wg.Wait()
+}
+
+// readingsToJSON returns the JSON form of the readings of a sensor, as a sensor's server
+// sends them.
+func readingsToJSON(readings map[string]interface{}, err error) (map[string]interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	values, err := protoutils.ReadingGoToProto(readings)
+	if err != nil {
+		return nil, err
+	}
+	return (&structpb.Struct{Fields: values}).AsMap(), nil
+}
+
+func healthState(h health.ResourceHealth) *HealthState {
+	state := &HealthState{
+		Healthy:          h.Healthy(),
+		LastReconfigured: h.LastReconfigured,
+		LastError:        h.LastError,
+		LastStop:         h.LastStop,
+	}
+	if h.Error != nil {
+		state.Error = h.Error.Error()
+	}
+	if !h.LastSuccess.IsZero() {
+		lastSuccess := h.LastSuccess
+		state.LastSuccess = &lastSuccess
+	}
+	return state
+}
@@ -0,0 +1,139 @@
+package dashboard_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/dashboard"
+	"go.viam.com/rdk/robot/health"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestDashboard(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	thermometer := inject.NewSensor("thermometer")
+	thermometer.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"celsius": 21.5, "unit": "C"}, nil
+	}
+	broken := inject.NewSensor("broken")
+	broken.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("sensor unplugged")
+	}
+	slow := inject.NewSensor("slow")
+	slow.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	motor1 := inject.NewMotor("motor1")
+
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		StatusFunc: func(ctx context.Context, names []resource.Name) ([]robot.Status, error) {
+			statuses := make([]robot.Status, 0, len(names))
+			for _, name := range names {
+				if name == broken.Name() {
+					return nil, errors.New("no status")
+				}
+				statuses = append(statuses, robot.Status{Name: name, Status: map[string]interface{}{"on": true}})
+			}
+			return statuses, nil
+		},
+		HealthFunc: func(ctx context.Context) (health.Report, error) {
+			return health.NewReport([]health.ResourceHealth{
+				{Name: thermometer.Name(), Constructed: true},
+				{Name: broken.Name(), Constructed: true, Error: errors.New("sensor unplugged")},
+				{Name: slow.Name(), Constructed: true},
+				{Name: motor1.Name(), Constructed: true},
+			}), nil
+		},
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		thermometer.Name(): thermometer,
+		broken.Name():      broken,
+		slow.Name():        slow,
+		motor1.Name():      motor1,
+	})
+	_, done := r.OperationManager().Create(ctx, "/viam.component.motor.v1.MotorService/GoFor", nil)
+	defer done()
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	t.Run("every field", func(t *testing.T) {
+		dash, err := robotClient.Dashboard().Get(ctx, dashboard.Request{ReadingsTimeoutMs: 50})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dash.Ready, test.ShouldNotBeNil)
+		test.That(t, *dash.Ready, test.ShouldBeFalse)
+		test.That(t, dash.Resources, test.ShouldHaveLength, 4)
+		states := map[string]dashboard.ResourceState{}
+		for _, state := range dash.Resources {
+			states[state.Name] = state
+		}
+
+		state := states[thermometer.Name().String()]
+		test.That(t, state.Status, test.ShouldResemble, map[string]interface{}{"on": true})
+		test.That(t, state.Readings, test.ShouldResemble, map[string]interface{}{"celsius": 21.5, "unit": "C"})
+		test.That(t, state.Health.Healthy, test.ShouldBeTrue)
+
+		// a resource failing to be read does not fail the others.
+		state = states[broken.Name().String()]
+		test.That(t, state.Status, test.ShouldBeNil)
+		test.That(t, state.StatusError, test.ShouldContainSubstring, "no status")
+		test.That(t, state.ReadingsError, test.ShouldContainSubstring, "sensor unplugged")
+		test.That(t, state.Health.Healthy, test.ShouldBeFalse)
+		test.That(t, state.Health.Error, test.ShouldContainSubstring, "sensor unplugged")
+
+		state = states[slow.Name().String()]
+		test.That(t, state.Readings, test.ShouldBeNil)
+		test.That(t, state.ReadingsError, test.ShouldContainSubstring, "deadline exceeded")
+
+		// only sensors have readings.
+		state = states[motor1.Name().String()]
+		test.That(t, state.Status, test.ShouldResemble, map[string]interface{}{"on": true})
+		test.That(t, state.Readings, test.ShouldBeNil)
+		test.That(t, state.ReadingsError, test.ShouldBeEmpty)
+
+		// the request for the dashboard is itself running, and started last.
+		test.That(t, dash.Operations, test.ShouldHaveLength, 2)
+		test.That(t, dash.Operations[0].Method, test.ShouldEqual, "/viam.component.motor.v1.MotorService/GoFor")
+		test.That(t, dash.Operations[1].Method, test.ShouldEqual, "/"+dashboard.ServiceName+"/GetDashboard")
+	})
+
+	t.Run("field mask", func(t *testing.T) {
+		dash, err := robotClient.Dashboard().Get(ctx, dashboard.Request{
+			Fields:    []string{dashboard.FieldReadings},
+			Resources: []string{"thermometer", motor.Named("motor1").String()},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, dash.Ready, test.ShouldBeNil)
+		test.That(t, dash.Operations, test.ShouldBeEmpty)
+		test.That(t, dash.Resources, test.ShouldResemble, []dashboard.ResourceState{
+			{Name: motor1.Name().String()},
+			{Name: thermometer.Name().String(), Readings: map[string]interface{}{"celsius": 21.5, "unit": "C"}},
+		})
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		_, err := robotClient.Dashboard().Get(ctx, dashboard.Request{Fields: []string{"logs"}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `unknown dashboard field "logs"`)
+
+		_, err = robotClient.Dashboard().Get(ctx, dashboard.Request{Resources: []string{"gripper"}})
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, `no resource named "gripper"`)
+	})
+}
@@ -0,0 +1,67 @@
+package dashboard
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/protoutils"
+)
+
+// ServiceName is the name of the gRPC service serving a robot's dashboard. Its requests and
+// responses are google.protobuf.Struct messages holding the JSON form of Request and
+// Dashboard.
+const ServiceName = "viam.rdk.dashboard.v1.DashboardService"
+
+// A Server serves a robot's dashboard with ServiceDesc.
+type Server struct {
+	robot Robot
+}
+
+// NewServer returns a server of the dashboard of the given robot.
+func NewServer(r Robot) *Server {
+	return &Server{robot: r}
+}
+
+func (s *Server) getDashboard(ctx context.Context, req Request) (interface{}, error) {
+	return Build(ctx, s.robot, req)
+}
+
+// ServiceDesc describes the gRPC service serving a robot's dashboard. It is served with a
+// Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/dashboard",
+	structrpc.Unary("GetDashboard", (*Server).getDashboard),
+)
+
+// A Client is the dashboard of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the dashboard served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Get returns the dashboard of the robot selected by the request, in one round trip.
+func (c *Client) Get(ctx context.Context, req Request) (*Dashboard, error) {
+	var dash Dashboard
+	if err := c.client.Invoke(ctx, "GetDashboard", req, &dash); err != nil {
+		return nil, err
+	}
+	for i, state := range dash.Resources {
+		if state.Readings == nil {
+			continue
+		}
+		values, err := structpb.NewStruct(state.Readings)
+		if err != nil {
+			return nil, err
+		}
+		if dash.Resources[i].Readings, err = protoutils.ReadingProtoToGo(values.Fields); err != nil {
+			return nil, err
+		}
+	}
+	return &dash, nil
+}
@@ -0,0 +1,81 @@
+package effectiveconfig_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/components/arm"
+	_ "go.viam.com/rdk/components/arm/fake"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/effectiveconfig"
+	"go.viam.com/rdk/services/motion"
+	_ "go.viam.com/rdk/services/motion/builtin"
+	"go.viam.com/rdk/testutils/localrobot"
+	"go.viam.com/rdk/testutils/robottestutils"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	remoteCfg, err := config.FromReader(ctx, "", strings.NewReader(`{
+		"components": [
+			{
+				"name": "arm1",
+				"api": "rdk:component:arm",
+				"model": "rdk:builtin:fake",
+				"attributes": {"arm-model": "ur5e"}
+			}
+		]
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+	remote := localrobot.New(t, remoteCfg)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, remote.StartWeb(ctx, options), test.ShouldBeNil)
+
+	r := localrobot.New(t, &config.Config{
+		Cloud:   &config.Cloud{ID: "robot", Secret: "secret"},
+		Remotes: []config.Remote{{Name: "foo", Address: addr}},
+	})
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		_, err := r.ResourceByName(arm.Named("foo:arm1"))
+		test.That(tb, err, test.ShouldBeNil)
+	})
+
+	// the config only holds what is set.
+	test.That(t, r.Config().MaxRemoteDepth, test.ShouldEqual, 0)
+
+	effective, err := r.(effectiveconfig.Robot).EffectiveConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, effective.Config.Cloud, test.ShouldBeNil)
+	test.That(t, effective.Config.MaxRemoteDepth, test.ShouldEqual, config.DefaultMaxRemoteDepth)
+	test.That(t, effective.Config.ResourceRetryInterval, test.ShouldEqual, config.DefaultResourceRetryInterval)
+	test.That(t, effective.Config.CallDeadlines, test.ShouldResemble, &config.CallDeadlines{Default: config.DefaultCallDeadline})
+	test.That(t, effective.Config.Remotes, test.ShouldHaveLength, 1)
+	var services []resource.Name
+	for _, conf := range effective.Config.Services {
+		services = append(services, conf.ResourceName())
+	}
+	test.That(t, services, test.ShouldContain, motion.Named(resource.DefaultServiceName))
+	test.That(t, effective.RemoteResources["foo"], test.ShouldContain, arm.Named("foo:arm1").String())
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	served, err := robotClient.EffectiveConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, served.RemoteResources, test.ShouldResemble, effective.RemoteResources)
+	test.That(t, served.Config.MaxRemoteDepth, test.ShouldEqual, config.DefaultMaxRemoteDepth)
+	test.That(t, served.Config.CallDeadlines, test.ShouldResemble, effective.Config.CallDeadlines)
+	test.That(t, served.Config.Services, test.ShouldHaveLength, len(effective.Config.Services))
+	test.That(t, served.Config.Network.BindAddress, test.ShouldEqual, effective.Config.Network.BindAddress)
+}
@@ -0,0 +1,64 @@
+// Package effectiveconfig serves the complete config a robot runs with, so that the reason it
+// behaves as it does can be found without reproducing how its config was merged and defaulted.
+package effectiveconfig
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/robot"
+)
+
+// ServiceName is the name of the gRPC service serving the effective config of a robot. Its
+// requests and responses are google.protobuf.Struct messages, the responses holding the JSON
+// form of robot.EffectiveConfig.
+const ServiceName = "viam.rdk.effectiveconfig.v1.EffectiveConfigService"
+
+// A Robot is a robot that reports the config it runs with.
+type Robot interface {
+	robot.Robot
+	// EffectiveConfig returns the complete config the robot runs with, with defaults applied,
+	// unlike Config which only holds what is set.
+	EffectiveConfig(ctx context.Context) (*robot.EffectiveConfig, error)
+}
+
+// A Server serves the effective config of a robot with ServiceDesc.
+type Server struct {
+	robot Robot
+}
+
+// NewServer returns a server of the effective config of the given robot.
+func NewServer(r Robot) *Server {
+	return &Server{robot: r}
+}
+
+func (s *Server) getEffectiveConfig(ctx context.Context, _ struct{}) (interface{}, error) {
+	return s.robot.EffectiveConfig(ctx)
+}
+
+// ServiceDesc describes the gRPC service serving the effective config of a robot. It is
+// served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/effectiveconfig",
+	structrpc.Unary("GetEffectiveConfig", (*Server).getEffectiveConfig),
+)
+
+// A Client gets the effective config of a robot over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the effective config served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Get returns the complete config the robot runs with, with defaults applied.
+func (c *Client) Get(ctx context.Context) (*robot.EffectiveConfig, error) {
+	var resp robot.EffectiveConfig
+	if err := c.client.Invoke(ctx, "GetEffectiveConfig", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,175 @@
+// Package events publishes changes to the state of a robot's resources and remotes, such as
+// a resource being added or failing, to subscribers within the robot process and to clients
+// over gRPC, so that they can react to them without polling the robot.
+package events
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before its subscription
+// ends.
+const subscriberBuffer = 64
+
+var errClosed = errors.New("event bus is closed")
+
+// A Type is a kind of event.
+type Type string
+
+// The types of events published by a robot.
+const (
+	// ResourceAdded is published when a resource is added to the robot, whether or not it
+	// could be constructed.
+	ResourceAdded Type = "resource_added"
+	// ResourceRemoved is published when a resource is removed from the robot.
+	ResourceRemoved Type = "resource_removed"
+	// ResourceErrored is published when a resource becomes unavailable, or fails again with a
+	// different error.
+	ResourceErrored Type = "resource_errored"
+	// ResourceRecovered is published when a resource that errored is available again.
+	ResourceRecovered Type = "resource_recovered"
+	// RemoteConnected is published when the robot connects to a remote.
+	RemoteConnected Type = "remote_connected"
+	// RemoteDisconnected is published when the robot loses its connection to a remote, or
+	// the remote is removed.
+	RemoteDisconnected Type = "remote_disconnected"
+	// LimitViolated is published when a request to a resource is refused for violating one of
+	// its motion limits.
+	LimitViolated Type = "limit_violated"
+	// FrameSystemUpdated is published when a frame is added to, moved or re-parented in, or
+	// removed from the frame system at runtime.
+	FrameSystemUpdated Type = "frame_system_updated"
+)
+
+// An Event is a change to the state of a resource or remote of a robot.
+type Event struct {
+	Type Type
+	// Name is the resource the event is about, for resource events.
+	Name resource.Name
+	// Remote is the remote the event is about, for remote events.
+	Remote string
+	// Frame is the frame the event is about, for FrameSystemUpdated events.
+	Frame string
+	// Error is why the resource is unavailable, for ResourceErrored events, or the violation,
+	// for LimitViolated events.
+	Error string
+	Time  time.Time
+}
+
+// A Service publishes events to subscribers.
+type Service interface {
+	// Subscribe returns the events of the given types, or of every type if none are given,
+	// published from now on. The channel is closed when the context is done or the subscriber
+	// falls too far behind.
+	Subscribe(ctx context.Context, types ...Type) (<-chan Event, error)
+}
+
+type subscriber struct {
+	types  map[Type]bool
+	events chan Event
+	done   chan struct{}
+}
+
+func (s *subscriber) wants(ev Event) bool {
+	return len(s.types) == 0 || s.types[ev.Type]
+}
+
+// A Robot is a robot publishing changes to the state of its resources and remotes.
+type Robot interface {
+	// Events returns the bus publishing changes to the state of the robot's resources and
+	// remotes, which is also served to modules and remote clients.
+	Events() *Bus
+}
+
+// A Bus is the Service of a robot.
+type Bus struct {
+	mu          sync.Mutex
+	logger      logging.Logger
+	subscribers map[*subscriber]struct{}
+	closed      bool
+}
+
+var _ Service = (*Bus)(nil)
+
+// NewBus returns a new Bus with no subscribers.
+func NewBus(logger logging.Logger) *Bus {
+	return &Bus{logger: logger, subscribers: map[*subscriber]struct{}{}}
+}
+
+// Publish sends the event to every subscriber of its type. Events without a time are
+// published at the current time.
+func (b *Bus) Publish(ev Event) {
+	if ev.Time.IsZero() {
+		ev.Time = clock.Now()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for sub := range b.subscribers {
+		if !sub.wants(ev) {
+			continue
+		}
+		select {
+		case sub.events <- ev:
+		default:
+			b.logger.Debug("event subscriber fell behind; ending its subscription")
+			b.removeSubscriber(sub)
+		}
+	}
+}
+
+// Subscribe returns the events of the given types, or of every type if none are given,
+// published from now on.
+func (b *Bus) Subscribe(ctx context.Context, types ...Type) (<-chan Event, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, errClosed
+	}
+	sub := &subscriber{
+		types:  map[Type]bool{},
+		events: make(chan Event, subscriberBuffer),
+		done:   make(chan struct{}),
+	}
+	for _, typ := range types {
+		sub.types[typ] = true
+	}
+	b.subscribers[sub] = struct{}{}
+	go func() {
+		select {
+		case <-ctx.Done():
+			b.mu.Lock()
+			b.removeSubscriber(sub)
+			b.mu.Unlock()
+		case <-sub.done:
+		}
+	}()
+	return sub.events, nil
+}
+
+// removeSubscriber ends the subscription. It must be called with the bus locked.
+func (b *Bus) removeSubscriber(sub *subscriber) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.events)
+	close(sub.done)
+}
+
+// Close ends every subscription.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subscribers {
+		b.removeSubscriber(sub)
+	}
+}
@@ -0,0 +1,109 @@
+package events_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/events"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func nextEvent(t *testing.T, subscription <-chan events.Event) events.Event {
+	t.Helper()
+	select {
+	case ev, ok := <-subscription:
+		test.That(t, ok, test.ShouldBeTrue)
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return events.Event{}
+	}
+}
+
+// testService checks the behavior every Service shares, publishing with the given bus.
+func testService(t *testing.T, bus *events.Bus, svc events.Service) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	all, err := svc.Subscribe(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	remotes, err := svc.Subscribe(ctx, events.RemoteConnected, events.RemoteDisconnected)
+	test.That(t, err, test.ShouldBeNil)
+
+	cam := camera.Named("cam1")
+	bus.Publish(events.Event{Type: events.ResourceAdded, Name: cam})
+	bus.Publish(events.Event{Type: events.ResourceErrored, Name: cam, Error: "no frames"})
+	bus.Publish(events.Event{Type: events.RemoteConnected, Remote: "arm-pi"})
+
+	ev := nextEvent(t, all)
+	test.That(t, ev.Type, test.ShouldEqual, events.ResourceAdded)
+	test.That(t, ev.Name, test.ShouldResemble, cam)
+	test.That(t, ev.Time.IsZero(), test.ShouldBeFalse)
+	ev = nextEvent(t, all)
+	test.That(t, ev.Type, test.ShouldEqual, events.ResourceErrored)
+	test.That(t, ev.Error, test.ShouldEqual, "no frames")
+	test.That(t, nextEvent(t, all).Remote, test.ShouldEqual, "arm-pi")
+
+	// subscribers only receive the types they ask for.
+	ev = nextEvent(t, remotes)
+	test.That(t, ev.Type, test.ShouldEqual, events.RemoteConnected)
+	test.That(t, ev.Remote, test.ShouldEqual, "arm-pi")
+	test.That(t, ev.Name, test.ShouldResemble, resource.Name{})
+
+	// a subscription ends with its context.
+	cancel()
+	for range all {
+	}
+}
+
+func TestBus(t *testing.T) {
+	bus := events.NewBus(logging.NewTestLogger(t))
+	testService(t, bus, bus)
+
+	t.Run("slow subscribers and close", func(t *testing.T) {
+		slow, err := bus.Subscribe(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		for i := 0; i < 100; i++ {
+			bus.Publish(events.Event{Type: events.ResourceRecovered, Name: camera.Named("cam1")})
+		}
+		received := 0
+		for range slow {
+			received++
+		}
+		test.That(t, received, test.ShouldBeLessThan, 100)
+
+		subscription, err := bus.Subscribe(context.Background())
+		test.That(t, err, test.ShouldBeNil)
+		bus.Close()
+		_, ok := <-subscription
+		test.That(t, ok, test.ShouldBeFalse)
+		_, err = bus.Subscribe(context.Background())
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(nil)
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	testService(t, r.Events(), robotClient.Events())
+}
@@ -0,0 +1,132 @@
+package events
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/resource"
+)
+
+// ServiceName is the name of the gRPC service serving a bus. Its requests and responses are
+// google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.events.v1.EventService"
+
+type subscribeRequest struct {
+	Types []Type `json:"types,omitempty"`
+}
+
+// eventData is the JSON form of an Event.
+type eventData struct {
+	Type   Type      `json:"type"`
+	Name   string    `json:"name,omitempty"`
+	Remote string    `json:"remote,omitempty"`
+	Frame  string    `json:"frame,omitempty"`
+	Error  string    `json:"error,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+func newEventData(ev Event) eventData {
+	data := eventData{Type: ev.Type, Remote: ev.Remote, Frame: ev.Frame, Error: ev.Error, Time: ev.Time}
+	if ev.Name != (resource.Name{}) {
+		data.Name = ev.Name.String()
+	}
+	return data
+}
+
+func (data eventData) event() (Event, error) {
+	ev := Event{Type: data.Type, Remote: data.Remote, Frame: data.Frame, Error: data.Error, Time: data.Time}
+	if data.Name != "" {
+		name, err := resource.NewFromString(data.Name)
+		if err != nil {
+			return Event{}, err
+		}
+		ev.Name = name
+	}
+	return ev, nil
+}
+
+// A Server serves a bus with ServiceDesc.
+type Server struct {
+	bus Service
+}
+
+// NewServer returns a server for the given bus.
+func NewServer(bus Service) *Server {
+	return &Server{bus: bus}
+}
+
+func (s *Server) subscribe(req subscribeRequest, stream structrpc.ServerStream) error {
+	ctx := stream.Context()
+	events, err := s.bus.Subscribe(ctx, req.Types...)
+	if err != nil {
+		return err
+	}
+	// let the client know it is subscribed, so that it misses no event published after.
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for ev := range events {
+		if err := stream.Send(newEventData(ev)); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return status.Error(codes.Aborted, "subscription ended; the subscriber may have fallen behind")
+}
+
+// ServiceDesc describes the gRPC service serving a bus. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/events",
+	structrpc.ServerStreaming("Subscribe", (*Server).subscribe),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the bus served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Subscribe returns the events of the given types, or of every type if none are given,
+// published from now on.
+func (c *Client) Subscribe(ctx context.Context, types ...Type) (<-chan Event, error) {
+	stream, err := c.client.Stream(ctx, "Subscribe", subscribeRequest{Types: types})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := stream.Header(); err != nil {
+		return nil, err
+	}
+	events := make(chan Event, subscriberBuffer)
+	go func() {
+		defer close(events)
+		for {
+			var data eventData
+			if err := stream.Recv(&data); err != nil {
+				return
+			}
+			ev, err := data.event()
+			if err != nil {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,157 @@
+// Package failover tracks components that have a standby configured, such as a second GPS
+// unit or camera. While a primary is unavailable, lookups of it are served by its standby;
+// a Monitor records when that starts and stops and reports it to watchers.
+package failover
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// watchBuffer is how many events a watcher may fall behind before its watch ends.
+const watchBuffer = 64
+
+// An Event is a change in whether lookups of a primary are served by its standby.
+type Event struct {
+	Primary resource.Name
+	Standby resource.Name
+	// FailedOver is whether lookups of the primary are now served by its standby. It is false
+	// when they are served by the primary again.
+	FailedOver bool
+	// Reason is why the primary is unavailable, if it failed over.
+	Reason string
+	Time   time.Time
+}
+
+// A Robot is a robot serving lookups of unavailable resources by their standby.
+type Robot interface {
+	// Failovers returns the monitor of the resources whose lookups are served by their
+	// standby while they are unavailable.
+	Failovers() *Monitor
+}
+
+// A Monitor records which primaries have failed over to their standby.
+type Monitor struct {
+	mu         sync.Mutex
+	logger     logging.Logger
+	failedOver map[resource.Name]resource.Name
+	watchers   map[chan Event]chan struct{}
+	closed     bool
+}
+
+// NewMonitor returns a new Monitor with no primary failed over.
+func NewMonitor(logger logging.Logger) *Monitor {
+	return &Monitor{
+		logger:     logger,
+		failedOver: map[resource.Name]resource.Name{},
+		watchers:   map[chan Event]chan struct{}{},
+	}
+}
+
+// Update records whether lookups of the primary are served by the given standby, which they
+// are while the primary is unavailable with primaryErr. If that changed, it reports an
+// event and returns true.
+func (m *Monitor) Update(primary, standby resource.Name, primaryErr error) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, failedOver := m.failedOver[primary]
+	switch {
+	case primaryErr != nil && (!failedOver || current != standby):
+		m.failedOver[primary] = standby
+		m.logger.Warnw("failing over to standby", "primary", primary, "standby", standby, "reason", primaryErr)
+		m.publish(Event{Primary: primary, Standby: standby, FailedOver: true, Reason: primaryErr.Error(), Time: clock.Now()})
+		return true
+	case primaryErr == nil && failedOver:
+		delete(m.failedOver, primary)
+		m.logger.Infow("primary available again; failing back from standby", "primary", primary, "standby", current)
+		m.publish(Event{Primary: primary, Standby: current, Time: clock.Now()})
+		return true
+	default:
+		return false
+	}
+}
+
+// FailedOver returns the standby serving lookups of the given primary, if it failed over.
+func (m *Monitor) FailedOver(primary resource.Name) (resource.Name, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	standby, ok := m.failedOver[primary]
+	return standby, ok
+}
+
+// Primaries returns the primaries that are failed over, sorted by name.
+func (m *Monitor) Primaries() []resource.Name {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	primaries := make([]resource.Name, 0, len(m.failedOver))
+	for primary := range m.failedOver {
+		primaries = append(primaries, primary)
+	}
+	sort.Slice(primaries, func(i, j int) bool {
+		return primaries[i].String() < primaries[j].String()
+	})
+	return primaries
+}
+
+// publish sends the event to every watcher. It must be called with the monitor locked.
+func (m *Monitor) publish(ev Event) {
+	for events := range m.watchers {
+		select {
+		case events <- ev:
+		default:
+			m.logger.Debug("failover watcher fell behind; ending its watch")
+			m.removeWatcher(events)
+		}
+	}
+}
+
+// Watch returns the events that happen until the context is done or the watcher falls too
+// far behind, after which the channel is closed.
+func (m *Monitor) Watch(ctx context.Context) <-chan Event {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	events := make(chan Event, watchBuffer)
+	if m.closed {
+		close(events)
+		return events
+	}
+	done := make(chan struct{})
+	m.watchers[events] = done
+	go func() {
+		select {
+		case <-ctx.Done():
+			m.mu.Lock()
+			m.removeWatcher(events)
+			m.mu.Unlock()
+		case <-done:
+		}
+	}()
+	return events
+}
+
+// removeWatcher ends the watch. It must be called with the monitor locked.
+func (m *Monitor) removeWatcher(events chan Event) {
+	done, ok := m.watchers[events]
+	if !ok {
+		return
+	}
+	delete(m.watchers, events)
+	close(events)
+	close(done)
+}
+
+// Close ends every watch.
+func (m *Monitor) Close() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.closed = true
+	for events := range m.watchers {
+		m.removeWatcher(events)
+	}
+}
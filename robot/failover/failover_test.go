@@ -0,0 +1,90 @@
+package failover_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/failover"
+)
+
+func nextEvent(t *testing.T, events <-chan failover.Event) failover.Event {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		test.That(t, ok, test.ShouldBeTrue)
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return failover.Event{}
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	monitor := failover.NewMonitor(logging.NewTestLogger(t))
+	events := monitor.Watch(ctx)
+	gps1 := movementsensor.Named("gps1")
+	gps2 := movementsensor.Named("gps2")
+	gps3 := movementsensor.Named("gps3")
+
+	// nothing happens while the primary is available.
+	test.That(t, monitor.Update(gps1, gps2, nil), test.ShouldBeFalse)
+	test.That(t, monitor.Primaries(), test.ShouldBeEmpty)
+
+	test.That(t, monitor.Update(gps1, gps2, errors.New("no fix")), test.ShouldBeTrue)
+	ev := nextEvent(t, events)
+	test.That(t, ev.Primary, test.ShouldResemble, gps1)
+	test.That(t, ev.Standby, test.ShouldResemble, gps2)
+	test.That(t, ev.FailedOver, test.ShouldBeTrue)
+	test.That(t, ev.Reason, test.ShouldEqual, "no fix")
+	standby, ok := monitor.FailedOver(gps1)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, standby, test.ShouldResemble, gps2)
+	test.That(t, monitor.Primaries(), test.ShouldResemble, []resource.Name{gps1})
+
+	// failing over to the same standby again is not a change.
+	test.That(t, monitor.Update(gps1, gps2, errors.New("still no fix")), test.ShouldBeFalse)
+	// a different standby is.
+	test.That(t, monitor.Update(gps1, gps3, errors.New("no fix")), test.ShouldBeTrue)
+	test.That(t, nextEvent(t, events).Standby, test.ShouldResemble, gps3)
+
+	test.That(t, monitor.Update(gps1, gps3, nil), test.ShouldBeTrue)
+	ev = nextEvent(t, events)
+	test.That(t, ev.FailedOver, test.ShouldBeFalse)
+	test.That(t, ev.Standby, test.ShouldResemble, gps3)
+	_, ok = monitor.FailedOver(gps1)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// a watch ends with its context.
+	cancel()
+	for range events {
+	}
+
+	t.Run("slow watchers and close", func(t *testing.T) {
+		slow := monitor.Watch(context.Background())
+		for i := 0; i < 100; i++ {
+			monitor.Update(gps1, gps2, errors.New("no fix"))
+			monitor.Update(gps1, gps2, nil)
+		}
+		received := 0
+		for range slow {
+			received++
+		}
+		test.That(t, received, test.ShouldBeLessThan, 200)
+
+		watching := monitor.Watch(context.Background())
+		monitor.Close()
+		_, ok := <-watching
+		test.That(t, ok, test.ShouldBeFalse)
+		_, ok = <-monitor.Watch(context.Background())
+		test.That(t, ok, test.ShouldBeFalse)
+	})
+}
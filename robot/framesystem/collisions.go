@@ -0,0 +1,141 @@
+package framesystem
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// A Collision is a pair of geometries closer than the collision buffer, such as two
+// interpenetrating links of the robot or a link and an obstacle. Its geometries are named by
+// their labels, or by their frames if unlabeled.
+type Collision struct {
+	Geometry1, Geometry2 string
+	Frame1, Frame2       string
+	// DistanceMM is the distance between the geometries, which is negative by how deep they
+	// interpenetrate.
+	DistanceMM float64
+}
+
+type framedGeometry struct {
+	frame, part string
+	geometry    spatialmath.Geometry
+}
+
+func (fg framedGeometry) name() string {
+	if label := fg.geometry.Label(); label != "" {
+		return label
+	}
+	return fg.frame
+}
+
+// partOf returns the name of the part a frame belongs to: the frame placing a part is named
+// after it with an "_origin" suffix.
+func partOf(frame string) string {
+	return strings.TrimSuffix(frame, "_origin")
+}
+
+// CheckCollisions returns the collisions between the geometries of the frame system at the
+// given inputs, and between them and the obstacles of the world state, which may be nil.
+// Geometries of the same part, such as the links of an arm, and of parts mounted on each
+// other, such as an arm and its gripper, touch by design and are not checked against each
+// other. Obstacles are not checked against each other. Collisions are sorted by distance.
+func CheckCollisions(
+	fs referenceframe.FrameSystem,
+	inputs map[string][]referenceframe.Input,
+	worldState *referenceframe.WorldState,
+	collisionBufferMM float64,
+) ([]Collision, error) {
+	geometries, err := referenceframe.FrameSystemGeometries(fs, inputs)
+	if err != nil {
+		return nil, err
+	}
+	frames := make([]string, 0, len(geometries))
+	for name := range geometries {
+		frames = append(frames, name)
+	}
+	sort.Strings(frames)
+
+	// the part each part is mounted on.
+	mountedOn := map[string]string{}
+	var robotGeometries []framedGeometry
+	for _, name := range frames {
+		part := partOf(name)
+		placing := fs.Frame(part + "_origin")
+		if placing == nil {
+			placing = fs.Frame(name)
+		}
+		parent, err := fs.Parent(placing)
+		if err != nil {
+			return nil, err
+		}
+		mountedOn[part] = partOf(parent.Name())
+		for _, geometry := range geometries[name].Geometries() {
+			robotGeometries = append(robotGeometries, framedGeometry{frame: name, part: part, geometry: geometry})
+		}
+	}
+	obstacles, err := worldState.ObstaclesInWorldFrame(fs, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	var collisions []Collision
+	check := func(x, y framedGeometry) error {
+		distance, err := x.geometry.DistanceFrom(y.geometry)
+		if err != nil {
+			if distance, err = y.geometry.DistanceFrom(x.geometry); err != nil {
+				return err
+			}
+		}
+		if distance <= collisionBufferMM {
+			collisions = append(collisions, Collision{
+				Geometry1:  x.name(),
+				Geometry2:  y.name(),
+				Frame1:     x.frame,
+				Frame2:     y.frame,
+				DistanceMM: distance,
+			})
+		}
+		return nil
+	}
+	for i, x := range robotGeometries {
+		for _, y := range robotGeometries[i+1:] {
+			if x.part == y.part || mountedOn[x.part] == y.part || mountedOn[y.part] == x.part {
+				continue
+			}
+			if err := check(x, y); err != nil {
+				return nil, err
+			}
+		}
+		for _, obstacle := range obstacles.Geometries() {
+			if err := check(x, framedGeometry{frame: referenceframe.World, geometry: obstacle}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	sort.SliceStable(collisions, func(i, j int) bool { return collisions[i].DistanceMM < collisions[j].DistanceMM })
+	return collisions, nil
+}
+
+// CheckCollisions returns the collisions between the geometries of the frame system at its
+// current inputs, and between them and the obstacles of the world state, which may be nil,
+// and those stored by the service, such as to check that the robot is clear before executing
+// a plan. See CheckCollisions.
+func (lfs *LiveFrameSystem) CheckCollisions(
+	ctx context.Context,
+	worldState *referenceframe.WorldState,
+	collisionBufferMM float64,
+) ([]Collision, error) {
+	inputs, err := lfs.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	worldState, err = WithStoredObstacles(ctx, lfs.svc, worldState)
+	if err != nil {
+		return nil, err
+	}
+	return CheckCollisions(lfs, inputs, worldState, collisionBufferMM)
+}
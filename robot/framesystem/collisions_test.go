@@ -0,0 +1,80 @@
+package framesystem_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestCheckCollisions(t *testing.T) {
+	ctx := context.Background()
+	cube := &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: 100, Y: 100, Z: 100}
+	var parts []*referenceframe.FrameSystemPart
+	for _, link := range []referenceframe.LinkConfig{
+		{ID: "base", Parent: referenceframe.World, Geometry: cube},
+		// mounted on the base, which it interpenetrates by design.
+		{ID: "mount", Parent: "base", Translation: r3.Vector{Z: 50}, Geometry: cube},
+		{ID: "pillar", Parent: referenceframe.World, Translation: r3.Vector{X: 80}, Geometry: cube},
+		{ID: "sign", Parent: referenceframe.World, Translation: r3.Vector{X: 1000}, Geometry: cube},
+	} {
+		link := link
+		lif, err := link.ParseConfig()
+		test.That(t, err, test.ShouldBeNil)
+		parts = append(parts, &referenceframe.FrameSystemPart{FrameConfig: lif})
+	}
+	fs, err := referenceframe.NewFrameSystem("test", parts, nil)
+	test.That(t, err, test.ShouldBeNil)
+	inputs := referenceframe.StartPositions(fs)
+
+	collisions, err := framesystem.CheckCollisions(fs, inputs, nil, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldHaveLength, 2)
+	for _, collision := range collisions {
+		test.That(t, collision.DistanceMM, test.ShouldAlmostEqual, -20)
+		test.That(t, collision.Frame2, test.ShouldEqual, "pillar_origin")
+	}
+	test.That(t, []string{collisions[0].Frame1, collisions[1].Frame1}, test.ShouldResemble, []string{"base_origin", "mount_origin"})
+
+	// obstacles are checked against the geometries of the frame system.
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}), r3.Vector{X: 20, Y: 20, Z: 20}, "lamp")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle})},
+		nil)
+	test.That(t, err, test.ShouldBeNil)
+	collisions, err = framesystem.CheckCollisions(fs, inputs, worldState, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldHaveLength, 3)
+	test.That(t, collisions[2].Frame1, test.ShouldEqual, "mount_origin")
+	test.That(t, collisions[2].Geometry2, test.ShouldEqual, "lamp")
+	test.That(t, collisions[2].Frame2, test.ShouldEqual, referenceframe.World)
+	test.That(t, collisions[2].DistanceMM, test.ShouldAlmostEqual, -10)
+
+	// geometries within the collision buffer are reported too.
+	collisions, err = framesystem.CheckCollisions(fs, inputs, nil, 800)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldHaveLength, 3)
+	test.That(t, collisions[2].Frame1, test.ShouldEqual, "pillar_origin")
+	test.That(t, collisions[2].Frame2, test.ShouldEqual, "sign_origin")
+	test.That(t, collisions[2].DistanceMM, test.ShouldBeBetween, 0, 800)
+
+	svc := inject.NewFrameSystemService("test")
+	svc.FrameSystemFunc = func(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error) {
+		return fs, nil
+	}
+	svc.CurrentInputsFunc = func(ctx context.Context) (map[string][]referenceframe.Input, map[string]referenceframe.InputEnabled, error) {
+		return inputs, nil, nil
+	}
+	liveFS, err := framesystem.NewLiveFrameSystem(ctx, svc, nil)
+	test.That(t, err, test.ShouldBeNil)
+	collisions, err = liveFS.CheckCollisions(ctx, nil, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldHaveLength, 2)
+}
@@ -79,6 +79,39 @@ type Service interface {
 	FrameSystem(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error)
 }
 
+// A CachedService is a frame system service that can return its frame system without reading
+// any components.
+type CachedService interface {
+	Service
+	// CachedFrameSystem returns the frame system of the machine, with the frames that move as
+	// its components report them where they were last reported.
+	CachedFrameSystem(ctx context.Context) (referenceframe.FrameSystem, error)
+}
+
+// CachedFrameSystem returns the frame system of the service without reading any components,
+// if it can, or else its frame system as FrameSystem returns it.
+func CachedFrameSystem(ctx context.Context, svc Service) (referenceframe.FrameSystem, error) {
+	if cached, ok := svc.(CachedService); ok {
+		return cached.CachedFrameSystem(ctx)
+	}
+	return svc.FrameSystem(ctx, nil)
+}
+
+// A Robot is a robot whose frame system changes at runtime. The robot package refers to this
+// package, so the robot it is cannot be named here.
+type Robot interface {
+	// RuntimeFrames returns the frames added to the robot's frame system at runtime, which
+	// are also served to remote clients. Changes to them publish FrameSystemUpdated events.
+	RuntimeFrames() *RuntimeFrames
+	// Obstacles returns the store of the obstacles of the robot's environment, which the
+	// motion service avoids and the frame system includes on every request, and which are
+	// also served to remote clients.
+	Obstacles() *ObstacleStore
+	// LiveFrameSystem returns the frame system of the robot, including the given additional
+	// transforms, with its inputs read from the robot's components on each query.
+	LiveFrameSystem(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (*LiveFrameSystem, error)
+}
+
 // FromDependencies is a helper for getting the framesystem from a collection of dependencies.
 func FromDependencies(deps resource.Dependencies) (Service, error) {
 	return resource.FromDependencies[Service](deps, InternalServiceName)
@@ -90,6 +123,7 @@ func New(ctx context.Context, deps resource.Dependencies, logger logging.Logger)
 		Named:      InternalServiceName.AsNamed(),
 		components: make(map[string]resource.Resource),
 		logger:     logger,
+		obstacles:  NewObstacleStore(logger.Sublogger("obstacles")),
 	}
 	if err := fs.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &Config{}}); err != nil {
 		return nil, err
@@ -107,10 +141,13 @@ func (svc *frameSystemService) Name() resource.Name {
 }
 
 // Config is a slice of *config.FrameSystemPart.
+// Odometry drives the frames of the parts it names by where their movement sensors report
+// they are.
 type Config struct {
 	resource.TriviallyValidateConfig
 	Parts                []*referenceframe.FrameSystemPart
 	AdditionalTransforms []*referenceframe.LinkInFrame
+	Odometry             map[string]resource.OdometryConfig
 }
 
 // String prints out a table of each frame in the system, with columns of name, parent, translation and orientation.
@@ -153,9 +190,14 @@ type frameSystemService struct {
 	resource.TriviallyCloseable
 	components map[string]resource.Resource
 	logger     logging.Logger
+	obstacles  *ObstacleStore
+
+	parts    []*referenceframe.FrameSystemPart
+	odometry map[string]resource.OdometryConfig
+	partsMu  sync.RWMutex
 
-	parts   []*referenceframe.FrameSystemPart
-	partsMu sync.RWMutex
+	odometryMu   sync.Mutex
+	lastOdometry map[string]spatialmath.Pose
 }
 
 // Reconfigure will rebuild the frame system from the newly updated robot.
@@ -187,6 +229,10 @@ func (svc *frameSystemService) Reconfigure(ctx context.Context, deps resource.De
 		return err
 	}
 	svc.parts = sortedParts
+	svc.odometry = fsCfg.Odometry
+	svc.odometryMu.Lock()
+	svc.lastOdometry = map[string]spatialmath.Pose{}
+	svc.odometryMu.Unlock()
 	svc.logger.Debugf("reconfigured robot frame system: %v", (&Config{Parts: sortedParts}).String())
 	return nil
 }
@@ -284,14 +330,87 @@ func (svc *frameSystemService) CurrentInputs(
 	return input, resources, nil
 }
 
-// FrameSystem returns the frame system of the robot.
+// FrameSystem returns the frame system of the robot, including the transforms of its stored
+// obstacles, with the frames driven by odometry where their movement sensors report them now.
 func (svc *frameSystemService) FrameSystem(
 	ctx context.Context,
 	additionalTransforms []*referenceframe.LinkInFrame,
 ) (referenceframe.FrameSystem, error) {
-	_, span := trace.StartSpan(ctx, "services::framesystem::FrameSystem")
+	ctx, span := trace.StartSpan(ctx, "services::framesystem::FrameSystem")
 	defer span.End()
-	return referenceframe.NewFrameSystem(LocalFrameSystemName, svc.parts, additionalTransforms)
+	stored, err := svc.obstacles.Transforms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	svc.partsMu.RLock()
+	parts, odometry, components := svc.parts, svc.odometry, svc.components
+	svc.partsMu.RUnlock()
+	parts, err = svc.withOdometry(ctx, parts, odometry, components)
+	if err != nil {
+		return nil, err
+	}
+	transforms := append([]*referenceframe.LinkInFrame{}, additionalTransforms...)
+	transforms = append(transforms, connectedTransforms(parts, additionalTransforms, stored)...)
+	return referenceframe.NewFrameSystem(LocalFrameSystemName, parts, transforms)
+}
+
+// CachedFrameSystem returns the frame system of the robot, including the transforms of its
+// stored obstacles, with the frames driven by odometry where their movement sensors last
+// reported them. Unlike FrameSystem, it reads no components.
+func (svc *frameSystemService) CachedFrameSystem(ctx context.Context) (referenceframe.FrameSystem, error) {
+	stored, err := svc.obstacles.Transforms(ctx)
+	if err != nil {
+		return nil, err
+	}
+	svc.partsMu.RLock()
+	parts, odometry := svc.parts, svc.odometry
+	svc.partsMu.RUnlock()
+	parts = svc.withLastOdometry(parts, odometry)
+	return referenceframe.NewFrameSystem(LocalFrameSystemName, parts, connectedTransforms(parts, nil, stored))
+}
+
+// Obstacles returns the store of the obstacles of the robot's environment.
+func (svc *frameSystemService) Obstacles() *ObstacleStore {
+	return svc.obstacles
+}
+
+// connectedTransforms returns the stored transforms that connect to the world frame through
+// the given parts and additional transforms, leaving out those named like any of them and
+// those whose parent is missing, such as after the component they were placed on was removed.
+func connectedTransforms(
+	parts []*referenceframe.FrameSystemPart,
+	additionalTransforms, stored []*referenceframe.LinkInFrame,
+) []*referenceframe.LinkInFrame {
+	connected := map[string]bool{referenceframe.World: true}
+	for _, part := range parts {
+		connected[part.FrameConfig.Name()] = true
+	}
+	for _, transform := range additionalTransforms {
+		connected[transform.Name()] = true
+	}
+	candidates := make([]*referenceframe.LinkInFrame, 0, len(stored))
+	for _, transform := range stored {
+		if !connected[transform.Name()] {
+			candidates = append(candidates, transform)
+		}
+	}
+	var added []*referenceframe.LinkInFrame
+	// stored transforms may be parented to each other, so add them until no more connect.
+	for progress := true; progress; {
+		progress = false
+		remaining := candidates[:0]
+		for _, transform := range candidates {
+			if !connected[transform.Parent()] || connected[transform.Name()] {
+				remaining = append(remaining, transform)
+				continue
+			}
+			added = append(added, transform)
+			connected[transform.Name()] = true
+			progress = true
+		}
+		candidates = remaining
+	}
+	return added
 }
 
 // TransformPointCloud applies the same pose offset to each point in a single pointcloud and returns the transformed point cloud.
@@ -0,0 +1,76 @@
+package framesystem
+
+import (
+	"context"
+
+	"go.opencensus.io/trace"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// A LiveFrameSystem is a frame system whose inputs are read from the components of the robot,
+// such as the joint positions of arms and the positions of gantries, on each query, so that
+// callers do not have to collect them. Its frames are those of the robot when it was
+// returned; it does not follow later reconfigurations.
+type LiveFrameSystem struct {
+	referenceframe.FrameSystem
+	svc Service
+}
+
+// NewLiveFrameSystem returns the frame system of the service, including the given additional
+// transforms, with its inputs read from the components of the service.
+func NewLiveFrameSystem(
+	ctx context.Context,
+	svc Service,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) (*LiveFrameSystem, error) {
+	fs, err := svc.FrameSystem(ctx, additionalTransforms)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveFrameSystem{FrameSystem: fs, svc: svc}, nil
+}
+
+// CurrentInputs returns the current inputs of each frame of the frame system.
+func (lfs *LiveFrameSystem) CurrentInputs(ctx context.Context) (map[string][]referenceframe.Input, error) {
+	current, _, err := lfs.svc.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	inputs := referenceframe.StartPositions(lfs)
+	for name := range inputs {
+		if input, ok := current[name]; ok {
+			inputs[name] = input
+		}
+	}
+	return inputs, nil
+}
+
+// CurrentTransform transforms the given transformable to the destination frame at the current
+// inputs of the frame system.
+func (lfs *LiveFrameSystem) CurrentTransform(
+	ctx context.Context,
+	tf referenceframe.Transformable,
+	dst string,
+) (referenceframe.Transformable, error) {
+	ctx, span := trace.StartSpan(ctx, "services::framesystem::LiveFrameSystem::CurrentTransform")
+	defer span.End()
+
+	inputs, err := lfs.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lfs.Transform(inputs, tf, dst)
+}
+
+// CurrentPose returns the current pose of the origin of the named frame in the destination
+// frame.
+func (lfs *LiveFrameSystem) CurrentPose(ctx context.Context, frame, dst string) (*referenceframe.PoseInFrame, error) {
+	tf, err := lfs.CurrentTransform(ctx, referenceframe.NewPoseInFrame(frame, spatialmath.NewZeroPose()), dst)
+	if err != nil {
+		return nil, err
+	}
+	pose, _ := tf.(*referenceframe.PoseInFrame)
+	return pose, nil
+}
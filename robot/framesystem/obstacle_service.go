@@ -0,0 +1,124 @@
+package framesystem
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ObstacleServiceName is the name of the gRPC service serving the obstacles of a robot's
+// environment. Its requests and responses are google.protobuf.Struct messages holding the
+// JSON form of the types below.
+const ObstacleServiceName = "viam.rdk.framesystem.v1.ObstacleService"
+
+// ObstacleUpdater puts and removes the obstacles of a robot's environment at runtime.
+type ObstacleUpdater interface {
+	// PutObstacles adds a set of obstacles, or replaces the set of the same name, naming where
+	// it comes from. A set put with a positive TTL is dropped after it.
+	PutObstacles(ctx context.Context, obstacles config.ObstacleConfig, source string, ttl time.Duration) error
+	// RemoveObstacles removes a set of obstacles put at runtime.
+	RemoveObstacles(ctx context.Context, name string) error
+	// ObstacleSets returns the sets of obstacles that have not expired, sorted by name.
+	ObstacleSets(ctx context.Context) ([]ObstacleSet, error)
+}
+
+var _ ObstacleUpdater = (*ObstacleStore)(nil)
+
+type obstacleRequest struct {
+	Name      string                 `json:"name,omitempty"`
+	Obstacles *config.ObstacleConfig `json:"obstacles,omitempty"`
+	Source    string                 `json:"source,omitempty"`
+	TTL       string                 `json:"ttl,omitempty"`
+}
+
+type obstacleSetsResponse struct {
+	Sets []ObstacleSet `json:"sets"`
+}
+
+// An ObstacleServer serves the obstacles of a robot's environment with ObstacleServiceDesc.
+type ObstacleServer struct {
+	obstacles ObstacleUpdater
+}
+
+// NewObstacleServer returns a server for the given obstacles.
+func NewObstacleServer(obstacles ObstacleUpdater) *ObstacleServer {
+	return &ObstacleServer{obstacles: obstacles}
+}
+
+func (s *ObstacleServer) putObstacles(ctx context.Context, req obstacleRequest) (interface{}, error) {
+	if req.Obstacles == nil {
+		return nil, status.Error(codes.InvalidArgument, "obstacles are required")
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %v", err)
+		}
+	}
+	return struct{}{}, s.obstacles.PutObstacles(ctx, *req.Obstacles, req.Source, ttl)
+}
+
+func (s *ObstacleServer) removeObstacles(ctx context.Context, req obstacleRequest) (interface{}, error) {
+	return struct{}{}, s.obstacles.RemoveObstacles(ctx, req.Name)
+}
+
+func (s *ObstacleServer) listObstacles(ctx context.Context, _ obstacleRequest) (interface{}, error) {
+	sets, err := s.obstacles.ObstacleSets(ctx)
+	return obstacleSetsResponse{Sets: sets}, err
+}
+
+// ObstacleServiceDesc describes the gRPC service serving the obstacles of a robot's
+// environment. It is served with an ObstacleServer.
+var ObstacleServiceDesc = structrpc.ServiceDesc(ObstacleServiceName, "rdk/robot/framesystem",
+	structrpc.Unary("PutObstacles", (*ObstacleServer).putObstacles),
+	structrpc.Unary("RemoveObstacles", (*ObstacleServer).removeObstacles),
+	structrpc.Unary("ListObstacles", (*ObstacleServer).listObstacles),
+)
+
+// An ObstacleClient is the ObstacleUpdater of a robot served over a connection to it.
+type ObstacleClient struct {
+	client *structrpc.Client
+}
+
+var _ ObstacleUpdater = (*ObstacleClient)(nil)
+
+// NewObstacleClient returns a client of the obstacles served over the given connection.
+func NewObstacleClient(conn grpc.ClientConnInterface) *ObstacleClient {
+	return &ObstacleClient{client: structrpc.NewClient(conn, ObstacleServiceName)}
+}
+
+// PutObstacles adds a set of obstacles, or replaces the set of the same name, naming where it
+// comes from. A set put with a positive TTL is dropped after it.
+func (c *ObstacleClient) PutObstacles(
+	ctx context.Context,
+	obstacles config.ObstacleConfig,
+	source string,
+	ttl time.Duration,
+) error {
+	req := obstacleRequest{Obstacles: &obstacles, Source: source}
+	if ttl > 0 {
+		req.TTL = ttl.String()
+	}
+	return c.client.Invoke(ctx, "PutObstacles", req, nil)
+}
+
+// RemoveObstacles removes a set of obstacles put at runtime.
+func (c *ObstacleClient) RemoveObstacles(ctx context.Context, name string) error {
+	return c.client.Invoke(ctx, "RemoveObstacles", obstacleRequest{Name: name}, nil)
+}
+
+// ObstacleSets returns the sets of obstacles that have not expired, sorted by name.
+func (c *ObstacleClient) ObstacleSets(ctx context.Context) ([]ObstacleSet, error) {
+	var resp obstacleSetsResponse
+	if err := c.client.Invoke(ctx, "ListObstacles", obstacleRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Sets, nil
+}
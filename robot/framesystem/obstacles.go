@@ -0,0 +1,205 @@
+package framesystem
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// ConfigObstacleSource is the source of the sets of obstacles configured for the robot.
+	ConfigObstacleSource = "config"
+	// APIObstacleSource is the source of the sets of obstacles put without naming their source.
+	APIObstacleSource = "api"
+)
+
+// An ObstacleSet is a named set of obstacles and transforms kept by an ObstacleStore.
+type ObstacleSet struct {
+	config.ObstacleConfig
+	// Source is where the set comes from: ConfigObstacleSource for the sets configured for the
+	// robot, or whatever named it when putting it, such as a vision service detecting it.
+	Source string `json:"source"`
+	// Expires is when the set is dropped from the store, or nil if it is kept until removed.
+	Expires *time.Time `json:"expires,omitempty"`
+}
+
+func (set ObstacleSet) expired(now time.Time) bool {
+	return set.Expires != nil && !now.Before(*set.Expires)
+}
+
+// An ObstacleStore keeps the obstacles and transforms of a robot's environment, from its
+// config, from detections, or put through the API, so that the motion service avoids them and
+// the frame system includes them on every request without each request specifying them.
+type ObstacleStore struct {
+	logger logging.Logger
+
+	mu   sync.Mutex
+	sets map[string]ObstacleSet
+}
+
+// NewObstacleStore returns an empty store of obstacles.
+func NewObstacleStore(logger logging.Logger) *ObstacleStore {
+	return &ObstacleStore{logger: logger, sets: map[string]ObstacleSet{}}
+}
+
+// Reconfigure replaces the sets configured for the robot with those given. Configured sets
+// replace sets of the same name put at runtime.
+func (s *ObstacleStore) Reconfigure(confs []config.ObstacleConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, set := range s.sets {
+		if set.Source == ConfigObstacleSource {
+			delete(s.sets, name)
+		}
+	}
+	for _, conf := range confs {
+		if previous, ok := s.sets[conf.Name]; ok {
+			s.logger.Warnw("configured obstacles replace obstacles of the same name", "name", conf.Name, "source", previous.Source)
+		}
+		s.sets[conf.Name] = ObstacleSet{ObstacleConfig: conf, Source: ConfigObstacleSource}
+	}
+}
+
+// PutObstacles adds a set of obstacles, or replaces the set of the same name, naming where it
+// comes from, such as the vision service detecting it. A set put with a positive TTL is
+// dropped after it, so that detections that are not refreshed do not linger. Sets configured
+// for the robot cannot be replaced.
+func (s *ObstacleStore) PutObstacles(ctx context.Context, conf config.ObstacleConfig, source string, ttl time.Duration) error {
+	if err := conf.Validate("obstacles"); err != nil {
+		return err
+	}
+	if source == "" {
+		source = APIObstacleSource
+	}
+	if source == ConfigObstacleSource {
+		return errors.Errorf("source %q is reserved for configured obstacles", ConfigObstacleSource)
+	}
+	set := ObstacleSet{ObstacleConfig: conf, Source: source}
+	if ttl > 0 {
+		expires := time.Now().Add(ttl)
+		set.Expires = &expires
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if previous, ok := s.sets[conf.Name]; ok && previous.Source == ConfigObstacleSource {
+		return errors.Errorf("obstacles %q are configured; change the config to change them", conf.Name)
+	}
+	s.sets[conf.Name] = set
+	s.logger.CDebugw(ctx, "put obstacles", "name", conf.Name, "source", source, "ttl", ttl)
+	return nil
+}
+
+// RemoveObstacles removes a set of obstacles put at runtime.
+func (s *ObstacleStore) RemoveObstacles(ctx context.Context, name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set, ok := s.sets[name]
+	if !ok || set.expired(time.Now()) {
+		return errors.Errorf("no obstacles %q", name)
+	}
+	if set.Source == ConfigObstacleSource {
+		return errors.Errorf("obstacles %q are configured; change the config to remove them", name)
+	}
+	delete(s.sets, name)
+	s.logger.CDebugw(ctx, "removed obstacles", "name", name)
+	return nil
+}
+
+// ObstacleSets returns the sets of obstacles in the store that have not expired, sorted by
+// name.
+func (s *ObstacleStore) ObstacleSets(ctx context.Context) ([]ObstacleSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	sets := make([]ObstacleSet, 0, len(s.sets))
+	for name, set := range s.sets {
+		if set.expired(now) {
+			delete(s.sets, name)
+			continue
+		}
+		sets = append(sets, set)
+	}
+	sort.Slice(sets, func(i, j int) bool { return sets[i].Name < sets[j].Name })
+	return sets, nil
+}
+
+// Transforms returns the transforms of the sets of obstacles in the store.
+func (s *ObstacleStore) Transforms(ctx context.Context) ([]*referenceframe.LinkInFrame, error) {
+	sets, err := s.ObstacleSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var transforms []*referenceframe.LinkInFrame
+	for _, set := range sets {
+		_, setTransforms, err := set.ParseConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid obstacles %q", set.Name)
+		}
+		transforms = append(transforms, setTransforms...)
+	}
+	return transforms, nil
+}
+
+// WorldState returns the given world state, which may be nil, with the obstacles of the
+// store added. Obstacles of the given world state replace those of the same name in the
+// store. The transforms of the store are not added; frame systems include them.
+func (s *ObstacleStore) WorldState(ctx context.Context, worldState *referenceframe.WorldState) (*referenceframe.WorldState, error) {
+	sets, err := s.ObstacleSets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(sets) == 0 {
+		return worldState, nil
+	}
+	names := worldState.ObstacleNames()
+	obstacles := append([]*referenceframe.GeometriesInFrame{}, worldState.Obstacles()...)
+	for _, set := range sets {
+		geometries, _, err := set.ParseConfig()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid obstacles %q", set.Name)
+		}
+		var kept []spatialmath.Geometry
+		for _, geometry := range geometries.Geometries() {
+			if names[geometry.Label()] {
+				continue
+			}
+			names[geometry.Label()] = true
+			kept = append(kept, geometry)
+		}
+		if len(kept) != 0 {
+			obstacles = append(obstacles, referenceframe.NewGeometriesInFrame(geometries.Parent(), kept))
+		}
+	}
+	return referenceframe.NewWorldState(obstacles, worldState.Transforms())
+}
+
+// An ObstacleKeeper is a frame system service keeping a store of obstacles, whose transforms
+// its frame system includes.
+type ObstacleKeeper interface {
+	Service
+	// Obstacles returns the store of the obstacles of the robot's environment.
+	Obstacles() *ObstacleStore
+}
+
+// WithStoredObstacles returns the given world state, which may be nil, with the obstacles
+// stored by the service added, if it keeps any.
+func WithStoredObstacles(
+	ctx context.Context,
+	svc Service,
+	worldState *referenceframe.WorldState,
+) (*referenceframe.WorldState, error) {
+	keeper, ok := svc.(ObstacleKeeper)
+	if !ok {
+		return worldState, nil
+	}
+	return keeper.Obstacles().WorldState(ctx, worldState)
+}
@@ -0,0 +1,136 @@
+package framesystem_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+var workcell = config.ObstacleConfig{
+	Name:       "workcell",
+	Geometries: []spatialmath.GeometryConfig{{Type: spatialmath.BoxType, X: 1000, Y: 1000, Z: 10}},
+	Transforms: []referenceframe.LinkConfig{{ID: "table", Parent: referenceframe.World, Translation: r3.Vector{X: 500}}},
+}
+
+func TestObstacleStore(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	svc, err := framesystem.New(ctx, resource.Dependencies{}, logger)
+	test.That(t, err, test.ShouldBeNil)
+	store := svc.(framesystem.ObstacleKeeper).Obstacles()
+
+	store.Reconfigure([]config.ObstacleConfig{workcell})
+	cup := config.ObstacleConfig{
+		Name:       "cup",
+		Parent:     "table",
+		Geometries: []spatialmath.GeometryConfig{{Type: spatialmath.SphereType, R: 40, Label: "cup"}},
+	}
+	test.That(t, store.PutObstacles(ctx, cup, "detector", time.Hour), test.ShouldBeNil)
+	test.That(t, store.PutObstacles(ctx, workcell, "", 0), test.ShouldNotBeNil)
+	test.That(t, store.PutObstacles(ctx, cup, framesystem.ConfigObstacleSource, 0), test.ShouldNotBeNil)
+	test.That(t, store.PutObstacles(ctx, config.ObstacleConfig{Name: "empty"}, "", 0), test.ShouldNotBeNil)
+	test.That(t, store.RemoveObstacles(ctx, "workcell"), test.ShouldNotBeNil)
+
+	sets, err := store.ObstacleSets(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sets, test.ShouldHaveLength, 2)
+	test.That(t, sets[0].Name, test.ShouldEqual, "cup")
+	test.That(t, sets[0].Source, test.ShouldEqual, "detector")
+	test.That(t, sets[0].Expires, test.ShouldNotBeNil)
+	test.That(t, sets[1].Source, test.ShouldEqual, framesystem.ConfigObstacleSource)
+
+	// the frame system includes the stored transforms, and the world state the stored obstacles.
+	fs, err := svc.FrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.Frame("table"), test.ShouldNotBeNil)
+	lamp, err := spatialmath.NewSphere(spatialmath.NewZeroPose(), 10, "lamp")
+	test.That(t, err, test.ShouldBeNil)
+	requested, err := referenceframe.NewWorldState(
+		[]*referenceframe.GeometriesInFrame{referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{lamp})},
+		nil)
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := framesystem.WithStoredObstacles(ctx, svc, requested)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, worldState.ObstacleNames(), test.ShouldResemble, map[string]bool{"lamp": true, "cup": true, "workcell_0": true})
+	obstacles, err := worldState.ObstaclesInWorldFrame(fs, referenceframe.StartPositions(fs))
+	test.That(t, err, test.ShouldBeNil)
+	for _, obstacle := range obstacles.Geometries() {
+		if obstacle.Label() == "cup" {
+			test.That(t, obstacle.Pose().Point(), test.ShouldResemble, r3.Vector{X: 500})
+		}
+	}
+
+	// transforms whose parent is missing are left out of the frame system.
+	orphan := config.ObstacleConfig{
+		Name:       "orphan",
+		Transforms: []referenceframe.LinkConfig{{ID: "shelf", Parent: "missing"}},
+	}
+	test.That(t, store.PutObstacles(ctx, orphan, "", 0), test.ShouldBeNil)
+	fs, err = svc.FrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.Frame("shelf"), test.ShouldBeNil)
+	test.That(t, store.RemoveObstacles(ctx, "orphan"), test.ShouldBeNil)
+
+	// sets put with a TTL expire.
+	test.That(t, store.PutObstacles(ctx, cup, "detector", time.Nanosecond), test.ShouldBeNil)
+	time.Sleep(time.Millisecond)
+	sets, err = store.ObstacleSets(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sets, test.ShouldHaveLength, 1)
+	test.That(t, store.RemoveObstacles(ctx, "cup"), test.ShouldNotBeNil)
+
+	store.Reconfigure(nil)
+	worldState, err = framesystem.WithStoredObstacles(ctx, svc, requested)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, worldState, test.ShouldEqual, requested)
+}
+
+func TestObstacleClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	obstacles := robotClient.Obstacles()
+	test.That(t, obstacles.PutObstacles(ctx, workcell, "", time.Hour), test.ShouldBeNil)
+	test.That(t, obstacles.PutObstacles(ctx, config.ObstacleConfig{Name: "empty"}, "", 0), test.ShouldNotBeNil)
+	sets, err := obstacles.ObstacleSets(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sets, test.ShouldHaveLength, 1)
+	test.That(t, sets[0].Name, test.ShouldEqual, "workcell")
+	test.That(t, sets[0].Source, test.ShouldEqual, framesystem.APIObstacleSource)
+	test.That(t, sets[0].Expires, test.ShouldNotBeNil)
+	test.That(t, sets[0].Geometries, test.ShouldHaveLength, 1)
+	test.That(t, sets[0].Geometries[0].X, test.ShouldEqual, 1000)
+	test.That(t, sets[0].Transforms[0].ID, test.ShouldEqual, "table")
+
+	stored, err := r.Obstacles().ObstacleSets(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, stored, test.ShouldHaveLength, 1)
+
+	test.That(t, obstacles.RemoveObstacles(ctx, "workcell"), test.ShouldBeNil)
+	sets, err = obstacles.ObstacleSets(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sets, test.ShouldBeEmpty)
+}
@@ -0,0 +1,132 @@
+package framesystem
+
+import (
+	"context"
+
+	geo "github.com/kellydunn/golang-geo"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// An OdometrySource reports where a component is, as a movement sensor does: its position, as a
+// geographic point relative to an origin, and its orientation.
+type OdometrySource interface {
+	Position(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error)
+	Orientation(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error)
+}
+
+// odometryPose returns the pose the source reports, from where it reports being at the origin.
+func odometryPose(ctx context.Context, source OdometrySource, origin *geo.Point) (spatialmath.Pose, error) {
+	position, _, err := source.Position(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	orientation, err := source.Orientation(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return spatialmath.NewPose(spatialmath.GeoPointToPoint(position, origin), orientation), nil
+}
+
+// withOdometry returns the parts with the frames driven by odometry moved by the poses their
+// movement sensors, among the components, report, from the poses they are configured at. A
+// frame whose movement sensor cannot be read keeps the pose last read, if any.
+func (svc *frameSystemService) withOdometry(
+	ctx context.Context,
+	parts []*referenceframe.FrameSystemPart,
+	odometry map[string]resource.OdometryConfig,
+	components map[string]resource.Resource,
+) ([]*referenceframe.FrameSystemPart, error) {
+	if len(odometry) == 0 {
+		return parts, nil
+	}
+	driven := make([]*referenceframe.FrameSystemPart, 0, len(parts))
+	for _, part := range parts {
+		conf, ok := odometry[part.FrameConfig.Name()]
+		if !ok {
+			driven = append(driven, part)
+			continue
+		}
+		pose, err := svc.readOdometry(ctx, part.FrameConfig.Name(), conf, components[conf.MovementSensor])
+		if err != nil {
+			return nil, err
+		}
+		driven = append(driven, movedPart(part, pose))
+	}
+	return driven, nil
+}
+
+// withLastOdometry returns the parts with the frames driven by odometry moved by the poses last
+// read from their movement sensors, leaving those not read yet at the poses they are configured
+// at. No movement sensor is read.
+func (svc *frameSystemService) withLastOdometry(
+	parts []*referenceframe.FrameSystemPart,
+	odometry map[string]resource.OdometryConfig,
+) []*referenceframe.FrameSystemPart {
+	if len(odometry) == 0 {
+		return parts
+	}
+	svc.odometryMu.Lock()
+	defer svc.odometryMu.Unlock()
+	driven := make([]*referenceframe.FrameSystemPart, 0, len(parts))
+	for _, part := range parts {
+		pose, ok := svc.lastOdometry[part.FrameConfig.Name()]
+		if _, isDriven := odometry[part.FrameConfig.Name()]; !isDriven || !ok {
+			driven = append(driven, part)
+			continue
+		}
+		driven = append(driven, movedPart(part, pose))
+	}
+	return driven
+}
+
+// movedPart returns the part with its frame moved by the pose from the pose it is configured at.
+func movedPart(part *referenceframe.FrameSystemPart, pose spatialmath.Pose) *referenceframe.FrameSystemPart {
+	frameConfig := part.FrameConfig
+	return &referenceframe.FrameSystemPart{
+		FrameConfig: referenceframe.NewLinkInFrame(
+			frameConfig.Parent(),
+			spatialmath.Compose(frameConfig.Pose(), pose),
+			frameConfig.Name(),
+			frameConfig.Geometry(),
+		),
+		ModelFrame: part.ModelFrame,
+	}
+}
+
+// readOdometry returns the pose the movement sensor of the named frame reports, or the pose last
+// read if it cannot be read now. The sensor is nil if it is not a component of the robot.
+func (svc *frameSystemService) readOdometry(
+	ctx context.Context,
+	frame string,
+	conf resource.OdometryConfig,
+	sensor resource.Resource,
+) (spatialmath.Pose, error) {
+	var pose spatialmath.Pose
+	var err error
+	source, isSource := sensor.(OdometrySource)
+	switch {
+	case sensor == nil:
+		err = DependencyNotFoundError(conf.MovementSensor)
+	case !isSource:
+		err = errors.Errorf("%q cannot report odometry", conf.MovementSensor)
+	default:
+		pose, err = odometryPose(ctx, source, conf.Origin())
+	}
+
+	svc.odometryMu.Lock()
+	defer svc.odometryMu.Unlock()
+	if err == nil {
+		svc.lastOdometry[frame] = pose
+		return pose, nil
+	}
+	last, ok := svc.lastOdometry[frame]
+	if !ok {
+		return nil, errors.Wrapf(err, "cannot read odometry of frame %q", frame)
+	}
+	svc.logger.CWarnw(ctx, "cannot read odometry; using the pose last read", "frame", frame, "error", err)
+	return last, nil
+}
@@ -0,0 +1,110 @@
+package framesystem_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	geo "github.com/kellydunn/golang-geo"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestOdometryFrames(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	position := geo.NewPoint(0, 0)
+	var orientation spatialmath.Orientation = spatialmath.NewZeroOrientation()
+	var readErr error
+	odom := inject.NewMovementSensor("odom")
+	odom.PositionFunc = func(ctx context.Context, extra map[string]interface{}) (*geo.Point, float64, error) {
+		return position, 0, readErr
+	}
+	odom.OrientationFunc = func(ctx context.Context, extra map[string]interface{}) (spatialmath.Orientation, error) {
+		return orientation, readErr
+	}
+	deps := resource.Dependencies{
+		movementsensor.Named("odom"): odom,
+		base.Named("base1"):          inject.NewBase("base1"),
+	}
+	svc, err := framesystem.New(ctx, deps, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the base starts 1m along x in the world, and an arm is mounted 100mm above it.
+	basePose := spatialmath.NewPoseFromPoint(r3.Vector{X: 1000})
+	armOffset := spatialmath.NewPoseFromPoint(r3.Vector{Z: 100})
+	parts := []*referenceframe.FrameSystemPart{
+		{FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, basePose, "base1", nil)},
+		{FrameConfig: referenceframe.NewLinkInFrame("base1", armOffset, "arm1", nil)},
+	}
+	test.That(t, svc.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &framesystem.Config{
+		Parts:    parts,
+		Odometry: map[string]resource.OdometryConfig{"base1": {MovementSensor: "odom"}},
+	}}), test.ShouldBeNil)
+
+	armInWorld := func() spatialmath.Pose {
+		t.Helper()
+		pif, err := svc.TransformPose(ctx, referenceframe.NewPoseInFrame("arm1", spatialmath.NewZeroPose()), referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return pif.Pose()
+	}
+	test.That(t, spatialmath.PoseAlmostEqual(armInWorld(), spatialmath.Compose(basePose, armOffset)), test.ShouldBeTrue)
+
+	// the arm moves with the base as its odometry changes.
+	position = geo.NewPoint(0.00001, 0.00002)
+	orientation = &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90}
+	moved := spatialmath.NewPose(spatialmath.GeoPointToPoint(position, geo.NewPoint(0, 0)), orientation)
+	want := spatialmath.Compose(spatialmath.Compose(basePose, moved), armOffset)
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(armInWorld(), want, 1e-6), test.ShouldBeTrue)
+
+	// odometry that cannot be read leaves the base where it was last read.
+	readErr = errors.New("no odometry")
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(armInWorld(), want, 1e-6), test.ShouldBeTrue)
+
+	// the cached frame system places the base where its odometry was last read, without reading it.
+	cachedArmInWorld := func() spatialmath.Pose {
+		t.Helper()
+		fs, err := framesystem.CachedFrameSystem(ctx, svc)
+		test.That(t, err, test.ShouldBeNil)
+		pif, err := fs.Transform(
+			referenceframe.StartPositions(fs),
+			referenceframe.NewPoseInFrame("arm1", spatialmath.NewZeroPose()),
+			referenceframe.World,
+		)
+		test.That(t, err, test.ShouldBeNil)
+		return pif.(*referenceframe.PoseInFrame).Pose()
+	}
+	readErr = nil
+	position = geo.NewPoint(0.00003, 0)
+	test.That(t, spatialmath.PoseAlmostCoincidentEps(cachedArmInWorld(), want, 1e-6), test.ShouldBeTrue)
+
+	// a frame whose odometry was never read is not placed, or is at its configured pose in the
+	// cached frame system.
+	readErr = errors.New("no odometry")
+	test.That(t, svc.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &framesystem.Config{
+		Parts:    parts,
+		Odometry: map[string]resource.OdometryConfig{"base1": {MovementSensor: "odom"}},
+	}}), test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(cachedArmInWorld(), spatialmath.Compose(basePose, armOffset)), test.ShouldBeTrue)
+	_, err = svc.FrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot read odometry of frame \"base1\"")
+
+	test.That(t, svc.Reconfigure(ctx, deps, resource.Config{ConvertedAttributes: &framesystem.Config{
+		Parts:    parts,
+		Odometry: map[string]resource.OdometryConfig{"base1": {MovementSensor: "base1"}},
+	}}), test.ShouldBeNil)
+	_, err = svc.FrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot report odometry")
+}
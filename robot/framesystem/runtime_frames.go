@@ -0,0 +1,244 @@
+package framesystem
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// FrameUpdater adds, moves, and re-parents frames of a robot's frame system at runtime, such
+// as the frame of a fixture a camera has just detected, without config edits and restarts.
+// Only frames added at runtime can be changed; configured frames change with the config.
+type FrameUpdater interface {
+	// AddTransform adds a frame to the frame system. Its parent may be any frame of the frame
+	// system, including another frame added at runtime.
+	AddTransform(ctx context.Context, frame referenceframe.LinkConfig) error
+	// UpdateFrame moves or re-parents a frame added at runtime, replacing its pose, parent,
+	// and geometry with those given.
+	UpdateFrame(ctx context.Context, frame referenceframe.LinkConfig) error
+	// RemoveFrame removes a frame added at runtime. A frame that is the parent of other frames
+	// added at runtime cannot be removed.
+	RemoveFrame(ctx context.Context, name string) error
+	// RuntimeFrames returns the frames added at runtime, sorted by name.
+	RuntimeFrames(ctx context.Context) ([]referenceframe.LinkConfig, error)
+}
+
+// RuntimeFrames keeps the frames added to a robot's frame system at runtime.
+type RuntimeFrames struct {
+	logger   logging.Logger
+	onChange func(ctx context.Context) error
+	onUpdate func(frame string)
+
+	// opMu serializes changes so that a failed change can be reverted. mu guards the frames
+	// and is not held while calling onChange, which reads the frame system parts.
+	opMu   sync.Mutex
+	mu     sync.Mutex
+	frames map[string]referenceframe.LinkConfig
+}
+
+var _ FrameUpdater = (*RuntimeFrames)(nil)
+
+// NewRuntimeFrames returns an empty set of runtime frames. onChange is called whenever the
+// frames change and should rebuild the frame system, failing if a frame cannot be part of
+// it; if it fails, the change is reverted. onUpdate is then called with the name of the
+// frame that changed.
+func NewRuntimeFrames(
+	logger logging.Logger,
+	onChange func(ctx context.Context) error,
+	onUpdate func(frame string),
+) *RuntimeFrames {
+	return &RuntimeFrames{
+		logger:   logger,
+		onChange: onChange,
+		onUpdate: onUpdate,
+		frames:   map[string]referenceframe.LinkConfig{},
+	}
+}
+
+func validateRuntimeFrame(frame referenceframe.LinkConfig) error {
+	if frame.ID == "" {
+		return errors.New("frame name must not be empty")
+	}
+	if frame.ID == referenceframe.World {
+		return errors.Errorf("cannot give frame the name %s", referenceframe.World)
+	}
+	if frame.Parent == "" {
+		return errors.Errorf("parent of frame %q must not be empty", frame.ID)
+	}
+	if frame.Parent == frame.ID {
+		return errors.Errorf("frame %q cannot be its own parent", frame.ID)
+	}
+	if _, err := frame.ParseConfig(); err != nil {
+		return errors.Wrapf(err, "invalid frame %q", frame.ID)
+	}
+	return nil
+}
+
+// AddTransform adds a frame to the frame system. Its parent may be any frame of the frame
+// system, including another frame added at runtime.
+func (rf *RuntimeFrames) AddTransform(ctx context.Context, frame referenceframe.LinkConfig) error {
+	if err := validateRuntimeFrame(frame); err != nil {
+		return err
+	}
+	rf.opMu.Lock()
+	defer rf.opMu.Unlock()
+
+	rf.mu.Lock()
+	_, exists := rf.frames[frame.ID]
+	rf.mu.Unlock()
+	if exists {
+		return errors.Errorf("frame %q was already added; update it instead", frame.ID)
+	}
+	if err := rf.apply(ctx, frame.ID, &frame, nil); err != nil {
+		return err
+	}
+	rf.logger.CInfow(ctx, "added frame", "frame", frame.ID, "parent", frame.Parent)
+	return nil
+}
+
+// UpdateFrame moves or re-parents a frame added at runtime, replacing its pose, parent, and
+// geometry with those given.
+func (rf *RuntimeFrames) UpdateFrame(ctx context.Context, frame referenceframe.LinkConfig) error {
+	if err := validateRuntimeFrame(frame); err != nil {
+		return err
+	}
+	rf.opMu.Lock()
+	defer rf.opMu.Unlock()
+
+	rf.mu.Lock()
+	previous, exists := rf.frames[frame.ID]
+	rf.mu.Unlock()
+	if !exists {
+		return errors.Errorf("no frame %q was added at runtime", frame.ID)
+	}
+	if err := rf.apply(ctx, frame.ID, &frame, &previous); err != nil {
+		return err
+	}
+	rf.logger.CInfow(ctx, "updated frame", "frame", frame.ID, "parent", frame.Parent)
+	return nil
+}
+
+// RemoveFrame removes a frame added at runtime. A frame that is the parent of other frames
+// added at runtime cannot be removed.
+func (rf *RuntimeFrames) RemoveFrame(ctx context.Context, name string) error {
+	rf.opMu.Lock()
+	defer rf.opMu.Unlock()
+
+	rf.mu.Lock()
+	previous, exists := rf.frames[name]
+	var children []string
+	for _, frame := range rf.frames {
+		if frame.Parent == name {
+			children = append(children, frame.ID)
+		}
+	}
+	rf.mu.Unlock()
+	if !exists {
+		return errors.Errorf("no frame %q was added at runtime", name)
+	}
+	if len(children) != 0 {
+		sort.Strings(children)
+		return errors.Errorf("frame %q is the parent of frames %v; remove or re-parent them first", name, children)
+	}
+	if err := rf.apply(ctx, name, nil, &previous); err != nil {
+		return err
+	}
+	rf.logger.CInfow(ctx, "removed frame", "frame", name)
+	return nil
+}
+
+// apply records the frame, or its removal if nil, and rebuilds the frame system, restoring
+// the previous frame, or its absence if nil, if that fails.
+func (rf *RuntimeFrames) apply(ctx context.Context, name string, frame, previous *referenceframe.LinkConfig) error {
+	set := func(frame *referenceframe.LinkConfig) {
+		rf.mu.Lock()
+		defer rf.mu.Unlock()
+		if frame == nil {
+			delete(rf.frames, name)
+		} else {
+			rf.frames[name] = *frame
+		}
+	}
+	set(frame)
+	if err := rf.onChange(ctx); err != nil {
+		set(previous)
+		if revertErr := rf.onChange(ctx); revertErr != nil {
+			rf.logger.CErrorw(ctx, "failed to restore frame system after failed frame change", "frame", name, "error", revertErr)
+		}
+		return err
+	}
+	rf.onUpdate(name)
+	return nil
+}
+
+// RuntimeFrames returns the frames added at runtime, sorted by name.
+func (rf *RuntimeFrames) RuntimeFrames(ctx context.Context) ([]referenceframe.LinkConfig, error) {
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	frames := make([]referenceframe.LinkConfig, 0, len(rf.frames))
+	for _, frame := range rf.frames {
+		frames = append(frames, frame)
+	}
+	sort.Slice(frames, func(i, j int) bool { return frames[i].ID < frames[j].ID })
+	return frames, nil
+}
+
+// FrameSystemParts returns the parts of the frames added at runtime that are connected to the
+// world frame through the given parts, and the names of the frames left out, because their
+// parent is missing, such as after the component it belongs to was removed, or one of the
+// given parts has the same name.
+func (rf *RuntimeFrames) FrameSystemParts(
+	parts []*referenceframe.FrameSystemPart,
+) ([]*referenceframe.FrameSystemPart, []string, error) {
+	frames, err := rf.RuntimeFrames(context.Background())
+	if err != nil {
+		return nil, nil, err
+	}
+	connected := map[string]bool{referenceframe.World: true}
+	for _, part := range parts {
+		connected[part.FrameConfig.Name()] = true
+	}
+
+	var added []*referenceframe.FrameSystemPart
+	var leftOut []string
+	candidates := frames[:0]
+	for _, frame := range frames {
+		if connected[frame.ID] {
+			leftOut = append(leftOut, frame.ID)
+			continue
+		}
+		candidates = append(candidates, frame)
+	}
+	frames = candidates
+	// frames may be parented to other runtime frames, so add them until no more connect.
+	for progress := true; progress; {
+		progress = false
+		remaining := frames[:0]
+		for _, frame := range frames {
+			if !connected[frame.Parent] {
+				remaining = append(remaining, frame)
+				continue
+			}
+			frame := frame
+			lif, err := frame.ParseConfig()
+			if err != nil {
+				return nil, nil, err
+			}
+			added = append(added, &referenceframe.FrameSystemPart{FrameConfig: lif})
+			connected[frame.ID] = true
+			progress = true
+		}
+		frames = remaining
+	}
+
+	for _, frame := range frames {
+		leftOut = append(leftOut, frame.ID)
+	}
+	sort.Strings(leftOut)
+	return added, leftOut, nil
+}
@@ -0,0 +1,179 @@
+package framesystem_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+var fixture = referenceframe.LinkConfig{
+	ID:          "fixture",
+	Translation: r3.Vector{X: 300, Y: 100},
+	Geometry:    &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: 200, Y: 200, Z: 50},
+	Parent:      "camera",
+}
+
+func TestRuntimeFrames(t *testing.T) {
+	ctx := context.Background()
+	var changes int
+	var changeErr error
+	var updated []string
+	frames := framesystem.NewRuntimeFrames(logging.NewTestLogger(t),
+		func(ctx context.Context) error {
+			changes++
+			return changeErr
+		},
+		func(frame string) { updated = append(updated, frame) })
+
+	for _, tc := range []struct {
+		name   string
+		modify func(frame *referenceframe.LinkConfig)
+		errStr string
+	}{
+		{"no name", func(frame *referenceframe.LinkConfig) { frame.ID = "" }, "name"},
+		{"world", func(frame *referenceframe.LinkConfig) { frame.ID = referenceframe.World }, "world"},
+		{"no parent", func(frame *referenceframe.LinkConfig) { frame.Parent = "" }, "parent"},
+		{"own parent", func(frame *referenceframe.LinkConfig) { frame.Parent = frame.ID }, "own parent"},
+		{"bad geometry", func(frame *referenceframe.LinkConfig) {
+			frame.Geometry = &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: -1}
+		}, "invalid frame"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			frame := fixture
+			tc.modify(&frame)
+			err := frames.AddTransform(ctx, frame)
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+	test.That(t, changes, test.ShouldEqual, 0)
+
+	// an added frame is part of the frame system once its parent is.
+	test.That(t, frames.AddTransform(ctx, fixture), test.ShouldBeNil)
+	test.That(t, frames.AddTransform(ctx, fixture).Error(), test.ShouldContainSubstring, "already added")
+	test.That(t, changes, test.ShouldEqual, 1)
+	test.That(t, updated, test.ShouldResemble, []string{"fixture"})
+	added, err := frames.RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added, test.ShouldResemble, []referenceframe.LinkConfig{fixture})
+
+	parts, leftOut, err := frames.FrameSystemParts(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parts, test.ShouldBeEmpty)
+	test.That(t, leftOut, test.ShouldResemble, []string{"fixture"})
+
+	camera, err := (&referenceframe.LinkConfig{ID: "camera", Parent: referenceframe.World}).ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	cameraPart := &referenceframe.FrameSystemPart{FrameConfig: camera}
+	parts, leftOut, err = frames.FrameSystemParts([]*referenceframe.FrameSystemPart{cameraPart})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leftOut, test.ShouldBeEmpty)
+	test.That(t, parts, test.ShouldHaveLength, 1)
+	test.That(t, parts[0].FrameConfig.Name(), test.ShouldEqual, "fixture")
+	test.That(t, parts[0].FrameConfig.Parent(), test.ShouldEqual, "camera")
+	test.That(t, spatialmath.PoseAlmostEqual(parts[0].FrameConfig.Pose(), spatialmath.NewPoseFromPoint(fixture.Translation)),
+		test.ShouldBeTrue)
+
+	// frames may be children of other runtime frames, in any order.
+	part := referenceframe.LinkConfig{ID: "part", Translation: r3.Vector{Z: 50}, Parent: "fixture"}
+	test.That(t, frames.AddTransform(ctx, part), test.ShouldBeNil)
+	parts, leftOut, err = frames.FrameSystemParts([]*referenceframe.FrameSystemPart{cameraPart})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leftOut, test.ShouldBeEmpty)
+	test.That(t, parts, test.ShouldHaveLength, 2)
+	test.That(t, parts[1].FrameConfig.Name(), test.ShouldEqual, "part")
+
+	// frames with the name of another frame of the frame system are left out.
+	fixtureCamera, err := (&referenceframe.LinkConfig{ID: "fixture", Parent: "camera"}).ParseConfig()
+	test.That(t, err, test.ShouldBeNil)
+	_, leftOut, err = frames.FrameSystemParts([]*referenceframe.FrameSystemPart{
+		cameraPart,
+		{FrameConfig: fixtureCamera},
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leftOut, test.ShouldResemble, []string{"fixture"})
+
+	// a failed update is reverted.
+	moved := fixture
+	moved.Parent = referenceframe.World
+	moved.Translation = r3.Vector{X: 500}
+	changeErr = errors.New("frame system broken")
+	err = frames.UpdateFrame(ctx, moved)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "frame system broken")
+	added, err = frames.RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added[0], test.ShouldResemble, fixture)
+	test.That(t, updated, test.ShouldResemble, []string{"fixture", "part"})
+	changeErr = nil
+
+	// frames can be moved and re-parented.
+	test.That(t, frames.UpdateFrame(ctx, moved), test.ShouldBeNil)
+	test.That(t, updated, test.ShouldResemble, []string{"fixture", "part", "fixture"})
+	parts, leftOut, err = frames.FrameSystemParts(nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leftOut, test.ShouldBeEmpty)
+	test.That(t, parts[0].FrameConfig.Parent(), test.ShouldEqual, referenceframe.World)
+	err = frames.UpdateFrame(ctx, referenceframe.LinkConfig{ID: "other", Parent: referenceframe.World})
+	test.That(t, err, test.ShouldBeError, errors.New(`no frame "other" was added at runtime`))
+
+	// parents are removed after their children.
+	err = frames.RemoveFrame(ctx, "fixture")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "remove or re-parent them first")
+	test.That(t, frames.RemoveFrame(ctx, "part"), test.ShouldBeNil)
+	test.That(t, frames.RemoveFrame(ctx, "fixture"), test.ShouldBeNil)
+	test.That(t, frames.RemoveFrame(ctx, "fixture"), test.ShouldNotBeNil)
+	added, err = frames.RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added, test.ShouldBeEmpty)
+}
+
+func TestUpdateClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	frames := robotClient.RuntimeFrames()
+	test.That(t, frames.AddTransform(ctx, fixture), test.ShouldBeNil)
+	test.That(t, frames.AddTransform(ctx, referenceframe.LinkConfig{ID: "bad"}), test.ShouldNotBeNil)
+	added, err := frames.RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added, test.ShouldHaveLength, 1)
+	test.That(t, added[0].ID, test.ShouldEqual, "fixture")
+	test.That(t, added[0].Parent, test.ShouldEqual, "camera")
+	test.That(t, added[0].Geometry.X, test.ShouldEqual, 200)
+
+	moved := fixture
+	moved.Parent = referenceframe.World
+	test.That(t, frames.UpdateFrame(ctx, moved), test.ShouldBeNil)
+	added, err = r.RuntimeFrames().RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added[0].Parent, test.ShouldEqual, referenceframe.World)
+
+	test.That(t, frames.RemoveFrame(ctx, "fixture"), test.ShouldBeNil)
+	added, err = frames.RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, added, test.ShouldBeEmpty)
+}
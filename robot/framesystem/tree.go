@@ -0,0 +1,190 @@
+package framesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// A TreeFrame is a frame of a frame system with its pose and geometries in the world frame,
+// and the frames whose parent it is.
+type TreeFrame struct {
+	Name        string                                `json:"name"`
+	Parent      string                                `json:"parent,omitempty"`
+	Translation r3.Vector                             `json:"translation"`
+	Orientation *spatialmath.OrientationVectorDegrees `json:"orientation"`
+	Inputs      []float64                             `json:"inputs,omitempty"`
+	Geometries  []*spatialmath.GeometryConfig         `json:"geometries,omitempty"`
+	Children    []*TreeFrame                          `json:"children,omitempty"`
+}
+
+// Tree returns the kinematic tree of the frame system at the given inputs, rooted at the
+// world frame. Children are sorted by name.
+func Tree(fs referenceframe.FrameSystem, inputs map[string][]referenceframe.Input) (*TreeFrame, error) {
+	geometries, err := referenceframe.FrameSystemGeometries(fs, inputs)
+	if err != nil {
+		return nil, err
+	}
+	frames := map[string]*TreeFrame{
+		referenceframe.World: {
+			Name:        referenceframe.World,
+			Orientation: spatialmath.NewZeroOrientation().OrientationVectorDegrees(),
+		},
+	}
+	names := fs.FrameNames()
+	sort.Strings(names)
+	for _, name := range names {
+		frame := fs.Frame(name)
+		parent, err := fs.Parent(frame)
+		if err != nil {
+			return nil, err
+		}
+		tf, err := fs.Transform(inputs, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), referenceframe.World)
+		if err != nil {
+			return nil, err
+		}
+		pose := tf.(*referenceframe.PoseInFrame).Pose()
+		node := &TreeFrame{
+			Name:        name,
+			Parent:      parent.Name(),
+			Translation: pose.Point(),
+			Orientation: pose.Orientation().OrientationVectorDegrees(),
+		}
+		if len(frame.DoF()) > 0 {
+			frameInputs, err := referenceframe.GetFrameInputs(frame, inputs)
+			if err != nil {
+				return nil, err
+			}
+			node.Inputs = referenceframe.InputsToFloats(frameInputs)
+		}
+		if gif, ok := geometries[name]; ok {
+			for _, geometry := range gif.Geometries() {
+				config, err := spatialmath.NewGeometryConfig(geometry)
+				if err != nil {
+					return nil, err
+				}
+				node.Geometries = append(node.Geometries, config)
+			}
+		}
+		frames[name] = node
+	}
+	// names are sorted, so children are added in order.
+	for _, name := range names {
+		node := frames[name]
+		frames[node.Parent].Children = append(frames[node.Parent].Children, node)
+	}
+	return frames[referenceframe.World], nil
+}
+
+// walk calls f with each frame of the tree, parents before their children.
+func (tf *TreeFrame) walk(f func(frame *TreeFrame)) {
+	f(tf)
+	for _, child := range tf.Children {
+		child.walk(f)
+	}
+}
+
+const (
+	svgViewSize   = 400.
+	svgViewMargin = 40.
+)
+
+// SVG renders the origins of the frames of the tree, joined to their parents, viewed from
+// above (X right, Y up) and from the side (X right, Z up).
+func (tf *TreeFrame) SVG() []byte {
+	minPoint := r3.Vector{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxPoint := r3.Vector{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	positions := map[string]r3.Vector{}
+	tf.walk(func(frame *TreeFrame) {
+		positions[frame.Name] = frame.Translation
+		minPoint = r3.Vector{
+			X: math.Min(minPoint.X, frame.Translation.X),
+			Y: math.Min(minPoint.Y, frame.Translation.Y),
+			Z: math.Min(minPoint.Z, frame.Translation.Z),
+		}
+		maxPoint = r3.Vector{
+			X: math.Max(maxPoint.X, frame.Translation.X),
+			Y: math.Max(maxPoint.Y, frame.Translation.Y),
+			Z: math.Max(maxPoint.Z, frame.Translation.Z),
+		}
+	})
+	// both views share a scale, so that distances compare between them.
+	extent := math.Max(maxPoint.Sub(minPoint).Norm(), 1)
+	scale := (svgViewSize - 2*svgViewMargin) / extent
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="sans-serif" font-size="10">`,
+		2*svgViewSize, svgViewSize)
+	buf.WriteString("\n")
+	for i, view := range []struct {
+		title string
+		up    func(v r3.Vector) float64
+		min   float64
+	}{
+		{"top (x, y)", func(v r3.Vector) float64 { return v.Y }, minPoint.Y},
+		{"side (x, z)", func(v r3.Vector) float64 { return v.Z }, minPoint.Z},
+	} {
+		offset := float64(i) * svgViewSize
+		project := func(v r3.Vector) (float64, float64) {
+			return offset + svgViewMargin + (v.X-minPoint.X)*scale, svgViewSize - svgViewMargin - (view.up(v)-view.min)*scale
+		}
+		fmt.Fprintf(&buf, `<rect x="%.0f" y="0" width="%.0f" height="%.0f" fill="none" stroke="#ccc"/>`+"\n",
+			offset, svgViewSize, svgViewSize)
+		fmt.Fprintf(&buf, `<text x="%.0f" y="15">%s</text>`+"\n", offset+5, view.title)
+		tf.walk(func(frame *TreeFrame) {
+			if frame.Parent == "" {
+				return
+			}
+			x1, y1 := project(positions[frame.Parent])
+			x2, y2 := project(frame.Translation)
+			fmt.Fprintf(&buf, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#888"/>`+"\n", x1, y1, x2, y2)
+		})
+		tf.walk(func(frame *TreeFrame) {
+			x, y := project(frame.Translation)
+			fmt.Fprintf(&buf, `<circle cx="%.1f" cy="%.1f" r="3"/><text x="%.1f" y="%.1f">%s</text>`+"\n",
+				x, y, x+5, y-5, html.EscapeString(frame.Name))
+		})
+	}
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// TreeHandler returns a handler serving the kinematic tree of the frame system returned by fs
+// as JSON or, with the query parameter format=svg, as an SVG image. The tree is at the zero
+// inputs of the frames, so that serving it reads no components.
+func TreeHandler(fs func(ctx context.Context) (referenceframe.FrameSystem, error)) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tree, err := func() (*TreeFrame, error) {
+			frameSystem, err := fs(r.Context())
+			if err != nil {
+				return nil, err
+			}
+			return Tree(frameSystem, referenceframe.StartPositions(frameSystem))
+		}()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if r.URL.Query().Get("format") == "svg" {
+			w.Header().Set("Content-Type", "image/svg+xml")
+			//nolint:errcheck
+			_, _ = w.Write(tree.SVG())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		//nolint:errcheck
+		_ = encoder.Encode(tree)
+	})
+}
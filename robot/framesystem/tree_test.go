@@ -0,0 +1,80 @@
+package framesystem_test
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func TestTreeHandler(t *testing.T) {
+	ctx := context.Background()
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	base, err := referenceframe.NewStaticFrame("base", spatialmath.NewPoseFromPoint(r3.Vector{Z: 100}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(base, fs.World()), test.ShouldBeNil)
+	joint, err := referenceframe.NewRotationalFrame("joint", spatialmath.R4AA{RZ: 1}, referenceframe.Limit{Min: -math.Pi, Max: math.Pi})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(joint, base), test.ShouldBeNil)
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "tool")
+	test.That(t, err, test.ShouldBeNil)
+	tool, err := referenceframe.NewStaticFrameWithGeometry("tool", spatialmath.NewPoseFromPoint(r3.Vector{X: 100}), box)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.AddFrame(tool, joint), test.ShouldBeNil)
+
+	var fsErr error
+	handler := framesystem.TreeHandler(func(ctx context.Context) (referenceframe.FrameSystem, error) {
+		if fsErr != nil {
+			return nil, fsErr
+		}
+		return fs, nil
+	})
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil).WithContext(ctx))
+		return w
+	}
+
+	// frames are nested under their parents, with their poses in the world frame at zero inputs.
+	w := get("/debug/framesystem")
+	test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+	test.That(t, w.Header().Get("Content-Type"), test.ShouldEqual, "application/json")
+	var tree framesystem.TreeFrame
+	test.That(t, json.Unmarshal(w.Body.Bytes(), &tree), test.ShouldBeNil)
+	test.That(t, tree.Name, test.ShouldEqual, referenceframe.World)
+	test.That(t, tree.Children, test.ShouldHaveLength, 1)
+	test.That(t, tree.Children[0].Name, test.ShouldEqual, "base")
+	test.That(t, tree.Children[0].Children, test.ShouldHaveLength, 1)
+	jointFrame := tree.Children[0].Children[0]
+	test.That(t, jointFrame.Parent, test.ShouldEqual, "base")
+	test.That(t, jointFrame.Inputs, test.ShouldResemble, []float64{0})
+	test.That(t, jointFrame.Orientation.Theta, test.ShouldAlmostEqual, 0)
+	test.That(t, jointFrame.Children, test.ShouldHaveLength, 1)
+	toolFrame := jointFrame.Children[0]
+	test.That(t, spatialmath.R3VectorAlmostEqual(toolFrame.Translation, r3.Vector{X: 100, Z: 100}, 1e-6), test.ShouldBeTrue)
+	test.That(t, toolFrame.Geometries, test.ShouldHaveLength, 1)
+	test.That(t, toolFrame.Geometries[0].Type, test.ShouldEqual, spatialmath.BoxType)
+	test.That(t, toolFrame.Geometries[0].Label, test.ShouldEqual, "tool")
+
+	w = get("/debug/framesystem?format=svg")
+	test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+	test.That(t, w.Header().Get("Content-Type"), test.ShouldEqual, "image/svg+xml")
+	test.That(t, strings.HasPrefix(w.Body.String(), "<svg"), test.ShouldBeTrue)
+	test.That(t, w.Body.String(), test.ShouldContainSubstring, ">tool</text>")
+
+	fsErr = errors.New("frame system broken")
+	w = get("/debug/framesystem")
+	test.That(t, w.Code, test.ShouldEqual, http.StatusInternalServerError)
+	test.That(t, w.Body.String(), test.ShouldContainSubstring, "frame system broken")
+}
@@ -0,0 +1,108 @@
+package framesystem
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// UpdateServiceName is the name of the gRPC service serving runtime frame updates. Its
+// requests and responses are google.protobuf.Struct messages holding the JSON form of the
+// types below.
+const UpdateServiceName = "viam.rdk.framesystem.v1.FrameUpdateService"
+
+type updateRequest struct {
+	Name  string                     `json:"name,omitempty"`
+	Frame *referenceframe.LinkConfig `json:"frame,omitempty"`
+}
+
+type framesResponse struct {
+	Frames []referenceframe.LinkConfig `json:"frames"`
+}
+
+// An UpdateServer serves runtime frame updates with UpdateServiceDesc.
+type UpdateServer struct {
+	frames FrameUpdater
+}
+
+// NewUpdateServer returns a server for the given runtime frames.
+func NewUpdateServer(frames FrameUpdater) *UpdateServer {
+	return &UpdateServer{frames: frames}
+}
+
+func (s *UpdateServer) addTransform(ctx context.Context, req updateRequest) (interface{}, error) {
+	if req.Frame == nil {
+		return nil, status.Error(codes.InvalidArgument, "frame is required")
+	}
+	return struct{}{}, s.frames.AddTransform(ctx, *req.Frame)
+}
+
+func (s *UpdateServer) updateFrame(ctx context.Context, req updateRequest) (interface{}, error) {
+	if req.Frame == nil {
+		return nil, status.Error(codes.InvalidArgument, "frame is required")
+	}
+	return struct{}{}, s.frames.UpdateFrame(ctx, *req.Frame)
+}
+
+func (s *UpdateServer) removeFrame(ctx context.Context, req updateRequest) (interface{}, error) {
+	return struct{}{}, s.frames.RemoveFrame(ctx, req.Name)
+}
+
+func (s *UpdateServer) listRuntimeFrames(ctx context.Context, _ updateRequest) (interface{}, error) {
+	frames, err := s.frames.RuntimeFrames(ctx)
+	return framesResponse{Frames: frames}, err
+}
+
+// UpdateServiceDesc describes the gRPC service serving runtime frame updates. It is served
+// with an UpdateServer.
+var UpdateServiceDesc = structrpc.ServiceDesc(UpdateServiceName, "rdk/robot/framesystem",
+	structrpc.Unary("AddTransform", (*UpdateServer).addTransform),
+	structrpc.Unary("UpdateFrame", (*UpdateServer).updateFrame),
+	structrpc.Unary("RemoveFrame", (*UpdateServer).removeFrame),
+	structrpc.Unary("ListRuntimeFrames", (*UpdateServer).listRuntimeFrames),
+)
+
+// An UpdateClient is the FrameUpdater of a robot served over a connection to it.
+type UpdateClient struct {
+	client *structrpc.Client
+}
+
+var _ FrameUpdater = (*UpdateClient)(nil)
+
+// NewUpdateClient returns a client of the runtime frame updates served over the given
+// connection.
+func NewUpdateClient(conn grpc.ClientConnInterface) *UpdateClient {
+	return &UpdateClient{client: structrpc.NewClient(conn, UpdateServiceName)}
+}
+
+// AddTransform adds a frame to the frame system. Its parent may be any frame of the frame
+// system, including another frame added at runtime.
+func (c *UpdateClient) AddTransform(ctx context.Context, frame referenceframe.LinkConfig) error {
+	return c.client.Invoke(ctx, "AddTransform", updateRequest{Frame: &frame}, nil)
+}
+
+// UpdateFrame moves or re-parents a frame added at runtime, replacing its pose, parent, and
+// geometry with those given.
+func (c *UpdateClient) UpdateFrame(ctx context.Context, frame referenceframe.LinkConfig) error {
+	return c.client.Invoke(ctx, "UpdateFrame", updateRequest{Frame: &frame}, nil)
+}
+
+// RemoveFrame removes a frame added at runtime. A frame that is the parent of other frames
+// added at runtime cannot be removed.
+func (c *UpdateClient) RemoveFrame(ctx context.Context, name string) error {
+	return c.client.Invoke(ctx, "RemoveFrame", updateRequest{Name: name}, nil)
+}
+
+// RuntimeFrames returns the frames added at runtime, sorted by name.
+func (c *UpdateClient) RuntimeFrames(ctx context.Context) ([]referenceframe.LinkConfig, error) {
+	var resp framesResponse
+	if err := c.client.Invoke(ctx, "ListRuntimeFrames", updateRequest{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Frames, nil
+}
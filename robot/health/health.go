@@ -0,0 +1,360 @@
+// Package health reports the liveness and readiness of a robot and of each of its resources,
+// and serves them with the standard gRPC health checking protocol so that orchestration
+// systems such as Kubernetes can probe the robot process.
+package health
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/resource"
+)
+
+const (
+	// ServiceLiveness is the health service that is serving as long as the robot can report
+	// its health. The empty service name is the same.
+	ServiceLiveness = "liveness"
+	// ServiceReadiness is the health service that is serving once every resource of the
+	// robot is constructed without error.
+	ServiceReadiness = "readiness"
+
+	// watchInterval is how often health is checked for Watch calls.
+	watchInterval = time.Second
+)
+
+// ResourceHealth is the health of one resource.
+type ResourceHealth struct {
+	Name resource.Name
+	// Constructed is whether the resource has been constructed, even if it since failed to
+	// reconfigure.
+	Constructed bool
+	// Error is the error that makes the resource unavailable, if any.
+	Error error
+	// LastReconfigured is when the resource was constructed or last reconfigured.
+	LastReconfigured *time.Time
+	// LastSuccess is when a request to the resource last succeeded, if ever.
+	LastSuccess time.Time
+	// LastError is the last error returned by a request to the resource, if any. Unlike
+	// Error, it does not make the resource unavailable.
+	LastError *ErrorRecord
+	// LastStop is when and why the resource was last stopped, if it was.
+	LastStop *StopRecord
+}
+
+// Healthy returns whether the resource is available.
+func (h ResourceHealth) Healthy() bool {
+	return h.Constructed && h.Error == nil
+}
+
+// RemoteHealth is the state of the connection to one remote.
+type RemoteHealth struct {
+	Name      string
+	Connected bool
+	// Since is when the remote last connected or lost its connection.
+	Since time.Time
+	// ReconnectAttempts is how many attempts to reconnect have failed since the connection
+	// was lost.
+	ReconnectAttempts int
+	// NextReconnect is when the next attempt to reconnect is made, if not connected.
+	NextReconnect time.Time
+	// CachedAt is when the metadata served for the remote while it is not connected was
+	// cached, if the remote caches its metadata.
+	CachedAt time.Time
+	// CachedResources are the names of the remote's resources when its metadata was cached.
+	CachedResources []resource.Name
+}
+
+// Report is the health of a robot.
+type Report struct {
+	// Ready is whether every resource of the robot is healthy. The resources of a
+	// disconnected remote are not resources of the robot until it reconnects, so remotes do
+	// not count.
+	Ready     bool
+	Resources []ResourceHealth
+	Remotes   []RemoteHealth
+}
+
+// NewReport returns the report for the given resources, sorted by name.
+func NewReport(resources []ResourceHealth) Report {
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name.String() < resources[j].Name.String()
+	})
+	report := Report{Ready: true, Resources: resources}
+	for _, res := range resources {
+		if !res.Healthy() {
+			report.Ready = false
+		}
+	}
+	return report
+}
+
+// Resource returns the health of the resource with the given short name.
+func (r Report) Resource(shortName string) (ResourceHealth, bool) {
+	for _, res := range r.Resources {
+		if res.Name.ShortName() == shortName {
+			return res, true
+		}
+	}
+	return ResourceHealth{}, false
+}
+
+// A Robot is a robot that reports its health and tracks the requests to its resources. The
+// robot package refers to this package, so the robot it is cannot be named here.
+type Robot interface {
+	// Health returns whether each resource of the robot is available and when requests to
+	// it last succeeded.
+	Health(ctx context.Context) (Report, error)
+	// HealthTracker returns the tracker recording successful requests to the robot's resources.
+	HealthTracker() *Tracker
+}
+
+// StopReason is a machine-readable reason a resource was stopped.
+type StopReason string
+
+// The reasons resources are stopped.
+const (
+	// StopReasonRequested is the reason of resources stopped by a request to stop them.
+	StopReasonRequested StopReason = "requested"
+	// StopReasonStopAll is the reason of resources stopped by a request to stop the whole
+	// robot.
+	StopReasonStopAll StopReason = "stop_all"
+	// StopReasonSessionExpired is the reason of resources stopped because the session
+	// controlling them expired.
+	StopReasonSessionExpired StopReason = "session_expired"
+)
+
+// ErrorRecord is the last error returned by requests to a resource.
+type ErrorRecord struct {
+	Error string              `json:"error"`
+	Class resource.ErrorClass `json:"class"`
+	Time  time.Time           `json:"time"`
+}
+
+// StopRecord is the last time a resource was stopped.
+type StopRecord struct {
+	Reason StopReason `json:"reason"`
+	Time   time.Time  `json:"time"`
+}
+
+// A Tracker records when requests to each resource last succeeded, the last error they
+// returned, and when and why each resource was last stopped.
+type Tracker struct {
+	mu          sync.Mutex
+	lastSuccess map[string]time.Time
+	lastError   map[string]ErrorRecord
+	lastStop    map[string]StopRecord
+}
+
+// NewTracker returns a new Tracker.
+func NewTracker() *Tracker {
+	return &Tracker{
+		lastSuccess: map[string]time.Time{},
+		lastError:   map[string]ErrorRecord{},
+		lastStop:    map[string]StopRecord{},
+	}
+}
+
+// LastSuccess returns when a request to the resource with the given short name last
+// succeeded.
+func (t *Tracker) LastSuccess(shortName string) time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastSuccess[shortName]
+}
+
+// LastError returns the last error returned by a request to the resource with the given
+// short name, if any.
+func (t *Tracker) LastError(shortName string) (ErrorRecord, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	record, ok := t.lastError[shortName]
+	return record, ok
+}
+
+// LastStop returns when and why the resource with the given short name was last stopped,
+// if it was.
+func (t *Tracker) LastStop(shortName string) (StopRecord, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	record, ok := t.lastStop[shortName]
+	return record, ok
+}
+
+// RecordError records an error returned by a request to the resource with the given short
+// name. Requests canceled by their caller did not fail and are not recorded.
+func (t *Tracker) RecordError(shortName string, err error) {
+	if errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled {
+		return
+	}
+	record := ErrorRecord{Error: err.Error(), Class: resource.ClassifyError(err), Time: clock.Now()}
+	t.mu.Lock()
+	t.lastError[shortName] = record
+	t.mu.Unlock()
+}
+
+// RecordStop records that the resource with the given short name was stopped for the given
+// reason.
+func (t *Tracker) RecordStop(shortName string, reason StopReason) {
+	record := StopRecord{Reason: reason, Time: clock.Now()}
+	t.mu.Lock()
+	t.lastStop[shortName] = record
+	t.mu.Unlock()
+}
+
+func (t *Tracker) record(name string) {
+	now := clock.Now()
+	t.mu.Lock()
+	t.lastSuccess[name] = now
+	t.mu.Unlock()
+}
+
+// isStop returns whether the given gRPC method stops a resource.
+func isStop(fullMethod string) bool {
+	return strings.HasSuffix(fullMethod, "/Stop")
+}
+
+// UnaryServerInterceptor records successful and failed requests to resources, and requests
+// stopping them.
+func (t *Tracker) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	named, ok := req.(interface{ GetName() string })
+	if !ok || named.GetName() == "" {
+		return resp, err
+	}
+	if err != nil {
+		t.RecordError(named.GetName(), err)
+		return resp, err
+	}
+	t.record(named.GetName())
+	if isStop(info.FullMethod) {
+		t.RecordStop(named.GetName(), StopReasonRequested)
+	}
+	return resp, err
+}
+
+// StreamServerInterceptor records successful and failed requests to resources. A stream
+// succeeds each time it sends a response for a resource, and fails if it ends with an
+// error.
+func (t *Tracker) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	stream := &trackedStream{ServerStream: ss, tracker: t}
+	err := handler(srv, stream)
+	if err != nil && stream.name != "" {
+		t.RecordError(stream.name, err)
+	}
+	return err
+}
+
+type trackedStream struct {
+	grpc.ServerStream
+	tracker *Tracker
+	name    string
+}
+
+func (s *trackedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if named, ok := m.(interface{ GetName() string }); ok && named.GetName() != "" {
+		s.name = named.GetName()
+	}
+	return nil
+}
+
+func (s *trackedStream) SendMsg(m interface{}) error {
+	if err := s.ServerStream.SendMsg(m); err != nil {
+		return err
+	}
+	if s.name != "" {
+		s.tracker.record(s.name)
+	}
+	return nil
+}
+
+// Server serves the health of a robot with the gRPC health checking protocol. Besides
+// ServiceLiveness and ServiceReadiness, the short name of any resource may be checked.
+type Server struct {
+	healthpb.UnimplementedHealthServer
+	health func(ctx context.Context) (Report, error)
+}
+
+// NewServer returns a Server reporting the health returned by the given function.
+func NewServer(health func(ctx context.Context) (Report, error)) *Server {
+	return &Server{health: health}
+}
+
+func (s *Server) check(ctx context.Context, service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+	report, err := s.health(ctx)
+	if err != nil {
+		return healthpb.HealthCheckResponse_NOT_SERVING, err
+	}
+	switch service {
+	case "", ServiceLiveness:
+		return healthpb.HealthCheckResponse_SERVING, nil
+	case ServiceReadiness:
+		if report.Ready {
+			return healthpb.HealthCheckResponse_SERVING, nil
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, nil
+	}
+	res, ok := report.Resource(service)
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, status.Errorf(codes.NotFound, "unknown service %q", service)
+	}
+	if res.Healthy() {
+		return healthpb.HealthCheckResponse_SERVING, nil
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING, nil
+}
+
+// Check returns the health of the given service.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	serving, err := s.check(ctx, req.GetService())
+	if err != nil {
+		return nil, err
+	}
+	return &healthpb.HealthCheckResponse{Status: serving}, nil
+}
+
+// Watch streams the health of the given service whenever it changes.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	ctx := stream.Context()
+	last := healthpb.HealthCheckResponse_UNKNOWN
+	for {
+		serving, err := s.check(ctx, req.GetService())
+		if err != nil {
+			if status.Code(err) != codes.NotFound {
+				return err
+			}
+			// unknown services are watched until they exist.
+		}
+		if serving != last {
+			if err := stream.Send(&healthpb.HealthCheckResponse{Status: serving}); err != nil {
+				return err
+			}
+			last = serving
+		}
+		if !clock.SleepContext(ctx, watchInterval) {
+			return ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/health"
+)
+
+func TestReport(t *testing.T) {
+	report := health.NewReport([]health.ResourceHealth{
+		{Name: arm.Named("b"), Constructed: true},
+		{Name: arm.Named("a"), Constructed: true, Error: errors.New("failed to reconfigure")},
+	})
+	test.That(t, report.Ready, test.ShouldBeFalse)
+	test.That(t, report.Resources[0].Name.ShortName(), test.ShouldEqual, "a")
+	res, ok := report.Resource("b")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, res.Healthy(), test.ShouldBeTrue)
+
+	test.That(t, health.NewReport(report.Resources[1:]).Ready, test.ShouldBeTrue)
+	test.That(t, health.NewReport(nil).Ready, test.ShouldBeTrue)
+}
+
+func TestTracker(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+	tracker := health.NewTracker()
+	test.That(t, tracker.LastSuccess("arm1").IsZero(), test.ShouldBeTrue)
+
+	callMethod := func(method string, err error) {
+		_, _ = tracker.UnaryServerInterceptor(context.Background(), &commonpb.DoCommandRequest{Name: "arm1"},
+			&grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, err
+			})
+	}
+	call := func(err error) {
+		callMethod("/viam.component.arm.v1.ArmService/DoCommand", err)
+	}
+	call(nil)
+	test.That(t, tracker.LastSuccess("arm1"), test.ShouldEqual, virtual.Now())
+	succeeded := virtual.Now()
+	_, ok := tracker.LastError("arm1")
+	test.That(t, ok, test.ShouldBeFalse)
+	_, ok = tracker.LastStop("arm1")
+	test.That(t, ok, test.ShouldBeFalse)
+
+	virtual.Step(time.Minute)
+	call(resource.NewHardwareFaultError(errors.New("encoder disconnected")))
+	test.That(t, tracker.LastSuccess("arm1"), test.ShouldEqual, succeeded)
+	lastError, ok := tracker.LastError("arm1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, lastError, test.ShouldResemble, health.ErrorRecord{
+		Error: "encoder disconnected",
+		Class: resource.ErrorClassHardwareFault,
+		Time:  virtual.Now(),
+	})
+
+	// canceled requests did not fail.
+	call(context.Canceled)
+	lastError, ok = tracker.LastError("arm1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, lastError.Class, test.ShouldEqual, resource.ErrorClassHardwareFault)
+
+	virtual.Step(time.Minute)
+	callMethod("/viam.component.arm.v1.ArmService/Stop", nil)
+	lastStop, ok := tracker.LastStop("arm1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, lastStop, test.ShouldResemble, health.StopRecord{Reason: health.StopReasonRequested, Time: virtual.Now()})
+	tracker.RecordStop("arm1", health.StopReasonSessionExpired)
+	lastStop, _ = tracker.LastStop("arm1")
+	test.That(t, lastStop.Reason, test.ShouldEqual, health.StopReasonSessionExpired)
+}
+
+func TestServer(t *testing.T) {
+	var (
+		report health.Report
+		err    error
+	)
+	server := health.NewServer(func(ctx context.Context) (health.Report, error) {
+		return report, err
+	})
+	check := func(service string) (healthpb.HealthCheckResponse_ServingStatus, error) {
+		resp, err := server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		return resp.GetStatus(), err
+	}
+
+	report = health.NewReport([]health.ResourceHealth{
+		{Name: arm.Named("arm1"), Constructed: true},
+		{Name: arm.Named("arm2")},
+	})
+	for service, expected := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":                      healthpb.HealthCheckResponse_SERVING,
+		health.ServiceLiveness:  healthpb.HealthCheckResponse_SERVING,
+		health.ServiceReadiness: healthpb.HealthCheckResponse_NOT_SERVING,
+		"arm1":                  healthpb.HealthCheckResponse_SERVING,
+		"arm2":                  healthpb.HealthCheckResponse_NOT_SERVING,
+	} {
+		serving, err := check(service)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, serving, test.ShouldEqual, expected)
+	}
+	_, checkErr := check("arm3")
+	test.That(t, checkErr, test.ShouldNotBeNil)
+
+	// a robot that cannot report its health is not live.
+	err = context.DeadlineExceeded
+	_, checkErr = check(health.ServiceLiveness)
+	test.That(t, checkErr, test.ShouldBeError, context.DeadlineExceeded)
+}
@@ -0,0 +1,316 @@
+// Package homing keeps the homing of a robot's components across restarts, so that
+// components with incremental encoders, such as gantries, do not have to be homed again each
+// time the robot starts.
+//
+// A component that can keep its homing implements Homeable. When the robot shuts down
+// cleanly, the homing state of each of its Homeable components that is homed and not moving
+// is kept in the robot's key-value store, marked clean. Once the robot is configured again,
+// the kept state is restored to the component and marked unclean, so that a state is only
+// ever restored once and never after the robot stopped without shutting down cleanly, where
+// the component may have moved or stalled without its position being tracked. A state is
+// not restored either if the component's config changed since it was kept.
+//
+// Where the integrity of a component's position cannot be guaranteed otherwise, such as after
+// it was moved by hand, Rehome forgets its homing so that it must be homed again.
+package homing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/kv"
+)
+
+// Namespace is the namespace of the key-value store holding the homing states.
+const Namespace = "homing"
+
+// A Homeable is a resource whose homing can be kept across restarts.
+type Homeable interface {
+	resource.Resource
+	// HomingState returns the state from which the resource can be restored to being homed
+	// after a restart, or nil if it is not homed. It is called when the robot shuts down.
+	HomingState(ctx context.Context) (map[string]interface{}, error)
+	// RestoreHomingState restores the resource to being homed from a state returned by
+	// HomingState, or forgets its homing so that it must be homed again if the state is nil.
+	RestoreHomingState(ctx context.Context, state map[string]interface{}) error
+}
+
+// A record is the homing state of a resource as kept in the key-value store.
+type record struct {
+	State map[string]interface{} `json:"state"`
+	// Clean is whether the robot shut down cleanly, with the resource at rest, since the state
+	// was kept.
+	Clean bool `json:"clean"`
+	// ConfigHash is the hash of the config of the resource when the state was kept.
+	ConfigHash string    `json:"config_hash"`
+	SavedAt    time.Time `json:"saved_at"`
+}
+
+// Status is whether a resource's homing is kept across restarts.
+type Status struct {
+	// Resource is the name of the resource.
+	Resource string `json:"resource"`
+	// Homed is whether the resource is homed.
+	Homed bool `json:"homed"`
+	// Restored is whether the resource was restored to being homed when the robot started.
+	Restored bool `json:"restored"`
+	// SavedAt is when the restored state was kept, if restored.
+	SavedAt *time.Time `json:"saved_at,omitempty"`
+}
+
+// A Service keeps the homing of a robot's components across restarts, either directly or
+// over a connection to the robot.
+type Service interface {
+	// Status returns whether the homing of each resource that can keep it is kept, sorted by
+	// resource name.
+	Status(ctx context.Context) ([]Status, error)
+	// Rehome forgets the homing of the named resource and its kept state, so that it must be
+	// homed again.
+	Rehome(ctx context.Context, name string) error
+}
+
+// A Robot is a robot whose components can keep their homing across restarts.
+type Robot interface {
+	robot.Robot
+	// Homing returns the keeper of the homing of the robot's components.
+	Homing() *Keeper
+}
+
+// A Keeper is the Service of a robot, keeping the homing of its components.
+type Keeper struct {
+	r      robot.LocalRobot
+	store  kv.Service
+	logger logging.Logger
+
+	mu sync.Mutex
+	// seen are the resources whose kept state was already looked at, and restored those
+	// restored from it.
+	seen     map[resource.Name]resource.Resource
+	restored map[resource.Name]time.Time
+}
+
+var _ Service = (*Keeper)(nil)
+
+// NewKeeper returns a keeper of the homing of the components of the given robot keeping
+// their states in the given store.
+func NewKeeper(r robot.LocalRobot, store kv.Service, logger logging.Logger) *Keeper {
+	return &Keeper{
+		r:        r,
+		store:    store,
+		logger:   logger,
+		seen:     map[resource.Name]resource.Resource{},
+		restored: map[resource.Name]time.Time{},
+	}
+}
+
+// homeables returns the resources of the robot whose homing can be kept, by name.
+func (k *Keeper) homeables() map[resource.Name]Homeable {
+	homeables := map[resource.Name]Homeable{}
+	for _, name := range k.r.ResourceNames() {
+		if name.ContainsRemoteNames() {
+			continue
+		}
+		res, err := k.r.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		if h, ok := res.(Homeable); ok {
+			homeables[name] = h
+		}
+	}
+	return homeables
+}
+
+// configHash returns the hash of the config of the named resource, or an empty string if it
+// is not configured.
+func (k *Keeper) configHash(name resource.Name) string {
+	cfg := k.r.Config()
+	if cfg == nil {
+		return ""
+	}
+	var conf *resource.Config
+	for _, confs := range [][]resource.Config{cfg.Components, cfg.Services} {
+		for idx := range confs {
+			if confs[idx].ResourceName() == name {
+				conf = &confs[idx]
+			}
+		}
+	}
+	if conf == nil {
+		return ""
+	}
+	data, err := json.Marshal(struct {
+		Model      string                 `json:"model"`
+		Attributes map[string]interface{} `json:"attributes"`
+	}{conf.Model.String(), conf.Attributes})
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (k *Keeper) load(ctx context.Context, name resource.Name) (*record, error) {
+	data, ok, err := k.store.Get(ctx, Namespace, name.String())
+	if err != nil || !ok {
+		return nil, err
+	}
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (k *Keeper) save(ctx context.Context, name resource.Name, rec record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	return k.store.Set(ctx, Namespace, name.String(), data)
+}
+
+// Restore restores the resources built since it was last called to being homed from the
+// states kept when the robot last shut down cleanly, and marks the kept states unclean. It is
+// called each time the robot is configured.
+func (k *Keeper) Restore(ctx context.Context) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	homeables := k.homeables()
+	for name, res := range k.seen {
+		if homeables[name] != res {
+			delete(k.seen, name)
+			delete(k.restored, name)
+		}
+	}
+	for name, h := range homeables {
+		if _, ok := k.seen[name]; ok {
+			continue
+		}
+		k.seen[name] = h
+		rec, err := k.load(ctx, name)
+		if err != nil {
+			k.logger.CWarnw(ctx, "failed to load kept homing state", "resource", name, "error", err)
+			continue
+		}
+		if rec == nil {
+			continue
+		}
+		switch hash := k.configHash(name); {
+		case !rec.Clean:
+			k.logger.CInfow(ctx, "not restoring homing since the robot did not shut down cleanly; the resource must be homed again",
+				"resource", name)
+		case rec.ConfigHash != hash:
+			k.logger.CInfow(ctx, "not restoring homing since the config of the resource changed; the resource must be homed again",
+				"resource", name)
+		default:
+			if err := h.RestoreHomingState(ctx, rec.State); err != nil {
+				k.logger.CWarnw(ctx, "failed to restore homing; the resource must be homed again", "resource", name, "error", err)
+				break
+			}
+			k.restored[name] = rec.SavedAt
+			k.logger.CInfow(ctx, "restored homing", "resource", name, "saved_at", rec.SavedAt)
+		}
+		if !rec.Clean {
+			continue
+		}
+		rec.Clean = false
+		if err := k.save(ctx, name, *rec); err != nil {
+			k.logger.CWarnw(ctx, "failed to mark kept homing state unclean", "resource", name, "error", err)
+		}
+	}
+}
+
+// Save keeps the homing states of the resources that are homed and not moving, marked clean.
+// It is called when the robot shuts down, before its resources are closed.
+func (k *Keeper) Save(ctx context.Context) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	var errs error
+	for name, h := range k.homeables() {
+		if actuator, ok := h.(resource.Actuator); ok {
+			moving, err := actuator.IsMoving(ctx)
+			if err != nil || moving {
+				k.logger.CWarnw(ctx, "not keeping homing since the resource may be moving; it must be homed again after restarting",
+					"resource", name, "error", err)
+				errs = multierr.Combine(errs, k.store.Delete(ctx, Namespace, name.String()))
+				continue
+			}
+		}
+		state, err := h.HomingState(ctx)
+		if err != nil {
+			k.logger.CWarnw(ctx, "failed to get homing state", "resource", name, "error", err)
+		}
+		if err != nil || state == nil {
+			errs = multierr.Combine(errs, k.store.Delete(ctx, Namespace, name.String()))
+			continue
+		}
+		rec := record{State: state, Clean: true, ConfigHash: k.configHash(name), SavedAt: clock.Now()}
+		errs = multierr.Combine(errs, k.save(ctx, name, rec))
+	}
+	return errs
+}
+
+// Status returns whether the homing of each resource that can keep it is kept, sorted by
+// resource name.
+func (k *Keeper) Status(ctx context.Context) ([]Status, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	statuses := []Status{}
+	for name, h := range k.homeables() {
+		state, err := h.HomingState(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get homing state of %s", name)
+		}
+		st := Status{Resource: name.String(), Homed: state != nil}
+		if savedAt, ok := k.restored[name]; ok {
+			st.Restored = true
+			st.SavedAt = &savedAt
+		}
+		statuses = append(statuses, st)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Resource < statuses[j].Resource
+	})
+	return statuses, nil
+}
+
+// Rehome forgets the homing of the named resource and its kept state, so that it must be
+// homed again.
+func (k *Keeper) Rehome(ctx context.Context, name string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	for resName, h := range k.homeables() {
+		if resName.String() != name && resName.ShortName() != name {
+			continue
+		}
+		if err := k.store.Delete(ctx, Namespace, resName.String()); err != nil {
+			return err
+		}
+		delete(k.restored, resName)
+		if err := h.RestoreHomingState(ctx, nil); err != nil {
+			return err
+		}
+		k.logger.CInfow(ctx, "forgot homing; the resource must be homed again", "resource", resName)
+		return nil
+	}
+	return status.Errorf(codes.NotFound, "no resource named %q keeps its homing", name)
+}
@@ -0,0 +1,177 @@
+package homing_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/gantry"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/homing"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// homeableGantry is a gantry that is homed at a position, and is at rest unless moving is set.
+type homeableGantry struct {
+	*inject.Gantry
+	mu       sync.Mutex
+	position float64
+	homed    bool
+	moving   bool
+}
+
+func newHomeableGantry() *homeableGantry {
+	g := &homeableGantry{Gantry: inject.NewGantry("gantry1")}
+	g.IsMovingFunc = func(ctx context.Context) (bool, error) {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.moving, nil
+	}
+	return g
+}
+
+func (g *homeableGantry) HomingState(ctx context.Context) (map[string]interface{}, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.homed {
+		return nil, nil
+	}
+	return map[string]interface{}{"position": g.position}, nil
+}
+
+func (g *homeableGantry) RestoreHomingState(ctx context.Context, state map[string]interface{}) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if state == nil {
+		g.homed = false
+		return nil
+	}
+	g.homed = true
+	g.position = state["position"].(float64)
+	return nil
+}
+
+func (g *homeableGantry) isHomed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.homed
+}
+
+func setupRobot(t *testing.T, g *homeableGantry, length float64) *inject.Robot {
+	t.Helper()
+	logger := logging.NewTestLogger(t)
+	r := inject.NewRobot(logger, map[resource.Name]resource.Resource{gantry.Named("gantry1"): g})
+	r.ConfigFunc = func() *config.Config {
+		return &config.Config{Components: []resource.Config{{
+			Name:       "gantry1",
+			API:        gantry.API,
+			Model:      resource.DefaultModelFamily.WithModel("single-axis"),
+			Attributes: map[string]interface{}{"length_mm": length},
+		}}}
+	}
+	return r
+}
+
+func TestKeeper(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	path := filepath.Join(t.TempDir(), kv.FileName)
+
+	// restart starts the robot again with a new gantry, as when its process restarts.
+	restart := func(length float64) (*homeableGantry, *homing.Keeper, func()) {
+		store := kv.NewStore(path, logger)
+		g := newHomeableGantry()
+		k := homing.NewKeeper(setupRobot(t, g, length), store, logger)
+		k.Restore(ctx)
+		return g, k, func() {
+			test.That(t, store.Close(), test.ShouldBeNil)
+		}
+	}
+
+	// nothing is restored before anything was kept.
+	g, k, closeStore := restart(100)
+	test.That(t, g.isHomed(), test.ShouldBeFalse)
+	test.That(t, g.RestoreHomingState(ctx, map[string]interface{}{"position": 30.0}), test.ShouldBeNil)
+	statuses, err := k.Status(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldResemble, []homing.Status{{Resource: "rdk:component:gantry/gantry1", Homed: true}})
+	test.That(t, k.Save(ctx), test.ShouldBeNil)
+	closeStore()
+
+	// the homing kept on a clean shutdown is restored once.
+	g, k, closeStore = restart(100)
+	test.That(t, g.isHomed(), test.ShouldBeTrue)
+	test.That(t, g.position, test.ShouldEqual, 30.0)
+	statuses, err = k.Status(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldHaveLength, 1)
+	test.That(t, statuses[0].Restored, test.ShouldBeTrue)
+	test.That(t, statuses[0].SavedAt, test.ShouldNotBeNil)
+	closeStore()
+
+	// the robot did not shut down cleanly, so the gantry must be homed again.
+	g, k, closeStore = restart(100)
+	test.That(t, g.isHomed(), test.ShouldBeFalse)
+	test.That(t, g.RestoreHomingState(ctx, map[string]interface{}{"position": 40.0}), test.ShouldBeNil)
+	test.That(t, k.Save(ctx), test.ShouldBeNil)
+	closeStore()
+
+	// the config of the gantry changed, so the gantry must be homed again.
+	g, k, closeStore = restart(200)
+	test.That(t, g.isHomed(), test.ShouldBeFalse)
+	test.That(t, g.RestoreHomingState(ctx, map[string]interface{}{"position": 40.0}), test.ShouldBeNil)
+	g.moving = true
+	test.That(t, k.Save(ctx), test.ShouldBeNil)
+	closeStore()
+
+	// the gantry was moving when the robot shut down, so the gantry must be homed again.
+	g, k, closeStore = restart(200)
+	test.That(t, g.isHomed(), test.ShouldBeFalse)
+	test.That(t, g.RestoreHomingState(ctx, map[string]interface{}{"position": 40.0}), test.ShouldBeNil)
+	test.That(t, k.Save(ctx), test.ShouldBeNil)
+	closeStore()
+
+	// rehoming forgets the homing of the gantry and its kept state.
+	g, k, closeStore = restart(200)
+	defer closeStore()
+	test.That(t, g.isHomed(), test.ShouldBeTrue)
+	test.That(t, k.Rehome(ctx, "gantry1"), test.ShouldBeNil)
+	test.That(t, g.isHomed(), test.ShouldBeFalse)
+	statuses, err = k.Status(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldResemble, []homing.Status{{Resource: "rdk:component:gantry/gantry1"}})
+	err = k.Rehome(ctx, "gantry2")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	g := newHomeableGantry()
+	test.That(t, g.RestoreHomingState(ctx, map[string]interface{}{"position": 30.0}), test.ShouldBeNil)
+	r := setupRobot(t, g, 100)
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	keeper := robotClient.Homing()
+
+	statuses, err := keeper.Status(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldResemble, []homing.Status{{Resource: "rdk:component:gantry/gantry1", Homed: true}})
+	test.That(t, keeper.Rehome(ctx, "gantry1"), test.ShouldBeNil)
+	test.That(t, g.isHomed(), test.ShouldBeFalse)
+	err = keeper.Rehome(ctx, "gantry2")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+}
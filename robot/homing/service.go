@@ -0,0 +1,74 @@
+package homing
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a keeper. Its requests and responses are
+// google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.homing.v1.HomingService"
+
+type request struct {
+	Resource string `json:"resource,omitempty"`
+}
+
+type statusResponse struct {
+	Statuses []Status `json:"statuses"`
+}
+
+// A Server serves a keeper with ServiceDesc.
+type Server struct {
+	keeper Service
+}
+
+// NewServer returns a server for the given keeper.
+func NewServer(keeper Service) *Server {
+	return &Server{keeper: keeper}
+}
+
+func (s *Server) getStatus(ctx context.Context, _ request) (interface{}, error) {
+	statuses, err := s.keeper.Status(ctx)
+	return statusResponse{Statuses: statuses}, err
+}
+
+func (s *Server) rehome(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.keeper.Rehome(ctx, req.Resource)
+}
+
+// ServiceDesc describes the gRPC service serving a keeper. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/homing",
+	structrpc.Unary("GetStatus", (*Server).getStatus),
+	structrpc.Unary("Rehome", (*Server).rehome),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the keeper served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Status returns whether the homing of each resource that can keep it is kept, sorted by
+// resource name.
+func (c *Client) Status(ctx context.Context) ([]Status, error) {
+	var resp statusResponse
+	if err := c.client.Invoke(ctx, "GetStatus", request{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Statuses, nil
+}
+
+// Rehome forgets the homing of the named resource and its kept state, so that it must be
+// homed again. Rehoming a resource that does not keep its homing fails with code NotFound.
+func (c *Client) Rehome(ctx context.Context, name string) error {
+	return c.client.Invoke(ctx, "Rehome", request{Resource: name}, nil)
+}
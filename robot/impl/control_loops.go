@@ -0,0 +1,89 @@
+package robotimpl
+
+import (
+	"context"
+
+	"github.com/golang/geo/r3"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/encoder"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/movementsensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/control"
+	"go.viam.com/rdk/robot/controlloops"
+)
+
+// ControlLoops returns the host of the control loops configured for the robot.
+func (r *localRobot) ControlLoops() *controlloops.Host {
+	return r.controlLoops
+}
+
+// controlled returns what the control loop with the given config drives.
+func (r *localRobot) controlled(ctx context.Context, conf config.ControlLoopConfig) (controlloops.Controlled, error) {
+	if conf.Motor != "" {
+		m, err := motor.FromRobot(r, conf.Motor)
+		if err != nil {
+			return nil, err
+		}
+		enc, err := encoder.FromRobot(r, conf.Encoder)
+		if err != nil {
+			return nil, err
+		}
+		return encodedMotor{Motor: m, enc: enc}, nil
+	}
+	b, err := base.FromRobot(r, conf.Base)
+	if err != nil {
+		return nil, err
+	}
+	ms, err := movementsensor.FromRobot(r, conf.MovementSensor)
+	if err != nil {
+		return nil, err
+	}
+	return sensedBase{Base: b, ms: ms}, nil
+}
+
+// encodedMotor is a motor driven by a control loop with the power it is given, whose state
+// is the position of an encoder in ticks.
+type encodedMotor struct {
+	motor.Motor
+	enc encoder.Encoder
+}
+
+func (m encodedMotor) SetState(ctx context.Context, state []*control.Signal) error {
+	return m.SetPower(ctx, state[0].GetSignalValueAt(0), nil)
+}
+
+func (m encodedMotor) State(ctx context.Context) ([]float64, error) {
+	ticks, _, err := m.enc.Position(ctx, encoder.PositionTypeTicks, nil)
+	return []float64{ticks}, err
+}
+
+// sensedBase is a base driven by a control loop with the angular power it is given,
+// preceded by the linear power if given two signals, whose state is the linear velocity and
+// compass heading of a movement sensor.
+type sensedBase struct {
+	base.Base
+	ms movementsensor.MovementSensor
+}
+
+func (b sensedBase) SetState(ctx context.Context, state []*control.Signal) error {
+	var linear r3.Vector
+	if len(state) > 1 {
+		linear.Y = state[0].GetSignalValueAt(0)
+	}
+	angular := r3.Vector{Z: state[len(state)-1].GetSignalValueAt(0)}
+	return b.SetPower(ctx, linear, angular, nil)
+}
+
+func (b sensedBase) State(ctx context.Context) ([]float64, error) {
+	linvel, err := b.ms.LinearVelocity(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	heading, err := b.ms.CompassHeading(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return []float64{linvel.Y, heading}, nil
+}
@@ -0,0 +1,98 @@
+package robotimpl
+
+import (
+	"sync"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/events"
+)
+
+// resourceState is the state of a resource or remote as last published to the event bus.
+type resourceState struct {
+	// err is why the resource is unavailable, if it is.
+	err string
+	// connected is whether a remote is connected.
+	connected bool
+}
+
+// stateTracker publishes the changes to the state of the robot's resources and remotes since
+// they were last published.
+type stateTracker struct {
+	mu        sync.Mutex
+	bus       *events.Bus
+	published map[resource.Name]resourceState
+}
+
+func newStateTracker(bus *events.Bus) *stateTracker {
+	return &stateTracker{bus: bus, published: map[resource.Name]resourceState{}}
+}
+
+// publishChanges publishes the changes to the state of the resources and remotes in the
+// manager's graph. Resources that are neither constructed nor failed to be are left out
+// until they are.
+func (t *stateTracker) publishChanges(manager *resourceManager) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	current := map[resource.Name]resourceState{}
+	for _, name := range manager.resources.Names() {
+		if name.API.Type.Namespace == resource.APINamespaceRDKInternal {
+			continue
+		}
+		gNode, ok := manager.resources.Node(name)
+		if !ok || gNode.MarkedForRemoval() {
+			continue
+		}
+		res, err := gNode.Resource()
+		if name.API == client.RemoteAPI {
+			rr, ok := res.(robot.RemoteRobot)
+			current[name] = resourceState{connected: err == nil && ok && rr.Connected()}
+			continue
+		}
+		if resource.IsNotInitializedError(err) {
+			continue
+		}
+		var state resourceState
+		if err != nil {
+			state.err = err.Error()
+		}
+		current[name] = state
+	}
+
+	for name, state := range current {
+		previous, existed := t.published[name]
+		if name.API == client.RemoteAPI {
+			if state.connected != previous.connected {
+				typ := events.RemoteDisconnected
+				if state.connected {
+					typ = events.RemoteConnected
+				}
+				t.bus.Publish(events.Event{Type: typ, Remote: name.Name})
+			}
+			continue
+		}
+		if !existed {
+			t.bus.Publish(events.Event{Type: events.ResourceAdded, Name: name})
+		}
+		switch {
+		case state.err != "" && state.err != previous.err:
+			t.bus.Publish(events.Event{Type: events.ResourceErrored, Name: name, Error: state.err})
+		case state.err == "" && previous.err != "":
+			t.bus.Publish(events.Event{Type: events.ResourceRecovered, Name: name})
+		}
+	}
+	for name, previous := range t.published {
+		if _, ok := current[name]; ok {
+			continue
+		}
+		if name.API == client.RemoteAPI {
+			if previous.connected {
+				t.bus.Publish(events.Event{Type: events.RemoteDisconnected, Remote: name.Name})
+			}
+			continue
+		}
+		t.bus.Publish(events.Event{Type: events.ResourceRemoved, Name: name})
+	}
+	t.published = current
+}
@@ -0,0 +1,87 @@
+package robotimpl
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/firmware"
+	"go.viam.com/rdk/resource"
+)
+
+// UpdateFirmware flashes the firmware update with the given name from the robot's
+// config. Resources backed by the microcontroller are stopped and closed during the
+// flash and rebuilt afterwards.
+func (r *localRobot) UpdateFirmware(ctx context.Context, name string) error {
+	var fwCfg *config.FirmwareConfig
+	for _, fw := range r.Config().Firmware {
+		if fw.Name == name {
+			fwCopy := fw
+			fwCfg = &fwCopy
+			break
+		}
+	}
+	if fwCfg == nil {
+		return errors.Errorf("firmware %q not found in config", name)
+	}
+	return firmware.Update(ctx, *fwCfg, &firmwareQuiescer{r: r}, r.logger.Sublogger("firmware"))
+}
+
+// firmwareQuiescer closes the resources backed by a microcontroller while it is being
+// flashed, and keeps them from being rebuilt until it is done. The rest of the robot can be
+// reconfigured meanwhile.
+type firmwareQuiescer struct {
+	r *localRobot
+}
+
+func (q *firmwareQuiescer) Quiesce(ctx context.Context, resources []string) (func(ctx context.Context) error, error) {
+	manager := q.r.manager
+
+	var names []resource.Name
+	for _, shortName := range resources {
+		var found bool
+		for _, n := range manager.ResourceNames() {
+			if n.ShortName() == shortName && !n.ContainsRemoteNames() {
+				names = append(names, n)
+				found = true
+			}
+		}
+		if !found {
+			return nil, resource.NewNotFoundError(resource.NewName(resource.API{}, shortName))
+		}
+	}
+
+	manager.configLock.Lock()
+	manager.setQuiesced(names, true)
+	var allErrs error
+	for _, name := range names {
+		gNode, ok := manager.resources.Node(name)
+		if !ok || gNode.IsUninitialized() {
+			continue
+		}
+		if res, err := gNode.Resource(); err == nil {
+			if actuator, ok := res.(resource.Actuator); ok {
+				allErrs = multierr.Combine(allErrs, actuator.Stop(ctx, nil))
+			}
+		}
+		allErrs = multierr.Combine(allErrs, manager.closeForRebuild(ctx, name, gNode))
+	}
+	manager.configLock.Unlock()
+	if allErrs != nil {
+		q.r.logger.CWarnw(ctx, "errors encountered while quiescing resources for firmware update", "error", allErrs)
+	}
+
+	return func(ctx context.Context) error {
+		manager.setQuiesced(names, false)
+		manager.completeConfig(ctx, q.r, true)
+		q.r.updateWeakDependents(ctx)
+		for _, name := range names {
+			if _, err := manager.ResourceByName(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, nil
+}
@@ -7,6 +7,9 @@ package robotimpl
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -20,8 +23,11 @@ import (
 	"go.viam.com/utils/pexec"
 	"go.viam.com/utils/rpc"
 
+	"go.viam.com/rdk/arbitration"
 	"go.viam.com/rdk/cloud"
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/faults"
 	icloud "go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
@@ -30,12 +36,30 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/controlloops"
+	"go.viam.com/rdk/robot/coordination"
+	"go.viam.com/rdk/robot/events"
+	"go.viam.com/rdk/robot/failover"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/health"
+	"go.viam.com/rdk/robot/homing"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/lan"
+	"go.viam.com/rdk/robot/maptiles"
+	"go.viam.com/rdk/robot/metadata"
+	"go.viam.com/rdk/robot/metrics"
+	"go.viam.com/rdk/robot/mission"
 	"go.viam.com/rdk/robot/packages"
+	"go.viam.com/rdk/robot/records"
+	"go.viam.com/rdk/robot/selftest"
+	"go.viam.com/rdk/robot/timesync"
+	"go.viam.com/rdk/robot/tools"
+	"go.viam.com/rdk/robot/trajectories"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/utils"
+	"go.viam.com/rdk/watchdog"
 )
 
 var _ = robot.LocalRobot(&localRobot{})
@@ -47,13 +71,40 @@ type localRobot struct {
 	statusLock    sync.Mutex
 	manager       *resourceManager
 	mostRecentCfg atomic.Value // config.Config
+	// patchMu serializes config patches, which are applied to the config they read.
+	patchMu sync.Mutex
+	profile profileState
 
 	operations              *operation.Manager
 	sessionManager          session.Manager
+	faultInjector           *faults.Injector
+	arbiter                 *arbitration.Arbiter
+	watchdog                *watchdog.Watchdog
+	restartingMu            sync.Mutex
+	restarting              map[string]bool
+	healthTracker           *health.Tracker
+	metrics                 *metrics.Recorder
+	kv                      *kv.Store
+	metadata                *metadata.Store
+	missions                *mission.Queue
+	trajectories            *trajectories.Recorder
+	homing                  *homing.Keeper
+	timeSync                *timesync.Tracker
+	mapTiles                *maptiles.Cache
+	eventBus                *events.Bus
+	selfTester              *selftest.Tester
+	tools                   *tools.Manager
+	runtimeFrames           *framesystem.RuntimeFrames
+	obstacles               *framesystem.ObstacleStore
+	controlLoops            *controlloops.Host
+	coordinator             *coordination.Coordinator
+	remoteDiscoverer        *lan.Discoverer
+	stateTracker            *stateTracker
 	packageManager          packages.ManagerSyncer
 	localPackages           packages.ManagerSyncer
 	cloudConnSvc            icloud.ConnectionService
 	logger                  logging.Logger
+	auditLogger             logging.Logger
 	activeBackgroundWorkers sync.WaitGroup
 	// reconfigureWorkers tracks goroutines spawned by reconfiguration functions. we only
 	// wait on this group in tests to prevent goleak-related failures. however, we do not
@@ -64,6 +115,7 @@ type localRobot struct {
 	closeContext               context.Context
 	triggerConfig              chan struct{}
 	configTicker               *time.Ticker
+	retryInterval              time.Duration
 	revealSensitiveConfigDiffs bool
 	shutdownCallback           func()
 
@@ -105,6 +157,26 @@ func (r *localRobot) ResourceNames() []resource.Name {
 	return r.manager.ResourceNames()
 }
 
+// ResourceLabels returns the labels of the known resources that have any, including those
+// of remotes.
+func (r *localRobot) ResourceLabels() map[resource.Name]map[string]string {
+	labels := r.metadata.ResourceLabels()
+	for _, remoteName := range r.RemoteNames() {
+		remote, ok := r.RemoteByName(remoteName)
+		if !ok {
+			continue
+		}
+		labeled, ok := remote.(metadata.Service)
+		if !ok {
+			continue
+		}
+		for name, resLabels := range labeled.ResourceLabels() {
+			labels[name.PrependRemote(remoteName)] = resLabels
+		}
+	}
+	return labels
+}
+
 // ResourceRPCAPIs returns all known resource RPC APIs in use.
 func (r *localRobot) ResourceRPCAPIs() []resource.RPCAPI {
 	return r.manager.ResourceRPCAPIs()
@@ -120,6 +192,295 @@ func (r *localRobot) OperationManager() *operation.Manager {
 	return r.operations
 }
 
+// FaultInjector returns the fault injector for the robot.
+func (r *localRobot) FaultInjector() *faults.Injector {
+	return r.faultInjector
+}
+
+// Arbiter returns the command arbiter for the robot.
+func (r *localRobot) Arbiter() *arbitration.Arbiter {
+	return r.arbiter
+}
+
+// Watchdog returns the watchdog canceling requests to the robot's resources that exceed
+// their operation timeout.
+func (r *localRobot) Watchdog() *watchdog.Watchdog {
+	return r.watchdog
+}
+
+// HealthTracker returns the tracker recording successful requests to the robot's resources.
+func (r *localRobot) HealthTracker() *health.Tracker {
+	return r.healthTracker
+}
+
+// Metrics returns the recorder of the operations served by the robot's resources.
+func (r *localRobot) Metrics() *metrics.Recorder {
+	return r.metrics
+}
+
+// KV returns the persistent key-value store of the robot.
+func (r *localRobot) KV() *kv.Store {
+	return r.kv
+}
+
+// Missions returns the queue of tasks of the robot.
+func (r *localRobot) Missions() *mission.Queue {
+	return r.missions
+}
+
+// Coordination returns the coordinator of the locks and barriers shared by the robot and its
+// remotes.
+func (r *localRobot) Coordination() *coordination.Coordinator {
+	return r.coordinator
+}
+
+// remoteCoordinator returns the coordinator of the named remote.
+func (r *localRobot) remoteCoordinator(name string) (coordination.Service, bool) {
+	remote, ok := r.RemoteByName(name)
+	if !ok {
+		return nil, false
+	}
+	rc, ok := remote.(*client.RobotClient)
+	if !ok {
+		return nil, false
+	}
+	return rc.Coordination(), true
+}
+
+// Trajectories returns the recorder of the trajectories of the robot's arms.
+func (r *localRobot) Trajectories() *trajectories.Recorder {
+	return r.trajectories
+}
+
+// Homing returns the keeper of the homing of the robot's components.
+func (r *localRobot) Homing() *homing.Keeper {
+	return r.homing
+}
+
+// TimeSync returns the tracker of the clocks of the robot's remotes.
+func (r *localRobot) TimeSync() *timesync.Tracker {
+	return r.timeSync
+}
+
+// MapTiles returns the cache of the map tiles of the areas configured for the robot.
+func (r *localRobot) MapTiles() *maptiles.Cache {
+	return r.mapTiles
+}
+
+// Events returns the bus publishing changes to the state of the robot's resources and
+// remotes.
+func (r *localRobot) Events() *events.Bus {
+	return r.eventBus
+}
+
+// SelfTester returns the tester exercising the robot's components with safe checks.
+func (r *localRobot) SelfTester() *selftest.Tester {
+	return r.selfTester
+}
+
+// Tools returns the manager of the tools mounted on the robot's arms.
+func (r *localRobot) Tools() *tools.Manager {
+	return r.tools
+}
+
+// toolArm returns the named local arm for a tool to be mounted on. The arm must be part of
+// the frame system for the frame of the tool to be added to it.
+func (r *localRobot) toolArm(name string) (resource.Resource, error) {
+	conf := r.Config().FindComponent(name)
+	if conf == nil || conf.API != arm.API {
+		return nil, resource.NewNotFoundError(arm.Named(name))
+	}
+	if conf.Frame == nil {
+		return nil, errors.Errorf("arm %q has no frame in the frame system to mount a tool on", name)
+	}
+	return r.ResourceByName(arm.Named(name))
+}
+
+// RuntimeFrames returns the frames added to the robot's frame system at runtime.
+func (r *localRobot) RuntimeFrames() *framesystem.RuntimeFrames {
+	return r.runtimeFrames
+}
+
+// Obstacles returns the store of the obstacles of the robot's environment.
+func (r *localRobot) Obstacles() *framesystem.ObstacleStore {
+	return r.obstacles
+}
+
+// updateFrameSystem rebuilds the frame system from the robot's config, mounted tools, and
+// runtime frames.
+func (r *localRobot) updateFrameSystem(ctx context.Context) error {
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	if err != nil {
+		return err
+	}
+	return r.reconfigureFrameSystem(ctx, fsCfg)
+}
+
+// updateRuntimeFrames rebuilds the frame system after a change to the runtime frames, failing
+// if any of them cannot be part of it.
+func (r *localRobot) updateRuntimeFrames(ctx context.Context) error {
+	fsCfg, leftOut, err := r.frameSystemConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if len(leftOut) != 0 {
+		return errors.Errorf("frame %q cannot be added to the frame system; its parent is missing, it is its own "+
+			"ancestor, or another frame has its name", leftOut[0])
+	}
+	return r.reconfigureFrameSystem(ctx, fsCfg)
+}
+
+// reconfigureFrameSystem rebuilds the frame system from the given config.
+func (r *localRobot) reconfigureFrameSystem(ctx context.Context, fsCfg *framesystem.Config) error {
+	components := map[resource.Name]resource.Resource{}
+	for _, n := range r.manager.resources.Names() {
+		if !n.API.IsComponent() {
+			continue
+		}
+		if res, err := r.ResourceByName(n); err == nil {
+			components[n] = res
+		}
+	}
+	return r.frameSvc.Reconfigure(ctx, components, resource.Config{ConvertedAttributes: fsCfg})
+}
+
+// selfTestComponents returns the names of the configured components, including those that
+// failed to build so that they fail the self-test.
+func (r *localRobot) selfTestComponents() []resource.Name {
+	cfg := r.mostRecentCfg.Load().(config.Config)
+	names := make([]resource.Name, 0, len(cfg.Components))
+	for _, conf := range cfg.Components {
+		names = append(names, conf.ResourceName())
+	}
+	return names
+}
+
+// Failovers returns the monitor of the resources of the robot that have a standby.
+func (r *localRobot) Failovers() *failover.Monitor {
+	return r.manager.failovers
+}
+
+// Health returns the health of every resource of the robot.
+func (r *localRobot) Health(ctx context.Context) (health.Report, error) {
+	var (
+		resources []health.ResourceHealth
+		remotes   []health.RemoteHealth
+	)
+	for _, name := range r.manager.resources.Names() {
+		if name.API.Type.Namespace == resource.APINamespaceRDKInternal {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return health.Report{}, err
+		}
+		gNode, ok := r.manager.resources.Node(name)
+		if !ok || gNode.MarkedForRemoval() {
+			continue
+		}
+		res, err := gNode.Resource()
+		if name.API == client.RemoteAPI {
+			remoteHealth := health.RemoteHealth{Name: name.Name}
+			if rc, ok := res.(*client.RobotClient); ok {
+				state := rc.ConnectionState()
+				remoteHealth.Connected = state.Connected
+				remoteHealth.Since = state.Since
+				remoteHealth.ReconnectAttempts = state.ReconnectAttempts
+				remoteHealth.NextReconnect = state.NextReconnect
+			}
+			if remoteCfg, err := resource.NativeConfig[*config.Remote](gNode.Config()); err == nil && !remoteHealth.Connected {
+				if md, ok := r.cachedRemoteMetadata(ctx, *remoteCfg); ok {
+					remoteHealth.CachedAt = md.CachedAt
+					if remoteHealth.CachedResources, err = md.resourceNames(); err != nil {
+						return health.Report{}, err
+					}
+					for i, name := range remoteHealth.CachedResources {
+						remoteHealth.CachedResources[i] = name.PrependRemote(remoteCfg.Name)
+					}
+				}
+			}
+			remotes = append(remotes, remoteHealth)
+			continue
+		}
+		resources = append(resources, health.ResourceHealth{
+			Name:             name,
+			Constructed:      !gNode.IsUninitialized(),
+			Error:            err,
+			LastReconfigured: gNode.LastReconfigured(),
+			LastSuccess:      r.healthTracker.LastSuccess(name.ShortName()),
+			LastError:        r.lastError(name),
+			LastStop:         r.lastStop(name),
+		})
+	}
+	sort.Slice(remotes, func(i, j int) bool {
+		return remotes[i].Name < remotes[j].Name
+	})
+	report := health.NewReport(resources)
+	report.Remotes = remotes
+	return report, nil
+}
+
+// lastError returns the last error returned by a request to the resource, if any.
+func (r *localRobot) lastError(name resource.Name) *health.ErrorRecord {
+	if record, ok := r.healthTracker.LastError(name.ShortName()); ok {
+		return &record
+	}
+	return nil
+}
+
+// lastStop returns when and why the resource was last stopped, if it was.
+func (r *localRobot) lastStop(name resource.Name) *health.StopRecord {
+	if record, ok := r.healthTracker.LastStop(name.ShortName()); ok {
+		return &record
+	}
+	return nil
+}
+
+// selfTestResult returns the outcome of the resource in the last self-test, if it was tested.
+func (r *localRobot) selfTestResult(name resource.Name) *selftest.Result {
+	if result, ok := r.selfTester.LastResult(name); ok {
+		return &result
+	}
+	return nil
+}
+
+// Records returns the records of the given resources that have any, or of all resources with
+// records if none are given. Those of the resources of remotes are returned by the remotes.
+func (r *localRobot) Records(ctx context.Context, names []resource.Name) (map[resource.Name]records.Records, error) {
+	if len(names) == 0 {
+		names = r.manager.ResourceNames()
+	}
+	recs := make(map[resource.Name]records.Records)
+	remoteNames := make(map[string][]resource.Name)
+	for _, name := range names {
+		if remoteName, ok := remoteNameByResource(name); ok {
+			remoteNames[remoteName] = append(remoteNames[remoteName], name.PopRemote())
+			continue
+		}
+		rec := records.Records{LastError: r.lastError(name), LastStop: r.lastStop(name), SelfTest: r.selfTestResult(name)}
+		if rec != (records.Records{}) {
+			recs[name] = rec
+		}
+	}
+	for remoteName, names := range remoteNames {
+		remote, ok := r.RemoteByName(remoteName)
+		if !ok {
+			continue
+		}
+		recordingRemote, ok := remote.(records.Robot)
+		if !ok {
+			continue
+		}
+		remoteRecs, err := recordingRemote.Records(ctx, names)
+		if err != nil {
+			return nil, err
+		}
+		for name, rec := range remoteRecs {
+			recs[name.PrependRemote(remoteName)] = rec
+		}
+	}
+	return recs, nil
+}
+
 // SessionManager returns the session manager for the robot.
 func (r *localRobot) SessionManager() session.Manager {
 	return r.sessionManager
@@ -149,8 +510,32 @@ func (r *localRobot) Close(ctx context.Context) error {
 	}
 	r.activeBackgroundWorkers.Wait()
 	r.sessionManager.Close()
+	if r.remoteDiscoverer != nil {
+		r.remoteDiscoverer.Close()
+	}
+
+	// stop control loops before the resources they drive are closed.
+	if r.controlLoops != nil {
+		r.controlLoops.Close(ctx)
+	}
 
 	var err error
+	if r.missions != nil {
+		err = multierr.Combine(err, r.missions.Close(ctx))
+	}
+	if r.trajectories != nil {
+		err = multierr.Combine(err, r.trajectories.Close(ctx))
+	}
+	// homing is kept before the resources whose positions it holds are closed.
+	if r.homing != nil {
+		err = multierr.Combine(err, r.homing.Save(ctx))
+	}
+	if r.timeSync != nil {
+		r.timeSync.Close()
+	}
+	if r.mapTiles != nil {
+		r.mapTiles.Close()
+	}
 	if r.cloudConnSvc != nil {
 		err = multierr.Combine(err, r.cloudConnSvc.Close(ctx))
 	}
@@ -163,6 +548,12 @@ func (r *localRobot) Close(ctx context.Context) error {
 	if r.webSvc != nil {
 		err = multierr.Combine(err, r.webSvc.Close(ctx))
 	}
+	if r.eventBus != nil {
+		r.eventBus.Close()
+	}
+	if r.kv != nil {
+		err = multierr.Combine(err, r.kv.Close())
+	}
 	return err
 }
 
@@ -184,8 +575,11 @@ func (r *localRobot) StopAll(ctx context.Context, extra map[resource.Name]map[st
 
 		if actuator, ok := res.(resource.Actuator); ok {
 			if err := actuator.Stop(ctx, extra[name]); err != nil {
+				r.healthTracker.RecordError(name.ShortName(), err)
 				resourceErrs = append(resourceErrs, name.Name)
+				continue
 			}
+			r.healthTracker.RecordStop(name.ShortName(), health.StopReasonStopAll)
 		}
 	}
 
@@ -214,6 +608,39 @@ func (r *localRobot) Config() *config.Config {
 	return &cfg
 }
 
+// EffectiveConfig returns the complete config the robot runs with, with defaults applied.
+func (r *localRobot) EffectiveConfig(ctx context.Context) (*robot.EffectiveConfig, error) {
+	cfg := r.Config().WithSecretReferences()
+	effective := *cfg
+	effective.Cloud = nil
+	effective.Auth = config.AuthConfig{}
+
+	if effective.MaxRemoteDepth == 0 {
+		effective.MaxRemoteDepth = config.DefaultMaxRemoteDepth
+	}
+	if effective.ResourceRetryInterval == 0 {
+		effective.ResourceRetryInterval = config.DefaultResourceRetryInterval
+	}
+	_, defaultDeadline := cfg.CallDeadlines.Deadlines()
+	effective.CallDeadlines = &config.CallDeadlines{Default: defaultDeadline}
+	if cfg.CallDeadlines != nil {
+		effective.CallDeadlines.APIs = cfg.CallDeadlines.APIs
+	}
+	// the bind address is left out of marshaled configs when it is the default.
+	effective.Network.BindAddressDefaultSet = false
+
+	remoteResources := map[string][]string{}
+	for _, name := range r.ResourceNames() {
+		if remote, ok := remoteNameByResource(name); ok {
+			remoteResources[remote] = append(remoteResources[remote], name.String())
+		}
+	}
+	for _, names := range remoteResources {
+		sort.Strings(names)
+	}
+	return &robot.EffectiveConfig{Config: &effective, RemoteResources: remoteResources}, nil
+}
+
 // Logger returns the logger the robot is using.
 func (r *localRobot) Logger() logging.Logger {
 	return r.logger
@@ -229,6 +656,12 @@ func (r *localRobot) StopWeb() {
 	r.webSvc.Stop()
 }
 
+// RotateWebCredentials swaps the credentials of the running web server for those in the
+// given options without restarting it.
+func (r *localRobot) RotateWebCredentials(ctx context.Context, o weboptions.Options) error {
+	return r.webSvc.RotateCredentials(ctx, o)
+}
+
 // WebAddress return the web service's address.
 func (r *localRobot) WebAddress() (string, error) {
 	return r.webSvc.Address(), nil
@@ -351,6 +784,9 @@ func (r *localRobot) Status(ctx context.Context, resourceNames []resource.Name)
 				Name:             name,
 				LastReconfigured: *lastReconfigured,
 				Status:           status,
+				LastError:        r.lastError(name),
+				LastStop:         r.lastStop(name),
+				SelfTest:         r.selfTestResult(name),
 			}
 		}
 		combinedResourceStatuses = append(combinedResourceStatuses, resourceStatus)
@@ -370,21 +806,51 @@ func newWithResources(
 	for _, opt := range opts {
 		opt.apply(&rOpts)
 	}
+	if rOpts.logger != nil {
+		logger = rOpts.logger
+	}
+	tlsConfig := cfg.Network.TLSConfig
+	if rOpts.tlsConfig != nil {
+		tlsConfig = rOpts.tlsConfig
+	}
 
+	homeDir := config.ViamDotDir
+	if rOpts.viamHomeDir != "" {
+		homeDir = rOpts.viamHomeDir
+	}
+
+	eventBus := events.NewBus(logger.Sublogger("events"))
+	metricsRecorder := metrics.NewRecorder()
 	closeCtx, cancel := context.WithCancel(ctx)
 	r := &localRobot{
 		manager: newResourceManager(
 			resourceManagerOptions{
-				debug:              cfg.Debug,
+				debug:              cfg.Debug || rOpts.debug,
 				fromCommand:        cfg.FromCommand,
-				allowInsecureCreds: cfg.AllowInsecureCreds,
-				untrustedEnv:       cfg.UntrustedEnv,
-				tlsConfig:          cfg.Network.TLSConfig,
+				allowInsecureCreds: cfg.AllowInsecureCreds || rOpts.allowInsecureCreds,
+				untrustedEnv:       cfg.UntrustedEnv || rOpts.untrustedEnv,
+				readOnly:           cfg.ReadOnly || rOpts.readOnly,
+				simulation:         cfg.Simulation || rOpts.simulation,
+				tlsConfig:          tlsConfig,
+				viewer:             rOpts.viewer,
+				limitViolated: func(name resource.Name, err error) {
+					eventBus.Publish(events.Event{Type: events.LimitViolated, Name: name, Error: err.Error()})
+				},
+				metrics: metricsRecorder,
 			},
 			logger,
 		),
 		operations:                 operation.NewManager(logger),
+		faultInjector:              faults.NewInjector(logger.Sublogger("faults")),
+		arbiter:                    arbitration.NewArbiter(logger.Sublogger("arbitration")),
+		restarting:                 map[string]bool{},
+		healthTracker:              health.NewTracker(),
+		metrics:                    metricsRecorder,
+		kv:                         kv.NewStore(filepath.Join(homeDir, kv.FileName), logger.Sublogger("kv")),
+		metadata:                   metadata.NewStore(),
+		eventBus:                   eventBus,
 		logger:                     logger,
+		auditLogger:                logger.Sublogger("config_audit"),
 		closeContext:               closeCtx,
 		cancelBackgroundWorkers:    cancel,
 		triggerConfig:              make(chan struct{}),
@@ -394,13 +860,26 @@ func newWithResources(
 		shutdownCallback:           rOpts.shutdownCallback,
 	}
 	r.mostRecentCfg.Store(config.Config{})
-	var heartbeatWindow time.Duration
-	if cfg.Network.Sessions.HeartbeatWindow == 0 {
-		heartbeatWindow = config.DefaultSessionHeartbeatWindow
-	} else {
-		heartbeatWindow = cfg.Network.Sessions.HeartbeatWindow
-	}
-	r.sessionManager = robot.NewSessionManager(r, heartbeatWindow)
+	r.stateTracker = newStateTracker(r.eventBus)
+	r.watchdog = watchdog.NewWatchdog(logger.Sublogger("watchdog"), r.restartResource)
+	r.selfTester = selftest.NewTester(logger.Sublogger("selftest"), r.selfTestComponents, r.ResourceByName)
+	r.tools = tools.NewManager(logger.Sublogger("tools"), r.toolArm, r.updateFrameSystem)
+	r.runtimeFrames = framesystem.NewRuntimeFrames(logger.Sublogger("runtime_frames"), r.updateRuntimeFrames,
+		func(frame string) {
+			r.eventBus.Publish(events.Event{Type: events.FrameSystemUpdated, Frame: frame})
+		})
+	r.controlLoops = controlloops.NewHost(logger.Sublogger("control_loops"), r.controlled)
+	r.coordinator = coordination.NewCoordinator(logger.Sublogger("coordination"), r.remoteCoordinator)
+	r.missions = mission.NewQueue(r, r.kv, logger.Sublogger("missions"))
+	r.trajectories = trajectories.NewRecorder(r, r.kv, logger.Sublogger("trajectories"))
+	r.homing = homing.NewKeeper(r, r.kv, logger.Sublogger("homing"))
+	r.timeSync = timesync.NewTracker(r, logger.Sublogger("timesync"), timesync.DefaultInterval)
+	r.mapTiles = maptiles.NewCache(logger.Sublogger("map_tiles"), filepath.Join(homeDir, maptiles.DirName), nil)
+	sessionsCfg := cfg.Network.Sessions
+	if sessionsCfg.HeartbeatWindow == 0 {
+		sessionsCfg.HeartbeatWindow = config.DefaultSessionHeartbeatWindow
+	}
+	r.sessionManager = robot.NewSessionManagerFromConfig(r, sessionsCfg)
 
 	var successful bool
 	defer func() {
@@ -439,10 +918,17 @@ func newWithResources(
 	// we assume these never appear in our configs and as such will not be removed from the
 	// resource graph
 	r.webSvc = web.New(r, logger, rOpts.webOptions...)
+	discoveryLogger := logger.Sublogger("remote_discovery")
+	lanNetwork := rOpts.lanNetwork
+	if lanNetwork == nil {
+		lanNetwork = lan.NewMDNSNetwork(discoveryLogger)
+	}
+	r.remoteDiscoverer = lan.NewDiscoverer(discoveryLogger, lanNetwork, r.webSvc.Address, r.addDiscoveredRemotes)
 	r.frameSvc, err = framesystem.New(ctx, resource.Dependencies{}, logger)
 	if err != nil {
 		return nil, err
 	}
+	r.obstacles = r.frameSvc.(framesystem.ObstacleKeeper).Obstacles()
 	if err := r.manager.resources.AddNode(
 		web.InternalServiceName,
 		resource.NewConfiguredGraphNode(resource.Config{}, r.webSvc, builtinModel)); err != nil {
@@ -473,16 +959,12 @@ func newWithResources(
 		cloudID = cfg.Cloud.ID
 	}
 
-	homeDir := config.ViamDotDir
-	if rOpts.viamHomeDir != "" {
-		homeDir = rOpts.viamHomeDir
-	}
 	// Once web service is started, start module manager
 	r.manager.startModuleManager(
 		closeCtx,
 		r.webSvc.ModuleAddress(),
 		r.removeOrphanedResources,
-		cfg.UntrustedEnv,
+		r.manager.opts.untrustedEnv,
 		homeDir,
 		cloudID,
 		logger,
@@ -490,11 +972,12 @@ func newWithResources(
 	)
 
 	r.activeBackgroundWorkers.Add(1)
-	r.configTicker = time.NewTicker(5 * time.Second)
+	r.retryInterval = config.DefaultResourceRetryInterval
+	r.configTicker = time.NewTicker(r.retryInterval)
 	// This goroutine tries to complete the config and update weak dependencies
-	// if any resources are not configured. It executes every 5 seconds or when
-	// manually triggered. Manual triggers are sent when changes in remotes are
-	// detected and in testing.
+	// if any resources are not configured, such as resources that failed to build.
+	// It executes every resource retry interval or when manually triggered. Manual
+	// triggers are sent when changes in remotes are detected and in testing.
 	goutils.ManagedGo(func() {
 		for {
 			if closeCtx.Err() != nil {
@@ -510,12 +993,24 @@ func newWithResources(
 				r.logger.CDebugw(ctx, "configuration attempt triggered by remote")
 			}
 			anyChanges := r.manager.updateRemotesResourceNames(closeCtx)
+			if anyChanges {
+				r.cacheConnectedRemotesMetadata(closeCtx)
+			}
 			if r.manager.anyResourcesNotConfigured() {
 				anyChanges = true
-				r.manager.completeConfig(closeCtx, r, false)
+				r.retryFailedResources(closeCtx)
 			}
+			if r.manager.updateFailovers() {
+				anyChanges = true
+			}
+			// changed kinematics files are read again, and the frame system rebuilt with them.
+			if r.manager.updateKinematics() {
+				anyChanges = true
+			}
+			r.stateTracker.publishChanges(r.manager)
 			if anyChanges {
 				r.updateWeakDependents(ctx)
+				r.homing.Restore(ctx)
 				r.logger.CDebugw(ctx, "configuration attempt completed with changes")
 			} else {
 				r.logger.CDebugw(ctx, "configuration attempt completed without changes")
@@ -524,6 +1019,9 @@ func newWithResources(
 	}, r.activeBackgroundWorkers.Done)
 
 	r.Reconfigure(ctx, cfg)
+	if err := r.checkStartedResources(ctx, cfg.DisablePartialStart); err != nil {
+		return nil, err
+	}
 
 	for name, res := range resources {
 		if err := r.manager.resources.AddNode(
@@ -536,10 +1034,84 @@ func newWithResources(
 		r.updateWeakDependents(ctx)
 	}
 
+	// the self-test runs once the robot is first configured so that its report is available
+	// before the robot is dispatched.
+	if cfg.SelfTest != nil {
+		report, err := r.selfTester.Run(ctx)
+		switch {
+		case err != nil:
+			r.logger.CErrorw(ctx, "failed to run self-test", "error", err)
+		case report.Passed:
+			r.logger.CInfow(ctx, "all components passed self-test", "components", len(report.Results))
+		default:
+			r.logger.CWarnw(ctx, "some components failed self-test; see the self-test report")
+		}
+	}
+
+	// tasks left from before a restart run once the robot is configured.
+	if err := r.missions.Start(ctx); err != nil {
+		r.logger.CErrorw(ctx, "failed to start mission queue", "error", err)
+	}
+
 	successful = true
 	return r, nil
 }
 
+// retryFailedResources completes the config, building the resources that failed to build, and
+// reports those that built. Their recovery is published to the event bus with the other
+// changes to the state of the robot's resources.
+func (r *localRobot) retryFailedResources(ctx context.Context) {
+	required, optional := r.manager.failedResources()
+	r.manager.completeConfig(ctx, r, false)
+	stillRequired, stillOptional := r.manager.failedResources()
+	for _, failed := range []map[resource.Name]error{required, optional} {
+		for name := range failed {
+			_, requiredFailed := stillRequired[name]
+			_, optionalFailed := stillOptional[name]
+			if !requiredFailed && !optionalFailed {
+				r.logger.CInfow(ctx, "resource that failed to build is now available", "resource", name)
+			}
+		}
+	}
+}
+
+// setResourceRetryInterval sets how often the resources that failed to build are retried,
+// where zero means the default.
+func (r *localRobot) setResourceRetryInterval(interval time.Duration) {
+	if interval == 0 {
+		interval = config.DefaultResourceRetryInterval
+	}
+	if r.configTicker == nil || interval == r.retryInterval {
+		return
+	}
+	r.retryInterval = interval
+	r.configTicker.Reset(interval)
+}
+
+// checkStartedResources reports the resources that failed to build as the robot started,
+// which are retried in the background. With partial start disabled, it returns an error if
+// any of them are not optional.
+func (r *localRobot) checkStartedResources(ctx context.Context, disablePartialStart bool) error {
+	required, optional := r.manager.failedResources()
+	for name, err := range optional {
+		r.logger.CWarnw(ctx, "optional resource failed to build; starting robot without it and retrying in the background",
+			"resource", name, "error", err)
+	}
+	if !disablePartialStart || len(required) == 0 {
+		return nil
+	}
+	names := make([]resource.Name, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b resource.Name) int { return strings.Compare(a.String(), b.String()) })
+	var errs error
+	for _, name := range names {
+		errs = multierr.Combine(errs, errors.Wrapf(required[name], "resource %s", name))
+	}
+	return errors.Wrap(errs, "partial start is disabled and required resources failed to build")
+}
+
 // New returns a new robot with parts sourced from the given config.
 func New(
 	ctx context.Context,
@@ -550,6 +1122,12 @@ func New(
 	return newWithResources(ctx, cfg, nil, logger, opts...)
 }
 
+// NewWithOptions returns a new robot with parts sourced from the given config, customized
+// by the given options. Unless WithLogger is given, the robot logs with a new logger.
+func NewWithOptions(ctx context.Context, cfg *config.Config, opts ...Option) (robot.LocalRobot, error) {
+	return newWithResources(ctx, cfg, nil, logging.NewLogger("robot"), opts...)
+}
+
 // removeOrphanedResources is called by the module manager to remove resources
 // orphaned due to module crashes.
 func (r *localRobot) removeOrphanedResources(ctx context.Context,
@@ -673,6 +1251,11 @@ func (r *localRobot) newResource(
 		}
 	}
 
+	if r.manager.opts.simulation {
+		if simulate, ok := resource.LookupSimulator(resName.API, conf.Model); ok {
+			return simulate(ctx, deps, conf, gNode.Logger())
+		}
+	}
 	if resInfo.Constructor != nil {
 		return resInfo.Constructor(ctx, deps, conf, gNode.Logger())
 	}
@@ -683,6 +1266,7 @@ func (r *localRobot) newResource(
 }
 
 func (r *localRobot) updateWeakDependents(ctx context.Context) {
+	ctx = r.manager.withSimulation(ctx)
 	// Track the current value of the resource graph's logical clock. This will
 	// later be used to determine if updateWeakDependents should be called during
 	// getDependencies.
@@ -843,16 +1427,52 @@ func (r *localRobot) updateWeakDependents(ctx context.Context) {
 // The output of this function is to be sent over GRPC to the client, so the client
 // can build its frame system. requests the remote components from the remote's frame system service.
 func (r *localRobot) FrameSystemConfig(ctx context.Context) (*framesystem.Config, error) {
-	localParts, err := r.getLocalFrameSystemParts()
+	fsCfg, leftOut, err := r.frameSystemConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if len(leftOut) != 0 {
+		r.logger.CDebugw(ctx, "runtime frames left out of the frame system", "frames", leftOut)
+	}
+	return fsCfg, nil
+}
+
+// frameSystemConfig returns the frame system config of the robot and the names of the
+// runtime frames left out of it because they are not connected to the world frame.
+func (r *localRobot) frameSystemConfig(ctx context.Context) (*framesystem.Config, []string, error) {
+	localParts, err := r.getLocalFrameSystemParts()
+	if err != nil {
+		return nil, nil, err
+	}
 	remoteParts, err := r.getRemoteFrameSystemParts(ctx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
+	parts := append(localParts, remoteParts...)
 
-	return &framesystem.Config{Parts: append(localParts, remoteParts...)}, nil
+	// runtime frames may be children of remote frames as well as local ones.
+	runtimeParts, leftOut, err := r.runtimeFrames.FrameSystemParts(parts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &framesystem.Config{Parts: append(parts, runtimeParts...), Odometry: r.frameOdometry()}, leftOut, nil
+}
+
+// frameOdometry returns the odometry driving the frames of the components of the robot, by
+// the names of their frames.
+func (r *localRobot) frameOdometry() map[string]resource.OdometryConfig {
+	odometry := map[string]resource.OdometryConfig{}
+	for _, component := range r.Config().Components {
+		if component.Odometry == nil || component.Frame == nil {
+			continue
+		}
+		name := component.Frame.ID
+		if name == "" {
+			name = component.Name
+		}
+		odometry[name] = *component.Odometry
+	}
+	return odometry
 }
 
 // getLocalFrameSystemParts collects and returns the physical parts of the robot that may have frame info,
@@ -896,6 +1516,20 @@ func (r *localRobot) getLocalFrameSystemParts() ([]*referenceframe.FrameSystemPa
 
 		parts = append(parts, &referenceframe.FrameSystemPart{FrameConfig: lif, ModelFrame: model})
 	}
+
+	// tools are only part of the frame system while the arm they are mounted on is.
+	toolParts, err := r.tools.FrameSystemParts()
+	if err != nil {
+		return nil, err
+	}
+	for _, toolPart := range toolParts {
+		for _, part := range parts {
+			if part.FrameConfig.Name() == toolPart.FrameConfig.Parent() {
+				parts = append(parts, toolPart)
+				break
+			}
+		}
+	}
 	return parts, nil
 }
 
@@ -910,30 +1544,48 @@ func (r *localRobot) getRemoteFrameSystemParts(ctx context.Context) ([]*referenc
 
 	remoteParts := make([]*referenceframe.FrameSystemPart, 0)
 	for _, remoteCfg := range cfg.Remotes {
-		// remote could be in config without being available (remotes could be down or otherwise unavailable)
-		if _, ok := remoteNameSet[remoteCfg.Name]; !ok {
-			r.logger.CDebugf(ctx, "remote %q is not available, skipping", remoteCfg.Name)
-			continue
-		}
 		// build the frame system part that connects remote world to base world
 		if remoteCfg.Frame == nil { // skip over remote if it has no frame info
 			r.logger.CDebugf(ctx, "remote %q has no frame config info, skipping", remoteCfg.Name)
 			continue
 		}
 
-		remoteRobot, ok := r.RemoteByName(remoteCfg.Name)
-		if !ok {
-			return nil, errors.Errorf("cannot find remote robot %q", remoteCfg.Name)
-		}
+		// remote could be in config without being available (remotes could be down or otherwise unavailable)
+		var remote robot.RemoteRobot
+		if _, ok := remoteNameSet[remoteCfg.Name]; ok {
+			remoteRobot, ok := r.RemoteByName(remoteCfg.Name)
+			if !ok {
+				return nil, errors.Errorf("cannot find remote robot %q", remoteCfg.Name)
+			}
 
-		remote, err := utils.AssertType[robot.RemoteRobot](remoteRobot)
-		if err != nil {
-			// should never happen
-			return nil, err
+			var err error
+			remote, err = utils.AssertType[robot.RemoteRobot](remoteRobot)
+			if err != nil {
+				// should never happen
+				return nil, err
+			}
 		}
-		if !remote.Connected() {
-			r.logger.CDebugf(ctx, "remote %q is not connected, skipping", remoteCfg.Name)
-			continue
+		var remoteFsParts []*referenceframe.FrameSystemPart
+		if remote != nil && remote.Connected() {
+			// get the parts from the remote itself
+			remoteFsCfg, err := remote.FrameSystemConfig(ctx)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error from remote %q", remoteCfg.Name)
+			}
+			remoteFsParts = remoteFsCfg.Parts
+		} else {
+			// serve the frame system the remote had when it was last connected, if it was cached
+			md, ok := r.cachedRemoteMetadata(ctx, remoteCfg)
+			if !ok {
+				r.logger.CDebugf(ctx, "remote %q is not connected, skipping", remoteCfg.Name)
+				continue
+			}
+			r.logger.CDebugf(ctx, "remote %q is not connected, using its frame system cached at %s", remoteCfg.Name, md.CachedAt)
+			parts, err := md.frameSystemParts()
+			if err != nil {
+				return nil, errors.Wrapf(err, "error reading cached frame system of remote %q", remoteCfg.Name)
+			}
+			remoteFsParts = parts
 		}
 
 		lif, err := remoteCfg.Frame.ParseConfig()
@@ -943,14 +1595,8 @@ func (r *localRobot) getRemoteFrameSystemParts(ctx context.Context) ([]*referenc
 		parentName := remoteCfg.Name + "_" + referenceframe.World
 		lif.SetName(parentName)
 		remoteParts = append(remoteParts, &referenceframe.FrameSystemPart{FrameConfig: lif})
-
-		// get the parts from the remote itself
-		remoteFsCfg, err := remote.FrameSystemConfig(ctx)
-		if err != nil {
-			return nil, errors.Wrapf(err, "error from remote %q", remoteCfg.Name)
-		}
-		framesystem.PrefixRemoteParts(remoteFsCfg.Parts, remoteCfg.Name, parentName)
-		remoteParts = append(remoteParts, remoteFsCfg.Parts...)
+		framesystem.PrefixRemoteParts(remoteFsParts, remoteCfg.Name, parentName)
+		remoteParts = append(remoteParts, remoteFsParts...)
 	}
 	return remoteParts, nil
 }
@@ -978,6 +1624,25 @@ func (r *localRobot) TransformPose(
 	return r.frameSvc.TransformPose(ctx, pose, dst, additionalTransforms)
 }
 
+// LiveFrameSystem returns the frame system of the robot, including the given additional
+// transforms, with its inputs read from the robot's components on each query.
+func (r *localRobot) LiveFrameSystem(
+	ctx context.Context,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) (*framesystem.LiveFrameSystem, error) {
+	return framesystem.NewLiveFrameSystem(ctx, r.frameSvc, additionalTransforms)
+}
+
+// ReadOnly returns whether the robot rejects the requests that would actuate it.
+func (r *localRobot) ReadOnly() bool {
+	return r.manager.opts.readOnly
+}
+
+// CachedFrameSystem returns the frame system of the robot without reading any of its components.
+func (r *localRobot) CachedFrameSystem(ctx context.Context) (referenceframe.FrameSystem, error) {
+	return framesystem.CachedFrameSystem(ctx, r.frameSvc)
+}
+
 // TransformPointCloud will transform the pointcloud to the desired frame in the robot's frame system.
 // Do not move the robot between the generation of the initial pointcloud and the receipt
 // of the transformed pointcloud because that will make the transformations inaccurate.
@@ -1091,6 +1756,24 @@ func dialRobotClient(
 	if config.ReconnectInterval != 0 {
 		rOpts = append(rOpts, client.WithReconnectEvery(config.ReconnectInterval))
 	}
+	if config.MaxReconnectInterval != 0 {
+		rOpts = append(rOpts, client.WithReconnectBackoff(config.MaxReconnectInterval))
+	}
+	if config.CallPolicy != nil {
+		retryCodes, err := config.CallPolicy.StatusCodes()
+		if err != nil {
+			return nil, err
+		}
+		rOpts = append(rOpts, client.WithCallPolicy(client.CallPolicy{
+			AttemptTimeout:    config.CallPolicy.AttemptTimeout,
+			MaxAttempts:       config.CallPolicy.MaxAttempts,
+			InitialBackoff:    config.CallPolicy.InitialBackoff,
+			MaxBackoff:        config.CallPolicy.MaxBackoff,
+			HedgeDelay:        config.CallPolicy.HedgeDelay,
+			RetryCodes:        retryCodes,
+			IdempotentMethods: config.CallPolicy.IdempotentMethods,
+		}))
+	}
 
 	robotClient, err := client.New(
 		ctx,
@@ -1114,6 +1797,8 @@ func (r *localRobot) Reconfigure(ctx context.Context, newConfig *config.Config)
 func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config, forceSync bool) {
 	var allErrs error
 
+	newConfig = r.applyProfile(ctx, newConfig)
+
 	// Sync Packages before reconfiguring rest of robot and resolving references to any packages
 	// in the config.
 	// TODO(RSDK-1849): Make this non-blocking so other resources that do not require packages can run before package sync finishes.
@@ -1125,6 +1810,29 @@ func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config,
 	}
 	allErrs = multierr.Combine(allErrs, r.localPackages.Sync(ctx, newConfig.Packages, newConfig.Modules))
 
+	if newConfig.ReadOnly && !r.manager.opts.readOnly {
+		r.logger.CWarn(ctx, "read_only takes effect when the robot starts; restart the robot to make it read-only")
+	}
+	if newConfig.Simulation && !r.manager.opts.simulation {
+		r.logger.CWarn(ctx, "simulation takes effect when the robot starts; restart the robot to simulate it")
+	}
+	r.faultInjector.Reconfigure(newConfig.Faults)
+	r.manager.setMaxRemoteDepth(newConfig.MaxRemoteDepth)
+	r.setResourceRetryInterval(newConfig.ResourceRetryInterval)
+	r.remoteDiscoverer.Reconfigure(newConfig.RemoteDiscovery)
+	if discovered := r.remoteDiscoverer.Remotes(newConfig.Remotes); len(discovered) != 0 {
+		newConfig.Remotes = append(slices.Clone(newConfig.Remotes), discovered...)
+	}
+	r.selfTester.Reconfigure(newConfig.SelfTest)
+	r.mapTiles.Reconfigure(newConfig.MapTiles)
+	r.obstacles.Reconfigure(newConfig.Obstacles)
+	// control loops are reconfigured last so that they drive resources as rebuilt.
+	defer r.controlLoops.Reconfigure(ctx, newConfig.ControlLoops)
+	apiDeadlines, defaultDeadline := newConfig.CallDeadlines.Deadlines()
+	r.watchdog.SetDeadlines(watchdog.Deadlines{APIs: apiDeadlines, Default: defaultDeadline})
+	r.watchdog.Reconfigure(append(slices.Clone(newConfig.Components), newConfig.Services...))
+	r.metadata.Reconfigure(append(slices.Clone(newConfig.Components), newConfig.Services...))
+
 	// Add default services and process their dependencies. Dependencies may
 	// already come from config validation so we check that here.
 	seen := make(map[resource.API]int)
@@ -1216,6 +1924,8 @@ func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config,
 	if err := r.manager.removeMarkedAndClose(ctx, alreadyClosed); err != nil {
 		allErrs = multierr.Combine(allErrs, err)
 	}
+	r.stateTracker.publishChanges(r.manager)
+	r.homing.Restore(ctx)
 
 	// Cleanup unused packages after all old resources have been closed above. This ensures
 	// processes are shutdown before any files are deleted they are using.
@@ -1229,6 +1939,13 @@ func (r *localRobot) reconfigure(ctx context.Context, newConfig *config.Config,
 	} else {
 		r.logger.CInfow(ctx, "Robot (re)configured")
 	}
+
+	// the changes hold no values of the configs, so they are logged regardless of whether
+	// sensitive config diffs are revealed.
+	for _, change := range diff.Changes() {
+		r.auditLogger.CInfow(ctx, "applied config change",
+			"kind", change.Kind, "section", change.Section, "name", change.Name, "fields", change.Fields)
+	}
 }
 
 // checkMaxInstance checks to see if the local robot has reached the maximum number of a specific resource type that are local.
@@ -2,14 +2,23 @@ package robotimpl
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"maps"
 	"math"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -29,26 +38,34 @@ import (
 	"go.viam.com/utils/testutils"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/status"
 
 	"go.viam.com/rdk/cloud"
 	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/components/arm/fake"
+	ur "go.viam.com/rdk/components/arm/universalrobots"
 	"go.viam.com/rdk/components/audioinput"
 	"go.viam.com/rdk/components/base"
+	fakebase "go.viam.com/rdk/components/base/fake"
 	"go.viam.com/rdk/components/board"
+	fakeboard "go.viam.com/rdk/components/board/fake"
+	"go.viam.com/rdk/components/board/genericlinux"
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/components/encoder"
 	fakeencoder "go.viam.com/rdk/components/encoder/fake"
 	"go.viam.com/rdk/components/generic"
 	"go.viam.com/rdk/components/gripper"
+	fakegripper "go.viam.com/rdk/components/gripper/fake"
 	"go.viam.com/rdk/components/motor"
 	fakemotor "go.viam.com/rdk/components/motor/fake"
 	"go.viam.com/rdk/components/movementsensor"
 	_ "go.viam.com/rdk/components/register"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/control"
 	"go.viam.com/rdk/examples/customresources/apis/gizmoapi"
 	"go.viam.com/rdk/examples/customresources/apis/summationapi"
+	"go.viam.com/rdk/faults"
 	rgrpc "go.viam.com/rdk/grpc"
 	internalcloud "go.viam.com/rdk/internal/cloud"
 	"go.viam.com/rdk/logging"
@@ -56,10 +73,15 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/events"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/health"
+	lantestutils "go.viam.com/rdk/robot/lan/testutils"
+	"go.viam.com/rdk/robot/metadata"
 	"go.viam.com/rdk/robot/packages"
 	putils "go.viam.com/rdk/robot/packages/testutils"
 	"go.viam.com/rdk/robot/server"
+	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	"go.viam.com/rdk/services/datamanager"
 	"go.viam.com/rdk/services/datamanager/builtin"
@@ -2009,6 +2031,11 @@ func TestReconnectRemote(t *testing.T) {
 	test.That(t, len(robot1.ResourceNames()), test.ShouldEqual, 2)
 	_, err = anArm.EndPosition(context.Background(), map[string]interface{}{})
 	test.That(t, err, test.ShouldBeError)
+	report, err := robot1.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Remotes, test.ShouldHaveLength, 1)
+	test.That(t, report.Remotes[0].Name, test.ShouldEqual, "remote")
+	test.That(t, report.Remotes[0].Connected, test.ShouldBeFalse)
 
 	// reconnect the first robot
 	ctx2 := context.Background()
@@ -2035,6 +2062,10 @@ func TestReconnectRemote(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	_, err = anArm.EndPosition(context.Background(), map[string]interface{}{})
 	test.That(t, err, test.ShouldBeNil)
+	report, err = robot1.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Remotes[0].Connected, test.ShouldBeTrue)
+	test.That(t, report.Remotes[0].ReconnectAttempts, test.ShouldEqual, 0)
 }
 
 func TestReconnectRemoteChangeConfig(t *testing.T) {
@@ -3347,3 +3378,1467 @@ func TestCloudMetadata(t *testing.T) {
 		})
 	})
 }
+
+func TestUpdateFirmware(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	image := []byte("some firmware")
+	pub, priv, err := ed25519.GenerateKey(nil)
+	test.That(t, err, test.ShouldBeNil)
+	artifact := path.Join(t.TempDir(), "firmware.bin")
+	test.That(t, os.WriteFile(artifact, image, 0o600), test.ShouldBeNil)
+
+	var uploaded []byte
+	flashing := make(chan struct{})
+	release := make(chan struct{})
+	otaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		file, _, err := r.FormFile("update")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		uploaded, _ = io.ReadAll(file)
+		flashing <- struct{}{}
+		<-release
+	}))
+	defer otaServer.Close()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "m1",
+				Model:               fakeModel,
+				API:                 motor.API,
+				ConvertedAttributes: &fakemotor.Config{},
+			},
+		},
+		Firmware: []config.FirmwareConfig{
+			{
+				Name:      "mcu",
+				Target:    config.FirmwareTargetESP32OTA,
+				Address:   otaServer.URL,
+				Artifact:  artifact,
+				Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(priv, image)),
+				PublicKey: base64.StdEncoding.EncodeToString(pub),
+				Resources: []string{"m1"},
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	before, err := motor.FromRobot(r, "m1")
+	test.That(t, err, test.ShouldBeNil)
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	updated := make(chan error)
+	go func() {
+		updated <- robotClient.Firmware().UpdateFirmware(ctx, "mcu")
+	}()
+	<-flashing
+
+	// only the motor is unavailable while flashing, the robot can still be reconfigured.
+	_, err = motor.FromRobot(r, "m1")
+	test.That(t, err, test.ShouldNotBeNil)
+	newCfg := &config.Config{
+		Components: append(slices.Clone(cfg.Components), resource.Config{
+			Name:                "m2",
+			Model:               fakeModel,
+			API:                 motor.API,
+			ConvertedAttributes: &fakemotor.Config{},
+		}),
+		Firmware: cfg.Firmware,
+	}
+	r.Reconfigure(ctx, newCfg)
+	_, err = motor.FromRobot(r, "m2")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = motor.FromRobot(r, "m1")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	close(release)
+	test.That(t, <-updated, test.ShouldBeNil)
+	test.That(t, uploaded, test.ShouldResemble, image)
+
+	// the motor should have been rebuilt after flashing.
+	after, err := motor.FromRobot(r, "m1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, after, test.ShouldNotEqual, before)
+
+	err = r.(*localRobot).UpdateFirmware(ctx, "other")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not found")
+}
+
+func TestFaultInjection(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "m1",
+				Model:               fakeModel,
+				API:                 motor.API,
+				ConvertedAttributes: &fakemotor.Config{},
+			},
+		},
+		Faults: []config.FaultConfig{
+			{Resource: "m1", Type: config.FaultTypeError, Code: "UNAVAILABLE", Methods: []string{"IsPowered"}},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	options.Debug = true
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	m1, err := motor.FromRobot(robotClient, "m1")
+	test.That(t, err, test.ShouldBeNil)
+
+	_, _, err = m1.IsPowered(ctx, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+	_, err = m1.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+
+	// faults can be replaced at runtime in debug mode.
+	resp, err := m1.DoCommand(ctx, map[string]interface{}{faults.CommandKey: map[string]interface{}{
+		"faults": []interface{}{map[string]interface{}{"type": "disconnect"}},
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp["faults"], test.ShouldHaveLength, 1)
+	_, _, err = m1.IsPowered(ctx, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+	_, err = m1.IsMoving(ctx)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Unavailable)
+
+	// a new config replaces them.
+	newCfg := *cfg
+	newCfg.Faults = nil
+	r.Reconfigure(ctx, &newCfg)
+	test.That(t, r.(*localRobot).FaultInjector().Faults(), test.ShouldBeEmpty)
+	_, _, err = m1.IsPowered(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+}
+
+func TestHealth(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "m1",
+				Model:               fakeModel,
+				API:                 motor.API,
+				ConvertedAttributes: &fakemotor.Config{},
+			},
+			{
+				Name:  "m2",
+				Model: resource.DefaultModelFamily.WithModel("does-not-exist"),
+				API:   motor.API,
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	report, err := r.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Ready, test.ShouldBeFalse)
+	m1Health, ok := report.Resource("m1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, m1Health.Healthy(), test.ShouldBeTrue)
+	test.That(t, m1Health.LastReconfigured, test.ShouldNotBeNil)
+	test.That(t, m1Health.LastSuccess.IsZero(), test.ShouldBeTrue)
+	m2Health, ok := report.Resource("m2")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, m2Health.Constructed, test.ShouldBeFalse)
+	test.That(t, m2Health.Error, test.ShouldNotBeNil)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	m1, err := motor.FromRobot(robotClient, "m1")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = m1.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	report, err = r.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	m1Health, _ = report.Resource("m1")
+	test.That(t, m1Health.LastSuccess.IsZero(), test.ShouldBeFalse)
+
+	// health is served with the gRPC health checking protocol.
+	conn, err := rpc.DialDirectGRPC(ctx, addr, logger.AsZap(), rpc.WithInsecure())
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	healthClient := healthpb.NewHealthClient(conn)
+	for service, expected := range map[string]healthpb.HealthCheckResponse_ServingStatus{
+		"":                      healthpb.HealthCheckResponse_SERVING,
+		health.ServiceLiveness:  healthpb.HealthCheckResponse_SERVING,
+		health.ServiceReadiness: healthpb.HealthCheckResponse_NOT_SERVING,
+		"m1":                    healthpb.HealthCheckResponse_SERVING,
+		"m2":                    healthpb.HealthCheckResponse_NOT_SERVING,
+	} {
+		resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, resp.GetStatus(), test.ShouldEqual, expected)
+	}
+	_, err = healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: "m3"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+
+	// the robot is ready once every resource is.
+	newCfg := *cfg
+	newCfg.Components = cfg.Components[:1]
+	r.Reconfigure(ctx, &newCfg)
+	resp, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: health.ServiceReadiness})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.GetStatus(), test.ShouldEqual, healthpb.HealthCheckResponse_SERVING)
+}
+
+// hooked is a component that records the lifecycle hooks called on it.
+type hooked struct {
+	resource.Named
+	resource.AlwaysRebuild
+	mu     *sync.Mutex
+	events *[]string
+	fail   bool
+}
+
+func (h *hooked) record(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	*h.events = append(*h.events, h.Name().ShortName()+"."+event)
+}
+
+func (h *hooked) PreStart(ctx context.Context) error {
+	h.record("PreStart")
+	if h.fail {
+		return errors.New("calibration failed")
+	}
+	return nil
+}
+
+func (h *hooked) PostStart(ctx context.Context) error {
+	h.record("PostStart")
+	return nil
+}
+
+func (h *hooked) PreClose(ctx context.Context) error {
+	h.record("PreClose")
+	return nil
+}
+
+func (h *hooked) Close(ctx context.Context) error {
+	h.record("Close")
+	return nil
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	recorded := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), events...)
+	}
+	hookedModel := resource.DefaultModelFamily.WithModel("hooked")
+	resource.RegisterComponent(doodadAPI, hookedModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			return &hooked{
+				Named:  conf.ResourceName().AsNamed(),
+				mu:     &mu,
+				events: &events,
+				fail:   conf.Name == "h2",
+			}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, hookedModel)
+	}()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "h1", API: doodadAPI, Model: hookedModel},
+			{Name: "h2", API: doodadAPI, Model: hookedModel},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	_, err := r.ResourceByName(resource.NewName(doodadAPI, "h1"))
+	test.That(t, err, test.ShouldBeNil)
+	// a resource that fails to pre-start is closed and never available.
+	_, err = r.ResourceByName(resource.NewName(doodadAPI, "h2"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "calibration failed")
+
+	test.That(t, recorded(), test.ShouldContain, "h2.PreStart")
+	test.That(t, recorded(), test.ShouldContain, "h2.Close")
+	test.That(t, recorded(), test.ShouldNotContain, "h2.PostStart")
+
+	newCfg := *cfg
+	newCfg.Components = nil
+	r.Reconfigure(ctx, &newCfg)
+
+	var h1Events []string
+	for _, event := range recorded() {
+		if strings.HasPrefix(event, "h1.") {
+			h1Events = append(h1Events, event)
+		}
+	}
+	test.That(t, h1Events, test.ShouldResemble, []string{"h1.PreStart", "h1.PostStart", "h1.PreClose", "h1.Close"})
+}
+
+func TestStandbyFailover(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	flakyModel := resource.DefaultModelFamily.WithModel("flaky")
+	resource.RegisterComponent(doodadAPI, flakyModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			if conf.Attributes.Bool("fail", false) {
+				return nil, errors.New("unit not detected")
+			}
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, flakyModel)
+	}()
+
+	primaryName := resource.NewName(doodadAPI, "gps1")
+	standbyName := resource.NewName(doodadAPI, "gps2")
+	robotConfig := func(fail bool) *config.Config {
+		return &config.Config{
+			Components: []resource.Config{
+				{
+					Name:       primaryName.Name,
+					API:        doodadAPI,
+					Model:      flakyModel,
+					Attributes: rutils.AttributeMap{"fail": fail},
+					Standby:    standbyName.Name,
+				},
+				{Name: standbyName.Name, API: doodadAPI, Model: flakyModel},
+			},
+		}
+	}
+	r := setupLocalRobot(t, ctx, robotConfig(true), logger)
+	events := r.(*localRobot).Failovers().Watch(ctx)
+
+	// lookups of the primary are served by the standby while it is unavailable.
+	standby, err := r.ResourceByName(standbyName)
+	test.That(t, err, test.ShouldBeNil)
+	res, err := r.ResourceByName(primaryName)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res, test.ShouldEqual, standby)
+	failedOverTo, ok := r.(*localRobot).Failovers().FailedOver(primaryName)
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, failedOverTo, test.ShouldResemble, standbyName)
+
+	// and by the primary again once it is available.
+	r.Reconfigure(ctx, robotConfig(false))
+	res, err = r.ResourceByName(primaryName)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res.Name(), test.ShouldResemble, primaryName)
+	_, ok = r.(*localRobot).Failovers().FailedOver(primaryName)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// the primary failed over as the robot started, before the watch began.
+	select {
+	case ev := <-events:
+		test.That(t, ev.Primary, test.ShouldResemble, primaryName)
+		test.That(t, ev.Standby, test.ShouldResemble, standbyName)
+		test.That(t, ev.FailedOver, test.ShouldBeFalse)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for failover event")
+	}
+
+	// a resource without a standby is unavailable as before.
+	cfg := robotConfig(true)
+	cfg.Components[0].Standby = ""
+	r.Reconfigure(ctx, cfg)
+	_, err = r.ResourceByName(primaryName)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unit not detected")
+}
+
+func TestOptionalResources(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var plugged atomic.Bool
+	usbModel := resource.DefaultModelFamily.WithModel("usb")
+	resource.RegisterComponent(doodadAPI, usbModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			if !plugged.Load() {
+				return nil, errors.New("device unplugged")
+			}
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, usbModel)
+	}()
+
+	cameraName := resource.NewName(doodadAPI, "camera1")
+	robotConfig := func(optional bool) *config.Config {
+		return &config.Config{
+			DisablePartialStart: true,
+			Components: []resource.Config{
+				{Name: cameraName.Name, API: doodadAPI, Model: usbModel, Optional: optional},
+				{
+					Name:  "arm1",
+					API:   arm.API,
+					Model: fakeModel,
+					ConvertedAttributes: &fake.Config{
+						ModelFilePath: "../../components/arm/fake/fake_model.json",
+					},
+				},
+			},
+		}
+	}
+
+	// a required resource that fails to build stops the robot from starting.
+	_, err := New(ctx, robotConfig(false), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "device unplugged")
+	test.That(t, err.Error(), test.ShouldContainSubstring, cameraName.String())
+
+	// an optional one does not, and is retried in the background.
+	r := setupLocalRobot(t, ctx, robotConfig(true), logger)
+	_, err = r.ResourceByName(arm.Named("arm1"))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = r.ResourceByName(cameraName)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "device unplugged")
+
+	plugged.Store(true)
+	r.(*localRobot).triggerConfig <- struct{}{}
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		_, err := r.ResourceByName(cameraName)
+		test.That(tb, err, test.ShouldBeNil)
+	})
+}
+
+func TestEventBus(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	flakyModel := resource.DefaultModelFamily.WithModel("flaky")
+	resource.RegisterComponent(doodadAPI, flakyModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			if conf.Attributes.Bool("fail", false) {
+				return nil, errors.New("unit not detected")
+			}
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, flakyModel)
+	}()
+
+	gpsName := resource.NewName(doodadAPI, "gps1")
+	robotConfig := func(fail bool) *config.Config {
+		return &config.Config{
+			Components: []resource.Config{
+				{
+					Name:       gpsName.Name,
+					API:        doodadAPI,
+					Model:      flakyModel,
+					Attributes: rutils.AttributeMap{"fail": fail},
+				},
+			},
+		}
+	}
+	r := setupLocalRobot(t, ctx, &config.Config{}, logger)
+	subscription, err := r.(*localRobot).Events().Subscribe(ctx)
+	test.That(t, err, test.ShouldBeNil)
+
+	nextEvent := func() events.Event {
+		t.Helper()
+		for {
+			select {
+			case ev, ok := <-subscription:
+				test.That(t, ok, test.ShouldBeTrue)
+				if ev.Name == gpsName {
+					return ev
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for event")
+				return events.Event{}
+			}
+		}
+	}
+
+	r.Reconfigure(ctx, robotConfig(true))
+	ev := nextEvent()
+	test.That(t, ev.Type, test.ShouldEqual, events.ResourceAdded)
+	ev = nextEvent()
+	test.That(t, ev.Type, test.ShouldEqual, events.ResourceErrored)
+	test.That(t, ev.Error, test.ShouldContainSubstring, "unit not detected")
+
+	r.Reconfigure(ctx, robotConfig(false))
+	test.That(t, nextEvent().Type, test.ShouldEqual, events.ResourceRecovered)
+
+	r.Reconfigure(ctx, &config.Config{})
+	test.That(t, nextEvent().Type, test.ShouldEqual, events.ResourceRemoved)
+}
+
+func TestResourceRetryInterval(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var plugged atomic.Bool
+	usbModel := resource.DefaultModelFamily.WithModel("usb")
+	resource.RegisterComponent(doodadAPI, usbModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			if !plugged.Load() {
+				return nil, errors.New("device unplugged")
+			}
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, usbModel)
+	}()
+
+	cameraName := resource.NewName(doodadAPI, "camera1")
+	r := setupLocalRobot(t, ctx, &config.Config{}, logger)
+	subscription, err := r.(*localRobot).Events().Subscribe(ctx)
+	test.That(t, err, test.ShouldBeNil)
+
+	r.Reconfigure(ctx, &config.Config{
+		ResourceRetryInterval: 50 * time.Millisecond,
+		Components:            []resource.Config{{Name: cameraName.Name, API: doodadAPI, Model: usbModel}},
+	})
+	_, err = r.ResourceByName(cameraName)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// the resource is built once the device is plugged in, well before the default interval,
+	// without anything triggering a retry.
+	plugged.Store(true)
+	deadline := time.After(config.DefaultResourceRetryInterval / 2)
+	for {
+		select {
+		case ev := <-subscription:
+			if ev.Name != cameraName || ev.Type != events.ResourceRecovered {
+				continue
+			}
+			_, err = r.ResourceByName(cameraName)
+			test.That(t, err, test.ShouldBeNil)
+			return
+		case <-deadline:
+			t.Fatal("timed out waiting for the resource to be retried")
+		}
+	}
+}
+
+func TestLastErrorAndStop(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "arm1",
+				API:                 arm.API,
+				Model:               resource.DefaultModelFamily.WithModel("fake"),
+				ConvertedAttributes: &fake.Config{ModelFilePath: "../../components/arm/fake/fake_model.json"},
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	report, err := r.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	armHealth, ok := report.Resource("arm1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, armHealth.LastError, test.ShouldBeNil)
+	test.That(t, armHealth.LastStop, test.ShouldBeNil)
+
+	r.(*localRobot).HealthTracker().RecordError("arm1", resource.NewInvalidCommandError(errors.New("joint 3 out of range")))
+	test.That(t, r.StopAll(ctx, nil), test.ShouldBeNil)
+
+	report, err = r.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	armHealth, _ = report.Resource("arm1")
+	test.That(t, armHealth.LastError, test.ShouldNotBeNil)
+	test.That(t, armHealth.LastError.Class, test.ShouldEqual, resource.ErrorClassInvalidCommand)
+	test.That(t, armHealth.LastStop, test.ShouldNotBeNil)
+	test.That(t, armHealth.LastStop.Reason, test.ShouldEqual, health.StopReasonStopAll)
+
+	// the status service reports them to clients.
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	statuses, err := robotClient.Status(ctx, []resource.Name{arm.Named("arm1")})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldHaveLength, 1)
+	test.That(t, statuses[0].LastError, test.ShouldNotBeNil)
+	test.That(t, statuses[0].LastError.Error, test.ShouldEqual, "joint 3 out of range")
+	test.That(t, statuses[0].LastError.Class, test.ShouldEqual, resource.ErrorClassInvalidCommand)
+	test.That(t, statuses[0].LastError.Time.Equal(armHealth.LastError.Time), test.ShouldBeTrue)
+	test.That(t, statuses[0].LastStop, test.ShouldNotBeNil)
+	test.That(t, statuses[0].LastStop.Reason, test.ShouldEqual, health.StopReasonStopAll)
+	// the records are served apart from the status of the arm.
+	status, ok := statuses[0].Status.(map[string]interface{})
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, status, test.ShouldNotContainKey, "last_error")
+	test.That(t, status, test.ShouldNotContainKey, "last_stop")
+}
+
+func TestWatchdogRestart(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var constructed atomic.Int32
+	countingModel := resource.DefaultModelFamily.WithModel("counting")
+	resource.RegisterComponent(doodadAPI, countingModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			constructed.Add(1)
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, countingModel)
+	}()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:             "imu1",
+				API:              doodadAPI,
+				Model:            countingModel,
+				OperationTimeout: 20 * time.Millisecond,
+				RestartOnTimeout: true,
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	test.That(t, constructed.Load(), test.ShouldEqual, 1)
+	limit, ok := r.(*localRobot).Watchdog().Limit("imu1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, limit.Timeout, test.ShouldEqual, 20*time.Millisecond)
+
+	hung := make(chan struct{})
+	defer close(hung)
+	_, err := r.(*localRobot).Watchdog().UnaryServerInterceptor(ctx, &commonpb.DoCommandRequest{Name: "imu1"},
+		&grpc.UnaryServerInfo{FullMethod: "/viam.component.generic.v1.GenericService/DoCommand"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			<-hung
+			return nil, nil
+		})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+
+	// the resource is rebuilt by the next configuration attempt.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, constructed.Load(), test.ShouldEqual, 2)
+		_, err := r.ResourceByName(resource.NewName(doodadAPI, "imu1"))
+		test.That(tb, err, test.ShouldBeNil)
+	})
+}
+
+func TestConcurrentResourceConstruction(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	releaseSlow := make(chan struct{})
+	dependentBuilt := make(chan struct{})
+	gatedModel := resource.DefaultModelFamily.WithModel("gated")
+	resource.RegisterComponent(doodadAPI, gatedModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			switch conf.Name {
+			case "slow":
+				<-releaseSlow
+			case "dependent":
+				close(dependentBuilt)
+			}
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, gatedModel)
+	}()
+
+	// the dependent resource should be built as soon as its own dependency is,
+	// without waiting for the unrelated slow resource.
+	go func() {
+		select {
+		case <-dependentBuilt:
+		case <-time.After(10 * time.Second):
+			t.Error("dependent resource was not built while an unrelated resource was being built")
+		}
+		close(releaseSlow)
+	}()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "slow", API: doodadAPI, Model: gatedModel},
+			{Name: "base", API: doodadAPI, Model: gatedModel},
+			{Name: "dependent", API: doodadAPI, Model: gatedModel, DependsOn: []string{"base"}},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	for _, name := range []string{"slow", "base", "dependent"} {
+		_, err := r.ResourceByName(resource.NewName(doodadAPI, name))
+		test.That(t, err, test.ShouldBeNil)
+	}
+}
+
+func TestSelfTest(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "arm1",
+				API:                 arm.API,
+				Model:               resource.DefaultModelFamily.WithModel("fake"),
+				ConvertedAttributes: &fake.Config{ModelFilePath: "../../components/arm/fake/fake_model.json"},
+			},
+			{
+				Name:  "unbuilt",
+				API:   doodadAPI,
+				Model: resource.DefaultModelFamily.WithModel("unregistered"),
+			},
+		},
+		SelfTest: &config.SelfTestConfig{Timeout: "5s"},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	// the self-test runs once the robot is first configured.
+	report, err := r.(*localRobot).SelfTester().LastReport(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report, test.ShouldNotBeNil)
+	test.That(t, report.Passed, test.ShouldBeFalse)
+	test.That(t, report.Results, test.ShouldHaveLength, 2)
+	armResult, ok := report.Result(arm.Named("arm1"))
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, armResult.Passed, test.ShouldBeTrue)
+	test.That(t, armResult.Skipped, test.ShouldBeFalse)
+	unbuiltResult, ok := report.Result(resource.NewName(doodadAPI, "unbuilt"))
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, unbuiltResult.Passed, test.ShouldBeFalse)
+
+	// the status service reports the outcome of each component.
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	statuses, err := robotClient.Status(ctx, []resource.Name{arm.Named("arm1")})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldHaveLength, 1)
+	test.That(t, statuses[0].SelfTest, test.ShouldNotBeNil)
+	test.That(t, statuses[0].SelfTest.Passed, test.ShouldBeTrue)
+
+	// operators can run it again on demand.
+	rerun, err := robotClient.SelfTest().Run(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, rerun.Passed, test.ShouldBeFalse)
+	test.That(t, rerun.Time.After(report.Time), test.ShouldBeTrue)
+}
+
+func TestRestartResource(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	constructed := map[string]int{}
+	countingModel := resource.DefaultModelFamily.WithModel("restartable")
+	resource.RegisterComponent(doodadAPI, countingModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			mu.Lock()
+			constructed[conf.Name]++
+			mu.Unlock()
+			return &hooked{Named: conf.ResourceName().AsNamed(), mu: &sync.Mutex{}, events: &[]string{}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, countingModel)
+	}()
+	counts := func() map[string]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return maps.Clone(constructed)
+	}
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "controller", API: doodadAPI, Model: countingModel},
+			{Name: "wheel", API: doodadAPI, Model: countingModel, DependsOn: []string{"controller"}},
+			{Name: "other", API: doodadAPI, Model: countingModel},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	test.That(t, counts(), test.ShouldResemble, map[string]int{"controller": 1, "wheel": 1, "other": 1})
+	before, err := r.ResourceByName(resource.NewName(doodadAPI, "controller"))
+	test.That(t, err, test.ShouldBeNil)
+
+	// the resource and the resources depending on it are rebuilt, and nothing else is.
+	test.That(t, r.(*localRobot).RestartResource(ctx, resource.NewName(doodadAPI, "controller")), test.ShouldBeNil)
+	test.That(t, counts(), test.ShouldResemble, map[string]int{"controller": 2, "wheel": 2, "other": 1})
+	after, err := r.ResourceByName(resource.NewName(doodadAPI, "controller"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, after, test.ShouldNotEqual, before)
+
+	err = r.(*localRobot).RestartResource(ctx, resource.NewName(doodadAPI, "missing"))
+	test.That(t, resource.IsNotFoundError(err), test.ShouldBeTrue)
+	err = r.(*localRobot).RestartResource(ctx, web.InternalServiceName)
+	test.That(t, resource.IsNotFoundError(err), test.ShouldBeTrue)
+}
+
+type parkable struct {
+	*hooked
+}
+
+func (p *parkable) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	if _, ok := cmd["park"]; ok {
+		p.record("park")
+	}
+	return nil, nil
+}
+
+func TestShutdownOrder(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	var (
+		mu     sync.Mutex
+		events []string
+	)
+	parkableModel := resource.DefaultModelFamily.WithModel("parkable")
+	resource.RegisterComponent(doodadAPI, parkableModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			return &parkable{&hooked{Named: conf.ResourceName().AsNamed(), mu: &mu, events: &events}}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(doodadAPI, parkableModel)
+	}()
+
+	// the gripper is mounted on the arm, which is powered by the board without depending on it.
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:  "gripper",
+				API:   doodadAPI,
+				Model: parkableModel,
+				Frame: &referenceframe.LinkConfig{Parent: "arm"},
+			},
+			{Name: "board", API: doodadAPI, Model: parkableModel},
+			{
+				Name:     "arm",
+				API:      doodadAPI,
+				Model:    parkableModel,
+				Frame:    &referenceframe.LinkConfig{Parent: referenceframe.World},
+				Shutdown: &resource.ShutdownConfig{Before: []string{"board"}, Action: map[string]interface{}{"park": true}},
+			},
+		},
+	}
+	r, err := New(ctx, cfg, logger, WithViamHomeDir(t.TempDir()))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, r.Close(ctx), test.ShouldBeNil)
+
+	mu.Lock()
+	defer mu.Unlock()
+	var closing []string
+	for _, event := range events {
+		if !strings.HasSuffix(event, ".PreStart") && !strings.HasSuffix(event, ".PostStart") {
+			closing = append(closing, event)
+		}
+	}
+	test.That(t, closing, test.ShouldResemble, []string{
+		"gripper.PreClose", "gripper.Close",
+		"arm.park", "arm.PreClose", "arm.Close",
+		"board.PreClose", "board.Close",
+	})
+}
+
+func TestControlLoops(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	hold := config.ControlLoopConfig{
+		Name:      "hold",
+		Frequency: 50,
+		Motor:     "m1",
+		Encoder:   "e1",
+		Blocks: []control.BlockConfig{
+			{Name: "setpoint", Type: "constant", Attribute: rutils.AttributeMap{"constant_val": 10.0}},
+			{Name: "error", Type: "sum", Attribute: rutils.AttributeMap{"sum_string": "+-"}, DependsOn: []string{"setpoint", "m1"}},
+			{Name: "PID", Type: "PID", Attribute: rutils.AttributeMap{"kP": 0.1, "kI": 0.0, "kD": 0.0}, DependsOn: []string{"error"}},
+			{Name: "m1", Type: "endpoint", DependsOn: []string{"PID"}},
+		},
+	}
+	missing := hold
+	missing.Name = "missing"
+	missing.Motor = "m2"
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "m1", API: motor.API, Model: fakeModel, ConvertedAttributes: &fakemotor.Config{}},
+			{Name: "e1", API: encoder.API, Model: fakeModel, ConvertedAttributes: &fakeencoder.Config{}},
+		},
+		ControlLoops: []config.ControlLoopConfig{hold, missing},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		statuses := r.(*localRobot).ControlLoops().Status()
+		test.That(tb, statuses, test.ShouldHaveLength, 2)
+		test.That(tb, statuses[0].Name, test.ShouldEqual, "hold")
+		test.That(tb, statuses[0].Running, test.ShouldBeTrue)
+		test.That(tb, statuses[0].Stats.Ticks, test.ShouldBeGreaterThan, 0)
+		test.That(tb, statuses[1].Name, test.ShouldEqual, "missing")
+		test.That(tb, statuses[1].Running, test.ShouldBeFalse)
+		test.That(tb, statuses[1].Error, test.ShouldContainSubstring, "m2")
+	})
+	m1, err := motor.FromRobot(r, "m1")
+	test.That(t, err, test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		on, _, err := m1.IsPowered(ctx, nil)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, on, test.ShouldBeTrue)
+	})
+
+	// removing the loop stops it along with the motor it drives.
+	newCfg := *cfg
+	newCfg.ControlLoops = nil
+	r.Reconfigure(ctx, &newCfg)
+	test.That(t, r.(*localRobot).ControlLoops().Status(), test.ShouldBeEmpty)
+	on, _, err := m1.IsPowered(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, on, test.ShouldBeFalse)
+}
+
+func TestNewWithOptions(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "arm1", API: arm.API, Model: resource.DefaultModelFamily.WithModel("fake")},
+		},
+	}
+
+	var mu sync.Mutex
+	var snapshots []resource.Snapshot
+	viewed := func() []resource.Snapshot {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]resource.Snapshot(nil), snapshots...)
+	}
+	r, err := NewWithOptions(ctx, cfg,
+		WithLogger(logger),
+		WithViamHomeDir(t.TempDir()),
+		WithUntrustedEnv(),
+		WithViewer(func(snapshot resource.Snapshot) {
+			mu.Lock()
+			defer mu.Unlock()
+			snapshots = append(snapshots, snapshot)
+		}),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+	test.That(t, r.Logger(), test.ShouldEqual, logger)
+	test.That(t, r.(*localRobot).manager.opts.untrustedEnv, test.ShouldBeTrue)
+	test.That(t, r.(*localRobot).manager.opts.debug, test.ShouldBeFalse)
+
+	seen := viewed()
+	test.That(t, seen, test.ShouldNotBeEmpty)
+	test.That(t, seen[len(seen)-1].Dot, test.ShouldContainSubstring, "arm1")
+
+	// only changes to the resource graph are shown to the viewer.
+	r.Reconfigure(ctx, cfg)
+	test.That(t, viewed(), test.ShouldHaveLength, len(seen))
+	r.Reconfigure(ctx, &config.Config{})
+	latest := viewed()
+	test.That(t, len(latest), test.ShouldBeGreaterThan, len(seen))
+	test.That(t, latest[len(latest)-1].Dot, test.ShouldNotContainSubstring, "arm1")
+}
+
+func TestCoordinationRemotes(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	remoteRobot := setupLocalRobot(t, ctx, &config.Config{}, logger.Sublogger("remote"))
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, remoteRobot.StartWeb(ctx, options), test.ShouldBeNil)
+	r := setupLocalRobot(t, ctx, &config.Config{Remotes: []config.Remote{{Name: "bar", Address: addr}}}, logger)
+
+	// locks of a remote are held by the remote, which serializes on them with its parent.
+	lease, err := r.(*localRobot).Coordination().Acquire(ctx, "bar:workspace", "parent", time.Minute)
+	test.That(t, err, test.ShouldBeNil)
+	leases, err := remoteRobot.(*localRobot).Coordination().Leases(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, leases, test.ShouldHaveLength, 1)
+	test.That(t, leases[0].ID, test.ShouldEqual, lease.ID)
+	timeoutCtx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	_, err = remoteRobot.(*localRobot).Coordination().Acquire(timeoutCtx, "workspace", "remote", time.Minute)
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+	test.That(t, r.(*localRobot).Coordination().Release(ctx, "bar:workspace", lease.ID), test.ShouldBeNil)
+	_, err = remoteRobot.(*localRobot).Coordination().Acquire(ctx, "workspace", "remote", time.Minute)
+	test.That(t, err, test.ShouldBeNil)
+}
+
+// stuck is a generic component whose commands run until canceled.
+type stuck struct {
+	resource.Named
+	resource.TriviallyReconfigurable
+	resource.TriviallyCloseable
+	started chan struct{}
+}
+
+func (s *stuck) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+	s.started <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestOperations(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	started := make(chan struct{}, 1)
+	stuckModel := resource.DefaultModelFamily.WithModel("stuck")
+	resource.RegisterComponent(generic.API, stuckModel, resource.Registration[resource.Resource, resource.NoNativeConfig]{
+		Constructor: func(
+			ctx context.Context,
+			deps resource.Dependencies,
+			conf resource.Config,
+			logger logging.Logger,
+		) (resource.Resource, error) {
+			return &stuck{Named: conf.ResourceName().AsNamed(), started: started}, nil
+		},
+	})
+	defer func() {
+		resource.Deregister(generic.API, stuckModel)
+	}()
+
+	r := setupLocalRobot(t, ctx, &config.Config{
+		Components: []resource.Config{{Name: "stuck1", API: generic.API, Model: stuckModel}},
+	}, logger)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	res, err := generic.FromRobot(robotClient, "stuck1")
+	test.That(t, err, test.ShouldBeNil)
+	done := make(chan error, 1)
+	go func() {
+		_, err := res.DoCommand(ctx, map[string]interface{}{"move": true})
+		done <- err
+	}()
+	<-started
+
+	// the stuck command is listed with the resource it is for and who called it.
+	ops, err := robotClient.Operations().ListOperations(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, ops, test.ShouldHaveLength, 1)
+	test.That(t, ops[0].Method, test.ShouldEqual, "/viam.component.generic.v1.GenericService/DoCommand")
+	test.That(t, ops[0].Resource, test.ShouldEqual, "stuck1")
+	test.That(t, ops[0].Address, test.ShouldNotBeEmpty)
+
+	// and canceled remotely.
+	test.That(t, robotClient.Operations().CancelOperation(ctx, ops[0].ID), test.ShouldBeNil)
+	err = <-done
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Canceled)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		ops, err := robotClient.Operations().ListOperations(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, ops, test.ShouldBeEmpty)
+	})
+	err = robotClient.Operations().CancelOperation(ctx, ops[0].ID)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+}
+
+func TestResourceLabels(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	fakeModel := resource.DefaultModelFamily.WithModel("fake")
+	remoteRobot := setupLocalRobot(t, ctx, &config.Config{
+		Components: []resource.Config{{
+			Name: "arm1", API: arm.API, Model: fakeModel, ConvertedAttributes: &fake.Config{},
+			Labels: map[string]string{"side": "left"},
+		}},
+	}, logger.Sublogger("remote"))
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, remoteRobot.StartWeb(ctx, options), test.ShouldBeNil)
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name: "left_arm", API: arm.API, Model: fakeModel, ConvertedAttributes: &fake.Config{},
+				Labels: map[string]string{"side": "left", "zone": "cell-1"},
+			},
+			{
+				Name: "right_arm", API: arm.API, Model: fakeModel, ConvertedAttributes: &fake.Config{},
+				Labels: map[string]string{"side": "right", "zone": "cell-1"},
+			},
+		},
+		Remotes: []config.Remote{{Name: "bar", Address: addr}},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	labeledRobot, ok := r.(metadata.Robot)
+	test.That(t, ok, test.ShouldBeTrue)
+
+	// resources of remotes are selected by the labels they have on their robot.
+	names, err := metadata.ResourceNamesByLabel(labeledRobot, "side=left")
+	test.That(t, err, test.ShouldBeNil)
+	rtestutils.VerifySameResourceNames(t, names, []resource.Name{arm.Named("left_arm"), arm.Named("bar:arm1")})
+	names, err = metadata.ResourceNamesByLabel(labeledRobot, "zone=cell-1,side!=left")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []resource.Name{arm.Named("right_arm")})
+	_, err = metadata.ResourceNamesByLabel(labeledRobot, "=left")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// labels are served to clients.
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	names, err = metadata.ResourceNamesByLabel(robotClient, "side")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []resource.Name{arm.Named("arm1")})
+	names, err = robotClient.Metadata().ResourceNames(ctx, "side=right")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldBeEmpty)
+	labels, err := robotClient.Metadata().ResourceLabels(ctx, "side=left")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, labels, test.ShouldResemble, map[resource.Name]map[string]string{arm.Named("arm1"): {"side": "left"}})
+
+	// relabeling a resource takes effect on reconfigure.
+	newCfg := &config.Config{Components: slices.Clone(cfg.Components), Remotes: cfg.Remotes}
+	newCfg.Components[1].Labels = map[string]string{"side": "left"}
+	r.Reconfigure(ctx, newCfg)
+	names, err = metadata.ResourceNamesByLabel(labeledRobot, "side=left,!zone")
+	test.That(t, err, test.ShouldBeNil)
+	rtestutils.VerifySameResourceNames(t, names, []resource.Name{arm.Named("right_arm"), arm.Named("bar:arm1")})
+}
+
+func TestRemoteDiscovery(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	network := lantestutils.NewFakeNetwork()
+	newRobot := func(cfg *config.Config, logger logging.Logger) robot.LocalRobot {
+		r, err := New(ctx, cfg, logger, WithViamHomeDir(t.TempDir()), WithLANNetwork(network))
+		test.That(t, err, test.ShouldBeNil)
+		t.Cleanup(func() {
+			test.That(t, r.Close(ctx), test.ShouldBeNil)
+		})
+		return r
+	}
+
+	fakeModel := resource.DefaultModelFamily.WithModel("fake")
+	remoteRobot := newRobot(&config.Config{
+		Components:      []resource.Config{{Name: "arm1", API: arm.API, Model: fakeModel, ConvertedAttributes: &fake.Config{}}},
+		RemoteDiscovery: &config.RemoteDiscoveryConfig{Name: "cell-1", Interval: "10ms"},
+	}, logger.Sublogger("remote"))
+	options, _, _ := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, remoteRobot.StartWeb(ctx, options), test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, network.Advertised(), test.ShouldResemble, []string{"cell-1"})
+	})
+
+	// robots are discovered without being added unless configured.
+	r := newRobot(&config.Config{}, logger)
+	candidates, err := r.(*localRobot).DiscoverRemotes(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, candidates, test.ShouldHaveLength, 1)
+	test.That(t, candidates[0].Name, test.ShouldEqual, "cell-1")
+	test.That(t, r.RemoteNames(), test.ShouldBeEmpty)
+
+	// discovered robots matching auto_add are added as remotes and kept across reconfigures.
+	cfg := &config.Config{
+		RemoteDiscovery: &config.RemoteDiscoveryConfig{Name: "main", AutoAdd: []string{"cell-*"}, Interval: "10ms"},
+	}
+	r.Reconfigure(ctx, cfg)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		_, err := arm.FromRobot(r, "cell-1:arm1")
+		test.That(tb, err, test.ShouldBeNil)
+	})
+	test.That(t, r.RemoteNames(), test.ShouldResemble, []string{"cell-1"})
+	test.That(t, network.Advertised(), test.ShouldResemble, []string{"cell-1"})
+
+	r.Reconfigure(ctx, &config.Config{RemoteDiscovery: cfg.RemoteDiscovery, Debug: true})
+	test.That(t, r.RemoteNames(), test.ShouldResemble, []string{"cell-1"})
+
+	// disabling discovery removes the remotes it added.
+	r.Reconfigure(ctx, &config.Config{})
+	test.That(t, r.RemoteNames(), test.ShouldBeEmpty)
+}
+
+func TestReadOnly(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	fakeModel := resource.DefaultModelFamily.WithModel("fake")
+	cfg := &config.Config{
+		ReadOnly: true,
+		Components: []resource.Config{
+			{Name: "arm1", API: arm.API, Model: fakeModel, ConvertedAttributes: &fake.Config{}},
+			{Name: "m1", API: motor.API, Model: fakeModel, ConvertedAttributes: &fakemotor.Config{}},
+			{Name: "gripper1", API: gripper.API, Model: fakeModel, ConvertedAttributes: &fakegripper.Config{}},
+			{Name: "board1", API: board.API, Model: fakeModel, ConvertedAttributes: &fakeboard.Config{}},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	// actuation is rejected by the robot's API, with its own status code.
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	a, err := arm.FromRobot(robotClient, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	positions, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	err = a.MoveToJointPositions(ctx, positions, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "read-only mode")
+	_, err = a.DoCommand(ctx, map[string]interface{}{"move": true})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+
+	m1, err := motor.FromRobot(robotClient, "m1")
+	test.That(t, err, test.ShouldBeNil)
+	err = m1.SetPower(ctx, 0.5, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	powered, _, err := m1.IsPowered(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, powered, test.ShouldBeFalse)
+
+	g, err := gripper.FromRobot(robotClient, "gripper1")
+	test.That(t, err, test.ShouldBeNil)
+	pg, ok := g.(gripper.PositionGripper)
+	test.That(t, ok, test.ShouldBeTrue)
+	_, err = pg.Width(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	err = pg.MoveToWidth(ctx, 10, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	_, err = g.Grab(ctx, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+
+	b, err := board.FromRobot(robotClient, "board1")
+	test.That(t, err, test.ShouldBeNil)
+	pin, err := b.GPIOPinByName("1")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = pin.Get(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = pin.PWM(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	err = pin.Set(ctx, true, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	err = pin.SetPWM(ctx, 0.5, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+	err = pin.SetPWMFreq(ctx, 100, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.PermissionDenied)
+
+	// a robot that is not read-only is actuated normally.
+	r = setupLocalRobot(t, ctx, &config.Config{Components: cfg.Components}, logger)
+	options, _, addr = robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+	writableClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, writableClient.Close(ctx), test.ShouldBeNil)
+	}()
+	a, err = arm.FromRobot(writableClient, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, a.MoveToJointPositions(ctx, positions, nil), test.ShouldBeNil)
+	b, err = board.FromRobot(writableClient, "board1")
+	test.That(t, err, test.ShouldBeNil)
+	pin, err = b.GPIOPinByName("1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pin.Set(ctx, true, nil), test.ShouldBeNil)
+}
+
+func TestMotionLimits(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	fakeModel := resource.DefaultModelFamily.WithModel("fake")
+	baseConf := resource.Config{
+		Name: "base1", API: base.API, Model: fakeModel, ConvertedAttributes: &fakebase.Config{},
+		Limits: &resource.MotionLimits{MaxLinearSpeedMmPerSec: 300},
+	}
+	r := setupLocalRobot(t, ctx, &config.Config{Components: []resource.Config{baseConf}}, logger)
+	subscription, err := r.(*localRobot).Events().Subscribe(ctx, events.LimitViolated)
+	test.That(t, err, test.ShouldBeNil)
+
+	b, err := base.FromRobot(r, "base1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 200}, r3.Vector{}, nil), test.ShouldBeNil)
+	err = b.SetVelocity(ctx, r3.Vector{Y: 400}, r3.Vector{}, nil)
+	test.That(t, resource.IsLimitViolationError(err), test.ShouldBeTrue)
+	select {
+	case ev := <-subscription:
+		test.That(t, ev.Name, test.ShouldResemble, base.Named("base1"))
+		test.That(t, ev.Error, test.ShouldEqual, err.Error())
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+
+	// requests are limited over the network too, with their own status code.
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, r.StartWeb(ctx, options), test.ShouldBeNil)
+	robotClient, err := client.New(ctx, addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	b, err = base.FromRobot(robotClient, "base1")
+	test.That(t, err, test.ShouldBeNil)
+	err = b.MoveStraight(ctx, 100, 500, nil)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.OutOfRange)
+
+	// raising the limit applies to the next request.
+	baseConf.Limits = &resource.MotionLimits{MaxLinearSpeedMmPerSec: 600}
+	r.Reconfigure(ctx, &config.Config{Components: []resource.Config{baseConf}})
+	test.That(t, b.MoveStraight(ctx, 100, 500, nil), test.ShouldBeNil)
+}
+
+func TestSimulation(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := &config.Config{
+		Simulation: true,
+		Components: []resource.Config{
+			{
+				Name:  "arm1",
+				API:   arm.API,
+				Model: ur.Model,
+				Frame: &referenceframe.LinkConfig{Parent: referenceframe.World},
+				// no UR arm listens here; the simulated arm never connects.
+				ConvertedAttributes: &ur.Config{Host: "127.0.0.1", SpeedDegsPerSec: 30},
+			},
+			{
+				Name:  "board1",
+				API:   board.API,
+				Model: resource.DefaultModelFamily.WithModel("jetson"),
+				Attributes: rutils.AttributeMap{
+					"digital_interrupts": []interface{}{map[string]interface{}{"name": "encoder", "pin": "15"}},
+				},
+				ConvertedAttributes: &genericlinux.Config{
+					DigitalInterrupts: []board.DigitalInterruptConfig{{Name: "encoder", Pin: "15"}},
+				},
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	// the simulated arm has the kinematics and frame of the real one.
+	a, err := arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, a.ModelFrame().DoF(), test.ShouldHaveLength, 6)
+	positions, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	positions.Values[0] = 10
+	test.That(t, a.MoveToJointPositions(ctx, positions, nil), test.ShouldBeNil)
+	moved, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moved.Values[0], test.ShouldAlmostEqual, 10)
+
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Parts, test.ShouldHaveLength, 1)
+	test.That(t, fsCfg.Parts[0].FrameConfig.Name(), test.ShouldEqual, "arm1")
+	test.That(t, fsCfg.Parts[0].FrameConfig.Parent(), test.ShouldEqual, referenceframe.World)
+
+	// the simulated board has the digital interrupts of the real one.
+	b, err := board.FromRobot(r, "board1")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = b.DigitalInterruptByName("encoder")
+	test.That(t, err, test.ShouldBeNil)
+	pin, err := b.GPIOPinByName("37")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pin.Set(ctx, true, nil), test.ShouldBeNil)
+
+	// reconfiguring keeps simulating the arm.
+	newCfg := &config.Config{Simulation: true, Components: cfg.Components}
+	newCfg.Components[0].ConvertedAttributes = &ur.Config{Host: "127.0.0.2", SpeedDegsPerSec: 30}
+	r.Reconfigure(ctx, newCfg)
+	a, err = arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+}
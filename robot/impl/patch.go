@@ -0,0 +1,73 @@
+package robotimpl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+)
+
+// PatchConfig applies the patch to the robot's current config and reconfigures the robot
+// with the result, returning the robot's config once reconfigured. The patch is rejected,
+// leaving the robot as it is, if it does not apply to the current config or if the patched
+// config has errors that the current config does not, as found by Validate.
+func (r *localRobot) PatchConfig(ctx context.Context, patch config.Patch) (*config.Config, error) {
+	r.patchMu.Lock()
+	defer r.patchMu.Unlock()
+
+	current := r.Config()
+	patched, err := current.ApplyPatch(patch)
+	if err != nil {
+		return nil, err
+	}
+	processPatchedResources(patched.Components, "components", resource.APITypeComponentName)
+	processPatchedResources(patched.Services, "services", resource.APITypeServiceName)
+
+	known := map[string]bool{}
+	for _, issue := range Validate(ctx, current).Errors() {
+		known[issue.Resource+": "+issue.Message] = true
+	}
+	var errs error
+	for _, issue := range Validate(ctx, patched).Errors() {
+		if !known[issue.Resource+": "+issue.Message] {
+			errs = multierr.Combine(errs, errors.Errorf("%s: %s", issue.Path, issue.Message))
+		}
+	}
+	if errs != nil {
+		return nil, errors.Wrap(errs, "patched config is invalid")
+	}
+
+	if data, err := json.Marshal(patch); err == nil {
+		r.logger.CInfow(ctx, "applying config patch", "patch", string(data))
+	}
+	r.Reconfigure(ctx, patched)
+	return r.Config(), nil
+}
+
+// processPatchedResources converts the attributes of the resources changed by a patch and
+// finds their implicit dependencies, as is done for the resources of a config read. Errors
+// are left to be found by Validate.
+func processPatchedResources(confs []resource.Config, field, apiType string) {
+	for idx := range confs {
+		conf := &confs[idx]
+		if conf.ConvertedAttributes != nil {
+			continue
+		}
+		reg, ok := resource.LookupRegistration(conf.API, conf.Model)
+		if ok && reg.AttributeMapConverter != nil {
+			converted, err := reg.AttributeMapConverter(conf.Attributes)
+			if err != nil {
+				continue
+			}
+			conf.ConvertedAttributes = converted
+		}
+		if deps, err := conf.Validate(fmt.Sprintf("%s.%d", field, idx), apiType); err == nil {
+			conf.ImplicitDependsOn = deps
+		}
+	}
+}
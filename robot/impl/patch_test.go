@@ -0,0 +1,80 @@
+package robotimpl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/board"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/utils"
+)
+
+func TestPatchConfig(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{Name: "motor1", API: motor.API, Model: resource.DefaultModelFamily.WithModel("fake")},
+			{Name: "motor2", API: motor.API, Model: resource.DefaultModelFamily.WithModel("fake")},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	lr := r.(*localRobot)
+	parsePatch := func(raw string) config.Patch {
+		var patch config.Patch
+		test.That(t, json.Unmarshal([]byte(raw), &patch), test.ShouldBeNil)
+		return patch
+	}
+
+	patched, err := lr.PatchConfig(ctx, parsePatch(`{
+		"components": {
+			"remove": ["motor2"],
+			"attributes": {"motor1": {"max_rpm": 100}},
+			"add": [{"name": "board1", "type": "board", "model": "fake"}]
+		}
+	}`))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, patched.FindComponent("motor2"), test.ShouldBeNil)
+	test.That(t, patched.FindComponent("board1"), test.ShouldNotBeNil)
+	test.That(t, patched.FindComponent("motor1").Attributes, test.ShouldResemble, utils.AttributeMap{"max_rpm": 100.0})
+	_, err = r.ResourceByName(board.Named("board1"))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = r.ResourceByName(motor.Named("motor2"))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// patches that do not apply or make the config invalid leave the robot as it is.
+	for _, raw := range []string{
+		`{"components": {"remove": ["motor2"]}}`,
+		`{"components": {"add": [{"name": "motor3", "type": "motor", "model": "fake", "depends_on": ["nope"]}]}}`,
+		`{"components": {"attributes": {"motor1": {"max_rpm": "fast"}}}}`,
+	} {
+		_, err = lr.PatchConfig(ctx, parsePatch(raw))
+		test.That(t, err, test.ShouldNotBeNil)
+	}
+	test.That(t, r.Config().FindComponent("motor3"), test.ShouldBeNil)
+	test.That(t, r.Config().FindComponent("motor1").Attributes, test.ShouldResemble, utils.AttributeMap{"max_rpm": 100.0})
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	patched, err = robotClient.ConfigPatcher().PatchConfig(ctx, parsePatch(`{"components": {"remove": ["board1"]}}`))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, patched.FindComponent("board1"), test.ShouldBeNil)
+	test.That(t, patched.FindComponent("motor1"), test.ShouldNotBeNil)
+	_, err = r.ResourceByName(board.Named("board1"))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	_, err = robotClient.ConfigPatcher().PatchConfig(ctx, parsePatch(`{"components": {"remove": ["board1"]}}`))
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
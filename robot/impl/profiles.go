@@ -0,0 +1,109 @@
+package robotimpl
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/profiles"
+)
+
+// profileState is the profile a robot is switched to.
+type profileState struct {
+	mu sync.Mutex
+	// configured is the profile of the last config the robot was reconfigured with, and active
+	// the profile the robot is switched to.
+	configured string
+	active     string
+	// base is the last config the robot was reconfigured with, before its profile was applied.
+	base *config.Config
+	// cpuGovernor is the CPU frequency governor last set by a profile.
+	cpuGovernor string
+}
+
+// setCPUGovernor sets the CPU frequency governor, and can be replaced by tests.
+var setCPUGovernor = profiles.SetCPUGovernor
+
+// applyProfile returns the config with the attributes set by the profile the robot is
+// switched to, switching to the profile of the config if it changed since the last config.
+func (r *localRobot) applyProfile(ctx context.Context, cfg *config.Config) *config.Config {
+	r.profile.mu.Lock()
+	defer r.profile.mu.Unlock()
+
+	base := *cfg
+	r.profile.base = &base
+	if cfg.Profile != r.profile.configured {
+		r.profile.configured = cfg.Profile
+		r.profile.active = cfg.Profile
+	}
+	if r.profile.active == "" {
+		return cfg
+	}
+
+	profile, ok := cfg.FindProfile(r.profile.active)
+	if !ok {
+		r.logger.CWarnw(ctx, "profile is no longer configured; switching to the profile of the config",
+			"profile", r.profile.active, "config profile", cfg.Profile)
+		r.profile.active = cfg.Profile
+		if profile, ok = cfg.FindProfile(cfg.Profile); !ok {
+			return cfg
+		}
+	}
+	profiled, err := cfg.WithProfile(profile.Name)
+	if err != nil {
+		r.logger.CErrorw(ctx, "failed to apply profile", "profile", profile.Name, "error", err)
+		return cfg
+	}
+	processPatchedResources(profiled.Components, "components", resource.APITypeComponentName)
+	processPatchedResources(profiled.Services, "services", resource.APITypeServiceName)
+
+	if profile.CPUGovernor != "" && profile.CPUGovernor != r.profile.cpuGovernor {
+		if err := setCPUGovernor(profile.CPUGovernor); err != nil {
+			r.logger.CErrorw(ctx, "failed to set the CPU frequency governor of profile", "profile", profile.Name, "error", err)
+		} else {
+			r.profile.cpuGovernor = profile.CPUGovernor
+		}
+	}
+	return profiled
+}
+
+// Profiles returns the profile the robot is switched to and the profiles of its config.
+func (r *localRobot) Profiles(ctx context.Context) (*profiles.Status, error) {
+	r.profile.mu.Lock()
+	defer r.profile.mu.Unlock()
+	st := &profiles.Status{Active: r.profile.active, Profiles: []string{}}
+	if r.profile.base != nil {
+		for _, profile := range r.profile.base.Profiles {
+			st.Profiles = append(st.Profiles, profile.Name)
+		}
+	}
+	return st, nil
+}
+
+// SetProfile switches the robot to the named profile of its config, or to none if the name is
+// empty, and reconfigures the robot with the last config it received with the profile applied.
+func (r *localRobot) SetProfile(ctx context.Context, name string) (*profiles.Status, error) {
+	r.patchMu.Lock()
+	defer r.patchMu.Unlock()
+
+	r.profile.mu.Lock()
+	base := r.profile.base
+	if base == nil {
+		r.profile.mu.Unlock()
+		return nil, errors.New("robot is not configured yet")
+	}
+	if _, ok := base.FindProfile(name); name != "" && !ok {
+		r.profile.mu.Unlock()
+		return nil, errors.Errorf("no profile named %q", name)
+	}
+	previous := r.profile.active
+	r.profile.active = name
+	r.profile.mu.Unlock()
+
+	r.logger.CInfow(ctx, "switching profile", "from", previous, "to", name)
+	r.Reconfigure(ctx, base)
+	return r.Profiles(ctx)
+}
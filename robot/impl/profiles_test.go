@@ -0,0 +1,106 @@
+package robotimpl
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/motor"
+	fakemotor "go.viam.com/rdk/components/motor/fake"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/profiles"
+	"go.viam.com/rdk/utils"
+)
+
+func TestProfiles(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	var governors []string
+	prevSetCPUGovernor := setCPUGovernor
+	setCPUGovernor = func(governor string) error {
+		governors = append(governors, governor)
+		return nil
+	}
+	defer func() {
+		setCPUGovernor = prevSetCPUGovernor
+	}()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "motor1",
+				API:                 motor.API,
+				Model:               resource.DefaultModelFamily.WithModel("fake"),
+				Attributes:          utils.AttributeMap{"max_rpm": 100},
+				ConvertedAttributes: &fakemotor.Config{MaxRPM: 100},
+			},
+		},
+		Profiles: []config.ProfileConfig{
+			{
+				Name:        "patrol",
+				Components:  map[string]utils.AttributeMap{"motor1": {"max_rpm": 10}},
+				CPUGovernor: "powersave",
+			},
+			{Name: "inspect", CPUGovernor: "performance"},
+		},
+		Profile: "inspect",
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	lr := r.(*localRobot)
+	maxRPM := func() float64 {
+		m, err := motor.FromRobot(r, "motor1")
+		test.That(t, err, test.ShouldBeNil)
+		return m.(*fakemotor.Motor).MaxRPM
+	}
+	test.That(t, maxRPM(), test.ShouldEqual, 100)
+	test.That(t, governors, test.ShouldResemble, []string{"performance"})
+
+	st, err := lr.SetProfile(ctx, "patrol")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, st, test.ShouldResemble, &profiles.Status{Active: "patrol", Profiles: []string{"patrol", "inspect"}})
+	test.That(t, maxRPM(), test.ShouldEqual, 10)
+	test.That(t, governors, test.ShouldResemble, []string{"performance", "powersave"})
+
+	// the profile is kept across configs of the same profile.
+	r.Reconfigure(ctx, cfg)
+	test.That(t, maxRPM(), test.ShouldEqual, 10)
+
+	st, err = lr.SetProfile(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, st.Active, test.ShouldEqual, "")
+	test.That(t, maxRPM(), test.ShouldEqual, 100)
+
+	_, err = lr.SetProfile(ctx, "nope")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `no profile named "nope"`)
+
+	// changing the profile of the config switches to it.
+	patrolCfg := *cfg
+	patrolCfg.Profile = "patrol"
+	r.Reconfigure(ctx, &patrolCfg)
+	test.That(t, maxRPM(), test.ShouldEqual, 10)
+
+	robotClient, err := client.NewInProcess(ctx, r, logger, client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	st, err = robotClient.Profiles().Profiles(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, st.Active, test.ShouldEqual, "patrol")
+	st, err = robotClient.Profiles().SetProfile(ctx, "inspect")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, st.Active, test.ShouldEqual, "inspect")
+	test.That(t, maxRPM(), test.ShouldEqual, 100)
+	test.That(t, governors, test.ShouldResemble, []string{"performance", "powersave", "performance"})
+
+	_, err = robotClient.Profiles().SetProfile(ctx, "nope")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+}
@@ -0,0 +1,143 @@
+package robotimpl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	pb "go.viam.com/api/robot/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// remoteMetadataNamespace is the namespace of the robot's kv store that holds the metadata
+// cached for remotes with CacheMetadata set, keyed by the name of the remote.
+const remoteMetadataNamespace = "remotes"
+
+// remoteMetadata is the metadata of a remote's resources last seen while it was connected.
+type remoteMetadata struct {
+	// Address is the address of the remote the metadata came from. Metadata cached for a
+	// different address than the remote's current one is not used.
+	Address          string            `json:"address"`
+	CachedAt         time.Time         `json:"cached_at"`
+	ResourceNames    []string          `json:"resource_names"`
+	FrameSystemParts []json.RawMessage `json:"frame_system_parts"`
+}
+
+// resourceNames returns the names of the remote's resources, relative to the remote.
+func (md *remoteMetadata) resourceNames() ([]resource.Name, error) {
+	names := make([]resource.Name, 0, len(md.ResourceNames))
+	for _, nameStr := range md.ResourceNames {
+		name, err := resource.NewFromString(nameStr)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// frameSystemParts returns the parts of the remote's frame system, as served by the remote.
+func (md *remoteMetadata) frameSystemParts() ([]*referenceframe.FrameSystemPart, error) {
+	parts := make([]*referenceframe.FrameSystemPart, 0, len(md.FrameSystemParts))
+	for _, data := range md.FrameSystemParts {
+		var partPb pb.FrameSystemConfig
+		if err := protojson.Unmarshal(data, &partPb); err != nil {
+			return nil, err
+		}
+		part, err := referenceframe.ProtobufToFrameSystemPart(&partPb)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, part)
+	}
+	return parts, nil
+}
+
+// newRemoteMetadata returns the current metadata of a connected remote's resources.
+func newRemoteMetadata(ctx context.Context, address string, remote robot.Robot) (remoteMetadata, error) {
+	md := remoteMetadata{Address: address, CachedAt: time.Now()}
+	for _, name := range remote.ResourceNames() {
+		md.ResourceNames = append(md.ResourceNames, name.String())
+	}
+	fsCfg, err := remote.FrameSystemConfig(ctx)
+	if err != nil {
+		return remoteMetadata{}, err
+	}
+	for _, part := range fsCfg.Parts {
+		partPb, err := part.ToProtobuf()
+		if err != nil {
+			return remoteMetadata{}, err
+		}
+		data, err := protojson.Marshal(partPb)
+		if err != nil {
+			return remoteMetadata{}, err
+		}
+		md.FrameSystemParts = append(md.FrameSystemParts, data)
+	}
+	return md, nil
+}
+
+// cacheRemoteMetadata persists the metadata of a connected remote's resources if its config
+// asks for it.
+func (r *localRobot) cacheRemoteMetadata(ctx context.Context, remoteCfg config.Remote, remote robot.Robot) {
+	if !remoteCfg.CacheMetadata {
+		return
+	}
+	md, err := newRemoteMetadata(ctx, remoteCfg.Address, remote)
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(md); err == nil {
+			err = r.kv.Set(ctx, remoteMetadataNamespace, remoteCfg.Name, data)
+		}
+	}
+	if err != nil {
+		r.logger.CWarnw(ctx, "failed to cache remote metadata", "remote", remoteCfg.Name, "error", err)
+	}
+}
+
+// cacheConnectedRemotesMetadata refreshes the cached metadata of every connected remote
+// whose config asks for it.
+func (r *localRobot) cacheConnectedRemotesMetadata(ctx context.Context) {
+	for _, remoteCfg := range r.Config().Remotes {
+		if !remoteCfg.CacheMetadata {
+			continue
+		}
+		remoteRobot, ok := r.RemoteByName(remoteCfg.Name)
+		if !ok {
+			continue
+		}
+		if remote, ok := remoteRobot.(robot.RemoteRobot); ok && remote.Connected() {
+			r.cacheRemoteMetadata(ctx, remoteCfg, remote)
+		}
+	}
+}
+
+// cachedRemoteMetadata returns the metadata last cached for a remote, if its config asks for
+// it to be cached and any was cached for its current address.
+func (r *localRobot) cachedRemoteMetadata(ctx context.Context, remoteCfg config.Remote) (*remoteMetadata, bool) {
+	if !remoteCfg.CacheMetadata {
+		return nil, false
+	}
+	data, ok, err := r.kv.Get(ctx, remoteMetadataNamespace, remoteCfg.Name)
+	if err != nil {
+		r.logger.CWarnw(ctx, "failed to read cached remote metadata", "remote", remoteCfg.Name, "error", err)
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+	var md remoteMetadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		r.logger.CWarnw(ctx, "failed to read cached remote metadata", "remote", remoteCfg.Name, "error", err)
+		return nil, false
+	}
+	if md.Address != remoteCfg.Address {
+		return nil, false
+	}
+	return &md, true
+}
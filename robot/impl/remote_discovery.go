@@ -0,0 +1,51 @@
+package robotimpl
+
+import (
+	"context"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/robot/lan"
+)
+
+// DiscoverRemotes browses the local network now and returns the robots advertised on it,
+// which are candidate remotes of the robot.
+func (r *localRobot) DiscoverRemotes(ctx context.Context) ([]lan.Candidate, error) {
+	return r.remoteDiscoverer.Discover(ctx)
+}
+
+// addDiscoveredRemotes adds the discovered robots that the config auto-adds as remotes and
+// that are not remotes yet, so that they are connected to by the next configuration attempt.
+func (r *localRobot) addDiscoveredRemotes() {
+	ctx := r.closeContext
+	if ctx.Err() != nil {
+		return
+	}
+	current := r.Config()
+	var remotes []config.Remote
+	for _, remote := range r.remoteDiscoverer.Remotes(current.Remotes) {
+		if _, err := remote.Validate("remotes"); err != nil {
+			r.logger.CWarnw(ctx, "cannot add discovered robot as remote", "name", remote.Name, "error", err)
+			continue
+		}
+		r.logger.CInfow(ctx, "adding discovered robot as remote", "name", remote.Name, "address", remote.Address)
+		remotes = append(remotes, remote)
+	}
+	if len(remotes) == 0 {
+		return
+	}
+	diff := config.Diff{
+		Left:     current,
+		Right:    current,
+		Added:    &config.Config{Remotes: remotes},
+		Modified: &config.ModifiedConfigDiff{},
+		Removed:  &config.Config{},
+	}
+	if err := r.manager.updateResources(ctx, &diff); err != nil {
+		r.logger.CWarnw(ctx, "errors encountered while adding discovered remotes", "error", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case r.triggerConfig <- struct{}{}:
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/jhump/protoreflect/desc"
@@ -16,7 +17,6 @@ import (
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/pexec"
 	"go.viam.com/utils/rpc"
-	"golang.org/x/sync/errgroup"
 
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
@@ -26,6 +26,8 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/failover"
+	"go.viam.com/rdk/robot/metrics"
 	"go.viam.com/rdk/robot/web"
 	"go.viam.com/rdk/services/shell"
 	rutils "go.viam.com/rdk/utils"
@@ -39,6 +41,7 @@ func init() {
 
 var (
 	resourceCloseTimeout    = 30 * time.Second
+	shutdownActionTimeout   = 30 * time.Second
 	errShellServiceDisabled = errors.New("shell service disabled in an untrusted environment")
 	errProcessesDisabled    = errors.New("processes disabled in an untrusted environment")
 )
@@ -53,6 +56,26 @@ type resourceManager struct {
 	logger         logging.Logger
 	configLock     sync.Mutex
 	viz            resource.Visualizer
+	failovers      *failover.Monitor
+
+	// maxRemoteDepth is the configured max remote depth, or zero for the default.
+	maxRemoteDepth atomic.Int64
+	// rejectedRemoteChains are the errors of the chains of remotes already reported as not merged.
+	rejectedRemoteChains sync.Map
+
+	limitersMu sync.Mutex
+	// limiters enforce the motion limits of the resources configured with them, across lookups.
+	limiters map[resource.Name]*resource.MotionLimiter
+
+	kinematicsMu sync.Mutex
+	// kinematics give the kinematic models of the resources configured with them, across
+	// lookups.
+	kinematics map[resource.Name]*resource.Kinematics
+
+	quiescedMu sync.Mutex
+	// quiesced are the resources left closed until they are resumed, such as those backed
+	// by a microcontroller being flashed.
+	quiesced map[resource.Name]struct{}
 }
 
 type resourceManagerOptions struct {
@@ -60,7 +83,14 @@ type resourceManagerOptions struct {
 	fromCommand        bool
 	allowInsecureCreds bool
 	untrustedEnv       bool
+	readOnly           bool
+	simulation         bool
 	tlsConfig          *tls.Config
+	viewer             func(resource.Snapshot)
+	// limitViolated is called with every request refused for violating a motion limit.
+	limitViolated func(resource.Name, error)
+	// metrics exposes the usage of the processes of modules.
+	metrics *metrics.Recorder
 }
 
 // newResourceManager returns a properly initialized set of parts.
@@ -75,6 +105,10 @@ func newResourceManager(
 		processConfigs: make(map[string]pexec.ProcessConfig),
 		opts:           opts,
 		logger:         logger,
+		failovers:      failover.NewMonitor(logger.Sublogger("failover")),
+		limiters:       map[resource.Name]*resource.MotionLimiter{},
+		kinematics:     map[resource.Name]*resource.Kinematics{},
+		quiesced:       map[resource.Name]struct{}{},
 	}
 }
 
@@ -88,6 +122,22 @@ func newProcessManager(
 	return pexec.NewProcessManager(logger.AsZap())
 }
 
+// saveSnapshot saves a snapshot of the resource graph and shows it to the viewer if it
+// changed. It must be called with the config lock held.
+func (manager *resourceManager) saveSnapshot() {
+	latest, _ := manager.viz.GetSnapshot(0)
+	if err := manager.viz.SaveSnapshot(manager.resources); err != nil {
+		manager.logger.Warnw("failed to save graph snapshot", "error", err)
+		return
+	}
+	if manager.opts.viewer == nil {
+		return
+	}
+	if saved, err := manager.viz.GetSnapshot(0); err == nil && !saved.Snapshot.CreatedAt.Equal(latest.Snapshot.CreatedAt) {
+		manager.opts.viewer(saved.Snapshot)
+	}
+}
+
 func fromRemoteNameToRemoteNodeName(name string) resource.Name {
 	return resource.NewName(client.RemoteAPI, name)
 }
@@ -114,6 +164,7 @@ func (manager *resourceManager) startModuleManager(
 		ViamHomeDir:             viamHomeDir,
 		RobotCloudID:            robotCloudID,
 		PackagesDir:             packagesDir,
+		Metrics:                 manager.opts.metrics,
 	}
 	manager.moduleManager = modmanager.NewManager(ctx, parentAddr, logger, mmOpts)
 }
@@ -140,6 +191,37 @@ func (manager *resourceManager) addRemote(
 	manager.updateRemoteResourceNames(ctx, rName, rr)
 }
 
+// setMaxRemoteDepth sets how many remotes deep remote resources are merged, where zero means
+// config.DefaultMaxRemoteDepth.
+func (manager *resourceManager) setMaxRemoteDepth(depth int) {
+	manager.maxRemoteDepth.Store(int64(depth))
+}
+
+// checkRemoteChain returns an error naming the chain of remotes the remote resource is
+// reached through if the chain loops back to a remote already in it, which happens when
+// remotes are remotes of each other, or if it is deeper than the max remote depth.
+func (manager *resourceManager) checkRemoteChain(name resource.Name) error {
+	if !name.ContainsRemoteNames() {
+		return nil
+	}
+	chain := strings.Split(name.Remote, ":")
+	seen := make(map[string]bool, len(chain))
+	for _, remote := range chain {
+		if seen[remote] {
+			return errors.Errorf("remote cycle detected: %s reaches remote %q again", strings.Join(chain, " -> "), remote)
+		}
+		seen[remote] = true
+	}
+	maxDepth := int(manager.maxRemoteDepth.Load())
+	if maxDepth == 0 {
+		maxDepth = config.DefaultMaxRemoteDepth
+	}
+	if len(chain) > maxDepth {
+		return errors.Errorf("remote chain %s is deeper than the max remote depth of %d", strings.Join(chain, " -> "), maxDepth)
+	}
+	return nil
+}
+
 func (manager *resourceManager) remoteResourceNames(remoteName resource.Name) []resource.Name {
 	var filtered []resource.Name
 	if _, ok := manager.resources.Node(remoteName); !ok {
@@ -189,6 +271,13 @@ func (manager *resourceManager) updateRemoteResourceNames(
 
 	for _, resName := range newResources {
 		remoteResName := resName
+		if err := manager.checkRemoteChain(resName.PrependRemote(remoteName.Name)); err != nil {
+			// the remote is polled for its resources, so each chain is only reported once.
+			if _, reported := manager.rejectedRemoteChains.LoadOrStore(err.Error(), true); !reported {
+				manager.logger.CErrorw(ctx, "not adding remote resource", "name", remoteResName, "remote", remoteName, "reason", err)
+			}
+			continue
+		}
 		res, err := rr.ResourceByName(remoteResName) // this returns a remote known OR foreign resource client
 		if err != nil {
 			if errors.Is(err, client.ErrMissingClientRegistration) {
@@ -314,6 +403,30 @@ func (manager *resourceManager) anyResourcesNotConfigured() bool {
 	return false
 }
 
+// failedResources returns the errors of the local components and services that are not
+// built, split by whether they are configured as optional.
+func (manager *resourceManager) failedResources() (required, optional map[resource.Name]error) {
+	required = map[resource.Name]error{}
+	optional = map[resource.Name]error{}
+	for _, name := range manager.resources.Names() {
+		if name.ContainsRemoteNames() || !(name.API.IsComponent() || name.API.IsService()) {
+			continue
+		}
+		gNode, ok := manager.resources.Node(name)
+		if !ok {
+			continue
+		}
+		if _, err := gNode.Resource(); err != nil {
+			if gNode.Config().Optional {
+				optional[name] = err
+			} else {
+				required[name] = err
+			}
+		}
+	}
+	return required, optional
+}
+
 func (manager *resourceManager) internalResourceNames() []resource.Name {
 	names := []resource.Name{}
 	for _, k := range manager.resources.Names() {
@@ -437,6 +550,9 @@ func (manager *resourceManager) closeResource(ctx context.Context, res resource.
 	closeCtx, cancel := context.WithTimeout(ctx, resourceCloseTimeout)
 	defer cancel()
 
+	if err := resource.PreClose(closeCtx, res); err != nil {
+		manager.logger.CErrorw(ctx, "error running pre-close hook of resource", "resource", res.Name(), "error", err)
+	}
 	allErrs := res.Close(closeCtx)
 
 	resName := res.Name()
@@ -469,13 +585,21 @@ func (manager *resourceManager) closeAndUnsetResource(ctx context.Context, gNode
 func (manager *resourceManager) removeMarkedAndClose(
 	ctx context.Context,
 	excludeFromClose map[resource.Name]struct{},
+) error {
+	return manager.removeMarkedAndShutdown(ctx, excludeFromClose, nil)
+}
+
+// removeMarkedAndShutdown is removeMarkedAndClose, carrying out the given shutdown actions of
+// resources just before closing them.
+func (manager *resourceManager) removeMarkedAndShutdown(
+	ctx context.Context,
+	excludeFromClose map[resource.Name]struct{},
+	shutdowns map[resource.Name]func(ctx context.Context) error,
 ) error {
 	defer func() {
 		manager.configLock.Lock()
 		defer manager.configLock.Unlock()
-		if err := manager.viz.SaveSnapshot(manager.resources); err != nil {
-			manager.logger.Warnw("failed to save graph snapshot", "error", err)
-		}
+		manager.saveSnapshot()
 	}()
 
 	var allErrs error
@@ -485,6 +609,11 @@ func (manager *resourceManager) removeMarkedAndClose(
 		if _, ok := excludeFromClose[resName]; ok {
 			continue
 		}
+		if shutdown, ok := shutdowns[resName]; ok {
+			if err := shutdown(ctx); err != nil {
+				manager.logger.CErrorw(ctx, "error carrying out shutdown action of resource", "resource", resName, "error", err)
+			}
+		}
 		allErrs = multierr.Combine(allErrs, manager.closeResource(ctx, res))
 	}
 	return allErrs
@@ -503,7 +632,8 @@ func (manager *resourceManager) Close(ctx context.Context) error {
 	excludeWebFromClose := map[resource.Name]struct{}{
 		web.InternalServiceName: {},
 	}
-	if err := manager.removeMarkedAndClose(ctx, excludeWebFromClose); err != nil {
+	manager.orderShutdown()
+	if err := manager.removeMarkedAndShutdown(ctx, excludeWebFromClose, manager.shutdownActions()); err != nil {
 		allErrs = multierr.Combine(allErrs, err)
 	}
 
@@ -513,10 +643,83 @@ func (manager *resourceManager) Close(ctx context.Context) error {
 			allErrs = multierr.Combine(allErrs, errors.Wrap(err, "error closing module manager"))
 		}
 	}
+	manager.failovers.Close()
 
 	return allErrs
 }
 
+// orderShutdown orders the closing of resources beyond their dependencies: components
+// mounted on the frame of another component are closed before it, and resources are closed
+// before those named in their shutdown config, so that e.g. a gripper is closed before the
+// arm it is mounted on, and the arm before the board powering it.
+func (manager *resourceManager) orderShutdown() {
+	byShortName := map[string][]resource.Name{}
+	for _, name := range manager.resources.Names() {
+		if name.API.IsComponent() || name.API.IsService() {
+			byShortName[name.ShortName()] = append(byShortName[name.ShortName()], name)
+		}
+	}
+	closeBefore := func(first resource.Name, then string) {
+		for _, thenName := range byShortName[then] {
+			if manager.resources.IsNodeDependingOn(first, thenName) {
+				continue
+			}
+			if err := manager.resources.AddChild(first, thenName); err != nil {
+				manager.logger.Warnw("cannot order shutdown of resources", "resource", first, "before", thenName, "error", err)
+			}
+		}
+	}
+	for _, name := range manager.resources.Names() {
+		node, ok := manager.resources.Node(name)
+		if !ok {
+			continue
+		}
+		conf := node.Config()
+		if name.API.IsComponent() && conf.Frame != nil && conf.Frame.Parent != "" {
+			closeBefore(name, conf.Frame.Parent)
+		}
+		if conf.Shutdown != nil {
+			for _, before := range conf.Shutdown.Before {
+				closeBefore(name, before)
+			}
+		}
+	}
+}
+
+// shutdownActions returns the shutdown actions configured for resources. An action is
+// carried out by the ShutdownAction registered for the API of its resource, or else sent to
+// the resource with DoCommand.
+func (manager *resourceManager) shutdownActions() map[resource.Name]func(ctx context.Context) error {
+	shutdowns := map[resource.Name]func(ctx context.Context) error{}
+	for _, name := range manager.resources.Names() {
+		node, ok := manager.resources.Node(name)
+		if !ok {
+			continue
+		}
+		conf := node.Config()
+		if conf.Shutdown == nil || len(conf.Shutdown.Action) == 0 {
+			continue
+		}
+		// resources are already marked for removal, so their nodes refuse to return them.
+		res, err := node.UnsafeResource()
+		if err != nil {
+			continue
+		}
+		action := conf.Shutdown.Action
+		shutdowns[name] = func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, shutdownActionTimeout)
+			defer cancel()
+			manager.logger.CInfow(ctx, "Carrying out shutdown action of resource", "resource", name)
+			if reg, ok := resource.LookupGenericAPIRegistration(name.API); ok && reg.Shutdown != nil {
+				return reg.Shutdown(ctx, res, action)
+			}
+			_, err := res.DoCommand(ctx, action)
+			return err
+		}
+	}
+	return shutdowns
+}
+
 // completeConfig process the tree in reverse order and attempts to build or reconfigure
 // resources that are wrapped in a placeholderResource. this function will attempt to
 // process resources concurrently when they do not depend on each other unless
@@ -528,9 +731,7 @@ func (manager *resourceManager) completeConfig(
 ) {
 	manager.configLock.Lock()
 	defer func() {
-		if err := manager.viz.SaveSnapshot(manager.resources); err != nil {
-			manager.logger.Warnw("failed to save graph snapshot", "error", err)
-		}
+		manager.saveSnapshot()
 		manager.configLock.Unlock()
 	}()
 
@@ -544,167 +745,244 @@ func (manager *resourceManager) completeConfig(
 	}
 
 	// sort resources into topological "levels" based on their dependencies. resources in
-	// any given level only depend on resources in prior levels. this makes it safe to
-	// process resources within a level concurrently as long as levels are processed in
-	// order.
+	// any given level only depend on resources in prior levels.
 	levels := manager.resources.ReverseTopologicalSortInLevels()
 	timeout := rutils.GetResourceConfigurationTimeout(manager.logger)
-	for _, resourceNames := range levels {
-		// we use an errgroup here instead of a normal waitgroup to conveniently bubble
-		// up errors in resource processing goroutinues that warrant an early exit.
-		var levelErrG errgroup.Group
-		for _, resName := range resourceNames {
-			select {
-			case <-ctx.Done():
+
+	// processResource is intended to be run concurrently for resources that do not depend
+	// on each other. if any processResource function returns a non-nil error then the
+	// entire `completeConfig` function will exit early.
+	//
+	// currently only a top-level context cancellation will result in an early
+	// exist - individual resource processing failures will not.
+	processResource := func(resName resource.Name) error {
+		defer func() {
+			lr.reconfigureWorkers.Done()
+		}()
+
+		resChan := make(chan struct{}, 1)
+		ctxWithTimeout, timeoutCancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer timeoutCancel()
+
+		stopSlowLogger := rutils.SlowStartupLogger(
+			ctx, "Waiting for resource to complete (re)configuration", "resource", resName.String(), manager.logger)
+
+		lr.reconfigureWorkers.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer func() {
+				stopSlowLogger()
+				resChan <- struct{}{}
+			}()
+			gNode, ok := manager.resources.Node(resName)
+			if !ok || !gNode.NeedsReconfigure() || manager.isQuiesced(resName) {
+				return
+			}
+			if !(resName.API.IsComponent() || resName.API.IsService()) {
 				return
-			default:
 			}
 
-			resName := resName
-			// processResource is intended to be run concurrently for each resource
-			// within a topological sort level. if any processResource function returns a
-			// non-nil error then the entire `completeConfig` function will exit early.
-			//
-			// currently only a top-level context cancellation will result in an early
-			// exist - individual resource processing failures will not.
-			processResource := func() error {
-				defer func() {
-					lr.reconfigureWorkers.Done()
-				}()
-
-				resChan := make(chan struct{}, 1)
-				ctxWithTimeout, timeoutCancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
-				defer timeoutCancel()
-
-				stopSlowLogger := rutils.SlowStartupLogger(
-					ctx, "Waiting for resource to complete (re)configuration", "resource", resName.String(), manager.logger)
-
-				lr.reconfigureWorkers.Add(1)
-				goutils.PanicCapturingGo(func() {
-					defer func() {
-						stopSlowLogger()
-						resChan <- struct{}{}
-					}()
-					gNode, ok := manager.resources.Node(resName)
-					if !ok || !gNode.NeedsReconfigure() {
-						return
-					}
-					if !(resName.API.IsComponent() || resName.API.IsService()) {
-						return
-					}
+			var verb string
+			conf := gNode.Config()
+			if gNode.IsUninitialized() {
+				verb = "configuring"
+				gNode.InitializeLogger(
+					manager.logger, resName.String(), conf.LogConfiguration.Level,
+				)
+			} else {
+				verb = "reconfiguring"
+			}
+			manager.logger.CInfow(ctx, fmt.Sprintf("Now %s resource", verb), "resource", resName)
 
-					var verb string
-					conf := gNode.Config()
-					if gNode.IsUninitialized() {
-						verb = "configuring"
-						gNode.InitializeLogger(
-							manager.logger, resName.String(), conf.LogConfiguration.Level,
-						)
-					} else {
-						verb = "reconfiguring"
+			// this is done in config validation but partial start rules require us to check again
+			if _, err := conf.Validate("", resName.API.Type.Name); err != nil {
+				gNode.LogAndSetLastError(
+					fmt.Errorf("resource config validation error: %w", err),
+					"resource", conf.ResourceName(),
+					"model", conf.Model)
+				return
+			}
+			if manager.moduleManager.Provides(conf) {
+				if _, err := manager.moduleManager.ValidateConfig(ctxWithTimeout, conf); err != nil {
+					gNode.LogAndSetLastError(
+						fmt.Errorf("modular resource config validation error: %w", err),
+						"resource", conf.ResourceName(),
+						"model", conf.Model)
+					return
+				}
+			}
+
+			switch {
+			case resName.API.IsComponent(), resName.API.IsService():
+
+				newRes, newlyBuilt, err := manager.processResource(ctxWithTimeout, conf, gNode, lr)
+				if newlyBuilt || err != nil {
+					if err := manager.markChildrenForUpdate(resName); err != nil {
+						manager.logger.CErrorw(ctx,
+							"failed to mark children of resource for update",
+							"resource", resName,
+							"reason", err)
 					}
-					manager.logger.CInfow(ctx, fmt.Sprintf("Now %s resource", verb), "resource", resName)
+				}
 
-					// this is done in config validation but partial start rules require us to check again
-					if _, err := conf.Validate("", resName.API.Type.Name); err != nil {
+				if err != nil {
+					gNode.LogAndSetLastError(
+						fmt.Errorf("resource build error: %w", err),
+						"resource", conf.ResourceName(),
+						"model", conf.Model)
+					return
+				}
+
+				if newlyBuilt {
+					if err := resource.PreStart(ctxWithTimeout, newRes); err != nil {
 						gNode.LogAndSetLastError(
-							fmt.Errorf("resource config validation error: %w", err),
+							fmt.Errorf("resource pre-start error: %w", multierr.Combine(err, newRes.Close(ctx))),
 							"resource", conf.ResourceName(),
 							"model", conf.Model)
 						return
 					}
-					if manager.moduleManager.Provides(conf) {
-						if _, err := manager.moduleManager.ValidateConfig(ctxWithTimeout, conf); err != nil {
-							gNode.LogAndSetLastError(
-								fmt.Errorf("modular resource config validation error: %w", err),
-								"resource", conf.ResourceName(),
-								"model", conf.Model)
-							return
-						}
-					}
-
-					switch {
-					case resName.API.IsComponent(), resName.API.IsService():
-
-						newRes, newlyBuilt, err := manager.processResource(ctxWithTimeout, conf, gNode, lr)
-						if newlyBuilt || err != nil {
-							if err := manager.markChildrenForUpdate(resName); err != nil {
-								manager.logger.CErrorw(ctx,
-									"failed to mark children of resource for update",
-									"resource", resName,
-									"reason", err)
-							}
-						}
+				}
 
-						if err != nil {
-							gNode.LogAndSetLastError(
-								fmt.Errorf("resource build error: %w", err),
-								"resource", conf.ResourceName(),
-								"model", conf.Model)
-							return
+				// if the ctxWithTimeout fails with DeadlineExceeded, then that means that
+				// resource generation is running async, and we don't currently have good
+				// validation around how this might affect the resource graph. So, we avoid
+				// updating the graph to be safe.
+				if errors.Is(ctxWithTimeout.Err(), context.DeadlineExceeded) {
+					manager.logger.CErrorw(
+						ctx, "error building resource", "resource", conf.ResourceName(), "model", conf.Model, "error", ctxWithTimeout.Err())
+				} else {
+					gNode.SwapResource(newRes, conf.Model)
+					if newlyBuilt {
+						if err := resource.PostStart(ctxWithTimeout, newRes); err != nil {
+							manager.logger.CErrorw(ctx, "error running post-start hook of resource",
+								"resource", conf.ResourceName(), "model", conf.Model, "error", err)
 						}
+					}
+				}
 
-						// if the ctxWithTimeout fails with DeadlineExceeded, then that means that
-						// resource generation is running async, and we don't currently have good
-						// validation around how this might affect the resource graph. So, we avoid
-						// updating the graph to be safe.
-						if errors.Is(ctxWithTimeout.Err(), context.DeadlineExceeded) {
-							manager.logger.CErrorw(
-								ctx, "error building resource", "resource", conf.ResourceName(), "model", conf.Model, "error", ctxWithTimeout.Err())
-						} else {
-							gNode.SwapResource(newRes, conf.Model)
-						}
+			default:
+				err := errors.New("config is not for a component or service")
+				gNode.LogAndSetLastError(err, "resource", resName)
+			}
+		})
 
-					default:
-						err := errors.New("config is not for a component or service")
-						gNode.LogAndSetLastError(err, "resource", resName)
-					}
-				})
+		select {
+		case <-resChan:
+		case <-ctxWithTimeout.Done():
+			// this resource is taking too long to process, so we give up but
+			// continue processing other resources. we do not wait for this
+			// resource to finish processing since it may be running outside code
+			// and have unexpected behavior.
+			if errors.Is(ctxWithTimeout.Err(), context.DeadlineExceeded) {
+				lr.logger.CWarn(ctx, rutils.NewBuildTimeoutError(resName.String()))
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		return nil
+	}
 
-				select {
-				case <-resChan:
-				case <-ctxWithTimeout.Done():
-					// this resource is taking too long to process, so we give up but
-					// continue processing other resources. we do not wait for this
-					// resource to finish processing since it may be running outside code
-					// and have unexpected behavior.
-					if errors.Is(ctxWithTimeout.Err(), context.DeadlineExceeded) {
-						lr.logger.CWarn(ctx, rutils.NewBuildTimeoutError(resName.String()))
-					}
-				case <-ctx.Done():
-					return ctx.Err()
+	if forceSync {
+		for _, resourceNames := range levels {
+			for _, resName := range resourceNames {
+				if ctx.Err() != nil {
+					return
 				}
-				return nil
+				if err := processResource(resName); err != nil {
+					return
+				}
+			}
+		}
+		return
+	}
+	manager.processConcurrently(ctx, lr, levels, processResource)
+}
+
+// processConcurrently processes the given resources, sorted into levels that only depend
+// on resources in prior levels, with a bounded pool of workers. Each resource is processed
+// as soon as the resources it depends on are, rather than once every resource of the
+// prior level is, so that one slow resource only holds up the resources depending on it.
+// It stops starting resources once processing one fails.
+func (manager *resourceManager) processConcurrently(
+	ctx context.Context,
+	lr *localRobot,
+	levels [][]resource.Name,
+	processResource func(resName resource.Name) error,
+) {
+	// pending counts the dependencies of each resource that are yet to be processed.
+	pending := map[resource.Name]int{}
+	dependents := map[resource.Name][]resource.Name{}
+	for _, resourceNames := range levels {
+		for _, resName := range resourceNames {
+			pending[resName] = 0
+		}
+	}
+	for resName := range pending {
+		for _, dep := range manager.resources.GetAllParentsOf(resName) {
+			if _, ok := pending[dep]; ok {
+				pending[resName]++
+				dependents[dep] = append(dependents[dep], resName)
 			}
+		}
+	}
 
-			syncRes := forceSync
-			if !syncRes {
-				// TODO(RSDK-6925): support concurrent processing of resources of
-				// APIs with a maximum instance limit. Currently this limit is
-				// validated later in the resource creation flow and assumes that
-				// each resource is created synchronously to have an accurate
-				// creation count.
+	type result struct {
+		resName resource.Name
+		err     error
+	}
+	var (
+		workers = make(chan struct{}, rutils.GetResourceConfigurationConcurrency(manager.logger))
+		results = make(chan result)
+		running int
+		failed  bool
+		// maxInstanceMu serializes processing resources of APIs with a maximum
+		// instance limit, since that limit is validated later in the resource
+		// creation flow against a count of instances already created.
+		maxInstanceMu sync.Mutex
+	)
+	start := func(resName resource.Name) {
+		running++
+		lr.reconfigureWorkers.Add(1)
+		goutils.PanicCapturingGo(func() {
+			defer lr.reconfigureWorkers.Done()
+			var err error
+			select {
+			case workers <- struct{}{}:
 				if c, ok := resource.LookupGenericAPIRegistration(resName.API); ok && c.MaxInstance != 0 {
-					syncRes = true
+					maxInstanceMu.Lock()
+					err = processResource(resName)
+					maxInstanceMu.Unlock()
+				} else {
+					err = processResource(resName)
 				}
+				<-workers
+			case <-ctx.Done():
+				err = ctx.Err()
 			}
+			results <- result{resName, err}
+		})
+	}
 
-			if syncRes {
-				if err := processResource(); err != nil {
-					return
-				}
-			} else {
-				lr.reconfigureWorkers.Add(1)
-				levelErrG.Go(func() error {
-					defer lr.reconfigureWorkers.Done()
-					return processResource()
-				})
+	for resName, count := range pending {
+		if count == 0 {
+			start(resName)
+		}
+	}
+	for running > 0 {
+		res := <-results
+		running--
+		if res.err != nil {
+			failed = true
+		}
+		if failed {
+			continue
+		}
+		for _, dependent := range dependents[res.resName] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				start(dependent)
 			}
-		} // for-each resource name
-		if err := levelErrG.Wait(); err != nil {
-			return
 		}
-	} // for-each level
+	}
 }
 
 func (manager *resourceManager) completeConfigForRemotes(ctx context.Context, lr *localRobot) {
@@ -745,6 +1023,7 @@ func (manager *resourceManager) completeConfigForRemotes(ctx context.Context, lr
 				continue
 			}
 			manager.addRemote(ctx, rr, gNode, *remConf)
+			lr.cacheRemoteMetadata(ctx, *remConf, rr)
 			rr.SetParentNotifier(func() {
 				if lr.closeContext.Err() != nil {
 					return
@@ -896,12 +1175,42 @@ func (manager *resourceManager) markChildrenForUpdate(rName resource.Name) error
 	return nil
 }
 
+// closeForRebuild closes the resource of the node and marks it and the resources depending
+// on it for update so that they are rebuilt by the next call to completeConfig. The config
+// lock must be held.
+func (manager *resourceManager) closeForRebuild(ctx context.Context, name resource.Name, gNode *resource.GraphNode) error {
+	err := manager.closeAndUnsetResource(ctx, gNode)
+	gNode.SetNeedsUpdate()
+	return multierr.Combine(err, manager.markChildrenForUpdate(name))
+}
+
+// setQuiesced sets whether the named resources are left closed by completeConfig.
+func (manager *resourceManager) setQuiesced(names []resource.Name, quiesced bool) {
+	manager.quiescedMu.Lock()
+	defer manager.quiescedMu.Unlock()
+	for _, name := range names {
+		if quiesced {
+			manager.quiesced[name] = struct{}{}
+		} else {
+			delete(manager.quiesced, name)
+		}
+	}
+}
+
+func (manager *resourceManager) isQuiesced(name resource.Name) bool {
+	manager.quiescedMu.Lock()
+	defer manager.quiescedMu.Unlock()
+	_, ok := manager.quiesced[name]
+	return ok
+}
+
 func (manager *resourceManager) processResource(
 	ctx context.Context,
 	conf resource.Config,
 	gNode *resource.GraphNode,
 	lr *localRobot,
 ) (resource.Resource, bool, error) {
+	ctx = manager.withSimulation(ctx)
 	if gNode.IsUninitialized() {
 		newRes, err := lr.newResource(ctx, gNode, conf)
 		if err != nil {
@@ -1133,11 +1442,11 @@ func (manager *resourceManager) updateResources(
 // returns an error otherwise.
 func (manager *resourceManager) ResourceByName(name resource.Name) (resource.Resource, error) {
 	if gNode, ok := manager.resources.Node(name); ok {
-		res, err := gNode.Resource()
+		res, _, err := manager.failOver(name, gNode)
 		if err != nil {
-			return nil, resource.NewNotAvailableError(name, err)
+			return nil, err
 		}
-		return res, nil
+		return manager.limited(name, gNode.Config(), manager.calibrated(name, gNode.Config(), res)), nil
 	}
 	// if we haven't found a resource of this name then we are going to look into remote resources to find it.
 	// This is kind of weird and arguably you could have a ResourcesByPartialName that would match against
@@ -1161,6 +1470,145 @@ func (manager *resourceManager) ResourceByName(name resource.Name) (resource.Res
 	return nil, resource.NewNotFoundError(name)
 }
 
+// withSimulation returns the context to build and reconfigure resources with, telling them
+// whether the robot runs in simulation.
+func (manager *resourceManager) withSimulation(ctx context.Context) context.Context {
+	if !manager.opts.simulation {
+		return ctx
+	}
+	return resource.WithSimulation(ctx)
+}
+
+// limited returns the resource of the given name as looked up, enforcing the motion limits of
+// its config if it has any and its API supports them.
+func (manager *resourceManager) limited(name resource.Name, conf resource.Config, res resource.Resource) resource.Resource {
+	manager.limitersMu.Lock()
+	defer manager.limitersMu.Unlock()
+	if conf.Limits == nil {
+		delete(manager.limiters, name)
+		return res
+	}
+	reg, ok := resource.LookupGenericAPIRegistration(name.API)
+	if !ok || reg.Limit == nil {
+		return res
+	}
+	// the limiter is kept across lookups, for the acceleration limits, until the limits change.
+	limiter, ok := manager.limiters[name]
+	if !ok || !reflect.DeepEqual(limiter.Limits(), *conf.Limits) {
+		limiter = resource.NewMotionLimiter(name, *conf.Limits, manager.logger.Sublogger("limits"), func(err error) {
+			if manager.opts.limitViolated != nil {
+				manager.opts.limitViolated(name, err)
+			}
+		})
+		manager.limiters[name] = limiter
+	}
+	return reg.Limit(res, limiter)
+}
+
+// calibrated returns the resource of the given name as looked up, with the kinematic model of
+// its config if it has one and its API supports it.
+func (manager *resourceManager) calibrated(name resource.Name, conf resource.Config, res resource.Resource) resource.Resource {
+	manager.kinematicsMu.Lock()
+	defer manager.kinematicsMu.Unlock()
+	if conf.Kinematics == nil {
+		delete(manager.kinematics, name)
+		return res
+	}
+	reg, ok := resource.LookupGenericAPIRegistration(name.API)
+	if !ok || reg.Calibrate == nil {
+		return res
+	}
+	// the kinematics are kept across lookups, for the model read from their file, until they
+	// change.
+	kinematics, ok := manager.kinematics[name]
+	if !ok || !reflect.DeepEqual(kinematics.Config(), *conf.Kinematics) {
+		kinematics = resource.NewKinematics(name, *conf.Kinematics, manager.logger.Sublogger("kinematics"))
+		manager.kinematics[name] = kinematics
+	}
+	return reg.Calibrate(res, kinematics)
+}
+
+// updateKinematics reads again the kinematics files of the resources that changed, returning
+// whether any did.
+func (manager *resourceManager) updateKinematics() bool {
+	manager.kinematicsMu.Lock()
+	defer manager.kinematicsMu.Unlock()
+	anyChanges := false
+	for _, kinematics := range manager.kinematics {
+		anyChanges = kinematics.Reload() || anyChanges
+	}
+	return anyChanges
+}
+
+// standbyOf returns the name of the standby configured for the resource of the given node.
+func standbyOf(name resource.Name, gNode *resource.GraphNode) (resource.Name, bool) {
+	standby := gNode.Config().Standby
+	if standby == "" {
+		return resource.Name{}, false
+	}
+	return resource.NewName(name.API, standby), true
+}
+
+// failOver returns the resource serving lookups of the given node: its own resource, or the
+// resource of its standby while it is unavailable and its standby is not. It also returns
+// whether lookups just failed over to or back from the standby.
+func (manager *resourceManager) failOver(name resource.Name, gNode *resource.GraphNode) (resource.Resource, bool, error) {
+	res, err := gNode.Resource()
+	standbyName, ok := standbyOf(name, gNode)
+	if !ok {
+		if err != nil {
+			return nil, false, resource.NewNotAvailableError(name, err)
+		}
+		return res, false, nil
+	}
+	if err == nil {
+		return res, manager.failovers.Update(name, standbyName, nil), nil
+	}
+	standby, standbyErr := manager.standbyResource(standbyName)
+	if standbyErr != nil {
+		return nil, false, resource.NewNotAvailableError(name, err)
+	}
+	return standby, manager.failovers.Update(name, standbyName, err), nil
+}
+
+// standbyResource returns the resource of the standby with the given name if it is available.
+// A standby with a standby of its own does not fail over again.
+func (manager *resourceManager) standbyResource(name resource.Name) (resource.Resource, error) {
+	gNode, ok := manager.resources.Node(name)
+	if !ok {
+		return nil, resource.NewNotFoundError(name)
+	}
+	return gNode.Resource()
+}
+
+// updateFailovers checks the availability of every resource with a standby, so that failing
+// over to and back from standbys is noticed even while nothing looks them up. It returns
+// whether any resource failed over or back.
+func (manager *resourceManager) updateFailovers() bool {
+	anyChanges := false
+	withStandby := map[resource.Name]bool{}
+	for _, name := range manager.resources.Names() {
+		gNode, ok := manager.resources.Node(name)
+		if !ok {
+			continue
+		}
+		if _, ok := standbyOf(name, gNode); !ok {
+			continue
+		}
+		withStandby[name] = true
+		_, changed, _ := manager.failOver(name, gNode)
+		anyChanges = changed || anyChanges
+	}
+	// resources that no longer exist or have a standby are no longer failed over.
+	for _, primary := range manager.failovers.Primaries() {
+		if !withStandby[primary] {
+			standby, _ := manager.failovers.FailedOver(primary)
+			anyChanges = manager.failovers.Update(primary, standby, nil) || anyChanges
+		}
+	}
+	return anyChanges
+}
+
 // PartsMergeResult is the result of merging in parts together.
 type PartsMergeResult struct {
 	ReplacedProcesses []pexec.ManagedProcess
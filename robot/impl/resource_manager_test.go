@@ -357,6 +357,45 @@ func TestManagerMergeNamesWithRemotes(t *testing.T) {
 	test.That(t, err, test.ShouldBeError)
 }
 
+func TestManagerRemoteChains(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	// middleRobot returns a robot with a remote named b, whose resources it has as b:<name>.
+	middleRobot := func() *dummyRobot {
+		middle := newDummyRobot(t, setupInjectRobot(logger))
+		middle.manager.addRemote(ctx, newDummyRobot(t, setupInjectRobot(logger)), nil, config.Remote{Name: "b"})
+		return middle
+	}
+
+	// the resources of a remote named b reached through b again are not merged.
+	manager := managerForDummyRobot(t, setupInjectRobot(logger))
+	manager.addRemote(ctx, middleRobot(), nil, config.Remote{Name: "b"})
+	_, err := manager.ResourceByName(arm.Named("b:arm1"))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = manager.ResourceByName(arm.Named("b:b:arm1"))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// nor are those reached through more remotes than the max remote depth.
+	manager = managerForDummyRobot(t, setupInjectRobot(logger))
+	manager.setMaxRemoteDepth(1)
+	manager.addRemote(ctx, middleRobot(), nil, config.Remote{Name: "a"})
+	_, err = manager.ResourceByName(arm.Named("a:arm1"))
+	test.That(t, err, test.ShouldBeNil)
+	_, err = manager.ResourceByName(arm.Named("a:b:arm1"))
+	test.That(t, err, test.ShouldNotBeNil)
+
+	test.That(t, manager.checkRemoteChain(arm.Named("arm1")), test.ShouldBeNil)
+	err = manager.checkRemoteChain(arm.Named("a:b:a:arm1"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `remote cycle detected: a -> b -> a reaches remote "a" again`)
+	err = manager.checkRemoteChain(arm.Named("a:b:arm1"))
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "remote chain a -> b is deeper than the max remote depth of 1")
+	manager.setMaxRemoteDepth(0)
+	test.That(t, manager.checkRemoteChain(arm.Named("a:b:arm1")), test.ShouldBeNil)
+}
+
 func TestManagerResourceRemoteName(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	injectRobot := &inject.Robot{}
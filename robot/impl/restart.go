@@ -0,0 +1,47 @@
+package robotimpl
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/resource"
+)
+
+// RestartResource closes the named local resource and reconstructs it from its config entry,
+// along with the resources depending on it. Actuators are stopped before they are closed.
+// Default services and other weak dependents are updated with the new resource.
+func (r *localRobot) RestartResource(ctx context.Context, name resource.Name) error {
+	if name.ContainsRemoteNames() {
+		return errors.Errorf("cannot restart remote resource %q", name)
+	}
+
+	manager := r.manager
+	manager.configLock.Lock()
+	gNode, ok := manager.resources.Node(name)
+	if !ok || gNode.MarkedForRemoval() || gNode.Config().Name == "" {
+		manager.configLock.Unlock()
+		return resource.NewNotFoundError(name)
+	}
+	r.logger.CInfow(ctx, "restarting resource", "resource", name)
+	var allErrs error
+	if res, err := gNode.Resource(); err == nil {
+		if actuator, ok := res.(resource.Actuator); ok {
+			allErrs = multierr.Combine(allErrs, actuator.Stop(ctx, nil))
+		}
+	}
+	allErrs = multierr.Combine(allErrs, manager.closeForRebuild(ctx, name, gNode))
+	manager.configLock.Unlock()
+	if allErrs != nil {
+		r.logger.CWarnw(ctx, "errors encountered while closing resource for restart", "resource", name, "error", allErrs)
+	}
+
+	manager.completeConfig(ctx, r, false)
+	r.updateWeakDependents(ctx)
+	r.stateTracker.publishChanges(manager)
+	if _, err := manager.ResourceByName(name); err != nil {
+		return errors.Wrapf(err, "failed to restart %q", name)
+	}
+	return nil
+}
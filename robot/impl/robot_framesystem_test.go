@@ -3,6 +3,8 @@ package robotimpl
 import (
 	"context"
 	"math"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -10,12 +12,21 @@ import (
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
 
+	"go.viam.com/rdk/components/arm"
+	fakearm "go.viam.com/rdk/components/arm/fake"
+	ur "go.viam.com/rdk/components/arm/universalrobots"
 	"go.viam.com/rdk/components/base"
+	fakebase "go.viam.com/rdk/components/base/fake"
 	"go.viam.com/rdk/components/gripper"
+	"go.viam.com/rdk/components/movementsensor"
+	fakemovementsensor "go.viam.com/rdk/components/movementsensor/fake"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/events"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/tools"
 	_ "go.viam.com/rdk/services/datamanager/builtin"
 	"go.viam.com/rdk/services/motion"
 	_ "go.viam.com/rdk/services/motion/builtin"
@@ -241,3 +252,440 @@ func TestServiceWithUnavailableRemote(t *testing.T) {
 	test.That(t, err, test.ShouldBeNil)
 	test.That(t, fs.FrameNames(), test.ShouldHaveLength, 2)
 }
+
+func TestFrameSystemConfigWithCachedRemote(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	remoteConfig, err := config.Read(ctx, rutils.ResolveFile("robot/impl/data/fake.json"), logger.Sublogger("remote"))
+	test.That(t, err, test.ShouldBeNil)
+	remoteRobot, err := New(ctx, remoteConfig, logger.Sublogger("remote"), WithViamHomeDir(t.TempDir()))
+	test.That(t, err, test.ShouldBeNil)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, remoteRobot.StartWeb(ctx, options), test.ShouldBeNil)
+
+	localConfig := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:  "myParentIsRemote",
+				API:   gripper.API,
+				Model: resource.DefaultModelFamily.WithModel("fake"),
+				Frame: &referenceframe.LinkConfig{
+					Parent: "bar:pieceArm",
+				},
+			},
+		},
+		Remotes: []config.Remote{
+			{
+				Name:          "bar",
+				Address:       addr,
+				CacheMetadata: true,
+				Frame: &referenceframe.LinkConfig{
+					Parent:      referenceframe.World,
+					Translation: r3.Vector{100, 200, 300},
+				},
+			},
+		},
+	}
+	partNames := func(cfg *config.Config, homeDir string) []string {
+		r, err := New(ctx, cfg, logger.Sublogger("local"), WithViamHomeDir(homeDir))
+		test.That(t, err, test.ShouldBeNil)
+		defer func() {
+			test.That(t, r.Close(ctx), test.ShouldBeNil)
+		}()
+		fsCfg, err := r.FrameSystemConfig(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		var names []string
+		for _, part := range fsCfg.Parts {
+			names = append(names, part.FrameConfig.Name())
+		}
+		return names
+	}
+
+	// the robot caches the frame system of the remote while connected to it.
+	homeDir := t.TempDir()
+	connectedNames := partNames(localConfig, homeDir)
+	test.That(t, connectedNames, test.ShouldContain, "bar:pieceArm")
+	test.That(t, remoteRobot.Close(ctx), test.ShouldBeNil)
+
+	// and serves it when the remote is unreachable as the robot starts.
+	r, err := New(ctx, localConfig, logger.Sublogger("local"), WithViamHomeDir(homeDir))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	var cachedNames []string
+	for _, part := range fsCfg.Parts {
+		cachedNames = append(cachedNames, part.FrameConfig.Name())
+	}
+	test.That(t, cachedNames, test.ShouldHaveLength, len(connectedNames))
+	for _, name := range connectedNames {
+		test.That(t, cachedNames, test.ShouldContain, name)
+	}
+	_, err = referenceframe.NewFrameSystem("test", fsCfg.Parts, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	report, err := r.(*localRobot).Health(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Remotes, test.ShouldHaveLength, 1)
+	test.That(t, report.Remotes[0].Connected, test.ShouldBeFalse)
+	test.That(t, report.Remotes[0].CachedAt.IsZero(), test.ShouldBeFalse)
+	test.That(t, report.Remotes[0].CachedResources, test.ShouldContain, arm.Named("bar:pieceArm"))
+
+	// the cache is only used by remotes that ask for it.
+	localConfig.Remotes[0].CacheMetadata = false
+	test.That(t, partNames(localConfig, homeDir), test.ShouldNotContain, "bar:pieceArm")
+}
+
+func TestToolFrames(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "arm1",
+				API:                 arm.API,
+				Model:               fakearm.Model,
+				Frame:               &referenceframe.LinkConfig{Parent: referenceframe.World},
+				ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+			},
+			{
+				Name:                "arm2",
+				API:                 arm.API,
+				Model:               fakearm.Model,
+				ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	toolFrame := tools.ToolFrameName("arm1")
+	hasToolFrame := func() bool {
+		fsCfg, err := r.FrameSystemConfig(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		for _, part := range fsCfg.Parts {
+			if part.FrameConfig.Name() == toolFrame {
+				return true
+			}
+		}
+		return false
+	}
+	test.That(t, hasToolFrame(), test.ShouldBeFalse)
+
+	gripperTool := tools.Tool{
+		Name:        "gripper",
+		Translation: r3.Vector{Z: 100},
+		Geometry:    &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: 50, Y: 50, Z: 100},
+		Payload:     tools.Payload{MassKg: 1.5},
+	}
+	test.That(t, r.(*localRobot).Tools().Declare(ctx, "arm1", gripperTool), test.ShouldBeNil)
+	test.That(t, r.(*localRobot).Tools().Mount(ctx, "arm1", "gripper"), test.ShouldBeNil)
+	test.That(t, hasToolFrame(), test.ShouldBeTrue)
+
+	res, err := r.ResourceByName(arm.Named("arm1"))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, res.(*fakearm.Arm).Payload(), test.ShouldResemble, gripperTool.Payload)
+
+	// the tool center point is offset from the end effector by the tool's translation.
+	endEffector, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame("arm1", spatialmath.NewZeroPose()),
+		referenceframe.World, nil)
+	test.That(t, err, test.ShouldBeNil)
+	tcp, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame(toolFrame, spatialmath.NewZeroPose()),
+		referenceframe.World, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tcp.Pose().Point().Distance(endEffector.Pose().Point()), test.ShouldAlmostEqual, 100)
+
+	// the tool frame survives reconfiguration.
+	r.Reconfigure(ctx, cfg)
+	test.That(t, hasToolFrame(), test.ShouldBeTrue)
+
+	test.That(t, r.(*localRobot).Tools().Unmount(ctx, "arm1"), test.ShouldBeNil)
+	test.That(t, hasToolFrame(), test.ShouldBeFalse)
+	test.That(t, res.(*fakearm.Arm).Payload(), test.ShouldResemble, tools.Payload{})
+
+	// tools cannot be mounted on arms outside of the frame system.
+	test.That(t, r.(*localRobot).Tools().Declare(ctx, "arm2", gripperTool), test.ShouldBeNil)
+	err = r.(*localRobot).Tools().Mount(ctx, "arm2", "gripper")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no frame")
+	mounted, err := r.(*localRobot).Tools().Mounted(ctx, "arm2")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mounted, test.ShouldBeNil)
+}
+
+func TestRuntimeFrames(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	armConf := resource.Config{
+		Name:                "arm1",
+		API:                 arm.API,
+		Model:               fakearm.Model,
+		Frame:               &referenceframe.LinkConfig{Parent: referenceframe.World},
+		ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+	}
+	cfg := &config.Config{Components: []resource.Config{armConf}}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	subscription, err := r.(*localRobot).Events().Subscribe(ctx, events.FrameSystemUpdated)
+	test.That(t, err, test.ShouldBeNil)
+	framePose := func(name string) spatialmath.Pose {
+		t.Helper()
+		pif, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), referenceframe.World, nil)
+		test.That(t, err, test.ShouldBeNil)
+		return pif.Pose()
+	}
+
+	fixture := referenceframe.LinkConfig{ID: "fixture", Translation: r3.Vector{Z: 100}, Parent: "arm1"}
+	test.That(t, r.(*localRobot).RuntimeFrames().AddTransform(ctx, fixture), test.ShouldBeNil)
+	ev := <-subscription
+	test.That(t, ev.Frame, test.ShouldEqual, "fixture")
+	test.That(t, framePose("fixture").Point().Distance(framePose("arm1").Point()), test.ShouldAlmostEqual, 100)
+
+	// re-parenting the frame moves it.
+	fixture.Parent = referenceframe.World
+	test.That(t, r.(*localRobot).RuntimeFrames().UpdateFrame(ctx, fixture), test.ShouldBeNil)
+	test.That(t, (<-subscription).Frame, test.ShouldEqual, "fixture")
+	test.That(t, spatialmath.PoseAlmostEqual(framePose("fixture"), spatialmath.NewPoseFromPoint(r3.Vector{Z: 100})),
+		test.ShouldBeTrue)
+
+	// frames must be connected to the world frame, and must not shadow configured frames.
+	err = r.(*localRobot).RuntimeFrames().AddTransform(ctx, referenceframe.LinkConfig{ID: "part", Parent: "missing"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be added to the frame system")
+	err = r.(*localRobot).RuntimeFrames().AddTransform(ctx, referenceframe.LinkConfig{ID: "arm1", Parent: referenceframe.World})
+	test.That(t, err, test.ShouldNotBeNil)
+	frames, err := r.(*localRobot).RuntimeFrames().RuntimeFrames(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, frames, test.ShouldResemble, []referenceframe.LinkConfig{fixture})
+
+	// frames whose parent leaves the frame system are left out until it returns.
+	fixture.Parent = "arm1"
+	test.That(t, r.(*localRobot).RuntimeFrames().UpdateFrame(ctx, fixture), test.ShouldBeNil)
+	r.Reconfigure(ctx, &config.Config{})
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Parts, test.ShouldBeEmpty)
+	r.Reconfigure(ctx, cfg)
+	test.That(t, framePose("fixture").Point().Distance(framePose("arm1").Point()), test.ShouldAlmostEqual, 100)
+
+	test.That(t, r.(*localRobot).RuntimeFrames().RemoveFrame(ctx, "fixture"), test.ShouldBeNil)
+	_, err = r.TransformPose(ctx, referenceframe.NewPoseInFrame("fixture", spatialmath.NewZeroPose()), referenceframe.World, nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestLiveFrameSystem(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "arm1",
+				API:                 arm.API,
+				Model:               fakearm.Model,
+				Frame:               &referenceframe.LinkConfig{Parent: referenceframe.World},
+				ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	marker := referenceframe.NewLinkInFrame("arm1", spatialmath.NewPoseFromPoint(r3.Vector{Z: 50}), "marker", nil)
+	fs, err := r.(*localRobot).LiveFrameSystem(ctx, []*referenceframe.LinkInFrame{marker})
+	test.That(t, err, test.ShouldBeNil)
+
+	atZero, err := fs.CurrentPose(ctx, "marker", referenceframe.World)
+	test.That(t, err, test.ShouldBeNil)
+	expected, err := fs.Transform(referenceframe.StartPositions(fs),
+		referenceframe.NewPoseInFrame("marker", spatialmath.NewZeroPose()), referenceframe.World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(atZero.Pose(), expected.(*referenceframe.PoseInFrame).Pose()), test.ShouldBeTrue)
+
+	// the inputs are read from the arm on each query.
+	res, err := r.ResourceByName(arm.Named("arm1"))
+	test.That(t, err, test.ShouldBeNil)
+	moved := referenceframe.FloatsToInputs([]float64{0.5, -0.5, 0.5, 0, 0.5, 0})
+	test.That(t, res.(referenceframe.InputEnabled).GoToInputs(ctx, moved), test.ShouldBeNil)
+
+	inputs, err := fs.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, referenceframe.InputsToFloats(inputs["arm1"]), test.ShouldResemble, referenceframe.InputsToFloats(moved))
+	atMoved, err := fs.CurrentPose(ctx, "marker", referenceframe.World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(atMoved.Pose(), atZero.Pose()), test.ShouldBeFalse)
+	tf, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame("marker", spatialmath.NewZeroPose()),
+		referenceframe.World, []*referenceframe.LinkInFrame{marker})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(atMoved.Pose(), tf.Pose()), test.ShouldBeTrue)
+
+	// the links of the arm touch each other by design, and are not collisions.
+	collisions, err := fs.CheckCollisions(ctx, nil, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldBeEmpty)
+}
+
+func TestConfiguredObstacles(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	cfg := &config.Config{
+		Components: []resource.Config{
+			{
+				Name:                "arm1",
+				API:                 arm.API,
+				Model:               fakearm.Model,
+				Frame:               &referenceframe.LinkConfig{Parent: referenceframe.World},
+				ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+			},
+		},
+		Obstacles: []config.ObstacleConfig{
+			{
+				Name:       "pedestal",
+				Geometries: []spatialmath.GeometryConfig{{Type: spatialmath.BoxType, X: 200, Y: 200, Z: 200}},
+				Transforms: []referenceframe.LinkConfig{{ID: "table", Parent: referenceframe.World, Translation: r3.Vector{X: 500}}},
+			},
+		},
+	}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+	sets, err := r.(*localRobot).Obstacles().ObstacleSets(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sets, test.ShouldHaveLength, 1)
+	test.That(t, sets[0].Source, test.ShouldEqual, framesystem.ConfigObstacleSource)
+
+	// the frame system includes the configured transforms, and collisions the configured obstacles.
+	fs, err := r.(*localRobot).LiveFrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.Frame("table"), test.ShouldNotBeNil)
+	collisions, err := fs.CheckCollisions(ctx, nil, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldNotBeEmpty)
+	test.That(t, collisions[0].Geometry2, test.ShouldEqual, "pedestal_0")
+
+	cfg.Obstacles = nil
+	r.Reconfigure(ctx, cfg)
+	fs, err = r.(*localRobot).LiveFrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fs.Frame("table"), test.ShouldBeNil)
+	collisions, err = fs.CheckCollisions(ctx, nil, 0)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, collisions, test.ShouldBeEmpty)
+}
+
+func TestConfiguredKinematics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	armConf := resource.Config{
+		Name:                "arm1",
+		API:                 arm.API,
+		Model:               fakearm.Model,
+		Frame:               &referenceframe.LinkConfig{Parent: referenceframe.World},
+		ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+		Kinematics:          &resource.KinematicsConfig{JointOffsets: []float64{10, 0, -5, 0, 0, 0}},
+	}
+	r := setupLocalRobot(t, ctx, &config.Config{Components: []resource.Config{armConf}}, logger)
+
+	// the arm, and the frame system, are at the pose of its model at its joint positions plus
+	// the offsets.
+	builtin, err := ur.MakeModelFrame("arm1")
+	test.That(t, err, test.ShouldBeNil)
+	want, err := builtin.Transform(referenceframe.FloatsToInputs([]float64{rutils.DegToRad(10), 0, rutils.DegToRad(-5), 0, 0, 0}))
+	test.That(t, err, test.ShouldBeNil)
+	a, err := arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := a.EndPosition(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(pose, want), test.ShouldBeTrue)
+	fs, err := r.(*localRobot).LiveFrameSystem(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	pif, err := fs.CurrentPose(ctx, "arm1", referenceframe.World)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.PoseAlmostEqual(pif.Pose(), want), test.ShouldBeTrue)
+
+	// a kinematics file replaces the model of the arm, and is read again when it changes.
+	file := filepath.Join(t.TempDir(), "arm1.json")
+	copyKinematics := func(from string) {
+		t.Helper()
+		data, err := os.ReadFile(rutils.ResolveFile(from))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, os.WriteFile(file, data, 0o600), test.ShouldBeNil)
+	}
+	copyKinematics("components/arm/xarm/xarm7_kinematics.json")
+	armConf.Kinematics = &resource.KinematicsConfig{File: file}
+	r.Reconfigure(ctx, &config.Config{Components: []resource.Config{armConf}})
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Parts, test.ShouldHaveLength, 1)
+	test.That(t, fsCfg.Parts[0].ModelFrame.DoF(), test.ShouldHaveLength, 7)
+
+	lr := r.(*localRobot)
+	test.That(t, lr.manager.updateKinematics(), test.ShouldBeFalse)
+	copyKinematics("components/arm/xarm/xarm6_kinematics.json")
+	test.That(t, os.Chtimes(file, time.Now(), time.Now().Add(time.Second)), test.ShouldBeNil)
+	test.That(t, lr.manager.updateKinematics(), test.ShouldBeTrue)
+	lr.updateWeakDependents(ctx)
+	fsCfg, err = r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Parts[0].ModelFrame.DoF(), test.ShouldHaveLength, 6)
+
+	// a file that cannot be read leaves the model last read.
+	test.That(t, os.WriteFile(file, []byte("{"), 0o600), test.ShouldBeNil)
+	test.That(t, os.Chtimes(file, time.Now(), time.Now().Add(2*time.Second)), test.ShouldBeNil)
+	test.That(t, lr.manager.updateKinematics(), test.ShouldBeFalse)
+	a, err = arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, a.ModelFrame().DoF(), test.ShouldHaveLength, 6)
+
+	// self collision checking gives the links of the arm geometries that plans are checked with.
+	armConf.Kinematics = &resource.KinematicsConfig{
+		File:          rutils.ResolveFile("referenceframe/testjson/ur5eDH.json"),
+		SelfCollision: &resource.SelfCollisionConfig{},
+	}
+	r.Reconfigure(ctx, &config.Config{Components: []resource.Config{armConf}})
+	fsCfg, err = r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	geometries, err := fsCfg.Parts[0].ModelFrame.Geometries(make([]referenceframe.Input, 6))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, geometries.Geometries(), test.ShouldHaveLength, 6)
+}
+
+func TestOdometryFrameSystem(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+
+	// the fake movement sensor reports a fixed position, which is taken as the origin.
+	cfg := &config.Config{Components: []resource.Config{
+		{
+			Name:                "odom",
+			API:                 movementsensor.API,
+			Model:               fakeModel,
+			ConvertedAttributes: &fakemovementsensor.Config{},
+		},
+		{
+			Name:                "base1",
+			API:                 base.API,
+			Model:               fakeModel,
+			ConvertedAttributes: &fakebase.Config{},
+			Frame:               &referenceframe.LinkConfig{Parent: referenceframe.World, Translation: r3.Vector{X: 1000}},
+			Odometry:            &resource.OdometryConfig{MovementSensor: "odom", OriginLatitude: 40.7, OriginLongitude: -73.98},
+		},
+		{
+			Name:                "arm1",
+			API:                 arm.API,
+			Model:               fakeModel,
+			ConvertedAttributes: &fakearm.Config{ArmModel: "ur5e"},
+			Frame:               &referenceframe.LinkConfig{Parent: "base1", Translation: r3.Vector{Z: 100}},
+		},
+	}}
+	r := setupLocalRobot(t, ctx, cfg, logger)
+
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Odometry, test.ShouldResemble, map[string]resource.OdometryConfig{
+		"base1": {MovementSensor: "odom", OriginLatitude: 40.7, OriginLongitude: -73.98},
+	})
+
+	pif, err := r.TransformPose(ctx, referenceframe.NewPoseInFrame("base1", spatialmath.NewZeroPose()), referenceframe.World, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, spatialmath.R3VectorAlmostEqual(pif.Pose().Point(), r3.Vector{X: 1000}, 1e-3), test.ShouldBeTrue)
+}
@@ -1,6 +1,11 @@
 package robotimpl
 
 import (
+	"crypto/tls"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/lan"
 	"go.viam.com/rdk/robot/web"
 )
 
@@ -18,6 +23,26 @@ type options struct {
 
 	// shutdownCallback provides a callback for the robot to be able to shut itself down.
 	shutdownCallback func()
+
+	// logger is the logger of the robot, replacing the one it is created with.
+	logger logging.Logger
+
+	// tlsConfig is used to connect to remotes, replacing that of the config's network.
+	tlsConfig *tls.Config
+
+	// debug, allowInsecureCreds, untrustedEnv, readOnly and simulation enable what the config
+	// fields of the same name do even if the config does not.
+	debug              bool
+	allowInsecureCreds bool
+	untrustedEnv       bool
+	readOnly           bool
+	simulation         bool
+
+	// viewer is called with each new snapshot of the resource graph.
+	viewer func(resource.Snapshot)
+
+	// lanNetwork is the network robots are discovered on, in place of multicast DNS.
+	lanNetwork lan.Network
 }
 
 // Option configures how we set up the web service.
@@ -73,3 +98,76 @@ func WithShutdownCallback(shutdownFunc func()) Option {
 		o.shutdownCallback = shutdownFunc
 	})
 }
+
+// WithLogger returns an Option which sets the logger of the robot.
+func WithLogger(logger logging.Logger) Option {
+	return newFuncOption(func(o *options) {
+		o.logger = logger
+	})
+}
+
+// WithTLSConfig returns an Option which sets the TLS config used to connect
+// to remotes, in place of that of the config's network.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return newFuncOption(func(o *options) {
+		o.tlsConfig = tlsConfig
+	})
+}
+
+// WithDebug returns an Option which enables debugging of connections to
+// remotes, as if the config had debug set.
+func WithDebug() Option {
+	return newFuncOption(func(o *options) {
+		o.debug = true
+	})
+}
+
+// WithAllowInsecureCreds returns an Option which allows credentials to be
+// sent to remotes over insecure connections, as if the config had
+// allow_insecure_creds set.
+func WithAllowInsecureCreds() Option {
+	return newFuncOption(func(o *options) {
+		o.allowInsecureCreds = true
+	})
+}
+
+// WithUntrustedEnv returns an Option which disables processes and the shell
+// service, as if the robot ran in an untrusted environment.
+func WithUntrustedEnv() Option {
+	return newFuncOption(func(o *options) {
+		o.untrustedEnv = true
+	})
+}
+
+// WithReadOnly returns an Option which rejects the requests to the robot that
+// would actuate its resources, as if the config had read_only set.
+func WithReadOnly() Option {
+	return newFuncOption(func(o *options) {
+		o.readOnly = true
+	})
+}
+
+// WithSimulation returns an Option which builds the robot's resources with the simulated
+// backends registered for their models, as if the config had simulation set.
+func WithSimulation() Option {
+	return newFuncOption(func(o *options) {
+		o.simulation = true
+	})
+}
+
+// WithViewer returns an Option which calls viewer with each new DOT snapshot
+// of the robot's resource graph, taken whenever resources are added or
+// removed. It must not block.
+func WithViewer(viewer func(resource.Snapshot)) Option {
+	return newFuncOption(func(o *options) {
+		o.viewer = viewer
+	})
+}
+
+// WithLANNetwork returns an Option which advertises the robot and discovers remotes on the
+// given network in place of the local network reached with multicast DNS.
+func WithLANNetwork(network lan.Network) Option {
+	return newFuncOption(func(o *options) {
+		o.lanNetwork = network
+	})
+}
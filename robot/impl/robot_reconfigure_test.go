@@ -13,7 +13,6 @@ import (
 	"testing"
 	"time"
 
-	"github.com/a8m/envsubst"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.viam.com/test"
@@ -56,7 +55,7 @@ var (
 func ConfigFromFile(tb testing.TB, filePath string) *config.Config {
 	tb.Helper()
 	logger := logging.NewTestLogger(tb)
-	buf, err := envsubst.ReadFile(filePath)
+	buf, err := config.ReadFile(filePath, logger)
 	test.That(tb, err, test.ShouldBeNil)
 	conf, err := config.FromReader(context.Background(), filePath, bytes.NewReader(buf), logger)
 	test.That(tb, err, test.ShouldBeNil)
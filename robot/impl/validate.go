@@ -0,0 +1,295 @@
+package robotimpl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+)
+
+// IssueSeverity is how serious a problem found validating a config is.
+type IssueSeverity string
+
+const (
+	// IssueError is a problem that keeps part of the robot from being configured.
+	IssueError IssueSeverity = "error"
+	// IssueWarning is something that could not be checked without running the robot, such as
+	// the config of a resource provided by a module.
+	IssueWarning IssueSeverity = "warning"
+)
+
+// A ConfigIssue is a problem found validating a config.
+type ConfigIssue struct {
+	// Path locates the problem in the config (e.g. "components.2").
+	Path string `json:"path"`
+	// Resource names the resource with the problem, if any.
+	Resource string        `json:"resource,omitempty"`
+	Severity IssueSeverity `json:"severity"`
+	Message  string        `json:"message"`
+}
+
+// A ValidationReport lists the problems found validating a config.
+type ValidationReport struct {
+	Issues []ConfigIssue `json:"issues"`
+}
+
+// Valid returns whether no errors were found. Warnings are allowed.
+func (r ValidationReport) Valid() bool {
+	for _, issue := range r.Issues {
+		if issue.Severity == IssueError {
+			return false
+		}
+	}
+	return true
+}
+
+// Errors returns the errors found.
+func (r ValidationReport) Errors() []ConfigIssue {
+	var errs []ConfigIssue
+	for _, issue := range r.Issues {
+		if issue.Severity == IssueError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+func (r *ValidationReport) add(severity IssueSeverity, path, res string, err error) {
+	r.Issues = append(r.Issues, ConfigIssue{Path: path, Resource: res, Severity: severity, Message: err.Error()})
+}
+
+// Validate checks the given config as a robot would when configured with it, without
+// constructing any resource, so that configs can be checked before they are deployed. The
+// attributes of built-in models are converted and validated, the dependencies of resources
+// and the parents of their frames must exist, and the frame system must be a tree rooted at
+// the world frame. Resources whose models are not built in are assumed to be provided by
+// modules, whose validation needs the modules to run. The config is not modified.
+func Validate(ctx context.Context, cfg *config.Config) ValidationReport {
+	var report ValidationReport
+	validateTopLevel(cfg, &report)
+
+	components := append([]resource.Config(nil), cfg.Components...)
+	services := append([]resource.Config(nil), cfg.Services...)
+	names := map[string]bool{}
+	for _, name := range resource.DefaultServices() {
+		names[name.Name] = true
+		names[name.String()] = true
+	}
+	validateResources := func(confs []resource.Config, field, apiType string) {
+		for idx := range confs {
+			conf := &confs[idx]
+			path := fmt.Sprintf("%s.%d", field, idx)
+			conf.AdjustPartialNames(apiType)
+			resName := conf.ResourceName().String()
+			if names[resName] && !isDefaultService(conf.ResourceName()) {
+				report.add(IssueError, path, resName, errors.Errorf("duplicate resource %s in robot config", resName))
+			}
+			names[conf.Name] = true
+			names[resName] = true
+
+			reg, builtin := resource.LookupRegistration(conf.API, conf.Model)
+			switch {
+			case !builtin && len(cfg.Modules) == 0:
+				report.add(IssueError, path, resName, errors.Errorf("unknown model %s for %s", conf.Model, conf.API))
+			case !builtin:
+				report.add(IssueWarning, path, resName,
+					errors.Errorf("model %s is not built in; its attributes are validated by the module providing it", conf.Model))
+			case conf.ConvertedAttributes == nil && reg.AttributeMapConverter != nil:
+				converted, err := reg.AttributeMapConverter(conf.Attributes)
+				if err != nil {
+					report.add(IssueError, path, resName, errors.Wrap(err, "error converting attributes"))
+					continue
+				}
+				conf.ConvertedAttributes = converted
+			}
+
+			deps, err := conf.Validate(path, apiType)
+			if err != nil {
+				report.add(IssueError, path, resName, err)
+				continue
+			}
+			conf.ImplicitDependsOn = deps
+		}
+	}
+	validateResources(components, "components", resource.APITypeComponentName)
+	validateResources(services, "services", resource.APITypeServiceName)
+
+	remotes := map[string]bool{}
+	for _, remote := range cfg.Remotes {
+		remotes[remote.Name] = true
+	}
+	checkDependencies := func(confs []resource.Config, field string) {
+		for idx, conf := range confs {
+			path := fmt.Sprintf("%s.%d", field, idx)
+			for _, dep := range append(append([]string(nil), conf.DependsOn...), conf.ImplicitDependsOn...) {
+				if names[dep] {
+					continue
+				}
+				if remote, _, ok := strings.Cut(dep, ":"); ok && remotes[remote] {
+					report.add(IssueWarning, path, conf.ResourceName().String(),
+						errors.Errorf("dependency %q is provided by remote %q, which is not checked", dep, remote))
+					continue
+				}
+				report.add(IssueError, path, conf.ResourceName().String(),
+					errors.Errorf("dependency %q is not a configured resource", dep))
+			}
+		}
+	}
+	checkDependencies(components, "components")
+	checkDependencies(services, "services")
+
+	validateFrameSystem(components, len(cfg.Remotes) != 0, &report)
+	return report
+}
+
+func isDefaultService(name resource.Name) bool {
+	for _, defaultName := range resource.DefaultServices() {
+		if name == defaultName {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTopLevel validates the parts of the config other than its resources.
+func validateTopLevel(cfg *config.Config, report *ValidationReport) {
+	if cfg.Cloud != nil {
+		cloud := *cfg.Cloud
+		if err := cloud.Validate("cloud", false); err != nil {
+			report.add(IssueError, "cloud", "", err)
+		}
+	}
+	network := cfg.Network
+	if err := network.Validate("network"); err != nil {
+		report.add(IssueError, "network", "", err)
+	}
+	auth := cfg.Auth
+	if err := auth.Validate("auth"); err != nil {
+		report.add(IssueError, "auth", "", err)
+	}
+	for idx, module := range cfg.Modules {
+		if err := module.Validate(fmt.Sprintf("modules.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("modules.%d", idx), "", err)
+		}
+	}
+	for idx, remote := range cfg.Remotes {
+		if _, err := remote.Validate(fmt.Sprintf("remotes.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("remotes.%d", idx), "", err)
+		}
+	}
+	for idx, process := range cfg.Processes {
+		if err := process.Validate(fmt.Sprintf("processes.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("processes.%d", idx), "", err)
+		}
+	}
+	for idx, pkg := range cfg.Packages {
+		if err := pkg.Validate(fmt.Sprintf("packages.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("packages.%d", idx), "", err)
+		}
+	}
+	for idx, firmware := range cfg.Firmware {
+		if err := firmware.Validate(fmt.Sprintf("firmware.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("firmware.%d", idx), "", err)
+		}
+	}
+	for idx, fault := range cfg.Faults {
+		if err := fault.Validate(fmt.Sprintf("faults.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("faults.%d", idx), "", err)
+		}
+	}
+	if cfg.SelfTest != nil {
+		if err := cfg.SelfTest.Validate("self_test"); err != nil {
+			report.add(IssueError, "self_test", "", err)
+		}
+	}
+	if cfg.CallDeadlines != nil {
+		if err := cfg.CallDeadlines.Validate("call_deadlines"); err != nil {
+			report.add(IssueError, "call_deadlines", "", err)
+		}
+	}
+	for idx, loop := range cfg.ControlLoops {
+		if err := loop.Validate(fmt.Sprintf("control_loops.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("control_loops.%d", idx), "", err)
+		}
+	}
+	for idx, area := range cfg.MapTiles {
+		if err := area.Validate(fmt.Sprintf("map_tiles.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("map_tiles.%d", idx), "", err)
+		}
+	}
+	for idx, obstacles := range cfg.Obstacles {
+		if err := obstacles.Validate(fmt.Sprintf("obstacles.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("obstacles.%d", idx), "", err)
+		}
+	}
+	for idx, provider := range cfg.SecretProviders {
+		if err := provider.Validate(fmt.Sprintf("secret_providers.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("secret_providers.%d", idx), "", err)
+		}
+	}
+	for idx, profile := range cfg.Profiles {
+		if err := profile.Validate(fmt.Sprintf("profiles.%d", idx)); err != nil {
+			report.add(IssueError, fmt.Sprintf("profiles.%d", idx), "", err)
+		} else if _, err := cfg.WithProfile(profile.Name); err != nil {
+			report.add(IssueError, fmt.Sprintf("profiles.%d", idx), "", err)
+		}
+	}
+}
+
+// validateFrameSystem checks that the frames of the components form a tree rooted at the
+// world frame. Parents that are not local frames may be frames of remotes if there are any.
+func validateFrameSystem(components []resource.Config, hasRemotes bool, report *ValidationReport) {
+	var parts []*referenceframe.FrameSystemPart
+	paths := map[string]string{}
+	for idx, component := range components {
+		if component.Frame == nil {
+			continue
+		}
+		path := fmt.Sprintf("components.%d.frame", idx)
+		linkConf := *component.Frame
+		if linkConf.ID == "" {
+			linkConf.ID = component.Name
+		}
+		lif, err := linkConf.ParseConfig()
+		if err != nil {
+			report.add(IssueError, path, component.ResourceName().String(), err)
+			continue
+		}
+		parts = append(parts, &referenceframe.FrameSystemPart{FrameConfig: lif})
+		paths[lif.Name()] = path
+	}
+
+	remoteParents := map[string]bool{}
+	var missingParents bool
+	for _, part := range parts {
+		parent := part.FrameConfig.Parent()
+		if parent == referenceframe.World || paths[parent] != "" || remoteParents[parent] {
+			continue
+		}
+		name := part.FrameConfig.Name()
+		if !hasRemotes {
+			report.add(IssueError, paths[name], "", referenceframe.NewParentFrameMissingError(name, parent))
+			missingParents = true
+			continue
+		}
+		report.add(IssueWarning, paths[name], "",
+			errors.Errorf("parent frame %q of frame %q is not local, so it must be a frame of a remote", parent, name))
+		remoteParents[parent] = true
+	}
+	for parent := range remoteParents {
+		parts = append(parts, &referenceframe.FrameSystemPart{
+			FrameConfig: referenceframe.NewLinkInFrame(referenceframe.World, nil, parent, nil),
+		})
+	}
+	if missingParents {
+		return
+	}
+	if _, err := referenceframe.TopologicallySortParts(parts); err != nil {
+		report.add(IssueError, "components", "", errors.Wrap(err, "invalid frame system"))
+	}
+}
@@ -0,0 +1,81 @@
+package robotimpl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+)
+
+func TestValidate(t *testing.T) {
+	ctx := context.Background()
+	parse := func(raw string) *config.Config {
+		var cfg config.Config
+		test.That(t, json.Unmarshal([]byte(raw), &cfg), test.ShouldBeNil)
+		return &cfg
+	}
+
+	valid := parse(`{
+		"components": [
+			{"name": "arm1", "type": "arm", "model": "fake", "attributes": {"arm-model": "ur5e"}, "frame": {"parent": "world"}},
+			{"name": "gripper1", "type": "gripper", "model": "fake", "frame": {"parent": "arm1"}},
+			{"name": "motor1", "type": "motor", "model": "fake", "depends_on": ["arm1"]}
+		]
+	}`)
+	report := Validate(ctx, valid)
+	test.That(t, report.Issues, test.ShouldBeEmpty)
+	test.That(t, report.Valid(), test.ShouldBeTrue)
+	// the config is not modified.
+	test.That(t, valid.Components[0].ConvertedAttributes, test.ShouldBeNil)
+
+	invalid := parse(`{
+		"components": [
+			{"name": "arm1", "type": "arm", "model": "fake", "frame": {"parent": "base1"}},
+			{"name": "motor1", "type": "motor", "model": "fake", "attributes": {"max_rpm": "fast"}},
+			{"name": "motor2", "type": "motor", "model": "fake", "depends_on": ["board1"]},
+			{"name": "camera1", "type": "camera", "model": "acme:cameras:nope"},
+			{"name": "motor2", "type": "motor", "model": "fake"}
+		],
+		"control_loops": [{"name": "hold"}]
+	}`)
+	report = Validate(ctx, invalid)
+	test.That(t, report.Valid(), test.ShouldBeFalse)
+	messages := map[string]string{}
+	for _, issue := range report.Errors() {
+		messages[issue.Path] += issue.Message
+	}
+	test.That(t, messages["components.0.frame"], test.ShouldContainSubstring, "base1")
+	test.That(t, messages["components.1"], test.ShouldContainSubstring, "converting attributes")
+	test.That(t, messages["components.2"], test.ShouldContainSubstring, `dependency "board1"`)
+	test.That(t, messages["components.3"], test.ShouldContainSubstring, "unknown model")
+	test.That(t, messages["components.4"], test.ShouldContainSubstring, "duplicate")
+	test.That(t, messages["control_loops.0"], test.ShouldContainSubstring, "frequency_hz")
+
+	// models provided by modules and frames of remotes are warned about instead.
+	modular := parse(`{
+		"modules": [{"name": "cams", "executable_path": "/usr/bin/cams"}],
+		"remotes": [{"name": "other", "address": "other.local:8080"}],
+		"components": [
+			{"name": "camera1", "type": "camera", "model": "acme:cameras:nope", "frame": {"parent": "other:arm"}}
+		]
+	}`)
+	report = Validate(ctx, modular)
+	test.That(t, report.Valid(), test.ShouldBeTrue)
+	test.That(t, report.Issues, test.ShouldHaveLength, 2)
+	for _, issue := range report.Issues {
+		test.That(t, issue.Severity, test.ShouldEqual, IssueWarning)
+	}
+
+	cyclic := parse(`{
+		"components": [
+			{"name": "arm1", "type": "arm", "model": "fake", "frame": {"parent": "arm2"}},
+			{"name": "arm2", "type": "arm", "model": "fake", "frame": {"parent": "arm1"}}
+		]
+	}`)
+	report = Validate(ctx, cyclic)
+	test.That(t, report.Errors(), test.ShouldHaveLength, 1)
+	test.That(t, report.Errors()[0].Message, test.ShouldContainSubstring, "invalid frame system")
+}
@@ -0,0 +1,53 @@
+package robotimpl
+
+import (
+	"go.uber.org/multierr"
+)
+
+// restartResource closes the local resources with the given short name, one of whose
+// requests exceeded its operation timeout, so that they and their dependents are rebuilt by
+// the next configuration attempt. Restarts already under way are not repeated.
+func (r *localRobot) restartResource(shortName string) {
+	r.restartingMu.Lock()
+	if r.restarting[shortName] || r.closeContext.Err() != nil {
+		r.restartingMu.Unlock()
+		return
+	}
+	r.restarting[shortName] = true
+	r.restartingMu.Unlock()
+
+	r.activeBackgroundWorkers.Add(1)
+	go func() {
+		defer r.activeBackgroundWorkers.Done()
+		defer func() {
+			r.restartingMu.Lock()
+			delete(r.restarting, shortName)
+			r.restartingMu.Unlock()
+		}()
+
+		ctx := r.closeContext
+		manager := r.manager
+		manager.configLock.Lock()
+		var allErrs error
+		for _, name := range manager.resources.Names() {
+			if name.ShortName() != shortName || name.ContainsRemoteNames() {
+				continue
+			}
+			gNode, ok := manager.resources.Node(name)
+			if !ok || gNode.IsUninitialized() || gNode.MarkedForRemoval() {
+				continue
+			}
+			r.logger.CWarnw(ctx, "restarting resource after an operation exceeded its timeout", "resource", name)
+			allErrs = multierr.Combine(allErrs, manager.closeForRebuild(ctx, name, gNode))
+		}
+		manager.configLock.Unlock()
+		if allErrs != nil {
+			r.logger.CWarnw(ctx, "errors encountered while restarting resource", "resource", shortName, "error", allErrs)
+		}
+
+		select {
+		case <-ctx.Done():
+		case r.triggerConfig <- struct{}{}:
+		}
+	}()
+}
@@ -0,0 +1,126 @@
+// Package kinematics solves the inverse kinematics of the components of a robot's frame system
+// on request, returning several solutions, so that callers can choose between configurations
+// reaching the same pose, such as elbow up and elbow down, before committing to a motion.
+package kinematics
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+)
+
+// SolveOptions tune how inverse kinematics are solved.
+type SolveOptions struct {
+	// Solver is the name of the solver to use: "nlopt", the default, which solves any frame, or
+	// "analytic", for the kinematic models with a closed-form solver registered.
+	Solver string
+	// MaxSolutions is how many distinct solutions to return at most. Defaults to 8.
+	MaxSolutions int
+	// Timeout bounds how long solutions are looked for. Defaults to 5 seconds.
+	Timeout time.Duration
+}
+
+// A Solution is a configuration of the inputs of a frame reaching a goal.
+type Solution struct {
+	Inputs []float64 `json:"inputs"`
+	// Score is how far the configuration is from the goal, by the solver's metric.
+	Score float64 `json:"score"`
+}
+
+// A Service solves the inverse kinematics of the components of a robot's frame system.
+type Service interface {
+	// Solve returns the configurations of the inputs of the named frame, such as the joint
+	// positions of an arm, that put its end at the goal, sorted by their distance from the
+	// seed, nearest first. The seed defaults to the current inputs of the frame, and options to
+	// the defaults of SolveOptions if nil.
+	Solve(
+		ctx context.Context,
+		goal *referenceframe.PoseInFrame,
+		seed []referenceframe.Input,
+		frame string,
+		opts *SolveOptions,
+	) ([]Solution, error)
+}
+
+// A Solver solves inverse kinematics in a robot's frame system.
+type Solver struct {
+	logger logging.Logger
+	liveFS func(ctx context.Context) (*framesystem.LiveFrameSystem, error)
+}
+
+var _ Service = (*Solver)(nil)
+
+// NewSolver returns a solver of the frame system returned by liveFS, which is read on each
+// request.
+func NewSolver(logger logging.Logger, liveFS func(ctx context.Context) (*framesystem.LiveFrameSystem, error)) *Solver {
+	return &Solver{logger: logger, liveFS: liveFS}
+}
+
+// Solve returns the configurations of the inputs of the named frame, such as the joint
+// positions of an arm, that put its end at the goal, sorted by their distance from the seed,
+// nearest first. The goal may be in any frame of the frame system, and is transformed at its
+// current inputs. The other frames are not moved to reach it.
+func (s *Solver) Solve(
+	ctx context.Context,
+	goal *referenceframe.PoseInFrame,
+	seed []referenceframe.Input,
+	frameName string,
+	opts *SolveOptions,
+) ([]Solution, error) {
+	if goal == nil {
+		return nil, errors.New("goal is required")
+	}
+	if opts == nil {
+		opts = &SolveOptions{}
+	}
+	fs, err := s.liveFS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	frame := fs.Frame(frameName)
+	if frame == nil {
+		return nil, referenceframe.NewFrameMissingError(frameName)
+	}
+	parent, err := fs.Parent(frame)
+	if err != nil {
+		return nil, err
+	}
+	inputs, err := fs.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		seed = inputs[frameName]
+	}
+	tf, err := fs.Transform(inputs, goal, parent.Name())
+	if err != nil {
+		return nil, err
+	}
+
+	solutions, err := motionplan.SolveIK(ctx, &motionplan.IKRequest{
+		Logger:       s.logger,
+		Frame:        frame,
+		Goal:         tf.(*referenceframe.PoseInFrame).Pose(),
+		Seed:         seed,
+		Solver:       opts.Solver,
+		MaxSolutions: opts.MaxSolutions,
+		Timeout:      opts.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]Solution, 0, len(solutions))
+	for _, solution := range solutions {
+		results = append(results, Solution{
+			Inputs: referenceframe.InputsToFloats(solution.Configuration),
+			Score:  solution.Score,
+		})
+	}
+	return results, nil
+}
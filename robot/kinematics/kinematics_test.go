@@ -0,0 +1,172 @@
+package kinematics_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan/ik"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/kinematics"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// spinnerJSON is a single joint turning a 100mm link about the z axis, which reaches each
+// point of its circle both turning left and turning right.
+const spinnerJSON = `{
+	"name": "spinner",
+	"links": [
+		{"id": "base", "parent": "world"},
+		{"id": "tip", "parent": "joint", "translation": {"x": 100, "y": 0, "z": 0}}
+	],
+	"joints": [
+		{"id": "joint", "type": "revolute", "parent": "base", "axis": {"x": 0, "y": 0, "z": 1}, "min": -360, "max": 360}
+	]
+}`
+
+// spinnerSolver solves the spinner in closed form.
+type spinnerSolver struct {
+	referenceframe.Frame
+}
+
+func (s *spinnerSolver) Solve(
+	ctx context.Context,
+	c chan<- *ik.Solution,
+	seed []referenceframe.Input,
+	m ik.StateMetric,
+	rseed int,
+) error {
+	// the metric is zero at the goal, so the angle reaching it is found by sampling the metric.
+	best, bestScore := 0., math.Inf(1)
+	for deg := -180.; deg < 180; deg += 0.5 {
+		inputs := []referenceframe.Input{{Value: deg * math.Pi / 180}}
+		pose, err := s.Transform(inputs)
+		if err != nil {
+			return err
+		}
+		if score := m(&ik.State{Configuration: inputs, Position: pose, Frame: s}); score < bestScore {
+			best, bestScore = inputs[0].Value, score
+		}
+	}
+	other := best - 2*math.Pi
+	if best < 0 {
+		other = best + 2*math.Pi
+	}
+	for _, angle := range []float64{best, best, other} {
+		select {
+		case c <- &ik.Solution{Configuration: []referenceframe.Input{{Value: angle}}, Exact: true}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func init() {
+	ik.RegisterAnalyticSolver("spinner", func(model referenceframe.Frame, logger logging.Logger) (ik.InverseKinematics, error) {
+		return &spinnerSolver{Frame: model}, nil
+	})
+}
+
+func spinnerFrameSystem(t *testing.T) framesystem.Service {
+	t.Helper()
+	model, err := referenceframe.UnmarshalModelJSON([]byte(spinnerJSON), "spinner1")
+	test.That(t, err, test.ShouldBeNil)
+	lif := referenceframe.NewLinkInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: 500}), "spinner1", nil)
+	fs, err := referenceframe.NewFrameSystem("test", []*referenceframe.FrameSystemPart{{FrameConfig: lif, ModelFrame: model}}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	svc := inject.NewFrameSystemService("test")
+	svc.FrameSystemFunc = func(ctx context.Context, additionalTransforms []*referenceframe.LinkInFrame) (referenceframe.FrameSystem, error) {
+		return fs, nil
+	}
+	svc.CurrentInputsFunc = func(ctx context.Context) (map[string][]referenceframe.Input, map[string]referenceframe.InputEnabled, error) {
+		return map[string][]referenceframe.Input{"spinner1": {{Value: 0.1}}}, nil, nil
+	}
+	return svc
+}
+
+func TestSolve(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	svc := spinnerFrameSystem(t)
+	solver := kinematics.NewSolver(logger, func(ctx context.Context) (*framesystem.LiveFrameSystem, error) {
+		return framesystem.NewLiveFrameSystem(ctx, svc, nil)
+	})
+
+	// the goal is given in the world frame, and solved in the frame of the spinner's base.
+	goal := referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPose(
+		r3.Vector{X: 500, Y: 100},
+		&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 90},
+	))
+	solutions, err := solver.Solve(ctx, goal, nil, "spinner1", &kinematics.SolveOptions{Solver: ik.AnalyticSolver})
+	test.That(t, err, test.ShouldBeNil)
+	// duplicates are dropped, and the solution nearest the current inputs comes first.
+	test.That(t, solutions, test.ShouldHaveLength, 2)
+	test.That(t, solutions[0].Inputs[0], test.ShouldAlmostEqual, math.Pi/2)
+	test.That(t, solutions[1].Inputs[0], test.ShouldAlmostEqual, -3*math.Pi/2)
+
+	// the seed orders the solutions.
+	solutions, err = solver.Solve(ctx, goal, []referenceframe.Input{{Value: -4}}, "spinner1",
+		&kinematics.SolveOptions{Solver: ik.AnalyticSolver})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, solutions[0].Inputs[0], test.ShouldAlmostEqual, -3*math.Pi/2)
+
+	solutions, err = solver.Solve(ctx, goal, nil, "spinner1", &kinematics.SolveOptions{Solver: ik.AnalyticSolver, MaxSolutions: 1})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, solutions, test.ShouldHaveLength, 1)
+
+	_, err = solver.Solve(ctx, goal, nil, "spinner1", &kinematics.SolveOptions{Solver: "guess"})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unknown IK solver")
+	_, err = solver.Solve(ctx, goal, []referenceframe.Input{{}, {}}, "spinner1", &kinematics.SolveOptions{Solver: ik.AnalyticSolver})
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = solver.Solve(ctx, goal, nil, "missing", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	_, err = solver.Solve(ctx, goal, nil, "spinner1_origin", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no inputs to solve for")
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	svc := spinnerFrameSystem(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		LiveFrameSystemFunc: func(
+			ctx context.Context,
+			additionalTransforms []*referenceframe.LinkInFrame,
+		) (*framesystem.LiveFrameSystem, error) {
+			return framesystem.NewLiveFrameSystem(ctx, svc, additionalTransforms)
+		},
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	goal := referenceframe.NewPoseInFrame("spinner1_origin", spatialmath.NewPose(
+		r3.Vector{Y: -100},
+		&spatialmath.OrientationVectorDegrees{OZ: 1, Theta: -90},
+	))
+	solutions, err := robotClient.Kinematics().Solve(ctx, goal, nil, "spinner1", &kinematics.SolveOptions{Solver: ik.AnalyticSolver})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, solutions, test.ShouldHaveLength, 2)
+	test.That(t, solutions[0].Inputs[0], test.ShouldAlmostEqual, -math.Pi/2)
+
+	_, err = robotClient.Kinematics().Solve(ctx, goal, nil, "missing", nil)
+	test.That(t, err, test.ShouldNotBeNil)
+}
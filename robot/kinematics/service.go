@@ -0,0 +1,132 @@
+package kinematics
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ServiceName is the name of the gRPC service solving inverse kinematics. Its requests and
+// responses are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.kinematics.v1.KinematicsService"
+
+// poseConfig is the JSON form of a pose in a frame.
+type poseConfig struct {
+	Parent      string                         `json:"parent"`
+	Translation r3.Vector                      `json:"translation"`
+	Orientation *spatialmath.OrientationConfig `json:"orientation,omitempty"`
+}
+
+type request struct {
+	Frame        string      `json:"frame"`
+	Goal         *poseConfig `json:"goal,omitempty"`
+	Seed         []float64   `json:"seed,omitempty"`
+	Solver       string      `json:"solver,omitempty"`
+	MaxSolutions int         `json:"max_solutions,omitempty"`
+	Timeout      string      `json:"timeout,omitempty"`
+}
+
+type solveResponse struct {
+	Solutions []Solution `json:"solutions"`
+}
+
+// A Server serves inverse kinematics with ServiceDesc.
+type Server struct {
+	kinematics Service
+}
+
+// NewServer returns a server for the given inverse kinematics.
+func NewServer(kinematics Service) *Server {
+	return &Server{kinematics: kinematics}
+}
+
+func (s *Server) solve(ctx context.Context, req request) (interface{}, error) {
+	if req.Goal == nil {
+		return nil, status.Error(codes.InvalidArgument, "goal is required")
+	}
+	pose := spatialmath.NewPoseFromPoint(req.Goal.Translation)
+	if req.Goal.Orientation != nil {
+		orientation, err := req.Goal.Orientation.ParseConfig()
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid goal orientation: %v", err)
+		}
+		pose = spatialmath.NewPose(req.Goal.Translation, orientation)
+	}
+	opts := &SolveOptions{Solver: req.Solver, MaxSolutions: req.MaxSolutions}
+	if req.Timeout != "" {
+		var err error
+		if opts.Timeout, err = time.ParseDuration(req.Timeout); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid timeout: %v", err)
+		}
+	}
+	var seed []referenceframe.Input
+	if req.Seed != nil {
+		seed = referenceframe.FloatsToInputs(req.Seed)
+	}
+	solutions, err := s.kinematics.Solve(ctx, referenceframe.NewPoseInFrame(req.Goal.Parent, pose), seed, req.Frame, opts)
+	return solveResponse{Solutions: solutions}, err
+}
+
+// ServiceDesc describes the gRPC service solving inverse kinematics. It is served with a
+// Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/kinematics",
+	structrpc.Unary("Solve", (*Server).solve),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the inverse kinematics served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Solve returns the configurations of the inputs of the named frame, such as the joint
+// positions of an arm, that put its end at the goal, sorted by their distance from the seed,
+// nearest first. The seed defaults to the current inputs of the frame.
+func (c *Client) Solve(
+	ctx context.Context,
+	goal *referenceframe.PoseInFrame,
+	seed []referenceframe.Input,
+	frame string,
+	opts *SolveOptions,
+) ([]Solution, error) {
+	if goal == nil {
+		return nil, status.Error(codes.InvalidArgument, "goal is required")
+	}
+	orientation, err := spatialmath.NewOrientationConfig(goal.Pose().Orientation())
+	if err != nil {
+		return nil, err
+	}
+	req := request{
+		Frame: frame,
+		Goal:  &poseConfig{Parent: goal.Parent(), Translation: goal.Pose().Point(), Orientation: orientation},
+	}
+	if seed != nil {
+		req.Seed = referenceframe.InputsToFloats(seed)
+	}
+	if opts != nil {
+		req.Solver = opts.Solver
+		req.MaxSolutions = opts.MaxSolutions
+		if opts.Timeout > 0 {
+			req.Timeout = opts.Timeout.String()
+		}
+	}
+	var resp solveResponse
+	if err := c.client.Invoke(ctx, "Solve", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Solutions, nil
+}
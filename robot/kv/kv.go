@@ -0,0 +1,353 @@
+// Package kv is a small persistent key-value store for state local to a robot, with keys
+// grouped in namespaces such as the name of the module or application owning them. The
+// store of a robot is available to code running in the robot process, to modules and to
+// remote clients, so that applications need not keep state in their own files.
+//
+// A Store keeps its values in a bbolt database, with a bucket for each namespace, and a copy
+// of them in memory to serve reads. A change is on disk once the call making it returns. The
+// file of a store is locked while the store is open, so it cannot be shared by several
+// processes.
+package kv
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	bolt "go.etcd.io/bbolt"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+)
+
+const (
+	// FileName is the name of the file holding a robot's store in its Viam home directory.
+	FileName = "kv.db"
+
+	// openTimeout is how long opening a store waits for another process to close its file.
+	openTimeout = time.Second
+	// watchBuffer is how many events a watcher may fall behind before its watch ends.
+	watchBuffer = 64
+)
+
+var errClosed = errors.New("key-value store is closed")
+
+// An Event is a change to a key.
+type Event struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key"`
+	// Value is the new value of the key, unless it was deleted.
+	Value   []byte `json:"value,omitempty"`
+	Deleted bool   `json:"deleted,omitempty"`
+}
+
+// A Service gets, sets and watches the keys of a store, either directly or over a
+// connection to a robot.
+type Service interface {
+	// Get returns the value of the key and whether it is set.
+	Get(ctx context.Context, namespace, key string) ([]byte, bool, error)
+	// Set sets the value of the key.
+	Set(ctx context.Context, namespace, key string, value []byte) error
+	// Delete deletes the key. Deleting a key that is not set does nothing.
+	Delete(ctx context.Context, namespace, key string) error
+	// Keys returns the keys set in the namespace, sorted.
+	Keys(ctx context.Context, namespace string) ([]string, error)
+	// Watch returns the changes to the key, or to every key of the namespace if the key is
+	// empty, made from now on. The channel is closed when the context is done, when the
+	// store is closed or when the watcher falls too far behind; callers that need every
+	// change should then Get the keys they care about and Watch again.
+	Watch(ctx context.Context, namespace, key string) (<-chan Event, error)
+}
+
+// A Robot is a robot with a persistent key-value store.
+type Robot interface {
+	// KV returns the persistent key-value store of the robot, which is also served to
+	// modules and remote clients.
+	KV() *Store
+}
+
+// A Store is the Service of a robot, kept in a file.
+type Store struct {
+	path   string
+	logger logging.Logger
+
+	mu     sync.Mutex
+	loaded bool
+	closed bool
+	db     *bolt.DB
+	// data holds every value of the store, so that reads need not go to disk.
+	data     map[string]map[string][]byte
+	watchers map[*watcher]struct{}
+}
+
+var _ Service = (*Store)(nil)
+
+type watcher struct {
+	namespace string
+	key       string
+	events    chan Event
+	done      chan struct{}
+}
+
+func (w *watcher) matches(ev Event) bool {
+	return w.namespace == ev.Namespace && (w.key == "" || w.key == ev.Key)
+}
+
+// NewStore returns a store kept in the file at the given path, which is created when the
+// store is first used. A store with an empty path is only kept in memory.
+func NewStore(path string, logger logging.Logger) *Store {
+	return &Store{
+		path:     path,
+		logger:   logger,
+		data:     map[string]map[string][]byte{},
+		watchers: map[*watcher]struct{}{},
+	}
+}
+
+func validate(namespace, key string, keyRequired bool) error {
+	if namespace == "" {
+		return status.Error(codes.InvalidArgument, "namespace must not be empty")
+	}
+	if keyRequired && key == "" {
+		return status.Error(codes.InvalidArgument, "key must not be empty")
+	}
+	return nil
+}
+
+// load opens the database of the store the first time it is used. It must be called with
+// the store locked.
+func (s *Store) load() error {
+	if s.closed {
+		return errClosed
+	}
+	if s.loaded {
+		return nil
+	}
+	if s.path == "" {
+		s.loaded = true
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	db, err := bolt.Open(s.path, 0o600, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return errors.Wrapf(err, "failed to open key-value store %q", s.path)
+	}
+	data := map[string]map[string][]byte{}
+	if err := db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(namespace []byte, bucket *bolt.Bucket) error {
+			keys := map[string][]byte{}
+			data[string(namespace)] = keys
+			return bucket.ForEach(func(key, value []byte) error {
+				// values are only valid for the life of the transaction.
+				keys[string(key)] = append([]byte{}, value...)
+				return nil
+			})
+		})
+	}); err != nil {
+		return multierr.Combine(errors.Wrapf(err, "failed to read key-value store %q", s.path), db.Close())
+	}
+	s.db = db
+	s.data = data
+	s.loaded = true
+	return nil
+}
+
+func apply(data map[string]map[string][]byte, ev Event) {
+	if ev.Deleted {
+		delete(data[ev.Namespace], ev.Key)
+		if len(data[ev.Namespace]) == 0 {
+			delete(data, ev.Namespace)
+		}
+		return
+	}
+	if data[ev.Namespace] == nil {
+		data[ev.Namespace] = map[string][]byte{}
+	}
+	data[ev.Namespace][ev.Key] = ev.Value
+}
+
+// commit writes the change to the database, where each namespace is a bucket. The change
+// is on disk once commit returns, and not made at all if it fails.
+func (s *Store) commit(ev Event) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		namespace := []byte(ev.Namespace)
+		if ev.Deleted {
+			bucket := tx.Bucket(namespace)
+			if bucket == nil {
+				return nil
+			}
+			if err := bucket.Delete([]byte(ev.Key)); err != nil {
+				return err
+			}
+			if key, _ := bucket.Cursor().First(); key == nil {
+				return tx.DeleteBucket(namespace)
+			}
+			return nil
+		}
+		bucket, err := tx.CreateBucketIfNotExists(namespace)
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(ev.Key), ev.Value)
+	})
+}
+
+// write commits, applies and notifies the change. It must be called with the store locked
+// and loaded.
+func (s *Store) write(ev Event) error {
+	if s.db != nil {
+		if err := s.commit(ev); err != nil {
+			return errors.Wrapf(err, "failed to write to key-value store %q", s.path)
+		}
+	}
+	apply(s.data, ev)
+	for w := range s.watchers {
+		if !w.matches(ev) {
+			continue
+		}
+		select {
+		case w.events <- copyEvent(ev):
+		default:
+			s.logger.Debugw("key-value watcher fell behind; ending its watch",
+				"namespace", w.namespace, "key", w.key)
+			s.removeWatcher(w)
+		}
+	}
+	return nil
+}
+
+func copyEvent(ev Event) Event {
+	if ev.Value != nil {
+		ev.Value = append([]byte(nil), ev.Value...)
+	}
+	return ev
+}
+
+// Get returns the value of the key and whether it is set.
+func (s *Store) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	if err := validate(namespace, key, true); err != nil {
+		return nil, false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return nil, false, err
+	}
+	value, ok := s.data[namespace][key]
+	if !ok {
+		return nil, false, nil
+	}
+	return append([]byte{}, value...), true, nil
+}
+
+// Set sets the value of the key.
+func (s *Store) Set(ctx context.Context, namespace, key string, value []byte) error {
+	if err := validate(namespace, key, true); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	return s.write(Event{Namespace: namespace, Key: key, Value: append([]byte{}, value...)})
+}
+
+// Delete deletes the key. Deleting a key that is not set does nothing.
+func (s *Store) Delete(ctx context.Context, namespace, key string) error {
+	if err := validate(namespace, key, true); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	if _, ok := s.data[namespace][key]; !ok {
+		return nil
+	}
+	return s.write(Event{Namespace: namespace, Key: key, Deleted: true})
+}
+
+// Keys returns the keys set in the namespace, sorted.
+func (s *Store) Keys(ctx context.Context, namespace string) ([]string, error) {
+	if err := validate(namespace, "", false); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(s.data[namespace]))
+	for key := range s.data[namespace] {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Watch returns the changes to the key, or to every key of the namespace if the key is
+// empty, made from now on.
+func (s *Store) Watch(ctx context.Context, namespace, key string) (<-chan Event, error) {
+	if err := validate(namespace, key, false); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	w := &watcher{
+		namespace: namespace,
+		key:       key,
+		events:    make(chan Event, watchBuffer),
+		done:      make(chan struct{}),
+	}
+	s.watchers[w] = struct{}{}
+	go func() {
+		select {
+		case <-ctx.Done():
+			s.mu.Lock()
+			s.removeWatcher(w)
+			s.mu.Unlock()
+		case <-w.done:
+		}
+	}()
+	return w.events, nil
+}
+
+// removeWatcher ends the watch. It must be called with the store locked.
+func (s *Store) removeWatcher(w *watcher) {
+	if _, ok := s.watchers[w]; !ok {
+		return
+	}
+	delete(s.watchers, w)
+	close(w.events)
+	close(w.done)
+}
+
+// Close ends every watch and closes the file of the store.
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	for w := range s.watchers {
+		s.removeWatcher(w)
+	}
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
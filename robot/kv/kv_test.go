@@ -0,0 +1,186 @@
+package kv_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func nextEvent(t *testing.T, events <-chan kv.Event) kv.Event {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		test.That(t, ok, test.ShouldBeTrue)
+		return ev
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for event")
+		return kv.Event{}
+	}
+}
+
+// testService checks the behavior every Service shares.
+func testService(t *testing.T, svc kv.Service) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, found, err := svc.Get(ctx, "app", "count")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, found, test.ShouldBeFalse)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keyEvents, err := svc.Watch(watchCtx, "app", "count")
+	test.That(t, err, test.ShouldBeNil)
+	namespaceEvents, err := svc.Watch(watchCtx, "app", "")
+	test.That(t, err, test.ShouldBeNil)
+
+	test.That(t, svc.Set(ctx, "app", "count", []byte("1")), test.ShouldBeNil)
+	test.That(t, svc.Set(ctx, "app", "mode", []byte{}), test.ShouldBeNil)
+	test.That(t, svc.Set(ctx, "other", "count", []byte("2")), test.ShouldBeNil)
+	test.That(t, svc.Delete(ctx, "app", "count"), test.ShouldBeNil)
+	test.That(t, svc.Delete(ctx, "app", "missing"), test.ShouldBeNil)
+
+	value, found, err := svc.Get(ctx, "app", "mode")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, found, test.ShouldBeTrue)
+	test.That(t, value, test.ShouldResemble, []byte{})
+	value, found, err = svc.Get(ctx, "other", "count")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, found, test.ShouldBeTrue)
+	test.That(t, value, test.ShouldResemble, []byte("2"))
+	keys, err := svc.Keys(ctx, "app")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, keys, test.ShouldResemble, []string{"mode"})
+
+	test.That(t, nextEvent(t, keyEvents), test.ShouldResemble,
+		kv.Event{Namespace: "app", Key: "count", Value: []byte("1")})
+	test.That(t, nextEvent(t, keyEvents), test.ShouldResemble,
+		kv.Event{Namespace: "app", Key: "count", Deleted: true})
+	test.That(t, nextEvent(t, namespaceEvents).Key, test.ShouldEqual, "count")
+	test.That(t, nextEvent(t, namespaceEvents).Key, test.ShouldEqual, "mode")
+	test.That(t, nextEvent(t, namespaceEvents).Deleted, test.ShouldBeTrue)
+
+	// a watch ends with its context.
+	cancel()
+	for range keyEvents {
+	}
+
+	err = svc.Set(ctx, "", "count", []byte("1"))
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	_, _, err = svc.Get(ctx, "app", "")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
+
+func TestStore(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state", kv.FileName)
+
+	store := kv.NewStore(path, logger)
+	testService(t, store)
+	test.That(t, store.Close(), test.ShouldBeNil)
+	_, _, err := store.Get(ctx, "app", "mode")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	t.Run("values persist", func(t *testing.T) {
+		store := kv.NewStore(path, logger)
+		defer func() {
+			test.That(t, store.Close(), test.ShouldBeNil)
+		}()
+		keys, err := store.Keys(ctx, "app")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, keys, test.ShouldResemble, []string{"mode"})
+		value, _, err := store.Get(ctx, "other", "count")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, value, test.ShouldResemble, []byte("2"))
+	})
+
+	t.Run("file is locked while open", func(t *testing.T) {
+		store := kv.NewStore(path, logger)
+		defer func() {
+			test.That(t, store.Close(), test.ShouldBeNil)
+		}()
+		test.That(t, store.Set(ctx, "app", "next", []byte("3")), test.ShouldBeNil)
+
+		other := kv.NewStore(path, logger)
+		_, _, err := other.Get(ctx, "app", "next")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, other.Close(), test.ShouldBeNil)
+	})
+
+	t.Run("overwritten values do not grow the file", func(t *testing.T) {
+		store := kv.NewStore(path, logger)
+		for i := 0; i < 1000; i++ {
+			test.That(t, store.Set(ctx, "counter", "value", []byte(fmt.Sprint(i))), test.ShouldBeNil)
+		}
+		test.That(t, store.Close(), test.ShouldBeNil)
+		info, err := os.Stat(path)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, info.Size(), test.ShouldBeLessThan, 100*1024)
+
+		store = kv.NewStore(path, logger)
+		defer func() {
+			test.That(t, store.Close(), test.ShouldBeNil)
+		}()
+		value, _, err := store.Get(ctx, "counter", "value")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, value, test.ShouldResemble, []byte("999"))
+		keys, err := store.Keys(ctx, "app")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, keys, test.ShouldResemble, []string{"mode", "next"})
+	})
+}
+
+func TestSlowWatcher(t *testing.T) {
+	ctx := context.Background()
+	store := kv.NewStore("", logging.NewTestLogger(t))
+	defer func() {
+		test.That(t, store.Close(), test.ShouldBeNil)
+	}()
+	events, err := store.Watch(ctx, "app", "")
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 100; i++ {
+		test.That(t, store.Set(ctx, "app", "count", []byte(fmt.Sprint(i))), test.ShouldBeNil)
+	}
+	received := 0
+	for range events {
+		received++
+	}
+	test.That(t, received, test.ShouldBeLessThan, 100)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(nil)
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	testService(t, robotClient.KV())
+	// changes made over the client are made to the robot's store.
+	value, found, err := r.KV().Get(ctx, "other", "count")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, found, test.ShouldBeTrue)
+	test.That(t, value, test.ShouldResemble, []byte("2"))
+}
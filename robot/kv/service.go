@@ -0,0 +1,153 @@
+package kv
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a store. Its requests and responses
+// are google.protobuf.Struct messages holding the JSON form of the request types below,
+// with values encoded in base64.
+const ServiceName = "viam.rdk.kv.v1.KVService"
+
+type request struct {
+	Namespace string `json:"namespace"`
+	Key       string `json:"key,omitempty"`
+	Value     []byte `json:"value,omitempty"`
+}
+
+type getResponse struct {
+	Value []byte `json:"value,omitempty"`
+	Found bool   `json:"found"`
+}
+
+type keysResponse struct {
+	Keys []string `json:"keys"`
+}
+
+// A Server serves a store with ServiceDesc.
+type Server struct {
+	store Service
+}
+
+// NewServer returns a server for the given store.
+func NewServer(store Service) *Server {
+	return &Server{store: store}
+}
+
+func (s *Server) get(ctx context.Context, req request) (interface{}, error) {
+	value, found, err := s.store.Get(ctx, req.Namespace, req.Key)
+	return getResponse{Value: value, Found: found}, err
+}
+
+func (s *Server) set(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.store.Set(ctx, req.Namespace, req.Key, req.Value)
+}
+
+func (s *Server) delete(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.store.Delete(ctx, req.Namespace, req.Key)
+}
+
+func (s *Server) keys(ctx context.Context, req request) (interface{}, error) {
+	keys, err := s.store.Keys(ctx, req.Namespace)
+	return keysResponse{Keys: keys}, err
+}
+
+func (s *Server) watch(req request, stream structrpc.ServerStream) error {
+	ctx := stream.Context()
+	events, err := s.store.Watch(ctx, req.Namespace, req.Key)
+	if err != nil {
+		return err
+	}
+	for ev := range events {
+		if err := stream.Send(ev); err != nil {
+			return err
+		}
+	}
+	if ctx.Err() != nil {
+		return nil
+	}
+	return status.Error(codes.Aborted, "watch ended; the watcher may have fallen behind")
+}
+
+// ServiceDesc describes the gRPC service serving a store. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/kv",
+	structrpc.Unary("Get", (*Server).get),
+	structrpc.Unary("Set", (*Server).set),
+	structrpc.Unary("Delete", (*Server).delete),
+	structrpc.Unary("Keys", (*Server).keys),
+	structrpc.ServerStreaming("Watch", (*Server).watch),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the store served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Get returns the value of the key and whether it is set.
+func (c *Client) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	var resp getResponse
+	if err := c.client.Invoke(ctx, "Get", request{Namespace: namespace, Key: key}, &resp); err != nil {
+		return nil, false, err
+	}
+	if resp.Found && resp.Value == nil {
+		resp.Value = []byte{}
+	}
+	return resp.Value, resp.Found, nil
+}
+
+// Set sets the value of the key.
+func (c *Client) Set(ctx context.Context, namespace, key string, value []byte) error {
+	return c.client.Invoke(ctx, "Set", request{Namespace: namespace, Key: key, Value: value}, nil)
+}
+
+// Delete deletes the key. Deleting a key that is not set does nothing.
+func (c *Client) Delete(ctx context.Context, namespace, key string) error {
+	return c.client.Invoke(ctx, "Delete", request{Namespace: namespace, Key: key}, nil)
+}
+
+// Keys returns the keys set in the namespace, sorted.
+func (c *Client) Keys(ctx context.Context, namespace string) ([]string, error) {
+	var resp keysResponse
+	if err := c.client.Invoke(ctx, "Keys", request{Namespace: namespace}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Keys, nil
+}
+
+// Watch returns the changes to the key, or to every key of the namespace if the key is
+// empty, made from now on.
+func (c *Client) Watch(ctx context.Context, namespace, key string) (<-chan Event, error) {
+	stream, err := c.client.Stream(ctx, "Watch", request{Namespace: namespace, Key: key})
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan Event, watchBuffer)
+	go func() {
+		defer close(events)
+		for {
+			var ev Event
+			if err := stream.Recv(&ev); err != nil {
+				return
+			}
+			select {
+			case events <- ev:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events, nil
+}
@@ -0,0 +1,336 @@
+// Package lan advertises a robot on the local network with multicast DNS and discovers the
+// robots advertised by others, which are candidate remotes of the robot.
+package lan
+
+import (
+	"context"
+	"io"
+	"net"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edaniels/zeroconf"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// ServiceType is the DNS-SD service type robots are advertised with.
+const ServiceType = "_viam-robot._tcp"
+
+// browseTimeout bounds how long each browse of the local network lasts.
+const browseTimeout = 2 * time.Second
+
+// A Candidate is a robot discovered on the local network.
+type Candidate struct {
+	// Name is the name the robot advertises itself with.
+	Name string
+	// Address is the host and port the robot is served at.
+	Address string
+	// LastSeen is when the robot was last discovered.
+	LastSeen time.Time
+}
+
+// A Network advertises robots on the local network and browses for them.
+type Network interface {
+	// Advertise advertises the robot of the given name served at the port, until the
+	// returned closer is closed.
+	Advertise(name string, port int) (io.Closer, error)
+	// Browse returns the robots advertised on the network, browsing until the context is done.
+	Browse(ctx context.Context) ([]Candidate, error)
+}
+
+type mdnsNetwork struct {
+	logger logging.Logger
+}
+
+// NewMDNSNetwork returns the Network reached with multicast DNS.
+func NewMDNSNetwork(logger logging.Logger) Network {
+	return &mdnsNetwork{logger: logger}
+}
+
+type mdnsAdvertisement struct {
+	server *zeroconf.Server
+}
+
+func (a *mdnsAdvertisement) Close() error {
+	a.server.Shutdown()
+	return nil
+}
+
+func (n *mdnsNetwork) Advertise(name string, port int) (io.Closer, error) {
+	server, err := zeroconf.RegisterDynamic(name, ServiceType, "local.", port, nil, nil, n.logger.AsZap())
+	if err != nil {
+		return nil, err
+	}
+	return &mdnsAdvertisement{server: server}, nil
+}
+
+func (n *mdnsNetwork) Browse(ctx context.Context) ([]Candidate, error) {
+	resolver, err := zeroconf.NewResolver(n.logger.AsZap())
+	if err != nil {
+		return nil, err
+	}
+	defer resolver.Shutdown()
+
+	// entries are sent until the context is done, after which the channel is closed.
+	entries := make(chan *zeroconf.ServiceEntry)
+	if err := resolver.Browse(ctx, ServiceType, "local.", entries); err != nil {
+		return nil, err
+	}
+	var candidates []Candidate
+	for entry := range entries {
+		var ip net.IP
+		switch {
+		case len(entry.AddrIPv4) != 0:
+			ip = entry.AddrIPv4[0]
+		case len(entry.AddrIPv6) != 0:
+			ip = entry.AddrIPv6[0]
+		default:
+			continue
+		}
+		candidates = append(candidates, Candidate{
+			Name:    entry.Instance,
+			Address: net.JoinHostPort(ip.String(), strconv.Itoa(entry.Port)),
+		})
+	}
+	return candidates, nil
+}
+
+// A Robot is a robot discovering candidate remotes on the local network.
+type Robot interface {
+	// DiscoverRemotes browses the local network now and returns the robots advertised on it,
+	// which are candidate remotes of the robot.
+	DiscoverRemotes(ctx context.Context) ([]Candidate, error)
+}
+
+// A Discoverer advertises a robot on the local network and periodically discovers the
+// robots advertised by others, as configured.
+type Discoverer struct {
+	logger     logging.Logger
+	network    Network
+	address    func() string
+	onDiscover func()
+
+	mu         sync.Mutex
+	conf       *config.RemoteDiscoveryConfig
+	name       string
+	candidates map[string]Candidate
+	advert     io.Closer
+	advertised string
+
+	// runMu serializes starting and stopping the worker.
+	runMu   sync.Mutex
+	cancel  func()
+	workers sync.WaitGroup
+}
+
+// NewDiscoverer returns a discoverer advertising the robot served at the address returned
+// by address, which is empty until the robot is served. onDiscover is called once robots
+// that are added as remotes are discovered.
+func NewDiscoverer(logger logging.Logger, network Network, address func() string, onDiscover func()) *Discoverer {
+	return &Discoverer{
+		logger:     logger,
+		network:    network,
+		address:    address,
+		onDiscover: onDiscover,
+		candidates: map[string]Candidate{},
+	}
+}
+
+// Reconfigure starts, stops or restarts advertising and discovering as configured, if the
+// config has changed since the last call. A nil config disables both.
+func (d *Discoverer) Reconfigure(conf *config.RemoteDiscoveryConfig) {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	d.mu.Lock()
+	if reflect.DeepEqual(conf, d.conf) {
+		d.mu.Unlock()
+		return
+	}
+	d.conf = conf
+	d.mu.Unlock()
+
+	d.stop()
+	if conf == nil {
+		return
+	}
+	name := conf.Name
+	if name == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			d.logger.Warnw("cannot advertise robot without a name", "error", err)
+		}
+		name = hostname
+	}
+	d.mu.Lock()
+	d.name = name
+	d.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d.cancel = cancel
+	interval := conf.IntervalDuration()
+	d.workers.Add(1)
+	utils.ManagedGo(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			d.advertise(conf)
+			if _, err := d.discover(ctx, min(interval, browseTimeout)); err != nil && ctx.Err() == nil {
+				d.logger.Debugw("failed to discover robots", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}, d.workers.Done)
+}
+
+// advertise advertises the robot once it is served, and again if its port changes.
+func (d *Discoverer) advertise(conf *config.RemoteDiscoveryConfig) {
+	if conf.DisableAdvertise {
+		return
+	}
+	_, port, err := net.SplitHostPort(d.address())
+	if err != nil {
+		return
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.name == "" || d.advertised == port {
+		return
+	}
+	if d.advert != nil {
+		utils.UncheckedError(d.advert.Close())
+		d.advert = nil
+	}
+	advert, err := d.network.Advertise(d.name, portNum)
+	if err != nil {
+		d.logger.Warnw("failed to advertise robot", "name", d.name, "error", err)
+		return
+	}
+	d.logger.Debugw("advertising robot", "name", d.name, "port", portNum)
+	d.advert = advert
+	d.advertised = port
+}
+
+// discover browses the network for the given time and records the robots found, dropping
+// those not seen for three intervals.
+func (d *Discoverer) discover(ctx context.Context, timeout time.Duration) ([]Candidate, error) {
+	browseCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	found, err := d.network.Browse(browseCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	var added bool
+	for _, candidate := range found {
+		candidate.LastSeen = now
+		if candidate.Name == d.name {
+			continue
+		}
+		if _, ok := d.candidates[candidate.Name]; !ok {
+			d.logger.Debugw("discovered robot", "name", candidate.Name, "address", candidate.Address)
+			added = added || d.conf.ShouldAutoAdd(candidate.Name)
+		}
+		d.candidates[candidate.Name] = candidate
+	}
+	staleBefore := now.Add(-3 * d.conf.IntervalDuration())
+	for name, candidate := range d.candidates {
+		if candidate.LastSeen.Before(staleBefore) {
+			delete(d.candidates, name)
+		}
+	}
+	candidates := d.sortedCandidates()
+	d.mu.Unlock()
+
+	if added && d.onDiscover != nil {
+		d.onDiscover()
+	}
+	return candidates, nil
+}
+
+// sortedCandidates returns the candidates by name. d.mu must be held.
+func (d *Discoverer) sortedCandidates() []Candidate {
+	candidates := make([]Candidate, 0, len(d.candidates))
+	for _, candidate := range d.candidates {
+		candidates = append(candidates, candidate)
+	}
+	slices.SortFunc(candidates, func(a, b Candidate) int {
+		switch {
+		case a.Name < b.Name:
+			return -1
+		case a.Name > b.Name:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return candidates
+}
+
+// Discover browses the network now and returns the robots discovered, sorted by name. It
+// may be called whether or not discovery is configured.
+func (d *Discoverer) Discover(ctx context.Context) ([]Candidate, error) {
+	return d.discover(ctx, browseTimeout)
+}
+
+// Remotes returns the configs of the remotes discovered robots are added as, other than
+// those named like the given remotes.
+func (d *Discoverer) Remotes(configured []config.Remote) []config.Remote {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var remotes []config.Remote
+	for _, candidate := range d.sortedCandidates() {
+		if !d.conf.ShouldAutoAdd(candidate.Name) || rutils.ValidateRemoteName(candidate.Name) != nil {
+			continue
+		}
+		if slices.ContainsFunc(configured, func(remote config.Remote) bool { return remote.Name == candidate.Name }) {
+			continue
+		}
+		remotes = append(remotes, config.Remote{Name: candidate.Name, Address: candidate.Address})
+	}
+	return remotes
+}
+
+func (d *Discoverer) stop() {
+	if d.cancel != nil {
+		d.cancel()
+		d.cancel = nil
+	}
+	d.workers.Wait()
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.advert != nil {
+		utils.UncheckedError(d.advert.Close())
+		d.advert = nil
+	}
+	d.advertised = ""
+}
+
+// Close stops advertising and discovering.
+func (d *Discoverer) Close() {
+	d.runMu.Lock()
+	defer d.runMu.Unlock()
+	d.mu.Lock()
+	d.conf = nil
+	d.mu.Unlock()
+	d.stop()
+}
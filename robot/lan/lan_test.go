@@ -0,0 +1,66 @@
+package lan_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot/lan"
+	lantestutils "go.viam.com/rdk/robot/lan/testutils"
+)
+
+func TestDiscoverer(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	network := lantestutils.NewFakeNetwork()
+	_, err := network.Advertise("cell-1", 8081)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = network.Advertise("camera", 8082)
+	test.That(t, err, test.ShouldBeNil)
+
+	var address atomic.Value
+	address.Store("")
+	var discoveries atomic.Int64
+	d := lan.NewDiscoverer(logger, network, func() string { return address.Load().(string) }, func() { discoveries.Add(1) })
+	defer d.Close()
+
+	// robots are discovered whether or not discovery is configured.
+	candidates, err := d.Discover(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, candidates, test.ShouldHaveLength, 2)
+	test.That(t, candidates[0].Name, test.ShouldEqual, "camera")
+	test.That(t, candidates[1].Name, test.ShouldEqual, "cell-1")
+	test.That(t, candidates[1].Address, test.ShouldEqual, "localhost:8081")
+	test.That(t, d.Remotes(nil), test.ShouldBeEmpty)
+
+	// the robot is advertised once served, and not discovered by itself.
+	d.Reconfigure(&config.RemoteDiscoveryConfig{Name: "main", AutoAdd: []string{"cell-*"}, Interval: "10ms"})
+	address.Store("localhost:8080")
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, network.Advertised(), test.ShouldResemble, []string{"camera", "cell-1", "main"})
+	})
+	candidates, err = d.Discover(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, candidates, test.ShouldHaveLength, 2)
+
+	// robots matching auto_add are added as remotes unless one has their name.
+	test.That(t, d.Remotes(nil), test.ShouldResemble, []config.Remote{{Name: "cell-1", Address: "localhost:8081"}})
+	test.That(t, d.Remotes([]config.Remote{{Name: "cell-1", Address: "elsewhere:8080"}}), test.ShouldBeEmpty)
+
+	_, err = network.Advertise("cell-2", 8083)
+	test.That(t, err, test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		test.That(tb, d.Remotes(nil), test.ShouldHaveLength, 2)
+	})
+	test.That(t, discoveries.Load(), test.ShouldBeGreaterThanOrEqualTo, 1)
+
+	// disabling discovery stops advertising the robot.
+	d.Reconfigure(nil)
+	test.That(t, network.Advertised(), test.ShouldResemble, []string{"camera", "cell-1", "cell-2"})
+	test.That(t, d.Remotes(nil), test.ShouldBeEmpty)
+}
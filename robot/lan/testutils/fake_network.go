@@ -0,0 +1,71 @@
+// Package testutils is test helpers for discovering robots on the local network.
+package testutils
+
+import (
+	"context"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"sync"
+
+	"go.viam.com/rdk/robot/lan"
+)
+
+// FakeNetwork is a lan.Network within the process, on which robots advertised are
+// discovered by the robots browsing it without multicast DNS.
+type FakeNetwork struct {
+	mu      sync.Mutex
+	adverts map[string]int
+}
+
+var _ lan.Network = (*FakeNetwork)(nil)
+
+// NewFakeNetwork returns an empty network.
+func NewFakeNetwork() *FakeNetwork {
+	return &FakeNetwork{adverts: map[string]int{}}
+}
+
+type fakeAdvert struct {
+	network *FakeNetwork
+	name    string
+}
+
+func (a *fakeAdvert) Close() error {
+	a.network.mu.Lock()
+	defer a.network.mu.Unlock()
+	delete(a.network.adverts, a.name)
+	return nil
+}
+
+// Advertise advertises the robot of the given name served at the port on localhost.
+func (n *FakeNetwork) Advertise(name string, port int) (io.Closer, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.adverts[name] = port
+	return &fakeAdvert{network: n, name: name}, nil
+}
+
+// Browse returns the robots advertised, without waiting for the context to be done.
+func (n *FakeNetwork) Browse(ctx context.Context) ([]lan.Candidate, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	candidates := make([]lan.Candidate, 0, len(n.adverts))
+	for name, port := range n.adverts {
+		candidates = append(candidates, lan.Candidate{Name: name, Address: net.JoinHostPort("localhost", strconv.Itoa(port))})
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates, nil
+}
+
+// Advertised returns the names of the robots advertised, sorted.
+func (n *FakeNetwork) Advertised() []string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	names := make([]string, 0, len(n.adverts))
+	for name := range n.adverts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,373 @@
+// Package maptiles caches the map tiles of the geographic areas configured for a robot and
+// serves them from the robot itself, so that outdoor navigation UIs rendered from the robot's
+// web server keep showing maps in the field without internet connectivity.
+//
+// The tiles of each area are downloaded in the background from its OSM or XYZ tile server
+// while the robot is online, retrying the tiles that fail until every tile is cached, and kept
+// on disk across restarts. Tiles are served at /<area>/<z>/<x>/<y> relative to where the cache
+// is mounted, from disk, or fetched through from the tile server and cached when they are
+// missing, such as tiles beyond the configured zoom levels. The download progress of each
+// area is served at the root.
+package maptiles
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot"
+)
+
+const (
+	// DirName is the name of the directory holding a robot's map tiles in its Viam home
+	// directory.
+	DirName = "map_tiles"
+	// userAgent identifies the robot to tile servers, as their usage policies require.
+	userAgent = "viam-server"
+	// maxTileSize bounds the size of a tile read from a tile server.
+	maxTileSize = 4 << 20
+	// tileTimeout bounds how long a tile is waited for from a tile server.
+	tileTimeout = 30 * time.Second
+)
+
+// retryInterval is how long the tiles that failed to download are waited on before they are
+// downloaded again.
+var retryInterval = time.Minute
+
+// A Robot is a robot that caches and serves map tiles.
+type Robot interface {
+	robot.Robot
+	// MapTiles returns the cache of the map tiles of the areas configured for the robot.
+	MapTiles() *Cache
+}
+
+// AreaStatus is the download progress of the tiles of an area.
+type AreaStatus struct {
+	Name string `json:"name"`
+	// Tiles is how many tiles the area spans, and Cached how many of them are cached.
+	Tiles  int `json:"tiles"`
+	Cached int `json:"cached"`
+	// Failed is how many tiles failed to download in the latest attempt, and LastError why
+	// the last of them failed.
+	Failed    int    `json:"failed"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// A Cache downloads, stores, and serves the map tiles of configured areas.
+type Cache struct {
+	logger logging.Logger
+	dir    string
+	client *http.Client
+
+	mu    sync.Mutex
+	confs []config.MapTileAreaConfig
+	areas map[string]*AreaStatus
+
+	// runMu serializes starting and stopping the downloads.
+	runMu   sync.Mutex
+	cancel  func()
+	workers sync.WaitGroup
+}
+
+// NewCache returns a cache storing tiles in the given directory and downloading them with
+// the given client, or http.DefaultClient if nil. No tiles are downloaded until it is
+// reconfigured with areas.
+func NewCache(logger logging.Logger, dir string, client *http.Client) *Cache {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Cache{logger: logger, dir: dir, client: client, areas: map[string]*AreaStatus{}}
+}
+
+// Reconfigure restarts downloading the tiles of the given areas, if they have changed since
+// the last call. Tiles already cached are kept.
+func (c *Cache) Reconfigure(areas []config.MapTileAreaConfig) {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+	c.mu.Lock()
+	if reflect.DeepEqual(areas, c.confs) {
+		c.mu.Unlock()
+		return
+	}
+	c.confs = areas
+	c.mu.Unlock()
+
+	c.stop()
+	statuses := make(map[string]*AreaStatus, len(areas))
+	for _, area := range areas {
+		statuses[area.Name] = &AreaStatus{Name: area.Name, Tiles: area.TileCount()}
+	}
+	c.mu.Lock()
+	c.areas = statuses
+	c.mu.Unlock()
+	if len(areas) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.workers.Add(1)
+	utils.ManagedGo(func() {
+		for {
+			if failed := c.downloadAll(ctx, areas); failed == 0 {
+				return
+			}
+			if !utils.SelectContextOrWait(ctx, retryInterval) {
+				return
+			}
+		}
+	}, c.workers.Done)
+}
+
+// Close stops downloading tiles.
+func (c *Cache) Close() {
+	c.runMu.Lock()
+	defer c.runMu.Unlock()
+	c.stop()
+}
+
+func (c *Cache) stop() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	c.workers.Wait()
+}
+
+// Status returns the download progress of the tiles of each area, sorted by name.
+func (c *Cache) Status() []AreaStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	statuses := make([]AreaStatus, 0, len(c.areas))
+	for _, status := range c.areas {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
+
+// downloadAll downloads the tiles of the areas that are not cached yet, and returns how many
+// failed to download.
+func (c *Cache) downloadAll(ctx context.Context, areas []config.MapTileAreaConfig) int {
+	var failed int
+	for _, area := range areas {
+		c.updateStatus(area.Name, func(status *AreaStatus) {
+			status.Cached, status.Failed, status.LastError = 0, 0, ""
+		})
+		for zoom := area.MinZoom; zoom <= area.MaxZoom; zoom++ {
+			minX, maxX, minY, maxY := area.TileRange(zoom)
+			for x := minX; x <= maxX; x++ {
+				for y := minY; y <= maxY; y++ {
+					if ctx.Err() != nil {
+						return failed
+					}
+					err := c.download(ctx, area, zoom, x, y)
+					c.updateStatus(area.Name, func(status *AreaStatus) {
+						if err != nil {
+							status.Failed++
+							status.LastError = err.Error()
+							return
+						}
+						status.Cached++
+					})
+					if err != nil {
+						failed++
+					}
+				}
+			}
+		}
+		status := c.areaStatus(area.Name)
+		if status.Failed == 0 {
+			c.logger.Infow("cached map tiles", "area", area.Name, "tiles", status.Tiles)
+		} else {
+			c.logger.Warnw("failed to download map tiles; retrying later",
+				"area", area.Name, "failed", status.Failed, "tiles", status.Tiles, "error", status.LastError)
+		}
+	}
+	return failed
+}
+
+// download caches the tile unless it is cached already.
+func (c *Cache) download(ctx context.Context, area config.MapTileAreaConfig, zoom, x, y int) error {
+	path := c.tilePath(area, zoom, x, y)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	data, err := c.fetch(ctx, area, zoom, x, y)
+	if err != nil {
+		return err
+	}
+	return storeTile(path, data)
+}
+
+func (c *Cache) updateStatus(name string, update func(status *AreaStatus)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status, ok := c.areas[name]; ok {
+		update(status)
+	}
+}
+
+func (c *Cache) areaStatus(name string) AreaStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if status, ok := c.areas[name]; ok {
+		return *status
+	}
+	return AreaStatus{Name: name}
+}
+
+func (c *Cache) area(name string) (config.MapTileAreaConfig, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, area := range c.confs {
+		if area.Name == name {
+			return area, true
+		}
+	}
+	return config.MapTileAreaConfig{}, false
+}
+
+// tilePath returns the path of the file caching a tile. Tiles are stored by the URL template
+// of their tile server, so that areas with the same server share their tiles.
+func (c *Cache) tilePath(area config.MapTileAreaConfig, zoom, x, y int) string {
+	sum := sha256.Sum256([]byte(area.URL))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:8]), strconv.Itoa(zoom), strconv.Itoa(x), strconv.Itoa(y))
+}
+
+// fetch returns the tile read from the tile server of the area.
+func (c *Cache) fetch(ctx context.Context, area config.MapTileAreaConfig, zoom, x, y int) ([]byte, error) {
+	url := strings.NewReplacer(
+		"{z}", strconv.Itoa(zoom),
+		"{x}", strconv.Itoa(x),
+		"{y}", strconv.Itoa(y),
+	).Replace(area.URL)
+	ctx, cancel := context.WithTimeout(ctx, tileTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		utils.UncheckedError(resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("tile server returned %s for %s", resp.Status, url)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxTileSize {
+		return nil, errors.Errorf("tile %s is larger than %d bytes", url, maxTileSize)
+	}
+	return data, nil
+}
+
+// storeTile writes the tile to its path, replacing it at once so that a tile being written
+// is never served.
+func storeTile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tile-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		utils.UncheckedError(tmp.Close())
+		utils.UncheckedError(os.Remove(tmp.Name()))
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		utils.UncheckedError(os.Remove(tmp.Name()))
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// ServeHTTP serves the download progress of the areas at the root, and the tile at
+// /<area>/<z>/<x>/<y>, where y may be followed by an extension such as ".png".
+func (c *Cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	if path == "" {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(c.Status()); err != nil {
+			c.logger.Debugw("failed to write map tile status", "error", err)
+		}
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 {
+		http.NotFound(w, r)
+		return
+	}
+	area, ok := c.area(parts[0])
+	if !ok {
+		http.Error(w, fmt.Sprintf("no map tile area named %q", parts[0]), http.StatusNotFound)
+		return
+	}
+	zoom, x, y, err := parseTile(parts[1], parts[2], parts[3])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	tilePath := c.tilePath(area, zoom, x, y)
+	data, err := os.ReadFile(tilePath) //nolint:gosec
+	if err != nil {
+		// read through to the tile server, while it can be reached.
+		if data, err = c.fetch(r.Context(), area, zoom, x, y); err != nil {
+			http.Error(w, fmt.Sprintf("map tile is not cached and cannot be downloaded: %v", err), http.StatusBadGateway)
+			return
+		}
+		if err := storeTile(tilePath, data); err != nil {
+			c.logger.Warnw("failed to cache map tile", "area", area.Name, "error", err)
+		}
+	}
+	w.Header().Set("Content-Type", http.DetectContentType(data))
+	w.Header().Set("Cache-Control", "max-age=86400")
+	if _, err := w.Write(data); err != nil {
+		c.logger.Debugw("failed to write map tile", "error", err)
+	}
+}
+
+// parseTile parses the zoom level and coordinates of a tile from a tile URL.
+func parseTile(zoomStr, xStr, yStr string) (int, int, int, error) {
+	if ext := filepath.Ext(yStr); ext != "" {
+		yStr = strings.TrimSuffix(yStr, ext)
+	}
+	zoom, err := strconv.Atoi(zoomStr)
+	if err != nil || zoom < 0 || zoom > config.MaxMapTileZoom {
+		return 0, 0, 0, errors.Errorf("invalid zoom level %q", zoomStr)
+	}
+	x, errX := strconv.Atoi(xStr)
+	y, errY := strconv.Atoi(yStr)
+	if errX != nil || errY != nil || x < 0 || y < 0 || x >= 1<<zoom || y >= 1<<zoom {
+		return 0, 0, 0, errors.Errorf("invalid tile %s/%s at zoom level %d", xStr, yStr, zoom)
+	}
+	return zoom, x, y, nil
+}
@@ -0,0 +1,141 @@
+package maptiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+)
+
+func TestCache(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	origRetryInterval := retryInterval
+	retryInterval = 10 * time.Millisecond
+	defer func() {
+		retryInterval = origRetryInterval
+	}()
+
+	var online atomic.Bool
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		test.That(t, r.Header.Get("User-Agent"), test.ShouldEqual, userAgent)
+		if !online.Load() {
+			http.Error(w, "offline", http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintf(w, "tile %s", r.URL.Path)
+	}))
+	defer server.Close()
+
+	area := config.MapTileAreaConfig{
+		Name:         "field",
+		URL:          server.URL + "/{z}/{x}/{y}.png",
+		MinLatitude:  37.7,
+		MinLongitude: -122.5,
+		MaxLatitude:  37.8,
+		MaxLongitude: -122.4,
+		MaxZoom:      10,
+	}
+	test.That(t, area.Validate("map_tiles.0"), test.ShouldBeNil)
+	tiles := area.TileCount()
+
+	cache := NewCache(logger, t.TempDir(), nil)
+	defer cache.Close()
+	cache.Reconfigure([]config.MapTileAreaConfig{area})
+
+	// tiles failing to download are retried until they are all cached.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, cache.Status(), test.ShouldResemble, []AreaStatus{{
+			Name: "field", Tiles: tiles, Failed: tiles,
+			LastError: fmt.Sprintf("tile server returned 503 Service Unavailable for %s/10/163/396.png", server.URL),
+		}})
+	})
+	online.Store(true)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		test.That(tb, cache.Status(), test.ShouldResemble, []AreaStatus{{Name: "field", Tiles: tiles, Cached: tiles}})
+	})
+
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		cache.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("cached tiles", func(t *testing.T) {
+		online.Store(false)
+		defer online.Store(true)
+		w := get("/field/10/163/395.png")
+		test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+		test.That(t, w.Body.String(), test.ShouldEqual, "tile /10/163/395.png")
+		test.That(t, w.Header().Get("Content-Type"), test.ShouldStartWith, "text/plain")
+
+		w = get("/field/0/0/0")
+		test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+		test.That(t, w.Body.String(), test.ShouldEqual, "tile /0/0/0.png")
+
+		// tiles out of the area cannot be read through offline.
+		w = get("/field/12/0/0.png")
+		test.That(t, w.Code, test.ShouldEqual, http.StatusBadGateway)
+	})
+
+	t.Run("read through", func(t *testing.T) {
+		w := get("/field/12/1/2.png")
+		test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+		test.That(t, w.Body.String(), test.ShouldEqual, "tile /12/1/2.png")
+
+		before := requests.Load()
+		online.Store(false)
+		defer online.Store(true)
+		w = get("/field/12/1/2.png")
+		test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+		test.That(t, w.Body.String(), test.ShouldEqual, "tile /12/1/2.png")
+		test.That(t, requests.Load(), test.ShouldEqual, before)
+	})
+
+	t.Run("status", func(t *testing.T) {
+		w := get("/")
+		test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+		var statuses []AreaStatus
+		test.That(t, json.Unmarshal(w.Body.Bytes(), &statuses), test.ShouldBeNil)
+		test.That(t, statuses, test.ShouldResemble, []AreaStatus{{Name: "field", Tiles: tiles, Cached: tiles}})
+	})
+
+	t.Run("invalid tiles", func(t *testing.T) {
+		test.That(t, get("/forest/0/0/0.png").Code, test.ShouldEqual, http.StatusNotFound)
+		test.That(t, get("/field/0/0.png").Code, test.ShouldEqual, http.StatusNotFound)
+		test.That(t, get("/field/1/2/0.png").Code, test.ShouldEqual, http.StatusNotFound)
+		test.That(t, get("/field/21/0/0.png").Code, test.ShouldEqual, http.StatusNotFound)
+	})
+
+	t.Run("reconfigure", func(t *testing.T) {
+		// tiles already cached are kept when an area is added.
+		before := requests.Load()
+		moved := area
+		moved.Name = "field2"
+		cache.Reconfigure([]config.MapTileAreaConfig{area, moved})
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			test.That(tb, cache.Status(), test.ShouldResemble, []AreaStatus{
+				{Name: "field", Tiles: tiles, Cached: tiles},
+				{Name: "field2", Tiles: tiles, Cached: tiles},
+			})
+		})
+		test.That(t, requests.Load(), test.ShouldEqual, before)
+
+		cache.Reconfigure(nil)
+		test.That(t, cache.Status(), test.ShouldBeEmpty)
+		test.That(t, get("/field/0/0/0.png").Code, test.ShouldEqual, http.StatusNotFound)
+	})
+}
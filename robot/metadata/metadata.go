@@ -0,0 +1,86 @@
+// Package metadata keeps the metadata of the resources of a robot given by their configs,
+// such as their labels, and serves it to remote clients.
+package metadata
+
+import (
+	"maps"
+	"reflect"
+	"sync"
+
+	"go.viam.com/rdk/resource"
+)
+
+// A Service provides the labels of the resources of a robot.
+type Service interface {
+	// ResourceLabels returns the labels of the resources that have any.
+	ResourceLabels() map[resource.Name]map[string]string
+}
+
+// A Robot is a robot whose resources are selected by their labels. Its labels are served
+// with a Server.
+type Robot interface {
+	Service
+	// ResourceNames returns the names of all known resources.
+	ResourceNames() []resource.Name
+}
+
+// ResourceNamesByLabel returns the names of the known resources of the robot whose labels
+// match the selector, as parsed by resource.ParseLabelSelector.
+func ResourceNamesByLabel(r Robot, selector string) ([]resource.Name, error) {
+	sel, err := resource.ParseLabelSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+	return resource.SelectByLabel(r.ResourceNames(), r.ResourceLabels(), sel), nil
+}
+
+// A Store is the Service of a robot, keeping the labels of its resources as configured.
+type Store struct {
+	mu         sync.RWMutex
+	fromConfig []resource.Config
+	labels     map[resource.Name]map[string]string
+}
+
+var _ Service = (*Store)(nil)
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{labels: map[resource.Name]map[string]string{}}
+}
+
+// Reconfigure replaces all labels with those of the given resource configs if they have
+// changed since the last call.
+func (s *Store) Reconfigure(cfgs []resource.Config) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if reflect.DeepEqual(cfgs, s.fromConfig) {
+		return
+	}
+	s.fromConfig = cfgs
+
+	s.labels = map[resource.Name]map[string]string{}
+	for _, cfg := range cfgs {
+		if len(cfg.Labels) == 0 {
+			continue
+		}
+		s.labels[cfg.ResourceName()] = maps.Clone(cfg.Labels)
+	}
+}
+
+// Labels returns the labels of the named resource, if it has any.
+func (s *Store) Labels(name resource.Name) map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.labels[name])
+}
+
+// ResourceLabels returns the labels of the resources that have any.
+func (s *Store) ResourceLabels() map[resource.Name]map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	labels := make(map[resource.Name]map[string]string, len(s.labels))
+	for name, resLabels := range s.labels {
+		labels[name] = maps.Clone(resLabels)
+	}
+	return labels
+}
@@ -0,0 +1,106 @@
+package metadata_test
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/metadata"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestStore(t *testing.T) {
+	store := metadata.NewStore()
+	test.That(t, store.ResourceLabels(), test.ShouldBeEmpty)
+
+	store.Reconfigure([]resource.Config{
+		{Name: "left_arm", API: arm.API, Labels: map[string]string{"side": "left"}},
+		{Name: "base", API: base.API},
+	})
+	test.That(t, store.ResourceLabels(), test.ShouldResemble, map[resource.Name]map[string]string{
+		arm.Named("left_arm"): {"side": "left"},
+	})
+	test.That(t, store.Labels(arm.Named("left_arm")), test.ShouldResemble, map[string]string{"side": "left"})
+	test.That(t, store.Labels(base.Named("base")), test.ShouldBeNil)
+
+	// labels returned are copies.
+	store.Labels(arm.Named("left_arm"))["side"] = "right"
+	store.ResourceLabels()[arm.Named("left_arm")]["side"] = "right"
+	test.That(t, store.Labels(arm.Named("left_arm")), test.ShouldResemble, map[string]string{"side": "left"})
+
+	store.Reconfigure([]resource.Config{{Name: "base", API: base.API, Labels: map[string]string{"mobile": ""}}})
+	test.That(t, store.ResourceLabels(), test.ShouldResemble, map[resource.Name]map[string]string{
+		base.Named("base"): {"mobile": ""},
+	})
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	leftArm, rightArm, remoteArm := arm.Named("left_arm"), arm.Named("right_arm"), arm.Named("left_arm").PrependRemote("r1")
+	labels := map[resource.Name]map[string]string{
+		leftArm:   {"side": "left", "arm": ""},
+		rightArm:  {"side": "right", "arm": ""},
+		remoteArm: {"side": "left"},
+	}
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		ResourceLabelsFunc:  func() map[resource.Name]map[string]string { return labels },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		leftArm:            inject.NewArm(leftArm.Name),
+		rightArm:           inject.NewArm(rightArm.Name),
+		remoteArm:          inject.NewArm(remoteArm.ShortName()),
+		base.Named("base"): inject.NewBase("base"),
+	})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	// the client fetches the labels served by the robot when it refreshes.
+	test.That(t, robotClient.ResourceLabels(), test.ShouldResemble, labels)
+
+	names, err := metadata.ResourceNamesByLabel(robotClient, "side=left")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldHaveLength, 2)
+	test.That(t, names, test.ShouldContain, leftArm)
+	test.That(t, names, test.ShouldContain, remoteArm)
+	names, err = metadata.ResourceNamesByLabel(robotClient, "arm,side!=left")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []resource.Name{rightArm})
+	names, err = metadata.ResourceNamesByLabel(robotClient, "!side")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []resource.Name{base.Named("base")})
+	_, err = metadata.ResourceNamesByLabel(robotClient, "=left")
+	test.That(t, err, test.ShouldNotBeNil)
+
+	// queries made to the robot are filtered by it.
+	robotLabels, err := robotClient.Metadata().ResourceLabels(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, robotLabels, test.ShouldResemble, labels)
+	robotLabels, err = robotClient.Metadata().ResourceLabels(ctx, "side=left,arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, robotLabels, test.ShouldResemble, map[resource.Name]map[string]string{leftArm: labels[leftArm]})
+	names, err = robotClient.Metadata().ResourceNames(ctx, "!arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldHaveLength, 2)
+	test.That(t, names, test.ShouldContain, remoteArm)
+	test.That(t, names, test.ShouldContain, base.Named("base"))
+	names, err = robotClient.Metadata().ResourceNames(ctx, "side=top")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldBeEmpty)
+	_, err = robotClient.Metadata().ResourceLabels(ctx, "=left")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
@@ -0,0 +1,124 @@
+package metadata
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/resource"
+)
+
+// ServiceName is the name of the gRPC service serving the metadata of a robot's resources.
+// Its requests and responses are google.protobuf.Struct messages holding the JSON form of
+// the types below.
+const ServiceName = "viam.rdk.metadata.v1.MetadataService"
+
+type request struct {
+	Selector string `json:"selector,omitempty"`
+}
+
+type resourceLabels struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
+type labelsResponse struct {
+	Resources []resourceLabels `json:"resources"`
+}
+
+type namesResponse struct {
+	Names []string `json:"names"`
+}
+
+// A Server serves the labels of a robot's resources with ServiceDesc.
+type Server struct {
+	r Robot
+}
+
+// NewServer returns a server for the given robot.
+func NewServer(r Robot) *Server {
+	return &Server{r: r}
+}
+
+func (s *Server) getResourceLabels(ctx context.Context, req request) (interface{}, error) {
+	sel, err := resource.ParseLabelSelector(req.Selector)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var resp labelsResponse
+	for name, labels := range s.r.ResourceLabels() {
+		if !sel.Matches(labels) {
+			continue
+		}
+		resp.Resources = append(resp.Resources, resourceLabels{Name: name.String(), Labels: labels})
+	}
+	return resp, nil
+}
+
+func (s *Server) getResourceNames(ctx context.Context, req request) (interface{}, error) {
+	names, err := ResourceNamesByLabel(s.r, req.Selector)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	resp := namesResponse{Names: []string{}}
+	for _, name := range names {
+		resp.Names = append(resp.Names, name.String())
+	}
+	return resp, nil
+}
+
+// ServiceDesc describes the gRPC service serving the labels of a robot's resources. It is
+// served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/metadata",
+	structrpc.Unary("GetResourceLabels", (*Server).getResourceLabels),
+	structrpc.Unary("GetResourceNames", (*Server).getResourceNames),
+)
+
+// A Client fetches the labels of the resources of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the labels served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// ResourceLabels returns the labels of the resources of the robot that have any and whose
+// labels match the selector. The empty selector matches all resources.
+func (c *Client) ResourceLabels(ctx context.Context, selector string) (map[resource.Name]map[string]string, error) {
+	var resp labelsResponse
+	if err := c.client.Invoke(ctx, "GetResourceLabels", request{Selector: selector}, &resp); err != nil {
+		return nil, err
+	}
+	labels := make(map[resource.Name]map[string]string, len(resp.Resources))
+	for _, res := range resp.Resources {
+		name, err := resource.NewFromString(res.Name)
+		if err != nil {
+			return nil, err
+		}
+		labels[name] = res.Labels
+	}
+	return labels, nil
+}
+
+// ResourceNames returns the names of the resources of the robot whose labels match the
+// selector, including resources without labels.
+func (c *Client) ResourceNames(ctx context.Context, selector string) ([]resource.Name, error) {
+	var resp namesResponse
+	if err := c.client.Invoke(ctx, "GetResourceNames", request{Selector: selector}, &resp); err != nil {
+		return nil, err
+	}
+	names := make([]resource.Name, 0, len(resp.Names))
+	for _, n := range resp.Names {
+		name, err := resource.NewFromString(n)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
@@ -0,0 +1,310 @@
+// Package metrics records how many requests each resource of a robot serves, how long they
+// take, and how many fail, along with the usage of the processes of its modules, and exposes
+// them in the Prometheus text exposition format so that fleets can be scraped with standard
+// monitoring tools.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/resource"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// The names of the metrics exposed.
+const (
+	OperationsTotal    = "rdk_resource_operations_total"
+	OperationErrors    = "rdk_resource_operation_errors_total"
+	OperationDurations = "rdk_resource_operation_duration_seconds"
+
+	ModuleCPUPercent = "rdk_module_cpu_percent"
+	ModuleMemoryMB   = "rdk_module_memory_megabytes"
+	ModuleThrottle   = "rdk_module_throttle_ratio"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the buckets of the operation duration
+// histograms. They are the default buckets of Prometheus client libraries.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type operationKey struct {
+	resource string
+	method   string
+}
+
+type operationStats struct {
+	// codes counts the operations by the gRPC code they returned.
+	codes map[string]uint64
+	// errors counts the failed operations by class of error.
+	errors map[resource.ErrorClass]uint64
+	// buckets counts the operations within each of DefaultBuckets; the last counts them all.
+	buckets []uint64
+	sum     float64
+}
+
+// ModuleUsage is the usage of the process of a module over the last interval it was
+// measured for.
+type ModuleUsage struct {
+	CPUPercent float64
+	MemoryMB   float64
+	// Throttle is the fraction of the time the process is paused for.
+	Throttle float64
+}
+
+// A Robot is a robot recording the operations served by its resources.
+type Robot interface {
+	// Metrics returns the recorder of the operations served by the robot's resources, which
+	// the web service exposes at /metrics in the Prometheus text exposition format.
+	Metrics() *Recorder
+}
+
+// A Recorder records the operations served by the resources of a robot and the usage of the
+// processes of its modules.
+type Recorder struct {
+	mu         sync.Mutex
+	operations map[operationKey]*operationStats
+	modules    map[string]ModuleUsage
+}
+
+// NewRecorder returns a new Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{operations: map[operationKey]*operationStats{}, modules: map[string]ModuleUsage{}}
+}
+
+// RecordModuleUsage records the latest usage of the process of the named module.
+func (r *Recorder) RecordModuleUsage(module string, usage ModuleUsage) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.modules[module] = usage
+}
+
+// ForgetModule stops exposing the usage of the process of the named module, such as once
+// the module is removed.
+func (r *Recorder) ForgetModule(module string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.modules, module)
+}
+
+// ModuleUsage returns the latest usage recorded for the process of the named module, if any.
+func (r *Recorder) ModuleUsage(module string) (ModuleUsage, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage, ok := r.modules[module]
+	return usage, ok
+}
+
+// Record records an operation of the given method on the resource with the given short name
+// that took the given duration and returned the given error, if any.
+func (r *Recorder) Record(shortName, method string, duration time.Duration, err error) {
+	key := operationKey{resource: shortName, method: method}
+	code := status.Code(err).String()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, ok := r.operations[key]
+	if !ok {
+		stats = &operationStats{
+			codes:   map[string]uint64{},
+			errors:  map[resource.ErrorClass]uint64{},
+			buckets: make([]uint64, len(DefaultBuckets)+1),
+		}
+		r.operations[key] = stats
+	}
+	stats.codes[code]++
+	if err != nil {
+		stats.errors[resource.ClassifyError(err)]++
+	}
+	seconds := duration.Seconds()
+	for i, bound := range DefaultBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+	stats.buckets[len(DefaultBuckets)]++
+	stats.sum += seconds
+}
+
+// Operations returns how many operations of the given method the resource with the given
+// short name served, and how many of them failed.
+func (r *Recorder) Operations(shortName, method string) (total, failed uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats, ok := r.operations[operationKey{resource: shortName, method: method}]
+	if !ok {
+		return 0, 0
+	}
+	for _, n := range stats.errors {
+		failed += n
+	}
+	return stats.buckets[len(DefaultBuckets)], failed
+}
+
+// methodName returns the name of the method of a full gRPC method name.
+func methodName(fullMethod string) string {
+	return fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+}
+
+// UnaryServerInterceptor records requests to resources.
+func (r *Recorder) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	named, ok := req.(interface{ GetName() string })
+	if !ok || named.GetName() == "" {
+		return handler(ctx, req)
+	}
+	start := clock.Now()
+	resp, err := handler(ctx, req)
+	r.Record(named.GetName(), methodName(info.FullMethod), clock.Since(start), err)
+	return resp, err
+}
+
+// StreamServerInterceptor records streams of requests to resources. A stream is one
+// operation, lasting until it ends, on the resource named by its first request.
+func (r *Recorder) StreamServerInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	stream := &namedStream{ServerStream: ss}
+	start := clock.Now()
+	err := handler(srv, stream)
+	if stream.name != "" {
+		r.Record(stream.name, methodName(info.FullMethod), clock.Since(start), err)
+	}
+	return err
+}
+
+type namedStream struct {
+	grpc.ServerStream
+	name string
+}
+
+func (s *namedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if named, ok := m.(interface{ GetName() string }); ok && s.name == "" {
+		s.name = named.GetName()
+	}
+	return nil
+}
+
+// ServeHTTP serves the recorded metrics in the Prometheus text exposition format.
+func (r *Recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = r.WriteTo(w)
+}
+
+// WriteTo writes the recorded metrics to w in the Prometheus text exposition format.
+func (r *Recorder) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	keys := make([]operationKey, 0, len(r.operations))
+	for key := range r.operations {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].resource != keys[j].resource {
+			return keys[i].resource < keys[j].resource
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	var b strings.Builder
+	writeHeader(&b, OperationsTotal, "counter", "Operations served by each resource, by gRPC code.")
+	for _, key := range keys {
+		stats := r.operations[key]
+		for _, code := range sortedKeys(stats.codes) {
+			writeSample(&b, OperationsTotal, key, [][2]string{{"code", code}}, float64(stats.codes[code]))
+		}
+	}
+	writeHeader(&b, OperationErrors, "counter", "Operations failed by each resource, by class of error.")
+	for _, key := range keys {
+		stats := r.operations[key]
+		for _, class := range sortedKeys(stats.errors) {
+			writeSample(&b, OperationErrors, key, [][2]string{{"class", string(class)}}, float64(stats.errors[class]))
+		}
+	}
+	writeHeader(&b, OperationDurations, "histogram", "Durations of the operations served by each resource.")
+	for _, key := range keys {
+		stats := r.operations[key]
+		for i, bound := range DefaultBuckets {
+			le := strconv.FormatFloat(bound, 'g', -1, 64)
+			writeSample(&b, OperationDurations+"_bucket", key, [][2]string{{"le", le}}, float64(stats.buckets[i]))
+		}
+		count := float64(stats.buckets[len(DefaultBuckets)])
+		writeSample(&b, OperationDurations+"_bucket", key, [][2]string{{"le", "+Inf"}}, count)
+		writeSample(&b, OperationDurations+"_sum", key, nil, stats.sum)
+		writeSample(&b, OperationDurations+"_count", key, nil, count)
+	}
+
+	modules := sortedKeys(r.modules)
+	for _, gauge := range []struct {
+		name, help string
+		value      func(ModuleUsage) float64
+	}{
+		{ModuleCPUPercent, "CPU usage of each module process as a percentage of one core.", func(u ModuleUsage) float64 { return u.CPUPercent }},
+		{ModuleMemoryMB, "Resident memory of each module process.", func(u ModuleUsage) float64 { return u.MemoryMB }},
+		{ModuleThrottle, "Fraction of the time each module process is paused for.", func(u ModuleUsage) float64 { return u.Throttle }},
+	} {
+		writeHeader(&b, gauge.name, "gauge", gauge.help)
+		for _, module := range modules {
+			writeLabeledSample(&b, gauge.name, [][2]string{{"module", module}}, gauge.value(r.modules[module]))
+		}
+	}
+	r.mu.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func sortedKeys[K ~string, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func writeSample(b *strings.Builder, name string, key operationKey, labels [][2]string, value float64) {
+	labels = append([][2]string{{"resource", key.resource}, {"method", key.method}}, labels...)
+	writeLabeledSample(b, name, labels, value)
+}
+
+func writeLabeledSample(b *strings.Builder, name string, labels [][2]string, value float64) {
+	b.WriteString(name)
+	for i, label := range labels {
+		sep := ","
+		if i == 0 {
+			sep = "{"
+		}
+		fmt.Fprintf(b, `%s%s="%s"`, sep, label[0], labelValueReplacer.Replace(label[1]))
+	}
+	if len(labels) > 0 {
+		b.WriteString("}")
+	}
+	fmt.Fprintf(b, " %s\n", strconv.FormatFloat(value, 'g', -1, 64))
+}
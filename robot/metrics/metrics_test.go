@@ -0,0 +1,141 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	armpb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/metrics"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	recorder.Record("arm1", "GetEndPosition", 20*time.Millisecond, nil)
+	recorder.Record("arm1", "GetEndPosition", 2*time.Second, nil)
+	recorder.Record("arm1", "GetEndPosition", time.Millisecond,
+		resource.NewHardwareFaultError(errors.New("encoder disconnected")))
+	recorder.Record(`motor "1"`, "SetPower", time.Millisecond, status.Error(codes.DeadlineExceeded, "too slow"))
+
+	total, failed := recorder.Operations("arm1", "GetEndPosition")
+	test.That(t, total, test.ShouldEqual, 3)
+	test.That(t, failed, test.ShouldEqual, 1)
+	total, failed = recorder.Operations("arm1", "MoveToPosition")
+	test.That(t, total, test.ShouldEqual, 0)
+	test.That(t, failed, test.ShouldEqual, 0)
+
+	var b strings.Builder
+	_, err := recorder.WriteTo(&b)
+	test.That(t, err, test.ShouldBeNil)
+	out := b.String()
+	for _, line := range []string{
+		"# TYPE rdk_resource_operations_total counter",
+		`rdk_resource_operations_total{resource="arm1",method="GetEndPosition",code="OK"} 2`,
+		`rdk_resource_operations_total{resource="arm1",method="GetEndPosition",code="Unknown"} 1`,
+		`rdk_resource_operations_total{resource="motor \"1\"",method="SetPower",code="DeadlineExceeded"} 1`,
+		"# TYPE rdk_resource_operation_errors_total counter",
+		`rdk_resource_operation_errors_total{resource="arm1",method="GetEndPosition",class="hardware_fault"} 1`,
+		`rdk_resource_operation_errors_total{resource="motor \"1\"",method="SetPower",class="timeout"} 1`,
+		"# TYPE rdk_resource_operation_duration_seconds histogram",
+		`rdk_resource_operation_duration_seconds_bucket{resource="arm1",method="GetEndPosition",le="0.005"} 1`,
+		`rdk_resource_operation_duration_seconds_bucket{resource="arm1",method="GetEndPosition",le="0.025"} 2`,
+		`rdk_resource_operation_duration_seconds_bucket{resource="arm1",method="GetEndPosition",le="2.5"} 3`,
+		`rdk_resource_operation_duration_seconds_bucket{resource="arm1",method="GetEndPosition",le="+Inf"} 3`,
+		`rdk_resource_operation_duration_seconds_sum{resource="arm1",method="GetEndPosition"} 2.021`,
+		`rdk_resource_operation_duration_seconds_count{resource="arm1",method="GetEndPosition"} 3`,
+	} {
+		test.That(t, out, test.ShouldContainSubstring, line+"\n")
+	}
+
+	w := httptest.NewRecorder()
+	recorder.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	test.That(t, w.Code, test.ShouldEqual, http.StatusOK)
+	test.That(t, w.Header().Get("Content-Type"), test.ShouldEqual, metrics.ContentType)
+	test.That(t, w.Body.String(), test.ShouldEqual, out)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	recorder := metrics.NewRecorder()
+	info := &grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/GetEndPosition"}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		virtual.Step(30 * time.Millisecond)
+		return &armpb.GetEndPositionResponse{}, nil
+	}
+	_, err := recorder.UnaryServerInterceptor(context.Background(), &armpb.GetEndPositionRequest{Name: "arm1"}, info, handler)
+	test.That(t, err, test.ShouldBeNil)
+
+	// requests that do not name a resource are not recorded.
+	_, err = recorder.UnaryServerInterceptor(context.Background(), &armpb.GetEndPositionRequest{}, info, handler)
+	test.That(t, err, test.ShouldBeNil)
+
+	failing := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("arm not homed")
+	}
+	_, err = recorder.UnaryServerInterceptor(context.Background(), &armpb.GetEndPositionRequest{Name: "arm1"}, info, failing)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	total, failed := recorder.Operations("arm1", "GetEndPosition")
+	test.That(t, total, test.ShouldEqual, 2)
+	test.That(t, failed, test.ShouldEqual, 1)
+
+	var b strings.Builder
+	_, err = recorder.WriteTo(&b)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, b.String(), test.ShouldContainSubstring,
+		`rdk_resource_operation_duration_seconds_bucket{resource="arm1",method="GetEndPosition",le="0.025"} 1`+"\n")
+	test.That(t, b.String(), test.ShouldContainSubstring,
+		`rdk_resource_operation_duration_seconds_bucket{resource="arm1",method="GetEndPosition",le="0.05"} 2`+"\n")
+	test.That(t, b.String(), test.ShouldNotContainSubstring, `resource=""`)
+}
+
+func TestModuleUsage(t *testing.T) {
+	recorder := metrics.NewRecorder()
+	_, ok := recorder.ModuleUsage("mod1")
+	test.That(t, ok, test.ShouldBeFalse)
+
+	recorder.RecordModuleUsage("mod1", metrics.ModuleUsage{CPUPercent: 12.5, MemoryMB: 64, Throttle: 0.25})
+	recorder.RecordModuleUsage("mod2", metrics.ModuleUsage{CPUPercent: 1, MemoryMB: 32})
+	usage, ok := recorder.ModuleUsage("mod1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, usage, test.ShouldResemble, metrics.ModuleUsage{CPUPercent: 12.5, MemoryMB: 64, Throttle: 0.25})
+
+	var b strings.Builder
+	_, err := recorder.WriteTo(&b)
+	test.That(t, err, test.ShouldBeNil)
+	for _, line := range []string{
+		"# TYPE rdk_module_cpu_percent gauge",
+		`rdk_module_cpu_percent{module="mod1"} 12.5`,
+		`rdk_module_cpu_percent{module="mod2"} 1`,
+		"# TYPE rdk_module_memory_megabytes gauge",
+		`rdk_module_memory_megabytes{module="mod1"} 64`,
+		"# TYPE rdk_module_throttle_ratio gauge",
+		`rdk_module_throttle_ratio{module="mod1"} 0.25`,
+		`rdk_module_throttle_ratio{module="mod2"} 0`,
+	} {
+		test.That(t, b.String(), test.ShouldContainSubstring, line+"\n")
+	}
+
+	// removed modules are no longer exposed.
+	recorder.ForgetModule("mod1")
+	_, ok = recorder.ModuleUsage("mod1")
+	test.That(t, ok, test.ShouldBeFalse)
+	b.Reset()
+	_, err = recorder.WriteTo(&b)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, b.String(), test.ShouldNotContainSubstring, `module="mod1"`)
+	test.That(t, b.String(), test.ShouldContainSubstring, `rdk_module_memory_megabytes{module="mod2"} 32`+"\n")
+}
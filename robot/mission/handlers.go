@@ -0,0 +1,98 @@
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/transaction"
+)
+
+// The kinds of tasks every robot can run.
+const (
+	// KindWait waits for "seconds", such as between the rounds of a patrol.
+	KindWait = "wait"
+	// KindDoCommand sends "command" to the DoCommand of the resource named "resource".
+	KindDoCommand = "do_command"
+	// KindTransaction executes a transaction of "commands" within "window_sec", as
+	// described by the transaction package, and fails if any command does.
+	KindTransaction = "transaction"
+)
+
+func init() {
+	RegisterHandler(KindWait, waitHandler)
+	RegisterHandler(KindDoCommand, doCommandHandler)
+	RegisterHandler(KindTransaction, transactionHandler)
+}
+
+// decodeParams decodes the params of a task into the given struct by their JSON form.
+func decodeParams(params map[string]interface{}, into interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, into); err != nil {
+		return errors.Wrap(err, "invalid task params")
+	}
+	return nil
+}
+
+func waitHandler(ctx context.Context, r robot.Robot, params map[string]interface{}) (map[string]interface{}, error) {
+	var p struct {
+		Seconds float64 `json:"seconds"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	if !clock.SleepContext(ctx, time.Duration(p.Seconds*float64(time.Second))) {
+		return nil, ctx.Err()
+	}
+	return nil, nil
+}
+
+func doCommandHandler(ctx context.Context, r robot.Robot, params map[string]interface{}) (map[string]interface{}, error) {
+	var p struct {
+		Resource string                 `json:"resource"`
+		Command  map[string]interface{} `json:"command"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	resources := robot.AllResourcesByName(r, p.Resource)
+	switch len(resources) {
+	case 0:
+		return nil, errors.Errorf("no resource named %q", p.Resource)
+	case 1:
+		return resources[0].DoCommand(ctx, p.Command)
+	default:
+		return nil, errors.Errorf("more than one resource is named %q", p.Resource)
+	}
+}
+
+func transactionHandler(ctx context.Context, r robot.Robot, params map[string]interface{}) (map[string]interface{}, error) {
+	var p struct {
+		Commands  []transaction.Command `json:"commands"`
+		WindowSec float64               `json:"window_sec"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+	results, err := transaction.NewExecutor(r, nil).Execute(ctx, transaction.Batch{
+		Commands: p.Commands,
+		Window:   time.Duration(p.WindowSec * float64(time.Second)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var failed error
+	for _, result := range results {
+		if result.Error != "" && failed == nil {
+			failed = errors.Errorf("%s.%s failed: %s", result.Resource, result.Method, result.Error)
+		}
+	}
+	return map[string]interface{}{"results": results}, failed
+}
@@ -0,0 +1,425 @@
+// Package mission runs long-running tasks on a robot, such as patrols and deliveries.
+//
+// Clients enqueue named tasks, each of a kind with a registered Handler, and the robot runs
+// them one at a time: the task with the highest priority first, and tasks of the same
+// priority in the order they were enqueued. The queue and the history of finished tasks are
+// kept in the robot's key-value store so that they survive restarts. A task that was running
+// when the robot stopped is failed rather than run again, since it may have been partly
+// carried out.
+package mission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/kv"
+)
+
+const (
+	// Namespace is the namespace of the key-value store holding the tasks.
+	Namespace = "missions"
+	// MaxHistory is how many finished tasks are kept.
+	MaxHistory = 100
+)
+
+// A State is the state of a task.
+type State string
+
+// The states of a task.
+const (
+	StateQueued    State = "queued"
+	StateRunning   State = "running"
+	StateSucceeded State = "succeeded"
+	StateFailed    State = "failed"
+	StateCanceled  State = "canceled"
+)
+
+// Finished returns whether a task in this state is done running.
+func (s State) Finished() bool {
+	return s == StateSucceeded || s == StateFailed || s == StateCanceled
+}
+
+// A Request asks for a task to be run.
+type Request struct {
+	// Name describes the task to people, such as "patrol the warehouse".
+	Name string `json:"name"`
+	// Kind is the kind of the task, which must have a registered Handler.
+	Kind string `json:"kind"`
+	// Params are passed to the handler of the kind.
+	Params map[string]interface{} `json:"params,omitempty"`
+	// Priority orders the queue. Tasks with a higher priority run first.
+	Priority int `json:"priority,omitempty"`
+}
+
+// A Task is a request enqueued on a robot.
+type Task struct {
+	Request
+	ID    string `json:"id"`
+	State State  `json:"state"`
+	// Error is why the task failed, if it did.
+	Error string `json:"error,omitempty"`
+	// Result is what the handler of the task returned, if it succeeded.
+	Result map[string]interface{} `json:"result,omitempty"`
+	// Seq orders tasks of the same priority by when they were enqueued.
+	Seq        uint64    `json:"seq"`
+	EnqueuedAt time.Time `json:"enqueued_at"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// A Handler runs tasks of one kind on the robot and returns their result. It must return
+// soon after the context is done, which happens when the task is canceled.
+type Handler func(ctx context.Context, r robot.Robot, params map[string]interface{}) (map[string]interface{}, error)
+
+var (
+	handlersMu sync.RWMutex
+	handlers   = map[string]Handler{}
+)
+
+// RegisterHandler registers the handler of a kind of task. It panics if the kind is
+// already registered.
+func RegisterHandler(kind string, handler Handler) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	if _, ok := handlers[kind]; ok {
+		panic(errors.Errorf("mission handler for kind %q already registered", kind))
+	}
+	handlers[kind] = handler
+}
+
+// DeregisterHandler removes the handler of a kind of task. It is only meant for tests.
+func DeregisterHandler(kind string) {
+	handlersMu.Lock()
+	defer handlersMu.Unlock()
+	delete(handlers, kind)
+}
+
+func lookupHandler(kind string) (Handler, bool) {
+	handlersMu.RLock()
+	defer handlersMu.RUnlock()
+	handler, ok := handlers[kind]
+	return handler, ok
+}
+
+// A Service enqueues, lists and cancels the tasks of a robot, either directly or over a
+// connection to it.
+type Service interface {
+	// Enqueue enqueues a task and returns it.
+	Enqueue(ctx context.Context, req Request) (Task, error)
+	// Task returns the task with the given ID.
+	Task(ctx context.Context, id string) (Task, error)
+	// Tasks returns the tasks in any of the given states, or all tasks if none are given,
+	// in the order they were enqueued.
+	Tasks(ctx context.Context, states ...State) ([]Task, error)
+	// Cancel cancels a queued or running task.
+	Cancel(ctx context.Context, id string) error
+}
+
+// A Robot is a robot with a queue of tasks.
+type Robot interface {
+	robot.Robot
+	// Missions returns the queue of tasks of the robot.
+	Missions() *Queue
+}
+
+// A Queue is the Service of a robot, running its tasks.
+type Queue struct {
+	r      robot.Robot
+	store  kv.Service
+	logger logging.Logger
+
+	mu            sync.Mutex
+	started       bool
+	tasks         map[string]*Task
+	nextSeq       uint64
+	cancelRunning context.CancelFunc
+	canceled      bool
+	wake          chan struct{}
+
+	cancelCtx context.Context
+	cancel    context.CancelFunc
+	workers   sync.WaitGroup
+}
+
+var _ Service = (*Queue)(nil)
+
+// NewQueue returns a queue running tasks on the given robot and keeping them in the given
+// store. It runs no task until started.
+func NewQueue(r robot.Robot, store kv.Service, logger logging.Logger) *Queue {
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	return &Queue{
+		r:         r,
+		store:     store,
+		logger:    logger,
+		tasks:     map[string]*Task{},
+		wake:      make(chan struct{}, 1),
+		cancelCtx: cancelCtx,
+		cancel:    cancel,
+	}
+}
+
+// Start loads the tasks kept in the store and starts running them.
+func (q *Queue) Start(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.started {
+		return errors.New("mission queue already started")
+	}
+	ids, err := q.store.Keys(ctx, Namespace)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		data, ok, err := q.store.Get(ctx, Namespace, id)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		var task Task
+		if err := json.Unmarshal(data, &task); err != nil {
+			q.logger.CWarnw(ctx, "dropping unreadable task", "id", id, "error", err)
+			continue
+		}
+		if task.State == StateRunning {
+			q.finish(&task, StateFailed, nil, errors.New("interrupted by a restart of the robot"))
+		}
+		if _, ok := q.tasks[task.ID]; !ok {
+			q.tasks[task.ID] = &task
+		}
+		if task.Seq >= q.nextSeq {
+			q.nextSeq = task.Seq + 1
+		}
+	}
+	q.started = true
+	q.workers.Add(1)
+	utils.ManagedGo(q.run, q.workers.Done)
+	return nil
+}
+
+// save writes the task to the store. It must be called with the queue locked.
+func (q *Queue) save(task *Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	return q.store.Set(context.Background(), Namespace, task.ID, data)
+}
+
+// finish records how the task ended and prunes the history. It must be called with the
+// queue locked.
+func (q *Queue) finish(task *Task, state State, result map[string]interface{}, err error) {
+	task.State = state
+	task.Result = result
+	if err != nil {
+		task.Error = err.Error()
+	}
+	task.FinishedAt = clock.Now()
+	q.tasks[task.ID] = task
+	if err := q.save(task); err != nil {
+		q.logger.Warnw("failed to save task", "id", task.ID, "error", err)
+	}
+
+	var finished []*Task
+	for _, t := range q.tasks {
+		if t.State.Finished() {
+			finished = append(finished, t)
+		}
+	}
+	if len(finished) <= MaxHistory {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(finished[j].FinishedAt)
+	})
+	for _, t := range finished[:len(finished)-MaxHistory] {
+		delete(q.tasks, t.ID)
+		if err := q.store.Delete(context.Background(), Namespace, t.ID); err != nil {
+			q.logger.Warnw("failed to delete finished task", "id", t.ID, "error", err)
+		}
+	}
+}
+
+// Enqueue enqueues a task and returns it.
+func (q *Queue) Enqueue(ctx context.Context, req Request) (Task, error) {
+	if req.Kind == "" {
+		return Task{}, status.Error(codes.InvalidArgument, "task kind must not be empty")
+	}
+	if _, ok := lookupHandler(req.Kind); !ok {
+		return Task{}, status.Errorf(codes.InvalidArgument, "unknown task kind %q", req.Kind)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task := &Task{
+		Request:    req,
+		ID:         uuid.NewString(),
+		State:      StateQueued,
+		Seq:        q.nextSeq,
+		EnqueuedAt: clock.Now(),
+	}
+	if err := q.save(task); err != nil {
+		return Task{}, errors.Wrap(err, "failed to save task")
+	}
+	q.nextSeq++
+	q.tasks[task.ID] = task
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return *task, nil
+}
+
+// Task returns the task with the given ID.
+func (q *Queue) Task(ctx context.Context, id string) (Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task, ok := q.tasks[id]
+	if !ok {
+		return Task{}, status.Errorf(codes.NotFound, "no task with id %q", id)
+	}
+	return *task, nil
+}
+
+// Tasks returns the tasks in any of the given states, or all tasks if none are given, in
+// the order they were enqueued.
+func (q *Queue) Tasks(ctx context.Context, states ...State) ([]Task, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	tasks := []Task{}
+	for _, task := range q.tasks {
+		if len(states) == 0 || containsState(states, task.State) {
+			tasks = append(tasks, *task)
+		}
+	}
+	sort.Slice(tasks, func(i, j int) bool {
+		return tasks[i].Seq < tasks[j].Seq
+	})
+	return tasks, nil
+}
+
+func containsState(states []State, state State) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Cancel cancels a queued or running task. A running task is canceled once its handler
+// returns.
+func (q *Queue) Cancel(ctx context.Context, id string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	task, ok := q.tasks[id]
+	if !ok {
+		return status.Errorf(codes.NotFound, "no task with id %q", id)
+	}
+	switch task.State {
+	case StateQueued:
+		q.finish(task, StateCanceled, nil, nil)
+	case StateRunning:
+		q.canceled = true
+		q.cancelRunning()
+	case StateSucceeded, StateFailed, StateCanceled:
+		return status.Errorf(codes.FailedPrecondition, "task %q already %s", id, task.State)
+	}
+	return nil
+}
+
+// next returns the queued task to run next, if any. It must be called with the queue
+// locked.
+func (q *Queue) next() *Task {
+	var next *Task
+	for _, task := range q.tasks {
+		if task.State != StateQueued {
+			continue
+		}
+		if next == nil || task.Priority > next.Priority ||
+			(task.Priority == next.Priority && task.Seq < next.Seq) {
+			next = task
+		}
+	}
+	return next
+}
+
+func (q *Queue) run() {
+	for {
+		q.mu.Lock()
+		task := q.next()
+		if task == nil {
+			q.mu.Unlock()
+			select {
+			case <-q.cancelCtx.Done():
+				return
+			case <-q.wake:
+			}
+			continue
+		}
+		ctx, cancel := context.WithCancel(q.cancelCtx)
+		task.State = StateRunning
+		task.StartedAt = clock.Now()
+		q.cancelRunning = cancel
+		q.canceled = false
+		if err := q.save(task); err != nil {
+			q.logger.Warnw("failed to save task", "id", task.ID, "error", err)
+		}
+		q.mu.Unlock()
+
+		q.logger.Infow("running task", "id", task.ID, "name", task.Name, "kind", task.Kind)
+		result, err := q.runTask(ctx, task)
+		cancel()
+
+		q.mu.Lock()
+		switch {
+		case q.canceled:
+			q.finish(task, StateCanceled, nil, nil)
+		case q.cancelCtx.Err() != nil:
+			q.finish(task, StateFailed, nil, errors.New("interrupted by the robot shutting down"))
+		case err != nil:
+			q.finish(task, StateFailed, nil, err)
+		default:
+			q.finish(task, StateSucceeded, result, nil)
+		}
+		q.logger.Infow("task finished", "id", task.ID, "name", task.Name, "state", task.State, "error", task.Error)
+		q.cancelRunning = nil
+		q.mu.Unlock()
+		if q.cancelCtx.Err() != nil {
+			return
+		}
+	}
+}
+
+func (q *Queue) runTask(ctx context.Context, task *Task) (result map[string]interface{}, err error) {
+	handler, ok := lookupHandler(task.Kind)
+	if !ok {
+		return nil, errors.Errorf("unknown task kind %q", task.Kind)
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task panicked: %v", r)
+		}
+	}()
+	return handler(ctx, q.r, task.Params)
+}
+
+// Close stops running tasks. A running task is failed as interrupted.
+func (q *Queue) Close(ctx context.Context) error {
+	q.cancel()
+	q.workers.Wait()
+	return nil
+}
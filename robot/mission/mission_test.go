@@ -0,0 +1,283 @@
+package mission_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/gripper"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/mission"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func setupRobot(t *testing.T) *inject.Robot {
+	t.Helper()
+	logger := logging.NewTestLogger(t)
+	gripper1 := inject.NewGripper("gripper1")
+	gripper1.DoFunc = func(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
+		return map[string]interface{}{"echo": cmd["say"]}, nil
+	}
+	return inject.NewRobot(logger, map[resource.Name]resource.Resource{gripper.Named("gripper1"): gripper1})
+}
+
+// recorder is a kind of task that records the names of the tasks it runs, and runs until
+// released if their params ask it to block.
+type recorder struct {
+	mu      sync.Mutex
+	ran     []string
+	release chan struct{}
+}
+
+func registerRecorder(t *testing.T) *recorder {
+	t.Helper()
+	rec := &recorder{release: make(chan struct{})}
+	mission.RegisterHandler("record", func(
+		ctx context.Context,
+		r robot.Robot,
+		params map[string]interface{},
+	) (map[string]interface{}, error) {
+		rec.mu.Lock()
+		rec.ran = append(rec.ran, params["name"].(string))
+		rec.mu.Unlock()
+		if params["block"] == true {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-rec.release:
+			}
+		}
+		return map[string]interface{}{"done": params["name"]}, nil
+	})
+	t.Cleanup(func() {
+		mission.DeregisterHandler("record")
+	})
+	return rec
+}
+
+func (rec *recorder) recorded() []string {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return append([]string(nil), rec.ran...)
+}
+
+func waitForState(t *testing.T, queue *mission.Queue, id string, state mission.State) mission.Task {
+	t.Helper()
+	var task mission.Task
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		var err error
+		task, err = queue.Task(context.Background(), id)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, task.State, test.ShouldEqual, state)
+	})
+	return task
+}
+
+func record(name string, priority int, block bool) mission.Request {
+	return mission.Request{
+		Name:     name,
+		Kind:     "record",
+		Params:   map[string]interface{}{"name": name, "block": block},
+		Priority: priority,
+	}
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	rec := registerRecorder(t)
+	r := setupRobot(t)
+	queue := mission.NewQueue(r, kv.NewStore("", logger), logger)
+	defer func() {
+		test.That(t, queue.Close(ctx), test.ShouldBeNil)
+	}()
+
+	// tasks run by priority, then in the order they were enqueued.
+	first, err := queue.Enqueue(ctx, record("first", 0, true))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, first.State, test.ShouldEqual, mission.StateQueued)
+	for _, req := range []mission.Request{record("low", 0, false), record("high", 5, false), record("low2", 0, false)} {
+		_, err := queue.Enqueue(ctx, req)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	canceled, err := queue.Enqueue(ctx, record("canceled", 10, false))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, queue.Cancel(ctx, canceled.ID), test.ShouldBeNil)
+
+	// the highest priority task runs first once the queue starts.
+	test.That(t, queue.Start(ctx), test.ShouldBeNil)
+	waitForState(t, queue, first.ID, mission.StateRunning)
+	close(rec.release)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		queued, err := queue.Tasks(ctx, mission.StateQueued, mission.StateRunning)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, queued, test.ShouldBeEmpty)
+	})
+	test.That(t, rec.recorded(), test.ShouldResemble, []string{"high", "first", "low", "low2"})
+
+	task, err := queue.Task(ctx, first.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, task.State, test.ShouldEqual, mission.StateSucceeded)
+	test.That(t, task.Result, test.ShouldResemble, map[string]interface{}{"done": "first"})
+	test.That(t, task.FinishedAt.Before(task.StartedAt), test.ShouldBeFalse)
+	task, err = queue.Task(ctx, canceled.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, task.State, test.ShouldEqual, mission.StateCanceled)
+	all, err := queue.Tasks(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, all, test.ShouldHaveLength, 5)
+	test.That(t, all[0].ID, test.ShouldEqual, first.ID)
+
+	t.Run("running tasks can be canceled", func(t *testing.T) {
+		rec.release = make(chan struct{})
+		running, err := queue.Enqueue(ctx, record("running", 0, true))
+		test.That(t, err, test.ShouldBeNil)
+		waitForState(t, queue, running.ID, mission.StateRunning)
+		test.That(t, queue.Cancel(ctx, running.ID), test.ShouldBeNil)
+		waitForState(t, queue, running.ID, mission.StateCanceled)
+
+		err = queue.Cancel(ctx, running.ID)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+		err = queue.Cancel(ctx, "missing")
+		test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+	})
+
+	t.Run("builtin kinds", func(t *testing.T) {
+		task, err := queue.Enqueue(ctx, mission.Request{
+			Kind:   mission.KindDoCommand,
+			Params: map[string]interface{}{"resource": "gripper1", "command": map[string]interface{}{"say": "hi"}},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		task = waitForState(t, queue, task.ID, mission.StateSucceeded)
+		test.That(t, task.Result, test.ShouldResemble, map[string]interface{}{"echo": "hi"})
+
+		task, err = queue.Enqueue(ctx, mission.Request{
+			Kind:   mission.KindDoCommand,
+			Params: map[string]interface{}{"resource": "gripper2"},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		task = waitForState(t, queue, task.ID, mission.StateFailed)
+		test.That(t, task.Error, test.ShouldContainSubstring, "gripper2")
+
+		_, err = queue.Enqueue(ctx, mission.Request{Kind: "fly"})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	})
+
+	t.Run("history is bounded", func(t *testing.T) {
+		for i := 0; i < mission.MaxHistory; i++ {
+			_, err := queue.Enqueue(ctx, mission.Request{Kind: mission.KindWait})
+			test.That(t, err, test.ShouldBeNil)
+		}
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			queued, err := queue.Tasks(ctx, mission.StateQueued, mission.StateRunning)
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, queued, test.ShouldBeEmpty)
+		})
+		all, err := queue.Tasks(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, all, test.ShouldHaveLength, mission.MaxHistory)
+		_, err = queue.Task(ctx, first.ID)
+		test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+	})
+}
+
+func TestQueuePersists(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	rec := registerRecorder(t)
+	r := setupRobot(t)
+	path := filepath.Join(t.TempDir(), kv.FileName)
+
+	store := kv.NewStore(path, logger)
+	queue := mission.NewQueue(r, store, logger)
+	queued, err := queue.Enqueue(ctx, record("queued", 0, false))
+	test.That(t, err, test.ShouldBeNil)
+	// a task the robot was running when it stopped.
+	interrupted := mission.Task{
+		Request: record("interrupted", 0, false),
+		ID:      "interrupted",
+		State:   mission.StateRunning,
+		Seq:     7,
+	}
+	data, err := json.Marshal(interrupted)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, store.Set(ctx, mission.Namespace, interrupted.ID, data), test.ShouldBeNil)
+	test.That(t, queue.Close(ctx), test.ShouldBeNil)
+	test.That(t, store.Close(), test.ShouldBeNil)
+
+	store = kv.NewStore(path, logger)
+	defer func() {
+		test.That(t, store.Close(), test.ShouldBeNil)
+	}()
+	queue = mission.NewQueue(r, store, logger)
+	defer func() {
+		test.That(t, queue.Close(ctx), test.ShouldBeNil)
+	}()
+	test.That(t, queue.Start(ctx), test.ShouldBeNil)
+	waitForState(t, queue, queued.ID, mission.StateSucceeded)
+	task := waitForState(t, queue, interrupted.ID, mission.StateFailed)
+	test.That(t, task.Error, test.ShouldContainSubstring, "restart")
+	test.That(t, rec.recorded(), test.ShouldResemble, []string{"queued"})
+
+	// tasks enqueued after a restart come after those enqueued before it.
+	next, err := queue.Enqueue(ctx, mission.Request{Kind: mission.KindWait})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, next.Seq, test.ShouldBeGreaterThan, interrupted.Seq)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	registerRecorder(t)
+	r := setupRobot(t)
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+	missions := robotClient.Missions()
+
+	task, err := missions.Enqueue(ctx, record("patrol", 1, false))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, task.Name, test.ShouldEqual, "patrol")
+	test.That(t, task.State, test.ShouldEqual, mission.StateQueued)
+
+	got, err := missions.Task(ctx, task.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got.ID, test.ShouldEqual, task.ID)
+	test.That(t, got.Params, test.ShouldResemble, task.Params)
+	test.That(t, got.EnqueuedAt.Equal(task.EnqueuedAt), test.ShouldBeTrue)
+
+	test.That(t, missions.Cancel(ctx, task.ID), test.ShouldBeNil)
+	tasks, err := missions.Tasks(ctx, mission.StateCanceled)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tasks, test.ShouldHaveLength, 1)
+	tasks, err = missions.Tasks(ctx, mission.StateQueued)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, tasks, test.ShouldBeEmpty)
+
+	_, err = missions.Task(ctx, "missing")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+	_, err = missions.Enqueue(ctx, mission.Request{Kind: "fly"})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+
+	// the robot's own queue holds tasks enqueued over the client.
+	local, err := r.Missions().Task(ctx, task.ID)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, local.State, test.ShouldEqual, mission.StateCanceled)
+}
@@ -0,0 +1,105 @@
+package mission
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a queue. Its requests and responses
+// are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.mission.v1.MissionService"
+
+type idRequest struct {
+	ID string `json:"id"`
+}
+
+type listRequest struct {
+	States []State `json:"states,omitempty"`
+}
+
+type listResponse struct {
+	Tasks []Task `json:"tasks"`
+}
+
+// A Server serves a queue with ServiceDesc.
+type Server struct {
+	queue Service
+}
+
+// NewServer returns a server for the given queue.
+func NewServer(queue Service) *Server {
+	return &Server{queue: queue}
+}
+
+func (s *Server) enqueue(ctx context.Context, req Request) (interface{}, error) {
+	return s.queue.Enqueue(ctx, req)
+}
+
+func (s *Server) getTask(ctx context.Context, req idRequest) (interface{}, error) {
+	return s.queue.Task(ctx, req.ID)
+}
+
+func (s *Server) listTasks(ctx context.Context, req listRequest) (interface{}, error) {
+	tasks, err := s.queue.Tasks(ctx, req.States...)
+	return listResponse{Tasks: tasks}, err
+}
+
+func (s *Server) cancelTask(ctx context.Context, req idRequest) (interface{}, error) {
+	return struct{}{}, s.queue.Cancel(ctx, req.ID)
+}
+
+// ServiceDesc describes the gRPC service serving a queue. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/mission",
+	structrpc.Unary("Enqueue", (*Server).enqueue),
+	structrpc.Unary("GetTask", (*Server).getTask),
+	structrpc.Unary("ListTasks", (*Server).listTasks),
+	structrpc.Unary("CancelTask", (*Server).cancelTask),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the queue served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Enqueue enqueues a task and returns it.
+func (c *Client) Enqueue(ctx context.Context, req Request) (Task, error) {
+	var task Task
+	if err := c.client.Invoke(ctx, "Enqueue", req, &task); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+// Task returns the task with the given ID.
+func (c *Client) Task(ctx context.Context, id string) (Task, error) {
+	var task Task
+	if err := c.client.Invoke(ctx, "GetTask", idRequest{ID: id}, &task); err != nil {
+		return Task{}, err
+	}
+	return task, nil
+}
+
+// Tasks returns the tasks in any of the given states, or all tasks if none are given, in
+// the order they were enqueued.
+func (c *Client) Tasks(ctx context.Context, states ...State) ([]Task, error) {
+	var resp listResponse
+	if err := c.client.Invoke(ctx, "ListTasks", listRequest{States: states}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tasks, nil
+}
+
+// Cancel cancels a queued or running task.
+func (c *Client) Cancel(ctx context.Context, id string) error {
+	return c.client.Invoke(ctx, "CancelTask", idRequest{ID: id}, nil)
+}
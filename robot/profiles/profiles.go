@@ -0,0 +1,68 @@
+// Package profiles switches a robot between the runtime profiles of its config, such as a
+// "patrol" profile that lowers camera frame rates, capture rates, and the CPU frequency to
+// extend the battery life of a mobile robot, and an "inspect" profile that restores them.
+//
+// A profile sets attributes of the robot's components and services, as described by
+// config.ProfileConfig, and is applied through the robot's usual reconfiguration, so that all
+// of its settings change at once. The robot keeps the profile it is switched to across
+// configs it receives until the profile of its config changes.
+package profiles
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/robot"
+)
+
+// Status is the profile a robot is switched to and the profiles it can switch to.
+type Status struct {
+	// Active is the name of the profile the robot is switched to, or empty if none.
+	Active string `json:"active"`
+	// Profiles are the names of the profiles of the robot's config.
+	Profiles []string `json:"profiles"`
+}
+
+// A Service switches the profile of a robot.
+type Service interface {
+	// Profiles returns the profile the robot is switched to and the profiles it can switch to.
+	Profiles(ctx context.Context) (*Status, error)
+	// SetProfile switches the robot to the named profile, or to none if the name is empty, and
+	// returns its status once reconfigured.
+	SetProfile(ctx context.Context, name string) (*Status, error)
+}
+
+// A Robot is a robot whose profile can be switched.
+type Robot interface {
+	robot.Robot
+	Service
+}
+
+// cpuGovernorFiles matches the files setting the CPU frequency governor of each CPU on Linux.
+var cpuGovernorFiles = "/sys/devices/system/cpu/cpu*/cpufreq/scaling_governor"
+
+// SetCPUGovernor sets the CPU frequency governor of every CPU, such as "powersave" or
+// "performance". It fails where CPU frequency scaling is not available, such as outside of
+// Linux.
+func SetCPUGovernor(governor string) error {
+	files, err := filepath.Glob(cpuGovernorFiles)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return errors.New("CPU frequency scaling is not available")
+	}
+	var errs error
+	for _, file := range files {
+		//nolint:gosec
+		if err := os.WriteFile(file, []byte(governor), 0o644); err != nil {
+			cpu := filepath.Base(filepath.Dir(filepath.Dir(file)))
+			errs = multierr.Combine(errs, errors.Wrapf(err, "failed to set the CPU frequency governor of %s", cpu))
+		}
+	}
+	return errs
+}
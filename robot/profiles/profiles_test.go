@@ -0,0 +1,33 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.viam.com/test"
+)
+
+func TestSetCPUGovernor(t *testing.T) {
+	dir := t.TempDir()
+	prevFiles := cpuGovernorFiles
+	defer func() {
+		cpuGovernorFiles = prevFiles
+	}()
+	cpuGovernorFiles = filepath.Join(dir, "cpu*", "cpufreq", "scaling_governor")
+
+	err := SetCPUGovernor("powersave")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not available")
+
+	for _, cpu := range []string{"cpu0", "cpu1"} {
+		test.That(t, os.MkdirAll(filepath.Join(dir, cpu, "cpufreq"), 0o755), test.ShouldBeNil)
+		test.That(t, os.WriteFile(filepath.Join(dir, cpu, "cpufreq", "scaling_governor"), []byte("performance"), 0o600), test.ShouldBeNil)
+	}
+	test.That(t, SetCPUGovernor("powersave"), test.ShouldBeNil)
+	for _, cpu := range []string{"cpu0", "cpu1"} {
+		data, err := os.ReadFile(filepath.Join(dir, cpu, "cpufreq", "scaling_governor"))
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, string(data), test.ShouldEqual, "powersave")
+	}
+}
@@ -0,0 +1,85 @@
+package profiles
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service switching the profile of a robot. Its requests
+// and responses are google.protobuf.Struct messages holding the JSON form of the types below
+// and of Status.
+const ServiceName = "viam.rdk.profiles.v1.ProfilesService"
+
+// setProfileRequest names the profile to switch to.
+type setProfileRequest struct {
+	Name string `json:"name"`
+}
+
+// A Server serves the switching of a robot's profile with ServiceDesc.
+type Server struct {
+	service Service
+}
+
+// NewServer returns a server switching the profile of the given service.
+func NewServer(service Service) *Server {
+	return &Server{service: service}
+}
+
+func (s *Server) getProfiles(ctx context.Context, _ struct{}) (interface{}, error) {
+	return s.service.Profiles(ctx)
+}
+
+func (s *Server) setProfile(ctx context.Context, req setProfileRequest) (interface{}, error) {
+	st, err := s.service.SetProfile(ctx, req.Name)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return st, nil
+}
+
+// ServiceDesc describes the gRPC service switching the profile of a robot. It is served with
+// a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/profiles",
+	structrpc.Unary("GetProfiles", (*Server).getProfiles),
+	structrpc.Unary("SetProfile", (*Server).setProfile),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the profiles served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Profiles returns the profile the robot is switched to and the profiles it can switch to.
+func (c *Client) Profiles(ctx context.Context) (*Status, error) {
+	return c.invoke(ctx, "GetProfiles", struct{}{})
+}
+
+// SetProfile switches the robot to the named profile, or to none if the name is empty, and
+// returns its status once reconfigured. Switching to a profile the robot does not have fails
+// with code NotFound.
+func (c *Client) SetProfile(ctx context.Context, name string) (*Status, error) {
+	return c.invoke(ctx, "SetProfile", setProfileRequest{Name: name})
+}
+
+func (c *Client) invoke(ctx context.Context, method string, req interface{}) (*Status, error) {
+	var resp Status
+	if err := c.client.Invoke(ctx, method, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
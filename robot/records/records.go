@@ -0,0 +1,121 @@
+// Package records serves the records a robot keeps of its resources, such as their last
+// error and last stop, alongside the statuses returned by their APIs.
+package records
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/health"
+	"go.viam.com/rdk/robot/selftest"
+)
+
+// ServiceName is the name of the gRPC service serving the records of a robot's resources.
+// Its requests and responses are google.protobuf.Struct messages holding the JSON form of
+// the types below.
+const ServiceName = "viam.rdk.records.v1.RecordsService"
+
+// Records are the records a robot keeps of one of its resources.
+type Records struct {
+	// LastError is the last error returned by a request to the resource, if any.
+	LastError *health.ErrorRecord `json:"last_error,omitempty"`
+	// LastStop is when and why the resource was last stopped, if it was.
+	LastStop *health.StopRecord `json:"last_stop,omitempty"`
+	// SelfTest is the outcome of the resource in the last self-test, if it was tested.
+	SelfTest *selftest.Result `json:"self_test,omitempty"`
+}
+
+type request struct {
+	Names []string `json:"names,omitempty"`
+}
+
+type resourceRecords struct {
+	Name string `json:"name"`
+	Records
+}
+
+type response struct {
+	Resources []resourceRecords `json:"resources"`
+}
+
+// A Robot is a robot keeping records of its resources.
+type Robot interface {
+	robot.Robot
+	// Records returns the records of the given resources that have any, or of all resources
+	// with records if none are given.
+	Records(ctx context.Context, names []resource.Name) (map[resource.Name]Records, error)
+}
+
+// A Server serves the records of a robot's resources with ServiceDesc.
+type Server struct {
+	r Robot
+}
+
+// NewServer returns a server for the given robot.
+func NewServer(r Robot) *Server {
+	return &Server{r: r}
+}
+
+func (s *Server) getRecords(ctx context.Context, req request) (interface{}, error) {
+	names := make([]resource.Name, 0, len(req.Names))
+	for _, n := range req.Names {
+		name, err := resource.NewFromString(n)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		names = append(names, name)
+	}
+	records, err := s.r.Records(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	resp := response{Resources: []resourceRecords{}}
+	for name, rec := range records {
+		resp.Resources = append(resp.Resources, resourceRecords{Name: name.String(), Records: rec})
+	}
+	return resp, nil
+}
+
+// ServiceDesc describes the gRPC service serving the records of a robot's resources. It is
+// served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/records",
+	structrpc.Unary("GetRecords", (*Server).getRecords),
+)
+
+// A Client fetches the records of the resources of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the records served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Records returns the records of the given resources, or of all resources if none are
+// given. Resources without records are left out.
+func (c *Client) Records(ctx context.Context, names []resource.Name) (map[resource.Name]Records, error) {
+	req := request{Names: make([]string, 0, len(names))}
+	for _, name := range names {
+		req.Names = append(req.Names, name.String())
+	}
+	var resp response
+	if err := c.client.Invoke(ctx, "GetRecords", req, &resp); err != nil {
+		return nil, err
+	}
+	records := make(map[resource.Name]Records, len(resp.Resources))
+	for _, res := range resp.Resources {
+		name, err := resource.NewFromString(res.Name)
+		if err != nil {
+			return nil, err
+		}
+		records[name] = res.Records
+	}
+	return records, nil
+}
@@ -0,0 +1,75 @@
+package records_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/health"
+	"go.viam.com/rdk/robot/records"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	arm1, arm2 := arm.Named("arm1"), arm.Named("arm2")
+	lastError := &health.ErrorRecord{
+		Time:  time.Now().UTC(),
+		Error: "joint 3 out of range",
+		Class: resource.ErrorClassInvalidCommand,
+	}
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		StatusFunc: func(ctx context.Context, names []resource.Name) ([]robot.Status, error) {
+			var statuses []robot.Status
+			for _, name := range names {
+				statuses = append(statuses, robot.Status{Name: name, Status: map[string]interface{}{"is_moving": false}})
+			}
+			return statuses, nil
+		},
+		RecordsFunc: func(ctx context.Context, names []resource.Name) (map[resource.Name]records.Records, error) {
+			return map[resource.Name]records.Records{arm1: {LastError: lastError}}, nil
+		},
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		arm1: inject.NewArm(arm1.Name),
+		arm2: inject.NewArm(arm2.Name),
+	})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	recs, err := robotClient.Records(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, recs, test.ShouldResemble, map[resource.Name]records.Records{arm1: {LastError: lastError}})
+
+	// the robot client returns the records along with the statuses of the resources.
+	statuses, err := robotClient.Status(ctx, []resource.Name{arm1, arm2})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, statuses, test.ShouldHaveLength, 2)
+	test.That(t, statuses[0].LastError, test.ShouldResemble, lastError)
+	test.That(t, statuses[0].Status, test.ShouldResemble, map[string]interface{}{"is_moving": false})
+	test.That(t, statuses[1].LastError, test.ShouldBeNil)
+
+	r.Mu.Lock()
+	r.RecordsFunc = func(ctx context.Context, names []resource.Name) (map[resource.Name]records.Records, error) {
+		return nil, errors.New("no records")
+	}
+	r.Mu.Unlock()
+	_, err = robotClient.Status(ctx, []resource.Name{arm1})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "no records")
+}
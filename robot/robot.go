@@ -21,7 +21,9 @@ import (
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/health"
 	"go.viam.com/rdk/robot/packages"
+	"go.viam.com/rdk/robot/selftest"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	"go.viam.com/rdk/session"
 )
@@ -144,6 +146,25 @@ type Status struct {
 	Name             resource.Name
 	LastReconfigured time.Time
 	Status           interface{}
+	// LastError is the last error returned by a request to the resource, if any.
+	LastError *health.ErrorRecord
+	// LastStop is when and why the resource was last stopped, if it was.
+	LastStop *health.StopRecord
+	// SelfTest is the outcome of the resource in the last self-test, if it was tested.
+	SelfTest *selftest.Result
+}
+
+// An EffectiveConfig is the complete config a robot runs with, for finding out why it behaves
+// as it does. Its secrets are references rather than their values, and its cloud and auth
+// sections, which identify the robot, are left out.
+type EffectiveConfig struct {
+	// Config holds the resources the robot built, including the default services it adds,
+	// with their attributes as processed from includes, templates, and placeholders, and the
+	// robot-wide settings with their defaults applied.
+	Config *config.Config `json:"config"`
+	// RemoteResources are the names of the resources of each remote, prefixed with the name
+	// of the remote as the robot serves them.
+	RemoteResources map[string][]string `json:"remote_resources,omitempty"`
 }
 
 // RestartModuleRequest is a go mirror of a proto message.
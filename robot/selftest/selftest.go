@@ -0,0 +1,219 @@
+// Package selftest exercises the components of a robot with checks that are safe to run at
+// any time, such as reading a sensor once, querying the joint positions of an arm, or
+// grabbing a frame from a camera, and reports whether each passed so that operators know
+// the robot is functional before dispatching it.
+//
+// The check of a component is the SelfTest function of its API registration. Components
+// whose API has none are reported as skipped, and components that are unavailable fail.
+package selftest
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// Result is the outcome of the self-test of one component.
+type Result struct {
+	// Resource is the name of the component.
+	Resource string `json:"resource"`
+	// Passed is whether the component passed its checks. Skipped components pass.
+	Passed bool `json:"passed"`
+	// Skipped is whether the component was not checked, either because it is configured to
+	// be skipped or because its API has no self-test.
+	Skipped bool `json:"skipped,omitempty"`
+	// Error is why the component failed its checks, if it did.
+	Error string `json:"error,omitempty"`
+	// Duration is how long the checks took.
+	Duration time.Duration `json:"duration"`
+}
+
+// Report is the outcome of the self-test of a robot.
+type Report struct {
+	// Time is when the self-test started.
+	Time time.Time `json:"time"`
+	// Passed is whether every component passed its checks.
+	Passed bool `json:"passed"`
+	// Results are the outcomes for each component, sorted by name.
+	Results []Result `json:"results"`
+}
+
+// Result returns the outcome of the self-test of the named component.
+func (r Report) Result(name resource.Name) (Result, bool) {
+	for _, res := range r.Results {
+		if res.Resource == name.String() {
+			return res, true
+		}
+	}
+	return Result{}, false
+}
+
+// Service runs self-tests of a robot.
+type Service interface {
+	// Run self-tests every component of the robot and returns the report.
+	Run(ctx context.Context) (Report, error)
+	// LastReport returns the report of the last self-test, or nil if none ran yet.
+	LastReport(ctx context.Context) (*Report, error)
+}
+
+// A Robot is a robot exercising its components with safe checks. The robot package refers
+// to this package, so the robot it is cannot be named here.
+type Robot interface {
+	// SelfTester returns the tester exercising the robot's components with safe checks,
+	// which is also served to remote clients.
+	SelfTester() *Tester
+}
+
+// A Tester self-tests the components of a robot.
+type Tester struct {
+	logger     logging.Logger
+	components func() []resource.Name
+	lookup     func(name resource.Name) (resource.Resource, error)
+
+	// runMu ensures only one self-test runs at a time so that components are not exercised
+	// by several at once.
+	runMu sync.Mutex
+
+	mu   sync.Mutex
+	conf *config.SelfTestConfig
+	last *Report
+}
+
+var _ Service = (*Tester)(nil)
+
+// NewTester returns a tester of the components named by the given function, looked up
+// with lookup.
+func NewTester(
+	logger logging.Logger,
+	components func() []resource.Name,
+	lookup func(name resource.Name) (resource.Resource, error),
+) *Tester {
+	return &Tester{logger: logger, components: components, lookup: lookup}
+}
+
+// Reconfigure replaces the timeout and skipped components of later self-tests. A nil
+// config uses the defaults.
+func (t *Tester) Reconfigure(conf *config.SelfTestConfig) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.conf = conf
+}
+
+// Run self-tests every component concurrently and returns the report, which is also kept
+// as the last report.
+func (t *Tester) Run(ctx context.Context) (Report, error) {
+	t.runMu.Lock()
+	defer t.runMu.Unlock()
+
+	t.mu.Lock()
+	conf := t.conf
+	t.mu.Unlock()
+	timeout := conf.TimeoutDuration()
+	var skip []string
+	if conf != nil {
+		skip = conf.Skip
+	}
+
+	report := Report{Time: clock.Now(), Passed: true}
+	var (
+		resultsMu sync.Mutex
+		wg        sync.WaitGroup
+	)
+	for _, name := range t.components() {
+		name := name
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := t.check(ctx, name, timeout, slices.Contains(skip, name.ShortName()))
+			resultsMu.Lock()
+			defer resultsMu.Unlock()
+			report.Results = append(report.Results, result)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return Report{}, err
+	}
+
+	sort.Slice(report.Results, func(i, j int) bool {
+		return report.Results[i].Resource < report.Results[j].Resource
+	})
+	for _, result := range report.Results {
+		if !result.Passed {
+			report.Passed = false
+			t.logger.Warnw("component failed self-test", "resource", result.Resource, "error", result.Error)
+		}
+	}
+	t.mu.Lock()
+	t.last = &report
+	t.mu.Unlock()
+	return report, nil
+}
+
+// check runs the self-test of one component. A self-test that does not return within the
+// timeout fails, even if it ignores its context.
+func (t *Tester) check(ctx context.Context, name resource.Name, timeout time.Duration, skip bool) Result {
+	result := Result{Resource: name.String()}
+	if skip {
+		result.Passed = true
+		result.Skipped = true
+		return result
+	}
+	res, err := t.lookup(name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	reg, ok := resource.LookupGenericAPIRegistration(name.API)
+	if !ok || reg.SelfTest == nil {
+		result.Passed = true
+		result.Skipped = true
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	start := clock.Now()
+	done := make(chan error, 1)
+	go func() {
+		done <- reg.SelfTest(ctx, res)
+	}()
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = errors.Errorf("self-test did not complete within %v", timeout)
+	}
+	result.Duration = clock.Since(start)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Passed = true
+	return result
+}
+
+// LastReport returns the report of the last self-test, or nil if none ran yet.
+func (t *Tester) LastReport(ctx context.Context) (*Report, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.last, nil
+}
+
+// LastResult returns the outcome of the named component in the last self-test, if any.
+func (t *Tester) LastResult(name resource.Name) (Result, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.last == nil {
+		return Result{}, false
+	}
+	return t.last.Result(name)
+}
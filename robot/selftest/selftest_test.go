@@ -0,0 +1,140 @@
+package selftest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/generic"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/selftest"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+func newArm(name string) *inject.Arm {
+	a := inject.NewArm(name)
+	a.JointPositionsFunc = func(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+		return &pb.JointPositions{Values: []float64{0}}, nil
+	}
+	return a
+}
+
+func TestTester(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	failing := inject.NewSensor("sensor1")
+	failing.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		return nil, errors.New("i2c bus not responding")
+	}
+	hung := make(chan struct{})
+	defer close(hung)
+	hanging := inject.NewSensor("sensor2")
+	hanging.ReadingsFunc = func(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+		<-hung
+		return nil, nil
+	}
+	resources := map[resource.Name]resource.Resource{
+		arm.Named("arm1"):         newArm("arm1"),
+		arm.Named("arm2"):         newArm("arm2"),
+		sensor.Named("sensor1"):   failing,
+		sensor.Named("sensor2"):   hanging,
+		generic.Named("generic1"): inject.NewGenericComponent("generic1"),
+	}
+	names := []resource.Name{motor.Named("motor1")}
+	for name := range resources {
+		names = append(names, name)
+	}
+	tester := selftest.NewTester(logger,
+		func() []resource.Name { return names },
+		func(name resource.Name) (resource.Resource, error) {
+			if res, ok := resources[name]; ok {
+				return res, nil
+			}
+			return nil, resource.NewNotFoundError(name)
+		})
+
+	last, err := tester.LastReport(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, last, test.ShouldBeNil)
+
+	tester.Reconfigure(&config.SelfTestConfig{Timeout: "50ms", Skip: []string{"arm2"}})
+	report, err := tester.Run(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Passed, test.ShouldBeFalse)
+	test.That(t, report.Results, test.ShouldHaveLength, 6)
+
+	result := func(name resource.Name) selftest.Result {
+		t.Helper()
+		res, ok := report.Result(name)
+		test.That(t, ok, test.ShouldBeTrue)
+		return res
+	}
+	test.That(t, result(arm.Named("arm1")).Passed, test.ShouldBeTrue)
+	test.That(t, result(arm.Named("arm1")).Skipped, test.ShouldBeFalse)
+	test.That(t, result(arm.Named("arm2")).Skipped, test.ShouldBeTrue)
+	test.That(t, result(generic.Named("generic1")).Skipped, test.ShouldBeTrue)
+	test.That(t, result(generic.Named("generic1")).Passed, test.ShouldBeTrue)
+	test.That(t, result(sensor.Named("sensor1")).Passed, test.ShouldBeFalse)
+	test.That(t, result(sensor.Named("sensor1")).Error, test.ShouldContainSubstring, "i2c bus not responding")
+	test.That(t, result(sensor.Named("sensor2")).Passed, test.ShouldBeFalse)
+	test.That(t, result(sensor.Named("sensor2")).Error, test.ShouldContainSubstring, "did not complete within 50ms")
+	test.That(t, result(motor.Named("motor1")).Passed, test.ShouldBeFalse)
+	test.That(t, result(motor.Named("motor1")).Error, test.ShouldContainSubstring, "not found")
+
+	last, err = tester.LastReport(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, *last, test.ShouldResemble, report)
+	lastResult, ok := tester.LastResult(sensor.Named("sensor1"))
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, lastResult, test.ShouldResemble, result(sensor.Named("sensor1")))
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = tester.Run(canceledCtx)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		arm.Named("arm1"): newArm("arm1"),
+	})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	last, err := robotClient.SelfTest().LastReport(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, last, test.ShouldBeNil)
+
+	report, err := robotClient.SelfTest().Run(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, report.Passed, test.ShouldBeTrue)
+	test.That(t, report.Results, test.ShouldHaveLength, 1)
+	test.That(t, report.Results[0].Resource, test.ShouldEqual, arm.Named("arm1").String())
+	test.That(t, report.Time.Before(time.Now().Add(time.Second)), test.ShouldBeTrue)
+
+	last, err = robotClient.SelfTest().LastReport(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, last, test.ShouldNotBeNil)
+	test.That(t, last.Results, test.ShouldResemble, report.Results)
+}
@@ -0,0 +1,72 @@
+package selftest
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving self-tests. Its requests and
+// responses are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.selftest.v1.SelfTestService"
+
+type lastReportResponse struct {
+	Report *Report `json:"report,omitempty"`
+}
+
+// A Server serves self-tests with ServiceDesc.
+type Server struct {
+	tester Service
+}
+
+// NewServer returns a server for the given tester.
+func NewServer(tester Service) *Server {
+	return &Server{tester: tester}
+}
+
+func (s *Server) run(ctx context.Context, _ struct{}) (interface{}, error) {
+	return s.tester.Run(ctx)
+}
+
+func (s *Server) getLastReport(ctx context.Context, _ struct{}) (interface{}, error) {
+	report, err := s.tester.LastReport(ctx)
+	return lastReportResponse{Report: report}, err
+}
+
+// ServiceDesc describes the gRPC service serving self-tests. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/selftest",
+	structrpc.Unary("Run", (*Server).run),
+	structrpc.Unary("GetLastReport", (*Server).getLastReport),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the self-tests served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Run self-tests every component of the robot and returns the report.
+func (c *Client) Run(ctx context.Context) (Report, error) {
+	var report Report
+	if err := c.client.Invoke(ctx, "Run", struct{}{}, &report); err != nil {
+		return Report{}, err
+	}
+	return report, nil
+}
+
+// LastReport returns the report of the last self-test, or nil if none ran yet.
+func (c *Client) LastReport(ctx context.Context) (*Report, error) {
+	var resp lastReportResponse
+	if err := c.client.Invoke(ctx, "GetLastReport", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Report, nil
+}
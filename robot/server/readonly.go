@@ -0,0 +1,122 @@
+package server
+
+import (
+	"context"
+	"path"
+	"strings"
+	"sync"
+
+	googlegrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"go.viam.com/rdk/arbitration"
+	"go.viam.com/rdk/firmware"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/homing"
+	"go.viam.com/rdk/robot/mission"
+	"go.viam.com/rdk/robot/trajectories"
+)
+
+// A readOnlyRobot is a robot that may be in read-only mode.
+type readOnlyRobot interface {
+	robot.Robot
+	// ReadOnly returns whether the robot rejects the requests that would actuate it.
+	ReadOnly() bool
+}
+
+// actuatingRobotMethods are the methods of the robot's own services that actuate its
+// resources, which a read-only robot rejects along with the commands to its resources.
+var actuatingRobotMethods = map[string]bool{
+	"/" + mission.ServiceName + "/Enqueue":               true,
+	"/" + trajectories.ServiceName + "/ReplayTrajectory": true,
+	"/" + homing.ServiceName + "/Rehome":                 true,
+	"/" + firmware.ServiceName + "/Update":               true,
+}
+
+// A readOnlyGuard rejects the requests to a read-only robot that would actuate it: the
+// commands of the APIs of its resources, including DoCommand, and the methods of its own
+// services that actuate them. Stopping is never rejected.
+type readOnlyGuard struct {
+	// apis caches the resource API served by each gRPC service, or nil if it serves none.
+	apis sync.Map
+}
+
+func (g *readOnlyGuard) unaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *googlegrpc.UnaryServerInfo,
+	handler googlegrpc.UnaryHandler,
+) (interface{}, error) {
+	if err := g.check(info.FullMethod, req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (g *readOnlyGuard) streamServerInterceptor(
+	srv interface{},
+	ss googlegrpc.ServerStream,
+	info *googlegrpc.StreamServerInfo,
+	handler googlegrpc.StreamHandler,
+) error {
+	if err := g.check(info.FullMethod, nil); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// check returns an error if a call of fullMethod with the given request, which is nil for
+// streams, would actuate the robot.
+func (g *readOnlyGuard) check(fullMethod string, req interface{}) error {
+	if actuatingRobotMethods[fullMethod] {
+		return readOnlyMethodError(fullMethod)
+	}
+	method := path.Base(fullMethod)
+	if !arbitration.IsCommand(method) {
+		return nil
+	}
+	served := g.served(strings.TrimPrefix(path.Dir(fullMethod), "/"))
+	if served == nil {
+		return nil
+	}
+	if command, ok := req.(interface{ GetCommand() *structpb.Struct }); ok && method == "DoCommand" &&
+		served.reg.ReadCommand != nil && served.reg.ReadCommand(command.GetCommand().AsMap()) {
+		return nil
+	}
+	if named, ok := req.(interface{ GetName() string }); ok && named.GetName() != "" {
+		return resource.NewReadOnlyError(resource.NewName(served.api, named.GetName()))
+	}
+	return readOnlyMethodError(fullMethod)
+}
+
+// readOnlyMethodError is returned for calls of fullMethod rejected without a resource to name.
+func readOnlyMethodError(fullMethod string) error {
+	return status.Errorf(codes.PermissionDenied, "%s cannot be called; the robot is in read-only mode", fullMethod)
+}
+
+// A servedAPI is a resource API served by a gRPC service, with its registration.
+type servedAPI struct {
+	api resource.API
+	reg resource.APIRegistration[resource.Resource]
+}
+
+// served returns the resource API the gRPC service of the given name serves, or nil if it
+// serves none.
+func (g *readOnlyGuard) served(service string) *servedAPI {
+	if cached, ok := g.apis.Load(service); ok {
+		served, _ := cached.(*servedAPI)
+		return served
+	}
+	var found *servedAPI
+	for api, reg := range resource.RegisteredAPIs() {
+		if reg.RPCServiceDesc != nil && reg.RPCServiceDesc.ServiceName == service {
+			found = &servedAPI{api: api, reg: reg}
+			break
+		}
+	}
+	g.apis.Store(service, found)
+	return found
+}
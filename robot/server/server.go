@@ -241,8 +241,14 @@ func (s *Server) TransformPose(ctx context.Context, req *pb.TransformPoseRequest
 	if err != nil {
 		return nil, err
 	}
+	if req.GetSource() == nil {
+		return nil, grpcstatus.Error(codes.InvalidArgument, "source pose is required")
+	}
 	transformedPose, err := s.robot.TransformPose(ctx, referenceframe.ProtobufToPoseInFrame(req.Source), req.Destination, transforms)
-	return &pb.TransformPoseResponse{Pose: referenceframe.PoseInFrameToProtobuf(transformedPose)}, err
+	if err != nil {
+		return nil, err
+	}
+	return &pb.TransformPoseResponse{Pose: referenceframe.PoseInFrameToProtobuf(transformedPose)}, nil
 }
 
 // TransformPCD will transform the pointcloud to the desired frame in the robot's frame system.
@@ -433,6 +433,63 @@ func TestServerFrameSystemConfig(t *testing.T) {
 	})
 }
 
+func TestServerTransformPose(t *testing.T) {
+	injectRobot := &inject.Robot{}
+	server := server.New(injectRobot)
+
+	gripperPose := spatialmath.NewPose(r3.Vector{X: 1, Y: 2, Z: 3}, &spatialmath.OrientationVectorDegrees{OY: 1, Theta: 30})
+	gripper := referenceframe.NewLinkInFrame("arm", gripperPose, "gripper", nil)
+	transforms, err := referenceframe.LinkInFramesToTransformsProtobuf([]*referenceframe.LinkInFrame{gripper})
+	test.That(t, err, test.ShouldBeNil)
+
+	// the orientations of the source and transformed poses, and the supplemental transforms, are kept.
+	source := spatialmath.NewPose(r3.Vector{X: 10}, &spatialmath.R4AA{Theta: math.Pi / 2, RZ: 1})
+	transformed := spatialmath.NewPose(r3.Vector{Z: 500}, &spatialmath.EulerAngles{Roll: 0.1, Pitch: 0.2, Yaw: 0.3})
+	injectRobot.TransformPoseFunc = func(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		test.That(t, pose.Parent(), test.ShouldEqual, "gripper")
+		test.That(t, spatialmath.PoseAlmostEqual(pose.Pose(), source), test.ShouldBeTrue)
+		test.That(t, dst, test.ShouldEqual, referenceframe.World)
+		test.That(t, additionalTransforms, test.ShouldHaveLength, 1)
+		test.That(t, additionalTransforms[0].Name(), test.ShouldEqual, "gripper")
+		test.That(t, additionalTransforms[0].Parent(), test.ShouldEqual, "arm")
+		test.That(t, spatialmath.PoseAlmostEqual(additionalTransforms[0].Pose(), gripperPose), test.ShouldBeTrue)
+		return referenceframe.NewPoseInFrame(dst, transformed), nil
+	}
+	resp, err := server.TransformPose(context.Background(), &pb.TransformPoseRequest{
+		Source:                 referenceframe.PoseInFrameToProtobuf(referenceframe.NewPoseInFrame("gripper", source)),
+		Destination:            referenceframe.World,
+		SupplementalTransforms: transforms,
+	})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Pose.ReferenceFrame, test.ShouldEqual, referenceframe.World)
+	test.That(t, spatialmath.PoseAlmostEqual(spatialmath.NewPoseFromProtobuf(resp.Pose.Pose), transformed), test.ShouldBeTrue)
+
+	expectedErr := errors.New("frame not found")
+	injectRobot.TransformPoseFunc = func(
+		ctx context.Context,
+		pose *referenceframe.PoseInFrame,
+		dst string,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*referenceframe.PoseInFrame, error) {
+		return nil, expectedErr
+	}
+	resp, err = server.TransformPose(context.Background(), &pb.TransformPoseRequest{
+		Source:      referenceframe.PoseInFrameToProtobuf(referenceframe.NewPoseInFrame("gripper", source)),
+		Destination: referenceframe.World,
+	})
+	test.That(t, err, test.ShouldBeError, expectedErr)
+	test.That(t, resp, test.ShouldBeNil)
+
+	_, err = server.TransformPose(context.Background(), &pb.TransformPoseRequest{Destination: referenceframe.World})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "source pose is required")
+}
+
 func TestServerGetStatus(t *testing.T) {
 	// Sample lastReconfigured times to be used across status tests.
 	lastReconfigured, err := time.Parse("2006-01-02 15:04:05", "1998-04-30 19:08:00")
@@ -587,7 +644,7 @@ func TestServerGetStatus(t *testing.T) {
 		injectRobot := &inject.Robot{}
 		server := server.New(injectRobot)
 		injectRobot.StatusFunc = func(ctx context.Context, resourceNames []resource.Name) ([]robot.Status, error) {
-			return []robot.Status{{arm.Named("arm"), time.Time{}, struct{}{}}}, nil
+			return []robot.Status{{Name: arm.Named("arm"), Status: struct{}{}}}, nil
 		}
 
 		cancelCtx, cancel := context.WithCancel(context.Background())
@@ -608,7 +665,7 @@ func TestServerGetStatus(t *testing.T) {
 		injectRobot := &inject.Robot{}
 		server := server.New(injectRobot)
 		injectRobot.StatusFunc = func(ctx context.Context, resourceNames []resource.Name) ([]robot.Status, error) {
-			return []robot.Status{{arm.Named("arm"), time.Time{}, struct{}{}}}, nil
+			return []robot.Status{{Name: arm.Named("arm"), Status: struct{}{}}}, nil
 		}
 
 		timeoutCtx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -0,0 +1,291 @@
+package server
+
+import (
+	"context"
+	"fmt"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"go.opencensus.io/trace"
+	googlegrpc "google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"go.viam.com/rdk/arbitration"
+	"go.viam.com/rdk/faults"
+	"go.viam.com/rdk/firmware"
+	"go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/grpc/recording"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/camerapose"
+	"go.viam.com/rdk/robot/capabilities"
+	"go.viam.com/rdk/robot/chunking"
+	"go.viam.com/rdk/robot/configpatch"
+	"go.viam.com/rdk/robot/configschema"
+	"go.viam.com/rdk/robot/coordination"
+	"go.viam.com/rdk/robot/dashboard"
+	"go.viam.com/rdk/robot/effectiveconfig"
+	"go.viam.com/rdk/robot/events"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/health"
+	"go.viam.com/rdk/robot/homing"
+	"go.viam.com/rdk/robot/kinematics"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/metadata"
+	"go.viam.com/rdk/robot/metrics"
+	"go.viam.com/rdk/robot/mission"
+	"go.viam.com/rdk/robot/profiles"
+	"go.viam.com/rdk/robot/records"
+	"go.viam.com/rdk/robot/selftest"
+	"go.viam.com/rdk/robot/timesync"
+	"go.viam.com/rdk/robot/tools"
+	"go.viam.com/rdk/robot/trajectories"
+	"go.viam.com/rdk/robot/transaction"
+	"go.viam.com/rdk/watchdog"
+)
+
+// Interceptors are the server interceptors calls to the services of a robot are served with.
+type Interceptors struct {
+	Unary  googlegrpc.UnaryServerInterceptor
+	Stream googlegrpc.StreamServerInterceptor
+	// Resource is the innermost part of Unary, which serves requests to resources. Each
+	// command of a transaction is served with it, within the transaction's operation.
+	Resource googlegrpc.UnaryServerInterceptor
+}
+
+// InterceptorOptions configure the interceptors returned by NewInterceptors.
+type InterceptorOptions struct {
+	// Debug allows faults to be changed at runtime through DoCommand and traces every request.
+	Debug bool
+	// Recorder, if set, records the requests served to resources and their responses.
+	Recorder *recording.Recorder
+}
+
+// NewInterceptors returns the server interceptors of the robot. Both the web service and
+// in-process connections serve calls with them, so that calls are served the same way by
+// either.
+func NewInterceptors(r robot.Robot, opts InterceptorOptions) Interceptors {
+	unaryInterceptors := []googlegrpc.UnaryServerInterceptor{grpc.EnsureTimeoutUnaryServerInterceptor}
+	var streamInterceptors []googlegrpc.StreamServerInterceptor
+
+	faultRobot, injectsFaults := r.(faults.Robot)
+	if opts.Debug {
+		if injectsFaults {
+			// allow faults to be changed at runtime through DoCommand
+			unaryInterceptors = append(unaryInterceptors, faultRobot.FaultInjector().CommandUnaryServerInterceptor)
+		}
+		unaryInterceptors = append(unaryInterceptors, func(
+			ctx context.Context,
+			req interface{},
+			info *googlegrpc.UnaryServerInfo,
+			handler googlegrpc.UnaryHandler,
+		) (interface{}, error) {
+			ctx, span := trace.StartSpan(ctx, fmt.Sprintf("%v", req))
+			defer span.End()
+
+			return handler(ctx, req)
+		})
+	}
+
+	if sessManager := r.SessionManager(); sessManager != nil {
+		sessManagerInts := sessManager.ServerInterceptors()
+		if sessManagerInts.UnaryServerInterceptor != nil {
+			unaryInterceptors = append(unaryInterceptors, sessManagerInts.UnaryServerInterceptor)
+		}
+		if sessManagerInts.StreamServerInterceptor != nil {
+			streamInterceptors = append(streamInterceptors, sessManagerInts.StreamServerInterceptor)
+		}
+	}
+	if opManager := r.OperationManager(); opManager != nil {
+		unaryInterceptors = append(unaryInterceptors, opManager.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, opManager.StreamServerInterceptor)
+	}
+	unaryInterceptors = append(unaryInterceptors, logging.UnaryServerInterceptor)
+
+	var resourceInterceptors []googlegrpc.UnaryServerInterceptor
+	if readOnly, ok := r.(readOnlyRobot); ok && readOnly.ReadOnly() {
+		// reject outside of health tracking so that rejections are not recorded as errors.
+		guard := &readOnlyGuard{}
+		resourceInterceptors = append(resourceInterceptors, guard.unaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, guard.streamServerInterceptor)
+	}
+	if healthRobot, ok := r.(health.Robot); ok {
+		// track outside of arbitration and faults so that only requests resources served count.
+		healthTracker := healthRobot.HealthTracker()
+		resourceInterceptors = append(resourceInterceptors, healthTracker.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, healthTracker.StreamServerInterceptor)
+	}
+	if measuredRobot, ok := r.(metrics.Robot); ok {
+		// measure outside of arbitration and faults so that latencies are those clients see.
+		metricsRecorder := measuredRobot.Metrics()
+		resourceInterceptors = append(resourceInterceptors, metricsRecorder.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, metricsRecorder.StreamServerInterceptor)
+	}
+	if arbitratedRobot, ok := r.(arbitration.Robot); ok {
+		arbiter := arbitratedRobot.Arbiter()
+		resourceInterceptors = append(resourceInterceptors, arbiter.CommandUnaryServerInterceptor, arbiter.UnaryServerInterceptor)
+	}
+	if watchedRobot, ok := r.(watchdog.Robot); ok {
+		// watch outside of faults so that injected latency counts against operation timeouts.
+		resourceInterceptors = append(resourceInterceptors, watchedRobot.Watchdog().UnaryServerInterceptor)
+	}
+	if injectsFaults {
+		faultInjector := faultRobot.FaultInjector()
+		resourceInterceptors = append(resourceInterceptors, faultInjector.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, faultInjector.StreamServerInterceptor)
+	}
+	if opts.Recorder != nil {
+		// record innermost so that traces hold what resources returned, not injected faults
+		resourceInterceptors = append(resourceInterceptors, opts.Recorder.UnaryServerInterceptor)
+		streamInterceptors = append(streamInterceptors, opts.Recorder.StreamServerInterceptor)
+	}
+	unaryInterceptors = append(unaryInterceptors, resourceInterceptors...)
+
+	return Interceptors{
+		Unary:    grpc_middleware.ChainUnaryServer(unaryInterceptors...),
+		Stream:   grpc_middleware.ChainStreamServer(streamInterceptors...),
+		Resource: grpc_middleware.ChainUnaryServer(resourceInterceptors...),
+	}
+}
+
+// RegisterServices registers the services the robot serves besides its RobotService and the
+// APIs of its resources. Commands of transactions are served with the Resource interceptor
+// of ints, and calls served in chunks are invoked on chunkingConn.
+func RegisterServices(
+	r robot.Robot,
+	logger logging.Logger,
+	ints Interceptors,
+	chunkingConn googlegrpc.ClientConnInterface,
+	register func(desc *googlegrpc.ServiceDesc, srv interface{}) error,
+) error {
+	// commands of a transaction are served like the requests they stand for.
+	if err := register(&transaction.ServiceDesc, transaction.NewExecutor(r, ints.Resource)); err != nil {
+		return err
+	}
+	if opManager := r.OperationManager(); opManager != nil {
+		if err := register(&operation.ServiceDesc, operation.NewServer(opManager)); err != nil {
+			return err
+		}
+	}
+	if err := register(
+		&camerapose.ServiceDesc,
+		camerapose.NewServer(camerapose.NewStreamer(r, logger.Sublogger("camerapose"))),
+	); err != nil {
+		return err
+	}
+
+	if healthRobot, ok := r.(health.Robot); ok {
+		if err := register(&healthpb.Health_ServiceDesc, health.NewServer(healthRobot.Health)); err != nil {
+			return err
+		}
+	}
+	if storingRobot, ok := r.(kv.Robot); ok {
+		if err := register(&kv.ServiceDesc, kv.NewServer(storingRobot.KV())); err != nil {
+			return err
+		}
+	}
+	if publishingRobot, ok := r.(events.Robot); ok {
+		if err := register(&events.ServiceDesc, events.NewServer(publishingRobot.Events())); err != nil {
+			return err
+		}
+	}
+	if testedRobot, ok := r.(selftest.Robot); ok {
+		if err := register(&selftest.ServiceDesc, selftest.NewServer(testedRobot.SelfTester())); err != nil {
+			return err
+		}
+	}
+	if toolingRobot, ok := r.(tools.Robot); ok {
+		if err := register(&tools.ServiceDesc, tools.NewServer(toolingRobot.Tools())); err != nil {
+			return err
+		}
+	}
+	if framedRobot, ok := r.(framesystem.Robot); ok {
+		if err := register(&framesystem.UpdateServiceDesc, framesystem.NewUpdateServer(framedRobot.RuntimeFrames())); err != nil {
+			return err
+		}
+		if err := register(&framesystem.ObstacleServiceDesc, framesystem.NewObstacleServer(framedRobot.Obstacles())); err != nil {
+			return err
+		}
+		solver := kinematics.NewSolver(logger.Sublogger("kinematics"),
+			func(ctx context.Context) (*framesystem.LiveFrameSystem, error) {
+				return framedRobot.LiveFrameSystem(ctx, nil)
+			})
+		if err := register(&kinematics.ServiceDesc, kinematics.NewServer(solver)); err != nil {
+			return err
+		}
+	}
+	if coordinatedRobot, ok := r.(coordination.Robot); ok {
+		if err := register(&coordination.ServiceDesc, coordination.NewServer(coordinatedRobot.Coordination())); err != nil {
+			return err
+		}
+	}
+	if missionRobot, ok := r.(mission.Robot); ok {
+		if err := register(&mission.ServiceDesc, mission.NewServer(missionRobot.Missions())); err != nil {
+			return err
+		}
+	}
+	if labeledRobot, ok := r.(metadata.Robot); ok {
+		if err := register(&metadata.ServiceDesc, metadata.NewServer(labeledRobot)); err != nil {
+			return err
+		}
+	}
+	if patchableRobot, ok := r.(configpatch.Robot); ok {
+		if err := register(&configpatch.ServiceDesc, configpatch.NewServer(patchableRobot)); err != nil {
+			return err
+		}
+	}
+	if err := register(&configschema.ServiceDesc, configschema.NewServer()); err != nil {
+		return err
+	}
+	if recordingRobot, ok := r.(records.Robot); ok {
+		if err := register(&records.ServiceDesc, records.NewServer(recordingRobot)); err != nil {
+			return err
+		}
+	}
+	if trajectoryRobot, ok := r.(trajectories.Robot); ok {
+		if err := register(&trajectories.ServiceDesc, trajectories.NewServer(trajectoryRobot.Trajectories())); err != nil {
+			return err
+		}
+	}
+	if syncedRobot, ok := r.(timesync.Robot); ok {
+		if err := register(&timesync.ServiceDesc, timesync.NewServer(syncedRobot.TimeSync())); err != nil {
+			return err
+		}
+	}
+	if dashboardRobot, ok := r.(dashboard.Robot); ok {
+		if err := register(&dashboard.ServiceDesc, dashboard.NewServer(dashboardRobot)); err != nil {
+			return err
+		}
+	}
+	if configuredRobot, ok := r.(capabilities.Robot); ok {
+		if err := register(&capabilities.ServiceDesc, capabilities.NewServer(configuredRobot)); err != nil {
+			return err
+		}
+	}
+	if profiledRobot, ok := r.(profiles.Robot); ok {
+		if err := register(&profiles.ServiceDesc, profiles.NewServer(profiledRobot)); err != nil {
+			return err
+		}
+	}
+	if homingRobot, ok := r.(homing.Robot); ok {
+		if err := register(&homing.ServiceDesc, homing.NewServer(homingRobot.Homing())); err != nil {
+			return err
+		}
+	}
+	if watchedRobot, ok := r.(watchdog.Robot); ok {
+		if err := register(&watchdog.ServiceDesc, watchdog.NewServer(watchedRobot.Watchdog())); err != nil {
+			return err
+		}
+	}
+	if effectiveRobot, ok := r.(effectiveconfig.Robot); ok {
+		if err := register(&effectiveconfig.ServiceDesc, effectiveconfig.NewServer(effectiveRobot)); err != nil {
+			return err
+		}
+	}
+	if updatingRobot, ok := r.(firmware.Updater); ok {
+		if err := register(&firmware.ServiceDesc, firmware.NewServer(updatingRobot)); err != nil {
+			return err
+		}
+	}
+	return register(&chunking.ServiceDesc, chunking.NewServer(chunkingConn))
+}
@@ -2,28 +2,47 @@ package robot
 
 import (
 	"context"
+	"net"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"go.viam.com/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/health"
 	"go.viam.com/rdk/session"
 	rdkutils "go.viam.com/rdk/utils"
 )
 
 // NewSessionManager creates a new manager for holding sessions.
 func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManager {
+	return NewSessionManagerFromConfig(robot, config.SessionsConfig{HeartbeatWindow: heartbeatWindow})
+}
+
+// NewSessionManagerFromConfig creates a new manager for holding sessions that enforces
+// the session limits and idle timeout in the given config.
+func NewSessionManagerFromConfig(robot Robot, cfg config.SessionsConfig) *SessionManager {
 	m := &SessionManager{
 		robot:             robot,
-		heartbeatWindow:   heartbeatWindow,
+		heartbeatWindow:   cfg.HeartbeatWindow,
+		idleTimeout:       cfg.IdleTimeout,
+		maxSessions:       cfg.SessionLimit,
+		maxPerCredential:  cfg.SessionLimitPerCredential,
+		maxPerIP:          cfg.SessionLimitPerIP,
 		logger:            robot.Logger().Sublogger("session_manager"),
 		sessions:          map[uuid.UUID]*session.Session{},
 		resourceToSession: map[resource.Name]uuid.UUID{},
 	}
+	if m.maxSessions == 0 {
+		m.maxSessions = maxSessions
+	}
 	m.workers = rdkutils.NewStoppableWorkers(m.expireLoop)
 	return m
 }
@@ -31,9 +50,13 @@ func NewSessionManager(robot Robot, heartbeatWindow time.Duration) *SessionManag
 // SessionManager holds sessions for a particular robot and manages their
 // lifetime.
 type SessionManager struct {
-	robot           Robot
-	heartbeatWindow time.Duration
-	logger          logging.Logger
+	robot            Robot
+	heartbeatWindow  time.Duration
+	idleTimeout      time.Duration
+	maxSessions      int
+	maxPerCredential int
+	maxPerIP         int
+	logger           logging.Logger
 
 	sessionResourceMu sync.RWMutex
 	sessions          map[uuid.UUID]*session.Session
@@ -69,7 +92,7 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 		var toStop []resource.Name
 		m.sessionResourceMu.RLock()
 		for id, sess := range m.sessions {
-			if !sess.Active(now) {
+			if !sess.Active(now) || m.idle(sess, now) {
 				toDelete[id] = struct{}{}
 			}
 		}
@@ -80,6 +103,7 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 			}
 		}
 		m.sessionResourceMu.RUnlock()
+		toStop = append(toStop, m.operationResources(toDelete, toStop)...)
 
 		var resourceErrs []error
 		var serverClosing bool
@@ -119,6 +143,10 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 					if actuator, ok := res.(resource.Actuator); ok {
 						if err := actuator.Stop(ctx, nil); err != nil {
 							resourceErrs = append(resourceErrs, err)
+							return
+						}
+						if healthRobot, ok := m.robot.(health.Robot); ok {
+							healthRobot.HealthTracker().RecordStop(resName.ShortName(), health.StopReasonSessionExpired)
 						}
 					}
 				}()
@@ -131,6 +159,8 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 			return
 		}
 
+		m.cancelOperations(toDelete)
+
 		if len(toDelete) != 0 {
 			var deletedIDs []string
 			for id := range toDelete {
@@ -147,22 +177,112 @@ func (m *SessionManager) expireLoop(ctx context.Context) {
 	}
 }
 
+// idle returns whether the session has gone without any non-heartbeat activity
+// for longer than the idle timeout. Calls still in flight, such as long running
+// streams, count as activity for as long as they run.
+func (m *SessionManager) idle(sess *session.Session, now time.Time) bool {
+	if m.idleTimeout <= 0 || sess.CallsInFlight() > 0 {
+		return false
+	}
+	return now.Sub(sess.LastActivity()) > m.idleTimeout
+}
+
+// operationResources returns the names of the resources acted on by the in flight operations
+// started under the given sessions, other than those already in names, so that actuators
+// driven by a client whose session expired are stopped even if it never called a safety
+// monitored method on them.
+func (m *SessionManager) operationResources(sessIDs map[uuid.UUID]struct{}, names []resource.Name) []resource.Name {
+	if len(sessIDs) == 0 {
+		return nil
+	}
+	opManager := m.robot.OperationManager()
+	if opManager == nil {
+		return nil
+	}
+	var found []resource.Name
+	for _, op := range opManager.All() {
+		if _, ok := sessIDs[op.SessionID]; !ok || op.Resource == "" {
+			continue
+		}
+		rpcAPI, _, err := TypeAndMethodDescFromMethod(m.robot, op.Method)
+		if err != nil {
+			continue
+		}
+		name := resource.NewName(rpcAPI.API, op.Resource)
+		if slices.Contains(names, name) || slices.Contains(found, name) {
+			continue
+		}
+		found = append(found, name)
+	}
+	return found
+}
+
+// cancelOperations cancels any in flight operations (including streams) that were
+// started under the given sessions so that their resources are freed.
+func (m *SessionManager) cancelOperations(sessIDs map[uuid.UUID]struct{}) {
+	if len(sessIDs) == 0 {
+		return
+	}
+	opManager := m.robot.OperationManager()
+	if opManager == nil {
+		return
+	}
+	for _, op := range opManager.All() {
+		if _, ok := sessIDs[op.SessionID]; ok {
+			op.Cancel()
+		}
+	}
+}
+
 const (
+	// maxSessions is the default limit on concurrent sessions.
 	maxSessions = 1024
 )
 
 // Start creates a new session that expects at least one heartbeat within the configured window.
+// An error is returned if starting the session would exceed the total, per credential, or per IP
+// session limits. Only sessions are limited; connections without a session are not counted.
 func (m *SessionManager) Start(ctx context.Context, ownerID string) (*session.Session, error) {
 	sess := session.New(ctx, ownerID, m.heartbeatWindow, m.AssociateResource)
+	peerHost := sessionPeerHost(sess)
+
 	m.sessionResourceMu.Lock()
-	if len(m.sessions) > maxSessions {
-		return nil, errors.New("too many concurrent sessions")
+	defer m.sessionResourceMu.Unlock()
+	if len(m.sessions) >= m.maxSessions {
+		return nil, status.Error(codes.ResourceExhausted, "too many concurrent sessions")
+	}
+	var ownerCount, peerCount int
+	for _, other := range m.sessions {
+		if m.maxPerCredential > 0 && other.CheckOwnerID(ownerID) {
+			ownerCount++
+		}
+		if m.maxPerIP > 0 && peerHost != "" && sessionPeerHost(other) == peerHost {
+			peerCount++
+		}
+	}
+	if m.maxPerCredential > 0 && ownerCount >= m.maxPerCredential {
+		return nil, status.Error(codes.ResourceExhausted, "too many concurrent sessions for this credential")
+	}
+	if m.maxPerIP > 0 && peerCount >= m.maxPerIP {
+		return nil, status.Errorf(codes.ResourceExhausted, "too many concurrent sessions from %q", peerHost)
 	}
 	m.sessions[sess.ID()] = sess
-	m.sessionResourceMu.Unlock()
 	return sess, nil
 }
 
+// sessionPeerHost returns the remote host (without port) the session was last seen from.
+func sessionPeerHost(sess *session.Session) string {
+	info := sess.PeerConnectionInfo()
+	if info == nil || info.RemoteAddress == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(*info.RemoteAddress)
+	if err != nil {
+		return *info.RemoteAddress
+	}
+	return host
+}
+
 // FindByID finds a session by the given ID. If found, a heartbeat is triggered,
 // extending the lifetime of the session. If ownerID is in use but the session
 // in question has a different owner, this is a security violation and we report
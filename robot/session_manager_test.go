@@ -5,11 +5,16 @@ import (
 	"testing"
 	"time"
 
+	armpb "go.viam.com/api/component/arm/v1"
 	"go.viam.com/test"
 	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
 
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/session"
 	"go.viam.com/rdk/testutils/inject"
@@ -87,3 +92,178 @@ func TestSessionManagerExpiredSessions(t *testing.T) {
 			test.ShouldEqual, 1)
 	})
 }
+
+func TestSessionManagerLimits(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+
+	sm := robot.NewSessionManagerFromConfig(r, config.SessionsConfig{
+		HeartbeatWindow:           config.DefaultSessionHeartbeatWindow,
+		SessionLimit:              3,
+		SessionLimitPerCredential: 2,
+	})
+	defer sm.Close()
+
+	_, err := sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+	_, err = sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "for this credential")
+
+	_, err = sm.Start(ctx, "bar")
+	test.That(t, err, test.ShouldBeNil)
+
+	_, err = sm.Start(ctx, "baz")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "too many concurrent sessions")
+}
+
+func TestSessionManagerIdleSessions(t *testing.T) {
+	ctx := context.Background()
+	logger, logs := logging.NewObservedTestLogger(t)
+	r := &inject.Robot{}
+
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+
+	sm := robot.NewSessionManagerFromConfig(r, config.SessionsConfig{
+		HeartbeatWindow: time.Minute,
+		IdleTimeout:     50 * time.Millisecond,
+	})
+	defer sm.Close()
+
+	sess, err := sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+
+	opCtx, done := r.OperationManager().Create(session.ToContext(ctx, sess), "/some/Method", nil)
+	defer done()
+
+	// heartbeats alone should not keep an idle session around.
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		_, err := sm.FindByID(ctx, sess.ID(), "foo")
+		test.That(tb, err, test.ShouldBeError, session.ErrNoSession)
+	})
+	test.That(t, logs.FilterMessageSnippet("sessions expired").Len(), test.ShouldEqual, 1)
+	test.That(t, opCtx.Err(), test.ShouldNotBeNil)
+}
+
+type idleTestServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s idleTestServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestSessionManagerIdleSessionsWithOpenStream(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+	r.ResourceRPCAPIsFunc = func() []resource.RPCAPI {
+		return nil
+	}
+
+	idleTimeout := 50 * time.Millisecond
+	sm := robot.NewSessionManagerFromConfig(r, config.SessionsConfig{
+		HeartbeatWindow: time.Minute,
+		IdleTimeout:     idleTimeout,
+	})
+	defer sm.Close()
+
+	sess, err := sm.Start(ctx, "")
+	test.That(t, err, test.ShouldBeNil)
+
+	// a stream held open for several idle timeouts keeps its session around.
+	streamCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(session.IDMetadataKey, sess.ID().String()))
+	streaming := make(chan struct{})
+	release := make(chan struct{})
+	streamed := make(chan error)
+	go func() {
+		streamed <- sm.StreamServerInterceptor(
+			nil,
+			idleTestServerStream{ctx: streamCtx},
+			&grpc.StreamServerInfo{FullMethod: "/some.v1.Service/Stream", IsServerStream: true},
+			func(srv interface{}, stream grpc.ServerStream) error {
+				close(streaming)
+				<-release
+				return nil
+			},
+		)
+	}()
+	<-streaming
+	time.Sleep(4 * idleTimeout)
+	_, err = sm.FindByID(ctx, sess.ID(), "")
+	test.That(t, err, test.ShouldBeNil)
+
+	// once the stream ends, the session goes idle.
+	close(release)
+	test.That(t, <-streamed, test.ShouldBeNil)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		_, err := sm.FindByID(ctx, sess.ID(), "")
+		test.That(tb, err, test.ShouldBeError, session.ErrNoSession)
+	})
+}
+
+func TestSessionManagerStopsOperationResources(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	r := &inject.Robot{}
+	r.LoggerFunc = func() logging.Logger {
+		return logger
+	}
+	apiReg, ok := resource.LookupGenericAPIRegistration(arm.API)
+	test.That(t, ok, test.ShouldBeTrue)
+	r.ResourceRPCAPIsFunc = func() []resource.RPCAPI {
+		return []resource.RPCAPI{{API: arm.API, Desc: apiReg.ReflectRPCServiceDesc}}
+	}
+	stopped := make(chan struct{})
+	injectArm := inject.NewArm("arm1")
+	injectArm.StopFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		close(stopped)
+		return nil
+	}
+	r.ResourceByNameFunc = func(name resource.Name) (resource.Resource, error) {
+		if name != arm.Named("arm1") {
+			return nil, resource.NewNotFoundError(name)
+		}
+		return injectArm, nil
+	}
+
+	sm := robot.NewSessionManager(r, 50*time.Millisecond)
+	defer sm.Close()
+	sess, err := sm.Start(ctx, "foo")
+	test.That(t, err, test.ShouldBeNil)
+
+	// moving an arm is not safety monitored, but the arm is stopped once the client moving it
+	// stops heartbeating.
+	moved := make(chan error)
+	go func() {
+		_, err := r.OperationManager().UnaryServerInterceptor(
+			session.ToContext(ctx, sess),
+			&armpb.MoveToPositionRequest{Name: "arm1"},
+			&grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/MoveToPosition"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			},
+		)
+		moved <- err
+	}()
+	<-stopped
+	test.That(t, <-moved, test.ShouldBeError, context.Canceled)
+}
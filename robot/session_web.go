@@ -147,6 +147,9 @@ func (m *SessionManager) UnaryServerInterceptor(
 	if err != nil {
 		return nil, err
 	}
+	if sess, ok := session.FromContext(ctx); ok {
+		defer sess.StartCall()()
+	}
 	return handler(ctx, req)
 }
 
@@ -172,6 +175,9 @@ func (m *SessionManager) StreamServerInterceptor(
 	if err != nil {
 		return err
 	}
+	if sess, ok := session.FromContext(ctx); ok {
+		defer sess.StartCall()()
+	}
 	return handler(srv, &ssStreamContextWrapper{ss, ctx})
 }
 
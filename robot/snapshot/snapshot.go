@@ -0,0 +1,246 @@
+// Package snapshot captures the state of a robot into a bundle and restores it on the same
+// robot, such as after reimaging, or on another robot to clone it.
+//
+// A bundle contains the robot's resource config, the state of every local resource that
+// implements resource.Snapshotter, and the robot's frame system. The cloud, network, and
+// auth sections of the config identify a robot and are never captured.
+//
+// A State, captured with CaptureState, instead records the runtime state of a robot, such as
+// the joint positions of its arms, so that a test can be started from it again or a crash
+// investigated.
+package snapshot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"go.viam.com/utils"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+// Version is the version of the bundle format written by Capture.
+const Version = 1
+
+// A Bundle is a snapshot of a robot.
+type Bundle struct {
+	Version    int       `json:"version"`
+	CreatedAt  time.Time `json:"created_at"`
+	RDKVersion string    `json:"rdk_version,omitempty"`
+	// Config is the robot config without its cloud, network, and auth sections.
+	Config json.RawMessage `json:"config"`
+	// Resources are the states of the resources that implement resource.Snapshotter.
+	Resources []ResourceState `json:"resources,omitempty"`
+	// FrameSystem is the frame system of the robot, one part per entry. It is derived
+	// from the config and is used to check that a restored robot matches the original.
+	FrameSystem []json.RawMessage `json:"frame_system,omitempty"`
+}
+
+// ResourceState is the state of one resource in a bundle.
+type ResourceState struct {
+	Name  string                 `json:"name"`
+	State map[string]interface{} `json:"state"`
+}
+
+// Capture returns a snapshot of the given robot. Resources from remotes are not captured.
+func Capture(ctx context.Context, r robot.LocalRobot) (*Bundle, error) {
+	cfg := r.Config()
+	if cfg == nil {
+		return nil, errors.New("robot has no config")
+	}
+	// bundles leave the robot, so they hold the references to secrets rather than their values.
+	cfg = cfg.WithSecretReferences()
+	rawConfig, err := json.Marshal(&config.Config{
+		Modules:         cfg.Modules,
+		Remotes:         cfg.Remotes,
+		Components:      cfg.Components,
+		Processes:       cfg.Processes,
+		Services:        cfg.Services,
+		Packages:        cfg.Packages,
+		Firmware:        cfg.Firmware,
+		Faults:          cfg.Faults,
+		SecretProviders: cfg.SecretProviders,
+		GlobalLogConfig: cfg.GlobalLogConfig,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to capture config")
+	}
+
+	bundle := &Bundle{
+		Version:    Version,
+		CreatedAt:  clock.Now(),
+		RDKVersion: config.Version,
+		Config:     rawConfig,
+	}
+
+	names := r.ResourceNames()
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].String() < names[j].String()
+	})
+	for _, name := range names {
+		if name.ContainsRemoteNames() {
+			continue
+		}
+		res, err := r.ResourceByName(name)
+		if err != nil {
+			continue
+		}
+		snapshotter, ok := res.(resource.Snapshotter)
+		if !ok {
+			continue
+		}
+		state, err := snapshotter.Snapshot(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to capture the state of %q", name)
+		}
+		bundle.Resources = append(bundle.Resources, ResourceState{Name: name.String(), State: state})
+	}
+
+	if bundle.FrameSystem, err = frameSystem(ctx, r); err != nil {
+		return nil, err
+	}
+	return bundle, nil
+}
+
+func frameSystem(ctx context.Context, r robot.LocalRobot) ([]json.RawMessage, error) {
+	fsCfg, err := r.FrameSystemConfig(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to capture frame system")
+	}
+	parts := make([]json.RawMessage, 0, len(fsCfg.Parts))
+	for _, part := range fsCfg.Parts {
+		partProto, err := part.ToProtobuf()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to capture frame system")
+		}
+		raw, err := protojson.Marshal(partProto)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to capture frame system")
+		}
+		parts = append(parts, raw)
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return bytes.Compare(parts[i], parts[j]) < 0
+	})
+	return parts, nil
+}
+
+// RestoreOptions configure how a bundle is restored.
+type RestoreOptions struct {
+	// SkipConfig only restores resource states, keeping the robot's current config.
+	SkipConfig bool
+}
+
+// Restore restores a snapshot on the given robot. Unless opts.SkipConfig is set, the
+// robot is first reconfigured with the bundle's config; its cloud, network, and auth
+// config are kept. The state of every captured resource is then restored; failures are
+// combined into the returned error rather than stopping the restore.
+func Restore(ctx context.Context, r robot.LocalRobot, bundle *Bundle, opts RestoreOptions) error {
+	if bundle.Version != Version {
+		return errors.Errorf("unsupported snapshot version %d", bundle.Version)
+	}
+	logger := r.Logger().Sublogger("snapshot")
+
+	if !opts.SkipConfig {
+		fromBundle, err := config.FromReader(ctx, "", bytes.NewReader(bundle.Config), logger)
+		if err != nil {
+			return errors.Wrap(err, "failed to read snapshot config")
+		}
+		restored := *r.Config()
+		restored.Modules = fromBundle.Modules
+		restored.Remotes = fromBundle.Remotes
+		restored.Components = fromBundle.Components
+		restored.Processes = fromBundle.Processes
+		restored.Services = fromBundle.Services
+		restored.Packages = fromBundle.Packages
+		restored.Firmware = fromBundle.Firmware
+		restored.Faults = fromBundle.Faults
+		restored.SecretProviders = fromBundle.SecretProviders
+		restored.GlobalLogConfig = fromBundle.GlobalLogConfig
+		r.Reconfigure(ctx, &restored)
+
+		current, err := frameSystem(ctx, r)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(current, bundle.FrameSystem) {
+			logger.CWarn(ctx, "restored frame system differs from the snapshot's; some resources may have failed to build")
+		}
+	}
+
+	var restoreErr error
+	for _, rs := range bundle.Resources {
+		name, err := resource.NewFromString(rs.Name)
+		if err == nil {
+			err = restoreResource(ctx, r, name, rs.State)
+		}
+		if err != nil {
+			restoreErr = multierr.Combine(restoreErr, errors.Wrapf(err, "failed to restore the state of %q", rs.Name))
+		}
+	}
+	return restoreErr
+}
+
+func restoreResource(ctx context.Context, r robot.LocalRobot, name resource.Name, state map[string]interface{}) error {
+	res, err := r.ResourceByName(name)
+	if err != nil {
+		return err
+	}
+	snapshotter, ok := res.(resource.Snapshotter)
+	if !ok {
+		return errors.New("resource does not support snapshots")
+	}
+	return snapshotter.Restore(ctx, state)
+}
+
+// Write writes a bundle as JSON.
+func Write(w io.Writer, bundle *Bundle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(bundle)
+}
+
+// Read reads a bundle written by Write.
+func Read(r io.Reader) (*Bundle, error) {
+	var bundle Bundle
+	if err := json.NewDecoder(r).Decode(&bundle); err != nil {
+		return nil, errors.Wrap(err, "failed to read snapshot")
+	}
+	return &bundle, nil
+}
+
+// Save writes a bundle to the file at path.
+func Save(path string, bundle *Bundle) (err error) {
+	//nolint:gosec
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, f.Close())
+	}()
+	return Write(f, bundle)
+}
+
+// Load reads a bundle from the file at path.
+func Load(path string) (*Bundle, error) {
+	//nolint:gosec
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.UncheckedErrorFunc(f.Close)
+	return Read(f)
+}
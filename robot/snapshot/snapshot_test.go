@@ -0,0 +1,192 @@
+package snapshot_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	_ "go.viam.com/rdk/components/arm/fake"
+	_ "go.viam.com/rdk/components/base/fake"
+	"go.viam.com/rdk/components/gripper"
+	_ "go.viam.com/rdk/components/gripper/fake"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/snapshot"
+	"go.viam.com/rdk/testutils/localrobot"
+)
+
+const robotConfig = `{
+	"components": [
+		{
+			"name": "arm1",
+			"api": "rdk:component:arm",
+			"model": "rdk:builtin:fake",
+			"attributes": {"arm-model": "ur5e"},
+			"frame": {"parent": "world", "translation": {"x": 1, "y": 2, "z": 3}}
+		}
+	]
+}`
+
+func armJoints(t *testing.T, r robot.Robot) []float64 {
+	t.Helper()
+	a, err := arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	joints, err := a.JointPositions(context.Background(), nil)
+	test.That(t, err, test.ShouldBeNil)
+	return joints.Values
+}
+
+func TestCaptureAndRestore(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg, err := config.FromReader(ctx, "", strings.NewReader(robotConfig), logger)
+	test.That(t, err, test.ShouldBeNil)
+	cfg.Cloud = &config.Cloud{ID: "source", Secret: "secret"}
+	source := localrobot.New(t, cfg)
+
+	a, err := arm.FromRobot(source, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	joints := []float64{10, 20, 30, 40, 50, 60}
+	test.That(t, a.MoveToJointPositions(ctx, &pb.JointPositions{Values: joints}, nil), test.ShouldBeNil)
+
+	bundle, err := snapshot.Capture(ctx, source)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, bundle.Version, test.ShouldEqual, snapshot.Version)
+	test.That(t, bundle.Resources, test.ShouldHaveLength, 1)
+	test.That(t, bundle.Resources[0].Name, test.ShouldEqual, arm.Named("arm1").String())
+	test.That(t, bundle.FrameSystem, test.ShouldHaveLength, 1)
+	// the robot's identity is never captured.
+	test.That(t, string(bundle.Config), test.ShouldNotContainSubstring, "secret")
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	test.That(t, snapshot.Save(path, bundle), test.ShouldBeNil)
+	bundle, err = snapshot.Load(path)
+	test.That(t, err, test.ShouldBeNil)
+
+	// restoring on an empty robot clones the source.
+	target := localrobot.New(t, &config.Config{})
+	test.That(t, snapshot.Restore(ctx, target, bundle, snapshot.RestoreOptions{}), test.ShouldBeNil)
+	test.That(t, armJoints(t, target), test.ShouldResemble, joints)
+	fsCfg, err := target.FrameSystemConfig(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fsCfg.Parts, test.ShouldHaveLength, 1)
+	test.That(t, target.Config().Cloud, test.ShouldBeNil)
+
+	// restoring only the states keeps the current config.
+	test.That(t, a.MoveToJointPositions(ctx, &pb.JointPositions{Values: make([]float64, 6)}, nil), test.ShouldBeNil)
+	test.That(t, snapshot.Restore(ctx, source, bundle, snapshot.RestoreOptions{SkipConfig: true}), test.ShouldBeNil)
+	test.That(t, armJoints(t, source), test.ShouldResemble, joints)
+	test.That(t, source.Config().Cloud.ID, test.ShouldEqual, "source")
+
+	// states of missing resources are reported but do not stop the restore.
+	err = snapshot.Restore(ctx, localrobot.New(t, &config.Config{}), bundle, snapshot.RestoreOptions{SkipConfig: true})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "arm1")
+
+	var buf bytes.Buffer
+	bundle.Version = snapshot.Version + 1
+	test.That(t, snapshot.Write(&buf, bundle), test.ShouldBeNil)
+	bundle, err = snapshot.Read(&buf)
+	test.That(t, err, test.ShouldBeNil)
+	err = snapshot.Restore(ctx, target, bundle, snapshot.RestoreOptions{})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "unsupported snapshot version")
+}
+
+func TestCaptureAndRestoreState(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	cfg, err := config.FromReader(ctx, "", strings.NewReader(`{
+		"components": [
+			{
+				"name": "arm1",
+				"api": "rdk:component:arm",
+				"model": "rdk:builtin:fake",
+				"attributes": {"arm-model": "ur5e"},
+				"frame": {"parent": "base1"}
+			},
+			{
+				"name": "base1",
+				"api": "rdk:component:base",
+				"model": "rdk:builtin:fake",
+				"frame": {"parent": "world", "translation": {"x": 100, "y": 0, "z": 0}}
+			},
+			{
+				"name": "gripper1",
+				"api": "rdk:component:gripper",
+				"model": "rdk:builtin:fake",
+				"attributes": {"object_width_mm": 20}
+			}
+		]
+	}`), logger)
+	test.That(t, err, test.ShouldBeNil)
+	r := localrobot.New(t, cfg)
+
+	a, err := arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	joints := []float64{10, 20, 30, 40, 50, 60}
+	test.That(t, a.MoveToJointPositions(ctx, &pb.JointPositions{Values: joints}, nil), test.ShouldBeNil)
+	g, err := gripper.FromRobot(r, "gripper1")
+	test.That(t, err, test.ShouldBeNil)
+	grabbed, err := g.Grab(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, grabbed, test.ShouldBeTrue)
+
+	state, err := snapshot.CaptureState(ctx, r)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, state.Version, test.ShouldEqual, snapshot.StateVersion)
+	test.That(t, state.Errors, test.ShouldBeEmpty)
+	test.That(t, state.Arms, test.ShouldResemble, []snapshot.ArmState{{Name: "arm1", JointPositions: joints}})
+	test.That(t, state.Bases, test.ShouldHaveLength, 1)
+	test.That(t, state.Bases[0].Pose, test.ShouldNotBeNil)
+	test.That(t, state.Bases[0].Pose.X, test.ShouldAlmostEqual, 100)
+	test.That(t, state.Grippers, test.ShouldHaveLength, 1)
+	test.That(t, *state.Grippers[0].WidthMm, test.ShouldAlmostEqual, 20)
+	test.That(t, *state.Grippers[0].Holding, test.ShouldBeTrue)
+	frames := map[string]snapshot.Pose{}
+	for _, frame := range state.Frames {
+		frames[frame.Name] = frame.Pose
+	}
+	test.That(t, frames["base1"], test.ShouldResemble, *state.Bases[0].Pose)
+	// the frame of an arm is its end effector.
+	test.That(t, frames, test.ShouldContainKey, "arm1")
+
+	data, err := json.Marshal(state)
+	test.That(t, err, test.ShouldBeNil)
+	var read snapshot.State
+	test.That(t, json.Unmarshal(data, &read), test.ShouldBeNil)
+
+	// only the arms are replayed.
+	test.That(t, a.MoveToJointPositions(ctx, &pb.JointPositions{Values: make([]float64, 6)}, nil), test.ShouldBeNil)
+	test.That(t, g.Open(ctx, nil), test.ShouldBeNil)
+	test.That(t, snapshot.RestoreState(ctx, r, &read), test.ShouldBeNil)
+	test.That(t, armJoints(t, r), test.ShouldResemble, joints)
+	holding, err := g.(gripper.GripSensor).IsHolding(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, holding, test.ShouldBeFalse)
+
+	// arms that cannot take the captured positions are reported but do not stop the restore.
+	read.Arms = append([]snapshot.ArmState{
+		{Name: "arm2", JointPositions: joints},
+		{Name: "arm1", JointPositions: joints[:3]},
+		{Name: "arm1", JointPositions: []float64{1000, 0, 0, 0, 0, 0}},
+	}, read.Arms...)
+	err = snapshot.RestoreState(ctx, r, &read)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, `"arm2"`)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "arm has 6 joints, not 3")
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be moved to")
+	test.That(t, armJoints(t, r), test.ShouldResemble, joints)
+
+	read.Version = snapshot.StateVersion + 1
+	err = snapshot.RestoreState(ctx, r, &read)
+	test.That(t, err, test.ShouldBeError, "unsupported state version 2")
+}
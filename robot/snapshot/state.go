@@ -0,0 +1,251 @@
+package snapshot
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/components/gripper"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// StateVersion is the version of the state format written by CaptureState.
+const StateVersion = 1
+
+// A State is the runtime state of a robot: where its arms, bases, and grippers are, and
+// where every frame of its frame system is in the world frame. Unlike a Bundle, which
+// recreates a robot, a State records a moment of its operation, such as the start of a test
+// or the moment before a crash.
+type State struct {
+	Version    int            `json:"version"`
+	CapturedAt time.Time      `json:"captured_at"`
+	Arms       []ArmState     `json:"arms,omitempty"`
+	Bases      []BaseState    `json:"bases,omitempty"`
+	Grippers   []GripperState `json:"grippers,omitempty"`
+	// Frames are the poses of the frames of the robot's frame system in the world frame.
+	Frames []FramePose `json:"frames,omitempty"`
+	// Errors are the failures to read parts of the state, which are left out of it.
+	Errors []StateError `json:"errors,omitempty"`
+}
+
+// ArmState is the state of an arm.
+type ArmState struct {
+	Name string `json:"name"`
+	// JointPositions are the positions of the arm's joints in degrees or millimeters.
+	JointPositions []float64 `json:"joint_positions"`
+	IsMoving       bool      `json:"is_moving"`
+}
+
+// BaseState is the state of a base.
+type BaseState struct {
+	Name string `json:"name"`
+	// Pose is the pose of the base in the world frame, if the base is in the frame system.
+	Pose     *Pose `json:"pose,omitempty"`
+	IsMoving bool  `json:"is_moving"`
+}
+
+// GripperState is the state of a gripper. WidthMm and Holding are only set for grippers
+// that report them.
+type GripperState struct {
+	Name     string   `json:"name"`
+	IsMoving bool     `json:"is_moving"`
+	WidthMm  *float64 `json:"width_mm,omitempty"`
+	Holding  *bool    `json:"holding,omitempty"`
+}
+
+// FramePose is the pose of a frame in the world frame.
+type FramePose struct {
+	Name string `json:"name"`
+	Pose Pose   `json:"pose"`
+}
+
+// Pose is the JSON form of a pose, in millimeters and an orientation vector in degrees.
+type Pose struct {
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Z     float64 `json:"z"`
+	OX    float64 `json:"o_x"`
+	OY    float64 `json:"o_y"`
+	OZ    float64 `json:"o_z"`
+	Theta float64 `json:"theta"`
+}
+
+func poseFromSpatial(pose spatialmath.Pose) Pose {
+	pt := pose.Point()
+	ov := pose.Orientation().OrientationVectorDegrees()
+	return Pose{X: pt.X, Y: pt.Y, Z: pt.Z, OX: ov.OX, OY: ov.OY, OZ: ov.OZ, Theta: ov.Theta}
+}
+
+// Spatial returns the pose as a spatialmath.Pose.
+func (p Pose) Spatial() spatialmath.Pose {
+	return spatialmath.NewPose(
+		r3.Vector{X: p.X, Y: p.Y, Z: p.Z},
+		&spatialmath.OrientationVectorDegrees{OX: p.OX, OY: p.OY, OZ: p.OZ, Theta: p.Theta},
+	)
+}
+
+// StateError is a failure to read part of a state, such as the joint positions of an arm.
+type StateError struct {
+	// Source is the name of the resource, or "frame_system".
+	Source string `json:"source"`
+	Error  string `json:"error"`
+}
+
+// frameSystemSource is the Source of the errors reading the frame system.
+const frameSystemSource = "frame_system"
+
+// CaptureState returns the runtime state of the given robot, including its remotes'
+// resources. Parts of the state that fail to be read are recorded in its Errors rather than
+// failing the capture, since a state is most useful when the robot is misbehaving; an error
+// is only returned if ctx is done.
+func CaptureState(ctx context.Context, r robot.Robot) (*State, error) {
+	state := &State{Version: StateVersion, CapturedAt: clock.Now()}
+	fail := func(source string, err error) {
+		state.Errors = append(state.Errors, StateError{Source: source, Error: err.Error()})
+	}
+
+	frames := map[string]Pose{}
+	if fsCfg, err := r.FrameSystemConfig(ctx); err != nil {
+		fail(frameSystemSource, err)
+	} else {
+		for _, part := range fsCfg.Parts {
+			name := part.FrameConfig.Name()
+			origin := referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose())
+			pif, err := r.TransformPose(ctx, origin, referenceframe.World, nil)
+			if err != nil {
+				fail(frameSystemSource, errors.Wrapf(err, "failed to locate frame %q", name))
+				continue
+			}
+			frames[name] = poseFromSpatial(pif.Pose())
+			state.Frames = append(state.Frames, FramePose{Name: name, Pose: frames[name]})
+		}
+		sort.Slice(state.Frames, func(i, j int) bool { return state.Frames[i].Name < state.Frames[j].Name })
+	}
+
+	for _, name := range sorted(arm.NamesFromRobot(r)) {
+		a, err := arm.FromRobot(r, name)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		joints, err := a.JointPositions(ctx, nil)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		moving, err := a.IsMoving(ctx)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		state.Arms = append(state.Arms, ArmState{Name: name, JointPositions: joints.Values, IsMoving: moving})
+	}
+
+	for _, name := range sorted(base.NamesFromRobot(r)) {
+		b, err := base.FromRobot(r, name)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		moving, err := b.IsMoving(ctx)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		baseState := BaseState{Name: name, IsMoving: moving}
+		if pose, ok := frames[name]; ok {
+			baseState.Pose = &pose
+		}
+		state.Bases = append(state.Bases, baseState)
+	}
+
+	for _, name := range sorted(gripper.NamesFromRobot(r)) {
+		g, err := gripper.FromRobot(r, name)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		gripperState, err := captureGripper(ctx, g)
+		if err != nil {
+			fail(name, err)
+			continue
+		}
+		gripperState.Name = name
+		state.Grippers = append(state.Grippers, gripperState)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return state, nil
+}
+
+func captureGripper(ctx context.Context, g gripper.Gripper) (GripperState, error) {
+	moving, err := g.IsMoving(ctx)
+	if err != nil {
+		return GripperState{}, err
+	}
+	state := GripperState{IsMoving: moving}
+	if pg, ok := g.(gripper.PositionGripper); ok {
+		if width, err := pg.Width(ctx, nil); err == nil {
+			state.WidthMm = &width
+		}
+	}
+	if gs, ok := g.(gripper.GripSensor); ok {
+		if holding, err := gs.IsHolding(ctx, nil); err == nil {
+			state.Holding = &holding
+		}
+	}
+	return state, nil
+}
+
+func sorted(names []string) []string {
+	sort.Strings(names)
+	return names
+}
+
+// RestoreState replays the parts of a state that are safe to replay on the given robot: the
+// arms that were at rest are moved back to their joint positions, if those are within the
+// arms' limits. Grippers are not replayed, since opening or closing them may drop or crush
+// what they hold, nor are bases, which would have to be driven to their poses. Failures are
+// combined into the returned error rather than stopping the restore.
+func RestoreState(ctx context.Context, r robot.Robot, state *State) error {
+	if state.Version != StateVersion {
+		return errors.Errorf("unsupported state version %d", state.Version)
+	}
+	var restoreErr error
+	for _, armState := range state.Arms {
+		if armState.IsMoving {
+			continue
+		}
+		if err := restoreArm(ctx, r, armState); err != nil {
+			restoreErr = multierr.Combine(restoreErr, errors.Wrapf(err, "failed to restore arm %q", armState.Name))
+		}
+	}
+	return restoreErr
+}
+
+func restoreArm(ctx context.Context, r robot.Robot, state ArmState) error {
+	a, err := arm.FromRobot(r, state.Name)
+	if err != nil {
+		return err
+	}
+	model := a.ModelFrame()
+	if len(state.JointPositions) != len(model.DoF()) {
+		return errors.Errorf("arm has %d joints, not %d", len(model.DoF()), len(state.JointPositions))
+	}
+	positions := &pb.JointPositions{Values: state.JointPositions}
+	if err := arm.CheckDesiredJointPositions(ctx, a, model.InputFromProtobuf(positions)); err != nil {
+		return err
+	}
+	return a.MoveToJointPositions(ctx, positions, nil)
+}
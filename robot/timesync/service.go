@@ -0,0 +1,87 @@
+package timesync
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a robot's clock. Its requests and
+// responses are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.timesync.v1.TimeSyncService"
+
+// getTimeResponse holds the times, by the served robot's clock, at which a request for its
+// time was received and its response sent.
+type getTimeResponse struct {
+	ReceiveTime  time.Time `json:"receive_time"`
+	TransmitTime time.Time `json:"transmit_time"`
+}
+
+// listClockEstimatesResponse holds the estimates of the clocks of the served robot's remotes.
+type listClockEstimatesResponse struct {
+	Estimates []Estimate `json:"estimates"`
+}
+
+// A Server serves a robot's clock with ServiceDesc.
+type Server struct {
+	tracker *Tracker
+}
+
+// NewServer returns a server of the clock of the robot whose remotes' clocks the given
+// tracker measures.
+func NewServer(tracker *Tracker) *Server {
+	return &Server{tracker: tracker}
+}
+
+func (s *Server) getTime(ctx context.Context, _ struct{}) (interface{}, error) {
+	// the receive time is taken as soon as the call is handled, so that the exchange is as
+	// tight as it can be.
+	received := time.Now()
+	return getTimeResponse{ReceiveTime: received, TransmitTime: time.Now()}, nil
+}
+
+func (s *Server) listClockEstimates(ctx context.Context, _ struct{}) (interface{}, error) {
+	return &listClockEstimatesResponse{Estimates: s.tracker.Estimates()}, nil
+}
+
+// ServiceDesc describes the gRPC service serving a robot's clock. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/timesync",
+	structrpc.Unary("GetTime", (*Server).getTime),
+	structrpc.Unary("ListClockEstimates", (*Server).listClockEstimates),
+)
+
+// A Client is the clock of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+// NewClient returns a client of the clock served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Exchange makes one exchange with the robot and returns the estimate of the offset of its
+// clock it makes. The estimate has no Remote.
+func (c *Client) Exchange(ctx context.Context) (Estimate, error) {
+	var resp getTimeResponse
+	sent := time.Now()
+	if err := c.client.Invoke(ctx, "GetTime", struct{}{}, &resp); err != nil {
+		return Estimate{}, err
+	}
+	received := time.Now()
+	offset := (resp.ReceiveTime.Sub(sent) + resp.TransmitTime.Sub(received)) / 2
+	delay := received.Sub(sent) - resp.TransmitTime.Sub(resp.ReceiveTime)
+	return Estimate{Offset: offset, Uncertainty: max(delay, 0) / 2, Measured: received}, nil
+}
+
+// Estimates returns the estimates the robot keeps of the clocks of its own remotes.
+func (c *Client) Estimates(ctx context.Context) ([]Estimate, error) {
+	var resp listClockEstimatesResponse
+	if err := c.client.Invoke(ctx, "ListClockEstimates", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Estimates, nil
+}
@@ -0,0 +1,222 @@
+// Package timesync measures the offsets between the clock of a robot and the clocks of its
+// remotes, so that data timestamped on different hosts, such as the readings of sensors on
+// several robots, can be aligned even when the clocks of the hosts drift apart.
+//
+// Offsets are measured with NTP-style exchanges over the connections to the remotes: the
+// robot notes when it sends a request and receives the response, and the remote when it
+// receives the request and sends the response. The exchange with the shortest round trip of a
+// measurement is kept, and half its round trip bounds how far off the estimated offset can be.
+// Each remote also serves the estimates it keeps for its own remotes, which are composed with
+// the remote's offset so that the clocks of remotes of remotes are estimated too.
+//
+// A timestamp taken by a resource is aligned with the clock of the robot it is on: the
+// readings of "other:imu" are aligned with the estimate of the clock of the remote "other".
+package timesync
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/utils"
+)
+
+const (
+	// DefaultInterval is how often the clocks of remotes are measured.
+	DefaultInterval = 10 * time.Second
+	// ExchangesPerMeasurement is how many exchanges a measurement of a clock makes, keeping
+	// the one with the shortest round trip.
+	ExchangesPerMeasurement = 4
+)
+
+// An Estimate is the estimated offset of the clock of a remote from the clock of a robot.
+type Estimate struct {
+	// Remote is the name of the remote, with the names of the remotes it is reached through
+	// (e.g. "other:arm-pi").
+	Remote string `json:"remote"`
+	// Offset is how far ahead the remote's clock is.
+	Offset time.Duration `json:"offset"`
+	// Uncertainty bounds how far off Offset may be.
+	Uncertainty time.Duration `json:"uncertainty"`
+	// Measured is when the offset was measured, by the robot's clock.
+	Measured time.Time `json:"measured"`
+}
+
+// A Timestamp is a time taken on some robot, aligned with the clock of the robot aligning it.
+type Timestamp struct {
+	// Time is the time by the aligning robot's clock.
+	Time time.Time
+	// Source is the time as taken.
+	Source time.Time
+	// Offset and Uncertainty are those of the estimate the time was aligned with, or zero for
+	// times taken on the aligning robot.
+	Offset      time.Duration
+	Uncertainty time.Duration
+}
+
+// A Robot is a robot that measures the clocks of its remotes.
+type Robot interface {
+	robot.Robot
+	// TimeSync returns the tracker of the clocks of the robot's remotes.
+	TimeSync() *Tracker
+}
+
+// remoteClock is a remote whose clock can be measured, such as a robot client.
+type remoteClock interface {
+	TimeSync() *Client
+}
+
+// A Tracker keeps the estimated offsets of the clocks of the remotes of a robot.
+type Tracker struct {
+	r       robot.Robot
+	logger  logging.Logger
+	workers utils.StoppableWorkers
+
+	mu        sync.Mutex
+	estimates map[string]Estimate
+}
+
+// NewTracker returns a tracker of the clocks of the remotes of the given robot, measuring them
+// every interval. If interval is zero, clocks are only measured by Measure.
+func NewTracker(r robot.Robot, logger logging.Logger, interval time.Duration) *Tracker {
+	t := &Tracker{r: r, logger: logger, estimates: map[string]Estimate{}}
+	if interval > 0 {
+		t.workers = utils.NewStoppableWorkers(func(ctx context.Context) {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+				}
+				t.Measure(ctx)
+			}
+		})
+	}
+	return t
+}
+
+// Measure measures the clocks of the robot's remotes now. The estimates of the remotes that
+// fail to be measured are kept from before, and those of the remotes that are gone are
+// dropped.
+func (t *Tracker) Measure(ctx context.Context) {
+	estimates := map[string]Estimate{}
+	t.mu.Lock()
+	previous := t.estimates
+	t.mu.Unlock()
+	for _, name := range t.r.RemoteNames() {
+		remote, ok := t.r.RemoteByName(name)
+		if !ok {
+			continue
+		}
+		clock, ok := remote.(remoteClock)
+		if !ok {
+			continue
+		}
+		estimate, err := measure(ctx, clock.TimeSync())
+		if err != nil {
+			if ctx.Err() == nil {
+				t.logger.CDebugw(ctx, "failed to measure clock of remote", "remote", name, "error", err)
+			}
+			for key, prev := range previous {
+				if key == name || strings.HasPrefix(key, name+":") {
+					estimates[key] = prev
+				}
+			}
+			continue
+		}
+		estimate.Remote = name
+		estimates[name] = estimate
+		// remotes that do not measure clocks have no estimates to add.
+		nested, err := clock.TimeSync().Estimates(ctx)
+		if err != nil {
+			continue
+		}
+		for _, other := range nested {
+			estimates[name+":"+other.Remote] = Estimate{
+				Remote:      name + ":" + other.Remote,
+				Offset:      estimate.Offset + other.Offset,
+				Uncertainty: estimate.Uncertainty + other.Uncertainty,
+				Measured:    estimate.Measured,
+			}
+		}
+	}
+	t.mu.Lock()
+	t.estimates = estimates
+	t.mu.Unlock()
+}
+
+// measure returns the estimate of the clock served by the client from the exchange with the
+// shortest round trip of ExchangesPerMeasurement.
+func measure(ctx context.Context, c *Client) (Estimate, error) {
+	var best Estimate
+	var errs error
+	for i := 0; i < ExchangesPerMeasurement; i++ {
+		estimate, err := c.Exchange(ctx)
+		if err != nil {
+			errs = err
+			continue
+		}
+		if best.Measured.IsZero() || estimate.Uncertainty < best.Uncertainty {
+			best = estimate
+		}
+	}
+	if best.Measured.IsZero() {
+		return Estimate{}, errs
+	}
+	return best, nil
+}
+
+// Estimates returns the estimates of the clocks of the robot's remotes, sorted by remote.
+func (t *Tracker) Estimates() []Estimate {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	estimates := make([]Estimate, 0, len(t.estimates))
+	for _, estimate := range t.estimates {
+		estimates = append(estimates, estimate)
+	}
+	sort.Slice(estimates, func(i, j int) bool { return estimates[i].Remote < estimates[j].Remote })
+	return estimates
+}
+
+// Estimate returns the estimate of the clock of the named remote, if it was measured.
+func (t *Tracker) Estimate(remote string) (Estimate, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	estimate, ok := t.estimates[remote]
+	return estimate, ok
+}
+
+// Align aligns a time taken by the named resource, such as "other:imu", with the robot's
+// clock. Times taken by the robot's own resources are returned as they are.
+func (t *Tracker) Align(resourceName string, taken time.Time) (Timestamp, error) {
+	idx := strings.LastIndex(resourceName, ":")
+	if idx == -1 {
+		return Timestamp{Time: taken, Source: taken}, nil
+	}
+	remote := resourceName[:idx]
+	estimate, ok := t.Estimate(remote)
+	if !ok {
+		return Timestamp{}, errors.Errorf("the clock of remote %q has not been measured", remote)
+	}
+	return Timestamp{
+		Time:        taken.Add(-estimate.Offset),
+		Source:      taken,
+		Offset:      estimate.Offset,
+		Uncertainty: estimate.Uncertainty,
+	}, nil
+}
+
+// Close stops measuring clocks.
+func (t *Tracker) Close() {
+	if t.workers != nil {
+		t.workers.Stop()
+	}
+}
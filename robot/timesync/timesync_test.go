@@ -0,0 +1,117 @@
+package timesync_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/timesync"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// syncedRobot is a robot with the given remotes whose clocks it measures.
+type syncedRobot struct {
+	*inject.Robot
+	tracker *timesync.Tracker
+}
+
+func (r *syncedRobot) TimeSync() *timesync.Tracker {
+	return r.tracker
+}
+
+func newSyncedRobot(t *testing.T, logger logging.Logger, remotes map[string]robot.Robot) *syncedRobot {
+	t.Helper()
+	r := &syncedRobot{Robot: &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+		RemoteNamesFunc: func() []string {
+			var names []string
+			for name := range remotes {
+				names = append(names, name)
+			}
+			return names
+		},
+		RemoteByNameFunc: func(name string) (robot.Robot, bool) {
+			remote, ok := remotes[name]
+			return remote, ok
+		},
+	}}
+	r.MockResourcesFromMap(nil)
+	r.tracker = timesync.NewTracker(r, logger, 0)
+	t.Cleanup(r.tracker.Close)
+	return r
+}
+
+func newClient(t *testing.T, logger logging.Logger, r robot.Robot) *client.RobotClient {
+	t.Helper()
+	robotClient, err := client.NewInProcess(context.Background(), r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	return robotClient
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+
+	// the robot reaches "arm-pi" through its remote "other".
+	armPi := newSyncedRobot(t, logger, nil)
+	armPiClient := newClient(t, logger, armPi)
+	defer func() {
+		test.That(t, armPiClient.Close(ctx), test.ShouldBeNil)
+	}()
+	other := newSyncedRobot(t, logger, map[string]robot.Robot{"arm-pi": armPiClient})
+	otherClient := newClient(t, logger, other)
+	r := newSyncedRobot(t, logger, map[string]robot.Robot{"other": otherClient})
+
+	other.tracker.Measure(ctx)
+	r.tracker.Measure(ctx)
+	estimates := r.tracker.Estimates()
+	test.That(t, estimates, test.ShouldHaveLength, 2)
+	test.That(t, estimates[0].Remote, test.ShouldEqual, "other")
+	test.That(t, estimates[1].Remote, test.ShouldEqual, "other:arm-pi")
+	for _, estimate := range estimates {
+		// the clocks are the same, so the offsets are within their uncertainties of zero.
+		test.That(t, estimate.Offset.Abs(), test.ShouldBeLessThanOrEqualTo, estimate.Uncertainty)
+		test.That(t, estimate.Uncertainty, test.ShouldBeLessThan, time.Second)
+		test.That(t, estimate.Measured.IsZero(), test.ShouldBeFalse)
+	}
+	test.That(t, estimates[1].Uncertainty, test.ShouldBeGreaterThanOrEqualTo, estimates[0].Uncertainty)
+
+	t.Run("remote estimates are served", func(t *testing.T) {
+		served, err := otherClient.TimeSync().Estimates(ctx)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, served, test.ShouldHaveLength, 1)
+		test.That(t, served[0].Remote, test.ShouldEqual, "arm-pi")
+	})
+
+	t.Run("align", func(t *testing.T) {
+		taken := time.Now()
+		stamp, err := r.tracker.Align("imu", taken)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stamp.Time, test.ShouldEqual, taken)
+		test.That(t, stamp.Offset, test.ShouldEqual, 0)
+
+		stamp, err = r.tracker.Align("other:arm-pi:imu", taken)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, stamp.Source, test.ShouldEqual, taken)
+		test.That(t, stamp.Offset, test.ShouldEqual, estimates[1].Offset)
+		test.That(t, stamp.Uncertainty, test.ShouldEqual, estimates[1].Uncertainty)
+		test.That(t, stamp.Time, test.ShouldEqual, taken.Add(-estimates[1].Offset))
+
+		_, err = r.tracker.Align("gone:imu", taken)
+		test.That(t, err, test.ShouldBeError, `the clock of remote "gone" has not been measured`)
+	})
+
+	t.Run("estimates are kept when measuring fails", func(t *testing.T) {
+		test.That(t, otherClient.Close(ctx), test.ShouldBeNil)
+		r.tracker.Measure(ctx)
+		test.That(t, r.tracker.Estimates(), test.ShouldResemble, estimates)
+	})
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving tools. Its requests and responses are
+// google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.tools.v1.ToolService"
+
+type request struct {
+	Arm  string `json:"arm"`
+	Name string `json:"name,omitempty"`
+	Tool *Tool  `json:"tool,omitempty"`
+}
+
+type mountedResponse struct {
+	Tool *Tool `json:"tool,omitempty"`
+}
+
+type listResponse struct {
+	Tools []Tool `json:"tools"`
+}
+
+// A Server serves tools with ServiceDesc.
+type Server struct {
+	tools Service
+}
+
+// NewServer returns a server for the given tools.
+func NewServer(tools Service) *Server {
+	return &Server{tools: tools}
+}
+
+func (s *Server) declareTool(ctx context.Context, req request) (interface{}, error) {
+	if req.Tool == nil {
+		return nil, status.Error(codes.InvalidArgument, "tool is required")
+	}
+	return struct{}{}, s.tools.Declare(ctx, req.Arm, *req.Tool)
+}
+
+func (s *Server) removeTool(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.tools.Remove(ctx, req.Arm, req.Name)
+}
+
+func (s *Server) mountTool(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.tools.Mount(ctx, req.Arm, req.Name)
+}
+
+func (s *Server) unmountTool(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.tools.Unmount(ctx, req.Arm)
+}
+
+func (s *Server) getMountedTool(ctx context.Context, req request) (interface{}, error) {
+	tool, err := s.tools.Mounted(ctx, req.Arm)
+	return mountedResponse{Tool: tool}, err
+}
+
+func (s *Server) listTools(ctx context.Context, req request) (interface{}, error) {
+	tools, err := s.tools.Tools(ctx, req.Arm)
+	return listResponse{Tools: tools}, err
+}
+
+// ServiceDesc describes the gRPC service serving tools. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/tools",
+	structrpc.Unary("DeclareTool", (*Server).declareTool),
+	structrpc.Unary("RemoveTool", (*Server).removeTool),
+	structrpc.Unary("MountTool", (*Server).mountTool),
+	structrpc.Unary("UnmountTool", (*Server).unmountTool),
+	structrpc.Unary("GetMountedTool", (*Server).getMountedTool),
+	structrpc.Unary("ListTools", (*Server).listTools),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the tools served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// Declare declares a tool for the named arm, replacing the tool of the same name. If the tool
+// is mounted, the replacement is mounted in its place.
+func (c *Client) Declare(ctx context.Context, arm string, tool Tool) error {
+	return c.client.Invoke(ctx, "DeclareTool", request{Arm: arm, Tool: &tool}, nil)
+}
+
+// Remove removes a tool declared for the named arm. A mounted tool cannot be removed.
+func (c *Client) Remove(ctx context.Context, arm, name string) error {
+	return c.client.Invoke(ctx, "RemoveTool", request{Arm: arm, Name: name}, nil)
+}
+
+// Mount mounts the named tool on the arm in place of the tool mounted on it, if any.
+func (c *Client) Mount(ctx context.Context, arm, name string) error {
+	return c.client.Invoke(ctx, "MountTool", request{Arm: arm, Name: name}, nil)
+}
+
+// Unmount unmounts the tool mounted on the arm, if any.
+func (c *Client) Unmount(ctx context.Context, arm string) error {
+	return c.client.Invoke(ctx, "UnmountTool", request{Arm: arm}, nil)
+}
+
+// Mounted returns the tool mounted on the arm, or nil if none is.
+func (c *Client) Mounted(ctx context.Context, arm string) (*Tool, error) {
+	var resp mountedResponse
+	if err := c.client.Invoke(ctx, "GetMountedTool", request{Arm: arm}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tool, nil
+}
+
+// Tools returns the tools declared for the arm, sorted by name.
+func (c *Client) Tools(ctx context.Context, arm string) ([]Tool, error) {
+	var resp listResponse
+	if err := c.client.Invoke(ctx, "ListTools", request{Arm: arm}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Tools, nil
+}
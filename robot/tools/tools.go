@@ -0,0 +1,314 @@
+// Package tools manages the tools mounted on the end effectors of a robot's arms. Tools are
+// declared for an arm at runtime and switched with Mount, which adds the tool's frame and
+// collision geometry to the robot's frame system and hands its payload to the arm, so that
+// tool changers do not require config edits and restarts.
+//
+// The frame of the tool mounted on an arm is named ToolFrameName(arm) whichever tool it is,
+// so that motion requests can target the tool center point without knowing which tool is
+// mounted.
+package tools
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// ToolFrameName returns the name of the frame of the tool mounted on the named arm.
+func ToolFrameName(arm string) string {
+	return arm + "_tool"
+}
+
+// Payload describes the mass properties of a tool.
+type Payload struct {
+	// MassKg is the mass of the tool in kilograms.
+	MassKg float64 `json:"mass_kg"`
+	// CenterOfMass is the center of mass of the tool in millimeters, relative to the end
+	// effector of the arm.
+	CenterOfMass r3.Vector `json:"center_of_mass"`
+	// Inertia is the inertia tensor of the tool about its center of mass in kg*m^2, given
+	// as Ixx, Iyy, Izz, Ixy, Ixz, Iyz. It may be empty if unknown.
+	Inertia []float64 `json:"inertia,omitempty"`
+}
+
+// A PayloadSetter is an arm whose controller compensates for the payload attached to its end
+// effector. The payload of a mounted tool is set on arms that implement it, and the zero
+// payload is set when the tool is unmounted.
+type PayloadSetter interface {
+	SetPayload(ctx context.Context, payload Payload) error
+}
+
+// Tool describes a tool that can be mounted on the end effector of an arm.
+type Tool struct {
+	// Name identifies the tool among those declared for an arm.
+	Name string `json:"name"`
+	// Translation and Orientation are the pose of the tool center point relative to the end
+	// effector of the arm.
+	Translation r3.Vector                      `json:"translation"`
+	Orientation *spatialmath.OrientationConfig `json:"orientation,omitempty"`
+	// Geometry is the collision geometry of the tool, relative to the tool center point.
+	Geometry *spatialmath.GeometryConfig `json:"geometry,omitempty"`
+	// Payload is the mass properties of the tool.
+	Payload Payload `json:"payload"`
+}
+
+// Validate checks if the tool is valid.
+func (t *Tool) Validate() error {
+	if t.Name == "" {
+		return errors.New("tool name must not be empty")
+	}
+	if t.Payload.MassKg < 0 {
+		return errors.New("tool mass must not be negative")
+	}
+	if n := len(t.Payload.Inertia); n != 0 && n != 6 {
+		return errors.Errorf("tool inertia must have 6 values (Ixx, Iyy, Izz, Ixy, Ixz, Iyz), got %d", n)
+	}
+	if _, err := t.linkConfig("").ParseConfig(); err != nil {
+		return errors.Wrapf(err, "invalid frame for tool %q", t.Name)
+	}
+	return nil
+}
+
+func (t *Tool) linkConfig(arm string) *referenceframe.LinkConfig {
+	return &referenceframe.LinkConfig{
+		ID:          ToolFrameName(arm),
+		Translation: t.Translation,
+		Orientation: t.Orientation,
+		Geometry:    t.Geometry,
+		Parent:      arm,
+	}
+}
+
+// Service declares and mounts tools on the arms of a robot.
+type Service interface {
+	// Declare declares a tool for the named arm, replacing the tool of the same name. If the
+	// tool is mounted, the replacement is mounted in its place.
+	Declare(ctx context.Context, arm string, tool Tool) error
+	// Remove removes a tool declared for the named arm. A mounted tool cannot be removed.
+	Remove(ctx context.Context, arm, name string) error
+	// Mount mounts the named tool on the arm in place of the tool mounted on it, if any.
+	Mount(ctx context.Context, arm, name string) error
+	// Unmount unmounts the tool mounted on the arm, if any.
+	Unmount(ctx context.Context, arm string) error
+	// Mounted returns the tool mounted on the arm, or nil if none is.
+	Mounted(ctx context.Context, arm string) (*Tool, error)
+	// Tools returns the tools declared for the arm, sorted by name.
+	Tools(ctx context.Context, arm string) ([]Tool, error)
+}
+
+// A Robot is a robot with tools mounted on its arms.
+type Robot interface {
+	// Tools returns the manager of the tools mounted on the robot's arms, which is also
+	// served to remote clients. Mounted tools are part of the frame system.
+	Tools() *Manager
+}
+
+// A Manager keeps the tools declared for and mounted on the arms of a robot.
+type Manager struct {
+	logger   logging.Logger
+	lookup   func(arm string) (resource.Resource, error)
+	onChange func(ctx context.Context) error
+
+	// opMu serializes changes so that a failed change can be reverted. mu guards the state
+	// and is not held while calling onChange, which reads the frame system parts.
+	opMu     sync.Mutex
+	mu       sync.Mutex
+	declared map[string]map[string]Tool
+	mounted  map[string]string
+}
+
+var _ Service = (*Manager)(nil)
+
+// NewManager returns a manager of the tools of the arms returned by lookup. onChange is
+// called whenever the mounted tools change and should rebuild the frame system; if it
+// fails, the change is reverted.
+func NewManager(
+	logger logging.Logger,
+	lookup func(arm string) (resource.Resource, error),
+	onChange func(ctx context.Context) error,
+) *Manager {
+	return &Manager{
+		logger:   logger,
+		lookup:   lookup,
+		onChange: onChange,
+		declared: map[string]map[string]Tool{},
+		mounted:  map[string]string{},
+	}
+}
+
+// Declare declares a tool for the named arm, replacing the tool of the same name. If the tool
+// is mounted, the replacement is mounted in its place.
+func (m *Manager) Declare(ctx context.Context, arm string, tool Tool) error {
+	if err := tool.Validate(); err != nil {
+		return err
+	}
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.Lock()
+	previous, existed := m.declared[arm][tool.Name]
+	if m.declared[arm] == nil {
+		m.declared[arm] = map[string]Tool{}
+	}
+	m.declared[arm][tool.Name] = tool
+	isMounted := m.mounted[arm] == tool.Name
+	m.mu.Unlock()
+	if !isMounted {
+		return nil
+	}
+
+	if err := m.apply(ctx, arm, &tool); err != nil {
+		m.mu.Lock()
+		if existed {
+			m.declared[arm][tool.Name] = previous
+		}
+		m.mu.Unlock()
+		return multierr.Combine(err, m.apply(ctx, arm, &previous))
+	}
+	return nil
+}
+
+// Remove removes a tool declared for the named arm. A mounted tool cannot be removed.
+func (m *Manager) Remove(ctx context.Context, arm, name string) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.declared[arm][name]; !ok {
+		return errors.Errorf("no tool %q declared for arm %q", name, arm)
+	}
+	if m.mounted[arm] == name {
+		return errors.Errorf("tool %q is mounted on arm %q; unmount it first", name, arm)
+	}
+	delete(m.declared[arm], name)
+	if len(m.declared[arm]) == 0 {
+		delete(m.declared, arm)
+	}
+	return nil
+}
+
+// Mount mounts the named tool on the arm in place of the tool mounted on it, if any.
+func (m *Manager) Mount(ctx context.Context, arm, name string) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.Lock()
+	tool, ok := m.declared[arm][name]
+	previous, hadPrevious := m.declared[arm][m.mounted[arm]]
+	m.mu.Unlock()
+	if !ok {
+		return errors.Errorf("no tool %q declared for arm %q", name, arm)
+	}
+	if err := m.switchTool(ctx, arm, &tool); err != nil {
+		if hadPrevious {
+			return multierr.Combine(err, m.switchTool(ctx, arm, &previous))
+		}
+		return multierr.Combine(err, m.switchTool(ctx, arm, nil))
+	}
+	m.logger.CInfow(ctx, "mounted tool", "arm", arm, "tool", name)
+	return nil
+}
+
+// Unmount unmounts the tool mounted on the arm, if any.
+func (m *Manager) Unmount(ctx context.Context, arm string) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.Lock()
+	previous, hadPrevious := m.declared[arm][m.mounted[arm]]
+	m.mu.Unlock()
+	if !hadPrevious {
+		return nil
+	}
+	if err := m.switchTool(ctx, arm, nil); err != nil {
+		return multierr.Combine(err, m.switchTool(ctx, arm, &previous))
+	}
+	m.logger.CInfow(ctx, "unmounted tool", "arm", arm, "tool", previous.Name)
+	return nil
+}
+
+// switchTool records the tool, or none if nil, as mounted on the arm and applies it.
+func (m *Manager) switchTool(ctx context.Context, arm string, tool *Tool) error {
+	m.mu.Lock()
+	if tool == nil {
+		delete(m.mounted, arm)
+	} else {
+		m.mounted[arm] = tool.Name
+	}
+	m.mu.Unlock()
+	return m.apply(ctx, arm, tool)
+}
+
+// apply sets the payload of the tool, or the zero payload if nil, on the arm and rebuilds
+// the frame system.
+func (m *Manager) apply(ctx context.Context, arm string, tool *Tool) error {
+	res, err := m.lookup(arm)
+	if err != nil {
+		return err
+	}
+	if setter, ok := res.(PayloadSetter); ok {
+		var payload Payload
+		if tool != nil {
+			payload = tool.Payload
+		}
+		if err := setter.SetPayload(ctx, payload); err != nil {
+			return errors.Wrapf(err, "failed to set payload of arm %q", arm)
+		}
+	}
+	return m.onChange(ctx)
+}
+
+// Mounted returns the tool mounted on the arm, or nil if none is.
+func (m *Manager) Mounted(ctx context.Context, arm string) (*Tool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tool, ok := m.declared[arm][m.mounted[arm]]
+	if !ok {
+		return nil, nil
+	}
+	return &tool, nil
+}
+
+// Tools returns the tools declared for the arm, sorted by name.
+func (m *Manager) Tools(ctx context.Context, arm string) ([]Tool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	tools := make([]Tool, 0, len(m.declared[arm]))
+	for _, tool := range m.declared[arm] {
+		tools = append(tools, tool)
+	}
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools, nil
+}
+
+// FrameSystemParts returns the frames of the mounted tools, each a child of its arm's frame.
+func (m *Manager) FrameSystemParts() ([]*referenceframe.FrameSystemPart, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	arms := make([]string, 0, len(m.mounted))
+	for arm := range m.mounted {
+		arms = append(arms, arm)
+	}
+	sort.Strings(arms)
+
+	parts := make([]*referenceframe.FrameSystemPart, 0, len(arms))
+	for _, arm := range arms {
+		tool := m.declared[arm][m.mounted[arm]]
+		lif, err := tool.linkConfig(arm).ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, &referenceframe.FrameSystemPart{FrameConfig: lif})
+	}
+	return parts, nil
+}
@@ -0,0 +1,195 @@
+package tools_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/tools"
+	"go.viam.com/rdk/spatialmath"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+type payloadArm struct {
+	*inject.Arm
+	payload tools.Payload
+	err     error
+}
+
+func (a *payloadArm) SetPayload(ctx context.Context, payload tools.Payload) error {
+	if a.err != nil {
+		return a.err
+	}
+	a.payload = payload
+	return nil
+}
+
+var gripper = tools.Tool{
+	Name:        "gripper",
+	Translation: r3.Vector{Z: 100},
+	Geometry:    &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: 50, Y: 50, Z: 100},
+	Payload:     tools.Payload{MassKg: 1.5, CenterOfMass: r3.Vector{Z: 50}},
+}
+
+var welder = tools.Tool{
+	Name:        "welder",
+	Translation: r3.Vector{Z: 250},
+	Payload:     tools.Payload{MassKg: 3, Inertia: []float64{0.1, 0.1, 0.05, 0, 0, 0}},
+}
+
+func TestToolValidate(t *testing.T) {
+	test.That(t, gripper.Validate(), test.ShouldBeNil)
+	test.That(t, welder.Validate(), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		name   string
+		modify func(tool *tools.Tool)
+		errStr string
+	}{
+		{"no name", func(tool *tools.Tool) { tool.Name = "" }, "name"},
+		{"negative mass", func(tool *tools.Tool) { tool.Payload.MassKg = -1 }, "mass"},
+		{"bad inertia", func(tool *tools.Tool) { tool.Payload.Inertia = []float64{1, 2} }, "inertia"},
+		{"bad geometry", func(tool *tools.Tool) {
+			tool.Geometry = &spatialmath.GeometryConfig{Type: spatialmath.BoxType, X: -1}
+		}, "invalid frame"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			tool := gripper
+			tc.modify(&tool)
+			err := tool.Validate()
+			test.That(t, err, test.ShouldNotBeNil)
+			test.That(t, err.Error(), test.ShouldContainSubstring, tc.errStr)
+		})
+	}
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	arm1 := &payloadArm{Arm: inject.NewArm("arm1")}
+	var changes int
+	var changeErr error
+	manager := tools.NewManager(logging.NewTestLogger(t),
+		func(name string) (resource.Resource, error) {
+			if name != "arm1" {
+				return nil, resource.NewNotFoundError(arm.Named(name))
+			}
+			return arm1, nil
+		},
+		func(ctx context.Context) error {
+			changes++
+			return changeErr
+		})
+
+	test.That(t, manager.Mount(ctx, "arm1", "gripper"), test.ShouldBeError,
+		errors.New(`no tool "gripper" declared for arm "arm1"`))
+	test.That(t, manager.Declare(ctx, "arm1", gripper), test.ShouldBeNil)
+	test.That(t, manager.Declare(ctx, "arm1", welder), test.ShouldBeNil)
+	test.That(t, manager.Declare(ctx, "arm2", gripper), test.ShouldBeNil)
+	test.That(t, changes, test.ShouldEqual, 0)
+	declared, err := manager.Tools(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, declared, test.ShouldResemble, []tools.Tool{gripper, welder})
+
+	// mounting a tool sets the payload of the arm and adds the tool's frame.
+	test.That(t, manager.Mount(ctx, "arm1", "gripper"), test.ShouldBeNil)
+	test.That(t, changes, test.ShouldEqual, 1)
+	test.That(t, arm1.payload, test.ShouldResemble, gripper.Payload)
+	mounted, err := manager.Mounted(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, *mounted, test.ShouldResemble, gripper)
+	parts, err := manager.FrameSystemParts()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parts, test.ShouldHaveLength, 1)
+	test.That(t, parts[0].FrameConfig.Name(), test.ShouldEqual, tools.ToolFrameName("arm1"))
+	test.That(t, parts[0].FrameConfig.Parent(), test.ShouldEqual, "arm1")
+	test.That(t, spatialmath.PoseAlmostEqual(parts[0].FrameConfig.Pose(), spatialmath.NewPoseFromPoint(r3.Vector{Z: 100})),
+		test.ShouldBeTrue)
+	test.That(t, parts[0].FrameConfig.Geometry(), test.ShouldNotBeNil)
+	test.That(t, manager.Remove(ctx, "arm1", "gripper").Error(), test.ShouldContainSubstring, "unmount it first")
+
+	// a failed switch is reverted.
+	changeErr = errors.New("frame system broken")
+	err = manager.Mount(ctx, "arm1", "welder")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "frame system broken")
+	mounted, err = manager.Mounted(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mounted.Name, test.ShouldEqual, "gripper")
+	test.That(t, arm1.payload, test.ShouldResemble, gripper.Payload)
+	changeErr = nil
+
+	arm1.err = errors.New("controller busy")
+	test.That(t, manager.Mount(ctx, "arm1", "welder").Error(), test.ShouldContainSubstring, "controller busy")
+	arm1.err = nil
+
+	// redeclaring the mounted tool applies it in place.
+	heavier := gripper
+	heavier.Payload.MassKg = 2
+	test.That(t, manager.Declare(ctx, "arm1", heavier), test.ShouldBeNil)
+	test.That(t, arm1.payload.MassKg, test.ShouldEqual, 2)
+
+	test.That(t, manager.Mount(ctx, "arm1", "welder"), test.ShouldBeNil)
+	test.That(t, arm1.payload, test.ShouldResemble, welder.Payload)
+
+	// unmounting clears the payload and removes the frame.
+	test.That(t, manager.Unmount(ctx, "arm1"), test.ShouldBeNil)
+	test.That(t, arm1.payload, test.ShouldResemble, tools.Payload{})
+	mounted, err = manager.Mounted(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mounted, test.ShouldBeNil)
+	parts, err = manager.FrameSystemParts()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, parts, test.ShouldBeEmpty)
+	test.That(t, manager.Remove(ctx, "arm1", "gripper"), test.ShouldBeNil)
+
+	// tools cannot be mounted on arms that do not exist.
+	test.That(t, resource.IsNotFoundError(manager.Mount(ctx, "arm2", "gripper")), test.ShouldBeTrue)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	arm1 := &payloadArm{Arm: inject.NewArm("arm1")}
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{arm.Named("arm1"): arm1})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	toolsClient := robotClient.Tools()
+	test.That(t, toolsClient.Declare(ctx, "arm1", gripper), test.ShouldBeNil)
+	test.That(t, toolsClient.Declare(ctx, "arm1", tools.Tool{}), test.ShouldNotBeNil)
+	declared, err := toolsClient.Tools(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, declared, test.ShouldHaveLength, 1)
+	test.That(t, declared[0].Name, test.ShouldEqual, "gripper")
+	test.That(t, declared[0].Geometry.X, test.ShouldEqual, 50)
+
+	mounted, err := toolsClient.Mounted(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mounted, test.ShouldBeNil)
+	test.That(t, toolsClient.Mount(ctx, "arm1", "gripper"), test.ShouldBeNil)
+	test.That(t, arm1.payload, test.ShouldResemble, gripper.Payload)
+	mounted, err = toolsClient.Mounted(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, mounted.Name, test.ShouldEqual, "gripper")
+
+	test.That(t, toolsClient.Unmount(ctx, "arm1"), test.ShouldBeNil)
+	test.That(t, toolsClient.Remove(ctx, "arm1", "gripper"), test.ShouldBeNil)
+	declared, err = toolsClient.Tools(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, declared, test.ShouldBeEmpty)
+}
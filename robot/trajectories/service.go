@@ -0,0 +1,131 @@
+package trajectories
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a recorder. Its requests and responses
+// are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.trajectories.v1.TrajectoryService"
+
+type request struct {
+	Arm     string         `json:"arm,omitempty"`
+	Name    string         `json:"name,omitempty"`
+	RateHz  float64        `json:"rate_hz,omitempty"`
+	Options *ReplayOptions `json:"options,omitempty"`
+}
+
+type namesResponse struct {
+	Names []string `json:"names"`
+}
+
+// A Server serves a recorder with ServiceDesc.
+type Server struct {
+	recorder Service
+}
+
+// NewServer returns a server for the given recorder.
+func NewServer(recorder Service) *Server {
+	return &Server{recorder: recorder}
+}
+
+func (s *Server) startRecording(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.recorder.StartRecording(ctx, req.Arm, req.Name, req.RateHz)
+}
+
+func (s *Server) stopRecording(ctx context.Context, req request) (interface{}, error) {
+	return s.recorder.StopRecording(ctx, req.Arm)
+}
+
+func (s *Server) getTrajectory(ctx context.Context, req request) (interface{}, error) {
+	return s.recorder.Trajectory(ctx, req.Name)
+}
+
+func (s *Server) listTrajectories(ctx context.Context, _ request) (interface{}, error) {
+	names, err := s.recorder.Names(ctx)
+	return namesResponse{Names: names}, err
+}
+
+func (s *Server) deleteTrajectory(ctx context.Context, req request) (interface{}, error) {
+	return struct{}{}, s.recorder.Delete(ctx, req.Name)
+}
+
+func (s *Server) replayTrajectory(ctx context.Context, req request) (interface{}, error) {
+	var opts ReplayOptions
+	if req.Options != nil {
+		opts = *req.Options
+	}
+	return struct{}{}, s.recorder.Replay(ctx, req.Name, opts)
+}
+
+// ServiceDesc describes the gRPC service serving a recorder. It is served with a Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/trajectories",
+	structrpc.Unary("StartRecording", (*Server).startRecording),
+	structrpc.Unary("StopRecording", (*Server).stopRecording),
+	structrpc.Unary("GetTrajectory", (*Server).getTrajectory),
+	structrpc.Unary("ListTrajectories", (*Server).listTrajectories),
+	structrpc.Unary("DeleteTrajectory", (*Server).deleteTrajectory),
+	structrpc.Unary("ReplayTrajectory", (*Server).replayTrajectory),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the recorder served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// StartRecording starts recording the joint positions of the named arm as a trajectory of
+// the given name, sampled rateHz times a second or DefaultRateHz if zero.
+func (c *Client) StartRecording(ctx context.Context, arm, name string, rateHz float64) error {
+	return c.client.Invoke(ctx, "StartRecording", request{Arm: arm, Name: name, RateHz: rateHz}, nil)
+}
+
+// StopRecording stops recording the arm and keeps the recorded trajectory, replacing the
+// trajectory of the same name.
+func (c *Client) StopRecording(ctx context.Context, arm string) (Trajectory, error) {
+	var trajectory Trajectory
+	if err := c.client.Invoke(ctx, "StopRecording", request{Arm: arm}, &trajectory); err != nil {
+		return Trajectory{}, err
+	}
+	return trajectory, nil
+}
+
+// Trajectory returns the named trajectory.
+func (c *Client) Trajectory(ctx context.Context, name string) (Trajectory, error) {
+	var trajectory Trajectory
+	if err := c.client.Invoke(ctx, "GetTrajectory", request{Name: name}, &trajectory); err != nil {
+		return Trajectory{}, err
+	}
+	return trajectory, nil
+}
+
+// Names returns the names of the trajectories kept, sorted.
+func (c *Client) Names(ctx context.Context) ([]string, error) {
+	var resp namesResponse
+	if err := c.client.Invoke(ctx, "ListTrajectories", request{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Names, nil
+}
+
+// Delete deletes the named trajectory. Deleting a trajectory that does not exist does
+// nothing.
+func (c *Client) Delete(ctx context.Context, name string) error {
+	return c.client.Invoke(ctx, "DeleteTrajectory", request{Name: name}, nil)
+}
+
+// Replay replays the named trajectory on its arm and returns once the arm reached its last
+// point. The arm is first moved to the first point.
+func (c *Client) Replay(ctx context.Context, name string, opts ReplayOptions) error {
+	return c.client.Invoke(ctx, "ReplayTrajectory", request{Name: name, Options: &opts}, nil)
+}
@@ -0,0 +1,406 @@
+// Package trajectories records and replays the joint trajectories of a robot's arms, for
+// teaching an arm a task by demonstration.
+//
+// While an arm is recorded, its joint positions are sampled at a fixed rate as it is
+// hand-guided or jogged. A recorded trajectory is kept by name in the robot's key-value
+// store so that it survives restarts, and can be replayed on its arm either with its
+// recorded timing, sped up or slowed down, or re-timed by having the motion service plan
+// the moves between its keyframes.
+package trajectories
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/services/motion"
+)
+
+const (
+	// Namespace is the namespace of the key-value store holding the trajectories.
+	Namespace = "trajectories"
+	// DefaultRateHz is how many times a second joint positions are sampled if no rate is given.
+	DefaultRateHz = 10.0
+	// MaxRateHz is how many times a second joint positions can be sampled at most.
+	MaxRateHz = 100.0
+	// KeyframeDeg is how far in degrees some joint must move from the previous keyframe of a
+	// re-timed trajectory for a point to be a keyframe.
+	KeyframeDeg = 5.0
+)
+
+// A Point is the joint positions of an arm at a time of a trajectory.
+type Point struct {
+	// Time is how long after the start of the trajectory the arm was at the point.
+	Time time.Duration `json:"time"`
+	// Joints are the joint positions of the arm in degrees.
+	Joints []float64 `json:"joints"`
+}
+
+// A Trajectory is the recorded joint positions of an arm.
+type Trajectory struct {
+	Name string `json:"name"`
+	// Arm is the name of the arm the trajectory was recorded on.
+	Arm        string    `json:"arm"`
+	RecordedAt time.Time `json:"recorded_at"`
+	Points     []Point   `json:"points"`
+}
+
+// Duration returns how long the trajectory lasts with its recorded timing.
+func (t *Trajectory) Duration() time.Duration {
+	if len(t.Points) == 0 {
+		return 0
+	}
+	return t.Points[len(t.Points)-1].Time
+}
+
+// ReplayOptions describe how a trajectory is replayed.
+type ReplayOptions struct {
+	// SpeedScale scales the recorded timing of the trajectory: 2 replays it twice as fast and
+	// 0.5 at half speed. Zero means 1. It does not apply to re-timed trajectories.
+	SpeedScale float64 `json:"speed_scale,omitempty"`
+	// Retime replays the trajectory by moving the arm between its keyframes with the motion
+	// service, which plans and times each move, rather than with its recorded timing.
+	// Keyframes are the points where some joint has moved by KeyframeDeg since the previous
+	// keyframe, and the last point.
+	Retime bool `json:"retime,omitempty"`
+	// MotionService is the name of the motion service re-timing the trajectory. It defaults
+	// to the builtin motion service.
+	MotionService string `json:"motion_service,omitempty"`
+}
+
+// A Service records, keeps and replays the trajectories of a robot's arms, either directly
+// or over a connection to the robot.
+type Service interface {
+	// StartRecording starts recording the joint positions of the named arm as a trajectory of
+	// the given name, sampled rateHz times a second or DefaultRateHz if zero.
+	StartRecording(ctx context.Context, arm, name string, rateHz float64) error
+	// StopRecording stops recording the arm and keeps the recorded trajectory, replacing the
+	// trajectory of the same name.
+	StopRecording(ctx context.Context, arm string) (Trajectory, error)
+	// Trajectory returns the named trajectory.
+	Trajectory(ctx context.Context, name string) (Trajectory, error)
+	// Names returns the names of the trajectories kept, sorted.
+	Names(ctx context.Context) ([]string, error)
+	// Delete deletes the named trajectory. Deleting a trajectory that does not exist does
+	// nothing.
+	Delete(ctx context.Context, name string) error
+	// Replay replays the named trajectory on its arm and returns once the arm reached its
+	// last point. The arm is first moved to the first point.
+	Replay(ctx context.Context, name string, opts ReplayOptions) error
+}
+
+// A Robot is a robot whose arms can be taught trajectories.
+type Robot interface {
+	robot.Robot
+	// Trajectories returns the recorder of the trajectories of the robot's arms.
+	Trajectories() *Recorder
+}
+
+type recording struct {
+	trajectory Trajectory
+	err        error
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// A Recorder is the Service of a robot, recording its arms.
+type Recorder struct {
+	r      robot.Robot
+	store  kv.Service
+	logger logging.Logger
+
+	mu         sync.Mutex
+	recordings map[string]*recording
+	replaying  map[string]bool
+	workers    sync.WaitGroup
+}
+
+var _ Service = (*Recorder)(nil)
+
+// NewRecorder returns a recorder of the arms of the given robot keeping trajectories in the
+// given store.
+func NewRecorder(r robot.Robot, store kv.Service, logger logging.Logger) *Recorder {
+	return &Recorder{
+		r:          r,
+		store:      store,
+		logger:     logger,
+		recordings: map[string]*recording{},
+		replaying:  map[string]bool{},
+	}
+}
+
+// StartRecording starts recording the joint positions of the named arm as a trajectory of
+// the given name, sampled rateHz times a second or DefaultRateHz if zero.
+func (rec *Recorder) StartRecording(ctx context.Context, armName, name string, rateHz float64) error {
+	if name == "" {
+		return status.Error(codes.InvalidArgument, "trajectory name must not be empty")
+	}
+	if rateHz == 0 {
+		rateHz = DefaultRateHz
+	}
+	if rateHz < 0 || rateHz > MaxRateHz {
+		return status.Errorf(codes.InvalidArgument, "rate must be positive and at most %v Hz", MaxRateHz)
+	}
+	a, err := arm.FromRobot(rec.r, armName)
+	if err != nil {
+		return err
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if _, ok := rec.recordings[armName]; ok {
+		return status.Errorf(codes.FailedPrecondition, "arm %q is already being recorded", armName)
+	}
+	if rec.replaying[armName] {
+		return status.Errorf(codes.FailedPrecondition, "arm %q is replaying a trajectory", armName)
+	}
+	recordCtx, cancel := context.WithCancel(context.Background())
+	recording := &recording{
+		trajectory: Trajectory{Name: name, Arm: armName, RecordedAt: clock.Now()},
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	rec.recordings[armName] = recording
+	rec.workers.Add(1)
+	utils.ManagedGo(func() {
+		defer close(recording.done)
+		recording.err = record(recordCtx, a, time.Duration(float64(time.Second)/rateHz), &recording.trajectory)
+	}, rec.workers.Done)
+	return nil
+}
+
+// record samples the joint positions of the arm into the trajectory until the context is
+// done.
+func record(ctx context.Context, a arm.Arm, interval time.Duration, trajectory *Trajectory) error {
+	ticker := clock.Robot().Ticker(interval)
+	defer ticker.Stop()
+	start := clock.Now()
+	for {
+		joints, err := a.JointPositions(ctx, nil)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "cannot read joint positions")
+		}
+		trajectory.Points = append(trajectory.Points, Point{
+			Time:   clock.Since(start),
+			Joints: append([]float64(nil), joints.Values...),
+		})
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// StopRecording stops recording the arm and keeps the recorded trajectory, replacing the
+// trajectory of the same name.
+func (rec *Recorder) StopRecording(ctx context.Context, armName string) (Trajectory, error) {
+	rec.mu.Lock()
+	recording, ok := rec.recordings[armName]
+	delete(rec.recordings, armName)
+	rec.mu.Unlock()
+	if !ok {
+		return Trajectory{}, status.Errorf(codes.FailedPrecondition, "arm %q is not being recorded", armName)
+	}
+	recording.cancel()
+	<-recording.done
+	if recording.err != nil {
+		return Trajectory{}, recording.err
+	}
+	if len(recording.trajectory.Points) == 0 {
+		return Trajectory{}, errors.Errorf("no joint positions of arm %q were recorded", armName)
+	}
+	data, err := json.Marshal(recording.trajectory)
+	if err != nil {
+		return Trajectory{}, err
+	}
+	if err := rec.store.Set(ctx, Namespace, recording.trajectory.Name, data); err != nil {
+		return Trajectory{}, errors.Wrap(err, "failed to save trajectory")
+	}
+	rec.logger.CInfow(ctx, "recorded trajectory", "name", recording.trajectory.Name, "arm", armName,
+		"points", len(recording.trajectory.Points), "duration", recording.trajectory.Duration())
+	return recording.trajectory, nil
+}
+
+// Trajectory returns the named trajectory.
+func (rec *Recorder) Trajectory(ctx context.Context, name string) (Trajectory, error) {
+	data, ok, err := rec.store.Get(ctx, Namespace, name)
+	if err != nil {
+		return Trajectory{}, err
+	}
+	if !ok {
+		return Trajectory{}, status.Errorf(codes.NotFound, "no trajectory named %q", name)
+	}
+	var trajectory Trajectory
+	if err := json.Unmarshal(data, &trajectory); err != nil {
+		return Trajectory{}, errors.Wrapf(err, "unreadable trajectory %q", name)
+	}
+	return trajectory, nil
+}
+
+// Names returns the names of the trajectories kept, sorted.
+func (rec *Recorder) Names(ctx context.Context) ([]string, error) {
+	names, err := rec.store.Keys(ctx, Namespace)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete deletes the named trajectory. Deleting a trajectory that does not exist does
+// nothing.
+func (rec *Recorder) Delete(ctx context.Context, name string) error {
+	return rec.store.Delete(ctx, Namespace, name)
+}
+
+// Replay replays the named trajectory on its arm and returns once the arm reached its last
+// point. The arm is first moved to the first point.
+func (rec *Recorder) Replay(ctx context.Context, name string, opts ReplayOptions) error {
+	if opts.SpeedScale == 0 {
+		opts.SpeedScale = 1
+	}
+	if opts.SpeedScale < 0 {
+		return status.Error(codes.InvalidArgument, "speed scale must be positive")
+	}
+	trajectory, err := rec.Trajectory(ctx, name)
+	if err != nil {
+		return err
+	}
+	a, err := arm.FromRobot(rec.r, trajectory.Arm)
+	if err != nil {
+		return err
+	}
+
+	rec.mu.Lock()
+	if _, ok := rec.recordings[trajectory.Arm]; ok {
+		rec.mu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "arm %q is being recorded", trajectory.Arm)
+	}
+	if rec.replaying[trajectory.Arm] {
+		rec.mu.Unlock()
+		return status.Errorf(codes.FailedPrecondition, "arm %q is already replaying a trajectory", trajectory.Arm)
+	}
+	rec.replaying[trajectory.Arm] = true
+	rec.mu.Unlock()
+	defer func() {
+		rec.mu.Lock()
+		delete(rec.replaying, trajectory.Arm)
+		rec.mu.Unlock()
+	}()
+
+	if opts.Retime {
+		return rec.retime(ctx, a, trajectory, opts.MotionService)
+	}
+	return replay(ctx, a, trajectory, opts.SpeedScale)
+}
+
+// replay moves the arm through the points of the trajectory with their recorded timing
+// scaled by speedScale.
+func replay(ctx context.Context, a arm.Arm, trajectory Trajectory, speedScale float64) error {
+	if len(trajectory.Points) == 0 {
+		return nil
+	}
+	if err := a.MoveToJointPositions(ctx, &pb.JointPositions{Values: trajectory.Points[0].Joints}, nil); err != nil {
+		return err
+	}
+	start := clock.Now()
+	for _, point := range trajectory.Points[1:] {
+		wait := time.Duration(float64(point.Time)/speedScale) - clock.Since(start)
+		if !clock.SleepContext(ctx, wait) {
+			return ctx.Err()
+		}
+		if err := a.MoveToJointPositions(ctx, &pb.JointPositions{Values: point.Joints}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retime moves the arm to the pose of each keyframe of the trajectory with the named motion
+// service.
+func (rec *Recorder) retime(ctx context.Context, a arm.Arm, trajectory Trajectory, motionName string) error {
+	if motionName == "" {
+		motionName = "builtin"
+	}
+	ms, err := motion.FromRobot(rec.r, motionName)
+	if err != nil {
+		return err
+	}
+	model := a.ModelFrame()
+	if model == nil {
+		return errors.Errorf("arm %q has no model to compute the poses of its trajectory", trajectory.Arm)
+	}
+	// the poses of an arm's model are relative to the origin of its frame.
+	originFrame := trajectory.Arm + "_origin"
+	for _, point := range keyframes(trajectory.Points) {
+		pose, err := motionplan.ComputePosition(model, &pb.JointPositions{Values: point.Joints})
+		if err != nil {
+			return err
+		}
+		if _, err := ms.Move(ctx, arm.Named(trajectory.Arm), referenceframe.NewPoseInFrame(originFrame, pose),
+			nil, nil, nil); err != nil {
+			return errors.Wrapf(err, "cannot move to the point at %v", point.Time)
+		}
+	}
+	return nil
+}
+
+// keyframes returns the first point, the points where some joint has moved by KeyframeDeg
+// since the previous keyframe, and the last point.
+func keyframes(points []Point) []Point {
+	if len(points) == 0 {
+		return nil
+	}
+	frames := []Point{points[0]}
+	for i, point := range points[1:] {
+		last := frames[len(frames)-1]
+		if i == len(points)-2 || maxJointDelta(last.Joints, point.Joints) >= KeyframeDeg {
+			frames = append(frames, point)
+		}
+	}
+	return frames
+}
+
+func maxJointDelta(a, b []float64) float64 {
+	if len(a) != len(b) {
+		return math.Inf(1)
+	}
+	var delta float64
+	for i := range a {
+		delta = math.Max(delta, math.Abs(a[i]-b[i]))
+	}
+	return delta
+}
+
+// Close stops every recording, discarding what was recorded.
+func (rec *Recorder) Close(ctx context.Context) error {
+	rec.mu.Lock()
+	for armName, recording := range rec.recordings {
+		recording.cancel()
+		delete(rec.recordings, armName)
+	}
+	rec.mu.Unlock()
+	rec.workers.Wait()
+	return nil
+}
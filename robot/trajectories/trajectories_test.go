@@ -0,0 +1,190 @@
+package trajectories_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	pb "go.viam.com/api/component/arm/v1"
+	motionpb "go.viam.com/api/service/motion/v1"
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/arm/universalrobots"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/trajectories"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+// guidedArm is an arm whose joints move by a degree each time they are read, as if it was
+// hand-guided, and which records the joint positions it is moved to.
+type guidedArm struct {
+	*inject.Arm
+	mu    sync.Mutex
+	reads float64
+	moves [][]float64
+}
+
+func newGuidedArm(t *testing.T) *guidedArm {
+	t.Helper()
+	model, err := universalrobots.MakeModelFrame("arm1")
+	test.That(t, err, test.ShouldBeNil)
+	a := &guidedArm{Arm: inject.NewArm("arm1")}
+	a.JointPositionsFunc = func(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.reads++
+		return &pb.JointPositions{Values: []float64{a.reads, 0, 0, 0, 0, 0}}, nil
+	}
+	a.MoveToJointPositionsFunc = func(ctx context.Context, pos *pb.JointPositions, extra map[string]interface{}) error {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		a.moves = append(a.moves, pos.Values)
+		return nil
+	}
+	a.ModelFrameFunc = func() referenceframe.Model { return model }
+	return a
+}
+
+func (a *guidedArm) moved() [][]float64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([][]float64(nil), a.moves...)
+}
+
+func TestRecorder(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	a := newGuidedArm(t)
+	var targets []*referenceframe.PoseInFrame
+	ms := inject.NewMotionService("builtin")
+	ms.MoveFunc = func(
+		ctx context.Context,
+		componentName resource.Name,
+		destination *referenceframe.PoseInFrame,
+		worldState *referenceframe.WorldState,
+		constraints *motionpb.Constraints,
+		extra map[string]interface{},
+	) (bool, error) {
+		test.That(t, componentName, test.ShouldResemble, arm.Named("arm1"))
+		targets = append(targets, destination)
+		return true, nil
+	}
+	r := inject.NewRobot(logger, map[resource.Name]resource.Resource{
+		arm.Named("arm1"):       a,
+		motion.Named("builtin"): ms,
+	})
+	path := filepath.Join(t.TempDir(), kv.FileName)
+	store := kv.NewStore(path, logger)
+	rec := trajectories.NewRecorder(r, store, logger)
+
+	test.That(t, rec.StartRecording(ctx, "arm1", "wave", trajectories.MaxRateHz), test.ShouldBeNil)
+	err := rec.StartRecording(ctx, "arm1", "wave", 0)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+	err = rec.Replay(ctx, "wave", trajectories.ReplayOptions{})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+	time.Sleep(100 * time.Millisecond)
+	trajectory, err := rec.StopRecording(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, trajectory.Name, test.ShouldEqual, "wave")
+	test.That(t, trajectory.Arm, test.ShouldEqual, "arm1")
+	test.That(t, len(trajectory.Points), test.ShouldBeGreaterThan, 2)
+	for i, point := range trajectory.Points {
+		test.That(t, point.Joints[0], test.ShouldEqual, float64(i+1))
+		if i > 0 {
+			test.That(t, point.Time, test.ShouldBeGreaterThan, trajectory.Points[i-1].Time)
+		}
+	}
+	_, err = rec.StopRecording(ctx, "arm1")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.FailedPrecondition)
+
+	// trajectories are kept in the store.
+	test.That(t, rec.Close(ctx), test.ShouldBeNil)
+	test.That(t, store.Close(), test.ShouldBeNil)
+	store = kv.NewStore(path, logger)
+	defer func() {
+		test.That(t, store.Close(), test.ShouldBeNil)
+	}()
+	rec = trajectories.NewRecorder(r, store, logger)
+	defer func() {
+		test.That(t, rec.Close(ctx), test.ShouldBeNil)
+	}()
+	names, err := rec.Names(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []string{"wave"})
+	kept, err := rec.Trajectory(ctx, "wave")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, kept.Points, test.ShouldResemble, trajectory.Points)
+
+	// replaying at twice the speed visits every point in half the time.
+	start := time.Now()
+	test.That(t, rec.Replay(ctx, "wave", trajectories.ReplayOptions{SpeedScale: 2}), test.ShouldBeNil)
+	test.That(t, time.Since(start), test.ShouldBeGreaterThanOrEqualTo, trajectory.Duration()/2)
+	test.That(t, time.Since(start), test.ShouldBeLessThan, trajectory.Duration())
+	moved := a.moved()
+	test.That(t, moved, test.ShouldHaveLength, len(trajectory.Points))
+	for i, point := range trajectory.Points {
+		test.That(t, moved[i], test.ShouldResemble, point.Joints)
+	}
+
+	// re-timed trajectories move through keyframes at least trajectories.KeyframeDeg apart
+	// with the motion service.
+	test.That(t, rec.Replay(ctx, "wave", trajectories.ReplayOptions{Retime: true}), test.ShouldBeNil)
+	test.That(t, len(targets), test.ShouldBeGreaterThan, 1)
+	test.That(t, len(targets), test.ShouldBeLessThanOrEqualTo, len(trajectory.Points)/int(trajectories.KeyframeDeg)+2)
+	for _, target := range targets {
+		test.That(t, target.Parent(), test.ShouldEqual, "arm1_origin")
+	}
+	test.That(t, a.moved(), test.ShouldHaveLength, len(trajectory.Points))
+
+	test.That(t, rec.Delete(ctx, "wave"), test.ShouldBeNil)
+	names, err = rec.Names(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldBeEmpty)
+}
+
+func TestClient(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	a := newGuidedArm(t)
+	r := inject.NewRobot(logger, map[resource.Name]resource.Resource{
+		arm.Named("arm1"):       a,
+		motion.Named("builtin"): inject.NewMotionService("builtin"),
+	})
+	robotClient, err := client.NewInProcess(ctx, r, logger,
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+		test.That(t, r.Trajectories().Close(ctx), test.ShouldBeNil)
+	}()
+	recorder := robotClient.Trajectories()
+
+	err = recorder.StartRecording(ctx, "arm1", "", 0)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	test.That(t, recorder.StartRecording(ctx, "arm1", "wave", 0), test.ShouldBeNil)
+	trajectory, err := recorder.StopRecording(ctx, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, trajectory.Points, test.ShouldNotBeEmpty)
+
+	got, err := recorder.Trajectory(ctx, "wave")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, got.Points, test.ShouldResemble, trajectory.Points)
+	names, err := recorder.Names(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, names, test.ShouldResemble, []string{"wave"})
+	test.That(t, recorder.Replay(ctx, "wave", trajectories.ReplayOptions{}), test.ShouldBeNil)
+	test.That(t, a.moved(), test.ShouldHaveLength, len(trajectory.Points))
+	test.That(t, recorder.Delete(ctx, "wave"), test.ShouldBeNil)
+	_, err = recorder.Trajectory(ctx, "wave")
+	test.That(t, status.Code(err), test.ShouldEqual, codes.NotFound)
+}
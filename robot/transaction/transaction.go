@@ -0,0 +1,314 @@
+// Package transaction executes batches of commands to several resources of a robot in one
+// request, such as opening a gripper while retracting an arm and backing up a base.
+//
+// Every command of a batch is validated before any is dispatched, so that a batch with an
+// invalid command has no effect. The commands are then dispatched together, with the
+// commands to the same resource run in order, and must all finish within the batch's
+// window. This saves a round trip per command over high-latency links.
+package transaction
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/grpc/structrpc"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
+)
+
+const (
+	// DefaultWindow is how long the commands of a batch have to finish if it does not say.
+	DefaultWindow = 5 * time.Second
+	// MaxWindow is the longest window a batch may have.
+	MaxWindow = time.Minute
+)
+
+// A Command is a call of a unary method of a resource's API.
+type Command struct {
+	// Resource is the short name of the resource.
+	Resource string `json:"resource"`
+	// Method is the name of the method, such as "Open" for a gripper.
+	Method string `json:"method"`
+	// Request holds the fields of the method's request in their JSON form, besides the
+	// name of the resource.
+	Request map[string]interface{} `json:"request,omitempty"`
+}
+
+func (c Command) String() string {
+	return c.Resource + "." + c.Method
+}
+
+// A Batch is a set of commands executed together.
+type Batch struct {
+	Commands []Command
+	// Window is how long the commands have to finish. Commands still running at the end of
+	// the window are canceled. Defaults to DefaultWindow.
+	Window time.Duration
+}
+
+// Result is the outcome of one command of a batch.
+type Result struct {
+	Resource string `json:"resource"`
+	Method   string `json:"method"`
+	// Response holds the fields of the method's response in their JSON form.
+	Response map[string]interface{} `json:"response,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+}
+
+// call is a validated command ready to be dispatched.
+type call struct {
+	name       resource.Name
+	fullMethod string
+	method     *grpc.MethodDesc
+	srv        interface{}
+	request    []byte
+}
+
+// Executor executes batches on a robot.
+type Executor struct {
+	r           robot.Robot
+	interceptor grpc.UnaryServerInterceptor
+}
+
+// NewExecutor returns an executor for the given robot. Each command is served through the
+// given interceptor, if any, as if it had been requested on its own.
+func NewExecutor(r robot.Robot, interceptor grpc.UnaryServerInterceptor) *Executor {
+	return &Executor{r: r, interceptor: interceptor}
+}
+
+// resourceName returns the name of the resource with the given short name.
+func (e *Executor) resourceName(shortName string) (resource.Name, error) {
+	var found []resource.Name
+	for _, name := range e.r.ResourceNames() {
+		if name.ShortName() == shortName {
+			found = append(found, name)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return resource.Name{}, resource.NewNotFoundError(resource.Name{Name: shortName})
+	case 1:
+		return found[0], nil
+	default:
+		return resource.Name{}, errors.Errorf("more than one resource is named %q", shortName)
+	}
+}
+
+// prepare validates a command and returns the call that executes it.
+func (e *Executor) prepare(cmd Command) (*call, error) {
+	name, err := e.resourceName(cmd.Resource)
+	if err != nil {
+		return nil, err
+	}
+	res, err := e.r.ResourceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	reg, ok := resource.LookupGenericAPIRegistration(name.API)
+	if !ok || reg.RPCServiceDesc == nil || reg.ReflectRPCServiceDesc == nil || reg.RPCServiceServerConstructor == nil {
+		return nil, errors.Errorf("API %s cannot be called remotely", name.API)
+	}
+
+	var method *grpc.MethodDesc
+	for idx := range reg.RPCServiceDesc.Methods {
+		if reg.RPCServiceDesc.Methods[idx].MethodName == cmd.Method {
+			method = &reg.RPCServiceDesc.Methods[idx]
+			break
+		}
+	}
+	methodDesc := reg.ReflectRPCServiceDesc.FindMethodByName(cmd.Method)
+	if method == nil || methodDesc == nil {
+		return nil, errors.Errorf("API %s has no unary method %q", name.API, cmd.Method)
+	}
+	request, err := encodeRequest(methodDesc, name, cmd.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	coll := reg.MakeEmptyCollection()
+	if err := coll.ReplaceAll(map[resource.Name]resource.Resource{name: res}); err != nil {
+		return nil, err
+	}
+	srv := reg.RPCServiceServerConstructor(coll)
+	if err, ok := srv.(error); ok {
+		return nil, err
+	}
+	return &call{
+		name:       name,
+		fullMethod: "/" + reg.RPCServiceDesc.ServiceName + "/" + cmd.Method,
+		method:     method,
+		srv:        srv,
+		request:    request,
+	}, nil
+}
+
+// encodeRequest returns the wire encoding of the request of the given method.
+func encodeRequest(methodDesc *desc.MethodDescriptor, name resource.Name, fields map[string]interface{}) ([]byte, error) {
+	msg := dynamic.NewMessage(methodDesc.GetInputType())
+	if len(fields) != 0 {
+		data, err := json.Marshal(fields)
+		if err != nil {
+			return nil, err
+		}
+		if err := msg.UnmarshalJSON(data); err != nil {
+			return nil, errors.Wrap(err, "invalid request")
+		}
+	}
+	if fd := msg.FindFieldDescriptorByName("name"); fd != nil {
+		if err := msg.TrySetField(fd, name.ShortName()); err != nil {
+			return nil, err
+		}
+	}
+	return msg.Marshal()
+}
+
+// Execute validates every command of the batch and, if they are all valid, dispatches them
+// and returns their results in order. If any command is invalid, none is dispatched and an
+// error describing every invalid command is returned.
+func (e *Executor) Execute(ctx context.Context, batch Batch) ([]Result, error) {
+	window := batch.Window
+	if window == 0 {
+		window = DefaultWindow
+	}
+	if window < 0 || window > MaxWindow {
+		return nil, status.Errorf(codes.InvalidArgument, "window must be between 0 and %s", MaxWindow)
+	}
+	if len(batch.Commands) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "batch has no commands")
+	}
+
+	calls := make([]*call, len(batch.Commands))
+	var errs error
+	for idx, cmd := range batch.Commands {
+		c, err := e.prepare(cmd)
+		if err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("command %d (%s): %w", idx, cmd, err))
+			continue
+		}
+		calls[idx] = c
+	}
+	if errs != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid batch: %v", errs)
+	}
+
+	// commands to the same resource run in order.
+	var order []resource.Name
+	byResource := map[resource.Name][]int{}
+	for idx, c := range calls {
+		if _, ok := byResource[c.name]; !ok {
+			order = append(order, c.name)
+		}
+		byResource[c.name] = append(byResource[c.name], idx)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+	results := make([]Result, len(calls))
+	var wg sync.WaitGroup
+	for _, name := range order {
+		indices := byResource[name]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, idx := range indices {
+				results[idx] = e.dispatch(ctx, batch.Commands[idx], calls[idx])
+			}
+		}()
+	}
+	wg.Wait()
+	return results, nil
+}
+
+func (e *Executor) dispatch(ctx context.Context, cmd Command, c *call) Result {
+	result := Result{Resource: cmd.Resource, Method: cmd.Method}
+	if err := ctx.Err(); err != nil {
+		result.Error = status.FromContextError(err).Err().Error()
+		return result
+	}
+	dec := func(in interface{}) error {
+		return proto.Unmarshal(c.request, in.(proto.Message))
+	}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, &transportStream{method: c.fullMethod})
+	resp, err := c.method.Handler(c.srv, ctx, dec, e.interceptor)
+	if err != nil {
+		result.Error = status.Convert(err).Err().Error()
+		return result
+	}
+	data, err := protojson.Marshal(resp.(proto.Message))
+	if err == nil {
+		err = json.Unmarshal(data, &result.Response)
+	}
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to encode response: %v", err)
+	}
+	return result
+}
+
+// transportStream lets handlers set headers on a command, which are dropped.
+type transportStream struct {
+	method string
+}
+
+func (ts *transportStream) Method() string                  { return ts.method }
+func (ts *transportStream) SetHeader(md metadata.MD) error  { return nil }
+func (ts *transportStream) SendHeader(md metadata.MD) error { return nil }
+func (ts *transportStream) SetTrailer(md metadata.MD) error { return nil }
+
+// wireBatch is the JSON form of a batch served over gRPC.
+type wireBatch struct {
+	Commands  []Command `json:"commands"`
+	WindowSec float64   `json:"window_sec,omitempty"`
+}
+
+// ServiceName is the gRPC service name batches are served under.
+const ServiceName = "viam.rdk.transaction.v1.TransactionService"
+
+// ExecuteMethod is the full gRPC method that executes a batch. Its request is a
+// google.protobuf.Struct holding a "commands" list and an optional "window_sec", and its
+// response is a google.protobuf.Struct holding a "results" list.
+const ExecuteMethod = "/" + ServiceName + "/Execute"
+
+// resultsResponse is the JSON form of the results of a batch served over gRPC.
+type resultsResponse struct {
+	Results []Result `json:"results"`
+}
+
+func (e *Executor) execute(ctx context.Context, batch wireBatch) (interface{}, error) {
+	results, err := e.Execute(ctx, Batch{
+		Commands: batch.Commands,
+		Window:   time.Duration(batch.WindowSec * float64(time.Second)),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resultsResponse{Results: results}, nil
+}
+
+// ServiceDesc describes the gRPC service executing batches. It is served with an Executor.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/robot/transaction",
+	structrpc.Unary("Execute", (*Executor).execute),
+)
+
+// Execute executes a batch on the robot served over the given connection.
+func Execute(ctx context.Context, conn grpc.ClientConnInterface, batch Batch) ([]Result, error) {
+	req := wireBatch{Commands: batch.Commands, WindowSec: batch.Window.Seconds()}
+	var resp resultsResponse
+	if err := structrpc.NewClient(conn, ServiceName).Invoke(ctx, "Execute", req, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
@@ -0,0 +1,169 @@
+package transaction_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/components/gripper"
+	"go.viam.com/rdk/components/motor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/transaction"
+	"go.viam.com/rdk/testutils/inject"
+)
+
+type recorder struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (r *recorder) record(call string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, call)
+}
+
+func (r *recorder) recorded() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.calls...)
+}
+
+func setupRobot(t *testing.T) (*inject.Robot, *recorder) {
+	t.Helper()
+	logger := logging.NewTestLogger(t)
+	rec := &recorder{}
+
+	gripper1 := inject.NewGripper("gripper1")
+	gripper1.OpenFunc = func(ctx context.Context, extra map[string]interface{}) error {
+		rec.record("gripper1.Open")
+		return nil
+	}
+	gripper1.GrabFunc = func(ctx context.Context, extra map[string]interface{}) (bool, error) {
+		rec.record("gripper1.Grab")
+		return true, nil
+	}
+	motor1 := inject.NewMotor("motor1")
+	motor1.GoForFunc = func(ctx context.Context, rpm, rotations float64, extra map[string]interface{}) error {
+		if rpm == 0 {
+			// stands for a move that does not finish in time.
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		rec.record("motor1.GoFor")
+		return nil
+	}
+
+	r := &inject.Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(map[resource.Name]resource.Resource{
+		gripper.Named("gripper1"): gripper1,
+		motor.Named("motor1"):     motor1,
+	})
+	return r, rec
+}
+
+func TestExecute(t *testing.T) {
+	ctx := context.Background()
+	r, rec := setupRobot(t)
+	executor := transaction.NewExecutor(r, nil)
+
+	t.Run("all commands are dispatched", func(t *testing.T) {
+		results, err := executor.Execute(ctx, transaction.Batch{Commands: []transaction.Command{
+			{Resource: "gripper1", Method: "Open"},
+			{Resource: "motor1", Method: "GoFor", Request: map[string]interface{}{"rpm": 60, "revolutions": 2}},
+			{Resource: "gripper1", Method: "Grab"},
+		}})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, results, test.ShouldHaveLength, 3)
+		for _, result := range results {
+			test.That(t, result.Error, test.ShouldBeEmpty)
+		}
+		test.That(t, results[2].Response, test.ShouldResemble, map[string]interface{}{"success": true})
+
+		calls := rec.recorded()
+		test.That(t, calls, test.ShouldHaveLength, 3)
+		// commands to the same resource run in order.
+		var gripperCalls []string
+		for _, call := range calls {
+			if call != "motor1.GoFor" {
+				gripperCalls = append(gripperCalls, call)
+			}
+		}
+		test.That(t, gripperCalls, test.ShouldResemble, []string{"gripper1.Open", "gripper1.Grab"})
+	})
+
+	t.Run("no command is dispatched if any is invalid", func(t *testing.T) {
+		before := len(rec.recorded())
+		_, err := executor.Execute(ctx, transaction.Batch{Commands: []transaction.Command{
+			{Resource: "gripper1", Method: "Open"},
+			{Resource: "gripper1", Method: "Fly"},
+			{Resource: "motor2", Method: "GoFor"},
+			{Resource: "motor1", Method: "GoFor", Request: map[string]interface{}{"rpm": "fast"}},
+		}})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "command 1 (gripper1.Fly)")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "command 2 (motor2.GoFor)")
+		test.That(t, err.Error(), test.ShouldContainSubstring, "command 3 (motor1.GoFor)")
+		test.That(t, rec.recorded(), test.ShouldHaveLength, before)
+
+		_, err = executor.Execute(ctx, transaction.Batch{})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+		_, err = executor.Execute(ctx, transaction.Batch{
+			Commands: []transaction.Command{{Resource: "gripper1", Method: "Open"}},
+			Window:   time.Hour,
+		})
+		test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+	})
+
+	t.Run("commands must finish within the window", func(t *testing.T) {
+		results, err := executor.Execute(ctx, transaction.Batch{
+			Commands: []transaction.Command{
+				{Resource: "motor1", Method: "GoFor", Request: map[string]interface{}{"rpm": 0, "revolutions": 2}},
+				{Resource: "motor1", Method: "GoFor", Request: map[string]interface{}{"rpm": 60, "revolutions": 2}},
+				{Resource: "gripper1", Method: "Open"},
+			},
+			Window: 50 * time.Millisecond,
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, results[0].Error, test.ShouldContainSubstring, "deadline exceeded")
+		test.That(t, results[1].Error, test.ShouldContainSubstring, "deadline exceeded")
+		test.That(t, results[2].Error, test.ShouldBeEmpty)
+	})
+}
+
+func TestExecuteOverClient(t *testing.T) {
+	ctx := context.Background()
+	r, rec := setupRobot(t)
+	robotClient, err := client.NewInProcess(ctx, r, logging.NewTestLogger(t),
+		client.WithRefreshEvery(0), client.WithCheckConnectedEvery(0))
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, robotClient.Close(ctx), test.ShouldBeNil)
+	}()
+
+	results, err := robotClient.ExecuteTransaction(ctx, transaction.Batch{Commands: []transaction.Command{
+		{Resource: "gripper1", Method: "Grab"},
+		{Resource: "motor1", Method: "GoFor", Request: map[string]interface{}{"rpm": 60, "revolutions": 2}},
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, results, test.ShouldResemble, []transaction.Result{
+		{Resource: "gripper1", Method: "Grab", Response: map[string]interface{}{"success": true}},
+		{Resource: "motor1", Method: "GoFor"},
+	})
+	test.That(t, rec.recorded(), test.ShouldHaveLength, 2)
+
+	_, err = robotClient.ExecuteTransaction(ctx, transaction.Batch{Commands: []transaction.Command{
+		{Resource: "gripper1", Method: "Fly"},
+	}})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.InvalidArgument)
+}
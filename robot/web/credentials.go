@@ -0,0 +1,176 @@
+package web
+
+import (
+	"context"
+	"crypto/tls"
+	"slices"
+	"sync"
+
+	"github.com/pkg/errors"
+	"go.viam.com/utils/rpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/config"
+	weboptions "go.viam.com/rdk/robot/web/options"
+	rutils "go.viam.com/rdk/utils"
+)
+
+// credentialRotator holds the credentials a running web server accepts and presents so
+// that they can be swapped without restarting the server. The RPC server and TLS
+// listener are handed delegates that always consult the current credentials.
+type credentialRotator struct {
+	mu              sync.RWMutex
+	authEntities    []string
+	handlers        map[rpc.CredentialsType]rpc.AuthHandler
+	tlsAuthEntities []string
+	tlsConfig       *tls.Config
+	bakedAuthCreds  rpc.Credentials
+}
+
+// authHandler returns an auth handler for the given credentials type that delegates to
+// the current handler for that type.
+func (c *credentialRotator) authHandler(credType rpc.CredentialsType) rpc.AuthHandler {
+	return rpc.AuthHandlerFunc(func(ctx context.Context, entity, payload string) (map[string]string, error) {
+		c.mu.RLock()
+		handler, ok := c.handlers[credType]
+		c.mu.RUnlock()
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "invalid credentials")
+		}
+		return handler.Authenticate(ctx, entity, payload)
+	})
+}
+
+// serverTLSConfig records the given TLS config as current and returns a copy whose
+// certificates are always looked up from the current TLS config.
+func (c *credentialRotator) serverTLSConfig(tlsConfig *tls.Config) *tls.Config {
+	c.mu.Lock()
+	c.tlsConfig = tlsConfig
+	c.mu.Unlock()
+	if tlsConfig == nil || tlsConfig.GetCertificate == nil {
+		return tlsConfig
+	}
+
+	current := func() *tls.Config {
+		c.mu.RLock()
+		defer c.mu.RUnlock()
+		return c.tlsConfig
+	}
+	out := tlsConfig.Clone()
+	out.GetCertificate = func(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return current().GetCertificate(hello)
+	}
+	if tlsConfig.GetClientCertificate != nil {
+		out.GetClientCertificate = func(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return current().GetClientCertificate(info)
+		}
+	}
+	return out
+}
+
+// bakedCreds returns the credentials baked into the local UI.
+func (c *credentialRotator) bakedCreds() rpc.Credentials {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.bakedAuthCreds
+}
+
+// rotate swaps in the credentials from the given options. Only secrets may change; if
+// the kinds of credentials accepted change, the web server must be restarted instead.
+func (c *credentialRotator) rotate(options weboptions.Options) error {
+	if err := checkManagedAuth(options); err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	authEntities := c.authEntities
+	currentHandlers := c.handlers
+	currentTLSAuthEntities := c.tlsAuthEntities
+	currentTLSConfig := c.tlsConfig
+	c.mu.RUnlock()
+
+	handlers, err := makeAuthHandlers(authEntities, options.Auth.Handlers)
+	if err != nil {
+		return err
+	}
+	if len(handlers) != len(currentHandlers) {
+		return errors.New("cannot rotate credentials: auth handler types changed")
+	}
+	for credType := range handlers {
+		if _, ok := currentHandlers[credType]; !ok {
+			return errors.Errorf("cannot rotate credentials: new auth handler type %q", credType)
+		}
+	}
+	if !slices.Equal(options.Auth.TLSAuthEntities, currentTLSAuthEntities) {
+		return errors.New("cannot rotate credentials: TLS auth entities changed")
+	}
+
+	newTLSConfig := options.Network.TLSConfig
+	switch {
+	case (newTLSConfig == nil) != (currentTLSConfig == nil):
+		return errors.New("cannot rotate credentials: TLS cannot be enabled or disabled without a restart")
+	case newTLSConfig != nil && (newTLSConfig.GetCertificate == nil || currentTLSConfig.GetCertificate == nil):
+		return errors.New("cannot rotate credentials: TLS certificate is not rotatable")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = handlers
+	c.tlsConfig = newTLSConfig
+	c.bakedAuthCreds = options.BakedAuthCreds
+	return nil
+}
+
+// checkManagedAuth ensures managed robots relying only on the location secret have
+// credentials to bake into the local UI.
+func checkManagedAuth(options weboptions.Options) error {
+	if options.Managed && len(options.Auth.Handlers) == 1 {
+		if options.BakedAuthEntity == "" || options.BakedAuthCreds.Type == "" {
+			return errors.New("expected baked in local UI credentials since managed")
+		}
+	}
+	return nil
+}
+
+// makeAuthHandlers creates an auth handler for each configured credentials type.
+func makeAuthHandlers(
+	authEntities []string,
+	handlerConfigs []config.AuthHandlerConfig,
+) (map[rpc.CredentialsType]rpc.AuthHandler, error) {
+	handlers := map[rpc.CredentialsType]rpc.AuthHandler{}
+	for _, handler := range handlerConfigs {
+		switch handler.Type {
+		case rpc.CredentialsTypeAPIKey:
+			apiKeys := parseAPIKeys(handler)
+			legacyAPIKeys := parseLegacyAPIKeys(handler, apiKeys)
+			hasAPIKeys := len(apiKeys) != 0
+			hasLegacyAPIKeys := len(legacyAPIKeys) != 0
+
+			switch {
+			case !hasLegacyAPIKeys && !hasAPIKeys:
+				return nil, errors.Errorf("%q handler requires non-empty API key or keys", handler.Type)
+			case hasLegacyAPIKeys && !hasAPIKeys:
+				handlers[handler.Type] = rpc.MakeSimpleMultiAuthHandler(authEntities, legacyAPIKeys)
+			case !hasLegacyAPIKeys && hasAPIKeys:
+				handlers[handler.Type] = rpc.MakeSimpleMultiAuthPairHandler(apiKeys)
+			default:
+				handlers[handler.Type] = makeMultiStepAPIKeyAuthHandler(authEntities, legacyAPIKeys, apiKeys)
+			}
+		case rutils.CredentialsTypeRobotLocationSecret:
+			locationSecrets := handler.Config.StringSlice("secrets")
+			if len(locationSecrets) == 0 {
+				secret := handler.Config.String("secret")
+				if secret == "" {
+					return nil, errors.Errorf("%q handler requires non-empty secret", handler.Type)
+				}
+				locationSecrets = []string{secret}
+			}
+			handlers[handler.Type] = rpc.MakeSimpleMultiAuthHandler(authEntities, locationSecrets)
+		case rpc.CredentialsTypeExternal:
+		default:
+			return nil, errors.Errorf("do not know how to handle auth for %q", handler.Type)
+		}
+	}
+	return handlers, nil
+}
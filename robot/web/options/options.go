@@ -69,6 +69,18 @@ type Options struct {
 	WebRTCOnPeerRemoved func(pc *webrtc.PeerConnection)
 
 	DisableMulticastDNS bool
+
+	// Metrics serves the metrics of the requests to resources at /metrics. They are not
+	// authenticated, so they are only served when asked for.
+	Metrics bool
+
+	// MapTiles serves the map tiles cached by the robot at /tiles. They are not authenticated,
+	// so they are only served when asked for.
+	MapTiles bool
+
+	// RecordTrace is a file that resource API requests and responses are recorded to
+	// for replay. See package grpc/recording.
+	RecordTrace string
 }
 
 // New returns a default set of options which will have the
@@ -13,6 +13,7 @@ import (
 	"go.viam.com/utils"
 	"go.viam.com/utils/rpc"
 
+	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/gostream"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/robot"
@@ -35,6 +36,8 @@ type Server struct {
 	streamNames             []string
 	nameToStreamState       map[string]*state.StreamState
 	activePeerStreams       map[*webrtc.PeerConnection]map[string]*peerState
+	videoUnsubscribers      map[*func()]struct{}
+	unregisterVideoSources  []func()
 	activeBackgroundWorkers sync.WaitGroup
 	isAlive                 bool
 }
@@ -47,11 +50,12 @@ func NewServer(
 	logger logging.Logger,
 ) (*Server, error) {
 	ss := &Server{
-		r:                 r,
-		logger:            logger,
-		nameToStreamState: map[string]*state.StreamState{},
-		activePeerStreams: map[*webrtc.PeerConnection]map[string]*peerState{},
-		isAlive:           true,
+		r:                  r,
+		logger:             logger,
+		nameToStreamState:  map[string]*state.StreamState{},
+		activePeerStreams:  map[*webrtc.PeerConnection]map[string]*peerState{},
+		videoUnsubscribers: map[*func()]struct{}{},
+		isAlive:            true,
 	}
 
 	for _, stream := range streams {
@@ -285,6 +289,12 @@ func (ss *Server) Close() error {
 	ss.mu.Lock()
 	ss.isAlive = false
 
+	for _, unregister := range ss.unregisterVideoSources {
+		unregister()
+	}
+	for unsubscribe := range ss.videoUnsubscribers {
+		(*unsubscribe)()
+	}
 	var errs error
 	for _, name := range ss.streamNames {
 		errs = multierr.Combine(errs, ss.nameToStreamState[name].Close())
@@ -307,5 +317,67 @@ func (ss *Server) add(stream gostream.Stream) error {
 	newStreamState.Init()
 	ss.nameToStreamState[streamName] = newStreamState
 	ss.streamNames = append(ss.streamNames, streamName)
+	if _, ok := stream.VideoTrackLocal(); ok {
+		ss.unregisterVideoSources = append(ss.unregisterVideoSources,
+			camera.RegisterEncodedVideoSource(streamName, encodedVideoSource{ss: ss, name: streamName}))
+	}
 	return nil
 }
+
+// SubscribeEncodedVideo returns the queue of the video frames encoded for the named stream
+// from now on, until unsubscribe is called or the server is closed, which close the queue.
+// The subscription counts as a peer of the stream, so that the stream runs while it lasts.
+// Frames the subscriber has no room for are handled with the policy of the queue.
+func (ss *Server) SubscribeEncodedVideo(
+	ctx context.Context, name string, queue gostream.QueueOptions,
+) (*gostream.Queue[gostream.EncodedVideoFrame], func(), error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if !ss.isAlive {
+		return nil, nil, errors.New("stream server is closed")
+	}
+	if err := queue.Validate(); err != nil {
+		return nil, nil, err
+	}
+	streamState, ok := ss.nameToStreamState[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("no stream for %q", name)
+	}
+	subscriber, ok := streamState.Stream.(gostream.EncodedVideoSubscriber)
+	if !ok {
+		return nil, nil, fmt.Errorf("stream %q does not provide its encoded video", name)
+	}
+	if err := streamState.Increment(ctx); err != nil {
+		return nil, nil, err
+	}
+	frames, unsubscribeStream := subscriber.SubscribeEncodedVideo(queue)
+	var once sync.Once
+	var unsubscribe func()
+	unsubscribe = func() {
+		once.Do(func() {
+			unsubscribeStream()
+			delete(ss.videoUnsubscribers, &unsubscribe)
+			if ss.isAlive {
+				utils.UncheckedError(streamState.Decrement(context.Background()))
+			}
+		})
+	}
+	ss.videoUnsubscribers[&unsubscribe] = struct{}{}
+	return frames, func() {
+		ss.mu.Lock()
+		defer ss.mu.Unlock()
+		unsubscribe()
+	}, nil
+}
+
+// encodedVideoSource provides the encoded video of a camera from its stream.
+type encodedVideoSource struct {
+	ss   *Server
+	name string
+}
+
+func (s encodedVideoSource) SubscribeEncodedVideo(
+	ctx context.Context, queue gostream.QueueOptions,
+) (*gostream.Queue[gostream.EncodedVideoFrame], func(), error) {
+	return s.ss.SubscribeEncodedVideo(ctx, s.name, queue)
+}
@@ -24,7 +24,6 @@ import (
 	"github.com/jhump/protoreflect/dynamic"
 	"github.com/pkg/errors"
 	"github.com/rs/cors"
-	"go.opencensus.io/trace"
 	pb "go.viam.com/api/robot/v1"
 	"go.viam.com/utils"
 	echopb "go.viam.com/utils/proto/rpc/examples/echo/v1"
@@ -36,11 +35,23 @@ import (
 
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/grpc"
+	"go.viam.com/rdk/grpc/recording"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/module"
+	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/client"
+	"go.viam.com/rdk/robot/coordination"
+	"go.viam.com/rdk/robot/events"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/maptiles"
+	"go.viam.com/rdk/robot/metadata"
+	"go.viam.com/rdk/robot/metrics"
+	"go.viam.com/rdk/robot/mission"
 	grpcserver "go.viam.com/rdk/robot/server"
+	"go.viam.com/rdk/robot/trajectories"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	rutils "go.viam.com/rdk/utils"
 	"go.viam.com/rdk/web"
@@ -62,6 +73,7 @@ type robotWebApp struct {
 	theRobot robot.Robot
 	logger   logging.Logger
 	options  weboptions.Options
+	creds    *credentialRotator
 }
 
 // Init does template initialization work.
@@ -139,7 +151,7 @@ func (app *robotWebApp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if app.options.Managed && hasManagedAuthHandlers(app.options.Auth.Handlers) {
 		data.BakedAuth = map[string]interface{}{
 			"authEntity": app.options.BakedAuthEntity,
-			"creds":      app.options.BakedAuthCreds,
+			"creds":      app.creds.bakedCreds(),
 		}
 	} else {
 		for _, handler := range app.options.Auth.Handlers {
@@ -193,6 +205,10 @@ type Service interface {
 
 	// Returns the unix socket path the module server listens on.
 	ModuleAddress() string
+
+	// RotateCredentials swaps the credentials of the running web server for those in the
+	// given options without restarting it.
+	RotateCredentials(context.Context, weboptions.Options) error
 }
 
 var internalWebServiceName = resource.NewName(
@@ -227,6 +243,24 @@ func (svc *webService) Start(ctx context.Context, o weboptions.Options) error {
 	return nil
 }
 
+// RotateCredentials swaps the auth handler secrets, TLS certificate, and baked in UI
+// credentials of the running web server for those in the given options. New connections
+// must use the new credentials while already authenticated connections keep working until
+// they close or their tokens expire. An error is returned if anything other than
+// credentials changed, in which case the web server must be restarted instead.
+func (svc *webService) RotateCredentials(ctx context.Context, o weboptions.Options) error {
+	svc.mu.Lock()
+	defer svc.mu.Unlock()
+	if !svc.isRunning {
+		return errors.New("web server not started")
+	}
+	if err := svc.creds.rotate(o); err != nil {
+		return err
+	}
+	svc.logger.CInfo(ctx, "rotated web server credentials")
+	return nil
+}
+
 // RunWeb starts the web server on the robot with web options and blocks until we cancel the context.
 func RunWeb(ctx context.Context, r robot.LocalRobot, o weboptions.Options, logger logging.Logger) (err error) {
 	defer func() {
@@ -328,6 +362,48 @@ func (svc *webService) StartModule(ctx context.Context) error {
 	if err := svc.modServer.RegisterServiceServer(ctx, &pb.RobotService_ServiceDesc, grpcserver.New(svc.r)); err != nil {
 		return err
 	}
+	if storingRobot, ok := svc.r.(kv.Robot); ok {
+		if err := svc.modServer.RegisterServiceServer(ctx, &kv.ServiceDesc, kv.NewServer(storingRobot.KV())); err != nil {
+			return err
+		}
+	}
+	if publishingRobot, ok := svc.r.(events.Robot); ok {
+		if err := svc.modServer.RegisterServiceServer(ctx, &events.ServiceDesc, events.NewServer(publishingRobot.Events())); err != nil {
+			return err
+		}
+	}
+	if coordinatedRobot, ok := svc.r.(coordination.Robot); ok {
+		if err := svc.modServer.RegisterServiceServer(
+			ctx,
+			&coordination.ServiceDesc,
+			coordination.NewServer(coordinatedRobot.Coordination()),
+		); err != nil {
+			return err
+		}
+	}
+	if missionRobot, ok := svc.r.(mission.Robot); ok {
+		if err := svc.modServer.RegisterServiceServer(
+			ctx,
+			&mission.ServiceDesc,
+			mission.NewServer(missionRobot.Missions()),
+		); err != nil {
+			return err
+		}
+	}
+	if labeledRobot, ok := svc.r.(metadata.Robot); ok {
+		if err := svc.modServer.RegisterServiceServer(ctx, &metadata.ServiceDesc, metadata.NewServer(labeledRobot)); err != nil {
+			return err
+		}
+	}
+	if trajectoryRobot, ok := svc.r.(trajectories.Robot); ok {
+		if err := svc.modServer.RegisterServiceServer(
+			ctx,
+			&trajectories.ServiceDesc,
+			trajectories.NewServer(trajectoryRobot.Trajectories()),
+		); err != nil {
+			return err
+		}
+	}
 	if err := svc.refreshResources(); err != nil {
 		return err
 	}
@@ -440,7 +516,7 @@ func (svc *webService) Close(ctx context.Context) error {
 
 // installWeb prepares the given mux to be able to serve the UI for the robot.
 func (svc *webService) installWeb(mux *goji.Mux, theRobot robot.Robot, options weboptions.Options) error {
-	app := &robotWebApp{theRobot: theRobot, logger: svc.logger, options: options}
+	app := &robotWebApp{theRobot: theRobot, logger: svc.logger, options: options, creds: &svc.creds}
 	if err := app.Init(); err != nil {
 		return err
 	}
@@ -490,6 +566,7 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 	}
 
 	options.Secure = options.Network.TLSConfig != nil || options.Network.TLSCertFile != ""
+	options.Network.TLSConfig = svc.creds.serverTLSConfig(options.Network.TLSConfig)
 	if options.SignalingAddress == "" && !options.Secure {
 		options.SignalingDialOpts = append(options.SignalingDialOpts, rpc.WithInsecure())
 	}
@@ -502,7 +579,20 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		}
 	}
 
-	rpcOpts, err := svc.initRPCOptions(listenerTCPAddr, options)
+	var recorder *recording.Recorder
+	if options.RecordTrace != "" {
+		recorder, err = recording.OpenTrace(options.RecordTrace, svc.logger.Sublogger("recording"))
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				utils.UncheckedError(recorder.Close())
+			}
+		}()
+	}
+
+	rpcOpts, ints, err := svc.initRPCOptions(listenerTCPAddr, options, recorder)
 	if err != nil {
 		return err
 	}
@@ -525,6 +615,16 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 		return err
 	}
 
+	// calls served in chunks are invoked with the robot's services in process.
+	chunkingConn := client.NewInProcessConn(svc.r, ints, svc.logger)
+	context.AfterFunc(ctx, func() { utils.UncheckedError(chunkingConn.Close()) })
+	if err := grpcserver.RegisterServices(svc.r, svc.logger, ints, chunkingConn,
+		func(desc *googlegrpc.ServiceDesc, srv interface{}) error {
+			return svc.rpcServer.RegisterServiceServer(ctx, desc, srv)
+		}); err != nil {
+		return err
+	}
+
 	if err := svc.refreshResources(); err != nil {
 		return err
 	}
@@ -567,6 +667,11 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 			if err := svc.rpcServer.Stop(); err != nil {
 				svc.logger.Errorw("error stopping rpc server", "error", err)
 			}
+			if recorder != nil {
+				if err := recorder.Close(); err != nil {
+					svc.logger.Errorw("error closing trace recording", "error", err)
+				}
+			}
 		}()
 		svc.closeStreamServer()
 	})
@@ -614,7 +719,11 @@ func (svc *webService) runWeb(ctx context.Context, options weboptions.Options) (
 }
 
 // Initialize RPC Server options.
-func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options weboptions.Options) ([]rpc.ServerOption, error) {
+func (svc *webService) initRPCOptions(
+	listenerTCPAddr *net.TCPAddr,
+	options weboptions.Options,
+	recorder *recording.Recorder,
+) ([]rpc.ServerOption, grpcserver.Interceptors, error) {
 	hosts := options.GetHosts(listenerTCPAddr)
 	rpcOpts := []rpc.ServerOption{
 		rpc.WithAuthIssuer(options.FQDN),
@@ -636,23 +745,13 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 		rpcOpts = append(rpcOpts, rpc.WithDisableMulticastDNS())
 	}
 
-	var unaryInterceptors []googlegrpc.UnaryServerInterceptor
-
-	unaryInterceptors = append(unaryInterceptors, grpc.EnsureTimeoutUnaryServerInterceptor)
+	if _, ok := svc.r.(robot.LocalRobot); ok {
+		// orchestration systems probe health without credentials.
+		rpcOpts = append(rpcOpts, rpc.WithAllowUnauthenticatedHealthCheck())
+	}
 
 	if options.Debug {
 		rpcOpts = append(rpcOpts, rpc.WithDebug())
-		unaryInterceptors = append(unaryInterceptors, func(
-			ctx context.Context,
-			req interface{},
-			info *googlegrpc.UnaryServerInfo,
-			handler googlegrpc.UnaryHandler,
-		) (interface{}, error) {
-			ctx, span := trace.StartSpan(ctx, fmt.Sprintf("%v", req))
-			defer span.End()
-
-			return handler(ctx, req)
-		})
 	}
 
 	if options.Network.TLSConfig != nil {
@@ -661,56 +760,40 @@ func (svc *webService) initRPCOptions(listenerTCPAddr *net.TCPAddr, options webo
 
 	authOpts, err := svc.initAuthHandlers(listenerTCPAddr, options)
 	if err != nil {
-		return nil, err
+		return nil, grpcserver.Interceptors{}, err
 	}
 	rpcOpts = append(rpcOpts, authOpts...)
 
-	var streamInterceptors []googlegrpc.StreamServerInterceptor
-
-	opManager := svc.r.OperationManager()
-	sessManagerInts := svc.r.SessionManager().ServerInterceptors()
-	if sessManagerInts.UnaryServerInterceptor != nil {
-		unaryInterceptors = append(unaryInterceptors, sessManagerInts.UnaryServerInterceptor)
-	}
-	unaryInterceptors = append(unaryInterceptors,
-		opManager.UnaryServerInterceptor, logging.UnaryServerInterceptor)
-
-	if sessManagerInts.StreamServerInterceptor != nil {
-		streamInterceptors = append(streamInterceptors, sessManagerInts.StreamServerInterceptor)
-	}
-	streamInterceptors = append(streamInterceptors, opManager.StreamServerInterceptor)
-
 	rpcOpts = append(
 		rpcOpts,
 		rpc.WithUnknownServiceHandler(svc.foreignServiceHandler),
 	)
 
-	unaryInterceptor := grpc_middleware.ChainUnaryServer(unaryInterceptors...)
-	streamInterceptor := grpc_middleware.ChainStreamServer(streamInterceptors...)
+	ints := grpcserver.NewInterceptors(svc.r, grpcserver.InterceptorOptions{Debug: options.Debug, Recorder: recorder})
 	rpcOpts = append(rpcOpts,
-		rpc.WithUnaryServerInterceptor(unaryInterceptor),
-		rpc.WithStreamServerInterceptor(streamInterceptor),
+		rpc.WithUnaryServerInterceptor(ints.Unary),
+		rpc.WithStreamServerInterceptor(ints.Stream),
 	)
 
-	return rpcOpts, nil
+	return rpcOpts, ints, nil
 }
 
 // Initialize authentication handler options.
 func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options weboptions.Options) ([]rpc.ServerOption, error) {
 	rpcOpts := []rpc.ServerOption{}
 
-	if options.Managed && len(options.Auth.Handlers) == 1 {
-		if options.BakedAuthEntity == "" || options.BakedAuthCreds.Type == "" {
-			return nil, errors.New("expected baked in local UI credentials since managed")
-		}
+	if err := checkManagedAuth(options); err != nil {
+		return nil, err
 	}
 
+	var authEntities []string
+	var handlers map[rpc.CredentialsType]rpc.AuthHandler
 	if len(options.Auth.Handlers) == 0 {
 		rpcOpts = append(rpcOpts, rpc.WithUnauthenticated())
 	} else {
 		listenerAddr := listenerTCPAddr.String()
 		hosts := options.GetHosts(listenerTCPAddr)
-		authEntities := make([]string, len(hosts.Internal))
+		authEntities = make([]string, len(hosts.Internal))
 		copy(authEntities, hosts.Internal)
 		if !options.Managed {
 			// allow authentication for non-unique entities.
@@ -734,48 +817,27 @@ func (svc *webService) initAuthHandlers(listenerTCPAddr *net.TCPAddr, options we
 		if options.Secure && len(options.Auth.TLSAuthEntities) != 0 {
 			rpcOpts = append(rpcOpts, rpc.WithTLSAuthHandler(options.Auth.TLSAuthEntities))
 		}
-		for _, handler := range options.Auth.Handlers {
-			switch handler.Type {
-			case rpc.CredentialsTypeAPIKey:
-				apiKeys := parseAPIKeys(handler)
-				legacyAPIKeys := parseLegacyAPIKeys(handler, apiKeys)
-				hasAPIKeys := len(apiKeys) != 0
-				hasLegacyAPIKeys := len(legacyAPIKeys) != 0
-
-				switch {
-				case !hasLegacyAPIKeys && !hasAPIKeys:
-					return nil, errors.Errorf("%q handler requires non-empty API key or keys", handler.Type)
-				case hasLegacyAPIKeys && !hasAPIKeys:
-					rpcOpts = append(rpcOpts, rpc.WithAuthHandler(
-						handler.Type,
-						rpc.MakeSimpleMultiAuthHandler(authEntities, legacyAPIKeys),
-					))
-				case !hasLegacyAPIKeys && hasAPIKeys:
-					rpcOpts = append(rpcOpts, rpc.WithAuthHandler(handler.Type, rpc.MakeSimpleMultiAuthPairHandler(apiKeys)))
-				default:
-					rpcOpts = append(rpcOpts, rpc.WithAuthHandler(handler.Type, makeMultiStepAPIKeyAuthHandler(authEntities, legacyAPIKeys, apiKeys)))
-				}
-			case rutils.CredentialsTypeRobotLocationSecret:
-				locationSecrets := handler.Config.StringSlice("secrets")
-				if len(locationSecrets) == 0 {
-					secret := handler.Config.String("secret")
-					if secret == "" {
-						return nil, errors.Errorf("%q handler requires non-empty secret", handler.Type)
-					}
-					locationSecrets = []string{secret}
-				}
 
-				rpcOpts = append(rpcOpts, rpc.WithAuthHandler(
-					handler.Type,
-					rpc.MakeSimpleMultiAuthHandler(authEntities, locationSecrets),
-				))
-			case rpc.CredentialsTypeExternal:
-			default:
-				return nil, errors.Errorf("do not know how to handle auth for %q", handler.Type)
+		var err error
+		handlers, err = makeAuthHandlers(authEntities, options.Auth.Handlers)
+		if err != nil {
+			return nil, err
+		}
+		// handlers are registered as delegates so that their secrets can be rotated.
+		for _, handler := range options.Auth.Handlers {
+			if _, ok := handlers[handler.Type]; ok {
+				rpcOpts = append(rpcOpts, rpc.WithAuthHandler(handler.Type, svc.creds.authHandler(handler.Type)))
 			}
 		}
 	}
 
+	svc.creds.mu.Lock()
+	svc.creds.authEntities = authEntities
+	svc.creds.handlers = handlers
+	svc.creds.tlsAuthEntities = options.Auth.TLSAuthEntities
+	svc.creds.bakedAuthCreds = options.BakedAuthCreds
+	svc.creds.mu.Unlock()
+
 	if options.Auth.ExternalAuthConfig != nil {
 		rpcOpts = append(rpcOpts, rpc.WithExternalAuthJWKSetTokenVerifier(
 			options.Auth.ExternalAuthConfig.ValidatedKeySet,
@@ -876,6 +938,13 @@ func (svc *webService) initHTTPServer(listenerTCPAddr *net.TCPAddr, options webo
 	return httpServer, nil
 }
 
+// A cachedFrameSystemRobot is a robot that can return its frame system without reading any of
+// its components.
+type cachedFrameSystemRobot interface {
+	robot.Robot
+	CachedFrameSystem(ctx context.Context) (referenceframe.FrameSystem, error)
+}
+
 // Initialize multiplexer between http handlers.
 func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 	mux := goji.NewMux()
@@ -896,6 +965,17 @@ func (svc *webService) initMux(options weboptions.Options) (*goji.Mux, error) {
 	// TODO: accept params to display different formats
 	mux.HandleFunc(pat.New("/debug/graph"), svc.handleVisualizeResourceGraph)
 
+	if measuredRobot, ok := svc.r.(metrics.Robot); ok && options.Metrics {
+		mux.Handle(pat.Get("/metrics"), measuredRobot.Metrics())
+	}
+	if fsRobot, ok := svc.r.(cachedFrameSystemRobot); ok && options.Debug {
+		// serve the kinematic tree of the frame system, to debug frame configs.
+		mux.Handle(pat.Get("/debug/framesystem"), framesystem.TreeHandler(fsRobot.CachedFrameSystem))
+	}
+	if tiledRobot, ok := svc.r.(maptiles.Robot); ok && options.MapTiles {
+		mux.Handle(pat.Get("/tiles/*"), http.StripPrefix("/tiles", tiledRobot.MapTiles()))
+	}
+
 	prefix := "/viam"
 	addPrefix := func(h http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
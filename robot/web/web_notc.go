@@ -47,6 +47,7 @@ type webService struct {
 	isRunning  bool
 	webWorkers sync.WaitGroup
 	modWorkers sync.WaitGroup
+	creds      credentialRotator
 }
 
 // Update updates the web service when the robot has changed.
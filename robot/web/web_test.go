@@ -7,7 +7,9 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"os"
 	"testing"
 	"time"
@@ -43,6 +45,9 @@ import (
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/maptiles"
+	"go.viam.com/rdk/robot/metrics"
+	"go.viam.com/rdk/robot/transaction"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	"go.viam.com/rdk/spatialmath"
@@ -735,6 +740,87 @@ func TestWebWithOnlyNewAPIKeyAuthHandlers(t *testing.T) {
 	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
 }
 
+func TestWebRotateCredentials(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	err := svc.RotateCredentials(ctx, options)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not started")
+
+	apiKeyID := uuid.New().String()
+	oldAPIKey := utils.RandomAlphaString(32)
+	newAPIKey := utils.RandomAlphaString(32)
+	apiKeyHandler := func(apiKey string) []config.AuthHandlerConfig {
+		return []config.AuthHandlerConfig{
+			{
+				Type: rpc.CredentialsTypeAPIKey,
+				Config: rutils.AttributeMap{
+					apiKeyID: apiKey,
+					"keys":   []string{apiKeyID},
+				},
+			},
+		}
+	}
+	dial := func(apiKey string, opts ...rpc.DialOption) (rpc.ClientConn, error) {
+		return rgrpc.Dial(context.Background(), addr, logger, append(opts,
+			rpc.WithAllowInsecureWithCredentialsDowngrade(),
+			rpc.WithEntityCredentials(apiKeyID, rpc.Credentials{
+				Type:    rpc.CredentialsTypeAPIKey,
+				Payload: apiKey,
+			}),
+		)...)
+	}
+
+	options.Auth.Handlers = apiKeyHandler(oldAPIKey)
+	err = svc.Start(ctx, options)
+	test.That(t, err, test.ShouldBeNil)
+
+	oldConn, err := dial(oldAPIKey, rpc.WithForceDirectGRPC())
+	test.That(t, err, test.ShouldBeNil)
+	oldArm, err := arm.NewClientFromConn(context.Background(), oldConn, "", arm.Named(arm1String), logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	options.Auth.Handlers = apiKeyHandler(newAPIKey)
+	test.That(t, svc.RotateCredentials(ctx, options), test.ShouldBeNil)
+
+	// new connections must use the new key
+	_, err = dial(oldAPIKey)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "invalid credentials")
+
+	newConn, err := dial(newAPIKey, rpc.WithForceDirectGRPC())
+	test.That(t, err, test.ShouldBeNil)
+	newArm, err := arm.NewClientFromConn(context.Background(), newConn, "", arm.Named(arm1String), logger)
+	test.That(t, err, test.ShouldBeNil)
+	arm1Position, err := newArm.EndPosition(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, arm1Position, test.ShouldResemble, pos)
+
+	// already authenticated connections keep working
+	arm1Position, err = oldArm.EndPosition(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, arm1Position, test.ShouldResemble, pos)
+
+	// changing the kinds of credentials accepted requires a restart
+	options.Auth.Handlers = append(options.Auth.Handlers, config.AuthHandlerConfig{
+		Type:   rutils.CredentialsTypeRobotLocationSecret,
+		Config: rutils.AttributeMap{"secret": "abc"},
+	})
+	err = svc.RotateCredentials(ctx, options)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "auth handler types changed")
+
+	test.That(t, oldArm.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, oldConn.Close(), test.ShouldBeNil)
+	test.That(t, newArm.Close(context.Background()), test.ShouldBeNil)
+	test.That(t, newConn.Close(), test.ShouldBeNil)
+	test.That(t, svc.Close(context.Background()), test.ShouldBeNil)
+}
+
 func TestWebReconfigure(t *testing.T) {
 	logger := logging.NewTestLogger(t)
 	ctx, robot := setupRobotCtx(t)
@@ -1409,3 +1495,121 @@ func signJWKBasedExternalAccessToken(
 
 	return token.SignedString(key)
 }
+
+func TestWebTransaction(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	conn, err := rgrpc.Dial(context.Background(), addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	results, err := transaction.Execute(ctx, conn, transaction.Batch{Commands: []transaction.Command{
+		{Resource: arm1String, Method: "GetEndPosition"},
+		{Resource: arm1String, Method: "GetEndPosition"},
+	}})
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, results, test.ShouldHaveLength, 2)
+	for _, result := range results {
+		test.That(t, result.Error, test.ShouldBeEmpty)
+		test.That(t, result.Response["pose"], test.ShouldNotBeNil)
+	}
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
+
+func TestWebMetrics(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	svc := web.New(injectRobot, logger)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	// metrics are only served when asked for.
+	resp, err := http.Get("http://" + addr + "/metrics")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldNotEqual, http.StatusOK)
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+
+	svc = web.New(injectRobot, logger)
+	options, _, addr = robottestutils.CreateBaseOptionsAndListener(t)
+	options.Metrics = true
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	conn, err := rgrpc.Dial(context.Background(), addr, logger)
+	test.That(t, err, test.ShouldBeNil)
+	arm1, err := arm.NewClientFromConn(context.Background(), conn, "", arm.Named(arm1String), logger)
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 2; i++ {
+		_, err = arm1.EndPosition(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+	}
+
+	resp, err = http.Get("http://" + addr + "/metrics")
+	test.That(t, err, test.ShouldBeNil)
+	body, err := io.ReadAll(resp.Body)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	test.That(t, resp.Header.Get("Content-Type"), test.ShouldEqual, metrics.ContentType)
+	test.That(t, string(body), test.ShouldContainSubstring,
+		`rdk_resource_operations_total{resource="arm1",method="GetEndPosition",code="OK"} 2`)
+	test.That(t, string(body), test.ShouldContainSubstring,
+		`rdk_resource_operation_duration_seconds_count{resource="arm1",method="GetEndPosition"} 2`)
+
+	test.That(t, conn.Close(), test.ShouldBeNil)
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
+
+// tiledRobot is a robot caching map tiles.
+type tiledRobot struct {
+	robot.Robot
+	tiles *maptiles.Cache
+}
+
+func (r tiledRobot) MapTiles() *maptiles.Cache {
+	return r.tiles
+}
+
+func TestWebMapTiles(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	ctx, injectRobot := setupRobotCtx(t)
+
+	tiles := maptiles.NewCache(logger, t.TempDir(), nil)
+	defer tiles.Close()
+	svc := web.New(tiledRobot{Robot: injectRobot, tiles: tiles}, logger)
+	options, _, addr := robottestutils.CreateBaseOptionsAndListener(t)
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	// map tiles are only served when asked for.
+	resp, err := http.Get("http://" + addr + "/tiles/")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldNotEqual, http.StatusOK)
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+
+	svc = web.New(tiledRobot{Robot: injectRobot, tiles: tiles}, logger)
+	options, _, addr = robottestutils.CreateBaseOptionsAndListener(t)
+	options.MapTiles = true
+	test.That(t, svc.Start(ctx, options), test.ShouldBeNil)
+
+	resp, err = http.Get("http://" + addr + "/tiles/")
+	test.That(t, err, test.ShouldBeNil)
+	body, err := io.ReadAll(resp.Body)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusOK)
+	test.That(t, string(body), test.ShouldEqual, "[]\n")
+
+	resp, err = http.Get("http://" + addr + "/tiles/field/0/0/0.png")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, resp.Body.Close(), test.ShouldBeNil)
+	test.That(t, resp.StatusCode, test.ShouldEqual, http.StatusNotFound)
+
+	test.That(t, svc.Close(ctx), test.ShouldBeNil)
+}
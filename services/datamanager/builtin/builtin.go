@@ -18,6 +18,7 @@ import (
 	goutils "go.viam.com/utils"
 	"go.viam.com/utils/rpc"
 
+	rclock "go.viam.com/rdk/clock"
 	"go.viam.com/rdk/components/sensor"
 	"go.viam.com/rdk/data"
 	"go.viam.com/rdk/internal/cloud"
@@ -67,8 +68,8 @@ var filesystemPollInterval = 30 * time.Second
 var minNumFiles = 5000
 
 var (
-	clock          = clk.New()
-	deletionTicker = clk.New()
+	clock          = rclock.Robot()
+	deletionTicker = rclock.Robot()
 )
 
 var errCaptureDirectoryConfigurationDisabled = errors.New("changing the capture directory is prohibited in this environment")
@@ -31,6 +31,8 @@ const (
 	GetImages      = "GetImages"
 	nextPointCloud = "NextPointCloud"
 	pointCloudMap  = "PointCloudMap"
+	// videoSegments captures segments of the encoded video of a camera's stream.
+	videoSegments = "VideoSegments"
 	// Non-exhaustive list of characters to strip from file paths, since not allowed
 	// on certain file systems.
 	filePathReservedChars = ":"
@@ -245,7 +247,7 @@ func getFileTimestampName() string {
 // TODO DATA-246: Implement this in some more robust, programmatic way.
 func getDataType(methodName string) v1.DataType {
 	switch methodName {
-	case nextPointCloud, readImage, pointCloudMap, GetImages:
+	case nextPointCloud, readImage, pointCloudMap, GetImages, videoSegments:
 		return v1.DataType_DATA_TYPE_BINARY_SENSOR
 	default:
 		return v1.DataType_DATA_TYPE_TABULAR_SENSOR
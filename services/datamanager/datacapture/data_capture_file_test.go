@@ -63,6 +63,16 @@ func TestBuildCaptureMetadata(t *testing.T) {
 			fileExtension:    ".pcd",
 			tags:             []string{},
 		},
+		{
+			name:             "Metadata for camera video segments stored as binary files",
+			componentType:    "camera",
+			componentName:    "cam1",
+			method:           videoSegments,
+			additionalParams: make(map[string]string),
+			dataType:         v1.DataType_DATA_TYPE_BINARY_SENSOR,
+			fileExtension:    "",
+			tags:             []string{},
+		},
 	}
 
 	for _, tc := range tests {
@@ -74,10 +74,18 @@ var ErrNotImplemented = errors.New("function coming soon but not yet implemented
 // Config describes how to configure the service; currently only used for specifying dependency on framesystem service.
 type Config struct {
 	LogFilePath string `json:"log_file_path"`
+	// ExecutionMonitor, if set, aborts planned motions of components reported to deviate from
+	// the paths commanded of them.
+	ExecutionMonitor *ExecutionMonitorConfig `json:"execution_monitor,omitempty"`
 }
 
 // Validate here adds a dependency on the internal framesystem service.
 func (c *Config) Validate(path string) ([]string, error) {
+	if c.ExecutionMonitor != nil {
+		if err := c.ExecutionMonitor.Validate(path); err != nil {
+			return nil, err
+		}
+	}
 	return []string{framesystem.InternalServiceName.String()}, nil
 }
 
@@ -116,6 +124,7 @@ func (ms *builtIn) Reconfigure(
 		}
 		ms.logger = logger
 	}
+	ms.executionMonitor = config.ExecutionMonitor
 	movementSensors := make(map[resource.Name]movementsensor.MovementSensor)
 	slamServices := make(map[resource.Name]slam.Service)
 	visionServices := make(map[resource.Name]vision.Service)
@@ -160,6 +169,9 @@ type builtIn struct {
 	components      map[resource.Name]resource.Resource
 	logger          logging.Logger
 	state           *state.State
+	// executionMonitor is the configured monitoring of planned motions, or nil if they are not
+	// monitored.
+	executionMonitor *ExecutionMonitorConfig
 }
 
 func (ms *builtIn) Close(ctx context.Context) error {
@@ -189,6 +201,12 @@ func (ms *builtIn) Move(
 	goalFrameName := destination.Parent()
 	ms.logger.CDebugf(ctx, "goal given in frame of %q", goalFrameName)
 
+	// obstacles stored for the robot are avoided on top of those of the request.
+	worldState, err := framesystem.WithStoredObstacles(ctx, ms.fsService, worldState)
+	if err != nil {
+		return false, err
+	}
+
 	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
 	if err != nil {
 		return false, err
@@ -231,13 +249,15 @@ func (ms *builtIn) Move(
 	}
 
 	// move all the components
+	monitor := ms.newExecutionMonitor(frameSys, resources)
+	commanded := fsInputs
 	for _, step := range plan.Trajectory() {
 		for name, inputs := range step {
 			if len(inputs) == 0 {
 				continue
 			}
 			r := resources[name]
-			if err := r.GoToInputs(ctx, inputs); err != nil {
+			if err := monitor.goToInputs(ctx, commanded, name, inputs); err != nil {
 				// If there is an error on GoToInputs, stop the component if possible before returning the error
 				if actuator, ok := r.(inputEnabledActuator); ok {
 					if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
@@ -247,10 +267,33 @@ func (ms *builtIn) Move(
 				return false, err
 			}
 		}
+		commanded = withInputs(commanded, step)
 	}
 	return true, nil
 }
 
+// newExecutionMonitor returns the monitor of moves of the resources of the frame system, as
+// configured.
+func (ms *builtIn) newExecutionMonitor(
+	frameSys referenceframe.FrameSystem,
+	resources map[string]referenceframe.InputEnabled,
+) *executionMonitor {
+	return &executionMonitor{conf: ms.executionMonitor, frameSys: frameSys, resources: resources, logger: ms.logger}
+}
+
+// withInputs returns the frame system inputs with the inputs of the step in place of those of
+// its frames.
+func withInputs(fsInputs, step map[string][]referenceframe.Input) map[string][]referenceframe.Input {
+	next := make(map[string][]referenceframe.Input, len(fsInputs))
+	for frame, inputs := range fsInputs {
+		next[frame] = inputs
+	}
+	for frame, inputs := range step {
+		next[frame] = inputs
+	}
+	return next
+}
+
 func (ms *builtIn) MoveOnMap(ctx context.Context, req motion.MoveOnMapReq) (motion.ExecutionID, error) {
 	if err := ctx.Err(); err != nil {
 		return uuid.Nil, err
@@ -0,0 +1,308 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	servicepb "go.viam.com/api/service/motion/v1"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+	rdkutils "go.viam.com/rdk/utils"
+)
+
+const (
+	defaultJogLinearMmPerSec = 50.
+	// jogPeriod is the longest step a component is moved in at once along a relative move, so
+	// that its speed is kept between the waypoints of its plan.
+	jogPeriod = 50 * time.Millisecond
+	// jogGuardHorizon is how far ahead, at the speed of a relative move, the path of the
+	// component is checked for collisions before each of its waypoints.
+	jogGuardHorizon = 500 * time.Millisecond
+)
+
+var _ motion.Jogger = (*builtIn)(nil)
+
+// MoveRelative moves the end frame of a component by a delta from where it is, along a straight
+// line, at the speeds of the request. Unless the request allows contact, the move ends short
+// of any collision with the stored obstacles or the robot itself, including with obstacles
+// stored while the component moves. If it allows contact, the component may touch obstacles
+// but is still kept from colliding with the robot itself.
+func (ms *builtIn) MoveRelative(ctx context.Context, req motion.MoveRelativeReq) error {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+
+	if req.Delta == nil {
+		return errors.New("a delta to move by is required")
+	}
+	if req.LinearMmPerSec < 0 || req.AngularDegsPerSec < 0 {
+		return errors.New("speeds of a relative move cannot be negative")
+	}
+	linear, angular := req.LinearMmPerSec, req.AngularDegsPerSec
+	if linear == 0 {
+		linear = defaultJogLinearMmPerSec
+	}
+	if angular == 0 {
+		angular = defaultAngularDegsPerSec
+	}
+
+	operation.CancelOtherWithLabel(ctx, builtinOpLabel)
+
+	worldState, err := framesystem.WithStoredObstacles(ctx, ms.fsService, nil)
+	if err != nil {
+		return err
+	}
+	frameSys, err := ms.fsService.FrameSystem(ctx, worldState.Transforms())
+	if err != nil {
+		return err
+	}
+	fsInputs, resources, err := ms.fsService.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	name := req.ComponentName.ShortName()
+	movingFrame := frameSys.Frame(name)
+	if movingFrame == nil {
+		return errors.Errorf("component named %s not found in robot frame system", name)
+	}
+
+	refName := req.ReferenceFrame
+	switch refName {
+	case "", motion.ToolFrame:
+		refName = name
+	case motion.BaseFrame:
+		parent, err := frameSys.Parent(movingFrame)
+		if err != nil {
+			return err
+		}
+		refName = parent.Name()
+	default:
+		if frameSys.Frame(refName) == nil {
+			return referenceframe.NewFrameMissingError(refName)
+		}
+	}
+
+	// the delta is applied along the axes of the reference frame, about the end frame.
+	tf, err := frameSys.Transform(fsInputs, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), refName)
+	if err != nil {
+		return err
+	}
+	current := tf.(*referenceframe.PoseInFrame).Pose()
+	goal := spatialmath.NewPose(
+		current.Point().Add(req.Delta.Point()),
+		spatialmath.Compose(
+			spatialmath.NewPoseFromOrientation(req.Delta.Orientation()),
+			spatialmath.NewPoseFromOrientation(current.Orientation()),
+		).Orientation(),
+	)
+	tf, err = frameSys.Transform(fsInputs, referenceframe.NewPoseInFrame(refName, goal), referenceframe.World)
+	if err != nil {
+		return err
+	}
+
+	constraints := &servicepb.Constraints{LinearConstraint: []*servicepb.LinearConstraint{{}}}
+	if req.AllowContact {
+		// the component may touch obstacles, but is still kept from colliding with the robot.
+		if constraints.CollisionSpecification, err = contactSpecifications(movingFrame, fsInputs, worldState); err != nil {
+			return err
+		}
+	}
+	plan, err := motionplan.PlanMotion(ctx, &motionplan.PlanRequest{
+		Logger:             ms.logger,
+		Goal:               tf.(*referenceframe.PoseInFrame),
+		Frame:              movingFrame,
+		StartConfiguration: fsInputs,
+		FrameSystem:        frameSys,
+		WorldState:         worldState,
+		ConstraintSpecs:    constraints,
+		Options:            req.Extra,
+	})
+	if err != nil {
+		return err
+	}
+	monitor := ms.newExecutionMonitor(frameSys, resources)
+	var guard *jogGuard
+	if !req.AllowContact {
+		guard = &jogGuard{
+			frame:    movingFrame,
+			frameSys: frameSys,
+			plan:     plan,
+			obstacles: func(ctx context.Context) (*referenceframe.WorldState, error) {
+				return framesystem.WithStoredObstacles(ctx, ms.fsService, nil)
+			},
+			lookAheadMM: linear * jogGuardHorizon.Seconds(),
+			logger:      ms.logger,
+		}
+	}
+	return paceTrajectory(ctx, monitor, guard, name, fsInputs, plan.Trajectory(), linear, angular)
+}
+
+// contactSpecifications returns the collision specifications allowing the frame to touch each
+// obstacle of the world state, which are none if the frame has no geometries to touch them with.
+func contactSpecifications(
+	frame referenceframe.Frame,
+	fsInputs map[string][]referenceframe.Input,
+	worldState *referenceframe.WorldState,
+) ([]*servicepb.CollisionSpecification, error) {
+	inputs, err := referenceframe.GetFrameInputs(frame, fsInputs)
+	if err != nil {
+		return nil, err
+	}
+	geometries, err := frame.Geometries(inputs)
+	if err != nil {
+		return nil, err
+	}
+	obstacles := worldState.ObstacleNames()
+	if len(geometries.Geometries()) == 0 || len(obstacles) == 0 {
+		return nil, nil
+	}
+	var allows []*servicepb.CollisionSpecification_AllowedFrameCollisions
+	for obstacle := range obstacles {
+		allows = append(allows, &servicepb.CollisionSpecification_AllowedFrameCollisions{Frame1: frame.Name(), Frame2: obstacle})
+	}
+	return []*servicepb.CollisionSpecification{{Allows: allows}}, nil
+}
+
+// A jogGuard checks the path of a component moved relatively against the obstacles around it
+// and the robot itself, a short horizon ahead, so that it is stopped before contact.
+type jogGuard struct {
+	frame    referenceframe.Frame
+	frameSys referenceframe.FrameSystem
+	plan     motionplan.Plan
+	// obstacles returns the obstacles to check against, which may change as the component moves.
+	obstacles   func(ctx context.Context) (*referenceframe.WorldState, error)
+	lookAheadMM float64
+	logger      logging.Logger
+}
+
+// clear returns whether the component, at the given inputs and moving on from the given
+// waypoint of the plan, would not collide within the horizon of the guard.
+func (g *jogGuard) clear(ctx context.Context, waypoint int, inputs map[string][]referenceframe.Input) (bool, error) {
+	stored, err := g.obstacles(ctx)
+	if err != nil {
+		return false, err
+	}
+	// obstacles relative to transforms stored since the frame system was built cannot be placed.
+	var obstacles []*referenceframe.GeometriesInFrame
+	for _, gif := range stored.Obstacles() {
+		if g.frameSys.Frame(gif.Parent()) != nil {
+			obstacles = append(obstacles, gif)
+		}
+	}
+	worldState, err := referenceframe.NewWorldState(obstacles, nil)
+	if err != nil {
+		return false, err
+	}
+
+	tf, err := g.frameSys.Transform(inputs, referenceframe.NewPoseInFrame(g.frame.Name(), spatialmath.NewZeroPose()), referenceframe.World)
+	if err != nil {
+		return false, err
+	}
+	state, err := motionplan.NewExecutionState(g.plan, waypoint, inputs,
+		map[string]*referenceframe.PoseInFrame{g.frame.Name(): tf.(*referenceframe.PoseInFrame)})
+	if err != nil {
+		return false, err
+	}
+	if err := motionplan.CheckPlan(g.frame, state, worldState, g.frameSys, g.lookAheadMM, g.logger); err != nil {
+		g.logger.CWarnw(ctx, "stopped short of a collision", "component", g.frame.Name(), "error", err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// paceTrajectory moves the components along the trajectory with the monitor, from the start
+// inputs, in steps taking at least as long as the speeds of the named frame allow. If a guard
+// is given, the path ahead is checked with it before moving to each waypoint, and the move
+// ends where it is, without an error, once the path ahead is not clear.
+func paceTrajectory(
+	ctx context.Context,
+	monitor *executionMonitor,
+	guard *jogGuard,
+	name string,
+	start map[string][]referenceframe.Input,
+	traj motionplan.Trajectory,
+	linearMmPerSec, angularDegsPerSec float64,
+) error {
+	poseAt := func(inputs map[string][]referenceframe.Input) (spatialmath.Pose, error) {
+		tf, err := monitor.frameSys.Transform(inputs, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), referenceframe.World)
+		if err != nil {
+			return nil, err
+		}
+		return tf.(*referenceframe.PoseInFrame).Pose(), nil
+	}
+
+	prev := start
+	prevPose, err := poseAt(prev)
+	if err != nil {
+		return err
+	}
+	for i, step := range traj {
+		if guard != nil {
+			clear, err := guard.clear(ctx, max(i-1, 0), prev)
+			if err != nil {
+				return err
+			}
+			if !clear {
+				// the move is clamped at the last step its path ahead is clear from.
+				return nil
+			}
+		}
+		next := withInputs(prev, step)
+		nextPose, err := poseAt(next)
+		if err != nil {
+			return err
+		}
+		delta := spatialmath.PoseDelta(prevPose, nextPose)
+		secs := math.Max(
+			delta.Point().Norm()/linearMmPerSec,
+			rdkutils.RadToDeg(spatialmath.QuatToR4AA(delta.Orientation().Quaternion()).Theta)/angularDegsPerSec,
+		)
+		duration := time.Duration(secs * float64(time.Second))
+		substeps := max(int(math.Ceil(float64(duration)/float64(jogPeriod))), 1)
+
+		commanded := prev
+		for i := 1; i <= substeps; i++ {
+			began := clock.Now()
+			substep := make(map[string][]referenceframe.Input, len(step))
+			for frame, inputs := range step {
+				if len(inputs) == 0 {
+					continue
+				}
+				from := prev[frame]
+				to := make([]referenceframe.Input, len(inputs))
+				for j := range inputs {
+					to[j] = inputs[j]
+					if j < len(from) {
+						to[j].Value = from[j].Value + (inputs[j].Value-from[j].Value)*float64(i)/float64(substeps)
+					}
+				}
+				substep[frame] = to
+				r := monitor.resources[frame]
+				if err := monitor.goToInputs(ctx, commanded, frame, to); err != nil {
+					// stop the component if possible before returning the error.
+					if actuator, ok := r.(inputEnabledActuator); ok {
+						if stopErr := actuator.Stop(ctx, nil); stopErr != nil {
+							return errors.Wrap(err, stopErr.Error())
+						}
+					}
+					return err
+				}
+			}
+			commanded = withInputs(commanded, substep)
+			wait := duration/time.Duration(substeps) - clock.Since(began)
+			if wait > 0 && !clock.SleepContext(ctx, wait) {
+				return ctx.Err()
+			}
+		}
+		prev, prevPose = next, nextPose
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package builtin
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/services/motion"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// inputRecorder records the inputs it is sent to.
+type inputRecorder struct {
+	mu     sync.Mutex
+	inputs [][]referenceframe.Input
+}
+
+func (r *inputRecorder) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.inputs) == 0 {
+		return []referenceframe.Input{{}}, nil
+	}
+	return r.inputs[len(r.inputs)-1], nil
+}
+
+func (r *inputRecorder) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.inputs = append(r.inputs, inputSteps...)
+	return nil
+}
+
+func TestMoveRelativeFailures(t *testing.T) {
+	ms, teardown := setupMotionServiceFromConfig(t, "../data/arm_gantry.json")
+	defer teardown()
+	ctx := context.Background()
+	jogger, ok := ms.(motion.Jogger)
+	test.That(t, ok, test.ShouldBeTrue)
+	delta := spatialmath.NewPoseFromPoint(r3.Vector{Z: 10})
+
+	err := jogger.MoveRelative(ctx, motion.MoveRelativeReq{ComponentName: arm.Named("arm1")})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "delta to move by is required")
+
+	err = jogger.MoveRelative(ctx, motion.MoveRelativeReq{ComponentName: camera.Named("fake"), Delta: delta})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "not found in robot frame system")
+
+	err = jogger.MoveRelative(ctx, motion.MoveRelativeReq{ComponentName: arm.Named("arm1"), Delta: delta, LinearMmPerSec: -1})
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "cannot be negative")
+
+	err = jogger.MoveRelative(ctx, motion.MoveRelativeReq{ComponentName: arm.Named("arm1"), Delta: delta, ReferenceFrame: "nowhere"})
+	test.That(t, err, test.ShouldBeError, referenceframe.NewFrameMissingError("nowhere"))
+}
+
+func TestPaceTrajectory(t *testing.T) {
+	ctx := context.Background()
+	slider, err := referenceframe.NewTranslationalFrame("slider", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+
+	recorder := &inputRecorder{}
+	start := map[string][]referenceframe.Input{"slider": {{Value: 0}}}
+	traj := motionplan.Trajectory{
+		{"slider": {{Value: 0}}},
+		{"slider": {{Value: 100}}},
+	}
+	monitor := &executionMonitor{
+		frameSys:  fs,
+		resources: map[string]referenceframe.InputEnabled{"slider": recorder},
+		logger:    logging.NewTestLogger(t),
+	}
+
+	// 100mm at 1000mm/s takes 100ms, moved in two steps of 50ms.
+	began := time.Now()
+	test.That(t, paceTrajectory(ctx, monitor, nil, "slider", start, traj, 1000, 20), test.ShouldBeNil)
+	test.That(t, time.Since(began), test.ShouldBeGreaterThanOrEqualTo, 90*time.Millisecond)
+	test.That(t, recorder.inputs, test.ShouldResemble, [][]referenceframe.Input{{{Value: 0}}, {{Value: 50}}, {{Value: 100}}})
+
+	// a canceled move stops between steps.
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = paceTrajectory(cancelCtx, monitor, nil, "slider", start, traj, 10, 20)
+	test.That(t, err, test.ShouldBeError, context.Canceled)
+}
+
+func TestJogGuard(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "slider")
+	test.That(t, err, test.ShouldBeNil)
+	slider, err := referenceframe.NewTranslationalFrameWithGeometry(
+		"slider", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000}, box)
+	test.That(t, err, test.ShouldBeNil)
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+
+	start := map[string][]referenceframe.Input{"slider": {{Value: 0}}}
+	var traj motionplan.Trajectory
+	var path motionplan.Path
+	for x := 0.; x <= 100; x += 20 {
+		traj = append(traj, map[string][]referenceframe.Input{"slider": {{Value: x}}})
+		path = append(path, motionplan.PathStep{
+			"slider": referenceframe.NewPoseInFrame(referenceframe.World, spatialmath.NewPoseFromPoint(r3.Vector{X: x})),
+		})
+	}
+
+	// an obstacle from 75mm to 85mm along the slider.
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 80}), r3.Vector{X: 10, Y: 10, Z: 10}, "obstacle")
+	test.That(t, err, test.ShouldBeNil)
+	guard := &jogGuard{
+		frame:    slider,
+		frameSys: fs,
+		plan:     motionplan.NewSimplePlan(path, traj),
+		obstacles: func(ctx context.Context) (*referenceframe.WorldState, error) {
+			return referenceframe.NewWorldState(
+				[]*referenceframe.GeometriesInFrame{
+					referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle}),
+				}, nil)
+		},
+		lookAheadMM: 25,
+		logger:      logger,
+	}
+	newMonitor := func(recorder *inputRecorder) *executionMonitor {
+		return &executionMonitor{
+			frameSys:  fs,
+			resources: map[string]referenceframe.InputEnabled{"slider": recorder},
+			logger:    logger,
+		}
+	}
+
+	// the move is clamped at the last waypoint from which the slider would not reach the
+	// obstacle within the horizon of the guard.
+	recorder := &inputRecorder{}
+	test.That(t, paceTrajectory(ctx, newMonitor(recorder), guard, "slider", start, traj, 10000, 1000), test.ShouldBeNil)
+	last, err := recorder.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, last[0].Value, test.ShouldEqual, 60)
+
+	// without a guard, the slider moves into the obstacle.
+	recorder = &inputRecorder{}
+	test.That(t, paceTrajectory(ctx, newMonitor(recorder), nil, "slider", start, traj, 10000, 1000), test.ShouldBeNil)
+	last, err = recorder.CurrentInputs(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, last[0].Value, test.ShouldEqual, 100)
+}
+
+func TestContactSpecifications(t *testing.T) {
+	box, err := spatialmath.NewBox(spatialmath.NewZeroPose(), r3.Vector{X: 10, Y: 10, Z: 10}, "slider")
+	test.That(t, err, test.ShouldBeNil)
+	slider, err := referenceframe.NewTranslationalFrameWithGeometry(
+		"slider", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000}, box)
+	test.That(t, err, test.ShouldBeNil)
+	inputs := map[string][]referenceframe.Input{"slider": {{Value: 0}}}
+	obstacle, err := spatialmath.NewBox(spatialmath.NewPoseFromPoint(r3.Vector{X: 80}), r3.Vector{X: 10, Y: 10, Z: 10}, "button")
+	test.That(t, err, test.ShouldBeNil)
+	worldState, err := referenceframe.NewWorldState([]*referenceframe.GeometriesInFrame{
+		referenceframe.NewGeometriesInFrame(referenceframe.World, []spatialmath.Geometry{obstacle}),
+	}, nil)
+	test.That(t, err, test.ShouldBeNil)
+
+	// the slider may touch the obstacle, while the rest of the robot is still checked.
+	specs, err := contactSpecifications(slider, inputs, worldState)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, specs, test.ShouldHaveLength, 1)
+	test.That(t, specs[0].Allows, test.ShouldHaveLength, 1)
+	test.That(t, specs[0].Allows[0].Frame1, test.ShouldEqual, "slider")
+	test.That(t, specs[0].Allows[0].Frame2, test.ShouldEqual, "button")
+
+	// a frame without geometries touches nothing, so there is nothing to allow.
+	bare, err := referenceframe.NewTranslationalFrame("bare", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	specs, err = contactSpecifications(bare, map[string][]referenceframe.Input{"bare": {{Value: 0}}}, worldState)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, specs, test.ShouldBeEmpty)
+}
@@ -0,0 +1,179 @@
+package builtin
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	goutils "go.viam.com/utils"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	defaultMonitorPollingHz = 10.
+	// monitorPathChecks is how many segments the commanded path of a frame is split into to
+	// measure how far from it the frame is reported.
+	monitorPathChecks = 10
+)
+
+// ExecutionMonitorConfig configures the aborting of planned motions whose components deviate
+// from the paths commanded of them, as when an arm is blocked or slips. Thresholds left at zero
+// are not checked.
+type ExecutionMonitorConfig struct {
+	// MaxJointDeviation is how far, in degrees, or millimeters for prismatic joints, a joint may
+	// be reported outside the range it is commanded to move through.
+	MaxJointDeviation float64 `json:"max_joint_deviation,omitempty"`
+	// MaxPositionDeviationMm is how far the frame of a component may be reported from the path
+	// commanded of it.
+	MaxPositionDeviationMm float64 `json:"max_position_deviation_mm,omitempty"`
+	// PollingHz is how often the positions of moving components are checked. It defaults to 10.
+	PollingHz float64 `json:"polling_hz,omitempty"`
+}
+
+// Validate returns an error if the execution monitor cannot be run as configured.
+func (c *ExecutionMonitorConfig) Validate(path string) error {
+	if c.MaxJointDeviation < 0 || c.MaxPositionDeviationMm < 0 || c.PollingHz < 0 {
+		return errors.Errorf("%s: execution monitor thresholds and polling rate cannot be negative", path)
+	}
+	if c.MaxJointDeviation == 0 && c.MaxPositionDeviationMm == 0 {
+		return errors.Errorf("%s: execution monitor needs a max_joint_deviation or max_position_deviation_mm", path)
+	}
+	return nil
+}
+
+func (c *ExecutionMonitorConfig) pollingPeriod() time.Duration {
+	hz := c.PollingHz
+	if hz == 0 {
+		hz = defaultMonitorPollingHz
+	}
+	return time.Duration(float64(time.Second) / hz)
+}
+
+// executionMonitor moves the components of a plan, aborting the moves of those reported to
+// deviate from the paths commanded of them.
+type executionMonitor struct {
+	// conf is nil if moves are not monitored.
+	conf      *ExecutionMonitorConfig
+	frameSys  referenceframe.FrameSystem
+	resources map[string]referenceframe.InputEnabled
+	logger    logging.Logger
+}
+
+// goToInputs moves the component of the named frame to the inputs, from those it has among the
+// frame system inputs. If it is reported to deviate from the straight path between them while
+// it moves, or to not have arrived once it has, its move is canceled, the operation of the
+// context is failed, and the deviation is returned for the component to be stopped.
+func (m *executionMonitor) goToInputs(
+	ctx context.Context,
+	fsInputs map[string][]referenceframe.Input,
+	name string,
+	to []referenceframe.Input,
+) error {
+	r := m.resources[name]
+	if m.conf == nil {
+		return r.GoToInputs(ctx, to)
+	}
+
+	moveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var deviation error
+	polled := make(chan struct{})
+	goutils.PanicCapturingGo(func() {
+		defer close(polled)
+		for clock.SleepContext(moveCtx, m.conf.pollingPeriod()) {
+			if deviation = m.check(moveCtx, fsInputs, name, to); deviation != nil {
+				cancel()
+				return
+			}
+		}
+	})
+	err := r.GoToInputs(moveCtx, to)
+	cancel()
+	<-polled
+	if deviation == nil && err == nil {
+		// a component that finished its move is where it was moved to.
+		deviation = m.check(ctx, withInputs(fsInputs, map[string][]referenceframe.Input{name: to}), name, to)
+	}
+	if deviation == nil {
+		return err
+	}
+	m.logger.CWarnw(ctx, "aborting motion", "component", name, "error", deviation)
+	operation.Fail(ctx, deviation)
+	return deviation
+}
+
+// check returns an error if the component of the named frame is reported beyond the thresholds
+// of the monitor from the path between its frame system inputs and the inputs it is moving to.
+// A component whose inputs cannot be read is not taken to deviate.
+func (m *executionMonitor) check(
+	ctx context.Context,
+	fsInputs map[string][]referenceframe.Input,
+	name string,
+	to []referenceframe.Input,
+) error {
+	reported, err := m.resources[name].CurrentInputs(ctx)
+	if err != nil {
+		m.logger.CDebugw(ctx, "cannot read inputs to monitor", "component", name, "error", err)
+		return nil
+	}
+	frame := m.frameSys.Frame(name)
+	from := fsInputs[name]
+	if frame == nil || len(reported) != len(to) || len(from) != len(to) {
+		return nil
+	}
+
+	if limit := m.conf.MaxJointDeviation; limit > 0 {
+		reportedValues := frame.ProtobufFromInput(reported).Values
+		fromValues := frame.ProtobufFromInput(from).Values
+		toValues := frame.ProtobufFromInput(to).Values
+		for j, value := range reportedValues {
+			lo, hi := math.Min(fromValues[j], toValues[j]), math.Max(fromValues[j], toValues[j])
+			if dev := math.Max(lo-value, value-hi); dev > limit {
+				return errors.Errorf("%s joint %d deviated %.2f from its commanded path, more than the %.2f allowed", name, j, dev, limit)
+			}
+		}
+	}
+
+	if limit := m.conf.MaxPositionDeviationMm; limit > 0 {
+		pointAt := func(inputs []referenceframe.Input) (r3.Vector, error) {
+			all := withInputs(fsInputs, map[string][]referenceframe.Input{name: inputs})
+			tf, err := m.frameSys.Transform(all, referenceframe.NewPoseInFrame(name, spatialmath.NewZeroPose()), referenceframe.World)
+			if err != nil {
+				return r3.Vector{}, err
+			}
+			return tf.(*referenceframe.PoseInFrame).Pose().Point(), nil
+		}
+		at, err := pointAt(reported)
+		if err != nil {
+			return nil
+		}
+		prev, err := pointAt(from)
+		if err != nil {
+			return nil
+		}
+		dist := math.Inf(1)
+		for i := 1; i <= monitorPathChecks; i++ {
+			inputs, err := frame.Interpolate(from, to, float64(i)/monitorPathChecks)
+			if err != nil {
+				return nil
+			}
+			next, err := pointAt(inputs)
+			if err != nil {
+				return nil
+			}
+			dist = math.Min(dist, spatialmath.DistToLineSegment(prev, next, at))
+			prev = next
+		}
+		if dist > limit {
+			return errors.Errorf("%s deviated %.1fmm from its commanded path, more than the %.1fmm allowed", name, dist, limit)
+		}
+	}
+	return nil
+}
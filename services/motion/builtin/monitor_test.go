@@ -0,0 +1,97 @@
+package builtin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+)
+
+// driftingSlider reports itself at a position of its own rather than where it is moved to.
+type driftingSlider struct {
+	at float64
+	// stuck is whether moves block until they are canceled.
+	stuck    bool
+	canceled bool
+}
+
+func (s *driftingSlider) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	return []referenceframe.Input{{Value: s.at}}, nil
+}
+
+func (s *driftingSlider) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	if !s.stuck {
+		return nil
+	}
+	<-ctx.Done()
+	s.canceled = true
+	return ctx.Err()
+}
+
+func TestExecutionMonitor(t *testing.T) {
+	logger := logging.NewTestLogger(t)
+	slider, err := referenceframe.NewTranslationalFrame("slider", r3.Vector{X: 1}, referenceframe.Limit{Min: 0, Max: 1000})
+	test.That(t, err, test.ShouldBeNil)
+	fs := referenceframe.NewEmptyFrameSystem("test")
+	test.That(t, fs.AddFrame(slider, fs.World()), test.ShouldBeNil)
+	fsInputs := map[string][]referenceframe.Input{"slider": {{Value: 0}}}
+	to := []referenceframe.Input{{Value: 100}}
+
+	newMonitor := func(s *driftingSlider, conf *ExecutionMonitorConfig) *executionMonitor {
+		return &executionMonitor{
+			conf:      conf,
+			frameSys:  fs,
+			resources: map[string]referenceframe.InputEnabled{"slider": s},
+			logger:    logger,
+		}
+	}
+
+	t.Run("unmonitored", func(t *testing.T) {
+		monitor := newMonitor(&driftingSlider{at: 40}, nil)
+		test.That(t, monitor.goToInputs(context.Background(), fsInputs, "slider", to), test.ShouldBeNil)
+	})
+
+	t.Run("off path while moving", func(t *testing.T) {
+		manager := operation.NewManager(logger)
+		ctx, done := manager.Create(context.Background(), "/viam.service.motion.v1.MotionService/Move", nil)
+		defer done()
+
+		s := &driftingSlider{at: 150, stuck: true}
+		monitor := newMonitor(s, &ExecutionMonitorConfig{MaxJointDeviation: 10, PollingHz: 100})
+		err := monitor.goToInputs(ctx, fsInputs, "slider", to)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "slider joint 0 deviated 50.00")
+		test.That(t, s.canceled, test.ShouldBeTrue)
+		test.That(t, operation.Get(ctx).Err(), test.ShouldEqual, err)
+	})
+
+	t.Run("short of the goal", func(t *testing.T) {
+		monitor := newMonitor(&driftingSlider{at: 95}, &ExecutionMonitorConfig{MaxJointDeviation: 10})
+		test.That(t, monitor.goToInputs(context.Background(), fsInputs, "slider", to), test.ShouldBeNil)
+
+		monitor = newMonitor(&driftingSlider{at: 40}, &ExecutionMonitorConfig{MaxPositionDeviationMm: 10})
+		err := monitor.goToInputs(context.Background(), fsInputs, "slider", to)
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, "slider deviated 60.0mm")
+	})
+}
+
+func TestExecutionMonitorConfig(t *testing.T) {
+	conf := &Config{ExecutionMonitor: &ExecutionMonitorConfig{MaxPositionDeviationMm: 5}}
+	_, err := conf.Validate("path")
+	test.That(t, err, test.ShouldBeNil)
+
+	conf.ExecutionMonitor = &ExecutionMonitorConfig{}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "needs a max_joint_deviation or max_position_deviation_mm")
+
+	conf.ExecutionMonitor = &ExecutionMonitorConfig{MaxJointDeviation: 1, PollingHz: -1}
+	_, err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+}
@@ -206,6 +206,10 @@ func (c *client) PlanHistory(
 	return append([]PlanWithStatus{pws}, statusHistory...), nil
 }
 
+func (c *client) MoveRelative(ctx context.Context, req MoveRelativeReq) error {
+	return moveRelative(ctx, c, req)
+}
+
 func (c *client) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
 	return protoutils.DoFromResourceClient(ctx, c.client, c.name, cmd)
 }
@@ -147,6 +147,32 @@ func TestClient(t *testing.T) {
 		}
 		test.That(t, receivedTransforms, test.ShouldNotBeNil)
 
+		// MoveRelative
+		var received motion.MoveRelativeReq
+		injectMS.MoveRelativeFunc = func(ctx context.Context, req motion.MoveRelativeReq) error {
+			received = req
+			return nil
+		}
+		jogger, ok := client.(motion.Jogger)
+		test.That(t, ok, test.ShouldBeTrue)
+		delta := spatialmath.NewPose(r3.Vector{Z: 10}, &spatialmath.OrientationVectorDegrees{OZ: 1, Theta: 15})
+		err = jogger.MoveRelative(ctx, motion.MoveRelativeReq{
+			ComponentName:  arm.Named("arm1"),
+			Delta:          delta,
+			ReferenceFrame: motion.BaseFrame,
+			LinearMmPerSec: 20,
+			AllowContact:   true,
+			Extra:          map[string]interface{}{"foo": "bar"},
+		})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, received.ComponentName, test.ShouldResemble, arm.Named("arm1"))
+		test.That(t, spatialmath.PoseAlmostEqual(received.Delta, delta), test.ShouldBeTrue)
+		test.That(t, received.ReferenceFrame, test.ShouldEqual, motion.BaseFrame)
+		test.That(t, received.LinearMmPerSec, test.ShouldEqual, 20)
+		test.That(t, received.AngularDegsPerSec, test.ShouldEqual, 0)
+		test.That(t, received.AllowContact, test.ShouldBeTrue)
+		test.That(t, received.Extra, test.ShouldResemble, map[string]interface{}{"foo": "bar"})
+
 		// DoCommand
 		injectMS.DoCommandFunc = testutils.EchoFunc
 		resp, err := client.DoCommand(context.Background(), testutils.TestCommand)
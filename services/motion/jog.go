@@ -0,0 +1,146 @@
+package motion
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// The frames a MoveRelativeReq delta can be given along, besides any frame of the frame system
+// by name.
+const (
+	// ToolFrame is the end frame of the component moved, so that it moves along its own axes.
+	ToolFrame = "tool"
+	// BaseFrame is the frame the component moved is mounted on, such as the base of an arm.
+	BaseFrame = "base"
+)
+
+// MoveRelativeReq describes a move of the end frame of a component by a delta from where it
+// is, such as a jog from a teleoperation UI.
+type MoveRelativeReq struct {
+	// ComponentName is the name of the component to move.
+	ComponentName resource.Name
+	// Delta is the move of the end frame of the component: its translation, in millimeters,
+	// along the axes of the reference frame, and its rotation about those axes, about the
+	// origin of the end frame.
+	Delta spatialmath.Pose
+	// ReferenceFrame is ToolFrame, BaseFrame, or the name of a frame of the frame system such
+	// as world. It defaults to ToolFrame.
+	ReferenceFrame string
+	// LinearMmPerSec is the speed of the end frame. It defaults to 50.
+	LinearMmPerSec float64
+	// AngularDegsPerSec is the angular speed of the end frame. It defaults to 20.
+	AngularDegsPerSec float64
+	// AllowContact lets the component touch obstacles instead of stopping it short of them,
+	// for tasks meant to touch them such as pressing a button. It is still kept from
+	// colliding with the robot itself.
+	AllowContact bool
+	Extra        map[string]interface{}
+}
+
+// A Jogger is a motion service that moves components relative to where they are, along a
+// straight line.
+type Jogger interface {
+	Service
+
+	// MoveRelative moves the end frame of the component by the delta of the request, at the
+	// speeds of the request, blocking until it is done.
+	MoveRelative(ctx context.Context, req MoveRelativeReq) error
+}
+
+// MoveRelativeCommandKey is the DoCommand key used to reach the MoveRelative method of a
+// motion service over the network. Its value holds the "component_name", "translation",
+// "orientation", "reference_frame", "linear_mm_per_sec", "angular_degs_per_sec",
+// "allow_contact" and "extra" of the request. Motion services that cannot move relatively
+// return an Unimplemented error.
+const MoveRelativeCommandKey = "move_relative"
+
+type moveRelativeCommand struct {
+	ComponentName     string                         `json:"component_name"`
+	Translation       r3.Vector                      `json:"translation"`
+	Orientation       *spatialmath.OrientationConfig `json:"orientation,omitempty"`
+	ReferenceFrame    string                         `json:"reference_frame,omitempty"`
+	LinearMmPerSec    float64                        `json:"linear_mm_per_sec,omitempty"`
+	AngularDegsPerSec float64                        `json:"angular_degs_per_sec,omitempty"`
+	AllowContact      bool                           `json:"allow_contact,omitempty"`
+	Extra             map[string]interface{}         `json:"extra,omitempty"`
+}
+
+// doMoveRelative carries out the MoveRelativeCommandKey command cmd with the service.
+func doMoveRelative(ctx context.Context, svc Service, cmd interface{}) (map[string]interface{}, error) {
+	var args moveRelativeCommand
+	raw, err := json.Marshal(cmd)
+	if err == nil {
+		err = json.Unmarshal(raw, &args)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s command: %v", MoveRelativeCommandKey, err)
+	}
+	jogger, ok := svc.(Jogger)
+	if !ok {
+		return nil, status.Errorf(codes.Unimplemented, "motion service %q does not support relative moves", svc.Name().ShortName())
+	}
+	componentName, err := resource.NewFromString(args.ComponentName)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s command: %v", MoveRelativeCommandKey, err)
+	}
+	delta := spatialmath.NewPoseFromPoint(args.Translation)
+	if args.Orientation != nil {
+		orientation, err := args.Orientation.ParseConfig()
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid %s orientation: %v", MoveRelativeCommandKey, err)
+		}
+		delta = spatialmath.NewPose(args.Translation, orientation)
+	}
+	err = jogger.MoveRelative(ctx, MoveRelativeReq{
+		ComponentName:     componentName,
+		Delta:             delta,
+		ReferenceFrame:    args.ReferenceFrame,
+		LinearMmPerSec:    args.LinearMmPerSec,
+		AngularDegsPerSec: args.AngularDegsPerSec,
+		AllowContact:      args.AllowContact,
+		Extra:             args.Extra,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{}, nil
+}
+
+// moveRelative sends the MoveRelativeCommandKey command for the request to the service with
+// DoCommand.
+func moveRelative(ctx context.Context, svc Service, req MoveRelativeReq) error {
+	if req.Delta == nil {
+		return errors.New("a delta to move by is required")
+	}
+	orientation, err := spatialmath.NewOrientationConfig(req.Delta.Orientation())
+	if err != nil {
+		return err
+	}
+	var args map[string]interface{}
+	raw, err := json.Marshal(moveRelativeCommand{
+		ComponentName:     req.ComponentName.String(),
+		Translation:       req.Delta.Point(),
+		Orientation:       orientation,
+		ReferenceFrame:    req.ReferenceFrame,
+		LinearMmPerSec:    req.LinearMmPerSec,
+		AngularDegsPerSec: req.AngularDegsPerSec,
+		AllowContact:      req.AllowContact,
+		Extra:             req.Extra,
+	})
+	if err == nil {
+		err = json.Unmarshal(raw, &args)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = svc.DoCommand(ctx, map[string]interface{}{MoveRelativeCommandKey: args})
+	return err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	commonpb "go.viam.com/api/common/v1"
 	pb "go.viam.com/api/service/motion/v1"
+	"google.golang.org/protobuf/types/known/structpb"
 
 	"go.viam.com/rdk/protoutils"
 	"go.viam.com/rdk/referenceframe"
@@ -169,5 +170,16 @@ func (server *serviceServer) DoCommand(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
+	if cmd, ok := req.GetCommand().AsMap()[MoveRelativeCommandKey]; ok {
+		res, err := doMoveRelative(ctx, svc, cmd)
+		if err != nil {
+			return nil, err
+		}
+		pbRes, err := structpb.NewStruct(res)
+		if err != nil {
+			return nil, err
+		}
+		return &commonpb.DoCommandResponse{Result: pbRes}, nil
+	}
 	return protoutils.DoFromResourceServer(ctx, svc, req)
 }
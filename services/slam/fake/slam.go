@@ -8,6 +8,7 @@ import (
 
 	"go.opencensus.io/trace"
 
+	"go.viam.com/rdk/clock"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
@@ -53,7 +54,7 @@ func NewSLAM(name resource.Name, logger logging.Logger) *SLAM {
 		Named:        name.AsNamed(),
 		logger:       logger,
 		dataCount:    -1,
-		mapTimestamp: time.Now().UTC(),
+		mapTimestamp: clock.Now().UTC(),
 	}
 }
 
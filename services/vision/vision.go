@@ -229,6 +229,17 @@ func (vm *vizModel) Detections(
 	return vm.detectorFunc(ctx, img)
 }
 
+// nextFrame returns the next frame of the camera, shared with its other consumers such as
+// the data manager, and records which frame it is on the span.
+func nextFrame(ctx context.Context, span *trace.Span, cam camera.Camera) (image.Image, func(), error) {
+	frame, release, err := camera.SharedFrameBroker(cam).Next(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	span.AddAttributes(trace.Int64Attribute("frame_sequence", int64(frame.Sequence)))
+	return frame.Image, release, nil
+}
+
 // DetectionsFromCamera returns the detections of the next image from the given camera.
 func (vm *vizModel) DetectionsFromCamera(
 	ctx context.Context,
@@ -244,7 +255,7 @@ func (vm *vizModel) DetectionsFromCamera(
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not find camera named %s", cameraName)
 	}
-	img, release, err := camera.ReadImage(ctx, cam)
+	img, release, err := nextFrame(ctx, span, cam)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not get image from %s", cameraName)
 	}
@@ -287,7 +298,7 @@ func (vm *vizModel) ClassificationsFromCamera(
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not find camera named %s", cameraName)
 	}
-	img, release, err := camera.ReadImage(ctx, cam)
+	img, release, err := nextFrame(ctx, span, cam)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not get image from %s", cameraName)
 	}
@@ -337,7 +348,7 @@ func (vm *vizModel) CaptureAllFromCamera(
 	if err != nil {
 		return viscapture.VisCapture{}, errors.Wrapf(err, "could not find camera named %s", cameraName)
 	}
-	img, release, err := camera.ReadImage(ctx, cam)
+	img, release, err := nextFrame(ctx, span, cam)
 	if err != nil {
 		return viscapture.VisCapture{}, errors.Wrapf(err, "could not get image from %s", cameraName)
 	}
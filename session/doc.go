@@ -14,8 +14,9 @@ the application layer (i.e. RDK, not TCP) maintained by a client (e.g. SDK) with
 Since the client maintains the session, it is responsible for telling the server it is still present
 every so often; this will be called staying within the heartbeat window. The client must send at least one
 session heartbeat within this window. As soon as the window lapses/expires, the server will safely stop all resources
-that are marked for safety monitoring that have been last used by that session, and no others; a lapsed client
-will attempt to establish a new session immediately prior to the next operation it performs.
+that are marked for safety monitoring that have been last used by that session, along with the resources acted on by
+operations still in flight under that session, and no others; a lapsed client will attempt to establish a new session
+immediately prior to the next operation it performs.
 
 # Goals of session management
 
@@ -117,7 +118,8 @@ The interceptor should be split up into two parts, the session heatbeater/manage
 The session manager is responsible for keeping track of all sessions in addition to which session was the last
 to be associated with a safety monitored resource. It should check session expiration on an interval less than
 or equal to the minimum heartbeat window (e.g. 1ms). When a session expires, it should Stop all resources it
-was associated with having their safety monitored methods called.
+was associated with having their safety monitored methods called, as well as the resources its in flight operations
+act on, and then cancel those operations.
 
 # Server Session Interceptor
 
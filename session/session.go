@@ -21,6 +21,8 @@ type Session struct {
 	peerConnInfo    *pb.PeerConnectionInfo
 	ownerID         []byte
 	deadline        time.Time
+	lastActivity    time.Time
+	callsInFlight   int
 	heartbeatWindow time.Duration
 
 	associateResource func(id uuid.UUID, resourceName resource.Name)
@@ -49,6 +51,7 @@ func NewWithID(
 		ownerID:           []byte(ownerID),
 		heartbeatWindow:   heartbeatWindow,
 		associateResource: associateResource,
+		lastActivity:      time.Now(),
 	}
 	sess.Heartbeat(ctx)
 	return sess
@@ -72,6 +75,40 @@ func (s *Session) Heartbeat(ctx context.Context) {
 	s.mu.Unlock()
 }
 
+// StartCall signals that a call (unary or streaming) was started under the session, and
+// returns the function to signal that it has ended. A session with calls in flight is not
+// idle, however long they run.
+func (s *Session) StartCall() func() {
+	s.mu.Lock()
+	s.callsInFlight++
+	s.lastActivity = time.Now()
+	s.mu.Unlock()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.callsInFlight--
+			s.lastActivity = time.Now()
+			s.mu.Unlock()
+		})
+	}
+}
+
+// CallsInFlight returns the number of calls started under the session that have not ended.
+func (s *Session) CallsInFlight() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.callsInFlight
+}
+
+// LastActivity returns the last time the session was used for something other
+// than a heartbeat.
+func (s *Session) LastActivity() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastActivity
+}
+
 // Active checks if this session is still active.
 func (s *Session) Active(at time.Time) bool {
 	s.mu.Lock()
@@ -81,6 +118,8 @@ func (s *Session) Active(at time.Time) bool {
 
 // PeerConnectionInfo returns connection info related to the session.
 func (s *Session) PeerConnectionInfo() *pb.PeerConnectionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.peerConnInfo
 }
 
@@ -100,3 +100,35 @@ func TestNewWithID(t *testing.T) {
 	test.That(t, sess1.Deadline().After(now), test.ShouldBeTrue)
 	test.That(t, sess1.Deadline().Before(now.Add(2*dur)), test.ShouldBeFalse)
 }
+
+func TestLastActivity(t *testing.T) {
+	sess := New(context.Background(), "owner1", time.Second, nil)
+	before := sess.LastActivity()
+	test.That(t, before.IsZero(), test.ShouldBeFalse)
+
+	time.Sleep(10 * time.Millisecond)
+	sess.Heartbeat(context.Background())
+	test.That(t, sess.LastActivity(), test.ShouldEqual, before)
+
+	done := sess.StartCall()
+	defer done()
+	test.That(t, sess.LastActivity().After(before), test.ShouldBeTrue)
+}
+
+func TestStartCall(t *testing.T) {
+	sess := New(context.Background(), "owner1", time.Second, nil)
+	test.That(t, sess.CallsInFlight(), test.ShouldEqual, 0)
+
+	done1 := sess.StartCall()
+	done2 := sess.StartCall()
+	test.That(t, sess.CallsInFlight(), test.ShouldEqual, 2)
+
+	before := sess.LastActivity()
+	time.Sleep(10 * time.Millisecond)
+	done1()
+	done1()
+	test.That(t, sess.CallsInFlight(), test.ShouldEqual, 1)
+	test.That(t, sess.LastActivity().After(before), test.ShouldBeTrue)
+	done2()
+	test.That(t, sess.CallsInFlight(), test.ShouldEqual, 0)
+}
@@ -0,0 +1,241 @@
+package gazebo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/components/arm"
+	fakearm "go.viam.com/rdk/components/arm/fake"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/motionplan"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+const (
+	// jointToleranceRad is how close a joint must come to its commanded position to reach it.
+	jointToleranceRad = 1e-3
+	// movePollInterval is how often the joints of a moving arm are checked against its goal.
+	movePollInterval = 50 * time.Millisecond
+)
+
+func init() {
+	resource.RegisterComponent(arm.API, Model, resource.Registration[arm.Arm, *ArmConfig]{
+		Constructor: NewArm,
+	})
+}
+
+// ArmConfig is used for converting config attributes of a simulated arm.
+type ArmConfig struct {
+	SimConfig
+	// ArmModel and ModelFilePath select the kinematics as they do for a fake arm.
+	ArmModel      string `json:"arm-model,omitempty"`
+	ModelFilePath string `json:"model-path,omitempty"`
+	// Joints are the names of the Gazebo joints, in kinematic order.
+	Joints []string `json:"joints"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *ArmConfig) Validate(path string) ([]string, error) {
+	if err := conf.SimConfig.Validate(path); err != nil {
+		return nil, err
+	}
+	if len(conf.Joints) == 0 {
+		return nil, resource.NewConfigValidationFieldRequiredError(path, "joints")
+	}
+	return (&fakearm.Config{ArmModel: conf.ArmModel, ModelFilePath: conf.ModelFilePath}).Validate(path)
+}
+
+type simArm struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	logger logging.Logger
+	client Client
+	entity string
+	joints []string
+	model  referenceframe.Model
+	opMgr  *operation.SingleOperationManager
+
+	mu sync.Mutex
+	// goal holds the joint positions last commanded, until they are reached.
+	goal []float64
+}
+
+// NewArm returns an arm whose joints are driven by a Gazebo model.
+func NewArm(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (arm.Arm, error) {
+	newConf, err := resource.NativeConfig[*ArmConfig](conf)
+	if err != nil {
+		return nil, err
+	}
+	client := NewCLIClient(newConf.World)
+	a, err := newArm(ctx, deps, conf, newConf, client, logger)
+	if err != nil {
+		return nil, multierr.Combine(err, client.Close())
+	}
+	return a, nil
+}
+
+func newArm(
+	ctx context.Context,
+	deps resource.Dependencies,
+	conf resource.Config,
+	newConf *ArmConfig,
+	client Client,
+	logger logging.Logger,
+) (arm.Arm, error) {
+	// the fake arm knows how to build kinematics from the same attributes.
+	kinematics, err := fakearm.NewArm(ctx, deps, resource.Config{
+		Name:  conf.Name,
+		API:   arm.API,
+		Model: fakearm.Model,
+		ConvertedAttributes: &fakearm.Config{
+			ArmModel:      newConf.ArmModel,
+			ModelFilePath: newConf.ModelFilePath,
+		},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	model := kinematics.ModelFrame()
+	if len(model.DoF()) != len(newConf.Joints) {
+		return nil, errors.Errorf("arm model has %d degrees of freedom but %d joints were given",
+			len(model.DoF()), len(newConf.Joints))
+	}
+
+	a := &simArm{
+		Named:  conf.ResourceName().AsNamed(),
+		logger: logger,
+		client: client,
+		entity: newConf.entity(conf),
+		joints: newConf.Joints,
+		model:  model,
+		opMgr:  operation.NewSingleOperationManager(),
+	}
+	if err := placeFromFrame(ctx, a.client, a.entity, conf); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+func (a *simArm) ModelFrame() referenceframe.Model {
+	return a.model
+}
+
+func (a *simArm) EndPosition(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+	joints, err := a.JointPositions(ctx, extra)
+	if err != nil {
+		return nil, err
+	}
+	return motionplan.ComputeOOBPosition(a.model, joints)
+}
+
+func (a *simArm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+	return arm.Move(ctx, a.logger, a, pose)
+}
+
+// MoveToJointPositions commands the joints to the given positions and waits for them to
+// get there.
+func (a *simArm) MoveToJointPositions(ctx context.Context, joints *pb.JointPositions, extra map[string]interface{}) error {
+	inputs := a.model.InputFromProtobuf(joints)
+	if err := arm.CheckDesiredJointPositions(ctx, a, inputs); err != nil {
+		return err
+	}
+	return a.moveTo(ctx, referenceframe.InputsToFloats(inputs))
+}
+
+func (a *simArm) moveTo(ctx context.Context, goal []float64) error {
+	a.mu.Lock()
+	a.goal = goal
+	a.mu.Unlock()
+	if err := a.client.SetJointPositions(ctx, a.entity, a.joints, goal); err != nil {
+		return err
+	}
+	return a.opMgr.WaitForSuccess(ctx, movePollInterval, func(ctx context.Context) (bool, error) {
+		moving, err := a.IsMoving(ctx)
+		return !moving, err
+	})
+}
+
+func (a *simArm) JointPositions(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+	inputs, err := a.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return a.model.ProtobufFromInput(inputs), nil
+}
+
+// Stop holds the arm at its current joint positions.
+func (a *simArm) Stop(ctx context.Context, extra map[string]interface{}) error {
+	a.opMgr.CancelRunning(ctx)
+	inputs, err := a.CurrentInputs(ctx)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.goal = nil
+	a.mu.Unlock()
+	return a.client.SetJointPositions(ctx, a.entity, a.joints, referenceframe.InputsToFloats(inputs))
+}
+
+// IsMoving returns whether the joints have yet to reach the positions last commanded.
+func (a *simArm) IsMoving(ctx context.Context) (bool, error) {
+	a.mu.Lock()
+	goal := a.goal
+	a.mu.Unlock()
+	if goal == nil {
+		return false, nil
+	}
+	positions, err := a.client.JointPositions(ctx, a.entity, a.joints)
+	if err != nil {
+		return false, err
+	}
+	for i, pos := range positions {
+		if math.Abs(pos-goal[i]) > jointToleranceRad {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (a *simArm) CurrentInputs(ctx context.Context) ([]referenceframe.Input, error) {
+	positions, err := a.client.JointPositions(ctx, a.entity, a.joints)
+	if err != nil {
+		return nil, err
+	}
+	return referenceframe.FloatsToInputs(positions), nil
+}
+
+func (a *simArm) GoToInputs(ctx context.Context, inputSteps ...[]referenceframe.Input) error {
+	for _, goal := range inputSteps {
+		if err := a.MoveToJointPositions(ctx, a.model.ProtobufFromInput(goal), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *simArm) Close(ctx context.Context) error {
+	a.opMgr.CancelRunning(ctx)
+	return a.client.Close()
+}
+
+func (a *simArm) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	inputs, err := a.CurrentInputs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gif, err := a.model.Geometries(inputs)
+	if err != nil {
+		return nil, err
+	}
+	return gif.Geometries(), nil
+}
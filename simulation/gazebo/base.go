@@ -0,0 +1,193 @@
+package gazebo
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/base"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/operation"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
+)
+
+const (
+	defaultWidthMM              = 600
+	defaultMaxLinearMMPerSec    = 300
+	defaultMaxAngularDegsPerSec = 90
+)
+
+func init() {
+	resource.RegisterComponent(base.API, Model, resource.Registration[base.Base, *BaseConfig]{
+		Constructor: NewBase,
+	})
+}
+
+// BaseConfig is used for converting config attributes of a simulated base.
+type BaseConfig struct {
+	SimConfig
+	WidthMM              int     `json:"width_mm,omitempty"`
+	WheelCircumferenceMM int     `json:"wheel_circumference_mm,omitempty"`
+	MaxLinearMMPerSec    float64 `json:"max_linear_mm_per_sec,omitempty"`
+	MaxAngularDegsPerSec float64 `json:"max_angular_degs_per_sec,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *BaseConfig) Validate(path string) ([]string, error) {
+	return nil, conf.SimConfig.Validate(path)
+}
+
+type simBase struct {
+	resource.Named
+	resource.AlwaysRebuild
+
+	logger     logging.Logger
+	client     Client
+	entity     string
+	props      base.Properties
+	maxLinear  float64
+	maxAngular float64
+	geometries []spatialmath.Geometry
+	opMgr      *operation.SingleOperationManager
+
+	mu     sync.Mutex
+	moving bool
+}
+
+// NewBase returns a base driven by the velocity controller of a Gazebo model.
+func NewBase(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (base.Base, error) {
+	newConf, err := resource.NativeConfig[*BaseConfig](conf)
+	if err != nil {
+		return nil, err
+	}
+	client := NewCLIClient(newConf.World)
+	b, err := newBase(ctx, conf, newConf, client, logger)
+	if err != nil {
+		return nil, multierr.Combine(err, client.Close())
+	}
+	return b, nil
+}
+
+func newBase(ctx context.Context, conf resource.Config, newConf *BaseConfig, client Client, logger logging.Logger) (base.Base, error) {
+	b := &simBase{
+		Named:      conf.ResourceName().AsNamed(),
+		logger:     logger,
+		client:     client,
+		entity:     newConf.entity(conf),
+		maxLinear:  newConf.MaxLinearMMPerSec,
+		maxAngular: newConf.MaxAngularDegsPerSec,
+		opMgr:      operation.NewSingleOperationManager(),
+	}
+	width := newConf.WidthMM
+	if width == 0 {
+		width = defaultWidthMM
+	}
+	b.props = base.Properties{
+		WidthMeters:              float64(width) * 0.001,
+		WheelCircumferenceMeters: float64(newConf.WheelCircumferenceMM) * 0.001,
+	}
+	if b.maxLinear == 0 {
+		b.maxLinear = defaultMaxLinearMMPerSec
+	}
+	if b.maxAngular == 0 {
+		b.maxAngular = defaultMaxAngularDegsPerSec
+	}
+	if conf.Frame != nil && conf.Frame.Geometry != nil {
+		geometry, err := conf.Frame.Geometry.ParseConfig()
+		if err != nil {
+			return nil, err
+		}
+		b.geometries = []spatialmath.Geometry{geometry}
+	}
+	if err := placeFromFrame(ctx, b.client, b.entity, conf); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// MoveStraight drives at the given speed until the distance is covered according to the
+// robot clock.
+func (b *simBase) MoveStraight(ctx context.Context, distanceMm int, mmPerSec float64, extra map[string]interface{}) error {
+	if distanceMm == 0 || mmPerSec == 0 {
+		return b.Stop(ctx, nil)
+	}
+	speed := math.Copysign(math.Abs(mmPerSec), float64(distanceMm)*mmPerSec)
+	dur := time.Duration(math.Abs(float64(distanceMm)/mmPerSec) * float64(time.Second))
+	return b.runFor(ctx, dur, r3.Vector{Y: speed}, r3.Vector{})
+}
+
+// Spin turns at the given speed until the angle is covered according to the robot clock.
+func (b *simBase) Spin(ctx context.Context, angleDeg, degsPerSec float64, extra map[string]interface{}) error {
+	if angleDeg == 0 || degsPerSec == 0 {
+		return b.Stop(ctx, nil)
+	}
+	speed := math.Copysign(math.Abs(degsPerSec), angleDeg*degsPerSec)
+	dur := time.Duration(math.Abs(angleDeg/degsPerSec) * float64(time.Second))
+	return b.runFor(ctx, dur, r3.Vector{}, r3.Vector{Z: speed})
+}
+
+func (b *simBase) runFor(ctx context.Context, dur time.Duration, linear, angular r3.Vector) error {
+	if err := b.setVelocity(ctx, linear, angular); err != nil {
+		return err
+	}
+	if b.opMgr.NewTimedWaitOp(ctx, dur) {
+		return b.Stop(ctx, nil)
+	}
+	return nil
+}
+
+// SetPower scales the maximum speeds by the given powers.
+func (b *simBase) SetPower(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	b.opMgr.CancelRunning(ctx)
+	return b.setVelocity(ctx, linear.Mul(b.maxLinear), angular.Mul(b.maxAngular))
+}
+
+func (b *simBase) SetVelocity(ctx context.Context, linear, angular r3.Vector, extra map[string]interface{}) error {
+	b.opMgr.CancelRunning(ctx)
+	return b.setVelocity(ctx, linear, angular)
+}
+
+// setVelocity converts from the base's convention (mm/s with +Y forward and deg/s) to
+// Gazebo's (m/s with +X forward and rad/s).
+func (b *simBase) setVelocity(ctx context.Context, linear, angular r3.Vector) error {
+	if err := b.client.SetVelocity(ctx, b.entity,
+		r3.Vector{X: linear.Y * 0.001, Y: -linear.X * 0.001, Z: linear.Z * 0.001},
+		r3.Vector{X: rutils.DegToRad(angular.X), Y: rutils.DegToRad(angular.Y), Z: rutils.DegToRad(angular.Z)},
+	); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.moving = linear.Norm() != 0 || angular.Norm() != 0
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *simBase) Stop(ctx context.Context, extra map[string]interface{}) error {
+	b.opMgr.CancelRunning(ctx)
+	return b.setVelocity(ctx, r3.Vector{}, r3.Vector{})
+}
+
+func (b *simBase) IsMoving(ctx context.Context) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.moving, nil
+}
+
+func (b *simBase) Properties(ctx context.Context, extra map[string]interface{}) (base.Properties, error) {
+	return b.props, nil
+}
+
+func (b *simBase) Close(ctx context.Context) error {
+	b.opMgr.CancelRunning(ctx)
+	return b.client.Close()
+}
+
+func (b *simBase) Geometries(ctx context.Context, extra map[string]interface{}) ([]spatialmath.Geometry, error) {
+	return b.geometries, nil
+}
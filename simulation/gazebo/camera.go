@@ -0,0 +1,123 @@
+package gazebo
+
+import (
+	"context"
+	"image"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+)
+
+func init() {
+	resource.RegisterComponent(camera.API, Model, resource.Registration[camera.Camera, *CameraConfig]{
+		Constructor: NewCamera,
+	})
+}
+
+// CameraConfig is used for converting config attributes of a simulated camera or lidar.
+type CameraConfig struct {
+	SimConfig
+	// ImageTopic is the topic a Gazebo camera sensor publishes images on.
+	ImageTopic string `json:"image_topic,omitempty"`
+	// PointCloudTopic is the topic a Gazebo lidar sensor publishes scans on.
+	PointCloudTopic string `json:"point_cloud_topic,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *CameraConfig) Validate(path string) ([]string, error) {
+	if err := conf.SimConfig.Validate(path); err != nil {
+		return nil, err
+	}
+	if conf.ImageTopic == "" && conf.PointCloudTopic == "" {
+		return nil, resource.NewConfigValidationError(path, errors.New("one of image_topic or point_cloud_topic is required"))
+	}
+	return nil, nil
+}
+
+type simCamera struct {
+	client          Client
+	imageTopic      string
+	pointCloudTopic string
+}
+
+// NewCamera returns a camera backed by Gazebo camera and lidar sensors.
+func NewCamera(ctx context.Context, _ resource.Dependencies, conf resource.Config, logger logging.Logger) (camera.Camera, error) {
+	newConf, err := resource.NativeConfig[*CameraConfig](conf)
+	if err != nil {
+		return nil, err
+	}
+	client := NewCLIClient(newConf.World)
+	cam, err := newCamera(ctx, conf, newConf, client, logger)
+	if err != nil {
+		return nil, multierr.Combine(err, client.Close())
+	}
+	return cam, nil
+}
+
+func newCamera(
+	ctx context.Context,
+	conf resource.Config,
+	newConf *CameraConfig,
+	client Client,
+	logger logging.Logger,
+) (camera.Camera, error) {
+	if err := placeFromFrame(ctx, client, newConf.entity(conf), conf); err != nil {
+		return nil, err
+	}
+	cam := &simCamera{
+		client:          client,
+		imageTopic:      newConf.ImageTopic,
+		pointCloudTopic: newConf.PointCloudTopic,
+	}
+	var (
+		src camera.VideoSource
+		err error
+	)
+	if cam.pointCloudTopic == "" {
+		src, err = camera.NewVideoSourceFromReader(ctx, imageOnlyCamera{cam}, nil, camera.ColorStream)
+	} else {
+		src, err = camera.NewVideoSourceFromReader(ctx, cam, nil, camera.ColorStream)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return camera.FromVideoSource(conf.ResourceName(), src, logger), nil
+}
+
+func (c *simCamera) Read(ctx context.Context) (image.Image, func(), error) {
+	if c.imageTopic == "" {
+		return nil, nil, errors.New("no image_topic configured for this camera")
+	}
+	img, err := c.client.Image(ctx, c.imageTopic)
+	if err != nil {
+		return nil, nil, err
+	}
+	return img, func() {}, nil
+}
+
+func (c *simCamera) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	return c.client.PointCloud(ctx, c.pointCloudTopic)
+}
+
+func (c *simCamera) Close(ctx context.Context) error {
+	return c.client.Close()
+}
+
+// imageOnlyCamera hides NextPointCloud so that cameras without a lidar do not claim to
+// support point clouds.
+type imageOnlyCamera struct {
+	cam *simCamera
+}
+
+func (c imageOnlyCamera) Read(ctx context.Context) (image.Image, func(), error) {
+	return c.cam.Read(ctx)
+}
+
+func (c imageOnlyCamera) Close(ctx context.Context) error {
+	return c.cam.Close(ctx)
+}
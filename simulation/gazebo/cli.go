@@ -0,0 +1,477 @@
+package gazebo
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/spatialmath"
+	rutils "go.viam.com/rdk/utils"
+)
+
+const (
+	// cliTimeoutMs is how long the gz tool waits on a service response.
+	cliTimeoutMs = "2000"
+	// maxMessageBytes is the largest message printed by gz that is read, which leaves room
+	// for uncompressed images of a few megapixels.
+	maxMessageBytes = 64 << 20
+)
+
+var errClientClosed = errors.New("gazebo client is closed")
+
+// cliClient talks to Gazebo through the gz command line tool, which must be on the PATH.
+// Commands run the tool once each. Reads are served from the latest message on their topic,
+// printed by a gz process kept echoing it from the first read until the client is closed.
+type cliClient struct {
+	world  string
+	run    func(ctx context.Context, args ...string) ([]byte, error)
+	stream func(ctx context.Context, args ...string) (io.ReadCloser, error)
+
+	workers rutils.StoppableWorkers
+
+	mu     sync.Mutex
+	closed bool
+	subs   map[string]*subscription
+}
+
+// NewCLIClient returns a client that uses the gz command line tool to talk to the given
+// world.
+func NewCLIClient(world string) Client {
+	return newCLIClient(world, runGZ, streamGZ)
+}
+
+func newCLIClient(
+	world string,
+	run func(ctx context.Context, args ...string) ([]byte, error),
+	stream func(ctx context.Context, args ...string) (io.ReadCloser, error),
+) *cliClient {
+	return &cliClient{
+		world:   world,
+		run:     run,
+		stream:  stream,
+		workers: rutils.NewStoppableWorkers(),
+		subs:    map[string]*subscription{},
+	}
+}
+
+// Close stops echoing the topics read from.
+func (c *cliClient) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	c.workers.Stop()
+	return nil
+}
+
+func runGZ(ctx context.Context, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath("gz"); err != nil {
+		return nil, errors.Wrap(err, "the gz tool is required to talk to Gazebo")
+	}
+	//nolint:gosec
+	out, err := exec.CommandContext(ctx, "gz", args...).CombinedOutput()
+	if err != nil {
+		return nil, errors.Wrapf(err, "gz %s failed: %s", args[0], strings.TrimSpace(string(out)))
+	}
+	return out, nil
+}
+
+// gzStream is the output of a running gz process.
+type gzStream struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *bytes.Buffer
+}
+
+func streamGZ(ctx context.Context, args ...string) (io.ReadCloser, error) {
+	if _, err := exec.LookPath("gz"); err != nil {
+		return nil, errors.Wrap(err, "the gz tool is required to talk to Gazebo")
+	}
+	//nolint:gosec
+	cmd := exec.CommandContext(ctx, "gz", args...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "gz %s failed to start", args[0])
+	}
+	return &gzStream{ReadCloser: stdout, cmd: cmd, stderr: stderr}, nil
+}
+
+// Close waits for the process to exit, which it does once its context is done.
+func (s *gzStream) Close() error {
+	if err := s.cmd.Wait(); err != nil {
+		return errors.Wrapf(err, "gz failed: %s", strings.TrimSpace(s.stderr.String()))
+	}
+	return nil
+}
+
+// A subscription holds the latest message printed by the gz process echoing a topic.
+type subscription struct {
+	mu sync.Mutex
+	// received is closed once the first message arrives or the echoing ends.
+	received chan struct{}
+	latest   []byte
+	err      error
+}
+
+func (s *subscription) set(msg []byte, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	first := s.latest == nil
+	if msg != nil {
+		s.latest = msg
+	}
+	s.err = err
+	if first {
+		close(s.received)
+	}
+}
+
+// message returns the latest message, waiting for the first one. The error the echoing
+// ended with is only returned if no message arrived.
+func (s *subscription) message(ctx context.Context) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-s.received:
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.latest == nil {
+		return nil, s.err
+	}
+	return s.latest, nil
+}
+
+// subscribe returns the subscription to the topic, starting to echo it if it is not already.
+func (c *cliClient) subscribe(topic string) (*subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, errClientClosed
+	}
+	if sub, ok := c.subs[topic]; ok {
+		return sub, nil
+	}
+	sub := &subscription{received: make(chan struct{})}
+	c.subs[topic] = sub
+	c.workers.AddWorkers(func(ctx context.Context) {
+		err := c.follow(ctx, topic, sub)
+		// the next read starts echoing the topic again.
+		c.mu.Lock()
+		delete(c.subs, topic)
+		c.mu.Unlock()
+		sub.set(nil, err)
+	})
+	return sub, nil
+}
+
+// follow keeps the subscription to the topic up to date until the echoing ends.
+func (c *cliClient) follow(ctx context.Context, topic string, sub *subscription) (err error) {
+	out, err := c.stream(ctx, "topic", "-e", "--json-output", "-t", topic)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, out.Close())
+	}()
+	scanner := bufio.NewScanner(out)
+	scanner.Buffer(nil, maxMessageBytes)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		sub.set(bytes.Clone(scanner.Bytes()), nil)
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrapf(err, "failed to read messages from %q", topic)
+	}
+	if ctx.Err() != nil {
+		return errClientClosed
+	}
+	return errors.Errorf("gz stopped echoing %q", topic)
+}
+
+// echo decodes the latest message on the topic, waiting for the first one.
+func (c *cliClient) echo(ctx context.Context, topic string, msg interface{}) error {
+	sub, err := c.subscribe(topic)
+	if err != nil {
+		return err
+	}
+	data, err := sub.message(ctx)
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(json.Unmarshal(data, msg), "failed to parse message from %q", topic)
+}
+
+func (c *cliClient) publish(ctx context.Context, topic, msgType, msg string) error {
+	_, err := c.run(ctx, "topic", "-t", topic, "-m", msgType, "-p", msg)
+	return err
+}
+
+func (c *cliClient) SetPose(ctx context.Context, entity string, pose spatialmath.Pose) error {
+	pt := pose.Point()
+	q := pose.Orientation().Quaternion()
+	req := fmt.Sprintf(`name: %q, position: {x: %g, y: %g, z: %g}, orientation: {x: %g, y: %g, z: %g, w: %g}`,
+		entity, pt.X, pt.Y, pt.Z, q.Imag, q.Jmag, q.Kmag, q.Real)
+	out, err := c.run(ctx, "service", "-s", fmt.Sprintf("/world/%s/set_pose", c.world),
+		"--reqtype", "gz.msgs.Pose", "--reptype", "gz.msgs.Boolean", "--timeout", cliTimeoutMs, "--req", req)
+	if err != nil {
+		return err
+	}
+	if !strings.Contains(string(out), "data: true") {
+		return errors.Errorf("gazebo refused to set pose of %q: %s", entity, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+type jsonVector struct {
+	X, Y, Z jsonFloat
+}
+
+type jsonQuaternion struct {
+	X, Y, Z jsonFloat
+	W       *jsonFloat
+}
+
+type jsonPose struct {
+	Name        string         `json:"name"`
+	Position    jsonVector     `json:"position"`
+	Orientation jsonQuaternion `json:"orientation"`
+}
+
+func (p jsonPose) pose() spatialmath.Pose {
+	// an omitted w is the default value of 1 for an identity rotation
+	w := 1.
+	if p.Orientation.W != nil {
+		w = float64(*p.Orientation.W)
+	}
+	return spatialmath.NewPose(
+		r3.Vector{X: float64(p.Position.X), Y: float64(p.Position.Y), Z: float64(p.Position.Z)},
+		&spatialmath.Quaternion{
+			Real: w,
+			Imag: float64(p.Orientation.X),
+			Jmag: float64(p.Orientation.Y),
+			Kmag: float64(p.Orientation.Z),
+		},
+	)
+}
+
+func (c *cliClient) Pose(ctx context.Context, entity string) (spatialmath.Pose, error) {
+	var msg struct {
+		Pose []jsonPose `json:"pose"`
+	}
+	if err := c.echo(ctx, fmt.Sprintf("/world/%s/pose/info", c.world), &msg); err != nil {
+		return nil, err
+	}
+	for _, p := range msg.Pose {
+		if p.Name == entity {
+			return p.pose(), nil
+		}
+	}
+	return nil, errors.Errorf("entity %q not found in world %q", entity, c.world)
+}
+
+func (c *cliClient) SetJointPositions(ctx context.Context, model string, joints []string, positions []float64) error {
+	if len(joints) != len(positions) {
+		return errors.Errorf("got %d positions for %d joints", len(positions), len(joints))
+	}
+	for i, joint := range joints {
+		topic := fmt.Sprintf("/model/%s/joint/%s/0/cmd_pos", model, joint)
+		if err := c.publish(ctx, topic, "gz.msgs.Double", fmt.Sprintf("data: %g", positions[i])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *cliClient) JointPositions(ctx context.Context, model string, joints []string) ([]float64, error) {
+	var msg struct {
+		Joint []struct {
+			Name  string `json:"name"`
+			Axis1 struct {
+				Position jsonFloat `json:"position"`
+			} `json:"axis1"`
+		} `json:"joint"`
+	}
+	if err := c.echo(ctx, fmt.Sprintf("/world/%s/model/%s/joint_state", c.world, model), &msg); err != nil {
+		return nil, err
+	}
+	byName := map[string]float64{}
+	for _, j := range msg.Joint {
+		byName[j.Name] = float64(j.Axis1.Position)
+	}
+	positions := make([]float64, 0, len(joints))
+	for _, joint := range joints {
+		pos, ok := byName[joint]
+		if !ok {
+			return nil, errors.Errorf("joint %q not found on model %q", joint, model)
+		}
+		positions = append(positions, pos)
+	}
+	return positions, nil
+}
+
+func (c *cliClient) SetVelocity(ctx context.Context, model string, linear, angular r3.Vector) error {
+	msg := fmt.Sprintf("linear: {x: %g, y: %g, z: %g}, angular: {x: %g, y: %g, z: %g}",
+		linear.X, linear.Y, linear.Z, angular.X, angular.Y, angular.Z)
+	return c.publish(ctx, fmt.Sprintf("/model/%s/cmd_vel", model), "gz.msgs.Twist", msg)
+}
+
+func (c *cliClient) Image(ctx context.Context, topic string) (image.Image, error) {
+	var msg struct {
+		Width           int    `json:"width"`
+		Height          int    `json:"height"`
+		Step            int    `json:"step"`
+		Data            string `json:"data"`
+		PixelFormatType string `json:"pixelFormatType"`
+	}
+	if err := c.echo(ctx, topic, &msg); err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(msg.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode image from %q", topic)
+	}
+	return decodeImage(msg.Width, msg.Height, msg.Step, msg.PixelFormatType, data)
+}
+
+func decodeImage(width, height, step int, format string, data []byte) (image.Image, error) {
+	var channels int
+	switch format {
+	case "RGB_INT8":
+		channels = 3
+	case "RGBA_INT8":
+		channels = 4
+	case "L_INT8":
+		channels = 1
+	default:
+		return nil, errors.Errorf("unsupported gazebo pixel format %q", format)
+	}
+	if step == 0 {
+		step = width * channels
+	}
+	if len(data) < step*height {
+		return nil, errors.Errorf("image data too short: got %d bytes for %dx%d", len(data), width, height)
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			px := data[y*step+x*channels:]
+			switch channels {
+			case 1:
+				img.SetNRGBA(x, y, color.NRGBA{px[0], px[0], px[0], 255})
+			case 3:
+				img.SetNRGBA(x, y, color.NRGBA{px[0], px[1], px[2], 255})
+			default:
+				img.SetNRGBA(x, y, color.NRGBA{px[0], px[1], px[2], px[3]})
+			}
+		}
+	}
+	return img, nil
+}
+
+type laserScan struct {
+	AngleMin          jsonFloat   `json:"angleMin"`
+	AngleStep         jsonFloat   `json:"angleStep"`
+	RangeMin          jsonFloat   `json:"rangeMin"`
+	RangeMax          jsonFloat   `json:"rangeMax"`
+	Count             int         `json:"count"`
+	VerticalAngleMin  jsonFloat   `json:"verticalAngleMin"`
+	VerticalAngleStep jsonFloat   `json:"verticalAngleStep"`
+	VerticalCount     int         `json:"verticalCount"`
+	Ranges            []jsonFloat `json:"ranges"`
+}
+
+func (c *cliClient) PointCloud(ctx context.Context, topic string) (pointcloud.PointCloud, error) {
+	var scan laserScan
+	if err := c.echo(ctx, topic, &scan); err != nil {
+		return nil, err
+	}
+	return scan.pointCloud()
+}
+
+// pointCloud converts the scan's ranges, in meters, to points in millimeters.
+func (scan laserScan) pointCloud() (pointcloud.PointCloud, error) {
+	rows := scan.VerticalCount
+	if rows == 0 {
+		rows = 1
+	}
+	if scan.Count*rows > len(scan.Ranges) {
+		return nil, errors.Errorf("scan has %d ranges but expected %d", len(scan.Ranges), scan.Count*rows)
+	}
+	pc := pointcloud.New()
+	for v := 0; v < rows; v++ {
+		vAngle := float64(scan.VerticalAngleMin) + float64(v)*float64(scan.VerticalAngleStep)
+		for h := 0; h < scan.Count; h++ {
+			r := float64(scan.Ranges[v*scan.Count+h])
+			if math.IsInf(r, 0) || math.IsNaN(r) || r < float64(scan.RangeMin) || r > float64(scan.RangeMax) {
+				continue
+			}
+			hAngle := float64(scan.AngleMin) + float64(h)*float64(scan.AngleStep)
+			pt := pointcloud.NewVector(
+				r*math.Cos(vAngle)*math.Cos(hAngle)*1000,
+				r*math.Cos(vAngle)*math.Sin(hAngle)*1000,
+				r*math.Sin(vAngle)*1000,
+			)
+			if err := pc.Set(pt, nil); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return pc, nil
+}
+
+// jsonFloat is a float that also accepts the string forms protobuf JSON uses for
+// non-finite values and 64 bit numbers.
+type jsonFloat float64
+
+func (f *jsonFloat) UnmarshalJSON(data []byte) error {
+	var num float64
+	if err := json.Unmarshal(data, &num); err == nil {
+		*f = jsonFloat(num)
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return err
+	}
+	switch str {
+	case "Infinity":
+		*f = jsonFloat(math.Inf(1))
+	case "-Infinity":
+		*f = jsonFloat(math.Inf(-1))
+	case "NaN":
+		*f = jsonFloat(math.NaN())
+	default:
+		num, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return err
+		}
+		*f = jsonFloat(num)
+	}
+	return nil
+}
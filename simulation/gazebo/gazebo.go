@@ -0,0 +1,88 @@
+// Package gazebo backs arms, bases, cameras, and lidars with a Gazebo (Ignition) simulation
+// so that full-stack behaviors can be exercised without hardware. Entities are placed in
+// the simulated world according to the robot's frame system when they are configured.
+package gazebo
+
+import (
+	"context"
+	"image"
+
+	"github.com/golang/geo/r3"
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+// Model is the model of components backed by a Gazebo simulation.
+var Model = resource.DefaultModelFamily.WithModel("gazebo")
+
+// A Client talks to a running Gazebo simulation. Distances are in meters and angles are
+// in radians, as in Gazebo.
+type Client interface {
+	// SetPose moves an entity to the given pose relative to the world.
+	SetPose(ctx context.Context, entity string, pose spatialmath.Pose) error
+	// Pose returns the pose of an entity relative to the world.
+	Pose(ctx context.Context, entity string) (spatialmath.Pose, error)
+	// SetJointPositions commands the named joints of a model to the given positions.
+	SetJointPositions(ctx context.Context, model string, joints []string, positions []float64) error
+	// JointPositions returns the positions of the named joints of a model.
+	JointPositions(ctx context.Context, model string, joints []string) ([]float64, error)
+	// SetVelocity commands the linear (m/s) and angular (rad/s) velocity of a model.
+	SetVelocity(ctx context.Context, model string, linear, angular r3.Vector) error
+	// Image returns the latest image published on a camera topic.
+	Image(ctx context.Context, topic string) (image.Image, error)
+	// PointCloud returns the latest scan published on a lidar topic, in millimeters.
+	PointCloud(ctx context.Context, topic string) (pointcloud.PointCloud, error)
+	// Close stops the client talking to the simulation.
+	Close() error
+}
+
+// SimConfig describes where a component lives in the simulation.
+type SimConfig struct {
+	// World is the name of the Gazebo world.
+	World string `json:"world"`
+	// Entity is the name of the Gazebo model backing the component. Defaults to the
+	// component's name.
+	Entity string `json:"entity,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (cfg *SimConfig) Validate(path string) error {
+	if cfg.World == "" {
+		return resource.NewConfigValidationFieldRequiredError(path, "world")
+	}
+	return nil
+}
+
+func (cfg *SimConfig) entity(conf resource.Config) string {
+	if cfg.Entity != "" {
+		return cfg.Entity
+	}
+	return conf.Name
+}
+
+// placeFromFrame moves the entity to the pose given by the component's frame when that
+// frame is relative to the world.
+func placeFromFrame(ctx context.Context, client Client, entity string, conf resource.Config) error {
+	if conf.Frame == nil || (conf.Frame.Parent != "" && conf.Frame.Parent != referenceframe.World) {
+		return nil
+	}
+	pose, err := conf.Frame.Pose()
+	if err != nil {
+		return err
+	}
+	return errors.Wrapf(client.SetPose(ctx, entity, toMeters(pose)), "failed to place %q in simulation", entity)
+}
+
+// toMeters converts a pose in millimeters to one in meters.
+func toMeters(pose spatialmath.Pose) spatialmath.Pose {
+	return spatialmath.NewPose(pose.Point().Mul(0.001), pose.Orientation())
+}
+
+// toMillimeters converts a pose in meters to one in millimeters.
+func toMillimeters(pose spatialmath.Pose) spatialmath.Pose {
+	return spatialmath.NewPose(pose.Point().Mul(1000), pose.Orientation())
+}
@@ -0,0 +1,321 @@
+package gazebo
+
+import (
+	"context"
+	"errors"
+	"image"
+	"io"
+	"math"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/geo/r3"
+	pb "go.viam.com/api/component/arm/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/referenceframe"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+type fakeClient struct {
+	mu       sync.Mutex
+	poses    map[string]spatialmath.Pose
+	joints   map[string]float64
+	linear   r3.Vector
+	angular  r3.Vector
+	setCalls int
+	// stuck keeps commanded joints from moving.
+	stuck  bool
+	closed bool
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{poses: map[string]spatialmath.Pose{}, joints: map[string]float64{}}
+}
+
+func (c *fakeClient) SetPose(ctx context.Context, entity string, pose spatialmath.Pose) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.poses[entity] = pose
+	return nil
+}
+
+func (c *fakeClient) Pose(ctx context.Context, entity string) (spatialmath.Pose, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.poses[entity], nil
+}
+
+func (c *fakeClient) SetJointPositions(ctx context.Context, model string, joints []string, positions []float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stuck {
+		return nil
+	}
+	for i, joint := range joints {
+		c.joints[joint] = positions[i]
+	}
+	return nil
+}
+
+func (c *fakeClient) setStuck(stuck bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stuck = stuck
+}
+
+func (c *fakeClient) JointPositions(ctx context.Context, model string, joints []string) ([]float64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	positions := make([]float64, 0, len(joints))
+	for _, joint := range joints {
+		positions = append(positions, c.joints[joint])
+	}
+	return positions, nil
+}
+
+func (c *fakeClient) SetVelocity(ctx context.Context, model string, linear, angular r3.Vector) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.linear, c.angular = linear, angular
+	c.setCalls++
+	return nil
+}
+
+func (c *fakeClient) velocity() (r3.Vector, r3.Vector, int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.linear, c.angular, c.setCalls
+}
+
+func (c *fakeClient) Image(ctx context.Context, topic string) (image.Image, error) {
+	return image.NewNRGBA(image.Rect(0, 0, 1, 1)), nil
+}
+
+func (c *fakeClient) PointCloud(ctx context.Context, topic string) (pointcloud.PointCloud, error) {
+	return pointcloud.New(), nil
+}
+
+func (c *fakeClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func TestCLIClient(t *testing.T) {
+	ctx := context.Background()
+	var lastArgs []string
+	outputs := map[string]string{}
+	var echoes int
+	client := newCLIClient("w",
+		func(ctx context.Context, args ...string) ([]byte, error) {
+			lastArgs = args
+			return nil, nil
+		},
+		func(ctx context.Context, args ...string) (io.ReadCloser, error) {
+			echoes++
+			for suffix, out := range outputs {
+				if strings.HasSuffix(strings.Join(args, " "), suffix) {
+					return io.NopCloser(strings.NewReader(strings.ReplaceAll(out, "\n", "") + "\n")), nil
+				}
+			}
+			return nil, errors.New("no such topic")
+		},
+	)
+	defer func() {
+		test.That(t, client.Close(), test.ShouldBeNil)
+	}()
+
+	t.Run("set pose", func(t *testing.T) {
+		err := client.SetPose(ctx, "box", spatialmath.NewPoseFromPoint(r3.Vector{X: 1}))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, lastArgs[2], test.ShouldEqual, "/world/w/set_pose")
+		test.That(t, lastArgs[len(lastArgs)-1], test.ShouldContainSubstring, `name: "box", position: {x: 1, y: 0, z: 0}`)
+	})
+
+	t.Run("pose", func(t *testing.T) {
+		outputs["-t /world/w/pose/info"] = `{"pose": [{"name": "other"}, {"name": "box", "position": {"x": 1, "z": 2}}]}`
+		pose, err := client.Pose(ctx, "box")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, spatialmath.PoseAlmostEqual(pose, spatialmath.NewPoseFromPoint(r3.Vector{X: 1, Z: 2})), test.ShouldBeTrue)
+
+		_, err = client.Pose(ctx, "missing")
+		test.That(t, err, test.ShouldNotBeNil)
+	})
+
+	t.Run("reads share a gz process", func(t *testing.T) {
+		stream, echoed := io.Pipe()
+		before := echoes
+		client := newCLIClient("w", nil, func(ctx context.Context, args ...string) (io.ReadCloser, error) {
+			echoes++
+			context.AfterFunc(ctx, func() { stream.Close() })
+			return stream, nil
+		})
+		defer func() {
+			test.That(t, client.Close(), test.ShouldBeNil)
+		}()
+		// gz starts echoing on the first read.
+		written := make(chan error, 1)
+		go func() {
+			_, err := echoed.Write([]byte(`{"pose": [{"name": "box", "position": {"x": 1}}]}` + "\n"))
+			written <- err
+		}()
+		pose, err := client.Pose(ctx, "box")
+		test.That(t, <-written, test.ShouldBeNil)
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pose.Point().X, test.ShouldEqual, 1)
+
+		_, err = echoed.Write([]byte(`{"pose": [{"name": "box", "position": {"x": 2}}]}` + "\n"))
+		test.That(t, err, test.ShouldBeNil)
+		testutils.WaitForAssertion(t, func(tb testing.TB) {
+			tb.Helper()
+			pose, err := client.Pose(ctx, "box")
+			test.That(tb, err, test.ShouldBeNil)
+			test.That(tb, pose.Point().X, test.ShouldEqual, 2)
+		})
+		test.That(t, echoes-before, test.ShouldEqual, 1)
+	})
+
+	t.Run("joints", func(t *testing.T) {
+		test.That(t, client.SetJointPositions(ctx, "arm", []string{"j1"}, []float64{0.5}), test.ShouldBeNil)
+		test.That(t, lastArgs, test.ShouldResemble,
+			[]string{"topic", "-t", "/model/arm/joint/j1/0/cmd_pos", "-m", "gz.msgs.Double", "-p", "data: 0.5"})
+
+		outputs["-t /world/w/model/arm/joint_state"] = `{"joint": [{"name": "j2", "axis1": {"position": 1}}, {"name": "j1", "axis1": {}}]}`
+		positions, err := client.JointPositions(ctx, "arm", []string{"j1", "j2"})
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, positions, test.ShouldResemble, []float64{0, 1})
+	})
+
+	t.Run("image", func(t *testing.T) {
+		// a 2x1 image with a red and a blue pixel
+		outputs["-t /camera"] = `{"width": 2, "height": 1, "data": "/wAAAAD/", "pixelFormatType": "RGB_INT8"}`
+		img, err := client.Image(ctx, "/camera")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, img.Bounds().Dx(), test.ShouldEqual, 2)
+		r, _, b, _ := img.At(1, 0).RGBA()
+		test.That(t, r, test.ShouldEqual, 0)
+		test.That(t, b, test.ShouldEqual, 0xffff)
+	})
+
+	t.Run("point cloud", func(t *testing.T) {
+		outputs["-t /lidar"] = `{"angleMin": 0, "angleStep": 1.5707963267948966, "rangeMin": 0.1, "rangeMax": 10,
+			"count": 3, "ranges": [1, "Infinity", 0.05]}`
+		pc, err := client.PointCloud(ctx, "/lidar")
+		test.That(t, err, test.ShouldBeNil)
+		test.That(t, pc.Size(), test.ShouldEqual, 1)
+		_, ok := pc.At(1000, 0, 0)
+		test.That(t, ok, test.ShouldBeTrue)
+	})
+}
+
+func TestArm(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	client := newFakeClient()
+
+	armConf := &ArmConfig{SimConfig: SimConfig{World: "w", Entity: "sim_arm"}, Joints: []string{"j1"}}
+	conf := resource.Config{
+		Name: "arm1",
+		Frame: &referenceframe.LinkConfig{
+			Parent:      referenceframe.World,
+			Translation: r3.Vector{X: 1000},
+		},
+		ConvertedAttributes: armConf,
+	}
+	a, err := newArm(ctx, nil, conf, armConf, client, logger)
+	test.That(t, err, test.ShouldBeNil)
+	pose, err := client.Pose(ctx, "sim_arm")
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, pose.Point(), test.ShouldResemble, r3.Vector{X: 1})
+
+	test.That(t, a.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{90}}, nil), test.ShouldBeNil)
+	test.That(t, client.joints["j1"], test.ShouldAlmostEqual, math.Pi/2)
+	joints, err := a.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, joints.Values[0], test.ShouldAlmostEqual, 90)
+	moving, err := a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	// the arm moves until its joints reach their goal, or it is stopped.
+	client.setStuck(true)
+	moved := make(chan error, 1)
+	go func() {
+		moved <- a.MoveToJointPositions(ctx, &pb.JointPositions{Values: []float64{45}}, nil)
+	}()
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		tb.Helper()
+		moving, err := a.IsMoving(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, moving, test.ShouldBeTrue)
+	})
+	test.That(t, len(moved), test.ShouldEqual, 0)
+	client.setStuck(false)
+	test.That(t, a.Stop(ctx, nil), test.ShouldBeNil)
+	test.That(t, <-moved, test.ShouldNotBeNil)
+	moving, err = a.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+	test.That(t, client.joints["j1"], test.ShouldAlmostEqual, math.Pi/2)
+
+	test.That(t, a.Close(ctx), test.ShouldBeNil)
+	test.That(t, client.closed, test.ShouldBeTrue)
+
+	armConf = &ArmConfig{SimConfig: SimConfig{World: "w"}, Joints: []string{"j1", "j2"}}
+	conf.ConvertedAttributes = armConf
+	_, err = newArm(ctx, nil, conf, armConf, newFakeClient(), logger)
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "degrees of freedom")
+}
+
+func TestBaseMoveStraight(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	client := newFakeClient()
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	baseConf := &BaseConfig{SimConfig: SimConfig{World: "w"}}
+	b, err := newBase(ctx, resource.Config{Name: "base1", ConvertedAttributes: baseConf}, baseConf, client, logger)
+	test.That(t, err, test.ShouldBeNil)
+
+	// 1m backwards at 0.5m/s takes 2 seconds of robot time
+	done := make(chan error, 1)
+	go func() {
+		done <- b.MoveStraight(ctx, -1000, 500, nil)
+	}()
+	finished := func() bool {
+		_, _, calls := client.velocity()
+		return calls == 2 && len(done) == 1
+	}
+	test.That(t, virtual.StepUntil(100*time.Millisecond, 10*time.Second, func() bool {
+		linear, _, calls := client.velocity()
+		return calls == 1 && linear == r3.Vector{X: -0.5}
+	}), test.ShouldBeTrue)
+	test.That(t, virtual.StepUntil(100*time.Millisecond, 10*time.Second, finished), test.ShouldBeTrue)
+	test.That(t, <-done, test.ShouldBeNil)
+	linear, angular, _ := client.velocity()
+	test.That(t, linear, test.ShouldResemble, r3.Vector{})
+	test.That(t, angular, test.ShouldResemble, r3.Vector{})
+
+	moving, err := b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeFalse)
+
+	test.That(t, b.SetVelocity(ctx, r3.Vector{Y: 100}, r3.Vector{Z: 180}, nil), test.ShouldBeNil)
+	linear, angular, _ = client.velocity()
+	test.That(t, linear, test.ShouldResemble, r3.Vector{X: 0.1})
+	test.That(t, angular.Z, test.ShouldAlmostEqual, math.Pi)
+	moving, err = b.IsMoving(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, moving, test.ShouldBeTrue)
+}
@@ -0,0 +1,140 @@
+// Package imperfection simulates the imperfections of real hardware in fake components:
+// response latency, Gaussian noise, drift, and dropouts. They are configured through the
+// imperfections attribute of fake models, so that behavior developed against fakes transfers
+// more realistically to real hardware:
+//
+//	imperfections:
+//	  latency_ms: 20
+//	  latency_jitter_ms: 5
+//	  noise_stddev: 0.5
+//	  drift_per_sec: 0.01
+//	  dropout_probability: 0.05
+//
+// Noise and drift are in the units of the values they are applied to, which each fake model
+// documents.
+package imperfection
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/resource"
+)
+
+// ErrDropout is returned by calls to a fake component that are simulated to drop out.
+var ErrDropout = errors.New("simulated dropout")
+
+// Config is the imperfections attribute of fake models.
+type Config struct {
+	// LatencyMs is the mean time every call takes to respond.
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	// LatencyJitterMs is the standard deviation of the response time.
+	LatencyJitterMs float64 `json:"latency_jitter_ms,omitempty"`
+	// NoiseStdDev is the standard deviation of the Gaussian noise added to every value.
+	NoiseStdDev float64 `json:"noise_stddev,omitempty"`
+	// DriftPerSec is the offset added to every value per second since the component was
+	// configured.
+	DriftPerSec float64 `json:"drift_per_sec,omitempty"`
+	// DropoutProbability is the probability, from 0 to 1, that a call fails with ErrDropout.
+	DropoutProbability float64 `json:"dropout_probability,omitempty"`
+	// Seed seeds the random number generator so that runs are reproducible. If zero, a
+	// random seed is used.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *Config) Validate(path string) error {
+	switch {
+	case conf.LatencyMs < 0:
+		return resource.NewConfigValidationError(path, errors.New("latency_ms cannot be negative"))
+	case conf.LatencyJitterMs < 0:
+		return resource.NewConfigValidationError(path, errors.New("latency_jitter_ms cannot be negative"))
+	case conf.NoiseStdDev < 0:
+		return resource.NewConfigValidationError(path, errors.New("noise_stddev cannot be negative"))
+	case conf.DropoutProbability < 0 || conf.DropoutProbability > 1:
+		return resource.NewConfigValidationError(path, errors.New("dropout_probability must be between 0 and 1"))
+	}
+	return nil
+}
+
+// An Injector injects the configured imperfections into the calls and values of a fake
+// component. A nil Injector injects nothing.
+type Injector struct {
+	conf  Config
+	start time.Time
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewInjector returns an Injector for the given config. A nil config has no imperfections
+// and returns a nil Injector.
+func (conf *Config) NewInjector() *Injector {
+	if conf == nil {
+		return nil
+	}
+	seed := conf.Seed
+	if seed == 0 {
+		//nolint:gosec
+		seed = rand.Int63()
+	}
+	return &Injector{
+		conf:  *conf,
+		start: clock.Now(),
+		//nolint:gosec
+		rand: rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Call simulates the latency of a call and whether it drops out. It returns ErrDropout if
+// the call drops out, or the context's error if it is done before the call responds.
+func (inj *Injector) Call(ctx context.Context) error {
+	if inj == nil {
+		return nil
+	}
+	inj.mu.Lock()
+	latency := inj.conf.LatencyMs + inj.rand.NormFloat64()*inj.conf.LatencyJitterMs
+	dropout := inj.rand.Float64() < inj.conf.DropoutProbability
+	inj.mu.Unlock()
+
+	if latency > 0 && !clock.SleepContext(ctx, time.Duration(latency*float64(time.Millisecond))) {
+		return ctx.Err()
+	}
+	if dropout {
+		return ErrDropout
+	}
+	return nil
+}
+
+// Value returns the value with the configured noise and drift added.
+func (inj *Injector) Value(value float64) float64 {
+	if inj == nil {
+		return value
+	}
+	drift := inj.conf.DriftPerSec * clock.Since(inj.start).Seconds()
+	inj.mu.Lock()
+	defer inj.mu.Unlock()
+	return value + drift + inj.rand.NormFloat64()*inj.conf.NoiseStdDev
+}
+
+// Values returns a copy of the values with the configured noise and drift added to each.
+func (inj *Injector) Values(values []float64) []float64 {
+	if values == nil {
+		return nil
+	}
+	out := make([]float64, len(values))
+	for idx, value := range values {
+		out[idx] = inj.Value(value)
+	}
+	return out
+}
+
+// Perturbs returns whether the Injector changes values.
+func (inj *Injector) Perturbs() bool {
+	return inj != nil && (inj.conf.NoiseStdDev != 0 || inj.conf.DriftPerSec != 0)
+}
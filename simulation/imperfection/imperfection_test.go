@@ -0,0 +1,104 @@
+package imperfection
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/clock"
+)
+
+func TestValidate(t *testing.T) {
+	valid := Config{LatencyMs: 10, LatencyJitterMs: 2, NoiseStdDev: 0.5, DriftPerSec: -0.1, DropoutProbability: 0.2}
+	test.That(t, valid.Validate("path"), test.ShouldBeNil)
+
+	for _, tc := range []struct {
+		conf   Config
+		errMsg string
+	}{
+		{Config{LatencyMs: -1}, "latency_ms"},
+		{Config{LatencyJitterMs: -1}, "latency_jitter_ms"},
+		{Config{NoiseStdDev: -1}, "noise_stddev"},
+		{Config{DropoutProbability: 1.5}, "dropout_probability"},
+	} {
+		err := tc.conf.Validate("path")
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.errMsg)
+	}
+}
+
+func TestInjector(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("nil", func(t *testing.T) {
+		var conf *Config
+		inj := conf.NewInjector()
+		test.That(t, inj, test.ShouldBeNil)
+		test.That(t, inj.Call(ctx), test.ShouldBeNil)
+		test.That(t, inj.Value(1.5), test.ShouldEqual, 1.5)
+		test.That(t, inj.Values([]float64{1, 2}), test.ShouldResemble, []float64{1, 2})
+		test.That(t, inj.Perturbs(), test.ShouldBeFalse)
+	})
+
+	t.Run("noise", func(t *testing.T) {
+		inj := (&Config{NoiseStdDev: 2, Seed: 1}).NewInjector()
+		test.That(t, inj.Perturbs(), test.ShouldBeTrue)
+		var sum, sumSq float64
+		const n = 10000
+		for i := 0; i < n; i++ {
+			v := inj.Value(10) - 10
+			sum += v
+			sumSq += v * v
+		}
+		mean := sum / n
+		test.That(t, mean, test.ShouldAlmostEqual, 0, 0.1)
+		test.That(t, math.Sqrt(sumSq/n-mean*mean), test.ShouldAlmostEqual, 2, 0.1)
+
+		// the same seed gives the same values.
+		a := (&Config{NoiseStdDev: 2, Seed: 7}).NewInjector()
+		b := (&Config{NoiseStdDev: 2, Seed: 7}).NewInjector()
+		test.That(t, a.Values([]float64{1, 2, 3}), test.ShouldResemble, b.Values([]float64{1, 2, 3}))
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		virtual := clock.NewVirtual(time.Now())
+		defer clock.Set(virtual)()
+		inj := (&Config{DriftPerSec: 0.5}).NewInjector()
+		test.That(t, inj.Value(1), test.ShouldEqual, 1)
+		virtual.Step(10 * time.Second)
+		test.That(t, inj.Value(1), test.ShouldEqual, 6)
+	})
+
+	t.Run("latency", func(t *testing.T) {
+		virtual := clock.NewVirtual(time.Now())
+		defer clock.Set(virtual)()
+		inj := (&Config{LatencyMs: 500}).NewInjector()
+		done := make(chan error, 1)
+		go func() {
+			done <- inj.Call(ctx)
+		}()
+		test.That(t, virtual.StepUntil(100*time.Millisecond, time.Second, func() bool { return len(done) == 1 }), test.ShouldBeTrue)
+		test.That(t, <-done, test.ShouldBeNil)
+
+		canceled, cancel := context.WithCancel(ctx)
+		cancel()
+		test.That(t, inj.Call(canceled), test.ShouldBeError, context.Canceled)
+	})
+
+	t.Run("dropout", func(t *testing.T) {
+		test.That(t, (&Config{DropoutProbability: 1}).NewInjector().Call(ctx), test.ShouldBeError, ErrDropout)
+		test.That(t, (&Config{DropoutProbability: 0}).NewInjector().Call(ctx), test.ShouldBeNil)
+
+		inj := (&Config{DropoutProbability: 0.3, Seed: 1}).NewInjector()
+		var dropped int
+		for i := 0; i < 1000; i++ {
+			if inj.Call(ctx) != nil {
+				dropped++
+			}
+		}
+		test.That(t, dropped, test.ShouldBeBetween, 250, 350)
+	})
+}
@@ -0,0 +1,343 @@
+// Package script implements a small declarative scenario format that scripts the state
+// fake components report over time, such as sensor readings, arm joint trajectories, and
+// base motion. Scenarios are written in YAML or JSON and loaded into fake models through
+// their config, so tests do not need a hand-written fake for every case.
+//
+// A scenario is a list of keyframes. Between keyframes, numeric values are interpolated
+// linearly unless step interpolation is chosen:
+//
+//	loop: true
+//	keyframes:
+//	  - at_sec: 0
+//	    readings: {temperature: 20, status: ok}
+//	    joints_deg: [0, 0, 0]
+//	  - at_sec: 10
+//	    readings: {temperature: 30, status: hot}
+//	    joints_deg: [90, 45, 0]
+package script
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/resource"
+)
+
+// Interpolation is how values change between keyframes.
+type Interpolation string
+
+const (
+	// InterpolationLinear changes numeric values linearly from one keyframe to the next.
+	InterpolationLinear Interpolation = "linear"
+	// InterpolationStep holds each keyframe's values until the next keyframe.
+	InterpolationStep Interpolation = "step"
+)
+
+// Position is the position of a base in the plane it started in.
+type Position struct {
+	XMm      float64 `json:"x_mm"`
+	YMm      float64 `json:"y_mm"`
+	ThetaDeg float64 `json:"theta_deg"`
+}
+
+// A Keyframe is the state of a component at a point in a scenario. Fields that are not
+// set are not scripted.
+type Keyframe struct {
+	// AtSec is the time of the keyframe since the scenario started.
+	AtSec float64 `json:"at_sec"`
+	// Readings are the readings of a sensor. Readings missing from a keyframe hold their
+	// previous value.
+	Readings map[string]interface{} `json:"readings,omitempty"`
+	// JointsDeg are the joint positions of an arm.
+	JointsDeg []float64 `json:"joints_deg,omitempty"`
+	// Position is the position of a base.
+	Position *Position `json:"position,omitempty"`
+}
+
+// A Script is a scenario for a fake component.
+type Script struct {
+	// Keyframes are the states of the component, in increasing time order.
+	Keyframes []Keyframe `json:"keyframes"`
+	// Interpolation is how values change between keyframes. Defaults to linear.
+	Interpolation Interpolation `json:"interpolation,omitempty"`
+	// Loop restarts the scenario once the last keyframe is reached. Otherwise the last
+	// keyframe's state is held.
+	Loop bool `json:"loop,omitempty"`
+}
+
+// Parse parses a scenario written in YAML or JSON.
+func Parse(data []byte) (*Script, error) {
+	// decode generically first so that the json field names apply to both formats.
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Wrap(err, "failed to parse scenario")
+	}
+	asJSON, err := json.Marshal(raw)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse scenario")
+	}
+	var s Script
+	if err := json.Unmarshal(asJSON, &s); err != nil {
+		return nil, errors.Wrap(err, "failed to parse scenario")
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Load reads a scenario from a YAML or JSON file.
+func Load(path string) (*Script, error) {
+	//nolint:gosec
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	s, err := Parse(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "scenario %q", filepath.Base(path))
+	}
+	return s, nil
+}
+
+// Validate ensures all parts of the scenario are valid.
+func (s *Script) Validate() error {
+	if len(s.Keyframes) == 0 {
+		return errors.New("scenario must have at least one keyframe")
+	}
+	switch s.Interpolation {
+	case "", InterpolationLinear, InterpolationStep:
+	default:
+		return errors.Errorf("unknown interpolation %q", s.Interpolation)
+	}
+	first := s.Keyframes[0]
+	for idx, kf := range s.Keyframes {
+		if kf.AtSec < 0 {
+			return errors.Errorf("keyframe %d: at_sec cannot be negative", idx)
+		}
+		if idx > 0 && kf.AtSec <= s.Keyframes[idx-1].AtSec {
+			return errors.Errorf("keyframe %d: at_sec must be greater than the previous keyframe's", idx)
+		}
+		// joints and positions are interpolated pairwise so every keyframe must set them alike.
+		if (kf.JointsDeg == nil) != (first.JointsDeg == nil) || len(kf.JointsDeg) != len(first.JointsDeg) {
+			return errors.Errorf("keyframe %d: every keyframe must set the same number of joints_deg", idx)
+		}
+		if (kf.Position == nil) != (first.Position == nil) {
+			return errors.Errorf("keyframe %d: either every keyframe or none must set position", idx)
+		}
+	}
+	return nil
+}
+
+// NumJoints returns the number of joints the scenario scripts, if any.
+func (s *Script) NumJoints() int {
+	return len(s.Keyframes[0].JointsDeg)
+}
+
+// State is the state of a component at a point in a scenario.
+type State struct {
+	Readings  map[string]interface{}
+	JointsDeg []float64
+	Position  *Position
+	// Moving is whether the joints or position are changing.
+	Moving bool
+}
+
+// At returns the state after the given time has elapsed since the scenario started.
+func (s *Script) At(elapsed time.Duration) State {
+	t := elapsed.Seconds()
+	last := s.Keyframes[len(s.Keyframes)-1]
+	if s.Loop && last.AtSec > 0 {
+		t = math.Mod(t, last.AtSec)
+	}
+
+	// prev is the last keyframe at or before t; next is the one after it, if any.
+	prev := 0
+	for prev+1 < len(s.Keyframes) && s.Keyframes[prev+1].AtSec <= t {
+		prev++
+	}
+	interpolate := s.Interpolation != InterpolationStep && t >= s.Keyframes[0].AtSec
+	next := -1
+	var frac float64
+	if interpolate && prev+1 < len(s.Keyframes) {
+		next = prev + 1
+		frac = (t - s.Keyframes[prev].AtSec) / (s.Keyframes[next].AtSec - s.Keyframes[prev].AtSec)
+	}
+
+	state := State{Readings: s.readingsAt(t, prev, interpolate)}
+	from := s.Keyframes[prev]
+	if from.JointsDeg != nil {
+		state.JointsDeg = append([]float64{}, from.JointsDeg...)
+	}
+	if from.Position != nil {
+		pos := *from.Position
+		state.Position = &pos
+	}
+	if next == -1 {
+		return state
+	}
+
+	to := s.Keyframes[next]
+	for idx := range state.JointsDeg {
+		if to.JointsDeg[idx] != from.JointsDeg[idx] {
+			state.Moving = true
+		}
+		state.JointsDeg[idx] = lerp(from.JointsDeg[idx], to.JointsDeg[idx], frac)
+	}
+	if state.Position != nil {
+		if *to.Position != *from.Position {
+			state.Moving = true
+		}
+		state.Position = &Position{
+			XMm:      lerp(from.Position.XMm, to.Position.XMm, frac),
+			YMm:      lerp(from.Position.YMm, to.Position.YMm, frac),
+			ThetaDeg: lerp(from.Position.ThetaDeg, to.Position.ThetaDeg, frac),
+		}
+	}
+	return state
+}
+
+// readingsAt returns the readings at time t, where prev is the index of the last keyframe
+// at or before t.
+func (s *Script) readingsAt(t float64, prev int, interpolate bool) map[string]interface{} {
+	readings := map[string]interface{}{}
+	for idx := 0; idx <= prev; idx++ {
+		for key, value := range s.Keyframes[idx].Readings {
+			readings[key] = value
+			if !interpolate {
+				continue
+			}
+			// interpolate from the last keyframe that sets the reading towards the next one.
+			from, ok := toFloat(value)
+			if !ok || (idx < prev && s.setsReadingBetween(key, idx+1, prev)) {
+				continue
+			}
+			for next := prev + 1; next < len(s.Keyframes); next++ {
+				nextValue, ok := s.Keyframes[next].Readings[key]
+				if !ok {
+					continue
+				}
+				if to, ok := toFloat(nextValue); ok {
+					at := s.Keyframes[idx].AtSec
+					readings[key] = lerp(from, to, (t-at)/(s.Keyframes[next].AtSec-at))
+				}
+				break
+			}
+		}
+	}
+	return readings
+}
+
+func (s *Script) setsReadingBetween(key string, from, to int) bool {
+	for idx := from; idx <= to; idx++ {
+		if _, ok := s.Keyframes[idx].Readings[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func lerp(from, to, frac float64) float64 {
+	return from + (to-from)*frac
+}
+
+// A Player plays a scenario on the robot clock, starting when it is created.
+type Player struct {
+	script *Script
+
+	mu    sync.Mutex
+	start time.Time
+}
+
+// NewPlayer returns a Player for the given scenario.
+func NewPlayer(s *Script) *Player {
+	return &Player{script: s, start: clock.Now()}
+}
+
+// Script returns the scenario being played.
+func (p *Player) Script() *Script {
+	return p.script
+}
+
+// Restart plays the scenario from the beginning.
+func (p *Player) Restart() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.start = clock.Now()
+}
+
+// State returns the current state of the scenario.
+func (p *Player) State() State {
+	p.mu.Lock()
+	start := p.start
+	p.mu.Unlock()
+	return p.script.At(clock.Since(start))
+}
+
+// Config is the scenario attribute of fake models: either the path to a scenario file or a
+// scenario written inline.
+type Config struct {
+	// File is the path to a YAML or JSON scenario. If set, no other field may be.
+	File          string        `json:"file,omitempty"`
+	Keyframes     []Keyframe    `json:"keyframes,omitempty"`
+	Interpolation Interpolation `json:"interpolation,omitempty"`
+	Loop          bool          `json:"loop,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid. Scenario files are only checked
+// when loaded.
+func (conf *Config) Validate(path string) error {
+	if conf.File != "" {
+		if len(conf.Keyframes) != 0 || conf.Interpolation != "" || conf.Loop {
+			return resource.NewConfigValidationError(path, errors.New("a scenario cannot set both file and keyframes"))
+		}
+		return nil
+	}
+	if err := conf.inline().Validate(); err != nil {
+		return resource.NewConfigValidationError(path, err)
+	}
+	return nil
+}
+
+func (conf *Config) inline() *Script {
+	return &Script{Keyframes: conf.Keyframes, Interpolation: conf.Interpolation, Loop: conf.Loop}
+}
+
+// NewPlayer loads the configured scenario and returns a Player for it. A nil config has no
+// scenario and returns a nil Player.
+func (conf *Config) NewPlayer() (*Player, error) {
+	if conf == nil {
+		return nil, nil
+	}
+	if conf.File != "" {
+		s, err := Load(conf.File)
+		if err != nil {
+			return nil, err
+		}
+		return NewPlayer(s), nil
+	}
+	s := conf.inline()
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return NewPlayer(s), nil
+}
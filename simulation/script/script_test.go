@@ -0,0 +1,131 @@
+package script
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/clock"
+)
+
+const yamlScenario = `
+loop: true
+keyframes:
+  - at_sec: 0
+    readings: {temperature: 20, status: ok}
+    joints_deg: [0, 10]
+  - at_sec: 10
+    readings: {status: hot}
+    joints_deg: [90, 10]
+  - at_sec: 20
+    readings: {temperature: 40}
+    joints_deg: [90, 10]
+`
+
+func TestParse(t *testing.T) {
+	s, err := Parse([]byte(yamlScenario))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, s.Loop, test.ShouldBeTrue)
+	test.That(t, s.Keyframes, test.ShouldHaveLength, 3)
+	test.That(t, s.NumJoints(), test.ShouldEqual, 2)
+
+	fromJSON, err := Parse([]byte(`{"keyframes": [{"at_sec": 0, "position": {"x_mm": 1, "y_mm": 2, "theta_deg": 3}}]}`))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, fromJSON.Keyframes[0].Position, test.ShouldResemble, &Position{XMm: 1, YMm: 2, ThetaDeg: 3})
+
+	path := filepath.Join(t.TempDir(), "scenario.yaml")
+	test.That(t, os.WriteFile(path, []byte(yamlScenario), 0o600), test.ShouldBeNil)
+	loaded, err := Load(path)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, loaded, test.ShouldResemble, s)
+
+	for _, tc := range []struct {
+		scenario string
+		err      string
+	}{
+		{`keyframes: []`, "at least one keyframe"},
+		{`{interpolation: cubic, keyframes: [{at_sec: 0}]}`, "unknown interpolation"},
+		{`keyframes: [{at_sec: 1}, {at_sec: 1}]`, "must be greater"},
+		{`keyframes: [{at_sec: -1}]`, "cannot be negative"},
+		{`keyframes: [{at_sec: 0, joints_deg: [1]}, {at_sec: 1, joints_deg: [1, 2]}]`, "joints_deg"},
+		{`keyframes: [{at_sec: 0, position: {x_mm: 1}}, {at_sec: 1}]`, "position"},
+		{`keyframes: [{at_sec: zero}]`, "failed to parse"},
+	} {
+		_, err := Parse([]byte(tc.scenario))
+		test.That(t, err, test.ShouldNotBeNil)
+		test.That(t, err.Error(), test.ShouldContainSubstring, tc.err)
+	}
+}
+
+func TestAt(t *testing.T) {
+	s, err := Parse([]byte(yamlScenario))
+	test.That(t, err, test.ShouldBeNil)
+
+	state := s.At(5 * time.Second)
+	test.That(t, state.Readings, test.ShouldResemble, map[string]interface{}{"temperature": 25.0, "status": "ok"})
+	test.That(t, state.JointsDeg, test.ShouldResemble, []float64{45, 10})
+	test.That(t, state.Moving, test.ShouldBeTrue)
+
+	// temperature is interpolated between the keyframes that set it.
+	state = s.At(15 * time.Second)
+	test.That(t, state.Readings, test.ShouldResemble, map[string]interface{}{"temperature": 35.0, "status": "hot"})
+	test.That(t, state.JointsDeg, test.ShouldResemble, []float64{90, 10})
+	test.That(t, state.Moving, test.ShouldBeFalse)
+
+	// the scenario loops once the last keyframe is reached.
+	state = s.At(25 * time.Second)
+	test.That(t, state.JointsDeg, test.ShouldResemble, []float64{45, 10})
+
+	s.Loop = false
+	state = s.At(25 * time.Second)
+	test.That(t, state.Readings, test.ShouldResemble, map[string]interface{}{"temperature": 40.0, "status": "hot"})
+	test.That(t, state.JointsDeg, test.ShouldResemble, []float64{90, 10})
+
+	s.Interpolation = InterpolationStep
+	state = s.At(5 * time.Second)
+	test.That(t, state.Readings, test.ShouldResemble, map[string]interface{}{"temperature": 20.0, "status": "ok"})
+	test.That(t, state.JointsDeg, test.ShouldResemble, []float64{0, 10})
+	test.That(t, state.Moving, test.ShouldBeFalse)
+}
+
+func TestPlayer(t *testing.T) {
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	conf := &Config{Keyframes: []Keyframe{
+		{AtSec: 0, Position: &Position{}},
+		{AtSec: 2, Position: &Position{XMm: 1000, ThetaDeg: 90}},
+	}}
+	test.That(t, conf.Validate("path"), test.ShouldBeNil)
+	player, err := conf.NewPlayer()
+	test.That(t, err, test.ShouldBeNil)
+
+	virtual.Step(time.Second)
+	state := player.State()
+	test.That(t, state.Position, test.ShouldResemble, &Position{XMm: 500, ThetaDeg: 45})
+	test.That(t, state.Moving, test.ShouldBeTrue)
+
+	virtual.Step(time.Second)
+	state = player.State()
+	test.That(t, state.Position, test.ShouldResemble, &Position{XMm: 1000, ThetaDeg: 90})
+	test.That(t, state.Moving, test.ShouldBeFalse)
+
+	player.Restart()
+	test.That(t, player.State().Position, test.ShouldResemble, &Position{})
+
+	var none *Config
+	player, err = none.NewPlayer()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, player, test.ShouldBeNil)
+
+	conf = &Config{File: "scenario.yaml", Loop: true}
+	err = conf.Validate("path")
+	test.That(t, err, test.ShouldNotBeNil)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "both file and keyframes")
+
+	_, err = (&Config{File: filepath.Join(t.TempDir(), "missing.yaml")}).NewPlayer()
+	test.That(t, err, test.ShouldNotBeNil)
+}
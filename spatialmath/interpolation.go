@@ -0,0 +1,197 @@
+package spatialmath
+
+import (
+	"errors"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/r3"
+)
+
+// curveLengthSamples is how many chords the length of a curved segment of a pose path is
+// measured along.
+const curveLengthSamples = 32
+
+// Slerp returns the orientation the given fraction of the way along the shortest rotation from
+// o1 to o2, turning at a constant rate.
+func Slerp(o1, o2 Orientation, by float64) Orientation {
+	q := Quaternion(slerp(o1.Quaternion(), o2.Quaternion(), by))
+	return &q
+}
+
+// pathSegment is a piece of a pose path, giving its poses from 0 at its start to 1 at its end.
+type pathSegment func(by float64) Pose
+
+// PosePath is a path through poses, from which poses can be taken by their distance along it,
+// as for smooth moves of an arm, gantry or camera. The distance a segment of a path covers is
+// the distance it translates, or, if it turns more, the angle in degrees it turns, so that a
+// path which only turns in place still has a length to be followed along.
+type PosePath struct {
+	segments []pathSegment
+	// ends holds the distance along the path that each segment ends at.
+	ends []float64
+	// start is the pose a path without segments stays at.
+	start Pose
+}
+
+// NewPosePath returns the path through the waypoints along straight lines, with orientations
+// slerped between those of the waypoints.
+func NewPosePath(waypoints ...Pose) (*PosePath, error) {
+	if len(waypoints) == 0 {
+		return nil, errors.New("a pose path needs at least one waypoint")
+	}
+	path := &PosePath{start: waypoints[0]}
+	for i := 1; i < len(waypoints); i++ {
+		path.addLine(waypoints[i-1], waypoints[i])
+	}
+	return path, nil
+}
+
+// NewBlendedPosePath returns the path through the waypoints along straight lines, as with
+// NewPosePath, but for its corners, which are cut by curves starting and ending up to radiusMm
+// from the waypoints they round. A corner is cut by no more than half of either line meeting
+// at it, so the path passes close to, rather than through, the waypoints between its ends.
+func NewBlendedPosePath(radiusMm float64, waypoints ...Pose) (*PosePath, error) {
+	if radiusMm < 0 {
+		return nil, errors.New("a pose path cannot be blended by a negative radius")
+	}
+	if len(waypoints) == 0 {
+		return nil, errors.New("a pose path needs at least one waypoint")
+	}
+	path := &PosePath{start: waypoints[0]}
+	from := waypoints[0]
+	for i := 1; i < len(waypoints)-1; i++ {
+		prev, corner, next := waypoints[i-1], waypoints[i], waypoints[i+1]
+		in := corner.Point().Sub(prev.Point()).Norm()
+		out := next.Point().Sub(corner.Point()).Norm()
+		r := math.Min(radiusMm, math.Min(in, out)/2)
+		if r < defaultDistanceEpsilon {
+			path.addLine(from, corner)
+			from = corner
+			continue
+		}
+		blendStart := Interpolate(corner, prev, r/in)
+		blendEnd := Interpolate(corner, next, r/out)
+		path.addLine(from, blendStart)
+		path.addSegment(quadraticBlend(blendStart, corner.Point(), blendEnd), true)
+		from = blendEnd
+	}
+	if len(waypoints) > 1 {
+		path.addLine(from, waypoints[len(waypoints)-1])
+	}
+	return path, nil
+}
+
+// NewSplinePosePath returns the path through the waypoints along a Catmull-Rom spline, which
+// passes through each of them without stopping to change direction, with orientations slerped
+// between those of the waypoints.
+func NewSplinePosePath(waypoints ...Pose) (*PosePath, error) {
+	if len(waypoints) == 0 {
+		return nil, errors.New("a pose path needs at least one waypoint")
+	}
+	path := &PosePath{start: waypoints[0]}
+	point := func(i int) r3.Vector {
+		// the ends of the spline are held in place by repeating them.
+		i = int(math.Max(0, math.Min(float64(i), float64(len(waypoints)-1))))
+		return waypoints[i].Point()
+	}
+	for i := 1; i < len(waypoints); i++ {
+		p0, p1, p2, p3 := point(i-2), point(i-1), point(i), point(i+1)
+		o1, o2 := waypoints[i-1].Orientation(), waypoints[i].Orientation()
+		path.addSegment(func(by float64) Pose {
+			return NewPose(catmullRom(p0, p1, p2, p3, by), Slerp(o1, o2, by))
+		}, true)
+	}
+	return path, nil
+}
+
+// Length returns the distance along the path from its start to its end.
+func (p *PosePath) Length() float64 {
+	if len(p.ends) == 0 {
+		return 0
+	}
+	return p.ends[len(p.ends)-1]
+}
+
+// At returns the pose at the given distance along the path, which is held to its ends. Within
+// the curved segments of a path, distances are approximate.
+func (p *PosePath) At(distance float64) Pose {
+	if len(p.segments) == 0 {
+		return p.start
+	}
+	i := sort.SearchFloat64s(p.ends, distance)
+	if i >= len(p.segments) {
+		return p.segments[len(p.segments)-1](1)
+	}
+	start := 0.
+	if i > 0 {
+		start = p.ends[i-1]
+	}
+	return p.segments[i](math.Max(0, distance-start) / (p.ends[i] - start))
+}
+
+// Sample returns the poses along the path at every stepMm of distance, and at its end.
+func (p *PosePath) Sample(stepMm float64) ([]Pose, error) {
+	if stepMm <= 0 {
+		return nil, errors.New("a pose path must be sampled by a positive step")
+	}
+	length := p.Length()
+	steps := int(math.Ceil(length/stepMm - defaultDistanceEpsilon))
+	poses := make([]Pose, 0, steps+1)
+	for i := 0; i < steps; i++ {
+		poses = append(poses, p.At(float64(i)*stepMm))
+	}
+	return append(poses, p.At(length)), nil
+}
+
+// addLine adds the straight segment between two poses to the path.
+func (p *PosePath) addLine(from, to Pose) {
+	p.addSegment(func(by float64) Pose {
+		return Interpolate(from, to, by)
+	}, false)
+}
+
+// addSegment adds the segment to the path, unless it neither translates nor turns.
+func (p *PosePath) addSegment(segment pathSegment, curved bool) {
+	start, end := segment(0), segment(1)
+	translation := end.Point().Sub(start.Point()).Norm()
+	if curved {
+		translation = 0
+		prev := start.Point()
+		for i := 1; i <= curveLengthSamples; i++ {
+			next := segment(float64(i) / curveLengthSamples).Point()
+			translation += next.Sub(prev).Norm()
+			prev = next
+		}
+	}
+	turn := math.Abs(QuatToR4AA(OrientationBetween(start.Orientation(), end.Orientation()).Quaternion()).Theta)
+	length := math.Max(translation, turn*180/math.Pi)
+	if length < defaultDistanceEpsilon {
+		return
+	}
+	p.segments = append(p.segments, segment)
+	p.ends = append(p.ends, p.Length()+length)
+}
+
+// quadraticBlend returns the segment curving from one pose to another along the quadratic Bézier
+// curve pulled toward the control point, with orientation slerped between theirs.
+func quadraticBlend(from Pose, control r3.Vector, to Pose) pathSegment {
+	return func(by float64) Pose {
+		a := from.Point().Mul((1 - by) * (1 - by))
+		b := control.Mul(2 * (1 - by) * by)
+		c := to.Point().Mul(by * by)
+		return NewPose(a.Add(b).Add(c), Slerp(from.Orientation(), to.Orientation(), by))
+	}
+}
+
+// catmullRom returns the point the given fraction of the way from p1 to p2 along the uniform
+// Catmull-Rom spline through p0, p1, p2 and p3.
+func catmullRom(p0, p1, p2, p3 r3.Vector, by float64) r3.Vector {
+	by2 := by * by
+	by3 := by2 * by
+	return p1.Mul(2).
+		Add(p2.Sub(p0).Mul(by)).
+		Add(p0.Mul(2).Sub(p1.Mul(5)).Add(p2.Mul(4)).Sub(p3).Mul(by2)).
+		Add(p1.Mul(3).Sub(p0).Sub(p2.Mul(3)).Add(p3).Mul(by3)).
+		Mul(0.5)
+}
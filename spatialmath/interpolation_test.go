@@ -0,0 +1,111 @@
+package spatialmath
+
+import (
+	"math"
+	"testing"
+
+	"github.com/golang/geo/r3"
+	"go.viam.com/test"
+)
+
+func TestSlerpOrientations(t *testing.T) {
+	o1 := NewZeroOrientation()
+	o2 := &OrientationVectorDegrees{OZ: 1, Theta: 90}
+	half := Slerp(o1, o2, 0.5)
+	test.That(t, OrientationAlmostEqual(half, &OrientationVectorDegrees{OZ: 1, Theta: 45}), test.ShouldBeTrue)
+	test.That(t, OrientationAlmostEqual(Slerp(o1, o2, 1), o2), test.ShouldBeTrue)
+}
+
+func TestPosePath(t *testing.T) {
+	_, err := NewPosePath()
+	test.That(t, err, test.ShouldNotBeNil)
+
+	path, err := NewPosePath(NewPoseFromPoint(r3.Vector{1, 2, 3}))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path.Length(), test.ShouldEqual, 0)
+	ptCompare(t, path.At(10).Point(), r3.Vector{1, 2, 3})
+
+	turned := &OrientationVectorDegrees{OZ: 1, Theta: 90}
+	path, err = NewPosePath(
+		NewZeroPose(),
+		NewPoseFromPoint(r3.Vector{100, 0, 0}),
+		NewPoseFromPoint(r3.Vector{100, 0, 0}),
+		NewPose(r3.Vector{100, 0, 0}, turned),
+		NewPose(r3.Vector{100, 50, 0}, turned),
+	)
+	test.That(t, err, test.ShouldBeNil)
+	// the repeated waypoint adds nothing, and the turn in place counts a millimeter a degree.
+	test.That(t, path.Length(), test.ShouldAlmostEqual, 240)
+	ptCompare(t, path.At(-5).Point(), r3.Vector{0, 0, 0})
+	ptCompare(t, path.At(25).Point(), r3.Vector{25, 0, 0})
+	test.That(t, OrientationAlmostEqual(path.At(145).Orientation(), &OrientationVectorDegrees{OZ: 1, Theta: 45}), test.ShouldBeTrue)
+	ptCompare(t, path.At(215).Point(), r3.Vector{100, 25, 0})
+	ptCompare(t, path.At(1000).Point(), r3.Vector{100, 50, 0})
+
+	poses, err := path.Sample(100)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, len(poses), test.ShouldEqual, 4)
+	ptCompare(t, poses[3].Point(), r3.Vector{100, 50, 0})
+	_, err = path.Sample(0)
+	test.That(t, err, test.ShouldNotBeNil)
+}
+
+func TestBlendedPosePath(t *testing.T) {
+	waypoints := []Pose{
+		NewZeroPose(),
+		NewPoseFromPoint(r3.Vector{100, 0, 0}),
+		NewPoseFromPoint(r3.Vector{100, 100, 0}),
+	}
+	_, err := NewBlendedPosePath(-1, waypoints...)
+	test.That(t, err, test.ShouldNotBeNil)
+
+	sharp, err := NewBlendedPosePath(0, waypoints...)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, sharp.Length(), test.ShouldAlmostEqual, 200)
+
+	path, err := NewBlendedPosePath(20, waypoints...)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path.Length(), test.ShouldBeLessThan, 200)
+	test.That(t, path.Length(), test.ShouldBeGreaterThan, 160+20*math.Sqrt2)
+	ptCompare(t, path.At(0).Point(), r3.Vector{0, 0, 0})
+	ptCompare(t, path.At(80).Point(), r3.Vector{80, 0, 0})
+	ptCompare(t, path.At(path.Length()).Point(), r3.Vector{100, 100, 0})
+
+	// the corner is cut, but the path stays within the radius of it.
+	poses, err := path.Sample(1)
+	test.That(t, err, test.ShouldBeNil)
+	closest := math.Inf(1)
+	for _, pose := range poses {
+		dist := pose.Point().Sub(r3.Vector{100, 0, 0}).Norm()
+		closest = math.Min(closest, dist)
+		test.That(t, pose.Point().X, test.ShouldBeLessThanOrEqualTo, 100+1e-6)
+		test.That(t, pose.Point().Y, test.ShouldBeGreaterThanOrEqualTo, -1e-6)
+	}
+	test.That(t, closest, test.ShouldBeGreaterThan, 1)
+	test.That(t, closest, test.ShouldBeLessThan, 20)
+}
+
+func TestSplinePosePath(t *testing.T) {
+	turned := &OrientationVectorDegrees{OZ: 1, Theta: 90}
+	waypoints := []Pose{
+		NewZeroPose(),
+		NewPoseFromPoint(r3.Vector{100, 0, 0}),
+		NewPose(r3.Vector{100, 100, 0}, turned),
+	}
+	path, err := NewSplinePosePath(waypoints...)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, path.Length(), test.ShouldBeGreaterThan, 200)
+
+	// the spline passes through each waypoint.
+	poses, err := path.Sample(0.5)
+	test.That(t, err, test.ShouldBeNil)
+	for _, waypoint := range waypoints {
+		closest := math.Inf(1)
+		for _, pose := range poses {
+			closest = math.Min(closest, pose.Point().Sub(waypoint.Point()).Norm())
+		}
+		test.That(t, closest, test.ShouldBeLessThan, 1)
+	}
+	end := path.At(path.Length())
+	test.That(t, PoseAlmostEqual(end, waypoints[2]), test.ShouldBeTrue)
+}
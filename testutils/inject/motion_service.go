@@ -3,6 +3,7 @@ package inject
 import (
 	"context"
 
+	"github.com/pkg/errors"
 	servicepb "go.viam.com/api/service/motion/v1"
 
 	"go.viam.com/rdk/referenceframe"
@@ -50,6 +51,10 @@ type MotionService struct {
 		ctx context.Context,
 		req motion.PlanHistoryReq,
 	) ([]motion.PlanWithStatus, error)
+	MoveRelativeFunc func(
+		ctx context.Context,
+		req motion.MoveRelativeReq,
+	) error
 	DoCommandFunc func(ctx context.Context,
 		cmd map[string]interface{}) (map[string]interface{}, error)
 	CloseFunc func(ctx context.Context) error
@@ -146,6 +151,18 @@ func (mgs *MotionService) PlanHistory(
 	return mgs.PlanHistoryFunc(ctx, req)
 }
 
+// MoveRelative calls the injected MoveRelative or the real variant.
+func (mgs *MotionService) MoveRelative(ctx context.Context, req motion.MoveRelativeReq) error {
+	if mgs.MoveRelativeFunc == nil {
+		jogger, ok := mgs.Service.(motion.Jogger)
+		if !ok {
+			return errors.New("MoveRelative not defined")
+		}
+		return jogger.MoveRelative(ctx, req)
+	}
+	return mgs.MoveRelativeFunc(ctx, req)
+}
+
 // DoCommand calls the injected DoCommand or the real variant.
 func (mgs *MotionService) DoCommand(ctx context.Context,
 	cmd map[string]interface{},
@@ -10,17 +10,37 @@ import (
 	"github.com/google/uuid"
 	"go.viam.com/utils/pexec"
 
+	"go.viam.com/rdk/arbitration"
 	"go.viam.com/rdk/cloud"
+	"go.viam.com/rdk/components/arm"
 	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/faults"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/operation"
 	"go.viam.com/rdk/pointcloud"
 	"go.viam.com/rdk/referenceframe"
 	"go.viam.com/rdk/resource"
 	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/controlloops"
+	"go.viam.com/rdk/robot/coordination"
+	"go.viam.com/rdk/robot/effectiveconfig"
+	"go.viam.com/rdk/robot/events"
+	"go.viam.com/rdk/robot/failover"
 	"go.viam.com/rdk/robot/framesystem"
+	"go.viam.com/rdk/robot/health"
+	"go.viam.com/rdk/robot/homing"
+	"go.viam.com/rdk/robot/kv"
+	"go.viam.com/rdk/robot/lan"
+	"go.viam.com/rdk/robot/metadata"
+	"go.viam.com/rdk/robot/metrics"
+	"go.viam.com/rdk/robot/mission"
 	"go.viam.com/rdk/robot/packages"
+	"go.viam.com/rdk/robot/records"
+	"go.viam.com/rdk/robot/selftest"
+	"go.viam.com/rdk/robot/tools"
+	"go.viam.com/rdk/robot/trajectories"
 	"go.viam.com/rdk/session"
+	"go.viam.com/rdk/watchdog"
 )
 
 // Robot is an injected robot.
@@ -32,9 +52,11 @@ type Robot struct {
 	ResourceByNameFunc     func(name resource.Name) (resource.Resource, error)
 	RemoteNamesFunc        func() []string
 	ResourceNamesFunc      func() []resource.Name
+	ResourceLabelsFunc     func() map[resource.Name]map[string]string
 	ResourceRPCAPIsFunc    func() []resource.RPCAPI
 	ProcessManagerFunc     func() pexec.ProcessManager
 	ConfigFunc             func() *config.Config
+	EffectiveConfigFunc    func(ctx context.Context) (*robot.EffectiveConfig, error)
 	LoggerFunc             func() logging.Logger
 	CloseFunc              func(ctx context.Context) error
 	StopAllFunc            func(ctx context.Context, extra map[resource.Name]map[string]interface{}) error
@@ -45,17 +67,52 @@ type Robot struct {
 		dst string,
 		additionalTransforms []*referenceframe.LinkInFrame,
 	) (*referenceframe.PoseInFrame, error)
+	LiveFrameSystemFunc func(
+		ctx context.Context,
+		additionalTransforms []*referenceframe.LinkInFrame,
+	) (*framesystem.LiveFrameSystem, error)
 	TransformPointCloudFunc func(ctx context.Context, srcpc pointcloud.PointCloud, srcName, dstName string) (pointcloud.PointCloud, error)
 	StatusFunc              func(ctx context.Context, resourceNames []resource.Name) ([]robot.Status, error)
+	RecordsFunc             func(ctx context.Context, names []resource.Name) (map[resource.Name]records.Records, error)
 	ModuleAddressFunc       func() (string, error)
 	CloudMetadataFunc       func(ctx context.Context) (cloud.Metadata, error)
 	ShutdownFunc            func(ctx context.Context) error
+	HealthFunc              func(ctx context.Context) (health.Report, error)
+	DiscoverRemotesFunc     func(ctx context.Context) ([]lan.Candidate, error)
 
 	ops        *operation.Manager
+	faults     *faults.Injector
+	arbiter    *arbitration.Arbiter
+	watchdog   *watchdog.Watchdog
+	health     *health.Tracker
+	metrics    *metrics.Recorder
+	kv         *kv.Store
+	missions   *mission.Queue
+	trajectory *trajectories.Recorder
+	homing     *homing.Keeper
+	failovers  *failover.Monitor
+	events     *events.Bus
+	selfTester *selftest.Tester
+	tools      *tools.Manager
+	frames     *framesystem.RuntimeFrames
+	obstacles  *framesystem.ObstacleStore
+	loops      *controlloops.Host
+	coord      *coordination.Coordinator
 	SessMgr    session.Manager
 	PackageMgr packages.Manager
 }
 
+// NewRobot returns a robot with only the given resources and no resource RPC APIs, logging
+// to the given logger.
+func NewRobot(logger logging.Logger, rs map[resource.Name]resource.Resource) *Robot {
+	r := &Robot{
+		LoggerFunc:          func() logging.Logger { return logger },
+		ResourceRPCAPIsFunc: func() []resource.RPCAPI { return nil },
+	}
+	r.MockResourcesFromMap(rs)
+	return r
+}
+
 // MockResourcesFromMap mocks ResourceNames and ResourceByName based on a resource map.
 func (r *Robot) MockResourcesFromMap(rs map[resource.Name]resource.Resource) {
 	func() {
@@ -122,6 +179,20 @@ func (r *Robot) ResourceNames() []resource.Name {
 	return r.ResourceNamesFunc()
 }
 
+// ResourceLabels calls the injected ResourceLabels or the real version, if the robot has
+// labels.
+func (r *Robot) ResourceLabels() map[resource.Name]map[string]string {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.ResourceLabelsFunc == nil {
+		if labeled, ok := r.LocalRobot.(metadata.Service); ok {
+			return labeled.ResourceLabels()
+		}
+		return nil
+	}
+	return r.ResourceLabelsFunc()
+}
+
 // ResourceRPCAPIs returns a list of all known resource RPC APIs.
 func (r *Robot) ResourceRPCAPIs() []resource.RPCAPI {
 	r.Mu.RLock()
@@ -153,6 +224,250 @@ func (r *Robot) OperationManager() *operation.Manager {
 	return r.ops
 }
 
+// FaultInjector returns a real fault injector.
+func (r *Robot) FaultInjector() *faults.Injector {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.faults == nil {
+		r.faults = faults.NewInjector(logger)
+	}
+	return r.faults
+}
+
+// Arbiter returns a real command arbiter.
+func (r *Robot) Arbiter() *arbitration.Arbiter {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.arbiter == nil {
+		r.arbiter = arbitration.NewArbiter(logger)
+	}
+	return r.arbiter
+}
+
+// Health calls the injected Health or the real version.
+func (r *Robot) Health(ctx context.Context) (health.Report, error) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.HealthFunc == nil {
+		return r.LocalRobot.(health.Robot).Health(ctx)
+	}
+	return r.HealthFunc(ctx)
+}
+
+// DiscoverRemotes calls the injected DiscoverRemotes or the real version.
+func (r *Robot) DiscoverRemotes(ctx context.Context) ([]lan.Candidate, error) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.DiscoverRemotesFunc == nil {
+		return r.LocalRobot.(lan.Robot).DiscoverRemotes(ctx)
+	}
+	return r.DiscoverRemotesFunc(ctx)
+}
+
+// HealthTracker returns a real health tracker.
+func (r *Robot) HealthTracker() *health.Tracker {
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.health == nil {
+		r.health = health.NewTracker()
+	}
+	return r.health
+}
+
+// Metrics returns a real metrics recorder.
+func (r *Robot) Metrics() *metrics.Recorder {
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.metrics == nil {
+		r.metrics = metrics.NewRecorder()
+	}
+	return r.metrics
+}
+
+// KV returns a real key-value store kept in memory.
+func (r *Robot) KV() *kv.Store {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.kv == nil {
+		r.kv = kv.NewStore("", logger)
+	}
+	return r.kv
+}
+
+// Missions returns a real mission queue kept in the robot's key-value store. It is never
+// started, so its tasks stay queued.
+func (r *Robot) Missions() *mission.Queue {
+	logger := r.Logger()
+	store := r.KV()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.missions == nil {
+		r.missions = mission.NewQueue(r, store, logger)
+	}
+	return r.missions
+}
+
+// Trajectories returns a real trajectory recorder kept in the robot's key-value store.
+func (r *Robot) Trajectories() *trajectories.Recorder {
+	logger := r.Logger()
+	store := r.KV()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.trajectory == nil {
+		r.trajectory = trajectories.NewRecorder(r, store, logger)
+	}
+	return r.trajectory
+}
+
+// Homing returns a real keeper of homing states kept in the robot's key-value store.
+func (r *Robot) Homing() *homing.Keeper {
+	logger := r.Logger()
+	store := r.KV()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.homing == nil {
+		r.homing = homing.NewKeeper(r, store, logger)
+	}
+	return r.homing
+}
+
+// Watchdog returns a real watchdog that never restarts resources.
+func (r *Robot) Watchdog() *watchdog.Watchdog {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.watchdog == nil {
+		r.watchdog = watchdog.NewWatchdog(logger, func(string) {})
+	}
+	return r.watchdog
+}
+
+// Failovers returns a real failover monitor.
+func (r *Robot) Failovers() *failover.Monitor {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.failovers == nil {
+		r.failovers = failover.NewMonitor(logger)
+	}
+	return r.failovers
+}
+
+// Events returns a real event bus. Nothing is published to it unless the test does.
+func (r *Robot) Events() *events.Bus {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.events == nil {
+		r.events = events.NewBus(logger)
+	}
+	return r.events
+}
+
+// SelfTester returns a real tester of the robot's components.
+func (r *Robot) SelfTester() *selftest.Tester {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.selfTester == nil {
+		components := func() []resource.Name {
+			var names []resource.Name
+			for _, name := range r.ResourceNames() {
+				if name.API.IsComponent() {
+					names = append(names, name)
+				}
+			}
+			return names
+		}
+		r.selfTester = selftest.NewTester(logger, components, r.ResourceByName)
+	}
+	return r.selfTester
+}
+
+// Tools returns a real tool manager. Tools can be mounted on any arm of the robot, and
+// mounting them does not change a frame system.
+func (r *Robot) Tools() *tools.Manager {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.tools == nil {
+		lookup := func(name string) (resource.Resource, error) {
+			return r.ResourceByName(arm.Named(name))
+		}
+		r.tools = tools.NewManager(logger, lookup, func(ctx context.Context) error { return nil })
+	}
+	return r.tools
+}
+
+// RuntimeFrames returns real runtime frames. Frames can be added with any parent, and adding
+// them does not change a frame system.
+func (r *Robot) RuntimeFrames() *framesystem.RuntimeFrames {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.frames == nil {
+		r.frames = framesystem.NewRuntimeFrames(logger, func(ctx context.Context) error { return nil }, func(string) {})
+	}
+	return r.frames
+}
+
+// Obstacles returns a real store of obstacles, which no frame system consults.
+func (r *Robot) Obstacles() *framesystem.ObstacleStore {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.obstacles == nil {
+		r.obstacles = framesystem.NewObstacleStore(logger)
+	}
+	return r.obstacles
+}
+
+// ControlLoops returns a real host of control loops. No loops run unless reconfigured, and
+// they cannot drive any resource.
+func (r *Robot) ControlLoops() *controlloops.Host {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.loops == nil {
+		r.loops = controlloops.NewHost(logger,
+			func(ctx context.Context, conf config.ControlLoopConfig) (controlloops.Controlled, error) {
+				return nil, errors.New("control loops cannot drive resources of an injected robot")
+			})
+	}
+	return r.loops
+}
+
+// Coordination returns a real coordinator, which holds no locks or barriers of remotes.
+func (r *Robot) Coordination() *coordination.Coordinator {
+	logger := r.Logger()
+	r.Mu.Lock()
+	defer r.Mu.Unlock()
+
+	if r.coord == nil {
+		r.coord = coordination.NewCoordinator(logger, func(string) (coordination.Service, bool) { return nil, false })
+	}
+	return r.coord
+}
+
 // SessionManager calls the injected SessionManager or the real version.
 func (r *Robot) SessionManager() session.Manager {
 	r.Mu.RLock()
@@ -185,6 +500,16 @@ func (r *Robot) Config() *config.Config {
 	return r.ConfigFunc()
 }
 
+// EffectiveConfig calls the injected EffectiveConfig or the real version.
+func (r *Robot) EffectiveConfig(ctx context.Context) (*robot.EffectiveConfig, error) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.EffectiveConfigFunc == nil {
+		return r.LocalRobot.(effectiveconfig.Robot).EffectiveConfig(ctx)
+	}
+	return r.EffectiveConfigFunc(ctx)
+}
+
 // Logger calls the injected Logger or the real version.
 func (r *Robot) Logger() logging.Logger {
 	r.Mu.RLock()
@@ -239,6 +564,19 @@ func (r *Robot) FrameSystemConfig(ctx context.Context) (*framesystem.Config, err
 	return r.FrameSystemConfigFunc(ctx)
 }
 
+// LiveFrameSystem calls the injected LiveFrameSystem or the real version.
+func (r *Robot) LiveFrameSystem(
+	ctx context.Context,
+	additionalTransforms []*referenceframe.LinkInFrame,
+) (*framesystem.LiveFrameSystem, error) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.LiveFrameSystemFunc == nil {
+		return r.LocalRobot.(framesystem.Robot).LiveFrameSystem(ctx, additionalTransforms)
+	}
+	return r.LiveFrameSystemFunc(ctx, additionalTransforms)
+}
+
 // TransformPose calls the injected TransformPose or the real version.
 func (r *Robot) TransformPose(
 	ctx context.Context,
@@ -275,6 +613,19 @@ func (r *Robot) Status(ctx context.Context, resourceNames []resource.Name) ([]ro
 	return r.StatusFunc(ctx, resourceNames)
 }
 
+// Records calls the injected Records or the real version, if the robot keeps records.
+func (r *Robot) Records(ctx context.Context, names []resource.Name) (map[resource.Name]records.Records, error) {
+	r.Mu.RLock()
+	defer r.Mu.RUnlock()
+	if r.RecordsFunc == nil {
+		if recordingRobot, ok := r.LocalRobot.(records.Robot); ok {
+			return recordingRobot.Records(ctx, names)
+		}
+		return nil, nil
+	}
+	return r.RecordsFunc(ctx, names)
+}
+
 // ModuleAddress calls the injected ModuleAddress or the real one.
 func (r *Robot) ModuleAddress() (string, error) {
 	r.Mu.RLock()
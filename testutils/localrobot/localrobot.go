@@ -0,0 +1,27 @@
+// Package localrobot constructs local robots for tests. It is apart from robottestutils and
+// inject because the tests of the robot/impl package use those, so they cannot import it.
+package localrobot
+
+import (
+	"context"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/robot"
+	robotimpl "go.viam.com/rdk/robot/impl"
+)
+
+// New returns a local robot built from the given config, which is closed when the test ends.
+func New(t *testing.T, cfg *config.Config) robot.LocalRobot {
+	t.Helper()
+	logger := logging.NewTestLogger(t)
+	r, err := robotimpl.New(context.Background(), cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	t.Cleanup(func() {
+		test.That(t, r.Close(context.Background()), test.ShouldBeNil)
+	})
+	return r
+}
@@ -0,0 +1,122 @@
+package scenario
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"go.viam.com/rdk/clock"
+)
+
+// ErrScripted is returned by calls that a behavior scripted to fail.
+var ErrScripted = errors.New("scripted failure")
+
+// Behavior scripts how a generated component responds to calls.
+type Behavior struct {
+	// LatencyMs is added to every call, measured on the robot clock.
+	LatencyMs int `json:"latency_ms,omitempty"`
+	// JitterMs adds up to this much extra random latency.
+	JitterMs int `json:"jitter_ms,omitempty"`
+	// FailureRate is the probability, between 0 and 1, that a call fails.
+	FailureRate float64 `json:"failure_rate,omitempty"`
+	// FailEvery makes every nth call fail.
+	FailEvery int `json:"fail_every,omitempty"`
+	// Seed seeds the random source used for jitter and failures.
+	Seed int64 `json:"seed,omitempty"`
+}
+
+// Validate ensures all parts of the behavior are valid.
+func (b Behavior) Validate() error {
+	if b.LatencyMs < 0 || b.JitterMs < 0 || b.FailEvery < 0 {
+		return errors.New("latency_ms, jitter_ms, and fail_every cannot be negative")
+	}
+	if b.FailureRate < 0 || b.FailureRate > 1 {
+		return errors.New("failure_rate must be between 0 and 1")
+	}
+	return nil
+}
+
+// behaviorRunner applies a behavior to the calls of one component.
+type behaviorRunner struct {
+	behavior Behavior
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	calls int
+}
+
+func newBehaviorRunner(b Behavior) *behaviorRunner {
+	//nolint:gosec
+	return &behaviorRunner{behavior: b, rand: rand.New(rand.NewSource(b.Seed))}
+}
+
+// apply waits out the scripted latency and returns an error if the call should fail.
+func (r *behaviorRunner) apply(ctx context.Context) error {
+	r.mu.Lock()
+	r.calls++
+	latency := time.Duration(r.behavior.LatencyMs) * time.Millisecond
+	if r.behavior.JitterMs > 0 {
+		latency += time.Duration(r.rand.Intn(r.behavior.JitterMs+1)) * time.Millisecond
+	}
+	fail := (r.behavior.FailEvery > 0 && r.calls%r.behavior.FailEvery == 0) ||
+		(r.behavior.FailureRate > 0 && r.rand.Float64() < r.behavior.FailureRate)
+	r.mu.Unlock()
+
+	if latency > 0 && !clock.SleepContext(ctx, latency) {
+		return ctx.Err()
+	}
+	if fail {
+		return ErrScripted
+	}
+	return nil
+}
+
+// Profile describes how the values reported by a generated sensor change over time.
+type Profile struct {
+	// Type is one of constant, sine, ramp, or square. Defaults to constant.
+	Type string `json:"type,omitempty"`
+	// Offset is the value at time zero, or the center of a sine or square wave.
+	Offset float64 `json:"offset,omitempty"`
+	// Amplitude is the peak deviation of a sine or square wave from Offset.
+	Amplitude float64 `json:"amplitude,omitempty"`
+	// PeriodSec is the period of a sine or square wave.
+	PeriodSec float64 `json:"period_sec,omitempty"`
+	// Slope is the change per second of a ramp.
+	Slope float64 `json:"slope,omitempty"`
+}
+
+// Validate ensures all parts of the profile are valid.
+func (p Profile) Validate() error {
+	switch p.Type {
+	case "", "constant", "ramp":
+	case "sine", "square":
+		if p.PeriodSec <= 0 {
+			return errors.Errorf("%s profile requires a positive period_sec", p.Type)
+		}
+	default:
+		return errors.Errorf("unknown profile type %q", p.Type)
+	}
+	return nil
+}
+
+// Value returns the value of the profile after the given time has elapsed.
+func (p Profile) Value(elapsed time.Duration) float64 {
+	t := elapsed.Seconds()
+	switch p.Type {
+	case "sine":
+		return p.Offset + p.Amplitude*math.Sin(2*math.Pi*t/p.PeriodSec)
+	case "square":
+		if math.Mod(t, p.PeriodSec) < p.PeriodSec/2 {
+			return p.Offset + p.Amplitude
+		}
+		return p.Offset - p.Amplitude
+	case "ramp":
+		return p.Offset + p.Slope*t
+	default:
+		return p.Offset
+	}
+}
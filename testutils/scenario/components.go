@@ -0,0 +1,212 @@
+package scenario
+
+import (
+	"context"
+	"time"
+
+	pb "go.viam.com/api/component/arm/v1"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/arm"
+	fakearm "go.viam.com/rdk/components/arm/fake"
+	"go.viam.com/rdk/components/camera"
+	fakecamera "go.viam.com/rdk/components/camera/fake"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/pointcloud"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/spatialmath"
+)
+
+func init() {
+	resource.RegisterComponent(arm.API, Model, resource.Registration[arm.Arm, *ArmConfig]{
+		Constructor: newArm,
+	})
+	resource.RegisterComponent(camera.API, Model, resource.Registration[camera.Camera, *CameraConfig]{
+		Constructor: newCamera,
+	})
+	resource.RegisterComponent(sensor.API, Model, resource.Registration[sensor.Sensor, *SensorConfig]{
+		Constructor: newSensor,
+	})
+}
+
+// ArmConfig is the config of a generated arm.
+type ArmConfig struct {
+	Behavior Behavior `json:"behavior"`
+	ArmModel string   `json:"arm-model,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *ArmConfig) Validate(path string) ([]string, error) {
+	if err := conf.Behavior.Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	return nil, nil
+}
+
+// CameraConfig is the config of a generated camera.
+type CameraConfig struct {
+	Behavior Behavior `json:"behavior"`
+	Width    int      `json:"width,omitempty"`
+	Height   int      `json:"height,omitempty"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *CameraConfig) Validate(path string) ([]string, error) {
+	if err := conf.Behavior.Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	return nil, nil
+}
+
+// SensorConfig is the config of a generated sensor.
+type SensorConfig struct {
+	Behavior Behavior `json:"behavior"`
+	Profile  Profile  `json:"profile"`
+}
+
+// Validate ensures all parts of the config are valid.
+func (conf *SensorConfig) Validate(path string) ([]string, error) {
+	if err := conf.Behavior.Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	if err := conf.Profile.Validate(); err != nil {
+		return nil, resource.NewConfigValidationError(path, err)
+	}
+	return nil, nil
+}
+
+// scriptedArm is a fake arm whose motion and state calls follow a behavior.
+type scriptedArm struct {
+	arm.Arm
+	behavior *behaviorRunner
+}
+
+func newArm(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (arm.Arm, error) {
+	newConf, err := resource.NativeConfig[*ArmConfig](conf)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := fakearm.NewArm(ctx, deps, resource.Config{
+		Name:                conf.Name,
+		API:                 arm.API,
+		Model:               fakearm.Model,
+		ConvertedAttributes: &fakearm.Config{ArmModel: newConf.ArmModel},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptedArm{Arm: inner, behavior: newBehaviorRunner(newConf.Behavior)}, nil
+}
+
+func (a *scriptedArm) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	return resource.NewMustRebuildError(conf.ResourceName())
+}
+
+func (a *scriptedArm) EndPosition(ctx context.Context, extra map[string]interface{}) (spatialmath.Pose, error) {
+	if err := a.behavior.apply(ctx); err != nil {
+		return nil, err
+	}
+	return a.Arm.EndPosition(ctx, extra)
+}
+
+func (a *scriptedArm) MoveToPosition(ctx context.Context, pose spatialmath.Pose, extra map[string]interface{}) error {
+	if err := a.behavior.apply(ctx); err != nil {
+		return err
+	}
+	return a.Arm.MoveToPosition(ctx, pose, extra)
+}
+
+func (a *scriptedArm) MoveToJointPositions(ctx context.Context, joints *pb.JointPositions, extra map[string]interface{}) error {
+	if err := a.behavior.apply(ctx); err != nil {
+		return err
+	}
+	return a.Arm.MoveToJointPositions(ctx, joints, extra)
+}
+
+func (a *scriptedArm) JointPositions(ctx context.Context, extra map[string]interface{}) (*pb.JointPositions, error) {
+	if err := a.behavior.apply(ctx); err != nil {
+		return nil, err
+	}
+	return a.Arm.JointPositions(ctx, extra)
+}
+
+// scriptedCamera is a fake camera whose image and point cloud calls follow a behavior.
+type scriptedCamera struct {
+	camera.Camera
+	behavior *behaviorRunner
+}
+
+func newCamera(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (camera.Camera, error) {
+	newConf, err := resource.NativeConfig[*CameraConfig](conf)
+	if err != nil {
+		return nil, err
+	}
+	inner, err := fakecamera.NewCamera(ctx, deps, resource.Config{
+		Name:                conf.Name,
+		API:                 camera.API,
+		Model:               fakecamera.Model,
+		ConvertedAttributes: &fakecamera.Config{Width: newConf.Width, Height: newConf.Height},
+	}, logger)
+	if err != nil {
+		return nil, err
+	}
+	return &scriptedCamera{Camera: inner, behavior: newBehaviorRunner(newConf.Behavior)}, nil
+}
+
+func (c *scriptedCamera) Reconfigure(ctx context.Context, deps resource.Dependencies, conf resource.Config) error {
+	return resource.NewMustRebuildError(conf.ResourceName())
+}
+
+func (c *scriptedCamera) Images(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
+	if err := c.behavior.apply(ctx); err != nil {
+		return nil, resource.ResponseMetadata{}, err
+	}
+	return c.Camera.Images(ctx)
+}
+
+func (c *scriptedCamera) Stream(ctx context.Context, errHandlers ...gostream.ErrorHandler) (gostream.VideoStream, error) {
+	if err := c.behavior.apply(ctx); err != nil {
+		return nil, err
+	}
+	return c.Camera.Stream(ctx, errHandlers...)
+}
+
+func (c *scriptedCamera) NextPointCloud(ctx context.Context) (pointcloud.PointCloud, error) {
+	if err := c.behavior.apply(ctx); err != nil {
+		return nil, err
+	}
+	return c.Camera.NextPointCloud(ctx)
+}
+
+// profileSensor reports a single value that follows a profile over robot time.
+type profileSensor struct {
+	resource.Named
+	resource.AlwaysRebuild
+	resource.TriviallyCloseable
+
+	behavior *behaviorRunner
+	profile  Profile
+	start    time.Time
+}
+
+func newSensor(ctx context.Context, deps resource.Dependencies, conf resource.Config, logger logging.Logger) (sensor.Sensor, error) {
+	newConf, err := resource.NativeConfig[*SensorConfig](conf)
+	if err != nil {
+		return nil, err
+	}
+	return &profileSensor{
+		Named:    conf.ResourceName().AsNamed(),
+		behavior: newBehaviorRunner(newConf.Behavior),
+		profile:  newConf.Profile,
+		start:    clock.Now(),
+	}, nil
+}
+
+func (s *profileSensor) Readings(ctx context.Context, extra map[string]interface{}) (map[string]interface{}, error) {
+	if err := s.behavior.apply(ctx); err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"value": s.profile.Value(clock.Since(s.start))}, nil
+}
@@ -0,0 +1,87 @@
+// Package scenario generates robot configs with many fake components that have scripted
+// behaviors (latency, failures, and value profiles) for large-scale integration tests.
+package scenario
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/utils"
+)
+
+// Model is the model of components generated by a scenario.
+var Model = resource.DefaultModelFamily.WithModel("scenario")
+
+// Options describe the robot a scenario generates.
+type Options struct {
+	Arms    int
+	Cameras int
+	Sensors int
+
+	// Behavior is applied to every generated component.
+	Behavior Behavior
+	// Behaviors overrides Behavior for the components with the given names.
+	Behaviors map[string]Behavior
+	// Profile is the value profile of every generated sensor.
+	Profile Profile
+	// Seed makes failures reproducible. Each component is seeded differently.
+	Seed int64
+}
+
+// Generate returns a robot config with the components described by opts. Components
+// are named arm0, arm1, ..., camera0, ..., sensor0, ....
+func Generate(opts Options) *config.Config {
+	cfg := &config.Config{}
+	var seed int64
+	behavior := func(name string) Behavior {
+		b, ok := opts.Behaviors[name]
+		if !ok {
+			b = opts.Behavior
+		}
+		b.Seed = opts.Seed + seed
+		seed++
+		return b
+	}
+	add := func(api resource.API, name string, attrs resource.ConfigValidator) {
+		cfg.Components = append(cfg.Components, resource.Config{
+			Name:                name,
+			API:                 api,
+			Model:               Model,
+			Attributes:          attributes(attrs),
+			ConvertedAttributes: attrs,
+		})
+	}
+
+	for i := 0; i < opts.Arms; i++ {
+		name := fmt.Sprintf("arm%d", i)
+		add(arm.API, name, &ArmConfig{Behavior: behavior(name)})
+	}
+	for i := 0; i < opts.Cameras; i++ {
+		name := fmt.Sprintf("camera%d", i)
+		add(camera.API, name, &CameraConfig{Behavior: behavior(name)})
+	}
+	for i := 0; i < opts.Sensors; i++ {
+		name := fmt.Sprintf("sensor%d", i)
+		add(sensor.API, name, &SensorConfig{Behavior: behavior(name), Profile: opts.Profile})
+	}
+	return cfg
+}
+
+// attributes converts native attributes to their generic form so that generated configs
+// can be serialized and handed to other processes.
+func attributes(attrs interface{}) utils.AttributeMap {
+	data, err := json.Marshal(attrs)
+	if err != nil {
+		panic(err)
+	}
+	var out utils.AttributeMap
+	if err := json.Unmarshal(data, &out); err != nil {
+		panic(err)
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package scenario_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/clock"
+	"go.viam.com/rdk/components/arm"
+	"go.viam.com/rdk/components/camera"
+	"go.viam.com/rdk/components/sensor"
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+	robotimpl "go.viam.com/rdk/robot/impl"
+	"go.viam.com/rdk/testutils/scenario"
+)
+
+func TestGenerate(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	cfg := scenario.Generate(scenario.Options{
+		Arms:      3,
+		Cameras:   2,
+		Sensors:   4,
+		Behaviors: map[string]scenario.Behavior{"arm1": {FailEvery: 2}},
+		Profile:   scenario.Profile{Type: "ramp", Offset: 1, Slope: 2},
+	})
+	test.That(t, cfg.Components, test.ShouldHaveLength, 9)
+	for _, conf := range cfg.Components {
+		test.That(t, conf.Attributes, test.ShouldNotBeNil)
+	}
+	// generated attributes convert back to the same config.
+	armConf, err := resource.TransformAttributeMap[*scenario.ArmConfig](cfg.Components[1].Attributes)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, armConf, test.ShouldResemble, cfg.Components[1].ConvertedAttributes)
+	test.That(t, armConf.Behavior.FailEvery, test.ShouldEqual, 2)
+
+	r, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+
+	names := r.ResourceNames()
+	for _, name := range []string{"arm0", "arm2", "camera1", "sensor3"} {
+		var found bool
+		for _, n := range names {
+			found = found || n.Name == name
+		}
+		test.That(t, found, test.ShouldBeTrue)
+	}
+
+	arm0, err := arm.FromRobot(r, "arm0")
+	test.That(t, err, test.ShouldBeNil)
+	arm1, err := arm.FromRobot(r, "arm1")
+	test.That(t, err, test.ShouldBeNil)
+	for i := 0; i < 2; i++ {
+		_, err = arm0.JointPositions(ctx, nil)
+		test.That(t, err, test.ShouldBeNil)
+	}
+	_, err = arm1.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	_, err = arm1.JointPositions(ctx, nil)
+	test.That(t, err, test.ShouldBeError, scenario.ErrScripted)
+
+	cam, err := camera.FromRobot(r, "camera0")
+	test.That(t, err, test.ShouldBeNil)
+	images, _, err := cam.Images(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, images, test.ShouldNotBeEmpty)
+
+	s, err := sensor.FromRobot(r, "sensor0")
+	test.That(t, err, test.ShouldBeNil)
+	readings, err := s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["value"], test.ShouldEqual, 1.)
+	virtual.Step(time.Second)
+	readings, err = s.Readings(ctx, nil)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, readings["value"], test.ShouldEqual, 3.)
+}
+
+func TestBehaviorLatency(t *testing.T) {
+	ctx := context.Background()
+	logger := logging.NewTestLogger(t)
+	virtual := clock.NewVirtual(time.Now())
+	defer clock.Set(virtual)()
+
+	cfg := scenario.Generate(scenario.Options{Sensors: 1, Behavior: scenario.Behavior{LatencyMs: 500}})
+	r, err := robotimpl.New(ctx, cfg, logger)
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, r.Close(ctx), test.ShouldBeNil)
+	}()
+	s, err := sensor.FromRobot(r, "sensor0")
+	test.That(t, err, test.ShouldBeNil)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := s.Readings(ctx, nil)
+		done <- err
+	}()
+	virtual.Step(400 * time.Millisecond)
+	test.That(t, done, test.ShouldBeEmpty)
+	test.That(t, virtual.StepUntil(100*time.Millisecond, time.Second, func() bool { return len(done) == 1 }), test.ShouldBeTrue)
+	test.That(t, <-done, test.ShouldBeNil)
+}
+
+func TestProfile(t *testing.T) {
+	for _, tc := range []struct {
+		profile  scenario.Profile
+		elapsed  time.Duration
+		expected float64
+	}{
+		{scenario.Profile{Offset: 2}, time.Hour, 2},
+		{scenario.Profile{Type: "ramp", Offset: 1, Slope: -1}, 3 * time.Second, -2},
+		{scenario.Profile{Type: "sine", Amplitude: 2, PeriodSec: 4}, time.Second, 2},
+		{scenario.Profile{Type: "square", Offset: 1, Amplitude: 1, PeriodSec: 2}, 1500 * time.Millisecond, 0},
+	} {
+		test.That(t, tc.profile.Validate(), test.ShouldBeNil)
+		test.That(t, tc.profile.Value(tc.elapsed), test.ShouldAlmostEqual, tc.expected)
+	}
+	test.That(t, scenario.Profile{Type: "sine"}.Validate(), test.ShouldNotBeNil)
+	test.That(t, scenario.Profile{Type: "bogus"}.Validate(), test.ShouldNotBeNil)
+}
@@ -2,6 +2,7 @@ package contextutils
 
 import (
 	"context"
+	"io"
 	"testing"
 	"time"
 
@@ -66,3 +67,34 @@ func TestContextWithTimeoutIfNoDeadline(t *testing.T) {
 	ctxDeadline, _ := ctx.Deadline()
 	test.That(t, ctxDeadline, test.ShouldEqual, deadlineCtxDeadline)
 }
+
+func TestReader(t *testing.T) {
+	pr, pw := io.Pipe()
+	r := NewReader(pr)
+	buf := make([]byte, 8)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := r.ReadContext(ctx, buf)
+	test.That(t, err, test.ShouldBeError, context.DeadlineExceeded)
+
+	// what arrives after a read was abandoned is kept for the next read.
+	go func() {
+		pw.Write([]byte("hello")) //nolint:errcheck
+	}()
+	n, err := r.ReadContext(context.Background(), buf[:3])
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(buf[:n]), test.ShouldEqual, "hel")
+	n, err = r.ReadContext(context.Background(), buf)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, string(buf[:n]), test.ShouldEqual, "lo")
+
+	test.That(t, pw.Close(), test.ShouldBeNil)
+	_, err = r.ReadContext(context.Background(), buf)
+	test.That(t, err, test.ShouldBeError, io.EOF)
+
+	r = NewReader(pr)
+	r.Close()
+	_, err = r.ReadContext(context.Background(), buf)
+	test.That(t, err, test.ShouldBeError, io.ErrClosedPipe)
+}
@@ -0,0 +1,84 @@
+package contextutils
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// A Reader reads from a reader whose reads block, such as a serial port, with reads that
+// return once their context is done. Reading is done in the background, so that data that
+// arrives after a read was abandoned is kept for the next read rather than lost.
+//
+// The background reading stops once the Reader is closed and its read in progress returns,
+// so the underlying reader should be closed along with it.
+type Reader struct {
+	r         io.Reader
+	startOnce sync.Once
+	chunks    chan readChunk
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	mu      sync.Mutex
+	pending []byte
+	err     error
+}
+
+type readChunk struct {
+	data []byte
+	err  error
+}
+
+// NewReader returns a Reader reading from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r, chunks: make(chan readChunk), closed: make(chan struct{})}
+}
+
+// Close stops reading in the background and fails reads in progress and after with
+// io.ErrClosedPipe.
+func (cr *Reader) Close() {
+	cr.closeOnce.Do(func() { close(cr.closed) })
+}
+
+func (cr *Reader) read() {
+	for {
+		buf := make([]byte, 4096)
+		n, err := cr.r.Read(buf)
+		select {
+		case cr.chunks <- readChunk{buf[:n], err}:
+		case <-cr.closed:
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// ReadContext reads into p as io.Reader.Read does, returning early with the error of the
+// context once it is done.
+func (cr *Reader) ReadContext(ctx context.Context, p []byte) (int, error) {
+	cr.startOnce.Do(func() { go cr.read() })
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if len(cr.pending) == 0 && cr.err == nil {
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		select {
+		case chunk := <-cr.chunks:
+			cr.pending, cr.err = chunk.data, chunk.err
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-cr.closed:
+			return 0, io.ErrClosedPipe
+		}
+	}
+	n := copy(p, cr.pending)
+	cr.pending = cr.pending[n:]
+	if len(cr.pending) == 0 && cr.err != nil {
+		return n, cr.err
+	}
+	return n, nil
+}
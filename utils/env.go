@@ -3,6 +3,7 @@ package utils
 import (
 	"os"
 	"runtime"
+	"strconv"
 	"time"
 
 	"go.viam.com/rdk/logging"
@@ -18,6 +19,15 @@ const (
 	// that resources are allowed to (re)configure.
 	ResourceConfigurationTimeoutEnvVar = "VIAM_RESOURCE_CONFIGURATION_TIMEOUT"
 
+	// DefaultResourceConfigurationConcurrency is the default number of resources
+	// that are allowed to (re)configure at once.
+	DefaultResourceConfigurationConcurrency = 32
+
+	// ResourceConfigurationConcurrencyEnvVar is the environment variable that can
+	// be set to override DefaultResourceConfigurationConcurrency as the number of
+	// resources that are allowed to (re)configure at once.
+	ResourceConfigurationConcurrencyEnvVar = "VIAM_RESOURCE_CONFIGURATION_CONCURRENCY"
+
 	// DefaultModuleStartupTimeout is the default module startup timeout.
 	DefaultModuleStartupTimeout = 5 * time.Minute
 
@@ -37,6 +47,22 @@ func GetResourceConfigurationTimeout(logger logging.Logger) time.Duration {
 	return timeoutHelper(DefaultResourceConfigurationTimeout, ResourceConfigurationTimeoutEnvVar, logger)
 }
 
+// GetResourceConfigurationConcurrency calculates the number of resources
+// allowed to (re)configure at once (env variable value if set,
+// DefaultResourceConfigurationConcurrency otherwise).
+func GetResourceConfigurationConcurrency(logger logging.Logger) int {
+	if concurrencyVal := os.Getenv(ResourceConfigurationConcurrencyEnvVar); concurrencyVal != "" {
+		concurrency, err := strconv.Atoi(concurrencyVal)
+		if err != nil || concurrency <= 0 {
+			logger.Warnf("Failed to parse %s env var as a positive integer, falling back to default concurrency of %d",
+				ResourceConfigurationConcurrencyEnvVar, DefaultResourceConfigurationConcurrency)
+			return DefaultResourceConfigurationConcurrency
+		}
+		return concurrency
+	}
+	return DefaultResourceConfigurationConcurrency
+}
+
 // GetModuleStartupTimeout calculates the module startup timeout
 // (env variable value if set, DefaultModuleStartupTimeout otherwise).
 func GetModuleStartupTimeout(logger logging.Logger) time.Duration {
@@ -0,0 +1,70 @@
+package watchdog
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"go.viam.com/rdk/grpc/structrpc"
+)
+
+// ServiceName is the name of the gRPC service serving a watchdog's diagnostics. Its requests
+// and responses are google.protobuf.Struct messages holding the JSON form of the types below.
+const ServiceName = "viam.rdk.watchdog.v1.WatchdogService"
+
+// A Service reports the requests of a robot that exceeded their limits, either directly or
+// over a connection to the robot.
+type Service interface {
+	// OverdueCalls returns the requests that exceeded their limit and whose handlers have not
+	// returned yet, oldest first.
+	OverdueCalls(ctx context.Context) ([]OverdueCall, error)
+}
+
+var _ Service = (*Watchdog)(nil)
+
+type overdueCallsResponse struct {
+	Calls []OverdueCall `json:"calls"`
+}
+
+// A Server serves a watchdog with ServiceDesc.
+type Server struct {
+	watchdog Service
+}
+
+// NewServer returns a server for the given watchdog.
+func NewServer(watchdog Service) *Server {
+	return &Server{watchdog: watchdog}
+}
+
+func (s *Server) getOverdueCalls(ctx context.Context, _ struct{}) (interface{}, error) {
+	calls, err := s.watchdog.OverdueCalls(ctx)
+	return overdueCallsResponse{Calls: calls}, err
+}
+
+// ServiceDesc describes the gRPC service serving a watchdog's diagnostics. It is served with a
+// Server.
+var ServiceDesc = structrpc.ServiceDesc(ServiceName, "rdk/watchdog",
+	structrpc.Unary("GetOverdueCalls", (*Server).getOverdueCalls),
+)
+
+// A Client is the Service of a robot served over a connection to it.
+type Client struct {
+	client *structrpc.Client
+}
+
+var _ Service = (*Client)(nil)
+
+// NewClient returns a client of the watchdog served over the given connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{client: structrpc.NewClient(conn, ServiceName)}
+}
+
+// OverdueCalls returns the requests that exceeded their limit and whose handlers have not
+// returned yet, oldest first.
+func (c *Client) OverdueCalls(ctx context.Context) ([]OverdueCall, error) {
+	var resp overdueCallsResponse
+	if err := c.client.Invoke(ctx, "GetOverdueCalls", struct{}{}, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Calls, nil
+}
@@ -0,0 +1,233 @@
+// Package watchdog cancels the gRPC requests served for a robot's resources that take
+// longer than the operation timeout configured for them, such as requests stalled on a
+// hung I2C device, so that one resource cannot stall the clients of the whole robot.
+// Resources without an operation timeout are bounded by the call deadline of their API, or
+// the default one.
+//
+// Requests whose handlers have not returned since they were canceled are kept track of as
+// overdue calls, so that drivers that do not honor cancellation can be found.
+package watchdog
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+// A Limit is how long requests to a resource may take.
+type Limit struct {
+	Timeout time.Duration
+	// Restart is whether the resource is restarted when a request takes longer.
+	Restart bool
+	// Deadline is whether the limit is the call deadline of the resource's API rather than its
+	// own operation timeout.
+	Deadline bool
+}
+
+// Deadlines are how long requests to resources without an operation timeout may take.
+type Deadlines struct {
+	// APIs are the deadlines of requests to resources of each API.
+	APIs map[resource.API]time.Duration
+	// Default is the deadline of requests to resources of other APIs. Zero means they are not
+	// bounded.
+	Default time.Duration
+}
+
+// An OverdueCall is a request that exceeded its limit and whose handler has not returned yet,
+// such as one blocked on unresponsive hardware by a driver that ignores cancellation.
+type OverdueCall struct {
+	Resource  string        `json:"resource"`
+	Method    string        `json:"method"`
+	Timeout   time.Duration `json:"timeout"`
+	StartedAt time.Time     `json:"started_at"`
+}
+
+// watchedCall is a request being watched.
+type watchedCall struct {
+	OverdueCall
+	returned bool
+}
+
+// A Robot is a robot watching for requests to its resources that exceed their timeout.
+type Robot interface {
+	// Watchdog returns the watchdog canceling requests to the robot's resources that exceed
+	// their operation timeout.
+	Watchdog() *Watchdog
+}
+
+// A Watchdog enforces the operation timeouts configured for resources on the requests
+// served for them. Only unary requests are watched, since streams last as long as their
+// clients want.
+type Watchdog struct {
+	mu         sync.Mutex
+	logger     logging.Logger
+	fromConfig []resource.Config
+	deadlines  Deadlines
+	limits     map[string]Limit
+	overdue    map[*watchedCall]struct{}
+	restart    func(shortName string)
+}
+
+// NewWatchdog returns a Watchdog with no limits. The given function is called to restart
+// a resource that exceeded its limit, if it is configured to be restarted.
+func NewWatchdog(logger logging.Logger, restart func(shortName string)) *Watchdog {
+	return &Watchdog{
+		logger:  logger,
+		limits:  map[string]Limit{},
+		overdue: map[*watchedCall]struct{}{},
+		restart: restart,
+	}
+}
+
+// Reconfigure replaces all limits with those of the given resource configs if they have
+// changed since the last call.
+func (w *Watchdog) Reconfigure(cfgs []resource.Config) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if reflect.DeepEqual(cfgs, w.fromConfig) {
+		return
+	}
+	w.fromConfig = cfgs
+	w.updateLimits()
+}
+
+// SetDeadlines replaces the call deadlines of the resources without an operation timeout.
+func (w *Watchdog) SetDeadlines(deadlines Deadlines) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if reflect.DeepEqual(deadlines, w.deadlines) {
+		return
+	}
+	w.deadlines = deadlines
+	w.updateLimits()
+}
+
+// updateLimits replaces all limits with those of the resource configs and call deadlines.
+func (w *Watchdog) updateLimits() {
+	w.limits = map[string]Limit{}
+	for _, cfg := range w.fromConfig {
+		if cfg.OperationTimeout > 0 {
+			w.limits[cfg.Name] = Limit{Timeout: cfg.OperationTimeout, Restart: cfg.RestartOnTimeout}
+			continue
+		}
+		deadline, ok := w.deadlines.APIs[cfg.API]
+		if !ok {
+			deadline = w.deadlines.Default
+		}
+		if deadline > 0 {
+			w.limits[cfg.Name] = Limit{Timeout: deadline, Deadline: true}
+		}
+	}
+}
+
+// Limit returns the limit of the resource with the given short name, if it has one.
+func (w *Watchdog) Limit(shortName string) (Limit, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	limit, ok := w.limits[shortName]
+	return limit, ok
+}
+
+// OverdueCalls returns the requests that exceeded their limit and whose handlers have not
+// returned yet, oldest first.
+func (w *Watchdog) OverdueCalls(ctx context.Context) ([]OverdueCall, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	calls := make([]OverdueCall, 0, len(w.overdue))
+	for call := range w.overdue {
+		calls = append(calls, call.OverdueCall)
+	}
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].StartedAt.Before(calls[j].StartedAt)
+	})
+	return calls, nil
+}
+
+// UnaryServerInterceptor cancels requests to resources that exceed their limit. The
+// request's handler is left to return on its own, since a hung driver may never do so.
+func (w *Watchdog) UnaryServerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	named, ok := req.(interface{ GetName() string })
+	if !ok {
+		return handler(ctx, req)
+	}
+	limit, ok := w.Limit(named.GetName())
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	call := &watchedCall{OverdueCall: OverdueCall{
+		Resource:  named.GetName(),
+		Method:    info.FullMethod,
+		Timeout:   limit.Timeout,
+		StartedAt: time.Now(),
+	}}
+	opCtx, cancel := context.WithTimeout(ctx, limit.Timeout)
+	defer cancel()
+	type result struct {
+		resp interface{}
+		err  error
+		// panicked is what the handler panicked with, if it did.
+		panicked interface{}
+	}
+	done := make(chan result, 1)
+	go func() {
+		var res result
+		defer func() {
+			res.panicked = recover()
+			w.mu.Lock()
+			call.returned = true
+			if _, ok := w.overdue[call]; ok {
+				delete(w.overdue, call)
+				w.logger.CInfow(ctx, "overdue operation returned",
+					"resource", call.Resource, "method", call.Method, "took", time.Since(call.StartedAt), "panicked", res.panicked)
+			}
+			w.mu.Unlock()
+			done <- res
+		}()
+		res.resp, res.err = handler(opCtx, req)
+	}()
+	select {
+	case res := <-done:
+		if res.panicked != nil {
+			// panics are left to be recovered from where requests are served, as if the
+			// handler was not watched.
+			panic(res.panicked)
+		}
+		return res.resp, res.err
+	case <-opCtx.Done():
+	}
+	if err := ctx.Err(); err != nil {
+		// the caller gave up first.
+		return nil, status.FromContextError(err).Err()
+	}
+	w.mu.Lock()
+	if !call.returned {
+		w.overdue[call] = struct{}{}
+	}
+	w.mu.Unlock()
+	w.logger.CWarnw(ctx, "operation exceeded its timeout; canceling it",
+		"resource", named.GetName(), "method", info.FullMethod, "timeout", limit.Timeout, "restart", limit.Restart)
+	if limit.Restart {
+		w.restart(named.GetName())
+	}
+	kind := "operation timeout"
+	if limit.Deadline {
+		kind = "call deadline"
+	}
+	return nil, status.Errorf(codes.DeadlineExceeded,
+		"%s on resource %q exceeded its %s of %v", info.FullMethod, named.GetName(), kind, limit.Timeout)
+}
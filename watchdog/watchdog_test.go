@@ -0,0 +1,183 @@
+package watchdog
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	commonpb "go.viam.com/api/common/v1"
+	"go.viam.com/test"
+	"go.viam.com/utils/testutils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"go.viam.com/rdk/logging"
+	"go.viam.com/rdk/resource"
+)
+
+func TestWatchdog(t *testing.T) {
+	var restarted atomic.Value
+	restarted.Store("")
+	w := NewWatchdog(logging.NewTestLogger(t), func(shortName string) {
+		restarted.Store(shortName)
+	})
+	w.Reconfigure([]resource.Config{
+		{Name: "imu1", OperationTimeout: 20 * time.Millisecond, RestartOnTimeout: true},
+		{Name: "imu2", OperationTimeout: 20 * time.Millisecond},
+		{Name: "imu3"},
+	})
+	_, ok := w.Limit("imu3")
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// hung is closed once the test ends so that hung handlers return.
+	hung := make(chan struct{})
+	defer close(hung)
+	call := func(ctx context.Context, name string, took time.Duration) error {
+		_, err := w.UnaryServerInterceptor(ctx, &commonpb.DoCommandRequest{Name: name},
+			&grpc.UnaryServerInfo{FullMethod: "/viam.component.movementsensor.v1.MovementSensorService/DoCommand"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				if took == 0 {
+					return &commonpb.DoCommandResponse{}, nil
+				}
+				// a hung driver does not return when its request is canceled.
+				select {
+				case <-time.After(took):
+				case <-hung:
+				}
+				return &commonpb.DoCommandResponse{}, nil
+			})
+		return err
+	}
+	ctx := context.Background()
+
+	test.That(t, call(ctx, "imu1", 0), test.ShouldBeNil)
+	test.That(t, call(ctx, "imu3", 50*time.Millisecond), test.ShouldBeNil)
+
+	err := call(ctx, "imu2", time.Minute)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "operation timeout of 20ms")
+	test.That(t, restarted.Load(), test.ShouldEqual, "")
+
+	err = call(ctx, "imu1", time.Minute)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+	test.That(t, restarted.Load(), test.ShouldEqual, "imu1")
+
+	// callers giving up first are not timeouts of the resource.
+	restarted.Store("")
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	err = call(cancelCtx, "imu1", time.Minute)
+	test.That(t, status.Code(err), test.ShouldEqual, codes.Canceled)
+	test.That(t, restarted.Load(), test.ShouldEqual, "")
+
+	// limits are removed with their config.
+	w.Reconfigure(nil)
+	_, ok = w.Limit("imu1")
+	test.That(t, ok, test.ShouldBeFalse)
+}
+
+// serverConn invokes a server directly rather than over a connection.
+type serverConn struct {
+	srv *Server
+}
+
+func (c serverConn) Invoke(ctx context.Context, method string, args, reply interface{}, opts ...grpc.CallOption) error {
+	for _, desc := range ServiceDesc.Methods {
+		if method != "/"+ServiceName+"/"+desc.MethodName {
+			continue
+		}
+		resp, err := desc.Handler(c.srv, ctx, func(in interface{}) error {
+			proto.Merge(in.(proto.Message), args.(proto.Message))
+			return nil
+		}, nil)
+		if err != nil {
+			return err
+		}
+		proto.Merge(reply.(proto.Message), resp.(proto.Message))
+		return nil
+	}
+	return status.Errorf(codes.Unimplemented, "unknown method %q", method)
+}
+
+func (c serverConn) NewStream(
+	ctx context.Context, desc *grpc.StreamDesc, method string, opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	return nil, status.Error(codes.Unimplemented, "streams are not supported")
+}
+
+func TestDeadlines(t *testing.T) {
+	cameraAPI := resource.APINamespaceRDK.WithComponentType("camera")
+	sensorAPI := resource.APINamespaceRDK.WithComponentType("sensor")
+	w := NewWatchdog(logging.NewTestLogger(t), func(string) {})
+	w.Reconfigure([]resource.Config{
+		{Name: "cam1", API: cameraAPI},
+		{Name: "cam2", API: cameraAPI, OperationTimeout: time.Second},
+		{Name: "sensor1", API: sensorAPI},
+	})
+	_, ok := w.Limit("cam1")
+	test.That(t, ok, test.ShouldBeFalse)
+
+	w.SetDeadlines(Deadlines{APIs: map[resource.API]time.Duration{cameraAPI: 20 * time.Millisecond}})
+	limit, ok := w.Limit("cam1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, limit, test.ShouldResemble, Limit{Timeout: 20 * time.Millisecond, Deadline: true})
+	limit, ok = w.Limit("cam2")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, limit, test.ShouldResemble, Limit{Timeout: time.Second})
+	_, ok = w.Limit("sensor1")
+	test.That(t, ok, test.ShouldBeFalse)
+
+	w.SetDeadlines(Deadlines{
+		APIs:    map[resource.API]time.Duration{cameraAPI: 20 * time.Millisecond},
+		Default: time.Minute,
+	})
+	limit, ok = w.Limit("sensor1")
+	test.That(t, ok, test.ShouldBeTrue)
+	test.That(t, limit, test.ShouldResemble, Limit{Timeout: time.Minute, Deadline: true})
+
+	// a handler ignoring cancellation is overdue until it returns.
+	release := make(chan struct{})
+	ctx := context.Background()
+	_, err := w.UnaryServerInterceptor(ctx, &commonpb.DoCommandRequest{Name: "cam1"},
+		&grpc.UnaryServerInfo{FullMethod: "/viam.component.camera.v1.CameraService/DoCommand"},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			<-release
+			return &commonpb.DoCommandResponse{}, nil
+		})
+	test.That(t, status.Code(err), test.ShouldEqual, codes.DeadlineExceeded)
+	test.That(t, err.Error(), test.ShouldContainSubstring, "call deadline of 20ms")
+
+	diagnostics := NewClient(serverConn{NewServer(w)})
+	calls, err := diagnostics.OverdueCalls(ctx)
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, calls, test.ShouldHaveLength, 1)
+	test.That(t, calls[0].Resource, test.ShouldEqual, "cam1")
+	test.That(t, calls[0].Method, test.ShouldEqual, "/viam.component.camera.v1.CameraService/DoCommand")
+	test.That(t, calls[0].Timeout, test.ShouldEqual, 20*time.Millisecond)
+	test.That(t, calls[0].StartedAt.IsZero(), test.ShouldBeFalse)
+
+	close(release)
+	testutils.WaitForAssertion(t, func(tb testing.TB) {
+		calls, err := w.OverdueCalls(ctx)
+		test.That(tb, err, test.ShouldBeNil)
+		test.That(tb, calls, test.ShouldBeEmpty)
+	})
+}
+
+func TestWatchdogPanics(t *testing.T) {
+	w := NewWatchdog(logging.NewTestLogger(t), func(string) {})
+	w.Reconfigure([]resource.Config{{Name: "arm1", OperationTimeout: time.Minute}})
+
+	// panics of watched handlers reach the caller as panics of unwatched ones do.
+	test.That(t, func() {
+		//nolint:errcheck
+		w.UnaryServerInterceptor(context.Background(), &commonpb.DoCommandRequest{Name: "arm1"},
+			&grpc.UnaryServerInfo{FullMethod: "/viam.component.arm.v1.ArmService/DoCommand"},
+			func(ctx context.Context, req interface{}) (interface{}, error) {
+				panic("driver bug")
+			})
+	}, test.ShouldPanicWith, "driver bug")
+}
@@ -13,6 +13,7 @@ import (
 	"runtime"
 	"runtime/pprof"
 	"slices"
+	"sync"
 	"time"
 
 	"github.com/invopop/jsonschema"
@@ -26,7 +27,9 @@ import (
 	"go.viam.com/rdk/config"
 	"go.viam.com/rdk/logging"
 	"go.viam.com/rdk/resource"
+	"go.viam.com/rdk/robot"
 	robotimpl "go.viam.com/rdk/robot/impl"
+	"go.viam.com/rdk/robot/snapshot"
 	"go.viam.com/rdk/robot/web"
 	weboptions "go.viam.com/rdk/robot/web/options"
 	rutils "go.viam.com/rdk/utils"
@@ -44,17 +47,44 @@ type Arguments struct {
 	SharedDir                  string `flag:"shareddir,usage=web resource directory"`
 	Version                    bool   `flag:"version,usage=print version"`
 	WebProfile                 bool   `flag:"webprofile,usage=include profiler in http server"`
+	WebMetrics                 bool   `flag:"webmetrics,usage=include resource request metrics in http server"`
+	WebMapTiles                bool   `flag:"webmaptiles,usage=include cached map tiles in http server"`
 	WebRTC                     bool   `flag:"webrtc,default=true,usage=force webrtc connections instead of direct"`
 	RevealSensitiveConfigDiffs bool   `flag:"reveal-sensitive-config-diffs,usage=show config diffs"`
 	UntrustedEnv               bool   `flag:"untrusted-env,usage=disable processes and shell from running in a untrusted environment"`
 	OutputTelemetry            bool   `flag:"output-telemetry,usage=print out telemetry data (metrics and spans)"`
 	DisableMulticastDNS        bool   `flag:"disable-mdns,usage=disable server discovery through multicast DNS"`
 	DumpResourcesPath          string `flag:"dump-resources,usage=dump all resource registrations as json to the provided file path"`
+	ValidateConfig             bool   `flag:"validate-config,usage=validate the config file, print the problems found as json, and exit"`
+	ReadOnly                   bool   `flag:"read-only,usage=reject actuating resource methods so the robot can be observed but not moved"`
+	Simulation                 bool   `flag:"simulation,usage=run the robot with simulated backends in place of the hardware of its resources"`
+	RecordTrace                string `flag:"record-trace,usage=record resource API requests and responses to the given file for replay"`
 }
 
+const (
+	// envUpgradeListenerFD is the descriptor of the listener handed over by the server that
+	// upgraded into this process.
+	envUpgradeListenerFD = "VIAM_UPGRADE_LISTENER_FD"
+	// envUpgradeSnapshot is the path of the snapshot of the resource states of the server
+	// that upgraded into this process.
+	envUpgradeSnapshot = "VIAM_UPGRADE_SNAPSHOT"
+)
+
 type robotServer struct {
 	args   Arguments
 	logger logging.Logger
+
+	listenerMu sync.Mutex
+	listener   net.Listener
+
+	// upgrade is set if the server shut down to upgrade in place.
+	upgrade *pendingUpgrade
+}
+
+// A pendingUpgrade is an in place upgrade to run once the server has shut down.
+type pendingUpgrade struct {
+	listenerFile *os.File
+	snapshotPath string
 }
 
 // RunServer is an entry point to starting the web server that can be called by main in a code
@@ -65,6 +95,18 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 		return err
 	}
 
+	// an upgrade runs last so that everything else is cleaned up first.
+	var upgrade *pendingUpgrade
+	defer func() {
+		if upgrade == nil {
+			return
+		}
+		if err == nil {
+			err = execUpgrade(upgrade.listenerFile, upgrade.snapshotPath)
+		}
+		err = multierr.Combine(err, upgrade.listenerFile.Close(), os.Remove(upgrade.snapshotPath))
+	}()
+
 	ctx, err = rutils.WithTrustedEnvironment(ctx, !argsParsed.UntrustedEnv)
 	if err != nil {
 		return err
@@ -74,6 +116,10 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 		return dumpResourceRegistrations(argsParsed.DumpResourcesPath)
 	}
 
+	if argsParsed.ValidateConfig {
+		return validateConfigFile(ctx, argsParsed.ConfigFile)
+	}
+
 	// Replace logger with logger based on flags.
 	logger := logging.NewLogger("")
 	logging.ReplaceGlobal(logger)
@@ -167,6 +213,10 @@ func RunServer(ctx context.Context, args []string, _ logging.Logger) (err error)
 	if err != nil {
 		logger.Error("Fatal error running server, exiting now: ", err)
 	}
+	upgrade = server.upgrade
+	if upgrade != nil {
+		logger.Info("upgrading server in place")
+	}
 
 	return err
 }
@@ -201,6 +251,9 @@ func (s *robotServer) createWebOptions(cfg *config.Config) (weboptions.Options,
 	options.Debug = s.args.Debug || cfg.Debug
 	options.WebRTC = s.args.WebRTC
 	options.DisableMulticastDNS = s.args.DisableMulticastDNS
+	options.Metrics = s.args.WebMetrics
+	options.MapTiles = s.args.WebMapTiles
+	options.RecordTrace = s.args.RecordTrace
 	if cfg.Cloud != nil && s.args.AllowInsecureCreds {
 		options.SignalingDialOpts = append(options.SignalingDialOpts, rpc.WithAllowInsecureWithCredentialsDowngrade())
 	}
@@ -217,6 +270,21 @@ func (s *robotServer) createWebOptions(cfg *config.Config) (weboptions.Options,
 	return options, nil
 }
 
+// A credentialRotator is a robot that can swap the credentials of its running web server.
+type credentialRotator interface {
+	RotateWebCredentials(ctx context.Context, o weboptions.Options) error
+}
+
+// rotateWebCredentials swaps the credentials of the running web server of the robot for
+// those in the given options, if the robot can do so without restarting it.
+func rotateWebCredentials(ctx context.Context, r robot.Robot, o weboptions.Options) error {
+	rotator, ok := r.(credentialRotator)
+	if !ok {
+		return errors.New("robot cannot rotate the credentials of its web server")
+	}
+	return rotator.RotateWebCredentials(ctx, o)
+}
+
 func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err error) {
 	ctx, cancel := context.WithCancel(ctx)
 
@@ -300,6 +368,8 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 		}
 		out.Debug = s.args.Debug || cfg.Debug
 		out.EnableWebProfile = s.args.WebProfile || cfg.EnableWebProfile
+		out.ReadOnly = s.args.ReadOnly || cfg.ReadOnly || out.ReadOnly
+		out.Simulation = s.args.Simulation || cfg.Simulation || out.Simulation
 		out.FromCommand = true
 		out.AllowInsecureCreds = s.args.AllowInsecureCreds
 		out.UntrustedEnv = s.args.UntrustedEnv
@@ -364,6 +434,7 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	defer func() {
 		err = multierr.Combine(err, myRobot.Close(context.Background()))
 	}()
+	s.restoreUpgradeSnapshot(ctx, myRobot)
 
 	// watch for and deliver changes to the robot
 	watcher, err := config.NewWatcher(ctx, cfg, s.logger)
@@ -401,10 +472,27 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 				}
 				var options weboptions.Options
 
-				if !diff.NetworkEqual {
+				restartWeb := !diff.NetworkEqual
+				if diff.CredentialsRotated {
+					// credentials alone can be swapped in place so that existing connections survive
+					options, err = s.createWebOptions(processedConfig)
+					if err == nil {
+						err = rotateWebCredentials(ctx, myRobot, options)
+					}
+					if err == nil {
+						restartWeb = false
+					} else {
+						s.logger.Infow("could not rotate credentials in place; restarting web service", "error", err)
+					}
+				}
+
+				if restartWeb {
 					// TODO(RSDK-2694): use internal web service reconfiguration instead
 					myRobot.StopWeb()
 					options, err = s.createWebOptions(processedConfig)
+					if err == nil {
+						err = s.listen(&options)
+					}
 					if err != nil {
 						s.logger.Errorw("reconfiguration aborted: error creating weboptions", "error", err)
 						continue
@@ -413,7 +501,7 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 
 				myRobot.Reconfigure(ctx, processedConfig)
 
-				if !diff.NetworkEqual {
+				if restartWeb {
 					if err := myRobot.StartWeb(ctx, options); err != nil {
 						s.logger.Errorw("reconfiguration failed: error starting web service while reconfiguring", "error", err)
 					}
@@ -427,18 +515,168 @@ func (s *robotServer) serveWeb(ctx context.Context, cfg *config.Config) (err err
 	defer func() {
 		<-onWatchDone
 	}()
+
+	upgradeRequests := make(chan os.Signal, 1)
+	notifyUpgrade(upgradeRequests)
+	onUpgradeDone := make(chan struct{})
+	utils.ManagedGo(func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-upgradeRequests:
+			}
+			if err := s.prepareUpgrade(ctx, myRobot); err != nil {
+				s.logger.Errorw("upgrade aborted", "error", err)
+				continue
+			}
+			s.logger.Info("shutting down to upgrade in place")
+			cancel()
+			return
+		}
+	}, func() {
+		close(onUpgradeDone)
+	})
+	defer func() {
+		stopNotifyUpgrade(upgradeRequests)
+		<-onUpgradeDone
+	}()
+
+	onWatchdogDone := make(chan struct{})
+	utils.ManagedGo(func() {
+		s.runWatchdog(ctx, myRobot)
+	}, func() {
+		close(onWatchdogDone)
+	})
+	defer func() {
+		<-onWatchdogDone
+	}()
 	defer cancel()
 
 	options, err := s.createWebOptions(processedConfig)
 	if err != nil {
 		return err
 	}
+	if err := s.listen(&options); err != nil {
+		return err
+	}
 	return web.RunWeb(ctx, myRobot, options, s.logger)
 }
 
+// listen sets the listener of the web service, inheriting it if the server was upgraded in
+// place. The server keeps track of it so that it can be handed over on upgrade.
+func (s *robotServer) listen(options *weboptions.Options) error {
+	if options.Network.Listener == nil {
+		lis, err := inheritedListener()
+		if err != nil {
+			return err
+		}
+		if lis == nil {
+			if lis, err = net.Listen("tcp", options.Network.BindAddress); err != nil {
+				return err
+			}
+		}
+		options.Network.Listener = lis
+		options.Network.BindAddress = ""
+	}
+	s.listenerMu.Lock()
+	defer s.listenerMu.Unlock()
+	s.listener = options.Network.Listener
+	return nil
+}
+
+// prepareUpgrade takes over the listener of the web service and saves a snapshot of the
+// robot's resource states for the upgraded server to restore.
+func (s *robotServer) prepareUpgrade(ctx context.Context, r robot.LocalRobot) (err error) {
+	s.listenerMu.Lock()
+	lis := s.listener
+	s.listenerMu.Unlock()
+	tcpLis, ok := lis.(*net.TCPListener)
+	if !ok {
+		return errors.Errorf("cannot hand over listener of type %T", lis)
+	}
+	listenerFile, err := tcpLis.File()
+	if err != nil {
+		return errors.Wrap(err, "failed to hand over listener")
+	}
+	defer func() {
+		if err != nil {
+			err = multierr.Combine(err, listenerFile.Close())
+		}
+	}()
+
+	bundle, err := snapshot.Capture(ctx, r)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp("", "viam-upgrade-*.json")
+	if err != nil {
+		return err
+	}
+	snapshotPath := f.Name()
+	if err := multierr.Combine(f.Close(), snapshot.Save(snapshotPath, bundle)); err != nil {
+		return multierr.Combine(err, os.Remove(snapshotPath))
+	}
+	s.upgrade = &pendingUpgrade{listenerFile: listenerFile, snapshotPath: snapshotPath}
+	return nil
+}
+
+// restoreUpgradeSnapshot restores the resource states saved by the server that upgraded
+// into this process, if any. Failures are logged since the robot is usable without them.
+func (s *robotServer) restoreUpgradeSnapshot(ctx context.Context, r robot.LocalRobot) {
+	snapshotPath, ok := os.LookupEnv(envUpgradeSnapshot)
+	if !ok {
+		return
+	}
+	if err := os.Unsetenv(envUpgradeSnapshot); err != nil {
+		s.logger.Warnw("failed to clear upgrade snapshot path", "error", err)
+	}
+	defer func() {
+		if err := os.Remove(snapshotPath); err != nil {
+			s.logger.Warnw("failed to remove upgrade snapshot", "error", err)
+		}
+	}()
+	bundle, err := snapshot.Load(snapshotPath)
+	if err == nil {
+		err = snapshot.Restore(ctx, r, bundle, snapshot.RestoreOptions{SkipConfig: true})
+	}
+	if err != nil {
+		s.logger.Errorw("failed to restore resource states after upgrade", "error", err)
+	}
+}
+
 // dumpResourceRegistrations prints all builtin resource registrations as a json array
 // to the provided file. If you edit this function, ensure that etc/system_manifest/main.go is
 // updated correspondingly.
+// validateConfigFile prints the problems found validating the given config file as json,
+// returning an error if any of them keeps the robot from being configured.
+func validateConfigFile(ctx context.Context, path string) error {
+	if path == "" {
+		return errors.New("a config file is required to validate")
+	}
+	//nolint:gosec
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var cfg config.Config
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return errors.Wrap(err, "cannot parse config")
+	}
+	report := robotimpl.Validate(ctx, &cfg)
+	jsonResult, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintln(os.Stdout, string(jsonResult)); err != nil {
+		return err
+	}
+	if !report.Valid() {
+		return errors.Errorf("config has %d error(s)", len(report.Errors()))
+	}
+	return nil
+}
+
 func dumpResourceRegistrations(outputPath string) error {
 	type resourceRegistration struct {
 		API   string `json:"api"`
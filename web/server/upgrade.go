@@ -0,0 +1,85 @@
+//go:build !windows
+
+package server
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// upgradeSignal asks a running server to upgrade itself to the binary it was started from,
+// such as after that binary was replaced by a fleet-wide update.
+const upgradeSignal = syscall.SIGUSR2
+
+// notifyUpgrade relays upgrade requests to ch.
+func notifyUpgrade(ch chan<- os.Signal) {
+	signal.Notify(ch, upgradeSignal)
+}
+
+// stopNotifyUpgrade stops relaying upgrade requests to ch.
+func stopNotifyUpgrade(ch chan<- os.Signal) {
+	signal.Stop(ch)
+}
+
+// inheritedListener returns the listener handed over by the server this process was
+// upgraded from, if any.
+func inheritedListener() (net.Listener, error) {
+	fdStr, ok := os.LookupEnv(envUpgradeListenerFD)
+	if !ok {
+		return nil, nil
+	}
+	// the listener must not be passed on to processes this one starts.
+	if err := os.Unsetenv(envUpgradeListenerFD); err != nil {
+		return nil, err
+	}
+	fd, err := strconv.Atoi(fdStr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s", envUpgradeListenerFD)
+	}
+	syscall.CloseOnExec(fd)
+	f := os.NewFile(uintptr(fd), "listener")
+	defer func() {
+		// FileListener duplicates the descriptor.
+		//nolint:errcheck,gosec
+		f.Close()
+	}()
+	lis, err := net.FileListener(f)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to inherit listener")
+	}
+	return lis, nil
+}
+
+// execUpgrade replaces this process with the server binary at the path it was started
+// from, keeping its pid and arguments. The listener's socket, given as a file, stays open
+// across the exec so that connections made during the upgrade wait in its backlog instead
+// of being refused. It only returns on failure.
+func execUpgrade(listenerFile *os.File, snapshotPath string) error {
+	// resolve the binary by the path it was started from so that a binary replaced on disk
+	// is picked up.
+	binary, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return errors.Wrap(err, "failed to find server binary")
+	}
+	// files of listeners are close-on-exec; a plain dup is not.
+	fd, err := syscall.Dup(int(listenerFile.Fd()))
+	if err != nil {
+		return errors.Wrap(err, "failed to hand over listener")
+	}
+
+	env := append(os.Environ(), envUpgradeListenerFD+"="+strconv.Itoa(fd))
+	if snapshotPath != "" {
+		env = append(env, envUpgradeSnapshot+"="+snapshotPath)
+	}
+	//nolint:gosec
+	err = syscall.Exec(binary, os.Args, env)
+	//nolint:errcheck,gosec
+	syscall.Close(fd)
+	return errors.Wrap(err, "failed to exec server binary")
+}
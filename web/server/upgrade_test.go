@@ -0,0 +1,67 @@
+//go:build !windows
+
+package server
+
+import (
+	"net"
+	"os"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"go.viam.com/test"
+
+	"go.viam.com/rdk/config"
+	"go.viam.com/rdk/logging"
+	weboptions "go.viam.com/rdk/robot/web/options"
+)
+
+func TestInheritedListener(t *testing.T) {
+	lis, err := inheritedListener()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, lis, test.ShouldBeNil)
+
+	// hand over a listener the way execUpgrade does.
+	original, err := net.Listen("tcp", "localhost:0")
+	test.That(t, err, test.ShouldBeNil)
+	f, err := original.(*net.TCPListener).File()
+	test.That(t, err, test.ShouldBeNil)
+	fd, err := syscall.Dup(int(f.Fd()))
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, f.Close(), test.ShouldBeNil)
+	t.Setenv(envUpgradeListenerFD, strconv.Itoa(fd))
+
+	s := &robotServer{logger: logging.NewTestLogger(t)}
+	options := weboptions.Options{Network: config.NetworkConfig{
+		NetworkConfigData: config.NetworkConfigData{BindAddress: "localhost:0"},
+	}}
+	test.That(t, s.listen(&options), test.ShouldBeNil)
+	inherited := options.Network.Listener
+	defer func() {
+		test.That(t, inherited.Close(), test.ShouldBeNil)
+	}()
+	test.That(t, options.Network.BindAddress, test.ShouldBeEmpty)
+	test.That(t, inherited.Addr().String(), test.ShouldEqual, original.Addr().String())
+	_, ok := os.LookupEnv(envUpgradeListenerFD)
+	test.That(t, ok, test.ShouldBeFalse)
+
+	// connections are accepted on the inherited listener.
+	conn, err := net.Dial("tcp", original.Addr().String())
+	test.That(t, err, test.ShouldBeNil)
+	defer func() {
+		test.That(t, conn.Close(), test.ShouldBeNil)
+	}()
+	// the old server closes its listener.
+	test.That(t, original.Close(), test.ShouldBeNil)
+	accepted, err := inherited.Accept()
+	test.That(t, err, test.ShouldBeNil)
+	test.That(t, accepted.Close(), test.ShouldBeNil)
+
+	// the inherited listener is only used once.
+	options = weboptions.Options{Network: config.NetworkConfig{
+		NetworkConfigData: config.NetworkConfigData{BindAddress: "localhost:0"},
+	}}
+	test.That(t, s.listen(&options), test.ShouldBeNil)
+	test.That(t, options.Network.Listener.Addr().String(), test.ShouldNotEqual, inherited.Addr().String())
+	test.That(t, options.Network.Listener.Close(), test.ShouldBeNil)
+}
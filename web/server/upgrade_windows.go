@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Upgrading in place relies on exec and inheriting file descriptors, which windows does not
+// support.
+
+func notifyUpgrade(ch chan<- os.Signal) {}
+
+func stopNotifyUpgrade(ch chan<- os.Signal) {}
+
+func inheritedListener() (net.Listener, error) {
+	return nil, nil
+}
+
+func execUpgrade(listenerFile *os.File, snapshotPath string) error {
+	return errors.New("upgrading in place is not supported on windows")
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.viam.com/utils"
+
+	"go.viam.com/rdk/robot"
+	"go.viam.com/rdk/robot/health"
+)
+
+// notifySystemd sends the given state to systemd if the server runs as a systemd service
+// that expects notifications, and does nothing otherwise.
+func notifySystemd(state string) error {
+	socket := os.Getenv("NOTIFY_SOCKET")
+	if socket == "" {
+		return nil
+	}
+	// a leading @ is an abstract socket.
+	if strings.HasPrefix(socket, "@") {
+		socket = "\x00" + socket[1:]
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return err
+	}
+	defer utils.UncheckedErrorFunc(conn.Close)
+	_, err = conn.Write([]byte(state))
+	return err
+}
+
+// watchdogInterval returns how often the systemd watchdog must be notified, if it is
+// enabled for this process.
+func watchdogInterval() (time.Duration, bool) {
+	usec, err := strconv.ParseInt(os.Getenv("WATCHDOG_USEC"), 10, 64)
+	if err != nil || usec <= 0 {
+		return 0, false
+	}
+	if pid := os.Getenv("WATCHDOG_PID"); pid != "" && pid != strconv.Itoa(os.Getpid()) {
+		return 0, false
+	}
+	return time.Duration(usec) * time.Microsecond, true
+}
+
+// runWatchdog tells systemd that the robot is up and, if the systemd watchdog is enabled,
+// notifies it for as long as the robot reports its health in time so that systemd restarts
+// a robot that hangs.
+func (s *robotServer) runWatchdog(ctx context.Context, r robot.Robot) {
+	if err := notifySystemd("READY=1"); err != nil {
+		s.logger.Warnw("failed to notify systemd", "error", err)
+	}
+	timeout, ok := watchdogInterval()
+	if !ok {
+		return
+	}
+	healthRobot, ok := r.(health.Robot)
+	if !ok {
+		s.logger.Warn("robot does not report its health; not notifying systemd watchdog")
+		return
+	}
+	// notify twice per timeout as recommended by systemd.
+	interval := timeout / 2
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		healthCtx, cancel := context.WithTimeout(ctx, interval)
+		_, err := healthRobot.Health(healthCtx)
+		cancel()
+		if err != nil {
+			s.logger.Warnw("robot did not report its health in time; not notifying systemd watchdog", "error", err)
+			continue
+		}
+		if err := notifySystemd("WATCHDOG=1"); err != nil {
+			s.logger.Warnw("failed to notify systemd watchdog", "error", err)
+		}
+	}
+}